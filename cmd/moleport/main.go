@@ -5,18 +5,34 @@ import (
 	"os"
 
 	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/cli/completioncmd"
+	"github.com/ousiassllc/moleport/internal/cli/configcmd"
 	"github.com/ousiassllc/moleport/internal/cli/daemoncmd"
+	"github.com/ousiassllc/moleport/internal/cli/editcmd"
+	"github.com/ousiassllc/moleport/internal/cli/exportcmd"
+	"github.com/ousiassllc/moleport/internal/cli/groupcmd"
+	"github.com/ousiassllc/moleport/internal/cli/healthcmd"
+	"github.com/ousiassllc/moleport/internal/cli/instancescmd"
+	"github.com/ousiassllc/moleport/internal/cli/listcmd"
+	"github.com/ousiassllc/moleport/internal/cli/logscmd"
+	"github.com/ousiassllc/moleport/internal/cli/migratecmd"
+	"github.com/ousiassllc/moleport/internal/cli/portcmd"
+	"github.com/ousiassllc/moleport/internal/cli/servicecmd"
 	"github.com/ousiassllc/moleport/internal/cli/statuscmd"
+	"github.com/ousiassllc/moleport/internal/cli/synccmd"
+	"github.com/ousiassllc/moleport/internal/cli/tuicmd"
+	"github.com/ousiassllc/moleport/internal/cli/tunnelcmd"
 	"github.com/ousiassllc/moleport/internal/cli/updatecmd"
-	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/core/config"
+	"github.com/ousiassllc/moleport/internal/daemon/autostart"
+	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/infra/yamlstore"
 )
 
 func main() {
 	// デーモンモードの場合は直接デーモンとして起動
-	if daemon.IsDaemonMode() {
+	if autostart.IsDaemonMode() {
 		flagConfigDir, _ := cli.ParseGlobalFlags()
 		configDir := cli.ResolveConfigDir(flagConfigDir)
 		daemoncmd.RunDaemonMode(configDir)
@@ -25,12 +41,14 @@ func main() {
 
 	// グローバルフラグを解析
 	flagConfigDir, args := cli.ParseGlobalFlags()
-	configDir := cli.ResolveConfigDir(flagConfigDir)
-	initI18n(configDir)
+	baseDir := cli.ResolveConfigDir(flagConfigDir)
+	// --socket でインスタンスが選択されている場合は、インスタンスの設定ディレクトリを使う
+	configDir := cli.InstanceConfigDir(baseDir)
+	initLocale(configDir)
 
 	// サブコマンドなしの場合は TUI を起動
 	if len(args) == 0 {
-		cli.RunTUI(configDir, nil)
+		tuicmd.RunTUI(configDir, nil)
 		return
 	}
 
@@ -41,8 +59,12 @@ func main() {
 	switch cmd {
 	case "daemon":
 		daemoncmd.RunDaemon(configDir, subArgs)
+	case "service":
+		servicecmd.RunService(configDir, subArgs)
+	case "instances":
+		instancescmd.RunInstances(baseDir, subArgs)
 	case "connect":
-		cli.RunConnect(configDir, subArgs)
+		tuicmd.RunConnect(configDir, subArgs)
 	case "disconnect":
 		cli.RunDisconnect(configDir, subArgs)
 	case "add":
@@ -53,20 +75,48 @@ func main() {
 		cli.RunStart(configDir, subArgs)
 	case "stop":
 		cli.RunStop(configDir, subArgs)
+	case "edit":
+		editcmd.RunEdit(configDir, subArgs)
+	case "migrate":
+		migratecmd.RunMigrate(configDir, subArgs)
+	case "up":
+		groupcmd.RunUp(configDir, subArgs)
+	case "down":
+		groupcmd.RunDown(configDir, subArgs)
+	case "tunnel":
+		tunnelcmd.RunTunnel(configDir, subArgs)
+	case "check-port":
+		portcmd.RunCheckPort(configDir, subArgs)
+	case "sync":
+		synccmd.RunSync(configDir, subArgs)
+	case "export":
+		exportcmd.RunExport(configDir, subArgs)
+	case "import":
+		exportcmd.RunImport(configDir, subArgs)
 	case "list":
-		cli.RunList(configDir, subArgs)
+		listcmd.RunList(configDir, subArgs)
 	case "status":
 		statuscmd.RunStatus(configDir, subArgs)
+	case "health":
+		healthcmd.RunHealth(configDir, subArgs)
+	case "logs":
+		logscmd.RunLogs(configDir, subArgs)
 	case "config":
-		cli.RunConfig(configDir, subArgs)
+		configcmd.RunConfig(configDir, subArgs)
 	case "reload":
 		cli.RunReload(configDir, subArgs)
+	case "secrets":
+		cli.RunSecrets(configDir, subArgs)
 	case "tui":
-		cli.RunTUI(configDir, subArgs)
+		tuicmd.RunTUI(configDir, subArgs)
 	case "version":
 		cli.RunVersion(configDir, subArgs)
 	case "update":
 		updatecmd.RunUpdate(configDir, subArgs)
+	case "completion":
+		completioncmd.RunCompletion(configDir, subArgs)
+	case "__complete":
+		completioncmd.RunComplete(configDir, subArgs)
 	case "help", "--help", "-h":
 		cli.RunHelp(configDir, subArgs)
 	default:
@@ -77,14 +127,15 @@ func main() {
 	}
 }
 
-// initI18n は config.yaml の Language フィールドを読み取り、i18n を初期化する。
-// config の読み込みに失敗した場合は環境変数からフォールバックする。
-func initI18n(configDir string) {
+// initLocale は config.yaml の Language と ui.time_format を読み取り、i18n と時刻表示形式を初期化する。
+// config の読み込みに失敗した場合は言語を環境変数から、時刻表示形式をデフォルトからフォールバックする。
+func initLocale(configDir string) {
 	var configLang string
 	store := yamlstore.NewYAMLStore()
-	cfgMgr := core.NewConfigManager(store, configDir)
+	cfgMgr := config.NewConfigManager(store, configDir)
 	if cfg, err := cfgMgr.LoadConfig(); err == nil {
 		configLang = cfg.Language
+		format.SetTimeFormat(cfg.UI.TimeFormat)
 	}
 	lang := i18n.Resolve(configLang)
 	_ = i18n.SetLang(lang)
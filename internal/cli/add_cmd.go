@@ -3,6 +3,7 @@ package cli
 import (
 	"flag"
 	"fmt"
+	"strings"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/i18n"
@@ -13,14 +14,21 @@ import (
 func RunAdd(configDir string, args []string) {
 	fs := flag.NewFlagSet("add", flag.ContinueOnError)
 
-	host := fs.String("host", "", "SSH ホスト名 (必須)")
-	fwdType := fs.String("type", "local", "転送種別: local, remote, dynamic")
-	localPort := fs.Int("local-port", 0, "ローカルポート (必須)")
-	remoteHost := fs.String("remote-host", "localhost", "リモートホスト")
-	remotePort := fs.Int("remote-port", 0, "リモートポート")
-	name := fs.String("name", "", "ルール名 (省略時は自動生成)")
-	remoteBindAddr := fs.String("remote-bind-addr", "", "リモート側バインドアドレス (デフォルト: 127.0.0.1)")
-	autoConnect := fs.Bool("auto-connect", false, "起動時に自動接続")
+	host := fs.String("host", "", i18n.T("cli.flags.add.host"))
+	fwdType := fs.String("type", "local", i18n.T("cli.flags.add.type"))
+	localPort := fs.Int("local-port", -1, i18n.T("cli.flags.add.local_port"))
+	remoteHost := fs.String("remote-host", "localhost", i18n.T("cli.flags.add.remote_host"))
+	remotePort := fs.Int("remote-port", 0, i18n.T("cli.flags.add.remote_port"))
+	name := fs.String("name", "", i18n.T("cli.flags.add.name"))
+	remoteBindAddr := fs.String("remote-bind-addr", "", i18n.T("cli.flags.add.remote_bind_addr"))
+	localBindAddr := fs.String("local-bind-addr", "", i18n.T("cli.flags.add.local_bind_addr"))
+	autoConnect := fs.Bool("auto-connect", false, i18n.T("cli.flags.add.auto_connect"))
+	autoReconnect := fs.Bool("auto-reconnect", false, i18n.T("cli.flags.add.auto_reconnect"))
+	ttl := fs.String("ttl", "", i18n.T("cli.flags.add.ttl"))
+	allow := fs.String("allow", "", i18n.T("cli.flags.add.allow"))
+	deny := fs.String("deny", "", i18n.T("cli.flags.add.deny"))
+	deleteOnExpire := fs.Bool("delete-on-expire", false, i18n.T("cli.flags.add.delete_on_expire"))
+	accessLog := fs.String("access-log", "", i18n.T("cli.flags.add.access_log"))
 
 	if err := fs.Parse(args); err != nil {
 		ExitError("%v", err)
@@ -29,13 +37,25 @@ func RunAdd(configDir string, args []string) {
 	if *host == "" {
 		ExitError("%s", i18n.T("cli.add.host_required"))
 	}
-	if *localPort == 0 {
+	if *localPort < 0 {
 		ExitError("%s", i18n.T("cli.add.local_port_required"))
 	}
-	if *localPort < core.MinPort || *localPort > core.MaxPort {
+	if (*localPort != 0 || *fwdType == "remote") && (*localPort < core.MinPort || *localPort > core.MaxPort) {
 		ExitError("%s", i18n.T("cli.add.port_range"))
 	}
 
+	for _, addr := range []string{*localBindAddr, *remoteBindAddr} {
+		if err := core.ValidateBindAddr(addr); err != nil {
+			ExitError("%v", err)
+		}
+	}
+
+	if _, err := protocol.ParseTTL(*ttl); err != nil {
+		ExitError("%v", err)
+	} else if *deleteOnExpire && *ttl == "" {
+		ExitError("%s", i18n.T("cli.add.delete_on_expire_requires_ttl"))
+	}
+
 	switch *fwdType {
 	case "local", "remote", "dynamic":
 		// OK
@@ -52,6 +72,10 @@ func RunAdd(configDir string, args []string) {
 		}
 	}
 
+	if (*allow != "" || *deny != "") && *fwdType != "dynamic" {
+		ExitError("%s", i18n.T("cli.add.acl_dynamic_only"))
+	}
+
 	client, ctx, cleanup := DaemonCall(configDir)
 	defer cleanup()
 
@@ -63,7 +87,16 @@ func RunAdd(configDir string, args []string) {
 		RemoteHost:     *remoteHost,
 		RemotePort:     *remotePort,
 		RemoteBindAddr: *remoteBindAddr,
+		LocalBindAddr:  *localBindAddr,
 		AutoConnect:    *autoConnect,
+		AutoReconnect:  *autoReconnect,
+		TTL:            *ttl,
+		DeleteOnExpire: *deleteOnExpire,
+		AccessLog:      *accessLog,
+	}
+
+	if *allow != "" || *deny != "" {
+		params.ACL = &protocol.SocksACL{Allow: splitList(*allow), Deny: splitList(*deny)}
 	}
 
 	var result protocol.ForwardAddResult
@@ -73,3 +106,14 @@ func RunAdd(configDir string, args []string) {
 
 	fmt.Println(i18n.T("cli.add.success", map[string]any{"Name": result.Name}))
 }
+
+// splitList はカンマ区切りの値を空白を除いて分割する。空の要素は除く。
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -163,3 +163,25 @@ func TestRunAdd_MockDaemon_Dynamic(t *testing.T) {
 		t.Error("RunAdd dynamic should produce output with mock daemon")
 	}
 }
+
+func TestRunAdd_ACLRequiresDynamic(t *testing.T) {
+	stubExit(t)
+
+	code, _ := captureExit(t, func() {
+		RunAdd("/tmp", []string{"--host", "myserver", "--local-port", "8080", "--remote-port", "80", "--allow", "10.0.0.0/8"})
+	})
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" 10.0.0.0/8, *.example.com,,")
+	if len(got) != 2 || got[0] != "10.0.0.0/8" || got[1] != "*.example.com" {
+		t.Errorf("splitList() = %q, want [10.0.0.0/8 *.example.com]", got)
+	}
+	if got := splitList(""); got != nil {
+		t.Errorf("splitList(\"\") = %q, want nil", got)
+	}
+}
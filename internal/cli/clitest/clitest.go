@@ -0,0 +1,111 @@
+package clitest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// ExitCalled は StubExit で差し替えた cli.ExitFunc が呼ばれたときに発生させる panic の値。
+type ExitCalled struct{ Code int }
+
+// StubExit は cli.ExitFunc を ExitCalled で panic する関数に差し替え、テストの終了時に元に戻す。
+func StubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(ExitCalled{Code: c}) }
+}
+
+// ExpectExit は fn が終了コード 1 で終了することを検証する。StubExit と併せて使い、stderr への出力は捨てる。
+func ExpectExit(t *testing.T, fn func()) {
+	t.Helper()
+	origStderr := os.Stderr
+	devNull, _ := os.Open(os.DevNull)
+	os.Stderr = devNull
+	defer func() {
+		os.Stderr = origStderr
+		_ = devNull.Close()
+		v := recover()
+		if ec, ok := v.(ExitCalled); !ok || ec.Code != 1 {
+			t.Errorf("exit = %v, want exit code 1", v)
+		}
+	}()
+	fn()
+}
+
+// CaptureStdout は fn が stdout に出力した内容を返す。
+func CaptureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = orig
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String()
+}
+
+// StubDaemon は cli.ConnectDaemon をモックデーモンへの接続に差し替え、受信したリクエストを返す。
+// モックデーモンはリクエストごとに respond の戻り値を結果として応答する。
+func StubDaemon(t *testing.T, respond func(req protocol.Request) any) <-chan protocol.Request {
+	t.Helper()
+	orig := cli.ConnectDaemon
+	t.Cleanup(func() { cli.ConnectDaemon = orig })
+
+	sockPath := filepath.Join(t.TempDir(), "mock.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	got := make(chan protocol.Request, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		scanner := bufio.NewScanner(conn)
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			var req protocol.Request
+			_ = json.Unmarshal(scanner.Bytes(), &req)
+			got <- req
+			resp, _ := protocol.NewResponse(req.ID, respond(req))
+			_ = json.NewEncoder(conn).Encode(resp)
+		}
+	}()
+
+	cli.ConnectDaemon = func(_ string) *client.IPCClient {
+		c := client.NewIPCClient(sockPath)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("mock connect: %v", err)
+		}
+		return c
+	}
+	return got
+}
+
+// Params は req のパラメータを T として返す。
+func Params[T any](req protocol.Request) T {
+	var p T
+	_ = json.Unmarshal(req.Params, &p)
+	return p
+}
@@ -0,0 +1,2 @@
+// Package clitest は CLI サブコマンドのテスト用ヘルパーとモックデーモンを提供する。
+package clitest
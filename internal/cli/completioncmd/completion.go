@@ -0,0 +1,77 @@
+package completioncmd
+
+import (
+	"slices"
+	"strings"
+)
+
+// Commands は補完候補に含めるサブコマンド。
+var Commands = []string{
+	"daemon", "service", "instances", "connect", "disconnect", "add", "delete", "start", "stop", "edit", "migrate",
+	"up", "down", "tunnel", "check-port", "sync", "export", "import", "list", "status", "health", "logs", "config",
+	"reload", "secrets", "tui", "version", "update", "completion", "help",
+}
+
+// subcommands はサブコマンドごとの第 1 引数の固定の候補。
+var subcommands = map[string][]string{
+	"daemon":     {"start", "stop", "status", "kill", "issue-cert"},
+	"service":    {"install", "uninstall", "status"},
+	"secrets":    {"clear"},
+	"sync":       {"pull", "push"},
+	"config":     {"validate"},
+	"completion": {"bash", "zsh", "fish"},
+}
+
+// hostArgs は第 1 引数にホスト名を取るサブコマンド。
+var hostArgs = []string{"connect", "disconnect", "tunnel"}
+
+// ruleArgs は第 1 引数にルール名を取るサブコマンド。
+var ruleArgs = []string{"delete", "start", "stop", "edit", "migrate", "status"}
+
+// hostFlags は値にホスト名を取るフラグ。
+var hostFlags = []string{"--host", "-host", "--to", "-to"}
+
+// Source は補完候補のホスト名・ルール名を取得する。
+type Source interface {
+	Hosts() []string
+	Rules() []string
+}
+
+// Complete は moleport に続く入力済みの単語列 words から、最後の単語の補完候補を返す。
+// 最後の単語は入力中の単語（空文字列の場合は未入力）として扱い、前方一致する候補のみを返す。
+func Complete(words []string, src Source) []string {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	if len(words) == 1 {
+		return filter(Commands, cur)
+	}
+
+	cmd, prev := words[0], words[len(words)-2]
+	first := len(words) == 2
+	switch {
+	case slices.Contains(hostFlags, prev):
+		return filter(src.Hosts(), cur)
+	case strings.HasPrefix(cur, "-"):
+		return nil
+	case first && subcommands[cmd] != nil:
+		return filter(subcommands[cmd], cur)
+	case first && slices.Contains(hostArgs, cmd):
+		return filter(src.Hosts(), cur)
+	case first && slices.Contains(ruleArgs, cmd):
+		return filter(src.Rules(), cur)
+	}
+	return nil
+}
+
+// filter は candidates のうち prefix で始まるものを返す。
+func filter(candidates []string, prefix string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
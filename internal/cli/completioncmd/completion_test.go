@@ -0,0 +1,37 @@
+package completioncmd
+
+import (
+	"slices"
+	"testing"
+)
+
+// fakeSource は固定のホスト名・ルール名を返す Source。
+type fakeSource struct{}
+
+func (fakeSource) Hosts() []string { return []string{"web", "db", "bastion"} }
+func (fakeSource) Rules() []string { return []string{"web-http", "db-pg"} }
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		words []string
+		want  []string
+	}{
+		{nil, Commands},
+		{[]string{"con"}, []string{"connect", "config"}},
+		{[]string{"daemon", "st"}, []string{"start", "stop", "status"}},
+		{[]string{"completion", ""}, []string{"bash", "zsh", "fish"}},
+		{[]string{"connect", ""}, []string{"web", "db", "bastion"}},
+		{[]string{"tunnel", "d"}, []string{"db"}},
+		{[]string{"start", "web"}, []string{"web-http"}},
+		{[]string{"migrate", "db-pg", "--to", "b"}, []string{"bastion"}},
+		{[]string{"list", "--host", ""}, []string{"web", "db", "bastion"}},
+		{[]string{"start", "--"}, nil},
+		{[]string{"connect", "web", ""}, nil},
+		{[]string{"version", ""}, nil},
+	}
+	for _, tt := range tests {
+		if got := Complete(tt.words, fakeSource{}); !slices.Equal(got, tt.want) {
+			t.Errorf("Complete(%q) = %q, want %q", tt.words, got, tt.want)
+		}
+	}
+}
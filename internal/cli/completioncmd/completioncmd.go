@@ -0,0 +1,85 @@
+package completioncmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/authtoken"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// lookupTimeout は補完候補を取得する RPC のタイムアウト。補完の応答を待たせないよう短くする。
+const lookupTimeout = 2 * time.Second
+
+// RunCompletion は completion サブコマンドを実行し、指定したシェルの補完スクリプトを出力する。
+func RunCompletion(configDir string, args []string) {
+	if len(args) != 1 || scripts[args[0]] == "" {
+		cli.ExitError("%s", i18n.T("cli.completion.usage"))
+	}
+	fmt.Print(scripts[args[0]])
+}
+
+// RunComplete は補完スクリプトから呼び出され、入力済みの単語列に対する補完候補を 1 行ずつ出力する。
+// ホスト名・ルール名はデーモンが稼働中の場合のみ取得し、デーモンの起動は行わない。
+func RunComplete(configDir string, args []string) {
+	for _, c := range Complete(args, daemonSource{configDir: configDir}) {
+		fmt.Println(c)
+	}
+}
+
+// daemonSource は稼働中のデーモンから IPC で補完候補を取得する Source。
+type daemonSource struct {
+	configDir string
+}
+
+// Hosts は host.list で取得したホスト名を返す。
+func (s daemonSource) Hosts() []string {
+	var result protocol.HostListResult
+	if !s.call("host.list", &result) {
+		return nil
+	}
+	names := make([]string, 0, len(result.Hosts))
+	for _, h := range result.Hosts {
+		names = append(names, h.Name)
+	}
+	return names
+}
+
+// Rules は forward.list で取得したルール名を返す。
+func (s daemonSource) Rules() []string {
+	var result protocol.ForwardListResult
+	if !s.call("forward.list", &result) {
+		return nil
+	}
+	names := make([]string, 0, len(result.Forwards))
+	for _, f := range result.Forwards {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+// call はデーモンが稼働中の場合のみ接続して method を呼び出し、成功したかを返す。
+func (s daemonSource) call(method string, result any) bool {
+	if running, _ := pidfile.IsRunning(daemon.PIDFilePath(s.configDir)); !running {
+		return false
+	}
+	c := client.NewIPCClient(daemon.SocketPath(s.configDir))
+	if token, err := authtoken.Read(daemon.TokenPath(s.configDir)); err == nil {
+		c.SetToken(token)
+	}
+	if err := c.Connect(); err != nil {
+		return false
+	}
+	defer func() { _ = c.Close() }()
+	c.SetProfile(cli.Profile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+	defer cancel()
+	return c.Call(ctx, method, nil, result) == nil
+}
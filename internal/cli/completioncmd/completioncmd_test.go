@@ -0,0 +1,81 @@
+package completioncmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/daemon"
+)
+
+type exitCalled struct{ code int }
+
+func stubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(exitCalled{code: c}) }
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = orig
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String()
+}
+
+func TestRunCompletion_Scripts(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish"} {
+		out := captureStdout(t, func() { RunCompletion(t.TempDir(), []string{shell}) })
+		if !strings.Contains(out, "moleport __complete") {
+			t.Errorf("%s script = %q, want a call to moleport __complete", shell, out)
+		}
+	}
+}
+
+func TestRunCompletion_InvalidShell(t *testing.T) {
+	stubExit(t)
+	for _, args := range [][]string{nil, {"powershell"}, {"bash", "zsh"}} {
+		func() {
+			origStderr := os.Stderr
+			devNull, _ := os.Open(os.DevNull)
+			os.Stderr = devNull
+			defer func() {
+				os.Stderr = origStderr
+				_ = devNull.Close()
+				if v, ok := recover().(exitCalled); !ok || v.code != 1 {
+					t.Errorf("RunCompletion(%q) exit = %v, want exit code 1", args, v)
+				}
+			}()
+			RunCompletion(t.TempDir(), args)
+		}()
+	}
+}
+
+func TestRunComplete_DaemonNotRunning(t *testing.T) {
+	configDir := t.TempDir()
+
+	if out := captureStdout(t, func() { RunComplete(configDir, []string{"disc"}) }); out != "disconnect\n" {
+		t.Errorf("output = %q, want disconnect", out)
+	}
+	// デーモンが停止している場合はホスト名を補完せず、デーモンも起動しない
+	if out := captureStdout(t, func() { RunComplete(configDir, []string{"connect", ""}) }); out != "" {
+		t.Errorf("output = %q, want no candidates", out)
+	}
+	if _, err := os.Stat(daemon.PIDFilePath(configDir)); err == nil {
+		t.Error("daemon should not be started for completion")
+	}
+}
@@ -0,0 +1,3 @@
+// Package completioncmd は completion サブコマンドと、シェル補完スクリプトから呼び出される
+// 補完候補の出力（__complete）の実装を提供する。
+package completioncmd
@@ -0,0 +1,42 @@
+package completioncmd
+
+// 各シェルの補完スクリプト。入力中のコマンドラインを moleport __complete に渡し、
+// 1 行 1 候補で出力された補完候補を使う。
+const (
+	bashScript = `# moleport の bash 補完
+_moleport() {
+    local IFS=$'\n'
+    COMPREPLY=($(moleport __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _moleport moleport
+`
+
+	zshScript = `#compdef moleport
+# moleport の zsh 補完
+_moleport() {
+    local -a candidates
+    candidates=(${(f)"$(moleport __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+    compadd -a candidates
+}
+if [ "$funcstack[1]" = "_moleport" ]; then
+    _moleport "$@"
+else
+    compdef _moleport moleport
+fi
+`
+
+	fishScript = `# moleport の fish 補完
+function __moleport_complete
+    set -l tokens (commandline -opc) (commandline -ct)
+    moleport __complete $tokens[2..-1] 2>/dev/null
+end
+complete -c moleport -f -a '(__moleport_complete)'
+`
+)
+
+// scripts はシェル名ごとの補完スクリプト。
+var scripts = map[string]string{
+	"bash": bashScript,
+	"zsh":  zshScript,
+	"fish": fishScript,
+}
@@ -11,7 +11,7 @@ import (
 // RunConfig は config サブコマンドを実行する。
 func RunConfig(configDir string, args []string) {
 	fs := flag.NewFlagSet("config", flag.ContinueOnError)
-	jsonFlag := fs.Bool("json", false, "JSON 形式で出力")
+	out := OutputFlags(fs)
 
 	if err := fs.Parse(args); err != nil {
 		ExitError("%v", err)
@@ -22,14 +22,13 @@ func RunConfig(configDir string, args []string) {
 
 	var result protocol.ConfigGetResult
 	if err := client.Call(ctx, "config.get", nil, &result); err != nil {
-		ExitError("%s", i18n.T("cli.config.get_failed", map[string]any{"Error": err}))
+		ExitErr(err, i18n.T("cli.config.get_failed", map[string]any{"Error": err}))
 	}
 
-	if *jsonFlag {
-		PrintJSON(result)
-		return
-	}
+	out.Print(result, func() { printConfig(result) })
+}
 
+func printConfig(result protocol.ConfigGetResult) {
 	fmt.Println(i18n.T("cli.config.header"))
 	fmt.Println(i18n.T("cli.config.ssh_config", map[string]any{"Path": result.SSHConfigPath}))
 	fmt.Println(i18n.T("cli.config.reconnect_header"))
@@ -42,4 +41,6 @@ func RunConfig(configDir string, args []string) {
 	fmt.Println(i18n.T("cli.config.log_header"))
 	fmt.Println(i18n.T("cli.config.log_level", map[string]any{"Value": result.Log.Level}))
 	fmt.Println(i18n.T("cli.config.log_file", map[string]any{"Value": result.Log.File}))
+	fmt.Println(i18n.T("cli.config.ui_header"))
+	fmt.Println(i18n.T("cli.config.ui_time_format", map[string]any{"Value": result.UI.TimeFormat}))
 }
@@ -0,0 +1,77 @@
+package configcmd
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core/config"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// RunConfig は config サブコマンドを実行する。validate の場合は設定ファイルを検証し、それ以外は設定を表示する。
+func RunConfig(configDir string, args []string) {
+	if len(args) > 0 && args[0] == "validate" {
+		RunValidate(configDir, args[1:])
+		return
+	}
+	cli.RunConfig(configDir, args)
+}
+
+// RunValidate は config validate サブコマンドを実行する。
+// 検証に失敗した設定ではデーモンが起動しないため、デーモンを介さずに設定ファイル（省略時は config.yaml）を検証する。
+// 問題がある場合は一覧を表示して終了コード 1 で終了する。
+func RunValidate(configDir string, args []string) {
+	flags := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	out := cli.OutputFlags(flags)
+	if err := flags.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+	path := filepath.Join(configDir, "config.yaml")
+	explicit := flags.NArg() > 0
+	if explicit {
+		path = flags.Arg(0)
+		if err := flags.Parse(flags.Args()[1:]); err != nil {
+			cli.ExitError("%v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && (explicit || !errors.Is(err, fs.ErrNotExist)) {
+		cli.ExitErr(err, i18n.T("cli.config.read_failed", map[string]any{"Error": err}))
+	}
+	result := Check(data)
+	out.Print(result, func() { printResult(path, result) })
+	if !result.Valid {
+		cli.ExitFunc(1)
+	}
+}
+
+// Check は config.yaml の内容を config.validate と同じ規則で検証する。空の内容は既定値のため有効とする。
+func Check(data []byte) protocol.ConfigValidateResult {
+	if _, err := config.Parse(data); err != nil {
+		return protocol.ConfigValidateResult{Issues: rpcerr.ConfigIssues(err)}
+	}
+	return protocol.ConfigValidateResult{Valid: true}
+}
+
+func printResult(path string, result protocol.ConfigValidateResult) {
+	if result.Valid {
+		fmt.Println(i18n.T("cli.config.valid", map[string]any{"Path": path}))
+		return
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("cli.config.invalid", map[string]any{"Path": path, "Count": len(result.Issues)}))
+	for _, issue := range result.Issues {
+		if issue.Field == "" {
+			fmt.Fprintf(os.Stderr, "  %s\n", issue.Message)
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "  %s: %s\n", issue.Field, issue.Message)
+	}
+}
@@ -0,0 +1,86 @@
+package configcmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type exitCalled struct{ code int }
+
+func stubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(exitCalled{code: c}) }
+}
+
+// runValidate は RunValidate を実行し、stdout の出力と終了コード（終了しなかった場合は 0）を返す。
+func runValidate(t *testing.T, configDir string, args ...string) (string, int) {
+	t.Helper()
+	stubExit(t)
+	origStdout, origStderr := os.Stdout, os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	devNull, _ := os.Open(os.DevNull)
+	os.Stdout, os.Stderr = w, devNull
+
+	code := 0
+	func() {
+		defer func() {
+			if ec, ok := recover().(exitCalled); ok {
+				code = ec.code
+			}
+		}()
+		RunValidate(configDir, args)
+	}()
+	_ = w.Close()
+	_ = devNull.Close()
+	os.Stdout, os.Stderr = origStdout, origStderr
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String(), code
+}
+
+func TestCheck(t *testing.T) {
+	if got := Check(nil); !got.Valid {
+		t.Errorf("Check(empty) = %+v, want valid", got)
+	}
+	got := Check([]byte("log:\n  level: verbose\nreconnect:\n  max_retries: -1\n"))
+	if got.Valid || len(got.Issues) != 2 {
+		t.Errorf("Check() = %+v, want 2 issues", got)
+	}
+}
+
+func TestRunValidate(t *testing.T) {
+	dir := t.TempDir()
+	if _, code := runValidate(t, dir); code != 0 {
+		t.Errorf("missing config.yaml: exit code = %d, want 0", code)
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	if err := os.WriteFile(bad, []byte("language: fr\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, code := runValidate(t, dir, bad, "--json")
+	if code != 1 {
+		t.Errorf("invalid file: exit code = %d, want 1", code)
+	}
+	var result protocol.ConfigValidateResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil || len(result.Issues) != 1 || result.Issues[0].Field != "language" {
+		t.Errorf("output = %q, want a language issue", out)
+	}
+
+	if _, code := runValidate(t, dir, filepath.Join(dir, "missing.yaml")); code != 1 {
+		t.Errorf("missing explicit file: exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,2 @@
+// Package configcmd は config サブコマンド（設定の表示と config validate による設定ファイルの検証）の実装を提供する。
+package configcmd
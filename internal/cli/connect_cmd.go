@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ousiassllc/moleport/internal/cli/credprompt"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
@@ -24,7 +25,7 @@ func RunConnect(configDir string, args []string) {
 	defer func() { _ = client.Close() }()
 
 	// クレデンシャルハンドラーを設定
-	client.SetCredentialHandler(newCLICredentialHandler())
+	client.SetCredentialHandler(credprompt.New())
 
 	ctx, cancel := context.WithTimeout(context.Background(), connectCallTimeout)
 	defer cancel()
@@ -1,8 +1,9 @@
-package cli
+package credprompt
 
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 
@@ -13,14 +14,16 @@ import (
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
-// newCLICredentialHandler はターミナルからクレデンシャルを読み取る CredentialHandler を返す。
-func newCLICredentialHandler() client.CredentialHandler {
+// New はターミナル（stdin/stderr）からクレデンシャルを読み取る CredentialHandler を返す。
+func New() client.CredentialHandler {
 	return func(req protocol.CredentialRequestNotification) (*protocol.CredentialResponseParams, error) {
 		switch req.Type {
 		case "password", "passphrase":
 			return handlePasswordPrompt(req)
 		case "keyboard-interactive":
 			return handleKeyboardInteractive(req)
+		case "host-key":
+			return handleHostKeyPrompt(req, os.Stdin)
 		default:
 			return nil, fmt.Errorf("unknown credential type: %s", req.Type)
 		}
@@ -87,3 +90,18 @@ func handleKeyboardInteractive(req protocol.CredentialRequestNotification) (*pro
 		Answers:   answers,
 	}, nil
 }
+
+// handleHostKeyPrompt は未登録のホスト鍵のフィンガープリントを表示し、信頼するか（yes/once/no）を読み取る。
+func handleHostKeyPrompt(req protocol.CredentialRequestNotification, in io.Reader) (*protocol.CredentialResponseParams, error) {
+	fmt.Fprint(os.Stderr, i18n.T("cli.credential.hostkey_prompt", map[string]any{
+		"Host": req.Host, "KeyType": req.KeyType, "Fingerprint": req.Fingerprint,
+	}))
+	line, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && line == "" {
+		return nil, err
+	}
+	return &protocol.CredentialResponseParams{
+		RequestID: req.RequestID,
+		Value:     strings.ToLower(strings.TrimSpace(line)),
+	}, nil
+}
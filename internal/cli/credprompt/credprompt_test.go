@@ -1,15 +1,16 @@
-package cli
+package credprompt
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
-func TestNewCLICredentialHandler_ReturnsNonNil(t *testing.T) {
-	handler := newCLICredentialHandler()
+func TestNew_ReturnsNonNil(t *testing.T) {
+	handler := New()
 	if handler == nil {
-		t.Error("newCLICredentialHandler should return a non-nil handler")
+		t.Error("New should return a non-nil handler")
 	}
 }
 
@@ -36,8 +37,8 @@ func TestHandleKeyboardInteractive_EmptyPrompts(t *testing.T) {
 	}
 }
 
-func TestNewCLICredentialHandler_UnknownType(t *testing.T) {
-	handler := newCLICredentialHandler()
+func TestNew_UnknownType(t *testing.T) {
+	handler := New()
 
 	req := protocol.CredentialRequestNotification{
 		RequestID: "test-id",
@@ -53,3 +54,21 @@ func TestNewCLICredentialHandler_UnknownType(t *testing.T) {
 		t.Error("response should be nil on error")
 	}
 }
+
+func TestHandleHostKeyPrompt(t *testing.T) {
+	req := protocol.CredentialRequestNotification{
+		RequestID:   "test-id",
+		Type:        "host-key",
+		Host:        "testhost",
+		KeyType:     "ssh-ed25519",
+		Fingerprint: "SHA256:abc",
+	}
+
+	resp, err := handleHostKeyPrompt(req, strings.NewReader(" Yes\n"))
+	if err != nil {
+		t.Fatalf("handleHostKeyPrompt: %v", err)
+	}
+	if resp.RequestID != "test-id" || resp.Value != "yes" {
+		t.Errorf("response = %+v, want RequestID test-id and Value yes", resp)
+	}
+}
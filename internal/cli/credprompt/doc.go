@@ -0,0 +1,3 @@
+// Package credprompt は CLI でデーモンからのクレデンシャル要求（パスワード・パスフレーズ・keyboard-interactive・ホスト鍵の確認）に
+// ターミナルで応答する CredentialHandler を提供する。
+package credprompt
@@ -7,10 +7,14 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"strings"
 
 	"github.com/ousiassllc/moleport/internal/cli"
 	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/autostart"
+	"github.com/ousiassllc/moleport/internal/daemon/liveconfig"
+	"github.com/ousiassllc/moleport/internal/daemon/logbuf"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
@@ -27,9 +31,11 @@ func RunDaemon(configDir string, args []string) {
 	case "stop":
 		runDaemonStop(configDir, args[1:])
 	case "status":
-		runDaemonStatus(configDir)
+		runDaemonStatus(configDir, args[1:])
 	case "kill":
 		runDaemonKill(configDir)
+	case "issue-cert":
+		runIssueCert(configDir, args[1:])
 	default:
 		cli.ExitError("%s", i18n.T("cli.daemon.unknown_subcommand", map[string]any{"Sub": args[0]}))
 	}
@@ -37,15 +43,15 @@ func RunDaemon(configDir string, args []string) {
 
 func runDaemonStart(configDir string) {
 	pidPath := daemon.PIDFilePath(configDir)
-	running, pid := daemon.IsRunning(pidPath)
+	running, pid := pidfile.IsRunning(pidPath)
 	if running {
 		fmt.Println(i18n.T("cli.daemon.already_running", map[string]any{"PID": pid}))
 		return
 	}
 
-	pid, err := daemon.StartDaemonProcess(configDir)
+	pid, err := autostart.StartDaemonProcess(configDir)
 	if err != nil {
-		cli.ExitError("%s", i18n.T("cli.daemon.start_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.daemon.start_failed", map[string]any{"Error": err}))
 	}
 
 	fmt.Println(i18n.T("cli.daemon.started", map[string]any{"PID": pid}))
@@ -53,21 +59,21 @@ func runDaemonStart(configDir string) {
 
 func runDaemonStop(configDir string, args []string) {
 	fs := flag.NewFlagSet("daemon stop", flag.ContinueOnError)
-	purge := fs.Bool("purge", false, "状態ファイルを削除して停止")
+	purge := fs.Bool("purge", false, i18n.T("cli.flags.daemon.purge"))
 	if err := fs.Parse(args); err != nil {
 		cli.ExitError("%v", err)
 	}
 
 	pidPath := daemon.PIDFilePath(configDir)
-	running, _ := daemon.IsRunning(pidPath)
+	running, _ := pidfile.IsRunning(pidPath)
 	if !running {
 		fmt.Println(i18n.T("cli.daemon.not_running"))
 		return
 	}
 
-	client, err := daemon.EnsureDaemon(configDir)
+	client, err := autostart.EnsureDaemon(configDir)
 	if err != nil {
-		cli.ExitError("%s", i18n.T("cli.daemon.connect_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.daemon.connect_failed", map[string]any{"Error": err}))
 	}
 	defer func() { _ = client.Close() }()
 
@@ -77,7 +83,7 @@ func runDaemonStop(configDir string, args []string) {
 	params := protocol.DaemonShutdownParams{Purge: *purge}
 	var result protocol.DaemonShutdownResult
 	if err := client.Call(ctx, "daemon.shutdown", params, &result); err != nil {
-		cli.ExitError("%s", i18n.T("cli.daemon.stop_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.daemon.stop_failed", map[string]any{"Error": err}))
 	}
 
 	if *purge {
@@ -89,14 +95,14 @@ func runDaemonStop(configDir string, args []string) {
 
 func runDaemonKill(configDir string) {
 	pidPath := daemon.PIDFilePath(configDir)
-	running, pid := daemon.IsRunning(pidPath)
+	running, pid := pidfile.IsRunning(pidPath)
 	if !running {
 		fmt.Println(i18n.T("cli.daemon.not_running"))
 		return
 	}
 
-	if err := daemon.KillProcess(pidPath); err != nil {
-		cli.ExitError("%s", i18n.T("cli.daemon.kill_failed", map[string]any{"Error": err}))
+	if err := pidfile.KillProcess(pidPath); err != nil {
+		cli.ExitErr(err, i18n.T("cli.daemon.kill_failed", map[string]any{"Error": err}))
 	}
 
 	// 強制終了では graceful shutdown が走らないため、state.yaml を手動で削除する。
@@ -107,17 +113,23 @@ func runDaemonKill(configDir string) {
 	fmt.Println(i18n.T("cli.daemon.killed", map[string]any{"PID": pid}))
 }
 
-func runDaemonStatus(configDir string) {
+func runDaemonStatus(configDir string, args []string) {
+	fs := flag.NewFlagSet("daemon status", flag.ContinueOnError)
+	out := cli.OutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
 	pidPath := daemon.PIDFilePath(configDir)
-	running, _ := daemon.IsRunning(pidPath)
+	running, _ := pidfile.IsRunning(pidPath)
 	if !running {
 		fmt.Println(i18n.T("cli.daemon.not_running"))
 		return
 	}
 
-	client, err := daemon.EnsureDaemon(configDir)
+	client, err := autostart.EnsureDaemon(configDir)
 	if err != nil {
-		cli.ExitError("%s", i18n.T("cli.daemon.connect_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.daemon.connect_failed", map[string]any{"Error": err}))
 	}
 	defer func() { _ = client.Close() }()
 
@@ -126,13 +138,19 @@ func runDaemonStatus(configDir string) {
 
 	var status protocol.DaemonStatusResult
 	if err := client.Call(ctx, "daemon.status", nil, &status); err != nil {
-		cli.ExitError("%s", i18n.T("cli.daemon.status_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.daemon.status_failed", map[string]any{"Error": err}))
 	}
+	out.Print(status, func() { printDaemonStatus(status) })
+}
 
+func printDaemonStatus(status protocol.DaemonStatusResult) {
 	fmt.Println(i18n.T("cli.daemon.status_header"))
 	fmt.Println(i18n.T("cli.daemon.status_version", map[string]any{"Version": status.Version}))
 	fmt.Println(i18n.T("cli.daemon.status_pid", map[string]any{"PID": status.PID}))
-	fmt.Println(i18n.T("cli.daemon.status_uptime", map[string]any{"Uptime": status.Uptime}))
+	if status.StartedAt != "" {
+		fmt.Println(i18n.T("cli.daemon.status_started", map[string]any{"Time": format.TimeString(status.StartedAt)}))
+	}
+	fmt.Println(i18n.T("cli.daemon.status_uptime", map[string]any{"Uptime": format.DurationString(status.Uptime)}))
 	fmt.Println(i18n.T("cli.daemon.status_clients", map[string]any{"Count": status.ConnectedClients}))
 	fmt.Println(i18n.T("cli.daemon.status_ssh", map[string]any{"Count": status.ActiveSSHConnections}))
 	fmt.Println(i18n.T("cli.daemon.status_forwards", map[string]any{"Count": status.ActiveForwards}))
@@ -141,7 +159,7 @@ func runDaemonStatus(configDir string) {
 // RunDaemonMode はデーモンモードで起動する。
 // --daemon-mode フラグが検出された場合に呼び出される。
 func RunDaemonMode(configDir string) {
-	logFile, err := setupDaemonLogging(configDir)
+	logFile, logs, err := setupDaemonLogging(configDir)
 	if err != nil {
 		slog.Error("failed to setup logging", "error", err)
 		cli.ExitFunc(1)
@@ -153,6 +171,7 @@ func RunDaemonMode(configDir string) {
 		slog.Error("failed to create daemon", "error", err)
 		cli.ExitFunc(1)
 	}
+	d.SetLogs(logs)
 
 	ctx := context.Background()
 	if err := d.Start(ctx); err != nil {
@@ -167,34 +186,23 @@ func RunDaemonMode(configDir string) {
 }
 
 // setupDaemonLogging はデーモンプロセス用のログ設定を行う。
-func setupDaemonLogging(configDir string) (*os.File, error) {
+// ログはファイルに出力し、logs.subscribe で配信するために直近のログを返り値の Buffer にも保持する。
+func setupDaemonLogging(configDir string) (*os.File, *logbuf.Buffer, error) {
 	logCfg := daemon.ResolveLogConfig(configDir)
 
 	if err := os.MkdirAll(filepath.Dir(logCfg.Path), 0700); err != nil {
-		return nil, fmt.Errorf("create log directory: %w", err)
+		return nil, nil, fmt.Errorf("create log directory: %w", err)
 	}
 
 	f, err := os.OpenFile(logCfg.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
 	if err != nil {
-		return nil, fmt.Errorf("open log file: %w", err)
+		return nil, nil, fmt.Errorf("open log file: %w", err)
 	}
 
-	level := parseSlogLevel(logCfg.Level)
-	handler := slog.NewTextHandler(f, &slog.HandlerOptions{Level: level})
-	slog.SetDefault(slog.New(handler))
-	return f, nil
-}
-
-// parseSlogLevel は文字列を slog.Level に変換する。
-func parseSlogLevel(s string) slog.Level {
-	switch strings.ToLower(s) {
-	case "debug":
-		return slog.LevelDebug
-	case "warn", "warning":
-		return slog.LevelWarn
-	case "error":
-		return slog.LevelError
-	default:
-		return slog.LevelInfo
-	}
+	// config.yaml の log.level の変更は稼働中に liveconfig.LogLevel へ反映される
+	liveconfig.LogLevel.Set(liveconfig.ParseLevel(logCfg.Level))
+	handler := slog.NewTextHandler(f, &slog.HandlerOptions{Level: &liveconfig.LogLevel})
+	logs := logbuf.New(logbuf.DefaultSize)
+	slog.SetDefault(slog.New(logs.Wrap(handler)))
+	return f, logs, nil
 }
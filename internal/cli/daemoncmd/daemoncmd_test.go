@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"fmt"
 	"io"
-	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
@@ -81,7 +80,7 @@ func writeFakePID(t *testing.T, configDir string) {
 
 func TestSetupDaemonLogging_DefaultLogPath(t *testing.T) {
 	tmpDir := t.TempDir()
-	f, err := setupDaemonLogging(tmpDir)
+	f, _, err := setupDaemonLogging(tmpDir)
 	if err != nil {
 		t.Fatalf("setupDaemonLogging() error = %v", err)
 	}
@@ -113,7 +112,7 @@ func TestSetupDaemonLogging_CustomLogPath(t *testing.T) {
 	if err := os.WriteFile(filepath.Join(tmpDir, "config.yaml"), cfgData, 0600); err != nil {
 		t.Fatalf("write config: %v", err)
 	}
-	f, err := setupDaemonLogging(tmpDir)
+	f, _, err := setupDaemonLogging(tmpDir)
 	if err != nil {
 		t.Fatalf("setupDaemonLogging() error = %v", err)
 	}
@@ -161,12 +160,20 @@ func TestRunDaemonStop_InvalidFlag(t *testing.T) {
 }
 
 func TestRunDaemonStatus_NotRunning(t *testing.T) {
-	output := captureStdout(t, func() { runDaemonStatus(t.TempDir()) })
+	output := captureStdout(t, func() { runDaemonStatus(t.TempDir(), nil) })
 	if output == "" {
 		t.Error("runDaemonStatus should produce output when daemon is not running")
 	}
 }
 
+func TestRunDaemonStatus_InvalidFlag(t *testing.T) {
+	stubExit(t)
+	code, _ := captureExit(t, func() { runDaemonStatus(t.TempDir(), []string{"--bad-flag"}) })
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
 func TestRunDaemonKill_NotRunning(t *testing.T) {
 	output := captureStdout(t, func() { runDaemonKill(t.TempDir()) })
 	if output == "" {
@@ -217,7 +224,7 @@ func TestRunDaemonStatus_RunningButNoSocket(t *testing.T) {
 	stubExit(t)
 	configDir := t.TempDir()
 	writeFakePID(t, configDir)
-	code, stderr := captureExit(t, func() { runDaemonStatus(configDir) })
+	code, stderr := captureExit(t, func() { runDaemonStatus(configDir, nil) })
 	if code != 1 {
 		t.Errorf("exit code = %d, want 1", code)
 	}
@@ -238,29 +245,3 @@ func TestRunDaemonKill_RunningProcess(t *testing.T) {
 		t.Error("runDaemonKill should produce output")
 	}
 }
-
-func TestParseSlogLevel(t *testing.T) {
-	tests := []struct {
-		input string
-		want  slog.Level
-	}{
-		{"debug", slog.LevelDebug},
-		{"DEBUG", slog.LevelDebug},
-		{"info", slog.LevelInfo},
-		{"INFO", slog.LevelInfo},
-		{"warn", slog.LevelWarn},
-		{"warning", slog.LevelWarn},
-		{"error", slog.LevelError},
-		{"ERROR", slog.LevelError},
-		{"unknown", slog.LevelInfo},
-		{"", slog.LevelInfo},
-	}
-	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			got := parseSlogLevel(tt.input)
-			if got != tt.want {
-				t.Errorf("parseSlogLevel(%q) = %v, want %v", tt.input, got, tt.want)
-			}
-		})
-	}
-}
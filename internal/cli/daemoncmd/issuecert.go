@@ -0,0 +1,45 @@
+package daemoncmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/tlsipc"
+)
+
+// runIssueCert は daemon issue-cert サブコマンドを実行する。
+// デーモンの CA（未作成なら作成する）で name のクライアント証明書（--read-only なら読み取り専用）を発行し、
+// CA 証明書・クライアント証明書・秘密鍵をまとめた PEM を --output のファイル（省略時は標準出力）に書き出す。
+func runIssueCert(configDir string, args []string) {
+	fs := flag.NewFlagSet("daemon issue-cert", flag.ContinueOnError)
+	output := fs.String("output", "", i18n.T("cli.flags.daemon.cert_output"))
+	readOnly := fs.Bool("read-only", false, i18n.T("cli.flags.daemon.cert_read_only"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+	if fs.NArg() != 1 {
+		cli.ExitError("%s", i18n.T("cli.daemon.issue_cert_usage"))
+	}
+	name := fs.Arg(0)
+
+	ca, err := tlsipc.LoadOrCreateCA(tlsipc.Dir(configDir))
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.daemon.issue_cert_failed", map[string]any{"Error": err}))
+	}
+	bundle, err := ca.ClientBundle(name, *readOnly)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.daemon.issue_cert_failed", map[string]any{"Error": err}))
+	}
+
+	if *output == "" {
+		_, _ = os.Stdout.Write(bundle)
+		return
+	}
+	if err := os.WriteFile(*output, bundle, 0600); err != nil {
+		cli.ExitErr(err, i18n.T("cli.daemon.issue_cert_failed", map[string]any{"Error": err}))
+	}
+	fmt.Println(i18n.T("cli.daemon.issue_cert_written", map[string]any{"Name": name, "Path": *output}))
+}
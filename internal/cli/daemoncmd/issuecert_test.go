@@ -0,0 +1,30 @@
+package daemoncmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/tlsipc"
+)
+
+func TestRunIssueCert_WritesBundle(t *testing.T) {
+	stubExit(t)
+	configDir := t.TempDir()
+	out := filepath.Join(t.TempDir(), "laptop.pem")
+
+	captureStdout(t, func() { runIssueCert(configDir, []string{"--output", out, "laptop"}) })
+	if _, err := tlsipc.LoadClientConfig(out); err != nil {
+		t.Errorf("LoadClientConfig() error = %v", err)
+	}
+	if _, err := tlsipc.LoadOrCreateCA(tlsipc.Dir(configDir)); err != nil {
+		t.Errorf("the daemon CA should have been created: %v", err)
+	}
+}
+
+func TestRunIssueCert_NameRequired(t *testing.T) {
+	stubExit(t)
+	code, _ := captureExit(t, func() { runIssueCert(t.TempDir(), nil) })
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,2 @@
+// Package editcmd は edit サブコマンドの実装を提供する。
+package editcmd
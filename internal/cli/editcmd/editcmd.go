@@ -0,0 +1,74 @@
+package editcmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RunEdit は edit サブコマンドを実行する。
+// 明示的に指定したフラグの項目だけを書き換え、アクティブなフォワードは新しい内容で再開する。
+func RunEdit(configDir string, args []string) {
+	fs := flag.NewFlagSet("edit", flag.ContinueOnError)
+	localPort := fs.Int("local-port", 0, i18n.T("cli.flags.edit.local_port"))
+	remoteHost := fs.String("remote-host", "", i18n.T("cli.flags.edit.remote_host"))
+	remotePort := fs.Int("remote-port", 0, i18n.T("cli.flags.edit.remote_port"))
+	localBindAddr := fs.String("local-bind-addr", "", i18n.T("cli.flags.edit.local_bind_addr"))
+	remoteBindAddr := fs.String("remote-bind-addr", "", i18n.T("cli.flags.edit.remote_bind_addr"))
+	autoConnect := fs.Bool("auto-connect", false, i18n.T("cli.flags.edit.auto_connect"))
+	autoReconnect := fs.Bool("auto-reconnect", false, i18n.T("cli.flags.edit.auto_reconnect"))
+	ttl := fs.String("ttl", "", i18n.T("cli.flags.edit.ttl"))
+	accessLog := fs.String("access-log", "", i18n.T("cli.flags.edit.access_log"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	// `edit <name> --local-port 8081` のようにルール名の後ろに置かれたフラグも解釈する
+	name := fs.Arg(0)
+	if name != "" {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			cli.ExitError("%v", err)
+		}
+	}
+	if name == "" {
+		cli.ExitError("%s", i18n.T("cli.edit.usage"))
+	}
+
+	params := protocol.ForwardUpdateParams{Name: name}
+	fields := map[string]func(){
+		"local-port":       func() { params.LocalPort = localPort },
+		"remote-host":      func() { params.RemoteHost = remoteHost },
+		"remote-port":      func() { params.RemotePort = remotePort },
+		"local-bind-addr":  func() { params.LocalBindAddr = localBindAddr },
+		"remote-bind-addr": func() { params.RemoteBindAddr = remoteBindAddr },
+		"auto-connect":     func() { params.AutoConnect = autoConnect },
+		"auto-reconnect":   func() { params.AutoReconnect = autoReconnect },
+		"ttl":              func() { params.TTL = ttl },
+		"access-log":       func() { params.AccessLog = accessLog },
+	}
+	changed := 0
+	fs.Visit(func(f *flag.Flag) {
+		fields[f.Name]()
+		changed++
+	})
+	if changed == 0 {
+		cli.ExitError("%s", i18n.T("cli.edit.no_changes"))
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	var result protocol.ForwardUpdateResult
+	if err := client.Call(ctx, "forward.update", params, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.edit.failed", map[string]any{"Error": err}))
+	}
+
+	key := "cli.edit.success"
+	if result.Status == protocol.SessionActive {
+		key = "cli.edit.restarted"
+	}
+	fmt.Println(i18n.T(key, map[string]any{"Name": result.Name}))
+}
@@ -0,0 +1,42 @@
+package editcmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli/clitest"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestRunEdit_SendsOnlyChangedFields(t *testing.T) {
+	got := clitest.StubDaemon(t, func(req protocol.Request) any {
+		p := clitest.Params[protocol.ForwardUpdateParams](req)
+		return protocol.ForwardUpdateResult{Name: p.Name, Status: protocol.SessionActive}
+	})
+
+	out := clitest.CaptureStdout(t, func() {
+		RunEdit(t.TempDir(), []string{"db", "--local-port", "15433", "--auto-reconnect", "--ttl="})
+	})
+
+	p := clitest.Params[protocol.ForwardUpdateParams](<-got)
+	if p.Name != "db" || p.LocalPort == nil || *p.LocalPort != 15433 {
+		t.Errorf("params = %+v, want db with local port 15433", p)
+	}
+	if p.AutoReconnect == nil || !*p.AutoReconnect || p.TTL == nil || *p.TTL != "" {
+		t.Errorf("params = %+v, want auto_reconnect and cleared ttl", p)
+	}
+	if p.RemoteHost != nil || p.RemotePort != nil || p.AutoConnect != nil || p.AccessLog != nil {
+		t.Errorf("params = %+v, want unspecified fields omitted", p)
+	}
+	if !strings.Contains(out, "db") {
+		t.Errorf("output = %q, want rule name", out)
+	}
+}
+
+func TestRunEdit_MissingArgs(t *testing.T) {
+	clitest.StubExit(t)
+
+	for _, args := range [][]string{nil, {"db"}, {"--local-port", "8080"}} {
+		clitest.ExpectExit(t, func() { RunEdit(t.TempDir(), args) })
+	}
+}
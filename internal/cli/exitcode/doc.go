@@ -0,0 +1,2 @@
+// Package exitcode は CLI の終了コードと、デーモンが返したエラーから終了コードを決める対応表を提供する。
+package exitcode
@@ -0,0 +1,60 @@
+package exitcode
+
+import (
+	"errors"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// 終了コード。デーモンが返したエラーは種類ごとに異なるコードで終了し、スクリプトから原因を判別できるようにする。
+// 2 と 3 は health サブコマンドが稼働状況に使う。
+const (
+	Failure      = 1 // 引数の誤りや種類を分類できないエラー
+	NotFound     = 4 // ホスト・ルール・グループが見つからない
+	Conflict     = 5 // ルールの重複・ポートの使用中・接続済み
+	AuthFailed   = 6 // 認証の失敗、クレデンシャルの入力のタイムアウト・取り消し
+	NotConnected = 7 // ホストが接続されていない
+	Invalid      = 8 // パラメータ・ルール名・設定の誤り
+	Denied       = 9 // 認証されていない、または権限がない
+)
+
+// codes は RPC のエラーコードごとの終了コード。
+var codes = map[int]int{
+	protocol.HostNotFound:         NotFound,
+	protocol.RuleNotFound:         NotFound,
+	protocol.GroupNotFound:        NotFound,
+	protocol.RuleAlreadyExists:    Conflict,
+	protocol.PortConflict:         Conflict,
+	protocol.AlreadyConnected:     Conflict,
+	protocol.AuthenticationFailed: AuthFailed,
+	protocol.CredentialTimeout:    AuthFailed,
+	protocol.CredentialCancelled:  AuthFailed,
+	protocol.NotConnected:         NotConnected,
+	protocol.InvalidParams:        Invalid,
+	protocol.InvalidRuleName:      Invalid,
+	protocol.InvalidConfig:        Invalid,
+	protocol.Unauthorized:         Denied,
+	protocol.PermissionDenied:     Denied,
+}
+
+// Of は err に応じた終了コードを返す。デーモンが返した *protocol.RPCError はエラーコードから決め、
+// それ以外のエラーは Failure を返す。
+func Of(err error) int {
+	var rpcErr *protocol.RPCError
+	if errors.As(err, &rpcErr) {
+		if code, ok := codes[rpcErr.Code]; ok {
+			return code
+		}
+	}
+	return Failure
+}
+
+// OfArgs は書式の引数に含まれる最初のエラーの終了コードを返す。エラーを含まない場合は Failure を返す。
+func OfArgs(args []any) int {
+	for _, arg := range args {
+		if err, ok := arg.(error); ok {
+			return Of(err)
+		}
+	}
+	return Failure
+}
@@ -0,0 +1,43 @@
+package exitcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"rule not found", &protocol.RPCError{Code: protocol.RuleNotFound}, NotFound},
+		{"wrapped port conflict", fmt.Errorf("start: %w", &protocol.RPCError{Code: protocol.PortConflict}), Conflict},
+		{"auth failed", &protocol.RPCError{Code: protocol.AuthenticationFailed}, AuthFailed},
+		{"not connected", &protocol.RPCError{Code: protocol.NotConnected}, NotConnected},
+		{"invalid config", &protocol.RPCError{Code: protocol.InvalidConfig}, Invalid},
+		{"permission denied", &protocol.RPCError{Code: protocol.PermissionDenied}, Denied},
+		{"unmapped code", &protocol.RPCError{Code: protocol.InternalError}, Failure},
+		{"local error", errors.New("boom"), Failure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Of(tt.err); got != tt.want {
+				t.Errorf("Of(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOfArgs(t *testing.T) {
+	err := &protocol.RPCError{Code: protocol.RuleNotFound}
+	if got := OfArgs([]any{"web", err}); got != NotFound {
+		t.Errorf("OfArgs(with error) = %d, want %d", got, NotFound)
+	}
+	if got := OfArgs([]any{"usage"}); got != Failure {
+		t.Errorf("OfArgs(without error) = %d, want %d", got, Failure)
+	}
+}
@@ -0,0 +1,22 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli/exitcode"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestExitError_UsesErrorArgument(t *testing.T) {
+	stubExit(t)
+	err := &protocol.RPCError{Code: protocol.RuleNotFound, Message: "rule \"web\" not found"}
+	if code, _ := captureExit(t, func() { ExitError("%v", err) }); code != exitcode.NotFound {
+		t.Errorf("ExitError(err) code = %d, want %d", code, exitcode.NotFound)
+	}
+	if code, _ := captureExit(t, func() { ExitError("%s", "usage") }); code != exitcode.Failure {
+		t.Errorf("ExitError(usage) code = %d, want %d", code, exitcode.Failure)
+	}
+	if code, stderr := captureExit(t, func() { ExitErr(err, "failed") }); code != exitcode.NotFound || stderr == "" {
+		t.Errorf("ExitErr() = %d, %q, want %d with a message", code, stderr, exitcode.NotFound)
+	}
+}
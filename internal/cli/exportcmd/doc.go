@@ -0,0 +1,2 @@
+// Package exportcmd は export / import サブコマンドの実装を提供する。
+package exportcmd
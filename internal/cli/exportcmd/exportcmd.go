@@ -0,0 +1,135 @@
+package exportcmd
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core/ruleio"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/infra/sshconfig"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RunExport は export サブコマンドを実行する。ルールとホスト別設定を標準出力または -o のファイルに書き出す。
+func RunExport(configDir string, args []string) {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "", i18n.T("cli.flags.export.format"))
+	output := fs.String("o", "", i18n.T("cli.flags.export.output"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+	if *format == "" {
+		*format = formatOf(*output)
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	var result protocol.ForwardExportResult
+	if err := client.Call(ctx, "forward.export", protocol.ForwardExportParams{Format: *format}, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.export.failed", map[string]any{"Error": err}))
+	}
+	if *output == "" {
+		fmt.Print(result.Data)
+		return
+	}
+	if err := os.WriteFile(*output, []byte(result.Data), 0o600); err != nil {
+		cli.ExitErr(err, i18n.T("cli.export.failed", map[string]any{"Error": err}))
+	}
+	fmt.Println(i18n.T("cli.export.written", map[string]any{"Path": *output}))
+}
+
+// RunImport は import サブコマンドを実行する。
+// export で書き出した文書（ファイルまたは "-" で標準入力）か、--ssh-config の ssh_config のフォワードの指定を取り込む。
+func RunImport(configDir string, args []string) {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	replace := fs.Bool("replace", false, i18n.T("cli.flags.import.replace"))
+	dryRun := fs.Bool("dry-run", false, i18n.T("cli.flags.import.dry_run"))
+	sshConfig := fs.String("ssh-config", "", i18n.T("cli.flags.import.ssh_config"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	// `import <file> --replace` のようにファイルの後ろに置かれたフラグも解釈する
+	path := fs.Arg(0)
+	if path != "" {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			cli.ExitError("%v", err)
+		}
+	}
+	if (path == "") == (*sshConfig == "") {
+		cli.ExitError("%s", i18n.T("cli.import.usage"))
+	}
+	data, err := readDocument(path, *sshConfig)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.import.failed", map[string]any{"Error": err}))
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	params := protocol.ForwardImportParams{Data: data, Replace: *replace, DryRun: *dryRun}
+	var result protocol.ForwardImportResult
+	if err := client.Call(ctx, "forward.import", params, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.import.failed", map[string]any{"Error": err}))
+	}
+	printResult(result)
+	if *dryRun {
+		fmt.Println(i18n.T("cli.import.dry_run"))
+	}
+}
+
+// readDocument は取り込む文書を返す。sshConfig が指定された場合はフォワードの指定をルールに変換した文書を返す。
+func readDocument(path, sshConfig string) (string, error) {
+	if sshConfig != "" {
+		rules, err := sshconfig.Forwards(sshConfig)
+		if err != nil {
+			return "", err
+		}
+		data, err := ruleio.Encode(ruleio.New(rules, nil), ruleio.FormatYAML)
+		return string(data), err
+	}
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	return string(data), err
+}
+
+// printResult は反映した変更と見送ったルールを 1 行ずつ表示する。
+func printResult(result protocol.ForwardImportResult) {
+	for _, name := range result.Added {
+		fmt.Printf("  + %s\n", name)
+	}
+	for _, name := range result.Replaced {
+		fmt.Printf("  ~ %s\n", name)
+	}
+	for _, name := range result.Hosts {
+		fmt.Printf("  * host %s\n", name)
+	}
+	fmt.Println(i18n.T("cli.import.done", map[string]any{
+		"Added": len(result.Added), "Replaced": len(result.Replaced), "Hosts": len(result.Hosts),
+	}))
+	if len(result.Skipped) > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.import.skipped", map[string]any{"Count": len(result.Skipped)}))
+		for _, s := range result.Skipped {
+			fmt.Fprintf(os.Stderr, "  ! %s\n", s)
+		}
+	}
+}
+
+// formatOf は出力先のファイルの拡張子から形式を返す。.json 以外は yaml とする。
+func formatOf(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return ruleio.FormatJSON
+	}
+	return ruleio.FormatYAML
+}
@@ -0,0 +1,82 @@
+package exportcmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli/clitest"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// stubDaemon は forward.export と forward.import に応答するモックデーモンに接続させ、受信したリクエストを返す。
+func stubDaemon(t *testing.T) <-chan protocol.Request {
+	t.Helper()
+	return clitest.StubDaemon(t, func(req protocol.Request) any {
+		if req.Method == "forward.export" {
+			return protocol.ForwardExportResult{Format: "json", Data: "{}\n"}
+		}
+		return protocol.ForwardImportResult{Added: []string{"db"}, Skipped: []string{"web: rule already exists"}}
+	})
+}
+
+func TestRunExport_WritesFileInFormatOfExtension(t *testing.T) {
+	got := stubDaemon(t)
+	path := filepath.Join(t.TempDir(), "rules.json")
+
+	clitest.CaptureStdout(t, func() { RunExport(t.TempDir(), []string{"-o", path}) })
+
+	req := <-got
+	p := clitest.Params[protocol.ForwardExportParams](req)
+	if req.Method != "forward.export" || p.Format != "json" {
+		t.Errorf("request = %s %+v, want forward.export in json", req.Method, p)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "{}\n" {
+		t.Errorf("file = %q, %v", data, err)
+	}
+}
+
+func TestRunImport_SSHConfig(t *testing.T) {
+	got := stubDaemon(t)
+	sshConfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(sshConfig, []byte("Host db\n    LocalForward 15432 localhost:5432\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out := clitest.CaptureStdout(t, func() {
+		RunImport(t.TempDir(), []string{"--ssh-config", sshConfig, "--dry-run"})
+	})
+
+	req := <-got
+	p := clitest.Params[protocol.ForwardImportParams](req)
+	if req.Method != "forward.import" || !p.DryRun || !strings.Contains(p.Data, "local_port: 15432") {
+		t.Errorf("request = %s %+v", req.Method, p)
+	}
+	if !strings.Contains(out, "+ db") {
+		t.Errorf("output = %q, want added rule", out)
+	}
+}
+
+func TestRunImport_FileWithTrailingFlags(t *testing.T) {
+	got := stubDaemon(t)
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\nforwards: []\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	clitest.CaptureStdout(t, func() { RunImport(t.TempDir(), []string{path, "--replace"}) })
+
+	p := clitest.Params[protocol.ForwardImportParams](<-got)
+	if !p.Replace || p.Data != "version: 1\nforwards: []\n" {
+		t.Errorf("params = %+v, want file contents with replace", p)
+	}
+}
+
+func TestRunImport_InvalidArgs(t *testing.T) {
+	clitest.StubExit(t)
+
+	for _, args := range [][]string{nil, {"rules.yaml", "--ssh-config", "config"}, {"missing.yaml"}} {
+		clitest.ExpectExit(t, func() { RunImport(t.TempDir(), args) })
+	}
+}
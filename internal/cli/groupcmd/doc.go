@@ -0,0 +1,2 @@
+// Package groupcmd はフォワードグループを一括で開始・停止する up / down サブコマンドの実装を提供する。
+package groupcmd
@@ -0,0 +1,53 @@
+package groupcmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RunUp は up サブコマンドを実行する。
+// グループの全ルールを開始し、いずれかが失敗した場合は開始したルールをデーモンが停止して元に戻す。
+func RunUp(configDir string, args []string) {
+	if len(args) == 0 {
+		cli.ExitError("%s", i18n.T("cli.group.up_usage"))
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	var result protocol.ForwardStartGroupResult
+	if err := client.Call(ctx, "forward.startGroup", protocol.ForwardGroupParams{Name: args[0]}, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.group.up_failed", map[string]any{"Error": err}))
+	}
+
+	if len(result.Started) == 0 {
+		fmt.Println(i18n.T("cli.group.already_up", map[string]any{"Name": result.Name}))
+		return
+	}
+	fmt.Println(i18n.T("cli.group.up", map[string]any{
+		"Name":  result.Name,
+		"Count": len(result.Started),
+		"Rules": strings.Join(result.Started, ", "),
+	}))
+}
+
+// RunDown は down サブコマンドを実行する。グループのルールのうちアクティブなものを停止する。
+func RunDown(configDir string, args []string) {
+	if len(args) == 0 {
+		cli.ExitError("%s", i18n.T("cli.group.down_usage"))
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	var result protocol.ForwardStopGroupResult
+	if err := client.Call(ctx, "forward.stopGroup", protocol.ForwardGroupParams{Name: args[0]}, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.group.down_failed", map[string]any{"Error": err}))
+	}
+
+	fmt.Println(i18n.T("cli.group.down", map[string]any{"Name": result.Name, "Count": len(result.Stopped)}))
+}
@@ -0,0 +1,170 @@
+package groupcmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type exitCalled struct{ code int }
+
+func stubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(exitCalled{code: c}) }
+}
+
+func captureExit(t *testing.T, fn func()) (code int, stderr string) {
+	t.Helper()
+	origStderr := os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = w.Close()
+		_ = r.Close()
+		os.Stderr = origStderr
+	})
+	os.Stderr = w
+	code = -1
+	func() {
+		defer func() {
+			if v := recover(); v != nil {
+				if ec, ok := v.(exitCalled); ok {
+					code = ec.code
+				} else {
+					panic(v)
+				}
+			}
+		}()
+		fn()
+	}()
+	_ = w.Close()
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	return code, buf.String()
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = orig
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String()
+}
+
+// stubConnectDaemon はメソッド名ごとに responses の結果（未登録のメソッドは {}）を返すモックデーモンに接続させる。
+func stubConnectDaemon(t *testing.T, responses map[string]string) {
+	t.Helper()
+	orig := cli.ConnectDaemon
+	t.Cleanup(func() { cli.ConnectDaemon = orig })
+
+	sockPath := filepath.Join(t.TempDir(), "mock.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handleMockConn(conn, responses)
+		}
+	}()
+
+	cli.ConnectDaemon = func(_ string) *client.IPCClient {
+		c := client.NewIPCClient(sockPath)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("mock connect: %v", err)
+		}
+		return c
+	}
+}
+
+func handleMockConn(conn net.Conn, responses map[string]string) {
+	defer func() { _ = conn.Close() }()
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req protocol.Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return
+		}
+		result := json.RawMessage(`{}`)
+		if r, ok := responses[req.Method]; ok {
+			result = json.RawMessage(r)
+		}
+		if err := enc.Encode(protocol.Response{JSONRPC: protocol.JSONRPCVersion, ID: req.ID, Result: result}); err != nil {
+			return
+		}
+	}
+}
+
+func TestRunUp(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+		want   string
+	}{
+		{"started", `{"name":"staging","started":["db","web"]}`, "staging started (2 rules: db, web)"},
+		{"already running", `{"name":"staging","started":[]}`, "All rules in staging are already running"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubConnectDaemon(t, map[string]string{"forward.startGroup": tt.result})
+			output := captureStdout(t, func() {
+				RunUp("", []string{"staging"})
+			})
+			if !strings.Contains(output, tt.want) {
+				t.Errorf("output = %q, want %q", output, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunDown(t *testing.T) {
+	stubConnectDaemon(t, map[string]string{"forward.stopGroup": `{"name":"staging","stopped":["db"]}`})
+	output := captureStdout(t, func() {
+		RunDown("", []string{"staging"})
+	})
+	if !strings.Contains(output, "staging stopped (1 rules)") {
+		t.Errorf("output = %q, want stop summary", output)
+	}
+}
+
+func TestRunUpDown_GroupRequired(t *testing.T) {
+	for _, run := range []func(string, []string){RunUp, RunDown} {
+		stubExit(t)
+		code, stderr := captureExit(t, func() {
+			run(t.TempDir(), nil)
+		})
+		if code != 1 || !strings.Contains(stderr, "moleport") {
+			t.Errorf("exit code = %d, stderr = %q, want 1 with usage", code, stderr)
+		}
+	}
+}
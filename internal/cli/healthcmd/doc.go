@@ -0,0 +1,2 @@
+// Package healthcmd は health サブコマンドの実装を提供する。
+package healthcmd
@@ -0,0 +1,87 @@
+package healthcmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// health サブコマンドの終了コード。コマンドの誤りや RPC の失敗は他のサブコマンドと同じく 1 で終了する。
+const (
+	ExitOK         = 0
+	ExitNotRunning = 2
+	ExitUnhealthy  = 3
+)
+
+// RunHealth は health サブコマンドを実行する。
+// デーモンの稼働状況を表示し、状態に応じた終了コードで終了する。デーモンが停止している場合も起動はしない。
+func RunHealth(configDir string, args []string) {
+	fs := flag.NewFlagSet("health", flag.ContinueOnError)
+	out := cli.OutputFlags(fs)
+	strict := fs.Bool("strict", false, i18n.T("cli.flags.health.strict"))
+	var forwards []string
+	fs.Func("forward", i18n.T("cli.flags.health.forward"), func(v string) error {
+		forwards = append(forwards, strings.Split(v, ",")...)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	if running, _ := pidfile.IsRunning(daemon.PIDFilePath(configDir)); !running {
+		stopped := protocol.DaemonHealthResult{Status: protocol.HealthFail, Checks: []protocol.HealthCheck{
+			{Name: "daemon", Status: protocol.HealthFail, Message: "not running"},
+		}}
+		out.Print(stopped, func() { fmt.Fprintln(os.Stderr, i18n.T("cli.daemon.not_running")) })
+		cli.ExitFunc(ExitNotRunning)
+		return
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	var result protocol.DaemonHealthResult
+	err := client.Call(ctx, "daemon.health", protocol.DaemonHealthParams{Forwards: forwards}, &result)
+	cleanup()
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.health.failed", map[string]any{"Error": err}))
+	}
+
+	out.Print(result, func() { fmt.Print(Format(result)) })
+	cli.ExitFunc(ExitCode(result, *strict))
+}
+
+// ExitCode は稼働状況に応じた終了コードを返す。warn は strict の場合のみ異常とする。
+func ExitCode(result protocol.DaemonHealthResult, strict bool) int {
+	switch result.Status {
+	case protocol.HealthOK:
+		return ExitOK
+	case protocol.HealthWarn:
+		if !strict {
+			return ExitOK
+		}
+	}
+	return ExitUnhealthy
+}
+
+// marks は確認結果の状態ごとの表示記号。
+var marks = map[string]string{protocol.HealthOK: "✓", protocol.HealthWarn: "!", protocol.HealthFail: "✗"}
+
+// Format は daemon.health の結果を 1 行 1 サブシステムの表示用文字列にする。
+func Format(result protocol.DaemonHealthResult) string {
+	width := 0
+	for _, c := range result.Checks {
+		width = max(width, len(c.Name))
+	}
+	var b strings.Builder
+	fmt.Fprintln(&b, i18n.T("cli.health.header", map[string]any{"Status": result.Status}))
+	for _, c := range result.Checks {
+		fmt.Fprintf(&b, "  %s %-*s  %s\n", marks[c.Status], width, c.Name, c.Message)
+	}
+	return b.String()
+}
@@ -0,0 +1,46 @@
+package healthcmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		status string
+		strict bool
+		want   int
+	}{
+		{protocol.HealthOK, true, ExitOK},
+		{protocol.HealthWarn, false, ExitOK},
+		{protocol.HealthWarn, true, ExitUnhealthy},
+		{protocol.HealthFail, false, ExitUnhealthy},
+	}
+	for _, tt := range tests {
+		if got := ExitCode(protocol.DaemonHealthResult{Status: tt.status}, tt.strict); got != tt.want {
+			t.Errorf("ExitCode(%q, strict=%v) = %d, want %d", tt.status, tt.strict, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	got := Format(protocol.DaemonHealthResult{Status: protocol.HealthFail, Checks: []protocol.HealthCheck{
+		{Name: "socket", Status: protocol.HealthOK, Message: "/tmp/moleport.sock"},
+		{Name: "forwards", Status: protocol.HealthWarn, Message: "1 active, 1 errors"},
+		{Name: "forward:db", Status: protocol.HealthFail, Message: "rule not found"},
+	}})
+
+	lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+	if len(lines) != 4 || !strings.Contains(lines[0], "fail") {
+		t.Fatalf("Format() = %q, want header and 3 checks", got)
+	}
+	// メッセージの開始位置をそろえる
+	want := []string{"  ✓ socket      /tmp/moleport.sock", "  ! forwards    1 active, 1 errors", "  ✗ forward:db  rule not found"}
+	for i, w := range want {
+		if lines[i+1] != w {
+			t.Errorf("line %d = %q, want %q", i+1, lines[i+1], w)
+		}
+	}
+}
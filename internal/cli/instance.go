@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"path/filepath"
+	"regexp"
+
+	"github.com/ousiassllc/moleport/internal/i18n"
+)
+
+// InstancesDirName は既定以外のインスタンスの設定ディレクトリを置くディレクトリ名。
+const InstancesDirName = "instances"
+
+// instanceNamePattern はインスタンス名として使える文字列。ディレクトリ名として安全な文字に限る。
+var instanceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// Instance は操作対象のデーモンのインスタンス名。ParseGlobalFlags が --socket フラグまたは
+// 環境変数 MOLEPORT_SOCKET から設定する。空の場合は既定のインスタンスを使用する。
+var Instance string
+
+// ValidInstanceName はインスタンス名が有効かを返す。
+func ValidInstanceName(name string) bool {
+	return instanceNamePattern.MatchString(name)
+}
+
+// InstanceDir は baseDir のインスタンス name の設定ディレクトリを返す。name が空の場合は baseDir を返す。
+func InstanceDir(baseDir, name string) string {
+	if name == "" {
+		return baseDir
+	}
+	return filepath.Join(baseDir, InstancesDirName, name)
+}
+
+// InstanceConfigDir は Instance の設定ディレクトリを返す。インスタンスごとに設定・状態・ソケットを分離する。
+// Instance が無効な名前の場合はエラーで終了する。
+func InstanceConfigDir(baseDir string) string {
+	if Instance != "" && !ValidInstanceName(Instance) {
+		ExitError("%s", i18n.T("cli.error.invalid_instance", map[string]any{"Name": Instance}))
+	}
+	return InstanceDir(baseDir, Instance)
+}
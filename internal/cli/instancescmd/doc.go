@@ -0,0 +1,2 @@
+// Package instancescmd は --socket で分離したデーモンのインスタンスを一覧表示する instances サブコマンドを提供する。
+package instancescmd
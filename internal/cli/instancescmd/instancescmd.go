@@ -0,0 +1,98 @@
+package instancescmd
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/i18n"
+)
+
+// defaultName は既定のインスタンスの表示名。
+const defaultName = "default"
+
+// Instance はデーモンのインスタンスの状態。
+type Instance struct {
+	Name      string `json:"name"`
+	Default   bool   `json:"default"`
+	Current   bool   `json:"current"`
+	ConfigDir string `json:"config_dir"`
+	Socket    string `json:"socket"`
+	Running   bool   `json:"running"`
+	PID       int    `json:"pid,omitempty"`
+}
+
+// RunInstances は instances サブコマンドを実行する。
+// baseDir はインスタンスを選択する前の設定ディレクトリ。既定のインスタンスと baseDir/instances 以下のインスタンスを表示する。
+func RunInstances(baseDir string, args []string) {
+	fs := flag.NewFlagSet("instances", flag.ContinueOnError)
+	out := cli.OutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+	instances, err := List(baseDir, cli.Instance)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.instances.failed", map[string]any{"Error": err}))
+	}
+	out.Print(instances, func() { printInstances(instances) })
+}
+
+// List は baseDir の既定のインスタンスと、baseDir/instances 以下のインスタンスを名前順に返す。
+// current は選択中のインスタンス名（空の場合は既定のインスタンス）。
+func List(baseDir, current string) ([]Instance, error) {
+	instances := []Instance{inspect(baseDir, "", current)}
+	entries, err := os.ReadDir(filepath.Join(baseDir, cli.InstancesDirName))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && cli.ValidInstanceName(e.Name()) {
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		instances = append(instances, inspect(baseDir, name, current))
+	}
+	return instances, nil
+}
+
+// inspect はインスタンス name の状態を PID ファイルから調べる。
+func inspect(baseDir, name, current string) Instance {
+	dir := cli.InstanceDir(baseDir, name)
+	running, pid := pidfile.IsRunning(daemon.PIDFilePath(dir))
+	inst := Instance{
+		Name:      name,
+		Default:   name == "",
+		Current:   name == current,
+		ConfigDir: dir,
+		Socket:    daemon.SocketPath(dir),
+		Running:   running,
+		PID:       pid,
+	}
+	if inst.Default {
+		inst.Name = defaultName
+	}
+	return inst
+}
+
+// printInstances はインスタンスの一覧を表示する。選択中のインスタンスには * を付ける。
+func printInstances(instances []Instance) {
+	for _, inst := range instances {
+		mark := " "
+		if inst.Current {
+			mark = "*"
+		}
+		status := i18n.T("cli.instances.stopped")
+		if inst.Running {
+			status = i18n.T("cli.instances.running", map[string]any{"PID": inst.PID})
+		}
+		fmt.Printf("%s %-16s %-22s %s\n", mark, inst.Name, status, inst.ConfigDir)
+	}
+}
@@ -0,0 +1,110 @@
+package instancescmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/daemon"
+)
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = orig
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String()
+}
+
+// setupInstances は既定のインスタンスと work・personal のインスタンスを作成し、work を実行中にする。
+func setupInstances(t *testing.T) string {
+	t.Helper()
+	base := t.TempDir()
+	for _, name := range []string{"work", "personal", ".hidden"} {
+		if err := os.MkdirAll(filepath.Join(base, cli.InstancesDirName, name), 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pid := strconv.Itoa(os.Getpid())
+	if err := os.WriteFile(daemon.PIDFilePath(cli.InstanceDir(base, "work")), []byte(pid), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return base
+}
+
+func TestList(t *testing.T) {
+	base := setupInstances(t)
+
+	got, err := List(base, "work")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var names []string
+	for _, inst := range got {
+		names = append(names, inst.Name)
+	}
+	if strings.Join(names, ",") != "default,personal,work" {
+		t.Fatalf("names = %v, want [default personal work]", names)
+	}
+	if !got[0].Default || got[0].ConfigDir != base || got[0].Running {
+		t.Errorf("default instance = %+v", got[0])
+	}
+	work := got[2]
+	if !work.Current || !work.Running || work.PID != os.Getpid() {
+		t.Errorf("work instance = %+v, want current and running", work)
+	}
+	if work.Socket != daemon.SocketPath(filepath.Join(base, "instances", "work")) {
+		t.Errorf("work socket = %q", work.Socket)
+	}
+	if got[1].Current || got[1].Running {
+		t.Errorf("personal instance = %+v, want stopped", got[1])
+	}
+}
+
+func TestList_NoInstancesDir(t *testing.T) {
+	got, err := List(t.TempDir(), "")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(got) != 1 || !got[0].Default || !got[0].Current {
+		t.Errorf("List = %+v, want only the current default instance", got)
+	}
+}
+
+func TestRunInstances_JSON(t *testing.T) {
+	base := setupInstances(t)
+
+	out := captureStdout(t, func() { RunInstances(base, []string{"--json"}) })
+	var got []Instance
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if len(got) != 3 || !got[0].Current {
+		t.Errorf("instances = %+v, want 3 with the default instance current", got)
+	}
+}
+
+func TestRunInstances_Text(t *testing.T) {
+	base := setupInstances(t)
+
+	out := captureStdout(t, func() { RunInstances(base, nil) })
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "* default") {
+		t.Errorf("output = %q, want 3 lines with the default instance marked", out)
+	}
+}
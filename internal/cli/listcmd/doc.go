@@ -0,0 +1,2 @@
+// Package listcmd は list サブコマンドの実装を提供する。
+package listcmd
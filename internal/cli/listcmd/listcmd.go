@@ -0,0 +1,140 @@
+package listcmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/format"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RunList は list サブコマンドを実行する。
+// --long を指定するとフォワードごとのセッション状態と停止理由も表示する。
+func RunList(configDir string, args []string) {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	out := cli.OutputFlags(fs)
+	hostFlag := fs.String("host", "", i18n.T("cli.flags.list.host"))
+	longFlag := fs.Bool("long", false, i18n.T("cli.flags.list.long"))
+
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	// ホスト一覧を取得
+	var hosts protocol.HostListResult
+	if err := client.Call(ctx, "host.list", nil, &hosts); err != nil {
+		cli.ExitErr(err, i18n.T("cli.list.get_hosts_failed", map[string]any{"Error": err}))
+	}
+
+	// フォワードルール一覧を取得
+	fwdParams := protocol.ForwardListParams{Host: *hostFlag}
+	var forwards protocol.ForwardListResult
+	if err := client.Call(ctx, "forward.list", fwdParams, &forwards); err != nil {
+		cli.ExitErr(err, i18n.T("cli.list.get_forwards_failed", map[string]any{"Error": err}))
+	}
+
+	// --long の場合はセッション情報も取得
+	var sessions protocol.SessionListResult
+	if *longFlag {
+		if err := client.Call(ctx, "session.list", nil, &sessions); err != nil {
+			cli.ExitErr(err, i18n.T("cli.list.get_sessions_failed", map[string]any{"Error": err}))
+		}
+	}
+
+	l := listing{Hosts: hosts.Hosts, Forwards: forwards.Forwards, Sessions: sessions.Sessions}
+	out.Print(l, func() { printListing(l, *hostFlag) })
+}
+
+// listing は list の出力内容。JSON では host.list・forward.list・session.list の結果をそのまま出力する。
+type listing struct {
+	Hosts    []protocol.HostInfo    `json:"hosts"`
+	Forwards []protocol.ForwardInfo `json:"forwards"`
+	Sessions []protocol.SessionInfo `json:"sessions,omitempty"`
+}
+
+// printListing はホストごとに転送ルールをまとめて表示する。host を指定した場合はそのホストのみ表示する。
+func printListing(l listing, host string) {
+	// ホスト数と接続数をカウント
+	connectedCount := 0
+	for _, h := range l.Hosts {
+		if h.State == protocol.StateConnected {
+			connectedCount++
+		}
+	}
+
+	fmt.Println(i18n.T("cli.list.hosts_header", map[string]any{"Total": len(l.Hosts), "Connected": connectedCount}))
+	fmt.Println()
+
+	// ホスト別に転送ルールをまとめて表示
+	fwdByHost := make(map[string][]protocol.ForwardInfo)
+	for _, f := range l.Forwards {
+		fwdByHost[f.Host] = append(fwdByHost[f.Host], f)
+	}
+	sessionByName := make(map[string]protocol.SessionInfo, len(l.Sessions))
+	for _, s := range l.Sessions {
+		sessionByName[s.Name] = s
+	}
+
+	for _, h := range l.Hosts {
+		if host != "" && h.Name != host {
+			continue
+		}
+
+		icon := "○"
+		switch h.State {
+		case protocol.StateConnected:
+			icon = "●"
+		case protocol.StatePendingAuth:
+			icon = "◎"
+		}
+
+		fmt.Printf("%s %s (%s:%d, %s)\n", icon, h.Name, h.HostName, h.Port, h.User)
+
+		rules := fwdByHost[h.Name]
+		if len(rules) == 0 {
+			fmt.Println("  " + i18n.T("cli.list.no_rules"))
+		} else {
+			for _, f := range rules {
+				printForwardLine(f)
+				if s, ok := sessionByName[f.Name]; ok {
+					printSessionDetail(s)
+				}
+			}
+		}
+		fmt.Println()
+	}
+}
+
+func printForwardLine(f protocol.ForwardInfo) {
+	typeChar := "L"
+	switch f.Type {
+	case protocol.ForwardTypeRemote:
+		typeChar = "R"
+	case protocol.ForwardTypeDynamic:
+		typeChar = "D"
+	}
+
+	if f.Type == protocol.ForwardTypeDynamic {
+		fmt.Printf("  %s  :%d\n", typeChar, f.LocalPort)
+	} else {
+		fmt.Printf("  %s  :%d  ->  %s:%d\n", typeChar, f.LocalPort, f.RemoteHost, f.RemotePort)
+	}
+}
+
+// printSessionDetail は --long 指定時にセッションの状態・停止理由・直近のエラーを表示する。
+func printSessionDetail(s protocol.SessionInfo) {
+	fmt.Println("     " + i18n.T("cli.list.session_status", map[string]any{"Status": s.Status}))
+	if s.StoppedReason != "" {
+		fmt.Println("     " + i18n.T("cli.list.stopped_reason", map[string]any{
+			"Reason": s.StoppedReason, "At": format.TimeString(s.StoppedAt),
+		}))
+	}
+	if s.LastError != "" {
+		fmt.Println("     " + i18n.T("cli.list.last_error", map[string]any{"Error": s.LastError}))
+	}
+}
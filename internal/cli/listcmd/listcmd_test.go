@@ -0,0 +1,277 @@
+package listcmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type exitCalled struct{ code int }
+
+func stubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(exitCalled{code: c}) }
+}
+
+func captureExit(t *testing.T, fn func()) (code int, stderr string) {
+	t.Helper()
+	origStderr := os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = w.Close()
+		_ = r.Close()
+		os.Stderr = origStderr
+	})
+	os.Stderr = w
+	code = -1
+	func() {
+		defer func() {
+			if v := recover(); v != nil {
+				if ec, ok := v.(exitCalled); ok {
+					code = ec.code
+				} else {
+					panic(v)
+				}
+			}
+		}()
+		fn()
+	}()
+	_ = w.Close()
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	return code, buf.String()
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = orig
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String()
+}
+
+// stubConnectDaemon はメソッド名ごとに responses の結果（未登録のメソッドは {}）を返すモックデーモンに接続させる。
+func stubConnectDaemon(t *testing.T, responses map[string]string) {
+	t.Helper()
+	orig := cli.ConnectDaemon
+	t.Cleanup(func() { cli.ConnectDaemon = orig })
+
+	sockPath := filepath.Join(t.TempDir(), "mock.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go handleMockConn(conn, responses)
+		}
+	}()
+
+	cli.ConnectDaemon = func(_ string) *client.IPCClient {
+		c := client.NewIPCClient(sockPath)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("mock connect: %v", err)
+		}
+		return c
+	}
+}
+
+func handleMockConn(conn net.Conn, responses map[string]string) {
+	defer func() { _ = conn.Close() }()
+	scanner := bufio.NewScanner(conn)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req protocol.Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			return
+		}
+		result := json.RawMessage(`{}`)
+		if r, ok := responses[req.Method]; ok {
+			result = json.RawMessage(r)
+		}
+		if err := enc.Encode(protocol.Response{JSONRPC: protocol.JSONRPCVersion, ID: req.ID, Result: result}); err != nil {
+			return
+		}
+	}
+}
+
+func TestPrintForwardLine_Local(t *testing.T) {
+	f := protocol.ForwardInfo{
+		Type:       protocol.ForwardTypeLocal,
+		LocalPort:  8080,
+		RemoteHost: "remote",
+		RemotePort: 80,
+	}
+
+	output := captureStdout(t, func() {
+		printForwardLine(f)
+	})
+
+	if !strings.Contains(output, "L") {
+		t.Errorf("local forward should show 'L', got %q", output)
+	}
+	if !strings.Contains(output, "8080") {
+		t.Errorf("should show local port 8080, got %q", output)
+	}
+	if !strings.Contains(output, "remote:80") {
+		t.Errorf("should show remote:80, got %q", output)
+	}
+}
+
+func TestPrintForwardLine_Remote(t *testing.T) {
+	f := protocol.ForwardInfo{
+		Type:       protocol.ForwardTypeRemote,
+		LocalPort:  9090,
+		RemoteHost: "host",
+		RemotePort: 3000,
+	}
+
+	output := captureStdout(t, func() {
+		printForwardLine(f)
+	})
+
+	if !strings.Contains(output, "R") {
+		t.Errorf("remote forward should show 'R', got %q", output)
+	}
+}
+
+func TestPrintForwardLine_Dynamic(t *testing.T) {
+	f := protocol.ForwardInfo{
+		Type:      protocol.ForwardTypeDynamic,
+		LocalPort: 1080,
+	}
+
+	output := captureStdout(t, func() {
+		printForwardLine(f)
+	})
+
+	if !strings.Contains(output, "D") {
+		t.Errorf("dynamic forward should show 'D', got %q", output)
+	}
+	if !strings.Contains(output, "1080") {
+		t.Errorf("should show local port 1080, got %q", output)
+	}
+	// dynamic は -> を含まない
+	if strings.Contains(output, "->") {
+		t.Errorf("dynamic forward should not show '->', got %q", output)
+	}
+}
+
+func TestRunList_DaemonNotRunning(t *testing.T) {
+	for _, args := range [][]string{nil, {"-json"}, {"-host", "myserver"}} {
+		stubExit(t)
+		code, stderr := captureExit(t, func() {
+			RunList(t.TempDir(), args)
+		})
+		if code != 1 {
+			t.Errorf("RunList(%v) exit code = %d, want 1", args, code)
+		}
+		if stderr == "" {
+			t.Errorf("RunList(%v) stderr should contain an error message", args)
+		}
+	}
+}
+
+func TestRunList_InvalidFlag(t *testing.T) {
+	stubExit(t)
+
+	code, _ := captureExit(t, func() {
+		RunList(t.TempDir(), []string{"--bad-flag"})
+	})
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
+func TestRunList_MockDaemon(t *testing.T) {
+	stubConnectDaemon(t, nil)
+
+	// モックデーモンは空のホスト/フォワードリストを返す
+	output := captureStdout(t, func() {
+		RunList("", []string{})
+	})
+
+	if output == "" {
+		t.Error("RunList should produce output with mock daemon")
+	}
+}
+
+func TestRunList_MockDaemon_JSON(t *testing.T) {
+	stubConnectDaemon(t, nil)
+
+	output := captureStdout(t, func() {
+		RunList("", []string{"-json"})
+	})
+
+	if !strings.Contains(output, "{") {
+		t.Errorf("JSON output should contain '{', got %q", output)
+	}
+}
+
+func TestRunList_MockDaemon_WithHost(t *testing.T) {
+	stubConnectDaemon(t, nil)
+
+	output := captureStdout(t, func() {
+		RunList("", []string{"-host", "myserver"})
+	})
+
+	if output == "" {
+		t.Error("RunList should produce output with host filter")
+	}
+}
+
+func TestRunList_Long(t *testing.T) {
+	stubConnectDaemon(t, map[string]string{
+		"host.list":    `{"hosts":[{"name":"prod","hostname":"10.0.0.1","port":22,"user":"deploy","state":"connected"}]}`,
+		"forward.list": `{"forwards":[{"name":"web","host":"prod","type":"local","local_port":8080,"remote_host":"localhost","remote_port":80}]}`,
+		"session.list": `{"sessions":[{"name":"web","host":"prod","type":"local","status":"stopped",` +
+			`"stopped_reason":"ssh_lost","stopped_at":"2026-01-02T03:04:05Z","last_error":"reconnection failed"}]}`,
+	})
+
+	output := captureStdout(t, func() {
+		RunList("", []string{"--long"})
+	})
+	for _, want := range []string{":8080", "stopped", "ssh_lost", "reconnection failed"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("output should contain %q, got %q", want, output)
+		}
+	}
+
+	plain := captureStdout(t, func() {
+		RunList("", nil)
+	})
+	if strings.Contains(plain, "ssh_lost") {
+		t.Errorf("output without --long should not show the stop reason, got %q", plain)
+	}
+}
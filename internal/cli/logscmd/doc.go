@@ -0,0 +1,2 @@
+// Package logscmd は logs サブコマンドの実装を提供する。
+package logscmd
@@ -0,0 +1,89 @@
+package logscmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RunLogs は logs サブコマンドを実行する。
+// デーモンが保持する直近のログを表示し、-f 指定時は Ctrl+C まで新しいログを表示し続ける。
+func RunLogs(configDir string, args []string) {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	follow := fs.Bool("f", false, i18n.T("cli.flags.logs.follow"))
+	lines := fs.Int("n", 50, i18n.T("cli.flags.logs.lines"))
+	level := fs.String("level", "info", i18n.T("cli.flags.logs.level"))
+	jsonOut := fs.Bool("json", false, i18n.T("cli.flags.logs.json"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+	var result protocol.LogsSubscribeResult
+	params := protocol.LogsSubscribeParams{Level: *level, Tail: max(*lines, 0)}
+	if err := client.Call(ctx, protocol.MethodLogsSubscribe, params, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.logs.failed", map[string]any{"Error": err}))
+	}
+
+	show := func(rec protocol.LogRecord) { Print(os.Stdout, rec, *jsonOut) }
+	for _, rec := range result.Records {
+		show(rec)
+	}
+	if !*follow {
+		return
+	}
+
+	// 購読は接続を閉じると解除されるため、終了時に logs.unsubscribe は呼ばない
+	sigCtx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for {
+		select {
+		case notif, ok := <-client.Events():
+			if !ok {
+				cli.ExitError("%s", i18n.T("cli.logs.disconnected"))
+			}
+			if notif.Method != protocol.EventLog {
+				continue
+			}
+			var rec protocol.LogRecord
+			if err := json.Unmarshal(notif.Params, &rec); err == nil {
+				show(rec)
+			}
+		case <-sigCtx.Done():
+			return
+		}
+	}
+}
+
+// Print は rec を 1 行で w に出力する。asJSON の場合は JSON、それ以外は
+// "時刻 レベル メッセージ key=value ..." の形式（属性はキーの昇順）で出力する。
+func Print(w io.Writer, rec protocol.LogRecord, asJSON bool) {
+	if asJSON {
+		_ = json.NewEncoder(w).Encode(rec)
+		return
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-5s %s", rec.Time.Local().Format(time.RFC3339), rec.Level, rec.Message)
+	for _, k := range slices.Sorted(maps.Keys(rec.Attrs)) {
+		v := rec.Attrs[k]
+		if strings.ContainsAny(v, " \t\"=") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&b, " %s=%s", k, v)
+	}
+	fmt.Fprintln(w, b.String())
+}
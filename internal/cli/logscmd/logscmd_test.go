@@ -0,0 +1,38 @@
+package logscmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestPrint(t *testing.T) {
+	rec := protocol.LogRecord{
+		Time:    time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC),
+		Level:   "WARN",
+		Message: "reconnecting",
+		Attrs:   map[string]string{"host": "bastion", "error": "connection reset by peer"},
+	}
+
+	var buf bytes.Buffer
+	Print(&buf, rec, false)
+	got := buf.String()
+	want := `WARN  reconnecting error="connection reset by peer" host=bastion` + "\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("Print() = %q, want suffix %q", got, want)
+	}
+	if !strings.HasPrefix(got, rec.Time.Local().Format(time.RFC3339)) {
+		t.Errorf("Print() = %q, want the local time first", got)
+	}
+
+	buf.Reset()
+	Print(&buf, rec, true)
+	var decoded protocol.LogRecord
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Message != rec.Message || decoded.Attrs["host"] != "bastion" {
+		t.Errorf("Print(json) = %q, %v", buf.String(), err)
+	}
+}
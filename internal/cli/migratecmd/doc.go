@@ -0,0 +1,2 @@
+// Package migratecmd は migrate サブコマンドの実装を提供する。
+package migratecmd
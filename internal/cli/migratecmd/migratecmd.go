@@ -0,0 +1,46 @@
+package migratecmd
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RunMigrate は migrate サブコマンドを実行する。
+// アクティブなフォワードを停止せずに別ホストへ付け替える。
+func RunMigrate(configDir string, args []string) {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	to := fs.String("to", "", i18n.T("cli.flags.migrate.to"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	// `migrate <name> --to <host>` のようにルール名の後ろに置かれたフラグも解釈する
+	name := fs.Arg(0)
+	if name != "" {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			cli.ExitError("%v", err)
+		}
+	}
+	if name == "" || *to == "" {
+		cli.ExitError("%s", i18n.T("cli.migrate.usage"))
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	params := protocol.ForwardMigrateParams{Name: name, To: *to}
+	var result protocol.ForwardMigrateResult
+	if err := client.Call(ctx, "forward.migrate", params, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.migrate.failed", map[string]any{"Error": err}))
+	}
+
+	fmt.Println(i18n.T("cli.migrate.success", map[string]any{
+		"Name": result.Name,
+		"From": result.FromHost,
+		"To":   result.Host,
+	}))
+}
@@ -0,0 +1,53 @@
+package migratecmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli/clitest"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// stubDaemon は forward.migrate に応答するモックデーモンに接続させ、受信したリクエストを返す。
+func stubDaemon(t *testing.T) <-chan protocol.Request {
+	t.Helper()
+	return clitest.StubDaemon(t, func(req protocol.Request) any {
+		p := clitest.Params[protocol.ForwardMigrateParams](req)
+		return protocol.ForwardMigrateResult{Name: p.Name, FromHost: "bastion1", Host: p.To, Status: protocol.SessionActive}
+	})
+}
+
+func TestRunMigrate_FlagAfterName(t *testing.T) {
+	got := stubDaemon(t)
+
+	out := clitest.CaptureStdout(t, func() {
+		RunMigrate(t.TempDir(), []string{"db", "--to", "bastion2"})
+	})
+
+	if p := clitest.Params[protocol.ForwardMigrateParams](<-got); p.Name != "db" || p.To != "bastion2" {
+		t.Errorf("params = %+v, want db -> bastion2", p)
+	}
+	if !strings.Contains(out, "db") || !strings.Contains(out, "bastion1") || !strings.Contains(out, "bastion2") {
+		t.Errorf("output = %q, want rule name and both hosts", out)
+	}
+}
+
+func TestRunMigrate_FlagBeforeName(t *testing.T) {
+	got := stubDaemon(t)
+
+	_ = clitest.CaptureStdout(t, func() {
+		RunMigrate(t.TempDir(), []string{"--to=bastion2", "db"})
+	})
+
+	if p := clitest.Params[protocol.ForwardMigrateParams](<-got); p.Name != "db" || p.To != "bastion2" {
+		t.Errorf("params = %+v, want db -> bastion2", p)
+	}
+}
+
+func TestRunMigrate_MissingArgs(t *testing.T) {
+	clitest.StubExit(t)
+
+	for _, args := range [][]string{nil, {"db"}, {"--to", "bastion2"}} {
+		clitest.ExpectExit(t, func() { RunMigrate(t.TempDir(), args) })
+	}
+}
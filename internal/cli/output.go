@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/ousiassllc/moleport/internal/i18n"
+)
+
+// Output は --json フラグに応じて、RPC の結果を表示用のテキストまたは JSON で出力する。
+// JSON では RPC の結果の構造体をそのまま出力するため、jq 等でのスクリプト処理に使える。
+type Output struct {
+	// JSON は --json が指定されたかを表す。fs.Parse の後に設定される。
+	JSON bool
+}
+
+// OutputFlags は fs に --json フラグを登録した Output を返す。
+func OutputFlags(fs *flag.FlagSet) *Output {
+	o := &Output{}
+	fs.BoolVar(&o.JSON, "json", false, i18n.T("cli.flags.output.json"))
+	return o
+}
+
+// Print は JSON 出力の場合は result を整形した JSON で出力し、それ以外の場合は text を呼び出す。
+func (o *Output) Print(result any, text func()) {
+	if o.JSON {
+		PrintJSON(result)
+		return
+	}
+	text()
+}
+
+// PrintJSON は値を整形された JSON として stdout に出力する。
+func PrintJSON(v any) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		ExitErr(err, i18n.T("cli.error.json_output_failed", map[string]any{"Error": err}))
+	}
+}
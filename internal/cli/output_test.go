@@ -0,0 +1,42 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"testing"
+)
+
+func TestPrintJSON_PrettyPrinted(t *testing.T) {
+	output := captureStdout(t, func() {
+		PrintJSON(map[string]string{"key": "value"})
+	})
+
+	if output != "{\n  \"key\": \"value\"\n}\n" {
+		t.Errorf("PrintJSON output = %q, want indented object", output)
+	}
+}
+
+func TestOutput_Print(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "count: 1\n"},
+		{[]string{"--json"}, "{\n  \"count\": 1\n}\n"},
+	}
+	for _, tt := range tests {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		out := OutputFlags(fs)
+		if err := fs.Parse(tt.args); err != nil {
+			t.Fatal(err)
+		}
+
+		// JSON 出力では text を呼ばない
+		output := captureStdout(t, func() {
+			out.Print(map[string]int{"count": 1}, func() { fmt.Println("count: 1") })
+		})
+		if output != tt.want {
+			t.Errorf("args %v: output = %q, want %q", tt.args, output, tt.want)
+		}
+	}
+}
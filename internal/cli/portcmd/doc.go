@@ -0,0 +1,2 @@
+// Package portcmd は check-port サブコマンドの実装を提供する。
+package portcmd
@@ -0,0 +1,53 @@
+package portcmd
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RunCheckPort は check-port サブコマンドを実行する。
+// ローカルポートが他のルールや他のプロセスで使用中かを確認し、使用中の場合は空いているポートを提案する。
+func RunCheckPort(configDir string, args []string) {
+	fs := flag.NewFlagSet("check-port", flag.ContinueOnError)
+	out := cli.OutputFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+	if fs.NArg() == 0 {
+		cli.ExitError("%s", i18n.T("cli.check_port.usage"))
+	}
+	port, err := strconv.Atoi(fs.Arg(0))
+	if err != nil || port < core.MinPort || port > core.MaxPort {
+		cli.ExitError("%s", i18n.T("cli.add.port_range"))
+	}
+
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	var result protocol.ForwardCheckPortResult
+	if err := client.Call(ctx, "forward.checkPort", protocol.ForwardCheckPortParams{Port: port}, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.check_port.failed", map[string]any{"Error": err}))
+	}
+	out.Print(result, func() { fmt.Println(Describe(result)) })
+}
+
+// Describe は forward.checkPort の結果を表示用の文字列にする。
+func Describe(result protocol.ForwardCheckPortResult) string {
+	if result.Available {
+		return i18n.T("cli.check_port.available", map[string]any{"Port": result.Port})
+	}
+	msg := i18n.T("cli.check_port.in_use", map[string]any{"Port": result.Port})
+	if result.Rule != "" {
+		msg = i18n.T("cli.check_port.in_use_by_rule", map[string]any{"Port": result.Port, "Rule": result.Rule})
+	}
+	if result.Suggestion != 0 {
+		msg += "\n" + i18n.T("cli.check_port.suggestion", map[string]any{"Port": result.Suggestion})
+	}
+	return msg
+}
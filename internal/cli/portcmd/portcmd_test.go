@@ -0,0 +1,34 @@
+package portcmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestDescribe(t *testing.T) {
+	tests := []struct {
+		name   string
+		result protocol.ForwardCheckPortResult
+		want   []string
+		absent string
+	}{
+		{"available", protocol.ForwardCheckPortResult{Port: 8080, Available: true}, []string{"8080"}, "8081"},
+		{"rule", protocol.ForwardCheckPortResult{Port: 8080, Rule: "web", Suggestion: 8081}, []string{"8080", "web", "8081"}, ""},
+		{"other process without suggestion", protocol.ForwardCheckPortResult{Port: 65535}, []string{"65535"}, "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Describe(tt.result)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("Describe() = %q, want it to contain %q", got, w)
+				}
+			}
+			if tt.absent != "" && strings.Contains(got, tt.absent) {
+				t.Errorf("Describe() = %q, should not contain %q", got, tt.absent)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
 
 	"github.com/ousiassllc/moleport/internal/i18n"
@@ -8,13 +9,21 @@ import (
 )
 
 // RunReload は reload サブコマンドを実行する。
+// --import を指定すると ssh_config の LocalForward 等をルールとして取り込む。
 func RunReload(configDir string, args []string) {
+	fs := flag.NewFlagSet("reload", flag.ContinueOnError)
+	importFlag := fs.Bool("import", false, i18n.T("cli.flags.reload.import"))
+
+	if err := fs.Parse(args); err != nil {
+		ExitError("%v", err)
+	}
+
 	client, ctx, cleanup := DaemonCall(configDir)
 	defer cleanup()
 
 	var result protocol.HostReloadResult
 	if err := client.Call(ctx, "host.reload", nil, &result); err != nil {
-		ExitError("%s", i18n.T("cli.reload.failed", map[string]any{"Error": err}))
+		ExitErr(err, i18n.T("cli.reload.failed", map[string]any{"Error": err}))
 	}
 
 	fmt.Println(i18n.T("cli.reload.success"))
@@ -28,4 +37,24 @@ func RunReload(configDir string, args []string) {
 	for _, name := range result.Removed {
 		fmt.Println(i18n.T("cli.reload.host_removed", map[string]any{"Name": name}))
 	}
+
+	if len(result.Importable) == 0 {
+		return
+	}
+	if !*importFlag {
+		fmt.Println(i18n.T("cli.reload.importable", map[string]any{"Count": len(result.Importable)}))
+		for _, f := range result.Importable {
+			fmt.Println(i18n.T("cli.reload.importable_item", map[string]any{"Name": f.Name, "Key": f.ImportKey}))
+		}
+		fmt.Println(i18n.T("cli.reload.import_hint"))
+		return
+	}
+
+	var imported protocol.HostImportForwardsResult
+	if err := client.Call(ctx, "host.importForwards", protocol.HostImportForwardsParams{}, &imported); err != nil {
+		ExitErr(err, i18n.T("cli.reload.import_failed", map[string]any{"Error": err}))
+	}
+	for _, f := range imported.Imported {
+		fmt.Println(i18n.T("cli.reload.imported", map[string]any{"Name": f.Name}))
+	}
 }
@@ -31,3 +31,15 @@ func TestRunReload_MockDaemon(t *testing.T) {
 		t.Error("RunReload should produce output with mock daemon")
 	}
 }
+
+func TestRunReload_InvalidFlag(t *testing.T) {
+	stubExit(t)
+
+	code, _ := captureExit(t, func() {
+		RunReload(t.TempDir(), []string{"--bogus"})
+	})
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
@@ -0,0 +1,45 @@
+package cli
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/ousiassllc/moleport/internal/daemon/autostart"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/tlsipc"
+)
+
+// Remote は接続先のリモートデーモンのアドレス（host:port）。ParseGlobalFlags が --remote フラグまたは
+// 環境変数 MOLEPORT_REMOTE から設定する。空の場合はローカルのデーモンに Unix ソケットで接続する。
+var Remote string
+
+// RemoteCertPath はリモートデーモンへの接続に使うクライアント証明書（moleport daemon issue-cert で作成した PEM）のパスを返す。
+// 環境変数 MOLEPORT_REMOTE_CERT が設定されていればその値、なければ設定ディレクトリの tls/client.pem。
+func RemoteCertPath(configDir string) string {
+	if p := os.Getenv("MOLEPORT_REMOTE_CERT"); p != "" {
+		return p
+	}
+	return filepath.Join(tlsipc.Dir(configDir), "client.pem")
+}
+
+// ConnectRemote は Remote のデーモンに相互 TLS 認証で接続し、IPCClient を返す。
+// リモートのデーモンは自動起動しない。
+func ConnectRemote(configDir string) (*client.IPCClient, error) {
+	cfg, err := tlsipc.LoadClientConfig(RemoteCertPath(configDir))
+	if err != nil {
+		return nil, err
+	}
+	c := client.NewIPCClientWithDialer(tlsipc.Dialer(Remote, cfg))
+	if err := c.Connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Connect は Remote が設定されていればリモートのデーモン、なければローカルのデーモン（未起動なら自動起動）に接続する。
+func Connect(configDir string) (*client.IPCClient, error) {
+	if Remote != "" {
+		return ConnectRemote(configDir)
+	}
+	return autostart.EnsureDaemon(configDir)
+}
@@ -2,14 +2,16 @@ package cli
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"time"
 
-	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/cli/exitcode"
+	"github.com/ousiassllc/moleport/internal/daemon/autostart"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/client"
 )
@@ -30,6 +32,11 @@ func ResolveConfigDir(flagValue string) string {
 		return filepath.Join(xdg, "moleport")
 	}
 
+	// Windows では %APPDATA%\moleport
+	if appData := os.Getenv("APPDATA"); appData != "" && runtime.GOOS == "windows" {
+		return filepath.Join(appData, "moleport")
+	}
+
 	home, err := os.UserHomeDir()
 	if err != nil {
 		home = os.Getenv("HOME")
@@ -43,14 +50,48 @@ func ResolveConfigDir(flagValue string) string {
 // NOTE: stubConnectDaemon を使用するテストは t.Parallel() と併用不可。
 var ConnectDaemon = defaultConnectDaemon
 
+// Profile は操作対象のプロファイル名。ParseGlobalFlags が --profile フラグまたは
+// 環境変数 MOLEPORT_PROFILE から設定する。空の場合は既定プロファイルを使用する。
+var Profile string
+
+// NoDaemon はデーモンが起動していない場合に自動起動しないか。ParseGlobalFlags が --no-daemon フラグまたは
+// 環境変数 MOLEPORT_NO_DAEMON から設定する。
+var NoDaemon bool
+
 func defaultConnectDaemon(configDir string) *client.IPCClient {
-	c, err := daemon.EnsureDaemon(configDir)
+	if Remote != "" {
+		c, err := ConnectRemote(configDir)
+		if err != nil {
+			ExitErr(err, i18n.T("cli.error.remote_connect_failed", map[string]any{"Addr": Remote, "Error": err}))
+		}
+		c.SetProfile(Profile)
+		checkProtocol(c)
+		return c
+	}
+	connect := autostart.EnsureDaemon
+	if NoDaemon {
+		connect = autostart.Connect
+	}
+	c, err := connect(configDir)
 	if err != nil {
 		ExitError("%s", i18n.T("cli.error.daemon_not_running"))
 	}
+	c.SetProfile(Profile)
+	checkProtocol(c)
 	return c
 }
 
+// checkProtocol は daemon.hello でデーモンの IPC プロトコルを確認し、互換性がない場合はエラーで終了する。
+// それ以外のエラーは無視し、後続の RPC 呼び出しに任せる。
+func checkProtocol(c *client.IPCClient) {
+	ctx, cancel := CallCtx()
+	defer cancel()
+	hello, err := c.Hello(ctx, Version)
+	if errors.Is(err, client.ErrIncompatibleProtocol) {
+		ExitError("%s", i18n.T("cli.error.protocol_mismatch", map[string]any{"DaemonVersion": hello.ServerVersion, "CLIVersion": Version}))
+	}
+}
+
 // defaultCallTimeout は RPC 呼び出しのデフォルトタイムアウト。
 const defaultCallTimeout = 10 * time.Second
 
@@ -74,37 +115,67 @@ func DaemonCall(configDir string) (cl *client.IPCClient, ctx context.Context, cl
 // NOTE: stubExit/captureExit を使用するテストは t.Parallel() と併用不可。
 var ExitFunc = os.Exit
 
-// ExitError はエラーメッセージを stderr に出力し、終了コード 1 で終了する。
+// ExitError はエラーメッセージを stderr に出力して終了する。
+// args にエラーを含む場合はその種類に応じた終了コードを、含まない場合は exitcode.Failure を使う。
 func ExitError(format string, args ...any) {
 	msg := fmt.Sprintf(format, args...)
 	fmt.Fprintf(os.Stderr, "%s: %s\n", i18n.T("cli.error.prefix"), msg)
-	ExitFunc(1)
+	ExitFunc(exitcode.OfArgs(args))
 }
 
-// PrintJSON は値を整形された JSON として stdout に出力する。
-func PrintJSON(v any) {
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "  ")
-	if err := enc.Encode(v); err != nil {
-		ExitError("%s", i18n.T("cli.error.json_output_failed", map[string]any{"Error": err}))
-	}
+// ExitErr は msg を stderr に出力し、err に応じた終了コードで終了する。
+func ExitErr(err error, msg string) {
+	fmt.Fprintf(os.Stderr, "%s: %s\n", i18n.T("cli.error.prefix"), msg)
+	ExitFunc(exitcode.Of(err))
 }
 
 // ParseGlobalFlags は os.Args からグローバルフラグを解析する。
-// --config-dir フラグの値と残りの引数を返す。
+// --config-dir フラグの値と残りの引数を返す。--profile・--remote・--socket フラグの値は Profile・Remote・Instance に、
+// --no-daemon フラグの有無は NoDaemon に設定する。
 func ParseGlobalFlags() (configDir string, args []string) {
+	Profile = os.Getenv("MOLEPORT_PROFILE")
+	Remote = os.Getenv("MOLEPORT_REMOTE")
+	NoDaemon = os.Getenv("MOLEPORT_NO_DAEMON") != ""
+	Instance = os.Getenv("MOLEPORT_SOCKET")
 	rawArgs := os.Args[1:]
 	for i := 0; i < len(rawArgs); i++ {
-		if rawArgs[i] == "--config-dir" && i+1 < len(rawArgs) {
-			configDir = rawArgs[i+1]
-			i++ // skip next arg (value)
+		if v, n, ok := globalFlag(rawArgs[i:], "--config-dir"); ok {
+			configDir = v
+			i += n
 			continue
 		}
-		if v, ok := strings.CutPrefix(rawArgs[i], "--config-dir="); ok {
-			configDir = v
+		if v, n, ok := globalFlag(rawArgs[i:], "--profile"); ok {
+			Profile = v
+			i += n
+			continue
+		}
+		if v, n, ok := globalFlag(rawArgs[i:], "--remote"); ok {
+			Remote = v
+			i += n
+			continue
+		}
+		if v, n, ok := globalFlag(rawArgs[i:], "--socket"); ok {
+			Instance = v
+			i += n
+			continue
+		}
+		if rawArgs[i] == "--no-daemon" {
+			NoDaemon = true
 			continue
 		}
 		args = append(args, rawArgs[i])
 	}
 	return configDir, args
 }
+
+// globalFlag は args の先頭が name フラグ（"name value" または "name=value"）であればその値と、
+// 値として追加で消費した引数の数を返す。
+func globalFlag(args []string, name string) (value string, consumed int, ok bool) {
+	if args[0] == name && len(args) > 1 {
+		return args[1], 1, true
+	}
+	if v, found := strings.CutPrefix(args[0], name+"="); found {
+		return v, 0, true
+	}
+	return "", 0, false
+}
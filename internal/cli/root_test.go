@@ -111,6 +111,89 @@ func TestParseGlobalFlags_ConfigDirEqualsFormat(t *testing.T) {
 	}
 }
 
+func TestParseGlobalFlags_Profile(t *testing.T) {
+	orig := os.Args
+	defer func() { os.Args = orig; Profile = ""; Remote = "" }()
+	t.Setenv("MOLEPORT_PROFILE", "personal")
+	t.Setenv("MOLEPORT_REMOTE", "jump:7443")
+
+	os.Args = []string{"moleport", "--profile", "work", "list", "--config-dir=/tmp/p", "--remote=bastion:7443"}
+	configDir, args := ParseGlobalFlags()
+	if Profile != "work" || configDir != "/tmp/p" || len(args) != 1 || args[0] != "list" {
+		t.Errorf("Profile = %q, configDir = %q, args = %v, want work, /tmp/p, [list]", Profile, configDir, args)
+	}
+	if Remote != "bastion:7443" {
+		t.Errorf("Remote = %q, want bastion:7443", Remote)
+	}
+
+	os.Args = []string{"moleport", "list"}
+	ParseGlobalFlags()
+	if Profile != "personal" || Remote != "jump:7443" {
+		t.Errorf("Profile = %q, Remote = %q, want env values personal, jump:7443", Profile, Remote)
+	}
+}
+
+func TestParseGlobalFlags_NoDaemon(t *testing.T) {
+	orig := os.Args
+	defer func() { os.Args = orig; NoDaemon = false }()
+
+	os.Args = []string{"moleport", "list", "--no-daemon"}
+	_, args := ParseGlobalFlags()
+	if !NoDaemon || len(args) != 1 || args[0] != "list" {
+		t.Errorf("NoDaemon = %v, args = %v, want true, [list]", NoDaemon, args)
+	}
+
+	os.Args = []string{"moleport", "list"}
+	ParseGlobalFlags()
+	if NoDaemon {
+		t.Error("NoDaemon = true without flag, want false")
+	}
+
+	t.Setenv("MOLEPORT_NO_DAEMON", "1")
+	ParseGlobalFlags()
+	if !NoDaemon {
+		t.Error("NoDaemon = false with MOLEPORT_NO_DAEMON, want true")
+	}
+}
+
+func TestParseGlobalFlags_Socket(t *testing.T) {
+	orig := os.Args
+	defer func() { os.Args = orig; Instance = "" }()
+
+	t.Setenv("MOLEPORT_SOCKET", "personal")
+	os.Args = []string{"moleport", "--socket", "work", "list"}
+	_, args := ParseGlobalFlags()
+	if Instance != "work" || len(args) != 1 || args[0] != "list" {
+		t.Errorf("Instance = %q, args = %v, want work, [list]", Instance, args)
+	}
+
+	os.Args = []string{"moleport", "list"}
+	ParseGlobalFlags()
+	if Instance != "personal" {
+		t.Errorf("Instance = %q with MOLEPORT_SOCKET, want personal", Instance)
+	}
+}
+
+func TestInstanceConfigDir(t *testing.T) {
+	defer func() { Instance = "" }()
+
+	Instance = ""
+	if got := InstanceConfigDir("/cfg"); got != "/cfg" {
+		t.Errorf("InstanceConfigDir() = %q, want /cfg", got)
+	}
+	Instance = "work"
+	if got, want := InstanceConfigDir("/cfg"), filepath.Join("/cfg", "instances", "work"); got != want {
+		t.Errorf("InstanceConfigDir() = %q, want %q", got, want)
+	}
+
+	stubExit(t)
+	Instance = "../etc"
+	code, _ := captureExit(t, func() { InstanceConfigDir("/cfg") })
+	if code != 1 {
+		t.Errorf("exit code = %d for invalid name, want 1", code)
+	}
+}
+
 func TestParseGlobalFlags_Empty(t *testing.T) {
 	orig := os.Args
 	defer func() { os.Args = orig }()
@@ -158,30 +241,6 @@ func TestCallCtx_ReturnsContext(t *testing.T) {
 	}
 }
 
-func TestPrintJSON_WritesToStdout(t *testing.T) {
-	data := map[string]string{"key": "value"}
-
-	output := captureStdout(t, func() {
-		PrintJSON(data)
-	})
-
-	if !strings.Contains(output, "key") || !strings.Contains(output, "value") {
-		t.Errorf("PrintJSON output = %q, want to contain key and value", output)
-	}
-}
-
-func TestPrintJSON_PrettyPrinted(t *testing.T) {
-	data := map[string]int{"a": 1}
-
-	output := captureStdout(t, func() {
-		PrintJSON(data)
-	})
-
-	if !strings.Contains(output, "  ") {
-		t.Errorf("PrintJSON should produce indented output, got %q", output)
-	}
-}
-
 func TestConnectDaemon_FailsWithoutDaemon(t *testing.T) {
 	stubExit(t)
 	configDir := t.TempDir()
@@ -0,0 +1,24 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/infra/secretstore"
+)
+
+// newSecretStore は secrets サブコマンドが操作する SecretStore を返す。テスト時に差し替える。
+var newSecretStore = secretstore.New
+
+// RunSecrets は secrets サブコマンドを実行する。
+// clear はデーモンが OS のキーチェーンに保存したパスフレーズをすべて削除する。
+func RunSecrets(_ string, args []string) {
+	if len(args) != 1 || args[0] != "clear" {
+		ExitError("%s", i18n.T("cli.secrets.usage"))
+	}
+	n, err := secretstore.Clear(newSecretStore())
+	if err != nil {
+		ExitErr(err, i18n.T("cli.secrets.clear_failed", map[string]any{"Error": err}))
+	}
+	fmt.Println(i18n.T("cli.secrets.cleared", map[string]any{"Count": n}))
+}
@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/infra/secretstore"
+)
+
+func TestRunSecrets_Clear(t *testing.T) {
+	store := secretstore.NewMemory()
+	orig := newSecretStore
+	t.Cleanup(func() { newSecretStore = orig })
+	newSecretStore = func() secretstore.SecretStore { return store }
+	_ = secretstore.Remember(store, "passphrase:/home/user/.ssh/id_ed25519", "s3cret")
+
+	output := captureStdout(t, func() {
+		RunSecrets("", []string{"clear"})
+	})
+	if !strings.Contains(output, "1") {
+		t.Errorf("output = %q, want cleared count", output)
+	}
+	if _, err := store.Get("passphrase:/home/user/.ssh/id_ed25519"); err == nil {
+		t.Error("cached passphrase should be deleted")
+	}
+}
+
+func TestRunSecrets_Usage(t *testing.T) {
+	stubExit(t)
+	for _, args := range [][]string{nil, {"list"}} {
+		if code, _ := captureExit(t, func() { RunSecrets("", args) }); code != 1 {
+			t.Errorf("RunSecrets(%v) exit code = %d, want 1", args, code)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package servicecmd はデーモンを systemd・launchd のユーザーサービスとして登録・削除する service サブコマンドを提供する。
+package servicecmd
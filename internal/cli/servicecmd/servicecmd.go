@@ -0,0 +1,145 @@
+package servicecmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/autostart"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/infra/usersvc"
+)
+
+const (
+	// startTimeout は登録したサービスのデーモンが接続を受け付けるまでの待ち時間。
+	startTimeout = 10 * time.Second
+	// pollInterval はデーモンの起動を確認する間隔。
+	pollInterval = 200 * time.Millisecond
+)
+
+// newManager は実行中の OS のサービスマネージャーを返す。テストで差し替える。
+var newManager = usersvc.New
+
+// executable はサービスとして起動する moleport の実行ファイルのパスを返す。テストで差し替える。
+var executable = func() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(exe)
+}
+
+// RunService は service サブコマンドを実行する。
+func RunService(configDir string, args []string) {
+	if len(args) == 0 {
+		cli.ExitError("%s", i18n.T("cli.service.usage"))
+	}
+	mgr, err := newManager()
+	if errors.Is(err, usersvc.ErrUnsupported) {
+		cli.ExitError("%s", i18n.T("cli.service.unsupported"))
+	}
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.service.status_failed", map[string]any{"Error": err}))
+	}
+	switch args[0] {
+	case "install":
+		runInstall(configDir, mgr)
+	case "uninstall":
+		runUninstall(mgr)
+	case "status":
+		runStatus(configDir, mgr)
+	default:
+		cli.ExitError("%s", i18n.T("cli.service.usage"))
+	}
+}
+
+// runInstall はデーモンをユーザーサービスとして登録・起動し、接続を受け付けるまで待つ。
+// サービス以外で起動したデーモンが稼働中の場合は、PID ファイルが競合するため登録しない。
+func runInstall(configDir string, mgr usersvc.Manager) {
+	st, _ := mgr.Status()
+	if running, pid := pidfile.IsRunning(daemon.PIDFilePath(configDir)); running && !st.Running {
+		cli.ExitError("%s", i18n.T("cli.service.daemon_running", map[string]any{"PID": pid}))
+	}
+
+	exe, err := executable()
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.service.install_failed", map[string]any{"Error": err}))
+	}
+	absConfigDir, err := filepath.Abs(configDir)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.service.install_failed", map[string]any{"Error": err}))
+	}
+	if err := mgr.Install(usersvc.Spec{Executable: exe, ConfigDir: absConfigDir}); err != nil {
+		cli.ExitErr(err, i18n.T("cli.service.install_failed", map[string]any{"Error": err}))
+	}
+	st, _ = mgr.Status()
+	fmt.Println(i18n.T("cli.service.installed", map[string]any{"Path": st.Path}))
+
+	if err := waitStarted(configDir, startTimeout); err != nil {
+		cli.ExitError("%s", i18n.T("cli.service.not_started", map[string]any{
+			"Error": err, "Log": daemon.ResolveLogConfig(configDir).Path,
+		}))
+	}
+	_, pid := pidfile.IsRunning(daemon.PIDFilePath(configDir))
+	fmt.Println(i18n.T("cli.service.started", map[string]any{"PID": pid}))
+}
+
+// waitStarted はデーモンが接続を受け付けるまで待つ。
+func waitStarted(configDir string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		c, err := autostart.Connect(configDir)
+		if err == nil {
+			_ = c.Close()
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return err
+		}
+		time.Sleep(pollInterval)
+	}
+}
+
+// runUninstall はユーザーサービスを停止して削除する。
+func runUninstall(mgr usersvc.Manager) {
+	st, _ := mgr.Status()
+	err := mgr.Uninstall()
+	if errors.Is(err, usersvc.ErrNotInstalled) {
+		cli.ExitError("%s", i18n.T("cli.service.not_installed"))
+	}
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.service.uninstall_failed", map[string]any{"Error": err}))
+	}
+	fmt.Println(i18n.T("cli.service.uninstalled", map[string]any{"Path": st.Path}))
+}
+
+// runStatus はユーザーサービスとデーモンの状態を表示する。
+func runStatus(configDir string, mgr usersvc.Manager) {
+	st, err := mgr.Status()
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.service.status_failed", map[string]any{"Error": err}))
+	}
+	if !st.Installed {
+		fmt.Println(i18n.T("cli.service.status_not_installed", map[string]any{"Path": st.Path}))
+		return
+	}
+	fmt.Println(i18n.T("cli.service.status_path", map[string]any{"Path": st.Path}))
+	fmt.Println(i18n.T("cli.service.status_enabled", map[string]any{"Enabled": yesNo(st.Enabled)}))
+	fmt.Println(i18n.T("cli.service.status_state", map[string]any{"State": st.State}))
+	if running, pid := pidfile.IsRunning(daemon.PIDFilePath(configDir)); running {
+		fmt.Println(i18n.T("cli.service.status_pid", map[string]any{"PID": pid}))
+	}
+}
+
+// yesNo は b を表示用の yes・no に変換する。
+func yesNo(b bool) string {
+	if b {
+		return i18n.T("cli.service.yes")
+	}
+	return i18n.T("cli.service.no")
+}
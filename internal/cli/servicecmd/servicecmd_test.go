@@ -0,0 +1,193 @@
+package servicecmd
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/infra/usersvc"
+)
+
+type exitCalled struct{ code int }
+
+func stubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(exitCalled{code: c}) }
+}
+
+func captureExit(t *testing.T, fn func()) (code int, stderr string) {
+	t.Helper()
+	origStderr := os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = w.Close()
+		_ = r.Close()
+		os.Stderr = origStderr
+	})
+	os.Stderr = w
+	code = -1
+	func() {
+		defer func() {
+			if v := recover(); v != nil {
+				if ec, ok := v.(exitCalled); ok {
+					code = ec.code
+				} else {
+					panic(v)
+				}
+			}
+		}()
+		fn()
+	}()
+	_ = w.Close()
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	return code, buf.String()
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = orig
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String()
+}
+
+// fakeManager は呼び出しを記録する usersvc.Manager。
+type fakeManager struct {
+	status     usersvc.Status
+	installErr error
+	installed  []usersvc.Spec
+}
+
+func (m *fakeManager) Install(spec usersvc.Spec) error {
+	m.installed = append(m.installed, spec)
+	return m.installErr
+}
+
+func (m *fakeManager) Uninstall() error {
+	if !m.status.Installed {
+		return usersvc.ErrNotInstalled
+	}
+	m.status = usersvc.Status{Path: m.status.Path}
+	return nil
+}
+
+func (m *fakeManager) Status() (usersvc.Status, error) {
+	return m.status, nil
+}
+
+func stubManager(t *testing.T, m usersvc.Manager, err error) {
+	t.Helper()
+	orig := newManager
+	t.Cleanup(func() { newManager = orig })
+	newManager = func() (usersvc.Manager, error) { return m, err }
+}
+
+func TestRunService_Usage(t *testing.T) {
+	stubExit(t)
+	stubManager(t, &fakeManager{}, nil)
+
+	for _, args := range [][]string{nil, {"restart"}} {
+		if code, stderr := captureExit(t, func() { RunService(t.TempDir(), args) }); code != 1 || !strings.Contains(stderr, "service") {
+			t.Errorf("RunService(%v) = (%d, %q), want usage error", args, code, stderr)
+		}
+	}
+}
+
+func TestRunService_Unsupported(t *testing.T) {
+	stubExit(t)
+	stubManager(t, nil, usersvc.ErrUnsupported)
+
+	code, stderr := captureExit(t, func() { RunService(t.TempDir(), []string{"status"}) })
+	if code != 1 || !strings.Contains(stderr, "launchd") {
+		t.Errorf("RunService(status) = (%d, %q), want unsupported error", code, stderr)
+	}
+}
+
+func TestRunService_Status(t *testing.T) {
+	m := &fakeManager{status: usersvc.Status{Path: "/u/moleport.service"}}
+	stubManager(t, m, nil)
+
+	if out := captureStdout(t, func() { RunService(t.TempDir(), []string{"status"}) }); !strings.Contains(out, "not installed") {
+		t.Errorf("status output = %q, want not installed", out)
+	}
+
+	m.status = usersvc.Status{Path: "/u/moleport.service", Installed: true, Enabled: true, State: "active", Running: true}
+	out := captureStdout(t, func() { RunService(t.TempDir(), []string{"status"}) })
+	for _, want := range []string{"/u/moleport.service", "yes", "active"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("status output = %q, want to contain %q", out, want)
+		}
+	}
+}
+
+func TestRunService_InstallRefusesRunningDaemon(t *testing.T) {
+	stubExit(t)
+	m := &fakeManager{}
+	stubManager(t, m, nil)
+	configDir := t.TempDir()
+	pf := pidfile.New(daemon.PIDFilePath(configDir))
+	if err := pf.Acquire(); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer func() { _ = pf.Release() }()
+
+	code, stderr := captureExit(t, func() { RunService(configDir, []string{"install"}) })
+	if code != 1 || !strings.Contains(stderr, "daemon stop") {
+		t.Errorf("install = (%d, %q), want daemon running error", code, stderr)
+	}
+	if len(m.installed) != 0 {
+		t.Errorf("installed %v while the daemon is running", m.installed)
+	}
+}
+
+func TestRunService_InstallFailure(t *testing.T) {
+	stubExit(t)
+	m := &fakeManager{installErr: errors.New("systemctl failed")}
+	stubManager(t, m, nil)
+	orig := executable
+	t.Cleanup(func() { executable = orig })
+	executable = func() (string, error) { return "/opt/moleport", nil }
+
+	code, stderr := captureExit(t, func() { RunService("relative/cfg", []string{"install"}) })
+	if code != 1 || !strings.Contains(stderr, "systemctl failed") {
+		t.Errorf("install = (%d, %q), want install error", code, stderr)
+	}
+	if len(m.installed) != 1 || m.installed[0].Executable != "/opt/moleport" || !strings.HasPrefix(m.installed[0].ConfigDir, "/") {
+		t.Errorf("installed = %+v, want absolute paths", m.installed)
+	}
+}
+
+func TestRunService_Uninstall(t *testing.T) {
+	stubExit(t)
+	m := &fakeManager{status: usersvc.Status{Path: "/u/moleport.service", Installed: true}}
+	stubManager(t, m, nil)
+
+	if out := captureStdout(t, func() { RunService(t.TempDir(), []string{"uninstall"}) }); !strings.Contains(out, "/u/moleport.service") {
+		t.Errorf("uninstall output = %q, want unit path", out)
+	}
+	code, _ := captureExit(t, func() { RunService(t.TempDir(), []string{"uninstall"}) })
+	if code != 1 {
+		t.Errorf("second uninstall exit code = %d, want 1", code)
+	}
+}
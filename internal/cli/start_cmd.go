@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"flag"
 	"fmt"
 
 	"github.com/ousiassllc/moleport/internal/i18n"
@@ -14,14 +15,27 @@ func RunStart(configDir string, args []string) {
 	}
 
 	name := args[0]
+	fs := flag.NewFlagSet("start", flag.ContinueOnError)
+	ttl := fs.String("ttl", "", i18n.T("cli.flags.start.ttl"))
+	if err := fs.Parse(args[1:]); err != nil {
+		ExitError("%v", err)
+	}
+	if _, err := protocol.ParseTTL(*ttl); err != nil {
+		ExitError("%v", err)
+	}
+
 	client, ctx, cleanup := DaemonCall(configDir)
 	defer cleanup()
 
-	params := protocol.ForwardStartParams{Name: name}
+	params := protocol.ForwardStartParams{Name: name, TTL: *ttl}
 	var result protocol.ForwardStartResult
 	if err := client.Call(ctx, "forward.start", params, &result); err != nil {
 		ExitError("%v", err)
 	}
 
+	if *ttl != "" {
+		fmt.Println(i18n.T("cli.start.success_ttl", map[string]any{"Name": result.Name, "TTL": *ttl}))
+		return
+	}
 	fmt.Println(i18n.T("cli.start.success", map[string]any{"Name": result.Name}))
 }
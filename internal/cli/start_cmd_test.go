@@ -14,6 +14,13 @@ func TestRunStart_NameRequired(t *testing.T) {
 	}
 }
 
+func TestRunStart_InvalidTTL(t *testing.T) {
+	stubExit(t)
+	if code, _ := captureExit(t, func() { RunStart(t.TempDir(), []string{"my-forward", "--ttl", "-1h"}) }); code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
 func TestRunStart_DaemonNotRunning(t *testing.T) {
 	stubExit(t)
 	configDir := t.TempDir()
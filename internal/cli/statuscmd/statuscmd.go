@@ -3,9 +3,12 @@ package statuscmd
 import (
 	"flag"
 	"fmt"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
 	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
@@ -14,7 +17,7 @@ import (
 // RunStatus は status サブコマンドを実行する。
 func RunStatus(configDir string, args []string) {
 	fs := flag.NewFlagSet("status", flag.ContinueOnError)
-	jsonFlag := fs.Bool("json", false, "JSON 形式で出力")
+	out := cli.OutputFlags(fs)
 
 	if err := fs.Parse(args); err != nil {
 		cli.ExitError("%v", err)
@@ -24,15 +27,15 @@ func RunStatus(configDir string, args []string) {
 
 	// 名前が指定された場合はセッション詳細を表示
 	if len(remaining) > 0 {
-		runSessionGet(configDir, remaining[0], *jsonFlag)
+		runSessionGet(configDir, remaining[0], out)
 		return
 	}
 
 	// 名前なしの場合はサマリーを表示
-	runStatusSummary(configDir, *jsonFlag)
+	runStatusSummary(configDir, out)
 }
 
-func runSessionGet(configDir string, name string, jsonOutput bool) {
+func runSessionGet(configDir string, name string, out *cli.Output) {
 	client := cli.ConnectDaemon(configDir)
 	defer func() { _ = client.Close() }()
 
@@ -45,21 +48,30 @@ func runSessionGet(configDir string, name string, jsonOutput bool) {
 		cli.ExitError("%v", err)
 	}
 
-	if jsonOutput {
-		cli.PrintJSON(session)
-		return
-	}
+	out.Print(session, func() { printSession(session) })
+}
 
+func printSession(session protocol.SessionGetResult) {
 	fmt.Println(i18n.T("cli.status.session_header", map[string]any{"Name": session.Name}))
 	fmt.Println(i18n.T("cli.status.session_host", map[string]any{"Host": session.Host}))
 	fmt.Println(i18n.T("cli.status.session_type", map[string]any{"Type": session.Type}))
-	fmt.Println(i18n.T("cli.status.session_local_port", map[string]any{"Port": session.LocalPort}))
+	port := session.LocalPort
+	if session.BoundPort != 0 {
+		port = session.BoundPort
+	}
+	fmt.Println(i18n.T("cli.status.session_local_port", map[string]any{"Port": port}))
 	if session.RemoteHost != "" {
 		fmt.Println(i18n.T("cli.status.session_remote", map[string]any{"Remote": fmt.Sprintf("%s:%d", session.RemoteHost, session.RemotePort)}))
 	}
 	fmt.Println(i18n.T("cli.status.session_status", map[string]any{"Status": session.Status}))
 	if session.ConnectedAt != "" {
-		fmt.Println(i18n.T("cli.status.session_connected_at", map[string]any{"Time": session.ConnectedAt}))
+		fmt.Println(i18n.T("cli.status.session_connected_at", map[string]any{"Time": format.TimeString(session.ConnectedAt)}))
+	}
+	// 期限は将来の時刻のため、relative 形式でも日時と残り時間を表示する
+	if expiresAt, err := time.Parse(time.RFC3339, session.ExpiresAt); err == nil {
+		fmt.Println(i18n.T("cli.status.session_expires_at", map[string]any{
+			"Time": format.TimeAs(expiresAt, core.TimeFormatLocal), "Remaining": format.Duration(time.Until(expiresAt)),
+		}))
 	}
 	fmt.Println(i18n.T("cli.status.session_bytes_sent", map[string]any{"Bytes": format.Bytes(session.BytesSent)}))
 	fmt.Println(i18n.T("cli.status.session_bytes_received", map[string]any{"Bytes": format.Bytes(session.BytesReceived)}))
@@ -71,17 +83,17 @@ func runSessionGet(configDir string, name string, jsonOutput bool) {
 	}
 }
 
-func runStatusSummary(configDir string, jsonOutput bool) {
+func runStatusSummary(configDir string, out *cli.Output) {
 	pidPath := daemon.PIDFilePath(configDir)
-	running, _ := daemon.IsRunning(pidPath)
-	if !running {
+	running, _ := pidfile.IsRunning(pidPath)
+	if !running && cli.Remote == "" {
 		fmt.Println(i18n.T("cli.daemon.not_running"))
 		return
 	}
 
-	client, err := daemon.EnsureDaemon(configDir)
+	client, err := cli.Connect(configDir)
 	if err != nil {
-		cli.ExitError("%s", i18n.T("cli.daemon.connect_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.daemon.connect_failed", map[string]any{"Error": err}))
 	}
 	defer func() { _ = client.Close() }()
 
@@ -91,37 +103,36 @@ func runStatusSummary(configDir string, jsonOutput bool) {
 	// デーモンステータス
 	var daemonStatus protocol.DaemonStatusResult
 	if err := client.Call(ctx, "daemon.status", nil, &daemonStatus); err != nil {
-		cli.ExitError("%s", i18n.T("cli.status.get_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.status.get_failed", map[string]any{"Error": err}))
 	}
 
 	// ホスト一覧
 	var hosts protocol.HostListResult
 	if err := client.Call(ctx, "host.list", nil, &hosts); err != nil {
-		cli.ExitError("%s", i18n.T("cli.status.get_hosts_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.status.get_hosts_failed", map[string]any{"Error": err}))
 	}
 
 	// セッション一覧
 	var sessions protocol.SessionListResult
 	if err := client.Call(ctx, "session.list", nil, &sessions); err != nil {
-		cli.ExitError("%s", i18n.T("cli.status.get_sessions_failed", map[string]any{"Error": err}))
-	}
-
-	if jsonOutput {
-		cli.PrintJSON(struct {
-			Daemon   protocol.DaemonStatusResult `json:"daemon"`
-			Hosts    []protocol.HostInfo         `json:"hosts"`
-			Sessions []protocol.SessionInfo      `json:"sessions"`
-		}{
-			Daemon:   daemonStatus,
-			Hosts:    hosts.Hosts,
-			Sessions: sessions.Sessions,
-		})
-		return
+		cli.ExitErr(err, i18n.T("cli.status.get_sessions_failed", map[string]any{"Error": err}))
 	}
 
+	s := summary{Daemon: daemonStatus, Hosts: hosts.Hosts, Sessions: sessions.Sessions}
+	out.Print(s, func() { printSummary(s) })
+}
+
+// summary は status の出力内容。JSON では daemon.status・host.list・session.list の結果をそのまま出力する。
+type summary struct {
+	Daemon   protocol.DaemonStatusResult `json:"daemon"`
+	Hosts    []protocol.HostInfo         `json:"hosts"`
+	Sessions []protocol.SessionInfo      `json:"sessions"`
+}
+
+func printSummary(s summary) {
 	connectedHosts := 0
 	pendingAuthHosts := 0
-	for _, h := range hosts.Hosts {
+	for _, h := range s.Hosts {
 		switch h.State {
 		case protocol.StateConnected:
 			connectedHosts++
@@ -133,23 +144,23 @@ func runStatusSummary(configDir string, jsonOutput bool) {
 	activeSessions := 0
 	stoppedSessions := 0
 	var totalSent, totalRecv int64
-	for _, s := range sessions.Sessions {
-		if s.Status == protocol.SessionActive {
+	for _, session := range s.Sessions {
+		if session.Status == protocol.SessionActive {
 			activeSessions++
 		} else {
 			stoppedSessions++
 		}
-		totalSent += s.BytesSent
-		totalRecv += s.BytesReceived
+		totalSent += session.BytesSent
+		totalRecv += session.BytesReceived
 	}
 
 	fmt.Println(i18n.T("cli.status.header"))
-	fmt.Println(i18n.T("cli.status.daemon_running", map[string]any{"PID": daemonStatus.PID, "Uptime": daemonStatus.Uptime}))
+	fmt.Println(i18n.T("cli.status.daemon_running", map[string]any{"PID": s.Daemon.PID, "Uptime": format.DurationString(s.Daemon.Uptime)}))
 	if pendingAuthHosts > 0 {
-		fmt.Println(i18n.T("cli.status.hosts_summary_auth", map[string]any{"Total": len(hosts.Hosts), "Connected": connectedHosts, "PendingAuth": pendingAuthHosts}))
+		fmt.Println(i18n.T("cli.status.hosts_summary_auth", map[string]any{"Total": len(s.Hosts), "Connected": connectedHosts, "PendingAuth": pendingAuthHosts}))
 	} else {
-		fmt.Println(i18n.T("cli.status.hosts_summary", map[string]any{"Total": len(hosts.Hosts), "Connected": connectedHosts}))
+		fmt.Println(i18n.T("cli.status.hosts_summary", map[string]any{"Total": len(s.Hosts), "Connected": connectedHosts}))
 	}
-	fmt.Println(i18n.T("cli.status.forwards_summary", map[string]any{"Total": len(sessions.Sessions), "Active": activeSessions, "Stopped": stoppedSessions}))
+	fmt.Println(i18n.T("cli.status.forwards_summary", map[string]any{"Total": len(s.Sessions), "Active": activeSessions, "Stopped": stoppedSessions}))
 	fmt.Println(i18n.T("cli.status.traffic_summary", map[string]any{"Sent": format.Bytes(totalSent), "Received": format.Bytes(totalRecv)}))
 }
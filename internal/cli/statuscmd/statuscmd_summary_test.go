@@ -4,10 +4,20 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
+// useTimeFormat はテスト中の時刻表示形式を設定し、終了時に relative へ戻す。
+func useTimeFormat(t *testing.T, style string) {
+	t.Helper()
+	format.SetTimeFormat(style)
+	t.Cleanup(func() { format.SetTimeFormat(core.TimeFormatRelative) })
+}
+
 func TestRunStatus_SessionGet_WithRemoteHost(t *testing.T) {
 	stubConnectDaemon(t)
 	stubMockResponses(t, map[string]json.RawMessage{
@@ -45,13 +55,32 @@ func TestRunStatus_SessionGet_WithConnectedAt(t *testing.T) {
 		}),
 	})
 
+	useTimeFormat(t, core.TimeFormatUTC)
 	output := captureStdout(t, func() { RunStatus("", []string{"test-session"}) })
 
-	if !strings.Contains(output, "2026-01-01T00:00:00Z") {
+	if !strings.Contains(output, "Jan 1, 2026 00:00:00 UTC") {
 		t.Errorf("output should contain connected_at time, got %q", output)
 	}
 }
 
+func TestRunStatus_SessionGet_ConnectedAtRelative(t *testing.T) {
+	stubConnectDaemon(t)
+	stubMockResponses(t, map[string]json.RawMessage{
+		"session.get": mustJSON(t, protocol.SessionInfo{
+			Name:        "test-session",
+			Status:      protocol.SessionActive,
+			ConnectedAt: time.Now().Add(-90 * time.Second).Format(time.RFC3339),
+		}),
+	})
+
+	useTimeFormat(t, core.TimeFormatRelative)
+	output := captureStdout(t, func() { RunStatus("", []string{"test-session"}) })
+
+	if !strings.Contains(output, "1m 30s ago") && !strings.Contains(output, "1m 31s ago") {
+		t.Errorf("output should contain relative connected_at time, got %q", output)
+	}
+}
+
 func TestRunStatus_SessionGet_WithReconnectCountAndLastError(t *testing.T) {
 	stubConnectDaemon(t)
 	stubMockResponses(t, map[string]json.RawMessage{
@@ -97,10 +126,11 @@ func TestRunStatus_SessionGet_WithAllFields(t *testing.T) {
 		}),
 	})
 
+	useTimeFormat(t, core.TimeFormatUTC)
 	output := captureStdout(t, func() { RunStatus("", []string{"full-session"}) })
 
 	// 全フィールドが出力されることを確認
-	for _, want := range []string{"full-session", "myhost", "local", "9090", "db.example.com", "5432", "2026-03-15T10:00:00Z", "5", "timeout"} {
+	for _, want := range []string{"full-session", "myhost", "local", "9090", "db.example.com", "5432", "Mar 15, 2026 10:00:00 UTC", "5", "timeout"} {
 		if !strings.Contains(output, want) {
 			t.Errorf("output should contain %q, got %q", want, output)
 		}
@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/cli"
 	"github.com/ousiassllc/moleport/internal/format"
@@ -145,7 +146,7 @@ func stubMockResponses(t *testing.T, m map[string]json.RawMessage) {
 }
 
 // setupMockDaemonDir は configDir に PID ファイルとモックソケットを配置し、
-// daemon.EnsureDaemon が正常に接続できる環境を構築するヘルパー。
+// cli.Connect が正常に接続できる環境を構築するヘルパー。
 func setupMockDaemonDir(t *testing.T) string {
 	t.Helper()
 	configDir := t.TempDir()
@@ -278,3 +279,17 @@ func TestRunStatus_SessionGet_JSON_MockDaemon(t *testing.T) {
 		t.Errorf("JSON output should contain '{', got %q", output)
 	}
 }
+
+func TestRunStatus_SessionGet_ExpiresAt(t *testing.T) {
+	stubConnectDaemon(t)
+	stubMockResponses(t, map[string]json.RawMessage{
+		"session.get": mustJSON(t, protocol.SessionInfo{
+			Name: "tmp", Status: protocol.SessionActive,
+			ExpiresAt: time.Now().Add(2*time.Hour + 30*time.Second).Format(time.RFC3339),
+		}),
+	})
+	output := captureStdout(t, func() { RunStatus("", []string{"tmp"}) })
+	if !strings.Contains(output, "2h 0m") {
+		t.Errorf("output should contain the remaining time until expiry, got %q", output)
+	}
+}
@@ -11,7 +11,7 @@ import (
 // RunStop は stop サブコマンドを実行する。
 func RunStop(configDir string, args []string) {
 	fs := flag.NewFlagSet("stop", flag.ContinueOnError)
-	all := fs.Bool("all", false, "全フォワーディングを一括停止")
+	all := fs.Bool("all", false, i18n.T("cli.flags.stop.all"))
 	if err := fs.Parse(args); err != nil {
 		ExitError("%v", err)
 	}
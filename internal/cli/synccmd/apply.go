@@ -0,0 +1,90 @@
+package synccmd
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/teamsync"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+)
+
+// printPlan は反映する変更を 1 行ずつ表示する。
+func printPlan(plan teamsync.Plan) {
+	for _, r := range plan.Add {
+		fmt.Printf("  + %s\n", r.Name)
+	}
+	for _, r := range plan.Replace {
+		fmt.Printf("  ~ %s\n", r.Name)
+	}
+	for _, name := range plan.Remove {
+		fmt.Printf("  - %s\n", name)
+	}
+	for _, name := range slices.Sorted(maps.Keys(plan.Hosts)) {
+		fmt.Printf("  * host %s\n", name)
+	}
+}
+
+// apply は plan をデーモンに反映し、反映に失敗した項目を衝突として返す。
+// 内容が変わったルールは削除してから追加し直すため、アクティブなフォワードは停止する。
+func apply(ctx context.Context, c *client.IPCClient, plan teamsync.Plan) []teamsync.Conflict {
+	var failed []teamsync.Conflict
+	fail := func(kind, name string, err error) {
+		failed = append(failed, teamsync.Conflict{Kind: kind, Name: name, Reason: err.Error()})
+	}
+
+	for _, name := range plan.Remove {
+		if err := deleteRule(ctx, c, name); err != nil {
+			fail("rule", name, err)
+		}
+	}
+	for _, r := range plan.Replace {
+		if err := deleteRule(ctx, c, r.Name); err != nil {
+			fail("rule", r.Name, err)
+			continue
+		}
+		if err := addRule(ctx, c, r); err != nil {
+			fail("rule", r.Name, err)
+		}
+	}
+	for _, r := range plan.Add {
+		if err := addRule(ctx, c, r); err != nil {
+			fail("rule", r.Name, err)
+		}
+	}
+	for name, meta := range plan.Hosts {
+		if err := updateHost(ctx, c, name, meta); err != nil {
+			fail("host", name, err)
+		}
+	}
+	return failed
+}
+
+func deleteRule(ctx context.Context, c *client.IPCClient, name string) error {
+	var result protocol.ForwardDeleteResult
+	return c.Call(ctx, "forward.delete", protocol.ForwardDeleteParams{Name: name}, &result)
+}
+
+func addRule(ctx context.Context, c *client.IPCClient, rule core.ForwardRule) error {
+	var result protocol.ForwardAddResult
+	return c.Call(ctx, "forward.add", convert.ToForwardAddParams(rule), &result)
+}
+
+// updateHost は meta の空でない項目をホストに設定する。
+func updateHost(ctx context.Context, c *client.IPCClient, name string, meta core.HostMetadata) error {
+	params := protocol.HostUpdateParams{Name: name}
+	for _, f := range []struct {
+		value string
+		dst   **string
+	}{{meta.Notes, &params.Notes}, {meta.AuthHint, &params.AuthHint}, {meta.JumpDescription, &params.JumpDescription}} {
+		if f.value != "" {
+			*f.dst = &f.value
+		}
+	}
+	var result protocol.HostUpdateResult
+	return c.Call(ctx, "host.update", params, &result)
+}
@@ -0,0 +1,2 @@
+// Package synccmd はチームで共有する設定を git リポジトリまたは URL から取り込み、共有ルールを push する sync サブコマンドを提供する。
+package synccmd
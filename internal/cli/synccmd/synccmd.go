@@ -0,0 +1,143 @@
+package synccmd
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/teamsync"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/infra/teamrepo"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+)
+
+// teamDir は設定ディレクトリ内で共有設定を置くディレクトリ名。
+const teamDir = "team"
+
+// fetchTimeout は共有設定の取得と push のタイムアウト。
+const fetchTimeout = 60 * time.Second
+
+// RunSync は sync サブコマンドを実行する。
+func RunSync(configDir string, args []string) {
+	if len(args) == 0 {
+		cli.ExitError("%s", i18n.T("cli.sync.usage"))
+	}
+	repo := teamrepo.New(filepath.Join(configDir, teamDir))
+	switch args[0] {
+	case "pull":
+		runPull(configDir, repo, args[1:])
+	case "push":
+		runPush(configDir, repo, args[1:])
+	default:
+		cli.ExitError("%s", i18n.T("cli.sync.usage"))
+	}
+}
+
+// runPull は共有設定を取得し、共有ルールとホスト情報をデーモンに反映する。
+func runPull(configDir string, repo *teamrepo.Repo, args []string) {
+	fs := flag.NewFlagSet("sync pull", flag.ContinueOnError)
+	gitURL := fs.String("git", "", i18n.T("cli.flags.sync.git"))
+	url := fs.String("url", "", i18n.T("cli.flags.sync.url"))
+	dryRun := fs.Bool("dry-run", false, i18n.T("cli.flags.sync.dry_run"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	if err := repo.Pull(ctx, teamrepo.Source{Git: *gitURL, URL: *url}); err != nil {
+		cli.ExitErr(err, i18n.T("cli.sync.pull_failed", map[string]any{"Error": err}))
+	}
+	team, err := repo.Load()
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.sync.pull_failed", map[string]any{"Error": err}))
+	}
+
+	c, callCtx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+	plan := teamsync.Merge(team, listRules(callCtx, c), listHosts(callCtx, c))
+	printPlan(plan)
+	if *dryRun {
+		fmt.Println(i18n.T("cli.sync.dry_run"))
+		return
+	}
+	conflicts := slices.Concat(plan.Conflicts, apply(callCtx, c, plan))
+	if plan.Empty() {
+		fmt.Println(i18n.T("cli.sync.up_to_date"))
+	} else {
+		fmt.Println(i18n.T("cli.sync.pulled", map[string]any{
+			"Added": len(plan.Add), "Updated": len(plan.Replace), "Removed": len(plan.Remove), "Hosts": len(plan.Hosts),
+		}))
+	}
+	if len(conflicts) > 0 {
+		fmt.Fprintln(os.Stderr, i18n.T("cli.sync.conflicts", map[string]any{"Count": len(conflicts)}))
+		for _, conflict := range conflicts {
+			fmt.Fprintf(os.Stderr, "  ! %s\n", conflict)
+		}
+	}
+}
+
+// runPush はローカルの共有ルールを共有設定に書き込み、git リポジトリにコミットして push する。
+func runPush(configDir string, repo *teamrepo.Repo, args []string) {
+	fs := flag.NewFlagSet("sync push", flag.ContinueOnError)
+	message := fs.String("m", "Update team forwards", i18n.T("cli.flags.sync.message"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	c, callCtx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+	team, err := repo.Load()
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.sync.push_failed", map[string]any{"Error": err}))
+	}
+	team.Forwards = teamsync.Export(listRules(callCtx, c))
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+	pushed, err := repo.Push(ctx, team, *message)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.sync.push_failed", map[string]any{"Error": err}))
+	}
+	if !pushed {
+		fmt.Println(i18n.T("cli.sync.push_no_changes"))
+		return
+	}
+	fmt.Println(i18n.T("cli.sync.pushed", map[string]any{"Count": len(team.Forwards)}))
+}
+
+// listRules はデーモンの全ルールを返す。
+func listRules(ctx context.Context, c *client.IPCClient) []core.ForwardRule {
+	var result protocol.ForwardListResult
+	if err := c.Call(ctx, "forward.list", nil, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.list.get_forwards_failed", map[string]any{"Error": err}))
+	}
+	rules := make([]core.ForwardRule, 0, len(result.Forwards))
+	for _, info := range result.Forwards {
+		if rule, err := convert.ToForwardRule(info); err == nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// listHosts はデーモンの全ホストのメタデータをホスト名ごとに返す。
+func listHosts(ctx context.Context, c *client.IPCClient) map[string]core.HostMetadata {
+	var result protocol.HostListResult
+	if err := c.Call(ctx, "host.list", nil, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.list.get_hosts_failed", map[string]any{"Error": err}))
+	}
+	hosts := make(map[string]core.HostMetadata, len(result.Hosts))
+	for _, h := range result.Hosts {
+		hosts[h.Name] = core.HostMetadata{Notes: h.Notes, AuthHint: h.AuthHint, JumpDescription: h.JumpDescription}
+	}
+	return hosts
+}
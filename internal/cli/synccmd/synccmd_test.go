@@ -0,0 +1,154 @@
+package synccmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+const teamYAML = `forwards:
+  - name: db
+    host: bastion
+    type: local
+    local_port: 15432
+    remote_port: 5432
+  - name: web
+    host: bastion
+    type: local
+    local_port: 8080
+    remote_port: 80
+hosts:
+  bastion:
+    notes: shared bastion
+`
+
+// stubDaemon は sync が使う RPC に応答するモックデーモンに接続させ、受信したメソッドとルール名を記録する。
+func stubDaemon(t *testing.T) func() []string {
+	t.Helper()
+	orig := cli.ConnectDaemon
+	t.Cleanup(func() { cli.ConnectDaemon = orig })
+
+	sockPath := filepath.Join(t.TempDir(), "mock.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var mu sync.Mutex
+	var calls []string
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var req protocol.Request
+			var p struct{ Name string }
+			_ = json.Unmarshal(scanner.Bytes(), &req)
+			_ = json.Unmarshal(req.Params, &p)
+			var result any = map[string]any{}
+			switch req.Method {
+			case "forward.list":
+				result = protocol.ForwardListResult{Forwards: []protocol.ForwardInfo{
+					{Name: "my-web", Host: "dev", Type: "local", LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+					{Name: "team-old", Host: "bastion", Type: "dynamic", LocalPort: 1090},
+				}}
+			case "host.list":
+				result = protocol.HostListResult{Hosts: []protocol.HostInfo{{Name: "bastion"}}}
+			default:
+				mu.Lock()
+				calls = append(calls, req.Method+" "+p.Name)
+				mu.Unlock()
+			}
+			resp, _ := protocol.NewResponse(req.ID, result)
+			_ = json.NewEncoder(conn).Encode(resp)
+		}
+	}()
+
+	cli.ConnectDaemon = func(_ string) *client.IPCClient {
+		c := client.NewIPCClient(sockPath)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("mock connect: %v", err)
+		}
+		return c
+	}
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(calls)
+	}
+}
+
+func serveTeamConfig(t *testing.T) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, teamYAML)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func captureOutput(t *testing.T, fn func()) {
+	t.Helper()
+	origOut, origErr := os.Stdout, os.Stderr
+	devNull, _ := os.Open(os.DevNull)
+	os.Stdout, os.Stderr = devNull, devNull
+	defer func() {
+		os.Stdout, os.Stderr = origOut, origErr
+		_ = devNull.Close()
+	}()
+	fn()
+}
+
+func TestRunSync_Pull(t *testing.T) {
+	calls := stubDaemon(t)
+	captureOutput(t, func() { RunSync(t.TempDir(), []string{"pull", "--url", serveTeamConfig(t)}) })
+
+	// team-web は my-web とローカルポートが重なるため取り込まない
+	want := []string{"forward.delete team-old", "forward.add team-db", "host.update bastion"}
+	if got := calls(); !slices.Equal(got, want) {
+		t.Errorf("calls = %v, want %v", got, want)
+	}
+}
+
+func TestRunSync_PullDryRun(t *testing.T) {
+	calls := stubDaemon(t)
+	captureOutput(t, func() { RunSync(t.TempDir(), []string{"pull", "--url", serveTeamConfig(t), "--dry-run"}) })
+
+	if got := calls(); len(got) != 0 {
+		t.Errorf("calls = %v, want no changes in dry run", got)
+	}
+}
+
+func TestRunSync_Errors(t *testing.T) {
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	type exitCalled struct{}
+	cli.ExitFunc = func(int) { panic(exitCalled{}) }
+
+	for _, args := range [][]string{nil, {"status"}, {"pull"}, {"pull", "--url", "ftp://example.com/team.yaml"}} {
+		captureOutput(t, func() {
+			defer func() {
+				if _, ok := recover().(exitCalled); !ok {
+					t.Errorf("RunSync(%v) should exit with an error", args)
+				}
+			}()
+			RunSync(t.TempDir(), args)
+		})
+	}
+}
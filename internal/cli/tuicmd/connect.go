@@ -0,0 +1,61 @@
+package tuicmd
+
+import (
+	"os"
+
+	"golang.org/x/term"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/tui/pages/hostpicker"
+)
+
+// isTerminal は標準入力と標準エラー出力が端末かどうかを返す。テスト時に差し替え可能にするため変数として定義する。
+var isTerminal = func() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stderr.Fd()))
+}
+
+// pickHost はホストの選択画面を表示する。テスト時に差し替え可能にするため変数として定義する。
+var pickHost = hostpicker.Run
+
+// RunConnect は connect サブコマンドを実行する。
+// ホスト名を省略して端末から実行した場合は、あいまい検索のホスト選択画面を表示し、選んだホストに接続する。
+// 選ばずに終了した場合は何もしない。ホスト名を指定した場合と端末でない場合は cli.RunConnect に委ねる。
+func RunConnect(configDir string, args []string) {
+	if len(args) > 0 || !isTerminal() {
+		cli.RunConnect(configDir, args)
+		return
+	}
+
+	hosts := listHosts(configDir)
+	if len(hosts) == 0 {
+		cli.ExitError("%s", i18n.T("cli.connect.no_hosts"))
+	}
+	name, ok, err := pickHost(hosts)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.connect.pick_failed", map[string]any{"Error": err}))
+	}
+	if !ok {
+		return
+	}
+	cli.RunConnect(configDir, []string{name})
+}
+
+// listHosts はデーモンから選択画面に表示するホストの一覧を取得する。
+func listHosts(configDir string) []core.SSHHost {
+	client, ctx, cleanup := cli.DaemonCall(configDir)
+	defer cleanup()
+
+	var result protocol.HostListResult
+	if err := client.Call(ctx, "host.list", nil, &result); err != nil {
+		cli.ExitErr(err, i18n.T("cli.list.get_hosts_failed", map[string]any{"Error": err}))
+	}
+	hosts := make([]core.SSHHost, len(result.Hosts))
+	for i, h := range result.Hosts {
+		hosts[i] = convert.ToSSHHost(h)
+	}
+	return hosts
+}
@@ -0,0 +1,148 @@
+package tuicmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// stubConnectDaemon は host.list に hosts を返すモックのデーモンに接続するよう差し替え、
+// ssh.connect で接続したホスト名を返す関数を返す。
+func stubConnectDaemon(t *testing.T, hosts []protocol.HostInfo) func() []string {
+	t.Helper()
+	orig := cli.ConnectDaemon
+	t.Cleanup(func() { cli.ConnectDaemon = orig })
+
+	sockPath := filepath.Join(t.TempDir(), "mock.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var mu sync.Mutex
+	var connected []string
+	serve := func(conn net.Conn) {
+		defer func() { _ = conn.Close() }()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var req protocol.Request
+			_ = json.Unmarshal(scanner.Bytes(), &req)
+			var result any = map[string]any{}
+			switch req.Method {
+			case "host.list":
+				result = protocol.HostListResult{Hosts: hosts}
+			case "ssh.connect":
+				var p protocol.SSHConnectParams
+				_ = json.Unmarshal(req.Params, &p)
+				mu.Lock()
+				connected = append(connected, p.Host)
+				mu.Unlock()
+				result = protocol.SSHConnectResult{Host: p.Host, Status: "connected"}
+			}
+			resp, _ := protocol.NewResponse(req.ID, result)
+			_ = json.NewEncoder(conn).Encode(resp)
+		}
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serve(conn)
+		}
+	}()
+
+	cli.ConnectDaemon = func(_ string) *client.IPCClient {
+		c := client.NewIPCClient(sockPath)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("mock connect: %v", err)
+		}
+		return c
+	}
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(connected)
+	}
+}
+
+// stubPicker は端末から実行したものとして扱い、ホストの選択画面の代わりに pick を使う。
+func stubPicker(t *testing.T, pick func([]core.SSHHost) (string, bool, error)) {
+	t.Helper()
+	origTerm, origPick := isTerminal, pickHost
+	t.Cleanup(func() { isTerminal, pickHost = origTerm, origPick })
+	isTerminal = func() bool { return true }
+	pickHost = pick
+}
+
+func TestRunConnect_PicksHost(t *testing.T) {
+	connected := stubConnectDaemon(t, []protocol.HostInfo{
+		{Name: "prod", HostName: "10.0.0.1", Port: 22, User: "deploy", State: "disconnected"},
+		{Name: "staging", HostName: "10.0.0.2", Port: 22, User: "deploy", State: "disconnected"},
+	})
+	var offered []string
+	stubPicker(t, func(hosts []core.SSHHost) (string, bool, error) {
+		for _, h := range hosts {
+			offered = append(offered, h.Name)
+		}
+		return "staging", true, nil
+	})
+
+	RunConnect(t.TempDir(), nil)
+
+	if !slices.Equal(offered, []string{"prod", "staging"}) {
+		t.Errorf("picker hosts = %v, want [prod staging]", offered)
+	}
+	if got := connected(); !slices.Equal(got, []string{"staging"}) {
+		t.Errorf("ssh.connect hosts = %v, want [staging]", got)
+	}
+}
+
+func TestRunConnect_PickCancelled(t *testing.T) {
+	connected := stubConnectDaemon(t, []protocol.HostInfo{{Name: "prod"}})
+	stubPicker(t, func([]core.SSHHost) (string, bool, error) { return "", false, nil })
+
+	RunConnect(t.TempDir(), nil)
+
+	if got := connected(); len(got) != 0 {
+		t.Errorf("ssh.connect hosts = %v, want none after cancelling", got)
+	}
+}
+
+func TestRunConnect_NoHosts(t *testing.T) {
+	stubExit(t)
+	stubConnectDaemon(t, nil)
+	stubPicker(t, func([]core.SSHHost) (string, bool, error) {
+		t.Error("picker should not be shown without hosts")
+		return "", false, nil
+	})
+
+	code, stderr := captureExit(t, func() { RunConnect(t.TempDir(), nil) })
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1 (stderr: %s)", code, stderr)
+	}
+}
+
+func TestRunConnect_NotTerminal(t *testing.T) {
+	stubExit(t)
+	origTerm := isTerminal
+	t.Cleanup(func() { isTerminal = origTerm })
+	isTerminal = func() bool { return false }
+
+	// 端末でない場合はホスト名が必須
+	code, _ := captureExit(t, func() { RunConnect(t.TempDir(), nil) })
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
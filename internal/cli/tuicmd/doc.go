@@ -0,0 +1,2 @@
+// Package tuicmd は tui サブコマンドと、TUI の画面を使う connect サブコマンドのホスト選択の実装を提供する。
+package tuicmd
@@ -0,0 +1,168 @@
+package tuicmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/config"
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/autostart"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/infra/yamlstore"
+	"github.com/ousiassllc/moleport/internal/ipc/authtoken"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/tui/app"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// daemonManagerAdapter は daemon パッケージの関数を app.DaemonManager に適合させる。
+type daemonManagerAdapter struct{}
+
+func (daemonManagerAdapter) StartDaemonProcess(configDir string) (int, error) {
+	return autostart.StartDaemonProcess(configDir)
+}
+
+func (daemonManagerAdapter) EnsureDaemonWithRetry(configDir string, maxWait time.Duration) (*client.IPCClient, error) {
+	return autostart.EnsureDaemonWithRetry(configDir, maxWait)
+}
+
+// noDaemonManagerAdapter は --no-daemon を指定した TUI 用の app.DaemonManager。
+// 接続ではデーモンを自動起動しないが、TUI からの明示的な再起動は行う。
+type noDaemonManagerAdapter struct{ daemonManagerAdapter }
+
+func (noDaemonManagerAdapter) EnsureDaemonWithRetry(configDir string, _ time.Duration) (*client.IPCClient, error) {
+	return autostart.Connect(configDir)
+}
+
+// remoteManagerAdapter はリモートのデーモンに接続した TUI 用の app.DaemonManager。
+// リモートのデーモンはこのマシンから起動できないため、再起動は失敗として扱う。
+type remoteManagerAdapter struct{}
+
+func (remoteManagerAdapter) StartDaemonProcess(string) (int, error) {
+	return 0, errors.New("cannot start a remote daemon from this machine")
+}
+
+func (remoteManagerAdapter) EnsureDaemonWithRetry(configDir string, _ time.Duration) (*client.IPCClient, error) {
+	return cli.ConnectRemote(configDir)
+}
+
+// RunTUI は tui サブコマンドを実行する。
+func RunTUI(configDir string, args []string) {
+	fs := flag.NewFlagSet("tui", flag.ContinueOnError)
+	readOnlyFlag := fs.Bool("read-only", false, i18n.T("cli.flags.tui.read_only"))
+	tokenFileFlag := fs.String("token-file", "", i18n.T("cli.flags.tui.token_file"))
+	plainFlag := fs.Bool("plain", false, i18n.T("cli.flags.tui.plain"))
+
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+	tuiCfg := configTUI(configDir)
+	readOnly := *readOnlyFlag || tuiCfg.ReadOnly
+	// 色は NO_COLOR でも無効になる（lipgloss が環境変数から判定する）
+	theme.SetPlain(*plainFlag || tuiCfg.Plain)
+	token, err := loginToken(configDir, *tokenFileFlag, readOnly)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.tui.token_read_failed", map[string]any{"Error": err}))
+	}
+
+	var manager app.DaemonManager = daemonManagerAdapter{}
+	switch {
+	case cli.Remote != "":
+		manager = remoteManagerAdapter{}
+	case cli.NoDaemon:
+		manager = noDaemonManagerAdapter{}
+	default:
+		// デーモンが未起動なら自動起動
+		pidPath := daemon.PIDFilePath(configDir)
+		running, _ := pidfile.IsRunning(pidPath)
+		if !running {
+			pid, err := autostart.StartDaemonProcess(configDir)
+			if err != nil {
+				cli.ExitErr(err, i18n.T("cli.tui.daemon_start_failed", map[string]any{"Error": err}))
+			}
+			fmt.Println(i18n.T("cli.tui.daemon_started", map[string]any{"PID": pid}))
+		}
+	}
+
+	// リトライ付きで接続
+	client, err := manager.EnsureDaemonWithRetry(configDir, 5*time.Second)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.tui.daemon_connect_failed", map[string]any{"Error": err}))
+	}
+	defer func() { _ = client.Close() }()
+	client.SetProfile(cli.Profile)
+	if err := authenticate(client, token, readOnly); err != nil {
+		cli.ExitErr(err, i18n.T("cli.tui.daemon_connect_failed", map[string]any{"Error": err}))
+	}
+	// 初期読み込みの前にデーモンの対応機能を確認し、古いデーモンにはバッチを使わない。
+	// 互換性がない場合は起動後のバージョンチェックでデーモンの再起動を提案する
+	helloCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	_, _ = client.Hello(helloCtx, cli.Version)
+	cancel()
+
+	// Bubble Tea プログラム起動
+	model := app.NewMainModel(client, cli.Version, configDir)
+	model.SetDaemonManager(manager)
+	model.SetRemote(cli.Remote != "")
+	// デーモンが読み取り専用のロールを割り当てた接続（読み取り専用の証明書など）も閲覧専用で起動する
+	model.SetReadOnly(readOnly || client.IsReadOnly())
+	p := tea.NewProgram(model, tea.WithAltScreen())
+
+	// TUI クレデンシャルハンドラーを設定
+	client.SetCredentialHandler(app.NewTUICredentialHandler(p))
+
+	if _, err := p.Run(); err != nil {
+		cli.ExitErr(err, i18n.T("cli.tui.tui_error", map[string]any{"Error": err}))
+	}
+}
+
+// loginToken は auth.login で提示し直すトークンを返す。--token-file を指定した場合はそのファイル、
+// 閲覧専用モードでローカルのデーモンに接続する場合はデーモンが発行した読み取り専用トークンを読む。
+// 既定の読み取り専用トークンを読めない場合は空を返し、auth.login の Role のみで読み取り専用にする。
+func loginToken(configDir, tokenFile string, readOnly bool) (string, error) {
+	if tokenFile != "" {
+		return authtoken.Read(tokenFile)
+	}
+	if !readOnly || cli.Remote != "" {
+		return "", nil
+	}
+	token, err := authtoken.Read(daemon.ReadOnlyTokenPath(configDir))
+	if err != nil {
+		return "", nil
+	}
+	return token, nil
+}
+
+// authenticate は token があれば auth.login で提示し直し、閲覧専用モードではデーモン側の接続も読み取り専用にする
+// （クライアント側の制限だけでは接続が管理者のまま残る）。読み取り専用トークンで認証した接続は管理者に戻らない。
+func authenticate(c *client.IPCClient, token string, readOnly bool) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if token != "" {
+		if err := c.Login(ctx, token); err != nil {
+			return err
+		}
+	}
+	if readOnly && !c.IsReadOnly() {
+		return c.RestrictToReadOnly(ctx)
+	}
+	return nil
+}
+
+// configTUI は config.yaml の tui の設定を返す。起動前に必要な read_only と plain の判定に使う。
+// 読み込みに失敗した場合はゼロ値を返す。
+func configTUI(configDir string) core.TUIConfig {
+	cfgMgr := config.NewConfigManager(yamlstore.NewYAMLStore(), configDir)
+	cfg, err := cfgMgr.LoadConfig()
+	if err != nil {
+		return core.TUIConfig{}
+	}
+	return cfg.TUI
+}
@@ -0,0 +1,150 @@
+package tuicmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/ipc/authtoken"
+)
+
+type exitCalled struct{ code int }
+
+func stubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(exitCalled{code: c}) }
+}
+
+func captureExit(t *testing.T, fn func()) (code int, stderr string) {
+	t.Helper()
+	origStderr := os.Stderr
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = w.Close()
+		_ = r.Close()
+		os.Stderr = origStderr
+	})
+	os.Stderr = w
+	code = -1
+	func() {
+		defer func() {
+			if v := recover(); v != nil {
+				if ec, ok := v.(exitCalled); ok {
+					code = ec.code
+				} else {
+					panic(v)
+				}
+			}
+		}()
+		fn()
+	}()
+	_ = w.Close()
+	var buf bytes.Buffer
+	_, _ = buf.ReadFrom(r)
+	return code, buf.String()
+}
+
+func TestRunTUI_DaemonStartFails(t *testing.T) {
+	stubExit(t)
+	configDir := t.TempDir()
+
+	// daemon が起動できない環境（configDir は一時ディレクトリ）では
+	// StartDaemonProcess がエラーを返し、exitError が呼ばれる
+	code, stderr := captureExit(t, func() {
+		RunTUI(configDir, []string{})
+	})
+
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+	if stderr == "" {
+		t.Error("stderr should contain an error message")
+	}
+}
+
+func TestRunTUI_UnknownFlag(t *testing.T) {
+	stubExit(t)
+
+	code, _ := captureExit(t, func() {
+		RunTUI(t.TempDir(), []string{"--bogus"})
+	})
+	if code != 1 {
+		t.Errorf("exit code = %d, want 1", code)
+	}
+}
+
+func TestConfigTUI(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   core.TUIConfig
+	}{
+		{"missing config", "", core.TUIConfig{}},
+		{"read_only true", "tui:\n  read_only: true\n", core.TUIConfig{ReadOnly: true}},
+		{"read_only false", "tui:\n  read_only: false\n", core.TUIConfig{}},
+		{"plain true", "tui:\n  plain: true\n", core.TUIConfig{Plain: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.config != "" {
+				if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(tt.config), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+			got := configTUI(dir)
+			if got.ReadOnly != tt.want.ReadOnly || got.Plain != tt.want.Plain {
+				t.Errorf("configTUI() = %+v, want read_only=%v plain=%v", got, tt.want.ReadOnly, tt.want.Plain)
+			}
+		})
+	}
+}
+
+func TestLoginToken(t *testing.T) {
+	dir := t.TempDir()
+	readOnlyToken, err := authtoken.Generate(daemon.ReadOnlyTokenPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokenFile := filepath.Join(t.TempDir(), "shared.token")
+	if err := os.WriteFile(tokenFile, []byte("shared-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		configDir string
+		tokenFile string
+		readOnly  bool
+		want      string
+	}{
+		{"admin without token file", dir, "", false, ""},
+		{"read-only uses daemon read-only token", dir, "", true, readOnlyToken},
+		{"read-only without issued token", t.TempDir(), "", true, ""},
+		{"token file wins", dir, tokenFile, true, "shared-token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := loginToken(tt.configDir, tt.tokenFile, tt.readOnly)
+			if err != nil {
+				t.Fatalf("loginToken() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("loginToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := loginToken(dir, filepath.Join(dir, "missing.token"), false); err == nil {
+		t.Error("loginToken() with missing token file should fail")
+	}
+}
@@ -0,0 +1,2 @@
+// Package tunnelcmd は tunnel サブコマンドの実装を提供する。
+package tunnelcmd
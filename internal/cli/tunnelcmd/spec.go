@@ -0,0 +1,97 @@
+package tunnelcmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/dockertarget"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Spec は -L で指定するローカルフォワードの定義。
+type Spec struct {
+	BindAddr   string
+	LocalPort  int
+	RemoteHost string
+	RemotePort int
+}
+
+// ParseSpec は ssh -L と同じ [bind_address:]port:host:hostport 形式を解析する。
+// IPv6 アドレスは [::1] のように角括弧で囲む。host には Docker のコンテナ（docker:<コンテナ名>）も指定できる。
+func ParseSpec(s string) (Spec, error) {
+	fields := splitSpec(s)
+	if i := slices.Index(fields, strings.TrimSuffix(dockertarget.Prefix, ":")); i > 0 && i+1 < len(fields) {
+		fields = slices.Replace(fields, i, i+2, dockertarget.Prefix+fields[i+1])
+	}
+	if len(fields) == 3 {
+		fields = append([]string{""}, fields...)
+	}
+	if len(fields) != 4 || fields[2] == "" {
+		return Spec{}, fmt.Errorf("invalid forward %q: want [bind_address:]port:host:hostport", s)
+	}
+	spec := Spec{BindAddr: fields[0], RemoteHost: fields[2]}
+	for i, p := range []*int{&spec.LocalPort, &spec.RemotePort} {
+		field := fields[1+2*i]
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			return Spec{}, fmt.Errorf("invalid port %q in %q", field, s)
+		}
+		if err := core.ValidatePort(n); err != nil {
+			return Spec{}, err
+		}
+		*p = n
+	}
+	if err := core.ValidateBindAddr(spec.BindAddr); err != nil {
+		return Spec{}, err
+	}
+	return spec, nil
+}
+
+// splitSpec は s を ":" で分割する。角括弧内の ":" では分割せず、角括弧は取り除く。
+func splitSpec(s string) []string {
+	var fields []string
+	var b strings.Builder
+	bracket := false
+	for _, r := range s {
+		switch {
+		case r == '[' && !bracket:
+			bracket = true
+		case r == ']' && bracket:
+			bracket = false
+		case r == ':' && !bracket:
+			fields = append(fields, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return append(fields, b.String())
+}
+
+// String は Spec を "bind:port -> host:hostport" の表示用文字列にする。
+func (s Spec) String() string {
+	return fmt.Sprintf("%s:%d -> %s:%d", bindOrDefault(s.BindAddr), s.LocalPort, s.RemoteHost, s.RemotePort)
+}
+
+// FindRule は host 上で spec と同じ転送を行う local ルールを探し、その名前を返す。
+func FindRule(forwards []protocol.ForwardInfo, host string, spec Spec) (string, bool) {
+	for _, f := range forwards {
+		if f.Host == host && f.Type == core.Local.String() &&
+			f.LocalPort == spec.LocalPort && f.RemoteHost == spec.RemoteHost && f.RemotePort == spec.RemotePort &&
+			bindOrDefault(f.LocalBindAddr) == bindOrDefault(spec.BindAddr) {
+			return f.Name, true
+		}
+	}
+	return "", false
+}
+
+// bindOrDefault は未指定のバインドアドレスを既定の 127.0.0.1 に置き換える。
+func bindOrDefault(addr string) string {
+	if addr == "" {
+		return "127.0.0.1"
+	}
+	return addr
+}
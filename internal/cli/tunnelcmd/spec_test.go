@@ -0,0 +1,46 @@
+package tunnelcmd
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Spec
+		wantErr bool
+	}{
+		{"8080:localhost:80", Spec{LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}, false},
+		{"0.0.0.0:8080:db.internal:5432", Spec{BindAddr: "0.0.0.0", LocalPort: 8080, RemoteHost: "db.internal", RemotePort: 5432}, false},
+		{"[::1]:8080:[fd00::5]:80", Spec{BindAddr: "::1", LocalPort: 8080, RemoteHost: "fd00::5", RemotePort: 80}, false},
+		{"15432:docker:pg:5432", Spec{LocalPort: 15432, RemoteHost: "docker:pg", RemotePort: 5432}, false},
+		{"127.0.0.1:15432:docker:pg:5432", Spec{BindAddr: "127.0.0.1", LocalPort: 15432, RemoteHost: "docker:pg", RemotePort: 5432}, false},
+		{"8080:localhost", Spec{}, true},
+		{"8080::80", Spec{}, true},
+		{"http:localhost:80", Spec{}, true},
+		{"8080:localhost:70000", Spec{}, true},
+		{"bad host:8080:localhost:80", Spec{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseSpec(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseSpec(%q) = %+v, %v; want %+v, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestFindRule(t *testing.T) {
+	forwards := []protocol.ForwardInfo{
+		{Name: "other-host", Host: "db", Type: "local", LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		{Name: "remote", Host: "web", Type: "remote", LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		{Name: "match", Host: "web", Type: "local", LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80, LocalBindAddr: "127.0.0.1"},
+	}
+	if name, ok := FindRule(forwards, "web", Spec{LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}); !ok || name != "match" {
+		t.Errorf("FindRule = %q, %v; want match", name, ok)
+	}
+	if _, ok := FindRule(forwards, "web", Spec{BindAddr: "0.0.0.0", LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}); ok {
+		t.Error("FindRule should not match a different bind address")
+	}
+}
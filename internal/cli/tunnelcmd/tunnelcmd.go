@@ -0,0 +1,150 @@
+package tunnelcmd
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// waitInterrupt は Ctrl+C（SIGINT）または SIGTERM を受け取るまで待つ。テスト時に差し替え可能。
+var waitInterrupt = func() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+}
+
+// tunnel は tunnel サブコマンドで開始したフォワードの状態。
+// added はこのコマンドでルールを追加したか、started はこのコマンドで開始したかを表す。
+type tunnel struct {
+	name    string
+	added   bool
+	started bool
+}
+
+// RunTunnel は tunnel サブコマンドを実行する。
+// ssh -L と同じ指定でルールを追加（同じ転送のルールがあれば再利用）して開始する。
+// --wait 指定時は Ctrl+C まで待ち、開始したフォワードを停止して追加したルールを削除する。
+func RunTunnel(configDir string, args []string) {
+	fs := flag.NewFlagSet("tunnel", flag.ContinueOnError)
+	forward := fs.String("L", "", i18n.T("cli.flags.tunnel.local"))
+	wait := fs.Bool("wait", false, i18n.T("cli.flags.tunnel.wait"))
+	name := fs.String("name", "", i18n.T("cli.flags.tunnel.name"))
+	if err := fs.Parse(args); err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	// `tunnel <host> -L ...` のようにホスト名の後ろに置かれたフラグも解釈する
+	host := fs.Arg(0)
+	if host != "" {
+		if err := fs.Parse(fs.Args()[1:]); err != nil {
+			cli.ExitError("%v", err)
+		}
+	}
+	if host == "" || *forward == "" || fs.NArg() > 0 {
+		cli.ExitError("%s", i18n.T("cli.tunnel.usage"))
+	}
+	spec, err := ParseSpec(*forward)
+	if err != nil {
+		cli.ExitError("%v", err)
+	}
+
+	client := cli.ConnectDaemon(configDir)
+	defer func() { _ = client.Close() }()
+
+	t, err := open(client, host, spec, *name)
+	if err != nil {
+		cli.ExitErr(err, i18n.T("cli.tunnel.failed", map[string]any{"Error": err}))
+	}
+	msg := map[string]any{"Name": t.name, "Host": host, "Forward": spec.String()}
+	if t.started {
+		fmt.Println(i18n.T("cli.tunnel.started", msg))
+	} else {
+		fmt.Println(i18n.T("cli.tunnel.already_active", msg))
+	}
+	if !*wait {
+		return
+	}
+
+	fmt.Println(i18n.T("cli.tunnel.waiting"))
+	waitInterrupt()
+	if !t.started && !t.added {
+		return
+	}
+	if err := t.close(client); err != nil {
+		cli.ExitErr(err, i18n.T("cli.tunnel.failed", map[string]any{"Error": err}))
+	}
+	fmt.Println(i18n.T("cli.tunnel.closed", msg))
+}
+
+// open は host 上の spec と同じ転送のルールを探し、なければ追加して開始する。
+// 開始に失敗した場合、追加したルールは削除する。
+func open(c *client.IPCClient, host string, spec Spec, name string) (*tunnel, error) {
+	var list protocol.ForwardListResult
+	if err := call(c, "forward.list", protocol.ForwardListParams{Host: host}, &list); err != nil {
+		return nil, err
+	}
+	t := &tunnel{}
+	if existing, ok := FindRule(list.Forwards, host, spec); ok {
+		t.name = existing
+	} else {
+		params := protocol.ForwardAddParams{
+			Name:          name,
+			Host:          host,
+			Type:          "local",
+			LocalPort:     spec.LocalPort,
+			RemoteHost:    spec.RemoteHost,
+			RemotePort:    spec.RemotePort,
+			LocalBindAddr: spec.BindAddr,
+		}
+		var added protocol.ForwardAddResult
+		if err := call(c, "forward.add", params, &added); err != nil {
+			return nil, err
+		}
+		t.name, t.added = added.Name, true
+	}
+
+	err := call(c, "forward.start", protocol.ForwardStartParams{Name: t.name}, &protocol.ForwardStartResult{})
+	var rpcErr *protocol.RPCError
+	switch {
+	case err == nil:
+		t.started = true
+	case errors.As(err, &rpcErr) && rpcErr.Code == protocol.AlreadyConnected:
+		// 既に稼働中のルールはそのまま使い、終了時にも停止しない
+	default:
+		if t.added {
+			_ = call(c, "forward.delete", protocol.ForwardDeleteParams{Name: t.name}, &protocol.ForwardDeleteResult{})
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// close はこのコマンドで開始したフォワードを停止し、追加したルールを削除する。
+func (t *tunnel) close(c *client.IPCClient) error {
+	if t.started {
+		if err := call(c, "forward.stop", protocol.ForwardStopParams{Name: t.name}, &protocol.ForwardStopResult{}); err != nil {
+			return err
+		}
+	}
+	if t.added {
+		return call(c, "forward.delete", protocol.ForwardDeleteParams{Name: t.name}, &protocol.ForwardDeleteResult{})
+	}
+	return nil
+}
+
+// call は呼び出しごとにタイムアウト付きのコンテキストで RPC を呼び出す。
+// --wait で長時間待った後の呼び出しがタイムアウト済みのコンテキストを使わないようにする。
+func call(c *client.IPCClient, method string, params, result any) error {
+	ctx, cancel := cli.CallCtx()
+	defer cancel()
+	return c.Call(ctx, method, params, result)
+}
@@ -0,0 +1,185 @@
+package tunnelcmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/cli"
+	"github.com/ousiassllc/moleport/internal/cli/exitcode"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type exitCalled struct{ code int }
+
+func stubExit(t *testing.T) {
+	t.Helper()
+	orig := cli.ExitFunc
+	t.Cleanup(func() { cli.ExitFunc = orig })
+	cli.ExitFunc = func(c int) { panic(exitCalled{code: c}) }
+}
+
+func expectExit(t *testing.T, want int, fn func()) {
+	t.Helper()
+	origStderr := os.Stderr
+	devNull, _ := os.Open(os.DevNull)
+	os.Stderr = devNull
+	defer func() {
+		os.Stderr = origStderr
+		_ = devNull.Close()
+		v := recover()
+		if ec, ok := v.(exitCalled); !ok || ec.code != want {
+			t.Errorf("exit = %v, want exit code %d", v, want)
+		}
+	}()
+	fn()
+}
+
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("os.Pipe: %v", err)
+	}
+	os.Stdout = w
+	fn()
+	_ = w.Close()
+	os.Stdout = orig
+	var buf bytes.Buffer
+	_, _ = io.Copy(&buf, r)
+	_ = r.Close()
+	return buf.String()
+}
+
+// stubDaemon はモックデーモンに接続させる。forwards を forward.list の結果として返し、
+// startCode が 0 以外なら forward.start をそのエラーコードで失敗させる。呼び出されたメソッド名を返す。
+func stubDaemon(t *testing.T, forwards []protocol.ForwardInfo, startCode int) func() []string {
+	t.Helper()
+	orig := cli.ConnectDaemon
+	t.Cleanup(func() { cli.ConnectDaemon = orig })
+
+	sockPath := filepath.Join(t.TempDir(), "mock.sock")
+	ln, err := net.Listen("unix", sockPath)
+	if err != nil {
+		t.Fatalf("listen unix: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+
+	var mu sync.Mutex
+	var methods []string
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var req protocol.Request
+			_ = json.Unmarshal(scanner.Bytes(), &req)
+			mu.Lock()
+			methods = append(methods, req.Method)
+			mu.Unlock()
+			var resp protocol.Response
+			switch req.Method {
+			case "forward.list":
+				resp, _ = protocol.NewResponse(req.ID, protocol.ForwardListResult{Forwards: forwards})
+			case "forward.add":
+				resp, _ = protocol.NewResponse(req.ID, protocol.ForwardAddResult{Name: "web-8080"})
+			case "forward.start":
+				if startCode != 0 {
+					resp = protocol.NewErrorResponse(req.ID, startCode, "start failed")
+					break
+				}
+				resp, _ = protocol.NewResponse(req.ID, protocol.ForwardStartResult{Name: "web-8080"})
+			default:
+				resp, _ = protocol.NewResponse(req.ID, struct{}{})
+			}
+			_ = json.NewEncoder(conn).Encode(resp)
+		}
+	}()
+
+	cli.ConnectDaemon = func(_ string) *client.IPCClient {
+		c := client.NewIPCClient(sockPath)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("mock connect: %v", err)
+		}
+		return c
+	}
+	return func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(methods)
+	}
+}
+
+// stubWait は waitInterrupt を即座に戻る関数に差し替える。
+func stubWait(t *testing.T) {
+	t.Helper()
+	orig := waitInterrupt
+	t.Cleanup(func() { waitInterrupt = orig })
+	waitInterrupt = func() {}
+}
+
+func TestRunTunnel_WaitAddsAndRemoves(t *testing.T) {
+	methods := stubDaemon(t, nil, 0)
+	stubWait(t)
+
+	out := captureStdout(t, func() {
+		RunTunnel(t.TempDir(), []string{"web", "-L", "8080:localhost:80", "--wait"})
+	})
+
+	want := []string{"forward.list", "forward.add", "forward.start", "forward.stop", "forward.delete"}
+	if got := methods(); !slices.Equal(got, want) {
+		t.Errorf("methods = %v, want %v", got, want)
+	}
+	if !strings.Contains(out, "web-8080") || !strings.Contains(out, "localhost:80") {
+		t.Errorf("output = %q, want rule name and destination", out)
+	}
+}
+
+func TestRunTunnel_ReusesActiveRule(t *testing.T) {
+	existing := []protocol.ForwardInfo{{Name: "web-http", Host: "web", Type: "local", LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}}
+	methods := stubDaemon(t, existing, protocol.AlreadyConnected)
+	stubWait(t)
+
+	_ = captureStdout(t, func() {
+		RunTunnel(t.TempDir(), []string{"-L=8080:localhost:80", "--wait", "web"})
+	})
+
+	// 既存の稼働中ルールは追加・停止・削除しない
+	want := []string{"forward.list", "forward.start"}
+	if got := methods(); !slices.Equal(got, want) {
+		t.Errorf("methods = %v, want %v", got, want)
+	}
+}
+
+func TestRunTunnel_StartFailureRemovesAddedRule(t *testing.T) {
+	methods := stubDaemon(t, nil, protocol.PortConflict)
+	stubExit(t)
+
+	expectExit(t, exitcode.Conflict, func() { RunTunnel(t.TempDir(), []string{"web", "-L", "8080:localhost:80"}) })
+
+	want := []string{"forward.list", "forward.add", "forward.start", "forward.delete"}
+	if got := methods(); !slices.Equal(got, want) {
+		t.Errorf("methods = %v, want %v", got, want)
+	}
+}
+
+func TestRunTunnel_MissingArgs(t *testing.T) {
+	stubExit(t)
+
+	for _, args := range [][]string{nil, {"web"}, {"-L", "8080:localhost:80"}, {"web", "-L", "8080"}, {"web", "extra", "-L", "8080:localhost:80"}} {
+		expectExit(t, exitcode.Failure, func() { RunTunnel(t.TempDir(), args) })
+	}
+}
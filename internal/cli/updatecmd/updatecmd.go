@@ -9,6 +9,8 @@ import (
 	"github.com/ousiassllc/moleport/internal/cli"
 	"github.com/ousiassllc/moleport/internal/core/update"
 	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/autostart"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
@@ -40,7 +42,7 @@ func RunUpdate(configDir string, args []string) {
 
 	result, err := vc.LatestVersion(ctx)
 	if err != nil {
-		cli.ExitError("%s", i18n.T("cli.update.check_failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.update.check_failed", map[string]any{"Error": err}))
 	}
 
 	if result == nil || !result.UpdateAvailable {
@@ -64,7 +66,7 @@ func RunUpdate(configDir string, args []string) {
 	u := update.NewUpdater(vc)
 
 	pidPath := daemon.PIDFilePath(configDir)
-	daemonRunning, _ := daemon.IsRunning(pidPath)
+	daemonRunning, _ := pidfile.IsRunning(pidPath)
 
 	assetName := fmt.Sprintf("moleport_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH)
 	fmt.Println(i18n.T("cli.update.downloading", map[string]any{"Asset": assetName}))
@@ -86,7 +88,7 @@ func RunUpdate(configDir string, args []string) {
 		if daemonRunning {
 			restartDaemonAfterUpdate(configDir)
 		}
-		cli.ExitError("%s", i18n.T("cli.update.failed", map[string]any{"Error": err}))
+		cli.ExitErr(err, i18n.T("cli.update.failed", map[string]any{"Error": err}))
 	}
 
 	if daemonRunning {
@@ -99,7 +101,7 @@ func RunUpdate(configDir string, args []string) {
 
 // stopDaemonForUpdate はアップデート前にデーモンを停止する。
 func stopDaemonForUpdate(configDir string) {
-	client, err := daemon.EnsureDaemon(configDir)
+	client, err := autostart.EnsureDaemon(configDir)
 	if err != nil {
 		return
 	}
@@ -114,7 +116,7 @@ func stopDaemonForUpdate(configDir string) {
 
 // restartDaemonAfterUpdate はアップデート後にデーモンを再起動する。
 func restartDaemonAfterUpdate(configDir string) {
-	if _, err := daemon.StartDaemonProcess(configDir); err != nil {
+	if _, err := autostart.StartDaemonProcess(configDir); err != nil {
 		fmt.Fprintf(os.Stderr, "%s\n", i18n.T("cli.update.restart_failed"))
 	}
 }
@@ -6,6 +6,7 @@ import (
 	"runtime"
 
 	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
@@ -19,12 +20,12 @@ func RunVersion(configDir string, args []string) {
 
 	// デーモンが稼働中ならバージョンチェックを実行
 	pidPath := daemon.PIDFilePath(configDir)
-	running, _ := daemon.IsRunning(pidPath)
-	if !running {
+	running, _ := pidfile.IsRunning(pidPath)
+	if !running && Remote == "" {
 		return
 	}
 
-	client, err := daemon.EnsureDaemon(configDir)
+	client, err := Connect(configDir)
 	if err != nil {
 		return
 	}
@@ -0,0 +1,43 @@
+package core
+
+import (
+	"fmt"
+	"time"
+)
+
+// フォワードのアクセスログの出力先（ForwardRule.AccessLog）。
+const (
+	// AccessLogFile はルールごとのファイルに JSON Lines で出力する。
+	AccessLogFile = "file"
+	// AccessLogSlog はデーモンのログにアクセスログ専用のロガーで出力する。
+	AccessLogSlog = "log"
+)
+
+// AccessRecord はフォワードを通過した 1 つの接続のアクセスログ。接続の終了時に記録する。
+type AccessRecord struct {
+	Time     time.Time `json:"time"`
+	Rule     string    `json:"rule"`
+	Host     string    `json:"host"`
+	Type     string    `json:"type"`
+	Client   string    `json:"client"`
+	Target   string    `json:"target,omitempty"` // 転送先。Dynamic では SOCKS5 で要求された宛先
+	Duration float64   `json:"duration_ms"`
+	Sent     int64     `json:"bytes_sent"`
+	Received int64     `json:"bytes_received"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// AccessLogger は AccessLog が設定されたルールのアクセスログを出力先に書き込む。
+type AccessLogger interface {
+	LogAccess(rule ForwardRule, rec AccessRecord)
+}
+
+// validateAccessLog はアクセスログの出力先が空・file・log のいずれかであることを検証する。
+func validateAccessLog(dest string) error {
+	switch dest {
+	case "", AccessLogFile, AccessLogSlog:
+		return nil
+	default:
+		return fmt.Errorf("invalid access_log %q: must be %q or %q", dest, AccessLogFile, AccessLogSlog)
+	}
+}
@@ -1,9 +1,8 @@
 package core
 
 import (
-	"os"
-	"path/filepath"
-	"sync"
+	"reflect"
+	"strings"
 )
 
 // YAMLStore は YAML ファイルの読み書きを担う。
@@ -26,116 +25,36 @@ type ConfigManager interface {
 	ConfigDir() string
 }
 
-type configManager struct {
-	mu        sync.RWMutex
-	store     YAMLStore
-	configDir string
-	cached    *Config
+// SaveRules は rules のうち設定ファイルに保存するルール（PersistentRules）を cfgMgr の forwards に書き込む。
+// update を指定した場合は同じ更新の中で設定のほかの項目も書き換える。
+func SaveRules(cfgMgr ConfigManager, rules []ForwardRule, update ...func(*Config)) error {
+	persistent := PersistentRules(rules)
+	return cfgMgr.UpdateConfig(func(c *Config) {
+		c.Forwards = persistent
+		for _, fn := range update {
+			fn(c)
+		}
+	})
 }
 
-// NewConfigManager は ConfigManager の実装を返す。
-func NewConfigManager(store YAMLStore, configDir string) ConfigManager {
-	return &configManager{
-		store:     store,
-		configDir: configDir,
+// SchemaKey は設定の構造体フィールドの YAML のキー名と inline 指定の有無を返す。
+// yaml タグがない場合は yaml.v3 と同じく小文字のフィールド名を使う。
+func SchemaKey(sf reflect.StructField) (string, bool) {
+	name, opts, _ := strings.Cut(sf.Tag.Get("yaml"), ",")
+	inline := strings.Contains(opts, "inline")
+	if name == "" && !inline {
+		name = strings.ToLower(sf.Name)
 	}
+	return name, inline
 }
 
-func (m *configManager) configPath() string {
-	return filepath.Join(m.configDir, "config.yaml")
-}
-
-func (m *configManager) statePath() string {
-	return filepath.Join(m.configDir, "state.yaml")
-}
-
-// LoadConfig は config.yaml を読み込み、キャッシュに保存する。
-// ファイルが存在しない場合はデフォルト設定を返す。
-func (m *configManager) LoadConfig() (*Config, error) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	cfg := DefaultConfig()
-	if err := m.store.Read(m.configPath(), &cfg); err != nil {
-		return nil, err
-	}
-	m.cached = &cfg
-	return &cfg, nil
-}
-
-// SaveConfig は設定を config.yaml に書き込み、キャッシュを更新する。
-func (m *configManager) SaveConfig(config *Config) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if err := m.store.Write(m.configPath(), config); err != nil {
-		return err
-	}
-	c := *config
-	m.cached = &c
-	return nil
-}
-
-// GetConfig はキャッシュされた設定を返す。
-// LoadConfig が呼ばれていない場合はデフォルト設定を返す。
-func (m *configManager) GetConfig() *Config {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-
-	if m.cached == nil {
-		cfg := DefaultConfig()
-		return &cfg
-	}
-	c := *m.cached
-	return &c
-}
-
-// UpdateConfig は設定をアトミックに変更して保存する。
-func (m *configManager) UpdateConfig(fn func(*Config)) error {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	var cfg Config
-	if m.cached != nil {
-		cfg = *m.cached
-	} else {
-		cfg = DefaultConfig()
-	}
-
-	fn(&cfg)
-
-	if err := m.store.Write(m.configPath(), &cfg); err != nil {
-		return err
-	}
-	m.cached = &cfg
-	return nil
-}
-
-// LoadState は state.yaml を読み込む。
-func (m *configManager) LoadState() (*State, error) {
-	var state State
-	if err := m.store.Read(m.statePath(), &state); err != nil {
-		return nil, err
+// ParseSchemaTag は "key=value,key=value" 形式の schema タグ（Config のドキュメント参照）を解析する。
+func ParseSchemaTag(tag string) map[string]string {
+	opts := make(map[string]string)
+	for part := range strings.SplitSeq(tag, ",") {
+		if key, value, ok := strings.Cut(part, "="); ok {
+			opts[key] = value
+		}
 	}
-	return &state, nil
-}
-
-// SaveState は状態を state.yaml に書き込む。
-func (m *configManager) SaveState(state *State) error {
-	return m.store.Write(m.statePath(), state)
-}
-
-// DeleteState は state.yaml を削除する。
-// ファイルが存在しない場合はエラーを返さない。
-func (m *configManager) DeleteState() error {
-	err := os.Remove(m.statePath())
-	if err != nil && !os.IsNotExist(err) {
-		return err
-	}
-	return nil
-}
-
-// ConfigDir は設定ディレクトリのパスを返す。
-func (m *configManager) ConfigDir() string {
-	return m.configDir
+	return opts
 }
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+type configManager struct {
+	mu        sync.RWMutex
+	store     core.YAMLStore
+	configDir string
+	cached    *core.Config
+}
+
+// NewConfigManager は ConfigManager の実装を返す。
+func NewConfigManager(store core.YAMLStore, configDir string) core.ConfigManager {
+	return &configManager{
+		store:     store,
+		configDir: configDir,
+	}
+}
+
+func (m *configManager) configPath() string {
+	return filepath.Join(m.configDir, "config.yaml")
+}
+
+func (m *configManager) statePath() string {
+	return filepath.Join(m.configDir, "state.yaml")
+}
+
+// LoadConfig は config.yaml を読み込み、キャッシュに保存する。
+// ファイルが存在しない場合はデフォルト設定を返す。古いスキーマバージョンの設定は移行してから読み込む。
+// 設定が Validate で拒否された場合は *core.InvalidConfigError を返し、キャッシュは更新しない。
+func (m *configManager) LoadConfig() (*core.Config, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	cfg, err := m.decodeConfig(migrations)
+	if err != nil {
+		return nil, err
+	}
+	m.cached = &cfg
+	return &cfg, nil
+}
+
+// SaveConfig は設定を検証して config.yaml に書き込み、キャッシュを更新する。
+func (m *configManager) SaveConfig(config *core.Config) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if err := Validate(config); err != nil {
+		return err
+	}
+	if err := m.store.Write(m.configPath(), config); err != nil {
+		return err
+	}
+	c := *config
+	m.cached = &c
+	return nil
+}
+
+// GetConfig はキャッシュされた設定を返す。
+// LoadConfig が呼ばれていない場合はデフォルト設定を返す。
+func (m *configManager) GetConfig() *core.Config {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.cached == nil {
+		cfg := core.DefaultConfig()
+		return &cfg
+	}
+	c := *m.cached
+	return &c
+}
+
+// UpdateConfig は設定をアトミックに変更して保存する。変更後の設定が Validate で拒否された場合は保存しない。
+func (m *configManager) UpdateConfig(fn func(*core.Config)) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var cfg core.Config
+	if m.cached != nil {
+		cfg = *m.cached
+	} else {
+		cfg = core.DefaultConfig()
+	}
+
+	fn(&cfg)
+
+	if err := Validate(&cfg); err != nil {
+		return err
+	}
+	if err := m.store.Write(m.configPath(), &cfg); err != nil {
+		return err
+	}
+	m.cached = &cfg
+	return nil
+}
+
+// LoadState は state.yaml を読み込む。
+func (m *configManager) LoadState() (*core.State, error) {
+	var state core.State
+	if err := m.store.Read(m.statePath(), &state); err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// SaveState は状態を state.yaml に書き込む。
+func (m *configManager) SaveState(state *core.State) error {
+	return m.store.Write(m.statePath(), state)
+}
+
+// DeleteState は state.yaml を削除する。
+// ファイルが存在しない場合はエラーを返さない。
+func (m *configManager) DeleteState() error {
+	err := os.Remove(m.statePath())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ConfigDir は設定ディレクトリのパスを返す。
+func (m *configManager) ConfigDir() string {
+	return m.configDir
+}
@@ -0,0 +1,2 @@
+// Package config は core.ConfigManager の実装（config.yaml と state.yaml の読み書きとキャッシュ）を提供する。
+package config
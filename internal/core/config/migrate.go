@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// schemaVersionKey は config.yaml でスキーマバージョンを表すキー。
+const schemaVersionKey = "schema_version"
+
+// migration は config.yaml の内容を 1 バージョン分移行する。raw はファイルをデコードしたマップで、直接書き換える。
+type migration func(raw map[string]any) error
+
+// migrations は設定の移行処理の一覧。migrations[i] はバージョン i の設定をバージョン i+1 に移行する。
+// 項目の名前や形式を変える場合は、ここに移行処理を追加して core.ConfigSchemaVersion を上げる。
+var migrations = []migration{
+	// 0 → 1: schema_version の導入。項目の変更はない
+	func(map[string]any) error { return nil },
+}
+
+// schemaVersion は raw のスキーマバージョンを返す。schema_version がない設定はバージョン 0 とみなす。
+func schemaVersion(raw map[string]any) (int, error) {
+	v, ok := raw[schemaVersionKey]
+	if !ok || v == nil {
+		return 0, nil
+	}
+	n, ok := v.(int)
+	if !ok || n < 0 {
+		return 0, fmt.Errorf("invalid %s: %v", schemaVersionKey, v)
+	}
+	return n, nil
+}
+
+// migrate は raw を from から steps の末尾のバージョンまで順に移行し、移行後のバージョンを書き込む。
+func migrate(raw map[string]any, from int, steps []migration) error {
+	for v := from; v < len(steps); v++ {
+		if err := steps[v](raw); err != nil {
+			return fmt.Errorf("migrate config from version %d: %w", v, err)
+		}
+		raw[schemaVersionKey] = v + 1
+	}
+	return nil
+}
+
+// upgrade は raw を steps の末尾のバージョンまで移行して既定値に重ねてデコードし、移行前のバージョンを返す。
+// raw が nil の場合は既定値を返す。現在より新しいバージョンの設定は移行せずにそのままデコードする。
+func upgrade(raw map[string]any, steps []migration) (core.Config, int, error) {
+	cfg := core.DefaultConfig()
+	if raw == nil {
+		return cfg, len(steps), nil
+	}
+	from, err := schemaVersion(raw)
+	if err != nil {
+		return cfg, 0, err
+	}
+	if from < len(steps) {
+		if err := migrate(raw, from, steps); err != nil {
+			return cfg, 0, err
+		}
+	}
+
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return cfg, 0, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, 0, err
+	}
+	return cfg, from, nil
+}
+
+// Parse は config.yaml の内容を解析し、古いスキーマバージョンの場合はメモリ上で移行してから Validate で検証する。
+func Parse(data []byte) (*core.Config, error) {
+	var raw map[string]any
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	cfg, _, err := upgrade(raw, migrations)
+	if err != nil {
+		return nil, err
+	}
+	if err := Validate(&cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// decodeConfig は config.yaml を読み込み、必要に応じて移行して検証した設定を返す。
+// 移行した場合は移行前のファイルをバックアップし、移行後の設定を書き戻す。検証に失敗した場合は書き戻さない。
+// 現在より新しいバージョンの設定は移行せずにそのまま読み込む。
+func (m *configManager) decodeConfig(steps []migration) (core.Config, error) {
+	var raw map[string]any
+	if err := m.store.Read(m.configPath(), &raw); err != nil {
+		return core.DefaultConfig(), err
+	}
+	cfg, from, err := upgrade(raw, steps)
+	if err != nil {
+		return cfg, err
+	}
+	if from > len(steps) {
+		slog.Warn("config schema is newer than supported", "schema_version", from, "supported", len(steps))
+	}
+	if err := Validate(&cfg); err != nil {
+		return cfg, err
+	}
+	if from >= len(steps) {
+		return cfg, nil
+	}
+
+	backup, err := m.backupConfig(from)
+	if err != nil {
+		return cfg, err
+	}
+	if err := m.store.Write(m.configPath(), &cfg); err != nil {
+		return cfg, err
+	}
+	slog.Info("config migrated", "from", from, "to", cfg.SchemaVersion, "backup", backup)
+	return cfg, nil
+}
+
+// backupConfig は移行前の config.yaml を config.yaml.v<version>.bak に複製し、そのパスを返す。
+func (m *configManager) backupConfig(version int) (string, error) {
+	data, err := os.ReadFile(m.configPath())
+	if err != nil {
+		return "", err
+	}
+	backup := fmt.Sprintf("%s.v%d.bak", m.configPath(), version)
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return "", fmt.Errorf("backup config: %w", err)
+	}
+	return backup, nil
+}
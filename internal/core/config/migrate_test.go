@@ -0,0 +1,114 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func writeConfig(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrations_CoverSchemaVersion(t *testing.T) {
+	if len(migrations) != core.ConfigSchemaVersion {
+		t.Errorf("len(migrations) = %d, want core.ConfigSchemaVersion (%d)", len(migrations), core.ConfigSchemaVersion)
+	}
+}
+
+func TestConfigManager_LoadConfig_MigratesLegacyConfig(t *testing.T) {
+	dir := t.TempDir()
+	legacy := "log:\n  level: debug\n"
+	writeConfig(t, dir, legacy)
+
+	cfg, err := NewConfigManager(newTestStore(), dir).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.SchemaVersion != core.ConfigSchemaVersion || cfg.Log.Level != "debug" {
+		t.Errorf("LoadConfig() = schema %d, level %q", cfg.SchemaVersion, cfg.Log.Level)
+	}
+
+	backup, err := os.ReadFile(filepath.Join(dir, "config.yaml.v0.bak"))
+	if err != nil || string(backup) != legacy {
+		t.Errorf("backup = %q, %v, want the legacy file", backup, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "config.yaml"))
+	if !strings.Contains(string(data), "schema_version: 1") {
+		t.Errorf("config.yaml was not rewritten:\n%s", data)
+	}
+}
+
+func TestConfigManager_LoadConfig_CurrentVersionNotRewritten(t *testing.T) {
+	dir := t.TempDir()
+	current := "schema_version: 1\nlog:\n  level: warn\n"
+	writeConfig(t, dir, current)
+
+	cfg, err := NewConfigManager(newTestStore(), dir).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.Log.Level != "warn" {
+		t.Errorf("Log.Level = %q, want warn", cfg.Log.Level)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "config.yaml")); string(data) != current {
+		t.Errorf("config.yaml was rewritten:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.yaml.v1.bak")); !os.IsNotExist(err) {
+		t.Errorf("unexpected backup, stat error = %v", err)
+	}
+}
+
+func TestConfigManager_DecodeConfig_RenameMigration(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "schema_version: 1\nssh_config: /etc/ssh/ssh_config\n")
+	steps := append(append([]migration{}, migrations...), func(raw map[string]any) error {
+		raw["ssh_config_path"] = raw["ssh_config"]
+		delete(raw, "ssh_config")
+		return nil
+	})
+
+	m := &configManager{store: newTestStore(), configDir: dir}
+	cfg, err := m.decodeConfig(steps)
+	if err != nil {
+		t.Fatalf("decodeConfig() error = %v", err)
+	}
+	if cfg.SSHConfigPath != "/etc/ssh/ssh_config" || cfg.SchemaVersion != 2 {
+		t.Errorf("decodeConfig() = path %q, schema %d", cfg.SSHConfigPath, cfg.SchemaVersion)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.yaml.v1.bak")); err != nil {
+		t.Errorf("backup missing: %v", err)
+	}
+}
+
+func TestConfigManager_LoadConfig_NewerVersion(t *testing.T) {
+	dir := t.TempDir()
+	newer := "schema_version: 99\nlanguage: ja\n"
+	writeConfig(t, dir, newer)
+
+	cfg, err := NewConfigManager(newTestStore(), dir).LoadConfig()
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if cfg.SchemaVersion != 99 || cfg.Language != "ja" {
+		t.Errorf("LoadConfig() = schema %d, language %q", cfg.SchemaVersion, cfg.Language)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "config.yaml")); string(data) != newer {
+		t.Errorf("config.yaml was rewritten:\n%s", data)
+	}
+}
+
+func TestConfigManager_LoadConfig_InvalidSchemaVersion(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "schema_version: latest\n")
+
+	if _, err := NewConfigManager(newTestStore(), dir).LoadConfig(); err == nil {
+		t.Error("LoadConfig() should fail for a non-numeric schema_version")
+	}
+}
@@ -1,10 +1,12 @@
-package core
+package config
 
 import (
 	"path/filepath"
 	"sync"
 	"testing"
 	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
 )
 
 func TestConfigManager_LoadState_Empty(t *testing.T) {
@@ -27,10 +29,10 @@ func TestConfigManager_SaveAndLoadState(t *testing.T) {
 	cm := NewConfigManager(store, dir)
 
 	now := time.Now().Truncate(time.Second)
-	state := &State{
+	state := &core.State{
 		LastUpdated: now,
-		ActiveForwards: []ForwardRule{
-			{Name: "web", Host: "server", Type: Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		ActiveForwards: []core.ForwardRule{
+			{Name: "web", Host: "server", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
 		},
 		SelectedHost: "server",
 	}
@@ -61,10 +63,10 @@ func TestConfigManager_DeleteState(t *testing.T) {
 	cm := NewConfigManager(store, dir)
 
 	// state.yaml を作成
-	state := &State{
+	state := &core.State{
 		LastUpdated: time.Now(),
-		ActiveForwards: []ForwardRule{
-			{Name: "web", Host: "server", Type: Local, LocalPort: 8080},
+		ActiveForwards: []core.ForwardRule{
+			{Name: "web", Host: "server", Type: core.Local, LocalPort: 8080},
 		},
 	}
 	if err := cm.SaveState(state); err != nil {
@@ -108,7 +110,7 @@ func TestConfigManager_ConfigPath(t *testing.T) {
 	cm := NewConfigManager(store, dir)
 
 	// config.yaml がディレクトリ内に作成されることを確認
-	cfg := &Config{SSHConfigPath: "/test"}
+	cfg := &core.Config{SSHConfigPath: "/test"}
 	if err := cm.SaveConfig(cfg); err != nil {
 		t.Fatalf("SaveConfig() error = %v", err)
 	}
@@ -139,7 +141,7 @@ func TestConfigManager_Concurrent(t *testing.T) {
 		wg.Add(1)
 		go func(i int) {
 			defer wg.Done()
-			_ = cm.UpdateConfig(func(cfg *Config) {
+			_ = cm.UpdateConfig(func(cfg *core.Config) {
 				cfg.Reconnect.MaxRetries = i
 			})
 		}(i)
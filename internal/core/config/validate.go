@@ -0,0 +1,222 @@
+package config
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"net"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/depgraph"
+	"github.com/ousiassllc/moleport/internal/core/hostforward"
+	"github.com/ousiassllc/moleport/internal/core/themeconf"
+	"net/url"
+)
+
+var durationType = reflect.TypeFor[core.Duration]()
+
+// Validate は設定を検証し、問題がある場合は全ての問題を含む *core.InvalidConfigError を返す。
+// 文字列（リストの要素を含む）の列挙値と整数の範囲は core.Config の schema タグに従い、ゼロ値は未設定（既定値を使う）とみなす。
+// 期間は負の値を拒否する。フォワードルールは core.ValidateForwardRule で検証し、ルール名・グループ名の重複と depends_on の循環、
+// Webhook のテンプレートの構文エラーと、http・https 以外の登録先の URL、未定義のテーマと不正な色のユーザーテーマ、
+// 書式が正しくないか名前が重複するホスト別のフォワード（hosts.<name>.forwards）も拒否する。
+func Validate(cfg *core.Config) error {
+	v := &validator{}
+	v.walk(reflect.ValueOf(cfg).Elem(), "")
+
+	names := make(map[string]bool, len(cfg.Forwards))
+	for i, rule := range cfg.Forwards {
+		field := fmt.Sprintf("forwards[%d]", i)
+		if rule.Name != "" && names[rule.Name] {
+			v.add(field, fmt.Sprintf("duplicate rule name %q", rule.Name))
+		}
+		names[rule.Name] = true
+		if err := core.ValidateForwardRule(rule); err != nil {
+			v.add(field, fmt.Sprintf("rule %q: %v", rule.Name, err))
+		}
+	}
+	if err := depgraph.Validate(cfg.Forwards); err != nil {
+		v.add("forwards", err.Error())
+	}
+	groups := make(map[string]bool, len(cfg.Groups))
+	for i, g := range cfg.Groups {
+		if groups[g.Name] {
+			v.add(fmt.Sprintf("groups[%d]", i), fmt.Sprintf("duplicate group name %q", g.Name))
+		}
+		groups[g.Name] = true
+	}
+	for i, w := range cfg.Webhooks {
+		if w.URL == "" {
+			v.add(fmt.Sprintf("webhooks[%d].url", i), "url is required")
+		}
+		if _, err := template.New("webhook").Parse(w.Template); err != nil {
+			v.add(fmt.Sprintf("webhooks[%d].template", i), err.Error())
+		}
+	}
+	if u, err := url.Parse(cfg.Registry.URL); cfg.Registry.URL != "" && (err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+		v.add("registry.url", "must be an http or https URL")
+	}
+	v.hostPort("dns.listen", cfg.DNS.Listen)
+	v.hostPort("remote.listen", cfg.Remote.Listen)
+	v.hostPort("event_bridge.listen", cfg.EventBridge.Listen)
+	v.themes(&cfg.TUI)
+	v.hostForwards(cfg.Hosts, names)
+
+	if len(v.issues) > 0 {
+		return &core.InvalidConfigError{Issues: v.issues}
+	}
+	return nil
+}
+
+// hostForwards はホスト別のフォワードの書式と、forwards のルール名 names・他のホスト別のフォワードとの名前の重複を検証する。
+func (v *validator) hostForwards(hosts map[string]core.HostConfig, names map[string]bool) {
+	seen := maps.Clone(names)
+	for _, host := range slices.Sorted(maps.Keys(hosts)) {
+		for i, spec := range hosts[host].Forwards {
+			field := fmt.Sprintf("hosts.%s.forwards[%d]", host, i)
+			rule, err := hostforward.Parse(host, spec, slices.Collect(maps.Keys(seen)))
+			if err != nil {
+				v.add(field, err.Error())
+				continue
+			}
+			if seen[rule.Name] {
+				v.add(field, fmt.Sprintf("duplicate rule name %q", rule.Name))
+			}
+			seen[rule.Name] = true
+		}
+	}
+}
+
+// validator は schema タグに基づく検証で見つかった問題を蓄積する。
+type validator struct {
+	issues []core.ConfigIssue
+}
+
+func (v *validator) add(field, msg string) {
+	v.issues = append(v.issues, core.ConfigIssue{Field: field, Message: msg})
+}
+
+// hostPort は addr が host:port 形式であることを検証する。空の場合は既定値を使うため検証しない。
+func (v *validator) hostPort(field, addr string) {
+	if addr == "" {
+		return
+	}
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		v.add(field, err.Error())
+	}
+}
+
+// themes は tui.themes のユーザーテーマと、tui.theme.accent が組み込みのアクセントかユーザーテーマを指すことを検証する。
+func (v *validator) themes(t *core.TUIConfig) {
+	bases := make(map[string]string, len(t.Themes))
+	for i, ut := range t.Themes {
+		field := fmt.Sprintf("tui.themes[%d]", i)
+		switch {
+		case ut.Name == "":
+			v.add(field+".name", "name is required")
+		case slices.Contains(themeconf.Accents, ut.Name):
+			v.add(field+".name", fmt.Sprintf("%q is a built-in theme", ut.Name))
+		case bases[ut.Name] != "":
+			v.add(field+".name", fmt.Sprintf("duplicate theme name %q", ut.Name))
+		}
+		bases[ut.Name] = cmp.Or(ut.Base, "dark")
+		c := reflect.ValueOf(ut.Colors)
+		for j := range c.NumField() {
+			if s := c.Field(j).String(); s != "" && !validColor(s) {
+				name, _ := core.SchemaKey(c.Type().Field(j))
+				v.add(field+".colors."+name, fmt.Sprintf("invalid color %q (use #RRGGBB, #RGB or an ANSI color number 0-255)", s))
+			}
+		}
+	}
+
+	accent := t.Theme.Accent
+	if accent == "" || slices.Contains(themeconf.Accents, accent) {
+		return
+	}
+	base, ok := bases[accent]
+	switch {
+	case !ok:
+		v.add("tui.theme.accent", fmt.Sprintf("unknown theme %q (must be one of %s, or a name in tui.themes)", accent, strings.Join(themeconf.Accents, ", ")))
+	case t.Theme.Base != "" && t.Theme.Base != base:
+		v.add("tui.theme.base", fmt.Sprintf("theme %q is defined for base %q", accent, base))
+	}
+}
+
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor は s が #RRGGBB・#RGB 形式の色か 0〜255 の ANSI カラー番号であるかを返す。
+func validColor(s string) bool {
+	if n, err := strconv.Atoi(s); err == nil {
+		return n >= 0 && n <= 255
+	}
+	return hexColor.MatchString(s)
+}
+
+// walk は構造体 rv のフィールドを再帰的に検証する。forwards は Validate が個別に検証するため対象外とする。
+func (v *validator) walk(rv reflect.Value, prefix string) {
+	t := rv.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, inline := core.SchemaKey(sf)
+		if !sf.IsExported() || name == "-" || (prefix == "" && name == "forwards") {
+			continue
+		}
+		if inline {
+			v.walk(rv.Field(i), prefix)
+			continue
+		}
+		v.field(rv.Field(i), prefix+name, core.ParseSchemaTag(sf.Tag.Get("schema")))
+	}
+}
+
+// field は値 fv を schema タグ tags に従って検証する。
+func (v *validator) field(fv reflect.Value, path string, tags map[string]string) {
+	switch {
+	case fv.Type() == durationType:
+		if d := fv.Interface().(core.Duration); d.Duration < 0 { // safe: durationType との比較で型は確定している
+			v.add(path, "must not be negative")
+		}
+		return
+	case fv.Kind() == reflect.Pointer:
+		if !fv.IsNil() {
+			v.field(fv.Elem(), path, tags)
+		}
+		return
+	}
+
+	switch fv.Kind() {
+	case reflect.Struct:
+		v.walk(fv, path+".")
+	case reflect.Slice:
+		for i := range fv.Len() {
+			v.field(fv.Index(i), fmt.Sprintf("%s[%d]", path, i), tags)
+		}
+	case reflect.Map:
+		iter := fv.MapRange()
+		for iter.Next() {
+			v.field(iter.Value(), path+"."+iter.Key().String(), tags)
+		}
+	case reflect.String:
+		enum := tags["enum"]
+		if s := fv.String(); s != "" && enum != "" && !slices.Contains(strings.Split(enum, "|"), s) {
+			v.add(path, fmt.Sprintf("invalid value %q (must be one of %s)", s, strings.ReplaceAll(enum, "|", ", ")))
+		}
+	case reflect.Int:
+		n := fv.Int()
+		if n == 0 {
+			return
+		}
+		if lo, err := strconv.ParseInt(tags["min"], 10, 64); err == nil && n < lo {
+			v.add(path, fmt.Sprintf("must be at least %d", lo))
+		}
+		if hi, err := strconv.ParseInt(tags["max"], 10, 64); err == nil && n > hi {
+			v.add(path, fmt.Sprintf("must be at most %d", hi))
+		}
+	}
+}
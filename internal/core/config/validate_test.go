@@ -0,0 +1,179 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+	"github.com/ousiassllc/moleport/internal/core/themeconf"
+)
+
+func TestValidate_Default(t *testing.T) {
+	cfg := core.DefaultConfig()
+	if err := Validate(&cfg); err != nil {
+		t.Errorf("Validate(DefaultConfig()) error = %v", err)
+	}
+	if err := Validate(&core.Config{}); err != nil {
+		t.Errorf("Validate(zero Config) error = %v", err)
+	}
+}
+
+func TestValidate_Issues(t *testing.T) {
+	retries := -2
+	cfg := core.DefaultConfig()
+	cfg.Log.Level = "verbose"
+	cfg.Reconnect.MaxRetries = -1
+	cfg.Reconnect.MaxDelay = core.Duration{Duration: -time.Second}
+	cfg.Hosts = map[string]core.HostConfig{"prod": {Reconnect: &core.ReconnectOverride{MaxRetries: &retries}}}
+	cfg.Forwards = []core.ForwardRule{
+		{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemotePort: 80},
+		{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8081, RemotePort: 80},
+		{Name: "bad", Type: core.Dynamic, LocalPort: 1080},
+		{Name: "loop", Host: "prod", Type: core.Local, LocalPort: 8082, RemotePort: 80, DependsOn: []string{"loop"}},
+	}
+	cfg.Groups = []core.ForwardGroup{{Name: "dev"}, {Name: "dev"}}
+	cfg.DNS.Listen = "5354"
+	cfg.Remote.Listen = "7443"
+	cfg.Notifications.Events = []string{"ssh.error", "forward.started"}
+	cfg.Webhooks = []serviceconf.Webhook{{URL: "https://example.com/hook", Template: "{{.Host"}}
+	cfg.Registry.URL = "registry.example.com/tunnels"
+
+	err := Validate(&cfg)
+	var invalid *core.InvalidConfigError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Validate() error = %v, want *core.InvalidConfigError", err)
+	}
+	got := make(map[string]bool, len(invalid.Issues))
+	for _, issue := range invalid.Issues {
+		got[issue.Field] = true
+	}
+	for _, field := range []string{
+		"log.level", "reconnect.max_retries", "reconnect.max_delay", "hosts.prod.reconnect.max_retries",
+		"forwards[1]", "forwards[2]", "forwards", "groups[1]", "dns.listen", "remote.listen",
+		"notifications.events[1]", "webhooks[0].template", "registry.url",
+	} {
+		if !got[field] {
+			t.Errorf("issues = %+v, missing %s", invalid.Issues, field)
+		}
+	}
+	if len(invalid.Issues) != 13 {
+		t.Errorf("len(issues) = %d, want 13: %+v", len(invalid.Issues), invalid.Issues)
+	}
+}
+
+func TestValidate_Themes(t *testing.T) {
+	cfg := core.DefaultConfig()
+	cfg.TUI.Theme = core.ThemeConfig{Base: "dark", Accent: "ocean"}
+	cfg.TUI.Themes = []themeconf.UserTheme{
+		{Name: "ocean", Base: "dark", Colors: themeconf.Colors{Accent: "#0EA5E9", Dim: "240"}},
+	}
+	if err := Validate(&cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	cfg.TUI.Theme.Base = "light"
+	cfg.TUI.Themes = append(cfg.TUI.Themes,
+		themeconf.UserTheme{Name: "solarized", Colors: themeconf.Colors{Text: "white"}},
+		themeconf.UserTheme{Name: "ocean", Colors: themeconf.Colors{Accent: "256"}},
+	)
+	err := Validate(&cfg)
+	var invalid *core.InvalidConfigError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Validate() error = %v, want *core.InvalidConfigError", err)
+	}
+	var got []string
+	for _, issue := range invalid.Issues {
+		got = append(got, issue.Field)
+	}
+	want := []string{
+		"tui.themes[1].name", "tui.themes[1].colors.text",
+		"tui.themes[2].name", "tui.themes[2].colors.accent", "tui.theme.base",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("issue fields = %v, want %v", got, want)
+	}
+
+	cfg.TUI.Themes = nil
+	cfg.TUI.Theme = core.ThemeConfig{Base: "dark", Accent: "ocean"}
+	if err := Validate(&cfg); err == nil {
+		t.Error("Validate() with an undefined accent should fail")
+	}
+}
+
+func TestParse(t *testing.T) {
+	cfg, err := Parse([]byte("language: ja\n"))
+	if err != nil || cfg.Language != "ja" || cfg.SchemaVersion != core.ConfigSchemaVersion {
+		t.Errorf("Parse() = %+v, %v", cfg, err)
+	}
+	if _, err := Parse([]byte("language: fr\n")); err == nil {
+		t.Error("Parse() should reject an unsupported language")
+	}
+	if _, err := Parse([]byte("reconnect: [\n")); err == nil {
+		t.Error("Parse() should reject malformed YAML")
+	}
+}
+
+func TestConfigManager_LoadConfig_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	writeConfig(t, dir, "log:\n  level: verbose\n")
+	cm := NewConfigManager(newTestStore(), dir)
+
+	var invalid *core.InvalidConfigError
+	if _, err := cm.LoadConfig(); !errors.As(err, &invalid) {
+		t.Fatalf("LoadConfig() error = %v, want *core.InvalidConfigError", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config.yaml.v0.bak")); !os.IsNotExist(err) {
+		t.Errorf("invalid config should not be migrated, stat error = %v", err)
+	}
+}
+
+func TestConfigManager_UpdateConfig_RejectsInvalid(t *testing.T) {
+	cm := NewConfigManager(newTestStore(), t.TempDir())
+	if _, err := cm.LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+
+	err := cm.UpdateConfig(func(cfg *core.Config) { cfg.Reconnect.MaxRetries = -1 })
+	var invalid *core.InvalidConfigError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("UpdateConfig() error = %v, want *core.InvalidConfigError", err)
+	}
+	if got := cm.GetConfig().Reconnect.MaxRetries; got != core.DefaultConfig().Reconnect.MaxRetries {
+		t.Errorf("MaxRetries = %d, want the previous value", got)
+	}
+	if err := cm.SaveConfig(&core.Config{Log: core.LogConfig{Level: "trace"}}); err == nil {
+		t.Error("SaveConfig() should reject an invalid log level")
+	}
+}
+
+func TestValidate_HostForwards(t *testing.T) {
+	cfg := core.DefaultConfig()
+	cfg.Forwards = []core.ForwardRule{{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}}
+	cfg.Hosts = map[string]core.HostConfig{
+		"prod": {Forwards: []string{"L 5432:localhost:5432 name=db", "D 1080"}},
+	}
+	if err := Validate(&cfg); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	cfg.Hosts["prod"] = core.HostConfig{Forwards: []string{"L 5432:localhost:5432 name=db", "L 8080:localhost:80 name=web"}}
+	cfg.Hosts["stg"] = core.HostConfig{Forwards: []string{"L 5433:localhost:5432 name=db", "L 5432"}}
+	err := Validate(&cfg)
+	var invalid *core.InvalidConfigError
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Validate() error = %v, want *core.InvalidConfigError", err)
+	}
+	var got []string
+	for _, issue := range invalid.Issues {
+		got = append(got, issue.Field)
+	}
+	want := []string{"hosts.prod.forwards[1]", "hosts.stg.forwards[0]", "hosts.stg.forwards[1]"}
+	if !slices.Equal(got, want) {
+		t.Errorf("issue fields = %v, want %v", got, want)
+	}
+}
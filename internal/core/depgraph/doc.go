@@ -0,0 +1,3 @@
+// Package depgraph はフォワードルールの depends_on による依存関係を解決し、依存先を先に開始して
+// 依存先が失敗したときに依存するルールを停止する ForwardManager を提供する。
+package depgraph
@@ -0,0 +1,141 @@
+package depgraph
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// Node は依存関係の要素。Host が true の場合は SSH ホスト、それ以外はフォワードルール。
+type Node struct {
+	Name string
+	Host bool
+}
+
+// CycleError は depends_on の依存関係が循環していることを示す。
+type CycleError struct {
+	Path []string
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("depends_on cycle: %s", strings.Join(e.Path, " -> "))
+}
+
+// Graph はフォワードルールの依存関係のグラフ。
+// depends_on の要素はルール名に一致すればルール、一致しなければホスト名として扱う。
+type Graph struct {
+	order []string
+	deps  map[string][]Node
+}
+
+// New は rules の depends_on から依存関係のグラフを作る。
+func New(rules []core.ForwardRule) *Graph {
+	g := &Graph{deps: make(map[string][]Node, len(rules))}
+	for _, r := range rules {
+		g.order = append(g.order, r.Name)
+		g.deps[r.Name] = nil
+	}
+	for _, r := range rules {
+		for _, d := range r.DependsOn {
+			_, isRule := g.deps[d]
+			g.deps[r.Name] = append(g.deps[r.Name], Node{Name: d, Host: !isRule})
+		}
+	}
+	return g
+}
+
+// Dependencies は name が依存するルールとホストを、依存先が先になる順序で返す。name 自身は含めない。
+// 依存関係が循環している場合は *CycleError を返す。
+func (g *Graph) Dependencies(name string) ([]Node, error) {
+	var out []Node
+	done := make(map[Node]bool)
+	var visit func(n Node, path []string) error
+	visit = func(n Node, path []string) error {
+		if slices.Contains(path, n.Name) {
+			return &CycleError{Path: append(path, n.Name)}
+		}
+		if done[n] {
+			return nil
+		}
+		if !n.Host {
+			for _, d := range g.deps[n.Name] {
+				if err := visit(d, append(path, n.Name)); err != nil {
+					return err
+				}
+			}
+		}
+		done[n] = true
+		out = append(out, n)
+		return nil
+	}
+	if err := visit(Node{Name: name}, nil); err != nil {
+		return nil, err
+	}
+	return out[:len(out)-1], nil
+}
+
+// Dependents は n に直接または間接的に依存するルールを、ルールの登録順に返す。
+func (g *Graph) Dependents(n Node) []string {
+	affected := map[Node]bool{n: true}
+	for changed := true; changed; {
+		changed = false
+		for _, name := range g.order {
+			if affected[Node{Name: name}] {
+				continue
+			}
+			for _, d := range g.deps[name] {
+				if affected[d] {
+					affected[Node{Name: name}] = true
+					changed = true
+					break
+				}
+			}
+		}
+	}
+	var out []string
+	for _, name := range g.order {
+		if affected[Node{Name: name}] && (n.Host || name != n.Name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// Sort は rules を依存先が先になるように並べ替えて返す。依存関係のないルールの順序は保つ。
+// 循環しているルールは元の位置に残す。
+func Sort(rules []core.ForwardRule) []core.ForwardRule {
+	g := New(rules)
+	byName := make(map[string]core.ForwardRule, len(rules))
+	for _, r := range rules {
+		byName[r.Name] = r
+	}
+	out := make([]core.ForwardRule, 0, len(rules))
+	added := make(map[string]bool, len(rules))
+	for _, r := range rules {
+		deps, _ := g.Dependencies(r.Name)
+		for _, d := range deps {
+			if dep, ok := byName[d.Name]; ok && !d.Host && !added[d.Name] {
+				added[d.Name] = true
+				out = append(out, dep)
+			}
+		}
+		if !added[r.Name] {
+			added[r.Name] = true
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// Validate は rules の depends_on に自分自身への依存や循環がないことを検証する。
+func Validate(rules []core.ForwardRule) error {
+	g := New(rules)
+	for _, r := range rules {
+		if _, err := g.Dependencies(r.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
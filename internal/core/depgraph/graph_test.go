@@ -0,0 +1,78 @@
+package depgraph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func testRules() []core.ForwardRule {
+	return []core.ForwardRule{
+		{Name: "db", Host: "internal", DependsOn: []string{"vpn", "bastion"}},
+		{Name: "web", Host: "prod"},
+		{Name: "vpn", Host: "gateway"},
+		{Name: "report", Host: "internal", DependsOn: []string{"db"}},
+	}
+}
+
+func TestGraph_Dependencies(t *testing.T) {
+	g := New(testRules())
+
+	got, err := g.Dependencies("report")
+	if err != nil {
+		t.Fatalf("Dependencies: %v", err)
+	}
+	want := []Node{{Name: "vpn"}, {Name: "bastion", Host: true}, {Name: "db"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Dependencies(report) = %+v, want %+v", got, want)
+	}
+	if got, _ := g.Dependencies("web"); len(got) != 0 {
+		t.Errorf("Dependencies(web) = %+v, want none", got)
+	}
+}
+
+func TestGraph_Cycle(t *testing.T) {
+	rules := []core.ForwardRule{
+		{Name: "a", DependsOn: []string{"b"}},
+		{Name: "b", DependsOn: []string{"c"}},
+		{Name: "c", DependsOn: []string{"a"}},
+		{Name: "self", DependsOn: []string{"self"}},
+	}
+	_, err := New(rules).Dependencies("a")
+	var cycle *CycleError
+	if !errors.As(err, &cycle) || !reflect.DeepEqual(cycle.Path, []string{"a", "b", "c", "a"}) {
+		t.Errorf("Dependencies(a) error = %v, want cycle a -> b -> c -> a", err)
+	}
+	if err := Validate(rules[3:]); err == nil {
+		t.Error("Validate(self dependency) = nil, want error")
+	}
+	if err := Validate(testRules()); err != nil {
+		t.Errorf("Validate(testRules) = %v, want nil", err)
+	}
+}
+
+func TestGraph_Dependents(t *testing.T) {
+	g := New(testRules())
+
+	if got := g.Dependents(Node{Name: "vpn"}); !reflect.DeepEqual(got, []string{"db", "report"}) {
+		t.Errorf("Dependents(vpn) = %v, want [db report]", got)
+	}
+	if got := g.Dependents(Node{Name: "bastion", Host: true}); !reflect.DeepEqual(got, []string{"db", "report"}) {
+		t.Errorf("Dependents(host bastion) = %v, want [db report]", got)
+	}
+	if got := g.Dependents(Node{Name: "report"}); len(got) != 0 {
+		t.Errorf("Dependents(report) = %v, want none", got)
+	}
+}
+
+func TestSort(t *testing.T) {
+	var names []string
+	for _, r := range Sort(testRules()) {
+		names = append(names, r.Name)
+	}
+	if want := []string{"vpn", "db", "web", "report"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Sort() = %v, want %v", names, want)
+	}
+}
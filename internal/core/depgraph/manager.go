@@ -0,0 +1,78 @@
+package depgraph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// Manager は depends_on に従ってフォワードを開始・停止する core.ForwardManager。
+// StartForward は依存先のホストへの接続と依存先のルールの開始を先に行う。
+// HandleForwardEvent・HandleSSHEvent は依存先の失敗を検知して、依存するルールを停止する。
+type Manager struct {
+	core.ForwardManager
+	ssh core.SSHManager
+}
+
+// Wrap は fwd を依存関係に従って開始・停止する Manager を返す。
+func Wrap(fwd core.ForwardManager, ssh core.SSHManager) *Manager {
+	return &Manager{ForwardManager: fwd, ssh: ssh}
+}
+
+// StartForward は ruleName の依存先を依存関係の順に開始してから ruleName を開始する。
+// 依存先のホストは接続し、アクティブでない依存先のルールは開始する。依存先の開始に失敗した場合は ruleName を開始しない。
+func (m *Manager) StartForward(ctx context.Context, ruleName string, cb core.CredentialCallback) error {
+	deps, err := New(m.GetRules()).Dependencies(ruleName)
+	if err != nil {
+		return err
+	}
+	for _, d := range deps {
+		if d.Host {
+			if m.ssh.IsConnected(d.Name) {
+				continue
+			}
+			if err := m.ssh.ConnectWithCallback(ctx, d.Name, cb); err != nil {
+				return fmt.Errorf("dependency host %q: %w", d.Name, err)
+			}
+			continue
+		}
+		var alreadyActive *core.AlreadyActiveError
+		if err := m.ForwardManager.StartForward(ctx, d.Name, cb); err != nil && !errors.As(err, &alreadyActive) {
+			return fmt.Errorf("dependency %q: %w", d.Name, err)
+		}
+	}
+	return m.ForwardManager.StartForward(ctx, ruleName, cb)
+}
+
+// HandleForwardEvent はフォワードがエラーで停止した場合に、そのルールに依存するアクティブなルールを停止する。
+// nil の Manager では何もしない。
+func (m *Manager) HandleForwardEvent(evt core.ForwardEvent) {
+	if m != nil && evt.Type == core.ForwardEventError {
+		m.stopDependents(Node{Name: evt.RuleName}, evt.RuleName)
+	}
+}
+
+// HandleSSHEvent はホストの接続が切断またはエラーになった場合に、そのホストに依存するアクティブなルールを停止する。
+// nil の Manager では何もしない。
+func (m *Manager) HandleSSHEvent(evt core.SSHEvent) {
+	if m != nil && (evt.Type == core.SSHEventDisconnected || evt.Type == core.SSHEventError) {
+		m.stopDependents(Node{Name: evt.HostName, Host: true}, evt.HostName)
+	}
+}
+
+// stopDependents は n に依存するルールのうちアクティブなものを停止する。
+func (m *Manager) stopDependents(n Node, cause string) {
+	for _, name := range New(m.GetRules()).Dependents(n) {
+		s, err := m.GetSession(name)
+		if err != nil || s.Status != core.Active {
+			continue
+		}
+		slog.Warn("stopping forward because its dependency failed", "rule", name, "dependency", cause)
+		if err := m.StopForward(name); err != nil {
+			slog.Warn("failed to stop dependent forward", "rule", name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package depgraph
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+)
+
+// fakeForwardManager は開始・停止を記録する core.ForwardManager。
+type fakeForwardManager struct {
+	core.ForwardManager
+	rules   []core.ForwardRule
+	active  map[string]bool
+	fail    map[string]bool
+	started []string
+	stopped []string
+}
+
+func newFake() *fakeForwardManager {
+	return &fakeForwardManager{rules: testRules(), active: map[string]bool{}, fail: map[string]bool{}}
+}
+
+func (f *fakeForwardManager) GetRules() []core.ForwardRule { return f.rules }
+
+func (f *fakeForwardManager) StartForward(_ context.Context, name string, _ core.CredentialCallback) error {
+	if f.active[name] {
+		return &core.AlreadyActiveError{Name: name}
+	}
+	if f.fail[name] {
+		return errors.New("connection refused")
+	}
+	f.started = append(f.started, name)
+	f.active[name] = true
+	return nil
+}
+
+func (f *fakeForwardManager) GetSession(name string) (*core.ForwardSession, error) {
+	if f.active[name] {
+		return &core.ForwardSession{Status: core.Active}, nil
+	}
+	return &core.ForwardSession{Status: core.Stopped}, nil
+}
+
+func (f *fakeForwardManager) StopForward(name string) error {
+	f.stopped = append(f.stopped, name)
+	delete(f.active, name)
+	return nil
+}
+
+func TestManager_StartForward(t *testing.T) {
+	fwd, ssh := newFake(), forwardtest.NewMockSSHManager()
+	m := Wrap(fwd, ssh)
+
+	if err := m.StartForward(t.Context(), "report", nil); err != nil {
+		t.Fatalf("StartForward: %v", err)
+	}
+	if want := []string{"vpn", "db", "report"}; !reflect.DeepEqual(fwd.started, want) {
+		t.Errorf("started = %v, want %v", fwd.started, want)
+	}
+	if !ssh.IsConnected("bastion") {
+		t.Error("dependency host bastion is not connected")
+	}
+
+	// 依存先がアクティブな場合は開始し直さない
+	fwd.started = nil
+	delete(fwd.active, "report")
+	if err := m.StartForward(t.Context(), "report", nil); err != nil || !reflect.DeepEqual(fwd.started, []string{"report"}) {
+		t.Errorf("StartForward with active dependencies: err = %v, started = %v", err, fwd.started)
+	}
+}
+
+func TestManager_StartForwardDependencyFails(t *testing.T) {
+	fwd := newFake()
+	fwd.fail["vpn"] = true
+	m := Wrap(fwd, forwardtest.NewMockSSHManager())
+
+	if err := m.StartForward(t.Context(), "db", nil); err == nil {
+		t.Fatal("StartForward = nil, want dependency error")
+	}
+	if fwd.active["db"] {
+		t.Error("db started although its dependency failed")
+	}
+}
+
+func TestManager_StopsDependents(t *testing.T) {
+	fwd := newFake()
+	for _, name := range []string{"vpn", "db", "web", "report"} {
+		fwd.active[name] = true
+	}
+	m := Wrap(fwd, forwardtest.NewMockSSHManager())
+
+	m.HandleForwardEvent(core.ForwardEvent{Type: core.ForwardEventStopped, RuleName: "vpn"})
+	if len(fwd.stopped) != 0 {
+		t.Errorf("stopped = %v after a user stop, want none", fwd.stopped)
+	}
+	m.HandleForwardEvent(core.ForwardEvent{Type: core.ForwardEventError, RuleName: "vpn"})
+	if want := []string{"db", "report"}; !reflect.DeepEqual(fwd.stopped, want) {
+		t.Errorf("stopped = %v, want %v", fwd.stopped, want)
+	}
+
+	fwd.stopped = nil
+	fwd.active["db"] = true
+	m.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventDisconnected, HostName: "bastion"})
+	if want := []string{"db"}; !reflect.DeepEqual(fwd.stopped, want) {
+		t.Errorf("stopped = %v after bastion disconnected, want %v", fwd.stopped, want)
+	}
+}
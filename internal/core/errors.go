@@ -13,6 +13,22 @@ var (
 	ErrCredentialCancelled = errors.New("credential cancelled")
 )
 
+// エラーの種類を表すセンチネルエラー。下記の構造化エラー型は Is で対応する種類に一致するため、
+// 呼び出し元は errors.Is で種類を判定し、詳細が必要な場合は errors.As で取り出す。
+var (
+	ErrHostNotFound  = errors.New("host not found")
+	ErrRuleNotFound  = errors.New("rule not found")
+	ErrGroupNotFound = errors.New("group not found")
+	ErrAlreadyExists = errors.New("already exists")
+	ErrAlreadyActive = errors.New("already active")
+	ErrNotConnected  = errors.New("not connected")
+	ErrPortInUse     = errors.New("port in use")
+	ErrAuthFailed    = errors.New("authentication failed")
+)
+
+// notFoundKinds は NotFoundError.Resource ごとのセンチネルエラー。
+var notFoundKinds = map[string]error{"host": ErrHostNotFound, "rule": ErrRuleNotFound, "group": ErrGroupNotFound}
+
 // NotFoundError はリソースが見つからないエラー。
 type NotFoundError struct {
 	Resource string // "host" or "rule"
@@ -23,6 +39,11 @@ func (e *NotFoundError) Error() string {
 	return fmt.Sprintf("%s %q not found", e.Resource, e.Name)
 }
 
+// Is は Resource に対応するセンチネルエラー（ErrHostNotFound 等）に一致する。
+func (e *NotFoundError) Is(target error) bool {
+	return target != nil && notFoundKinds[e.Resource] == target
+}
+
 // AlreadyExistsError はリソースが既に存在するエラー。
 type AlreadyExistsError struct {
 	Resource string
@@ -33,6 +54,9 @@ func (e *AlreadyExistsError) Error() string {
 	return fmt.Sprintf("%s %q already exists", e.Resource, e.Name)
 }
 
+// Is は ErrAlreadyExists に一致する。
+func (e *AlreadyExistsError) Is(target error) bool { return target == ErrAlreadyExists }
+
 // AlreadyActiveError は既にアクティブなエラー。
 type AlreadyActiveError struct {
 	Name string
@@ -42,6 +66,9 @@ func (e *AlreadyActiveError) Error() string {
 	return fmt.Sprintf("%q is already active", e.Name)
 }
 
+// Is は ErrAlreadyActive に一致する。
+func (e *AlreadyActiveError) Is(target error) bool { return target == ErrAlreadyActive }
+
 // NotConnectedError はホスト未接続エラー。
 type NotConnectedError struct {
 	HostName string
@@ -51,6 +78,9 @@ func (e *NotConnectedError) Error() string {
 	return fmt.Sprintf("host %q is not connected", e.HostName)
 }
 
+// Is は ErrNotConnected に一致する。
+func (e *NotConnectedError) Is(target error) bool { return target == ErrNotConnected }
+
 // AuthRequiredError は認証が必要なエラー。
 type AuthRequiredError struct {
 	HostName string
@@ -65,6 +95,62 @@ func (e *AuthRequiredError) Unwrap() error {
 	return e.Err
 }
 
+// Is は ErrAuthFailed に一致する。
+func (e *AuthRequiredError) Is(target error) bool { return target == ErrAuthFailed }
+
+// InvalidRuleNameError はルール名が命名規則に違反しているエラー。
+// Suggestion は規則に沿い、既存ルールとも重複しない代替名。
+type InvalidRuleNameError struct {
+	Name       string
+	Reason     string
+	Suggestion string
+}
+
+func (e *InvalidRuleNameError) Error() string {
+	return fmt.Sprintf("invalid rule name %q: %s (suggestion: %q)", e.Name, e.Reason, e.Suggestion)
+}
+
+// PortConflictError はローカルポートが他のルールまたは他のプロセスで使用中のエラー。
+// Rule は使用中のルール名（他のプロセスの場合は空）、Suggestion は空いているポート（見つからない場合は 0）。
+type PortConflictError struct {
+	Port       int
+	Rule       string
+	Suggestion int
+}
+
+func (e *PortConflictError) Error() string {
+	msg := fmt.Sprintf("port %d is already in use", e.Port)
+	if e.Rule != "" {
+		msg += fmt.Sprintf(" by rule %q", e.Rule)
+	}
+	if e.Suggestion != 0 {
+		msg += fmt.Sprintf(" (suggestion: %d)", e.Suggestion)
+	}
+	return msg
+}
+
+// Is は ErrPortInUse に一致する。
+func (e *PortConflictError) Is(target error) bool { return target == ErrPortInUse }
+
+// ConfigIssue は設定項目 1 つの検証エラー。Field は YAML のキーをドットでつないだパス（例: "reconnect.max_retries"、"forwards[0]"）。
+type ConfigIssue struct {
+	Field   string
+	Message string
+}
+
+// InvalidConfigError は設定の検証に失敗したエラー。Issues は検出した全ての問題。
+type InvalidConfigError struct {
+	Issues []ConfigIssue
+}
+
+func (e *InvalidConfigError) Error() string {
+	msgs := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		msgs[i] = issue.Field + ": " + issue.Message
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
 // authFailureMessages は認証失敗を示すエラー文字列のリスト。
 var authFailureMessages = []string{
 	"unable to authenticate",
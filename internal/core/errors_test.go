@@ -2,6 +2,7 @@ package core_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/ousiassllc/moleport/internal/core"
@@ -28,3 +29,34 @@ func TestIsAuthFailure(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorKinds(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind error
+	}{
+		{&core.NotFoundError{Resource: "host", Name: "prod"}, core.ErrHostNotFound},
+		{&core.NotFoundError{Resource: "rule", Name: "web"}, core.ErrRuleNotFound},
+		{&core.NotFoundError{Resource: "group", Name: "stg"}, core.ErrGroupNotFound},
+		{&core.AlreadyExistsError{Resource: "rule", Name: "web"}, core.ErrAlreadyExists},
+		{&core.AlreadyActiveError{Name: "web"}, core.ErrAlreadyActive},
+		{&core.NotConnectedError{HostName: "prod"}, core.ErrNotConnected},
+		{&core.PortConflictError{Port: 8080}, core.ErrPortInUse},
+		{&core.AuthRequiredError{HostName: "prod", Err: errors.New("unable to authenticate")}, core.ErrAuthFailed},
+	}
+	kinds := []error{
+		core.ErrHostNotFound, core.ErrRuleNotFound, core.ErrGroupNotFound, core.ErrAlreadyExists,
+		core.ErrAlreadyActive, core.ErrNotConnected, core.ErrPortInUse, core.ErrAuthFailed,
+	}
+	for _, tt := range tests {
+		wrapped := fmt.Errorf("wrapped: %w", tt.err)
+		for _, kind := range kinds {
+			if got := errors.Is(wrapped, kind); got != (kind == tt.kind) {
+				t.Errorf("errors.Is(%v, %v) = %v", tt.err, kind, got)
+			}
+		}
+	}
+	if errors.Is(&core.NotFoundError{Resource: "profile", Name: "x"}, core.ErrHostNotFound) {
+		t.Error("NotFoundError with an unknown resource should not match any kind")
+	}
+}
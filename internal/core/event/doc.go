@@ -0,0 +1,2 @@
+// Package event は SSH・フォワードの各マネージャーが共有するイベント配信の仕組みを提供する。
+package event
@@ -0,0 +1,55 @@
+package event
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// ChannelBuffer はイベントチャネルのバッファサイズ。
+const ChannelBuffer = 16
+
+// Emitter はイベントの配信を管理するジェネリック型。
+// mu は埋め込み先の *sync.RWMutex をポインタで共有する。
+type Emitter[E any] struct {
+	mu          *sync.RWMutex
+	subscribers []chan E
+}
+
+// NewEmitter は Emitter を初期化して返す。
+func NewEmitter[E any](mu *sync.RWMutex) Emitter[E] {
+	return Emitter[E]{mu: mu}
+}
+
+// Emit はイベントを発生順に全サブスクライバーへ非ブロッキングで送信する。
+func (e *Emitter[E]) Emit(events ...E) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, event := range events {
+		for _, ch := range e.subscribers {
+			select {
+			case ch <- event:
+			default:
+				slog.Warn("event dropped", "event_type", fmt.Sprintf("%T", event))
+			}
+		}
+	}
+}
+
+// Subscribe はイベントチャネルを作成・登録して返す。
+// 呼び出し元が mu.Lock() を保持していること。
+func (e *Emitter[E]) Subscribe() chan E {
+	ch := make(chan E, ChannelBuffer)
+	e.subscribers = append(e.subscribers, ch)
+	return ch
+}
+
+// CloseSubscribers は全チャネルをクローズし、サブスクライバー一覧をクリアする。
+// 呼び出し元が mu.Lock() を保持していること。
+func (e *Emitter[E]) CloseSubscribers() {
+	for _, ch := range e.subscribers {
+		close(ch)
+	}
+	e.subscribers = nil
+}
@@ -1,16 +1,16 @@
-package core_test
+package event_test
 
 import (
 	"sync"
 	"testing"
 	"time"
 
-	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/event"
 )
 
-func TestEventEmitter_MultipleSubscribers(t *testing.T) {
+func TestEmitter_MultipleSubscribers(t *testing.T) {
 	var mu sync.RWMutex
-	emitter := core.NewEventEmitter[string](&mu)
+	emitter := event.NewEmitter[string](&mu)
 
 	mu.Lock()
 	ch1 := emitter.Subscribe()
@@ -38,16 +38,16 @@ func TestEventEmitter_MultipleSubscribers(t *testing.T) {
 	}
 }
 
-func TestEventEmitter_BufferFullDrop(t *testing.T) {
+func TestEmitter_BufferFullDrop(t *testing.T) {
 	var mu sync.RWMutex
-	emitter := core.NewEventEmitter[int](&mu)
+	emitter := event.NewEmitter[int](&mu)
 
 	mu.Lock()
 	ch := emitter.Subscribe()
 	mu.Unlock()
 
 	// バッファを埋める
-	for i := range core.EventChannelBuffer {
+	for i := range event.ChannelBuffer {
 		emitter.Emit(i)
 	}
 
@@ -67,7 +67,7 @@ func TestEventEmitter_BufferFullDrop(t *testing.T) {
 
 	// ドロップされたイベントはチャネルに入っていないことを確認
 	received := 0
-	for range core.EventChannelBuffer {
+	for range event.ChannelBuffer {
 		select {
 		case <-ch:
 			received++
@@ -75,8 +75,8 @@ func TestEventEmitter_BufferFullDrop(t *testing.T) {
 		}
 	}
 
-	if received != core.EventChannelBuffer {
-		t.Errorf("received %d events, want %d", received, core.EventChannelBuffer)
+	if received != event.ChannelBuffer {
+		t.Errorf("received %d events, want %d", received, event.ChannelBuffer)
 	}
 
 	// 追加イベントは入っていない
@@ -87,9 +87,9 @@ func TestEventEmitter_BufferFullDrop(t *testing.T) {
 	}
 }
 
-func TestEventEmitter_CloseSubscribers(t *testing.T) {
+func TestEmitter_CloseSubscribers(t *testing.T) {
 	var mu sync.RWMutex
-	emitter := core.NewEventEmitter[string](&mu)
+	emitter := event.NewEmitter[string](&mu)
 
 	mu.Lock()
 	ch1 := emitter.Subscribe()
@@ -1,5 +1,13 @@
 package core
 
+import (
+	"context"
+	"fmt"
+	"net"
+	"slices"
+	"time"
+)
+
 // ForwardManager はポートフォワーディングルールとセッションを管理する。
 type ForwardManager interface {
 	// AddRule はフォワーディングルールを追加し、割り当てられたルール名を返す。
@@ -18,7 +26,19 @@ type ForwardManager interface {
 	// StartForward は指定ルールのポートフォワーディングを開始する。
 	// 必要に応じて SSH 接続を確立し、リスナーを作成して accept ループを起動する。
 	// cb が非 nil の場合、SSH 接続にクレデンシャルコールバックを使用する。
-	StartForward(ruleName string, cb CredentialCallback) error
+	// ctx は SSH 接続の確立にのみ使い、開始したフォワードの寿命には影響しない。
+	StartForward(ctx context.Context, ruleName string, cb CredentialCallback) error
+
+	// SetTTL はアクティブなセッションの有効期限を現在から ttl 後に設定し直す。ttl が 0 以下の場合は期限を解除する。
+	// 期限の 5 分前に ForwardEventExpiring を発行し、期限切れでフォワードを停止する（DeleteOnExpire の場合はルールも削除する）。
+	SetTTL(ruleName string, ttl time.Duration) error
+
+	// SetLimit はルールの送信・受信の帯域上限（kbps、0 は無制限）を変更する。転送中の接続にも即座に反映する。
+	SetLimit(ruleName string, uploadKbps, downloadKbps int) error
+
+	// CheckPort はローカルポートが他のルールや他のプロセスで使用されていないかを確認する。
+	// 使用中の場合は空いているポートの候補を含む *PortConflictError を返す。
+	CheckPort(port int) error
 
 	// StopForward は指定ルールのフォワーディングセッションを停止する。
 	// アクティブでない場合はエラーなしで何もしない。
@@ -34,10 +54,33 @@ type ForwardManager interface {
 	// GetAllSessions は全ルールのセッション情報を追加順に返す。
 	GetAllSessions() []ForwardSession
 
+	// LoadStopHistory は前回のデーモンが保存した停止理由を引き継ぎ、停止中のセッション情報に反映する。
+	LoadStopHistory(records []StopRecord)
+
+	// LoadSessionRecords は前回のデーモンが保存したセッションの開始時刻と再接続回数を引き継ぎ、
+	// 次にそのルールのフォワードを開始したときに一度だけ反映する。nil を渡すと未反映の記録を破棄する。
+	LoadSessionRecords(records []SessionRecord)
+
+	// MigrateForward は指定ルールの接続先ホストを toHost に切り替える。
+	// アクティブなセッションは旧ホストで停止して新ホストで再開し、セッション ID・接続開始時刻・
+	// 転送量カウンタ・再接続回数を引き継ぐ。停止中のルールはホストの書き換えのみ行う。
+	// cb が非 nil の場合、新ホストへの SSH 接続にクレデンシャルコールバックを使用する。
+	MigrateForward(ruleName, toHost string, cb CredentialCallback) (*ForwardSession, error)
+
+	// UpdateRule は同名の既存ルールを rule の内容に置き換え、現在のセッション情報を返す。
+	// アクティブなセッションは新しい内容で再開し、MigrateForward と同様にセッション ID・転送量カウンタ等を引き継ぐ。
+	// 再開に失敗した場合はルールを元に戻し、セッションは停止する。
+	UpdateRule(rule ForwardRule, cb CredentialCallback) (*ForwardSession, error)
+
 	// MarkReconnecting は当該ホストのアクティブセッションを SessionReconnecting 状態にする。
 	MarkReconnecting(hostName string)
 
-	// RestoreForwards は SSH 再接続後に SessionReconnecting 状態の全フォワードを復元する。
+	// MarkAutoReconnecting は当該ホストのアクティブセッションのうち AutoReconnect が有効なものを
+	// SessionReconnecting 状態にする。SSH の自動再接続を伴わない切断で呼ばれる。
+	MarkAutoReconnecting(hostName string)
+
+	// RestoreForwards は SSH 接続の確立後に SessionReconnecting 状態の全フォワードと、
+	// AutoReconnect が有効で SessionError 状態のフォワードを復元する。
 	RestoreForwards(hostName string) []ForwardRestoreResult
 
 	// FailReconnecting は再接続失敗時に SessionReconnecting 状態のフォワードを Error 状態にする。
@@ -49,3 +92,72 @@ type ForwardManager interface {
 	// Close は全フォワーディングを停止し、サブスクライバーチャネルを閉じる。
 	Close()
 }
+
+// ValidateForwardRule はルールのホストとポートを検証する。
+// Local/Dynamic の LocalPort 0 は開始時に空いているポートを割り当てるため許可する。
+func ValidateForwardRule(rule ForwardRule) error {
+	if rule.Host == "" {
+		return fmt.Errorf("host is required")
+	}
+	if rule.LocalPort != 0 || rule.Type == Remote {
+		if err := ValidatePort(rule.LocalPort); err != nil {
+			return fmt.Errorf("local_port: %w", err)
+		}
+	}
+	if rule.Type == Local || rule.Type == Remote {
+		if err := ValidatePort(rule.RemotePort); err != nil {
+			return fmt.Errorf("remote_port: %w", err)
+		}
+	}
+	if err := ValidateBindAddr(rule.LocalBindAddr); err != nil {
+		return fmt.Errorf("local_bind_addr: %w", err)
+	}
+	if err := ValidateBindAddr(rule.RemoteBindAddr); err != nil {
+		return fmt.Errorf("remote_bind_addr: %w", err)
+	}
+	if rule.ACL != nil && rule.Type != Dynamic {
+		return fmt.Errorf("acl is only supported for dynamic forwards")
+	}
+	if err := rule.ACL.Validate(); err != nil {
+		return err
+	}
+	if rule.MaxUploadKbps < 0 || rule.MaxDownloadKbps < 0 {
+		return fmt.Errorf("bandwidth limit must not be negative")
+	}
+	return validateAccessLog(rule.AccessLog)
+}
+
+// ValidateBindAddr はバインドアドレスが空・IP アドレス・"localhost" のいずれかであることを検証する。
+func ValidateBindAddr(addr string) error {
+	if addr == "" || addr == "localhost" || net.ParseIP(addr) != nil {
+		return nil
+	}
+	return fmt.Errorf("invalid bind address %q: must be an IP address or localhost", addr)
+}
+
+// LocalBindHost はルールのローカル側アドレスを返す。LocalBindAddr が空の場合は LocalhostAddr を返す。
+func (r ForwardRule) LocalBindHost() string {
+	if r.LocalBindAddr == "" {
+		return LocalhostAddr
+	}
+	return r.LocalBindAddr
+}
+
+// PersistentRules は設定ファイルに保存するルールを返す。DeleteOnExpire の一時ルールとホストに定義されたルールは除く。
+func PersistentRules(rules []ForwardRule) []ForwardRule {
+	return slices.DeleteFunc(slices.Clone(rules), func(r ForwardRule) bool { return r.DeleteOnExpire || r.HostDefined })
+}
+
+// MinPort はポート番号の最小値。
+const MinPort = 1
+
+// MaxPort はポート番号の最大値。
+const MaxPort = 65535
+
+// ValidatePort はポート番号が有効範囲内かを検証する。
+func ValidatePort(port int) error {
+	if port < MinPort || port > MaxPort {
+		return fmt.Errorf("port must be between %d and %d, got %d", MinPort, MaxPort, port)
+	}
+	return nil
+}
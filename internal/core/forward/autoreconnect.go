@@ -0,0 +1,93 @@
+package forward
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/sshlink"
+)
+
+// リスナーの再作成に失敗した場合の待機時間。失敗するたびに倍増し、listenerRetryMaxDelay で頭打ちになる。
+// テストから短縮できるよう変数にしている。
+var (
+	listenerRetryDelay    = time.Second
+	listenerRetryMaxDelay = 30 * time.Second
+)
+
+// MarkAutoReconnecting は当該ホストのアクティブセッションのうち AutoReconnect が有効なものを
+// SessionReconnecting 状態にする。次に SSH 接続が確立したときに RestoreForwards で復元される。
+func (m *forwardManager) MarkAutoReconnecting(hostName string) {
+	m.markReconnecting(hostName, func(rule core.ForwardRule) bool { return rule.AutoReconnect })
+}
+
+// listenerFailed はセッションの停止以外でリスナーが閉じた場合に acceptLoop から呼ばれる。
+// AutoReconnect が有効なルールは SessionReconnecting にしてリスナーを作り直し、無効なルールは SessionError にする。
+func (m *forwardManager) listenerFailed(af *activeForward, cause error) {
+	reason := core.StopReasonError
+	if !m.sshManager.IsConnected(af.session.Rule.Host) {
+		reason = core.StopReasonSSHLost
+	}
+	m.mu.Lock()
+	// 停止・再接続待ちへの遷移でリスナーを閉じた場合は対象外
+	if current, ok := m.active[af.session.Rule.Name]; !ok || current != af || af.session.Status != core.Active {
+		m.mu.Unlock()
+		return
+	}
+	if !af.session.Rule.AutoReconnect {
+		af.cancel()
+		m.mu.Unlock()
+		m.setForwardError(af, reason, fmt.Sprintf("listener closed: %v", cause))
+		return
+	}
+	evt := m.suspendLocked(af, cause)
+	m.mu.Unlock()
+
+	m.events.Emit(evt)
+	go m.relisten(af)
+}
+
+// relisten は SSH 接続が維持されている間、バックオフしながら af のリスナーの再作成を試みる。
+// SSH 接続が切れた場合は、次に接続が確立したときの RestoreForwards に復元を委ねて終了する。
+func (m *forwardManager) relisten(af *activeForward) {
+	rule := af.session.Rule
+	delay := listenerRetryDelay
+	for {
+		select {
+		case <-m.ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		if !m.awaitingRestore(af) || !m.sshManager.IsConnected(rule.Host) {
+			return
+		}
+
+		err := m.reopenOnHost(af)
+		if err == nil || !m.awaitingRestore(af) {
+			return
+		}
+		slog.Debug("forward relisten failed", "rule", rule.Name, "retry_in", delay, "error", err)
+		m.mu.Lock()
+		af.session.LastError = err.Error()
+		m.mu.Unlock()
+		delay = min(delay*2, listenerRetryMaxDelay)
+	}
+}
+
+// reopenOnHost はルールのホストの現在の SSH 接続で af のリスナーを作り直す。
+func (m *forwardManager) reopenOnHost(af *activeForward) error {
+	sshConn, sshClient, err := sshlink.Get(m.sshManager, af.session.Rule.Host)
+	if err != nil {
+		return err
+	}
+	return m.reopen(af, sshConn, sshClient)
+}
+
+// awaitingRestore は af がまだ active マップに存在し SessionReconnecting 状態かを返す。
+func (m *forwardManager) awaitingRestore(af *activeForward) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	current, ok := m.active[af.session.Rule.Name]
+	return ok && current == af && af.session.Status == core.SessionReconnecting
+}
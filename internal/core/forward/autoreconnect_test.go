@@ -0,0 +1,83 @@
+package forward
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+)
+
+// startListenerTest は LocalForward が作成したリスナーを記録するマネージャーで "web" を開始し、
+// イベントチャネルとリスナー一覧を取得する関数を返す。
+func startListenerTest(t *testing.T, autoReconnect bool) (core.ForwardManager, <-chan core.ForwardEvent, func() []*forwardtest.MockListener) {
+	t.Helper()
+	orig := listenerRetryDelay
+	listenerRetryDelay = time.Millisecond
+	t.Cleanup(func() { listenerRetryDelay = orig })
+
+	mockConn, listeners := forwardtest.NewRecordingConn()
+	fm := newConnectedManager(mockConn)
+	t.Cleanup(fm.Close)
+	_, _ = fm.AddRule(core.ForwardRule{
+		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemotePort: 80, AutoReconnect: autoReconnect,
+	})
+	if err := fm.StartForward(t.Context(), "web", nil); err != nil {
+		t.Fatalf("StartForward: %v", err)
+	}
+	return fm, fm.Subscribe(), listeners
+}
+
+func TestListenerFailed_AutoReconnectRelistens(t *testing.T) {
+	fm, events, listeners := startListenerTest(t, true)
+	_ = listeners()[0].Close()
+
+	ev := forwardtest.DrainEvent(t, events)
+	if ev.Type != core.ForwardEventReconnecting || ev.Error == nil {
+		t.Fatalf("event = %v (error %v), want reconnecting with cause", ev.Type, ev.Error)
+	}
+	ev = forwardtest.DrainEvent(t, events)
+	if ev.Type != core.ForwardEventRestored || ev.Session.ReconnectCount != 1 {
+		t.Fatalf("event = %v (reconnect count %d), want restored with count 1", ev.Type, ev.Session.ReconnectCount)
+	}
+	if n := len(listeners()); n != 2 {
+		t.Errorf("listeners opened = %d, want 2", n)
+	}
+	forwardtest.AssertSessionStatus(t, fm, "web", core.Active)
+}
+
+func TestListenerFailed_WithoutAutoReconnectErrors(t *testing.T) {
+	fm, events, listeners := startListenerTest(t, false)
+	_ = listeners()[0].Close()
+
+	if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventError {
+		t.Fatalf("event = %v, want error", ev.Type)
+	}
+	session, _ := fm.GetSession("web")
+	if session.Status != core.SessionError || !strings.Contains(session.LastError, "listener closed") {
+		t.Errorf("session = %v %q, want error with listener closed", session.Status, session.LastError)
+	}
+}
+
+func TestRestoreForwards_AutoReconnectFromError(t *testing.T) {
+	fm, events, _ := startListenerTest(t, true)
+	_, _ = fm.AddRule(core.ForwardRule{Name: "api", Host: "server1", Type: core.Local, LocalPort: 8081, RemotePort: 81})
+	_ = fm.StartForward(t.Context(), "api", nil)
+	forwardtest.DrainEvent(t, events)
+
+	// 自動再接続の対象は auto_reconnect のルールのみ
+	fm.MarkAutoReconnecting("server1")
+	if ev := forwardtest.DrainEvent(t, events); ev.RuleName != "web" {
+		t.Fatalf("reconnecting rule = %q, want web", ev.RuleName)
+	}
+	forwardtest.AssertSessionStatus(t, fm, "api", core.Active)
+
+	fm.FailReconnecting("server1")
+	forwardtest.DrainEvent(t, events)
+	results := fm.RestoreForwards("server1")
+	if len(results) != 1 || !results[0].OK || results[0].RuleName != "web" {
+		t.Fatalf("results = %+v, want web restored", results)
+	}
+	forwardtest.AssertSessionStatus(t, fm, "web", core.Active)
+}
@@ -2,160 +2,78 @@ package forward
 
 import (
 	"errors"
-	"fmt"
-	"io"
 	"log/slog"
 	"net"
-	"sync"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/core/socks5"
+	"github.com/ousiassllc/moleport/internal/core/forward/relay"
 )
 
-// halfCloser は TCP half-close をサポートする接続を表す。
-// net.TCPConn はこのインターフェースを満たすが、SSH チャネル経由の接続は
-// 満たさない場合がある。
-type halfCloser interface {
-	CloseWrite() error
-}
-
-// bufPool は io.CopyBuffer で使用するバッファの再利用プール。
-// バッファサイズは io.Copy のデフォルト (32KB) と同じ。
-var bufPool = sync.Pool{
-	New: func() any {
-		buf := make([]byte, 32*1024)
-		return &buf
-	},
-}
+// bridgeErrorInterval は同じブリッジエラーを ForwardEventError として再通知するまでの最小間隔。
+const bridgeErrorInterval = 30 * time.Second
 
 // acceptLoop はリスナーで接続を受け付け、ブリッジを作成する。
-func (m *forwardManager) acceptLoop(af *activeForward, rule core.ForwardRule, sshClient interface {
-	Dial(n, addr string) (net.Conn, error)
-}) {
-	for {
-		conn, err := af.listener.Accept()
-		if err != nil {
-			select {
-			case <-af.ctx.Done():
-				return
-			default:
-				slog.Warn("accept error", "rule", rule.Name, "error", err)
-				return
-			}
-		}
-
-		go m.bridge(af, rule, conn, sshClient)
-	}
-}
-
-// dialRemote はルールの種類に応じてリモート接続を確立する。
-func (m *forwardManager) dialRemote(rule core.ForwardRule, sshClient interface {
-	Dial(n, addr string) (net.Conn, error)
-}) (net.Conn, error) {
-	switch rule.Type {
-	case core.Local:
-		remoteAddr := fmt.Sprintf("%s:%d", rule.RemoteHost, rule.RemotePort)
-		return sshClient.Dial("tcp", remoteAddr)
-	case core.Remote:
-		localAddr := net.JoinHostPort(core.LocalhostAddr, fmt.Sprintf("%d", rule.LocalPort))
-		return net.Dial("tcp", localAddr)
-	default:
-		return nil, fmt.Errorf("unsupported forward type for bridge: %v", rule.Type)
+// セッションの停止以外でリスナーが閉じた場合は listenerFailed で再接続またはエラーに遷移させる。
+func (m *forwardManager) acceptLoop(af *activeForward, rule core.ForwardRule, sshClient relay.Dialer) {
+	err := relay.Serve(af.ctx, af.listener, func(conn net.Conn) { m.bridge(af, rule, conn, sshClient) })
+	if err != nil {
+		slog.Warn("accept error", "rule", rule.Name, "error", err)
+		m.listenerFailed(af, err)
 	}
 }
 
 // bridge は受け付けた接続とリモート/ローカルの間でデータを転送する。
-func (m *forwardManager) bridge(af *activeForward, rule core.ForwardRule, conn net.Conn, sshClient interface {
-	Dial(n, addr string) (net.Conn, error)
-}) {
+// 転送中の接続は af.inflight で追跡し、停止時の完了待ちと強制切断の対象にする。
+// ルールに AccessLog が設定されている場合は、接続の終了時にアクセスログを記録する。
+func (m *forwardManager) bridge(af *activeForward, rule core.ForwardRule, conn net.Conn, sshClient relay.Dialer) {
 	defer func() { _ = conn.Close() }()
+	tracked := af.inflight.Track(conn)
+	defer tracked.Done()
 
-	if rule.Type == core.Dynamic {
-		m.handleSOCKS5(af, conn, sshClient)
-		return
+	start := time.Now()
+	t, err := relay.Forward(rule, conn, sshClient, &af.sent, &af.received, m.limits.Get(rule.Name), tracked.Attach)
+	if m.access != nil && rule.AccessLog != "" {
+		m.access.LogAccess(rule, t.Record(rule, conn.RemoteAddr(), start, err))
 	}
-
-	remote, err := m.dialRemote(rule, sshClient)
-	if err != nil {
-		slog.Warn("bridge dial failed", "rule", rule.Name, "error", err)
-		return
+	if errors.Is(err, relay.ErrDenied) {
+		m.socksDenied(af, err)
+	} else if err != nil {
+		m.bridgeFailed(af, err)
 	}
-	defer func() { _ = remote.Close() }()
-
-	m.copyBidirectional(af, conn, remote)
 }
 
-// handleSOCKS5 は最小限の SOCKS5 プロトコルを処理する（認証なし、CONNECT のみ）。
-func (m *forwardManager) handleSOCKS5(af *activeForward, conn net.Conn, sshClient interface {
-	Dial(n, addr string) (net.Conn, error)
-}) {
-	if err := socks5.Negotiate(conn); err != nil {
-		slog.Debug("socks5 negotiate failed", "rule", af.session.Rule.Name, "error", err)
-		return
-	}
-
-	targetAddr, err := socks5.ParseRequest(conn)
-	if err != nil {
-		slog.Debug("socks5 parse request failed", "rule", af.session.Rule.Name, "error", err)
-		return
-	}
+// socksDenied は SOCKS5 の ACL で拒否した接続をログに記録し、ForwardEventDenied を発行する。
+func (m *forwardManager) socksDenied(af *activeForward, err error) {
+	m.mu.RLock()
+	session := af.session
+	m.mu.RUnlock()
+	slog.Info("socks5 destination denied", "rule", session.Rule.Name, "error", err)
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventDenied, RuleName: session.Rule.Name, Session: &session, Error: err})
+}
 
-	remote, err := sshClient.Dial("tcp", targetAddr)
-	if err != nil {
-		// Connection refused
-		_, _ = conn.Write([]byte{socks5.Version, socks5.ReplyConnectionRefused, 0x00, socks5.AddrIPv4, 0, 0, 0, 0, 0, 0})
+// bridgeFailed は転送先への接続の失敗をセッションの LastError に記録し、ForwardEventError を発行する。
+// 失敗は接続単位のためセッションは Active のまま維持する。接続のたびに通知が溢れないよう、
+// 同じエラーの再通知は bridgeErrorInterval に 1 回までに抑える。
+func (m *forwardManager) bridgeFailed(af *activeForward, err error) {
+	rule := af.session.Rule
+	slog.Warn("bridge dial failed", "rule", rule.Name, "error", err)
+	msg := "dial failed: " + err.Error()
+
+	m.mu.Lock()
+	if current, ok := m.active[rule.Name]; !ok || current != af {
+		m.mu.Unlock()
 		return
 	}
-	defer func() { _ = remote.Close() }()
-
-	// Success response
-	if _, err := conn.Write([]byte{socks5.Version, socks5.ReplySuccess, 0x00, socks5.AddrIPv4, 0, 0, 0, 0, 0, 0}); err != nil {
+	now := time.Now()
+	if af.session.LastError == msg && now.Sub(af.lastErrorAt) < bridgeErrorInterval {
+		m.mu.Unlock()
 		return
 	}
+	af.session.LastError = msg
+	af.lastErrorAt = now
+	session := af.session
+	m.mu.Unlock()
 
-	m.copyBidirectional(af, conn, remote)
-}
-
-// copyBidirectional は二つの接続間でデータを双方向にコピーする。
-// コピー完了後、half-close (CloseWrite) で EOF を相手側に伝播する。
-func (m *forwardManager) copyBidirectional(af *activeForward, a, b net.Conn) {
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go func() {
-		defer wg.Done()
-		bufp := bufPool.Get().(*[]byte) // safe: Pool.New always returns *[]byte
-		defer bufPool.Put(bufp)
-		n, err := io.CopyBuffer(b, a, *bufp)
-		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
-			slog.Debug("copy error", "rule", af.session.Rule.Name, "error", err)
-		}
-		af.sent.Add(n)
-		closeWrite(b)
-	}()
-
-	go func() {
-		defer wg.Done()
-		bufp := bufPool.Get().(*[]byte) // safe: Pool.New always returns *[]byte
-		defer bufPool.Put(bufp)
-		n, err := io.CopyBuffer(a, b, *bufp)
-		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
-			slog.Debug("copy error", "rule", af.session.Rule.Name, "error", err)
-		}
-		af.received.Add(n)
-		closeWrite(a)
-	}()
-
-	wg.Wait()
-}
-
-// closeWrite は接続の書き込み側を閉じる。
-// halfCloser をサポートする場合は CloseWrite で half-close を行い、
-// サポートしない場合は Close でフォールバックする。
-func closeWrite(c net.Conn) {
-	if hc, ok := c.(halfCloser); ok {
-		_ = hc.CloseWrite()
-	} else {
-		_ = c.Close()
-	}
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventError, RuleName: rule.Name, Session: &session, Error: errors.New(msg)})
 }
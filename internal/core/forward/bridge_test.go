@@ -1,230 +1,80 @@
 package forward
 
 import (
-	"bytes"
-	"context"
 	"io"
 	"net"
-	"sync"
-	"sync/atomic"
+	"strings"
 	"testing"
-	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+	"github.com/ousiassllc/moleport/internal/core/socksacl"
 )
 
-type halfCloseConn struct {
-	net.Conn
-	closeWriteCalled atomic.Bool
-}
-
-func (c *halfCloseConn) CloseWrite() error { c.closeWriteCalled.Store(true); return nil }
-
-type plainConn struct {
-	net.Conn
-	mu          sync.Mutex
-	closeCalled int
-}
-
-func (c *plainConn) Close() error {
-	c.mu.Lock()
-	c.closeCalled++
-	c.mu.Unlock()
-	return c.Conn.Close()
-}
-
-func newSOCKS5TestPair(t *testing.T) (client, server net.Conn, fm *forwardManager) {
-	t.Helper()
-	c, s := net.Pipe()
-	t.Cleanup(func() { _ = c.Close(); _ = s.Close() })
-	return c, s, NewForwardManager(context.Background(), forwardtest.NewMockSSHManager()).(*forwardManager)
-}
-
-func newTestDialer(ch chan<- string) *forwardtest.MockSOCKS5Dialer {
-	return &forwardtest.MockSOCKS5Dialer{DialF: func(_, addr string) (net.Conn, error) {
-		ch <- addr
-		rc, _ := net.Pipe()
-		return rc, nil
-	}}
-}
-
-func runCopyBidirectional(t *testing.T, a, b net.Conn) <-chan struct{} {
-	t.Helper()
-	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager()).(*forwardManager)
-	af := &activeForward{session: core.ForwardSession{Rule: core.ForwardRule{Name: t.Name()}}}
-	done := make(chan struct{})
-	go func() { defer close(done); fm.copyBidirectional(af, a, b) }()
-	return done
-}
-
-func doSOCKS5Connect(t *testing.T, request []byte) string {
-	t.Helper()
-	clientConn, serverConn, fm := newSOCKS5TestPair(t)
-	dialedAddr := make(chan string, 1)
-	go fm.handleSOCKS5(&activeForward{}, serverConn, newTestDialer(dialedAddr))
-
-	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x00})
-	resp := make([]byte, 2)
-	if _, err := io.ReadFull(clientConn, resp); err != nil {
-		t.Fatalf("read greeting response: %v", err)
-	}
-	if !bytes.Equal(resp, []byte{0x05, 0x00}) {
-		t.Fatalf("unexpected greeting response: %v", resp)
+func TestBridge_DialFailureRecordsError(t *testing.T) {
+	fm := newConnectedManager(forwardtest.NewMockConn(true, false))
+	defer fm.Close()
+	access := &forwardtest.AccessRecorder{}
+	fm.access = access
+	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "db", RemotePort: 80, AccessLog: core.AccessLogFile})
+	if err := fm.StartForward(t.Context(), "web", nil); err != nil {
+		t.Fatalf("StartForward: %v", err)
 	}
-	_, _ = clientConn.Write(request)
-	successResp := make([]byte, 10)
-	if _, err := io.ReadFull(clientConn, successResp); err != nil {
-		t.Fatalf("read success response: %v", err)
-	}
-	if successResp[0] != 0x05 || successResp[1] != 0x00 {
-		t.Fatalf("unexpected success response: %v", successResp)
-	}
-	select {
-	case addr := <-dialedAddr:
-		return addr
-	case <-time.After(time.Second):
-		t.Fatal("timeout waiting for dial")
-		return ""
-	}
-}
-
-func TestHandleSOCKS5_ConnectVariants(t *testing.T) {
-	domainReq := []byte{0x05, 0x01, 0x00, 0x03, byte(len("example.com"))} //nolint:gosec // domain length is always < 256
-	domainReq = append(domainReq, []byte("example.com")...)
-	domainReq = append(domainReq, 0x00, 0x50)
-	ipv4Req := []byte{0x05, 0x01, 0x00, 0x01, 192, 168, 1, 1, 0x1F, 0x90}
-	ipv6Req := []byte{0x05, 0x01, 0x00, 0x04}
-	ipv6Req = append(ipv6Req, net.ParseIP("::1").To16()...)
-	ipv6Req = append(ipv6Req, 0x01, 0xBB)
+	events := fm.Subscribe()
+	fm.mu.RLock()
+	af := fm.active["web"]
+	fm.mu.RUnlock()
 
-	tests := []struct {
-		name    string
-		request []byte
-		want    string
-	}{
-		{"Domain", domainReq, "example.com:80"},
-		{"IPv4", ipv4Req, "192.168.1.1:8080"},
-		{"IPv6", ipv6Req, net.JoinHostPort("::1", "443")},
+	// 同じエラーが続いても通知は 1 回に抑える
+	for range 2 {
+		client, server := net.Pipe()
+		_ = client.Close()
+		fm.bridge(af, af.session.Rule, server, &forwardtest.MockSOCKS5Dialer{})
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if got := doSOCKS5Connect(t, tt.request); got != tt.want {
-				t.Errorf("dialed addr = %q, want %q", got, tt.want)
-			}
-		})
-	}
-}
 
-func TestHandleSOCKS5_NoAuthMethodRejected(t *testing.T) {
-	clientConn, serverConn, fm := newSOCKS5TestPair(t)
-	done := make(chan struct{})
-	go func() {
-		defer close(done)
-		fm.handleSOCKS5(&activeForward{}, serverConn, &forwardtest.MockSOCKS5Dialer{})
-	}()
-	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x02}) // username/password only
-	resp := make([]byte, 2)
-	if _, err := io.ReadFull(clientConn, resp); err != nil {
-		t.Fatalf("read response: %v", err)
-	}
-	if !bytes.Equal(resp, []byte{0x05, 0xFF}) {
-		t.Errorf("expected no acceptable methods (0xFF), got %v", resp)
-	}
-	select {
-	case <-done:
-	case <-time.After(time.Second):
-		t.Fatal("handleSOCKS5 did not return after rejection")
-	}
-}
-
-func TestHandleSOCKS5_FragmentedWrites(t *testing.T) {
-	clientConn, serverConn, fm := newSOCKS5TestPair(t)
-	dialedAddr := make(chan string, 1)
-	af := &activeForward{session: core.ForwardSession{Rule: core.ForwardRule{Name: "test"}}}
-	go fm.handleSOCKS5(af, serverConn, newTestDialer(dialedAddr))
-
-	for _, b := range []byte{0x05, 0x01, 0x00} {
-		_, _ = clientConn.Write([]byte{b})
-		time.Sleep(time.Millisecond)
-	}
-	resp := make([]byte, 2)
-	_, _ = io.ReadFull(clientConn, resp)
-
-	domain := "a.b"
-	req := []byte{0x05, 0x01, 0x00, 0x03, byte(len(domain))} //nolint:gosec // domain length is always < 256
-	req = append(req, []byte(domain)...)
-	req = append(req, 0x00, 0x50)
-	for _, b := range req {
-		_, _ = clientConn.Write([]byte{b})
-		time.Sleep(time.Millisecond)
+	ev := forwardtest.DrainEvent(t, events)
+	if ev.Type != core.ForwardEventError || ev.Error == nil || !strings.Contains(ev.Error.Error(), "not implemented") {
+		t.Fatalf("event = %v (error %v), want error with dial cause", ev.Type, ev.Error)
 	}
-	successResp := make([]byte, 10)
-	_, _ = io.ReadFull(clientConn, successResp)
-
 	select {
-	case addr := <-dialedAddr:
-		if addr != "a.b:80" {
-			t.Errorf("dialed addr = %q, want %q", addr, "a.b:80")
-		}
-	case <-time.After(2 * time.Second):
-		t.Fatal("timeout waiting for dial")
-	}
-}
-
-func TestCopyBidirectional_HalfClose(t *testing.T) {
-	aClient, aServer := net.Pipe()
-	bClient, bServer := net.Pipe()
-	t.Cleanup(func() { _ = aClient.Close(); _ = bClient.Close() })
-	hcA := &halfCloseConn{Conn: aServer}
-	hcB := &halfCloseConn{Conn: bServer}
-	done := runCopyBidirectional(t, hcA, hcB)
-
-	_, _ = aClient.Write([]byte("hello"))
-	_ = aClient.Close()
-	buf := make([]byte, 5)
-	if _, err := io.ReadFull(bClient, buf); err != nil {
-		t.Fatalf("read: %v", err)
+	case ev := <-events:
+		t.Errorf("repeated error should be throttled, got %v", ev.Type)
+	default:
 	}
-	_ = bClient.Close()
-	select {
-	case <-done:
-	case <-time.After(2 * time.Second):
-		t.Fatal("timeout")
+	session, _ := fm.GetSession("web")
+	if session.Status != core.Active || !strings.HasPrefix(session.LastError, "dial failed") {
+		t.Errorf("session = %v %q, want active with dial error", session.Status, session.LastError)
 	}
-	if !hcB.closeWriteCalled.Load() {
-		t.Error("CloseWrite not called on b")
-	}
-	if !hcA.closeWriteCalled.Load() {
-		t.Error("CloseWrite not called on a")
+	// 失敗した接続もアクセスログに記録する
+	if len(access.Records) != 2 || access.Records[0].Target != "db:80" || access.Records[0].Client == "" || !strings.Contains(access.Records[0].Error, "not implemented") {
+		t.Errorf("records = %+v, want 2 records to db:80 with dial error", access.Records)
 	}
 }
 
-func TestCopyBidirectional_FallbackClose(t *testing.T) {
-	aClient, aServer := net.Pipe()
-	bClient, bServer := net.Pipe()
-	t.Cleanup(func() { _ = aClient.Close(); _ = bClient.Close() })
-	pcA := &plainConn{Conn: aServer}
-	pcB := &plainConn{Conn: bServer}
-	done := runCopyBidirectional(t, pcA, pcB)
+func TestBridge_SOCKSDeniedEmitsEvent(t *testing.T) {
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
+	defer fm.Close()
+	access := &forwardtest.AccessRecorder{}
+	fm.access = access
+	_, _ = fm.AddRule(core.ForwardRule{Name: "socks", Host: "server1", Type: core.Dynamic, LocalPort: 1080, ACL: &socksacl.ACL{Deny: []string{"*.internal"}}, AccessLog: core.AccessLogSlog})
+	_ = fm.StartForward(t.Context(), "socks", nil)
+	events := fm.Subscribe()
+	af := fm.active["socks"]
+
+	client, server := net.Pipe()
+	go func() {
+		_, _ = client.Write([]byte{0x05, 0x01, 0x00})
+		_, _ = io.ReadFull(client, make([]byte, 2))
+		_, _ = client.Write(append([]byte{0x05, 0x01, 0x00, 0x03, 11}, append([]byte("db.internal"), 0x00, 0x50)...))
+		_, _ = io.ReadFull(client, make([]byte, 10))
+		_ = client.Close()
+	}()
+	fm.bridge(af, af.session.Rule, server, &forwardtest.MockSOCKS5Dialer{})
 
-	_ = aClient.Close()
-	_ = bClient.Close()
-	select {
-	case <-done:
-	case <-time.After(2 * time.Second):
-		t.Fatal("timeout")
-	}
-	pcA.mu.Lock()
-	ac := pcA.closeCalled
-	pcA.mu.Unlock()
-	pcB.mu.Lock()
-	bc := pcB.closeCalled
-	pcB.mu.Unlock()
-	if ac < 1 {
-		t.Error("Close not called on a")
+	if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventDenied || ev.Session == nil || ev.Session.Rule.Host != "server1" {
+		t.Errorf("event = %+v, want Denied for socks on server1", ev)
 	}
-	if bc < 1 {
-		t.Error("Close not called on b")
+	if len(access.Records) != 1 || access.Records[0].Target != "db.internal:80" || !strings.Contains(access.Records[0].Error, "denied") {
+		t.Errorf("records = %+v, want denied db.internal:80", access.Records)
 	}
 }
@@ -0,0 +1,3 @@
+// Package dockertarget は Local ルールの転送先 "docker:<コンテナ名>" を、SSH 接続先の Docker ソケットに問い合わせて
+// コンテナの公開ポートまたは IP アドレスに解決する。
+package dockertarget
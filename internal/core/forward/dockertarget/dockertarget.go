@@ -0,0 +1,164 @@
+package dockertarget
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/relay"
+)
+
+const (
+	// Prefix は RemoteHost でコンテナ名を指定する接頭辞。
+	Prefix = "docker:"
+	// SocketPath は SSH 接続先の Docker Engine API のソケット。
+	SocketPath = "/var/run/docker.sock"
+	// requestTimeout は Docker Engine API への問い合わせのタイムアウト。
+	requestTimeout = 10 * time.Second
+)
+
+// Container は rule の転送先がコンテナの場合にコンテナ名を返す。
+func Container(rule core.ForwardRule) (string, bool) {
+	if rule.Type != core.Local {
+		return "", false
+	}
+	name, ok := strings.CutPrefix(rule.RemoteHost, Prefix)
+	return name, ok && name != ""
+}
+
+// Dialer は "docker:<コンテナ名>:<ポート>" への接続を、解決したアドレスへの接続に置き換える relay.Dialer。
+// 解決結果は Dialer ごとに保持するため、SSH 接続やリスナーを作り直すたびに新しい Dialer で解決し直す。
+type Dialer struct {
+	relay.Dialer
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// Wrap は d を転送先のコンテナを解決する Dialer で包む。
+func Wrap(d relay.Dialer) *Dialer {
+	return &Dialer{Dialer: d, cache: make(map[string]string)}
+}
+
+// Dial は addr がコンテナの場合は解決したアドレスに、それ以外は addr にそのまま接続する。
+func (d *Dialer) Dial(n, addr string) (net.Conn, error) {
+	rest, ok := strings.CutPrefix(addr, Prefix)
+	if !ok {
+		return d.Dialer.Dial(n, addr)
+	}
+	name, portStr, err := net.SplitHostPort(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker target %q: %w", addr, err)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid docker target %q: %w", addr, err)
+	}
+	resolved, err := d.Resolve(name, port)
+	if err != nil {
+		return nil, err
+	}
+	return d.Dialer.Dial(n, resolved)
+}
+
+// Resolve はコンテナ name の port への接続先アドレスを返す。解決済みの場合は保持している結果を返す。
+// port が公開されている場合は接続先ホストの公開ポート、それ以外はコンテナの IP アドレスの port を使う。
+func (d *Dialer) Resolve(name string, port int) (string, error) {
+	key := fmt.Sprintf("%s:%d", name, port)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if addr, ok := d.cache[key]; ok {
+		return addr, nil
+	}
+	info, err := d.inspect(name)
+	if err != nil {
+		return "", fmt.Errorf("docker container %s: %w", name, err)
+	}
+	addr, err := info.target(port)
+	if err != nil {
+		return "", fmt.Errorf("docker container %s: %w", name, err)
+	}
+	d.cache[key] = addr
+	return addr, nil
+}
+
+// inspect は Docker Engine API でコンテナの情報を取得する。
+func (d *Dialer) inspect(name string) (*container, error) {
+	client := &http.Client{
+		Timeout: requestTimeout,
+		Transport: &http.Transport{
+			DialContext:       func(context.Context, string, string) (net.Conn, error) { return d.Dialer.Dial("unix", SocketPath) },
+			DisableKeepAlives: true,
+		},
+	}
+	resp, err := client.Get("http://docker/containers/" + url.PathEscape(name) + "/json")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.New("not found")
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	var info container
+	if err := json.NewDecoder(resp.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// container は Docker Engine API の GET /containers/{id}/json の応答のうち、接続先の解決に使う項目。
+type container struct {
+	State struct {
+		Running bool
+	}
+	NetworkSettings struct {
+		Ports map[string][]struct {
+			HostIP   string `json:"HostIp"`
+			HostPort string
+		}
+		Networks map[string]struct {
+			IPAddress string
+		}
+	}
+}
+
+// target はコンテナの port への接続先アドレスを返す。
+func (c *container) target(port int) (string, error) {
+	if !c.State.Running {
+		return "", errors.New("not running")
+	}
+	for _, b := range c.NetworkSettings.Ports[fmt.Sprintf("%d/tcp", port)] {
+		if b.HostPort == "" {
+			continue
+		}
+		host := b.HostIP
+		if ip := net.ParseIP(host); ip == nil || ip.IsUnspecified() {
+			host = core.LocalhostAddr
+		}
+		return net.JoinHostPort(host, b.HostPort), nil
+	}
+	names := make([]string, 0, len(c.NetworkSettings.Networks))
+	for name := range c.NetworkSettings.Networks {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if ip := c.NetworkSettings.Networks[name].IPAddress; ip != "" {
+			return net.JoinHostPort(ip, strconv.Itoa(port)), nil
+		}
+	}
+	return "", fmt.Errorf("no published port or IP address for port %d", port)
+}
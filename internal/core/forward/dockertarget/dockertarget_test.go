@@ -0,0 +1,112 @@
+package dockertarget
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// fakeDialer は Docker ソケットへの接続を srv に向け、それ以外の接続先を記録する relay.Dialer。
+type fakeDialer struct {
+	srv    *httptest.Server
+	dialed []string
+}
+
+func (f *fakeDialer) Dial(n, addr string) (net.Conn, error) {
+	if n == "unix" && addr == SocketPath {
+		return net.Dial("tcp", f.srv.Listener.Addr().String())
+	}
+	f.dialed = append(f.dialed, addr)
+	return nil, errors.New("not connected")
+}
+
+const inspectJSON = `{
+	"State": {"Running": true},
+	"NetworkSettings": {
+		"Ports": {"5432/tcp": [{"HostIp": "0.0.0.0", "HostPort": "49153"}], "6379/tcp": null},
+		"Networks": {"bridge": {"IPAddress": "172.17.0.2"}}
+	}
+}`
+
+func newFakeDocker(t *testing.T) (*fakeDialer, *atomic.Int32) {
+	t.Helper()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		switch r.URL.Path {
+		case "/containers/pg/json":
+			_, _ = w.Write([]byte(inspectJSON))
+		case "/containers/stopped/json":
+			_, _ = w.Write([]byte(`{"State": {"Running": false}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return &fakeDialer{srv: srv}, &calls
+}
+
+func TestContainer(t *testing.T) {
+	if name, ok := Container(core.ForwardRule{Type: core.Local, RemoteHost: "docker:pg"}); !ok || name != "pg" {
+		t.Errorf("Container(docker:pg) = %q, %v", name, ok)
+	}
+	for _, rule := range []core.ForwardRule{
+		{Type: core.Local, RemoteHost: "localhost"},
+		{Type: core.Local, RemoteHost: "docker:"},
+		{Type: core.Remote, RemoteHost: "docker:pg"},
+	} {
+		if _, ok := Container(rule); ok {
+			t.Errorf("Container(%+v) = true, want false", rule)
+		}
+	}
+}
+
+func TestDialer_Resolve(t *testing.T) {
+	fake, calls := newFakeDocker(t)
+	d := Wrap(fake)
+
+	tests := []struct {
+		name    string
+		port    int
+		want    string
+		wantErr string
+	}{
+		{"pg", 5432, "127.0.0.1:49153", ""},
+		{"pg", 6379, "172.17.0.2:6379", ""},
+		{"stopped", 5432, "", "not running"},
+		{"missing", 5432, "", "not found"},
+	}
+	for _, tt := range tests {
+		got, err := d.Resolve(tt.name, tt.port)
+		if got != tt.want || (err == nil) != (tt.wantErr == "") || (err != nil && !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("Resolve(%s, %d) = %q, %v; want %q, %q", tt.name, tt.port, got, err, tt.want, tt.wantErr)
+		}
+	}
+
+	// 解決済みの転送先は問い合わせ直さない
+	before := calls.Load()
+	if _, err := d.Resolve("pg", 5432); err != nil || calls.Load() != before {
+		t.Errorf("cached Resolve: err = %v, calls %d -> %d", err, before, calls.Load())
+	}
+}
+
+func TestDialer_Dial(t *testing.T) {
+	fake, _ := newFakeDocker(t)
+	d := Wrap(fake)
+
+	_, _ = d.Dial("tcp", "docker:pg:5432")
+	_, _ = d.Dial("tcp", "localhost:80")
+	if want := []string{"127.0.0.1:49153", "localhost:80"}; !slices.Equal(fake.dialed, want) {
+		t.Errorf("dialed = %v, want %v", fake.dialed, want)
+	}
+	if _, err := d.Dial("tcp", "docker:missing:5432"); err == nil || !strings.Contains(err.Error(), "docker container missing") {
+		t.Errorf("Dial(missing) error = %v", err)
+	}
+}
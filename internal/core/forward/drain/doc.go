@@ -0,0 +1,2 @@
+// Package drain はフォワードの転送中の接続を追跡し、停止時の完了待ちと強制切断を行う。
+package drain
@@ -0,0 +1,125 @@
+package drain
+
+import (
+	"io"
+	"net"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// Tracker は転送中の接続を追跡する。ゼロ値で使用できる。
+type Tracker struct {
+	mu     sync.Mutex
+	conns  map[*Conn]struct{}
+	idle   chan struct{} // Wait 中に追跡中の接続がなくなった時点で閉じる
+	closed bool
+}
+
+// Conn は追跡中の 1 つの転送。受け付けた接続と転送先への接続をまとめて閉じられるよう保持する。
+type Conn struct {
+	t       *Tracker
+	closers []io.Closer
+	info    core.ConnInfo
+}
+
+// Track は受け付けた接続 c の追跡を開始する。転送を終えたら Done を呼ぶこと。
+// CloseAll の後に追跡を開始した接続は直ちに閉じる。
+func (t *Tracker) Track(c io.Closer) *Conn {
+	conn := &Conn{t: t, closers: []io.Closer{c}, info: core.ConnInfo{Since: time.Now()}}
+	if nc, ok := c.(interface{ RemoteAddr() net.Addr }); ok && nc.RemoteAddr() != nil {
+		conn.info.Peer = nc.RemoteAddr().String()
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		_ = c.Close()
+	}
+	if t.conns == nil {
+		t.conns = make(map[*Conn]struct{})
+	}
+	t.conns[conn] = struct{}{}
+	return conn
+}
+
+// Attach は強制切断の際に一緒に閉じる接続（転送先への接続等）を追加する。
+func (c *Conn) Attach(closer io.Closer) {
+	c.t.mu.Lock()
+	defer c.t.mu.Unlock()
+	if c.t.closed {
+		_ = closer.Close()
+	}
+	c.closers = append(c.closers, closer)
+}
+
+// Done は転送の完了を記録し、追跡を終える。
+func (c *Conn) Done() {
+	t := c.t
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.conns, c)
+	if len(t.conns) == 0 && t.idle != nil {
+		close(t.idle)
+		t.idle = nil
+	}
+}
+
+// Len は追跡中の接続数を返す。
+func (t *Tracker) Len() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.conns)
+}
+
+// Conns は追跡中の接続の接続元と開始時刻を開始順に返す。
+func (t *Tracker) Conns() []core.ConnInfo {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if len(t.conns) == 0 {
+		return nil
+	}
+	out := make([]core.ConnInfo, 0, len(t.conns))
+	for c := range t.conns {
+		out = append(out, c.info)
+	}
+	slices.SortFunc(out, func(a, b core.ConnInfo) int { return a.Since.Compare(b.Since) })
+	return out
+}
+
+// Wait は追跡中の接続がすべて完了するか timeout が経過するまで待つ。すべて完了した場合は true を返す。
+func (t *Tracker) Wait(timeout time.Duration) bool {
+	t.mu.Lock()
+	if len(t.conns) == 0 {
+		t.mu.Unlock()
+		return true
+	}
+	if t.idle == nil {
+		t.idle = make(chan struct{})
+	}
+	idle := t.idle
+	t.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return true
+	case <-timer.C:
+		return false
+	}
+}
+
+// CloseAll は追跡中の接続をすべて閉じ、以降に追跡を開始する接続も直ちに閉じるようにする。
+// 閉じた接続の追跡は、各転送が Done を呼んだ時点で終わる。
+func (t *Tracker) CloseAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for c := range t.conns {
+		for _, closer := range c.closers {
+			_ = closer.Close()
+		}
+	}
+}
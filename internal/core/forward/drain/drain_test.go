@@ -0,0 +1,91 @@
+package drain
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// closer は Close の呼び出し回数を数える io.Closer。
+type closer struct{ n atomic.Int32 }
+
+func (c *closer) Close() error {
+	c.n.Add(1)
+	return nil
+}
+
+func TestTracker_WaitForDone(t *testing.T) {
+	var tr Tracker
+	if !tr.Wait(time.Millisecond) {
+		t.Fatal("Wait() with no connections = false, want true")
+	}
+
+	conn := tr.Track(&closer{})
+	if tr.Len() != 1 {
+		t.Fatalf("Len() = %d, want 1", tr.Len())
+	}
+	if tr.Wait(20 * time.Millisecond) {
+		t.Fatal("Wait() = true while a connection is in flight")
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		conn.Done()
+	}()
+	if !tr.Wait(time.Second) {
+		t.Fatal("Wait() = false, want true after Done")
+	}
+	if tr.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", tr.Len())
+	}
+}
+
+func TestTracker_CloseAll(t *testing.T) {
+	var tr Tracker
+	client, remote := &closer{}, &closer{}
+	conn := tr.Track(client)
+	conn.Attach(remote)
+
+	tr.CloseAll()
+	if client.n.Load() != 1 || remote.n.Load() != 1 {
+		t.Errorf("Close calls = %d/%d, want 1/1", client.n.Load(), remote.n.Load())
+	}
+	// 閉じた後に受け付けた接続や転送先への接続は直ちに閉じる
+	late := &closer{}
+	conn.Attach(late)
+	tr.Track(late)
+	if late.n.Load() != 2 {
+		t.Errorf("late Close calls = %d, want 2", late.n.Load())
+	}
+}
+
+func TestTracker_Conns(t *testing.T) {
+	var tr Tracker
+	if got := tr.Conns(); got != nil {
+		t.Fatalf("Conns() with no connections = %v, want nil", got)
+	}
+
+	server, client := net.Pipe()
+	defer func() { _ = server.Close(); _ = client.Close() }()
+	first := tr.Track(server)
+	time.Sleep(time.Millisecond)
+	second := tr.Track(&closer{})
+
+	conns := tr.Conns()
+	if len(conns) != 2 {
+		t.Fatalf("len(Conns()) = %d, want 2", len(conns))
+	}
+	if conns[0].Peer != "pipe" || conns[1].Peer != "" {
+		t.Errorf("Peers = %q/%q, want %q/%q", conns[0].Peer, conns[1].Peer, "pipe", "")
+	}
+	if !conns[0].Since.Before(conns[1].Since) {
+		t.Errorf("Conns() not ordered by start time: %v", conns)
+	}
+
+	first.Done()
+	second.Done()
+	if got := tr.Conns(); got != nil {
+		t.Errorf("Conns() after Done = %v, want nil", got)
+	}
+}
@@ -2,62 +2,58 @@ package forward
 
 import (
 	"context"
+	"io"
 	"testing"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/core/forwardtest"
 )
 
-func TestForwardManager_GetSession_NotFound(t *testing.T) {
-	_, err := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager()).GetSession("nonexistent")
-	if err == nil {
+func TestForwardManager_GetSession_Inactive(t *testing.T) {
+	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil)
+	if _, err := fm.GetSession("nonexistent"); err == nil {
 		t.Fatal("GetSession() should return error for nonexistent rule")
 	}
-}
-
-func TestForwardManager_GetSession_Inactive(t *testing.T) {
-	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager())
 	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
 	session, err := fm.GetSession("web")
-	if err != nil {
-		t.Fatalf("GetSession() error = %v", err)
-	}
-	if session.Status != core.Stopped {
-		t.Errorf("session status = %v, want %v", session.Status, core.Stopped)
-	}
-	if session.Rule.Name != "web" {
-		t.Errorf("session rule name = %q, want %q", session.Rule.Name, "web")
+	if err != nil || session.Status != core.Stopped || session.Rule.Name != "web" {
+		t.Fatalf("GetSession() = %+v, %v; want stopped session of web", session, err)
 	}
 }
 
 func TestForwardManager_GetAllSessions(t *testing.T) {
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", forwardtest.NewMockConn(false, true))
-	fm := NewForwardManager(context.Background(), sm)
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
 	_, _ = fm.AddRule(core.ForwardRule{Name: "fwd1", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
 	_, _ = fm.AddRule(core.ForwardRule{Name: "fwd2", Host: "server1", Type: core.Dynamic, LocalPort: 1081})
-	_ = fm.StartForward("fwd1", nil)
+	_ = fm.StartForward(t.Context(), "fwd1", nil)
+	fm.stopRates() // 定期サンプリングと競合しないよう手動で記録する
+	t0 := time.Now()
+	fm.rates.Record(t0, fm.counters())
+	fm.active["fwd1"].sent.Store(2000)
+	for range 3 {
+		fm.active["fwd1"].inflight.Track(io.NopCloser(nil))
+	}
+	fm.rates.Record(t0.Add(2*time.Second), fm.counters())
 	sessions := fm.GetAllSessions()
 	if len(sessions) != 2 {
 		t.Fatalf("len(sessions) = %d, want 2", len(sessions))
 	}
-	if sessions[0].Status != core.Active {
-		t.Errorf("sessions[0] status = %v, want %v", sessions[0].Status, core.Active)
+	if sessions[0].Status != core.Active || sessions[1].Status != core.Stopped {
+		t.Errorf("statuses = %v/%v, want %v/%v", sessions[0].Status, sessions[1].Status, core.Active, core.Stopped)
 	}
-	if sessions[1].Status != core.Stopped {
-		t.Errorf("sessions[1] status = %v, want %v", sessions[1].Status, core.Stopped)
+	if s := sessions[0]; s.SendRate != 1000 || s.ActiveConns != 3 || len(s.RateHistory) != 1 {
+		t.Errorf("rates = %v/%v conns=%d history=%v, want 1000/0 conns=3", s.SendRate, s.ReceiveRate, s.ActiveConns, s.RateHistory)
 	}
 	fm.Close()
 }
 
 func TestForwardManager_Subscribe_MultipleSubscribers(t *testing.T) {
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", forwardtest.NewMockConn(false, true))
-	fm := NewForwardManager(context.Background(), sm)
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
 	ch1 := fm.Subscribe()
 	ch2 := fm.Subscribe()
 	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
-	_ = fm.StartForward("web", nil)
+	_ = fm.StartForward(t.Context(), "web", nil)
 	for _, ch := range []<-chan core.ForwardEvent{ch1, ch2} {
 		ev := forwardtest.DrainEvent(t, ch)
 		if ev.Type != core.ForwardEventStarted {
@@ -0,0 +1,2 @@
+// Package expiry はフォワードの有効期限（TTL）の警告と期限切れのタイマーをルール名ごとに管理する。
+package expiry
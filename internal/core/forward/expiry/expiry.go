@@ -0,0 +1,98 @@
+package expiry
+
+import (
+	"sync"
+	"time"
+)
+
+// WarnBefore は期限切れの警告を通知する、期限までの残り時間。
+const WarnBefore = 5 * time.Minute
+
+// entry は 1 つのルールに設定した警告と期限切れのタイマー。
+type entry struct {
+	warn   *time.Timer
+	expire *time.Timer
+}
+
+// Timers はルール名ごとの有効期限のタイマーを保持する。ゼロ値では使えないため New で生成する。
+type Timers struct {
+	mu         sync.Mutex
+	warnBefore time.Duration
+	entries    map[string]*entry
+	stopped    bool
+}
+
+// New は期限の warnBefore 前に警告する Timers を返す。
+func New(warnBefore time.Duration) *Timers {
+	return &Timers{warnBefore: warnBefore, entries: make(map[string]*entry)}
+}
+
+// Deadline は現在から ttl 後の時刻を返す。ttl が 0 以下の場合は期限なしを表すゼロ値を返す。
+func Deadline(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(ttl)
+}
+
+// Schedule は name の有効期限を at に設定する。既存の期限は置き換える。
+// 期限の warnBefore 前に warn を、期限に expire を呼ぶ。残りが warnBefore 以下の場合、warn は直ちに呼ぶ。
+// at がゼロ値または Stop 後の場合は期限を解除するのみ。
+func (t *Timers) Schedule(name string, at time.Time, warn, expire func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cancelLocked(name)
+	if at.IsZero() || t.stopped {
+		return
+	}
+	ttl := time.Until(at)
+	e := &entry{}
+	e.warn = time.AfterFunc(max(ttl-t.warnBefore, 0), t.fire(name, e, false, warn))
+	e.expire = time.AfterFunc(ttl, t.fire(name, e, true, expire))
+	t.entries[name] = e
+}
+
+// Cancel は name の有効期限を解除する。期限がない場合は何もしない。
+func (t *Timers) Cancel(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.cancelLocked(name)
+}
+
+// Stop は全ての有効期限を解除し、以降の Schedule を無効にする。
+func (t *Timers) Stop() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for name := range t.entries {
+		t.cancelLocked(name)
+	}
+	t.stopped = true
+}
+
+// cancelLocked は name のタイマーを止めて削除する。呼び出し元が t.mu を保持していること。
+func (t *Timers) cancelLocked(name string) {
+	if e, ok := t.entries[name]; ok {
+		e.warn.Stop()
+		e.expire.Stop()
+		delete(t.entries, name)
+	}
+}
+
+// fire は e が現在も name の期限である場合にのみ fn を呼ぶタイマー関数を返す。
+// 置き換えや解除の直前に発火したタイマーが古い期限で fn を呼ぶことを防ぐ。
+func (t *Timers) fire(name string, e *entry, last bool, fn func()) func() {
+	return func() {
+		t.mu.Lock()
+		current := t.entries[name] == e
+		if current && last {
+			delete(t.entries, name)
+		}
+		t.mu.Unlock()
+		if current {
+			fn()
+		}
+	}
+}
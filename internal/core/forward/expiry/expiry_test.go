@@ -0,0 +1,72 @@
+package expiry
+
+import (
+	"testing"
+	"time"
+)
+
+const waitTimeout = 2 * time.Second
+
+func wait(t *testing.T, ch <-chan string, want string) {
+	t.Helper()
+	select {
+	case got := <-ch:
+		if got != want {
+			t.Fatalf("got %q, want %q", got, want)
+		}
+	case <-time.After(waitTimeout):
+		t.Fatalf("timed out waiting for %q", want)
+	}
+}
+
+func record(ch chan<- string, s string) func() {
+	return func() { ch <- s }
+}
+
+func TestTimers_WarnThenExpire(t *testing.T) {
+	timers := New(30 * time.Millisecond)
+	ch := make(chan string, 2)
+	timers.Schedule("web", Deadline(60*time.Millisecond), record(ch, "warn"), record(ch, "expire"))
+	wait(t, ch, "warn")
+	wait(t, ch, "expire")
+}
+
+func TestTimers_ShortTTLWarnsImmediately(t *testing.T) {
+	timers := New(time.Hour)
+	ch := make(chan string, 2)
+	timers.Schedule("web", Deadline(50*time.Millisecond), record(ch, "warn"), record(ch, "expire"))
+	wait(t, ch, "warn")
+	wait(t, ch, "expire")
+}
+
+func TestTimers_ReplaceAndCancel(t *testing.T) {
+	timers := New(0)
+	ch := make(chan string, 4)
+	timers.Schedule("web", Deadline(20*time.Millisecond), func() {}, record(ch, "old"))
+	timers.Schedule("web", Deadline(40*time.Millisecond), func() {}, record(ch, "new"))
+	timers.Schedule("db", Deadline(20*time.Millisecond), func() {}, record(ch, "db"))
+	timers.Cancel("db")
+	wait(t, ch, "new")
+	select {
+	case got := <-ch:
+		t.Errorf("unexpected callback %q after replace/cancel", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestTimers_ZeroTTLAndStop(t *testing.T) {
+	timers := New(0)
+	called := make(chan string, 2)
+	if at := Deadline(0); !at.IsZero() {
+		t.Errorf("Deadline(0) = %v, want zero", at)
+	}
+	timers.Schedule("web", time.Time{}, record(called, "warn"), record(called, "expire"))
+	timers.Schedule("db", Deadline(20*time.Millisecond), func() {}, record(called, "db"))
+	timers.Stop()
+	timers.Schedule("web", Deadline(time.Millisecond), record(called, "warn"), record(called, "expire"))
+	select {
+	case got := <-called:
+		t.Errorf("unexpected callback %q", got)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
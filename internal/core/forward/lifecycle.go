@@ -7,13 +7,18 @@ import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/expiry"
+	"github.com/ousiassllc/moleport/internal/core/forward/portcheck"
+	"github.com/ousiassllc/moleport/internal/core/forward/relay"
+	"github.com/ousiassllc/moleport/internal/core/forward/resume"
+	"github.com/ousiassllc/moleport/internal/core/forward/sshlink"
 )
 
 // StartForward はフォワーディングセッションを開始する。
-// cb が非 nil の場合、SSH 接続にクレデンシャルコールバックを使用する。
-func (m *forwardManager) StartForward(ruleName string, cb core.CredentialCallback) error {
+// cb が非 nil の場合、SSH 接続にクレデンシャルコールバックを使用する。ctx は SSH 接続の確立にのみ使う。
+func (m *forwardManager) StartForward(ctx context.Context, ruleName string, cb core.CredentialCallback) error {
 	m.mu.Lock()
-	rule, exists := m.rules[ruleName]
+	rule, exists := m.rules.Get(ruleName)
 	if !exists {
 		m.mu.Unlock()
 		return &core.NotFoundError{Resource: "rule", Name: ruleName}
@@ -25,6 +30,8 @@ func (m *forwardManager) StartForward(ruleName string, cb core.CredentialCallbac
 	}
 
 	m.active[ruleName] = &activeForward{starting: true}
+	active, reserved := m.portOwners(ruleName)
+	rec := m.resumed.Take(ruleName)
 	m.mu.Unlock()
 
 	cleanup := func() {
@@ -35,46 +42,37 @@ func (m *forwardManager) StartForward(ruleName string, cb core.CredentialCallbac
 		m.mu.Unlock()
 	}
 
-	if !m.sshManager.IsConnected(rule.Host) {
-		if err := m.sshManager.ConnectWithCallback(rule.Host, cb); err != nil {
+	if rule.Type != core.Remote && rule.LocalPort != 0 {
+		if err := portcheck.Check(rule.LocalPort, active, reserved, m.portInUse); err != nil {
 			cleanup()
-			return fmt.Errorf("failed to connect to host %s: %w", rule.Host, err)
+			return err
 		}
 	}
 
-	sshConn, err := m.sshManager.GetSSHConnection(rule.Host)
-	if err != nil {
-		cleanup()
-		return fmt.Errorf("failed to get SSH connection: %w", err)
-	}
-
-	sshClient, err := m.sshManager.GetConnection(rule.Host)
+	sshConn, sshClient, err := sshlink.Open(ctx, m.sshManager, rule, cb)
 	if err != nil {
 		cleanup()
-		return fmt.Errorf("failed to get SSH client: %w", err)
+		return err
 	}
 
-	ctx, cancel := context.WithCancel(m.ctx)
-
-	listener, err := openListener(ctx, sshConn, rule)
+	fwdCtx, cancel := context.WithCancel(m.ctx)
+	expiresAt := expiry.Deadline(rule.TTL.Duration)
 
+	listener, err := relay.Listen(fwdCtx, sshConn, rule)
 	if err != nil {
 		cancel()
 		cleanup()
 		return fmt.Errorf("failed to create listener: %w", err)
 	}
 
-	af := &activeForward{
-		session: core.ForwardSession{
-			ID:          fmt.Sprintf("%s-%d", ruleName, time.Now().UnixNano()),
-			Rule:        rule,
-			Status:      core.Active,
-			ConnectedAt: time.Now(),
-		},
-		listener: listener,
-		ctx:      ctx,
-		cancel:   cancel,
+	session := core.ForwardSession{
+		ID:          fmt.Sprintf("%s-%d", ruleName, time.Now().UnixNano()),
+		ConnectedAt: time.Now(),
+		ExpiresAt:   expiresAt,
 	}
+	// 前回のデーモンから復元したセッションは開始時刻と再接続回数を引き継ぐ
+	resume.Apply(rec, &session)
+	af := newActiveForward(fwdCtx, cancel, listener, rule, session)
 
 	m.mu.Lock()
 	m.active[ruleName] = af
@@ -82,35 +80,58 @@ func (m *forwardManager) StartForward(ruleName string, cb core.CredentialCallbac
 
 	go m.acceptLoop(af, rule, sshClient)
 
-	m.events.Emit(core.ForwardEvent{
-		Type:     core.ForwardEventStarted,
-		RuleName: ruleName,
-		Session:  &af.session,
-	})
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventStarted, RuleName: ruleName, Session: &af.session})
+	// 期限切れ警告が開始イベントより先に配信されないよう、タイマーは発行後に設定する
+	m.scheduleExpiry(ruleName, expiresAt)
 
-	slog.Info("forward started", "rule", ruleName, "type", rule.Type, "local_port", rule.LocalPort)
+	slog.Info("forward started", "rule", ruleName, "type", rule.Type, "local_port", af.session.ListenPort())
 	return nil
 }
 
 // StopForward はフォワーディングセッションを停止する。
+// 転送中の接続がある場合はドレインし、完了を待ってから停止する。ドレイン中に再度呼ばれた場合は直ちに切断する。
 func (m *forwardManager) StopForward(ruleName string) error {
+	m.stopForward(ruleName, core.StopReasonUser, true)
+	return nil
+}
+
+// StopAllForwards は全フォワーディングセッションを停止する。転送中の接続がある場合は StopForward と同様にドレインする。
+func (m *forwardManager) StopAllForwards() error {
+	m.stopAll(core.StopReasonUser, true)
+	return nil
+}
+
+// stopForward はフォワーディングセッションを reason で停止し、ForwardEventStopped を発行する。
+// drain が true でドレインを開始した場合は ForwardEventDraining を発行し、停止は finishDrain が行う。
+func (m *forwardManager) stopForward(ruleName string, reason core.StopReason, drain bool) {
+	var timeout time.Duration
+	if drain && m.drainTimeout != nil {
+		timeout = m.drainTimeout()
+	}
+
 	m.mu.Lock()
-	session := m.stopForwardLocked(ruleName)
+	if af := m.startDrainLocked(ruleName, timeout); af != nil {
+		session := m.sessionOf(af)
+		m.expiry.Cancel(ruleName)
+		m.mu.Unlock()
+
+		m.events.Emit(core.ForwardEvent{Type: core.ForwardEventDraining, RuleName: ruleName, Session: &session})
+		slog.Info("forward draining", "rule", ruleName, "conns", session.ActiveConns, "timeout", timeout)
+		go m.finishDrain(ruleName, af, reason, timeout)
+		return
+	}
+	session := m.stopForwardLocked(ruleName, reason)
+	m.expiry.Cancel(ruleName)
 	m.mu.Unlock()
 
 	if session != nil {
-		m.events.Emit(core.ForwardEvent{
-			Type:     core.ForwardEventStopped,
-			RuleName: ruleName,
-			Session:  session,
-		})
-		slog.Info("forward stopped", "rule", ruleName)
+		m.events.Emit(core.ForwardEvent{Type: core.ForwardEventStopped, RuleName: ruleName, Session: session})
+		slog.Info("forward stopped", "rule", ruleName, "reason", session.StoppedReason)
 	}
-	return nil
 }
 
-// StopAllForwards は全フォワーディングセッションを停止する。
-func (m *forwardManager) StopAllForwards() error {
+// stopAll は全フォワーディングセッションを reason で停止する。drain は stopForward にそのまま渡す。
+func (m *forwardManager) stopAll(reason core.StopReason, drain bool) {
 	m.mu.RLock()
 	names := make([]string, 0, len(m.active))
 	for name := range m.active {
@@ -119,17 +140,47 @@ func (m *forwardManager) StopAllForwards() error {
 	m.mu.RUnlock()
 
 	for _, name := range names {
-		if err := m.StopForward(name); err != nil {
-			return err
-		}
+		m.stopForward(name, reason, drain)
 	}
-	return nil
 }
 
-// stopForwardLocked はロック保持中にフォワーディングセッションを停止する。
-// 呼び出し元が m.mu.Lock() を保持していること。
+// startDrainLocked は転送中の接続があるアクティブなセッションの新規受付を止め、SessionDraining 状態にする。
+// timeout が 0 以下、ドレイン中、または転送中の接続がない場合は何もせず nil を返す。呼び出し元が m.mu.Lock() を保持していること。
+func (m *forwardManager) startDrainLocked(ruleName string, timeout time.Duration) *activeForward {
+	af, exists := m.active[ruleName]
+	if !exists || timeout <= 0 || af.starting || af.session.Status != core.Active || af.inflight.Len() == 0 {
+		return nil
+	}
+	// acceptLoop がリスナーの停止を障害と誤認しないよう、閉じる前にコンテキストをキャンセルする
+	af.cancel()
+	_ = af.listener.Close()
+	af.session.Status = core.SessionDraining
+	return af
+}
+
+// finishDrain は転送中の接続の完了を timeout まで待ち、残った接続を強制切断してセッションを停止する。
+// 待機中に再度停止された場合など、af が既に active マップにない場合は何もしない。
+func (m *forwardManager) finishDrain(ruleName string, af *activeForward, reason core.StopReason, timeout time.Duration) {
+	if !af.inflight.Wait(timeout) {
+		slog.Warn("forward drain timed out, closing connections", "rule", ruleName, "conns", af.inflight.Len())
+	}
+
+	m.mu.Lock()
+	if current, ok := m.active[ruleName]; !ok || current != af {
+		m.mu.Unlock()
+		return
+	}
+	session := m.stopForwardLocked(ruleName, reason)
+	m.mu.Unlock()
+
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventStopped, RuleName: ruleName, Session: session})
+	slog.Info("forward stopped", "rule", ruleName, "reason", session.StoppedReason)
+}
+
+// stopForwardLocked はロック保持中にフォワーディングセッションを停止し、転送中の接続を切断して停止理由を記録する。
+// エラー状態のセッションはエラーになった時点の理由を残す。呼び出し元が m.mu.Lock() を保持していること。
 // 停止したセッション情報を返す（アクティブでない場合は nil）。
-func (m *forwardManager) stopForwardLocked(ruleName string) *core.ForwardSession {
+func (m *forwardManager) stopForwardLocked(ruleName string, reason core.StopReason) *core.ForwardSession {
 	af, exists := m.active[ruleName]
 	if !exists {
 		return nil
@@ -143,17 +194,25 @@ func (m *forwardManager) stopForwardLocked(ruleName string) *core.ForwardSession
 
 	_ = af.listener.Close()
 	af.cancel()
+	af.inflight.CloseAll()
+	if af.session.Status != core.SessionError || af.session.StoppedReason == core.StopReasonNone {
+		af.session.StoppedReason = reason
+		af.session.StoppedAt = time.Now()
+	}
 	af.session.Status = core.Stopped
 	af.session.BytesSent = af.sent.Load()
 	af.session.BytesReceived = af.received.Load()
 	session := af.session
+	m.stops.Record(ruleName, session.StoppedReason, session.StoppedAt)
 	delete(m.active, ruleName)
 	return &session
 }
 
 // Close は全フォワーディングを停止し、サブスクライバーチャネルを閉じる。
 func (m *forwardManager) Close() {
-	_ = m.StopAllForwards()
+	m.expiry.Stop()
+	m.stopRates()
+	m.stopAll(core.StopReasonDaemonShutdown, false)
 
 	m.mu.Lock()
 	defer m.mu.Unlock()
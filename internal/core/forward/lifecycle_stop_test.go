@@ -5,13 +5,14 @@ import (
 	"fmt"
 	"net"
 	"testing"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/core/forwardtest"
 )
 
 func TestForwardManager_StopForward_NotActive(t *testing.T) {
-	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager())
+	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil)
 	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
 	if err := fm.StopForward("web"); err != nil { // アクティブでないルールの停止はエラーにならない
 		t.Fatalf("StopForward() error = %v", err)
@@ -19,13 +20,11 @@ func TestForwardManager_StopForward_NotActive(t *testing.T) {
 }
 
 func TestForwardManager_StopAllForwards(t *testing.T) {
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", forwardtest.NewMockConn(false, true))
-	fm := NewForwardManager(context.Background(), sm)
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
 	_, _ = fm.AddRule(core.ForwardRule{Name: "fwd1", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
 	_, _ = fm.AddRule(core.ForwardRule{Name: "fwd2", Host: "server1", Type: core.Dynamic, LocalPort: 1081})
-	_ = fm.StartForward("fwd1", nil)
-	_ = fm.StartForward("fwd2", nil)
+	_ = fm.StartForward(t.Context(), "fwd1", nil)
+	_ = fm.StartForward(t.Context(), "fwd2", nil)
 	if err := fm.StopAllForwards(); err != nil {
 		t.Fatalf("StopAllForwards() error = %v", err)
 	}
@@ -37,11 +36,9 @@ func TestForwardManager_StopAllForwards(t *testing.T) {
 }
 
 func TestForwardManager_DeleteRule_StopsActive(t *testing.T) {
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", forwardtest.NewMockConn(false, true))
-	fm := NewForwardManager(context.Background(), sm)
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
 	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
-	_ = fm.StartForward("web", nil)
+	_ = fm.StartForward(t.Context(), "web", nil)
 	if err := fm.DeleteRule("web"); err != nil {
 		t.Fatalf("DeleteRule() error = %v", err)
 	}
@@ -51,15 +48,16 @@ func TestForwardManager_DeleteRule_StopsActive(t *testing.T) {
 }
 
 func TestForwardManager_Close(t *testing.T) {
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", forwardtest.NewMockConn(false, true))
-	fm := NewForwardManager(context.Background(), sm)
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
 	events := fm.Subscribe()
 	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
-	_ = fm.StartForward("web", nil)
+	_ = fm.StartForward(t.Context(), "web", nil)
 	forwardtest.DrainEvent(t, events) // drain started event
 	fm.Close()
-	for range events { // drain until channel closed
+	for ev := range events { // drain until channel closed
+		if ev.Type == core.ForwardEventStopped && ev.Session.StoppedReason != core.StopReasonDaemonShutdown {
+			t.Errorf("StoppedReason = %q, want daemon_shutdown", ev.Session.StoppedReason)
+		}
 	}
 }
 
@@ -67,15 +65,13 @@ func TestForwardManager_StartForward_ListenerError(t *testing.T) {
 	sm := forwardtest.NewMockSSHManager()
 	sm.SetConnected("server1", &forwardtest.MockSSHConnection{
 		Alive: true,
-		LocalForwardF: func(_ context.Context, _ int, _ string) (net.Listener, error) {
+		LocalForwardF: func(_ context.Context, _ int, _, _ string) (net.Listener, error) {
 			return nil, fmt.Errorf("address already in use")
 		},
 	})
-	fm := NewForwardManager(context.Background(), sm)
-	_, _ = fm.AddRule(core.ForwardRule{
-		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
-	})
-	if err := fm.StartForward("web", nil); err == nil {
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil)
+	_, _ = fm.AddRule(forwardtest.WebRule())
+	if err := fm.StartForward(t.Context(), "web", nil); err == nil {
 		t.Fatal("StartForward() should return error when listener fails")
 	}
 }
@@ -85,11 +81,11 @@ func TestForwardManager_StopForward_ClosesListener(t *testing.T) {
 	ml := forwardtest.NewMockListener()
 	sm.SetConnected("server1", &forwardtest.MockSSHConnection{
 		Alive:           true,
-		DynamicForwardF: func(_ context.Context, _ int) (net.Listener, error) { return ml, nil },
+		DynamicForwardF: func(_ context.Context, _ int, _ string) (net.Listener, error) { return ml, nil },
 	})
-	fm := NewForwardManager(context.Background(), sm)
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil)
 	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
-	_ = fm.StartForward("web", nil)
+	_ = fm.StartForward(t.Context(), "web", nil)
 	_ = fm.StopForward("web")
 	// ConnCh が閉じられていることで listener の Close が呼ばれたことを検証する
 	_, ok := <-ml.ConnCh
@@ -97,3 +93,29 @@ func TestForwardManager_StopForward_ClosesListener(t *testing.T) {
 		t.Error("StopForward should close the listener (ConnCh should be closed)")
 	}
 }
+
+func TestForwardManager_StopForward_Drains(t *testing.T) {
+	for _, timeout := range []time.Duration{time.Minute, 10 * time.Millisecond} {
+		fm := newConnectedManager(forwardtest.NewMockConn(false, true))
+		fm.drainTimeout = func() time.Duration { return timeout }
+		_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
+		_ = fm.StartForward(t.Context(), "web", nil)
+		events := fm.Subscribe()
+		client, server := net.Pipe()
+		conn := fm.active["web"].inflight.Track(server)
+
+		_ = fm.StopForward("web")
+		if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventDraining || ev.Session.ActiveConns != 1 {
+			t.Fatalf("event = %v (%+v), want Draining with 1 conn", ev.Type, ev.Session)
+		}
+		forwardtest.AssertSessionStatus(t, fm, "web", core.SessionDraining)
+		if timeout == time.Minute {
+			conn.Done() // 転送が完了すればタイムアウトを待たずに停止する
+		} else if _, err := client.Read(make([]byte, 1)); err == nil {
+			t.Error("remaining connection should be force-closed after the timeout")
+		}
+		if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventStopped || ev.Session.StoppedReason != core.StopReasonUser {
+			t.Fatalf("event = %v (%+v), want Stopped by user", ev.Type, ev.Session)
+		}
+	}
+}
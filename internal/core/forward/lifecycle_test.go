@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net"
 	"sync"
+	"sync/atomic"
 	"testing"
 
 	"github.com/ousiassllc/moleport/internal/core"
@@ -12,7 +13,7 @@ import (
 )
 
 func TestForwardManager_StartForward_RuleNotFound(t *testing.T) {
-	if err := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager()).StartForward("nonexistent", nil); err == nil {
+	if err := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil).StartForward(t.Context(), "nonexistent", nil); err == nil {
 		t.Fatal("StartForward() should return error for nonexistent rule")
 	}
 }
@@ -20,11 +21,9 @@ func TestForwardManager_StartForward_RuleNotFound(t *testing.T) {
 func TestForwardManager_StartForward_ConnectError(t *testing.T) {
 	sm := forwardtest.NewMockSSHManager()
 	sm.ConnectErr = fmt.Errorf("connection refused")
-	fm := NewForwardManager(context.Background(), sm)
-	_, _ = fm.AddRule(core.ForwardRule{
-		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
-	})
-	if err := fm.StartForward("web", nil); err == nil {
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil)
+	_, _ = fm.AddRule(forwardtest.WebRule())
+	if err := fm.StartForward(t.Context(), "web", nil); err == nil {
 		t.Fatal("StartForward() should return error when SSH connect fails")
 	}
 }
@@ -41,14 +40,12 @@ func TestForwardManager_StartForward_UsesCallbackForConnect(t *testing.T) {
 		sm.SetConnected(hostName, mockConn)
 		return nil
 	}
-	fm := NewForwardManager(context.Background(), sm)
-	_, _ = fm.AddRule(core.ForwardRule{
-		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
-	})
-	cb := func(_ core.CredentialRequest) (core.CredentialResponse, error) {
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil)
+	_, _ = fm.AddRule(forwardtest.WebRule())
+	cb := func(core.CredentialRequest) (core.CredentialResponse, error) {
 		return core.CredentialResponse{Value: "password123"}, nil
 	}
-	if err := fm.StartForward("web", cb); err != nil {
+	if err := fm.StartForward(t.Context(), "web", cb); err != nil {
 		t.Fatalf("StartForward() with callback should succeed, got error: %v", err)
 	}
 	if receivedCb == nil {
@@ -58,41 +55,32 @@ func TestForwardManager_StartForward_UsesCallbackForConnect(t *testing.T) {
 }
 
 func TestForwardManager_StartForward_Local(t *testing.T) {
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", forwardtest.NewMockConn(true, false))
-	fm := NewForwardManager(context.Background(), sm)
-	_, _ = fm.AddRule(core.ForwardRule{
-		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
-	})
+	fm := newConnectedManager(forwardtest.NewMockConn(true, false))
+	_, _ = fm.AddRule(forwardtest.WebRule())
+	fm.LoadSessionRecords([]core.SessionRecord{{Rule: "web", ReconnectCount: 2}})
 	events := fm.Subscribe()
-	if err := fm.StartForward("web", nil); err != nil {
+	if err := fm.StartForward(t.Context(), "web", nil); err != nil {
 		t.Fatalf("StartForward() error = %v", err)
 	}
 	ev := forwardtest.DrainEvent(t, events)
-	if ev.Type != core.ForwardEventStarted {
-		t.Errorf("event type = %v, want %v", ev.Type, core.ForwardEventStarted)
-	}
-	if ev.RuleName != "web" {
-		t.Errorf("event rule = %q, want %q", ev.RuleName, "web")
-	}
-	if ev.Session == nil {
-		t.Fatal("event session should not be nil")
-	}
-	if ev.Session.Status != core.Active {
-		t.Errorf("session status = %v, want %v", ev.Session.Status, core.Active)
+	if ev.Type != core.ForwardEventStarted || ev.RuleName != "web" || ev.Session == nil || ev.Session.Status != core.Active || ev.Session.ReconnectCount != 2 {
+		t.Fatalf("event = %+v, want Started for web with an active session carrying the saved reconnect count", ev)
 	}
 	forwardtest.AssertSessionStatus(t, fm, "web", core.Active)
-	if err := fm.StartForward("web", nil); err == nil {
+	if err := fm.StartForward(t.Context(), "web", nil); err == nil {
 		t.Fatal("StartForward() should return error for already active forward")
 	}
 	if err := fm.StopForward("web"); err != nil {
 		t.Fatalf("StopForward() error = %v", err)
 	}
 	ev = forwardtest.DrainEvent(t, events)
-	if ev.Type != core.ForwardEventStopped {
-		t.Errorf("event type = %v, want %v", ev.Type, core.ForwardEventStopped)
+	if ev.Type != core.ForwardEventStopped || ev.Session.StoppedReason != core.StopReasonUser {
+		t.Errorf("event = %v (%q), want %v by user", ev.Type, ev.Session.StoppedReason, core.ForwardEventStopped)
 	}
 	forwardtest.AssertSessionStatus(t, fm, "web", core.Stopped)
+	if s, _ := fm.GetSession("web"); s.StoppedReason != core.StopReasonUser || s.StoppedAt.IsZero() {
+		t.Errorf("GetSession() stopped = %q at %v, want user with time", s.StoppedReason, s.StoppedAt)
+	}
 }
 
 // TestForwardManager_StartForward_ConcurrentSameRule は並行呼び出しで重複リスナーが作成されないことを検証する。
@@ -102,69 +90,46 @@ func TestForwardManager_StartForward_ConcurrentSameRule(t *testing.T) {
 		sm.SetConnected(hostName, forwardtest.NewMockConn(true, false))
 		return nil
 	}
-	fm := NewForwardManager(context.Background(), sm)
-	_, _ = fm.AddRule(core.ForwardRule{
-		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
-	})
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil)
+	_, _ = fm.AddRule(forwardtest.WebRule())
 
-	const goroutines = 10
 	var wg sync.WaitGroup
-	wg.Add(goroutines)
-	errs := make([]error, goroutines)
-	for i := range goroutines {
-		go func() {
-			defer wg.Done()
-			errs[i] = fm.StartForward("web", nil)
-		}()
+	var successCount atomic.Int32
+	for range 10 {
+		wg.Go(func() {
+			if fm.StartForward(t.Context(), "web", nil) == nil {
+				successCount.Add(1)
+			}
+		})
 	}
 	wg.Wait()
-	var successCount int
-	for _, err := range errs {
-		if err == nil {
-			successCount++
-		}
-	}
-	if successCount != 1 {
-		t.Errorf("expected exactly 1 success, got %d", successCount)
+	if n := successCount.Load(); n != 1 {
+		t.Errorf("expected exactly 1 success, got %d", n)
 	}
 	fm.Close()
 }
 
 func TestForwardManager_StartForward_RemoteAndDynamic(t *testing.T) {
+	remoteConn := &forwardtest.MockSSHConnection{Alive: true, RemoteForwardF: func(context.Context, int, string, string) (net.Listener, error) {
+		return forwardtest.NewMockListener(), nil
+	}}
 	tests := []struct {
 		name     string
 		rule     core.ForwardRule
-		mockConn *forwardtest.MockSSHConnection
+		mockConn core.SSHConnection
 	}{
-		{
-			name: "Remote",
-			rule: core.ForwardRule{
-				Name: "remote-web", Host: "server1", Type: core.Remote, LocalPort: 3000, RemoteHost: "0.0.0.0", RemotePort: 80,
-			},
-			mockConn: &forwardtest.MockSSHConnection{
-				Alive: true,
-				RemoteForwardF: func(_ context.Context, _ int, _ string, _ string) (net.Listener, error) {
-					return forwardtest.NewMockListener(), nil
-				},
-			},
-		},
-		{
-			name:     "Dynamic",
-			rule:     core.ForwardRule{Name: "socks", Host: "server1", Type: core.Dynamic, LocalPort: 1080},
-			mockConn: forwardtest.NewMockConn(false, true),
-		},
+		{"Remote", core.ForwardRule{Name: "remote-web", Host: "server1", Type: core.Remote, LocalPort: 3000, RemoteHost: "0.0.0.0", RemotePort: 80}, remoteConn},
+		{"Dynamic", core.ForwardRule{Name: "socks", Host: "server1", Type: core.Dynamic, LocalPort: 1080}, forwardtest.NewMockConn(false, true)},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			sm := forwardtest.NewMockSSHManager()
-			sm.SetConnected("server1", tt.mockConn)
-			fm := NewForwardManager(context.Background(), sm)
+			fm := newConnectedManager(tt.mockConn)
+			defer fm.Close()
 			_, _ = fm.AddRule(tt.rule)
-			if err := fm.StartForward(tt.rule.Name, nil); err != nil {
+			if err := fm.StartForward(t.Context(), tt.rule.Name, nil); err != nil {
 				t.Fatalf("StartForward() error = %v", err)
 			}
 			forwardtest.AssertSessionStatus(t, fm, tt.rule.Name, core.Active)
-			fm.Close()
 		})
 	}
 }
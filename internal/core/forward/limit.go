@@ -0,0 +1,26 @@
+package forward
+
+import (
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// SetLimit はルールの送信・受信の帯域上限（kbps、0 は無制限）を変更する。転送中の接続にも即座に反映する。
+func (m *forwardManager) SetLimit(ruleName string, uploadKbps, downloadKbps int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rule, exists := m.rules.Get(ruleName)
+	if !exists {
+		return &core.NotFoundError{Resource: "rule", Name: ruleName}
+	}
+	rule.MaxUploadKbps, rule.MaxDownloadKbps = uploadKbps, downloadKbps
+	if err := core.ValidateForwardRule(rule); err != nil {
+		return err
+	}
+	m.rules.Replace(rule)
+	if af, ok := m.active[ruleName]; ok && !af.starting {
+		af.session.Rule = rule
+	}
+	m.limits.Set(ruleName, uploadKbps, downloadKbps)
+	return nil
+}
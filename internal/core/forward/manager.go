@@ -2,16 +2,27 @@ package forward
 
 import (
 	"context"
-	"fmt"
 	"net"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/event"
+	"github.com/ousiassllc/moleport/internal/core/forward/drain"
+	"github.com/ousiassllc/moleport/internal/core/forward/expiry"
+	"github.com/ousiassllc/moleport/internal/core/forward/portcheck"
+	"github.com/ousiassllc/moleport/internal/core/forward/rate"
+	"github.com/ousiassllc/moleport/internal/core/forward/relay"
+	"github.com/ousiassllc/moleport/internal/core/forward/resume"
+	"github.com/ousiassllc/moleport/internal/core/forward/ruleset"
+	"github.com/ousiassllc/moleport/internal/core/forward/stophistory"
+	"github.com/ousiassllc/moleport/internal/core/forward/throttle"
 )
 
 // activeForward は実行中のフォワーディングセッションを保持する。
 // starting が true の場合、起動処理中のプレースホルダーを表す。
+// lastErrorAt は最後にブリッジエラーを通知した時刻で、m.mu で保護する。
 type activeForward struct {
 	session  core.ForwardSession
 	listener net.Listener
@@ -19,96 +30,107 @@ type activeForward struct {
 	cancel   context.CancelFunc
 	sent     atomic.Int64
 	received atomic.Int64
+	inflight drain.Tracker
 	starting bool
+
+	lastErrorAt time.Time
+}
+
+// newActiveForward は listener で受け付けるアクティブな activeForward を作成する。
+// session の Rule・Status・BoundPort は rule と listener から設定し、転送量は session の値から数え始める。
+func newActiveForward(ctx context.Context, cancel context.CancelFunc, listener net.Listener, rule core.ForwardRule, session core.ForwardSession) *activeForward {
+	session.Rule, session.Status, session.BoundPort = rule, core.Active, relay.BoundPort(rule, listener)
+	af := &activeForward{session: session, listener: listener, ctx: ctx, cancel: cancel}
+	af.sent.Store(session.BytesSent)
+	af.received.Store(session.BytesReceived)
+	return af
 }
 
 type forwardManager struct {
-	mu         sync.RWMutex
-	ctx        context.Context
-	sshManager core.SSHManager
-	rules      map[string]core.ForwardRule
-	ruleOrder  []string // 追加順序を保持
-	active     map[string]*activeForward
-	events     core.EventEmitter[core.ForwardEvent]
-	closed     bool
-	nextID     int
+	mu           sync.RWMutex
+	ctx          context.Context
+	sshManager   core.SSHManager
+	portInUse    portcheck.Prober
+	rules        *ruleset.Set
+	active       map[string]*activeForward
+	expiry       *expiry.Timers
+	limits       *throttle.Registry
+	rates        *rate.Sampler
+	stopRates    context.CancelFunc
+	stops        *stophistory.History
+	resumed      *resume.Records
+	events       event.Emitter[core.ForwardEvent]
+	drainTimeout func() time.Duration
+	access       core.AccessLogger
+	closed       bool
 }
 
 // NewForwardManager は ForwardManager の実装を返す。
-func NewForwardManager(ctx context.Context, sshManager core.SSHManager) core.ForwardManager {
+// portInUse はローカルポートが他のプロセスに使用されているかの確認に使う。nil の場合は確認しない。
+// drainTimeout は停止時に転送中の接続の完了を待つ上限を返す。停止のたびに呼ぶため設定の変更が即座に反映される。
+// nil の場合は待たずに切断する。access は AccessLog が設定されたルールのアクセスログの出力に使う。nil の場合は記録しない。
+func NewForwardManager(ctx context.Context, sshManager core.SSHManager, portInUse portcheck.Prober, drainTimeout func() time.Duration, access core.AccessLogger) core.ForwardManager {
 	m := &forwardManager{
-		ctx:        ctx,
-		sshManager: sshManager,
-		rules:      make(map[string]core.ForwardRule),
-		active:     make(map[string]*activeForward),
+		ctx:          ctx,
+		sshManager:   sshManager,
+		portInUse:    portInUse,
+		rules:        ruleset.New(),
+		active:       make(map[string]*activeForward),
+		expiry:       expiry.New(expiry.WarnBefore),
+		limits:       throttle.NewRegistry(),
+		rates:        rate.New(),
+		stops:        stophistory.New(),
+		resumed:      resume.New(),
+		drainTimeout: drainTimeout,
+		access:       access,
 	}
-	m.events = core.NewEventEmitter[core.ForwardEvent](&m.mu)
+	m.events = event.NewEmitter[core.ForwardEvent](&m.mu)
+
+	var sampleCtx context.Context
+	sampleCtx, m.stopRates = context.WithCancel(ctx)
+	go m.rates.Run(sampleCtx, rate.Interval, m.counters)
 	return m
 }
 
 // AddRule はフォワーディングルールを追加する。
+// ルール名は前後の空白を除いて命名規則と大文字小文字を区別しない一意性を検証する。
 // 成功時はルール名（自動生成名を含む）を返す。
 func (m *forwardManager) AddRule(rule core.ForwardRule) (string, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if rule.Name == "" {
-		m.nextID++
-		rule.Name = fmt.Sprintf("forward-%d", m.nextID)
-	}
-
-	if _, exists := m.rules[rule.Name]; exists {
-		return "", &core.AlreadyExistsError{Resource: "rule", Name: rule.Name}
-	}
-
-	if rule.Host == "" {
-		return "", fmt.Errorf("host is required")
-	}
-
-	if err := core.ValidatePort(rule.LocalPort); err != nil {
-		return "", fmt.Errorf("local_port: %w", err)
+	added, err := m.rules.Add(rule)
+	if err != nil {
+		return "", err
 	}
-
-	if rule.Type == core.Local || rule.Type == core.Remote {
-		if err := core.ValidatePort(rule.RemotePort); err != nil {
-			return "", fmt.Errorf("remote_port: %w", err)
-		}
-		if rule.RemoteHost == "" {
-			rule.RemoteHost = "localhost"
-		}
-	}
-
-	m.rules[rule.Name] = rule
-	m.ruleOrder = append(m.ruleOrder, rule.Name)
-	return rule.Name, nil
+	m.limits.Set(added.Name, added.MaxUploadKbps, added.MaxDownloadKbps)
+	return added.Name, nil
 }
 
 // DeleteRule はフォワーディングルールを削除する。アクティブな場合は停止する。
 func (m *forwardManager) DeleteRule(name string) error {
+	return m.deleteRule(name, core.StopReasonUser)
+}
+
+// deleteRule はフォワーディングルールを削除する。アクティブな場合は reason で停止する。
+func (m *forwardManager) deleteRule(name string, reason core.StopReason) error {
 	m.mu.Lock()
-	if _, exists := m.rules[name]; !exists {
+	if _, exists := m.rules.Get(name); !exists {
 		m.mu.Unlock()
 		return &core.NotFoundError{Resource: "rule", Name: name}
 	}
 
 	// アクティブな場合は停止（ロックを保持したまま）
-	session := m.stopForwardLocked(name)
+	session := m.stopForwardLocked(name, reason)
+	m.expiry.Cancel(name)
+	m.limits.Delete(name)
+	m.stops.Delete(name)
 
-	delete(m.rules, name)
-	for i, n := range m.ruleOrder {
-		if n == name {
-			m.ruleOrder = append(m.ruleOrder[:i], m.ruleOrder[i+1:]...)
-			break
-		}
-	}
+	m.rules.Delete(name)
 	m.mu.Unlock()
 
 	if session != nil {
-		m.events.Emit(core.ForwardEvent{
-			Type:     core.ForwardEventStopped,
-			RuleName: name,
-			Session:  session,
-		})
+		m.events.Emit(core.ForwardEvent{Type: core.ForwardEventStopped, RuleName: name, Session: session})
 	}
 	return nil
 }
@@ -118,13 +140,7 @@ func (m *forwardManager) GetRules() []core.ForwardRule {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	rules := make([]core.ForwardRule, 0, len(m.ruleOrder))
-	for _, name := range m.ruleOrder {
-		if rule, ok := m.rules[name]; ok {
-			rules = append(rules, rule)
-		}
-	}
-	return rules
+	return m.rules.All()
 }
 
 // GetRulesByHost はホスト名でフィルタしたルール一覧を返す。
@@ -132,13 +148,7 @@ func (m *forwardManager) GetRulesByHost(hostName string) []core.ForwardRule {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	var rules []core.ForwardRule
-	for _, name := range m.ruleOrder {
-		if rule, ok := m.rules[name]; ok && rule.Host == hostName {
-			rules = append(rules, rule)
-		}
-	}
-	return rules
+	return m.rules.ByHost(hostName)
 }
 
 // GetSession はルール名からセッション情報を返す。
@@ -147,21 +157,18 @@ func (m *forwardManager) GetSession(ruleName string) (*core.ForwardSession, erro
 	defer m.mu.RUnlock()
 
 	if af, exists := m.active[ruleName]; exists && !af.starting {
-		session := af.session
-		session.BytesSent = af.sent.Load()
-		session.BytesReceived = af.received.Load()
+		session := m.sessionOf(af)
 		return &session, nil
 	}
 
-	rule, exists := m.rules[ruleName]
+	rule, exists := m.rules.Get(ruleName)
 	if !exists {
 		return nil, &core.NotFoundError{Resource: "rule", Name: ruleName}
 	}
 
-	return &core.ForwardSession{
-		Rule:   rule,
-		Status: core.Stopped,
-	}, nil
+	session := core.ForwardSession{Rule: rule, Status: core.Stopped}
+	m.stops.Apply(ruleName, &session)
+	return &session, nil
 }
 
 // GetAllSessions は全ルールのセッション情報を返す。
@@ -169,28 +176,36 @@ func (m *forwardManager) GetAllSessions() []core.ForwardSession {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	sessions := make([]core.ForwardSession, 0, len(m.ruleOrder))
-	for _, name := range m.ruleOrder {
-		rule, ok := m.rules[name]
-		if !ok {
-			continue
-		}
-
-		if af, active := m.active[name]; active && !af.starting {
-			session := af.session
-			session.BytesSent = af.sent.Load()
-			session.BytesReceived = af.received.Load()
-			sessions = append(sessions, session)
+	rules := m.rules.All()
+	sessions := make([]core.ForwardSession, 0, len(rules))
+	for _, rule := range rules {
+		if af, active := m.active[rule.Name]; active && !af.starting {
+			sessions = append(sessions, m.sessionOf(af))
 		} else {
-			sessions = append(sessions, core.ForwardSession{
-				Rule:   rule,
-				Status: core.Stopped,
-			})
+			session := core.ForwardSession{Rule: rule, Status: core.Stopped}
+			m.stops.Apply(rule.Name, &session)
+			sessions = append(sessions, session)
 		}
 	}
 	return sessions
 }
 
+// LoadStopHistory は前回のデーモンが保存した停止理由を引き継ぐ。
+func (m *forwardManager) LoadStopHistory(records []core.StopRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stops.Load(records)
+}
+
+// LoadSessionRecords は前回のデーモンが保存したセッションの開始時刻と再接続回数を引き継ぐ。
+func (m *forwardManager) LoadSessionRecords(records []core.SessionRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.resumed.Load(records)
+}
+
 // Subscribe はイベントチャネルを返す。
 func (m *forwardManager) Subscribe() <-chan core.ForwardEvent {
 	m.mu.Lock()
@@ -2,6 +2,9 @@ package forward
 
 import (
 	"context"
+	"errors"
+	"net"
+	"strconv"
 	"sync"
 	"testing"
 
@@ -9,57 +12,17 @@ import (
 	"github.com/ousiassllc/moleport/internal/core/forwardtest"
 )
 
-func TestForwardManager_GetRules_Order(t *testing.T) {
-	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager())
-	names := []string{"alpha", "beta", "gamma"}
-	for _, name := range names {
-		if _, err := fm.AddRule(core.ForwardRule{
-			Name: name, Host: "server1", Type: core.Dynamic, LocalPort: 1080,
-		}); err != nil {
-			t.Fatalf("AddRule(%q) error = %v", name, err)
-		}
-	}
-	rules := fm.GetRules()
-	if len(rules) != 3 {
-		t.Fatalf("len(rules) = %d, want 3", len(rules))
-	}
-	for i, name := range names {
-		if rules[i].Name != name {
-			t.Errorf("rules[%d].Name = %q, want %q", i, rules[i].Name, name)
-		}
-	}
-}
-
-func TestForwardManager_GetRulesByHost(t *testing.T) {
-	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager())
-	_, _ = fm.AddRule(core.ForwardRule{Name: "web1", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
-	_, _ = fm.AddRule(core.ForwardRule{Name: "web2", Host: "server2", Type: core.Dynamic, LocalPort: 1081})
-	_, _ = fm.AddRule(core.ForwardRule{Name: "web3", Host: "server1", Type: core.Dynamic, LocalPort: 1082})
-	rules := fm.GetRulesByHost("server1")
-	if len(rules) != 2 {
-		t.Fatalf("len(rules) = %d, want 2", len(rules))
-	}
-	if rules[0].Name != "web1" {
-		t.Errorf("rules[0].Name = %q, want %q", rules[0].Name, "web1")
-	}
-	if rules[1].Name != "web3" {
-		t.Errorf("rules[1].Name = %q, want %q", rules[1].Name, "web3")
-	}
-}
-
 func TestForwardManager_GetRulesByHost_Empty(t *testing.T) {
-	rules := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager()).GetRulesByHost("nonexistent")
+	rules := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil).GetRulesByHost("nonexistent")
 	if len(rules) != 0 {
 		t.Errorf("len(rules) = %d, want 0", len(rules))
 	}
 }
 
 func TestForwardManager_DeleteRule_Concurrent(t *testing.T) {
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", forwardtest.NewMockConn(false, true))
-	fm := NewForwardManager(context.Background(), sm)
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
 	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
-	_ = fm.StartForward("web", nil)
+	_ = fm.StartForward(t.Context(), "web", nil)
 	var wg sync.WaitGroup
 	wg.Add(2)
 	for range 2 {
@@ -74,36 +37,114 @@ func TestForwardManager_DeleteRule_Concurrent(t *testing.T) {
 	}
 }
 
-func TestForwardManager_AddRule_DefaultRemoteHost(t *testing.T) {
-	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager())
-	// Local タイプで RemoteHost を指定しない場合、"localhost" がデフォルトになる
-	_, err := fm.AddRule(core.ForwardRule{Name: "web-local", Host: "server1", Type: core.Local, LocalPort: 8080, RemotePort: 80})
-	if err != nil {
-		t.Fatalf("AddRule() error = %v", err)
-	}
-	rules := fm.GetRules()
-	if len(rules) != 1 {
-		t.Fatalf("len(rules) = %d, want 1", len(rules))
-	}
-	if rules[0].RemoteHost != "localhost" {
-		t.Errorf("RemoteHost = %q, want %q", rules[0].RemoteHost, "localhost")
-	}
-	// Remote タイプでも同様
-	_, err = fm.AddRule(core.ForwardRule{Name: "web-remote", Host: "server1", Type: core.Remote, LocalPort: 3000, RemotePort: 80})
-	if err != nil {
-		t.Fatalf("AddRule() error = %v", err)
-	}
-	rules = fm.GetRules()
-	if rules[1].RemoteHost != "localhost" {
-		t.Errorf("RemoteHost = %q, want %q", rules[1].RemoteHost, "localhost")
-	}
-	// Dynamic タイプでは RemoteHost はそのまま空
-	_, err = fm.AddRule(core.ForwardRule{Name: "socks", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
-	if err != nil {
-		t.Fatalf("AddRule() error = %v", err)
-	}
-	rules = fm.GetRules()
-	if rules[2].RemoteHost != "" {
-		t.Errorf("Dynamic RemoteHost = %q, want empty", rules[2].RemoteHost)
+func TestForwardManager_StartForward_PortConflict(t *testing.T) {
+	sm := forwardtest.NewMockSSHManager()
+	fm := NewForwardManager(context.Background(), sm, func(port int) bool { return port == 8080 }, nil, nil)
+	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemotePort: 80})
+	_, _ = fm.AddRule(core.ForwardRule{Name: "api", Host: "server1", Type: core.Local, LocalPort: 8081, RemotePort: 81})
+
+	var conflict *core.PortConflictError
+	if err := fm.StartForward(t.Context(), "web", nil); !errors.As(err, &conflict) {
+		t.Fatalf("StartForward() error = %v, want PortConflictError", err)
+	}
+	if conflict.Suggestion != 8082 {
+		t.Errorf("Suggestion = %d, want 8082 (8081 is reserved by api)", conflict.Suggestion)
+	}
+	if sm.IsConnected("server1") {
+		t.Error("StartForward() should not connect when the port is taken")
+	}
+	// 起動処理中のプレースホルダーが残っていれば AlreadyActiveError になる
+	if err := fm.StartForward(t.Context(), "web", nil); !errors.As(err, &conflict) {
+		t.Errorf("second StartForward() error = %v, want PortConflictError", err)
+	}
+}
+
+func TestForwardManager_CheckPort_ReportsActiveRule(t *testing.T) {
+	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil).(*forwardManager)
+	_, _ = fm.AddRule(core.ForwardRule{Name: "db", Host: "server1", Type: core.Local, LocalPort: 5432, RemotePort: 5432})
+	fm.active["db"] = &activeForward{starting: true}
+
+	var conflict *core.PortConflictError
+	if err := fm.CheckPort(5432); !errors.As(err, &conflict) || conflict.Rule != "db" {
+		t.Errorf("CheckPort(5432) = %v, want conflict with rule db", err)
+	}
+	if err := fm.CheckPort(5433); err != nil {
+		t.Errorf("CheckPort(5433) = %v, want nil", err)
+	}
+}
+
+func TestForwardManager_StartForward_AllocatesFreePort(t *testing.T) {
+	sm := forwardtest.NewMockSSHManager()
+	conn := forwardtest.NewMockConn(true, false)
+	conn.LocalForwardF = func(_ context.Context, port int, _, _ string) (net.Listener, error) {
+		return net.Listen("tcp", net.JoinHostPort(core.LocalhostAddr, strconv.Itoa(port)))
+	}
+	sm.SetConnected("server1", conn)
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil)
+	defer fm.Close()
+	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Local, RemotePort: 80})
+	events := fm.Subscribe()
+	if err := fm.StartForward(t.Context(), "web", nil); err != nil {
+		t.Fatalf("StartForward() error = %v", err)
+	}
+
+	ev := forwardtest.DrainEvent(t, events)
+	if ev.Type != core.ForwardEventStarted || ev.Session.BoundPort == 0 {
+		t.Fatalf("event = %v with bound port %d, want started with an allocated port", ev.Type, ev.Session.BoundPort)
+	}
+	if session, _ := fm.GetSession("web"); session.ListenPort() != ev.Session.BoundPort {
+		t.Errorf("ListenPort() = %d, want %d", session.ListenPort(), ev.Session.BoundPort)
+	}
+}
+
+func TestForwardManager_AddDeleteRule(t *testing.T) {
+	fm := NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil)
+	if name, err := fm.AddRule(forwardtest.WebRule()); err != nil || name != "web" {
+		t.Fatalf("AddRule() = %q, %v; want web", name, err)
+	}
+	if rules := fm.GetRules(); len(rules) != 1 || rules[0].Name != "web" || rules[0].Host != "server1" {
+		t.Errorf("GetRules() = %+v, want web on server1", rules)
+	}
+	if err := fm.DeleteRule("web"); err != nil || len(fm.GetRules()) != 0 {
+		t.Fatalf("DeleteRule() error = %v, rules = %+v", err, fm.GetRules())
+	}
+	if err := fm.DeleteRule("nonexistent"); err == nil {
+		t.Fatal("DeleteRule() should return error for nonexistent rule")
+	}
+}
+
+// newConnectedManager は server1 に conn で接続済みの forwardManager を返す。
+func newConnectedManager(conn core.SSHConnection) *forwardManager {
+	sm := forwardtest.NewMockSSHManager()
+	sm.SetConnected("server1", conn)
+	return NewForwardManager(context.Background(), sm, nil, nil, nil).(*forwardManager)
+}
+
+func TestForwardManager_SetLimit(t *testing.T) {
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
+	defer fm.Close()
+	_, _ = fm.AddRule(core.ForwardRule{Name: "socks", Host: "server1", Type: core.Dynamic, LocalPort: 1080, MaxUploadKbps: 64})
+	_ = fm.StartForward(t.Context(), "socks", nil)
+
+	var notFound *core.NotFoundError
+	if err := fm.SetLimit("missing", 1, 1); !errors.As(err, &notFound) {
+		t.Errorf("SetLimit(missing) = %v, want NotFoundError", err)
+	}
+	if err := fm.SetLimit("socks", -1, 0); err == nil {
+		t.Error("SetLimit() should reject a negative limit")
+	}
+	lim := fm.limits.Get("socks")
+	if err := fm.SetLimit("socks", 0, 512); err != nil {
+		t.Fatalf("SetLimit() error = %v", err)
+	}
+	if s, _ := fm.GetSession("socks"); s.Rule.MaxUploadKbps != 0 || s.Rule.MaxDownloadKbps != 512 {
+		t.Errorf("session limits = %d/%d, want 0/512", s.Rule.MaxUploadKbps, s.Rule.MaxDownloadKbps)
+	}
+	if fm.limits.Get("socks") != lim || lim.Upload.Rate() != 0 || lim.Download.Rate() != 512 {
+		t.Error("SetLimit() should update the shared limiter in place")
+	}
+	_ = fm.DeleteRule("socks")
+	if fm.limits.Get("socks") != nil {
+		t.Error("DeleteRule() should drop the limiter")
 	}
 }
@@ -0,0 +1,160 @@
+package forward
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/portcheck"
+	"github.com/ousiassllc/moleport/internal/core/forward/relay"
+	"github.com/ousiassllc/moleport/internal/core/forward/sshlink"
+)
+
+// MigrateForward はルールの接続先ホストを toHost に切り替える。
+// アクティブなセッションの切り替えは replaceRule と同じ。
+func (m *forwardManager) MigrateForward(ruleName, toHost string, cb core.CredentialCallback) (*core.ForwardSession, error) {
+	if toHost == "" {
+		return nil, fmt.Errorf("target host is required")
+	}
+
+	m.mu.RLock()
+	rule, exists := m.rules.Get(ruleName)
+	m.mu.RUnlock()
+	if !exists {
+		return nil, &core.NotFoundError{Resource: "rule", Name: ruleName}
+	}
+	if rule.Host == toHost {
+		return nil, fmt.Errorf("rule %s is already on host %s", ruleName, toHost)
+	}
+
+	rule.Host = toHost
+	old, session, err := m.replaceRule(rule, cb)
+	if err != nil {
+		return nil, err
+	}
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventMigrated, RuleName: ruleName, Session: session, FromHost: old.Host})
+	slog.Info("forward migrated", "rule", ruleName, "from", old.Host, "to", toHost)
+	return session, nil
+}
+
+// UpdateRule は同名の既存ルールを rule の内容に置き換える。アクティブなセッションの再開は replaceRule と同じ。
+func (m *forwardManager) UpdateRule(rule core.ForwardRule, cb core.CredentialCallback) (*core.ForwardSession, error) {
+	_, session, err := m.replaceRule(rule, cb)
+	if err != nil {
+		return nil, err
+	}
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventUpdated, RuleName: rule.Name, Session: session})
+	slog.Info("forward updated", "rule", rule.Name, "status", session.Status)
+	return session, nil
+}
+
+// replaceRule は同名の既存ルールを rule で置き換え、置き換える前のルールと現在のセッション情報を返す。
+// 停止中のルールは書き換えのみ行う。アクティブなセッションは新しい接続先への SSH 接続を先に確立してから
+// 旧セッションを停止して再開し、セッション ID・接続開始時刻・転送量カウンタ・再接続回数を引き継ぐ。
+// 再開できなかった場合はルールを元に戻し、セッションは停止したままにする。
+func (m *forwardManager) replaceRule(rule core.ForwardRule, cb core.CredentialCallback) (core.ForwardRule, *core.ForwardSession, error) {
+	m.mu.Lock()
+	old, err := m.rules.Update(rule)
+	if err != nil {
+		m.mu.Unlock()
+		return core.ForwardRule{}, nil, err
+	}
+	rule, _ = m.rules.Get(rule.Name)
+	ruleName := rule.Name
+
+	af, active := m.active[ruleName]
+	if !active {
+		// 停止中のルールは書き換えるだけ
+		m.limits.Set(ruleName, rule.MaxUploadKbps, rule.MaxDownloadKbps)
+		session := core.ForwardSession{Rule: rule, Status: core.Stopped}
+		m.stops.Apply(ruleName, &session)
+		m.mu.Unlock()
+		return old, &session, nil
+	}
+	fail := func(err error) (core.ForwardRule, *core.ForwardSession, error) {
+		m.rules.Replace(old)
+		m.mu.Unlock()
+		return core.ForwardRule{}, nil, err
+	}
+	if af.starting {
+		return fail(fmt.Errorf("forward %s is starting", ruleName))
+	}
+	if rule.Type != core.Remote && rule.LocalPort != 0 && rule.LocalPort != old.LocalPort {
+		active, reserved := m.portOwners(ruleName)
+		if err := portcheck.Check(rule.LocalPort, active, reserved, m.portInUse); err != nil {
+			return fail(err)
+		}
+	}
+	m.mu.Unlock()
+
+	sshConn, sshClient, err := sshlink.Open(m.ctx, m.sshManager, rule, cb)
+	if err != nil {
+		m.restoreRule(ruleName, old)
+		return core.ForwardRule{}, nil, err
+	}
+
+	// 旧セッションを停止して新しいルールで再開する。
+	// ローカルポートを再利用するため、新しいリスナーを開く前に旧リスナーを閉じる必要がある
+	m.mu.Lock()
+	if current, ok := m.active[ruleName]; !ok || current != af {
+		m.rules.Replace(old)
+		m.mu.Unlock()
+		return core.ForwardRule{}, nil, fmt.Errorf("forward %s changed during update", ruleName)
+	}
+	// 再開に成功した場合は新しいセッションに置き換わるため、停止理由は失敗時のロールバックにのみ現れる
+	prev := m.stopForwardLocked(ruleName, core.StopReasonError)
+	m.limits.Set(ruleName, rule.MaxUploadKbps, rule.MaxDownloadKbps)
+	m.active[ruleName] = &activeForward{starting: true}
+	m.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(m.ctx)
+	listener, err := relay.Listen(ctx, sshConn, rule)
+	if err != nil {
+		cancel()
+		m.rollbackReplace(ruleName, old, prev)
+		return core.ForwardRule{}, nil, fmt.Errorf("failed to restart forward on host %s: %w", rule.Host, err)
+	}
+
+	newAF := newActiveForward(ctx, cancel, listener, rule, core.ForwardSession{
+		ID: prev.ID, ConnectedAt: prev.ConnectedAt, ExpiresAt: prev.ExpiresAt,
+		BytesSent: prev.BytesSent, BytesReceived: prev.BytesReceived, ReconnectCount: prev.ReconnectCount,
+	})
+
+	m.mu.Lock()
+	// 再開中に StopForward や DeleteRule でプレースホルダーが取り除かれていないか再確認
+	if placeholder, ok := m.active[ruleName]; !ok || !placeholder.starting {
+		m.mu.Unlock()
+		cancel()
+		_ = listener.Close()
+		return core.ForwardRule{}, nil, fmt.Errorf("forward %s was stopped during update", ruleName)
+	}
+	m.active[ruleName] = newAF
+	session := newAF.session
+	m.mu.Unlock()
+
+	go m.acceptLoop(newAF, rule, sshClient)
+	return old, &session, nil
+}
+
+// restoreRule は置き換えたルールを old に戻す。
+func (m *forwardManager) restoreRule(ruleName string, old core.ForwardRule) {
+	m.mu.Lock()
+	if _, ok := m.rules.Get(ruleName); ok {
+		m.rules.Replace(old)
+		m.limits.Set(ruleName, old.MaxUploadKbps, old.MaxDownloadKbps)
+	}
+	m.mu.Unlock()
+}
+
+// rollbackReplace は新しいルールでの再開に失敗したルールを old に戻し、停止イベントを発行する。
+func (m *forwardManager) rollbackReplace(ruleName string, old core.ForwardRule, prev *core.ForwardSession) {
+	m.restoreRule(ruleName, old)
+	m.mu.Lock()
+	if af, ok := m.active[ruleName]; ok && af.starting {
+		delete(m.active, ruleName)
+	}
+	m.mu.Unlock()
+
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventStopped, RuleName: ruleName, Session: prev})
+}
@@ -0,0 +1,110 @@
+package forward
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+)
+
+func newMigrateManager(t *testing.T, target *forwardtest.MockSSHConnection) (*forwardManager, <-chan core.ForwardEvent) {
+	t.Helper()
+	sm := forwardtest.NewMockSSHManager()
+	sm.SetConnected("bastion1", forwardtest.NewMockConn(true, false))
+	sm.SetConnected("bastion2", target)
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil).(*forwardManager)
+	t.Cleanup(fm.Close)
+	_, _ = fm.AddRule(core.ForwardRule{
+		Name: "db", Host: "bastion1", Type: core.Local, LocalPort: 15432, RemoteHost: "db", RemotePort: 5432,
+	})
+	return fm, fm.Subscribe()
+}
+
+func TestForwardManager_MigrateForward_PreservesSession(t *testing.T) {
+	fm, events := newMigrateManager(t, forwardtest.NewMockConn(true, false))
+	if err := fm.StartForward(t.Context(), "db", nil); err != nil {
+		t.Fatalf("StartForward() error = %v", err)
+	}
+	forwardtest.DrainEvent(t, events)
+	before, _ := fm.GetSession("db")
+	fm.active["db"].sent.Store(100)
+	fm.active["db"].received.Store(200)
+
+	session, err := fm.MigrateForward("db", "bastion2", nil)
+	if err != nil {
+		t.Fatalf("MigrateForward() error = %v", err)
+	}
+	if session.ID != before.ID || session.Rule.Host != "bastion2" || session.Status != core.Active {
+		t.Errorf("session = %+v, want same ID on bastion2", session)
+	}
+	ev := forwardtest.DrainEvent(t, events)
+	if ev.Type != core.ForwardEventMigrated || ev.FromHost != "bastion1" {
+		t.Errorf("event = %+v, want Migrated from bastion1", ev)
+	}
+
+	got, _ := fm.GetSession("db")
+	if got.BytesSent != 100 || got.BytesReceived != 200 {
+		t.Errorf("counters = %d/%d, want 100/200", got.BytesSent, got.BytesReceived)
+	}
+	if rules := fm.GetRulesByHost("bastion2"); len(rules) != 1 {
+		t.Errorf("rules on bastion2 = %+v, want rewritten rule", rules)
+	}
+}
+
+func TestForwardManager_MigrateForward_StoppedRule(t *testing.T) {
+	fm, events := newMigrateManager(t, forwardtest.NewMockConn(true, false))
+
+	session, err := fm.MigrateForward("db", "bastion2", nil)
+	if err != nil {
+		t.Fatalf("MigrateForward() error = %v", err)
+	}
+	if session.Status != core.Stopped || session.Rule.Host != "bastion2" {
+		t.Errorf("session = %+v, want stopped rule on bastion2", session)
+	}
+	if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventMigrated {
+		t.Errorf("event type = %v, want Migrated", ev.Type)
+	}
+}
+
+func TestForwardManager_MigrateForward_RollbackOnListenError(t *testing.T) {
+	fm, events := newMigrateManager(t, forwardtest.NewMockConn(false, false))
+	if err := fm.StartForward(t.Context(), "db", nil); err != nil {
+		t.Fatalf("StartForward() error = %v", err)
+	}
+	forwardtest.DrainEvent(t, events)
+
+	if _, err := fm.MigrateForward("db", "bastion2", nil); err == nil {
+		t.Fatal("MigrateForward() should fail when the target cannot listen")
+	}
+	if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventStopped {
+		t.Errorf("event type = %v, want Stopped", ev.Type)
+	}
+	if rules := fm.GetRulesByHost("bastion1"); len(rules) != 1 {
+		t.Errorf("rule should be rolled back to bastion1, got %+v", fm.GetRules())
+	}
+}
+
+func TestForwardManager_MigrateForward_SameHost(t *testing.T) {
+	fm, _ := newMigrateManager(t, forwardtest.NewMockConn(true, false))
+	if _, err := fm.MigrateForward("db", "bastion1", nil); err == nil {
+		t.Fatal("MigrateForward() to the same host should fail")
+	}
+}
+
+func TestForwardManager_UpdateRule_RestartsActive(t *testing.T) {
+	fm, events := newMigrateManager(t, forwardtest.NewMockConn(true, false))
+	_ = fm.StartForward(t.Context(), "db", nil)
+	forwardtest.DrainEvent(t, events)
+	before, _ := fm.GetSession("db")
+
+	rule := before.Rule
+	rule.RemotePort = 5433
+	session, err := fm.UpdateRule(rule, nil)
+	if err != nil || session.ID != before.ID || session.Status != core.Active || session.Rule.RemotePort != 5433 {
+		t.Fatalf("UpdateRule() = %+v, %v; want the restarted session with the same ID", session, err)
+	}
+	if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventUpdated {
+		t.Errorf("event type = %v, want Updated", ev.Type)
+	}
+}
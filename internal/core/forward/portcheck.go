@@ -0,0 +1,23 @@
+package forward
+
+import "github.com/ousiassllc/moleport/internal/core/forward/portcheck"
+
+// CheckPort はローカルポートが他のルールや他のプロセスで使用されていないかを確認する。
+func (m *forwardManager) CheckPort(port int) error {
+	m.mu.RLock()
+	active, reserved := m.portOwners("")
+	m.mu.RUnlock()
+	return portcheck.Check(port, active, reserved, m.portInUse)
+}
+
+// portOwners は self 以外のルールが使用するローカルポートを portcheck.Owners で返す。
+// 呼び出し元は m.mu を保持していること。
+func (m *forwardManager) portOwners(self string) (map[int]string, map[int]bool) {
+	return portcheck.Owners(m.rules.All(), self, func(name string) (int, bool) {
+		af, ok := m.active[name]
+		if !ok {
+			return 0, false
+		}
+		return af.session.BoundPort, true
+	})
+}
@@ -0,0 +1,2 @@
+// Package portcheck はフォワード開始前のローカルポートの競合確認と空きポートの提案を提供する。
+package portcheck
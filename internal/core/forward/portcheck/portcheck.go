@@ -0,0 +1,54 @@
+package portcheck
+
+import "github.com/ousiassllc/moleport/internal/core"
+
+// suggestRange は空きポートを探す範囲。要求されたポートの次から数える。
+const suggestRange = 100
+
+// Prober はローカルポートが他のプロセスに使用されているかを返す。
+type Prober func(port int) bool
+
+// Check は port が使用可能かを確認する。
+// active は起動中の他のルールが使用しているポートとそのルール名、reserved は停止中を含む他のルールに割り当て済みのポート。
+// inUse が nil の場合は他のプロセスによる使用を確認しない。
+func Check(port int, active map[int]string, reserved map[int]bool, inUse Prober) error {
+	owner, taken := active[port]
+	if !taken && (inUse == nil || !inUse(port)) {
+		return nil
+	}
+	return &core.PortConflictError{Port: port, Rule: owner, Suggestion: Suggest(port, reserved, inUse)}
+}
+
+// Owners は rules のうち self 以外のルールが使用するローカルポートを返す。
+// active は起動中（起動処理中を含む）のルールが Listen するポートとルール名、reserved は全ルールに割り当て済みのポート。
+// bound はルールが起動中かと Listen しているポートを返す。ポートが 0 の場合はルールの LocalPort を使う。
+func Owners(rules []core.ForwardRule, self string, bound func(name string) (int, bool)) (map[int]string, map[int]bool) {
+	active := make(map[int]string)
+	reserved := make(map[int]bool)
+	for _, rule := range rules {
+		if rule.Name == self || rule.Type == core.Remote {
+			continue
+		}
+		reserved[rule.LocalPort] = true
+		if port, ok := bound(rule.Name); ok {
+			if port == 0 {
+				port = rule.LocalPort
+			}
+			active[port] = rule.Name
+		}
+	}
+	delete(active, 0)
+	return active, reserved
+}
+
+// Suggest は port の次から順に、他のルールに割り当てられておらず使用されていないポートを探す。
+// 見つからない場合は 0 を返す。
+func Suggest(port int, reserved map[int]bool, inUse Prober) int {
+	for p := port + 1; p <= min(port+suggestRange, 65535); p++ {
+		if reserved[p] || (inUse != nil && inUse(p)) {
+			continue
+		}
+		return p
+	}
+	return 0
+}
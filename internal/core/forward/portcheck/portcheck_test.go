@@ -0,0 +1,70 @@
+package portcheck
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestCheck(t *testing.T) {
+	busy := map[int]bool{8080: true, 8082: true}
+	inUse := func(port int) bool { return busy[port] }
+
+	tests := []struct {
+		name     string
+		port     int
+		active   map[int]string
+		reserved map[int]bool
+		want     *core.PortConflictError
+	}{
+		{"free", 9000, nil, nil, nil},
+		{"other process", 8080, nil, map[int]bool{8081: true}, &core.PortConflictError{Port: 8080, Suggestion: 8083}},
+		{"other rule", 9000, map[int]string{9000: "db"}, map[int]bool{9000: true}, &core.PortConflictError{Port: 9000, Rule: "db", Suggestion: 9001}},
+		{"no suggestion at the top of the range", 65535, map[int]string{65535: "db"}, nil, &core.PortConflictError{Port: 65535, Rule: "db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.port, tt.active, tt.reserved, inUse)
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Check() = %v, want nil", err)
+				}
+				return
+			}
+			var conflict *core.PortConflictError
+			if !errors.As(err, &conflict) || *conflict != *tt.want {
+				t.Errorf("Check() = %#v, want %#v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_NilProberOnlyChecksRules(t *testing.T) {
+	if err := Check(8080, nil, nil, nil); err != nil {
+		t.Errorf("Check() = %v, want nil without a prober", err)
+	}
+}
+
+func TestOwners(t *testing.T) {
+	rules := []core.ForwardRule{
+		{Name: "web", Type: core.Local, LocalPort: 8080},
+		{Name: "db", Type: core.Local, LocalPort: 5432},
+		{Name: "auto", Type: core.Dynamic},
+		{Name: "rev", Type: core.Remote, LocalPort: 3000},
+		{Name: "self", Type: core.Local, LocalPort: 9000},
+	}
+	bound := map[string]int{"web": 0, "auto": 40000, "rev": 3000, "self": 9000}
+	active, reserved := Owners(rules, "self", func(name string) (int, bool) {
+		port, ok := bound[name]
+		return port, ok
+	})
+
+	if !reflect.DeepEqual(active, map[int]string{8080: "web", 40000: "auto"}) {
+		t.Errorf("active = %v, want web on 8080 and auto on 40000", active)
+	}
+	if !reflect.DeepEqual(reserved, map[int]bool{8080: true, 5432: true, 0: true}) {
+		t.Errorf("reserved = %v, want 8080, 5432 and 0", reserved)
+	}
+}
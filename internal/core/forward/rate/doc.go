@@ -0,0 +1,2 @@
+// Package rate はセッションごとの累計転送量を定期的に記録し、直近の送受信レートとその推移を算出する。
+package rate
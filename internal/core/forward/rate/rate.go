@@ -0,0 +1,118 @@
+package rate
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Interval は転送量のサンプリング間隔。
+const Interval = time.Second
+
+// Window はレートの算出に使うサンプリング区間の数。直近 Window 区間の平均レートを返す。
+const Window = 5
+
+// HistoryLen はセッションごとに保持するレートの推移の件数。
+const HistoryLen = 30
+
+// Stats はセッションの送受信レート（バイト/秒）と、区間ごとの送受信合計レートの推移（古い順）を表す。
+type Stats struct {
+	Send    float64
+	Receive float64
+	History []float64
+}
+
+// Counter は 1 回のサンプリングで観測したセッションの累計送受信バイト数。
+type Counter struct {
+	ID       string
+	Sent     int64
+	Received int64
+}
+
+type sample struct {
+	at             time.Time
+	sent, received int64
+}
+
+// series は 1 セッション分の直近のサンプルとレートの推移。
+type series struct {
+	samples []sample
+	history []float64
+}
+
+// Sampler はセッション ID ごとの転送量のサンプルを保持する。ゼロ値では使えないため New で生成する。
+type Sampler struct {
+	mu     sync.Mutex
+	series map[string]*series
+}
+
+// New は空の Sampler を返す。
+func New() *Sampler {
+	return &Sampler{series: make(map[string]*series)}
+}
+
+// Run は interval ごとに collect で稼働中のセッションの転送量を取得して記録する。
+// ctx がキャンセルされるまでブロックする。
+func (s *Sampler) Run(ctx context.Context, interval time.Duration, collect func() []Counter) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			s.Record(now, collect())
+		}
+	}
+}
+
+// Record は at 時点の転送量を記録する。counters に含まれないセッション（停止済み）の記録は破棄する。
+// 再接続などでカウンターが巻き戻った区間はレート 0 として扱う。
+func (s *Sampler) Record(at time.Time, counters []Counter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(counters))
+	for _, c := range counters {
+		seen[c.ID] = true
+		sr, ok := s.series[c.ID]
+		if !ok {
+			sr = &series{}
+			s.series[c.ID] = sr
+		}
+		if n := len(sr.samples); n > 0 {
+			prev := sr.samples[n-1]
+			if sec := at.Sub(prev.at).Seconds(); sec > 0 {
+				delta := max(c.Sent-prev.sent, 0) + max(c.Received-prev.received, 0)
+				sr.history = append(sr.history, float64(delta)/sec)
+				sr.history = sr.history[max(len(sr.history)-HistoryLen, 0):]
+			}
+		}
+		sr.samples = append(sr.samples, sample{at: at, sent: c.Sent, received: c.Received})
+		sr.samples = sr.samples[max(len(sr.samples)-(Window+1), 0):]
+	}
+	for id := range s.series {
+		if !seen[id] {
+			delete(s.series, id)
+		}
+	}
+}
+
+// Stats は id のセッションの直近 Window 区間の平均レートと推移を返す。サンプルが 2 件未満の場合、レートは 0。
+func (s *Sampler) Stats(id string) Stats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sr, ok := s.series[id]
+	if !ok {
+		return Stats{}
+	}
+	st := Stats{History: slices.Clone(sr.history)}
+	first, last := sr.samples[0], sr.samples[len(sr.samples)-1]
+	if sec := last.at.Sub(first.at).Seconds(); sec > 0 {
+		st.Send = float64(max(last.sent-first.sent, 0)) / sec
+		st.Receive = float64(max(last.received-first.received, 0)) / sec
+	}
+	return st
+}
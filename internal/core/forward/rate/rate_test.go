@@ -0,0 +1,75 @@
+package rate
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestSampler_Record(t *testing.T) {
+	s := New()
+	t0 := time.Unix(1000, 0)
+
+	if st := s.Stats("a"); st.Send != 0 || st.History != nil {
+		t.Errorf("Stats(unknown) = %+v, want zero", st)
+	}
+	s.Record(t0, []Counter{{ID: "a", Sent: 100, Received: 1000}})
+	if st := s.Stats("a"); st.Send != 0 || st.Receive != 0 {
+		t.Errorf("Stats after one sample = %+v, want zero rates", st)
+	}
+	s.Record(t0.Add(time.Second), []Counter{{ID: "a", Sent: 300, Received: 1000}, {ID: "b"}})
+	s.Record(t0.Add(2*time.Second), []Counter{{ID: "a", Sent: 500, Received: 1400}, {ID: "b"}})
+
+	st := s.Stats("a")
+	if st.Send != 200 || st.Receive != 200 {
+		t.Errorf("rates = %v/%v, want 200/200", st.Send, st.Receive)
+	}
+	if want := []float64{200, 600}; !slices.Equal(st.History, want) {
+		t.Errorf("History = %v, want %v", st.History, want)
+	}
+
+	// カウンターの巻き戻りは負のレートにしない。含まれないセッションは破棄する
+	s.Record(t0.Add(3*time.Second), []Counter{{ID: "a", Sent: 0, Received: 0}})
+	if st := s.Stats("a"); st.History[len(st.History)-1] != 0 {
+		t.Errorf("History = %v, want trailing 0 after reset", st.History)
+	}
+	if _, ok := s.series["b"]; ok {
+		t.Error("Record() should drop series of stopped sessions")
+	}
+}
+
+func TestSampler_WindowAndHistoryBounded(t *testing.T) {
+	s := New()
+	t0 := time.Unix(0, 0)
+	for i := range HistoryLen + 10 {
+		s.Record(t0.Add(time.Duration(i)*time.Second), []Counter{{ID: "a", Sent: int64(i * 10)}})
+	}
+	st := s.Stats("a")
+	if len(st.History) != HistoryLen || len(s.series["a"].samples) != Window+1 {
+		t.Errorf("history = %d, samples = %d; want %d, %d", len(st.History), len(s.series["a"].samples), HistoryLen, Window+1)
+	}
+	if st.Send != 10 {
+		t.Errorf("Send = %v, want 10", st.Send)
+	}
+}
+
+func TestSampler_Run(t *testing.T) {
+	s := New()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	calls := make(chan struct{}, 8)
+	go func() {
+		s.Run(ctx, time.Millisecond, func() []Counter {
+			select {
+			case calls <- struct{}{}:
+			default:
+			}
+			return nil
+		})
+		close(done)
+	}()
+	<-calls
+	cancel()
+	<-done
+}
@@ -0,0 +1,31 @@
+package forward
+
+import (
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/rate"
+)
+
+// counters は稼働中のセッションの累計転送量を返す。rate.Sampler のサンプリングで使う。
+func (m *forwardManager) counters() []rate.Counter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	out := make([]rate.Counter, 0, len(m.active))
+	for _, af := range m.active {
+		if !af.starting {
+			out = append(out, rate.Counter{ID: af.session.ID, Sent: af.sent.Load(), Received: af.received.Load()})
+		}
+	}
+	return out
+}
+
+// sessionOf は af のセッション情報に現在の転送量・レート・接続数を反映して返す。m.mu を保持して呼び出す。
+func (m *forwardManager) sessionOf(af *activeForward) core.ForwardSession {
+	session := af.session
+	session.BytesSent = af.sent.Load()
+	session.BytesReceived = af.received.Load()
+	session.ActiveConns, session.Connections = af.inflight.Len(), af.inflight.Conns()
+	st := m.rates.Stats(session.ID)
+	session.SendRate, session.ReceiveRate, session.RateHistory = st.Send, st.Receive, st.History
+	return session
+}
@@ -4,101 +4,116 @@ import (
 	"context"
 	"errors"
 	"log/slog"
-	"net"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/relay"
+	"github.com/ousiassllc/moleport/internal/core/forward/sshlink"
 )
 
+// errRestoreAborted は復元中にフォワードが停止または置き換えられた場合のエラー。
+var errRestoreAborted = errors.New("forward was stopped during restoration")
+
 // MarkReconnecting は当該ホストのアクティブセッションを SessionReconnecting 状態にする。
 func (m *forwardManager) MarkReconnecting(hostName string) {
+	m.markReconnecting(hostName, func(core.ForwardRule) bool { return true })
+}
+
+// markReconnecting は当該ホストのアクティブセッションのうち match を満たすものを SessionReconnecting 状態にする。
+func (m *forwardManager) markReconnecting(hostName string, match func(core.ForwardRule) bool) {
 	var events []core.ForwardEvent
 
 	m.mu.Lock()
 	for _, af := range m.active {
-		if af.starting {
-			continue
-		}
-		if af.session.Rule.Host == hostName && af.session.Status == core.Active {
-			_ = af.listener.Close()
-			af.cancel()
-			af.session.Status = core.SessionReconnecting
-			af.session.BytesSent = af.sent.Load()
-			af.session.BytesReceived = af.received.Load()
-			session := af.session
-			events = append(events, core.ForwardEvent{
-				Type:     core.ForwardEventReconnecting,
-				RuleName: af.session.Rule.Name,
-				Session:  &session,
-			})
+		if !af.starting && af.session.Rule.Host == hostName && af.session.Status == core.Active && match(af.session.Rule) {
+			events = append(events, m.suspendLocked(af, nil))
 		}
 	}
 	m.mu.Unlock()
 
-	for _, evt := range events {
-		m.events.Emit(evt)
+	m.events.Emit(events...)
+}
+
+// suspendLocked はリスナーを閉じてセッションを SessionReconnecting 状態にし、発行する ForwardEventReconnecting を返す。
+// cause が非 nil の場合は LastError に記録する。呼び出し元が m.mu.Lock() を保持していること。
+func (m *forwardManager) suspendLocked(af *activeForward, cause error) core.ForwardEvent {
+	_ = af.listener.Close()
+	af.cancel()
+	af.session.Status = core.SessionReconnecting
+	af.session.BytesSent = af.sent.Load()
+	af.session.BytesReceived = af.received.Load()
+	if cause != nil {
+		af.session.LastError = cause.Error()
 	}
+	session := af.session
+	return core.ForwardEvent{Type: core.ForwardEventReconnecting, RuleName: session.Rule.Name, Session: &session, Error: cause}
 }
 
-// RestoreForwards は SSH 再接続後に SessionReconnecting 状態の全フォワードを復元する。
+// RestoreForwards は SSH 接続の確立後に SessionReconnecting 状態の全フォワードを復元する。
+// AutoReconnect が有効なルールは SessionError 状態からも再接続中に戻して復元する。
 func (m *forwardManager) RestoreForwards(hostName string) []core.ForwardRestoreResult {
-	// SessionReconnecting 状態のフォワードを収集
-	m.mu.RLock()
 	var targets []*activeForward
+	var events []core.ForwardEvent
+
+	m.mu.Lock()
 	for _, af := range m.active {
-		if af.starting {
+		if af.starting || af.session.Rule.Host != hostName {
 			continue
 		}
-		if af.session.Rule.Host == hostName && af.session.Status == core.SessionReconnecting {
+		switch {
+		case af.session.Status == core.SessionReconnecting:
+			targets = append(targets, af)
+		case af.session.Status == core.SessionError && af.session.Rule.AutoReconnect:
+			events = append(events, m.suspendLocked(af, nil))
 			targets = append(targets, af)
 		}
 	}
-	m.mu.RUnlock()
+	m.mu.Unlock()
 
+	m.events.Emit(events...)
 	if len(targets) == 0 {
 		return nil
 	}
 
 	// 新しい SSH 接続を取得
-	sshConn, sshConnErr := m.sshManager.GetSSHConnection(hostName)
-	sshClient, sshClientErr := m.sshManager.GetConnection(hostName)
+	sshConn, sshClient, connErr := sshlink.Get(m.sshManager, hostName)
 
 	results := make([]core.ForwardRestoreResult, 0, len(targets))
 	for _, af := range targets {
-		result := m.restoreSingleForward(af, sshConn, sshConnErr, sshClient, sshClientErr)
-		results = append(results, result)
+		results = append(results, m.restoreSingleForward(af, sshConn, sshClient, connErr))
 	}
 	return results
 }
 
 // restoreSingleForward は単一のフォワードを復元する。
-func (m *forwardManager) restoreSingleForward(
-	af *activeForward,
-	sshConn core.SSHConnection,
-	sshConnErr error,
-	sshClient interface {
-		Dial(n, addr string) (net.Conn, error)
-	},
-	sshClientErr error,
-) core.ForwardRestoreResult {
+// connErr が非 nil の場合は SSH 接続を取得できなかったものとしてエラー状態にする。
+func (m *forwardManager) restoreSingleForward(af *activeForward, sshConn core.SSHConnection, sshClient relay.Dialer, connErr error) core.ForwardRestoreResult {
 	rule := af.session.Rule
 
-	if sshConnErr != nil {
-		m.setForwardError(af, sshConnErr.Error())
-		return core.ForwardRestoreResult{RuleName: rule.Name, OK: false, Error: sshConnErr.Error()}
+	if connErr != nil {
+		m.setForwardError(af, core.StopReasonError, connErr.Error())
+		return core.ForwardRestoreResult{RuleName: rule.Name, OK: false, Error: connErr.Error()}
 	}
-	if sshClientErr != nil {
-		m.setForwardError(af, sshClientErr.Error())
-		return core.ForwardRestoreResult{RuleName: rule.Name, OK: false, Error: sshClientErr.Error()}
+
+	if err := m.reopen(af, sshConn, sshClient); err != nil {
+		if !errors.Is(err, errRestoreAborted) {
+			m.setForwardError(af, core.StopReasonError, err.Error())
+		}
+		return core.ForwardRestoreResult{RuleName: rule.Name, OK: false, Error: err.Error()}
 	}
+	return core.ForwardRestoreResult{RuleName: rule.Name, OK: true}
+}
 
+// reopen は新しいリスナーを作成し、SessionReconnecting 状態の af を再接続回数を加算したアクティブなセッションに
+// 置き換えて ForwardEventRestored を発行する。af が停止または置き換えられていた場合は errRestoreAborted を返す。
+func (m *forwardManager) reopen(af *activeForward, sshConn core.SSHConnection, sshClient relay.Dialer) error {
+	rule := af.session.Rule
 	ctx, cancel := context.WithCancel(m.ctx)
 
-	listener, err := openListener(ctx, sshConn, rule)
-
+	listener, err := relay.Listen(ctx, sshConn, rule)
 	if err != nil {
 		cancel()
-		m.setForwardError(af, err.Error())
-		return core.ForwardRestoreResult{RuleName: rule.Name, OK: false, Error: err.Error()}
+		return err
 	}
 
 	// 成功: 新しい activeForward を作成して置き換え（旧 acceptLoop とのデータレースを回避）
@@ -108,53 +123,36 @@ func (m *forwardManager) restoreSingleForward(
 		m.mu.Unlock()
 		cancel()
 		_ = listener.Close()
-		return core.ForwardRestoreResult{RuleName: rule.Name, OK: false, Error: "forward was stopped during restoration"}
+		return errRestoreAborted
 	}
 
-	newAF := &activeForward{
-		session: core.ForwardSession{
-			ID:             af.session.ID,
-			Rule:           rule,
-			Status:         core.Active,
-			ConnectedAt:    af.session.ConnectedAt,
-			BytesSent:      af.sent.Load(),
-			BytesReceived:  af.received.Load(),
-			ReconnectCount: af.session.ReconnectCount + 1,
-		},
-		listener: listener,
-		ctx:      ctx,
-		cancel:   cancel,
-	}
+	newAF := newActiveForward(ctx, cancel, listener, rule, core.ForwardSession{
+		ID: af.session.ID, ConnectedAt: af.session.ConnectedAt, ExpiresAt: af.session.ExpiresAt,
+		BytesSent: af.sent.Load(), BytesReceived: af.received.Load(), ReconnectCount: af.session.ReconnectCount + 1,
+	})
 	m.active[rule.Name] = newAF
 	session := newAF.session
 	m.mu.Unlock()
 
 	go m.acceptLoop(newAF, rule, sshClient)
 
-	m.events.Emit(core.ForwardEvent{
-		Type:     core.ForwardEventRestored,
-		RuleName: rule.Name,
-		Session:  &session,
-	})
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventRestored, RuleName: rule.Name, Session: &session})
 
 	slog.Info("forward restored", "rule", rule.Name, "reconnect_count", session.ReconnectCount)
-	return core.ForwardRestoreResult{RuleName: rule.Name, OK: true}
+	return nil
 }
 
-// setForwardError はフォワードを SessionError 状態にし、ForwardEventError を発行する。
-func (m *forwardManager) setForwardError(af *activeForward, errMsg string) {
+// setForwardError はフォワードを reason による SessionError 状態にし、ForwardEventError を発行する。
+func (m *forwardManager) setForwardError(af *activeForward, reason core.StopReason, errMsg string) {
 	m.mu.Lock()
 	af.session.Status = core.SessionError
 	af.session.LastError = errMsg
+	af.session.StoppedReason = reason
+	af.session.StoppedAt = time.Now()
 	session := af.session
 	m.mu.Unlock()
 
-	m.events.Emit(core.ForwardEvent{
-		Type:     core.ForwardEventError,
-		RuleName: session.Rule.Name,
-		Session:  &session,
-		Error:    errors.New(errMsg),
-	})
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventError, RuleName: session.Rule.Name, Session: &session, Error: errors.New(errMsg)})
 }
 
 // FailReconnecting は再接続失敗時に SessionReconnecting 状態のフォワードを Error 状態にする。
@@ -163,24 +161,16 @@ func (m *forwardManager) FailReconnecting(hostName string) {
 
 	m.mu.Lock()
 	for _, af := range m.active {
-		if af.starting {
-			continue
-		}
-		if af.session.Rule.Host == hostName && af.session.Status == core.SessionReconnecting {
+		if !af.starting && af.session.Rule.Host == hostName && af.session.Status == core.SessionReconnecting {
 			af.session.Status = core.SessionError
 			af.session.LastError = "reconnection failed"
+			af.session.StoppedReason = core.StopReasonSSHLost
+			af.session.StoppedAt = time.Now()
 			session := af.session
-			events = append(events, core.ForwardEvent{
-				Type:     core.ForwardEventError,
-				RuleName: session.Rule.Name,
-				Session:  &session,
-				Error:    errors.New("reconnection failed"),
-			})
+			events = append(events, core.ForwardEvent{Type: core.ForwardEventError, RuleName: session.Rule.Name, Session: &session, Error: errors.New(session.LastError)})
 		}
 	}
 	m.mu.Unlock()
 
-	for _, evt := range events {
-		m.events.Emit(evt)
-	}
+	m.events.Emit(events...)
 }
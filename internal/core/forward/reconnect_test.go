@@ -10,18 +10,12 @@ import (
 	"github.com/ousiassllc/moleport/internal/core/forwardtest"
 )
 
-// setupReconnectTest creates a ForwardManager with a single local rule on "server1",
-// starts the forward, subscribes to events, and marks the host as reconnecting.
-// It drains the reconnecting event before returning.
+// setupReconnectTest は server1 の "web" を開始して再接続中にし、再接続イベントを読み捨てたイベントチャネルを返す。
 func setupReconnectTest(t *testing.T, mockConn *forwardtest.MockSSHConnection) (core.ForwardManager, <-chan core.ForwardEvent) {
 	t.Helper()
-	sm := forwardtest.NewMockSSHManager()
-	sm.SetConnected("server1", mockConn)
-	fm := NewForwardManager(context.Background(), sm)
-	_, _ = fm.AddRule(core.ForwardRule{
-		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
-	})
-	_ = fm.StartForward("web", nil)
+	fm := newConnectedManager(mockConn)
+	_, _ = fm.AddRule(forwardtest.WebRule())
+	_ = fm.StartForward(t.Context(), "web", nil)
 	events := fm.Subscribe()
 	fm.MarkReconnecting("server1")
 	forwardtest.DrainEvent(t, events)
@@ -33,15 +27,13 @@ func TestForwardManager_MarkReconnecting(t *testing.T) {
 	mockConn := forwardtest.NewMockConn(true, true)
 	sm.SetConnected("server1", mockConn)
 	sm.SetConnected("server2", mockConn)
-	fm := NewForwardManager(context.Background(), sm)
-	_, _ = fm.AddRule(core.ForwardRule{
-		Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
-	})
+	fm := NewForwardManager(context.Background(), sm, nil, nil, nil)
+	_, _ = fm.AddRule(forwardtest.WebRule())
 	_, _ = fm.AddRule(core.ForwardRule{Name: "socks", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
 	_, _ = fm.AddRule(core.ForwardRule{Name: "other", Host: "server2", Type: core.Dynamic, LocalPort: 1081})
-	_ = fm.StartForward("web", nil)
-	_ = fm.StartForward("socks", nil)
-	_ = fm.StartForward("other", nil)
+	_ = fm.StartForward(t.Context(), "web", nil)
+	_ = fm.StartForward(t.Context(), "socks", nil)
+	_ = fm.StartForward(t.Context(), "other", nil)
 	events := fm.Subscribe()
 
 	fm.MarkReconnecting("server1")
@@ -64,43 +56,19 @@ func TestForwardManager_MarkReconnecting(t *testing.T) {
 }
 
 func TestForwardManager_RestoreForwards(t *testing.T) {
-	callCount := 0
-	mockConn := &forwardtest.MockSSHConnection{
-		Alive: true,
-		LocalForwardF: func(_ context.Context, _ int, _ string) (net.Listener, error) {
-			callCount++
-			return forwardtest.NewMockListener(), nil
-		},
-	}
-	fm, events := setupReconnectTest(t, mockConn)
+	fm, events := setupReconnectTest(t, forwardtest.NewMockConn(true, false))
 	results := fm.RestoreForwards("server1")
-	if len(results) != 1 {
-		t.Fatalf("len(results) = %d, want 1", len(results))
-	}
-	if !results[0].OK {
-		t.Errorf("result OK = false, want true, error = %q", results[0].Error)
-	}
-	if results[0].RuleName != "web" {
-		t.Errorf("result RuleName = %q, want %q", results[0].RuleName, "web")
+	if len(results) != 1 || !results[0].OK || results[0].RuleName != "web" {
+		t.Fatalf("results = %+v, want one successful result for web", results)
 	}
 	ev := forwardtest.DrainEvent(t, events)
-	if ev.Type != core.ForwardEventRestored {
-		t.Errorf("event type = %v, want %v", ev.Type, core.ForwardEventRestored)
+	if ev.Type != core.ForwardEventRestored || ev.Session == nil {
+		t.Fatalf("event = %+v, want %v with session", ev, core.ForwardEventRestored)
 	}
-	if ev.Session == nil {
-		t.Fatal("event session should not be nil")
-	}
-	if ev.Session.Status != core.Active {
-		t.Errorf("session status = %v, want %v", ev.Session.Status, core.Active)
-	}
-	if ev.Session.ReconnectCount != 1 {
-		t.Errorf("reconnect count = %d, want 1", ev.Session.ReconnectCount)
+	if ev.Session.Status != core.Active || ev.Session.ReconnectCount != 1 {
+		t.Errorf("session status = %v, reconnect count = %d; want %v, 1", ev.Session.Status, ev.Session.ReconnectCount, core.Active)
 	}
 	forwardtest.AssertSessionStatus(t, fm, "web", core.Active)
-	session, _ := fm.GetSession("web")
-	if session.ReconnectCount != 1 {
-		t.Errorf("reconnect count = %d, want 1", session.ReconnectCount)
-	}
 	fm.Close()
 }
 
@@ -108,7 +76,7 @@ func TestForwardManager_RestoreForwards_Error(t *testing.T) {
 	callCount := 0
 	mockConn := &forwardtest.MockSSHConnection{
 		Alive: true,
-		LocalForwardF: func(_ context.Context, _ int, _ string) (net.Listener, error) {
+		LocalForwardF: func(_ context.Context, _ int, _, _ string) (net.Listener, error) {
 			callCount++
 			if callCount == 1 {
 				return forwardtest.NewMockListener(), nil
@@ -118,14 +86,8 @@ func TestForwardManager_RestoreForwards_Error(t *testing.T) {
 	}
 	fm, events := setupReconnectTest(t, mockConn)
 	results := fm.RestoreForwards("server1")
-	if len(results) != 1 {
-		t.Fatalf("len(results) = %d, want 1", len(results))
-	}
-	if results[0].OK {
-		t.Error("result OK = true, want false")
-	}
-	if results[0].Error == "" {
-		t.Error("result Error should not be empty")
+	if len(results) != 1 || results[0].OK || results[0].Error == "" {
+		t.Fatalf("results = %+v, want one failed result with error", results)
 	}
 	ev := forwardtest.DrainEvent(t, events)
 	if ev.Type != core.ForwardEventError {
@@ -139,19 +101,18 @@ func TestForwardManager_FailReconnecting(t *testing.T) {
 	fm, events := setupReconnectTest(t, forwardtest.NewMockConn(true, false))
 	fm.FailReconnecting("server1")
 	ev := forwardtest.DrainEvent(t, events)
-	if ev.Type != core.ForwardEventError {
-		t.Errorf("event type = %v, want %v", ev.Type, core.ForwardEventError)
-	}
-	if ev.Session == nil {
-		t.Fatal("event session should not be nil")
-	}
-	if ev.Session.Status != core.SessionError {
-		t.Errorf("session status = %v, want %v", ev.Session.Status, core.SessionError)
+	if ev.Type != core.ForwardEventError || ev.Session == nil || ev.Session.Status != core.SessionError {
+		t.Fatalf("event = %+v, want %v with session in %v", ev, core.ForwardEventError, core.SessionError)
 	}
 	forwardtest.AssertSessionStatus(t, fm, "web", core.SessionError)
 	session, _ := fm.GetSession("web")
-	if session.LastError == "" {
-		t.Error("session LastError should not be empty")
+	if session.LastError == "" || session.StoppedReason != core.StopReasonSSHLost {
+		t.Errorf("session = %q (%q), want LastError and ssh_lost", session.LastError, session.StoppedReason)
+	}
+	// エラー状態のセッションを停止しても、エラーになった理由を残す
+	_ = fm.StopForward("web")
+	if session, _ := fm.GetSession("web"); session.Status != core.Stopped || session.StoppedReason != core.StopReasonSSHLost {
+		t.Errorf("after stop = %v (%q), want stopped with ssh_lost", session.Status, session.StoppedReason)
 	}
 	fm.Close()
 }
@@ -0,0 +1,2 @@
+// Package relay はフォワード用リスナーの作成と、受け付けた接続と転送先との間のデータ中継を提供する。
+package relay
@@ -0,0 +1,81 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/faultinject"
+)
+
+// Listen はルールの種類に応じてフォワーディング用リスナーを作成する。
+func Listen(
+	ctx context.Context, sshConn core.SSHConnection, rule core.ForwardRule,
+) (net.Listener, error) {
+	switch rule.Type {
+	case core.Local:
+		remoteAddr := fmt.Sprintf("%s:%d", rule.RemoteHost, rule.RemotePort)
+		return sshConn.LocalForward(ctx, rule.LocalPort, remoteAddr, rule.LocalBindAddr)
+	case core.Remote:
+		localAddr := net.JoinHostPort(rule.LocalBindHost(), fmt.Sprintf("%d", rule.LocalPort))
+		return sshConn.RemoteForward(ctx, rule.RemotePort, localAddr, rule.RemoteBindAddr)
+	case core.Dynamic:
+		return sshConn.DynamicForward(ctx, rule.LocalPort, rule.LocalBindAddr)
+	default:
+		return nil, fmt.Errorf("unsupported forward type: %v", rule.Type)
+	}
+}
+
+// Serve は ctx が終了するまで listener で接続を受け付け、受け付けた接続ごとに handle をゴルーチンで呼ぶ。
+// ctx の終了以外の理由で受け付けられなくなった場合は Accept のエラーを返す。
+func Serve(ctx context.Context, listener net.Listener, handle func(net.Conn)) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		faultinject.DelayAccept(ctx)
+		go handle(conn)
+	}
+}
+
+// BoundPort は Local/Dynamic のリスナーが実際に Listen しているローカルポートを返す。
+// Remote のリスナーやポートを取得できない場合は 0 を返す。
+func BoundPort(rule core.ForwardRule, ln net.Listener) int {
+	if rule.Type == core.Remote {
+		return 0
+	}
+	if addr, ok := ln.Addr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
+
+// Dial はルールの種類に応じて、受け付けた接続の転送先へ接続する。
+func Dial(rule core.ForwardRule, sshClient Dialer) (net.Conn, error) {
+	switch rule.Type {
+	case core.Local:
+		return sshClient.Dial("tcp", Target(rule))
+	case core.Remote:
+		return net.Dial("tcp", Target(rule))
+	default:
+		return nil, fmt.Errorf("unsupported forward type for bridge: %v", rule.Type)
+	}
+}
+
+// Target は Local ではリモート側、Remote ではローカル側の転送先アドレスを返す。Dynamic では空文字列を返す。
+func Target(rule core.ForwardRule) string {
+	switch rule.Type {
+	case core.Local:
+		return fmt.Sprintf("%s:%d", rule.RemoteHost, rule.RemotePort)
+	case core.Remote:
+		return net.JoinHostPort(rule.LocalBindHost(), fmt.Sprintf("%d", rule.LocalPort))
+	default:
+		return ""
+	}
+}
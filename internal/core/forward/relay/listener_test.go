@@ -0,0 +1,153 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+)
+
+func TestListen_Remote_PassesRemoteBindAddr(t *testing.T) {
+	tests := []struct {
+		name           string
+		remoteBindAddr string
+		wantBindAddr   string
+	}{
+		{"custom bind addr", "0.0.0.0", "0.0.0.0"},
+		{"empty passthrough to sshconn", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotBindAddr string
+			conn := &forwardtest.MockSSHConnection{
+				Alive: true,
+				RemoteForwardF: func(_ context.Context, _ int, _ string, remoteBindAddr string) (net.Listener, error) {
+					gotBindAddr = remoteBindAddr
+					return forwardtest.NewMockListener(), nil
+				},
+			}
+
+			rule := core.ForwardRule{
+				Name:           "test-remote",
+				Host:           "server",
+				Type:           core.Remote,
+				LocalPort:      3000,
+				RemotePort:     8080,
+				RemoteBindAddr: tt.remoteBindAddr,
+			}
+
+			ln, err := Listen(context.Background(), conn, rule)
+			if err != nil {
+				t.Fatalf("Listen() error = %v", err)
+			}
+			defer func() { _ = ln.Close() }()
+
+			if gotBindAddr != tt.wantBindAddr {
+				t.Errorf("remoteBindAddr passed to RemoteForward = %q, want %q", gotBindAddr, tt.wantBindAddr)
+			}
+		})
+	}
+}
+
+func TestListen_PassesLocalBindAddr(t *testing.T) {
+	var got string
+	conn := &forwardtest.MockSSHConnection{
+		Alive: true,
+		LocalForwardF: func(_ context.Context, _ int, _, localBindAddr string) (net.Listener, error) {
+			got = localBindAddr
+			return forwardtest.NewMockListener(), nil
+		},
+		RemoteForwardF: func(_ context.Context, _ int, localAddr, _ string) (net.Listener, error) {
+			got = localAddr
+			return forwardtest.NewMockListener(), nil
+		},
+		DynamicForwardF: func(_ context.Context, _ int, localBindAddr string) (net.Listener, error) {
+			got = localBindAddr
+			return forwardtest.NewMockListener(), nil
+		},
+	}
+
+	tests := []struct {
+		name string
+		rule core.ForwardRule
+		want string
+	}{
+		{"local", core.ForwardRule{Type: core.Local, LocalPort: 8080, RemotePort: 80, LocalBindAddr: "0.0.0.0"}, "0.0.0.0"},
+		{"dynamic", core.ForwardRule{Type: core.Dynamic, LocalPort: 1080, LocalBindAddr: "::1"}, "::1"},
+		{"remote target", core.ForwardRule{Type: core.Remote, LocalPort: 3000, RemotePort: 80, LocalBindAddr: "192.168.1.5"}, "192.168.1.5:3000"},
+		{"remote default target", core.ForwardRule{Type: core.Remote, LocalPort: 3000, RemotePort: 80}, "127.0.0.1:3000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ln, err := Listen(context.Background(), conn, tt.rule)
+			if err != nil {
+				t.Fatalf("Listen() error = %v", err)
+			}
+			_ = ln.Close()
+			if got != tt.want {
+				t.Errorf("address passed to sshconn = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBoundPort(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	want := ln.Addr().(*net.TCPAddr).Port
+
+	if got := BoundPort(core.ForwardRule{Type: core.Local}, ln); got != want {
+		t.Errorf("BoundPort(local) = %d, want %d", got, want)
+	}
+	if got := BoundPort(core.ForwardRule{Type: core.Remote}, ln); got != 0 {
+		t.Errorf("BoundPort(remote) = %d, want 0", got)
+	}
+}
+
+func TestServe(t *testing.T) {
+	ln := forwardtest.NewMockListener()
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make(chan net.Conn, 1)
+	done := make(chan error, 1)
+	go func() { done <- Serve(ctx, ln, func(c net.Conn) { handled <- c }) }()
+
+	c, _ := net.Pipe()
+	ln.ConnCh <- c
+	if got := <-handled; got != c {
+		t.Errorf("handled conn = %v, want the accepted conn", got)
+	}
+	cancel()
+	_ = ln.Close()
+	if err := <-done; err != nil {
+		t.Errorf("Serve() after cancel = %v, want nil", err)
+	}
+
+	ln = forwardtest.NewMockListener()
+	_ = ln.Close()
+	if err := Serve(context.Background(), ln, func(net.Conn) {}); err == nil {
+		t.Error("Serve() should return the Accept error when the listener closes")
+	}
+}
+
+func TestForward_DialFailure(t *testing.T) {
+	dialErr := errors.New("refused")
+	dialer := &forwardtest.MockSOCKS5Dialer{DialF: func(string, string) (net.Conn, error) { return nil, dialErr }}
+	rule := core.ForwardRule{Name: "web", Type: core.Local, RemoteHost: "app", RemotePort: 80}
+	var sent, received atomic.Int64
+	attached := false
+
+	conn, _ := net.Pipe()
+	_, err := Forward(rule, conn, dialer, &sent, &received, nil, func(io.Closer) { attached = true })
+	if !errors.Is(err, dialErr) || attached {
+		t.Errorf("Forward() error = %v, attached = %v; want dial error without attach", err, attached)
+	}
+}
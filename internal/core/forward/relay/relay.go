@@ -0,0 +1,171 @@
+package relay
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/throttle"
+	"github.com/ousiassllc/moleport/internal/core/socks5"
+	"github.com/ousiassllc/moleport/internal/core/socksacl"
+)
+
+// ErrDenied は SOCKS5 の宛先がルールの ACL で許可されていないことを表す。
+var ErrDenied = errors.New("denied by acl")
+
+// Dialer は転送先への接続を確立する。*ssh.Client はこのインターフェースを満たす。
+type Dialer interface {
+	Dial(n, addr string) (net.Conn, error)
+}
+
+// Transfer は 1 つの接続の中継結果。Target は SOCKS5 で要求された宛先で、それ以外では空。
+type Transfer struct {
+	Target   string
+	Sent     int64
+	Received int64
+}
+
+// Record は start に受け付けた client からの接続の中継結果を、現在時刻で記録するアクセスログに変換する。
+// err は転送先への接続の失敗や ACL による拒否など、接続を中継できなかった理由。
+func (t Transfer) Record(rule core.ForwardRule, client net.Addr, start time.Time, err error) core.AccessRecord {
+	now := time.Now()
+	rec := core.AccessRecord{
+		Time: now, Rule: rule.Name, Host: rule.Host, Type: rule.Type.String(), Target: cmp.Or(t.Target, Target(rule)),
+		Duration: float64(now.Sub(start).Microseconds()) / 1000, Sent: t.Sent, Received: t.Received,
+	}
+	if client != nil {
+		rec.Client = client.String()
+	}
+	if err != nil {
+		rec.Error = err.Error()
+	}
+	return rec
+}
+
+// Forward は受け付けた conn をルールの転送先へ中継し、この接続で転送した量を返す。Dynamic では ServeSOCKS5 で中継する。
+// attach は Local/Remote で転送先への接続を確立した時点で、その接続を渡して呼ぶ。lim は Copy にそのまま渡す。
+// 転送先への接続の失敗や ACL による拒否など、接続を中継できなかった場合はエラーを返す。
+func Forward(rule core.ForwardRule, conn net.Conn, dialer Dialer, sent, received *atomic.Int64, lim *throttle.Pair, attach func(io.Closer)) (Transfer, error) {
+	if rule.Type == core.Dynamic {
+		return ServeSOCKS5(rule.Name, conn, dialer, rule.ACL, sent, received, lim)
+	}
+	remote, err := Dial(rule, dialer)
+	if err != nil {
+		return Transfer{}, err
+	}
+	defer func() { _ = remote.Close() }()
+	attach(remote)
+	return Copy(rule.Name, conn, remote, sent, received, lim), nil
+}
+
+// halfCloser は TCP half-close をサポートする接続を表す。
+// net.TCPConn はこのインターフェースを満たすが、SSH チャネル経由の接続は
+// 満たさない場合がある。
+type halfCloser interface {
+	CloseWrite() error
+}
+
+// bufPool は io.CopyBuffer で使用するバッファの再利用プール。
+// バッファサイズは io.Copy のデフォルト (32KB) と同じ。
+var bufPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, 32*1024)
+		return &buf
+	},
+}
+
+// Copy は二つの接続間でデータを双方向にコピーし、a→b を sent、b→a を received に加算する。
+// コピー完了後、half-close (CloseWrite) で EOF を相手側に伝播し、この接続で転送した量を返す。rule はログ出力にのみ使う。
+// lim が nil でない場合、a→b を lim.Upload、b→a を lim.Download の上限に合わせて遅延させる。
+func Copy(rule string, a, b net.Conn, sent, received *atomic.Int64, lim *throttle.Pair) Transfer {
+	var up, down io.Reader = a, b
+	if lim != nil {
+		up, down = lim.Upload.Reader(a), lim.Download.Reader(b)
+	}
+	var t Transfer
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		t.Sent = copyHalf(rule, b, up)
+		sent.Add(t.Sent)
+	}()
+	go func() {
+		defer wg.Done()
+		t.Received = copyHalf(rule, a, down)
+		received.Add(t.Received)
+	}()
+	wg.Wait()
+	return t
+}
+
+// copyHalf は src から dst へコピーし、完了後に dst の書き込み側を閉じる。
+func copyHalf(rule string, dst net.Conn, src io.Reader) int64 {
+	bufp := bufPool.Get().(*[]byte) // safe: Pool.New always returns *[]byte
+	defer bufPool.Put(bufp)
+	n, err := io.CopyBuffer(dst, src, *bufp)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+		slog.Debug("copy error", "rule", rule, "error", err)
+	}
+	closeWrite(dst)
+	return n
+}
+
+// closeWrite は接続の書き込み側を閉じる。
+// halfCloser をサポートする場合は CloseWrite で half-close を行い、
+// サポートしない場合は Close でフォールバックする。
+func closeWrite(c net.Conn) {
+	if hc, ok := c.(halfCloser); ok {
+		_ = hc.CloseWrite()
+	} else {
+		_ = c.Close()
+	}
+}
+
+// ServeSOCKS5 は最小限の SOCKS5 プロトコルを処理し（認証なし、CONNECT のみ）、
+// 要求された宛先へ dialer で接続して Copy で中継する。lim は Copy にそのまま渡す。
+// acl が宛先を許可しない場合は接続せずに ErrDenied をラップしたエラーを返す。
+// それ以外は宛先への接続に失敗した場合のみエラーを返す。クライアント側のプロトコル違反はログに記録して nil を返す。
+// 返す Transfer の Target は要求を解析できた時点で設定する。
+func ServeSOCKS5(rule string, conn net.Conn, dialer Dialer, acl *socksacl.ACL, sent, received *atomic.Int64, lim *throttle.Pair) (Transfer, error) {
+	if err := socks5.Negotiate(conn); err != nil {
+		slog.Debug("socks5 negotiate failed", "rule", rule, "error", err)
+		return Transfer{}, nil
+	}
+
+	targetAddr, err := socks5.ParseRequest(conn)
+	if err != nil {
+		slog.Debug("socks5 parse request failed", "rule", rule, "error", err)
+		return Transfer{}, nil
+	}
+	t := Transfer{Target: targetAddr}
+
+	if !acl.Permits(targetAddr) {
+		_, _ = conn.Write([]byte{socks5.Version, socks5.ReplyNotAllowed, 0x00, socks5.AddrIPv4, 0, 0, 0, 0, 0, 0})
+		return t, fmt.Errorf("%s: %w", targetAddr, ErrDenied)
+	}
+
+	remote, err := dialer.Dial("tcp", targetAddr)
+	if err != nil {
+		// Connection refused
+		_, _ = conn.Write([]byte{socks5.Version, socks5.ReplyConnectionRefused, 0x00, socks5.AddrIPv4, 0, 0, 0, 0, 0, 0})
+		return t, fmt.Errorf("dial %s: %w", targetAddr, err)
+	}
+	defer func() { _ = remote.Close() }()
+
+	// Success response
+	if _, err := conn.Write([]byte{socks5.Version, socks5.ReplySuccess, 0x00, socks5.AddrIPv4, 0, 0, 0, 0, 0, 0}); err != nil {
+		return t, nil
+	}
+
+	t = Copy(rule, conn, remote, sent, received, lim)
+	t.Target = targetAddr
+	return t, nil
+}
@@ -0,0 +1,291 @@
+package relay
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+	"github.com/ousiassllc/moleport/internal/core/socksacl"
+)
+
+type halfCloseConn struct {
+	net.Conn
+	closeWriteCalled atomic.Bool
+}
+
+func (c *halfCloseConn) CloseWrite() error { c.closeWriteCalled.Store(true); return nil }
+
+type plainConn struct {
+	net.Conn
+	mu          sync.Mutex
+	closeCalled int
+}
+
+func (c *plainConn) Close() error {
+	c.mu.Lock()
+	c.closeCalled++
+	c.mu.Unlock()
+	return c.Conn.Close()
+}
+
+func newSOCKS5TestPair(t *testing.T) (client, server net.Conn) {
+	t.Helper()
+	c, s := net.Pipe()
+	t.Cleanup(func() { _ = c.Close(); _ = s.Close() })
+	return c, s
+}
+
+// serveSOCKS5 は t.Name() をルール名として ServeSOCKS5 を実行する。
+func serveSOCKS5(t *testing.T, conn net.Conn, dialer Dialer) {
+	var sent, received atomic.Int64
+	_, _ = ServeSOCKS5(t.Name(), conn, dialer, nil, &sent, &received, nil)
+}
+
+func newTestDialer(ch chan<- string) *forwardtest.MockSOCKS5Dialer {
+	return &forwardtest.MockSOCKS5Dialer{DialF: func(_, addr string) (net.Conn, error) {
+		ch <- addr
+		rc, _ := net.Pipe()
+		return rc, nil
+	}}
+}
+
+func runCopyBidirectional(t *testing.T, a, b net.Conn) <-chan Transfer {
+	t.Helper()
+	var sent, received atomic.Int64
+	done := make(chan Transfer, 1)
+	go func() { done <- Copy(t.Name(), a, b, &sent, &received, nil) }()
+	return done
+}
+
+func doSOCKS5Connect(t *testing.T, request []byte) string {
+	t.Helper()
+	clientConn, serverConn := newSOCKS5TestPair(t)
+	dialedAddr := make(chan string, 1)
+	go serveSOCKS5(t, serverConn, newTestDialer(dialedAddr))
+
+	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x00})
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(clientConn, resp); err != nil {
+		t.Fatalf("read greeting response: %v", err)
+	}
+	if !bytes.Equal(resp, []byte{0x05, 0x00}) {
+		t.Fatalf("unexpected greeting response: %v", resp)
+	}
+	_, _ = clientConn.Write(request)
+	successResp := make([]byte, 10)
+	if _, err := io.ReadFull(clientConn, successResp); err != nil {
+		t.Fatalf("read success response: %v", err)
+	}
+	if successResp[0] != 0x05 || successResp[1] != 0x00 {
+		t.Fatalf("unexpected success response: %v", successResp)
+	}
+	select {
+	case addr := <-dialedAddr:
+		return addr
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for dial")
+		return ""
+	}
+}
+
+func TestServeSOCKS5_ConnectVariants(t *testing.T) {
+	domainReq := []byte{0x05, 0x01, 0x00, 0x03, byte(len("example.com"))} //nolint:gosec // domain length is always < 256
+	domainReq = append(domainReq, []byte("example.com")...)
+	domainReq = append(domainReq, 0x00, 0x50)
+	ipv4Req := []byte{0x05, 0x01, 0x00, 0x01, 192, 168, 1, 1, 0x1F, 0x90}
+	ipv6Req := []byte{0x05, 0x01, 0x00, 0x04}
+	ipv6Req = append(ipv6Req, net.ParseIP("::1").To16()...)
+	ipv6Req = append(ipv6Req, 0x01, 0xBB)
+
+	tests := []struct {
+		name    string
+		request []byte
+		want    string
+	}{
+		{"Domain", domainReq, "example.com:80"},
+		{"IPv4", ipv4Req, "192.168.1.1:8080"},
+		{"IPv6", ipv6Req, net.JoinHostPort("::1", "443")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := doSOCKS5Connect(t, tt.request); got != tt.want {
+				t.Errorf("dialed addr = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeSOCKS5_NoAuthMethodRejected(t *testing.T) {
+	clientConn, serverConn := newSOCKS5TestPair(t)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		serveSOCKS5(t, serverConn, &forwardtest.MockSOCKS5Dialer{})
+	}()
+	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x02}) // username/password only
+	resp := make([]byte, 2)
+	if _, err := io.ReadFull(clientConn, resp); err != nil {
+		t.Fatalf("read response: %v", err)
+	}
+	if !bytes.Equal(resp, []byte{0x05, 0xFF}) {
+		t.Errorf("expected no acceptable methods (0xFF), got %v", resp)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("ServeSOCKS5 did not return after rejection")
+	}
+}
+
+func TestServeSOCKS5_DialFailureReturnsError(t *testing.T) {
+	clientConn, serverConn := newSOCKS5TestPair(t)
+	errCh := make(chan error, 1)
+	go func() {
+		var sent, received atomic.Int64
+		_, err := ServeSOCKS5(t.Name(), serverConn, &forwardtest.MockSOCKS5Dialer{}, nil, &sent, &received, nil)
+		errCh <- err
+	}()
+	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x00})
+	greeting := make([]byte, 2)
+	_, _ = io.ReadFull(clientConn, greeting)
+	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x00, 0x01, 10, 0, 0, 1, 0x00, 0x50})
+	resp := make([]byte, 10)
+	if _, err := io.ReadFull(clientConn, resp); err != nil {
+		t.Fatalf("read reply: %v", err)
+	}
+	if resp[1] != 0x05 {
+		t.Errorf("reply = %#x, want connection refused (0x05)", resp[1])
+	}
+	if err := <-errCh; err == nil || !strings.Contains(err.Error(), "10.0.0.1:80") {
+		t.Errorf("ServeSOCKS5() error = %v, want dial error with target", err)
+	}
+}
+
+func TestServeSOCKS5_DeniedByACL(t *testing.T) {
+	clientConn, serverConn := newSOCKS5TestPair(t)
+	errCh := make(chan error, 1)
+	dialer := &forwardtest.MockSOCKS5Dialer{DialF: func(_, addr string) (net.Conn, error) {
+		t.Errorf("Dial(%s) called for a denied destination", addr)
+		return nil, io.EOF
+	}}
+	acl := &socksacl.ACL{Allow: []string{"192.168.0.0/16"}}
+	go func() {
+		var sent, received atomic.Int64
+		_, err := ServeSOCKS5(t.Name(), serverConn, dialer, acl, &sent, &received, nil)
+		errCh <- err
+	}()
+	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x00})
+	greeting := make([]byte, 2)
+	_, _ = io.ReadFull(clientConn, greeting)
+	_, _ = clientConn.Write([]byte{0x05, 0x01, 0x00, 0x01, 10, 0, 0, 1, 0x00, 0x50})
+	resp := make([]byte, 10)
+	if _, err := io.ReadFull(clientConn, resp); err != nil {
+		t.Fatalf("read reply: %v", err)
+	}
+	if resp[1] != 0x02 {
+		t.Errorf("reply = %#x, want not allowed (0x02)", resp[1])
+	}
+	if err := <-errCh; !errors.Is(err, ErrDenied) {
+		t.Errorf("ServeSOCKS5() error = %v, want ErrDenied", err)
+	}
+}
+
+func TestServeSOCKS5_FragmentedWrites(t *testing.T) {
+	clientConn, serverConn := newSOCKS5TestPair(t)
+	dialedAddr := make(chan string, 1)
+	go serveSOCKS5(t, serverConn, newTestDialer(dialedAddr))
+
+	for _, b := range []byte{0x05, 0x01, 0x00} {
+		_, _ = clientConn.Write([]byte{b})
+		time.Sleep(time.Millisecond)
+	}
+	resp := make([]byte, 2)
+	_, _ = io.ReadFull(clientConn, resp)
+
+	domain := "a.b"
+	req := []byte{0x05, 0x01, 0x00, 0x03, byte(len(domain))} //nolint:gosec // domain length is always < 256
+	req = append(req, []byte(domain)...)
+	req = append(req, 0x00, 0x50)
+	for _, b := range req {
+		_, _ = clientConn.Write([]byte{b})
+		time.Sleep(time.Millisecond)
+	}
+	successResp := make([]byte, 10)
+	_, _ = io.ReadFull(clientConn, successResp)
+
+	select {
+	case addr := <-dialedAddr:
+		if addr != "a.b:80" {
+			t.Errorf("dialed addr = %q, want %q", addr, "a.b:80")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for dial")
+	}
+}
+
+func TestCopy_HalfClose(t *testing.T) {
+	aClient, aServer := net.Pipe()
+	bClient, bServer := net.Pipe()
+	t.Cleanup(func() { _ = aClient.Close(); _ = bClient.Close() })
+	hcA := &halfCloseConn{Conn: aServer}
+	hcB := &halfCloseConn{Conn: bServer}
+	done := runCopyBidirectional(t, hcA, hcB)
+
+	_, _ = aClient.Write([]byte("hello"))
+	_ = aClient.Close()
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(bClient, buf); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	_ = bClient.Close()
+	select {
+	case tr := <-done:
+		if tr.Sent != 5 || tr.Received != 0 {
+			t.Errorf("transfer = %+v, want 5 bytes sent", tr)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout")
+	}
+	if !hcB.closeWriteCalled.Load() {
+		t.Error("CloseWrite not called on b")
+	}
+	if !hcA.closeWriteCalled.Load() {
+		t.Error("CloseWrite not called on a")
+	}
+}
+
+func TestCopy_FallbackClose(t *testing.T) {
+	aClient, aServer := net.Pipe()
+	bClient, bServer := net.Pipe()
+	t.Cleanup(func() { _ = aClient.Close(); _ = bClient.Close() })
+	pcA := &plainConn{Conn: aServer}
+	pcB := &plainConn{Conn: bServer}
+	done := runCopyBidirectional(t, pcA, pcB)
+
+	_ = aClient.Close()
+	_ = bClient.Close()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout")
+	}
+	pcA.mu.Lock()
+	ac := pcA.closeCalled
+	pcA.mu.Unlock()
+	pcB.mu.Lock()
+	bc := pcB.closeCalled
+	pcB.mu.Unlock()
+	if ac < 1 {
+		t.Error("Close not called on a")
+	}
+	if bc < 1 {
+		t.Error("Close not called on b")
+	}
+}
@@ -0,0 +1,2 @@
+// Package resume は前回のデーモンが保存したセッションの開始時刻と再接続回数を保持し、復元したフォワードの開始時に引き継ぐ。
+package resume
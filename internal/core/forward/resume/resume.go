@@ -0,0 +1,43 @@
+package resume
+
+import "github.com/ousiassllc/moleport/internal/core"
+
+// Records はルール名をキーに未反映のセッションの記録を保持する。
+// 排他制御は行わないため、呼び出し元がロックを保持すること。
+type Records struct {
+	records map[string]core.SessionRecord
+}
+
+// New は空の Records を生成する。
+func New() *Records {
+	return &Records{records: make(map[string]core.SessionRecord)}
+}
+
+// Load は記録を records で置き換える。nil を渡すと未反映の記録を破棄する。ルール名が空の記録は無視する。
+func (r *Records) Load(records []core.SessionRecord) {
+	r.records = make(map[string]core.SessionRecord, len(records))
+	for _, rec := range records {
+		if rec.Rule != "" {
+			r.records[rec.Rule] = rec
+		}
+	}
+}
+
+// Take は name の記録を取り出して削除する。記録は一度だけ反映するため、2 回目以降はゼロ値を返す。
+func (r *Records) Take(name string) core.SessionRecord {
+	rec := r.records[name]
+	delete(r.records, name)
+	return rec
+}
+
+// Apply は rec の開始時刻と再接続回数を session に設定する。rec がゼロ値の場合は何もしない。
+// 開始時刻が記録されていない場合は session の開始時刻を維持する。
+func Apply(rec core.SessionRecord, session *core.ForwardSession) {
+	if rec.Rule == "" {
+		return
+	}
+	if !rec.StartedAt.IsZero() {
+		session.ConnectedAt = rec.StartedAt
+	}
+	session.ReconnectCount = rec.ReconnectCount
+}
@@ -0,0 +1,57 @@
+package resume
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestRecords_TakeOnce(t *testing.T) {
+	r := New()
+	startedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	r.Load([]core.SessionRecord{{Rule: "web", StartedAt: startedAt, ReconnectCount: 2}, {StartedAt: startedAt}})
+
+	if rec := r.Take("web"); !rec.StartedAt.Equal(startedAt) || rec.ReconnectCount != 2 {
+		t.Errorf("Take(web) = %+v, want the loaded record", rec)
+	}
+	if rec := r.Take("web"); rec.Rule != "" {
+		t.Errorf("second Take(web) = %+v, want zero value", rec)
+	}
+	if rec := r.Take(""); rec.Rule != "" || !rec.StartedAt.IsZero() {
+		t.Errorf("Take(\"\") = %+v, want records without a rule to be ignored", rec)
+	}
+}
+
+func TestRecords_LoadNilDiscards(t *testing.T) {
+	r := New()
+	r.Load([]core.SessionRecord{{Rule: "web", ReconnectCount: 1}})
+	r.Load(nil)
+	if rec := r.Take("web"); rec.Rule != "" {
+		t.Errorf("Take(web) after Load(nil) = %+v, want zero value", rec)
+	}
+}
+
+func TestApply(t *testing.T) {
+	now := time.Now()
+	startedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		rec       core.SessionRecord
+		wantAt    time.Time
+		wantCount int
+	}{
+		{"no record", core.SessionRecord{}, now, 0},
+		{"record", core.SessionRecord{Rule: "web", StartedAt: startedAt, ReconnectCount: 3}, startedAt, 3},
+		{"record without start time", core.SessionRecord{Rule: "web", ReconnectCount: 1}, now, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := core.ForwardSession{ConnectedAt: now}
+			Apply(tt.rec, &s)
+			if !s.ConnectedAt.Equal(tt.wantAt) || s.ReconnectCount != tt.wantCount {
+				t.Errorf("Apply() = ConnectedAt %v ReconnectCount %d, want %v and %d", s.ConnectedAt, s.ReconnectCount, tt.wantAt, tt.wantCount)
+			}
+		})
+	}
+}
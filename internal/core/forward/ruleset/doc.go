@@ -0,0 +1,2 @@
+// Package ruleset はフォワーディングルールの集合を追加順に保持し、追加時の検証と名前の自動生成を提供する。
+package ruleset
@@ -0,0 +1,116 @@
+package ruleset
+
+import (
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ruleconflict"
+	"github.com/ousiassllc/moleport/internal/core/rulename"
+)
+
+// Set はルール名をキーにフォワーディングルールを追加順に保持する。
+// 排他制御は行わないため、呼び出し元がロックを保持すること。
+type Set struct {
+	rules map[string]core.ForwardRule
+	order []string
+}
+
+// New は空の Set を生成する。
+func New() *Set {
+	return &Set{rules: make(map[string]core.ForwardRule)}
+}
+
+// Add はルールを検証して追加し、保存したルールを返す。
+// ルール名は前後の空白を除いて命名規則と大文字小文字を区別しない一意性を検証し、空の場合はホスト・種別・ローカルポートから生成する。
+// Local/Remote で RemoteHost が空の場合は "localhost" を補う。
+// 既存のルールと待ち受けポートまたは転送先が重複する場合は ruleconflict.Check のエラーを返す。
+func (s *Set) Add(rule core.ForwardRule) (core.ForwardRule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		rule.Name = rulename.Generate(rule.Host, rule.Type, rule.LocalPort, s.order)
+	}
+	if err := rulename.Check(rule.Name, s.order); err != nil {
+		return core.ForwardRule{}, err
+	}
+	if err := core.ValidateForwardRule(rule); err != nil {
+		return core.ForwardRule{}, err
+	}
+	rule = withDefaults(rule)
+	if err := ruleconflict.Check(rule, s.All()); err != nil {
+		return core.ForwardRule{}, err
+	}
+
+	s.rules[rule.Name] = rule
+	s.order = append(s.order, rule.Name)
+	return rule, nil
+}
+
+// Update は同名の既存ルールを検証した rule で置き換え、置き換える前のルールを返す。
+// RemoteHost の補完は Add と同じ。
+func (s *Set) Update(rule core.ForwardRule) (core.ForwardRule, error) {
+	old, ok := s.rules[rule.Name]
+	if !ok {
+		return core.ForwardRule{}, &core.NotFoundError{Resource: "rule", Name: rule.Name}
+	}
+	if err := core.ValidateForwardRule(rule); err != nil {
+		return core.ForwardRule{}, err
+	}
+	s.rules[rule.Name] = withDefaults(rule)
+	return old, nil
+}
+
+// withDefaults は Local/Remote で RemoteHost が空の場合に "localhost" を補う。
+func withDefaults(rule core.ForwardRule) core.ForwardRule {
+	if (rule.Type == core.Local || rule.Type == core.Remote) && rule.RemoteHost == "" {
+		rule.RemoteHost = "localhost"
+	}
+	return rule
+}
+
+// Get は名前が name のルールを返す。
+func (s *Set) Get(name string) (core.ForwardRule, bool) {
+	rule, ok := s.rules[name]
+	return rule, ok
+}
+
+// Replace は同名の既存ルールを rule で置き換える。存在しない場合は何もしない。
+func (s *Set) Replace(rule core.ForwardRule) {
+	if _, ok := s.rules[rule.Name]; ok {
+		s.rules[rule.Name] = rule
+	}
+}
+
+// Delete は名前が name のルールを削除し、削除したかを返す。
+func (s *Set) Delete(name string) bool {
+	if _, ok := s.rules[name]; !ok {
+		return false
+	}
+	delete(s.rules, name)
+	for i, n := range s.order {
+		if n == name {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// All は全ルールを追加順に返す。
+func (s *Set) All() []core.ForwardRule {
+	rules := make([]core.ForwardRule, 0, len(s.order))
+	for _, name := range s.order {
+		rules = append(rules, s.rules[name])
+	}
+	return rules
+}
+
+// ByHost は接続先ホストが host のルールを追加順に返す。
+func (s *Set) ByHost(host string) []core.ForwardRule {
+	var rules []core.ForwardRule
+	for _, name := range s.order {
+		if rule := s.rules[name]; rule.Host == host {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
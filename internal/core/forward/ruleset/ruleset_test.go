@@ -0,0 +1,112 @@
+package ruleset
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestSet(t *testing.T) {
+	s := New()
+	for _, rule := range []core.ForwardRule{
+		{Name: "web", Host: "a", Type: core.Local, LocalPort: 8080, RemotePort: 80},
+		{Host: "b", Type: core.Dynamic, LocalPort: 1080},
+		{Name: " db ", Host: "a", Type: core.Local, LocalPort: 5432, RemotePort: 5432},
+		{Name: "rev", Host: "c", Type: core.Remote, LocalPort: 3000, RemotePort: 80},
+	} {
+		if _, err := s.Add(rule); err != nil {
+			t.Fatalf("Add(%+v) error = %v", rule, err)
+		}
+	}
+	if _, err := s.Add(core.ForwardRule{Name: "WEB", Host: "a", Type: core.Dynamic, LocalPort: 1081}); err == nil {
+		t.Error("Add() should reject a name that differs only in case")
+	}
+	var conflict *core.PortConflictError
+	if _, err := s.Add(core.ForwardRule{Name: "web2", Host: "b", Type: core.Local, LocalPort: 8080, RemotePort: 80}); !errors.As(err, &conflict) || conflict.Rule != "web" {
+		t.Errorf("Add() with a taken port error = %v, want PortConflictError with web", err)
+	}
+	if got, err := New().Add(core.ForwardRule{Host: "b", Type: core.Dynamic, LocalPort: 1080}); err != nil || got.Name != "b-dynamic-1080" {
+		t.Errorf("Add() without a name = %q, %v, want the same generated name in a new set", got.Name, err)
+	}
+	auto := New()
+	for _, want := range []string{"b-dynamic-0", "b-dynamic-0-2"} {
+		if got, err := auto.Add(core.ForwardRule{Host: "b", Type: core.Dynamic}); err != nil || got.Name != want {
+			t.Errorf("Add() generated name = %q, %v, want %q", got.Name, err, want)
+		}
+	}
+
+	var names []string
+	for _, rule := range s.All() {
+		names = append(names, rule.Name)
+	}
+	if want := []string{"web", "b-dynamic-1080", "db", "rev"}; !slices.Equal(names, want) {
+		t.Errorf("All() = %v, want %v", names, want)
+	}
+	for name, want := range map[string]string{"web": "localhost", "rev": "localhost", "b-dynamic-1080": ""} {
+		if rule, _ := s.Get(name); rule.RemoteHost != want {
+			t.Errorf("%s RemoteHost = %q, want %q", name, rule.RemoteHost, want)
+		}
+	}
+	if got := s.ByHost("none"); len(got) != 0 {
+		t.Errorf("ByHost(none) = %+v, want empty", got)
+	}
+	if got := s.ByHost("a"); len(got) != 2 || got[1].Name != "db" {
+		t.Errorf("ByHost(a) = %+v, want web and db", got)
+	}
+
+	s.Replace(core.ForwardRule{Name: "db", Host: "c"})
+	if !s.Delete("web") || s.Delete("web") {
+		t.Error("Delete() should report whether the rule existed")
+	}
+	if got := s.ByHost("c"); len(got) != 2 || len(s.All()) != 3 {
+		t.Errorf("after Replace/Delete: ByHost(c) = %+v, All() = %+v", got, s.All())
+	}
+}
+
+func TestSet_Add_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    core.ForwardRule
+		wantErr bool
+	}{
+		{"empty host", core.ForwardRule{Type: core.Local, LocalPort: 8080, RemotePort: 80}, true},
+		{"zero local port allocates on start", core.ForwardRule{Host: "s", Type: core.Local, RemotePort: 80}, false},
+		{"zero local port for remote", core.ForwardRule{Host: "s", Type: core.Remote, RemotePort: 80}, true},
+		{"negative local port", core.ForwardRule{Host: "s", Type: core.Local, LocalPort: -1, RemotePort: 80}, true},
+		{"too large local port", core.ForwardRule{Host: "s", Type: core.Local, LocalPort: 65536, RemotePort: 80}, true},
+		{"valid min local port", core.ForwardRule{Host: "s", Type: core.Local, LocalPort: 1, RemotePort: 80}, false},
+		{"valid max local port", core.ForwardRule{Host: "s", Type: core.Local, LocalPort: 65535, RemotePort: 80}, false},
+		{"invalid remote port", core.ForwardRule{Host: "s", Type: core.Local, LocalPort: 8080}, true},
+		{"dynamic without remote port", core.ForwardRule{Host: "s", Type: core.Dynamic, LocalPort: 1080}, false},
+		{"bandwidth limits", core.ForwardRule{Host: "s", Type: core.Dynamic, LocalPort: 1080, MaxUploadKbps: 512, MaxDownloadKbps: 1024}, false},
+		{"negative bandwidth limit", core.ForwardRule{Host: "s", Type: core.Dynamic, LocalPort: 1080, MaxDownloadKbps: -1}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New().Add(tt.rule); (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSet_Update(t *testing.T) {
+	s := New()
+	_, _ = s.Add(core.ForwardRule{Name: "web", Host: "a", Type: core.Local, LocalPort: 8080, RemoteHost: "app", RemotePort: 80})
+
+	old, err := s.Update(core.ForwardRule{Name: "web", Host: "a", Type: core.Local, LocalPort: 9090, RemotePort: 8000})
+	if err != nil || old.LocalPort != 8080 {
+		t.Fatalf("Update() = %+v, %v; want the previous rule", old, err)
+	}
+	if rule, _ := s.Get("web"); rule.LocalPort != 9090 || rule.RemoteHost != "localhost" {
+		t.Errorf("updated rule = %+v, want port 9090 and default RemoteHost", rule)
+	}
+	if _, err := s.Update(core.ForwardRule{Name: "web", Host: "a", Type: core.Local, LocalPort: 9090}); err == nil {
+		t.Error("Update() should validate the rule")
+	}
+	if _, err := s.Update(core.ForwardRule{Name: "missing", Host: "a", Type: core.Dynamic, LocalPort: 1080}); err == nil {
+		t.Error("Update() should fail for an unknown rule")
+	}
+}
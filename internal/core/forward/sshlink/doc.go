@@ -0,0 +1,2 @@
+// Package sshlink はフォワードのリスナー作成と転送に使う SSH 接続の取得を提供する。
+package sshlink
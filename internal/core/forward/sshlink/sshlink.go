@@ -0,0 +1,45 @@
+package sshlink
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/dockertarget"
+	"github.com/ousiassllc/moleport/internal/core/forward/relay"
+)
+
+// Open は rule のホストに SSH 接続し（未接続の場合のみ cb を使って接続する）、Get と同じ接続を返す。
+// 転送先が Docker のコンテナの場合は、開始の時点で接続先を解決し、解決できなければエラーを返す。
+// ctx がキャンセルされた場合は接続を中断する。
+func Open(ctx context.Context, mgr core.SSHManager, rule core.ForwardRule, cb core.CredentialCallback) (core.SSHConnection, relay.Dialer, error) {
+	if !mgr.IsConnected(rule.Host) {
+		if err := mgr.ConnectWithCallback(ctx, rule.Host, cb); err != nil {
+			return nil, nil, fmt.Errorf("failed to connect to host %s: %w", rule.Host, err)
+		}
+	}
+	sshConn, sshClient, err := Get(mgr, rule.Host)
+	if err != nil {
+		return nil, nil, err
+	}
+	if name, ok := dockertarget.Container(rule); ok {
+		if _, err := sshClient.Resolve(name, rule.RemotePort); err != nil {
+			return nil, nil, err
+		}
+	}
+	return sshConn, sshClient, nil
+}
+
+// Get は接続済みの host について、リスナーの作成に使う接続と転送先への接続に使う Dialer を返す。
+// Dialer は Docker のコンテナの転送先を最初の接続時に解決する。
+func Get(mgr core.SSHManager, host string) (core.SSHConnection, *dockertarget.Dialer, error) {
+	sshConn, err := mgr.GetSSHConnection(host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get SSH connection: %w", err)
+	}
+	sshClient, err := mgr.GetConnection(host)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get SSH client: %w", err)
+	}
+	return sshConn, dockertarget.Wrap(sshClient), nil
+}
@@ -0,0 +1,41 @@
+package sshlink
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+)
+
+func TestOpen_ConnectsOnlyWhenDisconnected(t *testing.T) {
+	sm := forwardtest.NewMockSSHManager()
+	conn := forwardtest.NewMockConn(true, false)
+	sm.SSHConns["bastion"] = conn
+	var calls int
+	sm.ConnectWithCbFn = func(host string, _ core.CredentialCallback) error {
+		calls++
+		return sm.Connect(t.Context(), host)
+	}
+
+	for range 2 {
+		got, _, err := Open(t.Context(), sm, core.ForwardRule{Host: "bastion"}, nil)
+		if err != nil || got != conn {
+			t.Fatalf("Open() = %v, %v; want the mock connection", got, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("ConnectWithCallback calls = %d, want 1", calls)
+	}
+}
+
+func TestOpen_Errors(t *testing.T) {
+	sm := forwardtest.NewMockSSHManager()
+	sm.ConnectErr = errors.New("auth failed")
+	if _, _, err := Open(t.Context(), sm, core.ForwardRule{Host: "bastion"}, nil); err == nil || !errors.Is(err, sm.ConnectErr) {
+		t.Errorf("Open() error = %v, want wrapped connect error", err)
+	}
+	if _, _, err := Get(sm, "bastion"); !errors.As(err, new(*core.NotConnectedError)) {
+		t.Errorf("Get() error = %v, want NotConnectedError", err)
+	}
+}
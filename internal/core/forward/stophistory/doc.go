@@ -0,0 +1,2 @@
+// Package stophistory はフォワーディングルールごとの最後の停止理由と時刻を保持する。
+package stophistory
@@ -0,0 +1,56 @@
+package stophistory
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// History はルール名をキーに最後の停止理由と時刻を保持する。
+// 排他制御は行わないため、呼び出し元がロックを保持すること。
+type History struct {
+	records map[string]core.StopRecord
+}
+
+// New は空の History を生成する。
+func New() *History {
+	return &History{records: make(map[string]core.StopRecord)}
+}
+
+// Record は name の停止理由と時刻を記録する。以前の記録は上書きする。
+func (h *History) Record(name string, reason core.StopReason, at time.Time) {
+	h.records[name] = core.StopRecord{Rule: name, Reason: reason, At: at}
+}
+
+// Delete は name の記録を削除する。
+func (h *History) Delete(name string) {
+	delete(h.records, name)
+}
+
+// Apply は name の記録があれば session の StoppedReason と StoppedAt に設定する。
+func (h *History) Apply(name string, session *core.ForwardSession) {
+	if rec, ok := h.records[name]; ok {
+		session.StoppedReason = rec.Reason
+		session.StoppedAt = rec.At
+	}
+}
+
+// Load は records を記録に加える。理由が空の記録は無視する。
+func (h *History) Load(records []core.StopRecord) {
+	for _, rec := range records {
+		if rec.Rule != "" && rec.Reason != core.StopReasonNone {
+			h.records[rec.Rule] = rec
+		}
+	}
+}
+
+// Records は全記録をルール名順に返す。
+func (h *History) Records() []core.StopRecord {
+	out := make([]core.StopRecord, 0, len(h.records))
+	for _, rec := range h.records {
+		out = append(out, rec)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Rule < out[j].Rule })
+	return out
+}
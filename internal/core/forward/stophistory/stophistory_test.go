@@ -0,0 +1,47 @@
+package stophistory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestHistory_RecordApply(t *testing.T) {
+	h := New()
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h.Record("web", core.StopReasonUser, at)
+	h.Record("web", core.StopReasonTTL, at.Add(time.Minute))
+
+	var s core.ForwardSession
+	h.Apply("web", &s)
+	if s.StoppedReason != core.StopReasonTTL || !s.StoppedAt.Equal(at.Add(time.Minute)) {
+		t.Errorf("Apply() = %q at %v, want ttl at %v", s.StoppedReason, s.StoppedAt, at.Add(time.Minute))
+	}
+
+	h.Delete("web")
+	var cleared core.ForwardSession
+	h.Apply("web", &cleared)
+	if cleared.StoppedReason != core.StopReasonNone {
+		t.Errorf("Apply() after Delete = %q, want empty", cleared.StoppedReason)
+	}
+}
+
+func TestHistory_LoadRecords(t *testing.T) {
+	h := New()
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	h.Load([]core.StopRecord{
+		{Rule: "web", Reason: core.StopReasonDaemonShutdown, At: at},
+		{Rule: "db", Reason: core.StopReasonSSHLost, At: at},
+		{Rule: "skip"},
+	})
+
+	want := []core.StopRecord{
+		{Rule: "db", Reason: core.StopReasonSSHLost, At: at},
+		{Rule: "web", Reason: core.StopReasonDaemonShutdown, At: at},
+	}
+	if got := h.Records(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Records() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,2 @@
+// Package throttle はフォワードの帯域制限に使うトークンバケットをルール名ごとに管理する。
+package throttle
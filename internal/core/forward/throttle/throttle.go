@@ -0,0 +1,137 @@
+package throttle
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// bytesPerKbps は 1 kbps あたりの毎秒のバイト数。
+const bytesPerKbps = 1000 / 8
+
+// window はバーストとして許容する、および 1 回の読み込みで扱う転送量の時間幅。
+const window = 100 * time.Millisecond
+
+// minChunk は 1 回の読み込みの最小バイト数。低い上限で読み込みが細かくなりすぎるのを防ぐ。
+const minChunk = 256
+
+// Limiter は kbps 単位の上限を持つトークンバケット。上限 0 は無制限を表す。
+// ゼロ値は無制限の Limiter として使え、SetRate で動作中に上限を変更できる。
+type Limiter struct {
+	mu     sync.Mutex
+	kbps   int
+	tokens float64
+	last   time.Time
+}
+
+// SetRate は上限を kbps に変更する。0 以下は無制限を表す。
+func (l *Limiter) SetRate(kbps int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.kbps = max(kbps, 0)
+	l.tokens = 0
+	l.last = time.Now()
+}
+
+// Rate は現在の上限（kbps）を返す。無制限の場合は 0。
+func (l *Limiter) Rate() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.kbps
+}
+
+// chunk は 1 回の読み込みの上限バイト数を返す。無制限の場合は 0。
+func (l *Limiter) chunk() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.kbps == 0 {
+		return 0
+	}
+	return max(int(float64(l.kbps*bytesPerKbps)*window.Seconds()), minChunk)
+}
+
+// reserve は n バイト分のトークンを消費し、上限を守るために待つべき時間を返す。
+// トークンの不足は負債として持ち越すため、複数の接続で共有しても合計が上限に収まる。
+func (l *Limiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.kbps == 0 {
+		return 0
+	}
+	rate := float64(l.kbps * bytesPerKbps)
+	now := time.Now()
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*rate, rate*window.Seconds())
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / rate * float64(time.Second))
+}
+
+// Reader は r からの読み込みを上限に合わせて遅延させる io.Reader を返す。
+// 上限は読み込みのたびに参照するため、SetRate の変更は読み込み中の接続にも反映される。
+func (l *Limiter) Reader(r io.Reader) io.Reader {
+	return &reader{l: l, r: r}
+}
+
+type reader struct {
+	l *Limiter
+	r io.Reader
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if c := r.l.chunk(); c > 0 && len(p) > c {
+		p = p[:c]
+	}
+	n, err := r.r.Read(p)
+	if n > 0 {
+		time.Sleep(r.l.reserve(n))
+	}
+	return n, err
+}
+
+// Pair はセッションの送信（アップロード）と受信（ダウンロード）の Limiter の組。
+type Pair struct {
+	Upload   Limiter
+	Download Limiter
+}
+
+// Registry はルール名ごとの Pair を保持する。ゼロ値では使えないため NewRegistry で生成する。
+type Registry struct {
+	mu    sync.Mutex
+	pairs map[string]*Pair
+}
+
+// NewRegistry は空の Registry を生成する。
+func NewRegistry() *Registry {
+	return &Registry{pairs: make(map[string]*Pair)}
+}
+
+// Set は name の送信・受信の上限（kbps）を設定する。
+// 既存の Pair は置き換えずに上限を更新するため、転送中の接続にも即座に反映される。
+func (r *Registry) Set(name string, uploadKbps, downloadKbps int) {
+	r.mu.Lock()
+	p, ok := r.pairs[name]
+	if !ok {
+		p = &Pair{}
+		r.pairs[name] = p
+	}
+	r.mu.Unlock()
+	p.Upload.SetRate(uploadKbps)
+	p.Download.SetRate(downloadKbps)
+}
+
+// Get は name の Pair を返す。登録されていない場合は nil を返す。
+func (r *Registry) Get(name string) *Pair {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.pairs[name]
+}
+
+// Delete は name の Pair を削除する。
+func (r *Registry) Delete(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pairs, name)
+}
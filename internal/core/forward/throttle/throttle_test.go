@@ -0,0 +1,63 @@
+package throttle
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestLimiter_Reader_Unlimited(t *testing.T) {
+	var l Limiter
+	data := bytes.Repeat([]byte("x"), 64*1024)
+	start := time.Now()
+	got, err := io.ReadAll(l.Reader(bytes.NewReader(data)))
+	if err != nil || len(got) != len(data) {
+		t.Fatalf("ReadAll() = %d bytes, %v; want %d bytes", len(got), err, len(data))
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("unlimited read took %v", elapsed)
+	}
+}
+
+func TestLimiter_Reader_Limited(t *testing.T) {
+	var l Limiter
+	l.SetRate(80) // 10000 B/s、バースト 1000 B
+	r := l.Reader(bytes.NewReader(make([]byte, 3000)))
+
+	buf := make([]byte, 32*1024)
+	n, err := r.Read(buf)
+	if err != nil || n != 1000 {
+		t.Fatalf("Read() = %d, %v; want 1000 bytes per chunk", n, err)
+	}
+	start := time.Now()
+	if _, err := io.ReadAll(r); err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 150*time.Millisecond || elapsed > 2*time.Second {
+		t.Errorf("limited read of 2000 bytes took %v, want about 200ms", elapsed)
+	}
+	if got := l.Rate(); got != 80 {
+		t.Errorf("Rate() = %d, want 80", got)
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if r.Get("web") != nil {
+		t.Fatal("Get() of unknown rule should be nil")
+	}
+	r.Set("web", 100, 0)
+	p := r.Get("web")
+	r.Set("web", 200, 300)
+	if r.Get("web") != p {
+		t.Fatal("Set() should update the existing Pair in place")
+	}
+	if p.Upload.Rate() != 200 || p.Download.Rate() != 300 {
+		t.Errorf("rates = %d/%d, want 200/300", p.Upload.Rate(), p.Download.Rate())
+	}
+	r.Delete("web")
+	if r.Get("web") != nil {
+		t.Error("Get() after Delete() should be nil")
+	}
+}
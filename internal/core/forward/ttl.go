@@ -0,0 +1,59 @@
+package forward
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/expiry"
+)
+
+// SetTTL はアクティブなセッションの有効期限を現在から ttl 後に設定し直す。ttl が 0 以下の場合は期限を解除する。
+func (m *forwardManager) SetTTL(ruleName string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.rules.Get(ruleName); !exists {
+		return &core.NotFoundError{Resource: "rule", Name: ruleName}
+	}
+	af, ok := m.active[ruleName]
+	if !ok || af.starting {
+		return fmt.Errorf("forward %s is not active", ruleName)
+	}
+	af.session.ExpiresAt = expiry.Deadline(ttl)
+	m.scheduleExpiry(ruleName, af.session.ExpiresAt)
+	return nil
+}
+
+// scheduleExpiry は ruleName の有効期限を at に設定する。at がゼロ値の場合は期限を解除する。
+func (m *forwardManager) scheduleExpiry(ruleName string, at time.Time) {
+	m.expiry.Schedule(ruleName, at, func() { m.warnExpiring(ruleName) }, func() { m.expire(ruleName) })
+}
+
+// warnExpiring は有効期限が近づいたセッションについて ForwardEventExpiring を発行する。
+func (m *forwardManager) warnExpiring(ruleName string) {
+	session, err := m.GetSession(ruleName)
+	if err != nil || session.ExpiresAt.IsZero() {
+		return
+	}
+	slog.Info("forward expiring", "rule", ruleName, "expires_at", session.ExpiresAt)
+	m.events.Emit(core.ForwardEvent{Type: core.ForwardEventExpiring, RuleName: ruleName, Session: session})
+}
+
+// expire は有効期限が切れたフォワードを停止する。DeleteOnExpire のルールは削除する。
+func (m *forwardManager) expire(ruleName string) {
+	m.mu.RLock()
+	rule, ok := m.rules.Get(ruleName)
+	m.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	slog.Info("forward expired", "rule", ruleName, "delete", rule.DeleteOnExpire)
+	if rule.DeleteOnExpire {
+		_ = m.deleteRule(ruleName, core.StopReasonTTL)
+		return
+	}
+	m.stopForward(ruleName, core.StopReasonTTL, false)
+}
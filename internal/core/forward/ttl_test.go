@@ -0,0 +1,48 @@
+package forward
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+)
+
+func TestForwardManager_TTL(t *testing.T) {
+	fm := newConnectedManager(forwardtest.NewMockConn(false, true))
+	defer fm.Close()
+	_, _ = fm.AddRule(core.ForwardRule{Name: "web", Host: "server1", Type: core.Dynamic, LocalPort: 1080})
+	_, _ = fm.AddRule(core.ForwardRule{
+		Name: "tmp", Host: "server1", Type: core.Dynamic, LocalPort: 1081,
+		TTL: core.Duration{Duration: 50 * time.Millisecond}, DeleteOnExpire: true,
+	})
+	if err := fm.SetTTL("web", time.Hour); err == nil {
+		t.Error("SetTTL() should fail for an inactive forward")
+	}
+	_ = fm.StartForward(t.Context(), "web", nil)
+	if err := fm.SetTTL("web", time.Hour); err != nil {
+		t.Fatalf("SetTTL() error = %v", err)
+	}
+	if s, _ := fm.GetSession("web"); time.Until(s.ExpiresAt) < 59*time.Minute {
+		t.Errorf("ExpiresAt = %v, want about an hour from now", s.ExpiresAt)
+	}
+	_ = fm.SetTTL("web", 0)
+	if s, _ := fm.GetSession("web"); !s.ExpiresAt.IsZero() {
+		t.Errorf("ExpiresAt = %v, want zero after clearing the TTL", s.ExpiresAt)
+	}
+
+	events := fm.Subscribe()
+	_ = fm.StartForward(t.Context(), "tmp", nil)
+	if ev := forwardtest.DrainEvent(t, events); ev.Type != core.ForwardEventStarted || ev.Session.ExpiresAt.IsZero() {
+		t.Fatalf("event = %v, want Started with expiry", ev.Type)
+	}
+	// 残りが警告時間（5 分）より短いため、警告は直ちに発行される
+	for _, want := range []core.ForwardEventType{core.ForwardEventExpiring, core.ForwardEventStopped} {
+		if ev := forwardtest.DrainEvent(t, events); ev.Type != want || ev.RuleName != "tmp" || ev.Session.StoppedReason == core.StopReasonUser {
+			t.Fatalf("event = %v %q (%q), want %v tmp", ev.Type, ev.RuleName, ev.Session.StoppedReason, want)
+		}
+	}
+	if rules := fm.GetRules(); len(rules) != 1 || rules[0].Name != "web" {
+		t.Errorf("rules = %+v, want only web after tmp expired", rules)
+	}
+}
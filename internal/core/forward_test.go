@@ -0,0 +1,76 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core/socksacl"
+)
+
+func TestValidateBindAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		wantErr bool
+	}{
+		{"", false},
+		{"localhost", false},
+		{"127.0.0.1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"*", true},
+		{"example.com", true},
+		{"127.0.0.1:80", true},
+	}
+	for _, tt := range tests {
+		err := ValidateBindAddr(tt.addr)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBindAddr(%q) error = %v, wantErr %v", tt.addr, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateForwardRule_BindAddr(t *testing.T) {
+	base := ForwardRule{Host: "server", Type: Remote, LocalPort: 3000, RemotePort: 8080}
+
+	local := base
+	local.LocalBindAddr = "bad host"
+	if err := ValidateForwardRule(local); err == nil {
+		t.Error("expected error for invalid local_bind_addr")
+	}
+
+	remote := base
+	remote.RemoteBindAddr = "bad host"
+	if err := ValidateForwardRule(remote); err == nil {
+		t.Error("expected error for invalid remote_bind_addr")
+	}
+
+	ok := base
+	ok.LocalBindAddr, ok.RemoteBindAddr = "192.168.1.5", "0.0.0.0"
+	if err := ValidateForwardRule(ok); err != nil {
+		t.Errorf("ValidateForwardRule() error = %v", err)
+	}
+}
+
+func TestForwardRule_LocalBindHost(t *testing.T) {
+	if got := (ForwardRule{}).LocalBindHost(); got != LocalhostAddr {
+		t.Errorf("LocalBindHost() = %q, want %q", got, LocalhostAddr)
+	}
+	if got := (ForwardRule{LocalBindAddr: "0.0.0.0"}).LocalBindHost(); got != "0.0.0.0" {
+		t.Errorf("LocalBindHost() = %q, want 0.0.0.0", got)
+	}
+}
+
+func TestValidateForwardRule_AccessLog(t *testing.T) {
+	for dest, wantErr := range map[string]bool{"": false, AccessLogFile: false, AccessLogSlog: false, "syslog": true} {
+		rule := ForwardRule{Host: "server", Type: Dynamic, LocalPort: 1080, AccessLog: dest}
+		if err := ValidateForwardRule(rule); (err != nil) != wantErr {
+			t.Errorf("ValidateForwardRule(access_log %q) error = %v, wantErr %v", dest, err, wantErr)
+		}
+	}
+}
+
+func TestValidateForwardRule_ACL(t *testing.T) {
+	rule := ForwardRule{Host: "server", Type: Local, LocalPort: 8080, RemotePort: 80, ACL: &socksacl.ACL{}}
+	if err := ValidateForwardRule(rule); err == nil {
+		t.Error("ValidateForwardRule() should reject an acl on a non-dynamic rule")
+	}
+}
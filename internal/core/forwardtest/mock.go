@@ -50,7 +50,7 @@ func (m *MockSSHManager) GetHost(name string) (*core.SSHHost, error) {
 	return nil, &core.NotFoundError{Resource: "host", Name: name}
 }
 
-func (m *MockSSHManager) Connect(hostName string) error {
+func (m *MockSSHManager) Connect(_ context.Context, hostName string) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	if m.ConnectErr != nil {
@@ -60,11 +60,11 @@ func (m *MockSSHManager) Connect(hostName string) error {
 	return nil
 }
 
-func (m *MockSSHManager) ConnectWithCallback(hostName string, cb core.CredentialCallback) error {
+func (m *MockSSHManager) ConnectWithCallback(ctx context.Context, hostName string, cb core.CredentialCallback) error {
 	if m.ConnectWithCbFn != nil {
 		return m.ConnectWithCbFn(hostName, cb)
 	}
-	return m.Connect(hostName)
+	return m.Connect(ctx, hostName)
 }
 
 func (m *MockSSHManager) Disconnect(hostName string) error {
@@ -107,6 +107,9 @@ func (m *MockSSHManager) Subscribe() <-chan core.SSHEvent {
 	return ch
 }
 
+func (m *MockSSHManager) UpdateSettings(core.ReconnectConfig, map[string]core.HostConfig, core.TimeoutConfig) {
+}
+
 func (m *MockSSHManager) Close() {
 	for _, ch := range m.subscribers {
 		close(ch)
@@ -130,14 +133,15 @@ type MockSSHConnection struct {
 	Client  *ssh.Client
 	Closed  bool
 	Alive   bool
+	Latency time.Duration
 
 	KeepAliveF      func(ctx context.Context, interval time.Duration)
-	LocalForwardF   func(ctx context.Context, localPort int, remoteAddr string) (net.Listener, error)
+	LocalForwardF   func(ctx context.Context, localPort int, remoteAddr string, localBindAddr string) (net.Listener, error)
 	RemoteForwardF  func(ctx context.Context, remotePort int, localAddr string, remoteBindAddr string) (net.Listener, error)
-	DynamicForwardF func(ctx context.Context, localPort int) (net.Listener, error)
+	DynamicForwardF func(ctx context.Context, localPort int, localBindAddr string) (net.Listener, error)
 }
 
-func (m *MockSSHConnection) Dial(_ core.SSHHost, _ core.CredentialCallback) (*ssh.Client, error) {
+func (m *MockSSHConnection) Dial(_ context.Context, _ core.SSHHost, _ core.CredentialCallback) (*ssh.Client, error) {
 	if m.DialErr != nil {
 		return nil, m.DialErr
 	}
@@ -151,11 +155,12 @@ func (m *MockSSHConnection) Close() error {
 	return nil
 }
 
-func (m *MockSSHConnection) IsAlive() bool { return m.Alive }
+func (m *MockSSHConnection) IsAlive() bool      { return m.Alive }
+func (m *MockSSHConnection) RTT() time.Duration { return m.Latency }
 
-func (m *MockSSHConnection) LocalForward(ctx context.Context, p int, addr string) (net.Listener, error) {
+func (m *MockSSHConnection) LocalForward(ctx context.Context, p int, addr string, localBindAddr string) (net.Listener, error) {
 	if m.LocalForwardF != nil {
-		return m.LocalForwardF(ctx, p, addr)
+		return m.LocalForwardF(ctx, p, addr, localBindAddr)
 	}
 	return nil, fmt.Errorf("not implemented")
 }
@@ -167,14 +172,14 @@ func (m *MockSSHConnection) RemoteForward(ctx context.Context, p int, addr strin
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (m *MockSSHConnection) DynamicForward(ctx context.Context, p int) (net.Listener, error) {
+func (m *MockSSHConnection) DynamicForward(ctx context.Context, p int, localBindAddr string) (net.Listener, error) {
 	if m.DynamicForwardF != nil {
-		return m.DynamicForwardF(ctx, p)
+		return m.DynamicForwardF(ctx, p, localBindAddr)
 	}
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (m *MockSSHConnection) KeepAlive(ctx context.Context, interval time.Duration) {
+func (m *MockSSHConnection) KeepAlive(ctx context.Context, interval time.Duration, _ int) {
 	if m.KeepAliveF != nil {
 		m.KeepAliveF(ctx, interval)
 		return
@@ -188,14 +193,37 @@ var _ core.SSHConnection = (*MockSSHConnection)(nil)
 func NewMockConn(local, dynamic bool) *MockSSHConnection {
 	c := &MockSSHConnection{Alive: true}
 	if local {
-		c.LocalForwardF = func(_ context.Context, _ int, _ string) (net.Listener, error) { return NewMockListener(), nil }
+		c.LocalForwardF = func(_ context.Context, _ int, _, _ string) (net.Listener, error) { return NewMockListener(), nil }
 	}
 	if dynamic {
-		c.DynamicForwardF = func(_ context.Context, _ int) (net.Listener, error) { return NewMockListener(), nil }
+		c.DynamicForwardF = func(_ context.Context, _ int, _ string) (net.Listener, error) { return NewMockListener(), nil }
 	}
 	return c
 }
 
+// NewRecordingConn は LocalForward で作成したリスナーを記録する MockSSHConnection と、記録したリスナーの一覧を返す関数を返す。
+func NewRecordingConn() (*MockSSHConnection, func() []*MockListener) {
+	var mu sync.Mutex
+	var listeners []*MockListener
+	c := &MockSSHConnection{Alive: true, LocalForwardF: func(context.Context, int, string, string) (net.Listener, error) {
+		l := NewMockListener()
+		mu.Lock()
+		listeners = append(listeners, l)
+		mu.Unlock()
+		return l, nil
+	}}
+	return c, func() []*MockListener {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*MockListener(nil), listeners...)
+	}
+}
+
+// WebRule は server1 の localhost:80 をローカルの 8080 番に転送するテスト用のルールを返す。
+func WebRule() core.ForwardRule {
+	return core.ForwardRule{Name: "web", Host: "server1", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}
+}
+
 // MockSOCKS5Dialer は SOCKS5 ダイアルのテスト用モック。
 type MockSOCKS5Dialer struct {
 	DialF func(n, addr string) (net.Conn, error)
@@ -237,6 +265,13 @@ func (l *MockListener) Close() error {
 
 func (l *MockListener) Addr() net.Addr { return &net.TCPAddr{IP: net.ParseIP("127.0.0.1"), Port: 0} }
 
+// AccessRecorder は記録されたアクセスログを保持する core.AccessLogger のテスト用実装。
+type AccessRecorder struct{ Records []core.AccessRecord }
+
+func (r *AccessRecorder) LogAccess(_ core.ForwardRule, rec core.AccessRecord) {
+	r.Records = append(r.Records, rec)
+}
+
 // DrainEvent はイベントチャネルからイベントを1件読み出す。タイムアウトで失敗する。
 func DrainEvent(t *testing.T, ch <-chan core.ForwardEvent) core.ForwardEvent {
 	t.Helper()
@@ -0,0 +1,41 @@
+package core
+
+// ReconnectOverride はホスト別の再接続設定オーバーライド。
+// 指定されたフィールドのみグローバル設定を上書きする。
+type ReconnectOverride struct {
+	Enabled      *bool     `yaml:"enabled,omitempty"`
+	MaxRetries   *int      `yaml:"max_retries,omitempty" schema:"min=0"`
+	InitialDelay *Duration `yaml:"initial_delay,omitempty"`
+	MaxDelay     *Duration `yaml:"max_delay,omitempty"`
+}
+
+// HostConfig はホスト別のオーバーライド設定。
+type HostConfig struct {
+	Reconnect    *ReconnectOverride `yaml:"reconnect,omitempty"`
+	HostMetadata `yaml:",inline" schema:"since=1.1.0"`
+	// ConnectTimeout と BannerTimeout は指定された場合のみ ssh_config とグローバル設定より優先する。
+	ConnectTimeout *Duration `yaml:"connect_timeout,omitempty" schema:"since=1.1.0"`
+	BannerTimeout  *Duration `yaml:"banner_timeout,omitempty" schema:"since=1.1.0"`
+	// KeepAliveInterval と KeepAliveCountMax は指定された場合のみ ssh_config の ServerAlive* とグローバル設定より優先する。
+	KeepAliveInterval *Duration `yaml:"keepalive_interval,omitempty" schema:"since=1.1.0"`
+	KeepAliveCountMax *int      `yaml:"keepalive_count_max,omitempty" schema:"since=1.1.0,min=1"`
+	// Credentials はパスワード・パスフレーズをユーザーへの問い合わせより先に取得する取得元。
+	Credentials *CredentialSource `yaml:"credentials,omitempty" schema:"since=1.1.0"`
+	// Forwards はホストに定義するフォワード。ssh_config のマジックコメントと同じ "L 5432:localhost:5432 name=db" 形式で書く。
+	Forwards []string `yaml:"forwards,omitempty" schema:"since=1.1.0"`
+}
+
+// HostMetadata は MolePort 独自に保持するホストの補足情報。
+// SSH config には書けないメモや認証手段のヒントをユーザーが記録するために使う。
+type HostMetadata struct {
+	Notes           string `yaml:"notes,omitempty"`
+	AuthHint        string `yaml:"auth_hint,omitempty"`
+	JumpDescription string `yaml:"jump_description,omitempty"`
+	// Tags はホストを分類するタグ（"env:prod" 等）。TUI のホスト一覧は先頭のタグでグループ化できる。
+	Tags []string `yaml:"tags,omitempty"`
+}
+
+// IsZero はメタデータが一つも設定されていないかを返す。
+func (m HostMetadata) IsZero() bool {
+	return m.Notes == "" && m.AuthHint == "" && m.JumpDescription == "" && len(m.Tags) == 0
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ホストの定義元（SSHHost.Source）。
+const (
+	// HostSourceSSHConfig は ssh_config の Host 行で定義されたホスト。
+	HostSourceSSHConfig = "ssh_config"
+	// HostSourceConfig は config.yaml の host_definitions で定義されたホスト。
+	HostSourceConfig = "config"
+)
+
+// HostDefinition は ssh_config に書かずに config.yaml で定義する SSH ホスト。
+// ホスト一覧では ssh_config のホストの後ろに並び、同名のホストがある場合は ssh_config を優先する。
+type HostDefinition struct {
+	Name         string `yaml:"name"`
+	HostName     string `yaml:"hostname"`
+	Port         int    `yaml:"port,omitempty"`
+	User         string `yaml:"user,omitempty"`
+	IdentityFile string `yaml:"identity_file,omitempty"`
+	// ProxyJump は経由する踏み台（ssh_config の ProxyJump と同じくカンマ区切りで多段指定できる）。
+	ProxyJump string `yaml:"proxy_jump,omitempty"`
+}
+
+// Validate は名前とホスト名が指定され、ポートが有効範囲内（0 は既定の 22）であることを検証する。
+func (d HostDefinition) Validate() error {
+	if d.Name == "" {
+		return fmt.Errorf("host name is required")
+	}
+	if strings.ContainsAny(d.Name, "*?!, \t") {
+		return fmt.Errorf("invalid host name %q: must not contain wildcards, commas or spaces", d.Name)
+	}
+	if d.HostName == "" {
+		return fmt.Errorf("hostname is required for host %q", d.Name)
+	}
+	if d.Port != 0 {
+		if err := ValidatePort(d.Port); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DiffHostNames は再読み込み前後のホスト一覧を比較し、追加・削除されたホスト名を出現順に返す。
+func DiffHostNames(before, after []SSHHost) (added, removed []string) {
+	beforeSet := make(map[string]bool, len(before))
+	for _, h := range before {
+		beforeSet[h.Name] = true
+	}
+	afterSet := make(map[string]bool, len(after))
+	for _, h := range after {
+		afterSet[h.Name] = true
+		if !beforeSet[h.Name] {
+			added = append(added, h.Name)
+		}
+	}
+	for _, h := range before {
+		if !afterSet[h.Name] {
+			removed = append(removed, h.Name)
+		}
+	}
+	return added, removed
+}
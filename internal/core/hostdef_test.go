@@ -0,0 +1,38 @@
+package core
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHostDefinition_Validate(t *testing.T) {
+	tests := []struct {
+		def     HostDefinition
+		wantErr bool
+	}{
+		{HostDefinition{Name: "lab", HostName: "203.0.113.10"}, false},
+		{HostDefinition{Name: "lab", HostName: "203.0.113.10", Port: 2222}, false},
+		{HostDefinition{HostName: "203.0.113.10"}, true},
+		{HostDefinition{Name: "lab-*", HostName: "203.0.113.10"}, true},
+		{HostDefinition{Name: "lab"}, true},
+		{HostDefinition{Name: "lab", HostName: "203.0.113.10", Port: 70000}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.def.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%+v) error = %v, wantErr %v", tt.def, err, tt.wantErr)
+		}
+	}
+}
+
+func TestDiffHostNames(t *testing.T) {
+	before := []SSHHost{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+	after := []SSHHost{{Name: "b"}, {Name: "d"}, {Name: "a"}, {Name: "e"}}
+
+	added, removed := DiffHostNames(before, after)
+	if !slices.Equal(added, []string{"d", "e"}) {
+		t.Errorf("added = %v, want [d e]", added)
+	}
+	if !slices.Equal(removed, []string{"c"}) {
+		t.Errorf("removed = %v, want [c]", removed)
+	}
+}
@@ -0,0 +1,3 @@
+// Package hostforward はホストの定義に添えて書くフォワード（ssh_config のマジックコメントと
+// config.yaml の hosts.<name>.forwards）の書式を解析し、フォワードルールに変換する。
+package hostforward
@@ -0,0 +1,142 @@
+package hostforward
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/rulename"
+)
+
+// CommentPrefix は ssh_config でフォワードを定義するマジックコメントの接頭辞（"#" の後ろ）。
+const CommentPrefix = "moleport:"
+
+// Parse はホスト host に定義されたフォワード spec をルールに変換する。
+// 書式は "<L|R|D> <forward> [name=<name>] [auto_connect] [auto_reconnect]" で、forward は
+// L・R では ssh -L・-R と同じ [bind_address:]port:host:hostport、D では [bind_address:]port。
+// name を省略した場合は、デーモンが名前を省略したルールに付ける名前と同じく rulename.Generate で
+// "<host>-local-5432" のように名前を付け、existing と重複する場合は "-2", "-3" … を付ける。
+func Parse(host, spec string, existing []string) (core.ForwardRule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) < 2 {
+		return core.ForwardRule{}, fmt.Errorf("invalid forward %q: want <L|R|D> <forward> [name=<name>] [auto_connect] [auto_reconnect]", spec)
+	}
+	kind := strings.ToUpper(fields[0])
+	rule, err := parseForward(kind, fields[1])
+	if err != nil {
+		return core.ForwardRule{}, fmt.Errorf("invalid forward %q: %w", spec, err)
+	}
+	rule.Host = host
+	rule.HostDefined = true
+
+	for _, opt := range fields[2:] {
+		key, value, hasValue := strings.Cut(opt, "=")
+		switch {
+		case key == "name" && hasValue:
+			if !rulename.Valid(value) {
+				return core.ForwardRule{}, fmt.Errorf("invalid forward %q: invalid rule name %q", spec, value)
+			}
+			rule.Name = value
+		case key == "auto_connect" && !hasValue:
+			rule.AutoConnect = true
+		case key == "auto_reconnect" && !hasValue:
+			rule.AutoReconnect = true
+		default:
+			return core.ForwardRule{}, fmt.Errorf("invalid forward %q: unknown option %q", spec, opt)
+		}
+	}
+	if rule.Name == "" {
+		rule.Name = rulename.Generate(host, rule.Type, rule.LocalPort, existing)
+	}
+	return rule, nil
+}
+
+// parseForward は種別 kind（L・R・D）の転送の指定を解析する。
+func parseForward(kind, s string) (core.ForwardRule, error) {
+	if kind != "L" && kind != "R" && kind != "D" {
+		return core.ForwardRule{}, fmt.Errorf("unknown forward type %q: want L, R or D", kind)
+	}
+	fields := splitSpec(s)
+	want := 4
+	if kind == "D" {
+		want = 2
+	}
+	if len(fields) == want-1 {
+		fields = append([]string{""}, fields...)
+	}
+	if len(fields) != want {
+		if kind == "D" {
+			return core.ForwardRule{}, fmt.Errorf("want [bind_address:]port")
+		}
+		return core.ForwardRule{}, fmt.Errorf("want [bind_address:]port:host:hostport")
+	}
+	bind := fields[0]
+	if bind == "*" {
+		bind = "0.0.0.0"
+	}
+	if err := core.ValidateBindAddr(bind); err != nil {
+		return core.ForwardRule{}, err
+	}
+	port, err := parsePort(fields[1])
+	if err != nil {
+		return core.ForwardRule{}, err
+	}
+	if kind == "D" {
+		return core.ForwardRule{Type: core.Dynamic, LocalPort: port, LocalBindAddr: bind}, nil
+	}
+
+	host := fields[2]
+	if host == "" {
+		return core.ForwardRule{}, fmt.Errorf("missing destination host")
+	}
+	destPort, err := parsePort(fields[3])
+	if err != nil {
+		return core.ForwardRule{}, err
+	}
+	if kind == "L" {
+		return core.ForwardRule{Type: core.Local, LocalPort: port, RemoteHost: host, RemotePort: destPort, LocalBindAddr: bind}, nil
+	}
+	// R はリモートの port で待ち受け、ローカルの host:hostport へ転送する
+	if err := core.ValidateBindAddr(host); err != nil {
+		return core.ForwardRule{}, err
+	}
+	if host == "localhost" || host == core.LocalhostAddr {
+		host = ""
+	}
+	// RemoteHost は登録時に補完される値と揃え、再読み込みで定義の変更と誤判定しないようにする
+	return core.ForwardRule{Type: core.Remote, LocalPort: destPort, RemotePort: port, RemoteHost: "localhost", RemoteBindAddr: bind, LocalBindAddr: host}, nil
+}
+
+// parsePort はポート番号を解析する。
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid port %q", s)
+	}
+	if err := core.ValidatePort(port); err != nil {
+		return 0, err
+	}
+	return port, nil
+}
+
+// splitSpec は s を ":" で分割する。角括弧内の ":" では分割せず、角括弧は取り除く。
+func splitSpec(s string) []string {
+	var fields []string
+	var b strings.Builder
+	bracket := false
+	for _, r := range s {
+		switch {
+		case r == '[' && !bracket:
+			bracket = true
+		case r == ']' && bracket:
+			bracket = false
+		case r == ':' && !bracket:
+			fields = append(fields, b.String())
+			b.Reset()
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return append(fields, b.String())
+}
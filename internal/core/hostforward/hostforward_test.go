@@ -0,0 +1,87 @@
+package hostforward
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		spec string
+		want core.ForwardRule
+	}{
+		{
+			spec: "L 5432:localhost:5432 name=db",
+			want: core.ForwardRule{Name: "db", Host: "prod", Type: core.Local, LocalPort: 5432, RemoteHost: "localhost", RemotePort: 5432, HostDefined: true},
+		},
+		{
+			spec: "l 127.0.0.1:8080:web.internal:80 auto_connect auto_reconnect",
+			want: core.ForwardRule{
+				Name: "prod-local-8080", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "web.internal", RemotePort: 80,
+				LocalBindAddr: "127.0.0.1", AutoConnect: true, AutoReconnect: true, HostDefined: true,
+			},
+		},
+		{
+			spec: "L [::1]:8443:[fd00::1]:443",
+			want: core.ForwardRule{Name: "prod-local-8443", Host: "prod", Type: core.Local, LocalPort: 8443, RemoteHost: "fd00::1", RemotePort: 443, LocalBindAddr: "::1", HostDefined: true},
+		},
+		{
+			spec: "R *:2222:localhost:22",
+			want: core.ForwardRule{Name: "prod-remote-22", Host: "prod", Type: core.Remote, LocalPort: 22, RemotePort: 2222, RemoteHost: "localhost", RemoteBindAddr: "0.0.0.0", HostDefined: true},
+		},
+		{
+			spec: "R 3000:192.168.1.5:3000",
+			want: core.ForwardRule{Name: "prod-remote-3000", Host: "prod", Type: core.Remote, LocalPort: 3000, RemotePort: 3000, RemoteHost: "localhost", LocalBindAddr: "192.168.1.5", HostDefined: true},
+		},
+		{
+			spec: "D 1080",
+			want: core.ForwardRule{Name: "prod-dynamic-1080", Host: "prod", Type: core.Dynamic, LocalPort: 1080, HostDefined: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := Parse("prod", tt.spec, nil)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_GeneratedNameAvoidsExisting(t *testing.T) {
+	got, err := Parse("prod", "D 1080", []string{"Prod-Dynamic-1080"})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if got.Name != "prod-dynamic-1080-2" {
+		t.Errorf("Name = %q, want prod-dynamic-1080-2", got.Name)
+	}
+	if got, _ := Parse("prod", "D 1080 name=socks", []string{"socks"}); got.Name != "socks" {
+		t.Errorf("Name = %q, want the explicit name to be kept", got.Name)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"L",
+		"X 5432:localhost:5432",
+		"L 5432:localhost",
+		"L 70000:localhost:5432",
+		"L 5432::5432",
+		"L bad-bind:5432:localhost:5432",
+		"R 2222:db.internal:22",
+		"D 1080:localhost:80",
+		"L 5432:localhost:5432 name=-bad",
+		"L 5432:localhost:5432 persist",
+	} {
+		if _, err := Parse("prod", spec, nil); err == nil {
+			t.Errorf("Parse(%q) should fail", spec)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package notifyconf はデスクトップ通知の設定と、通知の対象にできるイベント種別を提供する。
+package notifyconf
@@ -0,0 +1,22 @@
+package notifyconf
+
+import "slices"
+
+// デスクトップ通知の対象にできるイベント種別。
+const (
+	EventSSHError        = "ssh.error"
+	EventSSHDisconnected = "ssh.disconnected"
+	EventForwardError    = "forward.error"
+)
+
+// Config はデスクトップ通知（macOS の osascript、Linux の notify-send、Windows のトースト）の設定。
+type Config struct {
+	Enabled bool `yaml:"enabled"`
+	// Events は通知するイベント種別。空の場合は全ての種別を通知する。
+	Events []string `yaml:"events,omitempty" schema:"enum=ssh.error|ssh.disconnected|forward.error"`
+}
+
+// Wants は event を通知するかを返す。
+func (c Config) Wants(event string) bool {
+	return c.Enabled && (len(c.Events) == 0 || slices.Contains(c.Events, event))
+}
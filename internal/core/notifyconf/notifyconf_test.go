@@ -0,0 +1,24 @@
+package notifyconf
+
+import "testing"
+
+func TestConfig_Wants(t *testing.T) {
+	tests := []struct {
+		name  string
+		cfg   Config
+		event string
+		want  bool
+	}{
+		{"disabled", Config{Events: []string{EventSSHError}}, EventSSHError, false},
+		{"all events", Config{Enabled: true}, EventForwardError, true},
+		{"selected event", Config{Enabled: true, Events: []string{EventSSHError}}, EventSSHError, true},
+		{"unselected event", Config{Enabled: true, Events: []string{EventSSHError}}, EventForwardError, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.Wants(tt.event); got != tt.want {
+				t.Errorf("Wants(%q) = %v, want %v", tt.event, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package core
+
+import "context"
+
+// ForwardGroup は一括で開始・停止するフォワードルールのまとまり（"staging-stack" 等）。
+// Rules はルール名の並びで、この順に開始する。
+type ForwardGroup struct {
+	Name  string   `yaml:"name" schema:"max=63"`
+	Rules []string `yaml:"rules"`
+}
+
+// ProfileManager はフォワードグループ単位での開始・停止を担う。
+// デーモンのプロファイル（設定ディレクトリの切り替え）とは別の概念で、設定ファイルの groups を扱う。
+type ProfileManager interface {
+	// Groups は設定済みの全グループを設定ファイルの順に返す。
+	Groups() []ForwardGroup
+
+	// StartGroup はグループの全ルールを開始し、新たに開始したルール名を返す。既にアクティブなルールはそのままにする。
+	// いずれかのルールの開始に失敗した場合は、この呼び出しで開始したルールを停止してからエラーを返す。
+	// 未知のグループは *NotFoundError（Resource "group"）、存在しないルールを含む場合は開始前に *NotFoundError（Resource "rule"）を返す。
+	// ctx は各ルールの SSH 接続の確立に使う。
+	StartGroup(ctx context.Context, name string, cb CredentialCallback) ([]string, error)
+
+	// StopGroup はグループのルールのうちアクティブなものを停止し、停止したルール名を返す。
+	StopGroup(name string) ([]string, error)
+}
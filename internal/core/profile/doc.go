@@ -0,0 +1,2 @@
+// Package profile は設定ファイルの groups に定義したフォワードグループを一括で開始・停止する core.ProfileManager を提供する。
+package profile
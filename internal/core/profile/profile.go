@@ -0,0 +1,117 @@
+package profile
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// Forwarder はグループ内のルールの確認と開始・停止に使う。core.ForwardManager が満たす。
+type Forwarder interface {
+	GetRules() []core.ForwardRule
+	GetSession(ruleName string) (*core.ForwardSession, error)
+	StartForward(ctx context.Context, ruleName string, cb core.CredentialCallback) error
+	StopForward(ruleName string) error
+}
+
+// manager は core.ProfileManager の実装。グループ単位の操作は直列に実行する。
+type manager struct {
+	mu     sync.Mutex
+	fwd    Forwarder
+	groups func() []core.ForwardGroup
+}
+
+// New は groups が返すグループ定義を fwd で操作する ProfileManager を生成する。
+// groups は操作のたびに呼び出すため、設定の再読み込み後のグループ定義がそのまま反映される。
+func New(fwd Forwarder, groups func() []core.ForwardGroup) core.ProfileManager {
+	return &manager{fwd: fwd, groups: groups}
+}
+
+// Groups は設定済みの全グループを返す。
+func (m *manager) Groups() []core.ForwardGroup {
+	return slices.Clone(m.groups())
+}
+
+// StartGroup はグループの全ルールを開始する。失敗時はこの呼び出しで開始したルールを停止して元に戻す。
+func (m *manager) StartGroup(ctx context.Context, name string, cb core.CredentialCallback) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	group, err := m.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	rules := m.fwd.GetRules()
+	for _, r := range group.Rules {
+		if !slices.ContainsFunc(rules, func(rule core.ForwardRule) bool { return rule.Name == r }) {
+			return nil, &core.NotFoundError{Resource: "rule", Name: r}
+		}
+	}
+
+	var started []string
+	for _, r := range group.Rules {
+		if m.running(r) {
+			continue
+		}
+		if err := m.fwd.StartForward(ctx, r, cb); err != nil {
+			m.rollback(started)
+			return nil, fmt.Errorf("start group %q: rule %q: %w", name, r, err)
+		}
+		started = append(started, r)
+	}
+	return started, nil
+}
+
+// StopGroup はグループのルールのうち停止していないものを停止する。
+func (m *manager) StopGroup(name string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	group, err := m.lookup(name)
+	if err != nil {
+		return nil, err
+	}
+	var stopped []string
+	for _, r := range group.Rules {
+		if s, err := m.fwd.GetSession(r); err != nil || s.Status == core.Stopped {
+			continue
+		}
+		if err := m.fwd.StopForward(r); err != nil {
+			return stopped, fmt.Errorf("stop group %q: rule %q: %w", name, r, err)
+		}
+		stopped = append(stopped, r)
+	}
+	return stopped, nil
+}
+
+// lookup は名前が一致する最初のグループを返す。
+func (m *manager) lookup(name string) (core.ForwardGroup, error) {
+	for _, g := range m.groups() {
+		if g.Name == name {
+			return g, nil
+		}
+	}
+	return core.ForwardGroup{}, &core.NotFoundError{Resource: "group", Name: name}
+}
+
+// running はルールのセッションが開始済み（開始中・再接続中を含む）かを返す。
+func (m *manager) running(rule string) bool {
+	s, err := m.fwd.GetSession(rule)
+	if err != nil {
+		return false
+	}
+	return s.Status == core.Starting || s.Status == core.Active || s.Status == core.SessionReconnecting
+}
+
+// rollback は開始に失敗したグループのうち、既に開始したルールを逆順に停止する。
+func (m *manager) rollback(started []string) {
+	for _, r := range slices.Backward(started) {
+		if err := m.fwd.StopForward(r); err != nil {
+			slog.Warn("failed to roll back group forward", "rule", r, "error", err)
+		}
+	}
+}
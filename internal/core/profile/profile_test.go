@@ -0,0 +1,122 @@
+package profile
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// fakeForwarder はルールごとの状態を保持し、failOn のルールの開始を失敗させる。
+type fakeForwarder struct {
+	status map[string]core.SessionStatus
+	failOn string
+	calls  []string
+}
+
+func newFake(names ...string) *fakeForwarder {
+	f := &fakeForwarder{status: map[string]core.SessionStatus{}}
+	for _, n := range names {
+		f.status[n] = core.Stopped
+	}
+	return f
+}
+
+func (f *fakeForwarder) GetRules() []core.ForwardRule {
+	var rules []core.ForwardRule
+	for n := range f.status {
+		rules = append(rules, core.ForwardRule{Name: n})
+	}
+	return rules
+}
+
+func (f *fakeForwarder) GetSession(name string) (*core.ForwardSession, error) {
+	st, ok := f.status[name]
+	if !ok {
+		return nil, &core.NotFoundError{Resource: "rule", Name: name}
+	}
+	return &core.ForwardSession{Rule: core.ForwardRule{Name: name}, Status: st}, nil
+}
+
+func (f *fakeForwarder) StartForward(_ context.Context, name string, _ core.CredentialCallback) error {
+	f.calls = append(f.calls, "start "+name)
+	if name == f.failOn {
+		return errors.New("address already in use")
+	}
+	f.status[name] = core.Active
+	return nil
+}
+
+func (f *fakeForwarder) StopForward(name string) error {
+	f.calls = append(f.calls, "stop "+name)
+	f.status[name] = core.Stopped
+	return nil
+}
+
+func staging() []core.ForwardGroup {
+	return []core.ForwardGroup{{Name: "staging-stack", Rules: []string{"db", "redis", "web"}}}
+}
+
+func TestStartGroup(t *testing.T) {
+	fwd := newFake("db", "redis", "web", "other")
+	fwd.status["redis"] = core.Active
+	m := New(fwd, staging)
+
+	started, err := m.StartGroup(t.Context(), "staging-stack", nil)
+	if err != nil {
+		t.Fatalf("StartGroup: %v", err)
+	}
+	if want := []string{"db", "web"}; !slices.Equal(started, want) {
+		t.Errorf("started = %v, want %v", started, want)
+	}
+	if fwd.status["other"] != core.Stopped {
+		t.Error("rules outside the group should not be started")
+	}
+
+	stopped, err := m.StopGroup("staging-stack")
+	if err != nil {
+		t.Fatalf("StopGroup: %v", err)
+	}
+	if want := []string{"db", "redis", "web"}; !slices.Equal(stopped, want) {
+		t.Errorf("stopped = %v, want %v", stopped, want)
+	}
+}
+
+func TestStartGroup_RollsBackOnFailure(t *testing.T) {
+	fwd := newFake("db", "redis", "web")
+	fwd.failOn = "web"
+
+	if _, err := New(fwd, staging).StartGroup(t.Context(), "staging-stack", nil); err == nil {
+		t.Fatal("StartGroup should fail when a rule fails to start")
+	}
+	want := []string{"start db", "start redis", "start web", "stop redis", "stop db"}
+	if !slices.Equal(fwd.calls, want) {
+		t.Errorf("calls = %v, want %v", fwd.calls, want)
+	}
+}
+
+func TestStartGroup_NotFound(t *testing.T) {
+	tests := []struct {
+		name     string
+		group    string
+		resource string
+	}{
+		{"unknown group", "prod-stack", "group"},
+		{"unknown rule", "staging-stack", "rule"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fwd := newFake("db", "web")
+			_, err := New(fwd, staging).StartGroup(t.Context(), tt.group, nil)
+			var nf *core.NotFoundError
+			if !errors.As(err, &nf) || nf.Resource != tt.resource {
+				t.Fatalf("err = %v, want NotFoundError for %s", err, tt.resource)
+			}
+			if len(fwd.calls) != 0 {
+				t.Errorf("no rule should be started, got %v", fwd.calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package revision は一覧の要素ごとの変更をリビジョン番号で記録し、クライアントが前回取得した時点からの差分を求める。
+package revision
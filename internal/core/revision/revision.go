@@ -0,0 +1,109 @@
+package revision
+
+import "sync"
+
+// maxRemoved は差分のために保持する削除の記録の上限。超えた分は古いものから捨て、それより前からの差分は返せなくなる。
+const maxRemoved = 256
+
+// Tracker は一覧の要素ごとに最後に変化したリビジョンを記録する。
+// Observe で現在の一覧を渡すたびに前回との違いを調べ、変化があればリビジョンを 1 つ進める。
+// リビジョンはプロセス内でのみ有効で、デーモンの再起動で 0 から数え直す。
+type Tracker[T any] struct {
+	mu    sync.Mutex
+	rev   uint64
+	equal func(a, b T) bool
+	items map[string]entry[T]
+	order []string // 直近の Observe に渡された要素のキー（渡された順）
+	// removed は削除の記録（古い順）。floor は差分を返せる最小の since（これより前の削除の記録は捨てている）。
+	removed []tombstone
+	floor   uint64
+}
+
+type entry[T any] struct {
+	value T
+	rev   uint64
+}
+
+type tombstone struct {
+	key string
+	rev uint64
+}
+
+// New は空の Tracker を生成する。equal は要素が変化していないかを比較する関数。
+func New[T any](equal func(a, b T) bool) *Tracker[T] {
+	return &Tracker[T]{equal: equal, items: make(map[string]entry[T])}
+}
+
+// Observe は現在の一覧 items を記録し、現在のリビジョンを返す。key は要素を識別するキーを返す関数。
+// 前回から追加・変更・削除された要素があればリビジョンを 1 つ進め、それらの要素に新しいリビジョンを記録する。
+func (t *Tracker[T]) Observe(items []T, key func(T) string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	next := t.rev + 1
+	changed := false
+	seen := make(map[string]bool, len(items))
+	order := make([]string, len(items))
+	for i, item := range items {
+		k := key(item)
+		order[i] = k
+		seen[k] = true
+		if e, ok := t.items[k]; ok && t.equal(e.value, item) {
+			continue
+		}
+		t.items[k] = entry[T]{value: item, rev: next}
+		t.dropTombstone(k)
+		changed = true
+	}
+	for _, k := range t.order {
+		if seen[k] {
+			continue
+		}
+		delete(t.items, k)
+		t.removed = append(t.removed, tombstone{key: k, rev: next})
+		changed = true
+	}
+	t.order = order
+	if over := len(t.removed) - maxRemoved; over > 0 {
+		t.floor = t.removed[over-1].rev
+		t.removed = append([]tombstone(nil), t.removed[over:]...)
+	}
+	if changed {
+		t.rev = next
+	}
+	return t.rev
+}
+
+// Changes は since より後に追加・変更された要素（直近の Observe の順）と削除されたキーを、現在のリビジョンとともに返す。
+// since が 0、現在のリビジョンより新しい（デーモンが再起動した）、または古すぎて削除の記録が残っていない場合は ok=false を返す。
+// その場合、呼び出し側は一覧の全件を取得し直す。
+func (t *Tracker[T]) Changes(since uint64) (rev uint64, changed []T, removed []string, ok bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if since == 0 || since > t.rev || since < t.floor {
+		return t.rev, nil, nil, false
+	}
+	for _, k := range t.order {
+		if e := t.items[k]; e.rev > since {
+			changed = append(changed, e.value)
+		}
+	}
+	for _, ts := range t.removed {
+		if ts.rev > since {
+			removed = append(removed, ts.key)
+		}
+	}
+	return t.rev, changed, removed, true
+}
+
+// dropTombstone は再び追加された key の削除の記録を取り除く。
+func (t *Tracker[T]) dropTombstone(key string) {
+	kept := t.removed[:0]
+	for _, ts := range t.removed {
+		if ts.key != key {
+			kept = append(kept, ts)
+		}
+	}
+	t.removed = kept
+}
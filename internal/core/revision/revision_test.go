@@ -0,0 +1,81 @@
+package revision
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type item struct {
+	name  string
+	value int
+}
+
+func newTracker() *Tracker[item] {
+	return New(func(a, b item) bool { return a == b })
+}
+
+func key(i item) string { return i.name }
+
+func TestTracker_Changes(t *testing.T) {
+	tr := newTracker()
+	rev := tr.Observe([]item{{"a", 1}, {"b", 1}}, key)
+	if rev != 1 {
+		t.Fatalf("first Observe rev = %d, want 1", rev)
+	}
+	// 変化がなければリビジョンは進まない
+	if got := tr.Observe([]item{{"a", 1}, {"b", 1}}, key); got != rev {
+		t.Errorf("unchanged Observe rev = %d, want %d", got, rev)
+	}
+
+	next := tr.Observe([]item{{"a", 2}, {"c", 1}}, key)
+	if next != 2 {
+		t.Fatalf("changed Observe rev = %d, want 2", next)
+	}
+	gotRev, changed, removed, ok := tr.Changes(rev)
+	if !ok || gotRev != next {
+		t.Fatalf("Changes(%d) rev = %d, ok = %v, want %d, true", rev, gotRev, ok, next)
+	}
+	if want := []item{{"a", 2}, {"c", 1}}; !reflect.DeepEqual(changed, want) {
+		t.Errorf("changed = %v, want %v", changed, want)
+	}
+	if want := []string{"b"}; !reflect.DeepEqual(removed, want) {
+		t.Errorf("removed = %v, want %v", removed, want)
+	}
+
+	// 最新のリビジョンからの差分は空
+	if _, changed, removed, ok := tr.Changes(next); !ok || changed != nil || removed != nil {
+		t.Errorf("Changes(latest) = %v, %v, %v, want empty and ok", changed, removed, ok)
+	}
+}
+
+func TestTracker_ReAddDropsRemoval(t *testing.T) {
+	tr := newTracker()
+	tr.Observe([]item{{"a", 1}}, key)
+	tr.Observe(nil, key)
+	tr.Observe([]item{{"a", 1}}, key)
+
+	_, changed, removed, ok := tr.Changes(1)
+	if !ok || len(changed) != 1 || removed != nil {
+		t.Errorf("Changes(1) = %v, %v, %v, want a only in changed", changed, removed, ok)
+	}
+}
+
+func TestTracker_ChangesReset(t *testing.T) {
+	tr := newTracker()
+	rev := tr.Observe([]item{{"a", 1}}, key)
+
+	for _, since := range []uint64{0, rev + 1} {
+		if _, _, _, ok := tr.Changes(since); ok {
+			t.Errorf("Changes(%d) ok = true, want false", since)
+		}
+	}
+
+	// 削除の記録が上限を超えると、古いリビジョンからの差分は返せない
+	for i := range maxRemoved + 1 {
+		tr.Observe([]item{{fmt.Sprintf("x%d", i), 1}}, key)
+	}
+	if _, _, _, ok := tr.Changes(rev); ok {
+		t.Error("Changes() from a trimmed revision should report a reset")
+	}
+}
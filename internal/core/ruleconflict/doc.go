@@ -0,0 +1,2 @@
+// Package ruleconflict はフォワードルールの追加時に、既存のルールとの待ち受けポートの重複と転送先の重複を検出する。
+package ruleconflict
@@ -0,0 +1,21 @@
+package ruleconflict
+
+import (
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// DuplicateError は同じホストから同じ転送先へ転送するルールが既に存在するエラー。
+// Rule は既存のルール名、Target は転送先（"host:port"）。
+type DuplicateError struct {
+	Rule   string
+	Target string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("rule %q already forwards to %s", e.Rule, e.Target)
+}
+
+// Is は core.ErrAlreadyExists に一致する。
+func (e *DuplicateError) Is(target error) bool { return target == core.ErrAlreadyExists }
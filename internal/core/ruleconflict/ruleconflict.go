@@ -0,0 +1,94 @@
+package ruleconflict
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward/portcheck"
+)
+
+// Check は rule を existing に追加できるかを確認する。同名のルールは比較しない。
+// 同じポートで待ち受けるルールがある場合は *core.PortConflictError を、
+// 同じホストから同じ転送先へ転送するルールがある場合は *DuplicateError を返す。
+// 待ち受けポートが 0（開始時に自動割り当て）のルールはポートの重複を確認しない。
+func Check(rule core.ForwardRule, existing []core.ForwardRule) error {
+	self := listenOf(rule)
+	for _, other := range existing {
+		if other.Name == rule.Name {
+			continue
+		}
+		if l := listenOf(other); self.port != 0 && l.port == self.port && l.scope == self.scope && overlaps(l.addr, self.addr) {
+			return &core.PortConflictError{Port: self.port, Rule: other.Name, Suggestion: portcheck.Suggest(self.port, reserved(self.scope, existing), nil)}
+		}
+	}
+	target, ok := targetOf(rule)
+	if !ok {
+		return nil
+	}
+	for _, other := range existing {
+		if other.Name == rule.Name || other.Host != rule.Host || other.Type != rule.Type {
+			continue
+		}
+		if t, ok := targetOf(other); ok && t == target {
+			return &DuplicateError{Rule: other.Name, Target: target}
+		}
+	}
+	return nil
+}
+
+// listen はルールが待ち受けるアドレス。scope はローカルの場合は空、Remote の場合は待ち受けるホスト名。
+type listen struct {
+	scope string
+	addr  string
+	port  int
+}
+
+func listenOf(rule core.ForwardRule) listen {
+	if rule.Type == core.Remote {
+		return listen{scope: rule.Host, addr: normalize(rule.RemoteBindAddr), port: rule.RemotePort}
+	}
+	return listen{addr: normalize(rule.LocalBindAddr), port: rule.LocalPort}
+}
+
+// reserved は scope で待ち受けるルールに割り当て済みのポートを返す。
+func reserved(scope string, rules []core.ForwardRule) map[int]bool {
+	ports := make(map[int]bool)
+	for _, rule := range rules {
+		if l := listenOf(rule); l.scope == scope {
+			ports[l.port] = true
+		}
+	}
+	return ports
+}
+
+// targetOf はルールの転送先を "host:port" の形式で返す。Dynamic は転送先を持たないため false を返す。
+func targetOf(rule core.ForwardRule) (string, bool) {
+	switch rule.Type {
+	case core.Local:
+		return net.JoinHostPort(normalize(rule.RemoteHost), strconv.Itoa(rule.RemotePort)), true
+	case core.Remote:
+		return net.JoinHostPort(normalize(rule.LocalBindAddr), strconv.Itoa(rule.LocalPort)), true
+	}
+	return "", false
+}
+
+// normalize はアドレスを比較用に正規化する。空と "localhost" はループバックアドレスとして扱う。
+func normalize(addr string) string {
+	addr = strings.ToLower(addr)
+	if addr == "" || addr == "localhost" {
+		return core.LocalhostAddr
+	}
+	return addr
+}
+
+// overlaps は 2 つの待ち受けアドレスが同じポートを奪い合うかを返す。どちらかが全アドレスの場合も重複とみなす。
+func overlaps(a, b string) bool {
+	return a == b || isWildcard(a) || isWildcard(b)
+}
+
+func isWildcard(addr string) bool {
+	ip := net.ParseIP(addr)
+	return addr == "*" || (ip != nil && ip.IsUnspecified())
+}
@@ -0,0 +1,63 @@
+package ruleconflict
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestCheck(t *testing.T) {
+	existing := []core.ForwardRule{
+		{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		{Name: "api", Host: "prod", Type: core.Local, LocalPort: 8081, RemoteHost: "localhost", RemotePort: 3000},
+		{Name: "socks", Host: "prod", Type: core.Dynamic, LocalPort: 1080, LocalBindAddr: "0.0.0.0"},
+		{Name: "rev", Host: "prod", Type: core.Remote, LocalPort: 3000, RemoteHost: "localhost", RemotePort: 9000},
+		{Name: "auto", Host: "prod", Type: core.Dynamic},
+	}
+	tests := []struct {
+		name     string
+		rule     core.ForwardRule
+		wantPort string // PortConflictError.Rule
+		wantDup  string // DuplicateError.Rule
+		wantSugg int
+	}{
+		{"free port", core.ForwardRule{Name: "db", Host: "prod", Type: core.Local, LocalPort: 5432, RemoteHost: "db", RemotePort: 5432}, "", "", 0},
+		{"same port on another host", core.ForwardRule{Name: "stg", Host: "stg", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}, "web", "", 8082},
+		{"different bind address", core.ForwardRule{Name: "lan", Host: "stg", Type: core.Local, LocalPort: 8080, LocalBindAddr: "192.168.0.10", RemotePort: 80}, "", "", 0},
+		{"wildcard bind", core.ForwardRule{Name: "lan", Host: "stg", Type: core.Local, LocalPort: 1080, LocalBindAddr: "127.0.0.1", RemotePort: 80}, "socks", "", 1081},
+		{"same target", core.ForwardRule{Name: "web2", Host: "prod", Type: core.Local, LocalPort: 9090, RemoteHost: "127.0.0.1", RemotePort: 80}, "", "web", 0},
+		{"same target on another host", core.ForwardRule{Name: "web2", Host: "stg", Type: core.Local, LocalPort: 9090, RemoteHost: "localhost", RemotePort: 80}, "", "", 0},
+		{"remote port on the same host", core.ForwardRule{Name: "rev2", Host: "prod", Type: core.Remote, LocalPort: 4000, RemotePort: 9000}, "rev", "", 9001},
+		{"remote port on another host", core.ForwardRule{Name: "rev2", Host: "stg", Type: core.Remote, LocalPort: 3000, RemotePort: 9000}, "", "", 0},
+		{"automatic port", core.ForwardRule{Name: "auto2", Host: "prod", Type: core.Dynamic}, "", "", 0},
+		{"same name is ignored", core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}, "", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.rule, existing)
+			var conflict *core.PortConflictError
+			var dup *DuplicateError
+			switch {
+			case tt.wantPort != "":
+				if !errors.As(err, &conflict) || conflict.Rule != tt.wantPort || conflict.Suggestion != tt.wantSugg {
+					t.Errorf("Check() = %v, want a port conflict with %s suggesting %d", err, tt.wantPort, tt.wantSugg)
+				}
+			case tt.wantDup != "":
+				if !errors.As(err, &dup) || dup.Rule != tt.wantDup {
+					t.Errorf("Check() = %v, want a duplicate of %s", err, tt.wantDup)
+				}
+			case err != nil:
+				t.Errorf("Check() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestDuplicateError_IsAlreadyExists(t *testing.T) {
+	err := fmt.Errorf("add rule: %w", &DuplicateError{Rule: "web", Target: "127.0.0.1:80"})
+	if !errors.Is(err, core.ErrAlreadyExists) {
+		t.Errorf("errors.Is(%v, ErrAlreadyExists) = false, want true", err)
+	}
+}
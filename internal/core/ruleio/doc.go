@@ -0,0 +1,3 @@
+// Package ruleio はフォワードルールとホスト別設定の書き出し（export）・取り込み（import）に使う文書を提供する。
+// 文書は YAML または JSON で、取り込み時はローカルのルールと突き合わせて反映する変更を決める。
+package ruleio
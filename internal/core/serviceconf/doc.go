@@ -0,0 +1,3 @@
+// Package serviceconf はデーモンが設定に応じて起動する付帯サービス（Webhook・リモートフォワードの登録・DNS・mDNS・
+// リモート操作・イベントブリッジ・IPC のトランスポート・クライアント認証）の設定を提供する。
+package serviceconf
@@ -0,0 +1,82 @@
+package serviceconf
+
+// Webhook はライフサイクルイベントを HTTP POST で通知する Webhook の設定。
+type Webhook struct {
+	URL string `yaml:"url"`
+	// Events は通知するイベント種別（"forward.started" 等）。空の場合は全種別を通知する。
+	Events []string `yaml:"events,omitempty"`
+	// Secret が設定されている場合、本文の HMAC-SHA256 署名をヘッダーに付与する。
+	Secret string `yaml:"secret,omitempty"`
+	// MaxRetries は送信失敗時の再試行回数。0 以下の場合は既定値を使う。
+	MaxRetries int `yaml:"max_retries,omitempty" schema:"default=3,min=0"`
+	// Template はペイロードの text（Slack 互換）を生成する text/template。空の場合は既定のテンプレートを使う。
+	Template string `yaml:"template,omitempty" schema:"since=1.1.0"`
+}
+
+// Registry はリモートフォワードの公開先（ホスト・リモートポート・ローカルの転送先）をサービスディレクトリに登録する設定。
+type Registry struct {
+	// URL はフォワードの開始・停止のたびに公開先を POST するエンドポイント。空の場合は登録しない。
+	URL string `yaml:"url,omitempty"`
+	// Headers はリクエストに付与するヘッダー（"Authorization" 等）。値の ${VAR} は環境変数で置き換える。
+	Headers map[string]string `yaml:"headers,omitempty"`
+	// MaxRetries は送信失敗時の再試行回数。0 以下の場合は既定値を使う。
+	MaxRetries int `yaml:"max_retries,omitempty" schema:"default=3,min=0"`
+}
+
+// DefaultDNSListen は DNS リゾルバーの既定のリッスンアドレス。
+const DefaultDNSListen = "127.0.0.1:5354"
+
+// DNS はローカル DNS リゾルバーの設定。
+type DNS struct {
+	Enabled bool `yaml:"enabled"`
+	// Listen は UDP のリッスンアドレス。空の場合は DefaultDNSListen を使う。
+	Listen string `yaml:"listen,omitempty" schema:"default=127.0.0.1:5354"`
+}
+
+// MDNS はアクティブなローカル/ダイナミックフォワードを mDNS（_moleport._tcp）で LAN に公開する設定。
+type MDNS struct {
+	Enabled bool `yaml:"enabled"`
+}
+
+// DefaultRemoteListen はリモート操作用の TLS リスナーの既定のリッスンアドレス。
+const DefaultRemoteListen = ":7443"
+
+// Remote はリモート操作用の TLS リスナーの設定。
+// サーバー・クライアントとも設定ディレクトリの tls/ に置く CA が発行した証明書で相互に認証する。
+type Remote struct {
+	Enabled bool `yaml:"enabled"`
+	// Listen は TCP のリッスンアドレス。空の場合は DefaultRemoteListen を使う。
+	Listen string `yaml:"listen,omitempty" schema:"default=:7443"`
+	// Hosts はサーバー証明書に含めるホスト名・IP アドレス。クライアントはこのいずれかを指定して接続する。
+	Hosts []string `yaml:"hosts,omitempty"`
+}
+
+// DefaultEventBridgeListen は WebSocket のイベントブリッジの既定のリッスンアドレス。
+const DefaultEventBridgeListen = "127.0.0.1:7444"
+
+// EventBridge は外部のダッシュボード向けにイベントを WebSocket（ws://<listen>/events）で中継する設定。
+type EventBridge struct {
+	Enabled bool `yaml:"enabled"`
+	// Listen は TCP のリッスンアドレス。空の場合は DefaultEventBridgeListen を使う。
+	Listen string `yaml:"listen,omitempty" schema:"default=127.0.0.1:7444"`
+	// AllowedOrigins はブラウザからの接続を許可する Origin（"*" は全て）。リッスンアドレスと同じホストの Origin は常に許可する。
+	AllowedOrigins []string `yaml:"allowed_origins,omitempty"`
+}
+
+// IPCTransportMsgpack は JSON-RPC のメッセージを MessagePack でエンコードするトランスポート。
+const IPCTransportMsgpack = "msgpack"
+
+// IPC は IPC のトランスポートの設定。CLI/TUI が使う JSON-RPC の Unix ソケット（moleport.sock）は常に待ち受ける。
+type IPC struct {
+	// Transport は moleport.sock に加えて待ち受けるトランスポート。"msgpack" の場合は moleport.msgpack.sock を作成する。
+	// 空または "jsonrpc" の場合は追加しない。
+	Transport string `yaml:"transport,omitempty" schema:"default=jsonrpc,enum=jsonrpc|msgpack"`
+}
+
+// Auth は Unix ソケットで接続するクライアントの認証の設定。
+// 別の UID のプロセスからの接続は常に拒否する。接続元の UID を確認できない OS では RequireToken によらずトークンを必須にする。
+type Auth struct {
+	// RequireToken が true の場合、クライアントは auth.login で認証トークン（設定ディレクトリの moleport.token）を
+	// 提示するまで他のメソッドを呼び出せない。
+	RequireToken bool `yaml:"require_token"`
+}
@@ -21,6 +21,7 @@ const (
 
 	// Reply codes
 	ReplySuccess              = 0x00
+	ReplyNotAllowed           = 0x02
 	ReplyCommandNotSupported  = 0x07
 	ReplyAddrTypeNotSupported = 0x08
 	ReplyConnectionRefused    = 0x05
@@ -0,0 +1,2 @@
+// Package socksacl は Dynamic ルールの SOCKS5 プロキシで接続を許可・拒否する宛先の一覧（ACL）を提供する。
+package socksacl
@@ -0,0 +1,81 @@
+package socksacl
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"strings"
+)
+
+// ACL は Dynamic ルールの SOCKS5 プロキシで接続を許可する宛先の一覧。
+// 各エントリは CIDR（"10.0.0.0/8"）またはホスト名・IP アドレスのグロブ（"*.example.com"）。
+// CIDR は IP アドレスで指定された宛先にのみ一致し、ドメイン名の名前解決は行わない。
+type ACL struct {
+	// Allow が空でない場合、いずれかに一致する宛先のみ許可する。
+	Allow []string `yaml:"allow,omitempty"`
+	// Deny に一致する宛先は Allow より優先して拒否する。
+	Deny []string `yaml:"deny,omitempty"`
+}
+
+// Validate は全エントリが CIDR または有効なグロブであることを検証する。
+func (a *ACL) Validate() error {
+	if a == nil {
+		return nil
+	}
+	for _, entry := range append(append([]string{}, a.Allow...), a.Deny...) {
+		if err := validateACLEntry(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Permits は宛先アドレス（"host:port" またはホストのみ）への接続を許可するかを返す。a が nil の場合は常に許可する。
+func (a *ACL) Permits(addr string) bool {
+	if a == nil {
+		return true
+	}
+	host := addr
+	if h, _, err := net.SplitHostPort(addr); err == nil {
+		host = h
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if matchACL(a.Deny, host) {
+		return false
+	}
+	return len(a.Allow) == 0 || matchACL(a.Allow, host)
+}
+
+// validateACLEntry は ACL の 1 エントリを検証する。
+func validateACLEntry(entry string) error {
+	if entry == "" {
+		return fmt.Errorf("acl entry must not be empty")
+	}
+	if strings.Contains(entry, "/") {
+		if _, _, err := net.ParseCIDR(entry); err != nil {
+			return fmt.Errorf("invalid acl CIDR %q: %w", entry, err)
+		}
+		return nil
+	}
+	if _, err := path.Match(entry, ""); err != nil {
+		return fmt.Errorf("invalid acl pattern %q: %w", entry, err)
+	}
+	return nil
+}
+
+// matchACL は host がエントリのいずれかに一致するかを返す。
+func matchACL(entries []string, host string) bool {
+	ip := net.ParseIP(host)
+	for _, entry := range entries {
+		if strings.Contains(entry, "/") {
+			if _, cidr, err := net.ParseCIDR(entry); err == nil && ip != nil && cidr.Contains(ip) {
+				return true
+			}
+			continue
+		}
+		if ok, _ := path.Match(strings.ToLower(entry), host); ok {
+			return true
+		}
+	}
+	return false
+}
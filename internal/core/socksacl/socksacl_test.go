@@ -0,0 +1,57 @@
+package socksacl
+
+import "testing"
+
+func TestACL_Permits(t *testing.T) {
+	acl := &ACL{
+		Allow: []string{"10.0.0.0/8", "*.example.com", "db.internal"},
+		Deny:  []string{"10.0.0.1", "secret.example.com"},
+	}
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"10.1.2.3:443", true},
+		{"10.0.0.1:22", false},
+		{"api.example.com:443", true},
+		{"API.Example.com.:443", true},
+		{"secret.example.com:443", false},
+		{"example.com:443", false},
+		{"db.internal:5432", true},
+		{"192.168.0.1:80", false},
+		{"[2001:db8::1]:80", false},
+	}
+	for _, tt := range tests {
+		if got := acl.Permits(tt.addr); got != tt.want {
+			t.Errorf("Permits(%q) = %v, want %v", tt.addr, got, tt.want)
+		}
+	}
+
+	var none *ACL
+	if !none.Permits("anything:80") {
+		t.Error("nil ACL should permit every destination")
+	}
+	denyOnly := &ACL{Deny: []string{"192.168.0.0/16"}}
+	if denyOnly.Permits("192.168.1.1:80") || !denyOnly.Permits("example.org:80") {
+		t.Error("deny-only ACL should permit everything except denied destinations")
+	}
+}
+
+func TestACL_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		acl     *ACL
+		wantErr bool
+	}{
+		{"nil", nil, false},
+		{"valid", &ACL{Allow: []string{"10.0.0.0/8", "*.example.com"}, Deny: []string{"::1"}}, false},
+		{"bad cidr", &ACL{Allow: []string{"10.0.0.0/33"}}, true},
+		{"bad glob", &ACL{Deny: []string{"[a-"}}, true},
+		{"empty entry", &ACL{Allow: []string{""}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.acl.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
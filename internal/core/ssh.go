@@ -19,14 +19,17 @@ type SSHConnection interface {
 	// Dial はホスト情報を使って SSH 接続を確立し、クライアントを返す。
 	// cb が nil の場合、SSH エージェントと鍵ファイルのみで認証する。
 	// cb が非 nil の場合、パスワード・パスフレーズ・keyboard-interactive 認証も試行する。
-	Dial(host SSHHost, cb CredentialCallback) (*ssh.Client, error)
+	// ctx がキャンセルされた場合は、TCP 接続・SSH ハンドシェイクを中断して ctx のエラーを返す。
+	// 各段階の待ち時間の上限は host.ConnectTimeout と host.BannerTimeout に従う。
+	Dial(ctx context.Context, host SSHHost, cb CredentialCallback) (*ssh.Client, error)
 
 	// Close は SSH 接続を閉じる。
 	Close() error
 
 	// LocalForward はローカルポートフォワーディングのリスナーを作成する。
-	// localPort でリッスンし、remoteAddr へのトンネルを提供する。
-	LocalForward(ctx context.Context, localPort int, remoteAddr string) (net.Listener, error)
+	// localBindAddr の localPort でリッスンし、remoteAddr へのトンネルを提供する。
+	// localBindAddr が空の場合は LocalhostAddr (127.0.0.1) にバインドする。
+	LocalForward(ctx context.Context, localPort int, remoteAddr string, localBindAddr string) (net.Listener, error)
 
 	// RemoteForward はリモートポートフォワーディングのリスナーを作成する。
 	// リモート側の remotePort でリッスンし、ローカルの localAddr へ転送する。
@@ -34,14 +37,18 @@ type SSHConnection interface {
 	RemoteForward(ctx context.Context, remotePort int, localAddr string, remoteBindAddr string) (net.Listener, error)
 
 	// DynamicForward は SOCKS5 プロキシとして動作するリスナーを作成する。
-	DynamicForward(ctx context.Context, localPort int) (net.Listener, error)
+	// localBindAddr が空の場合は LocalhostAddr (127.0.0.1) にバインドする。
+	DynamicForward(ctx context.Context, localPort int, localBindAddr string) (net.Listener, error)
 
 	// IsAlive は SSH 接続が有効かどうかを返す。
 	IsAlive() bool
 
-	// KeepAlive は指定間隔で SSH 接続の生存確認を行う。
-	// コンテキストがキャンセルされるか、接続が切断されるまでブロックする。
-	KeepAlive(ctx context.Context, interval time.Duration)
+	// KeepAlive は interval ごとに SSH 接続の生存確認を行う。
+	// コンテキストがキャンセルされるか、接続が切断されるか、応答のない確認が countMax 回続くまでブロックする。
+	KeepAlive(ctx context.Context, interval time.Duration, countMax int)
+
+	// RTT は直近の keepalive の往復時間を返す。まだ計測していない場合は 0 を返す。
+	RTT() time.Duration
 }
 
 // SSHManager は SSH 接続のライフサイクルを管理する。
@@ -61,11 +68,13 @@ type SSHManager interface {
 	GetHost(name string) (*SSHHost, error)
 
 	// Connect は指定ホストへ SSH 接続を確立する。既に接続中の場合は何もしない。
-	Connect(hostName string) error
+	// 接続の確立前に ctx がキャンセルされた場合は、接続を中断して ctx のエラーを返す。
+	Connect(ctx context.Context, hostName string) error
 
 	// ConnectWithCallback は指定ホストへ SSH 接続を確立する（クレデンシャルコールバック付き）。
 	// IPC 経由の接続要求で使用され、パスワード・パスフレーズ・keyboard-interactive 認証をサポートする。
-	ConnectWithCallback(hostName string, cb CredentialCallback) error
+	// ctx の扱いは Connect と同じ。
+	ConnectWithCallback(ctx context.Context, hostName string, cb CredentialCallback) error
 
 	// GetPendingAuthHosts は pending_auth 状態のホスト名一覧を返す。
 	GetPendingAuthHosts() []string
@@ -82,6 +91,10 @@ type SSHManager interface {
 	// GetSSHConnection は接続済みホストの SSHConnection を返す。未接続の場合はエラーを返す。
 	GetSSHConnection(hostName string) (SSHConnection, error)
 
+	// UpdateSettings は再接続ポリシー・ホスト別設定・タイムアウトを差し替える。
+	// 以降の接続と再接続から反映し、確立済みの接続は切断しない。
+	UpdateSettings(reconnect ReconnectConfig, hostConfigs map[string]HostConfig, timeouts TimeoutConfig)
+
 	// Subscribe は SSH イベントを受信するチャネルを返す。
 	Subscribe() <-chan SSHEvent
 
@@ -0,0 +1,2 @@
+// Package dialplan は SSH 接続に使うホスト情報（ProxyJump の踏み台の並び・タイムアウト・keepalive）を解決する。
+package dialplan
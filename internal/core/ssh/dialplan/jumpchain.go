@@ -0,0 +1,128 @@
+package dialplan
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+const (
+	// MaxHops は 1 つの接続で経由できる踏み台の上限。設定ミスによる過剰な多段接続を防ぐ。
+	MaxHops = 8
+
+	defaultPort = 22
+)
+
+// Lookup は ssh_config のホスト名（エイリアス）からホスト定義を引く関数。
+type Lookup func(name string) (core.SSHHost, bool)
+
+// Spec は ProxyJump の要素 [user@]host[:port] を分解した値。Port は省略時 0。
+type Spec struct {
+	User string
+	Host string
+	Port int
+}
+
+// ParseSpec は ProxyJump の要素を分解する。ポートが不正な場合は host 全体をホスト名として扱う。
+func ParseSpec(s string) Spec {
+	var spec Spec
+	if i := strings.LastIndex(s, "@"); i >= 0 {
+		spec.User, s = s[:i], s[i+1:]
+	}
+	spec.Host = s
+	if strings.HasPrefix(s, "[") || strings.Count(s, ":") == 1 {
+		if host, port, err := net.SplitHostPort(s); err == nil {
+			if p, err := strconv.Atoi(port); err == nil && p > 0 && p <= 65535 {
+				spec.Host, spec.Port = host, p
+			}
+		}
+	}
+	return spec
+}
+
+// Expand は host の ProxyJump を接続順の踏み台の並びに展開する。
+// 最初の踏み台が ssh_config に定義されていれば、その ProxyJump も再帰的に先頭へ展開する（OpenSSH の挙動に準拠）。
+// ProxyCommand が設定されている場合や ProxyJump が none の場合は nil を返す。
+// 同じホストを二度経由する場合や MaxHops を超える場合はエラーを返す。
+func Expand(host core.SSHHost, lookup Lookup) ([]string, error) {
+	chain, err := expand(host, lookup, map[string]bool{host.Name: true})
+	if err != nil {
+		return nil, err
+	}
+	if len(chain) > MaxHops {
+		return nil, fmt.Errorf("ProxyJump chain for %s has %d hops (max %d)", host.Name, len(chain), MaxHops)
+	}
+	return chain, nil
+}
+
+func expand(host core.SSHHost, lookup Lookup, seen map[string]bool) ([]string, error) {
+	jumps := host.ProxyJump
+	if host.ProxyCommand != "" || len(jumps) == 0 || (len(jumps) == 1 && strings.EqualFold(jumps[0], "none")) {
+		return nil, nil
+	}
+	for _, j := range jumps {
+		name := ParseSpec(j).Host
+		if seen[name] {
+			return nil, fmt.Errorf("ProxyJump loop detected: %s is reached twice", name)
+		}
+		seen[name] = true
+	}
+
+	var chain []string
+	if first, ok := lookup(ParseSpec(jumps[0]).Host); ok {
+		sub, err := expand(first, lookup, seen)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, sub...)
+	}
+	return append(chain, jumps...), nil
+}
+
+// Resolve は host の JumpChain と JumpHosts を解決して設定したコピーを返す。
+func Resolve(host core.SSHHost, lookup Lookup) (core.SSHHost, error) {
+	chain, err := Expand(host, lookup)
+	if err != nil {
+		return host, err
+	}
+	host.JumpChain = chain
+	host.JumpHosts = Hosts(chain, lookup, host)
+	return host, nil
+}
+
+// Hosts は chain の各要素を接続情報に解決する。
+// ssh_config に定義されたホストはその設定を使い、[user@]host[:port] の指定で上書きする。
+// 未定義のホストはポート 22、ユーザー未指定なら target のユーザーで接続する。
+// ProxyCommand は先頭の踏み台でのみ使われる。
+func Hosts(chain []string, lookup Lookup, target core.SSHHost) []core.SSHHost {
+	hosts := make([]core.SSHHost, 0, len(chain))
+	for _, s := range chain {
+		spec := ParseSpec(s)
+		h, ok := lookup(spec.Host)
+		if !ok {
+			h = core.SSHHost{Name: s, HostName: spec.Host, Port: defaultPort, User: target.User}
+		}
+		h.ProxyJump, h.JumpChain, h.JumpHosts = nil, nil, nil
+		if spec.User != "" {
+			h.User = spec.User
+		}
+		if spec.Port > 0 {
+			h.Port = spec.Port
+		}
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+// Contains は chain が name のホストを経由するかを返す。
+func Contains(chain []string, name string) bool {
+	for _, s := range chain {
+		if ParseSpec(s).Host == name {
+			return true
+		}
+	}
+	return false
+}
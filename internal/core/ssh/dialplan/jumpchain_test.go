@@ -0,0 +1,104 @@
+package dialplan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func lookupOf(hosts ...core.SSHHost) Lookup {
+	return func(name string) (core.SSHHost, bool) {
+		for _, h := range hosts {
+			if h.Name == name {
+				return h, true
+			}
+		}
+		return core.SSHHost{}, false
+	}
+}
+
+func TestParseSpec(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Spec
+	}{
+		{"bastion", Spec{Host: "bastion"}},
+		{"admin@bastion:2222", Spec{User: "admin", Host: "bastion", Port: 2222}},
+		{"[::1]:2200", Spec{Host: "::1", Port: 2200}},
+		{"fe80::1", Spec{Host: "fe80::1"}},
+		{"bastion:ssh", Spec{Host: "bastion:ssh"}},
+	}
+	for _, tt := range tests {
+		if got := ParseSpec(tt.in); got != tt.want {
+			t.Errorf("ParseSpec(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	lookup := lookupOf(
+		core.SSHHost{Name: "edge"},
+		core.SSHHost{Name: "jump1", ProxyJump: []string{"edge"}},
+		core.SSHHost{Name: "loop-a", ProxyJump: []string{"loop-b"}},
+		core.SSHHost{Name: "loop-b", ProxyJump: []string{"loop-a"}},
+	)
+
+	tests := []struct {
+		name    string
+		host    core.SSHHost
+		want    []string
+		wantErr string
+	}{
+		{"direct", core.SSHHost{Name: "db"}, nil, ""},
+		{"none", core.SSHHost{Name: "db", ProxyJump: []string{"none"}}, nil, ""},
+		{"proxycommand wins", core.SSHHost{Name: "db", ProxyJump: []string{"jump1"}, ProxyCommand: "nc %h %p"}, nil, ""},
+		{"multi-hop", core.SSHHost{Name: "db", ProxyJump: []string{"jump1", "u@jump2:2222"}}, []string{"edge", "jump1", "u@jump2:2222"}, ""},
+		{"loop", core.SSHHost{Name: "db", ProxyJump: []string{"loop-a"}}, nil, "loop"},
+		{"self", core.SSHHost{Name: "db", ProxyJump: []string{"db"}}, nil, "loop"},
+		{"too many hops", core.SSHHost{Name: "db", ProxyJump: strings.Split("a,b,c,d,e,f,g,h,i", ",")}, nil, "max"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.host, lookup)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHosts(t *testing.T) {
+	lookup := lookupOf(core.SSHHost{Name: "jump1", HostName: "10.0.0.1", Port: 22, User: "ops", ProxyJump: []string{"edge"}})
+	target := core.SSHHost{Name: "db", User: "app"}
+
+	got := Hosts([]string{"jump1", "root@jump1:2200", "10.0.0.9"}, lookup, target)
+	want := []core.SSHHost{
+		{Name: "jump1", HostName: "10.0.0.1", Port: 22, User: "ops"},
+		{Name: "jump1", HostName: "10.0.0.1", Port: 2200, User: "root"},
+		{Name: "10.0.0.9", HostName: "10.0.0.9", Port: 22, User: "app"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Hosts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestContains(t *testing.T) {
+	chain := []string{"edge", "admin@jump1:2222"}
+	if !Contains(chain, "jump1") || !Contains(chain, "edge") {
+		t.Error("Contains should match host part of each spec")
+	}
+	if Contains(chain, "admin") {
+		t.Error("Contains should not match user part")
+	}
+}
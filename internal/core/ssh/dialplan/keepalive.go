@@ -0,0 +1,39 @@
+package dialplan
+
+import (
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+const (
+	// DefaultKeepAliveInterval は keepalive の間隔がどこにも指定されていない場合の値。
+	DefaultKeepAliveInterval = 30 * time.Second
+	// DefaultKeepAliveCountMax は応答のない keepalive の許容回数がどこにも指定されていない場合の値（OpenSSH と同じ）。
+	DefaultKeepAliveCountMax = 3
+)
+
+// KeepAlive は keepalive の間隔と、応答のない keepalive を接続断とみなすまでの回数を解決して
+// host の ServerAliveInterval・ServerAliveCountMax に設定する。
+// 優先順位はホスト別設定、ssh_config の ServerAliveInterval・ServerAliveCountMax、グローバル設定、既定値の順。
+func KeepAlive(host core.SSHHost, global core.ReconnectConfig, hc core.HostConfig) core.SSHHost {
+	switch {
+	case hc.KeepAliveInterval != nil && hc.KeepAliveInterval.Duration > 0:
+		host.ServerAliveInterval = hc.KeepAliveInterval.Duration
+	case host.ServerAliveInterval > 0:
+	case global.KeepAliveInterval.Duration > 0:
+		host.ServerAliveInterval = global.KeepAliveInterval.Duration
+	default:
+		host.ServerAliveInterval = DefaultKeepAliveInterval
+	}
+	switch {
+	case hc.KeepAliveCountMax != nil && *hc.KeepAliveCountMax > 0:
+		host.ServerAliveCountMax = *hc.KeepAliveCountMax
+	case host.ServerAliveCountMax > 0:
+	case global.KeepAliveCountMax > 0:
+		host.ServerAliveCountMax = global.KeepAliveCountMax
+	default:
+		host.ServerAliveCountMax = DefaultKeepAliveCountMax
+	}
+	return host
+}
@@ -0,0 +1,41 @@
+package dialplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestKeepAlive(t *testing.T) {
+	global := core.ReconnectConfig{KeepAliveInterval: core.Duration{Duration: 20 * time.Second}, KeepAliveCountMax: 5}
+
+	tests := []struct {
+		name         string
+		host         core.SSHHost
+		global       core.ReconnectConfig
+		hc           core.HostConfig
+		wantInterval time.Duration
+		wantCount    int
+	}{
+		{"defaults", core.SSHHost{}, core.ReconnectConfig{}, core.HostConfig{}, 30 * time.Second, 3},
+		{"global", core.SSHHost{}, global, core.HostConfig{}, 20 * time.Second, 5},
+		{"ssh_config wins over global", core.SSHHost{ServerAliveInterval: 10 * time.Second, ServerAliveCountMax: 2}, global, core.HostConfig{}, 10 * time.Second, 2},
+		{
+			"host override wins",
+			core.SSHHost{ServerAliveInterval: 10 * time.Second, ServerAliveCountMax: 2},
+			global,
+			core.HostConfig{KeepAliveInterval: durPtr(5 * time.Second), KeepAliveCountMax: intPtr(1)},
+			5 * time.Second, 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := KeepAlive(tt.host, tt.global, tt.hc)
+			if got.ServerAliveInterval != tt.wantInterval || got.ServerAliveCountMax != tt.wantCount {
+				t.Errorf("keepalive = (%v, %d), want (%v, %d)",
+					got.ServerAliveInterval, got.ServerAliveCountMax, tt.wantInterval, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package dialplan
+
+import "github.com/ousiassllc/moleport/internal/core"
+
+// Reconnect はグローバルの再接続設定にホスト別オーバーライドをマージして返す。
+func Reconnect(global core.ReconnectConfig, override *core.ReconnectOverride) core.ReconnectConfig {
+	if override == nil {
+		return global
+	}
+	result := global
+	if override.Enabled != nil {
+		result.Enabled = *override.Enabled
+	}
+	if override.MaxRetries != nil {
+		result.MaxRetries = *override.MaxRetries
+	}
+	if override.InitialDelay != nil {
+		result.InitialDelay = *override.InitialDelay
+	}
+	if override.MaxDelay != nil {
+		result.MaxDelay = *override.MaxDelay
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package dialplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func boolPtr(b bool) *bool { return &b }
+func intPtr(i int) *int    { return &i }
+
+func TestReconnect(t *testing.T) {
+	global := core.ReconnectConfig{
+		Enabled:      true,
+		MaxRetries:   5,
+		InitialDelay: core.Duration{Duration: 1 * time.Second},
+		MaxDelay:     core.Duration{Duration: 30 * time.Second},
+	}
+
+	tests := []struct {
+		name     string
+		override *core.ReconnectOverride
+		want     core.ReconnectConfig
+	}{
+		{
+			name:     "nil override returns global unchanged",
+			override: nil,
+			want:     global,
+		},
+		{
+			name:     "override Enabled only",
+			override: &core.ReconnectOverride{Enabled: boolPtr(false)},
+			want: core.ReconnectConfig{
+				Enabled:      false,
+				MaxRetries:   5,
+				InitialDelay: core.Duration{Duration: 1 * time.Second},
+				MaxDelay:     core.Duration{Duration: 30 * time.Second},
+			},
+		},
+		{
+			name:     "override MaxRetries only",
+			override: &core.ReconnectOverride{MaxRetries: intPtr(10)},
+			want: core.ReconnectConfig{
+				Enabled:      true,
+				MaxRetries:   10,
+				InitialDelay: core.Duration{Duration: 1 * time.Second},
+				MaxDelay:     core.Duration{Duration: 30 * time.Second},
+			},
+		},
+		{
+			name: "override all fields",
+			override: &core.ReconnectOverride{
+				Enabled:      boolPtr(false),
+				MaxRetries:   intPtr(2),
+				InitialDelay: durPtr(500 * time.Millisecond),
+				MaxDelay:     durPtr(10 * time.Second),
+			},
+			want: core.ReconnectConfig{
+				Enabled:      false,
+				MaxRetries:   2,
+				InitialDelay: core.Duration{Duration: 500 * time.Millisecond},
+				MaxDelay:     core.Duration{Duration: 10 * time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Reconnect(global, tt.override)
+			if got.Enabled != tt.want.Enabled {
+				t.Errorf("Enabled = %v, want %v", got.Enabled, tt.want.Enabled)
+			}
+			if got.MaxRetries != tt.want.MaxRetries {
+				t.Errorf("MaxRetries = %v, want %v", got.MaxRetries, tt.want.MaxRetries)
+			}
+			if got.InitialDelay != tt.want.InitialDelay {
+				t.Errorf("InitialDelay = %v, want %v", got.InitialDelay, tt.want.InitialDelay)
+			}
+			if got.MaxDelay != tt.want.MaxDelay {
+				t.Errorf("MaxDelay = %v, want %v", got.MaxDelay, tt.want.MaxDelay)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package dialplan
+
+import "github.com/ousiassllc/moleport/internal/core"
+
+// Timeouts はグローバル設定とホスト別オーバーライドを host に適用して返す。
+func Timeouts(host core.SSHHost, global core.TimeoutConfig, hc core.HostConfig) core.SSHHost {
+	if host.ConnectTimeout <= 0 {
+		host.ConnectTimeout = global.ConnectTimeout.Duration
+	}
+	host.BannerTimeout = global.BannerTimeout.Duration
+	if hc.ConnectTimeout != nil {
+		host.ConnectTimeout = hc.ConnectTimeout.Duration
+	}
+	if hc.BannerTimeout != nil {
+		host.BannerTimeout = hc.BannerTimeout.Duration
+	}
+	return host
+}
@@ -0,0 +1,43 @@
+package dialplan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func durPtr(d time.Duration) *core.Duration { return &core.Duration{Duration: d} }
+
+func TestTimeouts(t *testing.T) {
+	global := core.TimeoutConfig{
+		ConnectTimeout: core.Duration{Duration: 10 * time.Second},
+		BannerTimeout:  core.Duration{Duration: 15 * time.Second},
+	}
+
+	tests := []struct {
+		name        string
+		sshConfig   time.Duration
+		hc          core.HostConfig
+		wantConnect time.Duration
+		wantBanner  time.Duration
+	}{
+		{"global defaults", 0, core.HostConfig{}, 10 * time.Second, 15 * time.Second},
+		{"ssh_config ConnectTimeout", 3 * time.Second, core.HostConfig{}, 3 * time.Second, 15 * time.Second},
+		{
+			"host override wins",
+			3 * time.Second,
+			core.HostConfig{ConnectTimeout: durPtr(5 * time.Second), BannerTimeout: durPtr(30 * time.Second)},
+			5 * time.Second, 30 * time.Second,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Timeouts(core.SSHHost{Name: "h", ConnectTimeout: tt.sshConfig}, global, tt.hc)
+			if got.ConnectTimeout != tt.wantConnect || got.BannerTimeout != tt.wantBanner {
+				t.Errorf("timeouts = (%v, %v), want (%v, %v)",
+					got.ConnectTimeout, got.BannerTimeout, tt.wantConnect, tt.wantBanner)
+			}
+		})
+	}
+}
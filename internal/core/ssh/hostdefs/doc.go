@@ -0,0 +1,2 @@
+// Package hostdefs は config.yaml で定義されたホストを ssh_config のホスト一覧に統合する。
+package hostdefs
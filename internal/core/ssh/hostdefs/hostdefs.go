@@ -0,0 +1,100 @@
+package hostdefs
+
+import (
+	"errors"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// parser は ssh_config の解析結果に config.yaml のホスト定義を加える core.SSHConfigParser。
+type parser struct {
+	base core.SSHConfigParser
+	defs func() []core.HostDefinition
+}
+
+// NewParser は base の解析結果に defs() のホストを加える core.SSHConfigParser を返す。
+// defs は解析のたびに呼び出すため、host.add などで追加した定義も次の ReloadHosts で反映される。
+func NewParser(base core.SSHConfigParser, defs func() []core.HostDefinition) core.SSHConfigParser {
+	return &parser{base: base, defs: defs}
+}
+
+// Parse は configPath の ssh_config を解析し、ホスト定義を統合した一覧を返す。
+// ホスト定義がある場合、ssh_config が存在しなくてもホスト定義のみの一覧を返す。
+func (p *parser) Parse(configPath string) ([]core.SSHHost, error) {
+	defs := p.defs()
+	hosts, err := p.base.Parse(configPath)
+	if err != nil && (len(defs) == 0 || !errors.Is(err, fs.ErrNotExist)) {
+		return nil, err
+	}
+	return Merge(hosts, defs), nil
+}
+
+// Merge は hosts の後ろに defs のホストを加える。
+// 不正な定義と、hosts または先行する定義と同名の定義は警告を記録して無視する。
+func Merge(hosts []core.SSHHost, defs []core.HostDefinition) []core.SSHHost {
+	seen := make(map[string]bool, len(hosts)+len(defs))
+	for _, h := range hosts {
+		seen[h.Name] = true
+	}
+	for _, d := range defs {
+		if err := d.Validate(); err != nil {
+			slog.Warn("ignoring invalid host definition", "host", d.Name, "error", err)
+			continue
+		}
+		if seen[d.Name] {
+			slog.Warn("ignoring host definition that duplicates an existing host", "host", d.Name)
+			continue
+		}
+		seen[d.Name] = true
+		hosts = append(hosts, ToSSHHost(d))
+	}
+	return hosts
+}
+
+// ToSSHHost はホスト定義を SSHHost に変換する。ポートとユーザーの既定値は ssh_config と同じく 22 とローカルユーザー。
+func ToSSHHost(d core.HostDefinition) core.SSHHost {
+	host := core.SSHHost{
+		Name:     d.Name,
+		HostName: d.HostName,
+		Port:     d.Port,
+		User:     d.User,
+		State:    core.Disconnected,
+		Source:   core.HostSourceConfig,
+	}
+	if host.Port == 0 {
+		host.Port = 22
+	}
+	if host.User == "" {
+		if u, err := user.Current(); err == nil {
+			host.User = u.Username
+		}
+	}
+	if d.IdentityFile != "" {
+		host.IdentityFiles = []string{expandTilde(d.IdentityFile)}
+	}
+	for _, jump := range strings.Split(d.ProxyJump, ",") {
+		if jump = strings.TrimSpace(jump); jump != "" {
+			host.ProxyJump = append(host.ProxyJump, jump)
+		}
+	}
+	return host
+}
+
+// expandTilde は先頭の "~/" をホームディレクトリに展開する。
+func expandTilde(path string) string {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, rest)
+}
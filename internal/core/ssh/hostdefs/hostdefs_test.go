@@ -0,0 +1,60 @@
+package hostdefs
+
+import (
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+type stubParser struct {
+	hosts []core.SSHHost
+	err   error
+}
+
+func (p stubParser) Parse(string) ([]core.SSHHost, error) { return p.hosts, p.err }
+
+func TestParser_Merge(t *testing.T) {
+	base := stubParser{hosts: []core.SSHHost{{Name: "web", Source: core.HostSourceSSHConfig}}}
+	defs := []core.HostDefinition{
+		{Name: "bastion", HostName: "203.0.113.10", Port: 2222, User: "ops", IdentityFile: "/keys/ops", ProxyJump: "web, edge"},
+		{Name: "web", HostName: "shadowed.example.com"},
+		{Name: "broken"},
+		{Name: "edge", HostName: "edge.example.com"},
+	}
+	hosts, err := NewParser(base, func() []core.HostDefinition { return defs }).Parse("/unused")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var names []string
+	for _, h := range hosts {
+		names = append(names, h.Name)
+	}
+	if fmt.Sprint(names) != "[web bastion edge]" {
+		t.Fatalf("hosts = %v, want [web bastion edge]", names)
+	}
+	b := hosts[1]
+	if b.Source != core.HostSourceConfig || b.Port != 2222 || b.User != "ops" || b.State != core.Disconnected {
+		t.Errorf("bastion = %+v", b)
+	}
+	if fmt.Sprint(b.IdentityFiles, b.ProxyJump) != "[/keys/ops] [web edge]" {
+		t.Errorf("IdentityFiles = %v, ProxyJump = %v", b.IdentityFiles, b.ProxyJump)
+	}
+	if hosts[2].Port != 22 {
+		t.Errorf("edge Port = %d, want default 22", hosts[2].Port)
+	}
+}
+
+func TestParser_MissingSSHConfig(t *testing.T) {
+	missing := stubParser{err: fmt.Errorf("failed to open ssh config: %w", fs.ErrNotExist)}
+	defs := []core.HostDefinition{{Name: "bastion", HostName: "203.0.113.10"}}
+
+	hosts, err := NewParser(missing, func() []core.HostDefinition { return defs }).Parse("/missing")
+	if err != nil || len(hosts) != 1 {
+		t.Errorf("Parse() = %v, %v; want host definitions only", hosts, err)
+	}
+	if _, err := NewParser(missing, func() []core.HostDefinition { return nil }).Parse("/missing"); err == nil {
+		t.Error("missing ssh_config without host definitions should be an error")
+	}
+}
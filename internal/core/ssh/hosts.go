@@ -21,6 +21,7 @@ func (m *sshManager) LoadHosts() ([]core.SSHHost, error) {
 	for i, h := range hosts {
 		m.hostsMap[h.Name] = i
 	}
+	m.setJumpChains()
 
 	return m.copyHosts(), nil
 }
@@ -55,6 +56,7 @@ func (m *sshManager) ReloadHosts() ([]core.SSHHost, error) {
 	for i, h := range hosts {
 		m.hostsMap[h.Name] = i
 	}
+	m.setJumpChains()
 
 	return m.copyHosts(), nil
 }
@@ -29,7 +29,7 @@ func TestSSHManager_LoadHosts(t *testing.T) {
 
 func TestSSHManager_LoadHosts_ParseError(t *testing.T) {
 	parser := &mockSSHConfigParser{err: fmt.Errorf("parse error")}
-	sm := NewSSHManager(context.Background(), parser, nil, "/fake/ssh/config", core.ReconnectConfig{}, nil)
+	sm := NewSSHManager(context.Background(), parser, nil, "/fake/ssh/config", core.ReconnectConfig{}, nil, core.TimeoutConfig{})
 
 	_, err := sm.LoadHosts()
 	if err == nil {
@@ -79,7 +79,7 @@ func TestSSHManager_ReloadHosts_PreservesState(t *testing.T) {
 		t.Fatalf("LoadHosts() error = %v", err)
 	}
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
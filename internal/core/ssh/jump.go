@@ -0,0 +1,66 @@
+package ssh
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	cryptossh "golang.org/x/crypto/ssh"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ssh/dialplan"
+)
+
+// lookupHost は名前でホスト定義を引く。呼び出し側で m.mu を保持すること。
+func (m *sshManager) lookupHost(name string) (core.SSHHost, bool) {
+	idx, ok := m.hostsMap[name]
+	if !ok {
+		return core.SSHHost{}, false
+	}
+	return m.hosts[idx], true
+}
+
+// setJumpChains は各ホストの踏み台の並びを表示用に解決する。呼び出し側で m.mu を保持すること。
+func (m *sshManager) setJumpChains() {
+	for i := range m.hosts {
+		chain, err := dialplan.Expand(m.hosts[i], m.lookupHost)
+		if err != nil {
+			slog.Warn("invalid ProxyJump", "host", m.hosts[i].Name, "error", err)
+		}
+		m.hosts[i].JumpChain = chain
+	}
+}
+
+// dial は踏み台の並びとタイムアウトを解決して host へ接続し、経由した踏み台を返す。
+// ctx がキャンセルされた場合は接続を中断する。
+func (m *sshManager) dial(ctx context.Context, conn core.SSHConnection, host core.SSHHost, cb core.CredentialCallback) (*cryptossh.Client, []string, error) {
+	m.mu.RLock()
+	resolved, err := dialplan.Resolve(host, m.lookupHost)
+	m.mu.RUnlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range resolved.JumpHosts {
+		resolved.JumpHosts[i] = m.withHostConfig(resolved.JumpHosts[i])
+	}
+	client, err := conn.Dial(ctx, m.withHostConfig(resolved), cb)
+	return client, resolved.JumpChain, err
+}
+
+// disconnectDependents は name を踏み台として経由している接続を切断する。
+func (m *sshManager) disconnectDependents(name string) {
+	m.mu.RLock()
+	var deps []string
+	for host, hc := range m.conns {
+		if dialplan.Contains(hc.jumps, name) {
+			deps = append(deps, host)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Strings(deps)
+	for _, dep := range deps {
+		slog.Info("disconnecting host routed through jump host", "host", dep, "jump_host", name)
+		_ = m.Disconnect(dep)
+	}
+}
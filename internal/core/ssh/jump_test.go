@@ -0,0 +1,49 @@
+package ssh
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestSSHManager_JumpChainAndCascade(t *testing.T) {
+	hosts := []core.SSHHost{
+		{Name: "bastion", HostName: "10.0.0.1", Port: 22, User: "ops"},
+		{Name: "db", HostName: "10.0.1.5", Port: 22, User: "app", ProxyJump: []string{"bastion", "inner:2222"}},
+	}
+	var dialed core.SSHHost
+	sm := newTestSSHManager(hosts, func() core.SSHConnection {
+		mock := &mockSSHConnection{isAlive: true}
+		mock.dialF = func(h core.SSHHost) {
+			if h.Name == "db" {
+				dialed = h
+			}
+		}
+		return mock
+	})
+	defer sm.Close()
+
+	loaded, err := sm.LoadHosts()
+	if err != nil {
+		t.Fatalf("LoadHosts() error = %v", err)
+	}
+	if got := loaded[1].JumpChain; len(got) != 2 || got[0] != "bastion" || got[1] != "inner:2222" {
+		t.Errorf("JumpChain = %v, want [bastion inner:2222]", got)
+	}
+
+	for _, name := range []string{"bastion", "db"} {
+		if err := sm.Connect(t.Context(), name); err != nil {
+			t.Fatalf("Connect(%s) error = %v", name, err)
+		}
+	}
+	if len(dialed.JumpHosts) != 2 || dialed.JumpHosts[0].HostName != "10.0.0.1" || dialed.JumpHosts[1].Port != 2222 {
+		t.Errorf("Dial got JumpHosts %+v", dialed.JumpHosts)
+	}
+
+	if err := sm.Disconnect("bastion"); err != nil {
+		t.Fatalf("Disconnect() error = %v", err)
+	}
+	if sm.IsConnected("db") {
+		t.Error("db should be disconnected together with its jump host")
+	}
+}
@@ -16,13 +16,13 @@ func isAuthFailure(err error) bool {
 }
 
 // Connect はホストへ SSH 接続を確立する。
-func (m *sshManager) Connect(hostName string) error {
-	return m.connectInternal(hostName, nil)
+func (m *sshManager) Connect(ctx context.Context, hostName string) error {
+	return m.connectInternal(ctx, hostName, nil)
 }
 
 // ConnectWithCallback はホストへ SSH 接続を確立する（クレデンシャルコールバック付き）。
-func (m *sshManager) ConnectWithCallback(hostName string, cb core.CredentialCallback) error {
-	return m.connectInternal(hostName, cb)
+func (m *sshManager) ConnectWithCallback(ctx context.Context, hostName string, cb core.CredentialCallback) error {
+	return m.connectInternal(ctx, hostName, cb)
 }
 
 // GetPendingAuthHosts は pending_auth 状態のホスト名一覧を返す。
@@ -39,7 +39,10 @@ func (m *sshManager) GetPendingAuthHosts() []string {
 }
 
 // connectInternal は Connect と ConnectWithCallback の共通実装。
-func (m *sshManager) connectInternal(hostName string, cb core.CredentialCallback) error {
+func (m *sshManager) connectInternal(ctx context.Context, hostName string, cb core.CredentialCallback) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	m.mu.Lock()
 	idx, ok := m.hostsMap[hostName]
 	if !ok {
@@ -62,7 +65,12 @@ func (m *sshManager) connectInternal(hostName string, cb core.CredentialCallback
 	m.mu.Unlock()
 
 	conn := m.connFactory()
-	client, err := conn.Dial(host, cb)
+	client, jumps, err := m.dial(ctx, conn, host, cb)
+	if err == nil && ctx.Err() != nil {
+		// 接続の確立中に呼び出し元が取り消した場合は、確立した接続を閉じて中断する
+		_ = conn.Close()
+		err = ctx.Err()
+	}
 	if err != nil {
 		m.mu.Lock()
 		// Connecting プレースホルダーを削除
@@ -70,6 +78,14 @@ func (m *sshManager) connectInternal(hostName string, cb core.CredentialCallback
 			delete(m.conns, hostName)
 		}
 
+		if ctx.Err() != nil {
+			if i, ok := m.hostsMap[hostName]; ok {
+				m.hosts[i].State = core.Disconnected
+			}
+			m.mu.Unlock()
+			return fmt.Errorf("connect to %s: %w", hostName, ctx.Err())
+		}
+
 		// 認証失敗の場合は PendingAuth 状態にする（コールバックなしの場合のみ）
 		if cb == nil && isAuthFailure(err) {
 			if i, ok := m.hostsMap[hostName]; ok {
@@ -84,17 +100,21 @@ func (m *sshManager) connectInternal(hostName string, cb core.CredentialCallback
 			m.hosts[i].State = core.ConnectionError
 		}
 		m.mu.Unlock()
+		if isAuthFailure(err) {
+			err = fmt.Errorf("%w: %w", core.ErrAuthFailed, err)
+		}
 		m.events.Emit(core.SSHEvent{Type: core.SSHEventError, HostName: hostName, Error: err})
 		return fmt.Errorf("failed to connect to %s: %w", hostName, err)
 	}
 
-	ctx, cancel := context.WithCancel(m.ctx) //nolint:gosec // cancel は hc.cancel に保持され Disconnect 時に呼ばれる
+	connCtx, cancel := context.WithCancel(m.ctx) //nolint:gosec // cancel は hc.cancel に保持され Disconnect 時に呼ばれる
 	hc := &hostConnection{
 		conn:   conn,
 		client: client,
-		ctx:    ctx,
+		ctx:    connCtx,
 		cancel: cancel,
 		state:  core.Connected,
+		jumps:  jumps,
 	}
 
 	m.mu.Lock()
@@ -107,23 +127,13 @@ func (m *sshManager) connectInternal(hostName string, cb core.CredentialCallback
 	m.events.Emit(core.SSHEvent{Type: core.SSHEventConnected, HostName: hostName})
 	slog.Info("SSH connected", "host", hostName)
 
-	// KeepAlive goroutine
 	// Connected イベント emit 後に起動して、イベント順序を保証する
-	go func() {
-		conn.KeepAlive(ctx, m.keepAliveInterval())
-		// KeepAlive が終了した場合（コンテキストキャンセル以外）、切断を検出
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			m.handleDisconnect(hostName)
-		}
-	}()
+	m.startKeepAlive(connCtx, conn, host)
 
 	return nil
 }
 
-// Disconnect はホストとの接続を切断する。
+// Disconnect はホストとの接続を切断する。このホストを踏み台として経由している接続も切断する。
 func (m *sshManager) Disconnect(hostName string) error {
 	m.mu.Lock()
 	// 進行中の再接続をキャンセル
@@ -154,6 +164,7 @@ func (m *sshManager) Disconnect(hostName string) error {
 
 	m.events.Emit(core.SSHEvent{Type: core.SSHEventDisconnected, HostName: hostName})
 	slog.Info("SSH disconnected", "host", hostName)
+	m.disconnectDependents(hostName)
 	return nil
 }
 
@@ -1,6 +1,8 @@
 package ssh
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -22,7 +24,7 @@ func TestSSHManager_Subscribe_MultipleSubscribers(t *testing.T) {
 	ch1 := sm.Subscribe()
 	ch2 := sm.Subscribe()
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
@@ -52,7 +54,7 @@ func TestSSHManager_Close(t *testing.T) {
 
 	events := sm.Subscribe()
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
@@ -100,7 +102,7 @@ func TestSSHManager_Connect_ConcurrentSameHost(t *testing.T) {
 		wg.Add(1)
 		go func(idx int) {
 			defer wg.Done()
-			errs[idx] = sm.Connect("server1")
+			errs[idx] = sm.Connect(t.Context(), "server1")
 		}(i)
 	}
 	wg.Wait()
@@ -138,7 +140,7 @@ func TestSSHManager_Connect_AuthFailure_PendingAuth(t *testing.T) {
 
 	events := sm.Subscribe()
 
-	err := sm.Connect("server1")
+	err := sm.Connect(t.Context(), "server1")
 	if err == nil {
 		t.Fatal("Connect() should return error on auth failure")
 	}
@@ -180,7 +182,7 @@ func TestSSHManager_ConnectWithCallback_Success(t *testing.T) {
 		return core.CredentialResponse{Value: "secret"}, nil
 	}
 
-	if err := sm.ConnectWithCallback("server1", cb); err != nil {
+	if err := sm.ConnectWithCallback(context.Background(), "server1", cb); err != nil {
 		t.Fatalf("ConnectWithCallback() error = %v", err)
 	}
 
@@ -212,7 +214,7 @@ func TestSSHManager_ConnectWithCallback_ClearsPendingAuth(t *testing.T) {
 	}
 
 	// まず Connect で PendingAuth にする
-	_ = sm.Connect("server1")
+	_ = sm.Connect(t.Context(), "server1")
 	host, _ := sm.GetHost("server1")
 	if host.State != core.PendingAuth {
 		t.Fatalf("expected PendingAuth, got %v", host.State)
@@ -222,7 +224,7 @@ func TestSSHManager_ConnectWithCallback_ClearsPendingAuth(t *testing.T) {
 	cb := func(req core.CredentialRequest) (core.CredentialResponse, error) {
 		return core.CredentialResponse{Value: "password"}, nil
 	}
-	if err := sm.ConnectWithCallback("server1", cb); err != nil {
+	if err := sm.ConnectWithCallback(context.Background(), "server1", cb); err != nil {
 		t.Fatalf("ConnectWithCallback() error = %v", err)
 	}
 
@@ -253,8 +255,8 @@ func TestSSHManager_GetPendingAuthHosts(t *testing.T) {
 	}
 
 	// 両方のホストに接続を試行（認証失敗 → PendingAuth）
-	_ = sm.Connect("server1")
-	_ = sm.Connect("server2")
+	_ = sm.Connect(t.Context(), "server1")
+	_ = sm.Connect(t.Context(), "server2")
 
 	pendingHosts := sm.GetPendingAuthHosts()
 	if len(pendingHosts) != 2 {
@@ -263,3 +265,23 @@ func TestSSHManager_GetPendingAuthHosts(t *testing.T) {
 
 	sm.Close()
 }
+
+func TestSSHManager_ConnectWithCallback_AuthFailureIsTyped(t *testing.T) {
+	sm := newTestSSHManager(testHosts(), func() core.SSHConnection {
+		return &mockSSHConnection{dialErr: fmt.Errorf("ssh: unable to authenticate")}
+	})
+	defer sm.Close()
+	if _, err := sm.LoadHosts(); err != nil {
+		t.Fatalf("LoadHosts() error = %v", err)
+	}
+
+	cb := func(core.CredentialRequest) (core.CredentialResponse, error) {
+		return core.CredentialResponse{Value: "wrong"}, nil
+	}
+	if err := sm.ConnectWithCallback(t.Context(), "server1", cb); !errors.Is(err, core.ErrAuthFailed) {
+		t.Errorf("ConnectWithCallback() error = %v, want ErrAuthFailed", err)
+	}
+	if err := sm.Connect(t.Context(), "missing"); !errors.Is(err, core.ErrHostNotFound) {
+		t.Errorf("Connect(missing) error = %v, want ErrHostNotFound", err)
+	}
+}
@@ -30,11 +30,12 @@ func TestSSHManager_KeepAliveInterval(t *testing.T) {
 				KeepAliveInterval: core.Duration{Duration: 45 * time.Second},
 			},
 			nil,
+			core.TimeoutConfig{},
 		)
 		if _, err := sm.LoadHosts(); err != nil {
 			t.Fatalf("LoadHosts() error = %v", err)
 		}
-		if err := sm.Connect("server1"); err != nil {
+		if err := sm.Connect(t.Context(), "server1"); err != nil {
 			t.Fatalf("Connect() error = %v", err)
 		}
 		select {
@@ -63,11 +64,12 @@ func TestSSHManager_KeepAliveInterval(t *testing.T) {
 			"/fake/ssh/config",
 			core.ReconnectConfig{Enabled: false},
 			nil,
+			core.TimeoutConfig{},
 		)
 		if _, err := sm.LoadHosts(); err != nil {
 			t.Fatalf("LoadHosts() error = %v", err)
 		}
-		if err := sm.Connect("server1"); err != nil {
+		if err := sm.Connect(t.Context(), "server1"); err != nil {
 			t.Fatalf("Connect() error = %v", err)
 		}
 		select {
@@ -96,7 +98,7 @@ func TestSSHManager_Connect_Disconnect(t *testing.T) {
 
 	events := sm.Subscribe()
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
@@ -153,12 +155,12 @@ func TestSSHManager_Connect_AlreadyConnected(t *testing.T) {
 		t.Fatalf("LoadHosts() error = %v", err)
 	}
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
 	// 二回目の接続はスキップされる
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("second Connect() error = %v", err)
 	}
 
@@ -179,7 +181,7 @@ func TestSSHManager_Connect_DialError(t *testing.T) {
 
 	events := sm.Subscribe()
 
-	err := sm.Connect("server1")
+	err := sm.Connect(t.Context(), "server1")
 	if err == nil {
 		t.Fatal("Connect() should return error on dial failure")
 	}
@@ -209,7 +211,7 @@ func TestSSHManager_Connect_HostNotFound(t *testing.T) {
 		t.Fatalf("LoadHosts() error = %v", err)
 	}
 
-	err := sm.Connect("nonexistent")
+	err := sm.Connect(t.Context(), "nonexistent")
 	if err == nil {
 		t.Fatal("Connect() should return error for nonexistent host")
 	}
@@ -250,7 +252,7 @@ func TestSSHManager_GetConnection(t *testing.T) {
 		t.Fatal("GetConnection() should return error when not connected")
 	}
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
@@ -280,7 +282,7 @@ func TestSSHManager_GetSSHConnection(t *testing.T) {
 		t.Fatal("GetSSHConnection() should return error when not connected")
 	}
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
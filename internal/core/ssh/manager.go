@@ -3,26 +3,13 @@ package ssh
 import (
 	"context"
 	"sync"
-	"time"
 
 	cryptossh "golang.org/x/crypto/ssh"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/event"
 )
 
-const (
-	// defaultKeepAliveInterval は KeepAliveInterval が未設定時のフォールバック値。
-	defaultKeepAliveInterval = 30 * time.Second
-)
-
-// keepAliveInterval は設定された KeepAlive 間隔を返す。未設定の場合はデフォルト値を返す。
-func (m *sshManager) keepAliveInterval() time.Duration {
-	if d := m.reconnectCfg.KeepAliveInterval.Duration; d > 0 {
-		return d
-	}
-	return defaultKeepAliveInterval
-}
-
 // hostConnection は個々のホストへの接続状態を保持する。
 type hostConnection struct {
 	conn   core.SSHConnection
@@ -30,6 +17,7 @@ type hostConnection struct {
 	ctx    context.Context
 	cancel context.CancelFunc
 	state  core.ConnectionState
+	jumps  []string // 経由している踏み台（接続順）
 }
 
 type sshManager struct {
@@ -40,12 +28,13 @@ type sshManager struct {
 	configPath   string
 	reconnectCfg core.ReconnectConfig
 	hostConfigs  map[string]core.HostConfig
+	timeouts     core.TimeoutConfig
 
 	hosts            []core.SSHHost
 	hostsMap         map[string]int
 	conns            map[string]*hostConnection
 	reconnectCancels map[string]context.CancelFunc // ホストごとの再接続キャンセル関数
-	events           core.EventEmitter[core.SSHEvent]
+	events           event.Emitter[core.SSHEvent]
 
 	closed bool
 }
@@ -58,6 +47,7 @@ func NewSSHManager(
 	configPath string,
 	reconnectCfg core.ReconnectConfig,
 	hostConfigs map[string]core.HostConfig,
+	timeouts core.TimeoutConfig,
 ) core.SSHManager {
 	if hostConfigs == nil {
 		hostConfigs = make(map[string]core.HostConfig)
@@ -69,14 +59,28 @@ func NewSSHManager(
 		configPath:       configPath,
 		reconnectCfg:     reconnectCfg,
 		hostConfigs:      hostConfigs,
+		timeouts:         timeouts,
 		hostsMap:         make(map[string]int),
 		conns:            make(map[string]*hostConnection),
 		reconnectCancels: make(map[string]context.CancelFunc),
 	}
-	m.events = core.NewEventEmitter[core.SSHEvent](&m.mu)
+	m.events = event.NewEmitter[core.SSHEvent](&m.mu)
 	return m
 }
 
+// UpdateSettings は再接続ポリシー・ホスト別設定・タイムアウトを差し替える。
+// 以降の接続・切断時の再接続から反映し、確立済みの接続と進行中の再接続はそのまま続ける。
+func (m *sshManager) UpdateSettings(reconnectCfg core.ReconnectConfig, hostConfigs map[string]core.HostConfig, timeouts core.TimeoutConfig) {
+	if hostConfigs == nil {
+		hostConfigs = make(map[string]core.HostConfig)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reconnectCfg = reconnectCfg
+	m.hostConfigs = hostConfigs
+	m.timeouts = timeouts
+}
+
 // copyHosts はホスト一覧のコピーを返す。mu.Lock の中で呼ぶこと。
 func (m *sshManager) copyHosts() []core.SSHHost {
 	result := make([]core.SSHHost, len(m.hosts))
@@ -37,13 +37,17 @@ type mockSSHConnection struct {
 	closed     bool
 	isAlive    bool
 	keepAliveF func(ctx context.Context, interval time.Duration)
+	dialF      func(host core.SSHHost)
 
-	localForwardF   func(ctx context.Context, localPort int, remoteAddr string) (net.Listener, error)
+	localForwardF   func(ctx context.Context, localPort int, remoteAddr, localBindAddr string) (net.Listener, error)
 	remoteForwardF  func(ctx context.Context, remotePort int, localAddr string, remoteBindAddr string) (net.Listener, error)
-	dynamicForwardF func(ctx context.Context, localPort int) (net.Listener, error)
+	dynamicForwardF func(ctx context.Context, localPort int, localBindAddr string) (net.Listener, error)
 }
 
-func (m *mockSSHConnection) Dial(host core.SSHHost, cb core.CredentialCallback) (*cryptossh.Client, error) {
+func (m *mockSSHConnection) Dial(_ context.Context, host core.SSHHost, cb core.CredentialCallback) (*cryptossh.Client, error) {
+	if m.dialF != nil {
+		m.dialF(host)
+	}
 	if m.dialErr != nil {
 		return nil, m.dialErr
 	}
@@ -57,9 +61,9 @@ func (m *mockSSHConnection) Close() error {
 	return nil
 }
 
-func (m *mockSSHConnection) LocalForward(ctx context.Context, localPort int, remoteAddr string) (net.Listener, error) {
+func (m *mockSSHConnection) LocalForward(ctx context.Context, localPort int, remoteAddr string, localBindAddr string) (net.Listener, error) {
 	if m.localForwardF != nil {
-		return m.localForwardF(ctx, localPort, remoteAddr)
+		return m.localForwardF(ctx, localPort, remoteAddr, localBindAddr)
 	}
 	return nil, fmt.Errorf("not implemented")
 }
@@ -71,9 +75,9 @@ func (m *mockSSHConnection) RemoteForward(ctx context.Context, remotePort int, l
 	return nil, fmt.Errorf("not implemented")
 }
 
-func (m *mockSSHConnection) DynamicForward(ctx context.Context, localPort int) (net.Listener, error) {
+func (m *mockSSHConnection) DynamicForward(ctx context.Context, localPort int, localBindAddr string) (net.Listener, error) {
 	if m.dynamicForwardF != nil {
-		return m.dynamicForwardF(ctx, localPort)
+		return m.dynamicForwardF(ctx, localPort, localBindAddr)
 	}
 	return nil, fmt.Errorf("not implemented")
 }
@@ -82,7 +86,9 @@ func (m *mockSSHConnection) IsAlive() bool {
 	return m.isAlive
 }
 
-func (m *mockSSHConnection) KeepAlive(ctx context.Context, interval time.Duration) {
+func (m *mockSSHConnection) RTT() time.Duration { return 0 }
+
+func (m *mockSSHConnection) KeepAlive(ctx context.Context, interval time.Duration, _ int) {
 	if m.keepAliveF != nil {
 		m.keepAliveF(ctx, interval)
 		return
@@ -111,5 +117,5 @@ func newTestSSHManager(hosts []core.SSHHost, connFactory func() core.SSHConnecti
 		MaxRetries:   3,
 		InitialDelay: core.Duration{Duration: 10 * time.Millisecond},
 		MaxDelay:     core.Duration{Duration: 50 * time.Millisecond},
-	}, nil)
+	}, nil, core.TimeoutConfig{})
 }
@@ -10,29 +10,9 @@ import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ssh/dialplan"
 )
 
-// resolveReconnectConfig はグローバル設定にホスト別オーバーライドをマージして返す。
-func resolveReconnectConfig(global core.ReconnectConfig, override *core.ReconnectOverride) core.ReconnectConfig {
-	if override == nil {
-		return global
-	}
-	result := global
-	if override.Enabled != nil {
-		result.Enabled = *override.Enabled
-	}
-	if override.MaxRetries != nil {
-		result.MaxRetries = *override.MaxRetries
-	}
-	if override.InitialDelay != nil {
-		result.InitialDelay = *override.InitialDelay
-	}
-	if override.MaxDelay != nil {
-		result.MaxDelay = *override.MaxDelay
-	}
-	return result
-}
-
 // backoffWithJitter は指数バックオフにジッター（0-10%）を加えた遅延を計算する。
 func backoffWithJitter(current, maxDelay time.Duration) time.Duration {
 	base := time.Duration(math.Min(float64(current)*2, float64(maxDelay)))
@@ -98,7 +78,7 @@ func (m *sshManager) cleanupDisconnected(hostName string) (disconnectState, bool
 
 	reconnectCfg := m.reconnectCfg
 	if hostCfg, ok := m.hostConfigs[hostName]; ok {
-		reconnectCfg = resolveReconnectConfig(reconnectCfg, hostCfg.Reconnect)
+		reconnectCfg = dialplan.Reconnect(reconnectCfg, hostCfg.Reconnect)
 	}
 	var host core.SSHHost
 	if idx, ok := m.hostsMap[hostName]; ok {
@@ -135,7 +115,7 @@ func (m *sshManager) reconnectLoop(hostName string, ds disconnectState) {
 			return
 		}
 
-		if m.tryReconnect(hostName, ds.host) {
+		if m.tryReconnect(reconnectCtx, hostName, ds.host) {
 			return
 		}
 
@@ -181,22 +161,23 @@ func (m *sshManager) isClosed() bool {
 	return m.closed
 }
 
-// tryReconnect は1回の再接続を試行し、成功時は true を返す。
-func (m *sshManager) tryReconnect(hostName string, host core.SSHHost) bool {
+// tryReconnect は1回の再接続を試行し、成功時は true を返す。再接続の停止で ctx がキャンセルされた場合は接続を中断する。
+func (m *sshManager) tryReconnect(ctx context.Context, hostName string, host core.SSHHost) bool {
 	conn := m.connFactory()
-	client, err := conn.Dial(host, nil)
+	client, jumps, err := m.dial(ctx, conn, host, nil)
 	if err != nil {
 		slog.Warn("reconnect dial failed", "host", hostName, "error", err)
 		return false
 	}
 
-	ctx, cancel := context.WithCancel(m.ctx) //nolint:gosec // cancel は hc.cancel に保持され Disconnect 時に呼ばれる
+	connCtx, cancel := context.WithCancel(m.ctx) //nolint:gosec // cancel は hc.cancel に保持され Disconnect 時に呼ばれる
 	hc := &hostConnection{
 		conn:   conn,
 		client: client,
-		ctx:    ctx,
+		ctx:    connCtx,
 		cancel: cancel,
 		state:  core.Connected,
+		jumps:  jumps,
 	}
 
 	m.mu.Lock()
@@ -210,15 +191,7 @@ func (m *sshManager) tryReconnect(hostName string, host core.SSHHost) bool {
 	m.events.Emit(core.SSHEvent{Type: core.SSHEventConnected, HostName: hostName})
 	slog.Info("SSH reconnected", "host", hostName)
 
-	go func() {
-		conn.KeepAlive(ctx, m.keepAliveInterval())
-		select {
-		case <-ctx.Done():
-			return
-		default:
-			m.handleDisconnect(hostName)
-		}
-	}()
+	m.startKeepAlive(connCtx, conn, host)
 
 	return true
 }
@@ -10,83 +10,8 @@ import (
 )
 
 func boolPtr(b bool) *bool                  { return &b }
-func intPtr(i int) *int                     { return &i }
 func durPtr(d time.Duration) *core.Duration { return &core.Duration{Duration: d} }
 
-func TestResolveReconnectConfig(t *testing.T) {
-	global := core.ReconnectConfig{
-		Enabled:      true,
-		MaxRetries:   5,
-		InitialDelay: core.Duration{Duration: 1 * time.Second},
-		MaxDelay:     core.Duration{Duration: 30 * time.Second},
-	}
-
-	tests := []struct {
-		name     string
-		override *core.ReconnectOverride
-		want     core.ReconnectConfig
-	}{
-		{
-			name:     "nil override returns global unchanged",
-			override: nil,
-			want:     global,
-		},
-		{
-			name:     "override Enabled only",
-			override: &core.ReconnectOverride{Enabled: boolPtr(false)},
-			want: core.ReconnectConfig{
-				Enabled:      false,
-				MaxRetries:   5,
-				InitialDelay: core.Duration{Duration: 1 * time.Second},
-				MaxDelay:     core.Duration{Duration: 30 * time.Second},
-			},
-		},
-		{
-			name:     "override MaxRetries only",
-			override: &core.ReconnectOverride{MaxRetries: intPtr(10)},
-			want: core.ReconnectConfig{
-				Enabled:      true,
-				MaxRetries:   10,
-				InitialDelay: core.Duration{Duration: 1 * time.Second},
-				MaxDelay:     core.Duration{Duration: 30 * time.Second},
-			},
-		},
-		{
-			name: "override all fields",
-			override: &core.ReconnectOverride{
-				Enabled:      boolPtr(false),
-				MaxRetries:   intPtr(2),
-				InitialDelay: durPtr(500 * time.Millisecond),
-				MaxDelay:     durPtr(10 * time.Second),
-			},
-			want: core.ReconnectConfig{
-				Enabled:      false,
-				MaxRetries:   2,
-				InitialDelay: core.Duration{Duration: 500 * time.Millisecond},
-				MaxDelay:     core.Duration{Duration: 10 * time.Second},
-			},
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := resolveReconnectConfig(global, tt.override)
-			if got.Enabled != tt.want.Enabled {
-				t.Errorf("Enabled = %v, want %v", got.Enabled, tt.want.Enabled)
-			}
-			if got.MaxRetries != tt.want.MaxRetries {
-				t.Errorf("MaxRetries = %v, want %v", got.MaxRetries, tt.want.MaxRetries)
-			}
-			if got.InitialDelay != tt.want.InitialDelay {
-				t.Errorf("InitialDelay = %v, want %v", got.InitialDelay, tt.want.InitialDelay)
-			}
-			if got.MaxDelay != tt.want.MaxDelay {
-				t.Errorf("MaxDelay = %v, want %v", got.MaxDelay, tt.want.MaxDelay)
-			}
-		})
-	}
-}
-
 func TestSSHManager_PerHostReconnectDisabled(t *testing.T) {
 	// グローバルで再接続有効だが、ホスト別に無効にした場合、再接続をスキップする。
 	hosts := testHosts()
@@ -117,6 +42,7 @@ func TestSSHManager_PerHostReconnectDisabled(t *testing.T) {
 		map[string]core.HostConfig{
 			"server1": {Reconnect: &core.ReconnectOverride{Enabled: boolPtr(false)}},
 		},
+		core.TimeoutConfig{},
 	)
 
 	if _, err := sm.LoadHosts(); err != nil {
@@ -125,7 +51,7 @@ func TestSSHManager_PerHostReconnectDisabled(t *testing.T) {
 
 	events := sm.Subscribe()
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
@@ -167,3 +93,25 @@ func TestSSHManager_PerHostReconnectDisabled(t *testing.T) {
 
 	sm.Close()
 }
+
+func TestSSHManager_UpdateSettings(t *testing.T) {
+	sm := NewSSHManager(context.Background(), &mockSSHConfigParser{hosts: testHosts()},
+		func() core.SSHConnection { return &mockSSHConnection{} }, "/fake/ssh/config",
+		core.ReconnectConfig{}, nil, core.TimeoutConfig{}).(*sshManager)
+
+	sm.UpdateSettings(
+		core.ReconnectConfig{Enabled: true, KeepAliveInterval: core.Duration{Duration: 5 * time.Second}},
+		map[string]core.HostConfig{"server1": {ConnectTimeout: durPtr(3 * time.Second)}},
+		core.TimeoutConfig{ConnectTimeout: core.Duration{Duration: 7 * time.Second}},
+	)
+
+	if got := sm.withHostConfig(core.SSHHost{Name: "server2"}).ServerAliveInterval; got != 5*time.Second {
+		t.Errorf("ServerAliveInterval = %v, want 5s from global reconnect config", got)
+	}
+	if got := sm.withHostConfig(core.SSHHost{Name: "server1"}).ConnectTimeout; got != 3*time.Second {
+		t.Errorf("server1 ConnectTimeout = %v, want 3s from host override", got)
+	}
+	if got := sm.withHostConfig(core.SSHHost{Name: "server2"}).ConnectTimeout; got != 7*time.Second {
+		t.Errorf("server2 ConnectTimeout = %v, want 7s from global timeouts", got)
+	}
+}
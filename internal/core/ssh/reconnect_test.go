@@ -86,6 +86,7 @@ func TestSSHManager_HandleDisconnect_WithReconnect(t *testing.T) {
 			MaxDelay:     core.Duration{Duration: 50 * time.Millisecond},
 		},
 		nil,
+		core.TimeoutConfig{},
 	)
 
 	if _, err := sm.LoadHosts(); err != nil {
@@ -94,7 +95,7 @@ func TestSSHManager_HandleDisconnect_WithReconnect(t *testing.T) {
 
 	events := sm.Subscribe()
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
@@ -156,6 +157,7 @@ func TestSSHManager_Disconnect_StopsReconnect(t *testing.T) {
 			MaxDelay:     core.Duration{Duration: 50 * time.Millisecond},
 		},
 		nil,
+		core.TimeoutConfig{},
 	)
 
 	if _, err := sm.LoadHosts(); err != nil {
@@ -164,7 +166,7 @@ func TestSSHManager_Disconnect_StopsReconnect(t *testing.T) {
 
 	events := sm.Subscribe()
 
-	if err := sm.Connect("server1"); err != nil {
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
 		t.Fatalf("Connect() error = %v", err)
 	}
 
@@ -0,0 +1,34 @@
+package ssh
+
+import (
+	"context"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ssh/dialplan"
+)
+
+// withHostConfig は接続に使うタイムアウト・keepalive とクレデンシャルの取得元を解決して host に設定したコピーを返す。
+// タイムアウトと keepalive の優先順位はホスト別設定、ssh_config、グローバル設定の順。
+func (m *sshManager) withHostConfig(host core.SSHHost) core.SSHHost {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	hc := m.hostConfigs[host.Name]
+	host = dialplan.Timeouts(host, m.timeouts, hc)
+	host = dialplan.KeepAlive(host, m.reconnectCfg, hc)
+	host.Credentials = hc.Credentials
+	return host
+}
+
+// startKeepAlive は接続の keepalive を開始し、応答がなくなった場合（ctx のキャンセル以外）は切断として扱う。
+func (m *sshManager) startKeepAlive(ctx context.Context, conn core.SSHConnection, host core.SSHHost) {
+	host = m.withHostConfig(host)
+	go func() {
+		conn.KeepAlive(ctx, host.ServerAliveInterval, host.ServerAliveCountMax)
+		select {
+		case <-ctx.Done():
+			return
+		default:
+			m.handleDisconnect(host.Name)
+		}
+	}()
+}
@@ -0,0 +1,68 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestSSHManager_ConnectPassesHostConfig(t *testing.T) {
+	hosts := []core.SSHHost{{Name: "server1", HostName: "192.168.1.1", Port: 22, User: "user"}}
+	var dialed core.SSHHost
+	creds := &core.CredentialSource{Provider: core.CredentialProviderEnv, Password: "PROD_PASSWORD"}
+	sm := NewSSHManager(
+		context.Background(),
+		&mockSSHConfigParser{hosts: hosts},
+		func() core.SSHConnection {
+			mock := &mockSSHConnection{isAlive: true}
+			mock.dialF = func(h core.SSHHost) { dialed = h }
+			return mock
+		},
+		"/fake/ssh/config",
+		core.ReconnectConfig{},
+		map[string]core.HostConfig{"server1": {BannerTimeout: durPtr(2 * time.Second), Credentials: creds}},
+		core.TimeoutConfig{ConnectTimeout: core.Duration{Duration: 7 * time.Second}},
+	)
+	defer sm.Close()
+	if _, err := sm.LoadHosts(); err != nil {
+		t.Fatalf("LoadHosts() error = %v", err)
+	}
+
+	if err := sm.Connect(t.Context(), "server1"); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if dialed.ConnectTimeout != 7*time.Second || dialed.BannerTimeout != 2*time.Second {
+		t.Errorf("Dial got timeouts (%v, %v), want (7s, 2s)", dialed.ConnectTimeout, dialed.BannerTimeout)
+	}
+	if dialed.Credentials != creds {
+		t.Errorf("Dial got credentials %+v, want %+v", dialed.Credentials, creds)
+	}
+}
+
+func TestSSHManager_ConnectCancelledDuringDial(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	mock := &mockSSHConnection{isAlive: true}
+	// 接続の確立と同時に呼び出し元が取り消した場合を再現する
+	mock.dialF = func(core.SSHHost) { cancel() }
+	sm := newTestSSHManager(testHosts(), func() core.SSHConnection { return mock })
+	defer sm.Close()
+	if _, err := sm.LoadHosts(); err != nil {
+		t.Fatalf("LoadHosts() error = %v", err)
+	}
+
+	if err := sm.Connect(ctx, "server1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Connect() error = %v, want context.Canceled", err)
+	}
+	if sm.IsConnected("server1") {
+		t.Error("a cancelled connection should not be registered")
+	}
+	if !mock.closed {
+		t.Error("the connection established after cancellation should be closed")
+	}
+	if host, _ := sm.GetHost("server1"); host.State != core.Disconnected {
+		t.Errorf("host state = %v, want Disconnected", host.State)
+	}
+}
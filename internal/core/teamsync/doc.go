@@ -0,0 +1,3 @@
+// Package teamsync はチームで共有する設定（team.yaml）とローカルのルール・ホスト情報の突き合わせを提供する。
+// 共有ルールは名前空間 Prefix を付けてローカルに取り込み、ローカル独自のルールと区別する。
+package teamsync
@@ -0,0 +1,194 @@
+package teamsync
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/rulename"
+	"reflect"
+)
+
+// Prefix はチームで共有するルールをローカルに取り込むときに名前に付ける名前空間。
+// この名前空間のルールは同期で管理され、共有設定から消えたものは削除される。
+const Prefix = "team-"
+
+// File はチームで共有する設定ファイル（team.yaml）の内容。
+// Forwards のルール名は名前空間を含まない。Hosts はホスト名ごとのメモや認証手段のヒント。
+type File struct {
+	Forwards []core.ForwardRule           `yaml:"forwards"`
+	Hosts    map[string]core.HostMetadata `yaml:"hosts,omitempty"`
+}
+
+// Conflict は取り込みを見送った共有設定の項目とその理由。Kind は "rule" または "host"。
+type Conflict struct {
+	Kind   string
+	Name   string
+	Reason string
+}
+
+// String は衝突の内容を "rule team-db: ..." の形式で返す。
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s %s: %s", c.Kind, c.Name, c.Reason)
+}
+
+// Plan は共有設定をローカルに反映するための変更内容。ルール名は名前空間を含む。
+type Plan struct {
+	Add       []core.ForwardRule
+	Replace   []core.ForwardRule
+	Remove    []string
+	Hosts     map[string]core.HostMetadata // ホストごとに、ローカルで未設定のため設定する項目のみを持つ
+	Conflicts []Conflict
+}
+
+// Empty は反映する変更がないかを返す。衝突は含めない。
+func (p Plan) Empty() bool {
+	return len(p.Add) == 0 && len(p.Replace) == 0 && len(p.Remove) == 0 && len(p.Hosts) == 0
+}
+
+// IsShared は name が同期で管理される共有ルールの名前かを返す。
+func IsShared(name string) bool {
+	return strings.HasPrefix(strings.ToLower(name), Prefix)
+}
+
+// Merge は共有設定 team とローカルのルール local、ホスト情報 hosts を突き合わせて Plan を返す。
+// 共有ルールは名前空間を付けて追加し、内容が変わったものは置き換え、共有設定から消えたものは削除する。
+// ローカル独自のルールとローカルポートが重なるルールや不正なルールは取り込まずに Conflicts に記録する。
+// 共有設定のフックコマンドは取り込まず、置き換えるルールにはローカルで設定したフックを引き継ぐ。
+// ホスト情報はローカルで未設定の項目のみを設定し、異なる値が設定済みの項目は Conflicts に記録する。
+func Merge(team File, local []core.ForwardRule, hosts map[string]core.HostMetadata) Plan {
+	var plan Plan
+	current := make(map[string]core.ForwardRule)
+	ports := make(map[int]string)
+	for _, r := range local {
+		if IsShared(r.Name) {
+			current[strings.ToLower(r.Name)] = r
+		} else if r.Type != core.Remote && r.LocalPort != 0 {
+			ports[r.LocalPort] = r.Name
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range team.Forwards {
+		r.Name = Prefix + strings.TrimSpace(r.Name)
+		if reason := check(r, seen, ports); reason != "" {
+			plan.Conflicts = append(plan.Conflicts, Conflict{Kind: "rule", Name: r.Name, Reason: reason})
+			continue
+		}
+		key := strings.ToLower(r.Name)
+		seen[key] = true
+		r = normalize(r)
+		existing, ok := current[key]
+		if ok {
+			r.OnStart, r.OnStop = existing.OnStart, existing.OnStop
+		}
+		switch {
+		case !ok:
+			plan.Add = append(plan.Add, r)
+		case !reflect.DeepEqual(existing, r):
+			plan.Replace = append(plan.Replace, r)
+		}
+	}
+	for key, r := range current {
+		if !seen[key] {
+			plan.Remove = append(plan.Remove, r.Name)
+		}
+	}
+	slices.Sort(plan.Remove)
+
+	plan.Hosts, plan.Conflicts = mergeHosts(team.Hosts, hosts, plan.Conflicts)
+	return plan
+}
+
+// check は共有ルール r を取り込めない理由を返す。取り込める場合は空文字列。
+func check(r core.ForwardRule, seen map[string]bool, ports map[int]string) string {
+	if !rulename.Valid(r.Name) {
+		return "invalid rule name"
+	}
+	if seen[strings.ToLower(r.Name)] {
+		return "duplicate rule name in team config"
+	}
+	if err := core.ValidateForwardRule(r); err != nil {
+		return err.Error()
+	}
+	if owner, ok := ports[r.LocalPort]; ok && r.Type != core.Remote {
+		return fmt.Sprintf("local port %d is used by local rule %s", r.LocalPort, owner)
+	}
+	return ""
+}
+
+// normalize は比較のために、ルール追加時に補われる値と共有しない値を揃える。
+// フックはデーモンがシェルで実行するため、共有設定を編集できる他のメンバーのコマンドを実行しないよう除く。
+func normalize(r core.ForwardRule) core.ForwardRule {
+	if (r.Type == core.Local || r.Type == core.Remote) && r.RemoteHost == "" {
+		r.RemoteHost = "localhost"
+	}
+	r.ImportKey = ""
+	r.DeleteOnExpire = false
+	r.OnStart, r.OnStop = "", ""
+	return r
+}
+
+// mergeHosts は共有のホスト情報のうちローカルで未設定の項目を返し、異なる値が設定済みの項目を conflicts に加える。
+func mergeHosts(team, local map[string]core.HostMetadata, conflicts []Conflict) (map[string]core.HostMetadata, []Conflict) {
+	names := make([]string, 0, len(team))
+	for name := range team {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var set map[string]core.HostMetadata
+	for _, name := range names {
+		cur, ok := local[name]
+		if !ok {
+			conflicts = append(conflicts, Conflict{Kind: "host", Name: name, Reason: "host not found"})
+			continue
+		}
+		want := team[name]
+		var add core.HostMetadata
+		var differ []string
+		for _, f := range []struct {
+			field     string
+			cur, want string
+			dst       *string
+		}{
+			{"notes", cur.Notes, want.Notes, &add.Notes},
+			{"auth_hint", cur.AuthHint, want.AuthHint, &add.AuthHint},
+			{"jump_description", cur.JumpDescription, want.JumpDescription, &add.JumpDescription},
+		} {
+			switch {
+			case f.want == "" || f.want == f.cur:
+			case f.cur == "":
+				*f.dst = f.want
+			default:
+				differ = append(differ, f.field)
+			}
+		}
+		if len(differ) > 0 {
+			conflicts = append(conflicts, Conflict{Kind: "host", Name: name, Reason: "local value differs: " + strings.Join(differ, ", ")})
+		}
+		if !add.IsZero() {
+			if set == nil {
+				set = make(map[string]core.HostMetadata)
+			}
+			set[name] = add
+		}
+	}
+	return set, conflicts
+}
+
+// Export はローカルのルールのうち共有ルールを、名前空間とフックコマンドを除いた共有設定のルールとして返す。
+func Export(local []core.ForwardRule) []core.ForwardRule {
+	var rules []core.ForwardRule
+	for _, r := range local {
+		if !IsShared(r.Name) || r.DeleteOnExpire {
+			continue
+		}
+		r.Name = r.Name[len(Prefix):]
+		r.ImportKey = ""
+		r.OnStart, r.OnStop = "", ""
+		rules = append(rules, r)
+	}
+	return rules
+}
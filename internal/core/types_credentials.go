@@ -7,6 +7,14 @@ const (
 	CredentialPassword            CredentialType = "password"
 	CredentialPassphrase          CredentialType = "passphrase"
 	CredentialKeyboardInteractive CredentialType = "keyboard-interactive"
+	// CredentialHostKey は known_hosts に未登録のホスト鍵を信頼するかの確認。
+	CredentialHostKey CredentialType = "host-key"
+)
+
+// host-key の確認に対する CredentialResponse.Value。それ以外の値は拒否として扱う。
+const (
+	HostKeyAccept     = "yes"  // 信頼して known_hosts に追記する
+	HostKeyAcceptOnce = "once" // 今回の接続に限り信頼する
 )
 
 // PromptInfo は keyboard-interactive 認証の個別プロンプト情報。
@@ -22,6 +30,9 @@ type CredentialRequest struct {
 	Host      string
 	Prompt    string       // password/passphrase 用
 	Prompts   []PromptInfo // keyboard-interactive 用
+	// KeyType と Fingerprint は host-key 用（鍵の種別と SHA256 フィンガープリント）。
+	KeyType     string
+	Fingerprint string
 }
 
 // CredentialResponse はクレデンシャル応答を表す。
@@ -35,3 +46,44 @@ type CredentialResponse struct {
 // CredentialCallback はクレデンシャル要求時に呼び出されるコールバック関数の型。
 // デーモンがクライアントにクレデンシャルを要求し、応答を受け取る。
 type CredentialCallback func(req CredentialRequest) (CredentialResponse, error)
+
+// CredentialProvider はユーザーへの問い合わせより先に参照されるクレデンシャルの取得元。
+type CredentialProvider interface {
+	// Lookup は req に対応する値を返す。提供できる値がない場合は ok に false を返す。
+	Lookup(req CredentialRequest) (value string, ok bool, err error)
+}
+
+// クレデンシャル取得元の種別（CredentialSource.Provider）。
+const (
+	CredentialProviderEnv      = "env"      // 環境変数
+	CredentialProviderKeychain = "keychain" // OS のキーチェーン
+	CredentialProviderExec     = "exec"     // コマンドの標準出力（op read 等）
+)
+
+// CredentialSource はホストのパスワード・パスフレーズの取得元の設定。
+// Password と Passphrase の意味は Provider によって異なり、env は環境変数名、
+// keychain はキーチェーンのアカウント名、exec は実行するコマンドを表す。空の場合は取得しない。
+type CredentialSource struct {
+	Provider   string `yaml:"provider" schema:"enum=env|keychain|exec"`
+	Password   string `yaml:"password,omitempty"`
+	Passphrase string `yaml:"passphrase,omitempty"`
+}
+
+// Ref はクレデンシャル種別 t に対応する参照を返す。password/passphrase 以外は空文字列を返す。
+func (s CredentialSource) Ref(t CredentialType) string {
+	switch t {
+	case CredentialPassword:
+		return s.Password
+	case CredentialPassphrase:
+		return s.Passphrase
+	default:
+		return ""
+	}
+}
+
+// SecretsConfig は入力されたクレデンシャルの保存の設定。
+type SecretsConfig struct {
+	// CachePassphrases が true の場合、入力された鍵のパスフレーズを OS のキーチェーンに保存し、
+	// 再接続やデーモンの再起動後に問い合わせずに再利用する。
+	CachePassphrases bool `yaml:"cache_passphrases"`
+}
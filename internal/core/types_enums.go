@@ -38,6 +38,16 @@ func (s ConnectionState) String() string {
 	}
 }
 
+// HostHealth はホストの SSH エンドポイントへの到達性を表す。値は IPC のワイヤー形式と同じ文字列。
+type HostHealth string
+
+const (
+	// HealthUnknown は未確認、または経由接続のため確認対象外であることを表す。
+	HealthUnknown     HostHealth = ""
+	HealthReachable   HostHealth = "reachable"
+	HealthUnreachable HostHealth = "unreachable"
+)
+
 // SessionStatus はポートフォワーディングセッションの状態を表す。
 type SessionStatus int
 
@@ -47,6 +57,8 @@ const (
 	Active
 	SessionReconnecting
 	SessionError
+	// SessionDraining は新しい接続の受け付けを止め、転送中の接続の完了を待っている状態。
+	SessionDraining
 )
 
 func (s SessionStatus) String() string {
@@ -61,6 +73,8 @@ func (s SessionStatus) String() string {
 		return "Reconnecting"
 	case SessionError:
 		return "Error"
+	case SessionDraining:
+		return "Draining"
 	default:
 		return fmt.Sprintf("SessionStatus(%d)", int(s))
 	}
@@ -37,6 +37,7 @@ func TestSessionStatus_String(t *testing.T) {
 		{Active, "Active"},
 		{SessionReconnecting, "Reconnecting"},
 		{SessionError, "Error"},
+		{SessionDraining, "Draining"},
 		{SessionStatus(99), "SessionStatus(99)"},
 	}
 	for _, tt := range tests {
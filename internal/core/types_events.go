@@ -47,6 +47,11 @@ const (
 	ForwardEventMetricsUpdated
 	ForwardEventReconnecting // SSH 接続断によりフォワードが再接続待ち
 	ForwardEventRestored     // SSH 再接続後にフォワードが自動復元
+	ForwardEventMigrated     // フォワードが別ホストへ移行
+	ForwardEventExpiring     // TTL による自動停止が近づいている
+	ForwardEventDenied       // SOCKS5 の ACL により宛先への接続を拒否した
+	ForwardEventDraining     // 停止要求を受けて転送中の接続の完了を待っている
+	ForwardEventUpdated      // ルールの内容が変更された（アクティブな場合は新しい内容で再開済み）
 )
 
 func (t ForwardEventType) String() string {
@@ -63,6 +68,16 @@ func (t ForwardEventType) String() string {
 		return "Reconnecting"
 	case ForwardEventRestored:
 		return "Restored"
+	case ForwardEventMigrated:
+		return "Migrated"
+	case ForwardEventExpiring:
+		return "Expiring"
+	case ForwardEventDenied:
+		return "Denied"
+	case ForwardEventDraining:
+		return "Draining"
+	case ForwardEventUpdated:
+		return "Updated"
 	default:
 		return fmt.Sprintf("ForwardEventType(%d)", int(t))
 	}
@@ -74,4 +89,5 @@ type ForwardEvent struct {
 	RuleName string
 	Session  *ForwardSession
 	Error    error
+	FromHost string // ForwardEventMigrated の場合の移行元ホスト
 }
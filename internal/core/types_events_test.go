@@ -32,6 +32,11 @@ func TestForwardEventType_String(t *testing.T) {
 		{ForwardEventMetricsUpdated, "MetricsUpdated"},
 		{ForwardEventReconnecting, "Reconnecting"},
 		{ForwardEventRestored, "Restored"},
+		{ForwardEventMigrated, "Migrated"},
+		{ForwardEventExpiring, "Expiring"},
+		{ForwardEventDenied, "Denied"},
+		{ForwardEventDraining, "Draining"},
+		{ForwardEventUpdated, "Updated"},
 		{ForwardEventType(99), "ForwardEventType(99)"},
 	}
 	for _, tt := range tests {
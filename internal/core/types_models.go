@@ -1,8 +1,11 @@
 package core
 
 import (
-	"fmt"
 	"time"
+
+	"github.com/ousiassllc/moleport/internal/core/notifyconf"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+	"github.com/ousiassllc/moleport/internal/core/socksacl"
 )
 
 // SSHHost は SSH config から読み込んだホスト情報と実行時の接続状態を保持する。
@@ -12,23 +15,75 @@ type SSHHost struct {
 	Port                  int
 	User                  string
 	IdentityFiles         []string
+	IdentityAgent         string
 	ProxyJump             []string
 	ProxyCommand          string
 	StrictHostKeyChecking string
 	State                 ConnectionState
 	ActiveForwardCount    int
+	Meta                  HostMetadata
+	// ConnectTimeout は TCP 接続、BannerTimeout は SSH バナー受信までの待ち時間。0 は既定値を使う。
+	// パーサーは ssh_config の ConnectTimeout のみを設定し、接続時に SSHManager が設定値で上書きする。
+	ConnectTimeout time.Duration
+	BannerTimeout  time.Duration
+	// ServerAliveInterval と ServerAliveCountMax は ssh_config の同名ディレクティブ。0 は未指定。
+	// 接続時に SSHManager がホスト別設定・グローバル設定で解決した keepalive の間隔と回数で上書きする。
+	ServerAliveInterval time.Duration
+	ServerAliveCountMax int
+	// ConfigForwards は ssh_config の LocalForward/RemoteForward/DynamicForward から得たルール候補。
+	ConfigForwards []ForwardRule
+	// DefaultForwards は ssh_config のマジックコメント（"# moleport: L 5432:localhost:5432"）で定義したルール。
+	DefaultForwards []ForwardRule
+	// JumpChain は ProxyJump を再帰的に展開した踏み台の並び（接続順）。LoadHosts 時に SSHManager が設定する。
+	JumpChain []string
+	// JumpHosts は JumpChain の各踏み台の接続情報。接続時に SSHManager が設定し、Dial はこの順に経由する。
+	JumpHosts []SSHHost
+	// Credentials はホスト別設定のクレデンシャル取得元。接続時に SSHManager が設定する。
+	Credentials *CredentialSource
+	// Source はホストの定義元（HostSourceSSHConfig または HostSourceConfig）。
+	Source string
+	// Health はバックグラウンドの疎通確認の結果。SSHManager は設定せず、デーモンが host.list の応答時に付与する。
+	Health HostHealth
+	// Latency は keepalive で計測した直近の往復時間。TUI が host.stats の結果から付与する。
+	Latency time.Duration
 }
 
 // ForwardRule はポートフォワーディングのルール定義。
+// Local/Dynamic で LocalPort が 0 の場合は、開始時に空いているポートを割り当てる。
 type ForwardRule struct {
-	Name           string      `yaml:"name"`
+	Name           string      `yaml:"name" schema:"max=63"`
 	Host           string      `yaml:"host"`
-	Type           ForwardType `yaml:"type"`
-	LocalPort      int         `yaml:"local_port"`
+	Type           ForwardType `yaml:"type" schema:"enum=local|remote|dynamic"`
+	LocalPort      int         `yaml:"local_port" schema:"min=0,max=65535"`
 	RemoteHost     string      `yaml:"remote_host,omitempty"`
-	RemotePort     int         `yaml:"remote_port,omitempty"`
+	RemotePort     int         `yaml:"remote_port,omitempty" schema:"min=1,max=65535"`
 	RemoteBindAddr string      `yaml:"remote_bind_addr,omitempty"`
 	AutoConnect    bool        `yaml:"auto_connect"`
+	// LocalBindAddr は Local/Dynamic ではローカルの待ち受けアドレス、Remote ではローカル側の転送先アドレス。空の場合は 127.0.0.1。
+	LocalBindAddr string `yaml:"local_bind_addr,omitempty" schema:"since=1.1.0"`
+	// AutoReconnect が true の場合、リスナーが閉じたときや SSH 接続が確立し直されたときにフォワードを自動で再開する。
+	AutoReconnect bool `yaml:"auto_reconnect,omitempty" schema:"since=1.1.0"`
+	// ImportKey は ssh_config からインポートしたルールの出所を示すキー。再インポート時の重複判定に使う。
+	ImportKey string `yaml:"import_key,omitempty" schema:"since=1.1.0"`
+	// TTL が正の場合、フォワードは開始から TTL 経過後に自動で停止する。
+	TTL Duration `yaml:"ttl,omitempty" schema:"since=1.1.0"`
+	// DeleteOnExpire が true のルールは TTL の期限切れ時に削除される一時ルールで、設定ファイルには保存しない。
+	DeleteOnExpire bool `yaml:"-"`
+	// HostDefined が true のルールは ssh_config のマジックコメントまたは config.yaml の hosts.<name>.forwards で
+	// ホストに定義されたルールで、ホストを読み込むたびに定義に合わせて登録し直す。設定ファイルの forwards には保存しない。
+	HostDefined bool `yaml:"-"`
+	// MaxUploadKbps と MaxDownloadKbps はセッションの送信・受信の帯域上限（kbps）。0 は無制限。
+	MaxUploadKbps   int `yaml:"max_upload_kbps,omitempty" schema:"min=0,since=1.1.0"`
+	MaxDownloadKbps int `yaml:"max_download_kbps,omitempty" schema:"min=0,since=1.1.0"`
+	// ACL は Dynamic ルールの SOCKS5 プロキシで接続を許可する宛先。nil の場合は制限しない。
+	ACL *socksacl.ACL `yaml:"acl,omitempty" schema:"since=1.1.0"`
+	// AccessLog は接続ごとのアクセスログの出力先（AccessLogFile または AccessLogSlog）。空の場合は記録しない。
+	AccessLog string `yaml:"access_log,omitempty" schema:"enum=file|log,since=1.1.0"`
+	// DependsOn は開始前に起動している必要があるルール名またはホスト名。依存先が失敗するとこのルールも停止する。
+	DependsOn []string `yaml:"depends_on,omitempty" schema:"since=1.1.0"`
+	// OnStart・OnStop はフォワードの開始後・停止前にデーモンがシェルで実行するコマンド。空の場合は実行しない。
+	OnStart string `yaml:"on_start,omitempty" schema:"since=1.1.0"`
+	OnStop  string `yaml:"on_stop,omitempty" schema:"since=1.1.0"`
 }
 
 // ForwardSession は実行中のポートフォワーディングセッションの状態とメトリクスを保持する。
@@ -41,6 +96,34 @@ type ForwardSession struct {
 	BytesReceived  int64
 	ReconnectCount int
 	LastError      string
+	// BoundPort は Local/Dynamic で実際に Listen しているローカルポート。LocalPort が 0 の場合は割り当てられたポート。
+	BoundPort int
+	// ExpiresAt は TTL による自動停止の予定時刻。期限がない場合はゼロ値。
+	ExpiresAt time.Time
+	// SendRate・ReceiveRate は直近の送受信レート（バイト/秒）、RateHistory は送受信合計レートの推移（古い順）。
+	SendRate    float64
+	ReceiveRate float64
+	RateHistory []float64
+	// ActiveConns は転送中の接続数、Connections はその接続元と開始時刻（開始順）。
+	ActiveConns int
+	Connections []ConnInfo
+	// StoppedReason・StoppedAt は停止またはエラーになった理由と時刻。稼働中はゼロ値。
+	StoppedReason StopReason
+	StoppedAt     time.Time
+}
+
+// ConnInfo は転送中の 1 つの接続を表す。
+type ConnInfo struct {
+	Peer  string // 接続元アドレス。取得できない場合は空文字列
+	Since time.Time
+}
+
+// ListenPort はセッションのローカルポートを返す。Listen 中であれば BoundPort、それ以外はルールの LocalPort。
+func (s ForwardSession) ListenPort() int {
+	if s.BoundPort != 0 {
+		return s.BoundPort
+	}
+	return s.Rule.LocalPort
 }
 
 // ForwardRestoreResult はフォワード復元の結果を表す。
@@ -59,45 +142,75 @@ type VersionCheckResult struct {
 }
 
 // Config はアプリケーション設定。
+// schema タグは config.schema で公開する制約で、カンマ区切りの key=value（since・default・min・max・enum）を指定する。
+// since は項目が追加されたバージョンで、省略時は親の値（最上位では 1.0.0）を引き継ぐ。
 type Config struct {
+	// SchemaVersion は設定ファイルの形式のバージョン。古い設定は読み込み時に ConfigSchemaVersion へ移行する。
+	SchemaVersion int                   `yaml:"schema_version" schema:"since=1.1.0"`
 	SSHConfigPath string                `yaml:"ssh_config_path"`
 	Reconnect     ReconnectConfig       `yaml:"reconnect"`
+	Timeouts      TimeoutConfig         `yaml:"timeouts" schema:"since=1.1.0"`
 	Hosts         map[string]HostConfig `yaml:"hosts,omitempty"`
 	Session       SessionConfig         `yaml:"session"`
 	Log           LogConfig             `yaml:"log"`
 	Forwards      []ForwardRule         `yaml:"forwards"`
-	Language      string                `yaml:"language"`
+	Language      string                `yaml:"language" schema:"enum=en|ja"`
 	UpdateCheck   UpdateCheckConfig     `yaml:"update_check"`
 	TUI           TUIConfig             `yaml:"tui"`
+	UI            UIConfig              `yaml:"ui" schema:"since=1.1.0"`
+	// IgnoredImports はインポートを見送った ssh_config フォワードの ImportKey 一覧。
+	IgnoredImports []string `yaml:"ignored_imports,omitempty" schema:"since=1.1.0"`
+	// Webhooks はフォワード・SSH 接続のライフサイクルイベントを通知する Webhook の一覧。
+	Webhooks []serviceconf.Webhook `yaml:"webhooks,omitempty" schema:"since=1.1.0"`
+	// Registry はリモートフォワードの公開先をチームのサービスディレクトリに登録する設定。
+	Registry serviceconf.Registry `yaml:"registry,omitempty" schema:"since=1.1.0"`
+	// DNS はアクティブなフォワードをルール名で解決するローカル DNS リゾルバーの設定。
+	DNS serviceconf.DNS `yaml:"dns,omitempty" schema:"since=1.1.0"`
+	// MDNS はアクティブなローカル/ダイナミックフォワードを LAN に mDNS で公開する設定。
+	MDNS serviceconf.MDNS `yaml:"mdns,omitempty" schema:"since=1.1.0"`
+	// HealthCheck はホストの SSH エンドポイントをバックグラウンドで疎通確認する設定。
+	HealthCheck HealthCheckConfig `yaml:"health_check,omitempty" schema:"since=1.1.0"`
+	// Groups は forward.startGroup / forward.stopGroup で一括して開始・停止するルールのまとまり。
+	Groups []ForwardGroup `yaml:"groups,omitempty" schema:"since=1.1.0"`
+	// Secrets は入力されたクレデンシャルを OS のキーチェーンに保存する設定。
+	Secrets SecretsConfig `yaml:"secrets,omitempty" schema:"since=1.1.0"`
+	// HostDefinitions は ssh_config に加えて使う、config.yaml で定義したホスト。
+	HostDefinitions []HostDefinition `yaml:"host_definitions,omitempty" schema:"since=1.1.0"`
+	// Remote は別のマシンの CLI/TUI からデーモンを操作するための TLS リスナーの設定。
+	Remote serviceconf.Remote `yaml:"remote,omitempty" schema:"since=1.1.0"`
+	// Auth はローカルのクライアントの認証の設定。
+	Auth serviceconf.Auth `yaml:"auth,omitempty" schema:"since=1.1.0"`
+	// IPC は自動化ツール向けに追加で待ち受ける IPC のトランスポートの設定。
+	IPC serviceconf.IPC `yaml:"ipc,omitempty" schema:"since=1.1.0"`
+	// EventBridge は外部のダッシュボードにイベントを WebSocket で中継する設定。
+	EventBridge serviceconf.EventBridge `yaml:"event_bridge,omitempty" schema:"since=1.1.0"`
+	// Notifications は SSH 接続・フォワードのエラーと切断をデスクトップ通知する設定。
+	Notifications notifyconf.Config `yaml:"notifications,omitempty" schema:"since=1.1.0"`
 }
 
 // UpdateCheckConfig は自動アップデートチェックの設定。
 type UpdateCheckConfig struct {
 	Enabled  bool     `yaml:"enabled"`
-	Interval Duration `yaml:"interval"`
+	Interval Duration `yaml:"interval" schema:"min=1h"`
 }
 
 // ReconnectConfig は自動再接続の設定。
 type ReconnectConfig struct {
 	Enabled           bool     `yaml:"enabled"`
-	MaxRetries        int      `yaml:"max_retries"`
+	MaxRetries        int      `yaml:"max_retries" schema:"min=0"`
 	InitialDelay      Duration `yaml:"initial_delay"`
 	MaxDelay          Duration `yaml:"max_delay"`
 	KeepAliveInterval Duration `yaml:"keepalive_interval"`
+	// KeepAliveCountMax は応答のない keepalive がこの回数続いた時点で接続断とみなす回数。0 は既定値（3 回）を使う。
+	KeepAliveCountMax int `yaml:"keepalive_count_max" schema:"since=1.1.0,min=0"`
 }
 
-// ReconnectOverride はホスト別の再接続設定オーバーライド。
-// 指定されたフィールドのみグローバル設定を上書きする。
-type ReconnectOverride struct {
-	Enabled      *bool     `yaml:"enabled,omitempty"`
-	MaxRetries   *int      `yaml:"max_retries,omitempty"`
-	InitialDelay *Duration `yaml:"initial_delay,omitempty"`
-	MaxDelay     *Duration `yaml:"max_delay,omitempty"`
-}
-
-// HostConfig はホスト別のオーバーライド設定。
-type HostConfig struct {
-	Reconnect *ReconnectOverride `yaml:"reconnect,omitempty"`
+// TimeoutConfig は SSH 接続確立時とフォワード停止時のタイムアウト設定。
+type TimeoutConfig struct {
+	ConnectTimeout Duration `yaml:"connect_timeout"`
+	BannerTimeout  Duration `yaml:"banner_timeout"`
+	// ForwardDrain はフォワードの停止時に転送中の接続の完了を待つ上限。0 の場合は待たずに切断する。
+	ForwardDrain Duration `yaml:"forward_drain" schema:"since=1.1.0,min=0"`
 }
 
 // SessionConfig はセッション復元の設定。
@@ -107,45 +220,29 @@ type SessionConfig struct {
 
 // LogConfig はログの設定。
 type LogConfig struct {
-	Level string `yaml:"level"`
+	Level string `yaml:"level" schema:"enum=debug|info|warn|error"`
 	File  string `yaml:"file"`
 }
 
-// TUIConfig は TUI の設定。
-type TUIConfig struct {
-	Theme ThemeConfig `yaml:"theme"`
-}
-
-// ThemeConfig はテーマの設定。
-type ThemeConfig struct {
-	Base   string `yaml:"base"`
-	Accent string `yaml:"accent"`
-}
-
-// State はアプリケーション終了時のセッション状態を保持する。
-type State struct {
-	LastUpdated    time.Time     `yaml:"last_updated"`
-	ActiveForwards []ForwardRule `yaml:"active_forwards"`
-	SelectedHost   string        `yaml:"selected_host"`
+// UIConfig は CLI/TUI 共通の表示設定。
+type UIConfig struct {
+	TimeFormat string `yaml:"time_format" schema:"enum=relative|local|utc"`
 }
 
-// MinPort はポート番号の最小値。
-const MinPort = 1
+// 時刻表示形式（ui.time_format）の設定値。
+const (
+	TimeFormatRelative = "relative" // "3m ago" のような相対表記
+	TimeFormatLocal    = "local"    // ローカルタイムゾーンの日時
+	TimeFormatUTC      = "utc"      // UTC の日時
+)
 
-// MaxPort はポート番号の最大値。
-const MaxPort = 65535
-
-// ValidatePort はポート番号が有効範囲内かを検証する。
-func ValidatePort(port int) error {
-	if port < MinPort || port > MaxPort {
-		return fmt.Errorf("port must be between %d and %d, got %d", MinPort, MaxPort, port)
-	}
-	return nil
-}
+// ConfigSchemaVersion は現在の設定ファイルの形式のバージョン。
+const ConfigSchemaVersion = 1
 
 // DefaultConfig はデフォルト設定を返す。
 func DefaultConfig() Config {
 	return Config{
+		SchemaVersion: ConfigSchemaVersion,
 		SSHConfigPath: "~/.ssh/config",
 		Reconnect: ReconnectConfig{
 			Enabled:           true,
@@ -153,6 +250,12 @@ func DefaultConfig() Config {
 			InitialDelay:      Duration{Duration: 1 * time.Second},
 			MaxDelay:          Duration{Duration: 60 * time.Second},
 			KeepAliveInterval: Duration{Duration: 30 * time.Second},
+			KeepAliveCountMax: 3,
+		},
+		Timeouts: TimeoutConfig{
+			ConnectTimeout: Duration{Duration: 10 * time.Second},
+			BannerTimeout:  Duration{Duration: 10 * time.Second},
+			ForwardDrain:   Duration{Duration: 10 * time.Second},
 		},
 		Session: SessionConfig{
 			AutoRestore: true,
@@ -165,5 +268,8 @@ func DefaultConfig() Config {
 			Enabled:  true,
 			Interval: Duration{Duration: 24 * time.Hour},
 		},
+		UI: UIConfig{
+			TimeFormat: TimeFormatRelative,
+		},
 	}
 }
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -29,6 +30,9 @@ func TestDefaultConfig(t *testing.T) {
 	if cfg.Reconnect.KeepAliveInterval.Duration != 30*time.Second {
 		t.Errorf("Reconnect.KeepAliveInterval = %v, want 30s", cfg.Reconnect.KeepAliveInterval.Duration)
 	}
+	if cfg.Timeouts.ConnectTimeout.Duration != 10*time.Second || cfg.Timeouts.BannerTimeout.Duration != 10*time.Second {
+		t.Errorf("Timeouts = %+v, want 10s connect and banner timeouts", cfg.Timeouts)
+	}
 	if cfg.Hosts != nil {
 		t.Errorf("Hosts should be nil, got %v", cfg.Hosts)
 	}
@@ -187,6 +191,34 @@ func TestConfig_YAMLRoundtrip_WithHosts(t *testing.T) {
 	}
 }
 
+func TestHostConfig_YAMLMetadataInline(t *testing.T) {
+	input := `
+notes: shared bastion
+auth_hint: use yubikey
+jump_description: via office vpn
+tags: [env:prod, team:infra]
+`
+	var hc HostConfig
+	if err := yaml.Unmarshal([]byte(input), &hc); err != nil {
+		t.Fatalf("Unmarshal HostConfig: %v", err)
+	}
+	want := HostMetadata{Notes: "shared bastion", AuthHint: "use yubikey", JumpDescription: "via office vpn", Tags: []string{"env:prod", "team:infra"}}
+	if !reflect.DeepEqual(hc.HostMetadata, want) {
+		t.Errorf("HostMetadata = %+v, want %+v", hc.HostMetadata, want)
+	}
+	if hc.IsZero() {
+		t.Error("IsZero() = true, want false")
+	}
+
+	data, err := yaml.Marshal(HostConfig{})
+	if err != nil {
+		t.Fatalf("Marshal HostConfig: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != "{}" {
+		t.Errorf("empty HostConfig marshaled to %q, want {}", data)
+	}
+}
+
 func TestForwardRule_YAMLRoundtrip_RemoteBindAddr(t *testing.T) {
 	original := ForwardRule{
 		Name:           "remote-fwd",
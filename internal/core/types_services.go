@@ -0,0 +1,8 @@
+package core
+
+// HealthCheckConfig はホストの疎通確認の設定。
+type HealthCheckConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Interval は確認の間隔。0 の場合は既定値（5 分）を使い、30 秒未満は 30 秒に切り上げる。
+	Interval Duration `yaml:"interval,omitempty" schema:"default=5m,min=30s"`
+}
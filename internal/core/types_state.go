@@ -0,0 +1,46 @@
+package core
+
+import "time"
+
+// StopReason はセッションが停止またはエラーになった理由を表す。値は IPC のワイヤー形式と同じ文字列。
+type StopReason string
+
+const (
+	// StopReasonNone は稼働中、または一度も開始していないことを表す。
+	StopReasonNone StopReason = ""
+	// StopReasonUser はユーザー操作（forward.stop、ルールの削除など）による停止。
+	StopReasonUser StopReason = "user"
+	// StopReasonSSHLost は SSH 接続の切断による停止。
+	StopReasonSSHLost StopReason = "ssh_lost"
+	// StopReasonTTL は TTL の期限切れによる停止。
+	StopReasonTTL StopReason = "ttl"
+	// StopReasonDaemonShutdown はデーモンの終了による停止。
+	StopReasonDaemonShutdown StopReason = "daemon_shutdown"
+	// StopReasonError はリスナーの異常終了や復元の失敗による停止。
+	StopReasonError StopReason = "error"
+)
+
+// StopRecord はルールの最後の停止理由と時刻を表す。state.yaml に保存され、デーモンの再起動後も引き継がれる。
+type StopRecord struct {
+	Rule   string     `yaml:"rule"`
+	Reason StopReason `yaml:"reason"`
+	At     time.Time  `yaml:"at"`
+}
+
+// SessionRecord はアクティブなフォワードの開始時刻と再接続回数を表す。異常終了からの復元で前回の稼働状況を伝えるために保存する。
+type SessionRecord struct {
+	Rule           string    `yaml:"rule"`
+	StartedAt      time.Time `yaml:"started_at"`
+	ReconnectCount int       `yaml:"reconnect_count,omitempty"`
+}
+
+// State はアプリケーション終了時のセッション状態を保持する。
+type State struct {
+	LastUpdated    time.Time     `yaml:"last_updated"`
+	ActiveForwards []ForwardRule `yaml:"active_forwards"`
+	SelectedHost   string        `yaml:"selected_host"`
+	// Sessions は ActiveForwards のルールごとの開始時刻と再接続回数。
+	Sessions []SessionRecord `yaml:"sessions,omitempty"`
+	// StopHistory は停止中のルールごとの最後の停止理由。
+	StopHistory []StopRecord `yaml:"stop_history,omitempty"`
+}
@@ -1,9 +1,12 @@
-package daemon
+package autostart
 
 import (
 	"fmt"
 	"time"
 
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/ipc/authtoken"
 	"github.com/ousiassllc/moleport/internal/ipc/client"
 )
 
@@ -16,19 +19,26 @@ var startDaemonFunc = StartDaemonProcess
 // EnsureDaemon はデーモンが起動中であることを確認し、接続済みの IPCClient を返す。
 // デーモンが起動していない場合は自動的にデーモンプロセスを起動してから接続する。
 func EnsureDaemon(configDir string) (*client.IPCClient, error) {
-	pidPath := PIDFilePath(configDir)
-	running, _ := IsRunning(pidPath)
+	pidPath := daemon.PIDFilePath(configDir)
+	running, _ := pidfile.IsRunning(pidPath)
 	if !running {
 		if _, err := startDaemonFunc(configDir); err != nil {
 			return nil, fmt.Errorf("failed to auto-start daemon: %w", err)
 		}
 	}
+	return Connect(configDir)
+}
 
-	c := client.NewIPCClient(SocketPath(configDir))
+// Connect はデーモンを起動せずに接続し、接続済みの IPCClient を返す。
+// --no-daemon を指定した CLI/TUI が使う。
+func Connect(configDir string) (*client.IPCClient, error) {
+	c := client.NewIPCClient(daemon.SocketPath(configDir))
+	if token, err := authtoken.Read(daemon.TokenPath(configDir)); err == nil {
+		c.SetToken(token)
+	}
 	if err := c.Connect(); err != nil {
 		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
-
 	return c, nil
 }
 
@@ -1,9 +1,12 @@
-package daemon
+package autostart
 
 import (
 	"errors"
 	"strings"
 	"testing"
+
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
 )
 
 func TestEnsureDaemon_AutoStartFailure(t *testing.T) {
@@ -28,7 +31,7 @@ func TestEnsureDaemon_AlreadyRunning_ConnectFails(t *testing.T) {
 	dir := t.TempDir()
 
 	// PIDファイルを作成し、自プロセスのPIDを書き込む（デーモン稼働中と見せかける）
-	pf := NewPIDFile(PIDFilePath(dir))
+	pf := pidfile.New(daemon.PIDFilePath(dir))
 	if err := pf.Acquire(); err != nil {
 		t.Fatalf("Acquire: %v", err)
 	}
@@ -43,3 +46,19 @@ func TestEnsureDaemon_AlreadyRunning_ConnectFails(t *testing.T) {
 		t.Errorf("unexpected error: %s", err)
 	}
 }
+
+func TestConnect_DoesNotStartDaemon(t *testing.T) {
+	dir := t.TempDir()
+
+	orig := startDaemonFunc
+	startDaemonFunc = func(configDir string) (int, error) {
+		t.Error("Connect() should not start the daemon")
+		return 0, errors.New("unexpected start")
+	}
+	defer func() { startDaemonFunc = orig }()
+
+	_, err := Connect(dir)
+	if err == nil || !strings.Contains(err.Error(), "failed to connect to daemon") {
+		t.Errorf("Connect() error = %v, want connect failure", err)
+	}
+}
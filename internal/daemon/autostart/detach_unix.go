@@ -0,0 +1,10 @@
+//go:build !windows
+
+package autostart
+
+import "syscall"
+
+// detachedProcAttr は端末から切り離すため、新しいセッションでデーモンを起動する属性を返す。
+func detachedProcAttr() *syscall.SysProcAttr {
+	return &syscall.SysProcAttr{Setsid: true}
+}
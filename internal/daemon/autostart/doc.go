@@ -0,0 +1,2 @@
+// Package autostart はデーモンプロセスの起動と、デーモンが起動していない場合に自動起動してから接続する処理を提供する。
+package autostart
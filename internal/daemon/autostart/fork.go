@@ -1,11 +1,12 @@
-package daemon
+package autostart
 
 import (
 	"fmt"
-	"net"
 	"os"
-	"syscall"
 	"time"
+
+	"github.com/ousiassllc/moleport/internal/daemon"
+	"github.com/ousiassllc/moleport/internal/ipc/localsock"
 )
 
 const (
@@ -44,7 +45,7 @@ func StartDaemonProcess(configDir string) (int, error) {
 		Dir:   "/",
 		Env:   os.Environ(),
 		Files: []*os.File{devNull, devNull, devNull},
-		Sys:   &syscall.SysProcAttr{Setsid: true},
+		Sys:   detachedProcAttr(),
 	}
 
 	proc, err := os.StartProcess(executable, args, attr)
@@ -56,10 +57,10 @@ func StartDaemonProcess(configDir string) (int, error) {
 	proc.Release()
 
 	// デーモンの起動完了を待機（ソケット接続を試行）
-	socketPath := SocketPath(configDir)
+	socketPath := daemon.SocketPath(configDir)
 	deadline := time.Now().Add(forkStartupTimeout)
 	for time.Now().Before(deadline) {
-		conn, err := net.DialTimeout("unix", socketPath, forkDialTimeout)
+		conn, err := localsock.DialTimeout(socketPath, forkDialTimeout)
 		if err == nil {
 			_ = conn.Close()
 			return pid, nil
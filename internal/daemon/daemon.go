@@ -8,19 +8,31 @@ import (
 	"os/signal"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/core/forward"
-	"github.com/ousiassllc/moleport/internal/core/ssh"
+	"github.com/ousiassllc/moleport/internal/core/config"
 	"github.com/ousiassllc/moleport/internal/core/update"
+	"github.com/ousiassllc/moleport/internal/daemon/liveconfig"
+	"github.com/ousiassllc/moleport/internal/daemon/logbuf"
+	"github.com/ousiassllc/moleport/internal/daemon/pidfile"
+	"github.com/ousiassllc/moleport/internal/daemon/sharedsvc"
 	"github.com/ousiassllc/moleport/internal/infra"
-	"github.com/ousiassllc/moleport/internal/infra/sshconfig"
+	"github.com/ousiassllc/moleport/internal/infra/desktopnotify"
+	"github.com/ousiassllc/moleport/internal/infra/hookexec"
+	"github.com/ousiassllc/moleport/internal/infra/secretstore"
+	"github.com/ousiassllc/moleport/internal/infra/sshauth"
+	"github.com/ousiassllc/moleport/internal/infra/tunnelregistry"
+	"github.com/ousiassllc/moleport/internal/infra/webhook"
 	"github.com/ousiassllc/moleport/internal/infra/yamlstore"
 	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
 	ipchandler "github.com/ousiassllc/moleport/internal/ipc/handler"
+	"github.com/ousiassllc/moleport/internal/ipc/localsock"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/wsbridge"
 )
 
 // LogConfig はデーモンのログ設定を保持する。
@@ -33,7 +45,7 @@ type LogConfig struct {
 // 設定の読み込みに失敗した場合はデフォルトの設定を使用する。
 func ResolveLogConfig(configDir string) LogConfig {
 	store := yamlstore.NewYAMLStore()
-	cfgMgr := core.NewConfigManager(store, configDir)
+	cfgMgr := config.NewConfigManager(store, configDir)
 	cfg, err := cfgMgr.LoadConfig()
 	if err != nil {
 		c := core.DefaultConfig()
@@ -46,9 +58,14 @@ func ResolveLogConfig(configDir string) LogConfig {
 	return LogConfig{Path: logPath, Level: cfg.Log.Level}
 }
 
-// SocketPath はデーモンの Unix ソケットパスを返す。
+// SocketPath はデーモンの Unix ソケットパス（Windows では名前付きパイプのパス）を返す。
 func SocketPath(configDir string) string {
-	return filepath.Join(configDir, "moleport.sock")
+	return localsock.Path(configDir, "moleport.sock")
+}
+
+// MsgpackSocketPath は MessagePack のトランスポートで待ち受けるソケットパスを返す。
+func MsgpackSocketPath(configDir string) string {
+	return localsock.Path(configDir, "moleport.msgpack.sock")
 }
 
 // PIDFilePath はデーモンの PID ファイルパスを返す。
@@ -56,21 +73,44 @@ func PIDFilePath(configDir string) string {
 	return filepath.Join(configDir, "moleport.pid")
 }
 
+// TokenPath はデーモンが起動時に発行する認証トークンのファイルパスを返す。
+func TokenPath(configDir string) string {
+	return filepath.Join(configDir, "moleport.token")
+}
+
+// ReadOnlyTokenPath はデーモンが起動時に発行する読み取り専用トークンのファイルパスを返す。
+func ReadOnlyTokenPath(configDir string) string {
+	return filepath.Join(configDir, "moleport-readonly.token")
+}
+
 // Daemon はデーモンプロセスの全コンポーネントを保持し、ライフサイクルを管理する。
+// 既定以外のプロファイルも同じ型のランタイムとして保持し、IPC サーバーとバージョンチェッカーを共有する。
 type Daemon struct {
-	configDir string
-	version   string
-	startedAt time.Time
-
-	cfgMgr         core.ConfigManager
-	sshMgr         core.SSHManager
-	fwdMgr         core.ForwardManager
+	configDir     string
+	sshConfigPath string
+	version       string
+	startedAt     time.Time
+
+	cfgMgr core.ConfigManager
+	sshMgr core.SSHManager
+	fwdMgr core.ForwardManager
+	// hooks は fwdMgr が包むフックの実行役。フォワードのイベントで on_start・on_stop を実行する。
+	hooks          *hookexec.Manager
 	versionChecker *update.VersionChecker
 
-	broker  *ipc.EventBroker
-	handler *ipchandler.Handler
-	server  *ipc.IPCServer
-	pidFile *PIDFile
+	broker   *broker.EventBroker
+	webhooks *webhook.Dispatcher
+	notifier *desktopnotify.Notifier
+	registry *tunnelregistry.Registry
+	handler  *ipchandler.Handler
+	server   *ipc.IPCServer
+	pidFile  *pidfile.File
+	// live は設定ファイルの変更を反映する Applier。監視の開始後に設定し、daemon.health が参照する。
+	live atomic.Pointer[liveconfig.Applier]
+	// ws は WebSocket のイベントブリッジ。EventBroker の送信関数から参照する。
+	ws atomic.Pointer[wsbridge.Server]
+	// shared は DNS リゾルバー・ホストの疎通確認・mDNS による公開。既定プロファイルの Daemon のみが起動し、全プロファイルを対象にする。
+	shared *sharedsvc.Services
 
 	ctx     context.Context
 	cancel  context.CancelFunc
@@ -78,88 +118,43 @@ type Daemon struct {
 	wg      sync.WaitGroup
 	stopped bool
 	purge   bool
+	// stateMu は SSH と Forward のイベントのゴルーチンからの saveState を直列化する。
+	stateMu sync.Mutex
 
 	warnings []string
+
+	// crashed は前回のデーモンが異常終了していたか。recovery はその復元結果（daemon.status で公開する）。
+	crashed  bool
+	recovery *protocol.RecoverySummary
+
+	// profiles は起動済みの既定以外のプロファイル。既定プロファイルの Daemon のみが保持する。
+	profileMu sync.Mutex
+	profiles  map[string]*Daemon
 }
 
 // New は新しい Daemon を生成する。
 func New(configDir string, version string) (*Daemon, error) {
-	if err := os.MkdirAll(configDir, 0700); err != nil {
-		return nil, fmt.Errorf("create config dir: %w", err)
-	}
-
-	store := yamlstore.NewYAMLStore()
-	cfgMgr := core.NewConfigManager(store, configDir)
-	cfg, err := cfgMgr.LoadConfig()
+	d, err := newRuntime(context.Background(), configDir, version)
 	if err != nil {
-		c := core.DefaultConfig()
-		cfg = &c
+		return nil, err
 	}
-
-	// SSH config パスの ~ を展開
-	sshConfigPath := cfg.SSHConfigPath
-	if expanded, err := infra.ExpandTilde(sshConfigPath); err == nil {
-		sshConfigPath = expanded
+	cfg := d.cfgMgr.GetConfig()
+	d.pidFile = pidfile.New(PIDFilePath(configDir))
+	d.versionChecker = update.New(version, cfg.UpdateCheck.Enabled, cfg.UpdateCheck.Interval.Duration)
+	if cfg.Secrets.CachePassphrases {
+		sshauth.SetPassphraseCache(secretstore.New())
 	}
+	d.profiles = make(map[string]*Daemon)
 
-	parser := sshconfig.NewSSHConfigParser()
-
-	ctx, cancel := context.WithCancel(context.Background())
-	sshMgr := ssh.NewSSHManager(
-		ctx,
-		parser,
-		func() core.SSHConnection { return infra.NewSSHConnection() },
-		sshConfigPath,
-		cfg.Reconnect,
-		cfg.Hosts,
-	)
-	fwdMgr := forward.NewForwardManager(ctx, sshMgr)
-
-	// 保存済みのフォワードルールを読み込む
-	var warnings []string
-	for _, rule := range cfg.Forwards {
-		if _, err := fwdMgr.AddRule(rule); err != nil {
-			slog.Warn("failed to load forward rule", "rule", rule.Name, "error", err)
-			warnings = append(warnings, fmt.Sprintf("failed to load forward rule %q: %v", rule.Name, err))
-		}
-	}
+	// server は New() 完了前に必ず設定されるため、Start() 後の通知送信は安全
+	d.attachHandler(d)
+	server := ipc.NewIPCServer(SocketPath(configDir), d.route)
 
-	pidFile := NewPIDFile(PIDFilePath(configDir))
-	versionChecker := update.New(version, cfg.UpdateCheck.Enabled, cfg.UpdateCheck.Interval.Duration)
-
-	// Daemon を先に生成し、IPC コンポーネントに渡す
-	d := &Daemon{
-		configDir:      configDir,
-		version:        version,
-		cfgMgr:         cfgMgr,
-		sshMgr:         sshMgr,
-		fwdMgr:         fwdMgr,
-		versionChecker: versionChecker,
-		pidFile:        pidFile,
-		ctx:            ctx,
-		cancel:         cancel,
-		warnings:       warnings,
-	}
-
-	// EventBroker: server.SendNotification をクロージャで渡す
-	// server は New() 完了前に必ず設定されるため、Start() 後の呼び出しは安全
-	broker := ipc.NewEventBroker(func(clientID string, notification protocol.Notification) error {
-		return d.server.SendNotification(clientID, notification)
-	})
-
-	handler := ipchandler.NewHandler(sshMgr, fwdMgr, cfgMgr, broker, d, versionChecker)
-	server := ipc.NewIPCServer(SocketPath(configDir), handler.Handle)
-
-	// クライアント切断時にブローカーから購読を削除する
-	server.OnClientDisconnected = func(clientID string) {
-		broker.RemoveClient(clientID)
-	}
+	// クライアント切断時に全プロファイルのブローカーから購読を削除する
+	server.OnClientDisconnected = d.removeClient
 
 	// Handler に通知送信用のサーバー参照を設定
-	handler.SetSender(server)
-
-	d.broker = broker
-	d.handler = handler
+	d.handler.SetSender(server)
 	d.server = server
 
 	return d, nil
@@ -184,24 +179,25 @@ func (d *Daemon) Start(ctx context.Context) error {
 	}()
 	d.startedAt = time.Now()
 	d.stopped = false
+	d.crashed = d.pidFile.Stale()
 
+	if err := d.setupAuth(); err != nil {
+		d.pidFile.Release()
+		return err
+	}
 	if err := d.server.Start(d.ctx); err != nil {
 		d.pidFile.Release()
 		return fmt.Errorf("start ipc server: %w", err)
 	}
-
-	// SSH ホストを読み込む（エラーは警告のみ）
-	if _, err := d.sshMgr.LoadHosts(); err != nil {
-		slog.Warn("failed to load SSH hosts", "error", err)
-		d.warnings = append(d.warnings, fmt.Sprintf("failed to load SSH hosts: %v", err))
-	}
+	d.startRemote()
+	d.startMsgpack()
+	d.startEventBridge()
 
 	const versionCheckInterval = 10 * time.Second
 	d.versionChecker.Start(d.ctx, versionCheckInterval)
 
-	d.startEventRouting()
-	d.restoreState()
-	d.autoStartForwards()
+	d.startRuntime()
+	d.startSharedServices()
 
 	slog.Info("daemon started", "pid", os.Getpid(), "config_dir", d.configDir)
 	return nil
@@ -220,28 +216,13 @@ func (d *Daemon) Stop() error {
 	slog.Info("daemon stopping")
 
 	d.versionChecker.Stop()
-
-	// コンテキストを最初にキャンセルして全コンポーネントに停止を通知
-	if d.cancel != nil {
-		d.cancel()
+	d.stopProfiles()
+	d.broker.NotifyShutdown()
+	d.stopRuntime()
+	if d.shared != nil {
+		d.shared.Stop()
 	}
-
-	if d.purge {
-		if err := d.cfgMgr.DeleteState(); err != nil {
-			slog.Warn("failed to delete state", "error", err)
-		}
-	} else {
-		d.saveState()
-	}
-
-	if err := d.fwdMgr.StopAllForwards(); err != nil {
-		slog.Warn("failed to stop all forwards", "error", err)
-	}
-	d.fwdMgr.Close()
-	d.sshMgr.Close()
-
-	// イベントルーティングゴルーチンの終了を待つ
-	d.wg.Wait()
+	d.stopEventBridge()
 
 	if err := d.server.Stop(); err != nil {
 		slog.Warn("failed to stop ipc server", "error", err)
@@ -255,6 +236,11 @@ func (d *Daemon) Stop() error {
 	return nil
 }
 
+// SetLogs は logs に記録したデーモンのログを logs.subscribe で配信するように設定する。Start の前に呼び出す。
+func (d *Daemon) SetLogs(logs *logbuf.Buffer) {
+	d.handler.SetLogSource(logs)
+}
+
 // Wait はシグナル (SIGTERM/SIGINT) を待ち、受信したら Stop() を呼ぶ。
 func (d *Daemon) Wait() error {
 	sigCh := make(chan os.Signal, 1)
@@ -2,10 +2,13 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/ousiassllc/moleport/internal/ipc/authtoken"
 	ipcclient "github.com/ousiassllc/moleport/internal/ipc/client"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
@@ -49,7 +52,7 @@ func TestDaemon_EventRouting(t *testing.T) {
 	}
 }
 
-func TestEnsureDaemon_Running(t *testing.T) {
+func TestDaemon_StatusOverSocket(t *testing.T) {
 	dir := createTestConfigDir(t)
 
 	d, err := New(dir, "test")
@@ -63,16 +66,12 @@ func TestEnsureDaemon_Running(t *testing.T) {
 	}
 	defer func() { _ = d.Stop() }()
 
-	client, err := EnsureDaemon(dir)
-	if err != nil {
-		t.Fatalf("EnsureDaemon() error: %v", err)
+	client := ipcclient.NewIPCClient(SocketPath(dir))
+	if err := client.Connect(); err != nil {
+		t.Fatalf("client Connect() error: %v", err)
 	}
 	defer func() { _ = client.Close() }()
 
-	if !client.IsConnected() {
-		t.Error("client is not connected")
-	}
-
 	// daemon.status を呼び出して応答を確認
 	callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
@@ -89,3 +88,48 @@ func TestEnsureDaemon_Running(t *testing.T) {
 		t.Errorf("Version = %q, want %q", status.Version, "test")
 	}
 }
+
+func TestDaemon_RequireToken(t *testing.T) {
+	dir := createTestConfigDir(t)
+	f, err := os.OpenFile(filepath.Join(dir, "config.yaml"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.WriteString("auth:\n  require_token: true\n")
+	_ = f.Close()
+
+	d, err := New(dir, "test")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer func() { _ = d.Stop() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	anon := ipcclient.NewIPCClient(SocketPath(dir))
+	if err := anon.Connect(); err != nil {
+		t.Fatalf("Connect() error: %v", err)
+	}
+	defer func() { _ = anon.Close() }()
+	var rpcErr *protocol.RPCError
+	if err := anon.Call(ctx, "daemon.status", nil, nil); !errors.As(err, &rpcErr) || rpcErr.Code != protocol.Unauthorized {
+		t.Errorf("daemon.status without token error = %v, want Unauthorized", err)
+	}
+
+	token, err := authtoken.Read(TokenPath(dir))
+	if err != nil {
+		t.Fatalf("read token: %v", err)
+	}
+	c := ipcclient.NewIPCClient(SocketPath(dir))
+	c.SetToken(token)
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() with token error: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+	if err := c.Call(ctx, "daemon.status", nil, nil); err != nil {
+		t.Errorf("daemon.status with token error = %v", err)
+	}
+}
@@ -6,14 +6,24 @@ import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/depgraph"
+	"github.com/ousiassllc/moleport/internal/daemon/health"
+	"github.com/ousiassllc/moleport/internal/daemon/hostsync"
+	"github.com/ousiassllc/moleport/internal/daemon/reconcile"
+	"github.com/ousiassllc/moleport/internal/daemon/recovery"
+	"github.com/ousiassllc/moleport/internal/faultinject"
+	"github.com/ousiassllc/moleport/internal/infra/webhook"
+	debughandler "github.com/ousiassllc/moleport/internal/ipc/handler/debug"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
-// startEventRouting は SSH/Forward イベントをブローカーにルーティングするゴルーチンを開始する。
-// SSH 再接続イベントを検知してフォワード復元をトリガーする。
+// startEventRouting は SSH/Forward イベントをブローカー・Webhook・デスクトップ通知・リモートフォワードの登録先・
+// ルールのフックにルーティングするゴルーチンを開始する。
+// SSH の切断・接続イベントを検知してフォワードの再接続待ちと復元をトリガーし、depends_on の依存先の失敗を依存するルールに伝える。
 func (d *Daemon) startEventRouting() {
 	sshEvents := d.sshMgr.Subscribe()
 	fwdEvents := d.fwdMgr.Subscribe()
+	deps, _ := d.fwdMgr.(*depgraph.Manager)
 
 	d.wg.Add(2)
 	go func() {
@@ -21,22 +31,33 @@ func (d *Daemon) startEventRouting() {
 		reconnecting := make(map[string]bool)
 		for evt := range sshEvents {
 			d.broker.HandleSSHEvent(evt)
+			if hook, ok := webhook.FromSSHEvent(evt); ok {
+				d.webhooks.Publish(hook)
+			}
+			d.notifier.HandleSSHEvent(evt)
+			deps.HandleSSHEvent(evt)
 			switch evt.Type {
 			case core.SSHEventReconnecting:
 				reconnecting[evt.HostName] = true
 				d.fwdMgr.MarkReconnecting(evt.HostName)
+			case core.SSHEventDisconnected:
+				d.fwdMgr.MarkAutoReconnecting(evt.HostName)
 			case core.SSHEventConnected:
-				if reconnecting[evt.HostName] {
-					delete(reconnecting, evt.HostName)
-					results := d.fwdMgr.RestoreForwards(evt.HostName)
-					d.logRestoreSummary(evt.HostName, results)
-				}
+				// auto_reconnect のルールは再接続中でなくても復元対象になるため、接続のたびに復元する
+				delete(reconnecting, evt.HostName)
+				recovery.LogSummary(evt.HostName, d.fwdMgr.RestoreForwards(evt.HostName))
 			case core.SSHEventError:
 				if reconnecting[evt.HostName] {
 					delete(reconnecting, evt.HostName)
 					d.fwdMgr.FailReconnecting(evt.HostName)
+					d.webhooks.Publish(webhook.ReconnectFailed(evt))
 				}
 			}
+			// 再接続中・復元後のフォワードの状態と再接続回数をスナップショットに反映する
+			switch evt.Type {
+			case core.SSHEventReconnecting, core.SSHEventDisconnected, core.SSHEventConnected, core.SSHEventError:
+				d.saveStateIfRunning()
+			}
 		}
 	}()
 
@@ -44,90 +65,86 @@ func (d *Daemon) startEventRouting() {
 		defer d.wg.Done()
 		for evt := range fwdEvents {
 			d.broker.HandleForwardEvent(evt)
+			if hook, ok := webhook.FromForwardEvent(evt); ok {
+				d.webhooks.Publish(hook)
+			}
+			d.notifier.HandleForwardEvent(evt)
+			d.registry.HandleForwardEvent(evt)
+			d.hooks.HandleForwardEvent(evt)
+			deps.HandleForwardEvent(evt)
+			// 異常終了に備えて稼働中もスナップショットを更新する。転送量などのメトリクスの更新では保存しない
+			switch evt.Type {
+			case core.ForwardEventStarted, core.ForwardEventStopped, core.ForwardEventRestored,
+				core.ForwardEventReconnecting, core.ForwardEventError, core.ForwardEventMigrated, core.ForwardEventUpdated:
+				d.saveStateIfRunning()
+			}
 		}
 	}()
 }
 
-// logRestoreSummary はフォワード復元結果のサマリーをログ出力する。
-func (d *Daemon) logRestoreSummary(hostName string, results []core.ForwardRestoreResult) {
-	if len(results) == 0 {
-		return
-	}
-	succeeded, failed := 0, 0
-	for _, r := range results {
-		if r.OK {
-			succeeded++
-		} else {
-			failed++
-			slog.Warn("forward restore failed", "host", hostName, "rule", r.RuleName, "error", r.Error)
-		}
-	}
-	slog.Info("forward restore summary", "host", hostName, "total", len(results), "succeeded", succeeded, "failed", failed)
-}
-
-// restoreState は前回の状態を復元する。auto_restore が有効な場合のみ。
+// restoreState は前回の状態を復元する。停止理由の履歴は常に引き継ぎ、フォワードの再開は
+// auto_restore が有効な場合か、前回のデーモンが異常終了していた場合に行う。
+// 異常終了からの復元では結果を recovered イベントとして配信し、daemon.status でも公開する。
 func (d *Daemon) restoreState() {
-	cfg := d.cfgMgr.GetConfig()
-	if !cfg.Session.AutoRestore {
-		return
-	}
-
 	state, err := d.cfgMgr.LoadState()
 	if err != nil {
 		slog.Debug("no state to restore", "error", err)
 		return
 	}
+	d.fwdMgr.LoadStopHistory(state.StopHistory)
 
-	for _, rule := range state.ActiveForwards {
-		if err := d.fwdMgr.StartForward(rule.Name, nil); err != nil {
-			slog.Warn("failed to restore forward", "rule", rule.Name, "error", err)
-		}
+	cfg := d.cfgMgr.GetConfig()
+	if !cfg.Session.AutoRestore && !d.crashed {
+		return
+	}
+
+	results := recovery.Restore(d.ctx, d.fwdMgr, state)
+	recovery.LogSummary("", results)
+	if d.crashed {
+		summary := recovery.Summarize(state, results)
+		d.recovery = &summary
+		d.broker.NotifyRecovered(summary)
+		slog.Warn("recovered from unclean shutdown",
+			"snapshot_at", summary.SnapshotAt, "restored", len(summary.Restored), "failed", len(summary.Failed))
 	}
 }
 
-// autoStartForwards は config.yaml で auto_connect が有効なフォワードルールを自動開始する。
-// restoreState() で既に開始済みのルールはスキップする。
+// autoStartForwards は config.yaml とホストの定義で auto_connect が有効なフォワードルールを自動開始する。
+// restoreState() で既に開始済みのルールはスキップする。開始できなかったルールは reconnect の設定に従って
+// バックグラウンドで再試行し、進捗を autostart イベントとして配信する。
 func (d *Daemon) autoStartForwards() {
 	cfg := d.cfgMgr.GetConfig()
 
-	var started, skipped, failed int
-	for _, rule := range cfg.Forwards {
-		if !rule.AutoConnect {
-			continue
-		}
-		// restoreState() で既にアクティブなルールはスキップ
-		if s, err := d.fwdMgr.GetSession(rule.Name); err == nil && s.Status == core.Active {
-			skipped++
-			continue
-		}
-		// cb=nil: daemon 起動時は対話的認証が不可のため、鍵認証/エージェントのみで接続を試みる
-		if err := d.fwdMgr.StartForward(rule.Name, nil); err != nil {
-			slog.Warn("auto-start forward failed", "rule", rule.Name, "error", err)
-			failed++
-		} else {
-			started++
-		}
+	rules := hostsync.AutoConnect(cfg.Forwards, d.fwdMgr.GetRules())
+	res := reconcile.Run(d.ctx, d.fwdMgr, rules, d.broker.NotifyAutoStart)
+	if len(rules) > 0 {
+		slog.Info("auto-start forwards summary", "started", res.Started, "skipped", res.Skipped, "failed", len(res.Failed))
 	}
 
-	if started+failed+skipped > 0 {
-		slog.Info("auto-start forwards summary", "started", started, "skipped", skipped, "failed", failed)
+	policy := reconcile.PolicyFrom(cfg.Reconnect)
+	for _, f := range res.Failed {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			reconcile.Retry(d.ctx, d.fwdMgr, f, policy, d.broker.NotifyAutoStart)
+		}()
 	}
 }
 
-// saveState はアクティブなフォワード状態を保存する。
-func (d *Daemon) saveState() {
-	sessions := d.fwdMgr.GetAllSessions()
-	var activeRules []core.ForwardRule
-	for _, s := range sessions {
-		if s.Status == core.Active {
-			activeRules = append(activeRules, s.Rule)
-		}
+// saveStateIfRunning はデーモンの稼働中に saveState を呼び出す。停止処理中は stopRuntime が保存する。
+func (d *Daemon) saveStateIfRunning() {
+	if d.ctx.Err() == nil {
+		d.saveState()
 	}
+}
 
-	state := &core.State{
-		LastUpdated:    time.Now(),
-		ActiveForwards: activeRules,
-	}
+// saveState はアクティブなフォワード状態と停止理由の履歴を保存する。
+func (d *Daemon) saveState() {
+	d.stateMu.Lock()
+	defer d.stateMu.Unlock()
+
+	shuttingDown := d.ctx != nil && d.ctx.Err() != nil
+	state := recovery.Snapshot(d.fwdMgr.GetAllSessions(), shuttingDown, time.Now())
 
 	if err := d.cfgMgr.SaveState(state); err != nil {
 		slog.Warn("failed to save state, retrying", "error", err)
@@ -163,7 +180,7 @@ func (d *Daemon) Status() protocol.DaemonStatusResult {
 		connectedClients = d.server.ConnectedClients()
 	}
 
-	return protocol.DaemonStatusResult{
+	result := protocol.DaemonStatusResult{
 		Version:              d.version,
 		PID:                  os.Getpid(),
 		StartedAt:            d.startedAt.Format(time.RFC3339),
@@ -172,5 +189,27 @@ func (d *Daemon) Status() protocol.DaemonStatusResult {
 		ActiveSSHConnections: activeSSH,
 		ActiveForwards:       activeForwards,
 		Warnings:             d.warnings,
+		Recovery:             d.recovery,
+	}
+	if faultinject.Available {
+		faults := debughandler.CountersInfo()
+		result.Faults = &faults
+	}
+	return result
+}
+
+// Health はサブシステムごとの稼働状況を返す。フォワードと SSH 接続は profile のランタイムを対象にし、
+// require に指定したルールは active であることを確認する。
+func (d *Daemon) Health(profile string, require []string) protocol.DaemonHealthResult {
+	rt := d
+	if profile != protocol.DefaultProfile {
+		if p, err := d.profile(profile, false); err == nil {
+			rt = p
+		}
+	}
+	in := health.Input{SocketPath: SocketPath(d.configDir), Sessions: rt.fwdMgr.GetAllSessions(), Hosts: rt.sshMgr.GetHosts(), Require: require}
+	if live := rt.live.Load(); live != nil {
+		in.ConfigErr = live.Err()
 	}
+	return health.Check(in)
 }
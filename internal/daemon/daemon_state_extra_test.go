@@ -1,15 +1,17 @@
 package daemon
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	cryptossh "golang.org/x/crypto/ssh"
 
 	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
@@ -28,8 +30,8 @@ func (m *mockSSHManagerForState) GetHosts() []core.SSHHost             { return
 func (m *mockSSHManagerForState) GetHost(string) (*core.SSHHost, error) {
 	return nil, fmt.Errorf("not found")
 }
-func (m *mockSSHManagerForState) Connect(string) error { return nil }
-func (m *mockSSHManagerForState) ConnectWithCallback(string, core.CredentialCallback) error {
+func (m *mockSSHManagerForState) Connect(context.Context, string) error { return nil }
+func (m *mockSSHManagerForState) ConnectWithCallback(context.Context, string, core.CredentialCallback) error {
 	return nil
 }
 func (m *mockSSHManagerForState) GetPendingAuthHosts() []string { return nil }
@@ -48,32 +50,12 @@ func (m *mockSSHManagerForState) Subscribe() <-chan core.SSHEvent {
 	return make(chan core.SSHEvent, 1)
 }
 func (m *mockSSHManagerForState) Close() {}
-
-// newBrokerStub は通知を無視するテスト用 EventBroker を返す。
-func newBrokerStub() *ipc.EventBroker {
-	return ipc.NewEventBroker(func(string, protocol.Notification) error { return nil })
+func (m *mockSSHManagerForState) UpdateSettings(core.ReconnectConfig, map[string]core.HostConfig, core.TimeoutConfig) {
 }
 
-// --- Tests: logRestoreSummary ---
-
-func TestLogRestoreSummary(t *testing.T) {
-	tests := []struct {
-		name    string
-		results []core.ForwardRestoreResult
-	}{
-		{"empty", nil},
-		{"all_success", []core.ForwardRestoreResult{{RuleName: "web", OK: true}, {RuleName: "db", OK: true}}},
-		{"mixed", []core.ForwardRestoreResult{
-			{RuleName: "web", OK: true}, {RuleName: "db", OK: false, Error: "refused"}, {RuleName: "api", OK: true},
-		}},
-		{"all_failed", []core.ForwardRestoreResult{{RuleName: "web", OK: false, Error: "timeout"}}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			d := newDaemonForStateTest(&core.Config{}, &mockForwardManagerForState{})
-			d.logRestoreSummary("myhost", tt.results) // パニックしないことを確認
-		})
-	}
+// newBrokerStub は通知を無視するテスト用 EventBroker を返す。
+func newBrokerStub() *broker.EventBroker {
+	return broker.NewEventBroker(func(string, protocol.Notification) error { return nil })
 }
 
 // --- Tests: restoreState ---
@@ -108,24 +90,26 @@ func TestRestoreState(t *testing.T) {
 		if len(fwd.startCalls) != 2 || fwd.startCalls[0] != "web" || fwd.startCalls[1] != "db" {
 			t.Fatalf("startCalls = %v, want [web db]", fwd.startCalls)
 		}
+		if d.recovery != nil {
+			t.Errorf("recovery = %+v, want nil for a clean restart", d.recovery)
+		}
 	})
-	t.Run("restore_with_start_failure", func(t *testing.T) {
-		fwd := &mockForwardManagerForState{startForwardFn: func(n string, _ core.CredentialCallback) error {
-			if n == "web" {
-				return fmt.Errorf("fail")
-			}
-			return nil
-		}}
+	t.Run("crash_recovery_ignores_auto_restore", func(t *testing.T) {
+		fwd := &mockForwardManagerForState{}
 		cfgMgr := &mockConfigManagerForState{
-			config: &core.Config{Session: core.SessionConfig{AutoRestore: true}},
+			config: &core.Config{},
 			loadStateFn: func() (*core.State, error) {
-				return &core.State{ActiveForwards: []core.ForwardRule{{Name: "web"}, {Name: "db"}}}, nil
+				return &core.State{ActiveForwards: []core.ForwardRule{{Name: "web", Host: "prod"}}}, nil
 			},
 		}
 		d := newDaemonForStateTestFull(cfgMgr, fwd)
+		d.sshMgr, d.broker, d.crashed = &mockSSHManagerForState{}, newBrokerStub(), true
 		d.restoreState()
-		if len(fwd.startCalls) != 2 {
-			t.Fatalf("startCalls = %d, want 2", len(fwd.startCalls))
+		if len(fwd.startCalls) != 1 || d.recovery == nil || d.recovery.Hosts[0] != "prod" {
+			t.Fatalf("startCalls = %v, recovery = %+v", fwd.startCalls, d.recovery)
+		}
+		if d.Status().Recovery != d.recovery {
+			t.Error("Status().Recovery should expose the recovery summary")
 		}
 	})
 }
@@ -138,7 +122,7 @@ func TestSaveState(t *testing.T) {
 		fwd := &mockForwardManagerForState{getAllSessionsFn: func() []core.ForwardSession {
 			return []core.ForwardSession{
 				{Status: core.Active, Rule: core.ForwardRule{Name: "web"}},
-				{Status: core.Stopped, Rule: core.ForwardRule{Name: "db"}},
+				{Status: core.Stopped, Rule: core.ForwardRule{Name: "db"}, StoppedReason: core.StopReasonTTL},
 				{Status: core.Active, Rule: core.ForwardRule{Name: "api"}},
 			}
 		}}
@@ -150,8 +134,8 @@ func TestSaveState(t *testing.T) {
 		if saved == nil || len(saved.ActiveForwards) != 2 {
 			t.Fatalf("ActiveForwards count = %v, want 2", saved)
 		}
-		if saved.ActiveForwards[0].Name != "web" || saved.ActiveForwards[1].Name != "api" {
-			t.Errorf("names = [%s,%s], want [web,api]", saved.ActiveForwards[0].Name, saved.ActiveForwards[1].Name)
+		if len(saved.StopHistory) != 1 || saved.StopHistory[0].Rule != "db" {
+			t.Errorf("StopHistory = %+v, want [db]", saved.StopHistory)
 		}
 	})
 	t.Run("save_error_no_panic", func(t *testing.T) {
@@ -182,6 +166,12 @@ func TestSaveState(t *testing.T) {
 
 // --- Tests: startEventRouting ---
 
+// newRoutingTestDaemon は startEventRouting のテスト用に、スナップショットの保存先をモックにした Daemon を作る。
+func newRoutingTestDaemon(sshCh chan core.SSHEvent, fwd *mockForwardManagerForState) *Daemon {
+	return &Daemon{sshMgr: &mockSSHManagerForState{subscribeCh: sshCh}, fwdMgr: fwd, broker: newBrokerStub(),
+		cfgMgr: &mockConfigManagerForState{config: &core.Config{}}, ctx: context.Background()}
+}
+
 func TestStartEventRouting(t *testing.T) {
 	t.Run("reconnecting_then_connected_restores", func(t *testing.T) {
 		sshCh, fwdCh := make(chan core.SSHEvent, 4), make(chan core.ForwardEvent, 1)
@@ -193,7 +183,7 @@ func TestStartEventRouting(t *testing.T) {
 			mu.Unlock()
 			return []core.ForwardRestoreResult{{RuleName: "web", OK: true}}
 		}}
-		d := &Daemon{sshMgr: &mockSSHManagerForState{subscribeCh: sshCh}, fwdMgr: fwd, broker: newBrokerStub()}
+		d := newRoutingTestDaemon(sshCh, fwd)
 		d.startEventRouting()
 		sshCh <- core.SSHEvent{Type: core.SSHEventReconnecting, HostName: "h1"}
 		sshCh <- core.SSHEvent{Type: core.SSHEventConnected, HostName: "h1"}
@@ -214,7 +204,7 @@ func TestStartEventRouting(t *testing.T) {
 	t.Run("reconnecting_then_error_fails", func(t *testing.T) {
 		sshCh, fwdCh := make(chan core.SSHEvent, 4), make(chan core.ForwardEvent, 1)
 		fwd := &mockForwardManagerForState{subscribeCh: fwdCh}
-		d := &Daemon{sshMgr: &mockSSHManagerForState{subscribeCh: sshCh}, fwdMgr: fwd, broker: newBrokerStub()}
+		d := newRoutingTestDaemon(sshCh, fwd)
 		d.startEventRouting()
 		sshCh <- core.SSHEvent{Type: core.SSHEventReconnecting, HostName: "h1"}
 		sshCh <- core.SSHEvent{Type: core.SSHEventError, HostName: "h1"}
@@ -227,32 +217,40 @@ func TestStartEventRouting(t *testing.T) {
 		}
 		fwd.mu.Unlock()
 	})
-	t.Run("connected_without_reconnecting_no_restore", func(t *testing.T) {
+	t.Run("connected_without_reconnecting_restores", func(t *testing.T) {
 		sshCh, fwdCh := make(chan core.SSHEvent, 2), make(chan core.ForwardEvent, 1)
 		restored := false
 		fwd := &mockForwardManagerForState{subscribeCh: fwdCh, restoreForwardsFn: func(string) []core.ForwardRestoreResult {
 			restored = true
 			return nil
 		}}
-		d := &Daemon{sshMgr: &mockSSHManagerForState{subscribeCh: sshCh}, fwdMgr: fwd, broker: newBrokerStub()}
+		d := newRoutingTestDaemon(sshCh, fwd)
 		d.startEventRouting()
 		sshCh <- core.SSHEvent{Type: core.SSHEventConnected, HostName: "h1"}
 		close(sshCh)
 		close(fwdCh)
 		d.wg.Wait()
-		if restored {
-			t.Error("RestoreForwards called without prior reconnecting")
+		if !restored {
+			t.Error("RestoreForwards should be called on every connect for auto_reconnect rules")
 		}
 	})
-	t.Run("forward_events_routed", func(t *testing.T) {
-		sshCh, fwdCh := make(chan core.SSHEvent, 1), make(chan core.ForwardEvent, 2)
+	t.Run("forward_events_update_snapshot", func(t *testing.T) {
+		sshCh, fwdCh := make(chan core.SSHEvent, 1), make(chan core.ForwardEvent, 3)
 		fwd := &mockForwardManagerForState{subscribeCh: fwdCh}
-		d := &Daemon{sshMgr: &mockSSHManagerForState{subscribeCh: sshCh}, fwdMgr: fwd, broker: newBrokerStub()}
+		var saves atomic.Int32
+		d := newRoutingTestDaemon(sshCh, fwd)
+		d.cfgMgr = &mockConfigManagerForState{config: &core.Config{}, saveStateFn: func(*core.State) error { saves.Add(1); return nil }}
 		d.startEventRouting()
 		fwdCh <- core.ForwardEvent{Type: core.ForwardEventStarted, RuleName: "web"}
+		fwdCh <- core.ForwardEvent{Type: core.ForwardEventMetricsUpdated, RuleName: "web"}
+		fwdCh <- core.ForwardEvent{Type: core.ForwardEventReconnecting, RuleName: "web"}
+		sshCh <- core.SSHEvent{Type: core.SSHEventReconnecting, HostName: "h1"}
 		close(sshCh)
 		close(fwdCh)
-		d.wg.Wait() // パニックしないことを確認
+		d.wg.Wait()
+		if got := saves.Load(); got != 3 {
+			t.Errorf("saves = %d, want 3 (started, forward reconnecting and SSH reconnecting, not metrics)", got)
+		}
 	})
 }
 
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -12,9 +13,12 @@ import (
 
 var _ core.ForwardManager = (*mockForwardManagerForState)(nil)
 
+// デーモンが呼び出さないメソッドは nil の埋め込みで満たす（呼び出すと panic する）。
 type mockForwardManagerForState struct {
+	core.ForwardManager
 	mu                    sync.Mutex
 	sessions              map[string]*core.ForwardSession
+	rules                 []core.ForwardRule
 	startCalls            []string
 	startForwardFn        func(string, core.CredentialCallback) error
 	getAllSessionsFn      func() []core.ForwardSession
@@ -30,11 +34,11 @@ func (m *mockForwardManagerForState) AddRule(rule core.ForwardRule) (string, err
 
 func (m *mockForwardManagerForState) DeleteRule(string) error { return nil }
 
-func (m *mockForwardManagerForState) GetRules() []core.ForwardRule { return nil }
+func (m *mockForwardManagerForState) GetRules() []core.ForwardRule { return m.rules }
 
 func (m *mockForwardManagerForState) GetRulesByHost(string) []core.ForwardRule { return nil }
 
-func (m *mockForwardManagerForState) StartForward(ruleName string, cb core.CredentialCallback) error {
+func (m *mockForwardManagerForState) StartForward(_ context.Context, ruleName string, cb core.CredentialCallback) error {
 	m.mu.Lock()
 	m.startCalls = append(m.startCalls, ruleName)
 	fn := m.startForwardFn
@@ -63,12 +67,17 @@ func (m *mockForwardManagerForState) GetAllSessions() []core.ForwardSession {
 	return nil
 }
 
+func (m *mockForwardManagerForState) LoadStopHistory([]core.StopRecord)       {}
+func (m *mockForwardManagerForState) LoadSessionRecords([]core.SessionRecord) {}
+
 func (m *mockForwardManagerForState) MarkReconnecting(host string) {
 	m.mu.Lock()
 	m.markReconnectingCalls = append(m.markReconnectingCalls, host)
 	m.mu.Unlock()
 }
 
+func (m *mockForwardManagerForState) MarkAutoReconnecting(string) {}
+
 func (m *mockForwardManagerForState) RestoreForwards(host string) []core.ForwardRestoreResult {
 	if m.restoreForwardsFn != nil {
 		return m.restoreForwardsFn(host)
@@ -133,6 +142,7 @@ func newDaemonForStateTest(cfg *core.Config, fwdMgr core.ForwardManager) *Daemon
 	return &Daemon{
 		cfgMgr: &mockConfigManagerForState{config: cfg},
 		fwdMgr: fwdMgr,
+		broker: newBrokerStub(),
 	}
 }
 
@@ -146,19 +156,11 @@ func TestAutoStartForwards(t *testing.T) {
 	tests := []struct {
 		name           string
 		forwards       []core.ForwardRule
+		hostRules      []core.ForwardRule
 		sessions       map[string]*core.ForwardSession
 		startForwardFn func(string, core.CredentialCallback) error
 		wantCalls      []string
 	}{
-		{
-			name: "starts_auto_connect_rules_only",
-			forwards: []core.ForwardRule{
-				{Name: "web", Host: "myhost", Type: core.Local, LocalPort: 8080, RemotePort: 80, AutoConnect: true},
-				{Name: "db", Host: "myhost", Type: core.Local, LocalPort: 5432, RemotePort: 5432, AutoConnect: false},
-				{Name: "api", Host: "myhost", Type: core.Local, LocalPort: 3000, RemotePort: 3000, AutoConnect: true},
-			},
-			wantCalls: []string{"web", "api"},
-		},
 		{
 			name: "skips_already_active_rules",
 			forwards: []core.ForwardRule{
@@ -186,17 +188,16 @@ func TestAutoStartForwards(t *testing.T) {
 			wantCalls: []string{"web", "api", "metrics"},
 		},
 		{
-			name: "no_auto_connect_rules",
+			name: "includes_host_defined_rules",
 			forwards: []core.ForwardRule{
-				{Name: "web", Host: "myhost", Type: core.Local, LocalPort: 8080, RemotePort: 80, AutoConnect: false},
-				{Name: "db", Host: "myhost", Type: core.Local, LocalPort: 5432, RemotePort: 5432, AutoConnect: false},
+				{Name: "web", Host: "myhost", Type: core.Local, LocalPort: 8080, RemotePort: 80, AutoConnect: true},
 			},
-			wantCalls: []string{},
-		},
-		{
-			name:      "empty_forwards_list",
-			forwards:  []core.ForwardRule{},
-			wantCalls: []string{},
+			hostRules: []core.ForwardRule{
+				{Name: "web", Host: "myhost", Type: core.Local, LocalPort: 8080, RemotePort: 80, AutoConnect: true},
+				{Name: "myhost-local-5432", Host: "myhost", Type: core.Local, LocalPort: 5432, RemotePort: 5432, AutoConnect: true, HostDefined: true},
+				{Name: "myhost-dynamic-1080", Host: "myhost", Type: core.Dynamic, LocalPort: 1080, HostDefined: true},
+			},
+			wantCalls: []string{"web", "myhost-local-5432"},
 		},
 		{
 			name: "mixed_skip_and_fail_and_success",
@@ -224,6 +225,7 @@ func TestAutoStartForwards(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mock := &mockForwardManagerForState{
 				sessions:       tt.sessions,
+				rules:          tt.hostRules,
 				startForwardFn: tt.startForwardFn,
 			}
 			cfg := &core.Config{
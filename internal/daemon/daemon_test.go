@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
 // createTestConfigDir はテスト用の設定ディレクトリを作成し、最小限の SSH config を配置する。
@@ -108,6 +110,10 @@ func TestDaemon_StartStop(t *testing.T) {
 	if _, err := os.Stat(sockPath); !os.IsNotExist(err) {
 		t.Error("socket file still exists after Stop")
 	}
+	// 二重 Stop がパニックしないことを確認
+	if err := d.Stop(); err != nil {
+		t.Fatalf("second Stop() error: %v", err)
+	}
 }
 
 func TestDaemon_Status(t *testing.T) {
@@ -147,6 +153,14 @@ func TestDaemon_Status(t *testing.T) {
 	if status.ActiveForwards != 0 {
 		t.Errorf("ActiveForwards = %d, want 0", status.ActiveForwards)
 	}
+
+	// 起動直後は正常で、存在しないルールを指定すると fail になる
+	if h := d.Health(protocol.DefaultProfile, nil); h.Status != protocol.HealthOK || len(h.Checks) != 4 {
+		t.Errorf("Health() = %+v, want ok with 4 checks", h)
+	}
+	if h := d.Health(protocol.DefaultProfile, []string{"missing"}); h.Status != protocol.HealthFail {
+		t.Errorf("Health(missing) status = %q, want fail", h.Status)
+	}
 }
 
 func TestDaemon_Shutdown(t *testing.T) {
@@ -179,39 +193,11 @@ func TestDaemon_Shutdown(t *testing.T) {
 	d.Stop()
 }
 
-func TestDaemon_DoubleStop(t *testing.T) {
-	dir := createTestConfigDir(t)
-
-	d, err := New(dir, "test")
-	if err != nil {
-		t.Fatalf("New() error: %v", err)
-	}
-
-	if err := d.Start(context.Background()); err != nil {
-		t.Fatalf("Start() error: %v", err)
-	}
-
-	if err := d.Stop(); err != nil {
-		t.Fatalf("first Stop() error: %v", err)
-	}
-	// 二重 Stop がパニックしないことを確認
-	if err := d.Stop(); err != nil {
-		t.Fatalf("second Stop() error: %v", err)
-	}
-}
-
-func TestSocketPath(t *testing.T) {
-	got := SocketPath("/tmp/test")
-	want := "/tmp/test/moleport.sock"
-	if got != want {
-		t.Errorf("SocketPath() = %q, want %q", got, want)
+func TestSocketPathAndPIDFilePath(t *testing.T) {
+	if got := SocketPath("/tmp/test"); got != "/tmp/test/moleport.sock" {
+		t.Errorf("SocketPath() = %q, want %q", got, "/tmp/test/moleport.sock")
 	}
-}
-
-func TestPIDFilePath(t *testing.T) {
-	got := PIDFilePath("/tmp/test")
-	want := "/tmp/test/moleport.pid"
-	if got != want {
-		t.Errorf("PIDFilePath() = %q, want %q", got, want)
+	if got := PIDFilePath("/tmp/test"); got != "/tmp/test/moleport.pid" {
+		t.Errorf("PIDFilePath() = %q, want %q", got, "/tmp/test/moleport.pid")
 	}
 }
@@ -0,0 +1,2 @@
+// Package health は daemon.health で返すサブシステムごとの稼働状況を判定する。
+package health
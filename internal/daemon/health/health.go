@@ -0,0 +1,111 @@
+package health
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Input は稼働状況の判定に使うデーモンの状態。
+type Input struct {
+	// ConfigErr は config.yaml の直近の再読み込みで発生したエラー。成功している場合は nil。
+	ConfigErr  error
+	SocketPath string
+	Sessions   []core.ForwardSession
+	Hosts      []core.SSHHost
+	// Require は active であることを確認するルール名。
+	Require []string
+}
+
+// severity は状態の深刻度。Status には最も深刻な状態を設定する。
+var severity = map[string]int{protocol.HealthOK: 0, protocol.HealthWarn: 1, protocol.HealthFail: 2}
+
+// Check は in から各サブシステムの確認結果と全体の状態を求める。
+// config.yaml の再読み込みの失敗、エラー状態のフォワードと SSH 接続は warn、
+// ソケットの消失と Require のルールが active でないことは fail とする。
+func Check(in Input) protocol.DaemonHealthResult {
+	var result protocol.DaemonHealthResult
+	result.Checks = append(result.Checks, checkConfig(in.ConfigErr), checkSocket(in.SocketPath))
+
+	sessions := make(map[string]core.ForwardSession, len(in.Sessions))
+	for _, s := range in.Sessions {
+		sessions[s.Rule.Name] = s
+		switch s.Status {
+		case core.Active:
+			result.ActiveForwards++
+		case core.SessionError:
+			result.ErrorForwards++
+		}
+	}
+	forwards := protocol.HealthCheck{Name: "forwards", Status: protocol.HealthOK,
+		Message: fmt.Sprintf("%d active, %d errors", result.ActiveForwards, result.ErrorForwards)}
+	if result.ErrorForwards > 0 {
+		forwards.Status = protocol.HealthWarn
+	}
+	result.Checks = append(result.Checks, forwards, checkHosts(in.Hosts))
+	for _, name := range in.Require {
+		result.Checks = append(result.Checks, checkRequired(name, sessions))
+	}
+
+	result.Status = protocol.HealthOK
+	for _, c := range result.Checks {
+		if severity[c.Status] > severity[result.Status] {
+			result.Status = c.Status
+		}
+	}
+	return result
+}
+
+func checkConfig(err error) protocol.HealthCheck {
+	if err != nil {
+		return protocol.HealthCheck{Name: "config", Status: protocol.HealthWarn, Message: "reload failed, keeping previous settings: " + err.Error()}
+	}
+	return protocol.HealthCheck{Name: "config", Status: protocol.HealthOK, Message: "loaded"}
+}
+
+// checkSocket は IPC ソケットのファイルが残っているかを確認する。
+// 削除されていると、接続済みのクライアント以外はデーモンに接続できない。
+func checkSocket(path string) protocol.HealthCheck {
+	if _, err := os.Stat(path); err != nil {
+		return protocol.HealthCheck{Name: "socket", Status: protocol.HealthFail, Message: err.Error()}
+	}
+	return protocol.HealthCheck{Name: "socket", Status: protocol.HealthOK, Message: path}
+}
+
+func checkHosts(hosts []core.SSHHost) protocol.HealthCheck {
+	var connected, failed int
+	for _, h := range hosts {
+		switch h.State {
+		case core.Connected:
+			connected++
+		case core.ConnectionError:
+			failed++
+		}
+	}
+	c := protocol.HealthCheck{Name: "ssh", Status: protocol.HealthOK, Message: fmt.Sprintf("%d connected, %d errors", connected, failed)}
+	if failed > 0 {
+		c.Status = protocol.HealthWarn
+	}
+	return c
+}
+
+func checkRequired(name string, sessions map[string]core.ForwardSession) protocol.HealthCheck {
+	c := protocol.HealthCheck{Name: "forward:" + name, Status: protocol.HealthFail}
+	s, ok := sessions[name]
+	switch {
+	case !ok:
+		c.Message = "rule not found"
+	case s.Status != core.Active:
+		c.Message = strings.ToLower(s.Status.String())
+		if s.LastError != "" {
+			c.Message += ": " + s.LastError
+		}
+	default:
+		c.Status = protocol.HealthOK
+		c.Message = fmt.Sprintf("active on port %d", s.ListenPort())
+	}
+	return c
+}
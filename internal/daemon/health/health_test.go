@@ -0,0 +1,92 @@
+package health
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// socketFile はソケットの代わりになるファイルを作成し、そのパスを返す。
+func socketFile(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "moleport.sock")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// checkOf は result から name の確認結果を返す。
+func checkOf(t *testing.T, result protocol.DaemonHealthResult, name string) protocol.HealthCheck {
+	t.Helper()
+	for _, c := range result.Checks {
+		if c.Name == name {
+			return c
+		}
+	}
+	t.Fatalf("check %q not found in %+v", name, result.Checks)
+	return protocol.HealthCheck{}
+}
+
+func TestCheck_Healthy(t *testing.T) {
+	result := Check(Input{
+		SocketPath: socketFile(t),
+		Sessions: []core.ForwardSession{
+			{Rule: core.ForwardRule{Name: "web", LocalPort: 8080}, Status: core.Active},
+			{Rule: core.ForwardRule{Name: "db"}, Status: core.Stopped},
+		},
+		Hosts:   []core.SSHHost{{Name: "prod", State: core.Connected}},
+		Require: []string{"web"},
+	})
+
+	if result.Status != protocol.HealthOK || result.ActiveForwards != 1 || result.ErrorForwards != 0 {
+		t.Errorf("result = %+v, want ok with 1 active forward", result)
+	}
+	if c := checkOf(t, result, "forward:web"); c.Status != protocol.HealthOK || !strings.Contains(c.Message, "8080") {
+		t.Errorf("forward:web = %+v, want ok on port 8080", c)
+	}
+}
+
+func TestCheck_Degraded(t *testing.T) {
+	result := Check(Input{
+		ConfigErr:  errors.New("yaml: line 3"),
+		SocketPath: socketFile(t),
+		Sessions:   []core.ForwardSession{{Rule: core.ForwardRule{Name: "web"}, Status: core.SessionError}},
+		Hosts:      []core.SSHHost{{Name: "prod", State: core.ConnectionError}},
+	})
+
+	if result.Status != protocol.HealthWarn || result.ErrorForwards != 1 {
+		t.Errorf("result = %+v, want warn with 1 error forward", result)
+	}
+	for _, name := range []string{"config", "forwards", "ssh"} {
+		if c := checkOf(t, result, name); c.Status != protocol.HealthWarn {
+			t.Errorf("%s = %+v, want warn", name, c)
+		}
+	}
+}
+
+func TestCheck_Unhealthy(t *testing.T) {
+	result := Check(Input{
+		SocketPath: filepath.Join(t.TempDir(), "missing.sock"),
+		Sessions:   []core.ForwardSession{{Rule: core.ForwardRule{Name: "db"}, Status: core.SessionError, LastError: "dial failed"}},
+		Require:    []string{"db", "cache"},
+	})
+
+	if result.Status != protocol.HealthFail {
+		t.Errorf("Status = %q, want fail", result.Status)
+	}
+	if c := checkOf(t, result, "socket"); c.Status != protocol.HealthFail {
+		t.Errorf("socket = %+v, want fail", c)
+	}
+	if c := checkOf(t, result, "forward:db"); c.Status != protocol.HealthFail || c.Message != "error: dial failed" {
+		t.Errorf("forward:db = %+v, want fail with last error", c)
+	}
+	if c := checkOf(t, result, "forward:cache"); c.Status != protocol.HealthFail || c.Message != "rule not found" {
+		t.Errorf("forward:cache = %+v, want fail for unknown rule", c)
+	}
+}
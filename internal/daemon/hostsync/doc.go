@@ -0,0 +1,3 @@
+// Package hostsync はホストに定義されたフォワード（ssh_config のマジックコメントと config.yaml の hosts.<name>.forwards）を
+// ForwardManager のルールと同期し、起動時に自動開始するルールを選ぶ。
+package hostsync
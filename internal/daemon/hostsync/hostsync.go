@@ -0,0 +1,100 @@
+package hostsync
+
+import (
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/hostforward"
+)
+
+// Sync はホストに定義されたフォワード（ssh_config のマジックコメントと config.yaml の hosts.<name>.forwards）を
+// fwdMgr へ反映する。定義が追加されたルールは登録し、変更されたルールは書き換えて実行中だった場合は新しい内容で再開し、
+// 定義がなくなったルールは停止して削除する。同じ名前のルールが forwards 等で登録済みの場合はそちらを優先する。
+// ルール名は大文字と小文字を区別せずに比較する。
+func Sync(fwdMgr core.ForwardManager, hosts []core.SSHHost, hostCfgs map[string]core.HostConfig) {
+	current := make(map[string]core.ForwardRule)
+	var reserved []string
+	for _, r := range fwdMgr.GetRules() {
+		current[strings.ToLower(r.Name)] = r
+		if !r.HostDefined {
+			reserved = append(reserved, r.Name)
+		}
+	}
+
+	wanted := make(map[string]bool)
+	for _, rule := range definedRules(hosts, hostCfgs, reserved) {
+		key := strings.ToLower(rule.Name)
+		if wanted[key] {
+			slog.Warn("ignoring host forward with duplicate name", "rule", rule.Name, "host", rule.Host)
+			continue
+		}
+		wanted[key] = true
+		old, exists := current[key]
+		switch {
+		case !exists:
+			if _, err := fwdMgr.AddRule(rule); err != nil {
+				slog.Warn("failed to add host forward", "rule", rule.Name, "host", rule.Host, "error", err)
+			}
+		case !old.HostDefined:
+			slog.Warn("ignoring host forward: a rule with the same name exists", "rule", rule.Name, "host", rule.Host)
+		case !reflect.DeepEqual(old, rule):
+			// 実行中のセッションは新しい内容で再開する
+			if _, err := fwdMgr.UpdateRule(rule, nil); err != nil {
+				slog.Warn("failed to update host forward", "rule", rule.Name, "host", rule.Host, "error", err)
+			}
+		}
+	}
+	for key, r := range current {
+		if !r.HostDefined || wanted[key] {
+			continue
+		}
+		if err := fwdMgr.DeleteRule(r.Name); err != nil {
+			slog.Warn("failed to delete host forward", "rule", r.Name, "error", err)
+		}
+	}
+}
+
+// definedRules はホストに定義されたフォワードをホストの順に返す。ホストごとにマジックコメント、hosts.<name>.forwards の順に並べる。
+// config.yaml の書式の誤りは読み込み時に検証されるため、ここでは警告してスキップする。
+// hosts.<name>.forwards の名前を省略したフォワードには、reserved（forwards 等で登録済みのルール名）と
+// 先に並べたフォワードの名前に重複しない名前を付ける。
+func definedRules(hosts []core.SSHHost, hostCfgs map[string]core.HostConfig, reserved []string) []core.ForwardRule {
+	var rules []core.ForwardRule
+	names := slices.Clone(reserved)
+	for _, h := range hosts {
+		for _, rule := range h.DefaultForwards {
+			rules = append(rules, rule)
+			names = append(names, rule.Name)
+		}
+		for _, spec := range hostCfgs[h.Name].Forwards {
+			rule, err := hostforward.Parse(h.Name, spec, names)
+			if err != nil {
+				slog.Warn("ignoring host forward in config", "host", h.Name, "error", err)
+				continue
+			}
+			rules = append(rules, rule)
+			names = append(names, rule.Name)
+		}
+	}
+	return rules
+}
+
+// AutoConnect は config.yaml の forwards と登録済みのホストに定義されたルールのうち、auto_connect が有効なルール名を
+// forwards、ホストの定義の順に返す。
+func AutoConnect(forwards, registered []core.ForwardRule) []string {
+	var names []string
+	for _, rule := range forwards {
+		if rule.AutoConnect {
+			names = append(names, rule.Name)
+		}
+	}
+	for _, rule := range registered {
+		if rule.HostDefined && rule.AutoConnect {
+			names = append(names, rule.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,137 @@
+package hostsync
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forward"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+)
+
+func TestSync(t *testing.T) {
+	fm := forward.NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil)
+	if _, err := fm.AddRule(forwardtest.WebRule()); err != nil {
+		t.Fatal(err)
+	}
+	db := core.ForwardRule{Name: "db", Host: "prod", Type: core.Local, LocalPort: 5432, RemoteHost: "localhost", RemotePort: 5432, HostDefined: true}
+	hosts := []core.SSHHost{{Name: "prod", DefaultForwards: []core.ForwardRule{db}}}
+	hostCfgs := map[string]core.HostConfig{
+		"prod": {Forwards: []string{"D 1080", "L 8080:localhost:80 name=web"}},
+	}
+
+	Sync(fm, hosts, hostCfgs)
+	if got := ruleNames(fm); !slices.Equal(got, []string{"web", "db", "prod-dynamic-1080"}) {
+		t.Fatalf("rules = %v, want [web db prod-dynamic-1080]", got)
+	}
+	// forwards で登録済みの同名ルールは置き換えない
+	if web, _ := fm.GetSession("web"); web.Rule.HostDefined {
+		t.Error("web should keep the rule from forwards")
+	}
+
+	// 変更されたルールは書き換え、定義がなくなったルールは削除する
+	hosts[0].DefaultForwards[0].RemotePort = 5433
+	delete(hostCfgs, "prod")
+	Sync(fm, hosts, hostCfgs)
+	if got := ruleNames(fm); !slices.Equal(got, []string{"web", "db"}) {
+		t.Fatalf("rules = %v, want [web db]", got)
+	}
+	if s, err := fm.GetSession("db"); err != nil || s.Rule.RemotePort != 5433 {
+		t.Errorf("db = %+v, err = %v; want remote port 5433", s, err)
+	}
+
+	Sync(fm, nil, nil)
+	if got := ruleNames(fm); !slices.Equal(got, []string{"web"}) {
+		t.Errorf("rules = %v, want only web after the host is removed", got)
+	}
+}
+
+func TestSync_CaseInsensitiveNames(t *testing.T) {
+	fm := forward.NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil)
+	hosts := []core.SSHHost{{Name: "prod"}}
+	hostCfgs := map[string]core.HostConfig{
+		"prod": {Forwards: []string{"L 5432:localhost:5432 name=db", "L 5433:localhost:5433 name=DB"}},
+	}
+
+	// 大文字と小文字だけが異なる名前は重複として後の定義を無視する
+	Sync(fm, hosts, hostCfgs)
+	if got := ruleNames(fm); !slices.Equal(got, []string{"db"}) {
+		t.Fatalf("rules = %v, want [db]", got)
+	}
+
+	// 名前の大文字と小文字だけを変えた定義は、削除せずに同じルールとして扱う
+	hostCfgs["prod"] = core.HostConfig{Forwards: []string{"L 5432:localhost:5432 name=DB"}}
+	Sync(fm, hosts, hostCfgs)
+	if got := fm.GetRules(); len(got) != 1 || got[0].LocalPort != 5432 {
+		t.Errorf("rules = %+v, want the db rule kept", got)
+	}
+}
+
+func TestSync_GeneratedNamesAvoidForwards(t *testing.T) {
+	fm := forward.NewForwardManager(context.Background(), forwardtest.NewMockSSHManager(), nil, nil, nil)
+	if _, err := fm.AddRule(core.ForwardRule{Name: "prod-dynamic-1080", Host: "prod", Type: core.Dynamic, LocalPort: 1081}); err != nil {
+		t.Fatal(err)
+	}
+	hosts := []core.SSHHost{{Name: "prod"}}
+	hostCfgs := map[string]core.HostConfig{"prod": {Forwards: []string{"D 1080", "D 127.0.0.2:1080"}}}
+
+	// 名前は forwards 等のルールと先に定義したフォワードに重複せず、同期し直しても変わらない
+	want := []string{"prod-dynamic-1080", "prod-dynamic-1080-2", "prod-dynamic-1080-3"}
+	for range 2 {
+		Sync(fm, hosts, hostCfgs)
+		if got := ruleNames(fm); !slices.Equal(got, want) {
+			t.Fatalf("rules = %v, want %v", got, want)
+		}
+	}
+}
+
+func ruleNames(fm core.ForwardManager) []string {
+	var names []string
+	for _, r := range fm.GetRules() {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+func TestAutoConnect(t *testing.T) {
+	tests := []struct {
+		name       string
+		forwards   []core.ForwardRule
+		registered []core.ForwardRule
+		want       []string
+	}{
+		{
+			name: "auto_connect_rules_only",
+			forwards: []core.ForwardRule{
+				{Name: "web", AutoConnect: true},
+				{Name: "db", AutoConnect: false},
+				{Name: "api", AutoConnect: true},
+			},
+			want: []string{"web", "api"},
+		},
+		{
+			name: "includes_host_defined_rules",
+			forwards: []core.ForwardRule{
+				{Name: "web", AutoConnect: true},
+			},
+			registered: []core.ForwardRule{
+				{Name: "web", AutoConnect: true},
+				{Name: "myhost-local-5432", AutoConnect: true, HostDefined: true},
+				{Name: "myhost-dynamic-1080", HostDefined: true},
+			},
+			want: []string{"web", "myhost-local-5432"},
+		},
+		{
+			name:     "no_auto_connect_rules",
+			forwards: []core.ForwardRule{{Name: "web"}, {Name: "db"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := AutoConnect(tt.forwards, tt.registered); !slices.Equal(got, tt.want) {
+				t.Errorf("AutoConnect() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package liveconfig は稼働中のデーモンへ config.yaml と ssh_config の変更を反映する。
+package liveconfig
@@ -0,0 +1,23 @@
+package liveconfig
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// LogLevel はデーモンのログハンドラーが参照するログレベル。config.yaml の log.level の変更時に更新する。
+var LogLevel slog.LevelVar
+
+// ParseLevel は log.level の文字列を slog.Level に変換する。不明な値は info として扱う。
+func ParseLevel(s string) slog.Level {
+	switch strings.ToLower(s) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
@@ -0,0 +1,219 @@
+package liveconfig
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/daemon/hostsync"
+	"gopkg.in/yaml.v3"
+)
+
+// Notifier は設定の再読み込み結果をクライアントへ通知する。
+type Notifier interface {
+	NotifyHostsChanged(added, removed []string)
+	NotifyConfigChanged(sections []string)
+}
+
+// Applier は再読み込みした設定と前回反映した設定の差分を、稼働中のマネージャーへ反映する。
+type Applier struct {
+	cfgMgr   core.ConfigManager
+	sshMgr   core.SSHManager
+	fwdMgr   core.ForwardManager
+	notifier Notifier
+	level    *slog.LevelVar
+
+	mu      sync.Mutex
+	applied snapshot
+	// err は直近の config.yaml の再読み込みで発生したエラー。
+	err error
+}
+
+// snapshot は前回反映した設定。UpdateConfig による書き換えの影響を受けないよう YAML に変換して保持する。
+type snapshot struct {
+	// sections は config.yaml のトップレベルのキーごとの値。
+	sections map[string][]byte
+	// rules はフォワードルール名ごとの値。
+	rules map[string][]byte
+}
+
+// New は cfgMgr が現在保持する設定を反映済みとする Applier を生成する。
+// level が nil の場合はログレベルを変更しない（既定以外のプロファイルはプロセスのロガーを共有するため）。
+func New(cfgMgr core.ConfigManager, sshMgr core.SSHManager, fwdMgr core.ForwardManager, notifier Notifier, level *slog.LevelVar) *Applier {
+	return &Applier{
+		cfgMgr:   cfgMgr,
+		sshMgr:   sshMgr,
+		fwdMgr:   fwdMgr,
+		notifier: notifier,
+		level:    level,
+		applied:  takeSnapshot(cfgMgr.GetConfig()),
+	}
+}
+
+// ReloadHosts はホスト一覧を再読み込みしてホストに定義されたフォワードを反映し、追加・削除されたホストを通知する。
+func (a *Applier) ReloadHosts() {
+	before := a.sshMgr.GetHosts()
+	after, err := a.sshMgr.ReloadHosts()
+	if err != nil {
+		slog.Warn("failed to reload SSH hosts", "error", err)
+		return
+	}
+	a.SyncHostForwards()
+	added, removed := core.DiffHostNames(before, after)
+	slog.Info("hosts reloaded", "total", len(after), "added", added, "removed", removed)
+	a.notifier.NotifyHostsChanged(added, removed)
+}
+
+// SyncHostForwards は読み込み済みのホストと現在の設定から、ホストに定義されたフォワードを反映する。
+func (a *Applier) SyncHostForwards() {
+	hostsync.Sync(a.fwdMgr, a.sshMgr.GetHosts(), a.cfgMgr.GetConfig().Hosts)
+}
+
+// ReloadConfig は config.yaml を読み直し、変更されたセクションを反映して通知する。
+// 読み込みに失敗した場合（編集途中の構文エラー等）は警告のみ出し、前回反映した設定を維持する。
+// ssh_config_path と log.file の変更はデーモンの再起動後に反映される。
+func (a *Applier) ReloadConfig() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	cfg, err := a.cfgMgr.LoadConfig()
+	a.err = err
+	if err != nil {
+		slog.Warn("failed to reload config, keeping current settings", "error", err)
+		return
+	}
+	next := takeSnapshot(cfg)
+	sections := changedSections(a.applied, next)
+	if len(sections) == 0 {
+		return
+	}
+	changed := make(map[string]bool, len(sections))
+	for _, s := range sections {
+		changed[s] = true
+	}
+
+	if changed["reconnect"] || changed["hosts"] || changed["timeouts"] {
+		a.sshMgr.UpdateSettings(cfg.Reconnect, cfg.Hosts, cfg.Timeouts)
+	}
+	if changed["log"] && a.level != nil {
+		a.level.Set(ParseLevel(cfg.Log.Level))
+	}
+	if changed["forwards"] {
+		a.applyForwards(cfg.Forwards, next.rules)
+	}
+	if changed["host_definitions"] {
+		a.ReloadHosts()
+	} else if changed["hosts"] {
+		a.SyncHostForwards()
+	}
+	a.applied = next
+
+	slog.Info("config reloaded", "sections", sections)
+	a.notifier.NotifyConfigChanged(sections)
+}
+
+// Err は直近の config.yaml の再読み込みが失敗していればそのエラーを返す。
+func (a *Applier) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+// applyForwards はフォワードルールの差分を反映する。
+// 追加されたルールは登録し、変更されたルールは登録し直して、実行中だった場合は再開する。
+// config.yaml から削除されたルールは停止して削除する。
+// forward.add 等の RPC で既に反映済みのルールは変更しない。
+func (a *Applier) applyForwards(rules []core.ForwardRule, next map[string][]byte) {
+	current := make(map[string]bool)
+	for _, r := range a.fwdMgr.GetRules() {
+		current[r.Name] = true
+	}
+	for _, rule := range rules {
+		old, existed := a.applied.rules[rule.Name]
+		switch {
+		case rule.Name == "":
+			slog.Warn("ignoring forward rule without name until restart", "host", rule.Host, "local_port", rule.LocalPort)
+		case !current[rule.Name]:
+			if _, err := a.fwdMgr.AddRule(rule); err != nil {
+				slog.Warn("failed to add forward rule", "rule", rule.Name, "error", err)
+			}
+		case existed && !bytes.Equal(old, next[rule.Name]):
+			a.replaceRule(rule)
+		}
+	}
+	for name := range a.applied.rules {
+		if _, ok := next[name]; ok || !current[name] {
+			continue
+		}
+		if err := a.fwdMgr.DeleteRule(name); err != nil {
+			slog.Warn("failed to delete forward rule", "rule", name, "error", err)
+		}
+	}
+}
+
+// replaceRule はルールを削除して登録し直し、実行中だった場合は新しい設定で再開する。
+func (a *Applier) replaceRule(rule core.ForwardRule) {
+	session, err := a.fwdMgr.GetSession(rule.Name)
+	running := err == nil && session.Status != core.Stopped && session.Status != core.SessionError
+	if err := a.fwdMgr.DeleteRule(rule.Name); err != nil {
+		slog.Warn("failed to replace forward rule", "rule", rule.Name, "error", err)
+		return
+	}
+	if _, err := a.fwdMgr.AddRule(rule); err != nil {
+		slog.Warn("failed to replace forward rule", "rule", rule.Name, "error", err)
+		return
+	}
+	if !running {
+		return
+	}
+	if err := a.fwdMgr.StartForward(context.Background(), rule.Name, nil); err != nil {
+		slog.Warn("failed to restart forward after config change", "rule", rule.Name, "error", err)
+	}
+}
+
+// takeSnapshot は cfg をトップレベルのキーごと、フォワードルールごとに YAML へ変換する。
+func takeSnapshot(cfg *core.Config) snapshot {
+	s := snapshot{sections: make(map[string][]byte), rules: make(map[string][]byte)}
+	v := reflect.ValueOf(*cfg)
+	for i := range v.NumField() {
+		s.sections[sectionName(v.Type().Field(i))] = marshal(v.Field(i).Interface())
+	}
+	for _, r := range cfg.Forwards {
+		if r.Name != "" {
+			s.rules[r.Name] = marshal(r)
+		}
+	}
+	return s
+}
+
+// changedSections は値が変わったトップレベルのキーを core.Config のフィールド順に返す。
+func changedSections(before, after snapshot) []string {
+	var sections []string
+	t := reflect.TypeFor[core.Config]()
+	for i := range t.NumField() {
+		name := sectionName(t.Field(i))
+		if !bytes.Equal(before.sections[name], after.sections[name]) {
+			sections = append(sections, name)
+		}
+	}
+	return sections
+}
+
+// sectionName はフィールドの YAML キー名を返す。
+func sectionName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+	return name
+}
+
+// marshal は v を YAML に変換する。nil と空のスライス・マップは同じ値になる。
+func marshal(v any) []byte {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	return data
+}
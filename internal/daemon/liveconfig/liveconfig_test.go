@@ -0,0 +1,162 @@
+package liveconfig
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/config"
+	"github.com/ousiassllc/moleport/internal/core/forward"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+	"github.com/ousiassllc/moleport/internal/infra/yamlstore"
+)
+
+// recordingSSHManager は UpdateSettings に渡された再接続設定を記録する。
+type recordingSSHManager struct {
+	*forwardtest.MockSSHManager
+	reconnect []core.ReconnectConfig
+}
+
+func (m *recordingSSHManager) UpdateSettings(rc core.ReconnectConfig, _ map[string]core.HostConfig, _ core.TimeoutConfig) {
+	m.reconnect = append(m.reconnect, rc)
+}
+
+// recordingNotifier は通知されたセクションを記録する。
+type recordingNotifier struct {
+	sections [][]string
+	hosts    int
+}
+
+func (n *recordingNotifier) NotifyHostsChanged(_, _ []string) { n.hosts++ }
+func (n *recordingNotifier) NotifyConfigChanged(sections []string) {
+	n.sections = append(n.sections, sections)
+}
+
+func TestApplier_ReloadConfig(t *testing.T) {
+	dir := t.TempDir()
+	cfgMgr := config.NewConfigManager(yamlstore.NewYAMLStore(), dir)
+	cfg := core.DefaultConfig()
+	web := forwardtest.WebRule()
+	db := core.ForwardRule{Name: "db", Host: "server1", Type: core.Local, LocalPort: 5432, RemoteHost: "localhost", RemotePort: 5432}
+	cfg.Forwards = []core.ForwardRule{web, db}
+	if err := cfgMgr.SaveConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cfgMgr.LoadConfig(); err != nil {
+		t.Fatal(err)
+	}
+
+	sm := &recordingSSHManager{MockSSHManager: forwardtest.NewMockSSHManager()}
+	sm.SetConnected("server1", forwardtest.NewMockConn(true, false))
+	fm := forward.NewForwardManager(context.Background(), sm, nil, nil, nil)
+	for _, r := range cfg.Forwards {
+		if _, err := fm.AddRule(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fm.StartForward(t.Context(), "web", nil); err != nil {
+		t.Fatal(err)
+	}
+	notifier := &recordingNotifier{}
+	var level slog.LevelVar
+	a := New(cfgMgr, sm, fm, notifier, &level)
+
+	// 変更がなければ何も通知しない
+	a.ReloadConfig()
+	if len(notifier.sections) != 0 {
+		t.Fatalf("sections = %v, want no notification for unchanged config", notifier.sections)
+	}
+
+	edited := core.DefaultConfig()
+	edited.Reconnect.MaxRetries = 3
+	edited.Log.Level = "debug"
+	web.RemotePort = 8000
+	cache := core.ForwardRule{Name: "cache", Host: "server1", Type: core.Local, LocalPort: 6379, RemoteHost: "localhost", RemotePort: 6379}
+	edited.Forwards = []core.ForwardRule{web, cache}
+	if err := cfgMgr.SaveConfig(&edited); err != nil {
+		t.Fatal(err)
+	}
+	a.ReloadConfig()
+
+	if len(notifier.sections) != 1 || !slices.Equal(notifier.sections[0], []string{"reconnect", "log", "forwards"}) {
+		t.Fatalf("sections = %v, want [reconnect log forwards]", notifier.sections)
+	}
+	if len(sm.reconnect) != 1 || sm.reconnect[0].MaxRetries != 3 {
+		t.Errorf("UpdateSettings calls = %+v, want MaxRetries 3", sm.reconnect)
+	}
+	if level.Level() != slog.LevelDebug {
+		t.Errorf("log level = %v, want debug", level.Level())
+	}
+	var names []string
+	for _, r := range fm.GetRules() {
+		names = append(names, r.Name)
+	}
+	if !slices.Equal(names, []string{"web", "cache"}) {
+		t.Errorf("rules = %v, want [web cache]", names)
+	}
+	// 変更されたルールは新しい設定で再開される
+	s, err := fm.GetSession("web")
+	if err != nil || s.Status != core.Active || s.Rule.RemotePort != 8000 {
+		t.Errorf("web session = %+v, err = %v; want active with remote port 8000", s, err)
+	}
+
+	// 構文エラーの設定は反映せず、前回の設定を維持する
+	if err := os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("log: [\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	a.ReloadConfig()
+	if len(notifier.sections) != 1 || cfgMgr.GetConfig().Log.Level != "debug" {
+		t.Errorf("invalid config should be ignored: sections = %v, level = %q", notifier.sections, cfgMgr.GetConfig().Log.Level)
+	}
+	if a.Err() == nil {
+		t.Error("Err() = nil, want the reload error")
+	}
+}
+
+func TestApplier_ReloadHostDefinitions(t *testing.T) {
+	cfgMgr := config.NewConfigManager(yamlstore.NewYAMLStore(), t.TempDir())
+	sm := &recordingSSHManager{MockSSHManager: forwardtest.NewMockSSHManager()}
+	notifier := &recordingNotifier{}
+	a := New(cfgMgr, sm, forward.NewForwardManager(context.Background(), sm, nil, nil, nil), notifier, nil)
+
+	cfg := core.DefaultConfig()
+	cfg.HostDefinitions = []core.HostDefinition{{Name: "box", HostName: "box.example.com"}}
+	if err := cfgMgr.SaveConfig(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	a.ReloadConfig()
+
+	if notifier.hosts != 1 || len(notifier.sections) != 1 || !slices.Equal(notifier.sections[0], []string{"host_definitions"}) {
+		t.Errorf("hosts notifications = %d, sections = %v", notifier.hosts, notifier.sections)
+	}
+	if len(sm.reconnect) != 0 {
+		t.Error("UpdateSettings should not be called when only host_definitions changed")
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input string
+		want  slog.Level
+	}{
+		{"debug", slog.LevelDebug},
+		{"DEBUG", slog.LevelDebug},
+		{"info", slog.LevelInfo},
+		{"INFO", slog.LevelInfo},
+		{"warn", slog.LevelWarn},
+		{"warning", slog.LevelWarn},
+		{"error", slog.LevelError},
+		{"ERROR", slog.LevelError},
+		{"unknown", slog.LevelInfo},
+		{"", slog.LevelInfo},
+	}
+	for _, tt := range tests {
+		if got := ParseLevel(tt.input); got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package logbuf はデーモンの直近のログを保持し、logs.subscribe の購読者に配信する。
+package logbuf
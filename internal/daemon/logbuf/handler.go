@@ -0,0 +1,86 @@
+package logbuf
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Wrap は next でログを出力し、同じログを b にも記録する slog.Handler を返す。
+// 記録するかどうかは next の Enabled に従うため、log.level より詳細なログは記録しない。
+func (b *Buffer) Wrap(next slog.Handler) slog.Handler {
+	return &handler{next: next, buf: b}
+}
+
+// attr は WithAttrs で追加された属性。key にはグループ名のプレフィックスを含む。
+type attr struct {
+	key, value string
+}
+
+type handler struct {
+	next   slog.Handler
+	buf    *Buffer
+	attrs  []attr
+	prefix string // WithGroup で指定されたグループ名（"a.b." の形式）
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.next.Handle(ctx, r)
+
+	rec := protocol.LogRecord{Time: r.Time, Level: r.Level.String(), Message: r.Message}
+	if len(h.attrs) > 0 || r.NumAttrs() > 0 {
+		rec.Attrs = make(map[string]string, len(h.attrs)+r.NumAttrs())
+		for _, a := range h.attrs {
+			rec.Attrs[a.key] = a.value
+		}
+		r.Attrs(func(a slog.Attr) bool {
+			flatten(h.prefix, a, func(key, value string) { rec.Attrs[key] = value })
+			return true
+		})
+	}
+	h.buf.add(r.Level, rec)
+	return err
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	c := *h
+	c.next = h.next.WithAttrs(attrs)
+	c.attrs = append([]attr(nil), h.attrs...)
+	for _, a := range attrs {
+		flatten(h.prefix, a, func(key, value string) { c.attrs = append(c.attrs, attr{key, value}) })
+	}
+	return &c
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	c := *h
+	c.next = h.next.WithGroup(name)
+	c.prefix = h.prefix + name + "."
+	return &c
+}
+
+// flatten は a を "group.key" 形式のキーと文字列の値に展開して add に渡す。
+func flatten(prefix string, a slog.Attr, add func(key, value string)) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() != slog.KindGroup {
+		add(prefix+a.Key, a.Value.String())
+		return
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range a.Value.Group() {
+		flatten(prefix, ga, add)
+	}
+}
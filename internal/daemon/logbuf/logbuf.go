@@ -0,0 +1,119 @@
+package logbuf
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// DefaultSize は Buffer が保持するログの既定の件数。
+const DefaultSize = 1000
+
+// queueSize は購読者ごとに送信を待つログの上限。送信が追いつかない場合、超えた分は破棄する。
+const queueSize = 256
+
+// entry はリングバッファに保持するログ 1 件。
+type entry struct {
+	level  slog.Level
+	record protocol.LogRecord
+}
+
+// subscriber は新しいログの購読者。ログは送信用の goroutine から順に送信する。
+type subscriber struct {
+	min  slog.Level
+	ch   chan protocol.LogRecord
+	done chan struct{}
+	once sync.Once
+}
+
+// Buffer は直近のログを一定件数保持するリングバッファ。Wrap で slog.Handler に組み込む。
+type Buffer struct {
+	mu      sync.Mutex
+	entries []entry
+	next    int
+	full    bool
+	subs    map[*subscriber]struct{}
+}
+
+// New は size 件のログを保持する Buffer を生成する。size が 0 以下の場合は DefaultSize を使う。
+func New(size int) *Buffer {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Buffer{entries: make([]entry, size), subs: make(map[*subscriber]struct{})}
+}
+
+// Subscribe は minLevel 以上のレベルの直近のログを最大 tail 件、古い順に返し、以降のログを send で送信する購読を登録する。
+// 直近のログの取得と購読の登録は同時に行うため、その間のログが欠けたり重複したりしない。
+// send はログを記録した goroutine とは別の goroutine から呼ばれるため、send の中でログを出力してもよい。
+// send がエラーを返した場合（クライアントの切断など）は購読を解除する。
+func (b *Buffer) Subscribe(minLevel slog.Level, tail int, send func(protocol.LogRecord) error) (recent []protocol.LogRecord, cancel func()) {
+	sub := &subscriber{min: minLevel, ch: make(chan protocol.LogRecord, queueSize), done: make(chan struct{})}
+	b.mu.Lock()
+	recent = b.recent(tail, minLevel)
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	cancel = func() {
+		b.mu.Lock()
+		delete(b.subs, sub)
+		b.mu.Unlock()
+		sub.once.Do(func() { close(sub.done) })
+	}
+	go func() {
+		for {
+			select {
+			case rec := <-sub.ch:
+				if err := send(rec); err != nil {
+					cancel()
+					return
+				}
+			case <-sub.done:
+				return
+			}
+		}
+	}()
+	return recent, cancel
+}
+
+// recent は minLevel 以上のレベルの直近のログを最大 n 件、古い順に返す。b.mu を保持して呼び出す。
+func (b *Buffer) recent(n int, minLevel slog.Level) []protocol.LogRecord {
+	ordered := b.entries[:b.next]
+	if b.full {
+		ordered = append(append([]entry{}, b.entries[b.next:]...), b.entries[:b.next]...)
+	}
+	var records []protocol.LogRecord
+	for i := len(ordered) - 1; i >= 0 && len(records) < n; i-- {
+		if ordered[i].level >= minLevel {
+			records = append(records, ordered[i].record)
+		}
+	}
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	return records
+}
+
+// add はログを記録し、購読者に配信する。
+func (b *Buffer) add(level slog.Level, rec protocol.LogRecord) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.entries[b.next] = entry{level: level, record: rec}
+	b.next++
+	if b.next == len(b.entries) {
+		b.next = 0
+		b.full = true
+	}
+	for sub := range b.subs {
+		if level < sub.min {
+			continue
+		}
+		select {
+		case sub.ch <- rec:
+		default:
+			// 送信が追いつかない購読者の分は破棄する
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package logbuf
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func newLogger(b *Buffer) *slog.Logger {
+	return slog.New(b.Wrap(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug})))
+}
+
+func messages(records []protocol.LogRecord) []string {
+	var msgs []string
+	for _, r := range records {
+		msgs = append(msgs, r.Message)
+	}
+	return msgs
+}
+
+// recent は購読を登録せずに直近のログを返す。
+func recent(b *Buffer, n int, minLevel slog.Level) []protocol.LogRecord {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.recent(n, minLevel)
+}
+
+func TestBuffer_Recent(t *testing.T) {
+	b := New(3)
+	logger := newLogger(b)
+	logger.Info("one")
+	logger.Debug("two")
+	logger.Warn("three")
+	logger.Info("four")
+
+	tests := []struct {
+		name     string
+		n        int
+		minLevel slog.Level
+		want     []string
+	}{
+		{"oldest entry is overwritten", 10, slog.LevelDebug, []string{"two", "three", "four"}},
+		{"level filter", 10, slog.LevelInfo, []string{"three", "four"}},
+		{"last n", 1, slog.LevelDebug, []string{"four"}},
+		{"none", 0, slog.LevelDebug, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := messages(recent(b, tt.n, tt.minLevel))
+			if len(got) != len(tt.want) {
+				t.Fatalf("recent() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("recent() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuffer_Attrs(t *testing.T) {
+	b := New(10)
+	newLogger(b).With("profile", "work").WithGroup("ssh").Info("connected", "host", "bastion", slog.Group("retry", "count", 2))
+
+	got := recent(b, 1, slog.LevelDebug)
+	if len(got) != 1 {
+		t.Fatalf("recent() = %v, want 1 record", got)
+	}
+	want := map[string]string{"profile": "work", "ssh.host": "bastion", "ssh.retry.count": "2"}
+	for k, v := range want {
+		if got[0].Attrs[k] != v {
+			t.Errorf("Attrs[%q] = %q, want %q (attrs = %v)", k, got[0].Attrs[k], v, got[0].Attrs)
+		}
+	}
+	if got[0].Level != "INFO" {
+		t.Errorf("Level = %q, want INFO", got[0].Level)
+	}
+}
+
+func TestBuffer_Subscribe(t *testing.T) {
+	b := New(10)
+	logger := newLogger(b)
+	logger.Warn("before")
+	received := make(chan protocol.LogRecord, 10)
+	tail, cancel := b.Subscribe(slog.LevelWarn, 10, func(r protocol.LogRecord) error {
+		received <- r
+		return nil
+	})
+	if got := messages(tail); len(got) != 1 || got[0] != "before" {
+		t.Errorf("Subscribe() recent = %v, want [before]", got)
+	}
+
+	logger.Info("ignored")
+	logger.Error("failed")
+	select {
+	case r := <-received:
+		if r.Message != "failed" {
+			t.Errorf("received %q, want %q", r.Message, "failed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for log record")
+	}
+
+	cancel()
+	logger.Error("after cancel")
+	select {
+	case r := <-received:
+		t.Errorf("received %q after cancel", r.Message)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestBuffer_SubscribeSendError(t *testing.T) {
+	b := New(10)
+	logger := newLogger(b)
+	sent := make(chan struct{}, 10)
+	b.Subscribe(slog.LevelDebug, 0, func(protocol.LogRecord) error {
+		sent <- struct{}{}
+		return errors.New("client gone")
+	})
+
+	logger.Info("first")
+	<-sent
+	deadline := time.Now().Add(time.Second)
+	for {
+		b.mu.Lock()
+		n := len(b.subs)
+		b.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("subscription should be removed after a send error")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
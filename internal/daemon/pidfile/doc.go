@@ -0,0 +1,2 @@
+// Package pidfile は flock による排他付きのデーモン PID ファイル管理を提供する。
+package pidfile
@@ -0,0 +1,33 @@
+//go:build !windows
+
+package pidfile
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// lock は f に flock で排他ロックを取得する。取得できない場合は待たずにエラーを返す。
+func lock(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB) //nolint:gosec // Fd() は有効な fd を返す
+}
+
+// release は PID ファイルを削除してからロックを解放し、ファイルを閉じる。
+// 先に削除することで、ロックの解放後に別のプロセスが作成した PID ファイルを消さない。
+func release(f *os.File, path string) error {
+	removeErr := os.Remove(path)
+	flockErr := syscall.Flock(int(f.Fd()), syscall.LOCK_UN) //nolint:gosec // Fd() は有効な fd を返す
+	closeErr := f.Close()
+	return errors.Join(removeErr, flockErr, closeErr)
+}
+
+// kill は pid のプロセスを SIGKILL で強制終了する。
+func kill(pid int) error {
+	return syscall.Kill(pid, syscall.SIGKILL)
+}
+
+// alive は pid のプロセスが存在するかを返す。
+func alive(pid int) bool {
+	return syscall.Kill(pid, 0) == nil
+}
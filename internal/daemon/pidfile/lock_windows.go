@@ -0,0 +1,51 @@
+package pidfile
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive は GetExitCodeProcess が実行中のプロセスに返す終了コード（STILL_ACTIVE）。
+const stillActive = 259
+
+// lockRange は PID ファイルの内容と重ならない、ロックするバイト範囲の位置。
+// Windows のファイルロックは他のプロセスの読み取りも妨げるため、IsRunning が PID を読めるよう内容の外側をロックする。
+var lockRange = windows.Overlapped{OffsetHigh: 0x7fffffff}
+
+// lock は f に LockFileEx で排他ロックを取得する。取得できない場合は待たずにエラーを返す。
+func lock(f *os.File) error {
+	ol := lockRange
+	return windows.LockFileEx(windows.Handle(f.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK|windows.LOCKFILE_FAIL_IMMEDIATELY, 0, 1, 0, &ol)
+}
+
+// release はロックを解放してファイルを閉じてから PID ファイルを削除する。Windows では開いているファイルを削除できない。
+func release(f *os.File, path string) error {
+	ol := lockRange
+	unlockErr := windows.UnlockFileEx(windows.Handle(f.Fd()), 0, 1, 0, &ol)
+	closeErr := f.Close()
+	removeErr := os.Remove(path)
+	return errors.Join(unlockErr, closeErr, removeErr)
+}
+
+// kill は pid のプロセスを TerminateProcess で強制終了する。
+func kill(pid int) error {
+	h, err := windows.OpenProcess(windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+	return windows.TerminateProcess(h, 1)
+}
+
+// alive は pid のプロセスが実行中かを返す。
+func alive(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer func() { _ = windows.CloseHandle(h) }()
+	var code uint32
+	return windows.GetExitCodeProcess(h, &code) == nil && code == stillActive
+}
@@ -1,39 +1,43 @@
-package daemon
+package pidfile
 
 import (
-	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
-	"syscall"
 )
 
-// PIDFile はデーモンの PID ファイルを管理する。
-// flock によるプロセス排他を提供する。
-type PIDFile struct {
-	path string
-	file *os.File
+// File はデーモンの PID ファイルを管理する。
+// ファイルロック（Unix は flock、Windows は LockFileEx）によるプロセス排他を提供する。
+type File struct {
+	path  string
+	file  *os.File
+	stale bool
 }
 
-// NewPIDFile は指定パスの PIDFile を生成する。
-func NewPIDFile(path string) *PIDFile {
-	return &PIDFile{path: path}
+// New は指定パスの File を生成する。
+func New(path string) *File {
+	return &File{path: path}
 }
 
-// Acquire は PID ファイルを作成し、flock で排他ロックを取得する。
+// Acquire は PID ファイルを作成し、排他ロックを取得する。
 // ロック取得に失敗した場合（デーモンが既に起動中）はエラーを返す。
-func (p *PIDFile) Acquire() error {
+func (p *File) Acquire() error {
 	f, err := os.OpenFile(p.path, os.O_CREATE|os.O_RDWR, 0600)
 	if err != nil {
 		return fmt.Errorf("open pid file: %w", err)
 	}
 
-	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+	if err := lock(f); err != nil {
 		f.Close()
 		return fmt.Errorf("daemon already running (lock failed): %w", err)
 	}
 
+	// ロックを取得できた時点で前回のプロセスは終了している。PID が残っていれば Release されずに終了した
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		p.stale = true
+	}
+
 	if err := f.Truncate(0); err != nil {
 		f.Close()
 		return fmt.Errorf("truncate pid file: %w", err)
@@ -50,21 +54,24 @@ func (p *PIDFile) Acquire() error {
 	return nil
 }
 
+// Stale は Acquire 時に前回のプロセスが PID ファイルを削除せずに終了していたか（クラッシュ等の異常終了）を返す。
+func (p *File) Stale() bool {
+	return p.stale
+}
+
 // Release は PID ファイルを削除し、ロックを解放してファイルを閉じる。
 // 複数回呼び出しても安全（冪等）。
-func (p *PIDFile) Release() error {
+func (p *File) Release() error {
 	if p.file == nil {
 		return nil
 	}
 
-	removeErr := os.Remove(p.path)
-	flockErr := syscall.Flock(int(p.file.Fd()), syscall.LOCK_UN) //nolint:gosec // Fd() は有効な fd を返す
-	closeErr := p.file.Close()
+	err := release(p.file, p.path)
 	p.file = nil
-	return errors.Join(removeErr, flockErr, closeErr)
+	return err
 }
 
-// KillProcess は PID ファイルから PID を読み取り、SIGKILL で強制終了し、PID ファイルを削除する。
+// KillProcess は PID ファイルから PID を読み取り、強制終了（Unix は SIGKILL）し、PID ファイルを削除する。
 // PID ファイルが存在しない場合やプロセスが既に終了している場合もエラーを返す。
 func KillProcess(pidPath string) error {
 	data, err := os.ReadFile(pidPath) //nolint:gosec // pidPath は内部で生成された PID ファイルパス
@@ -79,7 +86,7 @@ func KillProcess(pidPath string) error {
 		return fmt.Errorf("invalid pid file content: %q", pidStr)
 	}
 
-	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil {
+	if err := kill(pid); err != nil {
 		_ = os.Remove(pidPath)
 		return fmt.Errorf("kill process %d: %w", pid, err)
 	}
@@ -93,8 +100,8 @@ func KillProcess(pidPath string) error {
 
 // IsRunning は PID ファイルを読み取り、対応するプロセスが実行中かを返す。
 // ファイルが存在しない、内容が不正、またはプロセスが存在しない場合は (false, 0) を返す。
-// 注意: Kill(pid, 0) はプロセスの存在のみを確認する。PID 再利用により偽陽性の可能性があるが、
-// Acquire() の flock が実際の排他制御を保証する。
+// 注意: プロセスの存在のみを確認する。PID 再利用により偽陽性の可能性があるが、
+// Acquire() のファイルロックが実際の排他制御を保証する。
 func IsRunning(path string) (bool, int) {
 	data, err := os.ReadFile(path)
 	if err != nil {
@@ -107,7 +114,7 @@ func IsRunning(path string) (bool, int) {
 		return false, 0
 	}
 
-	if err := syscall.Kill(pid, 0); err != nil {
+	if !alive(pid) {
 		return false, 0
 	}
 
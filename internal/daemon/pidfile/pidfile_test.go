@@ -1,4 +1,4 @@
-package daemon
+package pidfile
 
 import (
 	"fmt"
@@ -9,9 +9,9 @@ import (
 	"testing"
 )
 
-func TestPIDFile_AcquireRelease_Lifecycle(t *testing.T) {
+func TestFile_AcquireRelease_Lifecycle(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.pid")
-	pf := NewPIDFile(path)
+	pf := New(path)
 
 	if err := pf.Acquire(); err != nil {
 		t.Fatalf("Acquire: %v", err)
@@ -32,25 +32,25 @@ func TestPIDFile_AcquireRelease_Lifecycle(t *testing.T) {
 	}
 }
 
-func TestPIDFile_DoubleAcquire_Fails(t *testing.T) {
+func TestFile_DoubleAcquire_Fails(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.pid")
 
-	pf1 := NewPIDFile(path)
+	pf1 := New(path)
 	if err := pf1.Acquire(); err != nil {
 		t.Fatalf("First Acquire: %v", err)
 	}
 	defer pf1.Release()
 
-	pf2 := NewPIDFile(path)
+	pf2 := New(path)
 	if err := pf2.Acquire(); err == nil {
 		pf2.Release()
 		t.Fatal("Second Acquire should fail due to flock contention")
 	}
 }
 
-func TestPIDFile_Release_Idempotent(t *testing.T) {
+func TestFile_Release_Idempotent(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.pid")
-	pf := NewPIDFile(path)
+	pf := New(path)
 
 	if err := pf.Acquire(); err != nil {
 		t.Fatalf("Acquire: %v", err)
@@ -66,10 +66,10 @@ func TestPIDFile_Release_Idempotent(t *testing.T) {
 	}
 }
 
-func TestPIDFile_AcquireAfterRelease(t *testing.T) {
+func TestFile_AcquireAfterRelease(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.pid")
 
-	pf1 := NewPIDFile(path)
+	pf1 := New(path)
 	if err := pf1.Acquire(); err != nil {
 		t.Fatalf("First Acquire: %v", err)
 	}
@@ -77,16 +77,35 @@ func TestPIDFile_AcquireAfterRelease(t *testing.T) {
 		t.Fatalf("Release: %v", err)
 	}
 
-	pf2 := NewPIDFile(path)
+	pf2 := New(path)
 	if err := pf2.Acquire(); err != nil {
 		t.Fatalf("Acquire after Release should succeed: %v", err)
 	}
 	defer pf2.Release()
+	if pf2.Stale() {
+		t.Error("Stale() should be false after a clean Release")
+	}
+}
+
+func TestFile_Acquire_DetectsStalePIDFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.pid")
+	// クラッシュしたプロセスが残した PID ファイルを模倣する
+	if err := os.WriteFile(path, []byte("99999999\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	pf := New(path)
+	if err := pf.Acquire(); err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	defer pf.Release()
+	if !pf.Stale() {
+		t.Error("Stale() should be true when a previous PID remained")
+	}
 }
 
-func TestPIDFile_FilePermissions(t *testing.T) {
+func TestFile_FilePermissions(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.pid")
-	pf := NewPIDFile(path)
+	pf := New(path)
 
 	if err := pf.Acquire(); err != nil {
 		t.Fatalf("Acquire: %v", err)
@@ -104,9 +123,9 @@ func TestPIDFile_FilePermissions(t *testing.T) {
 	}
 }
 
-func TestPIDFile_FileContent_MatchesPID(t *testing.T) {
+func TestFile_FileContent_MatchesPID(t *testing.T) {
 	path := filepath.Join(t.TempDir(), "test.pid")
-	pf := NewPIDFile(path)
+	pf := New(path)
 
 	if err := pf.Acquire(); err != nil {
 		t.Fatalf("Acquire: %v", err)
@@ -0,0 +1,140 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/daemon/profiledir"
+	"github.com/ousiassllc/moleport/internal/daemon/sharedsvc"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// errDaemonStopping はデーモン停止中にプロファイルを起動しようとした場合のエラー。
+var errDaemonStopping = errors.New("daemon is stopping")
+
+// errProfileNotFound は存在しないプロファイルに状態を変更しないメソッドを呼び出した場合のエラー。
+var errProfileNotFound = errors.New("profile not found")
+
+// route は params の profile に応じてリクエストを各プロファイルのハンドラーに振り分ける。
+// 未起動のプロファイルは最初のリクエスト時に起動する。設定ディレクトリが存在しないプロファイルは
+// 状態を変更するメソッド（管理者のクライアントのみ呼び出せる）でだけ作成し、それ以外はエラーにする。
+func (d *Daemon) route(ctx context.Context, clientID string, method string, params json.RawMessage) (any, *protocol.RPCError) {
+	if method == protocol.MethodProfileList {
+		return d.listProfiles(), nil
+	}
+	// ログはプロセス全体で共有するため、logs.* はプロファイルにかかわらず既定のハンドラーが処理する
+	name := protocol.ProfileOf(params)
+	if name == protocol.DefaultProfile || strings.HasPrefix(method, "logs.") {
+		return d.handler.Handle(ctx, clientID, method, params)
+	}
+	p, err := d.profile(name, !protocol.IsReadOnlyMethod(method))
+	if err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
+	return p.handler.Handle(ctx, clientID, method, params)
+}
+
+// profile は name のプロファイルのランタイムを返す。未起動の場合は起動する。
+// 設定ディレクトリが存在しない場合、create が true なら作成し、false なら errProfileNotFound を返す。
+func (d *Daemon) profile(name string, create bool) (*Daemon, error) {
+	if err := profiledir.ValidateName(name); err != nil {
+		return nil, err
+	}
+
+	d.profileMu.Lock()
+	defer d.profileMu.Unlock()
+	if d.profiles == nil {
+		return nil, errDaemonStopping
+	}
+	if p, ok := d.profiles[name]; ok {
+		return p, nil
+	}
+	dir := profiledir.Dir(d.configDir, name)
+	if _, err := os.Stat(dir); err != nil && !create {
+		return nil, fmt.Errorf("%w: %s", errProfileNotFound, name)
+	}
+
+	p, err := newRuntime(d.ctx, dir, d.version)
+	if err != nil {
+		return nil, fmt.Errorf("load profile %q: %w", name, err)
+	}
+	p.attachHandler(d)
+	if d.shared != nil {
+		if health := d.shared.Health(); health != nil {
+			p.handler.SetHealthSource(health)
+		}
+	}
+	p.startRuntime()
+	d.profiles[name] = p
+	slog.Info("profile started", "profile", name, "config_dir", p.configDir)
+	return p, nil
+}
+
+// listProfiles は既定プロファイルと、設定ディレクトリが存在するプロファイルの一覧を返す。
+func (d *Daemon) listProfiles() protocol.ProfileListResult {
+	d.profileMu.Lock()
+	defer d.profileMu.Unlock()
+
+	names := make(map[string]struct{}, len(d.profiles))
+	for name := range d.profiles {
+		names[name] = struct{}{}
+	}
+	for _, name := range profiledir.List(d.configDir) {
+		names[name] = struct{}{}
+	}
+
+	result := protocol.ProfileListResult{Profiles: []protocol.ProfileInfo{{Name: protocol.DefaultProfile, Loaded: true}}}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		_, loaded := d.profiles[name]
+		result.Profiles = append(result.Profiles, protocol.ProfileInfo{Name: name, Loaded: loaded})
+	}
+	return result
+}
+
+// sharedRuntimes は既定プロファイルと起動済みの全プロファイルのランタイムを、共有サービスが参照する形で返す。
+func (d *Daemon) sharedRuntimes() []sharedsvc.Runtime {
+	d.profileMu.Lock()
+	defer d.profileMu.Unlock()
+	rts := []sharedsvc.Runtime{{SSH: d.sshMgr, Forward: d.fwdMgr, Broker: d.broker}}
+	for _, p := range d.profiles {
+		rts = append(rts, sharedsvc.Runtime{SSH: p.sshMgr, Forward: p.fwdMgr, Broker: p.broker})
+	}
+	return rts
+}
+
+// removeClient は切断されたクライアントの購読を全プロファイルのブローカーから削除する。
+func (d *Daemon) removeClient(clientID string) {
+	d.broker.RemoveClient(clientID)
+	d.profileMu.Lock()
+	defer d.profileMu.Unlock()
+	for _, p := range d.profiles {
+		p.broker.RemoveClient(clientID)
+	}
+}
+
+// stopProfiles は起動済みの全プロファイルを停止し、以降のプロファイル起動を拒否する。
+// Stop から d.mu を保持した状態で呼ばれる。
+func (d *Daemon) stopProfiles() {
+	d.profileMu.Lock()
+	profiles := d.profiles
+	d.profiles = nil
+	d.profileMu.Unlock()
+
+	for name, p := range profiles {
+		p.purge = d.purge
+		p.broker.NotifyShutdown()
+		p.stopRuntime()
+		slog.Info("profile stopped", "profile", name)
+	}
+}
@@ -0,0 +1,78 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	ipcclient "github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestDaemon_ProfileRouting(t *testing.T) {
+	dir := createTestConfigDir(t)
+
+	d, err := New(dir, "test")
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	if err := d.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer func() { _ = d.Stop() }()
+
+	client := ipcclient.NewIPCClient(SocketPath(dir))
+	if err := client.Connect(); err != nil {
+		t.Fatalf("client Connect() error: %v", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// 存在しないプロファイルは読み取り系のメソッドでは作成されない
+	client.SetProfile("work")
+	var cfg protocol.ConfigGetResult
+	var rpcErr *protocol.RPCError
+	if err := client.Call(ctx, "config.get", nil, &cfg); !errors.As(err, &rpcErr) || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("config.get (missing profile) error = %v, want InvalidParams", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "profiles", "work")); !os.IsNotExist(err) {
+		t.Errorf("profile dir created by a read-only method: %v", err)
+	}
+
+	// 状態を変更するメソッドでプロファイルが起動し、設定ディレクトリが作成される
+	if err := client.Call(ctx, "config.update", protocol.ConfigUpdateParams{}, nil); err != nil {
+		t.Fatalf("config.update (work) error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "profiles", "work")); err != nil {
+		t.Errorf("profile dir not created: %v", err)
+	}
+	if err := client.Call(ctx, "config.get", nil, &cfg); err != nil {
+		t.Errorf("config.get (work) error: %v", err)
+	}
+
+	var list protocol.ProfileListResult
+	if err := client.Call(ctx, protocol.MethodProfileList, nil, &list); err != nil {
+		t.Fatalf("profile.list error: %v", err)
+	}
+	want := []protocol.ProfileInfo{{Name: "default", Loaded: true}, {Name: "work", Loaded: true}}
+	if len(list.Profiles) != len(want) {
+		t.Fatalf("profiles = %+v, want %+v", list.Profiles, want)
+	}
+	for i := range want {
+		if list.Profiles[i] != want[i] {
+			t.Errorf("profiles[%d] = %+v, want %+v", i, list.Profiles[i], want[i])
+		}
+	}
+
+	// 不正なプロファイル名は InvalidParams
+	client.SetProfile("../escape")
+	err = client.Call(ctx, "config.get", nil, &cfg)
+	if !errors.As(err, &rpcErr) || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("invalid profile error = %v, want InvalidParams", err)
+	}
+}
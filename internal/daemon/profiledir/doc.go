@@ -0,0 +1,2 @@
+// Package profiledir はプロファイル名の検証と、プロファイルごとの設定ディレクトリの配置を提供する。
+package profiledir
@@ -0,0 +1,48 @@
+package profiledir
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// dirName は既定以外のプロファイルの設定ディレクトリを置くディレクトリ名。
+const dirName = "profiles"
+
+// namePattern はプロファイル名として使える文字列。ディレクトリ名として安全な文字に限る。
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// ValidateName はプロファイル名が有効かを検証する。
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid profile name %q: use letters, digits, '-' or '_' (max 64)", name)
+	}
+	return nil
+}
+
+// Dir はプロファイルの設定ディレクトリを返す。既定プロファイルは configDir そのもの。
+func Dir(configDir, name string) string {
+	if name == "" || name == protocol.DefaultProfile {
+		return configDir
+	}
+	return filepath.Join(configDir, dirName, name)
+}
+
+// List は設定ディレクトリが存在するプロファイルの名前を返す。既定プロファイルと、名前が無効なディレクトリは含めない。
+func List(configDir string) []string {
+	entries, err := os.ReadDir(filepath.Join(configDir, dirName))
+	if err != nil && !os.IsNotExist(err) {
+		slog.Warn("failed to read profiles dir", "error", err)
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() && ValidateName(e.Name()) == nil {
+			names = append(names, e.Name())
+		}
+	}
+	return names
+}
@@ -0,0 +1,50 @@
+package profiledir
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestDir(t *testing.T) {
+	if got := Dir("/cfg", protocol.DefaultProfile); got != "/cfg" {
+		t.Errorf("Dir(default) = %q, want /cfg", got)
+	}
+	if got := Dir("/cfg", "work"); got != filepath.Join("/cfg", "profiles", "work") {
+		t.Errorf("Dir(work) = %q", got)
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"work", "personal_2", "a-b"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "../etc", "-work", "a/b", "with space"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) should fail", name)
+		}
+	}
+}
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	if got := List(dir); len(got) != 0 {
+		t.Errorf("List() without profiles = %v, want none", got)
+	}
+	for _, name := range []string{"work", "-invalid"} {
+		if err := os.MkdirAll(Dir(dir, name), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "profiles", "file"), nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if got := List(dir); !slices.Equal(got, []string{"work"}) {
+		t.Errorf("List() = %v, want [work]", got)
+	}
+}
@@ -0,0 +1,2 @@
+// Package reconcile はデーモン起動時に auto_connect のルールを開始し、失敗したルールをバックオフしながら再試行する。
+package reconcile
@@ -0,0 +1,110 @@
+package reconcile
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Forwarder はフォワードの開始と状態の取得を行う。core.ForwardManager が満たす。
+type Forwarder interface {
+	StartForward(ctx context.Context, ruleName string, cb core.CredentialCallback) error
+	GetSession(ruleName string) (*core.ForwardSession, error)
+}
+
+// Notifier は自動開始の進捗を通知する。
+type Notifier func(protocol.AutoStartProgress)
+
+// Policy は開始に失敗したルールの再試行の方針。MaxRetries が 0 の場合は再試行しない。
+type Policy struct {
+	MaxRetries   int
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+}
+
+// PolicyFrom は SSH の再接続設定から再試行の方針を返す。再接続が無効な場合は再試行しない。
+func PolicyFrom(cfg core.ReconnectConfig) Policy {
+	if !cfg.Enabled {
+		return Policy{}
+	}
+	return Policy{MaxRetries: cfg.MaxRetries, InitialDelay: cfg.InitialDelay.Duration, MaxDelay: cfg.MaxDelay.Duration}
+}
+
+// Failure は最初の試行で開始できなかったルールとそのエラー。
+type Failure struct {
+	Rule string
+	Err  error
+}
+
+// Result は Run の集計。Failed は Retry で再試行する。
+type Result struct {
+	Started int
+	Skipped int
+	Failed  []Failure
+}
+
+// Run は rules のうちアクティブでないものを順に開始する。開始済みのルール（前回状態の復元など）はスキップする。
+// daemon 起動時は対話的認証が不可のため、鍵認証/エージェントのみで接続を試みる。
+func Run(ctx context.Context, fwd Forwarder, rules []string, notify Notifier) Result {
+	var res Result
+	for _, name := range rules {
+		if active(fwd, name) {
+			res.Skipped++
+			continue
+		}
+		notify(protocol.AutoStartProgress{Rule: name, Status: protocol.AutoStartStatusStarting, Attempt: 1})
+		if err := fwd.StartForward(ctx, name, nil); err != nil {
+			slog.Warn("auto-start forward failed", "rule", name, "error", err)
+			res.Failed = append(res.Failed, Failure{Rule: name, Err: err})
+			continue
+		}
+		res.Started++
+		notify(protocol.AutoStartProgress{Rule: name, Status: protocol.AutoStartStatusStarted, Attempt: 1})
+	}
+	return res
+}
+
+// Retry は最初の試行で開始できなかったルール f.Rule を、policy に従って待ち時間を倍にしながら再試行する。
+// 開始できたか、ctx がキャンセルされたか、再試行の上限に達するまでブロックする。上限に達した場合は failed を通知する。
+// 待っている間に他の操作でアクティブになった場合は再試行をやめる。開始できた場合は true を返す。
+func Retry(ctx context.Context, fwd Forwarder, f Failure, policy Policy, notify Notifier) bool {
+	name, lastErr := f.Rule, f.Err
+	delay := policy.InitialDelay
+	for attempt := 2; attempt <= policy.MaxRetries+1; attempt++ {
+		notify(protocol.AutoStartProgress{
+			Rule: name, Status: protocol.AutoStartStatusRetrying, Attempt: attempt,
+			RetryIn: delay.String(), Error: lastErr.Error(),
+		})
+		select {
+		case <-ctx.Done():
+			return false
+		case <-time.After(delay):
+		}
+		if active(fwd, name) {
+			return true
+		}
+		if lastErr = fwd.StartForward(ctx, name, nil); lastErr == nil {
+			slog.Info("auto-start forward succeeded after retry", "rule", name, "attempt", attempt)
+			notify(protocol.AutoStartProgress{Rule: name, Status: protocol.AutoStartStatusStarted, Attempt: attempt})
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+		slog.Warn("auto-start forward retry failed", "rule", name, "attempt", attempt, "error", lastErr)
+		delay = min(delay*2, policy.MaxDelay)
+	}
+	notify(protocol.AutoStartProgress{
+		Rule: name, Status: protocol.AutoStartStatusFailed, Attempt: policy.MaxRetries + 1, Error: lastErr.Error(),
+	})
+	return false
+}
+
+// active は name のフォワードがアクティブかを返す。
+func active(fwd Forwarder, name string) bool {
+	s, err := fwd.GetSession(name)
+	return err == nil && s.Status == core.Active
+}
@@ -0,0 +1,111 @@
+package reconcile
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// fakeForwarder は StartForward の呼び出しを記録し、fails 回まで失敗する Forwarder。
+type fakeForwarder struct {
+	fails  map[string]int
+	active map[string]bool
+	calls  []string
+}
+
+func (f *fakeForwarder) StartForward(_ context.Context, name string, _ core.CredentialCallback) error {
+	f.calls = append(f.calls, name)
+	if f.fails[name] > 0 {
+		f.fails[name]--
+		return errors.New("connection refused")
+	}
+	f.active[name] = true
+	return nil
+}
+
+func (f *fakeForwarder) GetSession(name string) (*core.ForwardSession, error) {
+	if f.active[name] {
+		return &core.ForwardSession{Status: core.Active}, nil
+	}
+	return &core.ForwardSession{Status: core.Stopped}, nil
+}
+
+// recorder は通知された進捗を "rule:status:attempt" の形式で記録する。
+type recorder []string
+
+func (r *recorder) notify(p protocol.AutoStartProgress) {
+	*r = append(*r, p.Rule+":"+p.Status+":"+strconv.Itoa(p.Attempt))
+}
+
+func TestRun(t *testing.T) {
+	fwd := &fakeForwarder{fails: map[string]int{"db": 1}, active: map[string]bool{"restored": true}}
+	var got recorder
+
+	res := Run(t.Context(), fwd, []string{"restored", "web", "db"}, got.notify)
+	if res.Started != 1 || res.Skipped != 1 || len(res.Failed) != 1 || res.Failed[0].Rule != "db" {
+		t.Errorf("Run() = %+v, want 1 started, 1 skipped, db failed", res)
+	}
+	want := recorder{"web:starting:1", "web:started:1", "db:starting:1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("progress = %v, want %v", got, want)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	policy := Policy{MaxRetries: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+	first := Failure{Rule: "db", Err: errors.New("connection refused")}
+
+	t.Run("succeeds after backoff", func(t *testing.T) {
+		fwd := &fakeForwarder{fails: map[string]int{"db": 1}, active: map[string]bool{}}
+		var got recorder
+		if !Retry(t.Context(), fwd, first, policy, got.notify) {
+			t.Fatal("Retry() = false, want true")
+		}
+		want := recorder{"db:retrying:2", "db:retrying:3", "db:started:3"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("progress = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		fwd := &fakeForwarder{fails: map[string]int{"db": 10}, active: map[string]bool{}}
+		var got recorder
+		if Retry(t.Context(), fwd, first, policy, got.notify) {
+			t.Fatal("Retry() = true, want false")
+		}
+		if len(fwd.calls) != 3 || got[len(got)-1] != "db:failed:4" {
+			t.Errorf("calls = %v, progress = %v, want 3 calls and failed", fwd.calls, got)
+		}
+	})
+
+	t.Run("stops when started elsewhere", func(t *testing.T) {
+		fwd := &fakeForwarder{active: map[string]bool{"db": true}}
+		if !Retry(t.Context(), fwd, first, policy, func(protocol.AutoStartProgress) {}) || len(fwd.calls) != 0 {
+			t.Errorf("calls = %v, want none for an active rule", fwd.calls)
+		}
+	})
+
+	t.Run("no retries", func(t *testing.T) {
+		fwd := &fakeForwarder{active: map[string]bool{}}
+		var got recorder
+		Retry(t.Context(), fwd, first, PolicyFrom(core.ReconnectConfig{Enabled: false, MaxRetries: 5}), got.notify)
+		if len(fwd.calls) != 0 || !reflect.DeepEqual(got, recorder{"db:failed:1"}) {
+			t.Errorf("calls = %v, progress = %v, want only failed", fwd.calls, got)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		fwd := &fakeForwarder{active: map[string]bool{}}
+		if Retry(ctx, fwd, first, Policy{MaxRetries: 3, InitialDelay: time.Hour}, func(protocol.AutoStartProgress) {}) {
+			t.Error("Retry() = true after cancel, want false")
+		}
+	})
+}
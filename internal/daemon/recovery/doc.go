@@ -0,0 +1,2 @@
+// Package recovery は前回状態のスナップショットからのフォワード再開と、その結果の集計を提供する。
+package recovery
@@ -0,0 +1,117 @@
+package recovery
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/depgraph"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Starter はフォワードの開始を行う。core.ForwardManager が満たす。
+type Starter interface {
+	StartForward(ctx context.Context, ruleName string, cb core.CredentialCallback) error
+	LoadSessionRecords(records []core.SessionRecord)
+}
+
+// Snapshot は sessions から保存用の状態を作る。アクティブなフォワードとその開始時刻・再接続回数、停止理由のあるルールの履歴を含める。
+// SSH の再接続を待っているフォワードも、再接続中に保存した場合に復元の対象から外れないようアクティブとして含める。
+// shuttingDown が true の場合は停止していないフォワードも daemon_shutdown で停止したものとして履歴に含める。
+func Snapshot(sessions []core.ForwardSession, shuttingDown bool, now time.Time) *core.State {
+	state := &core.State{LastUpdated: now}
+	for _, s := range sessions {
+		if s.Status == core.Active || s.Status == core.SessionReconnecting {
+			state.ActiveForwards = append(state.ActiveForwards, s.Rule)
+			state.Sessions = append(state.Sessions, core.SessionRecord{Rule: s.Rule.Name, StartedAt: s.ConnectedAt, ReconnectCount: s.ReconnectCount})
+		}
+		switch {
+		case s.StoppedReason != core.StopReasonNone:
+			state.StopHistory = append(state.StopHistory, core.StopRecord{Rule: s.Rule.Name, Reason: s.StoppedReason, At: s.StoppedAt})
+		case shuttingDown && s.Status != core.Stopped:
+			state.StopHistory = append(state.StopHistory, core.StopRecord{Rule: s.Rule.Name, Reason: core.StopReasonDaemonShutdown, At: now})
+		}
+	}
+	return state
+}
+
+// Restore は state でアクティブだったフォワードを depends_on の依存先から順に開始し、ルールごとの結果を返す。
+// ホストへの接続はフォワード開始時に行われる。daemon 起動時は対話的認証が不可のため cb は nil とする。
+// ctx がキャンセルされた場合（デーモンの停止）は接続中のフォワードの開始を中断する。
+// 復元したセッションはスナップショットの開始時刻と再接続回数を引き継ぐ。
+func Restore(ctx context.Context, fwd Starter, state *core.State) []core.ForwardRestoreResult {
+	fwd.LoadSessionRecords(state.Sessions)
+	defer fwd.LoadSessionRecords(nil)
+	results := make([]core.ForwardRestoreResult, 0, len(state.ActiveForwards))
+	for _, rule := range depgraph.Sort(state.ActiveForwards) {
+		r := core.ForwardRestoreResult{RuleName: rule.Name, OK: true}
+		if err := fwd.StartForward(ctx, rule.Name, nil); err != nil {
+			r.OK, r.Error = false, err.Error()
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// Summarize は Restore の結果を、復元したルール・失敗したルール・再接続したホストにまとめる。
+// スナップショットに開始時刻と再接続回数があるルールは、復元したルールの前回の稼働状況として含める。
+func Summarize(state *core.State, results []core.ForwardRestoreResult) protocol.RecoverySummary {
+	hostOf := make(map[string]string, len(state.ActiveForwards))
+	for _, rule := range state.ActiveForwards {
+		hostOf[rule.Name] = rule.Host
+	}
+	records := make(map[string]core.SessionRecord, len(state.Sessions))
+	for _, rec := range state.Sessions {
+		records[rec.Rule] = rec
+	}
+
+	summary := protocol.RecoverySummary{Restored: []string{}, Hosts: []string{}}
+	if !state.LastUpdated.IsZero() {
+		summary.SnapshotAt = state.LastUpdated.Format(time.RFC3339)
+	}
+	hosts := make(map[string]bool)
+	for _, r := range results {
+		if !r.OK {
+			summary.Failed = append(summary.Failed, r.RuleName)
+			continue
+		}
+		summary.Restored = append(summary.Restored, r.RuleName)
+		if rec, ok := records[r.RuleName]; ok {
+			summary.Sessions = append(summary.Sessions, recoveredSession(rec))
+		}
+		if h := hostOf[r.RuleName]; h != "" && !hosts[h] {
+			hosts[h] = true
+			summary.Hosts = append(summary.Hosts, h)
+		}
+	}
+	sort.Strings(summary.Hosts)
+	return summary
+}
+
+// recoveredSession はスナップショットのセッションの記録を IPC の形式に変換する。
+func recoveredSession(rec core.SessionRecord) protocol.RecoveredSession {
+	s := protocol.RecoveredSession{Name: rec.Rule, ReconnectCount: rec.ReconnectCount}
+	if !rec.StartedAt.IsZero() {
+		s.StartedAt = rec.StartedAt.Format(time.RFC3339)
+	}
+	return s
+}
+
+// LogSummary はフォワード復元結果のサマリーをログ出力する。失敗したルールは個別に警告する。
+func LogSummary(hostName string, results []core.ForwardRestoreResult) {
+	if len(results) == 0 {
+		return
+	}
+	succeeded, failed := 0, 0
+	for _, r := range results {
+		if r.OK {
+			succeeded++
+		} else {
+			failed++
+			slog.Warn("forward restore failed", "host", hostName, "rule", r.RuleName, "error", r.Error)
+		}
+	}
+	slog.Info("forward restore summary", "host", hostName, "total", len(results), "succeeded", succeeded, "failed", failed)
+}
@@ -0,0 +1,114 @@
+package recovery
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type starterFunc func(string) error
+
+func (f starterFunc) StartForward(_ context.Context, name string, _ core.CredentialCallback) error {
+	return f(name)
+}
+
+func (starterFunc) LoadSessionRecords([]core.SessionRecord) {}
+
+func TestRestoreAndSummarize(t *testing.T) {
+	state := &core.State{
+		LastUpdated: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		ActiveForwards: []core.ForwardRule{
+			{Name: "web", Host: "prod"}, {Name: "db", Host: "db-host"}, {Name: "api", Host: "prod"}, {Name: "cache", Host: "cache-host"},
+		},
+		Sessions: []core.SessionRecord{
+			{Rule: "web", StartedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), ReconnectCount: 2},
+			{Rule: "cache", StartedAt: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+	}
+	var started []string
+	results := Restore(t.Context(), starterFunc(func(name string) error {
+		started = append(started, name)
+		if name == "cache" {
+			return errors.New("connection refused")
+		}
+		return nil
+	}), state)
+
+	if want := []string{"web", "db", "api", "cache"}; !reflect.DeepEqual(started, want) {
+		t.Fatalf("started = %v, want %v", started, want)
+	}
+	if results[3].OK || results[3].Error != "connection refused" {
+		t.Errorf("results[3] = %+v, want failure with error", results[3])
+	}
+
+	got := Summarize(state, results)
+	if got.SnapshotAt != "2026-01-02T03:04:05Z" {
+		t.Errorf("SnapshotAt = %q", got.SnapshotAt)
+	}
+	if want := []string{"web", "db", "api"}; !reflect.DeepEqual(got.Restored, want) {
+		t.Errorf("Restored = %v, want %v", got.Restored, want)
+	}
+	if want := []string{"cache"}; !reflect.DeepEqual(got.Failed, want) {
+		t.Errorf("Failed = %v, want %v", got.Failed, want)
+	}
+	if want := []string{"db-host", "prod"}; !reflect.DeepEqual(got.Hosts, want) {
+		t.Errorf("Hosts = %v, want %v", got.Hosts, want)
+	}
+	wantSessions := []protocol.RecoveredSession{{Name: "web", StartedAt: "2026-01-01T00:00:00Z", ReconnectCount: 2}}
+	if !reflect.DeepEqual(got.Sessions, wantSessions) {
+		t.Errorf("Sessions = %+v, want %+v", got.Sessions, wantSessions)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	stoppedAt := now.Add(-time.Hour)
+	sessions := []core.ForwardSession{
+		{Status: core.Active, Rule: core.ForwardRule{Name: "web"}, ConnectedAt: stoppedAt, ReconnectCount: 3},
+		{Status: core.Stopped, Rule: core.ForwardRule{Name: "db"}, StoppedReason: core.StopReasonTTL, StoppedAt: stoppedAt},
+		{Status: core.Stopped, Rule: core.ForwardRule{Name: "idle"}},
+		{Status: core.SessionError, Rule: core.ForwardRule{Name: "api"}, StoppedReason: core.StopReasonSSHLost, StoppedAt: stoppedAt},
+		{Status: core.SessionReconnecting, Rule: core.ForwardRule{Name: "cache"}, ConnectedAt: stoppedAt, ReconnectCount: 1},
+	}
+
+	got := Snapshot(sessions, false, now)
+	// 再接続待ちのフォワードもアクティブとして残す
+	if len(got.ActiveForwards) != 2 || got.ActiveForwards[0].Name != "web" || got.ActiveForwards[1].Name != "cache" || !got.LastUpdated.Equal(now) {
+		t.Errorf("Snapshot() = %+v, want web and cache active", got)
+	}
+	wantSessions := []core.SessionRecord{{Rule: "web", StartedAt: stoppedAt, ReconnectCount: 3}, {Rule: "cache", StartedAt: stoppedAt, ReconnectCount: 1}}
+	if !reflect.DeepEqual(got.Sessions, wantSessions) {
+		t.Errorf("Sessions = %+v, want %+v", got.Sessions, wantSessions)
+	}
+	want := []core.StopRecord{
+		{Rule: "db", Reason: core.StopReasonTTL, At: stoppedAt},
+		{Rule: "api", Reason: core.StopReasonSSHLost, At: stoppedAt},
+	}
+	if !reflect.DeepEqual(got.StopHistory, want) {
+		t.Errorf("StopHistory = %+v, want %+v", got.StopHistory, want)
+	}
+
+	got = Snapshot(sessions, true, now)
+	want = append([]core.StopRecord{{Rule: "web", Reason: core.StopReasonDaemonShutdown, At: now}}, want...)
+	want = append(want, core.StopRecord{Rule: "cache", Reason: core.StopReasonDaemonShutdown, At: now})
+	if !reflect.DeepEqual(got.StopHistory, want) {
+		t.Errorf("StopHistory while shutting down = %+v, want %+v", got.StopHistory, want)
+	}
+}
+
+func TestSummarize_Empty(t *testing.T) {
+	got := Summarize(&core.State{}, nil)
+	if got.SnapshotAt != "" || len(got.Restored) != 0 || got.Restored == nil || got.Failed != nil {
+		t.Errorf("Summarize(empty) = %+v", got)
+	}
+}
+
+func TestLogSummary(t *testing.T) {
+	LogSummary("myhost", nil) // パニックしないことを確認
+	LogSummary("myhost", []core.ForwardRestoreResult{{RuleName: "web", OK: true}, {RuleName: "db", Error: "refused"}})
+}
@@ -0,0 +1,183 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/config"
+	"github.com/ousiassllc/moleport/internal/core/depgraph"
+	"github.com/ousiassllc/moleport/internal/core/forward"
+	"github.com/ousiassllc/moleport/internal/core/notifyconf"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+	"github.com/ousiassllc/moleport/internal/core/ssh"
+	"github.com/ousiassllc/moleport/internal/core/ssh/hostdefs"
+	"github.com/ousiassllc/moleport/internal/daemon/hostsync"
+	"github.com/ousiassllc/moleport/internal/infra"
+	"github.com/ousiassllc/moleport/internal/infra/accesslog"
+	"github.com/ousiassllc/moleport/internal/infra/desktopnotify"
+	"github.com/ousiassllc/moleport/internal/infra/hookexec"
+	"github.com/ousiassllc/moleport/internal/infra/sshconfig"
+	"github.com/ousiassllc/moleport/internal/infra/tunnelregistry"
+	"github.com/ousiassllc/moleport/internal/infra/webhook"
+	"github.com/ousiassllc/moleport/internal/infra/yamlstore"
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
+	ipchandler "github.com/ousiassllc/moleport/internal/ipc/handler"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/wsbridge"
+)
+
+// newRuntime は configDir の設定から SSH・フォワード管理を構築した Daemon を生成する。
+// IPC サーバーや PID ファイルなどデーモン全体で共有するコンポーネントは呼び出し元が設定する。
+func newRuntime(parent context.Context, configDir string, version string) (*Daemon, error) {
+	if err := os.MkdirAll(configDir, 0700); err != nil {
+		return nil, fmt.Errorf("create config dir: %w", err)
+	}
+
+	store := yamlstore.NewYAMLStore()
+	cfgMgr := config.NewConfigManager(store, configDir)
+	cfg, err := cfgMgr.LoadConfig()
+	// 検証に失敗した設定で起動すると、以降の保存で既定値に置き換わってしまうため起動しない
+	var invalidConfig *core.InvalidConfigError
+	if errors.As(err, &invalidConfig) {
+		return nil, err
+	}
+	if err != nil {
+		c := core.DefaultConfig()
+		cfg = &c
+	}
+
+	// SSH config パスの ~ を展開
+	sshConfigPath := cfg.SSHConfigPath
+	if expanded, err := infra.ExpandTilde(sshConfigPath); err == nil {
+		sshConfigPath = expanded
+	}
+
+	ctx, cancel := context.WithCancel(parent)
+	sshMgr := ssh.NewSSHManager(
+		ctx,
+		hostdefs.NewParser(sshconfig.NewSSHConfigParser(), func() []core.HostDefinition { return cfgMgr.GetConfig().HostDefinitions }),
+		func() core.SSHConnection { return infra.NewSSHConnection() },
+		sshConfigPath,
+		cfg.Reconnect,
+		cfg.Hosts,
+		cfg.Timeouts,
+	)
+	// ルールの on_start・on_stop を実行し、depends_on に従って依存先を先に開始して依存先が失敗したルールを停止する
+	hooks := hookexec.Wrap(forward.NewForwardManager(ctx, sshMgr, infra.LocalPortInUse, func() time.Duration {
+		return cfgMgr.GetConfig().Timeouts.ForwardDrain.Duration
+	}, accesslog.New(filepath.Join(configDir, accesslog.DirName))))
+	fwdMgr := depgraph.Wrap(hooks, sshMgr)
+
+	// 保存済みのフォワードルールを読み込む
+	var warnings []string
+	for _, rule := range cfg.Forwards {
+		_, err := fwdMgr.AddRule(rule)
+		// 命名規則の導入前に保存された名前は代替名に置き換えて読み込む
+		var invalid *core.InvalidRuleNameError
+		if errors.As(err, &invalid) {
+			rule.Name = invalid.Suggestion
+			if _, err = fwdMgr.AddRule(rule); err == nil {
+				slog.Warn("renamed forward rule with invalid name", "rule", invalid.Name, "new_name", rule.Name)
+				warnings = append(warnings, fmt.Sprintf("forward rule %q was renamed to %q: %s", invalid.Name, rule.Name, invalid.Reason))
+			}
+		}
+		if err != nil {
+			slog.Warn("failed to load forward rule", "rule", rule.Name, "error", err)
+			warnings = append(warnings, fmt.Sprintf("failed to load forward rule %q: %v", rule.Name, err))
+		}
+	}
+
+	return &Daemon{
+		configDir:     configDir,
+		sshConfigPath: sshConfigPath,
+		version:       version,
+		cfgMgr:        cfgMgr,
+		sshMgr:        sshMgr,
+		fwdMgr:        fwdMgr,
+		hooks:         hooks,
+		webhooks: webhook.New(
+			func() []serviceconf.Webhook { return cfgMgr.GetConfig().Webhooks },
+			filepath.Join(configDir, webhook.DeadLetterFileName),
+		),
+		notifier: desktopnotify.New(func() notifyconf.Config { return cfgMgr.GetConfig().Notifications }),
+		registry: tunnelregistry.New(func() serviceconf.Registry { return cfgMgr.GetConfig().Registry }, sshMgr),
+		ctx:      ctx,
+		cancel:   cancel,
+		warnings: warnings,
+	}, nil
+}
+
+// attachHandler は d のマネージャーを公開する EventBroker と IPC ハンドラーを生成する。
+// 通知の送信とデーモン全体の操作（daemon.status / daemon.shutdown 等）は root が担う。
+func (d *Daemon) attachHandler(root *Daemon) {
+	d.broker = broker.NewEventBroker(func(clientID string, notification protocol.Notification) error {
+		if wsbridge.Owns(clientID) {
+			if ws := root.ws.Load(); ws != nil {
+				return ws.Send(clientID, notification)
+			}
+		}
+		return root.server.SendNotification(clientID, notification)
+	})
+	d.handler = ipchandler.NewHandler(d.sshMgr, d.fwdMgr, d.cfgMgr, d.broker, root, root.versionChecker)
+	if root.server != nil {
+		d.handler.SetSender(root.server)
+	}
+}
+
+// startRuntime はホストの読み込み、イベント配信、前回状態の復元とフォワードの自動開始を行い、
+// プロファイルの ssh_config と config.yaml の監視を開始する。
+func (d *Daemon) startRuntime() {
+	// SSH ホストを読み込む（エラーは警告のみ）
+	if _, err := d.sshMgr.LoadHosts(); err != nil {
+		slog.Warn("failed to load SSH hosts", "config_dir", d.configDir, "error", err)
+		d.warnings = append(d.warnings, fmt.Sprintf("failed to load SSH hosts: %v", err))
+	}
+	// ホストに定義されたフォワードは状態の復元と自動開始より先に登録する
+	hostsync.Sync(d.fwdMgr, d.sshMgr.GetHosts(), d.cfgMgr.GetConfig().Hosts)
+
+	d.startEventRouting()
+	d.wg.Add(2)
+	go func() {
+		defer d.wg.Done()
+		d.broker.RunSummary(d.ctx, broker.SummaryInterval, d.fwdMgr, d.sshMgr)
+	}()
+	go func() {
+		defer d.wg.Done()
+		d.broker.RunMetrics(d.ctx, broker.MetricsInterval, d.fwdMgr)
+	}()
+	d.restoreState()
+	d.autoStartForwards()
+	d.startWatchers()
+}
+
+// stopRuntime は状態を保存（purge 時は削除）し、全フォワードと SSH 接続を停止する。
+func (d *Daemon) stopRuntime() {
+	// コンテキストを最初にキャンセルして全コンポーネントに停止を通知
+	if d.cancel != nil {
+		d.cancel()
+	}
+
+	if d.purge {
+		if err := d.cfgMgr.DeleteState(); err != nil {
+			slog.Warn("failed to delete state", "error", err)
+		}
+	} else {
+		d.saveState()
+	}
+
+	// Close は残っているフォワードを daemon_shutdown の停止理由で停止する
+	d.fwdMgr.Close()
+	d.sshMgr.Close()
+
+	// イベントルーティングゴルーチンの終了を待ち、残りの Webhook 通知・フォワードの登録解除とデスクトップ通知を送信する
+	d.wg.Wait()
+	d.webhooks.Close()
+	d.registry.Close()
+	d.notifier.Close()
+}
@@ -0,0 +1,41 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewRuntime_RenamesInvalidRuleNames(t *testing.T) {
+	dir := createTestConfigDir(t)
+	forwards := "forwards:\n" +
+		"  - name: \"db tunnel\"\n    host: testhost\n    type: local\n    local_port: 15432\n    remote_port: 5432\n" +
+		"  - name: web\n    host: testhost\n    type: local\n    local_port: 18080\n    remote_port: 80\n"
+	f, err := os.OpenFile(filepath.Join(dir, "config.yaml"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(forwards); err != nil {
+		t.Fatal(err)
+	}
+	_ = f.Close()
+
+	d, err := newRuntime(context.Background(), dir, "test")
+	if err != nil {
+		t.Fatalf("newRuntime() error: %v", err)
+	}
+	defer d.cancel()
+
+	var names []string
+	for _, r := range d.fwdMgr.GetRules() {
+		names = append(names, r.Name)
+	}
+	if strings.Join(names, ",") != "db-tunnel,web" {
+		t.Errorf("rules = %v, want [db-tunnel web]", names)
+	}
+	if len(d.warnings) != 1 || !strings.Contains(d.warnings[0], `"db tunnel" was renamed to "db-tunnel"`) {
+		t.Errorf("warnings = %v", d.warnings)
+	}
+}
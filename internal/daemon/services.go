@@ -0,0 +1,151 @@
+package daemon
+
+import (
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+	"github.com/ousiassllc/moleport/internal/daemon/liveconfig"
+	"github.com/ousiassllc/moleport/internal/daemon/sharedsvc"
+	"github.com/ousiassllc/moleport/internal/infra/filewatch"
+	"github.com/ousiassllc/moleport/internal/infra/sshconfig"
+	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/ipc/authtoken"
+	"github.com/ousiassllc/moleport/internal/ipc/msgpackipc"
+	"github.com/ousiassllc/moleport/internal/ipc/tlsipc"
+	"github.com/ousiassllc/moleport/internal/ipc/wsbridge"
+)
+
+// startWatchers は ssh_config（Include 先を含む）と config.yaml の監視を開始し、変更を稼働中のデーモンへ反映する。
+// ログレベルはプロセス全体で共有するため、既定プロファイルの config.yaml の変更のみ反映する。
+func (d *Daemon) startWatchers() {
+	var level *slog.LevelVar
+	if d.profiles != nil {
+		level = &liveconfig.LogLevel
+	}
+	live := liveconfig.New(d.cfgMgr, d.sshMgr, d.fwdMgr, d.broker, level)
+	d.live.Store(live)
+	d.handler.SetHostsReloaded(live.SyncHostForwards)
+	d.watch(d.sshConfigPath, sshconfig.Sources, live.ReloadHosts)
+	d.watch(filepath.Join(d.configDir, "config.yaml"), nil, live.ReloadConfig)
+}
+
+// watch は path の監視を開始する。監視を開始できなくてもデーモンは継続し、
+// host.reload 等の RPC による再読み込みは引き続き使える。
+func (d *Daemon) watch(path string, sources filewatch.SourcesFunc, onChange func()) {
+	w, err := filewatch.New(path, sources, onChange)
+	if err != nil {
+		slog.Warn("failed to watch file", "path", path, "error", err)
+		return
+	}
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		w.Run(d.ctx)
+	}()
+}
+
+// startSharedServices は DNS リゾルバー、ホストの疎通確認と mDNS による公開をデーモン全体で一度だけ起動する。
+// 疎通確認の結果の取得元は起動済みのプロファイルのハンドラーにも設定し、後から起動するプロファイルは profile で設定する。
+func (d *Daemon) startSharedServices() {
+	shared, warnings := sharedsvc.Start(d.ctx, &d.wg, d.cfgMgr.GetConfig, d.sharedRuntimes)
+	d.warnings = append(d.warnings, warnings...)
+
+	d.profileMu.Lock()
+	defer d.profileMu.Unlock()
+	d.shared = shared
+	if health := shared.Health(); health != nil {
+		d.handler.SetHealthSource(health)
+		for _, p := range d.profiles {
+			p.handler.SetHealthSource(health)
+		}
+	}
+}
+
+// startRemote は設定で有効な場合に、別のマシンの CLI/TUI からの接続を受け付ける TLS リスナーを起動する。
+// リスナーは IPC サーバーの停止時に閉じる。起動に失敗してもデーモンは継続し、警告として記録する。
+func (d *Daemon) startRemote() {
+	cfg := d.cfgMgr.GetConfig().Remote
+	if !cfg.Enabled {
+		return
+	}
+	addr := cfg.Listen
+	if addr == "" {
+		addr = serviceconf.DefaultRemoteListen
+	}
+	ln, err := tlsipc.Listen(addr, tlsipc.Dir(d.configDir), cfg.Hosts)
+	if err != nil {
+		slog.Warn("failed to start remote listener", "error", err)
+		d.warnings = append(d.warnings, fmt.Sprintf("failed to start remote listener: %v", err))
+		return
+	}
+	d.server.Serve(ln)
+	slog.Info("remote listener started", "addr", ln.Addr().String())
+}
+
+// startMsgpack は設定で有効な場合に、MessagePack のトランスポートで接続を受け付ける Unix ソケットを作成する。
+// リスナーは IPC サーバーの停止時に閉じる。起動に失敗してもデーモンは継続し、警告として記録する。
+func (d *Daemon) startMsgpack() {
+	if d.cfgMgr.GetConfig().IPC.Transport != serviceconf.IPCTransportMsgpack {
+		return
+	}
+	ln, err := msgpackipc.Listen(MsgpackSocketPath(d.configDir))
+	if err != nil {
+		slog.Warn("failed to start msgpack listener", "error", err)
+		d.warnings = append(d.warnings, fmt.Sprintf("failed to start msgpack listener: %v", err))
+		return
+	}
+	d.server.Serve(ln)
+	slog.Info("msgpack listener started", "path", MsgpackSocketPath(d.configDir))
+}
+
+// startEventBridge は設定で有効な場合に、イベントを WebSocket で中継するブリッジを起動する。
+// 起動に失敗してもデーモンは継続し、警告として記録する。
+func (d *Daemon) startEventBridge() {
+	cfg := d.cfgMgr.GetConfig().EventBridge
+	if !cfg.Enabled {
+		return
+	}
+	addr := cfg.Listen
+	if addr == "" {
+		addr = serviceconf.DefaultEventBridgeListen
+	}
+	ws, err := wsbridge.Listen(addr, d.broker, cfg.AllowedOrigins)
+	if err != nil {
+		slog.Warn("failed to start event bridge", "error", err)
+		d.warnings = append(d.warnings, fmt.Sprintf("failed to start event bridge: %v", err))
+		return
+	}
+	d.ws.Store(ws)
+	slog.Info("event bridge started", "url", "ws://"+ws.Addr().String()+wsbridge.Path)
+}
+
+// stopEventBridge は起動中のイベントブリッジを停止する。
+func (d *Daemon) stopEventBridge() {
+	if ws := d.ws.Swap(nil); ws != nil {
+		if err := ws.Close(); err != nil {
+			slog.Warn("failed to stop event bridge", "error", err)
+		}
+	}
+}
+
+// setupAuth は認証トークンと読み取り専用トークンを発行し、IPC サーバーの接続元の認証を設定する。
+// トークンは常に発行してローカルのクライアントが auth.login で提示できるようにし、提示の必須化は auth.require_token に従う。
+// 接続元の UID を確認できない OS では auth.require_token によらず提示を必須にする。
+func (d *Daemon) setupAuth() error {
+	token, err := authtoken.Generate(TokenPath(d.configDir))
+	if err != nil {
+		return fmt.Errorf("setup ipc auth: %w", err)
+	}
+	readOnly, err := authtoken.Generate(ReadOnlyTokenPath(d.configDir))
+	if err != nil {
+		return fmt.Errorf("setup ipc auth: %w", err)
+	}
+	auth := ipc.Auth{ReadOnlyToken: readOnly, SameUID: true}
+	if d.cfgMgr.GetConfig().Auth.RequireToken || !ipc.PeerCredAvailable {
+		auth.Token = token
+	}
+	d.server.SetAuth(auth)
+	return nil
+}
@@ -0,0 +1,2 @@
+// Package sharedsvc は DNS リゾルバー、ホストの疎通確認と mDNS による公開を、デーモンの全プロファイルで共有するサービスとして一度だけ起動する。
+package sharedsvc
@@ -0,0 +1,125 @@
+package sharedsvc
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+	"github.com/ousiassllc/moleport/internal/infra/dnsserver"
+	"github.com/ousiassllc/moleport/internal/infra/healthprobe"
+	"github.com/ousiassllc/moleport/internal/infra/mdns"
+)
+
+// HealthNotifier はホストの疎通確認の結果の通知先。ipc/broker の EventBroker が満たす。
+type HealthNotifier interface {
+	NotifyHostHealth(name string, health core.HostHealth)
+}
+
+// Runtime はプロファイルのランタイムのうち、共有サービスが参照するマネージャー群。
+type Runtime struct {
+	SSH     core.SSHManager
+	Forward core.ForwardManager
+	Broker  HealthNotifier
+}
+
+// Services は起動した共有サービスを保持する。対象は runtimes が返す全プロファイルのホストとフォワード。
+type Services struct {
+	runtimes func() []Runtime
+	dns      *dnsserver.Server
+	prober   *healthprobe.Prober
+}
+
+// Start は cfg で有効な共有サービスを起動する。起動に失敗してもデーモンは継続し、失敗は警告として返す。
+// ゴルーチンは wg に登録し、ctx の終了で停止する。
+func Start(ctx context.Context, wg *sync.WaitGroup, cfg func() *core.Config, runtimes func() []Runtime) (*Services, []string) {
+	s := &Services{runtimes: runtimes}
+	var warnings []string
+	if err := s.startDNS(cfg().DNS); err != nil {
+		slog.Warn("failed to start dns resolver", "error", err)
+		warnings = append(warnings, fmt.Sprintf("failed to start dns resolver: %v", err))
+	}
+	if hc := cfg().HealthCheck; hc.Enabled {
+		s.prober = healthprobe.New(func() []healthprobe.Target {
+			return healthprobe.TargetsOf(s.Hosts())
+		}, hc.Interval.Duration, s.notifyHealth)
+		wg.Go(func() { s.prober.Run(ctx) })
+		slog.Info("host health probe started", "interval", hc.Interval.Duration)
+	}
+	// mDNS による公開は、設定の変更に合わせて開始・停止する
+	adv := mdns.New(func() bool { return cfg().MDNS.Enabled }, s.Sessions)
+	wg.Go(func() { adv.Run(ctx) })
+	return s, warnings
+}
+
+// startDNS は設定で有効な場合にローカル DNS リゾルバーを起動する。
+func (s *Services) startDNS(cfg serviceconf.DNS) error {
+	if !cfg.Enabled {
+		return nil
+	}
+	addr := cfg.Listen
+	if addr == "" {
+		addr = serviceconf.DefaultDNSListen
+	}
+	srv, err := dnsserver.Listen(addr, dnsserver.SessionLookup(s.Sessions))
+	if err != nil {
+		return err
+	}
+	s.dns = srv
+	slog.Info("dns resolver started", "addr", srv.Addr().String(), "domain", dnsserver.Domain)
+	return nil
+}
+
+// Stop は起動中の DNS リゾルバーを停止する。疎通確認と mDNS による公開は Start に渡した ctx の終了で停止する。
+func (s *Services) Stop() {
+	if s.dns == nil {
+		return
+	}
+	if err := s.dns.Close(); err != nil {
+		slog.Warn("failed to stop dns resolver", "error", err)
+	}
+	s.dns = nil
+}
+
+// Health は疎通確認の結果の取得元を返す。疎通確認が無効な場合は nil を返す。
+func (s *Services) Health() func(name string) core.HostHealth {
+	if s.prober == nil {
+		return nil
+	}
+	return s.prober.Health
+}
+
+// Sessions は全プロファイルのフォワードセッションを返す。DNS リゾルバーと mDNS による公開が参照する。
+func (s *Services) Sessions() []core.ForwardSession {
+	var sessions []core.ForwardSession
+	for _, rt := range s.runtimes() {
+		sessions = append(sessions, rt.Forward.GetAllSessions()...)
+	}
+	return sessions
+}
+
+// Hosts は全プロファイルの SSH ホストを返す。疎通確認の結果はホスト名で引くため、同名のホストは一度だけ含める。
+func (s *Services) Hosts() []core.SSHHost {
+	var hosts []core.SSHHost
+	seen := make(map[string]bool)
+	for _, rt := range s.runtimes() {
+		for _, h := range rt.SSH.GetHosts() {
+			if !seen[h.Name] {
+				seen[h.Name] = true
+				hosts = append(hosts, h)
+			}
+		}
+	}
+	return hosts
+}
+
+// notifyHealth は name のホストを持つプロファイルに疎通確認の結果を通知する。
+func (s *Services) notifyHealth(name string, health core.HostHealth) {
+	for _, rt := range s.runtimes() {
+		if _, err := rt.SSH.GetHost(name); err == nil {
+			rt.Broker.NotifyHostHealth(name, health)
+		}
+	}
+}
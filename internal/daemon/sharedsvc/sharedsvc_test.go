@@ -0,0 +1,92 @@
+package sharedsvc
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// 共有サービスが呼び出さないメソッドは nil の埋め込みで満たす（呼び出すと panic する）。
+type fakeSSH struct {
+	core.SSHManager
+	hosts []core.SSHHost
+}
+
+func (f *fakeSSH) GetHosts() []core.SSHHost { return f.hosts }
+
+func (f *fakeSSH) GetHost(name string) (*core.SSHHost, error) {
+	for _, h := range f.hosts {
+		if h.Name == name {
+			return &h, nil
+		}
+	}
+	return nil, &core.NotFoundError{Resource: "host", Name: name}
+}
+
+type fakeForward struct {
+	core.ForwardManager
+	sessions []core.ForwardSession
+}
+
+func (f *fakeForward) GetAllSessions() []core.ForwardSession { return f.sessions }
+
+type healthRecorder []string
+
+func (r *healthRecorder) NotifyHostHealth(name string, _ core.HostHealth) { *r = append(*r, name) }
+
+func runtime(hosts []string, rules []string, broker HealthNotifier) Runtime {
+	ssh := &fakeSSH{}
+	for _, h := range hosts {
+		ssh.hosts = append(ssh.hosts, core.SSHHost{Name: h})
+	}
+	fwd := &fakeForward{}
+	for _, r := range rules {
+		fwd.sessions = append(fwd.sessions, core.ForwardSession{Rule: core.ForwardRule{Name: r}})
+	}
+	return Runtime{SSH: ssh, Forward: fwd, Broker: broker}
+}
+
+func TestStart_Disabled(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+	s, warnings := Start(ctx, &wg, func() *core.Config { return &core.Config{} }, func() []Runtime { return nil })
+	if s.dns != nil || s.Health() != nil || len(warnings) != 0 {
+		t.Errorf("dns = %v, health = %v, warnings = %v, want none", s.dns, s.Health() != nil, warnings)
+	}
+	s.Stop()
+}
+
+func TestServices_CoversProfiles(t *testing.T) {
+	var root, work healthRecorder
+	rts := []Runtime{
+		runtime([]string{"prod", "shared"}, []string{"web"}, &root),
+		runtime([]string{"shared", "staging"}, []string{"db"}, &work),
+	}
+	s := &Services{runtimes: func() []Runtime { return rts }}
+
+	var sessions []string
+	for _, ss := range s.Sessions() {
+		sessions = append(sessions, ss.Rule.Name)
+	}
+	if strings.Join(sessions, ",") != "web,db" {
+		t.Errorf("Sessions() = %v, want [web db]", sessions)
+	}
+	var hosts []string
+	for _, h := range s.Hosts() {
+		hosts = append(hosts, h.Name)
+	}
+	if strings.Join(hosts, ",") != "prod,shared,staging" {
+		t.Errorf("Hosts() = %v, want [prod shared staging]", hosts)
+	}
+
+	s.notifyHealth("shared", core.HealthReachable)
+	s.notifyHealth("staging", core.HealthReachable)
+	if strings.Join(root, ",") != "shared" || strings.Join(work, ",") != "shared,staging" {
+		t.Errorf("notified root = %v, work = %v", root, work)
+	}
+}
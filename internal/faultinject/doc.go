@@ -0,0 +1,8 @@
+// Package faultinject は再接続・復元ロジックの耐障害性テスト用のフォールトインジェクション機能を提供する。
+//
+// 実際に障害を注入する実装は chaos ビルドタグ付きでビルドした場合のみ有効になる。
+// 通常ビルドではすべてのフックが何もしないため、本番バイナリへの影響はない。
+//
+//	go test -tags chaos ./...
+//	go build -tags chaos ./cmd/moleport
+package faultinject
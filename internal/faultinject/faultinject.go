@@ -0,0 +1,52 @@
+package faultinject
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnavailable は chaos ビルドタグなしでビルドされたバイナリで設定変更を要求した場合のエラー。
+var ErrUnavailable = errors.New("fault injection is not available (build with -tags chaos)")
+
+// ErrInjectedDialFailure は注入されたダイヤル失敗を表すエラー。
+var ErrInjectedDialFailure = errors.New("fault injection: dial failure")
+
+// defaultDropInterval は接続切断の判定間隔のデフォルト値。
+const defaultDropInterval = 10 * time.Second
+
+// Config はフォールトインジェクションの設定。
+type Config struct {
+	Enabled bool
+	// DropProbability は DropInterval ごとに各 SSH 接続を切断する確率（0.0〜1.0）。
+	DropProbability float64
+	DropInterval    time.Duration
+	// AcceptDelay はフォワードのリスナーが接続を受け付けた後に挿入する遅延。
+	AcceptDelay time.Duration
+	// DialFailureProbability は SSH ダイヤルを失敗させる確率（0.0〜1.0）。
+	DialFailureProbability float64
+}
+
+// Validate は設定値が有効範囲内かを検証する。
+func (c Config) Validate() error {
+	if c.DropProbability < 0 || c.DropProbability > 1 {
+		return fmt.Errorf("drop_probability must be between 0 and 1, got %v", c.DropProbability)
+	}
+	if c.DialFailureProbability < 0 || c.DialFailureProbability > 1 {
+		return fmt.Errorf("dial_failure_probability must be between 0 and 1, got %v", c.DialFailureProbability)
+	}
+	if c.DropInterval < 0 {
+		return fmt.Errorf("drop_interval must not be negative, got %v", c.DropInterval)
+	}
+	if c.AcceptDelay < 0 {
+		return fmt.Errorf("accept_delay must not be negative, got %v", c.AcceptDelay)
+	}
+	return nil
+}
+
+// Counters は注入した障害の累計回数。
+type Counters struct {
+	DroppedConnections int64
+	DelayedAccepts     int64
+	FailedDials        int64
+}
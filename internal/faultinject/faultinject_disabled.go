@@ -0,0 +1,29 @@
+//go:build !chaos
+
+package faultinject
+
+import (
+	"context"
+	"net"
+)
+
+// Available は障害注入がこのビルドで有効かを示す。
+const Available = false
+
+// Configure は通常ビルドでは常に ErrUnavailable を返す。
+func Configure(Config) error { return ErrUnavailable }
+
+// Current は通常ビルドでは常にゼロ値を返す。
+func Current() Config { return Config{} }
+
+// Stats は通常ビルドでは常にゼロ値を返す。
+func Stats() Counters { return Counters{} }
+
+// BeforeDial は通常ビルドでは何もしない。
+func BeforeDial(string) error { return nil }
+
+// WrapConn は通常ビルドでは conn をそのまま返す。
+func WrapConn(_ string, conn net.Conn) net.Conn { return conn }
+
+// DelayAccept は通常ビルドでは何もしない。
+func DelayAccept(context.Context) {}
@@ -0,0 +1,31 @@
+//go:build !chaos
+
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestDisabled_HooksAreNoop(t *testing.T) {
+	if err := Configure(Config{Enabled: true, DialFailureProbability: 1}); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Configure() error = %v, want ErrUnavailable", err)
+	}
+	if err := BeforeDial("host"); err != nil {
+		t.Errorf("BeforeDial() error = %v, want nil", err)
+	}
+
+	c1, c2 := net.Pipe()
+	defer func() { _ = c2.Close() }()
+	if got := WrapConn("host", c1); got != c1 {
+		t.Error("WrapConn() should return the original conn")
+	}
+	_ = c1.Close()
+
+	DelayAccept(context.Background())
+	if got := Stats(); got != (Counters{}) {
+		t.Errorf("Stats() = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,165 @@
+//go:build chaos
+
+package faultinject
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Available は障害注入がこのビルドで有効かを示す。
+const Available = true
+
+type injector struct {
+	mu     sync.Mutex
+	cfg    Config
+	conns  map[*trackedConn]struct{}
+	stopCh chan struct{}
+
+	dropped atomic.Int64
+	delayed atomic.Int64
+	failed  atomic.Int64
+}
+
+var global = &injector{conns: make(map[*trackedConn]struct{})}
+
+// randFloat は [0.0, 1.0) の乱数を返す。テスト時に差し替え可能にするため変数として定義する。
+var randFloat = rand.Float64 //nolint:gosec // テスト用の障害注入であり暗号学的強度は不要
+
+// Configure は障害注入の設定を適用する。
+// 接続切断が有効な場合は判定用のバックグラウンド goroutine を（再）起動する。
+func Configure(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	if cfg.DropInterval == 0 {
+		cfg.DropInterval = defaultDropInterval
+	}
+
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	if global.stopCh != nil {
+		close(global.stopCh)
+		global.stopCh = nil
+	}
+	global.cfg = cfg
+	if cfg.Enabled && cfg.DropProbability > 0 {
+		global.stopCh = make(chan struct{})
+		go global.dropLoop(cfg.DropInterval, global.stopCh)
+	}
+	slog.Warn("fault injection configured",
+		"enabled", cfg.Enabled,
+		"drop_probability", cfg.DropProbability,
+		"drop_interval", cfg.DropInterval,
+		"accept_delay", cfg.AcceptDelay,
+		"dial_failure_probability", cfg.DialFailureProbability)
+	return nil
+}
+
+// Current は現在の設定を返す。
+func Current() Config {
+	global.mu.Lock()
+	defer global.mu.Unlock()
+	return global.cfg
+}
+
+// Stats は注入した障害の累計回数を返す。
+func Stats() Counters {
+	return Counters{
+		DroppedConnections: global.dropped.Load(),
+		DelayedAccepts:     global.delayed.Load(),
+		FailedDials:        global.failed.Load(),
+	}
+}
+
+// BeforeDial は SSH ダイヤル前に呼び出し、設定された確率でダイヤル失敗を注入する。
+func BeforeDial(host string) error {
+	cfg := Current()
+	if !cfg.Enabled || cfg.DialFailureProbability <= 0 {
+		return nil
+	}
+	if randFloat() < cfg.DialFailureProbability {
+		global.failed.Add(1)
+		slog.Warn("fault injection: failing dial", "host", host)
+		return ErrInjectedDialFailure
+	}
+	return nil
+}
+
+// WrapConn は SSH 接続の下位コネクションを切断対象として登録する。
+// 返された net.Conn が Close されると登録は解除される。
+func WrapConn(host string, conn net.Conn) net.Conn {
+	tc := &trackedConn{Conn: conn, host: host}
+	global.mu.Lock()
+	global.conns[tc] = struct{}{}
+	global.mu.Unlock()
+	return tc
+}
+
+// DelayAccept はフォワードの接続受付後に設定された遅延を挿入する。
+// ctx がキャンセルされた場合は直ちに戻る。
+func DelayAccept(ctx context.Context) {
+	cfg := Current()
+	if !cfg.Enabled || cfg.AcceptDelay <= 0 {
+		return
+	}
+	global.delayed.Add(1)
+	t := time.NewTimer(cfg.AcceptDelay)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
+func (in *injector) dropLoop(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			in.dropRandom()
+		}
+	}
+}
+
+// dropRandom は登録済みの各接続を設定された確率で切断する。
+func (in *injector) dropRandom() {
+	in.mu.Lock()
+	p := in.cfg.DropProbability
+	var victims []*trackedConn
+	for tc := range in.conns {
+		if randFloat() < p {
+			victims = append(victims, tc)
+		}
+	}
+	in.mu.Unlock()
+
+	for _, tc := range victims {
+		in.dropped.Add(1)
+		slog.Warn("fault injection: dropping SSH connection", "host", tc.host)
+		_ = tc.Close()
+	}
+}
+
+type trackedConn struct {
+	net.Conn
+	host string
+	once sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		global.mu.Lock()
+		delete(global.conns, c)
+		global.mu.Unlock()
+	})
+	return c.Conn.Close()
+}
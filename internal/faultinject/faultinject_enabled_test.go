@@ -0,0 +1,98 @@
+//go:build chaos
+
+package faultinject
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// reset はテスト間でグローバル状態を初期化する。
+func reset(t *testing.T) {
+	t.Helper()
+	if err := Configure(Config{}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	global.dropped.Store(0)
+	global.delayed.Store(0)
+	global.failed.Store(0)
+	t.Cleanup(func() { _ = Configure(Config{}) })
+}
+
+func TestBeforeDial_InjectsFailure(t *testing.T) {
+	reset(t)
+	if err := Configure(Config{Enabled: true, DialFailureProbability: 1}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if err := BeforeDial("host"); !errors.Is(err, ErrInjectedDialFailure) {
+		t.Errorf("BeforeDial() error = %v, want ErrInjectedDialFailure", err)
+	}
+	if got := Stats().FailedDials; got != 1 {
+		t.Errorf("FailedDials = %d, want 1", got)
+	}
+}
+
+func TestBeforeDial_DisabledDoesNothing(t *testing.T) {
+	reset(t)
+	if err := Configure(Config{Enabled: false, DialFailureProbability: 1}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	if err := BeforeDial("host"); err != nil {
+		t.Errorf("BeforeDial() error = %v, want nil", err)
+	}
+}
+
+func TestDelayAccept(t *testing.T) {
+	reset(t)
+	if err := Configure(Config{Enabled: true, AcceptDelay: 20 * time.Millisecond}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	start := time.Now()
+	DelayAccept(context.Background())
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("DelayAccept() returned after %v, want >= 20ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Configure(Config{Enabled: true, AcceptDelay: time.Hour}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	DelayAccept(ctx)
+
+	if got := Stats().DelayedAccepts; got != 2 {
+		t.Errorf("DelayedAccepts = %d, want 2", got)
+	}
+}
+
+func TestDropLoop_ClosesWrappedConns(t *testing.T) {
+	reset(t)
+	c1, c2 := net.Pipe()
+	defer func() { _ = c2.Close() }()
+	conn := WrapConn("host", c1)
+
+	if err := Configure(Config{Enabled: true, DropProbability: 1, DropInterval: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for Stats().DroppedConnections == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if got := Stats().DroppedConnections; got != 1 {
+		t.Fatalf("DroppedConnections = %d, want 1", got)
+	}
+	if _, err := conn.Write([]byte("x")); err == nil {
+		t.Error("Write() on dropped conn should fail")
+	}
+
+	global.mu.Lock()
+	remaining := len(global.conns)
+	global.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("tracked conns = %d, want 0", remaining)
+	}
+}
@@ -0,0 +1,29 @@
+package faultinject
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"zero", Config{}, false},
+		{"valid", Config{Enabled: true, DropProbability: 0.5, DropInterval: time.Second, AcceptDelay: time.Millisecond, DialFailureProbability: 1}, false},
+		{"drop probability above 1", Config{DropProbability: 1.5}, true},
+		{"negative dial probability", Config{DialFailureProbability: -0.1}, true},
+		{"negative drop interval", Config{DropInterval: -time.Second}, true},
+		{"negative accept delay", Config{AcceptDelay: -time.Second}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package format
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+)
+
+// timeFormat は現在の時刻表示形式（core.TimeFormat* のいずれか）。
+var timeFormat atomic.Value
+
+func init() {
+	timeFormat.Store(core.TimeFormatRelative)
+}
+
+// nowFunc は現在時刻を返す。テスト時に差し替え可能にするため変数として定義する。
+var nowFunc = time.Now
+
+// ValidTimeFormat は ui.time_format の値として有効かを返す。
+// 空文字列はデフォルト（relative）として扱うため有効とする。
+func ValidTimeFormat(s string) bool {
+	switch s {
+	case "", core.TimeFormatRelative, core.TimeFormatLocal, core.TimeFormatUTC:
+		return true
+	}
+	return false
+}
+
+// SetTimeFormat は時刻表示形式を設定する。無効な値の場合は relative にフォールバックする。
+func SetTimeFormat(s string) {
+	if s == "" || !ValidTimeFormat(s) {
+		s = core.TimeFormatRelative
+	}
+	timeFormat.Store(s)
+}
+
+// TimeFormat は現在の時刻表示形式を返す。
+func TimeFormat() string {
+	return timeFormat.Load().(string)
+}
+
+// Duration は経過時間を "2h 15m" のような人間可読な文字列に変換する。
+// 上位2単位までを表示し、秒未満は切り捨てる。
+func Duration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	switch {
+	case d >= 24*time.Hour:
+		days := int(d.Hours()) / 24
+		hours := int(d.Hours()) % 24
+		return fmt.Sprintf("%dd %dh", days, hours)
+	case d >= time.Hour:
+		hours := int(d.Hours())
+		minutes := int(d.Minutes()) % 60
+		return fmt.Sprintf("%dh %dm", hours, minutes)
+	case d >= time.Minute:
+		minutes := int(d.Minutes())
+		seconds := int(d.Seconds()) % 60
+		return fmt.Sprintf("%dm %ds", minutes, seconds)
+	default:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	}
+}
+
+// DurationString は Go の Duration 文字列（"1h2m3.5s" 等）を Duration の形式に変換する。
+// パースできない場合は入力をそのまま返す。
+func DurationString(s string) string {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return s
+	}
+	return Duration(d)
+}
+
+// Time は現在の時刻表示形式に従って時刻を整形する。
+// relative では "3m ago"、local / utc ではロケールに応じた日時表記を返す。
+func Time(t time.Time) string {
+	return TimeAs(t, TimeFormat())
+}
+
+// TimeAs は指定した表示形式で時刻を整形する。
+func TimeAs(t time.Time, style string) string {
+	if t.IsZero() {
+		return ""
+	}
+	switch style {
+	case core.TimeFormatUTC:
+		return t.UTC().Format(i18n.T("format.date_layout")) + " UTC"
+	case core.TimeFormatLocal:
+		return t.Local().Format(i18n.T("format.date_layout"))
+	default:
+		return i18n.T("format.ago", map[string]any{"Duration": Duration(nowFunc().Sub(t))})
+	}
+}
+
+// TimeString は RFC3339 形式の時刻文字列を Time の形式に変換する。
+// パースできない場合は入力をそのまま返す。
+func TimeString(s string) string {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return s
+	}
+	return Time(t)
+}
+
+// Clock は現在の時刻表示形式に従って時刻のみ（"15:04:05"）を整形する。
+// utc の場合は UTC、それ以外はローカルタイムゾーンで表示する。
+func Clock(t time.Time) string {
+	if TimeFormat() == core.TimeFormatUTC {
+		return t.UTC().Format(time.TimeOnly) + "Z"
+	}
+	return t.Local().Format(time.TimeOnly)
+}
@@ -0,0 +1,91 @@
+package format
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestDuration(t *testing.T) {
+	tests := []struct {
+		input time.Duration
+		want  string
+	}{
+		{-5 * time.Second, "0s"},
+		{0, "0s"},
+		{45 * time.Second, "45s"},
+		{90 * time.Second, "1m 30s"},
+		{2*time.Hour + 15*time.Minute + 30*time.Second, "2h 15m"},
+		{50 * time.Hour, "2d 2h"},
+	}
+	for _, tt := range tests {
+		if got := Duration(tt.input); got != tt.want {
+			t.Errorf("Duration(%v) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestDurationString(t *testing.T) {
+	if got := DurationString("1h2m3.5s"); got != "1h 2m" {
+		t.Errorf("DurationString = %q, want %q", got, "1h 2m")
+	}
+	if got := DurationString("bogus"); got != "bogus" {
+		t.Errorf("DurationString(bogus) = %q, want passthrough", got)
+	}
+}
+
+func TestTimeAs(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	origNow := nowFunc
+	nowFunc = func() time.Time { return now }
+	t.Cleanup(func() { nowFunc = origNow })
+
+	ts := now.Add(-3 * time.Minute)
+
+	if got := TimeAs(ts, core.TimeFormatRelative); got != "3m 0s ago" {
+		t.Errorf("relative = %q, want %q", got, "3m 0s ago")
+	}
+	if got := TimeAs(ts, core.TimeFormatUTC); got != "Jun 1, 2025 11:57:00 UTC" {
+		t.Errorf("utc = %q, want %q", got, "Jun 1, 2025 11:57:00 UTC")
+	}
+	if got := TimeAs(time.Time{}, core.TimeFormatRelative); got != "" {
+		t.Errorf("zero time = %q, want empty", got)
+	}
+}
+
+func TestTimeString(t *testing.T) {
+	t.Cleanup(func() { SetTimeFormat(core.TimeFormatRelative) })
+	SetTimeFormat(core.TimeFormatUTC)
+
+	if got := TimeString("2025-06-01T21:00:00+09:00"); got != "Jun 1, 2025 12:00:00 UTC" {
+		t.Errorf("TimeString = %q, want %q", got, "Jun 1, 2025 12:00:00 UTC")
+	}
+	if got := TimeString("not-a-time"); got != "not-a-time" {
+		t.Errorf("TimeString(invalid) = %q, want passthrough", got)
+	}
+}
+
+func TestSetTimeFormat(t *testing.T) {
+	t.Cleanup(func() { SetTimeFormat(core.TimeFormatRelative) })
+
+	SetTimeFormat(core.TimeFormatLocal)
+	if got := TimeFormat(); got != core.TimeFormatLocal {
+		t.Errorf("TimeFormat() = %q, want %q", got, core.TimeFormatLocal)
+	}
+	SetTimeFormat("unknown")
+	if got := TimeFormat(); got != core.TimeFormatRelative {
+		t.Errorf("TimeFormat() after invalid = %q, want %q", got, core.TimeFormatRelative)
+	}
+}
+
+func TestValidTimeFormat(t *testing.T) {
+	for _, s := range []string{"", "relative", "local", "utc"} {
+		if !ValidTimeFormat(s) {
+			t.Errorf("ValidTimeFormat(%q) = false, want true", s)
+		}
+	}
+	if ValidTimeFormat("iso") {
+		t.Error("ValidTimeFormat(iso) = true, want false")
+	}
+}
@@ -12,7 +12,7 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-//go:embed locales/*.yaml
+//go:embed locales/*/*.yaml
 var localeFS embed.FS
 
 // Lang は対応言語を表す型。
@@ -67,19 +67,11 @@ func init() {
 
 // SetLang は現在の言語を設定し、翻訳データをロードする。
 func SetLang(lang Lang) error {
-	data, err := localeFS.ReadFile(fmt.Sprintf("locales/%s.yaml", string(lang)))
+	messages, err := loadMessages(lang)
 	if err != nil {
-		return fmt.Errorf("i18n: unsupported language %q: %w", lang, err)
+		return err
 	}
 
-	var raw map[string]any
-	if err := yaml.Unmarshal(data, &raw); err != nil {
-		return fmt.Errorf("i18n: failed to parse %s.yaml: %w", lang, err)
-	}
-
-	messages := make(map[string]string)
-	flattenYAML(raw, "", messages)
-
 	global.mu.Lock()
 	defer global.mu.Unlock()
 	global.lang = lang
@@ -88,6 +80,29 @@ func SetLang(lang Lang) error {
 	return nil
 }
 
+// loadMessages は locales/<lang>/ 配下の YAML をすべて読み込み、フラットなキーに展開する。
+func loadMessages(lang Lang) (map[string]string, error) {
+	dir := "locales/" + string(lang)
+	entries, err := localeFS.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("i18n: unsupported language %q: %w", lang, err)
+	}
+
+	messages := make(map[string]string)
+	for _, e := range entries {
+		data, err := localeFS.ReadFile(dir + "/" + e.Name())
+		if err != nil {
+			return nil, fmt.Errorf("i18n: failed to read %s/%s: %w", lang, e.Name(), err)
+		}
+		var raw map[string]any
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("i18n: failed to parse %s/%s: %w", lang, e.Name(), err)
+		}
+		flattenYAML(raw, "", messages)
+	}
+	return messages, nil
+}
+
 // CurrentLang は現在設定されている言語を返す。
 func CurrentLang() Lang {
 	global.mu.RLock()
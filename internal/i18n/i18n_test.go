@@ -3,8 +3,6 @@ package i18n
 import (
 	"strings"
 	"testing"
-
-	"gopkg.in/yaml.v3"
 )
 
 func resetLang(t *testing.T) {
@@ -189,20 +187,14 @@ func TestFlattenYAML_WithPrefix(t *testing.T) {
 }
 
 func TestLocaleKeyConsistency(t *testing.T) {
-	// ja.yaml と en.yaml のキーが一致することを確認する。
+	// locales/ja と locales/en のキーが一致することを確認する。
 	// 翻訳漏れがあればこのテストが失敗する。
 	loadKeys := func(lang Lang) map[string]string {
 		t.Helper()
-		data, err := localeFS.ReadFile("locales/" + string(lang) + ".yaml")
+		m, err := loadMessages(lang)
 		if err != nil {
-			t.Fatalf("failed to read %s.yaml: %v", lang, err)
-		}
-		var raw map[string]any
-		if err := yaml.Unmarshal(data, &raw); err != nil {
-			t.Fatalf("failed to parse %s.yaml: %v", lang, err)
+			t.Fatalf("failed to load %s: %v", lang, err)
 		}
-		m := make(map[string]string)
-		flattenYAML(raw, "", m)
 		return m
 	}
 
@@ -211,12 +203,12 @@ func TestLocaleKeyConsistency(t *testing.T) {
 
 	for k := range enKeys {
 		if _, ok := jaKeys[k]; !ok {
-			t.Errorf("key %q exists in en.yaml but missing in ja.yaml", k)
+			t.Errorf("key %q exists in en but missing in ja", k)
 		}
 	}
 	for k := range jaKeys {
 		if _, ok := enKeys[k]; !ok {
-			t.Errorf("key %q exists in ja.yaml but missing in en.yaml", k)
+			t.Errorf("key %q exists in ja but missing in en", k)
 		}
 	}
 }
@@ -0,0 +1,70 @@
+package accesslog
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// DirName はルールごとのアクセスログファイルを置く、設定ディレクトリ内のディレクトリ名。
+const DirName = "access"
+
+// Writer は core.AccessLogger の実装。
+// AccessLogFile のルールは dir/<ルール名>.log に 1 行 1 レコードの JSON で追記し、
+// AccessLogSlog のルールはデーモンのログに logger=access の属性付きで出力する。
+type Writer struct {
+	dir string
+	mu  sync.Mutex // ファイルへの追記を直列化する
+}
+
+// New は dir にルールごとのファイルを書き込む Writer を返す。dir は最初の書き込み時に作成する。
+func New(dir string) *Writer {
+	return &Writer{dir: dir}
+}
+
+// LogAccess は rule.AccessLog の出力先に rec を書き込む。書き込みに失敗した場合は警告をログに残す。
+func (w *Writer) LogAccess(rule core.ForwardRule, rec core.AccessRecord) {
+	switch rule.AccessLog {
+	case core.AccessLogFile:
+		if err := w.append(rule.Name, rec); err != nil {
+			slog.Warn("failed to write access log", "rule", rule.Name, "error", err)
+		}
+	case core.AccessLogSlog:
+		slog.Info("forward access", "logger", "access", "rule", rec.Rule, "host", rec.Host, "type", rec.Type, "client", rec.Client,
+			"target", rec.Target, "duration", time.Duration(rec.Duration*float64(time.Millisecond)),
+			"bytes_sent", rec.Sent, "bytes_received", rec.Received, "error", rec.Error)
+	}
+}
+
+// Path は rule のアクセスログファイルのパスを返す。
+func (w *Writer) Path(rule string) string {
+	return filepath.Join(w.dir, rule+".log")
+}
+
+func (w *Writer) append(rule string, rec core.AccessRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := os.MkdirAll(w.dir, 0700); err != nil {
+		return fmt.Errorf("create access log dir: %w", err)
+	}
+	f, err := os.OpenFile(w.Path(rule), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,41 @@
+package accesslog
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestWriter_LogAccess_File(t *testing.T) {
+	w := New(filepath.Join(t.TempDir(), DirName))
+	rule := core.ForwardRule{Name: "socks", AccessLog: core.AccessLogFile}
+	for _, target := range []string{"db.internal:5432", "web.internal:80"} {
+		w.LogAccess(rule, core.AccessRecord{Time: time.Now(), Rule: "socks", Client: "127.0.0.1:50000", Target: target, Sent: 10})
+	}
+	w.LogAccess(core.ForwardRule{Name: "quiet"}, core.AccessRecord{Rule: "quiet"})
+
+	f, err := os.Open(w.Path("socks"))
+	if err != nil {
+		t.Fatalf("open access log: %v", err)
+	}
+	defer func() { _ = f.Close() }()
+	var targets []string
+	for scanner := bufio.NewScanner(f); scanner.Scan(); {
+		var rec core.AccessRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line %q is not JSON: %v", scanner.Text(), err)
+		}
+		targets = append(targets, rec.Target)
+	}
+	if len(targets) != 2 || targets[0] != "db.internal:5432" || targets[1] != "web.internal:80" {
+		t.Errorf("targets = %v, want both records in order", targets)
+	}
+	if _, err := os.Stat(w.Path("quiet")); !os.IsNotExist(err) {
+		t.Errorf("rule without access_log should not create a file (stat error = %v)", err)
+	}
+}
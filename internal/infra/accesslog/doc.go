@@ -0,0 +1,2 @@
+// Package accesslog はフォワードを通過した接続のアクセスログを、ルールごとのファイルまたはデーモンのログに書き込む。
+package accesslog
@@ -0,0 +1,117 @@
+package credprovider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/infra/secretstore"
+)
+
+// errNotFound は取得元に値が存在しないことを示す。
+var errNotFound = errors.New("credential not found")
+
+// run はコマンドを実行して標準出力を返す。テストで差し替える。
+var run = func(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output() //nolint:gosec // 取得元のコマンドはユーザー設定値
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 {
+		return "", errNotFound
+	}
+	return string(out), err
+}
+
+// provider は CredentialSource の設定に従って値を取得する core.CredentialProvider。
+type provider struct {
+	src    core.CredentialSource
+	lookup func(ctx context.Context, ref string) (string, error)
+}
+
+// New は src の Provider に対応する core.CredentialProvider を返す。
+func New(src core.CredentialSource) (core.CredentialProvider, error) {
+	p := &provider{src: src}
+	switch src.Provider {
+	case core.CredentialProviderEnv:
+		p.lookup = lookupEnv
+	case core.CredentialProviderKeychain:
+		p.lookup = lookupKeychain
+	case core.CredentialProviderExec:
+		p.lookup = lookupExec
+	default:
+		return nil, fmt.Errorf("unknown credential provider %q", src.Provider)
+	}
+	return p, nil
+}
+
+// Lookup は req の種別に対応する参照から値を取得する。参照が空か値が存在しない場合は ok に false を返す。
+func (p *provider) Lookup(req core.CredentialRequest) (string, bool, error) {
+	ref := p.src.Ref(req.Type)
+	if ref == "" {
+		return "", false, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), core.CredentialTimeout)
+	defer cancel()
+	value, err := p.lookup(ctx, ref)
+	if errors.Is(err, errNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("%s credential provider: %w", p.src.Provider, err)
+	}
+	return strings.TrimRight(value, "\r\n"), true, nil
+}
+
+// lookupEnv は環境変数 ref の値を返す。
+func lookupEnv(_ context.Context, ref string) (string, error) {
+	if v, ok := os.LookupEnv(ref); ok {
+		return v, nil
+	}
+	return "", errNotFound
+}
+
+// lookupExec はシェル（Windows では cmd）経由でコマンド ref を実行し、標準出力を返す。
+func lookupExec(ctx context.Context, ref string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return run(ctx, "cmd", "/C", ref)
+	}
+	return run(ctx, "sh", "-c", ref)
+}
+
+// keychain は keychain 取得元が参照する SecretStore。テストで差し替える。
+var keychain = secretstore.New
+
+// lookupKeychain は OS のキーチェーン（サービス名 secretstore.Service）からアカウント ref のシークレットを返す。
+func lookupKeychain(_ context.Context, ref string) (string, error) {
+	v, err := keychain().Get(ref)
+	if errors.Is(err, secretstore.ErrNotFound) {
+		return "", errNotFound
+	}
+	return v, err
+}
+
+// Wrap は password/passphrase の要求に対して p を先に参照し、値がない場合は next に委ねるコールバックを返す。
+// 取得に失敗した場合は警告を記録して next に委ねる。next が nil で値がない場合はエラーを返す。
+func Wrap(p core.CredentialProvider, next core.CredentialCallback) core.CredentialCallback {
+	return func(req core.CredentialRequest) (core.CredentialResponse, error) {
+		value, ok, err := p.Lookup(req)
+		if err != nil {
+			slog.Warn("credential provider lookup failed", "host", req.Host, "type", req.Type, "error", err)
+		}
+		if ok {
+			return core.CredentialResponse{RequestID: req.RequestID, Value: value}, nil
+		}
+		if next == nil {
+			if err == nil {
+				err = fmt.Errorf("no %s available for %s", req.Type, req.Host)
+			}
+			return core.CredentialResponse{}, err
+		}
+		return next(req)
+	}
+}
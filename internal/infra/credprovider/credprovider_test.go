@@ -0,0 +1,106 @@
+package credprovider
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/infra/secretstore"
+)
+
+func TestNew_UnknownProvider(t *testing.T) {
+	if _, err := New(core.CredentialSource{Provider: "vault"}); err == nil {
+		t.Error("New() should reject an unknown provider")
+	}
+}
+
+func TestProvider_Env(t *testing.T) {
+	t.Setenv("MOLEPORT_TEST_PASSWORD", "s3cret")
+	p, err := New(core.CredentialSource{Provider: core.CredentialProviderEnv, Password: "MOLEPORT_TEST_PASSWORD", Passphrase: "MOLEPORT_TEST_UNSET"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	tests := []struct {
+		typ    core.CredentialType
+		want   string
+		wantOK bool
+	}{
+		{core.CredentialPassword, "s3cret", true},
+		{core.CredentialPassphrase, "", false},
+		{core.CredentialKeyboardInteractive, "", false},
+	}
+	for _, tt := range tests {
+		got, ok, err := p.Lookup(core.CredentialRequest{Type: tt.typ, Host: "prod"})
+		if err != nil || got != tt.want || ok != tt.wantOK {
+			t.Errorf("Lookup(%s) = (%q, %v, %v), want (%q, %v, nil)", tt.typ, got, ok, err, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestProvider_Exec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("exec provider uses sh")
+	}
+	p, _ := New(core.CredentialSource{Provider: core.CredentialProviderExec, Password: "printf 'from-cmd\\n'", Passphrase: "exit 3"})
+	if got, ok, err := p.Lookup(core.CredentialRequest{Type: core.CredentialPassword}); err != nil || !ok || got != "from-cmd" {
+		t.Errorf("Lookup(password) = (%q, %v, %v), want (from-cmd, true, nil)", got, ok, err)
+	}
+	if _, ok, err := p.Lookup(core.CredentialRequest{Type: core.CredentialPassphrase}); err != nil || ok {
+		t.Errorf("failing command without output should be a miss, got ok=%v err=%v", ok, err)
+	}
+}
+
+func TestProvider_Keychain(t *testing.T) {
+	store := secretstore.NewMemory()
+	_ = store.Set("prod-key", "from-keychain")
+	useKeychain(t, store)
+
+	p, _ := New(core.CredentialSource{Provider: core.CredentialProviderKeychain, Password: "missing", Passphrase: "prod-key"})
+	if got, ok, err := p.Lookup(core.CredentialRequest{Type: core.CredentialPassphrase}); err != nil || !ok || got != "from-keychain" {
+		t.Errorf("Lookup(passphrase) = (%q, %v, %v), want (from-keychain, true, nil)", got, ok, err)
+	}
+	if _, ok, err := p.Lookup(core.CredentialRequest{Type: core.CredentialPassword}); err != nil || ok {
+		t.Errorf("missing account should be a miss, got ok=%v err=%v", ok, err)
+	}
+}
+
+// useKeychain はテストの間 keychain 取得元が s を参照するようにする。
+func useKeychain(t *testing.T, s secretstore.SecretStore) {
+	t.Helper()
+	orig := keychain
+	t.Cleanup(func() { keychain = orig })
+	keychain = func() secretstore.SecretStore { return s }
+}
+
+// failingStore は常にエラーを返す SecretStore。
+type failingStore struct{ secretstore.SecretStore }
+
+func (failingStore) Get(string) (string, error) { return "", errors.New("locked") }
+
+func TestWrap(t *testing.T) {
+	t.Setenv("MOLEPORT_TEST_PASSWORD", "s3cret")
+	p, _ := New(core.CredentialSource{Provider: core.CredentialProviderEnv, Password: "MOLEPORT_TEST_PASSWORD"})
+	asked := 0
+	next := func(core.CredentialRequest) (core.CredentialResponse, error) {
+		asked++
+		return core.CredentialResponse{Value: "typed"}, nil
+	}
+
+	cb := Wrap(p, next)
+	if resp, err := cb(core.CredentialRequest{Type: core.CredentialPassword}); err != nil || resp.Value != "s3cret" || asked != 0 {
+		t.Errorf("password = (%+v, %v), asked %d; want provider value without asking", resp, err, asked)
+	}
+	if resp, err := cb(core.CredentialRequest{Type: core.CredentialPassphrase}); err != nil || resp.Value != "typed" || asked != 1 {
+		t.Errorf("passphrase = (%+v, %v), asked %d; want fallback to next", resp, err, asked)
+	}
+
+	p, _ = New(core.CredentialSource{Provider: core.CredentialProviderKeychain, Password: "acct"})
+	useKeychain(t, failingStore{})
+	if _, err := Wrap(p, nil)(core.CredentialRequest{Type: core.CredentialPassword}); err == nil {
+		t.Error("Wrap() without next should return an error when the provider fails")
+	}
+	if resp, err := Wrap(p, next)(core.CredentialRequest{Type: core.CredentialPassword}); err != nil || resp.Value != "typed" {
+		t.Errorf("provider failure should fall back to next, got (%+v, %v)", resp, err)
+	}
+}
@@ -0,0 +1,3 @@
+// Package credprovider は環境変数・OS のキーチェーン・外部コマンドからパスワードやパスフレーズを取得する
+// core.CredentialProvider と、ユーザーへの問い合わせより先にそれを参照するコールバックを提供する。
+package credprovider
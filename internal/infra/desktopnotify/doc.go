@@ -0,0 +1,2 @@
+// Package desktopnotify は SSH 接続・フォワードのエラーと切断を OS のデスクトップ通知で知らせる。
+package desktopnotify
@@ -0,0 +1,125 @@
+package desktopnotify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/notifyconf"
+)
+
+const (
+	queueSize   = 16
+	sendTimeout = 10 * time.Second
+	// drainTimeout は Close 時にキュー内の通知を表示し終えるまで待つ上限。
+	drainTimeout = 5 * time.Second
+)
+
+// Message はデスクトップ通知の内容。
+type Message struct {
+	Event string
+	Title string
+	Body  string
+}
+
+// Notifier はイベントをキューに積み、単一のゴルーチンで順に OS の通知機能で表示する。
+type Notifier struct {
+	config func() notifyconf.Config
+	send   func(ctx context.Context, msg Message) error
+	queue  chan Message
+	done   chan struct{}
+}
+
+// New は Notifier を生成し、通知ゴルーチンを開始する。config は通知のたびに呼ばれ、設定の変更を反映する。
+func New(config func() notifyconf.Config) *Notifier {
+	n := &Notifier{config: config, send: send, queue: make(chan Message, queueSize), done: make(chan struct{})}
+	go n.run()
+	return n
+}
+
+// HandleSSHEvent は SSH 接続のエラーと切断を通知する。
+func (n *Notifier) HandleSSHEvent(evt core.SSHEvent) {
+	if msg, ok := FromSSHEvent(evt); ok {
+		n.publish(msg)
+	}
+}
+
+// HandleForwardEvent はフォワードのエラーを通知する。
+func (n *Notifier) HandleForwardEvent(evt core.ForwardEvent) {
+	if msg, ok := FromForwardEvent(evt); ok {
+		n.publish(msg)
+	}
+}
+
+// Close はキューを閉じ、残りの通知を drainTimeout まで表示してから通知ゴルーチンを停止する。
+// nil の Notifier では何もしない。
+func (n *Notifier) Close() {
+	if n == nil {
+		return
+	}
+	close(n.queue)
+	select {
+	case <-n.done:
+	case <-time.After(drainTimeout):
+		slog.Warn("desktop notifications not sent before shutdown", "pending", len(n.queue))
+	}
+}
+
+// publish は設定で通知の対象とするイベントをキューに積む。nil の Notifier やキューが満杯の場合は破棄する。
+func (n *Notifier) publish(msg Message) {
+	if n == nil || !n.config().Wants(msg.Event) {
+		return
+	}
+	select {
+	case n.queue <- msg:
+	default:
+		slog.Warn("desktop notification queue full, event dropped", "event", msg.Event)
+	}
+}
+
+func (n *Notifier) run() {
+	defer close(n.done)
+	for msg := range n.queue {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		if err := n.send(ctx, msg); err != nil {
+			slog.Warn("failed to show desktop notification", "event", msg.Event, "error", err)
+		}
+		cancel()
+	}
+}
+
+// FromSSHEvent は SSH イベントを通知に変換する。通知の対象にできない種別の場合は false を返す。
+func FromSSHEvent(evt core.SSHEvent) (Message, bool) {
+	msg := Message{Title: "MolePort: " + evt.HostName}
+	switch evt.Type {
+	case core.SSHEventError:
+		msg.Event, msg.Body = notifyconf.EventSSHError, withError("SSH connection failed", evt.Error)
+	case core.SSHEventDisconnected:
+		msg.Event, msg.Body = notifyconf.EventSSHDisconnected, withError("SSH connection lost", evt.Error)
+	default:
+		return Message{}, false
+	}
+	return msg, true
+}
+
+// FromForwardEvent はフォワードイベントを通知に変換する。通知の対象にできない種別の場合は false を返す。
+func FromForwardEvent(evt core.ForwardEvent) (Message, bool) {
+	if evt.Type != core.ForwardEventError {
+		return Message{}, false
+	}
+	return Message{
+		Event: notifyconf.EventForwardError,
+		Title: "MolePort: " + evt.RuleName,
+		Body:  withError(fmt.Sprintf("Forward %s failed", evt.RuleName), evt.Error),
+	}, true
+}
+
+// withError は err がある場合に text の後にエラーの内容を付けて返す。
+func withError(text string, err error) string {
+	if err == nil {
+		return text
+	}
+	return text + ": " + err.Error()
+}
@@ -0,0 +1,63 @@
+package desktopnotify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/notifyconf"
+)
+
+func TestFromSSHEvent(t *testing.T) {
+	msg, ok := FromSSHEvent(core.SSHEvent{Type: core.SSHEventDisconnected, HostName: "prod", Error: errors.New("EOF")})
+	if !ok || msg.Event != notifyconf.EventSSHDisconnected || msg.Title != "MolePort: prod" || msg.Body != "SSH connection lost: EOF" {
+		t.Errorf("FromSSHEvent(disconnected) = %+v, %v", msg, ok)
+	}
+	if msg, ok := FromSSHEvent(core.SSHEvent{Type: core.SSHEventError, HostName: "prod"}); !ok || msg.Event != notifyconf.EventSSHError {
+		t.Errorf("FromSSHEvent(error) = %+v, %v", msg, ok)
+	}
+	if _, ok := FromSSHEvent(core.SSHEvent{Type: core.SSHEventConnected, HostName: "prod"}); ok {
+		t.Error("FromSSHEvent(connected) should not notify")
+	}
+}
+
+func TestFromForwardEvent(t *testing.T) {
+	msg, ok := FromForwardEvent(core.ForwardEvent{Type: core.ForwardEventError, RuleName: "web", Error: errors.New("listen failed")})
+	if !ok || msg.Event != notifyconf.EventForwardError || msg.Body != "Forward web failed: listen failed" {
+		t.Errorf("FromForwardEvent(error) = %+v, %v", msg, ok)
+	}
+	if _, ok := FromForwardEvent(core.ForwardEvent{Type: core.ForwardEventStopped, RuleName: "web"}); ok {
+		t.Error("FromForwardEvent(stopped) should not notify")
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	var mu sync.Mutex
+	var sent []string
+	n := &Notifier{
+		config: func() notifyconf.Config {
+			return notifyconf.Config{Enabled: true, Events: []string{notifyconf.EventForwardError}}
+		},
+		send: func(_ context.Context, msg Message) error {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, msg.Event)
+			return errors.New("notify-send not found")
+		},
+		queue: make(chan Message, queueSize),
+		done:  make(chan struct{}),
+	}
+	go n.run()
+
+	n.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventDisconnected, HostName: "prod"})
+	n.HandleForwardEvent(core.ForwardEvent{Type: core.ForwardEventError, RuleName: "web"})
+	n.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(sent) != 1 || sent[0] != notifyconf.EventForwardError {
+		t.Errorf("sent = %v, want only forward.error", sent)
+	}
+}
@@ -0,0 +1,45 @@
+package desktopnotify
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// 通知のタイトルと本文は、スクリプトへの埋め込みによる解釈を避けるため環境変数で渡す。
+const (
+	envTitle = "MOLEPORT_NOTIFY_TITLE"
+	envBody  = "MOLEPORT_NOTIFY_BODY"
+)
+
+// appleScript は macOS の通知センターに通知を表示する AppleScript。
+const appleScript = `display notification (system attribute "` + envBody + `") with title (system attribute "` + envTitle + `")`
+
+// toastScript は Windows のトースト通知を表示する PowerShell スクリプト。
+const toastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:` + envTitle + `)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:` + envBody + `)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('MolePort').Show([Windows.UI.Notifications.ToastNotification]::new($xml))`
+
+// send は OS の通知機能（macOS は osascript、Windows は PowerShell のトースト、それ以外は notify-send）で msg を表示する。
+func send(ctx context.Context, msg Message) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript", "-e", appleScript)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", toastScript)
+	default:
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name=MolePort", "--", msg.Title, msg.Body)
+	}
+	cmd.Env = append(os.Environ(), envTitle+"="+msg.Title, envBody+"="+msg.Body)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %w: %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
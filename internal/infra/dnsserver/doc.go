@@ -0,0 +1,3 @@
+// Package dnsserver はアクティブなフォワードのルール名を <rule>.moleport. として解決する小さな DNS リゾルバーを提供する。
+// 依存を増やさないため、必要最小限の DNS メッセージ（RFC 1035 / RFC 2782）のみを自前で解析・生成する。
+package dnsserver
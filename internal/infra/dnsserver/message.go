@@ -0,0 +1,155 @@
+package dnsserver
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// DNS レコード種別とクラス。
+const (
+	typeA   uint16 = 1
+	typeTXT uint16 = 16
+	typeSRV uint16 = 33
+	typeANY uint16 = 255
+
+	classIN uint16 = 1
+)
+
+// 応答コード。
+const (
+	rcodeSuccess  = 0
+	rcodeFormErr  = 1
+	rcodeNXDomain = 3
+	rcodeNotImp   = 4
+	rcodeRefused  = 5
+)
+
+const (
+	headerLen = 12
+	// recordTTL は応答レコードの TTL（秒）。フォワードの開始・停止をすぐ反映できるよう短くする。
+	recordTTL = 5
+	// namePointer は質問セクションの QNAME（オフセット 12）を指す圧縮ポインタ。
+	namePointer = 0xC000 | headerLen
+)
+
+var errMalformed = errors.New("malformed dns message")
+
+// query は受信した DNS クエリ。
+type query struct {
+	id       uint16
+	flags    uint16
+	name     string // 小文字化した FQDN（末尾 "." 付き）
+	qtype    uint16
+	qclass   uint16
+	question []byte // 応答にそのまま複製する質問セクション
+}
+
+// parseQuery は msg から 1 件の質問を持つクエリを解析する。
+func parseQuery(msg []byte) (*query, error) {
+	if len(msg) < headerLen {
+		return nil, errMalformed
+	}
+	q := &query{
+		id:    binary.BigEndian.Uint16(msg[0:2]),
+		flags: binary.BigEndian.Uint16(msg[2:4]),
+	}
+	if binary.BigEndian.Uint16(msg[4:6]) != 1 {
+		return q, fmt.Errorf("%w: expected exactly one question", errMalformed)
+	}
+
+	var labels []string
+	off := headerLen
+	for {
+		if off >= len(msg) {
+			return q, errMalformed
+		}
+		n := int(msg[off])
+		off++
+		if n == 0 {
+			break
+		}
+		// クエリの QNAME に圧縮ポインタは現れないため、ラベル以外は不正とする
+		if n > 63 || off+n > len(msg) {
+			return q, errMalformed
+		}
+		labels = append(labels, string(msg[off:off+n]))
+		off += n
+	}
+	if off+4 > len(msg) {
+		return q, errMalformed
+	}
+	q.qtype = binary.BigEndian.Uint16(msg[off : off+2])
+	q.qclass = binary.BigEndian.Uint16(msg[off+2 : off+4])
+	q.question = msg[headerLen : off+4]
+	q.name = strings.ToLower(strings.Join(labels, ".")) + "."
+	return q, nil
+}
+
+// record は応答に含めるリソースレコード。name が nil の場合は質問の QNAME への圧縮ポインタを使う。
+type record struct {
+	name  []byte
+	rtype uint16
+	data  []byte
+}
+
+// buildResponse は q への応答メッセージを組み立てる。
+func buildResponse(q *query, rcode int, answers, additional []record) []byte {
+	// QR=1, AA=1 とし、Opcode と RD はクエリから引き継ぐ
+	flags := uint16(0x8000|0x0400) | q.flags&0x7900 | uint16(rcode)
+	msg := make([]byte, headerLen, 512)
+	binary.BigEndian.PutUint16(msg[0:2], q.id)
+	binary.BigEndian.PutUint16(msg[2:4], flags)
+	if q.question != nil {
+		binary.BigEndian.PutUint16(msg[4:6], 1)
+		msg = append(msg, q.question...)
+	}
+	binary.BigEndian.PutUint16(msg[6:8], uint16(len(answers)))
+	binary.BigEndian.PutUint16(msg[10:12], uint16(len(additional)))
+	for _, r := range answers {
+		msg = appendRecord(msg, r)
+	}
+	for _, r := range additional {
+		msg = appendRecord(msg, r)
+	}
+	return msg
+}
+
+func appendRecord(msg []byte, r record) []byte {
+	if r.name == nil {
+		msg = binary.BigEndian.AppendUint16(msg, namePointer)
+	} else {
+		msg = append(msg, r.name...)
+	}
+	msg = binary.BigEndian.AppendUint16(msg, r.rtype)
+	msg = binary.BigEndian.AppendUint16(msg, classIN)
+	msg = binary.BigEndian.AppendUint32(msg, recordTTL)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(r.data)))
+	return append(msg, r.data...)
+}
+
+// encodeName は FQDN をラベル形式にエンコードする。
+func encodeName(fqdn string) []byte {
+	var b []byte
+	for _, label := range strings.Split(strings.TrimSuffix(fqdn, "."), ".") {
+		if label == "" {
+			continue
+		}
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+// srvData は SRV レコードの RDATA（priority=0, weight=0）を返す。
+func srvData(port int, target string) []byte {
+	b := make([]byte, 6, 6+len(target)+2)
+	binary.BigEndian.PutUint16(b[4:6], uint16(port))
+	return append(b, encodeName(target)...)
+}
+
+// txtData は TXT レコードの RDATA（単一の文字列）を返す。
+func txtData(s string) []byte {
+	return append([]byte{byte(len(s))}, s...)
+}
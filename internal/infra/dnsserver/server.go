@@ -0,0 +1,149 @@
+package dnsserver
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// Domain はフォワードの名前を提供するゾーン。
+const Domain = "moleport."
+
+// maxUDPSize は受信する UDP メッセージの最大長。
+const maxUDPSize = 4096
+
+// localhostIPv4 は A レコードで返すアドレス。
+var localhostIPv4 = []byte{127, 0, 0, 1}
+
+// Lookup はルール名（小文字）に対応するアクティブなフォワードのローカルポートを返す。
+// 該当するフォワードがない場合は ok=false を返す。
+type Lookup func(rule string) (port int, ok bool)
+
+// SessionLookup は sessions のうちルール名（大文字小文字を区別しない）が一致するアクティブなフォワードのポートを返す Lookup を生成する。
+// リモートフォワードはローカルでリッスンしないため対象外とする。
+func SessionLookup(sessions func() []core.ForwardSession) Lookup {
+	return func(rule string) (int, bool) {
+		for _, s := range sessions() {
+			if s.Status == core.Active && s.Rule.Type != core.Remote && strings.EqualFold(s.Rule.Name, rule) {
+				return s.ListenPort(), true
+			}
+		}
+		return 0, false
+	}
+}
+
+// Server は UDP で DNS クエリに応答するリゾルバー。
+type Server struct {
+	conn   net.PacketConn
+	lookup Lookup
+	done   chan struct{}
+}
+
+// Listen は addr で UDP をリッスンし、応答ゴルーチンを開始する。
+func Listen(addr string, lookup Lookup) (*Server, error) {
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen dns on %s: %w", addr, err)
+	}
+	s := &Server{conn: conn, lookup: lookup, done: make(chan struct{})}
+	go s.serve()
+	return s, nil
+}
+
+// Addr はリッスン中のアドレスを返す。
+func (s *Server) Addr() net.Addr {
+	return s.conn.LocalAddr()
+}
+
+// Close はリッスンを停止し、応答ゴルーチンの終了を待つ。
+func (s *Server) Close() error {
+	err := s.conn.Close()
+	<-s.done
+	return err
+}
+
+func (s *Server) serve() {
+	defer close(s.done)
+	buf := make([]byte, maxUDPSize)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Warn("dns read failed", "error", err)
+			}
+			return
+		}
+		resp := s.handle(buf[:n])
+		if resp == nil {
+			continue
+		}
+		if _, err := s.conn.WriteTo(resp, addr); err != nil {
+			slog.Debug("dns write failed", "addr", addr, "error", err)
+		}
+	}
+}
+
+// handle はクエリに対する応答を返す。応答できない不正なメッセージの場合は nil を返す。
+func (s *Server) handle(msg []byte) []byte {
+	q, err := parseQuery(msg)
+	switch {
+	case q == nil:
+		return nil
+	case q.flags&0x8000 != 0:
+		return nil // 応答メッセージには応答しない
+	case err != nil:
+		q.question = nil
+		return buildResponse(q, rcodeFormErr, nil, nil)
+	case q.flags&0x7800 != 0:
+		return buildResponse(q, rcodeNotImp, nil, nil)
+	case q.qclass != classIN || (q.name != Domain && !strings.HasSuffix(q.name, "."+Domain)):
+		return buildResponse(q, rcodeRefused, nil, nil)
+	case q.name == Domain:
+		return buildResponse(q, rcodeSuccess, nil, nil)
+	}
+
+	rule := ruleName(q.name)
+	port, ok := s.lookup(rule)
+	if !ok {
+		return buildResponse(q, rcodeNXDomain, nil, nil)
+	}
+
+	target := rule + "." + Domain
+	a := record{rtype: typeA, data: localhostIPv4}
+	txt := record{rtype: typeTXT, data: txtData("port=" + strconv.Itoa(port))}
+	srv := record{rtype: typeSRV, data: srvData(port, target)}
+	// SRV の target の A レコードを追加セクションで返し、再問い合わせを省けるようにする
+	targetA := record{name: encodeName(target), rtype: typeA, data: localhostIPv4}
+
+	switch q.qtype {
+	case typeA:
+		return buildResponse(q, rcodeSuccess, []record{a}, nil)
+	case typeTXT:
+		return buildResponse(q, rcodeSuccess, []record{txt}, nil)
+	case typeSRV:
+		return buildResponse(q, rcodeSuccess, []record{srv}, []record{targetA})
+	case typeANY:
+		return buildResponse(q, rcodeSuccess, []record{a, txt, srv}, nil)
+	default:
+		// 名前は存在するがレコードがない（NODATA）
+		return buildResponse(q, rcodeSuccess, nil, nil)
+	}
+}
+
+// ruleName は "<rule>.moleport." または "_service._proto.<rule>.moleport." からルール名を取り出す。
+func ruleName(fqdn string) string {
+	name := strings.TrimSuffix(fqdn, "."+Domain)
+	for strings.HasPrefix(name, "_") {
+		i := strings.Index(name, ".")
+		if i < 0 {
+			break
+		}
+		name = name[i+1:]
+	}
+	return name
+}
@@ -0,0 +1,145 @@
+package dnsserver
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// startTestServer は "web"（ポート 8080）と "db"（ポート 5432）を解決するサーバーを起動する。
+func startTestServer(t *testing.T) *Server {
+	t.Helper()
+	ports := map[string]int{"web": 8080, "db": 5432}
+	s, err := Listen("127.0.0.1:0", func(rule string) (int, bool) {
+		p, ok := ports[rule]
+		return p, ok
+	})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// resolverFor は s に問い合わせる Go の DNS リゾルバーを返す。
+func resolverFor(s *Server) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "udp", s.Addr().String())
+		},
+	}
+}
+
+func TestServer_ResolvesActiveForwards(t *testing.T) {
+	r := resolverFor(startTestServer(t))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	addrs, err := r.LookupHost(ctx, "Web.moleport.")
+	if err != nil || len(addrs) != 1 || addrs[0] != "127.0.0.1" {
+		t.Errorf("LookupHost = %v, %v, want [127.0.0.1]", addrs, err)
+	}
+
+	_, srvs, err := r.LookupSRV(ctx, "postgres", "tcp", "db.moleport.")
+	if err != nil || len(srvs) != 1 || srvs[0].Port != 5432 || srvs[0].Target != "db.moleport." {
+		t.Errorf("LookupSRV = %+v, %v, want port 5432 target db.moleport.", srvs, err)
+	}
+
+	txts, err := r.LookupTXT(ctx, "db.moleport.")
+	if err != nil || len(txts) != 1 || txts[0] != "port=5432" {
+		t.Errorf("LookupTXT = %v, %v, want [port=5432]", txts, err)
+	}
+
+	_, err = r.LookupHost(ctx, "missing.moleport.")
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) || !dnsErr.IsNotFound {
+		t.Errorf("LookupHost(missing) error = %v, want not found", err)
+	}
+}
+
+// rawQuery は name の type 1 (A) クエリを組み立てる。
+func rawQuery(id uint16, name string) []byte {
+	msg := make([]byte, headerLen)
+	binary.BigEndian.PutUint16(msg[0:2], id)
+	binary.BigEndian.PutUint16(msg[2:4], 0x0100) // RD
+	binary.BigEndian.PutUint16(msg[4:6], 1)
+	msg = append(msg, encodeName(name)...)
+	msg = binary.BigEndian.AppendUint16(msg, typeA)
+	return binary.BigEndian.AppendUint16(msg, classIN)
+}
+
+func TestServer_HandleRcodes(t *testing.T) {
+	s := &Server{lookup: func(string) (int, bool) { return 0, false }}
+	tests := []struct {
+		name  string
+		msg   []byte
+		rcode int
+	}{
+		{"outside zone", rawQuery(1, "example.com."), rcodeRefused},
+		{"unknown rule", rawQuery(2, "web.moleport."), rcodeNXDomain},
+		{"apex", rawQuery(3, "moleport."), rcodeSuccess},
+		{"truncated", rawQuery(4, "web.moleport.")[:20], rcodeFormErr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := s.handle(tt.msg)
+			if len(resp) < headerLen {
+				t.Fatalf("response too short: %v", resp)
+			}
+			if got := binary.BigEndian.Uint16(resp[0:2]); got != binary.BigEndian.Uint16(tt.msg[0:2]) {
+				t.Errorf("id = %d, want echoed", got)
+			}
+			flags := binary.BigEndian.Uint16(resp[2:4])
+			if flags&0x8000 == 0 || flags&0x0100 == 0 {
+				t.Errorf("flags = %#04x, want QR and RD set", flags)
+			}
+			if got := int(flags & 0x000F); got != tt.rcode {
+				t.Errorf("rcode = %d, want %d", got, tt.rcode)
+			}
+		})
+	}
+
+	if resp := s.handle([]byte{0, 1}); resp != nil {
+		t.Errorf("short message should be ignored, got %v", resp)
+	}
+}
+
+func TestRuleName(t *testing.T) {
+	tests := map[string]string{
+		"web.moleport.":               "web",
+		"_http._tcp.web.moleport.":    "web",
+		"api.v2.moleport.":            "api.v2",
+		"_postgres._tcp.db.moleport.": "db",
+	}
+	for in, want := range tests {
+		if got := ruleName(in); got != want {
+			t.Errorf("ruleName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestSessionLookup(t *testing.T) {
+	lookup := SessionLookup(func() []core.ForwardSession {
+		return []core.ForwardSession{
+			{Rule: core.ForwardRule{Name: "Web", Type: core.Local, LocalPort: 8080}, Status: core.Active},
+			{Rule: core.ForwardRule{Name: "db", Type: core.Local, LocalPort: 5432}, Status: core.Stopped},
+			{Rule: core.ForwardRule{Name: "expose", Type: core.Remote, LocalPort: 3000}, Status: core.Active},
+		}
+	})
+
+	if port, ok := lookup("web"); !ok || port != 8080 {
+		t.Errorf("lookup(web) = %d, %v, want 8080, true", port, ok)
+	}
+	for _, rule := range []string{"db", "expose", "missing"} {
+		if _, ok := lookup(rule); ok {
+			t.Errorf("lookup(%s) should not resolve", rule)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package filewatch は設定ファイル（と Include などで参照されるファイル）の変更を監視する。
+package filewatch
@@ -0,0 +1,127 @@
+package filewatch
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceInterval は変更を検知してから onChange を呼ぶまでの待ち時間。
+// エディタの保存は書き込み・リネームなど複数のイベントになるため、この間のイベントをまとめる。
+const debounceInterval = 500 * time.Millisecond
+
+// SourcesFunc は path の設定ファイルが参照するファイルと、Include のパターン（絶対パス）を返す。
+// files には path 自体を含める。
+type SourcesFunc func(path string) (files, patterns []string, err error)
+
+// Watcher は設定ファイルと参照先のファイルを監視し、変更があれば onChange を呼ぶ。
+// 置き換え保存や、Include のパターンに新しく一致したファイルも検知できるよう、ファイルの親ディレクトリを監視する。
+type Watcher struct {
+	path     string
+	sources  SourcesFunc
+	onChange func()
+	debounce time.Duration
+	fsw      *fsnotify.Watcher
+
+	dirs     map[string]bool
+	files    map[string]bool
+	patterns []string
+}
+
+// New は path の設定ファイルを監視する Watcher を生成する。監視は Run で開始する。
+// sources が nil の場合は path のみを監視する。
+func New(path string, sources SourcesFunc, onChange func()) (*Watcher, error) {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	w := &Watcher{
+		path:     filepath.Clean(path),
+		sources:  sources,
+		onChange: onChange,
+		debounce: debounceInterval,
+		fsw:      fsw,
+		dirs:     make(map[string]bool),
+	}
+	w.refresh()
+	return w, nil
+}
+
+// Run は ctx がキャンセルされるまで変更を監視し、終了時に監視を解除する。
+func (w *Watcher) Run(ctx context.Context) {
+	defer func() { _ = w.fsw.Close() }()
+
+	var fire <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			if ev.Op != fsnotify.Chmod && w.relevant(ev.Name) {
+				fire = time.After(w.debounce)
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			slog.Warn("file watcher error", "path", w.path, "error", err)
+		case <-fire:
+			fire = nil
+			// Include の追加・削除で監視対象が変わるため、通知の前に再計算する
+			w.refresh()
+			w.onChange()
+		}
+	}
+}
+
+// refresh は設定ファイルを読み直して監視対象のファイルと Include のパターンを更新し、未監視のディレクトリを追加する。
+// 設定ファイルを読めない場合（削除中や構文エラー）も設定ファイル自体の監視は続ける。
+func (w *Watcher) refresh() {
+	files, patterns := []string{w.path}, []string(nil)
+	if w.sources != nil {
+		var err error
+		if files, patterns, err = w.sources(w.path); err != nil {
+			slog.Debug("failed to resolve watched files", "path", w.path, "error", err)
+			files, patterns = []string{w.path}, nil
+		}
+	}
+	w.files = make(map[string]bool, len(files))
+	for _, f := range files {
+		w.files[filepath.Clean(f)] = true
+	}
+	w.patterns = patterns
+
+	for _, p := range append(files, patterns...) {
+		dir := filepath.Dir(p)
+		if w.dirs[dir] {
+			continue
+		}
+		// パターンのディレクトリ部分にワイルドカードを含む場合や、まだ存在しないディレクトリは監視できない
+		if err := w.fsw.Add(dir); err != nil {
+			slog.Debug("failed to watch directory", "dir", dir, "error", err)
+			continue
+		}
+		w.dirs[dir] = true
+	}
+}
+
+// relevant は name が監視対象のファイルか、Include のパターンに一致するかを返す。
+func (w *Watcher) relevant(name string) bool {
+	name = filepath.Clean(name)
+	if w.files[name] {
+		return true
+	}
+	for _, p := range w.patterns {
+		if ok, _ := filepath.Match(p, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package filewatch
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/infra/sshconfig"
+)
+
+// startWatcher は path を監視する Watcher を起動し、onChange の呼び出しを受け取るチャネルを返す。
+func startWatcher(t *testing.T, path string, sources SourcesFunc) <-chan struct{} {
+	t.Helper()
+	changed := make(chan struct{}, 10)
+	w, err := New(path, sources, func() { changed <- struct{}{} })
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	w.debounce = 20 * time.Millisecond
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		w.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return changed
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitChange(t *testing.T, changed <-chan struct{}, what string) {
+	t.Helper()
+	select {
+	case <-changed:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("no change notified after %s", what)
+	}
+}
+
+func TestWatcher(t *testing.T) {
+	dir := t.TempDir()
+	confDir := filepath.Join(dir, "conf.d")
+	if err := os.MkdirAll(confDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config")
+	writeFile(t, path, "Include conf.d/*.conf\n")
+	writeFile(t, filepath.Join(confDir, "a.conf"), "Host a\n")
+
+	changed := startWatcher(t, path, sshconfig.Sources)
+
+	writeFile(t, path, "Include conf.d/*.conf\nHost top\n")
+	waitChange(t, changed, "editing ssh_config")
+
+	writeFile(t, filepath.Join(confDir, "a.conf"), "Host a\n    Port 2222\n")
+	waitChange(t, changed, "editing an included file")
+
+	writeFile(t, filepath.Join(confDir, "b.conf"), "Host b\n")
+	waitChange(t, changed, "adding a file matching Include")
+
+	// Include の対象外のファイルの変更は通知しない
+	writeFile(t, filepath.Join(confDir, "notes.txt"), "memo\n")
+	writeFile(t, filepath.Join(dir, "known_hosts"), "")
+	select {
+	case <-changed:
+		t.Error("unrelated file change should not be notified")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+func TestWatcher_MissingConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	changed := startWatcher(t, path, sshconfig.Sources)
+
+	writeFile(t, path, "Host a\n")
+	waitChange(t, changed, "creating ssh_config")
+}
+
+func TestWatcher_SingleFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "log:\n  level: info\n")
+	changed := startWatcher(t, path, nil)
+
+	// 同じディレクトリの別ファイル（state.yaml など）の変更は通知しない
+	writeFile(t, filepath.Join(dir, "state.yaml"), "selected_host: a\n")
+	select {
+	case <-changed:
+		t.Error("sibling file change should not be notified")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	writeFile(t, path, "log:\n  level: debug\n")
+	waitChange(t, changed, "editing config.yaml")
+}
@@ -0,0 +1,2 @@
+// Package handoff はルールの停止・再開をまたいでローカル TCP リスナーを引き継ぐ仕組みを提供する。
+package handoff
@@ -0,0 +1,247 @@
+package handoff
+
+import (
+	"context"
+	"net"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DefaultGracePeriod は閉じられたリスナーを引き継ぎ待ちとして保持する時間。
+// ルールの停止から再開までがこの時間内であれば、ポートを解放せずに新しいリスナーへ引き継ぐ。
+const DefaultGracePeriod = 2 * time.Second
+
+var defaultPool = NewPool(DefaultGracePeriod)
+
+// Listen は既定のプールを使って addr で TCP リスナーを開く。
+func Listen(ctx context.Context, addr string) (net.Listener, error) {
+	return defaultPool.Listen(ctx, addr)
+}
+
+// IsAddrInUse は err がアドレスの使用中によるバインドの失敗かを返す。
+func IsAddrInUse(err error) bool {
+	return isAddrInUse(err)
+}
+
+// InUse は既定のプールを使って addr が他のソケットで使用中かを返す。
+func InUse(addr string) bool {
+	return defaultPool.InUse(addr)
+}
+
+// Pool は閉じられたリスナーを一定時間保持し、同じアドレスへの再 Listen に引き継ぐ。
+// 引き継ぎ待ちの間に届いた接続はカーネルのバックログに留まり、新しいリスナーが受け付ける。
+type Pool struct {
+	mu     sync.Mutex
+	grace  time.Duration
+	parked map[string]*parked
+}
+
+// parked は引き継ぎ待ちのソケットと、猶予切れで閉じるためのタイマー。
+type parked struct {
+	s     *socket
+	timer *time.Timer
+}
+
+// NewPool は猶予時間 grace の Pool を生成する。grace が 0 以下の場合は引き継ぎを行わない。
+func NewPool(grace time.Duration) *Pool {
+	return &Pool{grace: grace, parked: make(map[string]*parked)}
+}
+
+// Listen は addr で TCP リスナーを開く。引き継ぎ待ちのソケットがあればそれを再利用する。
+// 新規に開くソケットには SO_REUSEADDR を設定する。
+func (p *Pool) Listen(ctx context.Context, addr string) (net.Listener, error) {
+	p.mu.Lock()
+	if pk, ok := p.parked[addr]; ok {
+		delete(p.parked, addr)
+		if pk.timer.Stop() && !pk.s.isDead() {
+			p.mu.Unlock()
+			return newListener(p, addr, pk.s), nil
+		}
+		// 猶予切れと競合した、または既に使えないソケットは閉じてから開き直す
+		pk.s.close()
+	}
+	p.mu.Unlock()
+
+	ln, err := listenReuse(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+	return newListener(p, addr, newSocket(ln)), nil
+}
+
+// InUse は addr が他のソケットで使用中かを返す。
+// 引き継ぎ待ちのソケットは次の Listen で再利用されるため使用中とみなさない。
+func (p *Pool) InUse(addr string) bool {
+	p.mu.Lock()
+	_, ok := p.parked[addr]
+	p.mu.Unlock()
+	if ok {
+		return false
+	}
+
+	ln, err := listenReuse(context.Background(), addr)
+	if err != nil {
+		return isAddrInUse(err)
+	}
+	_ = ln.Close()
+	return false
+}
+
+// listenReuse は SO_REUSEADDR を設定したソケットで addr を Listen する。
+func listenReuse(ctx context.Context, addr string) (net.Listener, error) {
+	lc := net.ListenConfig{Control: func(_, _ string, c syscall.RawConn) error {
+		var sockErr error
+		if err := c.Control(func(fd uintptr) { sockErr = setReuseAddr(fd) }); err != nil {
+			return err
+		}
+		return sockErr
+	}}
+	return lc.Listen(ctx, "tcp", addr)
+}
+
+// park は s を引き継ぎ待ちとして保持し、猶予切れで閉じる。
+func (p *Pool) park(addr string, s *socket) {
+	_, port, _ := net.SplitHostPort(addr)
+	if p.grace <= 0 || port == "0" || s.isDead() {
+		// ポート 0 は毎回別のポートが割り当てられるため引き継ぐ意味がない
+		s.close()
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if old, ok := p.parked[addr]; ok {
+		old.timer.Stop()
+		old.s.close()
+	}
+	pk := &parked{s: s}
+	pk.timer = time.AfterFunc(p.grace, func() {
+		p.mu.Lock()
+		if p.parked[addr] != pk {
+			p.mu.Unlock()
+			return
+		}
+		delete(p.parked, addr)
+		p.mu.Unlock()
+		s.close()
+	})
+	p.parked[addr] = pk
+}
+
+// socket は実際の TCP リスナーと、そこから接続を受け付け続ける goroutine を保持する。
+// リスナーを引き継いでも accept 中の goroutine を止めずに済むよう、受け付けた接続はチャネル経由で渡す。
+type socket struct {
+	ln        net.Listener
+	conns     chan net.Conn
+	stop      chan struct{}
+	dead      chan struct{}
+	err       error
+	closeOnce sync.Once
+}
+
+func newSocket(ln net.Listener) *socket {
+	s := &socket{
+		ln:    ln,
+		conns: make(chan net.Conn),
+		stop:  make(chan struct{}),
+		dead:  make(chan struct{}),
+	}
+	go s.pump()
+	return s
+}
+
+func (s *socket) pump() {
+	defer close(s.dead)
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			s.err = err
+			return
+		}
+		select {
+		case s.conns <- conn:
+		case <-s.stop:
+			_ = conn.Close()
+			return
+		}
+	}
+}
+
+// requeue は閉じられたリスナーが受け取ってしまった接続を次のリスナーに回す。
+func (s *socket) requeue(conn net.Conn) {
+	go func() {
+		select {
+		case s.conns <- conn:
+		case <-s.stop:
+			_ = conn.Close()
+		}
+	}()
+}
+
+func (s *socket) isDead() bool {
+	select {
+	case <-s.dead:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *socket) close() {
+	s.closeOnce.Do(func() {
+		close(s.stop)
+		_ = s.ln.Close()
+	})
+}
+
+// listener は socket を利用する net.Listener。Close してもソケットは閉じず、Pool に返却する。
+type listener struct {
+	pool      *Pool
+	addr      string
+	s         *socket
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newListener(p *Pool, addr string, s *socket) *listener {
+	return &listener{pool: p, addr: addr, s: s, done: make(chan struct{})}
+}
+
+// Accept は次の接続を返す。Close 後は net.ErrClosed を返す。
+func (l *listener) Accept() (net.Conn, error) {
+	select {
+	case <-l.done:
+		return nil, net.ErrClosed
+	default:
+	}
+
+	select {
+	case conn := <-l.s.conns:
+		select {
+		case <-l.done:
+			l.s.requeue(conn)
+			return nil, net.ErrClosed
+		default:
+			return conn, nil
+		}
+	case <-l.done:
+		return nil, net.ErrClosed
+	case <-l.s.dead:
+		return nil, l.s.err
+	}
+}
+
+// Close はリスナーを閉じ、ソケットを引き継ぎ待ちとして Pool に返却する。
+func (l *listener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.done)
+		l.pool.park(l.addr, l.s)
+	})
+	return nil
+}
+
+// Addr はリスナーのアドレスを返す。
+func (l *listener) Addr() net.Addr {
+	return l.s.ln.Addr()
+}
@@ -0,0 +1,155 @@
+package handoff
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// freeAddr は空いているループバックアドレスを返す。
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+	return addr
+}
+
+func acceptAsync(ln net.Listener) <-chan error {
+	ch := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err == nil {
+			_ = conn.Close()
+		}
+		ch <- err
+	}()
+	return ch
+}
+
+func TestPool_HandoffKeepsPortAndQueuedConnections(t *testing.T) {
+	p := NewPool(time.Second)
+	addr := freeAddr(t)
+
+	first, err := p.Listen(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	_ = first.Close()
+
+	// 引き継ぎ待ちの間に届いた接続は拒否されない
+	conn, err := net.DialTimeout("tcp", addr, time.Second)
+	if err != nil {
+		t.Fatalf("Dial during handoff: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	second, err := p.Listen(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("re-Listen: %v", err)
+	}
+	defer func() { _ = second.Close() }()
+	if second.Addr().String() != addr {
+		t.Errorf("Addr = %s, want %s", second.Addr(), addr)
+	}
+
+	select {
+	case err := <-acceptAsync(second):
+		if err != nil {
+			t.Fatalf("Accept: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("queued connection was not handed to the new listener")
+	}
+}
+
+func TestPool_ClosesAfterGracePeriod(t *testing.T) {
+	p := NewPool(20 * time.Millisecond)
+	addr := freeAddr(t)
+
+	ln, err := p.Listen(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	_ = ln.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		plain, err := net.Listen("tcp", addr)
+		if err == nil {
+			_ = plain.Close()
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("port was not released after grace period: %v", err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestListener_CloseUnblocksAccept(t *testing.T) {
+	p := NewPool(0)
+	ln, err := p.Listen(context.Background(), "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	errCh := acceptAsync(ln)
+	_ = ln.Close()
+
+	select {
+	case err := <-errCh:
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("Accept error = %v, want net.ErrClosed", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Accept did not return after Close")
+	}
+}
+
+func TestPool_ConflictWithActiveListener(t *testing.T) {
+	p := NewPool(time.Second)
+	addr := freeAddr(t)
+
+	ln, err := p.Listen(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	if _, err := p.Listen(context.Background(), addr); err == nil {
+		t.Fatal("second Listen on an active address should fail")
+	}
+}
+
+func TestPool_InUse(t *testing.T) {
+	p := NewPool(time.Second)
+	addr := freeAddr(t)
+	if p.InUse(addr) {
+		t.Fatalf("InUse(%s) = true for a free address", addr)
+	}
+
+	other, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	if !p.InUse(addr) {
+		t.Errorf("InUse(%s) = false while another listener holds it", addr)
+	}
+	_ = other.Close()
+
+	// 引き継ぎ待ちのソケットは使用中とみなさない
+	ln, err := p.Listen(context.Background(), addr)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	_ = ln.Close()
+	if p.InUse(addr) {
+		t.Errorf("InUse(%s) = true for a parked socket", addr)
+	}
+}
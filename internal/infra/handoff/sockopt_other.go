@@ -0,0 +1,16 @@
+//go:build !unix && !windows
+
+package handoff
+
+import (
+	"errors"
+	"syscall"
+)
+
+// setReuseAddr は Unix 以外では何もしない。
+func setReuseAddr(uintptr) error { return nil }
+
+// isAddrInUse は err がアドレスの使用中によるバインドの失敗かを返す。
+func isAddrInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE)
+}
@@ -0,0 +1,21 @@
+//go:build unix
+
+package handoff
+
+import (
+	"errors"
+	"syscall"
+)
+
+// setReuseAddr はリスナーソケットに SO_REUSEADDR を設定し、TIME_WAIT 中のポートへの再バインドを許可する。
+//
+// SO_REUSEPORT は設定しない。同一ポートへの多重バインドが成立してしまい、
+// 別ルールや他プロセスとのポート競合を検出できなくなるため。停止直後の再開は Listen の引き継ぎで対応する。
+func setReuseAddr(fd uintptr) error {
+	return syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_REUSEADDR, 1)
+}
+
+// isAddrInUse は err がアドレスの使用中によるバインドの失敗かを返す。
+func isAddrInUse(err error) bool {
+	return errors.Is(err, syscall.EADDRINUSE)
+}
@@ -0,0 +1,16 @@
+package handoff
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// setReuseAddr は Windows では何もしない。Windows の SO_REUSEADDR は使用中のポートへの重複バインドを許し、
+// ポート競合を検出できなくなるため設定しない。
+func setReuseAddr(uintptr) error { return nil }
+
+// isAddrInUse は err がアドレスの使用中によるバインドの失敗（WSAEADDRINUSE）かを返す。
+func isAddrInUse(err error) bool {
+	return errors.Is(err, windows.WSAEADDRINUSE)
+}
@@ -0,0 +1,2 @@
+// Package healthprobe はホストの SSH エンドポイントへ定期的に TCP 接続を試み、到達性を記録する。
+package healthprobe
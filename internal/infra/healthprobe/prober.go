@@ -0,0 +1,161 @@
+package healthprobe
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+const (
+	// DefaultInterval は確認間隔が未設定の場合の既定値。
+	DefaultInterval = 5 * time.Minute
+	// MinInterval は確認間隔の下限。対象ホストへの負荷を抑えるため、これより短い間隔は切り上げる。
+	MinInterval = 30 * time.Second
+
+	probeTimeout    = 5 * time.Second
+	maxConcurrent   = 8
+	jitterFraction  = 0.2
+	initialDelayMax = 5 * time.Second
+)
+
+// Target は疎通確認の対象ホスト。Addr は "host:port" 形式。
+type Target struct {
+	Name string
+	Addr string
+}
+
+// TargetsOf は hosts のうち直接到達できるホストを疎通確認の対象として返す。
+// ProxyCommand や ProxyJump を経由するホストは手元から SSH ポートへ直接接続できないため除く。
+func TargetsOf(hosts []core.SSHHost) []Target {
+	targets := make([]Target, 0, len(hosts))
+	for _, h := range hosts {
+		if h.ProxyCommand != "" || len(h.JumpChain) > 0 || h.HostName == "" {
+			continue
+		}
+		targets = append(targets, Target{Name: h.Name, Addr: net.JoinHostPort(h.HostName, strconv.Itoa(h.Port))})
+	}
+	return targets
+}
+
+// Prober は一定間隔（ジッター付き）で各ホストの SSH ポートへ TCP 接続を試み、到達性を記録する。
+// SSH ハンドシェイクは行わないため、認証情報やホスト鍵は扱わない。
+type Prober struct {
+	targets  func() []Target
+	interval time.Duration
+	onChange func(name string, health core.HostHealth)
+	dial     func(ctx context.Context, addr string) error
+
+	mu     sync.RWMutex
+	health map[string]core.HostHealth
+}
+
+// New は Prober を生成する。targets は確認のたびに呼ばれ、ホスト一覧の変更を反映する。
+// onChange は到達性が変化したホストごとに呼ばれる。interval は MinInterval 以上に補正する。
+func New(targets func() []Target, interval time.Duration, onChange func(name string, health core.HostHealth)) *Prober {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Prober{
+		targets:  targets,
+		interval: max(interval, MinInterval),
+		onChange: onChange,
+		dial:     dialTCP,
+		health:   make(map[string]core.HostHealth),
+	}
+}
+
+// Health は name の直近の確認結果を返す。未確認のホストや nil の Prober では HealthUnknown を返す。
+func (p *Prober) Health(name string) core.HostHealth {
+	if p == nil {
+		return core.HealthUnknown
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.health[name]
+}
+
+// Run は ctx が終了するまで疎通確認を繰り返す。
+// 起動直後の負荷集中を避けるため、初回も短いランダムな遅延の後に確認する。
+func (p *Prober) Run(ctx context.Context) {
+	timer := time.NewTimer(time.Duration(rand.Int64N(int64(initialDelayMax)))) //nolint:gosec // ジッター用途のため暗号学的強度は不要
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+			p.probeAll(ctx)
+			timer.Reset(jittered(p.interval))
+		}
+	}
+}
+
+// probeAll は全対象を並行して確認し、結果を記録して変化したホストを通知する。
+func (p *Prober) probeAll(ctx context.Context) {
+	targets := p.targets()
+	results := make([]core.HostHealth, len(targets))
+	sem := make(chan struct{}, maxConcurrent)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = p.probe(ctx, t)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+
+	next := make(map[string]core.HostHealth, len(targets))
+	var changed []int
+	p.mu.Lock()
+	for i, t := range targets {
+		next[t.Name] = results[i]
+		if p.health[t.Name] != results[i] {
+			changed = append(changed, i)
+		}
+	}
+	p.health = next
+	p.mu.Unlock()
+
+	for _, i := range changed {
+		p.onChange(targets[i].Name, results[i])
+	}
+}
+
+func (p *Prober) probe(ctx context.Context, t Target) core.HostHealth {
+	if err := p.dial(ctx, t.Addr); err != nil {
+		slog.Debug("host health probe failed", "host", t.Name, "addr", t.Addr, "error", err)
+		return core.HealthUnreachable
+	}
+	return core.HealthReachable
+}
+
+// dialTCP は probeTimeout 以内に addr へ TCP 接続し、すぐに閉じる。
+func dialTCP(ctx context.Context, addr string) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// jittered は d を ±jitterFraction の範囲でランダムに揺らした値を返す。
+// 複数デーモンや複数ホストの確認が同じ時刻に揃わないようにする。
+func jittered(d time.Duration) time.Duration {
+	f := (rand.Float64()*2 - 1) * jitterFraction //nolint:gosec // ジッター用途のため暗号学的強度は不要
+	return d + time.Duration(f*float64(d))
+}
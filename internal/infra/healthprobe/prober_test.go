@@ -0,0 +1,102 @@
+package healthprobe
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestProber_ProbeAllRecordsAndNotifiesChanges(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	closedAddr := closed.Addr().String()
+	_ = closed.Close()
+
+	targets := []Target{{Name: "up", Addr: ln.Addr().String()}, {Name: "down", Addr: closedAddr}}
+	changes := make(map[string]core.HostHealth)
+	p := New(func() []Target { return targets }, time.Minute, func(name string, h core.HostHealth) {
+		changes[name] = h
+	})
+
+	p.probeAll(context.Background())
+	if got := p.Health("up"); got != core.HealthReachable {
+		t.Errorf("Health(up) = %q, want reachable", got)
+	}
+	if got := p.Health("down"); got != core.HealthUnreachable {
+		t.Errorf("Health(down) = %q, want unreachable", got)
+	}
+	if len(changes) != 2 {
+		t.Errorf("changes = %v, want both hosts", changes)
+	}
+
+	// 結果が変わらなければ通知しない
+	clear(changes)
+	p.probeAll(context.Background())
+	if len(changes) != 0 {
+		t.Errorf("changes = %v, want none on stable results", changes)
+	}
+
+	// 対象から外れたホストは未確認に戻る
+	targets = targets[:1]
+	p.probeAll(context.Background())
+	if got := p.Health("down"); got != core.HealthUnknown {
+		t.Errorf("Health(down) after removal = %q, want unknown", got)
+	}
+}
+
+func TestProber_SkipsRecordingWhenCancelled(t *testing.T) {
+	p := New(func() []Target { return []Target{{Name: "h", Addr: "x:22"}} }, 0, func(string, core.HostHealth) {
+		t.Error("onChange should not be called after cancellation")
+	})
+	p.dial = func(context.Context, string) error { return errors.New("canceled") }
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p.probeAll(ctx)
+	if got := p.Health("h"); got != core.HealthUnknown {
+		t.Errorf("Health = %q, want unknown", got)
+	}
+	if p.interval != DefaultInterval {
+		t.Errorf("interval = %v, want default %v", p.interval, DefaultInterval)
+	}
+}
+
+func TestNew_ClampsInterval(t *testing.T) {
+	p := New(nil, time.Second, nil)
+	if p.interval != MinInterval {
+		t.Errorf("interval = %v, want %v", p.interval, MinInterval)
+	}
+	for range 100 {
+		if d := jittered(time.Minute); d < 48*time.Second || d > 72*time.Second {
+			t.Fatalf("jittered(1m) = %v, want within ±20%%", d)
+		}
+	}
+	var nilProber *Prober
+	if nilProber.Health("h") != core.HealthUnknown {
+		t.Error("nil Prober should report unknown")
+	}
+}
+
+func TestTargetsOf(t *testing.T) {
+	got := TargetsOf([]core.SSHHost{
+		{Name: "bastion", HostName: "10.0.0.1", Port: 22},
+		{Name: "db", HostName: "10.0.1.5", Port: 22, JumpChain: []string{"bastion"}},
+		{Name: "legacy", HostName: "10.0.2.5", Port: 22, ProxyCommand: "nc %h %p"},
+		{Name: "v6", HostName: "::1", Port: 2222},
+	})
+	want := []Target{{Name: "bastion", Addr: "10.0.0.1:22"}, {Name: "v6", Addr: "[::1]:2222"}}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("TargetsOf() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,2 @@
+// Package hookexec はフォワードルールの on_start・on_stop のフックコマンドをセッションの情報を環境変数に設定して実行する。
+package hookexec
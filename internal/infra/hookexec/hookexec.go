@@ -0,0 +1,81 @@
+package hookexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// フックの種別。環境変数 MOLEPORT_HOOK に設定する。
+const (
+	HookStart = "on_start"
+	HookStop  = "on_stop"
+)
+
+const (
+	// Timeout はフックコマンドの実行時間の上限。超えた場合はプロセスを終了する。
+	Timeout = 30 * time.Second
+	// maxOutput はログに記録する出力の上限バイト数。
+	maxOutput = 4096
+	// waitDelay はプロセスの終了後、出力を受け取る子プロセスの終了を待つ上限。
+	waitDelay = time.Second
+)
+
+// Env はフックコマンドに渡すセッションの情報の環境変数を返す。
+func Env(hook string, s core.ForwardSession) []string {
+	return []string{
+		"MOLEPORT_HOOK=" + hook,
+		"MOLEPORT_RULE=" + s.Rule.Name,
+		"MOLEPORT_HOST=" + s.Rule.Host,
+		"MOLEPORT_TYPE=" + s.Rule.Type.String(),
+		"MOLEPORT_SESSION_ID=" + s.ID,
+		"MOLEPORT_LOCAL_ADDR=" + s.Rule.LocalBindHost(),
+		"MOLEPORT_LOCAL_PORT=" + strconv.Itoa(s.ListenPort()),
+		"MOLEPORT_REMOTE_HOST=" + s.Rule.RemoteHost,
+		"MOLEPORT_REMOTE_PORT=" + strconv.Itoa(s.Rule.RemotePort),
+	}
+}
+
+// Run はシェル（Windows では cmd）経由で command を実行し、終了状態と出力をログに記録する。
+// ctx の期限を過ぎた場合はプロセスを終了してエラーを返す。
+func Run(ctx context.Context, hook string, s core.ForwardSession, command string) error {
+	cmd := shellCommand(ctx, command)
+	cmd.Env = append(os.Environ(), Env(hook, s)...)
+	cmd.WaitDelay = waitDelay
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("timed out: %w", ctx.Err())
+	}
+	attrs := []any{"hook", hook, "rule", s.Rule.Name, "duration", time.Since(start).Round(time.Millisecond), "output", truncate(out)}
+	if err != nil {
+		slog.Warn("forward hook failed", append(attrs, "error", err)...)
+		return err
+	}
+	slog.Info("forward hook finished", attrs...)
+	return nil
+}
+
+// shellCommand は command をシェル（Windows では cmd）で実行するコマンドを返す。
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command) //nolint:gosec // フックはユーザー設定値
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command) //nolint:gosec // フックはユーザー設定値
+}
+
+// truncate は出力を maxOutput バイトまでに切り詰めて返す。
+func truncate(out []byte) string {
+	if len(out) <= maxOutput {
+		return string(out)
+	}
+	return string(out[:maxOutput]) + "...(truncated)"
+}
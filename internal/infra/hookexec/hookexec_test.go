@@ -0,0 +1,78 @@
+//go:build !windows
+
+package hookexec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func testSession() core.ForwardSession {
+	return core.ForwardSession{
+		ID:     "web-1",
+		Status: core.Active,
+		Rule: core.ForwardRule{
+			Name: "web", Host: "prod", Type: core.Local, LocalPort: 0,
+			RemoteHost: "localhost", RemotePort: 80,
+		},
+		BoundPort: 18080,
+	}
+}
+
+func TestEnv(t *testing.T) {
+	env := Env(HookStart, testSession())
+	for _, want := range []string{
+		"MOLEPORT_HOOK=on_start", "MOLEPORT_RULE=web", "MOLEPORT_HOST=prod", "MOLEPORT_TYPE=local",
+		"MOLEPORT_SESSION_ID=web-1", "MOLEPORT_LOCAL_ADDR=127.0.0.1", "MOLEPORT_LOCAL_PORT=18080",
+		"MOLEPORT_REMOTE_HOST=localhost", "MOLEPORT_REMOTE_PORT=80",
+	} {
+		if !slices.Contains(env, want) {
+			t.Errorf("Env() = %v, missing %q", env, want)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	err := Run(t.Context(), HookStop, testSession(), `echo "$MOLEPORT_HOOK $MOLEPORT_RULE $MOLEPORT_LOCAL_PORT" > `+out)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	data, _ := os.ReadFile(out)
+	if got := strings.TrimSpace(string(data)); got != "on_stop web 18080" {
+		t.Errorf("hook output = %q", got)
+	}
+
+	if err := Run(t.Context(), HookStop, testSession(), "exit 3"); err == nil {
+		t.Error("Run with failing command: expected error")
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := Run(ctx, HookStart, testSession(), "sleep 10")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("Run = %v, want timeout error", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Run took %v after timeout", d)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	if got := truncate([]byte("ok")); got != "ok" {
+		t.Errorf("truncate(short) = %q", got)
+	}
+	if got := truncate([]byte(strings.Repeat("x", maxOutput+10))); len(got) != maxOutput+len("...(truncated)") {
+		t.Errorf("truncate(long) length = %d", len(got))
+	}
+}
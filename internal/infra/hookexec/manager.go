@@ -0,0 +1,168 @@
+package hookexec
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// Manager はルールの on_start・on_stop のフックを実行する core.ForwardManager。
+// on_start はフォワードが待ち受けを始めるたび（開始・SSH 再接続や auto_reconnect による復元・移行や変更による再開）に
+// HandleForwardEvent からバックグラウンドで実行する。
+// on_stop は停止の要求（StopForward・StopAllForwards・DeleteRule・Close と、UpdateRule・MigrateForward による再開）では
+// 停止する前に実行して終了を待つ。TTL の期限切れ・エラー・SSH の切断など要求によらない停止では、
+// 停止を知らせるイベントを受けた後にバックグラウンドで実行する。
+// on_stop は on_start の対象になったフォワードごとに一度だけ実行する。
+type Manager struct {
+	core.ForwardManager
+	timeout time.Duration
+	wg      sync.WaitGroup
+
+	mu sync.Mutex
+	// started は on_start の対象になり、まだ on_stop の対象になっていないルール名。
+	started map[string]bool
+	closed  bool
+}
+
+// Wrap は fwd の開始・停止でフックを実行する Manager を返す。
+func Wrap(fwd core.ForwardManager) *Manager {
+	return &Manager{ForwardManager: fwd, timeout: Timeout, started: make(map[string]bool)}
+}
+
+// HandleForwardEvent はフォワードが待ち受けを始めたイベントで on_start を、要求によらず停止したイベントで on_stop を実行する。
+// 接続単位の失敗などセッションがアクティブのままのエラーでは何もしない。Close の後はフックを実行せず、nil の Manager では何もしない。
+// 単一のゴルーチンから呼ぶ。
+func (m *Manager) HandleForwardEvent(evt core.ForwardEvent) {
+	if m == nil || evt.Session == nil {
+		return
+	}
+	s := *evt.Session
+	switch evt.Type {
+	case core.ForwardEventStarted, core.ForwardEventRestored, core.ForwardEventMigrated, core.ForwardEventUpdated:
+		if s.Status == core.Active && m.markStarted(evt.RuleName) && s.Rule.OnStart != "" {
+			m.spawn(HookStart, s, s.Rule.OnStart)
+		}
+	case core.ForwardEventStopped, core.ForwardEventReconnecting, core.ForwardEventError:
+		if s.Status != core.Active && m.takeStarted(evt.RuleName) && s.Rule.OnStop != "" {
+			m.spawn(HookStop, s, s.Rule.OnStop)
+		}
+	}
+}
+
+// StopForward はアクティブなフォワードの on_stop を実行してから停止する。
+func (m *Manager) StopForward(ruleName string) error {
+	m.runStop(ruleName)
+	return m.ForwardManager.StopForward(ruleName)
+}
+
+// DeleteRule はアクティブなフォワードの on_stop を実行してからルールを削除する。
+func (m *Manager) DeleteRule(name string) error {
+	m.runStop(name)
+	return m.ForwardManager.DeleteRule(name)
+}
+
+// UpdateRule はアクティブなフォワードの on_stop を実行してからルールを置き換える。
+// 再開後の on_start は HandleForwardEvent が実行する。
+func (m *Manager) UpdateRule(rule core.ForwardRule, cb core.CredentialCallback) (*core.ForwardSession, error) {
+	m.runStop(rule.Name)
+	s, err := m.ForwardManager.UpdateRule(rule, cb)
+	if err != nil {
+		m.keepStarted(rule.Name)
+	}
+	return s, err
+}
+
+// MigrateForward はアクティブなフォワードの on_stop を実行してから接続先ホストを切り替える。
+// 切り替え後の on_start は HandleForwardEvent が実行する。
+func (m *Manager) MigrateForward(ruleName, toHost string, cb core.CredentialCallback) (*core.ForwardSession, error) {
+	m.runStop(ruleName)
+	s, err := m.ForwardManager.MigrateForward(ruleName, toHost, cb)
+	if err != nil {
+		m.keepStarted(ruleName)
+	}
+	return s, err
+}
+
+// StopAllForwards は全てのアクティブなフォワードの on_stop を並行して実行してから停止する。
+func (m *Manager) StopAllForwards() error {
+	m.runStopAll()
+	return m.ForwardManager.StopAllForwards()
+}
+
+// Close は全てのアクティブなフォワードの on_stop と実行中のフックの終了を待ってから停止する。
+func (m *Manager) Close() {
+	m.mu.Lock()
+	m.closed = true
+	m.mu.Unlock()
+	m.runStopAll()
+	m.wg.Wait()
+	m.ForwardManager.Close()
+}
+
+// runStop は ruleName がアクティブで on_start の対象になっていれば on_stop を実行する。
+func (m *Manager) runStop(ruleName string) {
+	s, err := m.GetSession(ruleName)
+	if err != nil || s.Status != core.Active || !m.takeStarted(ruleName) || s.Rule.OnStop == "" {
+		return
+	}
+	m.run(HookStop, *s, s.Rule.OnStop)
+}
+
+// runStopAll はアクティブで on_start の対象になった全フォワードの on_stop を並行して実行し、終了を待つ。
+func (m *Manager) runStopAll() {
+	var wg sync.WaitGroup
+	for _, s := range m.GetAllSessions() {
+		if s.Status == core.Active && m.takeStarted(s.Rule.Name) && s.Rule.OnStop != "" {
+			wg.Go(func() { m.run(HookStop, s, s.Rule.OnStop) })
+		}
+	}
+	wg.Wait()
+}
+
+// keepStarted は再開に失敗しても旧セッションがアクティブのままの場合に、on_stop の対象に戻す。
+func (m *Manager) keepStarted(ruleName string) {
+	if s, err := m.GetSession(ruleName); err == nil && s.Status == core.Active {
+		m.markStarted(ruleName)
+	}
+}
+
+// markStarted は ruleName を on_stop の対象にする。既に対象の場合は false を返す。
+func (m *Manager) markStarted(ruleName string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.started[ruleName] {
+		return false
+	}
+	m.started[ruleName] = true
+	return true
+}
+
+// takeStarted は ruleName を on_stop の対象から外し、対象だったかを返す。
+func (m *Manager) takeStarted(ruleName string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.started[ruleName] {
+		return false
+	}
+	delete(m.started, ruleName)
+	return true
+}
+
+// spawn はフックをバックグラウンドで実行する。Close の後は実行しない。
+func (m *Manager) spawn(hook string, s core.ForwardSession, command string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		return
+	}
+	m.wg.Go(func() { m.run(hook, s, command) })
+}
+
+// run は timeout を期限としてフックを実行する。失敗は Run がログに記録する。
+func (m *Manager) run(hook string, s core.ForwardSession, command string) {
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+	_ = Run(ctx, hook, s, command)
+}
@@ -0,0 +1,226 @@
+//go:build !windows
+
+package hookexec
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// fakeForwardManager は 1 つのセッションの開始・停止を記録する core.ForwardManager。
+type fakeForwardManager struct {
+	core.ForwardManager
+	session core.ForwardSession
+	log     *os.File
+	closed  bool
+}
+
+func (f *fakeForwardManager) StartForward(context.Context, string, core.CredentialCallback) error {
+	f.session.Status = core.Active
+	return nil
+}
+
+func (f *fakeForwardManager) StopForward(string) error {
+	_, _ = f.log.WriteString("stopped\n")
+	f.session.Status = core.Stopped
+	return nil
+}
+
+func (f *fakeForwardManager) UpdateRule(rule core.ForwardRule, _ core.CredentialCallback) (*core.ForwardSession, error) {
+	_, _ = f.log.WriteString("updated\n")
+	f.session.Rule = rule
+	s := f.session
+	return &s, nil
+}
+
+func (f *fakeForwardManager) GetSession(string) (*core.ForwardSession, error) {
+	s := f.session
+	return &s, nil
+}
+
+func (f *fakeForwardManager) GetAllSessions() []core.ForwardSession {
+	return []core.ForwardSession{f.session}
+}
+
+func (f *fakeForwardManager) Close() { f.closed = true }
+
+func newTestManager(t *testing.T) (*Manager, *fakeForwardManager, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "log")
+	log, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = log.Close() })
+	s := testSession()
+	s.Status = core.Stopped
+	s.Rule.OnStart = `echo "start $MOLEPORT_RULE" >> ` + path
+	s.Rule.OnStop = `echo "stop $MOLEPORT_RULE" >> ` + path
+	fwd := &fakeForwardManager{session: s, log: log}
+	return Wrap(fwd), fwd, path
+}
+
+func readLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// event は fwd の現在のセッションで typ のイベントを返す。
+func event(fwd *fakeForwardManager, typ core.ForwardEventType) core.ForwardEvent {
+	s := fwd.session
+	return core.ForwardEvent{Type: typ, RuleName: s.Rule.Name, Session: &s}
+}
+
+func TestManager_StartStop(t *testing.T) {
+	m, fwd, path := newTestManager(t)
+
+	if err := m.StartForward(t.Context(), "web", nil); err != nil {
+		t.Fatalf("StartForward: %v", err)
+	}
+	m.HandleForwardEvent(event(fwd, core.ForwardEventStarted))
+	m.wg.Wait()
+	if got := readLog(t, path); got != "start web\n" {
+		t.Errorf("log after start = %q", got)
+	}
+
+	// on_stop は停止の前に実行する
+	if err := m.StopForward("web"); err != nil {
+		t.Fatalf("StopForward: %v", err)
+	}
+	if got := readLog(t, path); got != "start web\nstop web\nstopped\n" {
+		t.Errorf("log after stop = %q", got)
+	}
+
+	// 停止中のフォワードでは on_stop を実行しない
+	if err := m.StopForward("web"); err != nil {
+		t.Fatalf("StopForward: %v", err)
+	}
+	if got := readLog(t, path); strings.Count(got, "stop web") != 1 {
+		t.Errorf("on_stop ran for a stopped forward: %q", got)
+	}
+
+	// 要求による停止の後に届く停止イベントでは on_stop を再実行しない
+	m.HandleForwardEvent(event(fwd, core.ForwardEventStopped))
+	m.wg.Wait()
+	if got := readLog(t, path); strings.Count(got, "stop web") != 1 {
+		t.Errorf("on_stop ran again on the stopped event: %q", got)
+	}
+}
+
+func TestManager_InternalTransitions(t *testing.T) {
+	tests := []struct {
+		name   string
+		events []core.ForwardEventType
+		status []core.SessionStatus
+		want   string
+	}{
+		{
+			name:   "restored after ssh reconnect",
+			events: []core.ForwardEventType{core.ForwardEventStarted, core.ForwardEventReconnecting, core.ForwardEventRestored},
+			status: []core.SessionStatus{core.Active, core.SessionReconnecting, core.Active},
+			want:   "start web\nstop web\nstart web\n",
+		},
+		{
+			name:   "ttl expiry",
+			events: []core.ForwardEventType{core.ForwardEventStarted, core.ForwardEventStopped},
+			status: []core.SessionStatus{core.Active, core.Stopped},
+			want:   "start web\nstop web\n",
+		},
+		{
+			name:   "listener error after reconnect wait",
+			events: []core.ForwardEventType{core.ForwardEventStarted, core.ForwardEventReconnecting, core.ForwardEventError},
+			status: []core.SessionStatus{core.Active, core.SessionReconnecting, core.SessionError},
+			want:   "start web\nstop web\n",
+		},
+		{
+			name:   "dial error keeps the session active",
+			events: []core.ForwardEventType{core.ForwardEventStarted, core.ForwardEventError},
+			status: []core.SessionStatus{core.Active, core.Active},
+			want:   "start web\n",
+		},
+		{
+			name:   "migrated to another host",
+			events: []core.ForwardEventType{core.ForwardEventMigrated},
+			status: []core.SessionStatus{core.Active},
+			want:   "start web\n",
+		},
+		{
+			name:   "stopped rule updated",
+			events: []core.ForwardEventType{core.ForwardEventUpdated},
+			status: []core.SessionStatus{core.Stopped},
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, fwd, path := newTestManager(t)
+			for i, typ := range tt.events {
+				fwd.session.Status = tt.status[i]
+				m.HandleForwardEvent(event(fwd, typ))
+				// フックはバックグラウンドで実行されるため、順序を確かめられるよう 1 つずつ待つ
+				m.wg.Wait()
+			}
+			if got := readLog(t, path); got != tt.want {
+				t.Errorf("log = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManager_UpdateRule(t *testing.T) {
+	m, fwd, path := newTestManager(t)
+	fwd.session.Status = core.Active
+	m.HandleForwardEvent(event(fwd, core.ForwardEventStarted))
+	m.wg.Wait()
+
+	// 再開の前に on_stop を実行し、再開のイベントで新しいルールの on_start を実行する
+	rule := fwd.session.Rule
+	rule.OnStart = strings.Replace(rule.OnStart, "start", "restart", 1)
+	if _, err := m.UpdateRule(rule, nil); err != nil {
+		t.Fatalf("UpdateRule: %v", err)
+	}
+	m.HandleForwardEvent(event(fwd, core.ForwardEventUpdated))
+	m.wg.Wait()
+	if got, want := readLog(t, path), "start web\nstop web\nupdated\nrestart web\n"; got != want {
+		t.Errorf("log = %q, want %q", got, want)
+	}
+}
+
+func TestManager_CloseRunsStopHooks(t *testing.T) {
+	m, fwd, path := newTestManager(t)
+	fwd.session.Status = core.Active
+	fwd.session.Rule.OnStart = ""
+	fwd.session.Rule.OnStop = "sleep 10"
+	m.timeout = 100 * time.Millisecond
+	m.HandleForwardEvent(event(fwd, core.ForwardEventStarted))
+
+	start := time.Now()
+	m.Close()
+	if !fwd.closed {
+		t.Error("underlying manager was not closed")
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("Close waited %v for a timed out hook", d)
+	}
+	if got := readLog(t, path); got != "" {
+		t.Errorf("log = %q, want empty", got)
+	}
+
+	// Close の後に届くイベントではフックを実行しない
+	fwd.session.Rule.OnStart = "echo late >> " + path
+	m.HandleForwardEvent(event(fwd, core.ForwardEventRestored))
+	m.wg.Wait()
+	if got := readLog(t, path); got != "" {
+		t.Errorf("log after close = %q, want empty", got)
+	}
+}
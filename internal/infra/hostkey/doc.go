@@ -0,0 +1,2 @@
+// Package hostkey は known_hosts によるホスト鍵の検証と、未登録の鍵を確認して信頼する TOFU を提供する。
+package hostkey
@@ -0,0 +1,112 @@
+package hostkey
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// mu は known_hosts への追記を直列化する。
+var mu sync.Mutex
+
+// Callback は known_hosts（path）でホスト鍵を検証する ssh.HostKeyCallback を返す。
+// strict は ssh_config の StrictHostKeyChecking で、"no" は検証しない。
+// known_hosts に未登録の鍵は、"accept-new" なら確認せずに追記し、
+// "yes" 以外で cb が非 nil なら CredentialHostKey の確認で信頼するかを問い合わせる。
+// 登録済みの鍵と一致しない場合は確認せずに拒否する。
+// host は確認に表示するホスト名（ssh_config のエイリアス）。
+func Callback(path, host, strict string, cb core.CredentialCallback) (ssh.HostKeyCallback, error) {
+	if strings.EqualFold(strict, "no") {
+		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // SSH config の StrictHostKeyChecking=no を尊重
+	}
+	if err := ensureFile(path); err != nil {
+		return nil, err
+	}
+	check, err := knownhosts.New(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load known_hosts (%s): %w", path, err)
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := check(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil || !errors.As(err, &keyErr) || len(keyErr.Want) > 0 {
+			return err
+		}
+		switch {
+		case strings.EqualFold(strict, "accept-new"):
+			return Append(path, hostname, key)
+		case strings.EqualFold(strict, "yes") || cb == nil:
+			return err
+		}
+		return confirm(path, host, hostname, key, cb, err)
+	}, nil
+}
+
+// confirm は未登録のホスト鍵を信頼するかを cb で問い合わせる。
+func confirm(path, host, hostname string, key ssh.PublicKey, cb core.CredentialCallback, unknown error) error {
+	fingerprint := ssh.FingerprintSHA256(key)
+	resp, err := cb(core.CredentialRequest{
+		Type:        core.CredentialHostKey,
+		Host:        host,
+		Prompt:      fmt.Sprintf("The authenticity of host '%s' can't be established. %s key fingerprint is %s.", hostname, key.Type(), fingerprint),
+		KeyType:     key.Type(),
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		return err
+	}
+	switch value := strings.ToLower(strings.TrimSpace(resp.Value)); {
+	case resp.Cancelled:
+		return core.ErrCredentialCancelled
+	case value == core.HostKeyAccept:
+		return Append(path, hostname, key)
+	case value == core.HostKeyAcceptOnce:
+		slog.Info("host key accepted for this connection", "host", host, "fingerprint", fingerprint)
+		return nil
+	default:
+		return fmt.Errorf("host key for %s rejected: %w", hostname, unknown)
+	}
+}
+
+// Append は hostname（"host:port"）の鍵を known_hosts に追記する。
+func Append(path, hostname string, key ssh.PublicKey) error {
+	mu.Lock()
+	defer mu.Unlock()
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open known_hosts: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := fmt.Fprintln(f, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)); err != nil {
+		return fmt.Errorf("failed to write known_hosts: %w", err)
+	}
+	slog.Info("host key added to known_hosts", "host", hostname, "fingerprint", ssh.FingerprintSHA256(key))
+	return nil
+}
+
+// ensureFile は known_hosts が存在しない場合に空ファイルを作成する。
+func ensureFile(path string) error {
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		return nil
+	}
+	slog.Warn("known_hosts file not found, creating empty file", "path", path)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create .ssh directory: %w", err)
+	}
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		return fmt.Errorf("failed to create known_hosts: %w", err)
+	}
+	return nil
+}
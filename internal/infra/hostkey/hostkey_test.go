@@ -0,0 +1,126 @@
+package hostkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func newKey(t *testing.T) ssh.PublicKey {
+	t.Helper()
+	pub, _, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	key, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		t.Fatalf("NewPublicKey: %v", err)
+	}
+	return key
+}
+
+var remote = &net.TCPAddr{IP: net.ParseIP("192.0.2.1"), Port: 2222}
+
+// answer は value で応答し、受け取った要求を req に記録するコールバックを返す。
+func answer(value string, req *core.CredentialRequest) core.CredentialCallback {
+	return func(r core.CredentialRequest) (core.CredentialResponse, error) {
+		*req = r
+		return core.CredentialResponse{Value: value}, nil
+	}
+}
+
+func TestCallback_UnknownKey(t *testing.T) {
+	tests := []struct {
+		name      string
+		strict    string
+		value     string
+		wantErr   bool
+		wantSaved bool
+	}{
+		{"accept and save", "", " YES\n", false, true},
+		{"accept once", "ask", core.HostKeyAcceptOnce, false, false},
+		{"reject", "", "no", true, false},
+		{"strict yes never asks", "yes", core.HostKeyAccept, true, false},
+		{"accept-new saves without asking", "accept-new", "no", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".ssh", "known_hosts")
+			key := newKey(t)
+			var req core.CredentialRequest
+			check, err := Callback(path, "prod", tt.strict, answer(tt.value, &req))
+			if err != nil {
+				t.Fatalf("Callback() error = %v", err)
+			}
+			if err := check("example.com:2222", remote, key); (err != nil) != tt.wantErr {
+				t.Fatalf("check error = %v, wantErr %v", err, tt.wantErr)
+			}
+			data, _ := os.ReadFile(path)
+			if saved := strings.Contains(string(data), "[example.com]:2222"); saved != tt.wantSaved {
+				t.Errorf("known_hosts saved = %v, want %v (%q)", saved, tt.wantSaved, data)
+			}
+			asked := req.Type == core.CredentialHostKey
+			if wantAsked := tt.strict == "" || tt.strict == "ask"; asked != wantAsked {
+				t.Errorf("asked = %v, want %v", asked, wantAsked)
+			} else if asked && (req.Host != "prod" || req.Fingerprint != ssh.FingerprintSHA256(key)) {
+				t.Errorf("request = %+v, want host prod with fingerprint", req)
+			}
+		})
+	}
+}
+
+func TestCallback_KnownAndMismatchedKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	key := newKey(t)
+	if err := Append(path, "example.com:22", key); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+	asked := false
+	check, err := Callback(path, "prod", "", func(core.CredentialRequest) (core.CredentialResponse, error) {
+		asked = true
+		return core.CredentialResponse{Value: core.HostKeyAccept}, nil
+	})
+	if err != nil {
+		t.Fatalf("Callback() error = %v", err)
+	}
+	if err := check("example.com:22", remote, key); err != nil {
+		t.Errorf("known key error = %v", err)
+	}
+	if err := check("example.com:22", remote, newKey(t)); err == nil || asked {
+		t.Errorf("mismatched key error = %v, asked = %v; want rejection without asking", err, asked)
+	}
+}
+
+func TestCallback_CancelledAndNoCallback(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "known_hosts")
+	cancel := func(core.CredentialRequest) (core.CredentialResponse, error) {
+		return core.CredentialResponse{Cancelled: true}, nil
+	}
+	check, _ := Callback(path, "prod", "", cancel)
+	if err := check("example.com:22", remote, newKey(t)); !errors.Is(err, core.ErrCredentialCancelled) {
+		t.Errorf("cancelled error = %v, want ErrCredentialCancelled", err)
+	}
+	check, _ = Callback(path, "prod", "", nil)
+	if err := check("example.com:22", remote, newKey(t)); err == nil {
+		t.Error("unknown key without callback should be rejected")
+	}
+}
+
+func TestCallback_StrictNo(t *testing.T) {
+	check, err := Callback(filepath.Join(t.TempDir(), "missing", "known_hosts"), "prod", "no", nil)
+	if err != nil {
+		t.Fatalf("Callback() error = %v", err)
+	}
+	if err := check("example.com:22", remote, newKey(t)); err != nil {
+		t.Errorf("StrictHostKeyChecking=no should accept any key, got %v", err)
+	}
+}
@@ -0,0 +1,292 @@
+package mdns
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"net"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+const (
+	// ServiceType は公開するサービスの種別。
+	ServiceType = "_moleport._tcp.local."
+	// servicesName は DNS-SD でサービスの種別を列挙するための名前。
+	servicesName = "_services._dns-sd._udp.local."
+	// PollInterval は設定とフォワードの状態を確認する間隔。
+	PollInterval = 2 * time.Second
+	// recordTTL は公開するレコードの TTL（秒）。
+	recordTTL = 120
+	// maxPacketSize は受信する UDP メッセージの最大長。
+	maxPacketSize = 9000
+)
+
+// group は mDNS の IPv4 マルチキャストアドレス。
+var group = &net.UDPAddr{IP: net.IPv4(224, 0, 0, 251), Port: 5353}
+
+// Service は mDNS で公開するフォワード。
+type Service struct {
+	Instance string // サービスインスタンス名（"<ルール名>@<ホスト名>"）
+	Port     int
+	TXT      []string
+}
+
+// Services は sessions のうちアクティブなローカル/ダイナミックフォワードを、hostname のマシンで公開する Service に変換する。
+// リモートフォワードはローカルでリッスンしないため対象外とする。
+func Services(sessions []core.ForwardSession, hostname string) []Service {
+	var services []Service
+	for _, s := range sessions {
+		if s.Status != core.Active || s.Rule.Type == core.Remote {
+			continue
+		}
+		txt := []string{"rule=" + s.Rule.Name, "host=" + s.Rule.Host, "type=" + s.Rule.Type.String(), "bind=" + s.Rule.LocalBindHost()}
+		if s.Rule.Type == core.Local {
+			txt = append(txt, "remote="+net.JoinHostPort(s.Rule.RemoteHost, strconv.Itoa(s.Rule.RemotePort)))
+		}
+		services = append(services, Service{Instance: s.Rule.Name + "@" + hostname, Port: s.ListenPort(), TXT: txt})
+	}
+	return services
+}
+
+// Advertiser はアクティブなフォワードを mDNS で公開し、クエリに応答する。
+type Advertiser struct {
+	enabled  func() bool
+	sessions func() []core.ForwardSession
+	hostname string          // ".local." を除くホスト名
+	addrs    func() []net.IP // A レコードで公開するアドレス
+
+	// conn・done・lastErr は Run のゴルーチンのみが参照する。
+	conn    *net.UDPConn
+	done    chan struct{}
+	lastErr string
+
+	mu       sync.Mutex
+	services map[string]Service // 小文字化したインスタンスの FQDN → 公開中のサービス
+}
+
+// New は enabled が true の間 sessions のアクティブなフォワードを公開する Advertiser を返す。
+func New(enabled func() bool, sessions func() []core.ForwardSession) *Advertiser {
+	return &Advertiser{enabled: enabled, sessions: sessions, hostname: localHostname(), addrs: localAddrs}
+}
+
+// Run は ctx がキャンセルされるまで PollInterval ごとに設定とフォワードの状態を確認し、公開するサービスを更新する。
+// 無効になった時と終了時は、公開中のサービスの削除（TTL 0 のレコード）を通知してからソケットを閉じる。
+func (a *Advertiser) Run(ctx context.Context) {
+	t := time.NewTicker(PollInterval)
+	defer t.Stop()
+	defer a.stop()
+	for {
+		a.refresh()
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+	}
+}
+
+// refresh は公開中のサービスを現在のフォワードに合わせ、追加・変更したサービスを告知し、なくなったサービスの削除を通知する。
+func (a *Advertiser) refresh() {
+	if !a.enabled() {
+		a.stop()
+		return
+	}
+	if a.conn == nil && !a.listen() {
+		return
+	}
+	next := make(map[string]Service)
+	for _, s := range Services(a.sessions(), a.hostname) {
+		next[strings.ToLower(instanceName(s))] = s
+	}
+	var gone, changed []Service
+	a.mu.Lock()
+	for key, s := range a.services {
+		if _, ok := next[key]; !ok {
+			gone = append(gone, s)
+		}
+	}
+	for key, s := range next {
+		if old, ok := a.services[key]; !ok || old.Port != s.Port || !slices.Equal(old.TXT, s.TXT) {
+			changed = append(changed, s)
+		}
+	}
+	a.services = next
+	a.mu.Unlock()
+
+	if len(gone) > 0 {
+		a.send(buildResponse(0, serviceRecords(gone, a.hostFQDN(), 0), nil))
+	}
+	if len(changed) > 0 {
+		a.send(buildResponse(0, serviceRecords(changed, a.hostFQDN(), recordTTL), a.hostRecords()))
+	}
+}
+
+// listen は mDNS のマルチキャストグループに参加し、クエリへの応答を開始する。
+// 失敗した場合は次の確認で再試行し、同じエラーの警告は繰り返し記録しない。
+func (a *Advertiser) listen() bool {
+	conn, err := net.ListenMulticastUDP("udp4", nil, group)
+	if err != nil {
+		if err.Error() != a.lastErr {
+			slog.Warn("failed to start mdns advertiser", "error", err)
+			a.lastErr = err.Error()
+		}
+		return false
+	}
+	a.conn, a.done, a.lastErr = conn, make(chan struct{}), ""
+	go a.serve(conn, a.done)
+	slog.Info("mdns advertiser started", "service", ServiceType, "host", a.hostFQDN())
+	return true
+}
+
+// stop は公開中のサービスの削除を通知し、ソケットを閉じる。
+func (a *Advertiser) stop() {
+	if a.conn == nil {
+		return
+	}
+	a.mu.Lock()
+	gone := slices.Collect(maps.Values(a.services))
+	a.services = nil
+	a.mu.Unlock()
+	if len(gone) > 0 {
+		a.send(buildResponse(0, serviceRecords(gone, a.hostFQDN(), 0), nil))
+	}
+	_ = a.conn.Close()
+	<-a.done
+	a.conn = nil
+	slog.Info("mdns advertiser stopped")
+}
+
+// send は msg をマルチキャストグループに送信する。
+func (a *Advertiser) send(msg []byte) {
+	if _, err := a.conn.WriteToUDP(msg, group); err != nil {
+		slog.Debug("mdns send failed", "error", err)
+	}
+}
+
+// serve はクエリを受信し、公開中のサービスに関する質問にマルチキャストで応答する。
+func (a *Advertiser) serve(conn *net.UDPConn, done chan struct{}) {
+	defer close(done)
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				slog.Warn("mdns read failed", "error", err)
+			}
+			return
+		}
+		_, qs, err := parseQuery(buf[:n])
+		if err != nil || len(qs) == 0 {
+			continue
+		}
+		if answers, additional := a.answer(qs); len(answers) > 0 {
+			if _, err := conn.WriteToUDP(buildResponse(0, answers, additional), group); err != nil {
+				slog.Debug("mdns send failed", "error", err)
+			}
+		}
+	}
+}
+
+// answer は質問に対する応答のレコードと追加のレコードを返す。公開中のサービスがなければ応答しない。
+func (a *Advertiser) answer(qs []question) (answers, additional []record) {
+	a.mu.Lock()
+	services := maps.Clone(a.services)
+	a.mu.Unlock()
+	if len(services) == 0 {
+		return nil, nil
+	}
+	host := a.hostFQDN()
+	all := slices.Collect(maps.Values(services))
+	for _, q := range qs {
+		wants := func(rtype uint16) bool { return q.qtype == rtype || q.qtype == typeANY }
+		switch s, ok := services[q.name]; {
+		case q.name == servicesName && wants(typePTR):
+			answers = append(answers, record{name: servicesName, rtype: typePTR, ttl: recordTTL, data: encodeName(ServiceType)})
+		case q.name == ServiceType && wants(typePTR):
+			for _, r := range serviceRecords(all, host, recordTTL) {
+				if r.rtype == typePTR {
+					answers = append(answers, r)
+				} else {
+					additional = append(additional, r)
+				}
+			}
+			additional = append(additional, a.hostRecords()...)
+		case q.name == host && wants(typeA):
+			answers = append(answers, a.hostRecords()...)
+		case ok:
+			for _, r := range serviceRecords([]Service{s}, host, recordTTL) {
+				if r.rtype != typePTR && wants(r.rtype) {
+					answers = append(answers, r)
+				}
+			}
+			additional = append(additional, a.hostRecords()...)
+		}
+	}
+	return answers, additional
+}
+
+// serviceRecords は services の PTR・SRV・TXT レコードを返す。ttl が 0 のレコードはサービスの削除を表す。
+func serviceRecords(services []Service, host string, ttl uint32) []record {
+	var records []record
+	for _, s := range services {
+		name := instanceName(s)
+		records = append(records,
+			record{name: ServiceType, rtype: typePTR, ttl: ttl, data: encodeName(name)},
+			record{name: name, rtype: typeSRV, flush: true, ttl: ttl, data: srvData(s.Port, host)},
+			record{name: name, rtype: typeTXT, flush: true, ttl: ttl, data: txtData(s.TXT)},
+		)
+	}
+	return records
+}
+
+// hostRecords はホスト名の A レコードを返す。
+func (a *Advertiser) hostRecords() []record {
+	var records []record
+	for _, ip := range a.addrs() {
+		records = append(records, record{name: a.hostFQDN(), rtype: typeA, flush: true, ttl: recordTTL, data: ip.To4()})
+	}
+	return records
+}
+
+// hostFQDN は SRV レコードのターゲットにするホスト名（"<hostname>.local."）を返す。
+func (a *Advertiser) hostFQDN() string {
+	return a.hostname + ".local."
+}
+
+// instanceName はサービスインスタンスの FQDN を返す。
+func instanceName(s Service) string {
+	return s.Instance + "." + ServiceType
+}
+
+// localHostname は os.Hostname の最初のラベルを小文字で返す。取得できない場合は "moleport" を返す。
+func localHostname() string {
+	name, err := os.Hostname()
+	name, _, _ = strings.Cut(strings.ToLower(name), ".")
+	if err != nil || name == "" {
+		return "moleport"
+	}
+	return name
+}
+
+// localAddrs はループバック以外の IPv4 アドレスを返す。
+func localAddrs() []net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+	var ips []net.IP
+	for _, addr := range addrs {
+		if ipnet, ok := addr.(*net.IPNet); ok && !ipnet.IP.IsLoopback() && ipnet.IP.To4() != nil {
+			ips = append(ips, ipnet.IP.To4())
+		}
+	}
+	return ips
+}
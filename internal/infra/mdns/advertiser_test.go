@@ -0,0 +1,183 @@
+package mdns
+
+import (
+	"encoding/binary"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// parsedRecord は応答から読み取ったレコード。
+type parsedRecord struct {
+	name  string
+	rtype uint16
+	ttl   uint32
+	data  []byte
+}
+
+// parseResponse は buildResponse の応答の応答セクションと追加セクションのレコードを返す。
+func parseResponse(t *testing.T, msg []byte) (answers, additional []parsedRecord) {
+	t.Helper()
+	an, ar := int(binary.BigEndian.Uint16(msg[6:8])), int(binary.BigEndian.Uint16(msg[10:12]))
+	off := headerLen
+	var records []parsedRecord
+	for range an + ar {
+		name, next, err := readName(msg, off)
+		if err != nil {
+			t.Fatalf("readName: %v", err)
+		}
+		n := int(binary.BigEndian.Uint16(msg[next+8 : next+10]))
+		records = append(records, parsedRecord{
+			name:  name,
+			rtype: binary.BigEndian.Uint16(msg[next : next+2]),
+			ttl:   binary.BigEndian.Uint32(msg[next+4 : next+8]),
+			data:  msg[next+10 : next+10+n],
+		})
+		off = next + 10 + n
+	}
+	return records[:an], records[an:]
+}
+
+// queryFor は name と qtype の質問を持つクエリを返す。
+func queryFor(name string, qtype uint16) []byte {
+	msg := make([]byte, headerLen)
+	binary.BigEndian.PutUint16(msg[4:6], 1)
+	msg = append(msg, encodeName(name)...)
+	msg = binary.BigEndian.AppendUint16(msg, qtype)
+	return binary.BigEndian.AppendUint16(msg, classIN|0x8000)
+}
+
+// testAdvertiser は "web"（ポート 8080）を公開中の Advertiser を返す。
+func testAdvertiser() *Advertiser {
+	a := &Advertiser{hostname: "laptop", addrs: func() []net.IP { return []net.IP{net.IPv4(192, 168, 1, 10)} }}
+	s := Service{Instance: "web@laptop", Port: 8080, TXT: []string{"rule=web"}}
+	a.services = map[string]Service{"web@laptop." + ServiceType: s}
+	return a
+}
+
+func TestServices(t *testing.T) {
+	sessions := []core.ForwardSession{
+		{Rule: core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}, Status: core.Active},
+		{Rule: core.ForwardRule{Name: "socks", Host: "prod", Type: core.Dynamic, LocalBindAddr: "0.0.0.0"}, Status: core.Active, BoundPort: 1080},
+		{Rule: core.ForwardRule{Name: "api", Host: "prod", Type: core.Local, LocalPort: 9000}, Status: core.Stopped},
+		{Rule: core.ForwardRule{Name: "back", Host: "prod", Type: core.Remote, LocalPort: 3000}, Status: core.Active},
+	}
+	got := Services(sessions, "laptop")
+	if len(got) != 2 {
+		t.Fatalf("Services = %+v, want web and socks", got)
+	}
+	wantTXT := []string{"rule=web", "host=prod", "type=local", "bind=127.0.0.1", "remote=localhost:80"}
+	if got[0].Instance != "web@laptop" || got[0].Port != 8080 || !slices.Equal(got[0].TXT, wantTXT) {
+		t.Errorf("web = %+v, want port 8080 and TXT %v", got[0], wantTXT)
+	}
+	wantTXT = []string{"rule=socks", "host=prod", "type=dynamic", "bind=0.0.0.0"}
+	if got[1].Instance != "socks@laptop" || got[1].Port != 1080 || !slices.Equal(got[1].TXT, wantTXT) {
+		t.Errorf("socks = %+v, want port 1080 and TXT %v", got[1], wantTXT)
+	}
+}
+
+func TestAdvertiser_AnswerBrowse(t *testing.T) {
+	a := testAdvertiser()
+	_, qs, err := parseQuery(queryFor("_MolePort._tcp.local.", typePTR))
+	if err != nil {
+		t.Fatalf("parseQuery: %v", err)
+	}
+	ans, add := a.answer(qs)
+	answers, additional := parseResponse(t, buildResponse(0, ans, add))
+
+	if len(answers) != 1 || answers[0].rtype != typePTR || answers[0].name != ServiceType {
+		t.Fatalf("answers = %+v, want one PTR", answers)
+	}
+	if target, _, _ := readName(answers[0].data, 0); target != "web@laptop."+ServiceType {
+		t.Errorf("PTR target = %q", target)
+	}
+	var types []uint16
+	for _, r := range additional {
+		types = append(types, r.rtype)
+	}
+	if !slices.Equal(types, []uint16{typeSRV, typeTXT, typeA}) {
+		t.Fatalf("additional types = %v, want SRV, TXT, A", types)
+	}
+	if port := binary.BigEndian.Uint16(additional[0].data[4:6]); port != 8080 {
+		t.Errorf("SRV port = %d, want 8080", port)
+	}
+	if target, _, _ := readName(additional[0].data, 6); target != "laptop.local." {
+		t.Errorf("SRV target = %q, want laptop.local.", target)
+	}
+	if string(additional[1].data) != "\x08rule=web" {
+		t.Errorf("TXT = %q", additional[1].data)
+	}
+	if !net.IP(additional[2].data).Equal(net.IPv4(192, 168, 1, 10)) || additional[2].ttl != recordTTL {
+		t.Errorf("A = %v (ttl %d)", net.IP(additional[2].data), additional[2].ttl)
+	}
+}
+
+func TestAdvertiser_AnswerInstanceAndHost(t *testing.T) {
+	a := testAdvertiser()
+	tests := []struct {
+		name  string
+		qname string
+		qtype uint16
+		want  []uint16
+	}{
+		{"instance TXT", "web@laptop." + ServiceType, typeTXT, []uint16{typeTXT}},
+		{"instance ANY", "web@laptop." + ServiceType, typeANY, []uint16{typeSRV, typeTXT}},
+		{"host A", "Laptop.local.", typeA, []uint16{typeA}},
+		{"service types", servicesName, typePTR, []uint16{typePTR}},
+		{"unknown instance", "db@laptop." + ServiceType, typeSRV, nil},
+		{"other service", "_http._tcp.local.", typePTR, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, qs, err := parseQuery(queryFor(tt.qname, tt.qtype))
+			if err != nil {
+				t.Fatalf("parseQuery: %v", err)
+			}
+			answers, _ := a.answer(qs)
+			var got []uint16
+			for _, r := range answers {
+				got = append(got, r.rtype)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("answer types = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServiceRecords_Goodbye(t *testing.T) {
+	for _, r := range serviceRecords([]Service{{Instance: "web@laptop", Port: 8080}}, "laptop.local.", 0) {
+		if r.ttl != 0 {
+			t.Errorf("%d record ttl = %d, want 0", r.rtype, r.ttl)
+		}
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	// 2 つ目の質問の名前は 1 つ目の "_tcp.local." を指す圧縮ポインタを含む
+	msg := queryFor("_moleport._tcp.local.", typePTR)
+	binary.BigEndian.PutUint16(msg[4:6], 2)
+	msg = append(msg, 4, '_', 's', 's', 'h', 0xC0, byte(headerLen+10))
+	msg = binary.BigEndian.AppendUint16(msg, typePTR)
+	msg = binary.BigEndian.AppendUint16(msg, classIN)
+	_, qs, err := parseQuery(msg)
+	if err != nil {
+		t.Fatalf("parseQuery: %v", err)
+	}
+	if len(qs) != 2 || qs[0].name != ServiceType || qs[1].name != "_ssh._tcp.local." {
+		t.Errorf("questions = %+v", qs)
+	}
+
+	// 応答は無視する
+	if _, qs, err := parseQuery(buildResponse(0, nil, nil)); err != nil || qs != nil {
+		t.Errorf("parseQuery(response) = %+v, %v, want no questions", qs, err)
+	}
+	// ループする圧縮ポインタ
+	loop := append(make([]byte, headerLen), 0xC0, byte(headerLen))
+	binary.BigEndian.PutUint16(loop[4:6], 1)
+	if _, _, err := parseQuery(loop); err == nil {
+		t.Error("parseQuery(pointer loop) succeeded, want error")
+	}
+}
@@ -0,0 +1,4 @@
+// Package mdns はアクティブなローカル/ダイナミックフォワードを mDNS（RFC 6762）と DNS-SD（RFC 6763）の
+// _moleport._tcp サービスとして LAN に公開する。
+// 依存を増やさないため、公開とクエリへの応答に必要な最小限の DNS メッセージのみを自前で解析・生成する。
+package mdns
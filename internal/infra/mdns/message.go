@@ -0,0 +1,160 @@
+package mdns
+
+import (
+	"encoding/binary"
+	"errors"
+	"slices"
+	"strings"
+)
+
+// DNS レコード種別とクラス。
+const (
+	typeA   uint16 = 1
+	typePTR uint16 = 12
+	typeTXT uint16 = 16
+	typeSRV uint16 = 33
+	typeANY uint16 = 255
+
+	classIN uint16 = 1
+	// classMask は質問のクラスから QU（ユニキャスト応答の要求）ビットを除くマスク。
+	classMask uint16 = 0x7FFF
+	// cacheFlush は一意なレコード（SRV・TXT・A）のクラスに立てるキャッシュフラッシュビット。
+	cacheFlush uint16 = 0x8000
+)
+
+const (
+	headerLen = 12
+	// maxPointers は 1 つの名前でたどる圧縮ポインタの上限。ループするポインタを打ち切る。
+	maxPointers = 16
+)
+
+var errMalformed = errors.New("malformed mdns message")
+
+// question は受信したクエリの質問。
+type question struct {
+	name  string // 小文字化した FQDN（末尾 "." 付き）
+	qtype uint16
+}
+
+// parseQuery は msg のクエリ ID と IN クラスの質問を返す。応答（QR=1）の場合は質問を返さない。
+func parseQuery(msg []byte) (uint16, []question, error) {
+	if len(msg) < headerLen {
+		return 0, nil, errMalformed
+	}
+	id := binary.BigEndian.Uint16(msg[0:2])
+	if msg[2]&0x80 != 0 {
+		return id, nil, nil
+	}
+	var qs []question
+	off := headerLen
+	for range binary.BigEndian.Uint16(msg[4:6]) {
+		name, next, err := readName(msg, off)
+		if err != nil {
+			return id, nil, err
+		}
+		if next+4 > len(msg) {
+			return id, nil, errMalformed
+		}
+		class := binary.BigEndian.Uint16(msg[next+2:next+4]) & classMask
+		if class == classIN || class == typeANY {
+			qs = append(qs, question{name: name, qtype: binary.BigEndian.Uint16(msg[next : next+2])})
+		}
+		off = next + 4
+	}
+	return id, qs, nil
+}
+
+// readName は off から始まる名前を読み取り、小文字化した FQDN と名前の直後のオフセットを返す。圧縮ポインタに対応する。
+func readName(msg []byte, off int) (string, int, error) {
+	var labels []string
+	end := -1
+	for pointers := 0; ; {
+		if off >= len(msg) {
+			return "", 0, errMalformed
+		}
+		n := int(msg[off])
+		switch {
+		case n == 0:
+			if end < 0 {
+				end = off + 1
+			}
+			return strings.ToLower(strings.Join(labels, ".")) + ".", end, nil
+		case n&0xC0 == 0xC0:
+			if off+2 > len(msg) || pointers == maxPointers {
+				return "", 0, errMalformed
+			}
+			if end < 0 {
+				end = off + 2
+			}
+			off = int(binary.BigEndian.Uint16(msg[off:off+2]) & 0x3FFF)
+			pointers++
+		case n > 63 || off+1+n > len(msg):
+			return "", 0, errMalformed
+		default:
+			labels = append(labels, string(msg[off+1:off+1+n]))
+			off += 1 + n
+		}
+	}
+}
+
+// record は送信するリソースレコード。
+type record struct {
+	name  string // FQDN（末尾 "." 付き）
+	rtype uint16
+	flush bool // キャッシュフラッシュビットを立てる一意なレコードか
+	ttl   uint32
+	data  []byte
+}
+
+// buildResponse は answers と additional を持つ応答メッセージ（QR=1, AA=1）を組み立てる。
+func buildResponse(id uint16, answers, additional []record) []byte {
+	msg := make([]byte, headerLen, 512)
+	binary.BigEndian.PutUint16(msg[0:2], id)
+	binary.BigEndian.PutUint16(msg[2:4], 0x8400)
+	binary.BigEndian.PutUint16(msg[6:8], uint16(len(answers)))
+	binary.BigEndian.PutUint16(msg[10:12], uint16(len(additional)))
+	for _, r := range slices.Concat(answers, additional) {
+		msg = append(msg, encodeName(r.name)...)
+		class := classIN
+		if r.flush {
+			class |= cacheFlush
+		}
+		msg = binary.BigEndian.AppendUint16(msg, r.rtype)
+		msg = binary.BigEndian.AppendUint16(msg, class)
+		msg = binary.BigEndian.AppendUint32(msg, r.ttl)
+		msg = binary.BigEndian.AppendUint16(msg, uint16(len(r.data)))
+		msg = append(msg, r.data...)
+	}
+	return msg
+}
+
+// encodeName は FQDN をラベル形式にエンコードする。
+func encodeName(fqdn string) []byte {
+	var b []byte
+	for label := range strings.SplitSeq(strings.TrimSuffix(fqdn, "."), ".") {
+		if label == "" {
+			continue
+		}
+		b = append(b, byte(len(label)))
+		b = append(b, label...)
+	}
+	return append(b, 0)
+}
+
+// srvData は SRV レコードの RDATA（priority=0, weight=0）を返す。
+func srvData(port int, target string) []byte {
+	b := make([]byte, 6, 6+len(target)+2)
+	binary.BigEndian.PutUint16(b[4:6], uint16(port))
+	return append(b, encodeName(target)...)
+}
+
+// txtData は TXT レコードの RDATA を返す。255 バイトを超える文字列は切り詰める。
+func txtData(entries []string) []byte {
+	var b []byte
+	for _, e := range entries {
+		e = e[:min(len(e), 255)]
+		b = append(b, byte(len(e)))
+		b = append(b, e...)
+	}
+	return b
+}
@@ -0,0 +1,20 @@
+//go:build !windows
+
+package proxycommand
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// shellCommand は command を sh -c で実行するコマンドを返す。子プロセスごと終了できるよう新しいプロセスグループで起動する。
+func shellCommand(command string) *exec.Cmd {
+	cmd := exec.Command("sh", "-c", command) //nolint:gosec // ProxyCommand は SSH config 由来のユーザー設定値
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	return cmd
+}
+
+// killTree は cmd のプロセスグループを SIGKILL で強制終了する。
+func killTree(cmd *exec.Cmd) {
+	_ = syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
@@ -0,0 +1,26 @@
+package proxycommand
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// shellCommand は command を cmd /C で実行するコマンドを返す。コンソールウィンドウは表示しない。
+func shellCommand(command string) *exec.Cmd {
+	cmd := exec.Command("cmd", "/C", command) //nolint:gosec // ProxyCommand は SSH config 由来のユーザー設定値
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: windows.CREATE_NO_WINDOW}
+	return cmd
+}
+
+// killTree は cmd のプロセスを子プロセスごと taskkill で強制終了する。失敗した場合は cmd のプロセスのみを終了する。
+func killTree(cmd *exec.Cmd) {
+	pid := strconv.Itoa(cmd.Process.Pid)
+	kill := exec.Command("taskkill", "/T", "/F", "/PID", pid)
+	kill.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: windows.CREATE_NO_WINDOW}
+	if err := kill.Run(); err != nil {
+		_ = cmd.Process.Kill()
+	}
+}
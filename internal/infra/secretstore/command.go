@@ -0,0 +1,92 @@
+//go:build !windows
+
+package secretstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// commandTimeout はキーチェーンのコマンド 1 回あたりの待ち時間。
+const commandTimeout = 10 * time.Second
+
+// run は stdin を標準入力に渡してコマンドを実行し、標準出力を返す。
+// 出力なしで異常終了した場合は ErrNotFound を返す。テストで差し替える。
+var run = func(stdin, name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	out, err := cmd.Output()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(out) == 0 {
+		return "", ErrNotFound
+	}
+	return string(out), err
+}
+
+// commandStore は macOS の security、Linux の secret-tool（Secret Service）を呼び出す SecretStore。
+type commandStore struct{}
+
+// New は実行中の OS のキーチェーンを使う SecretStore を返す。
+func New() SecretStore {
+	return commandStore{}
+}
+
+// Get は account のシークレットを返す。
+func (commandStore) Get(account string) (string, error) {
+	var out string
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		out, err = run("", "security", "find-generic-password", "-s", Service, "-a", account, "-w")
+	case "linux":
+		out, err = run("", "secret-tool", "lookup", "service", Service, "account", account)
+	default:
+		return "", unsupported()
+	}
+	return strings.TrimRight(out, "\r\n"), err
+}
+
+// Set は account のシークレットを保存する。
+func (commandStore) Set(account, secret string) error {
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		_, err = run("", "security", "add-generic-password", "-U", "-s", Service, "-a", account, "-w", secret)
+	case "linux":
+		_, err = run(secret, "secret-tool", "store", "--label=MolePort "+account, "service", Service, "account", account)
+	default:
+		return unsupported()
+	}
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("failed to store secret %q", account)
+	}
+	return err
+}
+
+// Delete は account のシークレットを削除する。
+func (commandStore) Delete(account string) error {
+	var err error
+	switch runtime.GOOS {
+	case "darwin":
+		_, err = run("", "security", "delete-generic-password", "-s", Service, "-a", account)
+	case "linux":
+		// secret-tool clear は該当がなくても成功するため、先に存在を確認する
+		if _, err = (commandStore{}).Get(account); err == nil {
+			_, err = run("", "secret-tool", "clear", "service", Service, "account", account)
+		}
+	default:
+		return unsupported()
+	}
+	return err
+}
+
+func unsupported() error {
+	return fmt.Errorf("keychain is not supported on %s", runtime.GOOS)
+}
@@ -0,0 +1,41 @@
+//go:build linux
+
+package secretstore
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCommandStore_SecretTool(t *testing.T) {
+	orig := run
+	t.Cleanup(func() { run = orig })
+	var calls [][]string
+	var stdins []string
+	run = func(stdin, name string, args ...string) (string, error) {
+		calls = append(calls, append([]string{name}, args...))
+		stdins = append(stdins, stdin)
+		if args[0] == "lookup" && args[len(args)-1] == "missing" {
+			return "", ErrNotFound
+		}
+		return "s3cret\n", nil
+	}
+
+	s := New()
+	if v, err := s.Get("key"); err != nil || v != "s3cret" {
+		t.Errorf("Get() = (%q, %v), want s3cret", v, err)
+	}
+	if err := s.Set("key", "typed"); err != nil || stdins[1] != "typed" {
+		t.Errorf("Set() error = %v, stdin = %q; want secret on stdin", err, stdins[1])
+	}
+	if want := []string{"secret-tool", "store", "--label=MolePort key", "service", Service, "account", "key"}; !reflect.DeepEqual(calls[1], want) {
+		t.Errorf("Set() command = %v, want %v", calls[1], want)
+	}
+	if err := s.Delete("missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete("key"); err != nil || calls[len(calls)-1][1] != "clear" {
+		t.Errorf("Delete() error = %v, last command = %v", err, calls[len(calls)-1])
+	}
+}
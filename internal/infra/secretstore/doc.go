@@ -0,0 +1,3 @@
+// Package secretstore は macOS Keychain・Secret Service・Windows Credential Manager に
+// MolePort のシークレットを保存する SecretStore を提供する。
+package secretstore
@@ -0,0 +1,121 @@
+package secretstore
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Service は OS のキーチェーンにシークレットを保存するサービス名。
+const Service = "moleport"
+
+// indexAccount は Remember で保存したアカウントの一覧を保持するエントリ。
+const indexAccount = "moleport-index"
+
+// ErrNotFound はアカウントのシークレットが保存されていないことを示す。
+var ErrNotFound = errors.New("secret not found")
+
+// SecretStore は OS のキーチェーンにアカウント名で識別するシークレットを保存する。
+type SecretStore interface {
+	// Get は account のシークレットを返す。保存されていない場合は ErrNotFound を返す。
+	Get(account string) (string, error)
+	// Set は account のシークレットを保存する。既存の値は上書きする。
+	Set(account, secret string) error
+	// Delete は account のシークレットを削除する。保存されていない場合は ErrNotFound を返す。
+	Delete(account string) error
+}
+
+// indexMu は索引の読み書きを直列化する。
+var indexMu sync.Mutex
+
+// Remember は account のシークレットを保存し、Clear で削除できるよう索引に記録する。
+func Remember(s SecretStore, account, secret string) error {
+	if err := s.Set(account, secret); err != nil {
+		return err
+	}
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	accounts, err := index(s)
+	if err != nil || slices.Contains(accounts, account) {
+		return err
+	}
+	return s.Set(indexAccount, strings.Join(append(accounts, account), "\n"))
+}
+
+// Clear は Remember で保存したシークレットと索引を削除し、削除した件数を返す。
+func Clear(s SecretStore) (int, error) {
+	indexMu.Lock()
+	defer indexMu.Unlock()
+	accounts, err := index(s)
+	if err != nil {
+		return 0, err
+	}
+	cleared := 0
+	for _, account := range accounts {
+		switch err := s.Delete(account); {
+		case err == nil:
+			cleared++
+		case !errors.Is(err, ErrNotFound):
+			return cleared, fmt.Errorf("failed to delete secret %q: %w", account, err)
+		}
+	}
+	if err := s.Delete(indexAccount); err != nil && !errors.Is(err, ErrNotFound) {
+		return cleared, err
+	}
+	return cleared, nil
+}
+
+// index は索引に記録されたアカウントの一覧を返す。
+func index(s SecretStore) ([]string, error) {
+	v, err := s.Get(indexAccount)
+	if errors.Is(err, ErrNotFound) || v == "" {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return strings.Split(v, "\n"), nil
+}
+
+// Memory はプロセス内のみでシークレットを保持する SecretStore。テストや OS のキーチェーンを使えない環境で使う。
+type Memory struct {
+	mu      sync.Mutex
+	secrets map[string]string
+}
+
+// NewMemory は空の Memory を返す。
+func NewMemory() *Memory {
+	return &Memory{secrets: make(map[string]string)}
+}
+
+// Get は account のシークレットを返す。
+func (m *Memory) Get(account string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	v, ok := m.secrets[account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+// Set は account のシークレットを保存する。
+func (m *Memory) Set(account, secret string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[account] = secret
+	return nil
+}
+
+// Delete は account のシークレットを削除する。
+func (m *Memory) Delete(account string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.secrets[account]; !ok {
+		return ErrNotFound
+	}
+	delete(m.secrets, account)
+	return nil
+}
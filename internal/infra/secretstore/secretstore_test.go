@@ -0,0 +1,37 @@
+package secretstore
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRememberAndClear(t *testing.T) {
+	s := NewMemory()
+	if err := s.Set("manual", "keep"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	for _, account := range []string{"a", "b", "a"} {
+		if err := Remember(s, account, "secret-"+account); err != nil {
+			t.Fatalf("Remember(%q) error = %v", account, err)
+		}
+	}
+	if v, err := s.Get("b"); err != nil || v != "secret-b" {
+		t.Errorf("Get(b) = (%q, %v), want secret-b", v, err)
+	}
+
+	// 索引にあるが既に削除されたエントリは件数に含めない
+	_ = s.Delete("b")
+	n, err := Clear(s)
+	if err != nil || n != 1 {
+		t.Errorf("Clear() = (%d, %v), want (1, nil)", n, err)
+	}
+	if _, err := s.Get("a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(a) after Clear error = %v, want ErrNotFound", err)
+	}
+	if v, _ := s.Get("manual"); v != "keep" {
+		t.Error("Clear() should not delete secrets that were not remembered")
+	}
+	if n, err := Clear(s); err != nil || n != 0 {
+		t.Errorf("second Clear() = (%d, %v), want (0, nil)", n, err)
+	}
+}
@@ -0,0 +1,112 @@
+package secretstore
+
+import (
+	"errors"
+	"syscall"
+	"unsafe"
+)
+
+// Windows Credential Manager の定数。
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	errorNotFound           = syscall.Errno(1168)
+)
+
+var (
+	advapi32       = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW  = advapi32.NewProc("CredReadW")
+	procCredWriteW = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+// credential は Win32 の CREDENTIALW 構造体。
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// wincredStore は Windows Credential Manager の汎用資格情報を使う SecretStore。
+type wincredStore struct{}
+
+// New は実行中の OS のキーチェーンを使う SecretStore を返す。
+func New() SecretStore {
+	return wincredStore{}
+}
+
+// target は account の資格情報のターゲット名を返す。
+func target(account string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(Service + ":" + account)
+}
+
+// Get は account のシークレットを返す。
+func (wincredStore) Get(account string) (string, error) {
+	name, err := target(account)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	if r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); r == 0 {
+		return "", mapErr(err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred))) //nolint:errcheck // CredFree は値を返さない
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+// Set は account のシークレットを保存する。
+func (wincredStore) Set(account, secret string) error {
+	name, err := target(account)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         name,
+		CredentialBlobSize: uint32(len(blob)), //nolint:gosec // シークレットは短い文字列
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	if r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0); r == 0 {
+		return mapErr(err)
+	}
+	return nil
+}
+
+// Delete は account のシークレットを削除する。
+func (wincredStore) Delete(account string) error {
+	name, err := target(account)
+	if err != nil {
+		return err
+	}
+	if r, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0); r == 0 {
+		return mapErr(err)
+	}
+	return nil
+}
+
+// mapErr は ERROR_NOT_FOUND を ErrNotFound に変換する。
+func mapErr(err error) error {
+	if errors.Is(err, errorNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
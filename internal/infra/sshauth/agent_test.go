@@ -0,0 +1,59 @@
+package sshauth
+
+import (
+	"net"
+	"path/filepath"
+	"testing"
+
+	"golang.org/x/crypto/ssh/agent"
+)
+
+// startAgent はテスト用の SSH エージェントを UNIX ソケットで起動し、そのパスを返す。
+func startAgent(t *testing.T) string {
+	t.Helper()
+	sock := filepath.Join(t.TempDir(), "agent.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix socket unavailable: %v", err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	keyring := agent.NewKeyring()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() { _ = agent.ServeAgent(keyring, conn) }()
+		}
+	}()
+	return sock
+}
+
+func TestTrySSHAgent_IdentityAgent(t *testing.T) {
+	sock := startAgent(t)
+	t.Setenv("SSH_AUTH_SOCK", sock)
+	t.Setenv("MOLEPORT_TEST_AGENT", sock)
+
+	tests := []struct {
+		identityAgent string
+		wantErr       bool
+	}{
+		{"", false},
+		{"SSH_AUTH_SOCK", false},
+		{"$MOLEPORT_TEST_AGENT", false},
+		{sock, false},
+		{"none", true},
+		{"$MOLEPORT_TEST_UNSET", true},
+		{filepath.Join(t.TempDir(), "missing.sock"), true},
+	}
+	for _, tt := range tests {
+		_, conn, err := trySSHAgent(tt.identityAgent)
+		if conn != nil {
+			_ = conn.Close()
+		}
+		if (err != nil) != tt.wantErr {
+			t.Errorf("trySSHAgent(%q) error = %v, wantErr %v", tt.identityAgent, err, tt.wantErr)
+		}
+	}
+}
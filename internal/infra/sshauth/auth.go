@@ -1,4 +1,4 @@
-package infra
+package sshauth
 
 import (
 	"errors"
@@ -8,34 +8,23 @@ import (
 	"net"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"golang.org/x/crypto/ssh"
 	"golang.org/x/crypto/ssh/agent"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/infra/credprovider"
 )
 
-// ExpandTilde は ~ をホームディレクトリに展開する。
-// "~/" または "~" のみ展開し、"~otheruser" パターンはそのまま返す。
-func ExpandTilde(path string) (string, error) {
-	if len(path) == 0 {
-		return path, nil
-	}
-	if path == "~" {
-		home := homeDir()
-		if home == "" {
-			return "", fmt.Errorf("failed to get home directory")
-		}
-		return home, nil
-	}
-	if len(path) >= 2 && path[0] == '~' && path[1] == '/' {
-		home := homeDir()
-		if home == "" {
-			return "", fmt.Errorf("failed to get home directory")
-		}
-		return filepath.Join(home, path[2:]), nil
+// homeDir はカレントユーザーのホームディレクトリを返す。
+// os.UserHomeDir が失敗した場合は HOME 環境変数にフォールバックする（infra.homeDir と同じ挙動）。
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return os.Getenv("HOME")
 	}
-	return path, nil
+	return home
 }
 
 // defaultKeyPaths は一般的な SSH 秘密鍵のパスを返す。
@@ -54,11 +43,21 @@ func defaultKeyPaths() []string {
 }
 
 // trySSHAgent は SSH エージェントからの認証メソッドと接続を取得する。
+// identityAgent は ssh_config の IdentityAgent で、"none" はエージェントを使わず、
+// 空または "SSH_AUTH_SOCK" は環境変数 SSH_AUTH_SOCK、"$VAR" は環境変数 VAR のソケットを使う。
 // 呼び出し元は返された net.Conn を適切にクローズする責任を持つ。
-func trySSHAgent() (ssh.AuthMethod, net.Conn, error) {
-	sock := os.Getenv("SSH_AUTH_SOCK")
+func trySSHAgent(identityAgent string) (ssh.AuthMethod, net.Conn, error) {
+	sock := identityAgent
+	switch {
+	case identityAgent == "none":
+		return nil, nil, fmt.Errorf("SSH agent disabled by IdentityAgent")
+	case identityAgent == "" || identityAgent == "SSH_AUTH_SOCK":
+		sock = os.Getenv("SSH_AUTH_SOCK")
+	case strings.HasPrefix(identityAgent, "$"):
+		sock = os.Getenv(identityAgent[1:])
+	}
 	if sock == "" {
-		return nil, nil, fmt.Errorf("SSH_AUTH_SOCK not set")
+		return nil, nil, fmt.Errorf("SSH agent socket not set")
 	}
 	conn, err := net.Dial("unix", sock)
 	if err != nil {
@@ -69,7 +68,8 @@ func trySSHAgent() (ssh.AuthMethod, net.Conn, error) {
 }
 
 // tryKeyFileWithPassphrase は秘密鍵ファイルから認証メソッドを取得する。
-// 鍵がパスフレーズで暗号化されている場合、コールバックを使ってパスフレーズを取得する。
+// 鍵がパスフレーズで暗号化されている場合、保存済みのパスフレーズを試し、
+// 復号できなければコールバックを使ってパスフレーズを取得する。
 func tryKeyFileWithPassphrase(path string, cb core.CredentialCallback, host core.SSHHost) (ssh.AuthMethod, error) {
 	keyData, err := os.ReadFile(filepath.Clean(path))
 	if err != nil {
@@ -77,42 +77,62 @@ func tryKeyFileWithPassphrase(path string, cb core.CredentialCallback, host core
 	}
 
 	signer, err := ssh.ParsePrivateKey(keyData)
-	if err != nil {
-		var passErr *ssh.PassphraseMissingError
-		if errors.As(err, &passErr) && cb != nil {
-			resp, cbErr := cb(core.CredentialRequest{
-				Type:   core.CredentialPassphrase,
-				Host:   host.Name,
-				Prompt: "Enter passphrase for key '" + path + "':",
-			})
-			if cbErr != nil {
-				return nil, fmt.Errorf("credential callback failed for %s: %w", path, cbErr)
-			}
-			if resp.Cancelled {
-				return nil, fmt.Errorf("passphrase input cancelled for %s", path)
-			}
-			signer, err = ssh.ParsePrivateKeyWithPassphrase(keyData, []byte(resp.Value))
-			if err != nil {
-				return nil, fmt.Errorf("failed to parse key file %s with passphrase: %w", path, err)
-			}
-			return ssh.PublicKeys(signer), nil
-		}
+	if err == nil {
+		return ssh.PublicKeys(signer), nil
+	}
+	var passErr *ssh.PassphraseMissingError
+	if !errors.As(err, &passErr) {
 		return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
 	}
+	if signer, ok := cachedSigner(path, keyData); ok {
+		return ssh.PublicKeys(signer), nil
+	}
+	if cb == nil {
+		return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+	}
+
+	resp, cbErr := cb(core.CredentialRequest{
+		Type:   core.CredentialPassphrase,
+		Host:   host.Name,
+		Prompt: "Enter passphrase for key '" + path + "':",
+	})
+	if cbErr != nil {
+		return nil, fmt.Errorf("credential callback failed for %s: %w", path, cbErr)
+	}
+	if resp.Cancelled {
+		return nil, fmt.Errorf("passphrase input cancelled for %s", path)
+	}
+	signer, err = ssh.ParsePrivateKeyWithPassphrase(keyData, []byte(resp.Value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key file %s with passphrase: %w", path, err)
+	}
+	// 取得元から得たパスフレーズはキーチェーンに複製しない
+	if host.Credentials == nil || host.Credentials.Passphrase == "" {
+		cachePassphrase(path, resp.Value)
+	}
 	return ssh.PublicKeys(signer), nil
 }
 
-// buildAuthMethods はホスト情報をもとに認証メソッドのリストを構築する。
+// BuildAuthMethods はホスト情報をもとに認証メソッドのリストを構築する。
 // SSH エージェントと鍵ファイルを組み合わせる。
 // cb が nil でない場合、パスフレーズ付き鍵・パスワード認証・keyboard-interactive 認証も追加する。
+// host.Credentials が設定されている場合、パスワード・パスフレーズは cb より先にその取得元を参照する。
 // 返される io.Closer は SSH エージェント接続を閉じるために使用する。
 // エージェントに接続しなかった場合は nil が返される。
-func buildAuthMethods(host core.SSHHost, cb core.CredentialCallback) ([]ssh.AuthMethod, io.Closer) {
+func BuildAuthMethods(host core.SSHHost, cb core.CredentialCallback) ([]ssh.AuthMethod, io.Closer) {
 	var methods []ssh.AuthMethod
 	var agentCloser io.Closer
+	ask := cb
+	if host.Credentials != nil {
+		if p, err := credprovider.New(*host.Credentials); err == nil {
+			cb = credprovider.Wrap(p, ask)
+		} else {
+			slog.Warn("invalid credential provider", "host", host.Name, "error", err)
+		}
+	}
 
 	// SSH エージェントを試行
-	if agentAuth, conn, err := trySSHAgent(); err == nil {
+	if agentAuth, conn, err := trySSHAgent(host.IdentityAgent); err == nil {
 		methods = append(methods, agentAuth)
 		agentCloser = conn
 	}
@@ -140,8 +160,8 @@ func buildAuthMethods(host core.SSHHost, cb core.CredentialCallback) ([]ssh.Auth
 		}
 	}
 
-	// パスワード認証（コールバックがある場合のみ）
-	if cb != nil {
+	// パスワード認証（コールバックがあるか、取得元にパスワードが設定されている場合のみ）
+	if cb != nil && (ask != nil || host.Credentials.Password != "") {
 		methods = append(methods, ssh.PasswordCallback(func() (string, error) {
 			resp, err := cb(core.CredentialRequest{
 				Type:   core.CredentialPassword,
@@ -159,7 +179,7 @@ func buildAuthMethods(host core.SSHHost, cb core.CredentialCallback) ([]ssh.Auth
 	}
 
 	// keyboard-interactive 認証（コールバックがある場合のみ）
-	if cb != nil {
+	if ask != nil {
 		methods = append(methods, ssh.KeyboardInteractive(
 			func(name, instruction string, questions []string, echos []bool) ([]string, error) {
 				prompts := make([]core.PromptInfo, len(questions))
@@ -1,4 +1,4 @@
-package infra
+package sshauth
 
 import (
 	"fmt"
@@ -162,3 +162,29 @@ func TestTryKeyFileWithPassphrase_CallbackError(t *testing.T) {
 		t.Errorf("error should contain callback error, got %q", err.Error())
 	}
 }
+
+func TestBuildAuthMethods_CredentialProvider(t *testing.T) {
+	t.Setenv("SSH_AUTH_SOCK", "")
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("MOLEPORT_TEST_PASSPHRASE", "test-passphrase")
+
+	_, encrypted := generateTestKey(t)
+	keyPath := filepath.Join(t.TempDir(), "id_test_enc")
+	if err := os.WriteFile(keyPath, encrypted, 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	host := core.SSHHost{
+		Name:          "test-host",
+		IdentityFiles: []string{keyPath},
+		Credentials:   &core.CredentialSource{Provider: core.CredentialProviderEnv, Passphrase: "MOLEPORT_TEST_PASSPHRASE"},
+	}
+
+	// コールバックなしでも取得元のパスフレーズで鍵を復号し、パスワード認証は追加しない
+	if methods, _ := BuildAuthMethods(host, nil); len(methods) != 1 {
+		t.Errorf("methods = %d, want 1 (public key only)", len(methods))
+	}
+	host.Credentials.Password = "MOLEPORT_TEST_PASSWORD"
+	if methods, _ := BuildAuthMethods(host, nil); len(methods) != 2 {
+		t.Errorf("methods = %d, want 2 (public key and password)", len(methods))
+	}
+}
@@ -1,4 +1,4 @@
-package infra
+package sshauth
 
 import (
 	"crypto/ed25519"
@@ -6,7 +6,6 @@ import (
 	"encoding/pem"
 	"fmt"
 	"os"
-	"os/user"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -16,39 +15,6 @@ import (
 	"github.com/ousiassllc/moleport/internal/core"
 )
 
-func TestExpandTilde_Exported(t *testing.T) {
-	u, err := user.Current()
-	if err != nil {
-		t.Fatalf("failed to get current user: %v", err)
-	}
-
-	tests := []struct {
-		input   string
-		want    string
-		wantErr bool
-	}{
-		{"~/.ssh/config", filepath.Join(u.HomeDir, ".ssh/config"), false},
-		{"~/", u.HomeDir, false},
-		{"~", u.HomeDir, false},
-		{"~otheruser/.ssh/config", "~otheruser/.ssh/config", false},
-		{"~otheruser", "~otheruser", false},
-		{"/absolute/path", "/absolute/path", false},
-		{"relative/path", "relative/path", false},
-		{"", "", false},
-	}
-
-	for _, tt := range tests {
-		got, err := ExpandTilde(tt.input)
-		if (err != nil) != tt.wantErr {
-			t.Errorf("ExpandTilde(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
-			continue
-		}
-		if got != tt.want {
-			t.Errorf("ExpandTilde(%q) = %q, want %q", tt.input, got, tt.want)
-		}
-	}
-}
-
 func TestDefaultKeyPaths(t *testing.T) {
 	paths := defaultKeyPaths()
 	if len(paths) == 0 {
@@ -110,7 +76,7 @@ func TestBuildAuthMethods_WithNilCallback(t *testing.T) {
 		IdentityFiles: []string{keyPath},
 	}
 
-	methods, closer := buildAuthMethods(host, nil)
+	methods, closer := BuildAuthMethods(host, nil)
 	if closer != nil {
 		if err := closer.Close(); err != nil {
 			t.Errorf("failed to close agent connection: %v", err)
@@ -142,7 +108,7 @@ func TestBuildAuthMethods_PasswordAuth(t *testing.T) {
 		return core.CredentialResponse{Value: "secret"}, nil
 	}
 
-	methods, closer := buildAuthMethods(host, cb)
+	methods, closer := BuildAuthMethods(host, cb)
 	if closer != nil {
 		if err := closer.Close(); err != nil {
 			t.Errorf("failed to close agent connection: %v", err)
@@ -181,7 +147,7 @@ func TestBuildAuthMethods_KeyboardInteractive(t *testing.T) {
 		return core.CredentialResponse{Answers: []string{"answer1", "answer2"}}, nil
 	}
 
-	methods, closer := buildAuthMethods(host, cb)
+	methods, closer := BuildAuthMethods(host, cb)
 	if closer != nil {
 		if err := closer.Close(); err != nil {
 			t.Errorf("failed to close agent connection: %v", err)
@@ -205,7 +171,7 @@ func TestBuildAuthMethods_NilCallbackNoPasswordOrKBI(t *testing.T) {
 		User:     "user",
 	}
 
-	methods, closer := buildAuthMethods(host, nil)
+	methods, closer := BuildAuthMethods(host, nil)
 	if closer != nil {
 		if err := closer.Close(); err != nil {
 			t.Errorf("failed to close agent connection: %v", err)
@@ -246,7 +212,7 @@ func TestBuildAuthMethods_WithCallbackAndKeyFile(t *testing.T) {
 		return core.CredentialResponse{Value: "secret"}, nil
 	}
 
-	methods, closer := buildAuthMethods(host, cb)
+	methods, closer := BuildAuthMethods(host, cb)
 	if closer != nil {
 		if err := closer.Close(); err != nil {
 			t.Errorf("failed to close agent connection: %v", err)
@@ -283,7 +249,7 @@ func TestBuildAuthMethods_MultipleIdentityFiles(t *testing.T) {
 		IdentityFiles: []string{keyPath1, keyPath2},
 	}
 
-	methods, closer := buildAuthMethods(host, nil)
+	methods, closer := BuildAuthMethods(host, nil)
 	if closer != nil {
 		if err := closer.Close(); err != nil {
 			t.Errorf("failed to close agent connection: %v", err)
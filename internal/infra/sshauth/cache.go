@@ -0,0 +1,59 @@
+package sshauth
+
+import (
+	"errors"
+	"log/slog"
+	"sync/atomic"
+
+	"golang.org/x/crypto/ssh"
+
+	"github.com/ousiassllc/moleport/internal/infra/secretstore"
+)
+
+// passphraseAccountPrefix はパスフレーズを保存するアカウント名の接頭辞（続けて鍵ファイルのパス）。
+const passphraseAccountPrefix = "passphrase:"
+
+// passphraseCache は入力されたパスフレーズを保存する SecretStore。未設定の場合は保存しない。
+var passphraseCache atomic.Pointer[secretstore.SecretStore]
+
+// SetPassphraseCache は入力されたパスフレーズを s に保存し、以降の接続で再利用するようにする。
+// s が nil の場合は保存しない。
+func SetPassphraseCache(s secretstore.SecretStore) {
+	if s == nil {
+		passphraseCache.Store(nil)
+		return
+	}
+	passphraseCache.Store(&s)
+}
+
+// cachedSigner は保存済みのパスフレーズで鍵を復号する。保存されていないか復号できない場合は ok に false を返す。
+func cachedSigner(path string, keyData []byte) (signer ssh.Signer, ok bool) {
+	s := passphraseCache.Load()
+	if s == nil {
+		return nil, false
+	}
+	passphrase, err := (*s).Get(passphraseAccountPrefix + path)
+	if err != nil {
+		if !errors.Is(err, secretstore.ErrNotFound) {
+			slog.Warn("failed to read cached passphrase", "path", path, "error", err)
+		}
+		return nil, false
+	}
+	signer, err = ssh.ParsePrivateKeyWithPassphrase(keyData, []byte(passphrase))
+	if err != nil {
+		slog.Debug("cached passphrase no longer decrypts key", "path", path, "error", err)
+		return nil, false
+	}
+	return signer, true
+}
+
+// cachePassphrase は鍵を復号できたパスフレーズを保存する。
+func cachePassphrase(path, passphrase string) {
+	s := passphraseCache.Load()
+	if s == nil {
+		return
+	}
+	if err := secretstore.Remember(*s, passphraseAccountPrefix+path, passphrase); err != nil {
+		slog.Warn("failed to cache passphrase", "path", path, "error", err)
+	}
+}
@@ -0,0 +1,45 @@
+package sshauth
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/infra/secretstore"
+)
+
+func TestTryKeyFileWithPassphrase_Cache(t *testing.T) {
+	store := secretstore.NewMemory()
+	SetPassphraseCache(store)
+	t.Cleanup(func() { SetPassphraseCache(nil) })
+
+	_, encrypted := generateTestKey(t)
+	keyPath := filepath.Join(t.TempDir(), "id_test_enc")
+	if err := os.WriteFile(keyPath, encrypted, 0600); err != nil {
+		t.Fatalf("failed to write key file: %v", err)
+	}
+	host := core.SSHHost{Name: "test-host"}
+	asked := 0
+	cb := func(core.CredentialRequest) (core.CredentialResponse, error) {
+		asked++
+		return core.CredentialResponse{Value: "test-passphrase"}, nil
+	}
+
+	if _, err := tryKeyFileWithPassphrase(keyPath, cb, host); err != nil || asked != 1 {
+		t.Fatalf("first attempt error = %v, asked %d; want prompt once", err, asked)
+	}
+	if v, _ := store.Get(passphraseAccountPrefix + keyPath); v != "test-passphrase" {
+		t.Errorf("cached passphrase = %q, want test-passphrase", v)
+	}
+	// 保存済みのパスフレーズはコールバックなし（再接続・デーモン再起動後）でも使われる
+	if _, err := tryKeyFileWithPassphrase(keyPath, nil, host); err != nil {
+		t.Errorf("cached attempt error = %v", err)
+	}
+
+	// 復号できない保存値は無視して問い合わせる
+	_ = store.Set(passphraseAccountPrefix+keyPath, "stale")
+	if _, err := tryKeyFileWithPassphrase(keyPath, cb, host); err != nil || asked != 2 {
+		t.Errorf("stale cache error = %v, asked %d; want prompt again", err, asked)
+	}
+}
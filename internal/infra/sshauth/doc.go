@@ -0,0 +1,2 @@
+// Package sshauth は SSH エージェント・鍵ファイル・クレデンシャルコールバックから SSH 認証メソッドを構築する。
+package sshauth
@@ -0,0 +1,218 @@
+package sshconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core/hostforward"
+	"github.com/ousiassllc/moleport/internal/infra"
+)
+
+// maxIncludeDepth は Include の入れ子の上限（OpenSSH と同じ値）。
+const maxIncludeDepth = 16
+
+// directive は ssh_config の 1 行分の設定。key は小文字に正規化する。
+type directive struct {
+	key   string
+	value string
+}
+
+// block は Host 行または Match 行で始まる設定のまとまり。
+// hosts と match はどちらか一方のみが設定される。
+type block struct {
+	hosts      []string
+	match      []string
+	directives []directive
+}
+
+// config は Include を展開済みの ssh_config。blocks はファイル上の出現順に並ぶ。
+// files は読み込んだ Include 先のファイル、patterns は Include のパターン（絶対パス）。
+type config struct {
+	blocks   []*block
+	files    []string
+	patterns []string
+}
+
+// loadConfig は path の ssh_config を読み込み、Include を展開した config を返す。
+// Include の相対パスは path のディレクトリ（既定では ~/.ssh）を基準に解決する。
+func loadConfig(path string) (*config, error) {
+	data, err := os.ReadFile(path) //nolint:gosec // path は SSH config のパスでユーザー指定値
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ssh config: %w", err)
+	}
+	l := &loader{baseDir: filepath.Dir(path), cfg: &config{}}
+	// 最初の Host/Match より前の設定は Host * と同様にすべてのホストへ適用する
+	if err := l.parse(path, data, &block{hosts: []string{"*"}}, 0); err != nil {
+		return nil, fmt.Errorf("failed to parse ssh config: %w", err)
+	}
+	return l.cfg, nil
+}
+
+// loader は Include を辿りながら config を組み立てる。
+type loader struct {
+	baseDir string
+	cfg     *config
+}
+
+// parse は data を解析して blocks に追加する。
+// 新しい Host/Match が現れるまでは cond の条件を引き継ぐ（Include されたファイルの先頭部分に相当）。
+func (l *loader) parse(name string, data []byte, cond *block, depth int) error {
+	cur := l.open(cond)
+	for i, line := range strings.Split(string(data), "\n") {
+		if value, ok := magicComment(line); ok {
+			cur.directives = append(cur.directives, directive{key: magicKey, value: value})
+			continue
+		}
+		key, value, ok := splitLine(line)
+		if !ok {
+			continue
+		}
+		switch key {
+		case "host":
+			cur = l.open(&block{hosts: splitArgs(value)})
+		case "match":
+			args := splitArgs(value)
+			if len(args) == 0 {
+				return fmt.Errorf("%s:%d: Match requires at least one criterion", name, i+1)
+			}
+			cur = l.open(&block{match: args})
+		case "include":
+			if err := l.include(value, cur, depth+1); err != nil {
+				return fmt.Errorf("%s:%d: %w", name, i+1, err)
+			}
+			// Include 後の設定は元の条件のまま、Include されたブロックより後ろに並べる
+			cur = l.open(cur)
+		default:
+			cur.directives = append(cur.directives, directive{key: key, value: unquote(value)})
+		}
+	}
+	return nil
+}
+
+// open は cond と同じ条件の空ブロックを追加して返す。
+func (l *loader) open(cond *block) *block {
+	b := &block{hosts: cond.hosts, match: cond.match}
+	l.cfg.blocks = append(l.cfg.blocks, b)
+	return b
+}
+
+// include は Include ディレクティブの各パターン（グロブ可）に一致するファイルを名前順に読み込む。
+// 一致するファイルがないパターンは OpenSSH と同様に無視する。
+func (l *loader) include(value string, cond *block, depth int) error {
+	if depth > maxIncludeDepth {
+		return fmt.Errorf("include nested too deeply (max %d)", maxIncludeDepth)
+	}
+	for _, pattern := range splitArgs(value) {
+		expanded, err := infra.ExpandTilde(pattern)
+		if err != nil {
+			return err
+		}
+		if !filepath.IsAbs(expanded) {
+			expanded = filepath.Join(l.baseDir, expanded)
+		}
+		matches, err := filepath.Glob(expanded)
+		if err != nil {
+			return fmt.Errorf("invalid include pattern %q: %w", pattern, err)
+		}
+		l.cfg.patterns = append(l.cfg.patterns, expanded)
+		for _, m := range matches {
+			if info, err := os.Stat(m); err != nil || info.IsDir() {
+				continue
+			}
+			data, err := os.ReadFile(m) //nolint:gosec // Include で指定されたユーザーの設定ファイル
+			if err != nil {
+				return fmt.Errorf("failed to read included file %s: %w", m, err)
+			}
+			l.cfg.files = append(l.cfg.files, m)
+			if err := l.parse(m, data, cond, depth); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// aliases は Host 行に列挙された具体的なエイリアス（ワイルドカード・否定を除く）を出現順に返す。
+func (c *config) aliases() []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, b := range c.blocks {
+		for _, alias := range b.hosts {
+			if strings.ContainsAny(alias, "*?!") || seen[alias] {
+				continue
+			}
+			seen[alias] = true
+			result = append(result, alias)
+		}
+	}
+	return result
+}
+
+// magicKey はマジックコメントを保持するディレクティブのキー。ssh_config のキーと衝突しないよう空白を含める。
+const magicKey = "# moleport"
+
+// magicComment は行が MolePort のマジックコメント（"# moleport: L 5432:localhost:5432"）であれば、接頭辞より後ろを返す。
+func magicComment(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "#")
+	if !ok {
+		return "", false
+	}
+	rest = strings.TrimSpace(rest)
+	n := len(hostforward.CommentPrefix)
+	if len(rest) < n || !strings.EqualFold(rest[:n], hostforward.CommentPrefix) {
+		return "", false
+	}
+	return strings.TrimSpace(rest[n:]), true
+}
+
+// splitLine は 1 行を小文字のキーと値に分解する。空行とコメント行は ok=false を返す。
+// キーと値の区切りは空白または "="。
+func splitLine(line string) (key, value string, ok bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || line[0] == '#' {
+		return "", "", false
+	}
+	i := strings.IndexAny(line, " \t=")
+	if i < 0 {
+		return strings.ToLower(line), "", true
+	}
+	value = strings.TrimSpace(line[i:])
+	value = strings.TrimSpace(strings.TrimPrefix(value, "="))
+	return strings.ToLower(line[:i]), value, true
+}
+
+// unquote は値全体が二重引用符で囲まれている場合に引用符を外す。
+func unquote(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' && !strings.Contains(value[1:len(value)-1], `"`) {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// splitArgs は空白区切りの引数列を分解する。二重引用符で囲まれた部分は 1 つの引数として扱う。
+func splitArgs(s string) []string {
+	var args []string
+	var b strings.Builder
+	quoted, inArg := false, false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted, inArg = !quoted, true
+		case !quoted && (r == ' ' || r == '\t'):
+			if inArg {
+				args = append(args, b.String())
+				b.Reset()
+				inArg = false
+			}
+		default:
+			b.WriteRune(r)
+			inArg = true
+		}
+	}
+	if inArg {
+		args = append(args, b.String())
+	}
+	return args
+}
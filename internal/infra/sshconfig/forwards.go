@@ -0,0 +1,147 @@
+package sshconfig
+
+import (
+	"log/slog"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/hostforward"
+	"github.com/ousiassllc/moleport/internal/core/rulename"
+)
+
+// forwardDirectives は MolePort ルールとして取り込める ssh_config ディレクティブと種別の対応。
+var forwardDirectives = []struct {
+	key string
+	typ core.ForwardType
+}{
+	{"LocalForward", core.Local},
+	{"RemoteForward", core.Remote},
+	{"DynamicForward", core.Dynamic},
+}
+
+// Forwards は configPath の ssh_config の各ホストの LocalForward/RemoteForward/DynamicForward を
+// MolePort のルールに変換して返す。ルールの Host は ssh_config のエイリアス。
+func Forwards(configPath string) ([]core.ForwardRule, error) {
+	hosts, err := NewSSHConfigParser().Parse(configPath)
+	if err != nil {
+		return nil, err
+	}
+	var rules []core.ForwardRule
+	for _, h := range hosts {
+		rules = append(rules, h.ConfigForwards...)
+	}
+	return rules, nil
+}
+
+// parseConfigForwards はホストに適用される LocalForward/RemoteForward/DynamicForward を
+// ForwardRule に変換する。MolePort で表現できない指定（UNIX ソケットや localhost 以外への
+// RemoteForward など）はスキップする。ルール名はデーモンが名前を省略したルールに付ける名前と同じく
+// rulename.Generate で生成し、同じホストの他のルールと重複しないようにする。
+func parseConfigForwards(s *settings) []core.ForwardRule {
+	alias := s.alias
+	var rules []core.ForwardRule
+	var names []string
+	for _, d := range forwardDirectives {
+		for _, v := range s.getAll(d.key) {
+			rule, ok := parseForwardValue(d.typ, v)
+			if !ok {
+				continue
+			}
+			rule.Host = alias
+			rule.Name = rulename.Generate(alias, rule.Type, rule.LocalPort, names)
+			names = append(names, rule.Name)
+			rule.ImportKey = alias + "/" + d.key + "/" + strings.Join(strings.Fields(v), " ")
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
+
+// parseDefaultForwards はホストに適用されるマジックコメントのフォワードをルールに変換する。
+// 書式が正しくないコメントは警告を記録してスキップする。
+func parseDefaultForwards(s *settings) []core.ForwardRule {
+	var rules []core.ForwardRule
+	var names []string
+	for _, v := range s.getAll(magicKey) {
+		rule, err := hostforward.Parse(s.alias, v, names)
+		if err != nil {
+			slog.Warn("ignoring moleport comment in ssh config", "host", s.alias, "error", err)
+			continue
+		}
+		rules = append(rules, rule)
+		names = append(names, rule.Name)
+	}
+	return rules
+}
+
+// parseForwardValue はディレクティブの値（"[bind:]port host:hostport" または "[bind:]port"）を解析する。
+func parseForwardValue(typ core.ForwardType, val string) (core.ForwardRule, bool) {
+	fields := strings.Fields(val)
+	want := 2
+	if typ == core.Dynamic {
+		want = 1
+	}
+	if len(fields) != want {
+		return core.ForwardRule{}, false
+	}
+
+	bind, port, ok := splitListen(fields[0])
+	if !ok {
+		return core.ForwardRule{}, false
+	}
+
+	switch typ {
+	case core.Local:
+		host, destPort, ok := splitHostPort(fields[1])
+		if !ok {
+			return core.ForwardRule{}, false
+		}
+		return core.ForwardRule{Type: core.Local, LocalPort: port, RemoteHost: host, RemotePort: destPort, LocalBindAddr: bind}, true
+	case core.Remote:
+		host, destPort, ok := splitHostPort(fields[1])
+		if !ok || !isLoopback(host) {
+			return core.ForwardRule{}, false
+		}
+		return core.ForwardRule{Type: core.Remote, LocalPort: destPort, RemotePort: port, RemoteBindAddr: bind}, true
+	default:
+		return core.ForwardRule{Type: core.Dynamic, LocalPort: port, LocalBindAddr: bind}, true
+	}
+}
+
+// splitListen は "[bind:]port" 形式の待ち受け指定を分解する。
+func splitListen(s string) (string, int, bool) {
+	if port, err := strconv.Atoi(s); err == nil {
+		return "", port, core.ValidatePort(port) == nil
+	}
+	bind, port, ok := splitHostPort(s)
+	if bind == "*" {
+		bind = "0.0.0.0"
+	}
+	return bind, port, ok
+}
+
+// splitHostPort は "host:port" / "[v6]:port" 形式を分解する。UNIX ソケットパスは拒否する。
+func splitHostPort(s string) (string, int, bool) {
+	if strings.HasPrefix(s, "/") {
+		return "", 0, false
+	}
+	host, p, err := net.SplitHostPort(s)
+	if err != nil || host == "" {
+		return "", 0, false
+	}
+	port, err := strconv.Atoi(p)
+	if err != nil || core.ValidatePort(port) != nil {
+		return "", 0, false
+	}
+	return host, port, true
+}
+
+func isLoopback(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
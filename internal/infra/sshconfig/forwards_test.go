@@ -0,0 +1,123 @@
+package sshconfig
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"reflect"
+)
+
+func TestSSHConfigParser_ConfigForwards(t *testing.T) {
+	path := writeSSHConfig(t, `
+Host fwd
+    HostName example.com
+    LocalForward 8080 db.internal:5432
+    LocalForward 127.0.0.1:9090 localhost:90
+    LocalForward 127.0.0.2:8080 db.internal:5433
+    RemoteForward 0.0.0.0:2222 localhost:22
+    RemoteForward 3000 other.host:3000
+    DynamicForward 1080
+    LocalForward 7000 /var/run/sock
+`)
+
+	hosts, err := NewSSHConfigParser().Parse(path)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(hosts) != 1 {
+		t.Fatalf("len(hosts) = %d, want 1", len(hosts))
+	}
+
+	want := []core.ForwardRule{
+		{Name: "fwd-local-8080", Host: "fwd", Type: core.Local, LocalPort: 8080, RemoteHost: "db.internal", RemotePort: 5432, ImportKey: "fwd/LocalForward/8080 db.internal:5432"},
+		{Name: "fwd-local-9090", Host: "fwd", Type: core.Local, LocalPort: 9090, RemoteHost: "localhost", RemotePort: 90, LocalBindAddr: "127.0.0.1", ImportKey: "fwd/LocalForward/127.0.0.1:9090 localhost:90"},
+		{Name: "fwd-local-8080-2", Host: "fwd", Type: core.Local, LocalPort: 8080, RemoteHost: "db.internal", RemotePort: 5433, LocalBindAddr: "127.0.0.2", ImportKey: "fwd/LocalForward/127.0.0.2:8080 db.internal:5433"},
+		{Name: "fwd-remote-22", Host: "fwd", Type: core.Remote, LocalPort: 22, RemotePort: 2222, RemoteBindAddr: "0.0.0.0", ImportKey: "fwd/RemoteForward/0.0.0.0:2222 localhost:22"},
+		{Name: "fwd-dynamic-1080", Host: "fwd", Type: core.Dynamic, LocalPort: 1080, ImportKey: "fwd/DynamicForward/1080"},
+	}
+	got := hosts[0].ConfigForwards
+	if len(got) != len(want) {
+		t.Fatalf("ConfigForwards = %+v, want %d rules", got, len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(got[i], want[i]) {
+			t.Errorf("ConfigForwards[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestForwards(t *testing.T) {
+	path := writeSSHConfig(t, `
+Host db
+    LocalForward 15432 localhost:5432
+Host plain
+    HostName plain.example.com
+Host proxy
+    DynamicForward 1080
+`)
+
+	rules, err := Forwards(path)
+	if err != nil {
+		t.Fatalf("Forwards: %v", err)
+	}
+	if len(rules) != 2 || rules[0].Host != "db" || rules[1].Host != "proxy" {
+		t.Errorf("Forwards = %+v, want db and proxy rules", rules)
+	}
+}
+
+func TestParseForwardValue_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		typ  core.ForwardType
+		val  string
+	}{
+		{"local missing dest", core.Local, "8080"},
+		{"local bad port", core.Local, "abc host:80"},
+		{"local out of range", core.Local, "70000 host:80"},
+		{"dynamic extra field", core.Dynamic, "1080 host:80"},
+		{"remote non-loopback", core.Remote, "8080 example.com:80"},
+		{"unix socket", core.Local, "/tmp/sock host:80"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, ok := parseForwardValue(tt.typ, tt.val); ok {
+				t.Errorf("parseForwardValue(%q) ok = true, want false", tt.val)
+			}
+		})
+	}
+}
+
+func TestSSHConfigParser_DefaultForwards(t *testing.T) {
+	path := writeSSHConfig(t, `
+Host db
+    HostName db.example.com
+    # moleport: L 5432:localhost:5432 name=db-pg auto_connect
+    # MolePort: D 1080
+    # moleport: L not-a-spec
+    # an ordinary comment
+Host web
+    HostName web.example.com
+`)
+
+	hosts, err := NewSSHConfigParser().Parse(path)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(hosts) != 2 {
+		t.Fatalf("len(hosts) = %d, want 2", len(hosts))
+	}
+
+	want := []core.ForwardRule{
+		{Name: "db-pg", Host: "db", Type: core.Local, LocalPort: 5432, RemoteHost: "localhost", RemotePort: 5432, AutoConnect: true, HostDefined: true},
+		{Name: "db-dynamic-1080", Host: "db", Type: core.Dynamic, LocalPort: 1080, HostDefined: true},
+	}
+	if got := hosts[0].DefaultForwards; !reflect.DeepEqual(got, want) {
+		t.Errorf("DefaultForwards = %+v, want %+v", got, want)
+	}
+	if got := hosts[1].DefaultForwards; len(got) != 0 {
+		t.Errorf("web DefaultForwards = %+v, want none", got)
+	}
+	if got := hosts[0].ConfigForwards; len(got) != 0 {
+		t.Errorf("ConfigForwards = %+v, want none", got)
+	}
+}
@@ -0,0 +1,205 @@
+package sshconfig
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/infra"
+)
+
+// multiValued は指定された値をすべて採用するキー。それ以外のキーは最初に得られた値を採用する。
+var multiValued = map[string]bool{
+	"identityfile":   true,
+	"localforward":   true,
+	"remoteforward":  true,
+	"dynamicforward": true,
+	magicKey:         true,
+}
+
+// settings は 1 つのホストエイリアスに適用される設定値。キーは小文字。
+type settings struct {
+	alias     string
+	localUser string
+	values    map[string]string
+	lists     map[string][]string
+}
+
+// resolve は alias に適用される設定を OpenSSH と同じく先勝ちで集める。
+// Match ブロックの条件は、それまでに得られた HostName・User で評価する。
+func (c *config) resolve(alias, localUser string) *settings {
+	s := &settings{alias: alias, localUser: localUser, values: map[string]string{}, lists: map[string][]string{}}
+	for _, b := range c.blocks {
+		if !s.applies(b) {
+			continue
+		}
+		for _, d := range b.directives {
+			if multiValued[d.key] {
+				s.lists[d.key] = append(s.lists[d.key], d.value)
+			} else if _, ok := s.values[d.key]; !ok {
+				s.values[d.key] = d.value
+			}
+		}
+	}
+	return s
+}
+
+// get は key（大文字小文字を区別しない）の値を返す。未指定の場合は空文字列を返す。
+func (s *settings) get(key string) string {
+	return s.values[strings.ToLower(key)]
+}
+
+// getAll は複数指定可能な key の値を出現順に返す。
+func (s *settings) getAll(key string) []string {
+	return s.lists[strings.ToLower(key)]
+}
+
+// applies はブロックの Host パターンまたは Match 条件が現在のホストに一致するかを返す。
+func (s *settings) applies(b *block) bool {
+	if b.match == nil {
+		return matchPatternList(b.hosts, s.alias)
+	}
+	return s.matchCriteria(b.match)
+}
+
+// matchCriteria は Match 行の条件をすべて満たすかを返す。
+// exec など評価しない条件を含むブロックは一致しないものとして扱う。
+func (s *settings) matchCriteria(args []string) bool {
+	for i := 0; i < len(args); i++ {
+		criterion := strings.ToLower(args[i])
+		negate := strings.HasPrefix(criterion, "!")
+		criterion = strings.TrimPrefix(criterion, "!")
+
+		var ok bool
+		switch criterion {
+		case "all", "final":
+			ok = true
+		case "canonical":
+			// CanonicalizeHostname は扱わないため、正規化後の再評価は発生しない
+			ok = false
+		case "host", "originalhost", "user", "localuser":
+			if i+1 >= len(args) {
+				return false
+			}
+			i++
+			list := strings.Split(args[i], ",")
+			switch criterion {
+			case "host":
+				ok = matchPatternList(list, s.hostName())
+			case "originalhost":
+				ok = matchPatternList(list, s.alias)
+			case "user":
+				ok = matchPatternList(list, s.user())
+			default:
+				ok = matchPatternList(list, s.localUser)
+			}
+		default:
+			return false
+		}
+		if ok == negate {
+			return false
+		}
+	}
+	return true
+}
+
+// hostName は HostName（%h はエイリアスに展開）を返す。未指定の場合はエイリアスを返す。
+func (s *settings) hostName() string {
+	v := s.get("HostName")
+	if v == "" {
+		return s.alias
+	}
+	return strings.NewReplacer("%%", "%", "%h", s.alias).Replace(v)
+}
+
+// user は User を返す。未指定の場合はローカルユーザー名を返す。
+func (s *settings) user() string {
+	if v := s.get("User"); v != "" {
+		return v
+	}
+	return s.localUser
+}
+
+// port は Port を返す。未指定または 1〜65535 の範囲外の場合は 22 を返す。
+func (s *settings) port() int {
+	port, err := strconv.Atoi(s.get("Port"))
+	if err != nil || core.ValidatePort(port) != nil {
+		return 22
+	}
+	return port
+}
+
+// seconds は秒数を表す key の値を返す。未指定または正の整数でない場合は 0 を返す。
+func (s *settings) seconds(key string) int {
+	n, err := strconv.Atoi(s.get(key))
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
+
+// expand は IdentityFile 等のパスに含まれるトークンと先頭の ~ を展開する。
+// サポートするトークンは %h（HostName）、%n（エイリアス）、%p、%r、%u（ローカルユーザー）、%d（ホームディレクトリ）、%%。
+func (s *settings) expand(path string) string {
+	if path == "" {
+		return ""
+	}
+	home, _ := os.UserHomeDir()
+	path = strings.NewReplacer(
+		"%%", "%",
+		"%h", s.hostName(),
+		"%n", s.alias,
+		"%p", strconv.Itoa(s.port()),
+		"%r", s.user(),
+		"%u", s.localUser,
+		"%d", home,
+	).Replace(path)
+	if expanded, err := infra.ExpandTilde(path); err == nil {
+		return expanded
+	}
+	return path
+}
+
+// matchPatternList は name がパターン列（"!" で否定、"*" と "?" のワイルドカード可）に一致するかを返す。
+// 否定パターンに一致した場合は他のパターンに関わらず一致しない。
+func matchPatternList(patterns []string, name string) bool {
+	name = strings.ToLower(name)
+	matched := false
+	for _, p := range patterns {
+		negated := strings.HasPrefix(p, "!")
+		if !matchGlob(strings.ToLower(strings.TrimPrefix(p, "!")), name) {
+			continue
+		}
+		if negated {
+			return false
+		}
+		matched = true
+	}
+	return matched
+}
+
+// matchGlob は s が "*"（任意の文字列）と "?"（任意の 1 文字）を含むパターンに一致するかを返す。
+func matchGlob(pattern, s string) bool {
+	for pattern != "" {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if matchGlob(pattern[1:], s[i:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if s == "" {
+				return false
+			}
+		default:
+			if s == "" || pattern[0] != s[0] {
+				return false
+			}
+		}
+		pattern, s = pattern[1:], s[1:]
+	}
+	return s == ""
+}
@@ -1,16 +1,11 @@
 package sshconfig
 
 import (
-	"fmt"
-	"os"
 	"os/user"
-	"strconv"
 	"strings"
-
-	ssh_config "github.com/kevinburke/ssh_config"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/infra"
 )
 
 type sshConfigParser struct{}
@@ -20,103 +15,69 @@ func NewSSHConfigParser() core.SSHConfigParser {
 	return &sshConfigParser{}
 }
 
+// Parse は configPath の ssh_config を Include を展開して解析し、Host 行に列挙された各エイリアスの接続情報を返す。
+// 各ホストの値は Host パターンと Match 条件に一致するブロックから OpenSSH と同じく先勝ちで決まる。
 func (p *sshConfigParser) Parse(configPath string) ([]core.SSHHost, error) {
-	f, err := os.Open(configPath) //nolint:gosec // configPath は SSH config のパスでユーザー指定値
-	if err != nil {
-		return nil, fmt.Errorf("failed to open ssh config: %w", err)
-	}
-	defer f.Close() //nolint:errcheck // 読み取り専用のため Close エラーは無視
-
-	cfg, err := ssh_config.Decode(f)
+	cfg, err := loadConfig(configPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse ssh config: %w", err)
+		return nil, err
 	}
 
-	currentUser := currentUsername()
+	localUser := currentUsername()
 
 	var hosts []core.SSHHost
-	seen := make(map[string]bool)
-
-	for _, host := range cfg.Hosts {
-		for _, pattern := range host.Patterns {
-			alias := pattern.String()
-
-			// ワイルドカードや否定パターンを除外
-			if strings.ContainsAny(alias, "*?!") {
-				continue
-			}
-			if seen[alias] {
-				continue
-			}
-			seen[alias] = true
-
-			sshHost := core.SSHHost{
-				Name:                  alias,
-				HostName:              getConfigValue(cfg, alias, "HostName", alias),
-				Port:                  getConfigPort(cfg, alias),
-				User:                  getConfigValue(cfg, alias, "User", currentUser),
-				IdentityFiles:         expandIdentityFiles(cfg, alias),
-				ProxyJump:             parseProxyJump(getConfigValue(cfg, alias, "ProxyJump", "")),
-				ProxyCommand:          getConfigValue(cfg, alias, "ProxyCommand", ""),
-				StrictHostKeyChecking: getConfigValue(cfg, alias, "StrictHostKeyChecking", ""),
-				State:                 core.Disconnected,
-				ActiveForwardCount:    0,
-			}
-
-			hosts = append(hosts, sshHost)
-		}
+	for _, alias := range cfg.aliases() {
+		s := cfg.resolve(alias, localUser)
+		hosts = append(hosts, core.SSHHost{
+			Name:                  alias,
+			HostName:              s.hostName(),
+			Port:                  s.port(),
+			User:                  s.user(),
+			IdentityFiles:         expandIdentityFiles(s),
+			IdentityAgent:         s.expand(s.get("IdentityAgent")),
+			ProxyJump:             parseProxyJump(s.get("ProxyJump")),
+			ProxyCommand:          s.get("ProxyCommand"),
+			StrictHostKeyChecking: s.get("StrictHostKeyChecking"),
+			ConnectTimeout:        time.Duration(s.seconds("ConnectTimeout")) * time.Second,
+			ServerAliveInterval:   time.Duration(s.seconds("ServerAliveInterval")) * time.Second,
+			ServerAliveCountMax:   s.seconds("ServerAliveCountMax"),
+			State:                 core.Disconnected,
+			ActiveForwardCount:    0,
+			ConfigForwards:        parseConfigForwards(s),
+			DefaultForwards:       parseDefaultForwards(s),
+			Source:                core.HostSourceSSHConfig,
+		})
 	}
 
 	return hosts, nil
 }
 
-func currentUsername() string {
-	u, err := user.Current()
+// Sources は configPath と、そこから Include で読み込まれるファイルの一覧、および Include のパターン（絶対パス）を返す。
+// パターンはまだ存在しないファイルが後から Include の対象になるかを判定するために使う。
+func Sources(configPath string) (files, patterns []string, err error) {
+	cfg, err := loadConfig(configPath)
 	if err != nil {
-		return ""
+		return nil, nil, err
 	}
-	return u.Username
+	return append([]string{configPath}, cfg.files...), cfg.patterns, nil
 }
 
-func getConfigValue(cfg *ssh_config.Config, alias, key, defaultVal string) string {
-	val, err := cfg.Get(alias, key)
-	if err != nil || val == "" {
-		return defaultVal
-	}
-	return val
-}
-
-func getConfigPort(cfg *ssh_config.Config, alias string) int {
-	val, err := cfg.Get(alias, "Port")
-	if err != nil || val == "" {
-		return 22
-	}
-	port, err := strconv.Atoi(val)
+func currentUsername() string {
+	u, err := user.Current()
 	if err != nil {
-		return 22
-	}
-	return port
-}
-
-func expandIdentityFile(path string) string {
-	if path == "" {
 		return ""
 	}
-	expanded, err := infra.ExpandTilde(path)
-	if err != nil {
-		return path
-	}
-	return expanded
+	return u.Username
 }
 
-func expandIdentityFiles(cfg *ssh_config.Config, alias string) []string {
-	vals, err := cfg.GetAll(alias, "IdentityFile")
-	if err != nil || len(vals) == 0 {
+func expandIdentityFiles(s *settings) []string {
+	vals := s.getAll("IdentityFile")
+	if len(vals) == 0 {
 		return nil
 	}
 	result := make([]string, 0, len(vals))
 	for _, v := range vals {
-		if expanded := expandIdentityFile(v); expanded != "" {
+		if expanded := s.expand(v); expanded != "" {
 			result = append(result, expanded)
 		}
 	}
@@ -0,0 +1,180 @@
+package sshconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// parseHosts は path を解析し、エイリアスをキーにした結果を返す。
+func parseHosts(t *testing.T, path string) map[string]core.SSHHost {
+	t.Helper()
+	hosts, err := NewSSHConfigParser().Parse(path)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	result := make(map[string]core.SSHHost, len(hosts))
+	for _, h := range hosts {
+		result[h.Name] = h
+	}
+	return result
+}
+
+func TestSSHConfigParser_Include(t *testing.T) {
+	path := writeSSHConfig(t, `
+Host web
+    Include conf.d/*.conf
+    Port 2200
+
+Host top
+    HostName top.example.com
+`)
+	dir := filepath.Join(filepath.Dir(path), "conf.d")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"a.conf": "User inherited\n\nHost db\n    HostName db.example.com\n",
+		"b.conf": "Host cache\n    HostName cache.example.com\n    Port 6380\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hosts := parseHosts(t, path)
+	if len(hosts) != 4 {
+		t.Fatalf("hosts = %v, want web, db, cache, top", hosts)
+	}
+	// Include 先の先頭部分は Include した Host web の条件で適用され、Include 後は元の条件に戻る
+	if h := hosts["web"]; h.User != "inherited" || h.Port != 2200 {
+		t.Errorf("web = %+v, want User inherited and Port 2200", h)
+	}
+	if h := hosts["db"]; h.HostName != "db.example.com" || h.User == "inherited" || h.Port != 22 {
+		t.Errorf("db = %+v, want included host without web settings", h)
+	}
+	if h := hosts["cache"]; h.Port != 6380 {
+		t.Errorf("cache Port = %d, want 6380", h.Port)
+	}
+	if h := hosts["top"]; h.HostName != "top.example.com" {
+		t.Errorf("top HostName = %q", h.HostName)
+	}
+}
+
+func TestSSHConfigParser_IncludeLoop(t *testing.T) {
+	path := writeSSHConfig(t, "Include config\n")
+	if _, err := NewSSHConfigParser().Parse(path); err == nil {
+		t.Error("expected error for recursive Include")
+	}
+}
+
+func TestSSHConfigParser_MatchAndWildcards(t *testing.T) {
+	path := writeSSHConfig(t, `
+Host *.prod !bastion.prod
+    ProxyJump bastion.prod
+
+Host app.prod bastion.prod stage
+    HostName %h.example.com
+
+Match originalhost stage
+    Port 2022
+
+Match host *.example.com user deploy
+    IdentityAgent none
+
+Match exec "true"
+    Port 1
+
+Host *
+    User deploy
+    ServerAliveInterval 15
+    ServerAliveCountMax 4
+`)
+
+	hosts := parseHosts(t, path)
+	app, bastion, stage := hosts["app.prod"], hosts["bastion.prod"], hosts["stage"]
+	if len(app.ProxyJump) != 1 || app.ProxyJump[0] != "bastion.prod" || bastion.ProxyJump != nil {
+		t.Errorf("ProxyJump app = %v, bastion = %v", app.ProxyJump, bastion.ProxyJump)
+	}
+	if app.HostName != "app.prod.example.com" {
+		t.Errorf("HostName = %q, want %%h expanded", app.HostName)
+	}
+	if stage.Port != 2022 || app.Port != 22 {
+		t.Errorf("Port stage = %d, app = %d; want 2022 and 22", stage.Port, app.Port)
+	}
+	// User は Match より後の Host * で決まるため、Match user deploy は一致しない
+	if app.IdentityAgent != "" || app.User != "deploy" {
+		t.Errorf("app = %+v, want no IdentityAgent and User deploy", app)
+	}
+	if app.ServerAliveInterval != 15*time.Second || app.ServerAliveCountMax != 4 {
+		t.Errorf("ServerAlive = %v/%d, want 15s/4", app.ServerAliveInterval, app.ServerAliveCountMax)
+	}
+}
+
+func TestSSHConfigParser_TokensAndPortRange(t *testing.T) {
+	path := writeSSHConfig(t, `
+Host box
+    HostName box.internal
+    User admin
+    Port 70000
+    IdentityFile /keys/%n-%r@%h:%p
+    IdentityAgent "/run/%r/agent %%"
+`)
+
+	h := parseHosts(t, path)["box"]
+	if h.Port != 22 {
+		t.Errorf("Port = %d, want 22 for out-of-range value", h.Port)
+	}
+	if len(h.IdentityFiles) != 1 || h.IdentityFiles[0] != "/keys/box-admin@box.internal:22" {
+		t.Errorf("IdentityFiles = %v", h.IdentityFiles)
+	}
+	if h.IdentityAgent != "/run/admin/agent %" {
+		t.Errorf("IdentityAgent = %q", h.IdentityAgent)
+	}
+}
+
+func TestMatchPatternList(t *testing.T) {
+	tests := []struct {
+		patterns []string
+		name     string
+		want     bool
+	}{
+		{[]string{"*"}, "anything", true},
+		{[]string{"web-??"}, "WEB-01", true},
+		{[]string{"web-??"}, "web-1", false},
+		{[]string{"*.example.com", "!db.example.com"}, "db.example.com", false},
+		{[]string{"!db"}, "web", false},
+		{[]string{"a*b*c"}, "axxbyyc", true},
+	}
+	for _, tt := range tests {
+		if got := matchPatternList(tt.patterns, tt.name); got != tt.want {
+			t.Errorf("matchPatternList(%v, %q) = %v, want %v", tt.patterns, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSources(t *testing.T) {
+	path := writeSSHConfig(t, "Include conf.d/*.conf ~/.ssh/missing\n")
+	dir := filepath.Join(filepath.Dir(path), "conf.d")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "a.conf"), []byte("Host a\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	files, patterns, err := Sources(path)
+	if err != nil {
+		t.Fatalf("Sources: %v", err)
+	}
+	if len(files) != 2 || files[0] != path || files[1] != filepath.Join(dir, "a.conf") {
+		t.Errorf("files = %v, want config and conf.d/a.conf", files)
+	}
+	if len(patterns) != 2 || patterns[0] != filepath.Join(dir, "*.conf") {
+		t.Errorf("patterns = %v, want conf.d/*.conf first", patterns)
+	}
+}
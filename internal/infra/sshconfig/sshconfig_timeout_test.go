@@ -0,0 +1,37 @@
+package sshconfig
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSSHConfigParser_ConnectTimeout(t *testing.T) {
+	path := writeSSHConfig(t, `
+Host slow
+    HostName 10.0.0.5
+    ConnectTimeout 5
+
+Host invalid
+    HostName 10.0.0.6
+    ConnectTimeout soon
+
+Host unset
+    HostName 10.0.0.7
+`)
+
+	parser := NewSSHConfigParser()
+	hosts, err := parser.Parse(path)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	want := map[string]time.Duration{"slow": 5 * time.Second, "invalid": 0, "unset": 0}
+	if len(hosts) != len(want) {
+		t.Fatalf("len(hosts) = %d, want %d", len(hosts), len(want))
+	}
+	for _, h := range hosts {
+		if h.ConnectTimeout != want[h.Name] {
+			t.Errorf("%s: ConnectTimeout = %v, want %v", h.Name, h.ConnectTimeout, want[h.Name])
+		}
+	}
+}
@@ -1,32 +1,31 @@
 package infra
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log/slog"
 	"net"
-	"os"
 	"path/filepath"
-	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"golang.org/x/crypto/ssh"
-	"golang.org/x/crypto/ssh/knownhosts"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/faultinject"
+	"github.com/ousiassllc/moleport/internal/infra/hostkey"
 	"github.com/ousiassllc/moleport/internal/infra/proxycommand"
-)
-
-const (
-	defaultDialTimeout      = 10 * time.Second
-	defaultHandshakeTimeout = 120 * time.Second
+	"github.com/ousiassllc/moleport/internal/infra/sshauth"
 )
 
 type sshConnection struct {
-	mu          sync.Mutex
-	client      *ssh.Client
-	agentCloser io.Closer
+	mu           sync.Mutex
+	client       *ssh.Client
+	jumps        []*ssh.Client // 経由した踏み台への接続（接続順）
+	agentClosers []io.Closer
+	rtt          atomic.Int64 // 直近の keepalive の往復時間（time.Duration）
 }
 
 // NewSSHConnection は core.SSHConnection の実装を返す。
@@ -35,8 +34,43 @@ func NewSSHConnection() core.SSHConnection {
 }
 
 // Dial は指定ホストへ SSH 接続を確立する。
-func (c *sshConnection) Dial(host core.SSHHost, cb core.CredentialCallback) (*ssh.Client, error) {
-	authMethods, agentCloser := buildAuthMethods(host, cb)
+// host.JumpHosts が設定されている場合は、各踏み台へ順に接続し、直前の踏み台を経由して次へ接続する。
+func (c *sshConnection) Dial(ctx context.Context, host core.SSHHost, cb core.CredentialCallback) (*ssh.Client, error) {
+	var (
+		via     *ssh.Client
+		jumps   []*ssh.Client
+		closers []io.Closer
+	)
+	for _, hop := range host.JumpHosts {
+		client, closer, err := dialHop(ctx, hop, via, cb)
+		if err != nil {
+			closeChain(nil, jumps, closers)
+			return nil, fmt.Errorf("failed to connect to jump host %s: %w", hop.Name, err)
+		}
+		jumps = append(jumps, client)
+		closers = appendCloser(closers, closer)
+		via = client
+	}
+
+	client, closer, err := dialHop(ctx, host, via, cb)
+	if err != nil {
+		closeChain(nil, jumps, closers)
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.client = client
+	c.jumps = jumps
+	c.agentClosers = appendCloser(closers, closer)
+	c.mu.Unlock()
+
+	return client, nil
+}
+
+// dialHop は host へ SSH 接続を確立する。via が nil でなければ via を経由して接続する。
+// 成功時はエージェント接続の Closer（なければ nil）を返す。ctx がキャンセルされた場合は接続を閉じて中断する。
+func dialHop(ctx context.Context, host core.SSHHost, via *ssh.Client, cb core.CredentialCallback) (*ssh.Client, io.Closer, error) {
+	authMethods, agentCloser := sshauth.BuildAuthMethods(host, cb)
 	// authMethods が空でも早期リターンしない。
 	// Go の crypto/ssh は常に "none" 認証を最初に試行するため、
 	// Tailscale SSH のように none 認証で動作するサーバーへの接続が可能。
@@ -52,10 +86,11 @@ func (c *sshConnection) Dial(host core.SSHHost, cb core.CredentialCallback) (*ss
 		}
 	}
 
-	hostKeyCallback, err := buildHostKeyCallback(host.StrictHostKeyChecking)
+	knownHostsPath := filepath.Join(homeDir(), ".ssh", "known_hosts")
+	hostKeyCallback, err := hostkey.Callback(knownHostsPath, host.Name, host.StrictHostKeyChecking, cb)
 	if err != nil {
 		closeAgent()
-		return nil, fmt.Errorf("failed to build host key callback: %w", err)
+		return nil, nil, fmt.Errorf("failed to build host key callback: %w", err)
 	}
 
 	config := &ssh.ClientConfig{
@@ -65,121 +100,91 @@ func (c *sshConnection) Dial(host core.SSHHost, cb core.CredentialCallback) (*ss
 	}
 
 	addr := net.JoinHostPort(host.HostName, fmt.Sprintf("%d", host.Port))
-	dialTimeout := defaultDialTimeout
+	connectTimeout := orDefault(host.ConnectTimeout, defaultDialTimeout)
+	bannerTimeout := orDefault(host.BannerTimeout, defaultBannerTimeout)
 
 	// クレデンシャルコールバックがある場合、ハンドシェイク中にユーザー入力を待つため
 	// デッドラインを長くする。
-	handshakeTimeout := dialTimeout
+	handshakeTimeout := connectTimeout
 	if cb != nil {
 		handshakeTimeout = defaultHandshakeTimeout
 	}
 
-	// ProxyJump が設定されていても現在は未対応のため警告を出力し、代替手段を案内
-	if len(host.ProxyJump) > 0 {
-		slog.Warn("ProxyJump is not supported; use ProxyCommand instead (e.g. ProxyCommand ssh -W %h:%p <jumphost>)",
-			"host", host.Name, "proxy_jump", host.ProxyJump)
+	if err := ctx.Err(); err != nil {
+		closeAgent()
+		return nil, nil, fmt.Errorf("failed to dial %s: %w", addr, err)
 	}
 
-	// 接続（ProxyCommand の有無で分岐）
-	// ProxyCommand が設定されている場合は ProxyJump より優先する（OpenSSH の挙動に準拠）。
+	// 障害注入（chaos ビルドのみ有効）
+	if err := faultinject.BeforeDial(host.Name); err != nil {
+		closeAgent()
+		return nil, nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+
+	// 接続（踏み台経由、ProxyCommand、直接接続の順に判定）
+	// ProxyCommand は経路の先頭でのみ使い、ProxyJump より優先する（OpenSSH の挙動に準拠）。
 	var conn net.Conn
-	if host.ProxyCommand != "" {
+	switch {
+	case via != nil:
+		conn, err = dialVia(ctx, via, addr, connectTimeout)
+	case host.ProxyCommand != "":
 		expandedCmd := proxycommand.ExpandCommand(host.ProxyCommand, host.HostName, host.Port, host.User)
 		conn, err = proxycommand.Dial(expandedCmd)
 		if err != nil {
-			closeAgent()
-			return nil, fmt.Errorf("failed to connect via ProxyCommand: %w", err)
-		}
-	} else {
-		conn, err = net.DialTimeout("tcp", addr, dialTimeout)
-		if err != nil {
-			closeAgent()
-			return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+			err = fmt.Errorf("failed to connect via ProxyCommand: %w", err)
 		}
+	default:
+		conn, err = dialTCP(ctx, addr, connectTimeout, tcpKeepAlive(host))
+	}
+	if err != nil {
+		closeAgent()
+		return nil, nil, err
 	}
 
-	// TCP + SSH ハンドシェイク全体にデッドラインを設定
-	if err := conn.SetDeadline(time.Now().Add(handshakeTimeout)); err != nil {
+	conn = faultinject.WrapConn(host.Name, conn)
+
+	// バナー受信までは bannerTimeout、受信後は handshakeTimeout のデッドラインを適用
+	bc, err := newBannerConn(conn, bannerTimeout, handshakeTimeout)
+	if err != nil {
 		_ = conn.Close()
 		closeAgent()
-		return nil, fmt.Errorf("failed to set deadline: %w", err)
+		return nil, nil, err
 	}
 
-	// SSH ハンドシェイク（デッドラインが適用される）
-	sshConn, chans, reqs, err := ssh.NewClientConn(conn, addr, config)
+	// SSH ハンドシェイク（デッドラインが適用される）。ctx がキャンセルされたら接続を閉じて中断する
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	sshConn, chans, reqs, err := ssh.NewClientConn(bc, addr, config)
+	if !stop() && err == nil {
+		// ハンドシェイクの完了と同時にキャンセルされた場合
+		_ = sshConn.Close()
+		err = ctx.Err()
+	}
 	if err != nil {
 		_ = conn.Close()
 		closeAgent()
-		return nil, fmt.Errorf("failed to establish SSH connection to %s: %w", addr, err)
+		if ctx.Err() != nil {
+			return nil, nil, fmt.Errorf("failed to establish SSH connection to %s: %w", addr, ctx.Err())
+		}
+		return nil, nil, bc.handshakeError(addr, bannerTimeout, err)
 	}
 
 	// ハンドシェイク完了後、デッドラインをクリア
 	if err := conn.SetDeadline(time.Time{}); err != nil {
 		_ = sshConn.Close()
 		closeAgent()
-		return nil, fmt.Errorf("failed to clear deadline: %w", err)
-	}
-
-	client := ssh.NewClient(sshConn, chans, reqs)
-
-	c.mu.Lock()
-	c.client = client
-	c.agentCloser = agentCloser
-	c.mu.Unlock()
-
-	return client, nil
-}
-
-func buildHostKeyCallback(strictHostKeyChecking string) (ssh.HostKeyCallback, error) {
-	if strings.EqualFold(strictHostKeyChecking, "no") {
-		return ssh.InsecureIgnoreHostKey(), nil //nolint:gosec // SSH config の StrictHostKeyChecking=no を尊重
+		return nil, nil, fmt.Errorf("failed to clear deadline: %w", err)
 	}
 
-	knownHostsPath := filepath.Join(homeDir(), ".ssh", "known_hosts")
-	callback, err := knownhosts.New(knownHostsPath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			// known_hosts が存在しない場合は空ファイルを自動生成し、
-			// 以降の接続でホストキーが記録されるようにする。
-			slog.Warn("known_hosts file not found, creating empty file",
-				"path", knownHostsPath)
-			if mkErr := os.MkdirAll(filepath.Dir(knownHostsPath), 0700); mkErr != nil {
-				return nil, fmt.Errorf("failed to create .ssh directory: %w", mkErr)
-			}
-			if mkErr := os.WriteFile(knownHostsPath, nil, 0600); mkErr != nil {
-				return nil, fmt.Errorf("failed to create known_hosts: %w", mkErr)
-			}
-			slog.Warn("known_hosts is empty; to trust host keys, run: ssh <host> manually",
-				"path", knownHostsPath)
-			// 空ファイルで再読込（全ホストキーを未知として扱う）
-			callback, err = knownhosts.New(knownHostsPath)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load known_hosts after creation: %w", err)
-			}
-			return callback, nil
-		}
-		return nil, fmt.Errorf("failed to load known_hosts (%s): %w", knownHostsPath, err)
-	}
-	return callback, nil
+	return ssh.NewClient(sshConn, chans, reqs), agentCloser, nil
 }
 
-// Close は SSH 接続とエージェント接続を閉じる。
+// Close は SSH 接続、経由した踏み台への接続、エージェント接続を閉じる。
 func (c *sshConnection) Close() error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if c.agentCloser != nil {
-		if err := c.agentCloser.Close(); err != nil {
-			slog.Debug("failed to close SSH agent connection", "error", err)
-		}
-		c.agentCloser = nil
-	}
-
-	if c.client == nil {
-		return nil
-	}
-	err := c.client.Close()
-	c.client = nil
+	err := closeChain(c.client, c.jumps, c.agentClosers)
+	c.client, c.jumps, c.agentClosers = nil, nil, nil
 	return err
 }
 
@@ -0,0 +1,49 @@
+//go:build chaos
+
+package infra
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/faultinject"
+)
+
+func TestSSHConnection_ChaosDialFailure(t *testing.T) {
+	s := newTestSSHServer(t)
+	t.Setenv("SSH_AUTH_SOCK", "")
+	t.Setenv("HOME", t.TempDir())
+	if err := faultinject.Configure(faultinject.Config{Enabled: true, DialFailureProbability: 1}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	t.Cleanup(func() { _ = faultinject.Configure(faultinject.Config{}) })
+
+	conn := NewSSHConnection()
+	t.Cleanup(func() { _ = conn.Close() })
+	if _, err := conn.Dial(t.Context(), testSSHHost(s), nil); !errors.Is(err, faultinject.ErrInjectedDialFailure) {
+		t.Errorf("Dial() error = %v, want ErrInjectedDialFailure", err)
+	}
+}
+
+func TestSSHConnection_ChaosDropConnection(t *testing.T) {
+	s := newTestSSHServer(t)
+	conn := dialTestServer(t, s, nil)
+	before := faultinject.Stats().DroppedConnections
+
+	if err := faultinject.Configure(faultinject.Config{Enabled: true, DropProbability: 1, DropInterval: 10 * time.Millisecond}); err != nil {
+		t.Fatalf("Configure() error = %v", err)
+	}
+	t.Cleanup(func() { _ = faultinject.Configure(faultinject.Config{}) })
+
+	deadline := time.Now().Add(5 * time.Second)
+	for conn.IsAlive() && time.Now().Before(deadline) {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if conn.IsAlive() {
+		t.Fatal("connection should be dropped by fault injection")
+	}
+	if faultinject.Stats().DroppedConnections <= before {
+		t.Error("DroppedConnections should be incremented")
+	}
+}
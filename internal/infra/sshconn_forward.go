@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/infra/handoff"
 )
 
 // closeOnCancel は ctx がキャンセルされたときにリスナーを閉じる goroutine を起動する。
@@ -20,20 +21,43 @@ func closeOnCancel(ctx context.Context, listener net.Listener, label string, add
 	}()
 }
 
+// LocalPortInUse はローカルポートが他のソケットで使用中かを返す。
+// 停止直後で handoff の引き継ぎ待ちにあるポートは使用中とみなさない。
+func LocalPortInUse(port int) bool {
+	return handoff.InUse(net.JoinHostPort(core.LocalhostAddr, fmt.Sprintf("%d", port)))
+}
+
+// bindHost はバインドアドレスを返す。空の場合は LocalhostAddr を返す。
+func bindHost(addr string) string {
+	if addr == "" {
+		return core.LocalhostAddr
+	}
+	return addr
+}
+
+// listenError はローカルのリスナーの作成に失敗したエラーを返す。ポートが使用中の場合は *core.PortConflictError を包む。
+func listenError(addr string, port int, err error) error {
+	if handoff.IsAddrInUse(err) {
+		err = &core.PortConflictError{Port: port}
+	}
+	return fmt.Errorf("failed to listen on %s: %w", addr, err)
+}
+
 // LocalForward はローカルポートフォワーディング用のリスナーを作成する。
 // このメソッドはリスナーの作成のみを行い、accept ループやデータ転送は行わない。
+// リスナーは handoff 経由で開くため、停止直後に同じポートで再開した場合は同じソケットを引き継ぐ。
 // 呼び出し元（ForwardManager）が返されたリスナーで accept ループを実行し、
 // Dial() で取得した ssh.Client を使ってリモートへのデータブリッジを行う。
-func (c *sshConnection) LocalForward(ctx context.Context, localPort int, remoteAddr string) (net.Listener, error) {
+func (c *sshConnection) LocalForward(ctx context.Context, localPort int, remoteAddr string, localBindAddr string) (net.Listener, error) {
 	client := c.getClient()
 	if client == nil {
 		return nil, fmt.Errorf("not connected")
 	}
 
-	addr := net.JoinHostPort(core.LocalhostAddr, fmt.Sprintf("%d", localPort))
-	listener, err := net.Listen("tcp", addr)
+	addr := net.JoinHostPort(bindHost(localBindAddr), fmt.Sprintf("%d", localPort))
+	listener, err := handoff.Listen(ctx, addr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		return nil, listenError(addr, localPort, err)
 	}
 
 	closeOnCancel(ctx, listener, "local forward", addr)
@@ -50,10 +74,7 @@ func (c *sshConnection) RemoteForward(ctx context.Context, remotePort int, local
 		return nil, fmt.Errorf("not connected")
 	}
 
-	if remoteBindAddr == "" {
-		remoteBindAddr = core.LocalhostAddr
-	}
-	addr := net.JoinHostPort(remoteBindAddr, fmt.Sprintf("%d", remotePort))
+	addr := net.JoinHostPort(bindHost(remoteBindAddr), fmt.Sprintf("%d", remotePort))
 	listener, err := client.Listen("tcp", addr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to listen remotely on %s: %w", addr, err)
@@ -67,16 +88,16 @@ func (c *sshConnection) RemoteForward(ctx context.Context, remotePort int, local
 // このメソッドはリスナーの作成のみを行い、SOCKS プロトコル処理やデータ転送は行わない。
 // 呼び出し元（ForwardManager）が返されたリスナーで accept ループを実行し、
 // Dial() で取得した ssh.Client を使って SOCKS プロキシのデータブリッジを行う。
-func (c *sshConnection) DynamicForward(ctx context.Context, localPort int) (net.Listener, error) {
+func (c *sshConnection) DynamicForward(ctx context.Context, localPort int, localBindAddr string) (net.Listener, error) {
 	client := c.getClient()
 	if client == nil {
 		return nil, fmt.Errorf("not connected")
 	}
 
-	addr := net.JoinHostPort(core.LocalhostAddr, fmt.Sprintf("%d", localPort))
-	listener, err := net.Listen("tcp", addr)
+	addr := net.JoinHostPort(bindHost(localBindAddr), fmt.Sprintf("%d", localPort))
+	listener, err := handoff.Listen(ctx, addr)
 	if err != nil {
-		return nil, fmt.Errorf("failed to listen on %s: %w", addr, err)
+		return nil, listenError(addr, localPort, err)
 	}
 
 	closeOnCancel(ctx, listener, "dynamic forward", addr)
@@ -94,20 +115,60 @@ func (c *sshConnection) IsAlive() bool {
 	return err == nil
 }
 
-// KeepAlive は定期的に keepalive リクエストを送信する。
-func (c *sshConnection) KeepAlive(ctx context.Context, interval time.Duration) {
+// KeepAlive は interval ごとに keepalive リクエストを送信する。
+// 送信に失敗した場合と、interval 以内に応答のない確認が countMax 回続いた場合に戻る。
+// 経路が無応答になった接続は TCP のタイムアウトを待たずにこの時点で接続断とみなす。
+func (c *sshConnection) KeepAlive(ctx context.Context, interval time.Duration, countMax int) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
+	// 最初の interval を待たずに往復時間を得るため、開始直後にも 1 回確認する
+	c.ping(ctx, interval)
+	missed := 0
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if !c.IsAlive() {
+			alive, answered := c.ping(ctx, interval)
+			switch {
+			case !answered:
+				missed++
+				if missed >= countMax {
+					slog.Warn("keepalive timed out, connection may be lost", "interval", interval, "missed", missed)
+					return
+				}
+			case !alive:
 				slog.Warn("keepalive failed, connection may be lost")
 				return
+			default:
+				missed = 0
 			}
 		}
 	}
 }
+
+// ping は keepalive リクエストを送信し、timeout 以内に応答があったか（answered）と接続が有効か（alive）を返す。
+// 応答があった場合はその往復時間を RTT として記録する。
+// 応答を待つ goroutine は、応答を受け取るか接続が閉じられた時点で終了する。
+func (c *sshConnection) ping(ctx context.Context, timeout time.Duration) (alive, answered bool) {
+	result := make(chan bool, 1)
+	start := time.Now()
+	go func() { result <- c.IsAlive() }()
+	select {
+	case alive = <-result:
+		if alive {
+			c.rtt.Store(int64(time.Since(start)))
+		}
+		return alive, true
+	case <-time.After(timeout):
+		return false, false
+	case <-ctx.Done():
+		return true, true
+	}
+}
+
+// RTT は直近の keepalive の往復時間を返す。まだ応答を受け取っていない場合は 0 を返す。
+func (c *sshConnection) RTT() time.Duration {
+	return time.Duration(c.rtt.Load())
+}
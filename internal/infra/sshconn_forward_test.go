@@ -2,8 +2,12 @@ package infra
 
 import (
 	"context"
+	"errors"
+	"net"
 	"testing"
 	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
 )
 
 func TestSSHConnection_LocalForwardSuccess(t *testing.T) {
@@ -13,7 +17,7 @@ func TestSSHConnection_LocalForwardSuccess(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	ln, err := conn.LocalForward(ctx, 0, "localhost:80")
+	ln, err := conn.LocalForward(ctx, 0, "localhost:80", "")
 	if err != nil {
 		t.Fatalf("LocalForward failed: %v", err)
 	}
@@ -38,7 +42,7 @@ func TestSSHConnection_DynamicForwardSuccess(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
-	ln, err := conn.DynamicForward(ctx, 0)
+	ln, err := conn.DynamicForward(ctx, 0, "")
 	if err != nil {
 		t.Fatalf("DynamicForward failed: %v", err)
 	}
@@ -77,3 +81,40 @@ func TestSSHConnection_RemoteForwardSuccess(t *testing.T) {
 		t.Error("Accept should fail after context cancellation")
 	}
 }
+
+func TestSSHConnection_LocalForwardBindAddr(t *testing.T) {
+	s := newTestSSHServer(t)
+	conn := dialTestServer(t, s, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := conn.LocalForward(ctx, 0, "localhost:80", "0.0.0.0")
+	if err != nil {
+		t.Fatalf("LocalForward failed: %v", err)
+	}
+	if addr, ok := ln.Addr().(*net.TCPAddr); !ok || !addr.IP.IsUnspecified() {
+		t.Errorf("listener address = %v, want unspecified IP", ln.Addr())
+	}
+	if got := bindHost(""); got != core.LocalhostAddr {
+		t.Errorf("bindHost(\"\") = %q, want %q", got, core.LocalhostAddr)
+	}
+}
+
+func TestSSHConnection_LocalForwardPortInUse(t *testing.T) {
+	s := newTestSSHServer(t)
+	conn := dialTestServer(t, s, nil)
+
+	taken, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer taken.Close()
+	port := taken.Addr().(*net.TCPAddr).Port
+
+	_, err = conn.LocalForward(t.Context(), port, "localhost:80", "")
+	var conflict *core.PortConflictError
+	if !errors.As(err, &conflict) || conflict.Port != port || !errors.Is(err, core.ErrPortInUse) {
+		t.Errorf("LocalForward() error = %v, want a PortConflictError for port %d", err, port)
+	}
+}
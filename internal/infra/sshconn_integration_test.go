@@ -5,6 +5,7 @@ import (
 	"crypto/ed25519"
 	"crypto/rand"
 	"encoding/pem"
+	"net"
 	"os"
 	"path/filepath"
 	"testing"
@@ -29,7 +30,7 @@ func dialTestServer(t *testing.T, s *testSSHServer, cb core.CredentialCallback,
 	conn := NewSSHConnection()
 	t.Cleanup(func() { _ = conn.Close() })
 
-	if _, err := conn.Dial(host, cb); err != nil {
+	if _, err := conn.Dial(t.Context(), host, cb); err != nil {
 		t.Fatalf("Dial failed: %v", err)
 	}
 	return conn
@@ -98,7 +99,7 @@ func TestSSHConnection_KeepAliveReturnsOnCancel(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	done := make(chan struct{})
 	go func() {
-		conn.KeepAlive(ctx, 50*time.Millisecond)
+		conn.KeepAlive(ctx, 50*time.Millisecond, 3)
 		close(done)
 	}()
 
@@ -119,7 +120,7 @@ func TestSSHConnection_KeepAliveReturnsOnDisconnect(t *testing.T) {
 
 	done := make(chan struct{})
 	go func() {
-		conn.KeepAlive(ctx, 50*time.Millisecond)
+		conn.KeepAlive(ctx, 50*time.Millisecond, 3)
 		close(done)
 	}()
 
@@ -132,3 +133,58 @@ func TestSSHConnection_KeepAliveReturnsOnDisconnect(t *testing.T) {
 		t.Fatal("KeepAlive did not return after disconnect")
 	}
 }
+
+func TestSSHConnection_KeepAliveReturnsWhenUnanswered(t *testing.T) {
+	// keepalive に応答しないサーバー（経路が無応答になった接続を模す）
+	s := startTestSSHServer(t, func(netConn net.Conn, cfg *ssh.ServerConfig) {
+		sshConn, chans, reqs, err := ssh.NewServerConn(netConn, cfg)
+		if err != nil {
+			_ = netConn.Close()
+			return
+		}
+		defer func() { _ = sshConn.Close() }()
+		// グローバルリクエストは応答せずに読み捨てる
+		go func() {
+			for range reqs {
+			}
+		}()
+		for newCh := range chans {
+			_ = newCh.Reject(ssh.Prohibited, "not supported")
+		}
+	})
+	conn := dialTestServer(t, s, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		conn.KeepAlive(ctx, 30*time.Millisecond, 2)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(3 * time.Second):
+		t.Fatal("KeepAlive did not return after unanswered keepalives")
+	}
+}
+
+func TestSSHConnection_KeepAliveRecordsRTT(t *testing.T) {
+	s := newTestSSHServer(t)
+	conn := dialTestServer(t, s, nil)
+	if rtt := conn.RTT(); rtt != 0 {
+		t.Fatalf("RTT before keepalive = %v, want 0", rtt)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go conn.KeepAlive(ctx, time.Second, 3)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for conn.RTT() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("RTT was not recorded after the first keepalive")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
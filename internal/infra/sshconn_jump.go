@@ -0,0 +1,120 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// dialVia は踏み台の SSH 接続 via を経由して、connectTimeout 以内に addr へ接続する。
+func dialVia(ctx context.Context, via *ssh.Client, addr string, connectTimeout time.Duration) (net.Conn, error) {
+	dialCtx, cancel := context.WithTimeout(ctx, connectTimeout)
+	defer cancel()
+
+	conn, err := via.DialContext(dialCtx, "tcp", addr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to dial %s via jump host: %w", addr, ctx.Err())
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, fmt.Errorf("failed to dial %s via jump host: connection timed out after %s: %w", addr, connectTimeout, err)
+		}
+		return nil, fmt.Errorf("failed to dial %s via jump host: %w", addr, err)
+	}
+	return &tunnelConn{Conn: conn}, nil
+}
+
+// tunnelConn は踏み台経由の接続（SSH チャネル）にデッドラインを提供する net.Conn。
+// SSH チャネルはデッドラインに対応しないため、期限に達した時点で接続を閉じて読み書きを中断する。
+type tunnelConn struct {
+	net.Conn
+	mu      sync.Mutex
+	timer   *time.Timer
+	expired bool
+}
+
+// SetDeadline は t に接続を閉じるタイマーを設定する。ゼロ値の場合はタイマーを解除する。
+func (c *tunnelConn) SetDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.timer != nil {
+		c.timer.Stop()
+		c.timer = nil
+	}
+	if t.IsZero() {
+		return nil
+	}
+	c.timer = time.AfterFunc(time.Until(t), func() {
+		c.mu.Lock()
+		c.expired = true
+		c.mu.Unlock()
+		_ = c.Conn.Close()
+	})
+	return nil
+}
+
+// SetReadDeadline は読み書き共通のデッドラインとして SetDeadline に委譲する。
+func (c *tunnelConn) SetReadDeadline(t time.Time) error { return c.SetDeadline(t) }
+
+// SetWriteDeadline は読み書き共通のデッドラインとして SetDeadline に委譲する。
+func (c *tunnelConn) SetWriteDeadline(t time.Time) error { return c.SetDeadline(t) }
+
+// Read はデッドラインで接続が閉じられた場合に os.ErrDeadlineExceeded を返す。
+func (c *tunnelConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	return n, c.deadlineError(err)
+}
+
+// Write はデッドラインで接続が閉じられた場合に os.ErrDeadlineExceeded を返す。
+func (c *tunnelConn) Write(p []byte) (int, error) {
+	n, err := c.Conn.Write(p)
+	return n, c.deadlineError(err)
+}
+
+func (c *tunnelConn) deadlineError(err error) error {
+	if err == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.expired {
+		return fmt.Errorf("%w: %v", os.ErrDeadlineExceeded, err)
+	}
+	return err
+}
+
+// appendCloser は closer が nil でなければ closers に追加する。
+func appendCloser(closers []io.Closer, closer io.Closer) []io.Closer {
+	if closer == nil {
+		return closers
+	}
+	return append(closers, closer)
+}
+
+// closeChain は client、踏み台への接続（内側から順）、エージェント接続を閉じ、client を閉じた際のエラーを返す。
+func closeChain(client *ssh.Client, jumps []*ssh.Client, closers []io.Closer) error {
+	var err error
+	if client != nil {
+		err = client.Close()
+	}
+	for i := len(jumps) - 1; i >= 0; i-- {
+		if jErr := jumps[i].Close(); jErr != nil {
+			slog.Debug("failed to close jump host connection", "error", jErr)
+		}
+	}
+	for _, closer := range closers {
+		if cErr := closer.Close(); cErr != nil {
+			slog.Debug("failed to close SSH agent connection", "error", cErr)
+		}
+	}
+	return err
+}
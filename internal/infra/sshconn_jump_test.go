@@ -0,0 +1,116 @@
+package infra
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// directTCPIPPayload は direct-tcpip チャネルのペイロード（RFC 4254 7.2）。
+type directTCPIPPayload struct {
+	Host     string
+	Port     uint32
+	OrigHost string
+	OrigPort uint32
+}
+
+// handleTestJumpConn は direct-tcpip チャネルを中継する踏み台として振る舞う。
+func handleTestJumpConn(netConn net.Conn, cfg *ssh.ServerConfig) {
+	sshConn, chans, reqs, err := ssh.NewServerConn(netConn, cfg)
+	if err != nil {
+		_ = netConn.Close()
+		return
+	}
+	defer func() { _ = sshConn.Close() }()
+	go ssh.DiscardRequests(reqs)
+
+	for newCh := range chans {
+		var p directTCPIPPayload
+		if newCh.ChannelType() != "direct-tcpip" || ssh.Unmarshal(newCh.ExtraData(), &p) != nil {
+			_ = newCh.Reject(ssh.Prohibited, "not supported")
+			continue
+		}
+		target, err := net.Dial("tcp", net.JoinHostPort(p.Host, fmt.Sprint(p.Port)))
+		if err != nil {
+			_ = newCh.Reject(ssh.ConnectionFailed, err.Error())
+			continue
+		}
+		ch, chReqs, err := newCh.Accept()
+		if err != nil {
+			_ = target.Close()
+			continue
+		}
+		go ssh.DiscardRequests(chReqs)
+		go func() {
+			_, _ = io.Copy(ch, target)
+			_ = ch.CloseWrite()
+		}()
+		go func() {
+			_, _ = io.Copy(target, ch)
+			_ = target.Close()
+		}()
+	}
+}
+
+func TestSSHConnection_DialThroughJumpHosts(t *testing.T) {
+	target := newTestSSHServer(t)
+	jump1 := startTestSSHServer(t, handleTestJumpConn)
+	jump2 := startTestSSHServer(t, handleTestJumpConn)
+
+	host := testSSHHost(target)
+	host.JumpHosts = append(host.JumpHosts, testSSHHost(jump1), testSSHHost(jump2))
+
+	conn := NewSSHConnection()
+	client, err := conn.Dial(t.Context(), host, nil)
+	if err != nil {
+		t.Fatalf("Dial through jump hosts: %v", err)
+	}
+	if _, _, err := client.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+		t.Errorf("request over chained connection failed: %v", err)
+	}
+	if got := len(conn.(*sshConnection).jumps); got != 2 {
+		t.Errorf("jumps = %d, want 2", got)
+	}
+
+	if err := conn.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if conn.(*sshConnection).jumps != nil {
+		t.Error("jump connections should be released on Close")
+	}
+}
+
+func TestSSHConnection_DialJumpHostFailure(t *testing.T) {
+	target := newTestSSHServer(t)
+	jump := startTestSSHServer(t, handleTestJumpConn)
+	_ = jump.ln.Close()
+
+	host := testSSHHost(target)
+	bad := testSSHHost(jump)
+	bad.Name = "bastion"
+	host.JumpHosts = append(host.JumpHosts, bad)
+
+	_, err := NewSSHConnection().Dial(t.Context(), host, nil)
+	if err == nil || !strings.Contains(err.Error(), "failed to connect to jump host bastion") {
+		t.Errorf("error = %v, want jump host failure", err)
+	}
+}
+
+func TestTunnelConn_DeadlineClosesConn(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = server.Close() }()
+
+	tc := &tunnelConn{Conn: client}
+	if err := tc.SetDeadline(time.Now().Add(50 * time.Millisecond)); err != nil {
+		t.Fatalf("SetDeadline: %v", err)
+	}
+	_, err := tc.Read(make([]byte, 1))
+	if !isTimeout(err) {
+		t.Errorf("Read error = %v, want deadline exceeded", err)
+	}
+}
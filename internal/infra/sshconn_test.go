@@ -38,7 +38,7 @@ func TestSSHConnection_CloseMultipleTimes(t *testing.T) {
 func TestSSHConnection_LocalForwardNotConnected(t *testing.T) {
 	conn := NewSSHConnection()
 	ctx := context.Background()
-	_, err := conn.LocalForward(ctx, 8080, "localhost:80")
+	_, err := conn.LocalForward(ctx, 8080, "localhost:80", "")
 	if err == nil {
 		t.Error("LocalForward should return error when not connected")
 	}
@@ -56,7 +56,7 @@ func TestSSHConnection_RemoteForwardNotConnected(t *testing.T) {
 func TestSSHConnection_DynamicForwardNotConnected(t *testing.T) {
 	conn := NewSSHConnection()
 	ctx := context.Background()
-	_, err := conn.DynamicForward(ctx, 1080)
+	_, err := conn.DynamicForward(ctx, 1080, "")
 	if err == nil {
 		t.Error("DynamicForward should return error when not connected")
 	}
@@ -100,7 +100,7 @@ func TestSSHConnection_DialAttemptsConnectionWithNoAuthMethods(t *testing.T) {
 	conn := NewSSHConnection()
 	defer func() { _ = conn.Close() }()
 
-	_, dialErr := conn.Dial(host, nil)
+	_, dialErr := conn.Dial(t.Context(), host, nil)
 	if dialErr == nil {
 		t.Fatal("Dial should return error (handshake fails with dummy server)")
 	}
@@ -148,7 +148,7 @@ func TestSSHConnection_DialTimeoutOnHangingHandshake(t *testing.T) {
 	defer func() { _ = conn.Close() }()
 
 	start := time.Now()
-	_, dialErr := conn.Dial(host, nil)
+	_, dialErr := conn.Dial(t.Context(), host, nil)
 	elapsed := time.Since(start)
 
 	if dialErr == nil {
@@ -179,7 +179,7 @@ func TestSSHConnection_DialWithProxyCommand(t *testing.T) {
 	conn := NewSSHConnection()
 	defer func() { _ = conn.Close() }()
 
-	_, err := conn.Dial(host, nil)
+	_, err := conn.Dial(t.Context(), host, nil)
 	if err == nil {
 		t.Fatal("Dial should return error")
 	}
@@ -211,7 +211,7 @@ func TestSSHConnection_DialWithProxyCommandPriority(t *testing.T) {
 	conn := NewSSHConnection()
 	defer func() { _ = conn.Close() }()
 
-	_, err := conn.Dial(host, nil)
+	_, err := conn.Dial(t.Context(), host, nil)
 	if err == nil {
 		t.Fatal("Dial should return error")
 	}
@@ -260,7 +260,7 @@ func TestSSHConnection_DialStrictHostKeyCheckingNo(t *testing.T) {
 	conn := NewSSHConnection()
 	defer func() { _ = conn.Close() }()
 
-	_, dialErr := conn.Dial(host, nil)
+	_, dialErr := conn.Dial(t.Context(), host, nil)
 	if dialErr == nil {
 		t.Fatal("Dial should return error (handshake fails with dummy server)")
 	}
@@ -0,0 +1,99 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+const (
+	defaultDialTimeout      = 10 * time.Second
+	defaultBannerTimeout    = 10 * time.Second
+	defaultHandshakeTimeout = 120 * time.Second
+)
+
+// orDefault は d が 0 以下の場合に def を返す。
+func orDefault(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
+
+// isTimeout はエラーがデッドライン超過・タイムアウトによるものかを返す。
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, os.ErrDeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+}
+
+// dialTCP は connectTimeout 以内（ctx のキャンセルまで）に addr へ TCP 接続し、keepAlive の TCP keepalive を有効にする。
+// タイムアウトした場合はエラーメッセージにタイムアウト値を含める。
+func dialTCP(ctx context.Context, addr string, connectTimeout time.Duration, keepAlive net.KeepAliveConfig) (net.Conn, error) {
+	d := net.Dialer{Timeout: connectTimeout, KeepAliveConfig: keepAlive}
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("failed to dial %s: %w", addr, ctx.Err())
+		}
+		if isTimeout(err) {
+			return nil, fmt.Errorf("failed to dial %s: connection timed out after %s: %w", addr, connectTimeout, err)
+		}
+		return nil, fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+	return conn, nil
+}
+
+// tcpKeepAlive は SSH の keepalive と同じ間隔・回数の TCP keepalive 設定を返す。0 の項目は OS の既定値を使う。
+func tcpKeepAlive(host core.SSHHost) net.KeepAliveConfig {
+	return net.KeepAliveConfig{
+		Enable:   true,
+		Idle:     host.ServerAliveInterval,
+		Interval: host.ServerAliveInterval,
+		Count:    host.ServerAliveCountMax,
+	}
+}
+
+// bannerConn は SSH バナー（サーバーからの最初のデータ）を受信するまで bannerTimeout の
+// デッドラインを適用し、受信後はハンドシェイク用のデッドラインに切り替える net.Conn。
+type bannerConn struct {
+	net.Conn
+	handshakeTimeout time.Duration
+	received         bool
+}
+
+// newBannerConn はバナー受信のデッドラインを設定した bannerConn を返す。
+func newBannerConn(conn net.Conn, bannerTimeout, handshakeTimeout time.Duration) (*bannerConn, error) {
+	if err := conn.SetDeadline(time.Now().Add(bannerTimeout)); err != nil {
+		return nil, fmt.Errorf("failed to set deadline: %w", err)
+	}
+	return &bannerConn{Conn: conn, handshakeTimeout: handshakeTimeout}, nil
+}
+
+// Read は最初のデータ受信時にデッドラインをハンドシェイク用に延長する。
+// バナー受信はハンドシェイクを行う goroutine からのみ行われるため、received の更新に排他は不要。
+func (c *bannerConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	if n > 0 && !c.received {
+		c.received = true
+		if dlErr := c.Conn.SetDeadline(time.Now().Add(c.handshakeTimeout)); dlErr != nil && err == nil {
+			err = dlErr
+		}
+	}
+	return n, err
+}
+
+// handshakeError は SSH ハンドシェイクのエラーを、バナー待ちのタイムアウトであればその旨とタイムアウト値を含めて返す。
+func (c *bannerConn) handshakeError(addr string, bannerTimeout time.Duration, err error) error {
+	if !c.received && isTimeout(err) {
+		return fmt.Errorf("failed to establish SSH connection to %s: no SSH banner received within %s: %w", addr, bannerTimeout, err)
+	}
+	if isTimeout(err) {
+		return fmt.Errorf("failed to establish SSH connection to %s: handshake timed out after %s: %w", addr, c.handshakeTimeout, err)
+	}
+	return fmt.Errorf("failed to establish SSH connection to %s: %w", addr, err)
+}
@@ -0,0 +1,130 @@
+package infra
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestSSHConnection_DialBannerTimeout(t *testing.T) {
+	// TCP accept するがバナーを送らないサーバー
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+
+	host := core.SSHHost{
+		Name:          "test-banner",
+		HostName:      "127.0.0.1",
+		Port:          ln.Addr().(*net.TCPAddr).Port,
+		User:          "testuser",
+		BannerTimeout: 200 * time.Millisecond,
+	}
+
+	conn := NewSSHConnection()
+	defer func() { _ = conn.Close() }()
+
+	start := time.Now()
+	_, dialErr := conn.Dial(t.Context(), host, nil)
+	elapsed := time.Since(start)
+
+	if dialErr == nil {
+		t.Fatal("Dial should return error when no banner is sent")
+	}
+	if !strings.Contains(dialErr.Error(), "no SSH banner received within 200ms") {
+		t.Errorf("error = %q, want banner timeout with value", dialErr)
+	}
+	if elapsed > 2*time.Second {
+		t.Errorf("Dial took %v, want banner timeout to apply", elapsed)
+	}
+}
+
+func TestBannerConn_ExtendsDeadlineAfterBanner(t *testing.T) {
+	client, server := net.Pipe()
+	defer func() { _ = client.Close() }()
+	defer func() { _ = server.Close() }()
+
+	bc, err := newBannerConn(client, 100*time.Millisecond, time.Second)
+	if err != nil {
+		t.Fatalf("newBannerConn: %v", err)
+	}
+
+	go func() {
+		_, _ = server.Write([]byte("SSH-2.0-test\r\n"))
+		// バナー後はバナータイムアウトを超えても読み取れる
+		time.Sleep(200 * time.Millisecond)
+		_, _ = server.Write([]byte("kex"))
+	}()
+
+	buf := make([]byte, 64)
+	if _, err := bc.Read(buf); err != nil {
+		t.Fatalf("read banner: %v", err)
+	}
+	if _, err := bc.Read(buf); err != nil {
+		t.Fatalf("read after banner: %v (deadline was not extended)", err)
+	}
+}
+
+func TestDialTCP_TimeoutMessageIncludesValue(t *testing.T) {
+	// 0 に近いタイムアウトで確実にタイムアウトさせる
+	_, err := dialTCP(t.Context(), "192.0.2.1:22", time.Nanosecond, net.KeepAliveConfig{})
+	if err == nil {
+		t.Skip("dial unexpectedly succeeded")
+	}
+	if !isTimeout(err) || !strings.Contains(err.Error(), "timed out after 1ns") {
+		t.Errorf("error = %q, want connection timeout with value", err)
+	}
+}
+
+func TestSSHConnection_DialCancelled(t *testing.T) {
+	// バナーを送らないサーバーへの接続を、バナー待ちの途中で取り消す
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	defer func() { _ = ln.Close() }()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer func() { _ = conn.Close() }()
+		}
+	}()
+
+	host := core.SSHHost{
+		Name:          "test-cancel",
+		HostName:      "127.0.0.1",
+		Port:          ln.Addr().(*net.TCPAddr).Port,
+		User:          "testuser",
+		BannerTimeout: time.Minute,
+	}
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, dialErr := NewSSHConnection().Dial(ctx, host, nil)
+	if !errors.Is(dialErr, context.DeadlineExceeded) {
+		t.Errorf("Dial() error = %v, want context.DeadlineExceeded", dialErr)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Dial took %v, want it to stop when ctx is done", elapsed)
+	}
+}
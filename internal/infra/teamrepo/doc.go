@@ -0,0 +1,3 @@
+// Package teamrepo は設定ディレクトリ内のチーム共有設定（team.yaml）を git リポジトリまたは URL から取得し、
+// git リポジトリの場合は変更をコミットして push する。
+package teamrepo
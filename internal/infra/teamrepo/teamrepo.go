@@ -0,0 +1,164 @@
+package teamrepo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core/teamsync"
+	"github.com/ousiassllc/moleport/internal/infra/yamlstore"
+)
+
+// FileName は共有設定のファイル名。
+const FileName = "team.yaml"
+
+// urlFileName は URL から取得する場合に取得元を記録するファイル名。
+const urlFileName = ".source-url"
+
+// maxFileSize は URL から取得する共有設定の最大サイズ。
+const maxFileSize = 1 << 20
+
+// ErrNoSource は取得元が指定も記録もされていない場合のエラー。
+var ErrNoSource = errors.New("no team config source: specify --git or --url")
+
+// ErrNotGit は push 先が git リポジトリでない場合のエラー。
+var ErrNotGit = errors.New("team config is not a git repository: push requires a --git source")
+
+// Source は共有設定の取得元。Git と URL のどちらか一方を指定する。両方とも空の場合は前回の取得元を使う。
+type Source struct {
+	Git string
+	URL string
+}
+
+// Repo は dir に置かれたチーム共有設定を扱う。
+type Repo struct {
+	dir        string
+	store      yamlstore.YAMLStore
+	httpClient *http.Client
+}
+
+// New は dir（通常は設定ディレクトリ配下の team/）を扱う Repo を返す。
+func New(dir string) *Repo {
+	return &Repo{dir: dir, store: yamlstore.NewYAMLStore(), httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// Pull は取得元から共有設定を取得する。git の場合は初回に clone し、以降は fast-forward で pull する。
+// URL の場合は team.yaml をダウンロードし、取得元を記録する。
+func (r *Repo) Pull(ctx context.Context, src Source) error {
+	switch {
+	case src.Git != "" && src.URL != "":
+		return errors.New("specify either --git or --url, not both")
+	case src.Git != "" && !r.isGit():
+		if err := os.MkdirAll(filepath.Dir(r.dir), 0700); err != nil {
+			return err
+		}
+		return git(ctx, "", "clone", "--quiet", src.Git, r.dir)
+	case src.Git != "" || (src.URL == "" && r.isGit()):
+		return git(ctx, r.dir, "pull", "--quiet", "--ff-only")
+	case src.URL != "":
+		return r.download(ctx, src.URL)
+	}
+	url, err := os.ReadFile(filepath.Join(r.dir, urlFileName)) //nolint:gosec // 設定ディレクトリ内のファイル
+	if err != nil {
+		return ErrNoSource
+	}
+	return r.download(ctx, strings.TrimSpace(string(url)))
+}
+
+// Load は取得済みの共有設定を読み込む。未取得の場合は空の File を返す。
+func (r *Repo) Load() (teamsync.File, error) {
+	var f teamsync.File
+	if err := r.store.Read(filepath.Join(r.dir, FileName), &f); err != nil {
+		return teamsync.File{}, fmt.Errorf("failed to read %s: %w", FileName, err)
+	}
+	return f, nil
+}
+
+// Push は f を team.yaml に書き込み、変更があればコミットして push する。変更がない場合は false を返す。
+// リモートが先行している場合は push に失敗するため、先に Pull で取り込む必要がある。
+func (r *Repo) Push(ctx context.Context, f teamsync.File, message string) (bool, error) {
+	if !r.isGit() {
+		return false, ErrNotGit
+	}
+	if err := r.store.Write(filepath.Join(r.dir, FileName), f); err != nil {
+		return false, err
+	}
+	if err := git(ctx, r.dir, "add", FileName); err != nil {
+		return false, err
+	}
+	if err := git(ctx, r.dir, "diff", "--cached", "--quiet"); err == nil {
+		return false, nil
+	}
+	if err := git(ctx, r.dir, "commit", "--quiet", "-m", message); err != nil {
+		return false, err
+	}
+	return true, git(ctx, r.dir, "push", "--quiet")
+}
+
+func (r *Repo) isGit() bool {
+	_, err := os.Stat(filepath.Join(r.dir, ".git"))
+	return err == nil
+}
+
+// download は url の共有設定を検証してから team.yaml に保存し、取得元を記録する。
+func (r *Repo) download(ctx context.Context, url string) error {
+	if !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return fmt.Errorf("invalid team config url %q: must be http or https", url)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch team config: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to fetch team config: %s", resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFileSize))
+	if err != nil {
+		return fmt.Errorf("failed to fetch team config: %w", err)
+	}
+
+	if err := os.MkdirAll(r.dir, 0700); err != nil {
+		return err
+	}
+	path := filepath.Join(r.dir, FileName)
+	if err := os.WriteFile(path+".tmp", data, 0600); err != nil {
+		return err
+	}
+	var f teamsync.File
+	if err := r.store.Read(path+".tmp", &f); err != nil {
+		_ = os.Remove(path + ".tmp")
+		return fmt.Errorf("invalid team config: %w", err)
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(r.dir, urlFileName), []byte(url+"\n"), 0600)
+}
+
+// git は dir で git コマンドを実行する。失敗時は標準エラー出力をエラーに含める。
+func git(ctx context.Context, dir string, args ...string) error {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = dir
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("git %s: %s", args[0], msg)
+		}
+		return fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return nil
+}
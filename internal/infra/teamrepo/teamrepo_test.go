@@ -0,0 +1,98 @@
+package teamrepo
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/teamsync"
+)
+
+const teamYAML = `forwards:
+  - name: db
+    host: bastion
+    type: local
+    local_port: 15432
+    remote_port: 5432
+hosts:
+  bastion:
+    notes: shared bastion
+`
+
+func TestRepo_PullURL(t *testing.T) {
+	body := teamYAML
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	r := New(filepath.Join(t.TempDir(), "team"))
+	if err := r.Pull(context.Background(), Source{}); !errors.Is(err, ErrNoSource) {
+		t.Fatalf("Pull() without source = %v, want ErrNoSource", err)
+	}
+	if err := r.Pull(context.Background(), Source{URL: srv.URL}); err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	f, err := r.Load()
+	if err != nil || len(f.Forwards) != 1 || f.Forwards[0].Name != "db" || f.Hosts["bastion"].Notes != "shared bastion" {
+		t.Fatalf("Load() = %+v, %v", f, err)
+	}
+
+	// 記録した取得元から再取得する。不正な内容の場合は既存のファイルを残す
+	body = "forwards: [broken"
+	if err := r.Pull(context.Background(), Source{}); err == nil {
+		t.Error("Pull() of invalid YAML should fail")
+	}
+	if f, _ := r.Load(); len(f.Forwards) != 1 {
+		t.Errorf("Load() after failed pull = %+v, want previous config", f)
+	}
+	if _, err := r.Push(context.Background(), f, "update"); !errors.Is(err, ErrNotGit) {
+		t.Errorf("Push() = %v, want ErrNotGit", err)
+	}
+}
+
+func TestRepo_GitPullPush(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	for k, v := range map[string]string{
+		"GIT_AUTHOR_NAME": "test", "GIT_AUTHOR_EMAIL": "test@example.com",
+		"GIT_COMMITTER_NAME": "test", "GIT_COMMITTER_EMAIL": "test@example.com",
+		"GIT_CONFIG_GLOBAL": filepath.Join(t.TempDir(), "gitconfig"),
+	} {
+		t.Setenv(k, v)
+	}
+	ctx := context.Background()
+	remote := filepath.Join(t.TempDir(), "remote.git")
+	if err := git(ctx, "", "init", "--quiet", "--bare", remote); err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+
+	alice := New(filepath.Join(t.TempDir(), "team"))
+	if err := alice.Pull(ctx, Source{Git: remote}); err != nil {
+		t.Fatalf("Pull() clone error = %v", err)
+	}
+	f := teamsync.File{Forwards: []core.ForwardRule{{Name: "db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemotePort: 5432}}}
+	if pushed, err := alice.Push(ctx, f, "add db"); err != nil || !pushed {
+		t.Fatalf("Push() = %v, %v; want pushed", pushed, err)
+	}
+	if pushed, err := alice.Push(ctx, f, "no change"); err != nil || pushed {
+		t.Errorf("Push() without changes = %v, %v; want nothing to push", pushed, err)
+	}
+
+	bob := New(filepath.Join(t.TempDir(), "team"))
+	if err := bob.Pull(ctx, Source{Git: remote}); err != nil {
+		t.Fatalf("Pull() error = %v", err)
+	}
+	if got, err := bob.Load(); err != nil || len(got.Forwards) != 1 || got.Forwards[0].Name != "db" {
+		t.Errorf("Load() = %+v, %v; want db", got, err)
+	}
+	if err := bob.Pull(ctx, Source{}); err != nil {
+		t.Errorf("Pull() from recorded git source error = %v", err)
+	}
+}
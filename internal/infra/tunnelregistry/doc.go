@@ -0,0 +1,3 @@
+// Package tunnelregistry はリモートフォワードの公開先（ホスト・リモートポート・ローカルの転送先）を、
+// フォワードの開始・停止に合わせてチームのサービスディレクトリ等の HTTP エンドポイントに登録・登録解除する。
+package tunnelregistry
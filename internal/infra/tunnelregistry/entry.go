@@ -0,0 +1,53 @@
+package tunnelregistry
+
+import (
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// 登録先に送信する操作。
+const (
+	ActionRegister   = "register"
+	ActionUnregister = "unregister"
+)
+
+// Entry は登録先に POST するリモートフォワードの公開先。
+type Entry struct {
+	Action    string    `json:"action"`
+	Timestamp time.Time `json:"timestamp"`
+	Rule      string    `json:"rule"`
+	SessionID string    `json:"session_id,omitempty"`
+	// Host は ssh_config のホスト名、HostName は実際の接続先（取得できない場合は空）。
+	Host     string `json:"host"`
+	HostName string `json:"hostname,omitempty"`
+	// RemoteBind・RemotePort は SSH サーバー側で待ち受けるアドレスとポート。
+	RemoteBind string `json:"remote_bind,omitempty"`
+	RemotePort int    `json:"remote_port"`
+	// LocalTarget はローカル側の転送先（"127.0.0.1:3000" 等）。
+	LocalTarget string `json:"local_target"`
+}
+
+// FromSession はアクティブなリモートフォワードの登録内容を返す。リモートフォワードでない場合は false を返す。
+func FromSession(s core.ForwardSession) (Entry, bool) {
+	if s.Rule.Type != core.Remote {
+		return Entry{}, false
+	}
+	return Entry{
+		Action:      ActionRegister,
+		Rule:        s.Rule.Name,
+		SessionID:   s.ID,
+		Host:        s.Rule.Host,
+		RemoteBind:  s.Rule.RemoteBindAddr,
+		RemotePort:  s.Rule.RemotePort,
+		LocalTarget: net.JoinHostPort(s.Rule.LocalBindHost(), strconv.Itoa(s.Rule.LocalPort)),
+	}, true
+}
+
+// sameTarget は e と o が同じ公開先を表すかを返す。操作・時刻・セッション ID は比較しない。
+func (e Entry) sameTarget(o Entry) bool {
+	return e.Rule == o.Rule && e.Host == o.Host && e.RemoteBind == o.RemoteBind &&
+		e.RemotePort == o.RemotePort && e.LocalTarget == o.LocalTarget
+}
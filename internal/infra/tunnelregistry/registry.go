@@ -0,0 +1,200 @@
+package tunnelregistry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+)
+
+const (
+	defaultMaxRetries = 3
+	queueSize         = 64
+	requestTimeout    = 10 * time.Second
+	// drainTimeout は Close 時にキュー内の登録・登録解除を送信し終えるまで待つ上限。
+	drainTimeout = 5 * time.Second
+)
+
+// HostLookup は ssh_config のホスト名から接続先を調べる。core.SSHManager が満たす。
+type HostLookup interface {
+	GetHost(name string) (*core.SSHHost, error)
+}
+
+// Registry はリモートフォワードの開始・停止を登録・登録解除の Entry に変換し、単一のゴルーチンで順に登録先へ POST する。
+// 送信に失敗した場合は指数バックオフで再試行する。
+type Registry struct {
+	config     func() serviceconf.Registry
+	hosts      HostLookup
+	httpClient *http.Client
+	retryDelay time.Duration
+
+	// registered はルール名ごとの登録済みの公開先。HandleForwardEvent のゴルーチンのみが参照する。
+	registered map[string]Entry
+
+	queue  chan Entry
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New は Registry を生成し、送信ゴルーチンを開始する。config は送信のたびに呼ばれ、設定の変更を反映する。
+// hosts が nil の場合は Entry の HostName を設定しない。
+func New(config func() serviceconf.Registry, hosts HostLookup) *Registry {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Registry{
+		config:     config,
+		hosts:      hosts,
+		httpClient: &http.Client{Timeout: requestTimeout},
+		retryDelay: time.Second,
+		registered: make(map[string]Entry),
+		queue:      make(chan Entry, queueSize),
+		ctx:        ctx,
+		cancel:     cancel,
+		done:       make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+// HandleForwardEvent はリモートフォワードの開始・復元・移行・変更で公開先を登録し、停止・エラーで登録を解除する。
+// 移行や変更で公開先が変わった場合は、以前の公開先の登録を解除してから登録する。
+// 登録先の URL が設定されていない場合と nil の Registry では何もしない。単一のゴルーチンから呼ぶ。
+func (r *Registry) HandleForwardEvent(evt core.ForwardEvent) {
+	if r == nil || r.config().URL == "" {
+		return
+	}
+	prev, registered := r.registered[evt.RuleName]
+	switch evt.Type {
+	case core.ForwardEventStarted, core.ForwardEventRestored, core.ForwardEventMigrated, core.ForwardEventUpdated:
+		var next Entry
+		ok := false
+		if evt.Session != nil && evt.Session.Status == core.Active {
+			next, ok = FromSession(*evt.Session)
+		}
+		if registered && ok && prev.sameTarget(next) {
+			return
+		}
+		if registered {
+			r.unregister(prev)
+		}
+		if ok {
+			r.register(next)
+		}
+	case core.ForwardEventStopped, core.ForwardEventError:
+		if registered {
+			r.unregister(prev)
+		}
+	}
+}
+
+// Close はキューを閉じ、残りの登録・登録解除を drainTimeout まで送信してから送信ゴルーチンを停止する。
+// nil の Registry では何もしない。
+func (r *Registry) Close() {
+	if r == nil {
+		return
+	}
+	close(r.queue)
+	select {
+	case <-r.done:
+	case <-time.After(drainTimeout):
+		r.cancel()
+		<-r.done
+	}
+	r.cancel()
+}
+
+func (r *Registry) register(e Entry) {
+	if r.hosts != nil {
+		if h, err := r.hosts.GetHost(e.Host); err == nil {
+			e.HostName = h.HostName
+		}
+	}
+	r.registered[e.Rule] = e
+	r.publish(e)
+}
+
+func (r *Registry) unregister(e Entry) {
+	delete(r.registered, e.Rule)
+	e.Action = ActionUnregister
+	r.publish(e)
+}
+
+// publish は e に現在時刻を設定して送信キューに積む。キューが満杯の場合は破棄する。
+func (r *Registry) publish(e Entry) {
+	e.Timestamp = time.Now().UTC()
+	select {
+	case r.queue <- e:
+	default:
+		slog.Warn("tunnel registry queue full, entry dropped", "action", e.Action, "rule", e.Rule)
+	}
+}
+
+func (r *Registry) run() {
+	defer close(r.done)
+	for e := range r.queue {
+		body, err := json.Marshal(e)
+		if err != nil {
+			continue
+		}
+		r.deliver(e, body)
+	}
+}
+
+// deliver は body を登録先に送信する。2xx 以外の応答や通信エラーの場合は再試行する。
+func (r *Registry) deliver(e Entry, body []byte) {
+	cfg := r.config()
+	if cfg.URL == "" {
+		return
+	}
+	retries := cfg.MaxRetries
+	if retries <= 0 {
+		retries = defaultMaxRetries
+	}
+	delay := r.retryDelay
+	var err error
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(delay):
+			case <-r.ctx.Done():
+				slog.Warn("tunnel registry delivery canceled", "action", e.Action, "rule", e.Rule, "error", err)
+				return
+			}
+			delay *= 2
+		}
+		if err = r.post(cfg, body); err == nil {
+			return
+		}
+		slog.Debug("tunnel registry delivery failed", "url", cfg.URL, "action", e.Action, "attempt", attempt+1, "error", err)
+	}
+	slog.Warn("tunnel registry delivery gave up", "url", cfg.URL, "action", e.Action, "rule", e.Rule, "error", err)
+}
+
+func (r *Registry) post(cfg serviceconf.Registry, body []byte) error {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodPost, cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MolePort")
+	for k, v := range cfg.Headers {
+		req.Header.Set(k, os.ExpandEnv(v))
+	}
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package tunnelregistry
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+)
+
+// hostLookup は "bastion" の接続先を返す HostLookup。
+type hostLookup struct{}
+
+func (hostLookup) GetHost(name string) (*core.SSHHost, error) {
+	if name != "bastion" {
+		return nil, errors.New("not found")
+	}
+	return &core.SSHHost{Name: name, HostName: "bastion.example.com"}, nil
+}
+
+// recorder は受信したリクエストの本文と Authorization ヘッダーを記録する登録先。
+type recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+	auth    []string
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var e Entry
+	_ = json.NewDecoder(r.Body).Decode(&e)
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.entries = append(rec.entries, e)
+	rec.auth = append(rec.auth, r.Header.Get("Authorization"))
+}
+
+// newTestRegistry は再試行間隔を短くした Registry を返す。
+func newTestRegistry(cfg serviceconf.Registry) *Registry {
+	r := New(func() serviceconf.Registry { return cfg }, hostLookup{})
+	r.retryDelay = time.Millisecond
+	return r
+}
+
+// remoteEvent は "callback"（bastion の 8080 番ポートから localhost:3000 への転送）のイベントを返す。
+func remoteEvent(typ core.ForwardEventType, remotePort int) core.ForwardEvent {
+	rule := core.ForwardRule{Name: "callback", Host: "bastion", Type: core.Remote, LocalPort: 3000, RemotePort: remotePort}
+	return core.ForwardEvent{Type: typ, RuleName: rule.Name, Session: &core.ForwardSession{ID: "s1", Rule: rule, Status: core.Active}}
+}
+
+func TestRegistry_RegistersRemoteForwards(t *testing.T) {
+	t.Setenv("REGISTRY_TOKEN", "t0ken")
+	rec := &recorder{}
+	srv := httptest.NewServer(rec)
+	defer srv.Close()
+
+	r := newTestRegistry(serviceconf.Registry{URL: srv.URL, Headers: map[string]string{"Authorization": "Bearer ${REGISTRY_TOKEN}"}})
+	r.HandleForwardEvent(core.ForwardEvent{Type: core.ForwardEventStarted, RuleName: "web", Session: &core.ForwardSession{
+		Rule: core.ForwardRule{Name: "web", Host: "bastion", Type: core.Local, LocalPort: 8080}, Status: core.Active,
+	}})
+	r.HandleForwardEvent(remoteEvent(core.ForwardEventStarted, 8080))
+	// 同じ公開先での復元は登録し直さない
+	r.HandleForwardEvent(remoteEvent(core.ForwardEventRestored, 8080))
+	// 公開先が変わった場合は以前の公開先の登録を解除してから登録する
+	r.HandleForwardEvent(remoteEvent(core.ForwardEventUpdated, 9090))
+	stopped := remoteEvent(core.ForwardEventStopped, 9090)
+	stopped.Session.Status = core.Stopped
+	r.HandleForwardEvent(stopped)
+	// 登録していないフォワードの停止は送信しない
+	r.HandleForwardEvent(stopped)
+	r.Close()
+
+	want := []struct {
+		action string
+		port   int
+	}{{ActionRegister, 8080}, {ActionUnregister, 8080}, {ActionRegister, 9090}, {ActionUnregister, 9090}}
+	if len(rec.entries) != len(want) {
+		t.Fatalf("received %d entries (%+v), want %d", len(rec.entries), rec.entries, len(want))
+	}
+	for i, w := range want {
+		e := rec.entries[i]
+		if e.Action != w.action || e.RemotePort != w.port {
+			t.Errorf("entry %d = %s %d, want %s %d", i, e.Action, e.RemotePort, w.action, w.port)
+		}
+		if rec.auth[i] != "Bearer t0ken" {
+			t.Errorf("entry %d Authorization = %q, want Bearer t0ken", i, rec.auth[i])
+		}
+	}
+	e := rec.entries[0]
+	if e.Rule != "callback" || e.SessionID != "s1" || e.Host != "bastion" || e.HostName != "bastion.example.com" ||
+		e.LocalTarget != "127.0.0.1:3000" || e.Timestamp.IsZero() {
+		t.Errorf("entry = %+v, want callback on bastion.example.com to 127.0.0.1:3000", e)
+	}
+}
+
+func TestRegistry_RetriesUntilSuccess(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	r := newTestRegistry(serviceconf.Registry{URL: srv.URL})
+	r.HandleForwardEvent(remoteEvent(core.ForwardEventStarted, 8080))
+	r.Close()
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+}
+
+func TestRegistry_DisabledWithoutURL(t *testing.T) {
+	r := newTestRegistry(serviceconf.Registry{})
+	r.HandleForwardEvent(remoteEvent(core.ForwardEventStarted, 8080))
+	if len(r.registered) != 0 || len(r.queue) != 0 {
+		t.Errorf("registered = %v, queued = %d, want nothing without url", r.registered, len(r.queue))
+	}
+	r.Close()
+
+	var nilRegistry *Registry
+	nilRegistry.HandleForwardEvent(remoteEvent(core.ForwardEventStarted, 8080))
+	nilRegistry.Close()
+}
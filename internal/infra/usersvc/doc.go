@@ -0,0 +1,3 @@
+// Package usersvc はデーモンを systemd のユーザーユニット（Linux）または launchd の LaunchAgent（macOS）として登録・削除し、
+// その状態を取得する。
+package usersvc
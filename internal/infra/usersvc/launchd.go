@@ -0,0 +1,81 @@
+package usersvc
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+)
+
+// launchd は launchd の LaunchAgent としてデーモンを登録する Manager。
+type launchd struct {
+	path   string // plist のパス
+	domain string // ログインユーザーのドメイン（gui/<uid>）
+}
+
+// Install は plist を書き込み、読み込み済みのエージェントを取り除いてから読み込み直す。
+// plist の RunAtLoad により読み込みと同時に起動する。新たに書き込んだ plist は、launchctl が失敗した場合に削除する。
+func (l *launchd) Install(spec Spec) error {
+	existed := exists(l.path)
+	if err := writeFile(l.path, "launchd.plist.tmpl", spec); err != nil {
+		return err
+	}
+	_, _ = run("launchctl", "bootout", l.target())
+	_, err := run("launchctl", "enable", l.target())
+	if err == nil {
+		_, err = run("launchctl", "bootstrap", l.domain, l.path)
+	}
+	if err != nil && !existed {
+		_ = os.Remove(l.path)
+	}
+	return err
+}
+
+// Uninstall はエージェントを停止して取り除き、plist を削除する。
+func (l *launchd) Uninstall() error {
+	if !exists(l.path) {
+		return ErrNotInstalled
+	}
+	if _, err := run("launchctl", "print", l.target()); err == nil {
+		if _, err := run("launchctl", "bootout", l.target()); err != nil {
+			return err
+		}
+	}
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Status はエージェントの状態を返す。launchctl print が失敗する場合は読み込まれていない。
+func (l *launchd) Status() (Status, error) {
+	st := Status{Path: l.path, Installed: exists(l.path)}
+	if !st.Installed {
+		return st, nil
+	}
+	out, err := run("launchctl", "print", l.target())
+	if err != nil {
+		st.State = "not loaded"
+		return st, nil
+	}
+	st.Enabled = true
+	st.State = printedState(out)
+	st.Running = st.State == "running"
+	return st, nil
+}
+
+// target はエージェントのサービスターゲット（gui/<uid>/<label>）を返す。
+func (l *launchd) target() string {
+	return l.domain + "/" + Label
+}
+
+// printedState は launchctl print の出力から最上位の state を返す。
+func printedState(out string) string {
+	sc := bufio.NewScanner(strings.NewReader(out))
+	for sc.Scan() {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "state = "); ok {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,73 @@
+package usersvc
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// systemd は systemd のユーザーユニットとしてデーモンを登録する Manager。
+type systemd struct {
+	path string // ユニットファイルのパス
+}
+
+// Install はユニットファイルを書き込み、有効化して（再）起動する。
+// 新たに書き込んだユニットファイルは、systemctl が失敗した場合に削除する。
+func (s *systemd) Install(spec Spec) error {
+	existed := exists(s.path)
+	if err := writeFile(s.path, "moleport.service.tmpl", spec); err != nil {
+		return err
+	}
+	for _, args := range [][]string{{"daemon-reload"}, {"enable", UnitName}, {"restart", UnitName}} {
+		if _, err := systemctl(args...); err != nil {
+			if !existed {
+				_ = os.Remove(s.path)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Uninstall はユニットを停止・無効化し、ユニットファイルを削除する。
+func (s *systemd) Uninstall() error {
+	if !exists(s.path) {
+		return ErrNotInstalled
+	}
+	if _, err := systemctl("disable", "--now", UnitName); err != nil {
+		return err
+	}
+	if err := os.Remove(s.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	_, err := systemctl("daemon-reload")
+	return err
+}
+
+// Status はユニットの状態を systemctl show の UnitFileState・ActiveState から返す。
+func (s *systemd) Status() (Status, error) {
+	st := Status{Path: s.path, Installed: exists(s.path)}
+	if !st.Installed {
+		return st, nil
+	}
+	out, err := systemctl("show", "--property=UnitFileState,ActiveState", UnitName)
+	if err != nil {
+		return st, err
+	}
+	for line := range strings.Lines(out) {
+		key, value, _ := strings.Cut(strings.TrimSpace(line), "=")
+		switch key {
+		case "UnitFileState":
+			st.Enabled = value == "enabled"
+		case "ActiveState":
+			st.State = value
+		}
+	}
+	st.Running = st.State == "active"
+	return st, nil
+}
+
+// systemctl はユーザーインスタンスの systemctl を実行する。
+func systemctl(args ...string) (string, error) {
+	return run("systemctl", append([]string{"--user"}, args...)...)
+}
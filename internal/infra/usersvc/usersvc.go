@@ -0,0 +1,138 @@
+package usersvc
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// UnitName は systemd のユーザーユニットの名前。
+	UnitName = "moleport.service"
+	// Label は launchd の LaunchAgent のラベル。
+	Label = "com.ousiassllc.moleport"
+
+	// commandTimeout は systemctl・launchctl の 1 回あたりの待ち時間。
+	commandTimeout = 30 * time.Second
+)
+
+var (
+	// ErrUnsupported は実行中の OS でユーザーサービスを扱えないことを示す。
+	ErrUnsupported = errors.New("user service is not supported on this platform")
+	// ErrNotInstalled はユーザーサービスが登録されていないことを示す。
+	ErrNotInstalled = errors.New("user service is not installed")
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"systemdQuote": systemdQuote,
+	"xml":          template.HTMLEscapeString,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Spec はサービスとして起動するデーモンのコマンド。
+type Spec struct {
+	Executable string // moleport の実行ファイルの絶対パス
+	ConfigDir  string // 設定ディレクトリの絶対パス
+}
+
+// Status はユーザーサービスの状態。
+type Status struct {
+	Path      string // ユニットファイル・plist のパス
+	Installed bool   // ユニットファイル・plist が存在するか
+	Enabled   bool   // ログイン時に起動するよう有効化されているか
+	Running   bool   // サービスのプロセスが稼働中か
+	State     string // サービスマネージャーが報告する状態（systemd の ActiveState、launchd の state）
+}
+
+// Manager は OS のサービスマネージャーにデーモンをユーザーサービスとして登録する。
+type Manager interface {
+	// Install はユニットファイル・plist を書き込み、有効化して起動する。登録済みの場合は書き換えて再起動する。
+	Install(spec Spec) error
+	// Uninstall はサービスを停止・無効化し、ユニットファイル・plist を削除する。未登録の場合は ErrNotInstalled を返す。
+	Uninstall() error
+	// Status はサービスの状態を返す。
+	Status() (Status, error)
+}
+
+// New は実行中の OS のサービスマネージャーを返す。Linux では systemd、macOS では launchd を使う。
+func New() (Manager, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil, err
+	}
+	switch runtime.GOOS {
+	case "linux":
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			configHome = filepath.Join(home, ".config")
+		}
+		return &systemd{path: filepath.Join(configHome, "systemd", "user", UnitName)}, nil
+	case "darwin":
+		return &launchd{
+			path:   filepath.Join(home, "Library", "LaunchAgents", Label+".plist"),
+			domain: fmt.Sprintf("gui/%d", os.Getuid()),
+		}, nil
+	default:
+		return nil, ErrUnsupported
+	}
+}
+
+// run はコマンドを実行し、標準出力と標準エラーを合わせて返す。テストで差し替える。
+var run = func(name string, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), commandTimeout)
+	defer cancel()
+	out, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+// render は spec でテンプレート name を展開する。
+func render(name string, spec Spec) ([]byte, error) {
+	var buf bytes.Buffer
+	data := struct {
+		Spec
+		Label string
+	}{spec, Label}
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFile はテンプレート name を展開して path に書き込む。
+func writeFile(path, name string, spec Spec) error {
+	data, err := render(name, spec)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// exists は path のファイルが存在するかを返す。
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// systemdQuote は systemd のコマンドラインの 1 引数として s を二重引用符で囲む。
+// バックスラッシュと二重引用符をエスケープし、指定子として解釈される % を %% にする。
+func systemdQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%")
+	return `"` + r.Replace(s) + `"`
+}
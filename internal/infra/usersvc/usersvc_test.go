@@ -0,0 +1,125 @@
+package usersvc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// stubRun は run を差し替え、実行したコマンドを記録する。respond は各コマンドの出力とエラーを返す。
+func stubRun(t *testing.T, respond func(args []string) (string, error)) *[][]string {
+	t.Helper()
+	orig := run
+	t.Cleanup(func() { run = orig })
+	var calls [][]string
+	run = func(name string, args ...string) (string, error) {
+		cmd := append([]string{name}, args...)
+		calls = append(calls, cmd)
+		if respond == nil {
+			return "", nil
+		}
+		return respond(cmd)
+	}
+	return &calls
+}
+
+func TestSystemd_Install(t *testing.T) {
+	calls := stubRun(t, nil)
+	s := &systemd{path: filepath.Join(t.TempDir(), "systemd", "user", UnitName)}
+
+	if err := s.Install(Spec{Executable: "/opt/mole port/moleport", ConfigDir: "/home/u/.config/moleport%1"}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		t.Fatalf("read unit: %v", err)
+	}
+	want := `ExecStart="/opt/mole port/moleport" --daemon-mode --config-dir "/home/u/.config/moleport%%1"`
+	if !strings.Contains(string(data), want) {
+		t.Errorf("unit = %s, want line %s", data, want)
+	}
+	wantCalls := [][]string{
+		{"systemctl", "--user", "daemon-reload"},
+		{"systemctl", "--user", "enable", UnitName},
+		{"systemctl", "--user", "restart", UnitName},
+	}
+	if !reflect.DeepEqual(*calls, wantCalls) {
+		t.Errorf("commands = %v, want %v", *calls, wantCalls)
+	}
+}
+
+func TestSystemd_StatusAndUninstall(t *testing.T) {
+	stubRun(t, func(args []string) (string, error) {
+		if args[2] == "show" {
+			return "UnitFileState=enabled\nActiveState=failed\n", nil
+		}
+		return "", nil
+	})
+	s := &systemd{path: filepath.Join(t.TempDir(), UnitName)}
+
+	if st, _ := s.Status(); st.Installed {
+		t.Errorf("Status() before install = %+v, want not installed", st)
+	}
+	if err := s.Uninstall(); !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("Uninstall() before install error = %v, want ErrNotInstalled", err)
+	}
+	if err := s.Install(Spec{Executable: "/bin/moleport", ConfigDir: "/cfg"}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	st, err := s.Status()
+	if err != nil || !st.Installed || !st.Enabled || st.Running || st.State != "failed" {
+		t.Errorf("Status() = (%+v, %v), want installed, enabled, state failed", st, err)
+	}
+	if err := s.Uninstall(); err != nil {
+		t.Fatalf("Uninstall() error = %v", err)
+	}
+	if exists(s.path) {
+		t.Error("unit file remains after Uninstall()")
+	}
+}
+
+func TestSystemd_InstallFailureRemovesUnit(t *testing.T) {
+	stubRun(t, func(args []string) (string, error) {
+		return "", errors.New("Failed to connect to bus")
+	})
+	s := &systemd{path: filepath.Join(t.TempDir(), UnitName)}
+
+	if err := s.Install(Spec{Executable: "/bin/moleport", ConfigDir: "/cfg"}); err == nil {
+		t.Fatal("Install() error = nil, want systemctl error")
+	}
+	if exists(s.path) {
+		t.Error("unit file remains after failed Install()")
+	}
+}
+
+func TestLaunchd_InstallAndStatus(t *testing.T) {
+	calls := stubRun(t, func(args []string) (string, error) {
+		if args[1] == "print" {
+			return "gui/501/com.ousiassllc.moleport = {\n\tstate = running\n\tpid = 4242\n\tendpoints = {\n\t\tstate = active\n\t}\n}\n", nil
+		}
+		return "", nil
+	})
+	l := &launchd{path: filepath.Join(t.TempDir(), "LaunchAgents", Label+".plist"), domain: "gui/501"}
+
+	if err := l.Install(Spec{Executable: "/Applications/A&B/moleport", ConfigDir: "/cfg"}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		t.Fatalf("read plist: %v", err)
+	}
+	if !strings.Contains(string(data), "<string>/Applications/A&amp;B/moleport</string>") {
+		t.Errorf("plist = %s, want escaped executable", data)
+	}
+	if last := (*calls)[len(*calls)-1]; !reflect.DeepEqual(last, []string{"launchctl", "bootstrap", "gui/501", l.path}) {
+		t.Errorf("last command = %v, want bootstrap", last)
+	}
+
+	st, err := l.Status()
+	if err != nil || !st.Installed || !st.Enabled || !st.Running || st.State != "running" {
+		t.Errorf("Status() = (%+v, %v), want running", st, err)
+	}
+}
@@ -1,6 +1,10 @@
 package infra
 
-import "os"
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
 
 // homeDir はカレントユーザーのホームディレクトリを返す。
 // os.UserHomeDir が失敗した場合は HOME 環境変数にフォールバックする。
@@ -11,3 +15,26 @@ func homeDir() string {
 	}
 	return home
 }
+
+// ExpandTilde は ~ をホームディレクトリに展開する。
+// "~/"（Windows では "~\" も）または "~" のみ展開し、"~otheruser" パターンはそのまま返す。
+func ExpandTilde(path string) (string, error) {
+	if len(path) == 0 {
+		return path, nil
+	}
+	if path == "~" {
+		home := homeDir()
+		if home == "" {
+			return "", fmt.Errorf("failed to get home directory")
+		}
+		return home, nil
+	}
+	if len(path) >= 2 && path[0] == '~' && (path[1] == '/' || path[1] == filepath.Separator) {
+		home := homeDir()
+		if home == "" {
+			return "", fmt.Errorf("failed to get home directory")
+		}
+		return filepath.Join(home, path[2:]), nil
+	}
+	return path, nil
+}
@@ -0,0 +1,40 @@
+package infra
+
+import (
+	"os/user"
+	"path/filepath"
+	"testing"
+)
+
+func TestExpandTilde_Exported(t *testing.T) {
+	u, err := user.Current()
+	if err != nil {
+		t.Fatalf("failed to get current user: %v", err)
+	}
+
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"~/.ssh/config", filepath.Join(u.HomeDir, ".ssh/config"), false},
+		{"~/", u.HomeDir, false},
+		{"~", u.HomeDir, false},
+		{"~otheruser/.ssh/config", "~otheruser/.ssh/config", false},
+		{"~otheruser", "~otheruser", false},
+		{"/absolute/path", "/absolute/path", false},
+		{"relative/path", "relative/path", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := ExpandTilde(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ExpandTilde(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ExpandTilde(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+)
+
+const (
+	// DeadLetterFileName は再試行しても送信できなかった通知を記録するファイル名。
+	DeadLetterFileName = "webhook_deadletter.log"
+
+	defaultMaxRetries = 3
+	queueSize         = 64
+	requestTimeout    = 10 * time.Second
+	// drainTimeout は Close 時にキュー内の通知を送信し終えるまで待つ上限。
+	drainTimeout = 5 * time.Second
+)
+
+// Dispatcher はイベントをキューに積み、単一のゴルーチンで順に Webhook へ送信する。
+// 送信に失敗した場合は指数バックオフで再試行し、最終的に失敗した通知はデッドレターログに記録する。
+type Dispatcher struct {
+	hooks          func() []serviceconf.Webhook
+	deadLetterPath string
+	httpClient     *http.Client
+	retryDelay     time.Duration
+
+	queue  chan Event
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New は Dispatcher を生成し、送信ゴルーチンを開始する。
+// hooks は送信のたびに呼ばれ、設定の変更を反映する。
+func New(hooks func() []serviceconf.Webhook, deadLetterPath string) *Dispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &Dispatcher{
+		hooks:          hooks,
+		deadLetterPath: deadLetterPath,
+		httpClient:     &http.Client{Timeout: requestTimeout},
+		retryDelay:     time.Second,
+		queue:          make(chan Event, queueSize),
+		ctx:            ctx,
+		cancel:         cancel,
+		done:           make(chan struct{}),
+	}
+	go d.run()
+	return d
+}
+
+// Publish はイベントを送信キューに積む。通知先がない場合やキューが満杯の場合は破棄する。
+// nil の Dispatcher では何もしない。Close 後に呼んではならない。
+func (d *Dispatcher) Publish(evt Event) {
+	if d == nil || !d.wanted(evt.Event) {
+		return
+	}
+	select {
+	case d.queue <- evt:
+	default:
+		slog.Warn("webhook queue full, event dropped", "event", evt.Event)
+	}
+}
+
+// Close はキューを閉じ、残りの通知を drainTimeout まで送信してから送信ゴルーチンを停止する。
+func (d *Dispatcher) Close() {
+	if d == nil {
+		return
+	}
+	close(d.queue)
+	select {
+	case <-d.done:
+	case <-time.After(drainTimeout):
+		d.cancel()
+		<-d.done
+	}
+	d.cancel()
+}
+
+// wanted は event を通知対象とする Webhook が一つでもあるかを返す。
+func (d *Dispatcher) wanted(event string) bool {
+	for _, h := range d.hooks() {
+		if matches(h, event) {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *Dispatcher) run() {
+	defer close(d.done)
+	for evt := range d.queue {
+		for _, h := range d.hooks() {
+			if !matches(h, evt.Event) {
+				continue
+			}
+			body, err := json.Marshal(evt.withText(h.Template))
+			if err != nil {
+				slog.Warn("failed to marshal webhook event", "error", err)
+				continue
+			}
+			d.deliver(h, evt.Event, body)
+		}
+	}
+}
+
+// deliver は hook に body を送信する。2xx 以外の応答や通信エラーの場合は再試行する。
+func (d *Dispatcher) deliver(hook serviceconf.Webhook, event string, body []byte) {
+	retries := hook.MaxRetries
+	if retries <= 0 {
+		retries = defaultMaxRetries
+	}
+	delay := d.retryDelay
+	var err error
+	attempt := 0
+	for ; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(delay):
+			case <-d.ctx.Done():
+				err = fmt.Errorf("%w (last error: %v)", d.ctx.Err(), err)
+				d.deadLetter(hook, event, body, attempt, err)
+				return
+			}
+			delay *= 2
+		}
+		if err = d.post(hook, event, body); err == nil {
+			return
+		}
+		slog.Debug("webhook delivery failed", "url", hook.URL, "event", event, "attempt", attempt+1, "error", err)
+	}
+	slog.Warn("webhook delivery gave up", "url", hook.URL, "event", event, "attempts", attempt, "error", err)
+	d.deadLetter(hook, event, body, attempt, err)
+}
+
+func (d *Dispatcher) post(hook serviceconf.Webhook, event string, body []byte) error {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "MolePort")
+	req.Header.Set(HeaderEvent, event)
+	if hook.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(hook.Secret, body))
+	}
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// deadLetterEntry はデッドレターログの 1 行。
+type deadLetterEntry struct {
+	Time     time.Time       `json:"time"`
+	URL      string          `json:"url"`
+	Event    string          `json:"event"`
+	Attempts int             `json:"attempts"`
+	Error    string          `json:"error"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// deadLetter は送信できなかった通知を JSON Lines 形式でデッドレターログに追記する。
+// 送信ゴルーチンからのみ呼ばれるため排他は不要。
+func (d *Dispatcher) deadLetter(hook serviceconf.Webhook, event string, body []byte, attempts int, cause error) {
+	line, err := json.Marshal(deadLetterEntry{
+		Time:     time.Now().UTC(),
+		URL:      hook.URL,
+		Event:    event,
+		Attempts: attempts,
+		Error:    errString(cause),
+		Payload:  body,
+	})
+	if err != nil {
+		return
+	}
+
+	f, err := os.OpenFile(d.deadLetterPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		slog.Warn("failed to open webhook dead-letter log", "error", err)
+		return
+	}
+	defer func() { _ = f.Close() }()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		slog.Warn("failed to write webhook dead-letter log", "error", err)
+	}
+}
@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+)
+
+// newTestDispatcher は再試行間隔を短くした Dispatcher を返す。
+func newTestDispatcher(t *testing.T, hooks ...serviceconf.Webhook) (*Dispatcher, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), DeadLetterFileName)
+	d := New(func() []serviceconf.Webhook { return hooks }, path)
+	d.retryDelay = time.Millisecond
+	return d, path
+}
+
+func TestDispatcher_SignsAndFilters(t *testing.T) {
+	received := make(chan *http.Request, 4)
+	bodies := make(chan []byte, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer srv.Close()
+
+	d, _ := newTestDispatcher(t, serviceconf.Webhook{
+		URL:    srv.URL,
+		Events: []string{EventForwardStarted},
+		Secret: "s3cret",
+	})
+	d.Publish(Event{Event: EventSSHConnected, Host: "ignored"})
+	d.Publish(Event{Event: EventForwardStarted, Rule: "db", Host: "bastion"})
+	d.Close()
+
+	if len(received) != 1 {
+		t.Fatalf("received %d requests, want 1 (filtered by events)", len(received))
+	}
+	r, body := <-received, <-bodies
+	if got := r.Header.Get(HeaderEvent); got != EventForwardStarted {
+		t.Errorf("%s = %q, want %q", HeaderEvent, got, EventForwardStarted)
+	}
+	if got, want := r.Header.Get(HeaderSignature), Sign("s3cret", body); got != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+	var evt Event
+	if err := json.Unmarshal(body, &evt); err != nil {
+		t.Fatalf("unmarshal body: %v", err)
+	}
+	if evt.Rule != "db" || evt.Host != "bastion" || evt.Text != "MolePort forward.started host=bastion rule=db" {
+		t.Errorf("payload = %+v, want rule db on bastion", evt)
+	}
+}
+
+func TestDispatcher_RetriesUntilSuccess(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	d, path := newTestDispatcher(t, serviceconf.Webhook{URL: srv.URL})
+	d.Publish(Event{Event: EventForwardStopped})
+	d.Close()
+
+	if got := calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("dead-letter log should not exist after success: %v", err)
+	}
+}
+
+func TestDispatcher_DeadLetterAfterRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	d, path := newTestDispatcher(t, serviceconf.Webhook{URL: srv.URL, MaxRetries: 1})
+	d.Publish(Event{Event: EventForwardError, Rule: "db", Error: "boom"})
+	d.Close()
+
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2 (1 + MaxRetries)", got)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read dead-letter log: %v", err)
+	}
+	var entry deadLetterEntry
+	if err := json.Unmarshal([]byte(strings.TrimSpace(string(data))), &entry); err != nil {
+		t.Fatalf("unmarshal dead-letter entry: %v", err)
+	}
+	if entry.URL != srv.URL || entry.Event != EventForwardError || entry.Attempts != 2 || !strings.Contains(entry.Error, "500") {
+		t.Errorf("dead-letter entry = %+v", entry)
+	}
+}
+
+func TestFromEvents(t *testing.T) {
+	if _, ok := FromSSHEvent(core.SSHEvent{Type: core.SSHEventReconnecting}); ok {
+		t.Error("reconnecting should not be notified")
+	}
+	evt, ok := FromSSHEvent(core.SSHEvent{Type: core.SSHEventDisconnected, HostName: "bastion"})
+	if !ok || evt.Event != EventSSHDisconnected || evt.Host != "bastion" {
+		t.Errorf("FromSSHEvent = %+v, %v", evt, ok)
+	}
+
+	session := &core.ForwardSession{Rule: core.ForwardRule{Name: "db", Host: "bastion"}}
+	evt, ok = FromForwardEvent(core.ForwardEvent{Type: core.ForwardEventError, RuleName: "db", Session: session, Error: errors.New("boom")})
+	if !ok || evt.Event != EventForwardError || evt.Host != "bastion" || evt.Error != "boom" {
+		t.Errorf("FromForwardEvent = %+v, %v", evt, ok)
+	}
+	if evt, ok := FromForwardEvent(core.ForwardEvent{Type: core.ForwardEventExpiring, RuleName: "db", Session: session}); !ok || evt.Event != EventForwardExpiring {
+		t.Errorf("FromForwardEvent(expiring) = %+v, %v", evt, ok)
+	}
+	if _, ok := FromForwardEvent(core.ForwardEvent{Type: core.ForwardEventMetricsUpdated}); ok {
+		t.Error("metrics updates should not be notified")
+	}
+}
+
+func TestEvent_WithText(t *testing.T) {
+	evt := Event{Event: EventSSHReconnectFailed, Host: "bastion", Error: "reconnect failed after 3 attempts"}
+	tests := []struct {
+		name string
+		tmpl string
+		want string
+	}{
+		{"default", "", "MolePort ssh.reconnect_failed host=bastion: reconnect failed after 3 attempts"},
+		{"custom", ":rotating_light: {{.Host}} is down ({{.Event}})", ":rotating_light: bastion is down (ssh.reconnect_failed)"},
+		{"invalid falls back to default", "{{.Host", "MolePort ssh.reconnect_failed host=bastion: reconnect failed after 3 attempts"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := evt.withText(tt.tmpl).Text; got != tt.want {
+				t.Errorf("Text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	failed := ReconnectFailed(core.SSHEvent{Type: core.SSHEventError, HostName: "bastion", Error: errors.New("boom")})
+	if failed.Event != EventSSHReconnectFailed || failed.Host != "bastion" || failed.Error != "boom" {
+		t.Errorf("ReconnectFailed = %+v", failed)
+	}
+}
@@ -0,0 +1,2 @@
+// Package webhook はフォワード・SSH 接続のライフサイクルイベントを設定された Webhook に HTTP POST で通知する。
+package webhook
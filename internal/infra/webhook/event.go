@@ -0,0 +1,122 @@
+package webhook
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/serviceconf"
+)
+
+// Webhook で通知するイベント種別。
+const (
+	EventForwardStarted  = "forward.started"
+	EventForwardStopped  = "forward.stopped"
+	EventForwardError    = "forward.error"
+	EventForwardExpiring = "forward.expiring"
+	EventSSHConnected    = "ssh.connected"
+	EventSSHDisconnected = "ssh.disconnected"
+	// EventSSHReconnectFailed は自動再接続が再試行の上限に達して失敗したことを示す。
+	EventSSHReconnectFailed = "ssh.reconnect_failed"
+)
+
+// DefaultTemplate は Template を指定していない Webhook のペイロードの text に使うテンプレート。
+const DefaultTemplate = `MolePort {{.Event}}{{if .Host}} host={{.Host}}{{end}}{{if .Rule}} rule={{.Rule}}{{end}}{{if .Error}}: {{.Error}}{{end}}`
+
+// HTTP ヘッダー名。
+const (
+	HeaderEvent     = "X-MolePort-Event"
+	HeaderSignature = "X-MolePort-Signature"
+)
+
+// Event は Webhook に送信するペイロード。Text は Webhook ごとのテンプレートで生成する Slack 互換のメッセージ。
+type Event struct {
+	Text      string    `json:"text"`
+	Event     string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+	Host      string    `json:"host,omitempty"`
+	Rule      string    `json:"rule,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// FromSSHEvent は SSH イベントを Webhook イベントに変換する。通知対象外の種別の場合は false を返す。
+func FromSSHEvent(evt core.SSHEvent) (Event, bool) {
+	var name string
+	switch evt.Type {
+	case core.SSHEventConnected:
+		name = EventSSHConnected
+	case core.SSHEventDisconnected:
+		name = EventSSHDisconnected
+	default:
+		return Event{}, false
+	}
+	return Event{Event: name, Timestamp: time.Now().UTC(), Host: evt.HostName, Error: errString(evt.Error)}, true
+}
+
+// ReconnectFailed は SSH の自動再接続の失敗を示す Webhook イベントを返す。evt は再接続中に発行された SSHEventError。
+func ReconnectFailed(evt core.SSHEvent) Event {
+	return Event{Event: EventSSHReconnectFailed, Timestamp: time.Now().UTC(), Host: evt.HostName, Error: errString(evt.Error)}
+}
+
+// FromForwardEvent はフォワードイベントを Webhook イベントに変換する。通知対象外の種別の場合は false を返す。
+func FromForwardEvent(evt core.ForwardEvent) (Event, bool) {
+	var name string
+	switch evt.Type {
+	case core.ForwardEventStarted:
+		name = EventForwardStarted
+	case core.ForwardEventStopped:
+		name = EventForwardStopped
+	case core.ForwardEventError:
+		name = EventForwardError
+	case core.ForwardEventExpiring:
+		name = EventForwardExpiring
+	default:
+		return Event{}, false
+	}
+	e := Event{Event: name, Timestamp: time.Now().UTC(), Rule: evt.RuleName, Error: errString(evt.Error)}
+	if evt.Session != nil {
+		e.Host = evt.Session.Rule.Host
+	}
+	return e, true
+}
+
+// matches は hook が event 種別を通知対象としているかを返す。
+func matches(hook serviceconf.Webhook, event string) bool {
+	return len(hook.Events) == 0 || slices.Contains(hook.Events, event)
+}
+
+// withText は tmpl（空の場合は DefaultTemplate）で Text を設定した evt を返す。
+// テンプレートが不正な場合は警告を記録して DefaultTemplate を使う。
+func (evt Event) withText(tmpl string) Event {
+	t, err := template.New("webhook").Parse(cmp.Or(tmpl, DefaultTemplate))
+	if err == nil {
+		var b strings.Builder
+		if err = t.Execute(&b, evt); err == nil {
+			evt.Text = b.String()
+			return evt
+		}
+	}
+	slog.Warn("invalid webhook template, using default", "error", err)
+	return evt.withText(DefaultTemplate)
+}
+
+// Sign は body の HMAC-SHA256 署名を "sha256=<hex>" 形式で返す。
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,29 @@
+package authtoken
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateRead(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "moleport.token")
+	token, err := Generate(path)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if len(token) != 2*tokenBytes {
+		t.Errorf("len(token) = %d, want %d", len(token), 2*tokenBytes)
+	}
+	if got, err := Read(path); err != nil || got != token {
+		t.Errorf("Read() = %q, %v, want %q", got, err, token)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("token file stat = %v, %v, want mode 0600", info, err)
+	}
+
+	next, _ := Generate(path)
+	if next == token {
+		t.Error("Generate() should issue a new token each time")
+	}
+}
@@ -0,0 +1,3 @@
+// Package authtoken は IPC の認証トークンのファイル管理を提供する。
+// デーモンは起動のたびにトークンを発行してファイルに書き出し、ローカルのクライアントはそれを読んで auth.login で提示する。
+package authtoken
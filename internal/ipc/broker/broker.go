@@ -0,0 +1,278 @@
+package broker
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Subscription はクライアントのイベント購読を表す。
+type Subscription struct {
+	ID       string
+	ClientID string
+	Types    map[string]bool // "ssh", "forward", "daemon", "metrics", "summary", "host", "config"
+	Hosts    map[string]bool // nil の場合はホスト名で絞り込まない
+	Rules    map[string]bool // nil の場合はルール名で絞り込まない
+}
+
+// NotifySender はクライアントに通知を送信する関数の型。
+type NotifySender func(clientID string, notification protocol.Notification) error
+
+// EventBroker はクライアント単位のイベント購読を管理し、コアマネージャーからの通知を配信する。
+type EventBroker struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*Subscription // subscriptionID -> Subscription
+	clientSubs    map[string][]string      // clientID -> []subscriptionID
+	sender        NotifySender
+	nextID        atomic.Int64
+	errorCount    atomic.Int64 // event.summary の集計区間内に発生したエラー数
+	history       history      // since_id で再送する SSH・フォワードイベント（mu で保護する）
+}
+
+// NewEventBroker は新しい EventBroker を生成する。
+func NewEventBroker(sender NotifySender) *EventBroker {
+	return &EventBroker{
+		subscriptions: make(map[string]*Subscription),
+		clientSubs:    make(map[string][]string),
+		sender:        sender,
+	}
+}
+
+// Subscribe はクライアントのイベント購読を登録し、購読 ID を返す。
+func (b *EventBroker) Subscribe(clientID string, types []string) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subscribe(clientID, types, Filter{})
+}
+
+// SubscribeSince は filter で絞り込む購読を登録し、sinceID より後に発生した SSH・フォワードイベントのうち購読の対象となるものを返す。
+// 再送するイベントの取得と購読の登録は同時に行うため、その間のイベントが欠けたり重複したりしない。
+func (b *EventBroker) SubscribeSince(clientID string, types []string, filter Filter, sinceID int64) (string, Replay) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subID := b.subscribe(clientID, types, filter)
+	return subID, b.history.since(sinceID, b.subscriptions[subID])
+}
+
+// subscribe は購読を登録し、購読 ID を返す。b.mu を保持して呼び出す。
+func (b *EventBroker) subscribe(clientID string, types []string, filter Filter) string {
+	id := b.nextID.Add(1)
+	subID := fmt.Sprintf("sub-%s-%d", clientID, id)
+
+	typeMap := make(map[string]bool, len(types))
+	for _, t := range types {
+		typeMap[t] = true
+	}
+
+	sub := &Subscription{
+		ID:       subID,
+		ClientID: clientID,
+		Types:    typeMap,
+		Hosts:    set(filter.Hosts),
+		Rules:    set(filter.Rules),
+	}
+
+	b.subscriptions[subID] = sub
+	b.clientSubs[clientID] = append(b.clientSubs[clientID], subID)
+
+	return subID
+}
+
+// Unsubscribe は購読を解除する。成功すると true を返す。
+func (b *EventBroker) Unsubscribe(subscriptionID string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sub, ok := b.subscriptions[subscriptionID]
+	if !ok {
+		return false
+	}
+
+	delete(b.subscriptions, subscriptionID)
+
+	// clientSubs から削除
+	subs := b.clientSubs[sub.ClientID]
+	for i, id := range subs {
+		if id == subscriptionID {
+			b.clientSubs[sub.ClientID] = append(subs[:i], subs[i+1:]...)
+			break
+		}
+	}
+	if len(b.clientSubs[sub.ClientID]) == 0 {
+		delete(b.clientSubs, sub.ClientID)
+	}
+
+	return true
+}
+
+// RemoveClient はクライアントの全購読を削除する。切断時に呼ばれる。
+func (b *EventBroker) RemoveClient(clientID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subIDs := b.clientSubs[clientID]
+	for _, id := range subIDs {
+		delete(b.subscriptions, id)
+	}
+	delete(b.clientSubs, clientID)
+}
+
+// HandleSSHEvent は SSH イベントを変換し、購読者に配信する。
+func (b *EventBroker) HandleSSHEvent(evt core.SSHEvent) {
+	notif := protocol.SSHEventNotification{
+		Type: sshEventTypeToString(evt.Type),
+		Host: evt.HostName,
+	}
+	if evt.Error != nil {
+		notif.Error = evt.Error.Error()
+	}
+	if evt.Type == core.SSHEventError {
+		b.errorCount.Add(1)
+	}
+
+	b.record("ssh", protocol.EventSSH, eventAttrs{hosts: []string{notif.Host}}, func(id int64) any {
+		notif.EventID = id
+		return notif
+	})
+}
+
+// HandleForwardEvent はポートフォワーディングイベントを変換し、購読者に配信する。
+func (b *EventBroker) HandleForwardEvent(evt core.ForwardEvent) {
+	notif := protocol.ForwardEventNotification{
+		Type:     forwardEventTypeToString(evt.Type),
+		Name:     evt.RuleName,
+		FromHost: evt.FromHost,
+	}
+	if evt.Session != nil {
+		notif.Host = evt.Session.Rule.Host
+		notif.Port = evt.Session.BoundPort
+		if evt.Type == core.ForwardEventDraining {
+			notif.Conns = evt.Session.ActiveConns
+		}
+	}
+	if evt.Error != nil {
+		notif.Error = evt.Error.Error()
+	}
+	if evt.Type == core.ForwardEventError {
+		b.errorCount.Add(1)
+	}
+
+	attrs := eventAttrs{hosts: []string{notif.Host, notif.FromHost}, rule: notif.Name}
+	b.record("forward", protocol.EventForward, attrs, func(id int64) any {
+		notif.EventID = id
+		return notif
+	})
+}
+
+// NotifyHostHealth はホストの疎通確認結果の変化を購読者に配信する。
+func (b *EventBroker) NotifyHostHealth(host string, health core.HostHealth) {
+	b.distributeAbout("host", protocol.EventHost, eventAttrs{hosts: []string{host}}, protocol.HostEventNotification{
+		Type: protocol.HostEventTypeHealth, Host: host, Health: string(health),
+	})
+}
+
+// NotifyHostsChanged は ssh_config の変更などでホスト一覧を再読み込みしたことを購読者に配信する。
+func (b *EventBroker) NotifyHostsChanged(added, removed []string) {
+	b.distribute("host", protocol.EventHost, protocol.HostEventNotification{
+		Type: protocol.HostEventTypeChanged, Added: added, Removed: removed,
+	})
+}
+
+// NotifyConfigChanged は config.yaml の変更を反映したことを購読者に配信する。
+func (b *EventBroker) NotifyConfigChanged(sections []string) {
+	b.distribute("config", protocol.EventConfig, protocol.ConfigEventNotification{
+		Type: protocol.ConfigEventTypeChanged, Sections: sections,
+	})
+}
+
+// NotifyShutdown はデーモンの停止開始を購読者に配信する。
+func (b *EventBroker) NotifyShutdown() {
+	b.distribute("daemon", protocol.EventDaemon, protocol.DaemonEventNotification{
+		Type: protocol.DaemonEventTypeShuttingDown,
+	})
+}
+
+// NotifyRecovered は異常終了からの復元結果を購読者に配信する。
+func (b *EventBroker) NotifyRecovered(summary protocol.RecoverySummary) {
+	b.distribute("daemon", protocol.EventDaemon, protocol.DaemonEventNotification{
+		Type:     protocol.DaemonEventTypeRecovered,
+		Recovery: &summary,
+	})
+}
+
+// NotifyAutoStart は auto_connect のルールの自動開始の進捗を購読者に配信する。
+func (b *EventBroker) NotifyAutoStart(progress protocol.AutoStartProgress) {
+	b.distributeAbout("daemon", protocol.EventDaemon, eventAttrs{rule: progress.Rule}, protocol.DaemonEventNotification{
+		Type:      protocol.DaemonEventTypeAutoStart,
+		AutoStart: &progress,
+	})
+}
+
+// distribute は指定イベント種別の購読者全員に通知を送信する。
+func (b *EventBroker) distribute(eventType string, method string, payload any) {
+	b.distributeAbout(eventType, method, eventAttrs{}, payload)
+}
+
+// distributeAbout は指定イベント種別の購読者のうち、属性 attrs のイベントを受け取るものに通知を送信する。
+func (b *EventBroker) distributeAbout(eventType string, method string, attrs eventAttrs, payload any) {
+	notif, ok := newNotification(method, payload)
+	if !ok {
+		return
+	}
+	// ロック中にターゲットを収集し、ロック解放後に送信する
+	b.mu.RLock()
+	targets := b.targets(eventType, attrs)
+	b.mu.RUnlock()
+	b.send(targets, notif)
+}
+
+// record は payload にイベント ID を設定した通知を再送用の履歴に追加し、購読者全員に送信する。
+// 履歴への追加と送信先の収集は同じロックの中で行い、SubscribeSince の再送と重ならないようにする。
+func (b *EventBroker) record(eventType string, method string, attrs eventAttrs, payload func(id int64) any) {
+	b.mu.Lock()
+	id := b.history.lastID + 1
+	notif, ok := newNotification(method, payload(id))
+	if !ok {
+		b.mu.Unlock()
+		return
+	}
+	b.history.add(id, eventType, attrs, notif)
+	targets := b.targets(eventType, attrs)
+	b.mu.Unlock()
+	b.send(targets, notif)
+}
+
+// newNotification は payload をパラメータとする通知を生成する。
+func newNotification(method string, payload any) (protocol.Notification, bool) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		slog.Warn("failed to marshal notification", "error", err)
+		return protocol.Notification{}, false
+	}
+	return protocol.Notification{JSONRPC: protocol.JSONRPCVersion, Method: method, Params: data}, true
+}
+
+// targets は eventType の属性 attrs のイベントを購読しているクライアントの ID を返す。b.mu を保持して呼び出す。
+func (b *EventBroker) targets(eventType string, attrs eventAttrs) []string {
+	sent := make(map[string]bool)
+	var targets []string
+	for _, sub := range b.subscriptions {
+		if sub.matches(eventType, attrs) && !sent[sub.ClientID] {
+			sent[sub.ClientID] = true
+			targets = append(targets, sub.ClientID)
+		}
+	}
+	return targets
+}
+
+// send は targets の各クライアントに通知を送信する。
+func (b *EventBroker) send(targets []string, notif protocol.Notification) {
+	for _, clientID := range targets {
+		go b.sender(clientID, notif)
+	}
+}
@@ -1,15 +1,28 @@
-package ipc
+package broker
 
 import (
 	"encoding/json"
 	"errors"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		if cond() {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("waitFor: condition not met within timeout")
+}
+
 // collectingSender はテスト用に通知を収集する NotifySender を返す。
 func collectingSender() (NotifySender, *notifLog) {
 	log := &notifLog{}
@@ -0,0 +1,2 @@
+// Package broker は IPC クライアントへのイベント通知の配信（購読管理・定期集計・メトリクス）を提供する。
+package broker
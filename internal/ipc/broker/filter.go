@@ -0,0 +1,69 @@
+package broker
+
+// eventTypes は購読できるイベント種別。
+var eventTypes = map[string]bool{
+	"ssh":     true,
+	"forward": true,
+	"daemon":  true,
+	"metrics": true,
+	"summary": true,
+	"host":    true,
+	"config":  true,
+}
+
+// IsEventType は t が購読できるイベント種別かを返す。
+func IsEventType(t string) bool {
+	return eventTypes[t]
+}
+
+// Filter は購読するイベントをホスト名とルール名で絞り込む条件。空のフィールドは絞り込まない。
+// 各条件はその属性を持つイベントにのみ適用する（ホスト名のない ssh 以外のイベントや、ルール名のない ssh イベントは絞り込まれない）。
+type Filter struct {
+	Hosts []string
+	Rules []string
+}
+
+// eventAttrs は購読の絞り込みに使うイベントの属性。
+type eventAttrs struct {
+	hosts []string // 移行イベントでは移行先と移行元の両方
+	rule  string
+}
+
+// set は values の集合を返す。空文字列は無視し、要素がなければ nil を返す。
+func set(values []string) map[string]bool {
+	var m map[string]bool
+	for _, v := range values {
+		if v == "" {
+			continue
+		}
+		if m == nil {
+			m = make(map[string]bool, len(values))
+		}
+		m[v] = true
+	}
+	return m
+}
+
+// matches は購読が eventType のイベント（属性 attrs）を受け取るかを返す。
+func (s *Subscription) matches(eventType string, attrs eventAttrs) bool {
+	if !s.Types[eventType] {
+		return false
+	}
+	if s.Rules != nil && attrs.rule != "" && !s.Rules[attrs.rule] {
+		return false
+	}
+	if s.Hosts == nil {
+		return true
+	}
+	known := false
+	for _, h := range attrs.hosts {
+		if h == "" {
+			continue
+		}
+		if s.Hosts[h] {
+			return true
+		}
+		known = true
+	}
+	return !known
+}
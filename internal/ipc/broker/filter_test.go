@@ -0,0 +1,118 @@
+package broker
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestSubscription_Matches(t *testing.T) {
+	sub := &Subscription{
+		Types: map[string]bool{"ssh": true, "forward": true, "host": true},
+		Hosts: set([]string{"prod", ""}),
+		Rules: set([]string{"web"}),
+	}
+
+	tests := []struct {
+		name      string
+		eventType string
+		attrs     eventAttrs
+		want      bool
+	}{
+		{"ssh event of a watched host", "ssh", eventAttrs{hosts: []string{"prod"}}, true},
+		{"ssh event of another host", "ssh", eventAttrs{hosts: []string{"staging"}}, false},
+		{"forward event of a watched rule and host", "forward", eventAttrs{hosts: []string{"prod", ""}, rule: "web"}, true},
+		{"forward event of another rule", "forward", eventAttrs{hosts: []string{"prod", ""}, rule: "db"}, false},
+		{"forward event migrated from a watched host", "forward", eventAttrs{hosts: []string{"staging", "prod"}, rule: "web"}, true},
+		{"forward event without a host", "forward", eventAttrs{hosts: []string{"", ""}, rule: "web"}, true},
+		{"host event without a host", "host", eventAttrs{}, true},
+		{"unsubscribed type", "config", eventAttrs{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sub.matches(tt.eventType, tt.attrs); got != tt.want {
+				t.Errorf("matches(%q, %+v) = %v, want %v", tt.eventType, tt.attrs, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventBroker_FilteredTargets(t *testing.T) {
+	sender, _ := collectingSender()
+	broker := NewEventBroker(sender)
+	broker.Subscribe("client-all", []string{"ssh", "forward"})
+	broker.SubscribeSince("client-prod", []string{"ssh", "forward"}, Filter{Hosts: []string{"prod"}}, 0)
+	broker.SubscribeSince("client-web", []string{"forward"}, Filter{Rules: []string{"web"}}, 0)
+
+	tests := []struct {
+		name      string
+		eventType string
+		attrs     eventAttrs
+		want      []string
+	}{
+		{"ssh event of prod", "ssh", eventAttrs{hosts: []string{"prod"}}, []string{"client-all", "client-prod"}},
+		{"ssh event of staging", "ssh", eventAttrs{hosts: []string{"staging"}}, []string{"client-all"}},
+		{"forward web on staging", "forward", eventAttrs{hosts: []string{"staging"}, rule: "web"}, []string{"client-all", "client-web"}},
+		{"forward db on prod", "forward", eventAttrs{hosts: []string{"prod"}, rule: "db"}, []string{"client-all", "client-prod"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker.mu.RLock()
+			got := broker.targets(tt.eventType, tt.attrs)
+			broker.mu.RUnlock()
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("targets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEventBroker_SubscribeSinceFiltered(t *testing.T) {
+	sender, _ := collectingSender()
+	broker := NewEventBroker(sender)
+
+	broker.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventConnected, HostName: "prod"})    // 1
+	broker.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventConnected, HostName: "staging"}) // 2
+	broker.HandleForwardEvent(core.ForwardEvent{
+		Type: core.ForwardEventStarted, RuleName: "web",
+		Session: &core.ForwardSession{Rule: core.ForwardRule{Name: "web", Host: "prod"}},
+	}) // 3
+	broker.HandleForwardEvent(core.ForwardEvent{
+		Type: core.ForwardEventStarted, RuleName: "api",
+		Session: &core.ForwardSession{Rule: core.ForwardRule{Name: "api", Host: "staging"}},
+	}) // 4
+
+	_, replay := broker.SubscribeSince("client-1", []string{"ssh", "forward"}, Filter{Hosts: []string{"prod"}}, 0)
+	if len(replay.Events) != 0 {
+		t.Errorf("replayed %d events without since_id", len(replay.Events))
+	}
+	_, replay = broker.SubscribeSince("client-1", []string{"ssh", "forward"}, Filter{Hosts: []string{"prod"}}, 1)
+	if got := eventIDs(t, replay.Events); !slices.Equal(got, []int64{3}) {
+		t.Errorf("replayed IDs = %v, want [3]", got)
+	}
+}
+
+func TestMetricsNotification_Filtered(t *testing.T) {
+	web, db := activeSession("a", 1, 1), activeSession("b", 2, 2)
+	web.Rule = core.ForwardRule{Name: "web", Host: "prod"}
+	db.Rule = core.ForwardRule{Name: "db", Host: "staging"}
+	metrics := map[string]bool{"metrics": true}
+
+	notif := metricsNotification([]core.ForwardSession{web, db}, []*Subscription{
+		{Types: metrics, Hosts: set([]string{"prod"})},
+	})
+	if len(notif.Sessions) != 1 || notif.Sessions[0].Name != "web" {
+		t.Errorf("sessions = %+v, want only web", notif.Sessions)
+	}
+
+	// クライアントの購読のいずれかが受け取るセッションを含める
+	notif = metricsNotification([]core.ForwardSession{web, db}, []*Subscription{
+		{Types: metrics, Hosts: set([]string{"prod"})},
+		{Types: metrics, Rules: set([]string{"db"})},
+	})
+	if len(notif.Sessions) != 2 {
+		t.Errorf("sessions = %+v, want web and db", notif.Sessions)
+	}
+}
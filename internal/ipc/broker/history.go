@@ -0,0 +1,65 @@
+package broker
+
+import "github.com/ousiassllc/moleport/internal/ipc/protocol"
+
+// HistorySize は events.subscribe の since_id で再送するために保持するイベントの件数。
+const HistorySize = 1000
+
+// Replay は events.subscribe の since_id に対して再送するイベント。
+type Replay struct {
+	Events []protocol.Notification
+	// LastID は最後に発行したイベント ID。
+	LastID int64
+	// Truncated は sinceID より後のイベントの一部を保持しておらず、Events に含まれないことを示す。
+	Truncated bool
+}
+
+// recordedEvent は履歴に保持するイベント。
+type recordedEvent struct {
+	id        int64
+	eventType string
+	attrs     eventAttrs
+	notif     protocol.Notification
+}
+
+// history は直近の SSH・フォワードイベントを HistorySize 件保持するリングバッファ。
+// イベント ID は 1 から連番で発行し、デーモンの再起動でリセットされる。
+type history struct {
+	events []recordedEvent
+	next   int
+	lastID int64
+}
+
+// add はイベントを追加する。id は直前に追加したイベントの次の ID とする。
+func (h *history) add(id int64, eventType string, attrs eventAttrs, notif protocol.Notification) {
+	h.lastID = id
+	e := recordedEvent{id: id, eventType: eventType, attrs: attrs, notif: notif}
+	if len(h.events) < HistorySize {
+		h.events = append(h.events, e)
+		return
+	}
+	h.events[h.next] = e
+	h.next = (h.next + 1) % HistorySize
+}
+
+// since は sinceID より後のイベントのうち sub が受け取るものを古い順に返す。sinceID が 0 以下の場合は再送しない。
+// sinceID が最後に発行した ID より大きい場合は、再起動前のデーモンの ID とみなして Truncated を返す。
+func (h *history) since(sinceID int64, sub *Subscription) Replay {
+	r := Replay{LastID: h.lastID}
+	if sinceID <= 0 || sinceID == h.lastID {
+		return r
+	}
+	if sinceID > h.lastID {
+		r.Truncated = true
+		return r
+	}
+	oldest := h.lastID - int64(len(h.events)) + 1
+	r.Truncated = sinceID+1 < oldest
+	for i := range h.events {
+		e := h.events[(h.next+i)%len(h.events)]
+		if e.id > sinceID && sub.matches(e.eventType, e.attrs) {
+			r.Events = append(r.Events, e.notif)
+		}
+	}
+	return r
+}
@@ -0,0 +1,93 @@
+package broker
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// eventIDs は通知の params に含まれる event_id を返す。
+func eventIDs(t *testing.T, notifs []protocol.Notification) []int64 {
+	t.Helper()
+	ids := make([]int64, len(notifs))
+	for i, n := range notifs {
+		var p struct {
+			EventID int64 `json:"event_id"`
+		}
+		if err := json.Unmarshal(n.Params, &p); err != nil {
+			t.Fatalf("unmarshal params: %v", err)
+		}
+		ids[i] = p.EventID
+	}
+	return ids
+}
+
+func TestEventBroker_SubscribeSince(t *testing.T) {
+	sender, _ := collectingSender()
+	broker := NewEventBroker(sender)
+
+	broker.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventConnected, HostName: "a"})             // 1
+	broker.HandleForwardEvent(core.ForwardEvent{Type: core.ForwardEventStarted, RuleName: "web"}) // 2
+	broker.NotifyHostsChanged([]string{"b"}, nil)                                                 // 履歴に残らない
+	broker.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventDisconnected, HostName: "a"})          // 3
+	broker.HandleForwardEvent(core.ForwardEvent{Type: core.ForwardEventStopped, RuleName: "web"}) // 4
+
+	tests := []struct {
+		name          string
+		types         []string
+		sinceID       int64
+		wantIDs       []int64
+		wantTruncated bool
+	}{
+		{"no since_id", []string{"ssh", "forward"}, 0, nil, false},
+		{"missed events", []string{"ssh", "forward", "host"}, 1, []int64{2, 3, 4}, false},
+		{"only subscribed types", []string{"ssh"}, 1, []int64{3}, false},
+		{"up to date", []string{"ssh", "forward"}, 4, nil, false},
+		{"cursor from a previous daemon", []string{"ssh", "forward"}, 99, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subID, replay := broker.SubscribeSince("client-1", tt.types, Filter{}, tt.sinceID)
+			defer broker.Unsubscribe(subID)
+			if replay.LastID != 4 {
+				t.Errorf("LastID = %d, want 4", replay.LastID)
+			}
+			got := eventIDs(t, replay.Events)
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("replayed IDs = %v, want %v", got, tt.wantIDs)
+			}
+			for i := range got {
+				if got[i] != tt.wantIDs[i] {
+					t.Errorf("replayed IDs = %v, want %v", got, tt.wantIDs)
+				}
+			}
+			if replay.Truncated != tt.wantTruncated {
+				t.Errorf("Truncated = %v, want %v", replay.Truncated, tt.wantTruncated)
+			}
+		})
+	}
+}
+
+func TestEventBroker_SubscribeSinceTruncated(t *testing.T) {
+	sender, _ := collectingSender()
+	broker := NewEventBroker(sender)
+	for range HistorySize + 10 {
+		broker.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventConnected, HostName: "a"})
+	}
+
+	_, replay := broker.SubscribeSince("client-1", []string{"ssh"}, Filter{}, 5)
+	if !replay.Truncated {
+		t.Error("Truncated should be true when events after since_id were dropped")
+	}
+	ids := eventIDs(t, replay.Events)
+	if len(ids) != HistorySize || ids[0] != 11 || ids[len(ids)-1] != HistorySize+10 {
+		t.Errorf("replayed %d events (%d..%d), want %d events (11..%d)", len(ids), ids[0], ids[len(ids)-1], HistorySize, HistorySize+10)
+	}
+
+	_, replay = broker.SubscribeSince("client-1", []string{"ssh"}, Filter{}, 10)
+	if replay.Truncated || len(replay.Events) != HistorySize {
+		t.Errorf("since_id just before the oldest event: Truncated = %v, %d events", replay.Truncated, len(replay.Events))
+	}
+}
@@ -0,0 +1,69 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+)
+
+// MetricsInterval は event.metrics の配信間隔。フォワードのレートのサンプリング間隔に合わせる。
+const MetricsInterval = time.Second
+
+// RunMetrics は interval ごとに稼働中のセッションの転送量・レート・接続数を event.metrics として配信する。
+// ホスト名・ルール名で絞り込む購読には、対象のセッションのみを配信する。ctx がキャンセルされるまでブロックする。
+func (b *EventBroker) RunMetrics(ctx context.Context, interval time.Duration, sessions SessionSource) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			subs := b.subscribersOf("metrics")
+			if len(subs) == 0 {
+				continue
+			}
+			all := sessions.GetAllSessions()
+			for clientID, clientSubs := range subs {
+				if notif, ok := newNotification(protocol.EventMetrics, metricsNotification(all, clientSubs)); ok {
+					b.send([]string{clientID}, notif)
+				}
+			}
+		}
+	}
+}
+
+// metricsNotification は稼働中のセッションのうち、subs のいずれかが受け取るものを event.metrics の通知にまとめる。
+func metricsNotification(sessions []core.ForwardSession, subs []*Subscription) protocol.MetricsEventNotification {
+	notif := protocol.MetricsEventNotification{Sessions: []protocol.SessionMetrics{}}
+	for _, s := range sessions {
+		if s.Status != core.Active {
+			continue
+		}
+		attrs := eventAttrs{hosts: []string{s.Rule.Host}, rule: s.Rule.Name}
+		for _, sub := range subs {
+			if sub.matches("metrics", attrs) {
+				notif.Sessions = append(notif.Sessions, convert.ToSessionMetrics(s))
+				break
+			}
+		}
+	}
+	return notif
+}
+
+// subscribersOf は eventType を購読しているクライアントごとに、その購読を返す。
+func (b *EventBroker) subscribersOf(eventType string) map[string][]*Subscription {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	subs := make(map[string][]*Subscription)
+	for _, sub := range b.subscriptions {
+		if sub.Types[eventType] {
+			subs[sub.ClientID] = append(subs[sub.ClientID], sub)
+		}
+	}
+	return subs
+}
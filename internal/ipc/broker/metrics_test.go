@@ -0,0 +1,44 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestMetricsNotification_OnlyActive(t *testing.T) {
+	active := activeSession("a", 10, 20)
+	active.Rule.Name, active.SendRate, active.ActiveConns = "web", 128, 2
+	notif := metricsNotification([]core.ForwardSession{active, {Rule: core.ForwardRule{Name: "db"}, Status: core.Stopped}}, []*Subscription{{Types: map[string]bool{"metrics": true}}})
+	if len(notif.Sessions) != 1 {
+		t.Fatalf("sessions = %+v, want only the active one", notif.Sessions)
+	}
+	if got := notif.Sessions[0]; got.Name != "web" || got.SendRate != 128 || got.ActiveConns != 2 || got.BytesReceived != 20 {
+		t.Errorf("metrics = %+v", got)
+	}
+}
+
+func TestEventBroker_RunMetrics(t *testing.T) {
+	sender, log := collectingSender()
+	broker := NewEventBroker(sender)
+	broker.Subscribe("client-metrics", []string{"metrics"})
+	broker.Subscribe("client-ssh", []string{"ssh"})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.RunMetrics(ctx, 10*time.Millisecond, stubSources{})
+
+	waitForEntries(t, log, 1)
+	e := log.get()[0]
+	if e.ClientID != "client-metrics" || e.Notification.Method != protocol.EventMetrics {
+		t.Fatalf("notification = %s to %q, want event.metrics to client-metrics", e.Notification.Method, e.ClientID)
+	}
+	var notif protocol.MetricsEventNotification
+	if err := json.Unmarshal(e.Notification.Params, &notif); err != nil || len(notif.Sessions) != 1 {
+		t.Errorf("metrics = %+v, %v; want one session", notif, err)
+	}
+}
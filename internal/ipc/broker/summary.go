@@ -0,0 +1,108 @@
+package broker
+
+import (
+	"context"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// SummaryInterval は event.summary の配信間隔。
+const SummaryInterval = 5 * time.Second
+
+// throughputWeight はスループットの指数移動平均で直近の区間に与える重み。
+// 単発の転送で値が跳ねないよう、過去の値と半々で平滑化する。
+const throughputWeight = 0.5
+
+// SessionSource は集計対象のセッション一覧の取得元。core.ForwardManager が満たす。
+type SessionSource interface {
+	GetAllSessions() []core.ForwardSession
+}
+
+// HostSource は集計対象のホスト一覧の取得元。core.SSHManager が満たす。
+type HostSource interface {
+	GetHosts() []core.SSHHost
+}
+
+// RunSummary は interval ごとにデーモン全体の集計値を event.summary として配信する。
+// ctx がキャンセルされるまでブロックする。
+func (b *EventBroker) RunSummary(ctx context.Context, interval time.Duration, sessions SessionSource, hosts HostSource) {
+	s := newSummarizer()
+	s.baseline(sessions.GetAllSessions())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			errs := int(b.errorCount.Swap(0))
+			notif := s.next(sessions.GetAllSessions(), hosts.GetHosts(), errs, interval)
+			b.distribute("summary", protocol.EventSummary, notif)
+		}
+	}
+}
+
+// summarizer は区間ごとの転送量の差分とスループットの移動平均を保持する。
+type summarizer struct {
+	prev       map[string]int64 // セッション ID -> 前回集計時の送受信合計
+	throughput float64
+	primed     bool
+}
+
+func newSummarizer() *summarizer {
+	return &summarizer{prev: make(map[string]int64)}
+}
+
+// baseline は起動時点の転送量を記録し、最初の区間に過去の転送量が計上されないようにする。
+func (s *summarizer) baseline(sessions []core.ForwardSession) {
+	for _, sess := range sessions {
+		if sess.Status == core.Active {
+			s.prev[sess.ID] = sess.BytesSent + sess.BytesReceived
+		}
+	}
+}
+
+// next は現在のセッション・ホスト状態から集計値を算出し、次の区間の基準を更新する。
+func (s *summarizer) next(sessions []core.ForwardSession, hosts []core.SSHHost, errs int, elapsed time.Duration) protocol.SummaryEventNotification {
+	notif := protocol.SummaryEventNotification{
+		TotalForwards: len(sessions),
+		Errors:        errs,
+		Interval:      elapsed.String(),
+	}
+
+	var delta int64
+	current := make(map[string]int64, len(sessions))
+	for _, sess := range sessions {
+		if sess.Status != core.Active {
+			continue
+		}
+		notif.ActiveForwards++
+		notif.BytesSent += sess.BytesSent
+		notif.BytesReceived += sess.BytesReceived
+
+		total := sess.BytesSent + sess.BytesReceived
+		current[sess.ID] = total
+		// 新しいセッションは前回値 0 として扱う
+		delta += max(total-s.prev[sess.ID], 0)
+	}
+	s.prev = current
+
+	for _, h := range hosts {
+		if h.State == core.Connected {
+			notif.HostsConnected++
+		}
+	}
+
+	rate := float64(delta) / elapsed.Seconds()
+	if s.primed {
+		s.throughput = throughputWeight*rate + (1-throughputWeight)*s.throughput
+	} else {
+		s.throughput = rate
+		s.primed = true
+	}
+	notif.ThroughputBps = s.throughput
+	return notif
+}
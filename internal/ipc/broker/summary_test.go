@@ -0,0 +1,103 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func activeSession(id string, sent, received int64) core.ForwardSession {
+	return core.ForwardSession{ID: id, Status: core.Active, BytesSent: sent, BytesReceived: received}
+}
+
+func TestSummarizer_Aggregates(t *testing.T) {
+	s := newSummarizer()
+	s.baseline([]core.ForwardSession{activeSession("a", 1000, 1000)})
+
+	sessions := []core.ForwardSession{
+		activeSession("a", 1500, 1500),
+		activeSession("b", 100, 0),
+		{ID: "c", Status: core.Stopped},
+	}
+	hosts := []core.SSHHost{{Name: "h1", State: core.Connected}, {Name: "h2", State: core.Disconnected}}
+
+	got := s.next(sessions, hosts, 2, time.Second)
+	want := protocol.SummaryEventNotification{
+		ActiveForwards: 2,
+		TotalForwards:  3,
+		HostsConnected: 1,
+		BytesSent:      1600,
+		BytesReceived:  1500,
+		ThroughputBps:  1100, // a: +1000, b: +100（新規セッションは全量）
+		Errors:         2,
+		Interval:       "1s",
+	}
+	if got != want {
+		t.Errorf("next() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSummarizer_ThroughputIsSmoothed(t *testing.T) {
+	s := newSummarizer()
+	s.baseline([]core.ForwardSession{activeSession("a", 0, 0)})
+
+	first := s.next([]core.ForwardSession{activeSession("a", 1000, 0)}, nil, 0, time.Second)
+	second := s.next([]core.ForwardSession{activeSession("a", 1000, 0)}, nil, 0, time.Second)
+
+	if first.ThroughputBps != 1000 {
+		t.Errorf("first throughput = %v, want 1000", first.ThroughputBps)
+	}
+	if second.ThroughputBps != 500 {
+		t.Errorf("second throughput = %v, want 500 (weighted average of 1000 and 0)", second.ThroughputBps)
+	}
+}
+
+type stubSources struct{}
+
+func (stubSources) GetAllSessions() []core.ForwardSession {
+	return []core.ForwardSession{activeSession("a", 10, 20)}
+}
+func (stubSources) GetHosts() []core.SSHHost { return []core.SSHHost{{State: core.Connected}} }
+
+func TestEventBroker_RunSummary(t *testing.T) {
+	sender, log := collectingSender()
+	broker := NewEventBroker(sender)
+	broker.Subscribe("client-summary", []string{"summary"})
+	broker.Subscribe("client-ssh", []string{"ssh"})
+
+	broker.HandleForwardEvent(core.ForwardEvent{Type: core.ForwardEventError, RuleName: "web", Error: errors.New("boom")})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go broker.RunSummary(ctx, 10*time.Millisecond, stubSources{}, stubSources{})
+
+	waitFor(t, func() bool {
+		for _, e := range log.get() {
+			if e.Notification.Method == protocol.EventSummary {
+				return true
+			}
+		}
+		return false
+	})
+	for _, e := range log.get() {
+		if e.Notification.Method != protocol.EventSummary {
+			continue
+		}
+		if e.ClientID != "client-summary" {
+			t.Errorf("summary sent to %q, want client-summary only", e.ClientID)
+		}
+		var notif protocol.SummaryEventNotification
+		if err := json.Unmarshal(e.Notification.Params, &notif); err != nil {
+			t.Fatalf("unmarshal summary: %v", err)
+		}
+		if notif.ActiveForwards != 1 || notif.HostsConnected != 1 || notif.Errors != 1 {
+			t.Errorf("summary = %+v, want 1 active, 1 host, 1 error", notif)
+		}
+		break
+	}
+}
@@ -0,0 +1,54 @@
+package broker
+
+import (
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// sshEventTypeToString は SSHEventType をイベント通知用のワイヤー文字列に変換する。
+func sshEventTypeToString(t core.SSHEventType) string {
+	switch t {
+	case core.SSHEventConnected:
+		return protocol.StateConnected
+	case core.SSHEventDisconnected:
+		return protocol.StateDisconnected
+	case core.SSHEventReconnecting:
+		return protocol.StateReconnecting
+	case core.SSHEventPendingAuth:
+		return protocol.StatePendingAuth
+	case core.SSHEventError:
+		return protocol.StateError
+	default:
+		return protocol.StateDisconnected
+	}
+}
+
+// forwardEventTypeToString は ForwardEventType をワイヤー文字列に変換する。
+func forwardEventTypeToString(t core.ForwardEventType) string {
+	switch t {
+	case core.ForwardEventStarted:
+		return protocol.ForwardEventTypeStarted
+	case core.ForwardEventStopped:
+		return protocol.ForwardEventTypeStopped
+	case core.ForwardEventError:
+		return protocol.ForwardEventTypeError
+	case core.ForwardEventMetricsUpdated:
+		return protocol.ForwardEventTypeMetricsUpdated
+	case core.ForwardEventReconnecting:
+		return protocol.ForwardEventTypeReconnecting
+	case core.ForwardEventRestored:
+		return protocol.ForwardEventTypeRestored
+	case core.ForwardEventMigrated:
+		return protocol.ForwardEventTypeMigrated
+	case core.ForwardEventExpiring:
+		return protocol.ForwardEventTypeExpiring
+	case core.ForwardEventDenied:
+		return protocol.ForwardEventTypeDenied
+	case core.ForwardEventDraining:
+		return protocol.ForwardEventTypeDraining
+	case core.ForwardEventUpdated:
+		return protocol.ForwardEventTypeUpdated
+	default:
+		return "unknown"
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcid"
 )
 
 const (
@@ -31,6 +32,7 @@ type CredentialHandler func(req protocol.CredentialRequestNotification) (*protoc
 // IPCClient は Unix ドメインソケット上で JSON-RPC 2.0 通信を行うクライアント。
 type IPCClient struct {
 	socketPath  string
+	dial        DialFunc
 	conn        net.Conn
 	enc         *json.Encoder
 	scanner     *bufio.Scanner
@@ -43,6 +45,10 @@ type IPCClient struct {
 	connected   atomic.Bool
 	credMu      sync.RWMutex
 	credHandler CredentialHandler
+	readOnly    atomic.Bool
+	profile     atomic.Value
+	token       string
+	server      atomic.Pointer[protocol.DaemonHelloResult]
 }
 
 // NewIPCClient は指定された Unix ソケットパスで新しい IPC クライアントを生成する。
@@ -55,11 +61,11 @@ func NewIPCClient(socketPath string) *IPCClient {
 	}
 }
 
-// Connect はデーモンの Unix ソケットに接続し、受信ループを開始する。
+// Connect はデーモンの Unix ソケット（NewIPCClientWithDialer で生成した場合はその接続先）に接続し、受信ループを開始する。
 func (c *IPCClient) Connect() error {
-	conn, err := net.Dial("unix", c.socketPath)
+	conn, err := c.dialConn()
 	if err != nil {
-		return fmt.Errorf("dial unix: %w", err)
+		return err
 	}
 
 	c.conn = conn
@@ -70,6 +76,10 @@ func (c *IPCClient) Connect() error {
 
 	go c.readLoop()
 
+	if err := c.login(); err != nil {
+		_ = c.Close()
+		return err
+	}
 	return nil
 }
 
@@ -110,60 +120,49 @@ func (c *IPCClient) Call(ctx context.Context, method string, params any, result
 	if !c.connected.Load() {
 		return errors.New("not connected")
 	}
-
-	id := int(c.nextID.Add(1))
-
-	var rawParams json.RawMessage
-	if params != nil {
-		data, err := json.Marshal(params)
-		if err != nil {
-			return fmt.Errorf("marshal params: %w", err)
-		}
-		rawParams = data
+	if c.readOnly.Load() && !protocol.IsReadOnlyMethod(method) {
+		return fmt.Errorf("%w: %s", ErrReadOnly, method)
 	}
 
+	rawParams, err := c.marshalParams(params)
+	if err != nil {
+		return err
+	}
+	id, ch := c.register()
 	req := protocol.Request{
 		JSONRPC: protocol.JSONRPCVersion,
-		ID:      &id,
+		ID:      rpcid.Number(int64(id)),
 		Method:  method,
 		Params:  rawParams,
 	}
-
-	ch := make(chan *protocol.Response, 1)
-	c.pendingMu.Lock()
-	c.pending[id] = ch
-	c.pendingMu.Unlock()
-
-	c.mu.Lock()
-	err := c.enc.Encode(req)
-	c.mu.Unlock()
-	if err != nil {
-		c.pendingMu.Lock()
-		delete(c.pending, id)
-		c.pendingMu.Unlock()
+	if err := c.write(req); err != nil {
+		c.unregister(id)
 		return fmt.Errorf("send request: %w", err)
 	}
+	return c.await(ctx, id, ch, result)
+}
 
-	select {
-	case resp, ok := <-ch:
-		if !ok {
-			return errors.New("connection closed")
-		}
-		if resp.Error != nil {
-			return resp.Error
-		}
-		if result != nil && resp.Result != nil {
-			if err := json.Unmarshal(resp.Result, result); err != nil {
-				return fmt.Errorf("unmarshal result: %w", err)
-			}
+// marshalParams は params を JSON にエンコードし、プロファイルが設定されていれば付加する。
+func (c *IPCClient) marshalParams(params any) (json.RawMessage, error) {
+	var rawParams json.RawMessage
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("marshal params: %w", err)
 		}
-		return nil
-	case <-ctx.Done():
-		c.pendingMu.Lock()
-		delete(c.pending, id)
-		c.pendingMu.Unlock()
-		return ctx.Err()
+		rawParams = data
 	}
+	if profile := c.Profile(); profile != "" {
+		return withProfile(rawParams, profile)
+	}
+	return rawParams, nil
+}
+
+// write は v を 1 行の JSON として送信する。
+func (c *IPCClient) write(v any) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.enc.Encode(v)
 }
 
 // Subscribe はイベントサブスクリプションを登録する。
@@ -176,6 +175,21 @@ func (c *IPCClient) Subscribe(ctx context.Context, types []string) (string, erro
 	return result.SubscriptionID, nil
 }
 
+// SubscribeSince はイベントサブスクリプションを登録し、sinceID より後に発生した SSH・フォワードイベントの再送を受け取る。
+// 再接続したクライアントが、切断中に取りこぼしたイベントを結果の Replay から処理するために使う。
+func (c *IPCClient) SubscribeSince(ctx context.Context, types []string, sinceID int64) (*protocol.EventsSubscribeResult, error) {
+	return c.SubscribeWith(ctx, protocol.EventsSubscribeParams{Types: types, SinceID: sinceID})
+}
+
+// SubscribeWith は params（ホスト名・ルール名による絞り込みを含む）でイベントサブスクリプションを登録する。
+func (c *IPCClient) SubscribeWith(ctx context.Context, params protocol.EventsSubscribeParams) (*protocol.EventsSubscribeResult, error) {
+	var result protocol.EventsSubscribeResult
+	if err := c.Call(ctx, protocol.MethodEventsSubscribe, params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // Unsubscribe はイベントサブスクリプションを解除する。
 func (c *IPCClient) Unsubscribe(ctx context.Context, subscriptionID string) error {
 	params := protocol.EventsUnsubscribeParams{SubscriptionID: subscriptionID}
@@ -206,6 +220,18 @@ func (c *IPCClient) readLoop() {
 			continue
 		}
 
+		// バッチの応答は Response の配列で届く
+		if line[0] == '[' {
+			var resps []protocol.Response
+			if err := json.Unmarshal(line, &resps); err != nil {
+				continue
+			}
+			for i := range resps {
+				c.deliver(&resps[i])
+			}
+			continue
+		}
+
 		// "id" フィールドの有無で Response と Notification を判別する
 		var raw map[string]json.RawMessage
 		if err := json.Unmarshal(line, &raw); err != nil {
@@ -217,17 +243,7 @@ func (c *IPCClient) readLoop() {
 			if err := json.Unmarshal(line, &resp); err != nil {
 				continue
 			}
-			if resp.ID != nil {
-				c.pendingMu.Lock()
-				ch, ok := c.pending[*resp.ID]
-				if ok {
-					delete(c.pending, *resp.ID)
-				}
-				c.pendingMu.Unlock()
-				if ok {
-					ch <- &resp
-				}
-			}
+			c.deliver(&resp)
 		} else {
 			var notif protocol.Notification
 			if err := json.Unmarshal(line, &notif); err != nil {
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// loginTimeout は接続直後の auth.login の応答待ちのタイムアウト。
+const loginTimeout = 5 * time.Second
+
+// SetToken は Connect 時に auth.login で提示する認証トークンを設定する。Connect の前に呼ぶ。
+func (c *IPCClient) SetToken(token string) {
+	c.token = token
+}
+
+// login は設定されたトークンで auth.login を呼び出し、デーモンが割り当てたロールが読み取り専用であれば
+// クライアントも読み取り専用スコープにする。Unix ソケットでトークンが未設定の場合は何もしないが、
+// Connect の前に読み取り専用スコープにした場合はデーモン側の接続も読み取り専用にするため常に呼び出す。
+func (c *IPCClient) login() error {
+	if c.token == "" && c.dial == nil && !c.readOnly.Load() {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), loginTimeout)
+	defer cancel()
+	return c.authLogin(ctx)
+}
+
+// Login は token を設定し、接続済みのデーモンに auth.login で提示し直す。
+// 読み取り専用トークンを提示した場合、デーモンは接続を読み取り専用にし、クライアントも読み取り専用スコープになる。
+func (c *IPCClient) Login(ctx context.Context, token string) error {
+	c.token = token
+	return c.authLogin(ctx)
+}
+
+// RestrictToReadOnly はクライアントを読み取り専用スコープにし、auth.login で Role に読み取り専用を指定して
+// デーモン側の接続も読み取り専用にする。クライアント側の制限だけではトークンなしで Unix ソケットに
+// 接続した場合に接続が管理者のまま残るため、閲覧専用モードは接続後にこれを呼び出す。
+func (c *IPCClient) RestrictToReadOnly(ctx context.Context) error {
+	c.readOnly.Store(true)
+	return c.authLogin(ctx)
+}
+
+// authLogin は auth.login を呼び出す。読み取り専用スコープの場合は Role に読み取り専用を指定する。
+// auth.login に対応していない古いデーモンには認証なしで接続する。
+func (c *IPCClient) authLogin(ctx context.Context) error {
+	params := protocol.AuthLoginParams{Token: c.token}
+	if c.readOnly.Load() {
+		params.Role = protocol.RoleReadOnly
+	}
+	var result protocol.AuthLoginResult
+	err := c.Call(ctx, protocol.MethodAuthLogin, params, &result)
+	var rpcErr *protocol.RPCError
+	if errors.As(err, &rpcErr) && rpcErr.Code == protocol.MethodNotFound {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("auth.login: %w", err)
+	}
+	if result.Role == protocol.RoleReadOnly {
+		c.readOnly.Store(true)
+	}
+	return nil
+}
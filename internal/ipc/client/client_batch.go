@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcid"
+)
+
+// BatchCall は Batch で呼び出す 1 件の RPC メソッド。
+type BatchCall struct {
+	Method string
+	Params any
+	// Result には応答の result フィールドがアンマーシャルされる。
+	Result any
+	// Err には Batch の後に、この呼び出しのエラー（サーバーが返した *RPCError など）が設定される。
+	Err error
+}
+
+// Batch は calls を 1 つの JSON-RPC バッチとして送信し、全ての応答を待つ。
+// 個々の呼び出しの結果は calls[i].Result と calls[i].Err に設定する。
+// 送信に失敗した場合と、応答を待つ間に ctx が終了した場合はエラーを返す（残りの呼び出しはデーモンに取り消しを通知する）。
+// 接続が切れた場合は、応答のない呼び出しの Err に設定する。
+// Hello でバッチに対応していないと分かったデーモンには、1 件ずつ順に呼び出す。
+func (c *IPCClient) Batch(ctx context.Context, calls []BatchCall) error {
+	if !c.connected.Load() {
+		return errors.New("not connected")
+	}
+	if !c.supports(protocol.CapabilityBatch) {
+		return c.callEach(ctx, calls)
+	}
+
+	ids := make([]int, len(calls))
+	chs := make([]chan *protocol.Response, len(calls))
+	reqs := make([]protocol.Request, 0, len(calls))
+	for i := range calls {
+		call := &calls[i]
+		call.Err = nil
+		if c.readOnly.Load() && !protocol.IsReadOnlyMethod(call.Method) {
+			call.Err = fmt.Errorf("%w: %s", ErrReadOnly, call.Method)
+			continue
+		}
+		rawParams, err := c.marshalParams(call.Params)
+		if err != nil {
+			call.Err = err
+			continue
+		}
+		ids[i], chs[i] = c.register()
+		reqs = append(reqs, protocol.Request{
+			JSONRPC: protocol.JSONRPCVersion,
+			ID:      rpcid.Number(int64(ids[i])),
+			Method:  call.Method,
+			Params:  rawParams,
+		})
+	}
+	if len(reqs) == 0 {
+		return nil
+	}
+
+	if err := c.write(reqs); err != nil {
+		for i := range calls {
+			if chs[i] != nil {
+				c.unregister(ids[i])
+			}
+		}
+		return fmt.Errorf("send batch: %w", err)
+	}
+	for i := range calls {
+		if chs[i] == nil {
+			continue
+		}
+		calls[i].Err = c.await(ctx, ids[i], chs[i], calls[i].Result)
+		if err := ctx.Err(); err != nil {
+			for j := i + 1; j < len(calls); j++ {
+				if chs[j] != nil {
+					c.abandon(ids[j])
+				}
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// callEach は calls を 1 件ずつ Call で呼び出す。ctx が終了した場合は残りを呼び出さずにエラーを返す。
+func (c *IPCClient) callEach(ctx context.Context, calls []BatchCall) error {
+	for i := range calls {
+		calls[i].Err = c.Call(ctx, calls[i].Method, calls[i].Params, calls[i].Result)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Notify は method を通知（ID なし）として送信する。デーモンはレスポンスを返さない。
+func (c *IPCClient) Notify(method string, params any) error {
+	if !c.connected.Load() {
+		return errors.New("not connected")
+	}
+	if c.readOnly.Load() && !protocol.IsReadOnlyMethod(method) {
+		return fmt.Errorf("%w: %s", ErrReadOnly, method)
+	}
+	rawParams, err := c.marshalParams(params)
+	if err != nil {
+		return err
+	}
+	req := protocol.Request{JSONRPC: protocol.JSONRPCVersion, Method: method, Params: rawParams}
+	if err := c.write(req); err != nil {
+		return fmt.Errorf("send notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/ousiassllc/moleport/internal/ipc/localsock"
+)
+
+// DialFunc はデーモンへの接続を確立する関数。
+type DialFunc func() (net.Conn, error)
+
+// NewIPCClientWithDialer は dial で接続する IPC クライアントを生成する。
+// TLS で接続するリモートのデーモンなど、ローカルのソケット以外の接続先に使う。
+func NewIPCClientWithDialer(dial DialFunc) *IPCClient {
+	c := NewIPCClient("")
+	c.dial = dial
+	return c
+}
+
+// dialConn はデーモンへの接続を確立する。
+func (c *IPCClient) dialConn() (net.Conn, error) {
+	if c.dial != nil {
+		conn, err := c.dial()
+		if err != nil {
+			return nil, fmt.Errorf("dial: %w", err)
+		}
+		return conn, nil
+	}
+	conn, err := localsock.Dial(c.socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("dial: %w", err)
+	}
+	return conn, nil
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// ErrIncompatibleProtocol はデーモンの IPC プロトコルのバージョンがクライアントと互換でないことを示す。
+var ErrIncompatibleProtocol = errors.New("incompatible IPC protocol")
+
+// Hello は daemon.hello でデーモンとプロトコルバージョンを交換し、デーモンの情報を返す。
+// daemon.hello に対応していない古いデーモンは protocol.LegacyProtocolVersion で機能フラグなしとみなす。
+// 互換性がない場合は、デーモンの情報と ErrIncompatibleProtocol をラップしたエラーを返す。
+// 結果は保存し、以降の Batch と取り消しの通知はデーモンが対応していない機能を使わずに行う。
+func (c *IPCClient) Hello(ctx context.Context, clientVersion string) (*protocol.DaemonHelloResult, error) {
+	var result protocol.DaemonHelloResult
+	params := protocol.DaemonHelloParams{ProtocolVersion: protocol.ProtocolVersion, ClientVersion: clientVersion}
+	err := c.Call(ctx, protocol.MethodDaemonHello, params, &result)
+	var rpcErr *protocol.RPCError
+	switch {
+	case errors.As(err, &rpcErr) && rpcErr.Code == protocol.MethodNotFound:
+		result = protocol.DaemonHelloResult{
+			ProtocolVersion:    protocol.LegacyProtocolVersion,
+			MinProtocolVersion: protocol.LegacyProtocolVersion,
+		}
+	case err != nil:
+		return nil, fmt.Errorf("daemon.hello: %w", err)
+	}
+	c.server.Store(&result)
+
+	if !protocol.Compatible(result.ProtocolVersion, result.MinProtocolVersion) {
+		return &result, fmt.Errorf("%w: daemon %s uses protocol version %d (requires %d or later), client uses %d",
+			ErrIncompatibleProtocol, result.ServerVersion, result.ProtocolVersion, result.MinProtocolVersion, protocol.ProtocolVersion)
+	}
+	return &result, nil
+}
+
+// supports はデーモンが機能フラグ capability に対応するかを返す。Hello を呼んでいない場合は対応するとみなす。
+func (c *IPCClient) supports(capability string) bool {
+	server := c.server.Load()
+	return server == nil || server.HasCapability(capability)
+}
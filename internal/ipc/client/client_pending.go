@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcid"
+)
+
+// register は新しいリクエスト ID を採番し、その応答を受け取るチャネルを登録する。
+func (c *IPCClient) register() (int, chan *protocol.Response) {
+	id := int(c.nextID.Add(1))
+	ch := make(chan *protocol.Response, 1)
+	c.pendingMu.Lock()
+	c.pending[id] = ch
+	c.pendingMu.Unlock()
+	return id, ch
+}
+
+func (c *IPCClient) unregister(id int) {
+	c.pendingMu.Lock()
+	delete(c.pending, id)
+	c.pendingMu.Unlock()
+}
+
+// await は id の応答を待ち、result にアンマーシャルする。応答の前に ctx が終了した場合はデーモンに取り消しを通知する。
+func (c *IPCClient) await(ctx context.Context, id int, ch chan *protocol.Response, result any) error {
+	select {
+	case resp, ok := <-ch:
+		if !ok {
+			return errors.New("connection closed")
+		}
+		if resp.Error != nil {
+			return resp.Error
+		}
+		if result != nil && resp.Result != nil {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("unmarshal result: %w", err)
+			}
+		}
+		return nil
+	case <-ctx.Done():
+		c.abandon(id)
+		return ctx.Err()
+	}
+}
+
+// abandon は id の応答待ちをやめ、デーモンに rpc.cancel を通知して処理を取り消す。
+// Hello で rpc.cancel に対応していないと分かったデーモンには通知しない。
+func (c *IPCClient) abandon(id int) {
+	c.unregister(id)
+	if c.supports(protocol.CapabilityCancel) {
+		_ = c.Notify(protocol.MethodRPCCancel, protocol.RPCCancelParams{ID: rpcid.Number(int64(id))})
+	}
+}
+
+// deliver は応答を、その ID のリクエストを待っている呼び出し元に渡す。
+func (c *IPCClient) deliver(resp *protocol.Response) {
+	n, isNum := resp.ID.Int()
+	if !isNum {
+		return
+	}
+	id := int(n)
+	c.pendingMu.Lock()
+	ch, ok := c.pending[id]
+	if ok {
+		delete(c.pending, id)
+	}
+	c.pendingMu.Unlock()
+	if ok {
+		ch <- resp
+	}
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SetProfile は以降のリクエストの対象プロファイルを設定する。空文字列は既定プロファイルを表す。
+// 設定されている場合、全リクエストの params に profile フィールドを付加する。
+func (c *IPCClient) SetProfile(profile string) {
+	c.profile.Store(profile)
+}
+
+// Profile は対象プロファイル名を返す。未設定の場合は空文字列を返す。
+func (c *IPCClient) Profile() string {
+	p, _ := c.profile.Load().(string)
+	return p
+}
+
+// withProfile は params に profile フィールドを付加した JSON を返す。
+func withProfile(params json.RawMessage, profile string) (json.RawMessage, error) {
+	obj := make(map[string]json.RawMessage)
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &obj); err != nil {
+			return nil, fmt.Errorf("params must be an object to set profile: %w", err)
+		}
+	}
+	name, err := json.Marshal(profile)
+	if err != nil {
+		return nil, err
+	}
+	obj["profile"] = name
+	return json.Marshal(obj)
+}
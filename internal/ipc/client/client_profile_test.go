@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestIPCClient_ProfileAddedToParams(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer func() { _ = serverConn.Close() }()
+
+	server := newMockServer(t, serverConn)
+	c := newTestClient(t, clientConn)
+	c.SetProfile("work")
+	if c.Profile() != "work" {
+		t.Fatalf("Profile() = %q, want work", c.Profile())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	calls := []struct {
+		method string
+		params any
+		want   string
+	}{
+		{"host.list", nil, `{"profile":"work"}`},
+		{"forward.start", map[string]string{"name": "web"}, `{"name":"web","profile":"work"}`},
+	}
+	for _, call := range calls {
+		errCh := make(chan error, 1)
+		go func() { errCh <- server.readAndRespond() }()
+		if err := c.Call(ctx, call.method, call.params, nil); err != nil {
+			t.Fatalf("Call(%q) error = %v", call.method, err)
+		}
+		if err := <-errCh; err != nil {
+			t.Fatalf("readAndRespond: %v", err)
+		}
+	}
+
+	got := server.getReceived()
+	for i, call := range calls {
+		var want, params map[string]any
+		_ = json.Unmarshal([]byte(call.want), &want)
+		if err := json.Unmarshal(got[i].Params, &params); err != nil {
+			t.Fatalf("unmarshal params: %v", err)
+		}
+		if len(params) != len(want) || params["profile"] != want["profile"] || params["name"] != want["name"] {
+			t.Errorf("%s params = %s, want %s", call.method, got[i].Params, call.want)
+		}
+	}
+}
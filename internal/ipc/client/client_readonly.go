@@ -0,0 +1,17 @@
+package client
+
+import "errors"
+
+// ErrReadOnly は読み取り専用クライアントから状態を変更するメソッドを呼び出した場合のエラー。
+var ErrReadOnly = errors.New("read-only client cannot call mutating method")
+
+// SetReadOnly はクライアントを読み取り専用スコープに設定する。
+// 有効な場合、protocol.IsReadOnlyMethod が false を返すメソッドは送信前に拒否される。
+func (c *IPCClient) SetReadOnly(readOnly bool) {
+	c.readOnly.Store(readOnly)
+}
+
+// IsReadOnly はクライアントが読み取り専用スコープかを返す。
+func (c *IPCClient) IsReadOnly() bool {
+	return c.readOnly.Load()
+}
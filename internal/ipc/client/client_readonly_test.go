@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestIPCClient_ReadOnlyRejectsMutatingMethods(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer func() { _ = serverConn.Close() }()
+
+	server := newMockServer(t, serverConn)
+	c := newTestClient(t, clientConn)
+	c.SetReadOnly(true)
+	if !c.IsReadOnly() {
+		t.Fatal("IsReadOnly() = false, want true")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	for _, method := range []string{"forward.start", "forward.delete", "config.update", "daemon.shutdown"} {
+		if err := c.Call(ctx, method, nil, nil); !errors.Is(err, ErrReadOnly) {
+			t.Errorf("Call(%q) error = %v, want ErrReadOnly", method, err)
+		}
+	}
+
+	// 読み取り専用メソッドはサーバーに送信される
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.readAndRespond() }()
+	var result map[string]any
+	if err := c.Call(ctx, "session.list", nil, &result); err != nil {
+		t.Fatalf("Call(session.list) error = %v", err)
+	}
+	if err := <-errCh; err != nil {
+		t.Fatalf("readAndRespond: %v", err)
+	}
+	if got := server.getReceived(); len(got) != 1 || got[0].Method != "session.list" {
+		t.Errorf("received = %+v, want only session.list", got)
+	}
+}
@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/themeinfo"
 )
 
 // parsedDurations はバリデーション時にパースした Duration を保持する。
@@ -49,6 +52,7 @@ func (h *Handler) Get() (any, *protocol.RPCError) {
 			InitialDelay:      cfg.Reconnect.InitialDelay.String(),
 			MaxDelay:          cfg.Reconnect.MaxDelay.String(),
 			KeepAliveInterval: cfg.Reconnect.KeepAliveInterval.String(),
+			KeepAliveCountMax: cfg.Reconnect.KeepAliveCountMax,
 		},
 		Session: protocol.SessionCfgInfo{
 			AutoRestore: cfg.Session.AutoRestore,
@@ -67,9 +71,24 @@ func (h *Handler) Get() (any, *protocol.RPCError) {
 				Base:   cfg.TUI.Theme.Base,
 				Accent: cfg.TUI.Theme.Accent,
 			},
+			Layout: protocol.LayoutInfo{
+				ForwardPercent: cfg.TUI.Layout.ForwardPercent,
+				HideLog:        cfg.TUI.Layout.HideLog,
+			},
+		},
+		UI: protocol.UIInfo{
+			TimeFormat: cfg.UI.TimeFormat,
 		},
 	}
 
+	for _, ut := range cfg.TUI.Themes {
+		result.TUI.Themes = append(result.TUI.Themes, themeinfo.UserTheme{
+			Name:   ut.Name,
+			Base:   ut.Base,
+			Colors: themeinfo.Colors(ut.Colors),
+		})
+	}
+
 	if len(cfg.Hosts) > 0 {
 		result.Hosts = make(map[string]protocol.HostConfigInfo, len(cfg.Hosts))
 		for name, hc := range cfg.Hosts {
@@ -147,8 +166,19 @@ func (h *Handler) Update(params json.RawMessage) (any, *protocol.RPCError) {
 				cfg.TUI.Theme.Accent = *p.TUI.Theme.Accent
 			}
 		}
+		if p.TUI != nil && p.TUI.Layout != nil {
+			if p.TUI.Layout.ForwardPercent != nil {
+				cfg.TUI.Layout.ForwardPercent = *p.TUI.Layout.ForwardPercent
+			}
+			if p.TUI.Layout.HideLog != nil {
+				cfg.TUI.Layout.HideLog = *p.TUI.Layout.HideLog
+			}
+		}
+		if p.UI != nil && p.UI.TimeFormat != nil {
+			cfg.UI.TimeFormat = *p.UI.TimeFormat
+		}
 	}); err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 
 	return protocol.ConfigUpdateResult{OK: true}, nil
@@ -178,6 +208,12 @@ func validateParams(p *protocol.ConfigUpdateParams) (parsedDurations, *protocol.
 			}
 		}
 	}
+	if p.UI != nil && p.UI.TimeFormat != nil && !format.ValidTimeFormat(*p.UI.TimeFormat) {
+		return nil, &protocol.RPCError{
+			Code:    protocol.InvalidParams,
+			Message: fmt.Sprintf("invalid ui.time_format: %q (must be relative, local or utc)", *p.UI.TimeFormat),
+		}
+	}
 	for name, update := range p.Hosts {
 		if update == nil || update.Reconnect == nil {
 			continue
@@ -211,6 +247,9 @@ func applyReconnect(cfg *core.Config, r *protocol.ReconnectUpdateInfo, durations
 	if d, ok := durations["reconnect.keepalive_interval"]; ok {
 		cfg.Reconnect.KeepAliveInterval = core.Duration{Duration: d}
 	}
+	if r.KeepAliveCountMax != nil {
+		cfg.Reconnect.KeepAliveCountMax = *r.KeepAliveCountMax
+	}
 }
 
 func applyHosts(cfg *core.Config, hosts map[string]*protocol.HostConfigUpdateInfo, durations parsedDurations) {
@@ -81,8 +81,8 @@ func TestGet(t *testing.T) {
 	if cfgResult.Log.Level != "info" {
 		t.Errorf("Log.Level = %q, want %q", cfgResult.Log.Level, "info")
 	}
-	if cfgResult.Reconnect.KeepAliveInterval != "30s" {
-		t.Errorf("Reconnect.KeepAliveInterval = %q, want %q", cfgResult.Reconnect.KeepAliveInterval, "30s")
+	if cfgResult.Reconnect.KeepAliveInterval != "30s" || cfgResult.Reconnect.KeepAliveCountMax != 3 {
+		t.Errorf("Reconnect keepalive = (%q, %d), want (30s, 3)", cfgResult.Reconnect.KeepAliveInterval, cfgResult.Reconnect.KeepAliveCountMax)
 	}
 	// デフォルトでは Language は空文字列
 	if cfgResult.Language != "" {
@@ -179,10 +179,11 @@ func TestGet_WithHosts(t *testing.T) {
 func TestUpdate_KeepAliveInterval(t *testing.T) {
 	h, cfgMgr := newTestHandler()
 
-	interval := "45s"
+	interval, count := "45s", 5
 	params := mustMarshal(t, protocol.ConfigUpdateParams{
 		Reconnect: &protocol.ReconnectUpdateInfo{
 			KeepAliveInterval: &interval,
+			KeepAliveCountMax: &count,
 		},
 	})
 
@@ -197,8 +198,8 @@ func TestUpdate_KeepAliveInterval(t *testing.T) {
 	}
 
 	cfg := cfgMgr.GetConfig()
-	if cfg.Reconnect.KeepAliveInterval.Duration != 45*time.Second {
-		t.Errorf("KeepAliveInterval = %v, want 45s", cfg.Reconnect.KeepAliveInterval.Duration)
+	if cfg.Reconnect.KeepAliveInterval.Duration != 45*time.Second || cfg.Reconnect.KeepAliveCountMax != 5 {
+		t.Errorf("keepalive = (%v, %d), want (45s, 5)", cfg.Reconnect.KeepAliveInterval.Duration, cfg.Reconnect.KeepAliveCountMax)
 	}
 }
 
@@ -0,0 +1,104 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/themeconf"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/themeinfo"
+)
+
+func TestGet_UITimeFormat(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+
+	cfg := core.DefaultConfig()
+	cfg.UI.TimeFormat = core.TimeFormatUTC
+	cfgMgr.config = &cfg
+
+	result, rpcErr := h.Get()
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+
+	cfgResult := result.(protocol.ConfigGetResult)
+	if cfgResult.UI.TimeFormat != core.TimeFormatUTC {
+		t.Errorf("UI.TimeFormat = %q, want %q", cfgResult.UI.TimeFormat, core.TimeFormatUTC)
+	}
+}
+
+func TestUpdate_UITimeFormat(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+
+	tf := core.TimeFormatLocal
+	params := mustMarshal(t, protocol.ConfigUpdateParams{
+		UI: &protocol.UIUpdateInfo{TimeFormat: &tf},
+	})
+
+	if _, rpcErr := h.Update(params); rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if got := cfgMgr.GetConfig().UI.TimeFormat; got != core.TimeFormatLocal {
+		t.Errorf("UI.TimeFormat = %q, want %q", got, core.TimeFormatLocal)
+	}
+}
+
+func TestUpdate_UITimeFormat_Invalid(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+
+	tf := "iso"
+	params := mustMarshal(t, protocol.ConfigUpdateParams{
+		UI: &protocol.UIUpdateInfo{TimeFormat: &tf},
+	})
+
+	_, rpcErr := h.Update(params)
+	if rpcErr == nil {
+		t.Fatal("expected error for invalid time_format")
+	}
+	if rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("code = %d, want %d", rpcErr.Code, protocol.InvalidParams)
+	}
+	if got := cfgMgr.GetConfig().UI.TimeFormat; got != core.TimeFormatRelative {
+		t.Errorf("UI.TimeFormat = %q, want unchanged %q", got, core.TimeFormatRelative)
+	}
+}
+
+func TestGetUpdate_TUILayout(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+
+	percent, hideLog := 60, true
+	params := mustMarshal(t, protocol.ConfigUpdateParams{
+		TUI: &protocol.TUIUpdateInfo{Layout: &protocol.LayoutUpdateInfo{ForwardPercent: &percent, HideLog: &hideLog}},
+	})
+	if _, rpcErr := h.Update(params); rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if got := cfgMgr.GetConfig().TUI.Layout; got.ForwardPercent != 60 || !got.HideLog {
+		t.Errorf("TUI.Layout = %+v, want forward_percent=60 hide_log=true", got)
+	}
+
+	result, rpcErr := h.Get()
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if got := result.(protocol.ConfigGetResult).TUI.Layout; got != (protocol.LayoutInfo{ForwardPercent: 60, HideLog: true}) {
+		t.Errorf("TUI.Layout = %+v", got)
+	}
+}
+
+func TestGet_UserThemes(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+	cfg := core.DefaultConfig()
+	cfg.TUI.Themes = []themeconf.UserTheme{{Name: "ocean", Base: "dark", Colors: themeconf.Colors{Accent: "#0EA5E9"}}}
+	cfgMgr.config = &cfg
+
+	result, rpcErr := h.Get()
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	want := []themeinfo.UserTheme{{Name: "ocean", Base: "dark", Colors: themeinfo.Colors{Accent: "#0EA5E9"}}}
+	if got := result.(protocol.ConfigGetResult).TUI.Themes; !reflect.DeepEqual(got, want) {
+		t.Errorf("TUI.Themes = %+v, want %+v", got, want)
+	}
+}
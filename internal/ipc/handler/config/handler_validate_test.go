@@ -3,6 +3,7 @@ package config
 import (
 	"testing"
 
+	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
@@ -77,3 +78,43 @@ func TestUpdate_InvalidDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestValidate(t *testing.T) {
+	h, _ := newTestHandler()
+	tests := []struct {
+		name      string
+		data      string
+		wantValid bool
+		wantField string
+	}{
+		{"valid", "schema_version: 1\nlog:\n  level: debug\n", true, ""},
+		{"invalid level", "log:\n  level: verbose\n", false, "log.level"},
+		{"negative retries", "reconnect:\n  max_retries: -1\n", false, "reconnect.max_retries"},
+		{"malformed yaml", "log: [\n", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, rpcErr := h.Validate(mustMarshal(t, protocol.ConfigValidateParams{Data: tt.data}))
+			if rpcErr != nil {
+				t.Fatalf("Validate() error = %v", rpcErr)
+			}
+			got := res.(protocol.ConfigValidateResult)
+			if got.Valid != tt.wantValid {
+				t.Fatalf("Valid = %v, want %v (issues %+v)", got.Valid, tt.wantValid, got.Issues)
+			}
+			if !tt.wantValid && (len(got.Issues) != 1 || got.Issues[0].Field != tt.wantField) {
+				t.Errorf("Issues = %+v, want one issue for %q", got.Issues, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestUpdate_RejectedByValidation(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+	cfgMgr.err = &core.InvalidConfigError{Issues: []core.ConfigIssue{{Field: "log.level", Message: "invalid value"}}}
+
+	_, rpcErr := h.Update(mustMarshal(t, protocol.ConfigUpdateParams{Log: &protocol.LogUpdateInfo{Level: strPtr("verbose")}}))
+	if rpcErr == nil || rpcErr.Code != protocol.InvalidConfig {
+		t.Fatalf("Update() error = %v, want InvalidConfig", rpcErr)
+	}
+}
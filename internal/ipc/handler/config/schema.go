@@ -0,0 +1,146 @@
+package config
+
+import (
+	"cmp"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// baseSchemaVersion は schema タグで since を指定していない設定項目のバージョン。
+const baseSchemaVersion = "1.0.0"
+
+var durationType = reflect.TypeFor[core.Duration]()
+
+// yamlMarshaler を実装する型（core.ForwardType 等）は YAML 上で文字列として表現される。
+type yamlMarshaler interface {
+	MarshalYAML() (any, error)
+}
+
+// Schema は config.schema リクエストを処理する。
+func (h *Handler) Schema() (any, *protocol.RPCError) {
+	return protocol.ConfigSchemaResult{Fields: configSchema()}, nil
+}
+
+// configSchema は core.Config の yaml・schema タグと core.DefaultConfig の値から設定項目のスキーマを生成する。
+func configSchema() []protocol.ConfigFieldSchema {
+	var fields []protocol.ConfigFieldSchema
+	walkSchema(&fields, reflect.TypeFor[core.Config](), reflect.ValueOf(core.DefaultConfig()), "", baseSchemaVersion)
+	return fields
+}
+
+// walkSchema は構造体 t のフィールドのスキーマを fields に追加する。
+// def は t の既定値で、リストやマップの要素のように既定値がない場合は無効な Value を渡す。
+func walkSchema(fields *[]protocol.ConfigFieldSchema, t reflect.Type, def reflect.Value, prefix, since string) {
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		name, inline := core.SchemaKey(sf)
+		if !sf.IsExported() || name == "-" {
+			continue
+		}
+		tags := core.ParseSchemaTag(sf.Tag.Get("schema"))
+		fieldSince := cmp.Or(tags["since"], since)
+		var fv reflect.Value
+		if def.IsValid() {
+			fv = def.Field(i)
+		}
+		if inline {
+			walkSchema(fields, sf.Type, fv, prefix, fieldSince)
+			continue
+		}
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft, fv = ft.Elem(), reflect.Value{}
+		}
+		path := prefix + name
+		f := protocol.ConfigFieldSchema{Path: path, Type: schemaType(ft), Since: fieldSince}
+		switch f.Type {
+		case "object":
+			*fields = append(*fields, f)
+			walkSchema(fields, ft, fv, path+".", fieldSince)
+			continue
+		case "list", "map":
+			f.Item = schemaType(ft.Elem())
+			*fields = append(*fields, f)
+			if f.Item == "object" {
+				elemPrefix := path + "[]."
+				if f.Type == "map" {
+					elemPrefix = path + ".*."
+				}
+				walkSchema(fields, ft.Elem(), reflect.Value{}, elemPrefix, fieldSince)
+			}
+			continue
+		}
+
+		f.Default = defaultValue(fv, tags["default"], f.Type)
+		f.Min = typedValue(tags["min"], f.Type)
+		f.Max = typedValue(tags["max"], f.Type)
+		if enum := tags["enum"]; enum != "" {
+			f.Enum = strings.Split(enum, "|")
+		}
+		*fields = append(*fields, f)
+	}
+}
+
+// schemaType は Go の型を config.schema の型名に変換する。
+func schemaType(t reflect.Type) string {
+	switch {
+	case t == durationType:
+		return "duration"
+	case t.Implements(reflect.TypeFor[yamlMarshaler]()):
+		return "string"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "int"
+	case reflect.Struct:
+		return "object"
+	case reflect.Slice:
+		return "list"
+	case reflect.Map:
+		return "map"
+	default:
+		return "string"
+	}
+}
+
+// defaultValue は既定値を返す。DefaultConfig の値がゼロ値の場合は、実行時に適用される schema タグの default を使う。
+func defaultValue(fv reflect.Value, tagDefault, typ string) any {
+	if !fv.IsValid() || fv.IsZero() {
+		if tagDefault != "" {
+			return typedValue(tagDefault, typ)
+		}
+		if !fv.IsValid() || typ == "string" || typ == "duration" {
+			return nil
+		}
+	}
+	switch typ {
+	case "duration":
+		return fv.Interface().(core.Duration).String() // safe: schemaType は core.Duration に対してのみ "duration" を返す
+	case "bool":
+		return fv.Bool()
+	case "int":
+		return int(fv.Int())
+	default:
+		return fv.String()
+	}
+}
+
+// typedValue は schema タグの値を型に応じて変換する。int 以外はそのまま文字列で返す。
+func typedValue(s, typ string) any {
+	if s == "" {
+		return nil
+	}
+	if typ == "int" {
+		if n, err := strconv.Atoi(s); err == nil {
+			return n
+		}
+	}
+	return s
+}
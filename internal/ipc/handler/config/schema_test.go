@@ -0,0 +1,55 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestSchema(t *testing.T) {
+	h, _ := newTestHandler()
+
+	result, rpcErr := h.Schema()
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	fields := result.(protocol.ConfigSchemaResult).Fields
+
+	byPath := make(map[string]protocol.ConfigFieldSchema, len(fields))
+	for _, f := range fields {
+		if _, dup := byPath[f.Path]; dup {
+			t.Errorf("duplicate path %q", f.Path)
+		}
+		if f.Since == "" {
+			t.Errorf("%s: Since is empty", f.Path)
+		}
+		byPath[f.Path] = f
+	}
+
+	tests := []protocol.ConfigFieldSchema{
+		{Path: "reconnect.max_delay", Type: "duration", Default: "1m0s", Since: "1.0.0"},
+		{Path: "reconnect.max_retries", Type: "int", Default: 10, Min: 0, Since: "1.0.0"},
+		{Path: "forwards", Type: "list", Item: "object", Since: "1.0.0"},
+		{Path: "forwards[].type", Type: "string", Enum: []string{"local", "remote", "dynamic"}, Since: "1.0.0"},
+		{Path: "forwards[].local_port", Type: "int", Min: 0, Max: 65535, Since: "1.0.0"},
+		{Path: "forwards[].auto_reconnect", Type: "bool", Since: "1.1.0"},
+		{Path: "hosts", Type: "map", Item: "object", Since: "1.0.0"},
+		{Path: "hosts.*.connect_timeout", Type: "duration", Since: "1.1.0"},
+		{Path: "hosts.*.reconnect.max_retries", Type: "int", Min: 0, Since: "1.0.0"},
+		{Path: "webhooks[].max_retries", Type: "int", Default: 3, Min: 0, Since: "1.1.0"},
+		{Path: "health_check.interval", Type: "duration", Default: "5m", Min: "30s", Since: "1.1.0"},
+		{Path: "ignored_imports", Type: "list", Item: "string", Since: "1.1.0"},
+	}
+	for _, want := range tests {
+		t.Run(want.Path, func(t *testing.T) {
+			got, ok := byPath[want.Path]
+			if !ok {
+				t.Fatalf("path %q not found", want.Path)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	coreconfig "github.com/ousiassllc/moleport/internal/core/config"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// Validate は config.validate リクエストを処理する。
+// Data が空の場合はデーモンの config.yaml を読み直して検証する。ファイルがない場合は既定値のため有効とする。
+func (h *Handler) Validate(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.ConfigValidateParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+
+	data := []byte(p.Data)
+	if p.Data == "" {
+		var err error
+		data, err = os.ReadFile(filepath.Join(h.cfgMgr.ConfigDir(), "config.yaml"))
+		if errors.Is(err, fs.ErrNotExist) {
+			return protocol.ConfigValidateResult{Valid: true}, nil
+		}
+		if err != nil {
+			return nil, rpcerr.From(err, protocol.InternalError)
+		}
+	}
+	if _, err := coreconfig.Parse(data); err != nil {
+		return protocol.ConfigValidateResult{Issues: rpcerr.ConfigIssues(err)}, nil
+	}
+	return protocol.ConfigValidateResult{Valid: true}, nil
+}
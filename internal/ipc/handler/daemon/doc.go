@@ -0,0 +1,2 @@
+// Package daemon はデーモン管理リクエスト（daemon.*）のハンドラを提供する。
+package daemon
@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// Daemon はデーモンの状態情報・稼働状況とシャットダウンを提供する。
+type Daemon interface {
+	Status() protocol.DaemonStatusResult
+	// Health は profile のランタイムを対象に稼働状況を返す。require のルールは active であることを確認する。
+	Health(profile string, require []string) protocol.DaemonHealthResult
+	Shutdown(purge bool) error
+}
+
+// Handler はデーモン管理の JSON-RPC メソッドを処理する。
+type Handler struct {
+	daemon Daemon
+}
+
+// New は新しいデーモン管理ハンドラを生成する。daemon が nil の場合、各メソッドは InternalError を返す。
+func New(daemon Daemon) *Handler {
+	return &Handler{daemon: daemon}
+}
+
+// Handle は daemon.* メソッドをディスパッチする。
+func (h *Handler) Handle(method string, params json.RawMessage) (any, *protocol.RPCError) {
+	if h.daemon == nil {
+		return nil, &protocol.RPCError{Code: protocol.InternalError, Message: "daemon not available"}
+	}
+	switch method {
+	case protocol.MethodDaemonHello:
+		return h.hello(params)
+	case "daemon.status":
+		return h.daemon.Status(), nil
+	case "daemon.health":
+		return h.health(params)
+	case "daemon.shutdown":
+		return h.shutdown(params)
+	default:
+		return nil, &protocol.RPCError{Code: protocol.MethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+// hello は daemon.hello を処理し、デーモンのプロトコルバージョン・バージョン・機能フラグを返す。
+// 互換性の判断はクライアントが行い、デーモンはクライアントのバージョンにかかわらず応答する。
+func (h *Handler) hello(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.DaemonHelloParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+	if !protocol.Compatible(p.ProtocolVersion, protocol.MinProtocolVersion) {
+		slog.Warn("client uses an incompatible IPC protocol", "client_protocol", p.ProtocolVersion, "client_version", p.ClientVersion)
+	}
+	return protocol.DaemonHelloResult{
+		ProtocolVersion:    protocol.ProtocolVersion,
+		MinProtocolVersion: protocol.MinProtocolVersion,
+		ServerVersion:      h.daemon.Status().Version,
+		Capabilities:       protocol.Capabilities(),
+	}, nil
+}
+
+func (h *Handler) health(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.DaemonHealthParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+	return h.daemon.Health(protocol.ProfileOf(params), p.Forwards), nil
+}
+
+func (h *Handler) shutdown(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.DaemonShutdownParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			slog.Debug("daemonShutdown: invalid params, using defaults", "error", err)
+		}
+	}
+
+	if err := h.daemon.Shutdown(p.Purge); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	return protocol.DaemonShutdownResult{OK: true}, nil
+}
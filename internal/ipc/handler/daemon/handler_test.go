@@ -0,0 +1,110 @@
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type mockDaemon struct {
+	shutdownErr   error
+	lastPurgeFlag bool
+	healthArgs    []string
+}
+
+func (m *mockDaemon) Status() protocol.DaemonStatusResult {
+	return protocol.DaemonStatusResult{Version: "test"}
+}
+
+func (m *mockDaemon) Health(profile string, require []string) protocol.DaemonHealthResult {
+	m.healthArgs = append([]string{profile}, require...)
+	return protocol.DaemonHealthResult{Status: protocol.HealthWarn}
+}
+
+func (m *mockDaemon) Shutdown(purge bool) error {
+	m.lastPurgeFlag = purge
+	return m.shutdownErr
+}
+
+func TestHandle_NilDaemon(t *testing.T) {
+	for _, method := range []string{protocol.MethodDaemonHello, "daemon.status", "daemon.health", "daemon.shutdown"} {
+		_, rpcErr := New(nil).Handle(method, nil)
+		if rpcErr == nil || rpcErr.Code != protocol.InternalError {
+			t.Errorf("%s: rpcErr = %v, want InternalError", method, rpcErr)
+		}
+	}
+}
+
+func TestHandle_Health(t *testing.T) {
+	d := &mockDaemon{}
+	h := New(d)
+
+	result, rpcErr := h.Handle("daemon.health", json.RawMessage(`{"profile":"work","forwards":["db"]}`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if r, ok := result.(protocol.DaemonHealthResult); !ok || r.Status != protocol.HealthWarn {
+		t.Errorf("result = %#v, want the daemon's health", result)
+	}
+	if len(d.healthArgs) != 2 || d.healthArgs[0] != "work" || d.healthArgs[1] != "db" {
+		t.Errorf("Health args = %v, want [work db]", d.healthArgs)
+	}
+
+	// profile を省略した場合は既定プロファイルを対象にする
+	if _, rpcErr := h.Handle("daemon.health", nil); rpcErr != nil || d.healthArgs[0] != protocol.DefaultProfile {
+		t.Errorf("rpcErr = %v, args = %v; want default profile", rpcErr, d.healthArgs)
+	}
+	if _, rpcErr := h.Handle("daemon.health", json.RawMessage(`{"forwards":"db"}`)); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("rpcErr = %v, want InvalidParams", rpcErr)
+	}
+}
+
+func TestHandle_Shutdown(t *testing.T) {
+	d := &mockDaemon{}
+	h := New(d)
+
+	result, rpcErr := h.Handle("daemon.shutdown", nil)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if r, ok := result.(protocol.DaemonShutdownResult); !ok || !r.OK {
+		t.Errorf("result = %#v, want OK", result)
+	}
+	if d.lastPurgeFlag {
+		t.Error("Shutdown should have been called with purge=false")
+	}
+
+	params, _ := json.Marshal(protocol.DaemonShutdownParams{Purge: true})
+	if _, rpcErr := h.Handle("daemon.shutdown", params); rpcErr != nil || !d.lastPurgeFlag {
+		t.Errorf("rpcErr = %v, purge = %v; want purge=true", rpcErr, d.lastPurgeFlag)
+	}
+
+	d.shutdownErr = errors.New("busy")
+	if _, rpcErr := h.Handle("daemon.shutdown", nil); rpcErr == nil || rpcErr.Code != protocol.InternalError {
+		t.Errorf("rpcErr = %v, want InternalError", rpcErr)
+	}
+}
+
+func TestHandle_UnknownMethod(t *testing.T) {
+	if _, rpcErr := New(&mockDaemon{}).Handle("daemon.unknown", nil); rpcErr == nil || rpcErr.Code != protocol.MethodNotFound {
+		t.Errorf("rpcErr = %v, want MethodNotFound", rpcErr)
+	}
+}
+
+func TestHandle_Hello(t *testing.T) {
+	h := New(&mockDaemon{})
+
+	result, rpcErr := h.Handle(protocol.MethodDaemonHello, json.RawMessage(`{"protocol_version":2,"client_version":"v1.2.0"}`))
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	r, ok := result.(protocol.DaemonHelloResult)
+	if !ok || r.ProtocolVersion != protocol.ProtocolVersion || r.ServerVersion != "test" || !r.HasCapability(protocol.CapabilityBatch) {
+		t.Errorf("result = %#v, want the protocol version, the daemon version and capabilities", result)
+	}
+	if _, rpcErr := h.Handle(protocol.MethodDaemonHello, json.RawMessage(`{"protocol_version":"2"}`)); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("rpcErr = %v, want InvalidParams", rpcErr)
+	}
+}
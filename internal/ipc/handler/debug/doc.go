@@ -0,0 +1,4 @@
+// Package debug はデバッグ用リクエスト（debug.*）のハンドラを提供する。
+//
+// 障害注入は chaos ビルドタグ付きでビルドしたデーモンでのみ利用できる。
+package debug
@@ -0,0 +1,81 @@
+package debug
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/faultinject"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Faults は debug.faults リクエストを処理する。
+// params が空の場合は現在の設定とカウンターを返す。
+func Faults(params json.RawMessage) (any, *protocol.RPCError) {
+	if !faultinject.Available {
+		return nil, &protocol.RPCError{Code: protocol.MethodNotFound, Message: faultinject.ErrUnavailable.Error()}
+	}
+
+	if len(params) > 0 && string(params) != "null" {
+		var p protocol.DebugFaultsParams
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+		cfg, err := applyParams(faultinject.Current(), p)
+		if err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+		}
+		if err := faultinject.Configure(cfg); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+		}
+	}
+
+	cfg := faultinject.Current()
+	return protocol.DebugFaultsResult{
+		Config: protocol.FaultConfigInfo{
+			Enabled:                cfg.Enabled,
+			DropProbability:        cfg.DropProbability,
+			DropInterval:           cfg.DropInterval.String(),
+			AcceptDelay:            cfg.AcceptDelay.String(),
+			DialFailureProbability: cfg.DialFailureProbability,
+		},
+		Counters: CountersInfo(),
+	}, nil
+}
+
+// CountersInfo は現在の障害注入カウンターを返す。
+func CountersInfo() protocol.FaultCountersInfo {
+	c := faultinject.Stats()
+	return protocol.FaultCountersInfo{
+		DroppedConnections: c.DroppedConnections,
+		DelayedAccepts:     c.DelayedAccepts,
+		FailedDials:        c.FailedDials,
+	}
+}
+
+// applyParams は指定されたフィールドのみを cfg に上書きする。
+func applyParams(cfg faultinject.Config, p protocol.DebugFaultsParams) (faultinject.Config, error) {
+	if p.Enabled != nil {
+		cfg.Enabled = *p.Enabled
+	}
+	if p.DropProbability != nil {
+		cfg.DropProbability = *p.DropProbability
+	}
+	if p.DialFailureProbability != nil {
+		cfg.DialFailureProbability = *p.DialFailureProbability
+	}
+	if p.DropInterval != nil {
+		d, err := time.ParseDuration(*p.DropInterval)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.DropInterval = d
+	}
+	if p.AcceptDelay != nil {
+		d, err := time.ParseDuration(*p.AcceptDelay)
+		if err != nil {
+			return cfg, err
+		}
+		cfg.AcceptDelay = d
+	}
+	return cfg, cfg.Validate()
+}
@@ -0,0 +1,77 @@
+package debug
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/faultinject"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestFaults_Unavailable(t *testing.T) {
+	if faultinject.Available {
+		t.Skip("chaos build")
+	}
+	_, rpcErr := Faults(json.RawMessage(`{"enabled":true}`))
+	if rpcErr == nil || rpcErr.Code != protocol.MethodNotFound {
+		t.Fatalf("Faults() error = %v, want MethodNotFound", rpcErr)
+	}
+}
+
+func TestFaults_Configure(t *testing.T) {
+	if !faultinject.Available {
+		t.Skip("requires -tags chaos")
+	}
+	t.Cleanup(func() { _ = faultinject.Configure(faultinject.Config{}) })
+
+	got, rpcErr := Faults(json.RawMessage(`{"enabled":true,"accept_delay":"50ms","dial_failure_probability":0.25}`))
+	if rpcErr != nil {
+		t.Fatalf("Faults() error = %v", rpcErr)
+	}
+	res := got.(protocol.DebugFaultsResult)
+	if !res.Config.Enabled || res.Config.AcceptDelay != "50ms" || res.Config.DialFailureProbability != 0.25 {
+		t.Errorf("Config = %+v", res.Config)
+	}
+
+	// 空パラメータは現在の設定を返すのみ
+	got, rpcErr = Faults(nil)
+	if rpcErr != nil {
+		t.Fatalf("Faults(nil) error = %v", rpcErr)
+	}
+	if res := got.(protocol.DebugFaultsResult); !res.Config.Enabled {
+		t.Error("Faults(nil) should keep current config")
+	}
+}
+
+func TestFaults_InvalidParams(t *testing.T) {
+	if !faultinject.Available {
+		t.Skip("requires -tags chaos")
+	}
+	t.Cleanup(func() { _ = faultinject.Configure(faultinject.Config{}) })
+
+	for _, params := range []string{
+		`{"drop_probability":2}`,
+		`{"accept_delay":"soon"}`,
+		`{"drop_interval":"-1s"}`,
+		`not json`,
+	} {
+		if _, rpcErr := Faults(json.RawMessage(params)); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+			t.Errorf("Faults(%s) error = %v, want InvalidParams", params, rpcErr)
+		}
+	}
+}
+
+func TestApplyParams_KeepsUnsetFields(t *testing.T) {
+	base := faultinject.Config{Enabled: true, DropProbability: 0.1, DropInterval: time.Second}
+	p := 0.5
+	got, err := applyParams(base, protocol.DebugFaultsParams{DialFailureProbability: &p})
+	if err != nil {
+		t.Fatalf("applyParams() error = %v", err)
+	}
+	want := base
+	want.DialFailureProbability = 0.5
+	if got != want {
+		t.Errorf("applyParams() = %+v, want %+v", got, want)
+	}
+}
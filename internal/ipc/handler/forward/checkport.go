@@ -0,0 +1,35 @@
+package forward
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// CheckPort は forward.checkPort リクエストを処理する。
+// ポートが使用中の場合もエラーにせず、使用中のルール名と空いているポートの候補を結果に含める。
+func (h *Handler) CheckPort(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.ForwardCheckPortParams
+	if len(params) == 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if p.Port < 1 || p.Port > 65535 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "port must be between 1 and 65535"}
+	}
+
+	err := h.fwdMgr.CheckPort(p.Port)
+	if err == nil {
+		return protocol.ForwardCheckPortResult{Port: p.Port, Available: true}, nil
+	}
+	var conflict *core.PortConflictError
+	if !errors.As(err, &conflict) {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	return protocol.ForwardCheckPortResult{Port: p.Port, Rule: conflict.Rule, Suggestion: conflict.Suggestion}, nil
+}
@@ -0,0 +1,48 @@
+package forward
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestCheckPort(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  string
+		portErr error
+		want    protocol.ForwardCheckPortResult
+		wantErr int
+	}{
+		{name: "available", params: `{"port":8080}`, want: protocol.ForwardCheckPortResult{Port: 8080, Available: true}},
+		{
+			name:    "conflict",
+			params:  `{"port":8080}`,
+			portErr: &core.PortConflictError{Port: 8080, Rule: "web", Suggestion: 8081},
+			want:    protocol.ForwardCheckPortResult{Port: 8080, Rule: "web", Suggestion: 8081},
+		},
+		{name: "out of range", params: `{"port":70000}`, wantErr: protocol.InvalidParams},
+		{name: "missing port", params: `{}`, wantErr: protocol.InvalidParams},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fwd, _ := newTestHandler()
+			fwd.portErr = tt.portErr
+			got, rpcErr := h.CheckPort(json.RawMessage(tt.params))
+			if tt.wantErr != 0 {
+				if rpcErr == nil || rpcErr.Code != tt.wantErr {
+					t.Fatalf("error = %v, want code %d", rpcErr, tt.wantErr)
+				}
+				return
+			}
+			if rpcErr != nil {
+				t.Fatalf("unexpected error: %v", rpcErr)
+			}
+			if got != tt.want {
+				t.Errorf("result = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,3 @@
+// Package forward はフォワードルールの移行（forward.migrate）・更新（forward.update）・書き出し（forward.export）・取り込み（forward.import）、
+// ローカルポートの確認（forward.checkPort）、帯域上限の変更（forward.setLimit）のハンドラを提供する。
+package forward
@@ -0,0 +1,33 @@
+package forward
+
+import (
+	"encoding/json"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// SetLimit は forward.setLimit リクエストを処理する。
+// ルールの帯域上限を変更して転送中の接続にも反映し、書き換えたルールを設定ファイルに保存する。
+func (h *Handler) SetLimit(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.ForwardSetLimitParams
+	if len(params) == 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if p.Name == "" {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "name is required"}
+	}
+	if p.MaxUploadKbps < 0 || p.MaxDownloadKbps < 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "bandwidth limits must not be negative"}
+	}
+
+	if err := h.fwdMgr.SetLimit(p.Name, p.MaxUploadKbps, p.MaxDownloadKbps); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+
+	h.saveRules()
+	return protocol.ForwardSetLimitResult(p), nil
+}
@@ -0,0 +1,43 @@
+package forward
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestSetLimit(t *testing.T) {
+	h, _, cfg := newTestHandler()
+	res, rpcErr := h.SetLimit(json.RawMessage(`{"name":"db","max_upload_kbps":512,"max_download_kbps":0}`))
+	if rpcErr != nil {
+		t.Fatalf("SetLimit: %v", rpcErr)
+	}
+	if want := (protocol.ForwardSetLimitResult{Name: "db", MaxUploadKbps: 512}); res != want {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+	if len(cfg.config.Forwards) != 1 || cfg.config.Forwards[0].MaxUploadKbps != 512 {
+		t.Errorf("saved forwards = %+v, want upload limit 512", cfg.config.Forwards)
+	}
+}
+
+func TestSetLimit_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   json.RawMessage
+		wantCode int
+	}{
+		{"missing params", nil, protocol.InvalidParams},
+		{"missing name", json.RawMessage(`{"max_upload_kbps":1}`), protocol.InvalidParams},
+		{"negative limit", json.RawMessage(`{"name":"db","max_download_kbps":-1}`), protocol.InvalidParams},
+		{"unknown rule", json.RawMessage(`{"name":"nope"}`), protocol.RuleNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newTestHandler()
+			if _, rpcErr := h.SetLimit(tt.params); rpcErr == nil || rpcErr.Code != tt.wantCode {
+				t.Errorf("rpcErr = %v, want code %d", rpcErr, tt.wantCode)
+			}
+		})
+	}
+}
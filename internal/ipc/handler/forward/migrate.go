@@ -0,0 +1,97 @@
+package forward
+
+import (
+	"encoding/json"
+	"log/slog"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// HostLookup は移行先ホストの存在確認に使う。core.SSHManager が満たす。
+type HostLookup interface {
+	GetHost(name string) (*core.SSHHost, error)
+}
+
+// Manager はフォワードの移行・更新、ルールの取得・追加、ポートの確認、帯域上限の変更を提供する。core.ForwardManager が満たす。
+type Manager interface {
+	CheckPort(port int) error
+	SetLimit(ruleName string, uploadKbps, downloadKbps int) error
+	GetSession(ruleName string) (*core.ForwardSession, error)
+	GetRules() []core.ForwardRule
+	AddRule(rule core.ForwardRule) (string, error)
+	MigrateForward(ruleName, toHost string, cb core.CredentialCallback) (*core.ForwardSession, error)
+	UpdateRule(rule core.ForwardRule, cb core.CredentialCallback) (*core.ForwardSession, error)
+}
+
+// CredentialCallbackFunc は接続先ホストに応じたクレデンシャルコールバックを返す。
+type CredentialCallbackFunc func(hostName string) core.CredentialCallback
+
+// Handler はフォワードの移行・更新・書き出し・取り込みとポート確認の JSON-RPC メソッドを処理する。
+type Handler struct {
+	hosts  HostLookup
+	fwdMgr Manager
+	cfgMgr core.ConfigManager
+}
+
+// New は新しいフォワードハンドラを生成する。
+func New(hosts HostLookup, fwdMgr Manager, cfgMgr core.ConfigManager) *Handler {
+	return &Handler{hosts: hosts, fwdMgr: fwdMgr, cfgMgr: cfgMgr}
+}
+
+// Migrate は forward.migrate リクエストを処理する。
+// アクティブなセッションを移行先ホストで再開し、書き換えたルールを設定ファイルに保存する。
+func (h *Handler) Migrate(params json.RawMessage, credCb CredentialCallbackFunc) (any, *protocol.RPCError) {
+	var p protocol.ForwardMigrateParams
+	if len(params) == 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if p.Name == "" {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "name is required"}
+	}
+	if p.To == "" {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "to is required"}
+	}
+
+	current, err := h.fwdMgr.GetSession(p.Name)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	if current.Rule.Host == p.To {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "rule is already on host " + p.To}
+	}
+	if _, err := h.hosts.GetHost(p.To); err != nil {
+		return nil, rpcerr.From(err, protocol.HostNotFound)
+	}
+
+	var cb core.CredentialCallback
+	if credCb != nil {
+		cb = credCb(p.To)
+	}
+	session, err := h.fwdMgr.MigrateForward(p.Name, p.To, cb)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+
+	h.saveRules()
+
+	info := convert.ToSessionInfo(*session)
+	return protocol.ForwardMigrateResult{
+		Name:     p.Name,
+		FromHost: current.Rule.Host,
+		Host:     session.Rule.Host,
+		Status:   info.Status,
+	}, nil
+}
+
+// saveRules はフォワードルールを設定ファイルに保存する。保存に失敗した場合はログに記録する。
+func (h *Handler) saveRules() {
+	if err := core.SaveRules(h.cfgMgr, h.fwdMgr.GetRules()); err != nil {
+		slog.Warn("failed to save forward rules to config", "error", err)
+	}
+}
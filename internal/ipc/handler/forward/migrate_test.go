@@ -0,0 +1,154 @@
+package forward
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type mockHosts struct{}
+
+func (mockHosts) GetHost(name string) (*core.SSHHost, error) {
+	if name == "bastion1" || name == "bastion2" {
+		return &core.SSHHost{Name: name}, nil
+	}
+	return nil, &core.NotFoundError{Resource: "host", Name: name}
+}
+
+type mockManager struct {
+	rule       core.ForwardRule
+	migrateErr error
+	updateErr  error
+	addErr     error
+	added      []core.ForwardRule
+	portErr    error
+	gotCb      core.CredentialCallback
+}
+
+func (m *mockManager) SetLimit(ruleName string, up, down int) error {
+	if ruleName != m.rule.Name {
+		return &core.NotFoundError{Resource: "rule", Name: ruleName}
+	}
+	m.rule.MaxUploadKbps, m.rule.MaxDownloadKbps = up, down
+	return nil
+}
+
+func (m *mockManager) GetSession(ruleName string) (*core.ForwardSession, error) {
+	if ruleName != m.rule.Name {
+		return nil, &core.NotFoundError{Resource: "rule", Name: ruleName}
+	}
+	return &core.ForwardSession{Rule: m.rule, Status: core.Active}, nil
+}
+
+func (m *mockManager) CheckPort(int) error { return m.portErr }
+
+func (m *mockManager) GetRules() []core.ForwardRule {
+	return append([]core.ForwardRule{m.rule}, m.added...)
+}
+
+func (m *mockManager) AddRule(rule core.ForwardRule) (string, error) {
+	if m.addErr != nil {
+		return "", m.addErr
+	}
+	m.added = append(m.added, rule)
+	return rule.Name, nil
+}
+
+func (m *mockManager) MigrateForward(_, toHost string, cb core.CredentialCallback) (*core.ForwardSession, error) {
+	if m.migrateErr != nil {
+		return nil, m.migrateErr
+	}
+	m.gotCb = cb
+	m.rule.Host = toHost
+	return &core.ForwardSession{Rule: m.rule, Status: core.Active}, nil
+}
+
+func (m *mockManager) UpdateRule(rule core.ForwardRule, cb core.CredentialCallback) (*core.ForwardSession, error) {
+	if m.updateErr != nil {
+		return nil, m.updateErr
+	}
+	m.gotCb = cb
+	m.rule = rule
+	return &core.ForwardSession{Rule: m.rule, Status: core.Active}, nil
+}
+
+type mockConfigManager struct {
+	core.ConfigManager
+	config core.Config
+}
+
+func (m *mockConfigManager) GetConfig() *core.Config { return &m.config }
+
+func (m *mockConfigManager) UpdateConfig(fn func(*core.Config)) error {
+	fn(&m.config)
+	return nil
+}
+
+func newTestHandler() (*Handler, *mockManager, *mockConfigManager) {
+	fwd := &mockManager{rule: core.ForwardRule{Name: "db", Host: "bastion1", Type: core.Local, LocalPort: 15432, RemoteHost: "localhost", RemotePort: 5432}}
+	cfg := &mockConfigManager{}
+	return New(mockHosts{}, fwd, cfg), fwd, cfg
+}
+
+func migrateParams(t *testing.T, name, to string) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(protocol.ForwardMigrateParams{Name: name, To: to})
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	return data
+}
+
+func TestMigrate_Success(t *testing.T) {
+	h, fwd, cfg := newTestHandler()
+	cb := func(core.CredentialRequest) (core.CredentialResponse, error) { return core.CredentialResponse{}, nil }
+	var cbHost string
+
+	res, rpcErr := h.Migrate(migrateParams(t, "db", "bastion2"), func(host string) core.CredentialCallback {
+		cbHost = host
+		return cb
+	})
+	if rpcErr != nil {
+		t.Fatalf("Migrate: %v", rpcErr)
+	}
+	got := res.(protocol.ForwardMigrateResult)
+	want := protocol.ForwardMigrateResult{Name: "db", FromHost: "bastion1", Host: "bastion2", Status: protocol.SessionActive}
+	if got != want {
+		t.Errorf("result = %+v, want %+v", got, want)
+	}
+	if cbHost != "bastion2" || fwd.gotCb == nil {
+		t.Errorf("credential callback host = %q, cb set = %v", cbHost, fwd.gotCb != nil)
+	}
+	if len(cfg.config.Forwards) != 1 || cfg.config.Forwards[0].Host != "bastion2" {
+		t.Errorf("saved forwards = %+v, want rule on bastion2", cfg.config.Forwards)
+	}
+}
+
+func TestMigrate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   json.RawMessage
+		err      error
+		wantCode int
+	}{
+		{"missing params", nil, nil, protocol.InvalidParams},
+		{"missing to", json.RawMessage(`{"name":"db"}`), nil, protocol.InvalidParams},
+		{"unknown rule", json.RawMessage(`{"name":"nope","to":"bastion2"}`), nil, protocol.RuleNotFound},
+		{"unknown host", json.RawMessage(`{"name":"db","to":"nope"}`), nil, protocol.HostNotFound},
+		{"same host", json.RawMessage(`{"name":"db","to":"bastion1"}`), nil, protocol.InvalidParams},
+		{"migrate failure", json.RawMessage(`{"name":"db","to":"bastion2"}`), errors.New("listen failed"), protocol.InternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fwd, _ := newTestHandler()
+			fwd.migrateErr = tt.err
+			_, rpcErr := h.Migrate(tt.params, nil)
+			if rpcErr == nil || rpcErr.Code != tt.wantCode {
+				t.Errorf("rpcErr = %v, want code %d", rpcErr, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package forward
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ruleio"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// Export は forward.export リクエストを処理する。ルールとホスト別設定を YAML または JSON の文書として返す。
+func (h *Handler) Export(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.ForwardExportParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+	if p.Format == "" {
+		p.Format = ruleio.FormatYAML
+	}
+
+	doc := ruleio.New(h.fwdMgr.GetRules(), h.cfgMgr.GetConfig().Hosts)
+	data, err := ruleio.Encode(doc, p.Format)
+	if err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
+	return protocol.ForwardExportResult{Format: p.Format, Data: string(data)}, nil
+}
+
+// Import は forward.import リクエストを処理する。
+// 文書のルールを追加し、Replace なら同名のルールを置き換える（アクティブなセッションは新しい内容で再開する）。
+// 追加・置き換えに失敗したルールは見送りとして返し、反映したルールとホスト別設定を設定ファイルに保存する。
+func (h *Handler) Import(params json.RawMessage, credCb CredentialCallbackFunc) (any, *protocol.RPCError) {
+	var p protocol.ForwardImportParams
+	if len(params) == 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	doc, err := ruleio.Decode([]byte(p.Data))
+	if err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid document: " + err.Error()}
+	}
+
+	plan := ruleio.NewPlan(doc, h.fwdMgr.GetRules(), h.cfgMgr.GetConfig().Hosts, p.Replace)
+	result := protocol.ForwardImportResult{Added: []string{}, Hosts: slices.Sorted(maps.Keys(plan.Hosts))}
+	skipped := plan.Skipped
+	if p.DryRun {
+		for _, r := range plan.Add {
+			result.Added = append(result.Added, r.Name)
+		}
+		for _, r := range plan.Replace {
+			result.Replaced = append(result.Replaced, r.Name)
+		}
+	} else {
+		for _, r := range plan.Add {
+			name, err := h.fwdMgr.AddRule(r)
+			if err != nil {
+				skipped = append(skipped, ruleio.Skip{Name: r.Name, Reason: err.Error()})
+				continue
+			}
+			result.Added = append(result.Added, name)
+		}
+		for _, r := range plan.Replace {
+			var cb core.CredentialCallback
+			if credCb != nil {
+				cb = credCb(r.Host)
+			}
+			if _, err := h.fwdMgr.UpdateRule(r, cb); err != nil {
+				skipped = append(skipped, ruleio.Skip{Name: r.Name, Reason: err.Error()})
+				continue
+			}
+			result.Replaced = append(result.Replaced, r.Name)
+		}
+		if err := h.saveImport(plan.Hosts); err != nil {
+			return nil, rpcerr.From(err, protocol.InternalError)
+		}
+	}
+	for _, s := range skipped {
+		result.Skipped = append(result.Skipped, s.String())
+	}
+	return result, nil
+}
+
+// saveImport はフォワードルールと取り込んだホスト別設定を設定ファイルに保存する。一時ルールは保存しない。
+func (h *Handler) saveImport(hosts map[string]core.HostConfig) error {
+	return core.SaveRules(h.cfgMgr, h.fwdMgr.GetRules(), func(c *core.Config) {
+		if len(hosts) > 0 && c.Hosts == nil {
+			c.Hosts = make(map[string]core.HostConfig, len(hosts))
+		}
+		maps.Copy(c.Hosts, hosts)
+	})
+}
@@ -0,0 +1,116 @@
+package forward
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestExport(t *testing.T) {
+	h, _, cfg := newTestHandler()
+	cfg.config.Hosts = map[string]core.HostConfig{"bastion1": {HostMetadata: core.HostMetadata{Notes: "prod"}}}
+
+	res, rpcErr := h.Export(json.RawMessage(`{"format":"json"}`))
+	if rpcErr != nil {
+		t.Fatalf("Export: %v", rpcErr)
+	}
+	got := res.(protocol.ForwardExportResult)
+	if got.Format != "json" || !strings.Contains(got.Data, `"local_port": 15432`) || !strings.Contains(got.Data, `"notes": "prod"`) {
+		t.Errorf("result = %+v", got)
+	}
+
+	if _, rpcErr := h.Export(json.RawMessage(`{"format":"toml"}`)); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("Export(toml) error = %v, want InvalidParams", rpcErr)
+	}
+}
+
+const importDoc = `
+version: 1
+forwards:
+  - name: db
+    host: bastion2
+    type: local
+    local_port: 15432
+    remote_host: localhost
+    remote_port: 5432
+  - name: web
+    host: bastion1
+    type: local
+    local_port: 8080
+    remote_host: localhost
+    remote_port: 80
+hosts:
+  bastion2:
+    notes: shared
+`
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name         string
+		replace      bool
+		dryRun       bool
+		wantReplaced []string
+		wantSkipped  int
+		wantSaved    bool
+	}{
+		{name: "add only", wantSkipped: 1, wantSaved: true},
+		{name: "replace", replace: true, wantReplaced: []string{"db"}, wantSaved: true},
+		{name: "dry run", replace: true, dryRun: true, wantReplaced: []string{"db"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fwd, cfg := newTestHandler()
+			params, _ := json.Marshal(protocol.ForwardImportParams{Data: importDoc, Replace: tt.replace, DryRun: tt.dryRun})
+
+			res, rpcErr := h.Import(params, nil)
+			if rpcErr != nil {
+				t.Fatalf("Import: %v", rpcErr)
+			}
+			got := res.(protocol.ForwardImportResult)
+			if !slices.Equal(got.Added, []string{"web"}) || !slices.Equal(got.Replaced, tt.wantReplaced) || len(got.Skipped) != tt.wantSkipped {
+				t.Errorf("result = %+v", got)
+			}
+			if !slices.Equal(got.Hosts, []string{"bastion2"}) {
+				t.Errorf("Hosts = %v, want [bastion2]", got.Hosts)
+			}
+			if saved := len(cfg.config.Forwards) > 0; saved != tt.wantSaved {
+				t.Errorf("saved = %v, want %v", saved, tt.wantSaved)
+			}
+			if tt.wantSaved && cfg.config.Hosts["bastion2"].Notes != "shared" {
+				t.Errorf("hosts = %+v, want bastion2 notes saved", cfg.config.Hosts)
+			}
+			if tt.dryRun && (len(fwd.added) != 0 || fwd.rule.Host != "bastion1") {
+				t.Errorf("dry run changed rules: added=%v rule=%+v", fwd.added, fwd.rule)
+			}
+		})
+	}
+}
+
+func TestImport_FailedRulesAreSkipped(t *testing.T) {
+	h, fwd, _ := newTestHandler()
+	fwd.addErr = errors.New("port conflict")
+	params, _ := json.Marshal(protocol.ForwardImportParams{Data: importDoc})
+
+	res, rpcErr := h.Import(params, nil)
+	if rpcErr != nil {
+		t.Fatalf("Import: %v", rpcErr)
+	}
+	got := res.(protocol.ForwardImportResult)
+	if len(got.Added) != 0 || !slices.Contains(got.Skipped, "web: port conflict") {
+		t.Errorf("result = %+v, want web skipped", got)
+	}
+}
+
+func TestImport_InvalidDocument(t *testing.T) {
+	h, _, _ := newTestHandler()
+	for _, params := range []string{``, `{"data":"version: 1\nunknown: x\n"}`} {
+		if _, rpcErr := h.Import(json.RawMessage(params), nil); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+			t.Errorf("Import(%q) error = %v, want InvalidParams", params, rpcErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package forward
+
+import (
+	"encoding/json"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// Update は forward.update リクエストを処理する。
+// 指定したフィールドを現在のルールに反映し、アクティブなセッションは新しい内容で再開して、ルールを設定ファイルに保存する。
+func (h *Handler) Update(params json.RawMessage, credCb CredentialCallbackFunc) (any, *protocol.RPCError) {
+	var p protocol.ForwardUpdateParams
+	if len(params) == 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if p.Name == "" {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "name is required"}
+	}
+
+	current, err := h.fwdMgr.GetSession(p.Name)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	rule, rpcErr := applyUpdate(current.Rule, p)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
+	var cb core.CredentialCallback
+	if credCb != nil {
+		cb = credCb(rule.Host)
+	}
+	session, err := h.fwdMgr.UpdateRule(rule, cb)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	h.saveRules()
+
+	return protocol.ForwardUpdateResult{Name: p.Name, Status: convert.ToSessionInfo(*session).Status}, nil
+}
+
+// applyUpdate は p で指定されたフィールドを rule に反映し、反映後のルールを検証する。
+func applyUpdate(rule core.ForwardRule, p protocol.ForwardUpdateParams) (core.ForwardRule, *protocol.RPCError) {
+	set(&rule.LocalPort, p.LocalPort)
+	set(&rule.RemoteHost, p.RemoteHost)
+	set(&rule.RemotePort, p.RemotePort)
+	set(&rule.LocalBindAddr, p.LocalBindAddr)
+	set(&rule.RemoteBindAddr, p.RemoteBindAddr)
+	set(&rule.AutoConnect, p.AutoConnect)
+	set(&rule.AutoReconnect, p.AutoReconnect)
+	set(&rule.AccessLog, p.AccessLog)
+	if p.TTL != nil {
+		ttl, err := protocol.ParseTTL(*p.TTL)
+		if err != nil {
+			return rule, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+		}
+		rule.TTL = core.Duration{Duration: ttl}
+	}
+	if rule.DeleteOnExpire && rule.TTL.Duration == 0 {
+		return rule, &protocol.RPCError{Code: protocol.InvalidParams, Message: "delete_on_expire requires ttl"}
+	}
+	if err := core.ValidateForwardRule(rule); err != nil {
+		return rule, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
+	return rule, nil
+}
+
+// set は v が指定されている場合に dst を v の値で置き換える。
+func set[T any](dst *T, v *T) {
+	if v != nil {
+		*dst = *v
+	}
+}
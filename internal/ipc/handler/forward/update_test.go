@@ -0,0 +1,63 @@
+package forward
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestUpdate_Success(t *testing.T) {
+	h, fwd, cfg := newTestHandler()
+	var cbHost string
+	res, rpcErr := h.Update(json.RawMessage(`{"name":"db","local_port":15433,"remote_port":5433,"ttl":"1h"}`),
+		func(host string) core.CredentialCallback {
+			cbHost = host
+			return func(core.CredentialRequest) (core.CredentialResponse, error) { return core.CredentialResponse{}, nil }
+		})
+	if rpcErr != nil {
+		t.Fatalf("Update: %v", rpcErr)
+	}
+	if want := (protocol.ForwardUpdateResult{Name: "db", Status: protocol.SessionActive}); res != want {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+	if cbHost != "bastion1" || fwd.gotCb == nil {
+		t.Errorf("credential callback host = %q, cb set = %v", cbHost, fwd.gotCb != nil)
+	}
+	got := fwd.rule
+	if got.LocalPort != 15433 || got.RemotePort != 5433 || got.TTL.Duration != time.Hour || got.Host != "bastion1" {
+		t.Errorf("updated rule = %+v", got)
+	}
+	if len(cfg.config.Forwards) != 1 || cfg.config.Forwards[0].LocalPort != 15433 {
+		t.Errorf("saved forwards = %+v, want local port 15433", cfg.config.Forwards)
+	}
+}
+
+func TestUpdate_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   json.RawMessage
+		err      error
+		wantCode int
+	}{
+		{"missing params", nil, nil, protocol.InvalidParams},
+		{"missing name", json.RawMessage(`{"local_port":1}`), nil, protocol.InvalidParams},
+		{"unknown rule", json.RawMessage(`{"name":"nope"}`), nil, protocol.RuleNotFound},
+		{"invalid port", json.RawMessage(`{"name":"db","local_port":70000}`), nil, protocol.InvalidParams},
+		{"invalid ttl", json.RawMessage(`{"name":"db","ttl":"soon"}`), nil, protocol.InvalidParams},
+		{"update failure", json.RawMessage(`{"name":"db","local_port":15433}`), errors.New("listen failed"), protocol.InternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fwd, _ := newTestHandler()
+			fwd.updateErr = tt.err
+			_, rpcErr := h.Update(tt.params, nil)
+			if rpcErr == nil || rpcErr.Code != tt.wantCode {
+				t.Errorf("rpcErr = %v, want code %d", rpcErr, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package group はフォワードグループの一覧（forward.listGroups）と一括開始・停止（forward.startGroup / forward.stopGroup）のハンドラを提供する。
+package group
@@ -0,0 +1,79 @@
+package group
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// Handler はフォワードグループの JSON-RPC メソッドを処理する。
+type Handler struct {
+	profiles core.ProfileManager
+}
+
+// New は新しいグループハンドラを生成する。
+func New(profiles core.ProfileManager) *Handler {
+	return &Handler{profiles: profiles}
+}
+
+// List は forward.listGroups リクエストを処理する。
+func (h *Handler) List() (any, *protocol.RPCError) {
+	groups := h.profiles.Groups()
+	infos := make([]protocol.ForwardGroupInfo, 0, len(groups))
+	for _, g := range groups {
+		infos = append(infos, protocol.ForwardGroupInfo{Name: g.Name, Rules: nonNil(g.Rules)})
+	}
+	return protocol.ForwardListGroupsResult{Groups: infos}, nil
+}
+
+// Start は forward.startGroup リクエストを処理する。ctx と cb はグループ内のルールの SSH 接続に使う。
+func (h *Handler) Start(ctx context.Context, params json.RawMessage, cb core.CredentialCallback) (any, *protocol.RPCError) {
+	name, rpcErr := parseName(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	started, err := h.profiles.StartGroup(ctx, name, cb)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	return protocol.ForwardStartGroupResult{Name: name, Started: nonNil(started)}, nil
+}
+
+// Stop は forward.stopGroup リクエストを処理する。
+func (h *Handler) Stop(params json.RawMessage) (any, *protocol.RPCError) {
+	name, rpcErr := parseName(params)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+	stopped, err := h.profiles.StopGroup(name)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	return protocol.ForwardStopGroupResult{Name: name, Stopped: nonNil(stopped)}, nil
+}
+
+// parseName は ForwardGroupParams を解析し、必須のグループ名を返す。
+func parseName(params json.RawMessage) (string, *protocol.RPCError) {
+	var p protocol.ForwardGroupParams
+	if len(params) == 0 {
+		return "", &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return "", &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if p.Name == "" {
+		return "", &protocol.RPCError{Code: protocol.InvalidParams, Message: "name is required"}
+	}
+	return p.Name, nil
+}
+
+// nonNil は JSON で null ではなく空配列になるよう、nil スライスを空スライスにする。
+func nonNil(names []string) []string {
+	if names == nil {
+		return []string{}
+	}
+	return names
+}
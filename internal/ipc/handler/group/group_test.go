@@ -0,0 +1,96 @@
+package group
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type mockProfiles struct {
+	err    error
+	gotCb  core.CredentialCallback
+	called string
+}
+
+func (m *mockProfiles) Groups() []core.ForwardGroup {
+	return []core.ForwardGroup{{Name: "staging", Rules: []string{"db", "web"}}, {Name: "empty"}}
+}
+
+func (m *mockProfiles) StartGroup(_ context.Context, name string, cb core.CredentialCallback) ([]string, error) {
+	m.called, m.gotCb = "start "+name, cb
+	return []string{"db"}, m.err
+}
+
+func (m *mockProfiles) StopGroup(name string) ([]string, error) {
+	m.called = "stop " + name
+	return nil, m.err
+}
+
+func TestList(t *testing.T) {
+	res, rpcErr := New(&mockProfiles{}).List()
+	if rpcErr != nil {
+		t.Fatalf("List: %v", rpcErr)
+	}
+	want := protocol.ForwardListGroupsResult{Groups: []protocol.ForwardGroupInfo{
+		{Name: "staging", Rules: []string{"db", "web"}},
+		{Name: "empty", Rules: []string{}},
+	}}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("result = %+v, want %+v", res, want)
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	m := &mockProfiles{}
+	h := New(m)
+	cb := func(core.CredentialRequest) (core.CredentialResponse, error) { return core.CredentialResponse{}, nil }
+
+	res, rpcErr := h.Start(t.Context(), json.RawMessage(`{"name":"staging"}`), cb)
+	if rpcErr != nil {
+		t.Fatalf("Start: %v", rpcErr)
+	}
+	if want := (protocol.ForwardStartGroupResult{Name: "staging", Started: []string{"db"}}); !reflect.DeepEqual(res, want) {
+		t.Errorf("Start result = %+v, want %+v", res, want)
+	}
+	if m.called != "start staging" || m.gotCb == nil {
+		t.Errorf("StartGroup call = %q (cb set: %v), want start staging with callback", m.called, m.gotCb != nil)
+	}
+
+	res, rpcErr = h.Stop(json.RawMessage(`{"name":"staging"}`))
+	if rpcErr != nil {
+		t.Fatalf("Stop: %v", rpcErr)
+	}
+	if want := (protocol.ForwardStopGroupResult{Name: "staging", Stopped: []string{}}); !reflect.DeepEqual(res, want) {
+		t.Errorf("Stop result = %+v, want %+v", res, want)
+	}
+}
+
+func TestStartStop_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		err    error
+		want   int
+	}{
+		{"missing params", ``, nil, protocol.InvalidParams},
+		{"missing name", `{}`, nil, protocol.InvalidParams},
+		{"unknown group", `{"name":"prod"}`, &core.NotFoundError{Resource: "group", Name: "prod"}, protocol.GroupNotFound},
+		{"start failure", `{"name":"staging"}`, errors.New("boom"), protocol.InternalError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := New(&mockProfiles{err: tt.err})
+			if _, rpcErr := h.Start(t.Context(), json.RawMessage(tt.params), nil); rpcErr == nil || rpcErr.Code != tt.want {
+				t.Errorf("Start error = %v, want code %d", rpcErr, tt.want)
+			}
+			if _, rpcErr := h.Stop(json.RawMessage(tt.params)); rpcErr == nil || rpcErr.Code != tt.want {
+				t.Errorf("Stop error = %v, want code %d", rpcErr, tt.want)
+			}
+		})
+	}
+}
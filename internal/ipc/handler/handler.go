@@ -4,20 +4,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 	"sync/atomic"
 
 	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/core/profile"
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
 	cfghandler "github.com/ousiassllc/moleport/internal/ipc/handler/config"
+	daemonhandler "github.com/ousiassllc/moleport/internal/ipc/handler/daemon"
+	debughandler "github.com/ousiassllc/moleport/internal/ipc/handler/debug"
+	fwdhandler "github.com/ousiassllc/moleport/internal/ipc/handler/forward"
+	grouphandler "github.com/ousiassllc/moleport/internal/ipc/handler/group"
+	hosthandler "github.com/ousiassllc/moleport/internal/ipc/handler/host"
+	logshandler "github.com/ousiassllc/moleport/internal/ipc/handler/logs"
+	sessionhandler "github.com/ousiassllc/moleport/internal/ipc/handler/session"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
-// DaemonInfo はデーモンの状態情報とシャットダウンを提供するインターフェース。
-type DaemonInfo interface {
-	Status() protocol.DaemonStatusResult
-	Shutdown(purge bool) error
-}
+// DaemonInfo はデーモンの状態情報・稼働状況とシャットダウンを提供するインターフェース。
+type DaemonInfo = daemonhandler.Daemon
 
 // NotificationSender はクライアントに通知を送信するインターフェース。
 type NotificationSender interface {
@@ -35,7 +41,13 @@ type Handler struct {
 	fwdMgr         core.ForwardManager
 	cfgMgr         core.ConfigManager
 	configH        *cfghandler.Handler
-	broker         *ipc.EventBroker
+	hostH          *hosthandler.Handler
+	fwdH           *fwdhandler.Handler
+	groupH         *grouphandler.Handler
+	sessionH       *sessionhandler.Handler
+	daemonH        *daemonhandler.Handler
+	logsH          *logshandler.Handler
+	broker         *broker.EventBroker
 	daemon         DaemonInfo
 	sender         NotificationSender
 	versionChecker VersionChecker
@@ -50,15 +62,22 @@ func NewHandler(
 	sshMgr core.SSHManager,
 	fwdMgr core.ForwardManager,
 	cfgMgr core.ConfigManager,
-	broker *ipc.EventBroker,
+	broker *broker.EventBroker,
 	daemon DaemonInfo,
 	versionChecker VersionChecker,
 ) *Handler {
 	return &Handler{
-		sshMgr:         sshMgr,
-		fwdMgr:         fwdMgr,
-		cfgMgr:         cfgMgr,
-		configH:        cfghandler.New(cfgMgr),
+		sshMgr:  sshMgr,
+		fwdMgr:  fwdMgr,
+		cfgMgr:  cfgMgr,
+		configH: cfghandler.New(cfgMgr),
+		hostH:   hosthandler.New(sshMgr, fwdMgr, cfgMgr),
+		fwdH:    fwdhandler.New(sshMgr, fwdMgr, cfgMgr),
+		groupH: grouphandler.New(profile.New(fwdMgr, func() []core.ForwardGroup {
+			return cfgMgr.GetConfig().Groups
+		})),
+		sessionH:       sessionhandler.New(fwdMgr),
+		daemonH:        daemonhandler.New(daemon),
 		broker:         broker,
 		daemon:         daemon,
 		versionChecker: versionChecker,
@@ -72,15 +91,44 @@ func (h *Handler) SetSender(sender NotificationSender) {
 	h.sender = sender
 }
 
+// SetLogSource はログの配信（logs.*）に使うデーモンのログの取得元を設定する。
+// 設定しない場合、logs.* は MethodNotFound を返す。
+func (h *Handler) SetLogSource(source logshandler.Source) {
+	h.logsH = logshandler.New(source, func(clientID string, n protocol.Notification) error {
+		return h.sender.SendNotification(clientID, n)
+	})
+}
+
+// SetHealthSource はホストの疎通確認結果の取得元を設定する。
+// 疎通確認が有効な場合に、ハンドラーの生成後に呼び出す。
+func (h *Handler) SetHealthSource(health func(name string) core.HostHealth) {
+	h.hostH.SetHealth(health)
+}
+
+// SetHostsReloaded は host.reload でホスト一覧を再読み込みした後に呼ぶ関数を設定する。
+// ホストに定義されたフォワードを反映するために使う。
+func (h *Handler) SetHostsReloaded(fn func()) {
+	h.hostH.SetReloaded(fn)
+}
+
 // Handle は JSON-RPC メソッドをディスパッチする。HandlerFunc として使用する。
-func (h *Handler) Handle(clientID string, method string, params json.RawMessage) (any, *protocol.RPCError) {
+// ctx はクレデンシャルの入力待ちを含む SSH 接続に渡し、キャンセルされると接続を中断する。
+func (h *Handler) Handle(ctx context.Context, clientID string, method string, params json.RawMessage) (any, *protocol.RPCError) {
+	if strings.HasPrefix(method, "host.") {
+		return h.hostH.Handle(method, params)
+	}
+	if strings.HasPrefix(method, "session.") {
+		return h.sessionH.Handle(method, params)
+	}
+	if strings.HasPrefix(method, "daemon.") {
+		return h.daemonH.Handle(method, params)
+	}
+	if strings.HasPrefix(method, "logs.") && h.logsH != nil {
+		return h.logsH.Handle(clientID, method, params)
+	}
 	switch method {
-	case "host.list":
-		return h.hostList()
-	case "host.reload":
-		return h.hostReload()
 	case "ssh.connect":
-		return h.sshConnect(clientID, params)
+		return h.sshConnect(ctx, clientID, params)
 	case "ssh.disconnect":
 		return h.sshDisconnect(params)
 	case protocol.MethodCredentialResponse:
@@ -92,25 +140,47 @@ func (h *Handler) Handle(clientID string, method string, params json.RawMessage)
 	case "forward.delete":
 		return h.forwardDelete(params)
 	case "forward.start":
-		return h.forwardStart(clientID, params)
+		return h.forwardStart(ctx, clientID, params)
 	case "forward.stop":
 		return h.forwardStop(params)
 	case "forward.stopAll":
 		return h.forwardStopAll()
-	case "session.list":
-		return h.sessionList()
-	case "session.get":
-		return h.sessionGet(params)
+	case "forward.migrate":
+		return h.fwdH.Migrate(params, func(host string) core.CredentialCallback {
+			return h.buildCredentialCallback(ctx, clientID, host)
+		})
+	case "forward.update":
+		return h.fwdH.Update(params, func(host string) core.CredentialCallback {
+			return h.buildCredentialCallback(ctx, clientID, host)
+		})
+	case "forward.export":
+		return h.fwdH.Export(params)
+	case "forward.import":
+		return h.fwdH.Import(params, func(host string) core.CredentialCallback {
+			return h.buildCredentialCallback(ctx, clientID, host)
+		})
+	case "forward.checkPort":
+		return h.fwdH.CheckPort(params)
+	case "forward.setLimit":
+		return h.fwdH.SetLimit(params)
+	case "forward.listGroups":
+		return h.groupH.List()
+	case "forward.startGroup":
+		return h.groupH.Start(ctx, params, h.buildCredentialCallback(ctx, clientID, ""))
+	case "forward.stopGroup":
+		return h.groupH.Stop(params)
 	case "config.get":
 		return h.configH.Get()
 	case "config.update":
 		return h.configH.Update(params)
+	case "config.schema":
+		return h.configH.Schema()
+	case "config.validate":
+		return h.configH.Validate(params)
 	case "version.check":
 		return h.versionCheck()
-	case "daemon.status":
-		return h.daemonStatus()
-	case "daemon.shutdown":
-		return h.daemonShutdown(params)
+	case "debug.faults":
+		return debughandler.Faults(params)
 	case protocol.MethodEventsSubscribe:
 		return h.eventsSubscribe(clientID, params)
 	case protocol.MethodEventsUnsubscribe:
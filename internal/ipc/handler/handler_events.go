@@ -3,16 +3,10 @@ package handler
 import (
 	"encoding/json"
 
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
-// validEventTypes は有効なイベント種別。
-var validEventTypes = map[string]bool{
-	"ssh":     true,
-	"forward": true,
-	"metrics": true,
-}
-
 func (h *Handler) eventsSubscribe(clientID string, params json.RawMessage) (any, *protocol.RPCError) {
 	var p protocol.EventsSubscribeParams
 	if err := parseParams(params, &p); err != nil {
@@ -23,13 +17,21 @@ func (h *Handler) eventsSubscribe(clientID string, params json.RawMessage) (any,
 	}
 
 	for _, t := range p.Types {
-		if !validEventTypes[t] {
+		if !broker.IsEventType(t) {
 			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid event type: " + t}
 		}
 	}
+	if p.SinceID < 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "since_id must not be negative"}
+	}
 
-	subID := h.broker.Subscribe(clientID, p.Types)
-	return protocol.EventsSubscribeResult{SubscriptionID: subID}, nil
+	subID, replay := h.broker.SubscribeSince(clientID, p.Types, broker.Filter{Hosts: p.Hosts, Rules: p.Rules}, p.SinceID)
+	return protocol.EventsSubscribeResult{
+		SubscriptionID: subID,
+		LastEventID:    replay.LastID,
+		Replay:         replay.Events,
+		Truncated:      replay.Truncated,
+	}, nil
 }
 
 func (h *Handler) eventsUnsubscribe(params json.RawMessage) (any, *protocol.RPCError) {
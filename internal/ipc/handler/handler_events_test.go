@@ -3,14 +3,15 @@ package handler
 import (
 	"testing"
 
+	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
 func TestHandler_EventsSubscribe(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
-	params := mustMarshal(t, protocol.EventsSubscribeParams{Types: []string{"ssh", "forward"}})
-	result, rpcErr := h.Handle("client-1", "events.subscribe", params)
+	params := mustMarshal(t, protocol.EventsSubscribeParams{Types: []string{"ssh", "forward", "daemon"}})
+	result, rpcErr := h.Handle(t.Context(), "client-1", "events.subscribe", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -29,12 +30,12 @@ func TestHandler_EventsUnsubscribe(t *testing.T) {
 
 	// まず購読を作成
 	subParams := mustMarshal(t, protocol.EventsSubscribeParams{Types: []string{"ssh"}})
-	subResult, _ := h.Handle("client-1", "events.subscribe", subParams)
+	subResult, _ := h.Handle(t.Context(), "client-1", "events.subscribe", subParams)
 	subID := subResult.(protocol.EventsSubscribeResult).SubscriptionID
 
 	// 購読を解除
 	unsubParams := mustMarshal(t, protocol.EventsUnsubscribeParams{SubscriptionID: subID})
-	result, rpcErr := h.Handle("client-1", "events.unsubscribe", unsubParams)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "events.unsubscribe", unsubParams)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -49,7 +50,7 @@ func TestHandler_EventsUnsubscribe(t *testing.T) {
 
 	// 存在しない購読 ID で解除するとエラー
 	badParams := mustMarshal(t, protocol.EventsUnsubscribeParams{SubscriptionID: "nonexistent"})
-	_, rpcErr = h.Handle("client-1", "events.unsubscribe", badParams)
+	_, rpcErr = h.Handle(t.Context(), "client-1", "events.unsubscribe", badParams)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error for nonexistent subscription")
 	}
@@ -58,7 +59,7 @@ func TestHandler_EventsUnsubscribe(t *testing.T) {
 func TestHandler_EventsSubscribe_EmptyTypes(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 	params := mustMarshal(t, protocol.EventsSubscribeParams{Types: []string{}})
-	_, rpcErr := h.Handle("client-1", "events.subscribe", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "events.subscribe", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error for empty types")
 	}
@@ -70,7 +71,7 @@ func TestHandler_EventsSubscribe_EmptyTypes(t *testing.T) {
 func TestHandler_EventsUnsubscribe_EmptySubscriptionID(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 	params := mustMarshal(t, protocol.EventsUnsubscribeParams{SubscriptionID: ""})
-	_, rpcErr := h.Handle("client-1", "events.unsubscribe", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "events.unsubscribe", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error for empty subscription_id")
 	}
@@ -83,7 +84,7 @@ func TestHandler_EventsSubscribe_InvalidType(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
 	params := mustMarshal(t, protocol.EventsSubscribeParams{Types: []string{"ssh", "invalid"}})
-	_, rpcErr := h.Handle("client-1", "events.subscribe", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "events.subscribe", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error for invalid event type")
 	}
@@ -91,3 +92,24 @@ func TestHandler_EventsSubscribe_InvalidType(t *testing.T) {
 		t.Errorf("error code = %d, want %d (InvalidParams)", rpcErr.Code, protocol.InvalidParams)
 	}
 }
+
+func TestHandler_EventsSubscribe_SinceID(t *testing.T) {
+	h, _, _, _ := newTestHandler()
+	h.broker.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventConnected, HostName: "prod"})
+	h.broker.HandleSSHEvent(core.SSHEvent{Type: core.SSHEventDisconnected, HostName: "prod"})
+
+	params := mustMarshal(t, protocol.EventsSubscribeParams{Types: []string{"ssh"}, SinceID: 1})
+	result, rpcErr := h.Handle(t.Context(), "client-1", "events.subscribe", params)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	subResult := result.(protocol.EventsSubscribeResult)
+	if subResult.LastEventID != 2 || len(subResult.Replay) != 1 || subResult.Truncated {
+		t.Errorf("result = %+v, want last_event_id 2 and 1 replayed event", subResult)
+	}
+
+	params = mustMarshal(t, protocol.EventsSubscribeParams{Types: []string{"ssh"}, SinceID: -1})
+	if _, rpcErr := h.Handle(t.Context(), "client-1", "events.subscribe", params); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("negative since_id: rpcErr = %v, want InvalidParams", rpcErr)
+	}
+}
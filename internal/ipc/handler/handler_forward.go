@@ -1,11 +1,14 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
 )
 
 func (h *Handler) forwardList(params json.RawMessage) (any, *protocol.RPCError) {
@@ -28,7 +31,7 @@ func (h *Handler) forwardList(params json.RawMessage) (any, *protocol.RPCError)
 		Forwards: make([]protocol.ForwardInfo, len(rules)),
 	}
 	for i, rule := range rules {
-		result.Forwards[i] = protocol.ToForwardInfo(rule)
+		result.Forwards[i] = convert.ToForwardInfo(rule)
 	}
 	return result, nil
 }
@@ -44,29 +47,53 @@ func (h *Handler) forwardAdd(params json.RawMessage) (any, *protocol.RPCError) {
 	); err != nil {
 		return nil, err
 	}
-	if p.LocalPort <= 0 {
-		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "local_port must be greater than 0"}
+	if p.LocalPort < 0 || p.MaxUploadKbps < 0 || p.MaxDownloadKbps < 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "local_port and bandwidth limits must not be negative"}
 	}
 
 	fwdType, err := core.ParseForwardType(p.Type)
 	if err != nil {
 		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
 	}
+	if err := core.ValidateBindAddr(p.LocalBindAddr); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "local_bind_addr: " + err.Error()}
+	}
+	if err := core.ValidateBindAddr(p.RemoteBindAddr); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "remote_bind_addr: " + err.Error()}
+	}
+	ttl, err := protocol.ParseTTL(p.TTL)
+	if err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
+	if p.DeleteOnExpire && ttl == 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "delete_on_expire requires ttl"}
+	}
 
 	rule := core.ForwardRule{
-		Name:           p.Name,
-		Host:           p.Host,
-		Type:           fwdType,
-		LocalPort:      p.LocalPort,
-		RemoteHost:     p.RemoteHost,
-		RemotePort:     p.RemotePort,
-		RemoteBindAddr: p.RemoteBindAddr,
-		AutoConnect:    p.AutoConnect,
+		Name:            p.Name,
+		Host:            p.Host,
+		Type:            fwdType,
+		LocalPort:       p.LocalPort,
+		RemoteHost:      p.RemoteHost,
+		RemotePort:      p.RemotePort,
+		RemoteBindAddr:  p.RemoteBindAddr,
+		LocalBindAddr:   p.LocalBindAddr,
+		AutoConnect:     p.AutoConnect,
+		AutoReconnect:   p.AutoReconnect,
+		TTL:             core.Duration{Duration: ttl},
+		DeleteOnExpire:  p.DeleteOnExpire,
+		MaxUploadKbps:   p.MaxUploadKbps,
+		MaxDownloadKbps: p.MaxDownloadKbps,
+		ACL:             convert.ToSocksACL(p.ACL),
+		AccessLog:       p.AccessLog,
+		DependsOn:       p.DependsOn,
+		OnStart:         p.OnStart,
+		OnStop:          p.OnStop,
 	}
 
 	name, err := h.fwdMgr.AddRule(rule)
 	if err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 
 	h.saveForwardRulesToConfig()
@@ -83,14 +110,14 @@ func (h *Handler) forwardDelete(params json.RawMessage) (any, *protocol.RPCError
 	}
 
 	if err := h.fwdMgr.DeleteRule(p.Name); err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 
 	h.saveForwardRulesToConfig()
 	return protocol.ForwardDeleteResult{OK: true}, nil
 }
 
-func (h *Handler) forwardStart(clientID string, params json.RawMessage) (any, *protocol.RPCError) {
+func (h *Handler) forwardStart(ctx context.Context, clientID string, params json.RawMessage) (any, *protocol.RPCError) {
 	var p protocol.ForwardStartParams
 	if err := parseParams(params, &p); err != nil {
 		return nil, err
@@ -98,17 +125,26 @@ func (h *Handler) forwardStart(clientID string, params json.RawMessage) (any, *p
 	if err := validateRequired(requiredField{"name", p.Name}); err != nil {
 		return nil, err
 	}
+	ttl, err := protocol.ParseTTL(p.TTL)
+	if err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
 
 	// クレデンシャルコールバックを StartForward に渡す。
 	// StartForward 内で SSH 未接続時にコールバック付きで接続するため、
 	// パスワード認証や keyboard-interactive 認証もサポートされる。
 	session, err := h.fwdMgr.GetSession(p.Name)
 	if err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
-	cb := h.buildCredentialCallback(clientID, session.Rule.Host)
-	if err := h.fwdMgr.StartForward(p.Name, cb); err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+	cb := h.buildCredentialCallback(ctx, clientID, session.Rule.Host)
+	if err := h.fwdMgr.StartForward(ctx, p.Name, cb); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	if ttl > 0 {
+		if err := h.fwdMgr.SetTTL(p.Name, ttl); err != nil {
+			return nil, rpcerr.From(err, protocol.InternalError)
+		}
 	}
 
 	return protocol.ForwardStartResult{
@@ -127,7 +163,7 @@ func (h *Handler) forwardStop(params json.RawMessage) (any, *protocol.RPCError)
 	}
 
 	if err := h.fwdMgr.StopForward(p.Name); err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 
 	return protocol.ForwardStopResult{
@@ -146,18 +182,15 @@ func (h *Handler) forwardStopAll() (any, *protocol.RPCError) {
 	}
 
 	if err := h.fwdMgr.StopAllForwards(); err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 
 	return protocol.ForwardStopAllResult{Stopped: active}, nil
 }
 
-// saveForwardRulesToConfig はフォワードルールを設定ファイルに保存する。
+// saveForwardRulesToConfig はフォワードルールを設定ファイルに保存する。保存に失敗した場合はログに記録する。
 func (h *Handler) saveForwardRulesToConfig() {
-	rules := h.fwdMgr.GetRules()
-	if err := h.cfgMgr.UpdateConfig(func(c *core.Config) {
-		c.Forwards = rules
-	}); err != nil {
+	if err := core.SaveRules(h.cfgMgr, h.fwdMgr.GetRules()); err != nil {
 		slog.Warn("failed to save forward rules to config", "error", err)
 	}
 }
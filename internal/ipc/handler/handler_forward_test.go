@@ -2,6 +2,7 @@ package handler
 
 import (
 	"testing"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
@@ -10,7 +11,7 @@ import (
 func TestHandler_ForwardList(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
-	result, rpcErr := h.Handle("client-1", "forward.list", nil)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "forward.list", nil)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -43,7 +44,7 @@ func TestHandler_ForwardAdd_Success(t *testing.T) {
 		RemotePort: 5432,
 	})
 
-	result, rpcErr := h.Handle("client-1", "forward.add", params)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "forward.add", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -65,7 +66,11 @@ func TestHandler_ForwardParamValidation(t *testing.T) {
 	}{
 		{"add_empty_host", "forward.add", protocol.ForwardAddParams{Host: "", Type: "local", LocalPort: 8080}},
 		{"add_empty_type", "forward.add", protocol.ForwardAddParams{Host: "prod", Type: "", LocalPort: 8080}},
-		{"add_zero_port", "forward.add", protocol.ForwardAddParams{Host: "prod", Type: "local", LocalPort: 0}},
+		{"add_negative_port", "forward.add", protocol.ForwardAddParams{Host: "prod", Type: "local", LocalPort: -1}},
+		{"add_invalid_ttl", "forward.add", protocol.ForwardAddParams{Host: "prod", Type: "dynamic", LocalPort: 1080, TTL: "-1h"}},
+		{"add_negative_limit", "forward.add", protocol.ForwardAddParams{Host: "prod", Type: "dynamic", LocalPort: 1080, MaxDownloadKbps: -1}},
+		{"add_delete_without_ttl", "forward.add", protocol.ForwardAddParams{Host: "prod", Type: "dynamic", LocalPort: 1080, DeleteOnExpire: true}},
+		{"start_invalid_ttl", "forward.start", protocol.ForwardStartParams{Name: "web", TTL: "soon"}},
 		{"delete_empty", "forward.delete", protocol.ForwardDeleteParams{Name: ""}},
 		{"start_empty", "forward.start", protocol.ForwardStartParams{Name: ""}},
 		{"stop_empty", "forward.stop", protocol.ForwardStopParams{Name: ""}},
@@ -73,45 +78,19 @@ func TestHandler_ForwardParamValidation(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			h, _, _, _ := newTestHandler()
-			params := mustMarshal(t, tt.params)
-			_, rpcErr := h.Handle("client-1", tt.method, params)
-			if rpcErr == nil {
-				t.Fatal("expected RPC error")
-			}
-			if rpcErr.Code != protocol.InvalidParams {
-				t.Errorf("error code = %d, want %d (InvalidParams)", rpcErr.Code, protocol.InvalidParams)
+			_, rpcErr := h.Handle(t.Context(), "client-1", tt.method, mustMarshal(t, tt.params))
+			if rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+				t.Errorf("rpcErr = %v, want code %d (InvalidParams)", rpcErr, protocol.InvalidParams)
 			}
 		})
 	}
 }
 
-func TestHandler_ForwardAdd_RuleAlreadyExists(t *testing.T) {
-	h, _, fwdMgr, _ := newTestHandler()
-	fwdMgr.addErr = &core.AlreadyExistsError{Resource: "rule", Name: "web"}
-
-	params := mustMarshal(t, protocol.ForwardAddParams{
-		Name:       "web",
-		Host:       "prod",
-		Type:       "local",
-		LocalPort:  8080,
-		RemoteHost: "localhost",
-		RemotePort: 80,
-	})
-
-	_, rpcErr := h.Handle("client-1", "forward.add", params)
-	if rpcErr == nil {
-		t.Fatal("expected RPC error")
-	}
-	if rpcErr.Code != protocol.RuleAlreadyExists {
-		t.Errorf("error code = %d, want %d (RuleAlreadyExists)", rpcErr.Code, protocol.RuleAlreadyExists)
-	}
-}
-
 func TestHandler_ForwardDelete_Success(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
 	params := mustMarshal(t, protocol.ForwardDeleteParams{Name: "web"})
-	result, rpcErr := h.Handle("client-1", "forward.delete", params)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "forward.delete", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -125,60 +104,51 @@ func TestHandler_ForwardDelete_Success(t *testing.T) {
 	}
 }
 
-func TestHandler_ForwardDelete_NotFound(t *testing.T) {
+func TestHandler_ForwardErrorCodes(t *testing.T) {
 	h, _, fwdMgr, _ := newTestHandler()
+	fwdMgr.addErr = &core.AlreadyExistsError{Resource: "rule", Name: "web"}
 	fwdMgr.deleteErr = &core.NotFoundError{Resource: "rule", Name: "nonexistent"}
-
-	params := mustMarshal(t, protocol.ForwardDeleteParams{Name: "nonexistent"})
-	_, rpcErr := h.Handle("client-1", "forward.delete", params)
-	if rpcErr == nil {
-		t.Fatal("expected RPC error")
+	tests := []struct {
+		method string
+		params any
+		want   int
+	}{
+		{"forward.add", protocol.ForwardAddParams{Name: "web", Host: "prod", Type: "local", LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}, protocol.RuleAlreadyExists},
+		{"forward.delete", protocol.ForwardDeleteParams{Name: "nonexistent"}, protocol.RuleNotFound},
+		{"forward.add", protocol.ForwardAddParams{Host: "prod", Type: "remote", LocalPort: 3000, RemotePort: 80, LocalBindAddr: "not-an-ip"}, protocol.InvalidParams},
 	}
-	if rpcErr.Code != protocol.RuleNotFound {
-		t.Errorf("error code = %d, want %d (RuleNotFound)", rpcErr.Code, protocol.RuleNotFound)
+	for _, tt := range tests {
+		_, rpcErr := h.Handle(t.Context(), "client-1", tt.method, mustMarshal(t, tt.params))
+		if rpcErr == nil || rpcErr.Code != tt.want {
+			t.Errorf("%s error = %v, want code %d", tt.method, rpcErr, tt.want)
+		}
 	}
 }
 
-func TestHandler_ForwardStart_Success(t *testing.T) {
+func TestHandler_ForwardStartStop_Success(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
-	params := mustMarshal(t, protocol.ForwardStartParams{Name: "web"})
-	result, rpcErr := h.Handle("client-1", "forward.start", params)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "forward.start", mustMarshal(t, protocol.ForwardStartParams{Name: "web"}))
 	if rpcErr != nil {
-		t.Fatalf("unexpected error: %v", rpcErr)
+		t.Fatalf("forward.start: %v", rpcErr)
 	}
-
-	startResult, ok := result.(protocol.ForwardStartResult)
-	if !ok {
-		t.Fatalf("result type = %T, want protocol.ForwardStartResult", result)
-	}
-	if startResult.Status != "active" {
-		t.Errorf("status = %q, want %q", startResult.Status, "active")
+	if got, ok := result.(protocol.ForwardStartResult); !ok || got.Status != "active" {
+		t.Errorf("forward.start result = %#v, want status active", result)
 	}
-}
-
-func TestHandler_ForwardStop_Success(t *testing.T) {
-	h, _, _, _ := newTestHandler()
 
-	params := mustMarshal(t, protocol.ForwardStopParams{Name: "web"})
-	result, rpcErr := h.Handle("client-1", "forward.stop", params)
+	result, rpcErr = h.Handle(t.Context(), "client-1", "forward.stop", mustMarshal(t, protocol.ForwardStopParams{Name: "web"}))
 	if rpcErr != nil {
-		t.Fatalf("unexpected error: %v", rpcErr)
-	}
-
-	stopResult, ok := result.(protocol.ForwardStopResult)
-	if !ok {
-		t.Fatalf("result type = %T, want protocol.ForwardStopResult", result)
+		t.Fatalf("forward.stop: %v", rpcErr)
 	}
-	if stopResult.Status != "stopped" {
-		t.Errorf("status = %q, want %q", stopResult.Status, "stopped")
+	if got, ok := result.(protocol.ForwardStopResult); !ok || got.Status != "stopped" {
+		t.Errorf("forward.stop result = %#v, want status stopped", result)
 	}
 }
 
 func TestHandler_ForwardStopAll(t *testing.T) {
 	h, _, fwdMgr, _ := newTestHandler()
 
-	result, rpcErr := h.Handle("client-1", "forward.stopAll", nil)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "forward.stopAll", nil)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -210,7 +180,7 @@ func TestHandler_ForwardAdd_SavesConfig(t *testing.T) {
 	})
 
 	before := cfgMgr.updateCallCount
-	_, rpcErr := h.Handle("client-1", "forward.add", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "forward.add", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -233,13 +203,26 @@ func TestHandler_ForwardAdd_SavesConfig(t *testing.T) {
 	}
 }
 
+func TestHandler_ForwardAdd_TemporaryRuleNotSaved(t *testing.T) {
+	h, _, _, cfgMgr := newTestHandler()
+	params := mustMarshal(t, protocol.ForwardAddParams{Name: "tmp", Host: "prod", Type: "dynamic", LocalPort: 1080, TTL: "2h", DeleteOnExpire: true})
+	if _, rpcErr := h.Handle(t.Context(), "client-1", "forward.add", params); rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	for _, f := range cfgMgr.GetConfig().Forwards {
+		if f.Name == "tmp" {
+			t.Error("config.Forwards should not contain a rule deleted on expiry")
+		}
+	}
+}
+
 func TestHandler_ForwardDelete_SavesConfig(t *testing.T) {
 	h, _, _, cfgMgr := newTestHandler()
 
 	params := mustMarshal(t, protocol.ForwardDeleteParams{Name: "web"})
 
 	before := cfgMgr.updateCallCount
-	_, rpcErr := h.Handle("client-1", "forward.delete", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "forward.delete", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -264,8 +247,8 @@ func TestHandler_ForwardStart_PassesCallbackToStartForward(t *testing.T) {
 		Status: core.Stopped,
 	})
 
-	params := mustMarshal(t, protocol.ForwardStartParams{Name: "db"})
-	_, rpcErr := h.Handle("client-1", "forward.start", params)
+	params := mustMarshal(t, protocol.ForwardStartParams{Name: "db", TTL: "2h"})
+	_, rpcErr := h.Handle(t.Context(), "client-1", "forward.start", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -273,4 +256,18 @@ func TestHandler_ForwardStart_PassesCallbackToStartForward(t *testing.T) {
 	if fwdMgr.lastStartCb == nil {
 		t.Error("forwardStart should pass non-nil CredentialCallback to StartForward")
 	}
+	if fwdMgr.lastTTL != 2*time.Hour {
+		t.Errorf("SetTTL ttl = %v, want 2h", fwdMgr.lastTTL)
+	}
+}
+
+func TestHandler_ForwardGroups_Routing(t *testing.T) {
+	h, _, _, _ := newTestHandler()
+	if _, rpcErr := h.Handle(t.Context(), "client-1", "forward.listGroups", nil); rpcErr != nil {
+		t.Errorf("forward.listGroups: %v", rpcErr)
+	}
+	_, rpcErr := h.Handle(t.Context(), "client-1", "forward.startGroup", mustMarshal(t, protocol.ForwardGroupParams{Name: "missing"}))
+	if rpcErr == nil || rpcErr.Code != protocol.GroupNotFound {
+		t.Errorf("forward.startGroup error = %v, want code %d (GroupNotFound)", rpcErr, protocol.GroupNotFound)
+	}
 }
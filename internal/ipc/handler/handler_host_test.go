@@ -10,7 +10,7 @@ import (
 func TestHandler_HostList(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
-	result, rpcErr := h.Handle("client-1", "host.list", nil)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "host.list", nil)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -38,7 +38,7 @@ func TestHandler_HostList(t *testing.T) {
 func TestHandler_HostReload(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
-	result, rpcErr := h.Handle("client-1", "host.reload", nil)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "host.reload", nil)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -67,7 +67,7 @@ func TestHandler_HostReload_Diff(t *testing.T) {
 		{Name: "dev", HostName: "dev.example.com", Port: 22, User: "deploy", State: core.Disconnected},
 	}
 
-	result, rpcErr := h.Handle("client-1", "host.reload", nil)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "host.reload", nil)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -87,3 +87,16 @@ func TestHandler_HostReload_Diff(t *testing.T) {
 		t.Errorf("Removed = %v, want [staging]", reloadResult.Removed)
 	}
 }
+
+func TestHandler_HostUpdate_Routed(t *testing.T) {
+	h, _, _, cfgMgr := newTestHandler()
+
+	hint := "use yubikey"
+	params := mustMarshal(t, protocol.HostUpdateParams{Name: "prod", AuthHint: &hint})
+	if _, rpcErr := h.Handle(t.Context(), "client-1", "host.update", params); rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if got := cfgMgr.config.Hosts["prod"].AuthHint; got != hint {
+		t.Errorf("AuthHint = %q, want %q", got, hint)
+	}
+}
@@ -1,13 +1,14 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"testing"
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 	"golang.org/x/crypto/ssh"
 )
@@ -55,18 +56,18 @@ func (m *mockSSHManager) GetHost(name string) (*core.SSHHost, error) {
 	return nil, &core.NotFoundError{Resource: "host", Name: name}
 }
 
-func (m *mockSSHManager) Connect(hostName string) error {
+func (m *mockSSHManager) Connect(_ context.Context, hostName string) error {
 	if m.connectFn != nil {
 		return m.connectFn(hostName)
 	}
 	return nil
 }
 
-func (m *mockSSHManager) ConnectWithCallback(hostName string, cb core.CredentialCallback) error {
+func (m *mockSSHManager) ConnectWithCallback(ctx context.Context, hostName string, cb core.CredentialCallback) error {
 	if m.connectWithCbFn != nil {
 		return m.connectWithCbFn(hostName, cb)
 	}
-	return m.Connect(hostName)
+	return m.Connect(ctx, hostName)
 }
 
 func (m *mockSSHManager) GetPendingAuthHosts() []string { return nil }
@@ -92,6 +93,8 @@ func (m *mockSSHManager) GetSSHConnection(_ string) (core.SSHConnection, error)
 }
 func (m *mockSSHManager) Subscribe() <-chan core.SSHEvent { return make(chan core.SSHEvent) }
 func (m *mockSSHManager) Close()                          {}
+func (m *mockSSHManager) UpdateSettings(core.ReconnectConfig, map[string]core.HostConfig, core.TimeoutConfig) {
+}
 
 type mockForwardManager struct {
 	rules         []core.ForwardRule
@@ -102,8 +105,8 @@ type mockForwardManager struct {
 	stopErr       error
 	stopAllErr    error
 	stopAllCalled bool
-	sessionErr    error
 	lastStartCb   core.CredentialCallback // StartForward に渡されたコールバックを記録
+	lastTTL       time.Duration           // SetTTL に渡された期間を記録
 }
 
 func (m *mockForwardManager) AddRule(rule core.ForwardRule) (string, error) {
@@ -138,7 +141,7 @@ func (m *mockForwardManager) GetRulesByHost(hostName string) []core.ForwardRule
 	return result
 }
 
-func (m *mockForwardManager) StartForward(ruleName string, cb core.CredentialCallback) error {
+func (m *mockForwardManager) StartForward(_ context.Context, ruleName string, cb core.CredentialCallback) error {
 	m.lastStartCb = cb
 	if m.startErr != nil {
 		return m.startErr
@@ -146,6 +149,14 @@ func (m *mockForwardManager) StartForward(ruleName string, cb core.CredentialCal
 	return nil
 }
 
+func (m *mockForwardManager) CheckPort(int) error             { return nil }
+func (m *mockForwardManager) SetLimit(string, int, int) error { return nil }
+
+func (m *mockForwardManager) SetTTL(_ string, ttl time.Duration) error {
+	m.lastTTL = ttl
+	return nil
+}
+
 func (m *mockForwardManager) StopForward(ruleName string) error {
 	if m.stopErr != nil {
 		return m.stopErr
@@ -153,15 +164,20 @@ func (m *mockForwardManager) StopForward(ruleName string) error {
 	return nil
 }
 
+func (m *mockForwardManager) MigrateForward(string, string, core.CredentialCallback) (*core.ForwardSession, error) {
+	return nil, nil
+}
+
+func (m *mockForwardManager) UpdateRule(core.ForwardRule, core.CredentialCallback) (*core.ForwardSession, error) {
+	return nil, nil
+}
+
 func (m *mockForwardManager) StopAllForwards() error {
 	m.stopAllCalled = true
 	return m.stopAllErr
 }
 
 func (m *mockForwardManager) GetSession(ruleName string) (*core.ForwardSession, error) {
-	if m.sessionErr != nil {
-		return nil, m.sessionErr
-	}
 	for _, s := range m.sessions {
 		if s.Rule.Name == ruleName {
 			return &s, nil
@@ -174,11 +190,12 @@ func (m *mockForwardManager) GetAllSessions() []core.ForwardSession {
 	return m.sessions
 }
 
-func (m *mockForwardManager) MarkReconnecting(hostName string) {}
-
+func (m *mockForwardManager) LoadStopHistory([]core.StopRecord)                  {}
+func (m *mockForwardManager) LoadSessionRecords([]core.SessionRecord)            {}
+func (m *mockForwardManager) MarkReconnecting(hostName string)                   {}
+func (m *mockForwardManager) MarkAutoReconnecting(hostName string)               {}
 func (m *mockForwardManager) RestoreForwards(string) []core.ForwardRestoreResult { return nil }
-
-func (m *mockForwardManager) FailReconnecting(hostName string) {}
+func (m *mockForwardManager) FailReconnecting(hostName string)                   {}
 
 func (m *mockForwardManager) Subscribe() <-chan core.ForwardEvent {
 	return make(chan core.ForwardEvent)
@@ -219,22 +236,14 @@ func (m *mockConfigManager) DeleteState() error              { return nil }
 func (m *mockConfigManager) ConfigDir() string               { return "/tmp/moleport" }
 
 type mockDaemonInfo struct {
-	status        protocol.DaemonStatusResult
-	shutdownFn    func(purge bool) error
-	lastPurgeFlag bool
+	status protocol.DaemonStatusResult
 }
 
-func (m *mockDaemonInfo) Status() protocol.DaemonStatusResult {
-	return m.status
-}
-
-func (m *mockDaemonInfo) Shutdown(purge bool) error {
-	m.lastPurgeFlag = purge
-	if m.shutdownFn != nil {
-		return m.shutdownFn(purge)
-	}
-	return nil
+func (m *mockDaemonInfo) Status() protocol.DaemonStatusResult { return m.status }
+func (m *mockDaemonInfo) Health(_ string, _ []string) protocol.DaemonHealthResult {
+	return protocol.DaemonHealthResult{Status: protocol.HealthOK}
 }
+func (m *mockDaemonInfo) Shutdown(_ bool) error { return nil }
 
 // --- Test helpers ---
 
@@ -254,7 +263,7 @@ func newTestHandler() (*Handler, *mockSSHManager, *mockForwardManager, *mockConf
 		}},
 	}
 	cfgMgr := &mockConfigManager{}
-	broker := ipc.NewEventBroker(func(_ string, _ protocol.Notification) error { return nil })
+	broker := broker.NewEventBroker(func(_ string, _ protocol.Notification) error { return nil })
 	daemon := &mockDaemonInfo{status: protocol.DaemonStatusResult{
 		Version: "test", PID: 1234, StartedAt: "2025-01-01T00:00:00Z",
 		Uptime: "1h0m0s", ConnectedClients: 2,
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
@@ -8,9 +9,10 @@ import (
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
 )
 
-func (h *Handler) sshConnect(clientID string, params json.RawMessage) (any, *protocol.RPCError) {
+func (h *Handler) sshConnect(ctx context.Context, clientID string, params json.RawMessage) (any, *protocol.RPCError) {
 	var p protocol.SSHConnectParams
 	if err := parseParams(params, &p); err != nil {
 		return nil, err
@@ -20,10 +22,10 @@ func (h *Handler) sshConnect(clientID string, params json.RawMessage) (any, *pro
 	}
 
 	// クレデンシャルコールバックを構築
-	cb := h.buildCredentialCallback(clientID, p.Host)
+	cb := h.buildCredentialCallback(ctx, clientID, p.Host)
 
-	if err := h.sshMgr.ConnectWithCallback(p.Host, cb); err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+	if err := h.sshMgr.ConnectWithCallback(ctx, p.Host, cb); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 
 	return protocol.SSHConnectResult{
@@ -33,7 +35,8 @@ func (h *Handler) sshConnect(clientID string, params json.RawMessage) (any, *pro
 }
 
 // buildCredentialCallback はクライアントへの通知とレスポンス待機を行うコールバックを構築する。
-func (h *Handler) buildCredentialCallback(clientID string, _ string) core.CredentialCallback {
+// レスポンスを待つ間に ctx がキャンセルされた場合は ctx のエラーを返す。
+func (h *Handler) buildCredentialCallback(ctx context.Context, clientID string, _ string) core.CredentialCallback {
 	if h.sender == nil {
 		slog.Warn("credential callback skipped: notification sender not set")
 		return nil
@@ -55,16 +58,15 @@ func (h *Handler) buildCredentialCallback(clientID string, _ string) core.Creden
 
 		// credential.request 通知をクライアントに送信
 		notif := protocol.CredentialRequestNotification{
-			RequestID: reqID,
-			Type:      string(req.Type),
-			Host:      req.Host,
-			Prompt:    req.Prompt,
+			RequestID:   reqID,
+			Type:        string(req.Type),
+			Host:        req.Host,
+			Prompt:      req.Prompt,
+			KeyType:     req.KeyType,
+			Fingerprint: req.Fingerprint,
 		}
-		if len(req.Prompts) > 0 {
-			notif.Prompts = make([]protocol.PromptData, len(req.Prompts))
-			for i, p := range req.Prompts {
-				notif.Prompts[i] = protocol.PromptData{Prompt: p.Prompt, Echo: p.Echo}
-			}
+		for _, p := range req.Prompts {
+			notif.Prompts = append(notif.Prompts, protocol.PromptData{Prompt: p.Prompt, Echo: p.Echo})
 		}
 
 		data, err := json.Marshal(notif)
@@ -93,6 +95,8 @@ func (h *Handler) buildCredentialCallback(clientID string, _ string) core.Creden
 			}, nil
 		case <-time.After(credentialTimeout):
 			return core.CredentialResponse{}, core.ErrCredentialTimeout
+		case <-ctx.Done():
+			return core.CredentialResponse{}, ctx.Err()
 		}
 	}
 }
@@ -138,7 +142,7 @@ func (h *Handler) sshDisconnect(params json.RawMessage) (any, *protocol.RPCError
 	}
 
 	if err := h.sshMgr.Disconnect(p.Host); err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 
 	return protocol.SSHDisconnectResult{
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"sync"
 	"testing"
 	"time"
@@ -37,7 +39,7 @@ func TestHandler_SSHConnect_Success(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
 	params := mustMarshal(t, protocol.SSHConnectParams{Host: "prod"})
-	result, rpcErr := h.Handle("client-1", "ssh.connect", params)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "ssh.connect", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -61,7 +63,7 @@ func TestHandler_SSHConnect_Error(t *testing.T) {
 	}
 
 	params := mustMarshal(t, protocol.SSHConnectParams{Host: "nonexistent"})
-	_, rpcErr := h.Handle("client-1", "ssh.connect", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "ssh.connect", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error")
 	}
@@ -74,7 +76,7 @@ func TestHandler_SSHDisconnect_Success(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
 	params := mustMarshal(t, protocol.SSHDisconnectParams{Host: "prod"})
-	result, rpcErr := h.Handle("client-1", "ssh.disconnect", params)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "ssh.disconnect", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -95,7 +97,7 @@ func TestHandler_SSHDisconnect_NotConnected(t *testing.T) {
 	}
 
 	params := mustMarshal(t, protocol.SSHDisconnectParams{Host: "prod"})
-	_, rpcErr := h.Handle("client-1", "ssh.disconnect", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "ssh.disconnect", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error")
 	}
@@ -113,7 +115,7 @@ func TestHandler_CredentialResponse_NoPending(t *testing.T) {
 		Value:     "secret",
 	})
 
-	_, rpcErr := h.Handle("client-1", "credential.response", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "credential.response", params)
 	if rpcErr == nil {
 		t.Fatal("expected error for non-existent credential request")
 	} else if rpcErr.Code != protocol.InvalidParams {
@@ -135,7 +137,7 @@ func TestHandler_CredentialResponse_RoutesToPending(t *testing.T) {
 		Value:     "my-password",
 	})
 
-	result, rpcErr := h.Handle("client-1", "credential.response", params)
+	result, rpcErr := h.Handle(t.Context(), "client-1", "credential.response", params)
 	if rpcErr != nil {
 		t.Fatalf("unexpected error: %v", rpcErr)
 	}
@@ -167,7 +169,7 @@ func TestHandler_BuildCredentialCallback_SendsNotification(t *testing.T) {
 	sender := &mockNotificationSender{}
 	h.SetSender(sender)
 
-	cb := h.buildCredentialCallback("client-1", "test-host")
+	cb := h.buildCredentialCallback(t.Context(), "client-1", "test-host")
 	if cb == nil {
 		t.Fatal("callback should not be nil when sender is set")
 	}
@@ -220,7 +222,7 @@ func TestHandler_BuildCredentialCallback_SendsNotification(t *testing.T) {
 		RequestID: credReq.RequestID,
 		Value:     "secret-pwd",
 	})
-	if _, err := h.Handle("client-1", "credential.response", respParams); err != nil {
+	if _, err := h.Handle(t.Context(), "client-1", "credential.response", respParams); err != nil {
 		t.Fatalf("Handle credential.response failed: %v", err)
 	}
 
@@ -235,7 +237,7 @@ func TestHandler_BuildCredentialCallback_SendsNotification(t *testing.T) {
 func TestHandler_SSHConnect_EmptyHost(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 	params := mustMarshal(t, protocol.SSHConnectParams{Host: ""})
-	_, rpcErr := h.Handle("client-1", "ssh.connect", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "ssh.connect", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error for empty host")
 	}
@@ -247,7 +249,7 @@ func TestHandler_SSHConnect_EmptyHost(t *testing.T) {
 func TestHandler_SSHDisconnect_EmptyHost(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 	params := mustMarshal(t, protocol.SSHDisconnectParams{Host: ""})
-	_, rpcErr := h.Handle("client-1", "ssh.disconnect", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "ssh.disconnect", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error for empty host")
 	}
@@ -259,7 +261,7 @@ func TestHandler_SSHDisconnect_EmptyHost(t *testing.T) {
 func TestHandler_CredentialResponse_EmptyRequestID(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 	params := mustMarshal(t, protocol.CredentialResponseParams{RequestID: ""})
-	_, rpcErr := h.Handle("client-1", "credential.response", params)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "credential.response", params)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error for empty request_id")
 	}
@@ -271,8 +273,21 @@ func TestHandler_CredentialResponse_EmptyRequestID(t *testing.T) {
 func TestHandler_BuildCredentialCallback_NilSender(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 	// sender が nil の場合、コールバックは nil を返す
-	cb := h.buildCredentialCallback("client-1", "test-host")
+	cb := h.buildCredentialCallback(t.Context(), "client-1", "test-host")
 	if cb != nil {
 		t.Error("callback should be nil when sender is nil")
 	}
 }
+
+func TestHandler_BuildCredentialCallback_Cancelled(t *testing.T) {
+	h, _, _, _ := newTestHandler()
+	h.SetSender(&mockNotificationSender{})
+	ctx, cancel := context.WithCancel(t.Context())
+	cb := h.buildCredentialCallback(ctx, "client-1", "test-host")
+
+	// 入力待ちの間に取り消された場合は ctx のエラーで打ち切る
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if _, err := cb(core.CredentialRequest{Type: core.CredentialPassword, Host: "test-host"}); !errors.Is(err, context.Canceled) {
+		t.Errorf("callback error = %v, want context.Canceled", err)
+	}
+}
@@ -9,7 +9,7 @@ import (
 func TestHandler_MethodNotFound(t *testing.T) {
 	h, _, _, _ := newTestHandler()
 
-	_, rpcErr := h.Handle("client-1", "nonexistent.method", nil)
+	_, rpcErr := h.Handle(t.Context(), "client-1", "nonexistent.method", nil)
 	if rpcErr == nil {
 		t.Fatal("expected RPC error")
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
 )
 
 func (h *Handler) versionCheck() (any, *protocol.RPCError) {
@@ -13,7 +14,7 @@ func (h *Handler) versionCheck() (any, *protocol.RPCError) {
 	}
 	result, err := h.versionChecker.LatestVersion(context.Background())
 	if err != nil {
-		return nil, protocol.ToRPCError(err, protocol.InternalError)
+		return nil, rpcerr.From(err, protocol.InternalError)
 	}
 	resp := protocol.VersionCheckResult{CurrentVersion: ver}
 	if result != nil {
@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
-	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
@@ -22,7 +22,7 @@ func (m *mockVersionChecker) LatestVersion(_ context.Context) (*core.VersionChec
 
 func newVCHandler(ver string, enabled bool, vc VersionChecker) *Handler {
 	cfg := &mockConfigManager{config: &core.Config{UpdateCheck: core.UpdateCheckConfig{Enabled: enabled}}}
-	b := ipc.NewEventBroker(func(_ string, _ protocol.Notification) error { return nil })
+	b := broker.NewEventBroker(func(_ string, _ protocol.Notification) error { return nil })
 	return NewHandler(&mockSSHManager{}, &mockForwardManager{}, cfg, b,
 		&mockDaemonInfo{status: protocol.DaemonStatusResult{Version: ver}}, vc)
 }
@@ -49,7 +49,7 @@ func TestHandler_VersionCheck(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, rpcErr := newVCHandler(tt.ver, tt.enabled, tt.vc).Handle("client-1", "version.check", nil)
+			result, rpcErr := newVCHandler(tt.ver, tt.enabled, tt.vc).Handle(t.Context(), "client-1", "version.check", nil)
 			if tt.wantErr {
 				if rpcErr == nil {
 					t.Fatal("expected RPC error")
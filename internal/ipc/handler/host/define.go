@@ -0,0 +1,83 @@
+package host
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// Add は host.add リクエストを処理する。
+// config.yaml の host_definitions にホストを追加し、ホスト一覧を再読み込みする。
+func (h *Handler) Add(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.HostAddParams
+	if rpcErr := parseParams(params, &p); rpcErr != nil {
+		return nil, rpcErr
+	}
+	def := core.HostDefinition(p)
+	if err := def.Validate(); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
+	if _, err := h.hosts.GetHost(def.Name); err == nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: (&core.AlreadyExistsError{Resource: "host", Name: def.Name}).Error()}
+	}
+
+	if err := h.cfgMgr.UpdateConfig(func(c *core.Config) {
+		c.HostDefinitions = append(c.HostDefinitions, def)
+	}); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	if _, err := h.hosts.ReloadHosts(); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	host, err := h.hosts.GetHost(def.Name)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	return protocol.HostAddResult{Host: convert.ToHostInfo(*host)}, nil
+}
+
+// Delete は host.delete リクエストを処理する。
+// config.yaml で定義したホストのみ削除でき、ssh_config のホストは削除できない。
+func (h *Handler) Delete(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.HostDeleteParams
+	if rpcErr := parseParams(params, &p); rpcErr != nil {
+		return nil, rpcErr
+	}
+	defined := slices.ContainsFunc(h.cfgMgr.GetConfig().HostDefinitions, func(d core.HostDefinition) bool {
+		return d.Name == p.Name
+	})
+	if !defined {
+		if _, err := h.hosts.GetHost(p.Name); err == nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: fmt.Sprintf("host %q is defined in ssh_config and cannot be deleted", p.Name)}
+		}
+		return nil, rpcerr.From(&core.NotFoundError{Resource: "host", Name: p.Name}, protocol.HostNotFound)
+	}
+
+	if err := h.cfgMgr.UpdateConfig(func(c *core.Config) {
+		c.HostDefinitions = slices.DeleteFunc(c.HostDefinitions, func(d core.HostDefinition) bool {
+			return d.Name == p.Name
+		})
+	}); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	if _, err := h.hosts.ReloadHosts(); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	return protocol.HostDeleteResult{OK: true}, nil
+}
+
+// parseParams は必須のリクエストパラメータを target にアンマーシャルする。
+func parseParams(params json.RawMessage, target any) *protocol.RPCError {
+	if len(params) == 0 {
+		return &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, target); err != nil {
+		return &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
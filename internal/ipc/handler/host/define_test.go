@@ -0,0 +1,81 @@
+package host
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ssh/hostdefs"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// definingSource は ReloadHosts で config.yaml のホスト定義を統合する HostSource。
+type definingSource struct {
+	mockHostSource
+	base   []core.SSHHost
+	cfgMgr *mockConfigManager
+}
+
+func (s *definingSource) ReloadHosts() ([]core.SSHHost, error) {
+	s.hosts = hostdefs.Merge(append([]core.SSHHost{}, s.base...), s.cfgMgr.GetConfig().HostDefinitions)
+	return s.hosts, nil
+}
+
+func newDefiningHandler() (*Handler, *mockConfigManager) {
+	base := []core.SSHHost{{Name: "prod", HostName: "prod.example.com", Port: 22, Source: core.HostSourceSSHConfig}}
+	cfgMgr := &mockConfigManager{}
+	src := &definingSource{mockHostSource: mockHostSource{hosts: base}, base: base, cfgMgr: cfgMgr}
+	return New(src, &mockRuleStore{}, cfgMgr), cfgMgr
+}
+
+func TestAddAndDelete(t *testing.T) {
+	h, cfgMgr := newDefiningHandler()
+
+	params := protocol.HostAddParams{Name: "bastion", HostName: "203.0.113.10", User: "ops", ProxyJump: "prod"}
+	result, rpcErr := h.Handle("host.add", mustMarshal(t, params))
+	if rpcErr != nil {
+		t.Fatalf("host.add error: %v", rpcErr)
+	}
+	info := result.(protocol.HostAddResult).Host
+	if info.Name != "bastion" || info.Port != 22 || info.Source != core.HostSourceConfig {
+		t.Errorf("host = %+v, want bastion on port 22 from config", info)
+	}
+	if defs := cfgMgr.GetConfig().HostDefinitions; len(defs) != 1 || defs[0].ProxyJump != "prod" {
+		t.Errorf("HostDefinitions = %+v", defs)
+	}
+
+	if _, rpcErr := h.Handle("host.add", mustMarshal(t, params)); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("duplicate host.add error = %v, want InvalidParams", rpcErr)
+	}
+	if _, rpcErr := h.Handle("host.delete", mustMarshal(t, protocol.HostDeleteParams{Name: "prod"})); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("deleting ssh_config host error = %v, want InvalidParams", rpcErr)
+	}
+	if _, rpcErr := h.Handle("host.delete", mustMarshal(t, protocol.HostDeleteParams{Name: "bastion"})); rpcErr != nil {
+		t.Fatalf("host.delete error: %v", rpcErr)
+	}
+	if len(cfgMgr.GetConfig().HostDefinitions) != 0 {
+		t.Error("host definition should be removed")
+	}
+	if _, err := h.hosts.GetHost("bastion"); err == nil {
+		t.Error("deleted host should disappear after reload")
+	}
+	if _, rpcErr := h.Handle("host.delete", mustMarshal(t, protocol.HostDeleteParams{Name: "bastion"})); rpcErr == nil || rpcErr.Code != protocol.HostNotFound {
+		t.Errorf("deleting unknown host error = %v, want HostNotFound", rpcErr)
+	}
+}
+
+func TestAdd_Invalid(t *testing.T) {
+	h, _ := newDefiningHandler()
+	for _, p := range []protocol.HostAddParams{
+		{Name: "", HostName: "example.com"},
+		{Name: "web*", HostName: "example.com"},
+		{Name: "web", HostName: ""},
+		{Name: "web", HostName: "example.com", Port: 70000},
+	} {
+		if _, rpcErr := h.Add(mustMarshal(t, p)); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+			t.Errorf("Add(%+v) error = %v, want InvalidParams", p, rpcErr)
+		}
+	}
+	if _, rpcErr := h.Handle("host.unknown", nil); rpcErr == nil || rpcErr.Code != protocol.MethodNotFound {
+		t.Errorf("unknown method error = %v, want MethodNotFound", rpcErr)
+	}
+}
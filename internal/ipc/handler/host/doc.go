@@ -0,0 +1,2 @@
+// Package host はホスト関連リクエスト（host.*）のハンドラを提供する。
+package host
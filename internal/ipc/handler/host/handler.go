@@ -0,0 +1,237 @@
+package host
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/revision"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/listopt"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// HostSource はホスト一覧の取得元。core.SSHManager が満たす。
+type HostSource interface {
+	LoadHosts() ([]core.SSHHost, error)
+	ReloadHosts() ([]core.SSHHost, error)
+	GetHosts() []core.SSHHost
+	GetHost(name string) (*core.SSHHost, error)
+	IsConnected(hostName string) bool
+	GetSSHConnection(hostName string) (core.SSHConnection, error)
+}
+
+// Handler はホスト関連の JSON-RPC メソッドを処理する。
+type Handler struct {
+	hosts  HostSource
+	rules  RuleStore
+	cfgMgr core.ConfigManager
+	health func(name string) core.HostHealth
+	// reloaded は host.reload でホスト一覧を再読み込みした後に呼ぶ関数。nil の場合は呼ばない。
+	reloaded func()
+	// revs は host.list・host.changes で返したホスト情報の変更を記録する。
+	revs *revision.Tracker[protocol.HostInfo]
+}
+
+// New は新しいホストハンドラを生成する。
+func New(hosts HostSource, rules RuleStore, cfgMgr core.ConfigManager) *Handler {
+	return &Handler{
+		hosts: hosts, rules: rules, cfgMgr: cfgMgr,
+		revs: revision.New(func(a, b protocol.HostInfo) bool { return reflect.DeepEqual(a, b) }),
+	}
+}
+
+// SetHealth はホストの疎通確認結果の取得元を設定する。設定しない場合、host.list は到達性を返さない。
+func (h *Handler) SetHealth(health func(name string) core.HostHealth) {
+	h.health = health
+}
+
+// SetReloaded は host.reload でホスト一覧を再読み込みした後に呼ぶ関数を設定する。
+func (h *Handler) SetReloaded(fn func()) {
+	h.reloaded = fn
+}
+
+// Handle は host.* メソッドをディスパッチする。
+func (h *Handler) Handle(method string, params json.RawMessage) (any, *protocol.RPCError) {
+	switch method {
+	case "host.list":
+		return h.List(params)
+	case "host.changes":
+		return h.Changes(params)
+	case "host.reload":
+		return h.Reload()
+	case "host.stats":
+		return h.Stats()
+	case "host.update":
+		return h.Update(params)
+	case "host.add":
+		return h.Add(params)
+	case "host.delete":
+		return h.Delete(params)
+	case "host.importForwards":
+		return h.ImportForwards(params)
+	default:
+		return nil, &protocol.RPCError{Code: protocol.MethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+// List は host.list リクエストを処理する。
+// config.yaml に保存されたホストのメタデータを併せて返す。params で状態の絞り込み・ページング・フィールド選択を指定できる。
+func (h *Handler) List(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.HostListParams
+	// params が nil や空の場合は全件を返す
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+
+	infos, err := h.hostInfos()
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	rev := h.revs.Observe(infos, hostName)
+	infos, total, err := listopt.Apply(infos, p.Options, func(info protocol.HostInfo) string { return info.State })
+	if err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
+	return protocol.HostListResult{Hosts: infos, Total: total, Revision: rev}, nil
+}
+
+// Changes は host.changes リクエストを処理する。since の後に追加・変更されたホストと削除されたホスト名を返す。
+// 差分を返せない場合は Reset を true にして全件を返す。
+func (h *Handler) Changes(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.HostChangesParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+
+	infos, err := h.hostInfos()
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	h.revs.Observe(infos, hostName)
+	rev, changed, removed, ok := h.revs.Changes(p.Since)
+	if !ok {
+		return protocol.HostChangesResult{Revision: rev, Reset: true, Hosts: infos, Removed: []string{}}, nil
+	}
+	if changed == nil {
+		changed = []protocol.HostInfo{}
+	}
+	if removed == nil {
+		removed = []string{}
+	}
+	return protocol.HostChangesResult{Revision: rev, Hosts: changed, Removed: removed}, nil
+}
+
+// hostInfos は全ホストの情報を、config.yaml のメタデータと疎通確認の結果を併せて返す。
+func (h *Handler) hostInfos() ([]protocol.HostInfo, error) {
+	hosts, err := h.hosts.LoadHosts()
+	if err != nil {
+		return nil, err
+	}
+
+	hostCfgs := h.cfgMgr.GetConfig().Hosts
+	infos := make([]protocol.HostInfo, len(hosts))
+	for i, host := range hosts {
+		host.Meta = hostCfgs[host.Name].HostMetadata
+		if h.health != nil {
+			host.Health = h.health(host.Name)
+		}
+		infos[i] = convert.ToHostInfo(host)
+	}
+	return infos, nil
+}
+
+func hostName(info protocol.HostInfo) string { return info.Name }
+
+// Reload は host.reload リクエストを処理する。
+func (h *Handler) Reload() (any, *protocol.RPCError) {
+	before := h.hosts.GetHosts()
+	after, err := h.hosts.ReloadHosts()
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	if h.reloaded != nil {
+		h.reloaded()
+	}
+
+	added, removed := core.DiffHostNames(before, after)
+	if added == nil {
+		added = []string{}
+	}
+	if removed == nil {
+		removed = []string{}
+	}
+
+	var importable []protocol.ForwardInfo
+	if candidates := h.importable(after); len(candidates) > 0 {
+		importable = toForwardInfos(candidates)
+	}
+
+	return protocol.HostReloadResult{
+		Total:      len(after),
+		Added:      added,
+		Removed:    removed,
+		Importable: importable,
+	}, nil
+}
+
+// Update は host.update リクエストを処理する。
+// 指定されたメタデータのみ更新し、すべて空になったホストの設定エントリは削除する。
+func (h *Handler) Update(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.HostUpdateParams
+	if len(params) == 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	if p.Name == "" {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "name is required"}
+	}
+
+	host, err := h.hosts.GetHost(p.Name)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.HostNotFound)
+	}
+
+	var meta core.HostMetadata
+	if err := h.cfgMgr.UpdateConfig(func(cfg *core.Config) {
+		if cfg.Hosts == nil {
+			cfg.Hosts = make(map[string]core.HostConfig)
+		}
+		hc := cfg.Hosts[p.Name]
+		if p.Notes != nil {
+			hc.Notes = *p.Notes
+		}
+		if p.AuthHint != nil {
+			hc.AuthHint = *p.AuthHint
+		}
+		if p.JumpDescription != nil {
+			hc.JumpDescription = *p.JumpDescription
+		}
+		if p.Tags != nil {
+			hc.Tags = *p.Tags
+		}
+		// 空のタグは未設定と同じ扱いにして、エントリが空かをすべてのフィールドで判定する
+		if len(hc.Tags) == 0 {
+			hc.Tags = nil
+		}
+		if reflect.ValueOf(hc).IsZero() {
+			delete(cfg.Hosts, p.Name)
+		} else {
+			cfg.Hosts[p.Name] = hc
+		}
+		meta = hc.HostMetadata
+	}); err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+
+	updated := *host
+	updated.Meta = meta
+	return protocol.HostUpdateResult{Host: convert.ToHostInfo(updated)}, nil
+}
@@ -0,0 +1,170 @@
+package host
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/listopt"
+)
+
+// --- Mock implementations ---
+
+type mockHostSource struct {
+	hosts []core.SSHHost
+	conns map[string]core.SSHConnection
+}
+
+func (m *mockHostSource) LoadHosts() ([]core.SSHHost, error)   { return m.hosts, nil }
+func (m *mockHostSource) ReloadHosts() ([]core.SSHHost, error) { return m.hosts, nil }
+func (m *mockHostSource) GetHosts() []core.SSHHost             { return m.hosts }
+func (m *mockHostSource) GetHost(name string) (*core.SSHHost, error) {
+	for _, h := range m.hosts {
+		if h.Name == name {
+			return &h, nil
+		}
+	}
+	return nil, &core.NotFoundError{Resource: "host", Name: name}
+}
+func (m *mockHostSource) IsConnected(name string) bool { return m.conns[name] != nil }
+func (m *mockHostSource) GetSSHConnection(name string) (core.SSHConnection, error) {
+	if conn, ok := m.conns[name]; ok {
+		return conn, nil
+	}
+	return nil, &core.NotFoundError{Resource: "connection", Name: name}
+}
+
+type mockConfigManager struct {
+	config *core.Config
+}
+
+func (m *mockConfigManager) LoadConfig() (*core.Config, error)    { return m.GetConfig(), nil }
+func (m *mockConfigManager) SaveConfig(config *core.Config) error { return nil }
+func (m *mockConfigManager) GetConfig() *core.Config {
+	if m.config == nil {
+		cfg := core.DefaultConfig()
+		m.config = &cfg
+	}
+	return m.config
+}
+func (m *mockConfigManager) UpdateConfig(fn func(*core.Config)) error {
+	fn(m.GetConfig())
+	return nil
+}
+func (m *mockConfigManager) LoadState() (*core.State, error) { return &core.State{}, nil }
+func (m *mockConfigManager) SaveState(_ *core.State) error   { return nil }
+func (m *mockConfigManager) DeleteState() error              { return nil }
+func (m *mockConfigManager) ConfigDir() string               { return "/tmp/moleport" }
+
+// --- Helpers ---
+
+func newTestHandler() (*Handler, *mockConfigManager) {
+	src := &mockHostSource{hosts: []core.SSHHost{
+		{Name: "prod", HostName: "prod.example.com", Port: 22, User: "deploy", State: core.Connected},
+		{Name: "staging", HostName: "staging.example.com", Port: 22, User: "deploy", State: core.Disconnected},
+	}}
+	cfgMgr := &mockConfigManager{}
+	return New(src, &mockRuleStore{}, cfgMgr), cfgMgr
+}
+
+func mustMarshal(t *testing.T, v any) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
+
+// --- Tests ---
+
+func TestList_IncludesHealth(t *testing.T) {
+	h, _ := newTestHandler()
+	h.SetHealth(func(name string) core.HostHealth {
+		if name == "prod" {
+			return core.HealthUnreachable
+		}
+		return core.HealthUnknown
+	})
+
+	res, rpcErr := h.List(nil)
+	if rpcErr != nil {
+		t.Fatalf("List: %v", rpcErr)
+	}
+	hosts := res.(protocol.HostListResult).Hosts
+	if hosts[0].Health != "unreachable" || hosts[1].Health != "" {
+		t.Errorf("health = (%q, %q), want (unreachable, empty)", hosts[0].Health, hosts[1].Health)
+	}
+}
+
+func TestList_StateFilterAndFields(t *testing.T) {
+	h, _ := newTestHandler()
+
+	params := mustMarshal(t, protocol.HostListParams{Options: listopt.Options{State: "disconnected", Fields: []string{"state"}}})
+	res, rpcErr := h.List(params)
+	if rpcErr != nil {
+		t.Fatalf("List: %v", rpcErr)
+	}
+	list := res.(protocol.HostListResult)
+	if list.Total != 1 || len(list.Hosts) != 1 {
+		t.Fatalf("Total = %d, Hosts = %+v, want only staging", list.Total, list.Hosts)
+	}
+	want := protocol.HostInfo{Name: "staging", State: "disconnected"}
+	if !reflect.DeepEqual(list.Hosts[0], want) {
+		t.Errorf("Hosts[0] = %+v, want %+v", list.Hosts[0], want)
+	}
+
+	if _, rpcErr := h.List(json.RawMessage(`{"limit":-1}`)); rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("negative limit: error = %v, want InvalidParams", rpcErr)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	h, _ := newTestHandler()
+	res, rpcErr := h.List(nil)
+	if rpcErr != nil {
+		t.Fatalf("List: %v", rpcErr)
+	}
+	since := res.(protocol.HostListResult).Revision
+
+	h.SetHealth(func(name string) core.HostHealth {
+		if name == "staging" {
+			return core.HealthReachable
+		}
+		return core.HealthUnknown
+	})
+	res, rpcErr = h.Changes(mustMarshal(t, protocol.HostChangesParams{Since: since}))
+	if rpcErr != nil {
+		t.Fatalf("Changes: %v", rpcErr)
+	}
+	changes := res.(protocol.HostChangesResult)
+	if changes.Reset || changes.Revision <= since {
+		t.Fatalf("Reset = %v, Revision = %d, want a newer revision than %d", changes.Reset, changes.Revision, since)
+	}
+	if len(changes.Hosts) != 1 || changes.Hosts[0].Name != "staging" || changes.Hosts[0].Health != "reachable" {
+		t.Errorf("Hosts = %+v, want only staging with its new health", changes.Hosts)
+	}
+	if len(changes.Removed) != 0 {
+		t.Errorf("Removed = %v, want empty", changes.Removed)
+	}
+
+	res, _ = h.Changes(nil)
+	if changes := res.(protocol.HostChangesResult); !changes.Reset || len(changes.Hosts) != 2 {
+		t.Errorf("without since: %+v, want a reset with all hosts", changes)
+	}
+}
+
+func TestReload_CallsReloaded(t *testing.T) {
+	h, _ := newTestHandler()
+	calls := 0
+	h.SetReloaded(func() { calls++ })
+
+	if _, rpcErr := h.Reload(); rpcErr != nil {
+		t.Fatalf("Reload: %v", rpcErr)
+	}
+	if calls != 1 {
+		t.Errorf("reloaded calls = %d, want 1", calls)
+	}
+}
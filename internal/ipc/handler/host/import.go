@@ -0,0 +1,135 @@
+package host
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// RuleStore はフォワードルールの取得・追加先。core.ForwardManager が満たす。
+type RuleStore interface {
+	GetRules() []core.ForwardRule
+	AddRule(rule core.ForwardRule) (string, error)
+}
+
+// ImportForwards は host.importForwards リクエストを処理する。
+// ssh_config の LocalForward/RemoteForward/DynamicForward をルールとして取り込む。
+// DryRun なら候補を返すだけ、Ignore なら候補を見送りとして記録し以後の候補から除外する。
+func (h *Handler) ImportForwards(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.HostImportForwardsParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+	if p.Host != "" {
+		if _, err := h.hosts.GetHost(p.Host); err != nil {
+			return nil, rpcerr.From(err, protocol.HostNotFound)
+		}
+	}
+
+	var candidates []core.ForwardRule
+	for _, rule := range h.importable(h.hosts.GetHosts()) {
+		if p.Host != "" && rule.Host != p.Host {
+			continue
+		}
+		if len(p.ImportKeys) > 0 && !slices.Contains(p.ImportKeys, rule.ImportKey) {
+			continue
+		}
+		candidates = append(candidates, rule)
+	}
+
+	result := protocol.HostImportForwardsResult{Imported: []protocol.ForwardInfo{}}
+	switch {
+	case p.DryRun:
+		result.Imported = toForwardInfos(candidates)
+	case p.Ignore:
+		for _, rule := range candidates {
+			result.Ignored = append(result.Ignored, rule.ImportKey)
+		}
+		if err := h.cfgMgr.UpdateConfig(func(c *core.Config) {
+			c.IgnoredImports = append(c.IgnoredImports, result.Ignored...)
+		}); err != nil {
+			return nil, rpcerr.From(err, protocol.InternalError)
+		}
+	default:
+		imported, addErr := h.addRules(candidates)
+		// 途中のルールの追加に失敗した場合も、デーモンと設定ファイルが食い違わないよう追加済みのルールは保存する
+		if len(imported) > 0 {
+			if err := core.SaveRules(h.cfgMgr, h.rules.GetRules()); err != nil {
+				return nil, rpcerr.From(err, protocol.InternalError)
+			}
+		}
+		if addErr != nil {
+			return nil, rpcerr.From(addErr, protocol.InternalError)
+		}
+		result.Imported = toForwardInfos(imported)
+	}
+	return result, nil
+}
+
+// addRules は candidates を順にルールとして追加し、追加したルールを返す。
+// 追加に失敗した場合はそこで止め、それまでに追加したルールとエラーを返す。
+func (h *Handler) addRules(candidates []core.ForwardRule) ([]core.ForwardRule, error) {
+	var added []core.ForwardRule
+	for _, rule := range candidates {
+		name, err := h.rules.AddRule(rule)
+		var exists *core.AlreadyExistsError
+		if errors.As(err, &exists) {
+			// 生成名が既存ルールと衝突した場合は自動採番に任せる
+			rule.Name = ""
+			name, err = h.rules.AddRule(rule)
+		}
+		if err != nil {
+			return added, err
+		}
+		rule.Name = name
+		added = append(added, rule)
+	}
+	return added, nil
+}
+
+// importable は hosts の ConfigForwards のうち、取り込み済み・見送り済み・同等ルールが
+// 既にあるものを除いた候補を返す。
+func (h *Handler) importable(hosts []core.SSHHost) []core.ForwardRule {
+	existing := h.rules.GetRules()
+	keys := make(map[string]struct{}, len(existing))
+	for _, r := range existing {
+		if r.ImportKey != "" {
+			keys[r.ImportKey] = struct{}{}
+		}
+	}
+	for _, k := range h.cfgMgr.GetConfig().IgnoredImports {
+		keys[k] = struct{}{}
+	}
+
+	var result []core.ForwardRule
+	for _, host := range hosts {
+		for _, rule := range host.ConfigForwards {
+			if _, ok := keys[rule.ImportKey]; ok {
+				continue
+			}
+			if slices.ContainsFunc(existing, func(r core.ForwardRule) bool {
+				return r.Host == rule.Host && r.Type == rule.Type &&
+					r.LocalPort == rule.LocalPort && r.RemotePort == rule.RemotePort
+			}) {
+				continue
+			}
+			result = append(result, rule)
+		}
+	}
+	return result
+}
+
+func toForwardInfos(rules []core.ForwardRule) []protocol.ForwardInfo {
+	infos := make([]protocol.ForwardInfo, len(rules))
+	for i, r := range rules {
+		infos[i] = convert.ToForwardInfo(r)
+	}
+	return infos
+}
@@ -0,0 +1,142 @@
+package host
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+type mockRuleStore struct {
+	rules []core.ForwardRule
+	// failKey は追加に失敗させるルールの ImportKey。
+	failKey string
+}
+
+func (m *mockRuleStore) GetRules() []core.ForwardRule { return m.rules }
+func (m *mockRuleStore) AddRule(rule core.ForwardRule) (string, error) {
+	if m.failKey != "" && rule.ImportKey == m.failKey {
+		return "", errors.New("add failed")
+	}
+	if rule.Name == "" {
+		rule.Name = "forward-1"
+	}
+	for _, r := range m.rules {
+		if r.Name == rule.Name {
+			return "", &core.AlreadyExistsError{Resource: "rule", Name: rule.Name}
+		}
+	}
+	m.rules = append(m.rules, rule)
+	return rule.Name, nil
+}
+
+func newImportHandler(existing ...core.ForwardRule) (*Handler, *mockRuleStore, *mockConfigManager) {
+	src := &mockHostSource{hosts: []core.SSHHost{
+		{Name: "prod", ConfigForwards: []core.ForwardRule{
+			{Name: "prod-local-8080", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "db", RemotePort: 5432, ImportKey: "prod/LocalForward/8080 db:5432"},
+			{Name: "prod-dynamic-1080", Host: "prod", Type: core.Dynamic, LocalPort: 1080, ImportKey: "prod/DynamicForward/1080"},
+		}},
+		{Name: "staging"},
+	}}
+	rules := &mockRuleStore{rules: existing}
+	cfgMgr := &mockConfigManager{}
+	return New(src, rules, cfgMgr), rules, cfgMgr
+}
+
+func TestImportForwards_DryRun(t *testing.T) {
+	h, rules, _ := newImportHandler()
+
+	res, rpcErr := h.ImportForwards(mustMarshal(t, protocol.HostImportForwardsParams{DryRun: true}))
+	if rpcErr != nil {
+		t.Fatalf("ImportForwards: %v", rpcErr)
+	}
+	got := res.(protocol.HostImportForwardsResult).Imported
+	if len(got) != 2 {
+		t.Fatalf("Imported = %+v, want 2 candidates", got)
+	}
+	if len(rules.rules) != 0 {
+		t.Errorf("dry run added rules: %+v", rules.rules)
+	}
+}
+
+func TestImportForwards_NoDuplicates(t *testing.T) {
+	h, rules, cfgMgr := newImportHandler()
+
+	if _, rpcErr := h.ImportForwards(nil); rpcErr != nil {
+		t.Fatalf("ImportForwards: %v", rpcErr)
+	}
+	if len(rules.rules) != 2 || len(cfgMgr.GetConfig().Forwards) != 2 {
+		t.Fatalf("rules = %+v, config = %+v", rules.rules, cfgMgr.GetConfig().Forwards)
+	}
+
+	res, rpcErr := h.ImportForwards(nil)
+	if rpcErr != nil {
+		t.Fatalf("re-import: %v", rpcErr)
+	}
+	if got := res.(protocol.HostImportForwardsResult).Imported; len(got) != 0 {
+		t.Errorf("re-import Imported = %+v, want none", got)
+	}
+}
+
+func TestImportForwards_SkipsEquivalentRule(t *testing.T) {
+	h, _, _ := newImportHandler(core.ForwardRule{Name: "socks", Host: "prod", Type: core.Dynamic, LocalPort: 1080})
+
+	res, _ := h.ImportForwards(mustMarshal(t, protocol.HostImportForwardsParams{DryRun: true}))
+	got := res.(protocol.HostImportForwardsResult).Imported
+	if len(got) != 1 || got[0].ImportKey != "prod/LocalForward/8080 db:5432" {
+		t.Errorf("Imported = %+v, want only LocalForward candidate", got)
+	}
+}
+
+func TestImportForwards_NameCollision(t *testing.T) {
+	h, rules, _ := newImportHandler(core.ForwardRule{Name: "prod-local-8080", Host: "prod", Type: core.Local, LocalPort: 9000, RemotePort: 80})
+
+	keys := []string{"prod/LocalForward/8080 db:5432"}
+	if _, rpcErr := h.ImportForwards(mustMarshal(t, protocol.HostImportForwardsParams{ImportKeys: keys})); rpcErr != nil {
+		t.Fatalf("ImportForwards: %v", rpcErr)
+	}
+	if len(rules.rules) != 2 || rules.rules[1].Name != "forward-1" {
+		t.Errorf("rules = %+v, want auto-named import", rules.rules)
+	}
+}
+
+func TestImportForwards_Ignore(t *testing.T) {
+	h, _, cfgMgr := newImportHandler()
+
+	params := protocol.HostImportForwardsParams{Host: "prod", ImportKeys: []string{"prod/DynamicForward/1080"}, Ignore: true}
+	if _, rpcErr := h.ImportForwards(mustMarshal(t, params)); rpcErr != nil {
+		t.Fatalf("ImportForwards: %v", rpcErr)
+	}
+	if got := cfgMgr.GetConfig().IgnoredImports; len(got) != 1 {
+		t.Fatalf("IgnoredImports = %v", got)
+	}
+
+	res, _ := h.Reload()
+	if got := res.(protocol.HostReloadResult).Importable; len(got) != 1 {
+		t.Errorf("Importable = %+v, want 1 after ignore", got)
+	}
+}
+
+func TestImportForwards_UnknownHost(t *testing.T) {
+	h, _, _ := newImportHandler()
+
+	_, rpcErr := h.ImportForwards(mustMarshal(t, protocol.HostImportForwardsParams{Host: "nope"}))
+	if rpcErr == nil || rpcErr.Code != protocol.HostNotFound {
+		t.Errorf("rpcErr = %v, want HostNotFound", rpcErr)
+	}
+}
+
+func TestImportForwards_PartialFailureSavesAddedRules(t *testing.T) {
+	h, rules, cfgMgr := newImportHandler()
+	rules.failKey = "prod/DynamicForward/1080"
+
+	if _, rpcErr := h.ImportForwards(nil); rpcErr == nil {
+		t.Fatal("ImportForwards should fail when a rule cannot be added")
+	}
+	// 失敗より前に追加したルールは設定ファイルにも保存されている
+	saved := cfgMgr.GetConfig().Forwards
+	if len(rules.rules) != 1 || len(saved) != 1 || saved[0].Name != "prod-local-8080" {
+		t.Errorf("rules = %+v, saved = %+v, want prod-local-8080 in both", rules.rules, saved)
+	}
+}
@@ -0,0 +1,28 @@
+package host
+
+import (
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Stats は host.stats リクエストを処理する。
+// 接続中のホストについて、keepalive で計測した直近の往復時間を返す。
+func (h *Handler) Stats() (any, *protocol.RPCError) {
+	result := protocol.HostStatsResult{Hosts: []protocol.HostStats{}}
+	for _, host := range h.hosts.GetHosts() {
+		if !h.hosts.IsConnected(host.Name) {
+			continue
+		}
+		conn, err := h.hosts.GetSSHConnection(host.Name)
+		if err != nil {
+			// 応答までの間に切断されたホストは含めない
+			continue
+		}
+		result.Hosts = append(result.Hosts, protocol.HostStats{
+			Name:      host.Name,
+			LatencyMs: float64(conn.RTT()) / float64(time.Millisecond),
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,36 @@
+package host
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/forwardtest"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestStats(t *testing.T) {
+	src := &mockHostSource{
+		hosts: []core.SSHHost{{Name: "prod"}, {Name: "staging"}, {Name: "fresh"}},
+		conns: map[string]core.SSHConnection{
+			"prod":  &forwardtest.MockSSHConnection{Latency: 12500 * time.Microsecond},
+			"fresh": &forwardtest.MockSSHConnection{},
+		},
+	}
+	h := New(src, &mockRuleStore{}, &mockConfigManager{})
+
+	result, rpcErr := h.Handle("host.stats", nil)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	stats := result.(protocol.HostStatsResult)
+	want := []protocol.HostStats{{Name: "prod", LatencyMs: 12.5}, {Name: "fresh"}}
+	if len(stats.Hosts) != len(want) {
+		t.Fatalf("Hosts = %+v, want %+v", stats.Hosts, want)
+	}
+	for i := range want {
+		if stats.Hosts[i] != want[i] {
+			t.Errorf("Hosts[%d] = %+v, want %+v", i, stats.Hosts[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package host
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestUpdate(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+
+	notes := "shared bastion"
+	hint := "use yubikey"
+	result, rpcErr := h.Update(mustMarshal(t, protocol.HostUpdateParams{Name: "prod", Notes: &notes, AuthHint: &hint}))
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+
+	updateResult, ok := result.(protocol.HostUpdateResult)
+	if !ok {
+		t.Fatalf("result type = %T, want protocol.HostUpdateResult", result)
+	}
+	if updateResult.Host.Notes != notes {
+		t.Errorf("Notes = %q, want %q", updateResult.Host.Notes, notes)
+	}
+	if updateResult.Host.AuthHint != hint {
+		t.Errorf("AuthHint = %q, want %q", updateResult.Host.AuthHint, hint)
+	}
+	if got := cfgMgr.config.Hosts["prod"].Notes; got != notes {
+		t.Errorf("persisted Notes = %q, want %q", got, notes)
+	}
+
+	// host.list にメタデータが反映される
+	result, rpcErr = h.List(nil)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	hostList := result.(protocol.HostListResult)
+	if hostList.Hosts[0].AuthHint != hint {
+		t.Errorf("List AuthHint = %q, want %q", hostList.Hosts[0].AuthHint, hint)
+	}
+	if hostList.Hosts[1].Notes != "" {
+		t.Errorf("staging Notes = %q, want empty", hostList.Hosts[1].Notes)
+	}
+}
+
+func TestUpdate_ClearRemovesEntry(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+	cfg := core.DefaultConfig()
+	cfg.Hosts = map[string]core.HostConfig{
+		"prod": {HostMetadata: core.HostMetadata{Notes: "old"}},
+	}
+	cfgMgr.config = &cfg
+
+	empty := ""
+	if _, rpcErr := h.Update(mustMarshal(t, protocol.HostUpdateParams{Name: "prod", Notes: &empty})); rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	if _, ok := cfgMgr.config.Hosts["prod"]; ok {
+		t.Error("empty host entry should be removed from config")
+	}
+}
+
+func TestUpdate_KeepsReconnectOverride(t *testing.T) {
+	h, cfgMgr := newTestHandler()
+	maxRetries := 3
+	cfg := core.DefaultConfig()
+	cfg.Hosts = map[string]core.HostConfig{
+		"prod": {Reconnect: &core.ReconnectOverride{MaxRetries: &maxRetries}},
+	}
+	cfgMgr.config = &cfg
+
+	notes := "keep override"
+	if _, rpcErr := h.Update(mustMarshal(t, protocol.HostUpdateParams{Name: "prod", Notes: &notes})); rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	hc := cfgMgr.config.Hosts["prod"]
+	if hc.Reconnect == nil || *hc.Reconnect.MaxRetries != 3 {
+		t.Errorf("Reconnect override lost: %+v", hc.Reconnect)
+	}
+	if hc.Notes != notes {
+		t.Errorf("Notes = %q, want %q", hc.Notes, notes)
+	}
+}
+
+func TestUpdate_ClearKeepsOtherSettings(t *testing.T) {
+	interval := core.Duration{Duration: 30 * time.Second}
+	countMax := 5
+	tests := map[string]core.HostConfig{
+		"connect_timeout":     {ConnectTimeout: &interval},
+		"keepalive_interval":  {KeepAliveInterval: &interval},
+		"keepalive_count_max": {KeepAliveCountMax: &countMax},
+		"credentials":         {Credentials: &core.CredentialSource{}},
+		"forwards":            {Forwards: []string{"L 5432:localhost:5432 name=db"}},
+	}
+	for name, want := range tests {
+		t.Run(name, func(t *testing.T) {
+			h, cfgMgr := newTestHandler()
+			cfg := core.DefaultConfig()
+			hc := want
+			hc.Notes = "old"
+			hc.Tags = []string{"env:prod"}
+			cfg.Hosts = map[string]core.HostConfig{"prod": hc}
+			cfgMgr.config = &cfg
+
+			// メモとタグを消してもほかの設定があるエントリは残す
+			empty := ""
+			tags := []string{}
+			if _, rpcErr := h.Update(mustMarshal(t, protocol.HostUpdateParams{Name: "prod", Notes: &empty, Tags: &tags})); rpcErr != nil {
+				t.Fatalf("unexpected error: %v", rpcErr)
+			}
+			got, ok := cfgMgr.config.Hosts["prod"]
+			if !ok {
+				t.Fatal("host entry with other settings was removed")
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Hosts[prod] = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUpdate_Errors(t *testing.T) {
+	h, _ := newTestHandler()
+
+	tests := []struct {
+		name     string
+		params   json.RawMessage
+		wantCode int
+	}{
+		{"nil params", nil, protocol.InvalidParams},
+		{"invalid json", json.RawMessage(`{`), protocol.InvalidParams},
+		{"missing name", mustMarshal(t, protocol.HostUpdateParams{}), protocol.InvalidParams},
+		{"unknown host", mustMarshal(t, protocol.HostUpdateParams{Name: "nope"}), protocol.HostNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, rpcErr := h.Update(tt.params)
+			if rpcErr == nil {
+				t.Fatal("expected error")
+			}
+			if rpcErr.Code != tt.wantCode {
+				t.Errorf("code = %d, want %d", rpcErr.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,2 @@
+// Package logs はデーモンのログの配信（logs.*）のハンドラを提供する。
+package logs
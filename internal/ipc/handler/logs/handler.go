@@ -0,0 +1,116 @@
+package logs
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Source はデーモンの直近のログと新しいログの購読を提供する。
+type Source interface {
+	// Subscribe は minLevel 以上の直近のログを最大 tail 件返し、以降のログを send で送信する購読を登録する。
+	// send がエラーを返した場合は購読を解除する。
+	Subscribe(minLevel slog.Level, tail int, send func(protocol.LogRecord) error) (recent []protocol.LogRecord, cancel func())
+}
+
+// Sender はクライアントに通知を送信する関数の型。
+type Sender func(clientID string, notification protocol.Notification) error
+
+// Handler はログの配信の JSON-RPC メソッドを処理する。
+type Handler struct {
+	source Source
+	send   Sender
+
+	mu     sync.Mutex
+	subs   map[string]func() // subscriptionID -> 購読の解除
+	nextID atomic.Int64
+}
+
+// New は source のログを send でクライアントに配信するハンドラを生成する。
+func New(source Source, send Sender) *Handler {
+	return &Handler{source: source, send: send, subs: make(map[string]func())}
+}
+
+// Handle は logs.* メソッドをディスパッチする。
+func (h *Handler) Handle(clientID string, method string, params json.RawMessage) (any, *protocol.RPCError) {
+	switch method {
+	case protocol.MethodLogsSubscribe:
+		return h.subscribe(clientID, params)
+	case protocol.MethodLogsUnsubscribe:
+		return h.unsubscribe(params)
+	default:
+		return nil, &protocol.RPCError{Code: protocol.MethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+// subscribe は直近のログを返し、以降のログを event.log 通知で配信する購読を登録する。
+// 購読はクライアントへの送信に失敗した時点（切断時など）で解除する。
+func (h *Handler) subscribe(clientID string, params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.LogsSubscribeParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+	level := slog.LevelInfo
+	if p.Level != "" {
+		if err := level.UnmarshalText([]byte(p.Level)); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid level: " + p.Level}
+		}
+	}
+	if p.Tail < 0 {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "tail must not be negative"}
+	}
+
+	id := fmt.Sprintf("logs-%s-%d", clientID, h.nextID.Add(1))
+	// 送信に失敗した購読の削除が登録より先に行われないよう、登録が終わるまでロックを保持する
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	recent, cancel := h.source.Subscribe(level, p.Tail, func(rec protocol.LogRecord) error {
+		data, err := json.Marshal(rec)
+		if err != nil {
+			return nil
+		}
+		err = h.send(clientID, protocol.Notification{JSONRPC: protocol.JSONRPCVersion, Method: protocol.EventLog, Params: data})
+		if err != nil {
+			h.remove(id)
+		}
+		return err
+	})
+	h.subs[id] = cancel
+	if recent == nil {
+		recent = []protocol.LogRecord{}
+	}
+	return protocol.LogsSubscribeResult{SubscriptionID: id, Records: recent}, nil
+}
+
+func (h *Handler) unsubscribe(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.LogsUnsubscribeParams
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+		}
+	}
+	if p.SubscriptionID == "" {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "subscription_id is required"}
+	}
+	cancel := h.remove(p.SubscriptionID)
+	if cancel == nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "subscription not found"}
+	}
+	cancel()
+	return protocol.LogsUnsubscribeResult{OK: true}, nil
+}
+
+// remove は購読を削除し、その解除関数を返す。購読が存在しない場合は nil を返す。
+func (h *Handler) remove(id string) func() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	cancel := h.subs[id]
+	delete(h.subs, id)
+	return cancel
+}
@@ -0,0 +1,117 @@
+package logs
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// fakeSource は直近のログとして recent を返し、購読した send を保持する Source。
+type fakeSource struct {
+	recent    []protocol.LogRecord
+	level     slog.Level
+	tail      int
+	send      func(protocol.LogRecord) error
+	cancelled int
+}
+
+func (s *fakeSource) Subscribe(minLevel slog.Level, tail int, send func(protocol.LogRecord) error) ([]protocol.LogRecord, func()) {
+	s.level, s.tail, s.send = minLevel, tail, send
+	return s.recent, func() { s.cancelled++ }
+}
+
+func TestHandle_Subscribe(t *testing.T) {
+	src := &fakeSource{recent: []protocol.LogRecord{{Level: "WARN", Message: "old"}}}
+	var sent []protocol.Notification
+	h := New(src, func(clientID string, n protocol.Notification) error {
+		if clientID != "client-1" {
+			t.Errorf("clientID = %q, want client-1", clientID)
+		}
+		sent = append(sent, n)
+		return nil
+	})
+
+	result, rpcErr := h.Handle("client-1", protocol.MethodLogsSubscribe, json.RawMessage(`{"level":"warn","tail":5}`))
+	if rpcErr != nil {
+		t.Fatalf("logs.subscribe error = %v", rpcErr)
+	}
+	res := result.(protocol.LogsSubscribeResult)
+	if res.SubscriptionID == "" || len(res.Records) != 1 || res.Records[0].Message != "old" {
+		t.Errorf("result = %+v", res)
+	}
+	if src.level != slog.LevelWarn || src.tail != 5 {
+		t.Errorf("Subscribe(%v, %d), want (WARN, 5)", src.level, src.tail)
+	}
+
+	if err := src.send(protocol.LogRecord{Level: "ERROR", Message: "new"}); err != nil {
+		t.Fatalf("send error = %v", err)
+	}
+	if len(sent) != 1 || sent[0].Method != protocol.EventLog {
+		t.Fatalf("sent = %+v, want one event.log notification", sent)
+	}
+	var rec protocol.LogRecord
+	if err := json.Unmarshal(sent[0].Params, &rec); err != nil || rec.Message != "new" {
+		t.Errorf("notification params = %s, %v", sent[0].Params, err)
+	}
+
+	params, _ := json.Marshal(protocol.LogsUnsubscribeParams{SubscriptionID: res.SubscriptionID})
+	if _, rpcErr := h.Handle("client-1", protocol.MethodLogsUnsubscribe, params); rpcErr != nil {
+		t.Fatalf("logs.unsubscribe error = %v", rpcErr)
+	}
+	if src.cancelled != 1 {
+		t.Errorf("cancelled = %d, want 1", src.cancelled)
+	}
+	if _, rpcErr := h.Handle("client-1", protocol.MethodLogsUnsubscribe, params); rpcErr == nil {
+		t.Error("unsubscribing twice should fail")
+	}
+}
+
+func TestHandle_SubscribeDefaults(t *testing.T) {
+	src := &fakeSource{}
+	result, rpcErr := New(src, nil).Handle("c", protocol.MethodLogsSubscribe, nil)
+	if rpcErr != nil {
+		t.Fatalf("logs.subscribe error = %v", rpcErr)
+	}
+	if src.level != slog.LevelInfo || src.tail != 0 {
+		t.Errorf("Subscribe(%v, %d), want (INFO, 0)", src.level, src.tail)
+	}
+	if res := result.(protocol.LogsSubscribeResult); res.Records == nil {
+		t.Error("Records should be an empty slice, not nil")
+	}
+}
+
+func TestHandle_SendErrorRemovesSubscription(t *testing.T) {
+	src := &fakeSource{}
+	h := New(src, func(string, protocol.Notification) error { return errors.New("client not found") })
+	result, _ := h.Handle("c", protocol.MethodLogsSubscribe, nil)
+	if err := src.send(protocol.LogRecord{Message: "x"}); err == nil {
+		t.Error("send should return the sender's error")
+	}
+	params, _ := json.Marshal(protocol.LogsUnsubscribeParams{SubscriptionID: result.(protocol.LogsSubscribeResult).SubscriptionID})
+	if _, rpcErr := h.Handle("c", protocol.MethodLogsUnsubscribe, params); rpcErr == nil {
+		t.Error("subscription should be removed after a send error")
+	}
+}
+
+func TestHandle_InvalidParams(t *testing.T) {
+	h := New(&fakeSource{}, nil)
+	tests := []struct {
+		method string
+		params string
+	}{
+		{protocol.MethodLogsSubscribe, `{"level":"verbose"}`},
+		{protocol.MethodLogsSubscribe, `{"tail":-1}`},
+		{protocol.MethodLogsSubscribe, `{"tail":"10"}`},
+		{protocol.MethodLogsUnsubscribe, `{}`},
+		{protocol.MethodLogsUnsubscribe, `{"subscription_id":"logs-c-99"}`},
+	}
+	for _, tt := range tests {
+		_, rpcErr := h.Handle("c", tt.method, json.RawMessage(tt.params))
+		if rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+			t.Errorf("%s %s: rpcErr = %v, want InvalidParams", tt.method, tt.params, rpcErr)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+// Package session はセッション関連リクエスト（session.*）のハンドラを提供する。
+package session
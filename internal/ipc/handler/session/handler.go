@@ -0,0 +1,132 @@
+package session
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/revision"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/listopt"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcerr"
+)
+
+// SessionSource はフォワードセッションの取得元。core.ForwardManager が満たす。
+type SessionSource interface {
+	GetAllSessions() []core.ForwardSession
+	GetSession(ruleName string) (*core.ForwardSession, error)
+}
+
+// Handler はセッション関連の JSON-RPC メソッドを処理する。
+type Handler struct {
+	sessions SessionSource
+	// revs は session.list・session.changes で返したセッション情報の変更を記録する。
+	revs *revision.Tracker[protocol.SessionInfo]
+}
+
+// New は新しいセッションハンドラを生成する。
+func New(sessions SessionSource) *Handler {
+	return &Handler{
+		sessions: sessions,
+		revs:     revision.New(func(a, b protocol.SessionInfo) bool { return reflect.DeepEqual(a, b) }),
+	}
+}
+
+// Handle は session.* メソッドをディスパッチする。
+func (h *Handler) Handle(method string, params json.RawMessage) (any, *protocol.RPCError) {
+	switch method {
+	case "session.list":
+		return h.List(params)
+	case "session.get":
+		return h.Get(params)
+	case "session.changes":
+		return h.Changes(params)
+	default:
+		return nil, &protocol.RPCError{Code: protocol.MethodNotFound, Message: "method not found: " + method}
+	}
+}
+
+// List は session.list リクエストを処理する。params で状態の絞り込み・ページング・フィールド選択を指定できる。
+func (h *Handler) List(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.SessionListParams
+	// params が nil や空の場合は全件を返す
+	if len(params) > 0 {
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
+		}
+	}
+
+	infos := h.sessionInfos()
+	rev := h.revs.Observe(infos, sessionName)
+	infos, total, err := listopt.Apply(infos, p.Options, func(info protocol.SessionInfo) string { return info.Status })
+	if err != nil {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: err.Error()}
+	}
+	return protocol.SessionListResult{Sessions: infos, Total: total, Revision: rev}, nil
+}
+
+// Changes は since の後に追加・変更されたセッションと削除されたセッションのルール名を返す。
+// 差分を返せない場合は Reset を true にして全件を返す。
+func (h *Handler) Changes(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.SessionChangesParams
+	if len(params) > 0 {
+		if err := parseParams(params, &p); err != nil {
+			return nil, err
+		}
+	}
+
+	infos := h.sessionInfos()
+	h.revs.Observe(infos, sessionName)
+	rev, changed, removed, ok := h.revs.Changes(p.Since)
+	if !ok {
+		return protocol.SessionChangesResult{Revision: rev, Reset: true, Sessions: infos, Removed: []string{}}, nil
+	}
+	if changed == nil {
+		changed = []protocol.SessionInfo{}
+	}
+	if removed == nil {
+		removed = []string{}
+	}
+	return protocol.SessionChangesResult{Revision: rev, Sessions: changed, Removed: removed}, nil
+}
+
+// Get は session.get リクエストを処理する。
+func (h *Handler) Get(params json.RawMessage) (any, *protocol.RPCError) {
+	var p protocol.SessionGetParams
+	if err := parseParams(params, &p); err != nil {
+		return nil, err
+	}
+	if p.Name == "" {
+		return nil, &protocol.RPCError{Code: protocol.InvalidParams, Message: "name is required"}
+	}
+
+	session, err := h.sessions.GetSession(p.Name)
+	if err != nil {
+		return nil, rpcerr.From(err, protocol.InternalError)
+	}
+	return convert.ToSessionInfo(*session), nil
+}
+
+// sessionInfos は全ルールのセッション情報を追加順に返す。
+func (h *Handler) sessionInfos() []protocol.SessionInfo {
+	sessions := h.sessions.GetAllSessions()
+	infos := make([]protocol.SessionInfo, len(sessions))
+	for i, s := range sessions {
+		infos[i] = convert.ToSessionInfo(s)
+	}
+	return infos
+}
+
+func sessionName(info protocol.SessionInfo) string { return info.Name }
+
+// parseParams は必須のリクエストパラメータを target にアンマーシャルする。
+func parseParams(params json.RawMessage, target any) *protocol.RPCError {
+	if len(params) == 0 {
+		return &protocol.RPCError{Code: protocol.InvalidParams, Message: "params required"}
+	}
+	if err := json.Unmarshal(params, target); err != nil {
+		return &protocol.RPCError{Code: protocol.InvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
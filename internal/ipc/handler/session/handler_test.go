@@ -0,0 +1,199 @@
+package session
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/listopt"
+)
+
+type mockSessionSource struct {
+	sessions []core.ForwardSession
+}
+
+func (m *mockSessionSource) GetAllSessions() []core.ForwardSession { return m.sessions }
+
+func (m *mockSessionSource) GetSession(ruleName string) (*core.ForwardSession, error) {
+	for _, s := range m.sessions {
+		if s.Rule.Name == ruleName {
+			return &s, nil
+		}
+	}
+	return nil, &core.NotFoundError{Resource: "rule", Name: ruleName}
+}
+
+func newTestHandler() (*Handler, *mockSessionSource) {
+	src := &mockSessionSource{sessions: []core.ForwardSession{{
+		ID: "web-123", Status: core.Active, BytesSent: 1024,
+		Rule:        core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		ConnectedAt: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}}}
+	return New(src), src
+}
+
+func mustMarshal(t *testing.T, v any) json.RawMessage {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return data
+}
+
+func TestList(t *testing.T) {
+	h, _ := newTestHandler()
+
+	result, rpcErr := h.Handle("session.list", nil)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+
+	sessionList, ok := result.(protocol.SessionListResult)
+	if !ok {
+		t.Fatalf("result type = %T, want protocol.SessionListResult", result)
+	}
+
+	if len(sessionList.Sessions) != 1 {
+		t.Fatalf("sessions count = %d, want 1", len(sessionList.Sessions))
+	}
+	if sessionList.Sessions[0].Name != "web" {
+		t.Errorf("sessions[0].Name = %q, want %q", sessionList.Sessions[0].Name, "web")
+	}
+	if sessionList.Sessions[0].Status != "active" {
+		t.Errorf("sessions[0].Status = %q, want %q", sessionList.Sessions[0].Status, "active")
+	}
+	if sessionList.Sessions[0].BytesSent != 1024 {
+		t.Errorf("sessions[0].BytesSent = %d, want %d", sessionList.Sessions[0].BytesSent, 1024)
+	}
+}
+
+func TestList_Options(t *testing.T) {
+	h, src := newTestHandler()
+	src.sessions = append(src.sessions,
+		core.ForwardSession{Rule: core.ForwardRule{Name: "db", Host: "prod", Type: core.Local, LocalPort: 5432}, Status: core.Stopped},
+		core.ForwardSession{Rule: core.ForwardRule{Name: "api", Host: "prod", Type: core.Local, LocalPort: 3000}, Status: core.Active, RateHistory: []float64{1, 2}},
+	)
+
+	params := mustMarshal(t, protocol.SessionListParams{Options: listopt.Options{
+		State: "active", Offset: 1, Limit: 5, Fields: []string{"status"},
+	}})
+	result, rpcErr := h.Handle("session.list", params)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+	list := result.(protocol.SessionListResult)
+	if list.Total != 2 {
+		t.Errorf("Total = %d, want 2 (active sessions before paging)", list.Total)
+	}
+	if len(list.Sessions) != 1 || list.Sessions[0].Name != "api" {
+		t.Fatalf("Sessions = %+v, want only api", list.Sessions)
+	}
+	if got := list.Sessions[0]; got.Status != "active" || got.Host != "" || got.RateHistory != nil {
+		t.Errorf("Sessions[0] = %+v, want only name and status", got)
+	}
+}
+
+func TestList_InvalidOptions(t *testing.T) {
+	h, _ := newTestHandler()
+
+	for _, opts := range []listopt.Options{{Offset: -1}, {Fields: []string{"nope"}}} {
+		params := mustMarshal(t, protocol.SessionListParams{Options: opts})
+		_, rpcErr := h.Handle("session.list", params)
+		if rpcErr == nil || rpcErr.Code != protocol.InvalidParams {
+			t.Errorf("session.list %+v: error = %v, want InvalidParams", opts, rpcErr)
+		}
+	}
+}
+
+func TestChanges(t *testing.T) {
+	h, src := newTestHandler()
+
+	result, rpcErr := h.Handle("session.list", nil)
+	if rpcErr != nil {
+		t.Fatalf("session.list: %v", rpcErr)
+	}
+	since := result.(protocol.SessionListResult).Revision
+
+	// 変化がなければ差分は空
+	changes := sessionChanges(t, h, since)
+	if changes.Reset || len(changes.Sessions) != 0 || len(changes.Removed) != 0 || changes.Revision != since {
+		t.Errorf("unchanged: %+v, want empty changes at revision %d", changes, since)
+	}
+
+	src.sessions = []core.ForwardSession{
+		{Rule: core.ForwardRule{Name: "db", Host: "prod", Type: core.Local, LocalPort: 5432}, Status: core.Active},
+	}
+	changes = sessionChanges(t, h, since)
+	if changes.Reset || changes.Revision <= since {
+		t.Fatalf("changed: Reset = %v, Revision = %d, want a newer revision than %d", changes.Reset, changes.Revision, since)
+	}
+	if len(changes.Sessions) != 1 || changes.Sessions[0].Name != "db" {
+		t.Errorf("Sessions = %+v, want db", changes.Sessions)
+	}
+	if len(changes.Removed) != 1 || changes.Removed[0] != "web" {
+		t.Errorf("Removed = %v, want [web]", changes.Removed)
+	}
+
+	// since が 0 の場合は全件を返す
+	if changes := sessionChanges(t, h, 0); !changes.Reset || len(changes.Sessions) != 1 {
+		t.Errorf("since 0: %+v, want a reset with all sessions", changes)
+	}
+}
+
+func sessionChanges(t *testing.T, h *Handler, since uint64) protocol.SessionChangesResult {
+	t.Helper()
+	result, rpcErr := h.Handle("session.changes", mustMarshal(t, protocol.SessionChangesParams{Since: since}))
+	if rpcErr != nil {
+		t.Fatalf("session.changes: %v", rpcErr)
+	}
+	return result.(protocol.SessionChangesResult)
+}
+
+func TestGet_Success(t *testing.T) {
+	h, _ := newTestHandler()
+
+	params := mustMarshal(t, protocol.SessionGetParams{Name: "web"})
+	result, rpcErr := h.Handle("session.get", params)
+	if rpcErr != nil {
+		t.Fatalf("unexpected error: %v", rpcErr)
+	}
+
+	sessionInfo, ok := result.(protocol.SessionInfo)
+	if !ok {
+		t.Fatalf("result type = %T, want protocol.SessionInfo", result)
+	}
+	if sessionInfo.Name != "web" {
+		t.Errorf("Name = %q, want %q", sessionInfo.Name, "web")
+	}
+	if sessionInfo.Status != "active" {
+		t.Errorf("Status = %q, want %q", sessionInfo.Status, "active")
+	}
+}
+
+func TestGet_EmptyName(t *testing.T) {
+	h, _ := newTestHandler()
+	params := mustMarshal(t, protocol.SessionGetParams{Name: ""})
+	_, rpcErr := h.Handle("session.get", params)
+	if rpcErr == nil {
+		t.Fatal("expected RPC error for empty name")
+	}
+	if rpcErr.Code != protocol.InvalidParams {
+		t.Errorf("error code = %d, want %d (InvalidParams)", rpcErr.Code, protocol.InvalidParams)
+	}
+}
+
+func TestGet_NotFound(t *testing.T) {
+	h, _ := newTestHandler()
+
+	params := mustMarshal(t, protocol.SessionGetParams{Name: "nonexistent"})
+	_, rpcErr := h.Handle("session.get", params)
+	if rpcErr == nil {
+		t.Fatal("expected RPC error")
+	}
+	if rpcErr.Code != protocol.RuleNotFound {
+		t.Errorf("error code = %d, want %d (RuleNotFound)", rpcErr.Code, protocol.RuleNotFound)
+	}
+}
@@ -0,0 +1,3 @@
+// Package localsock は CLI/TUI とデーモンの間のローカル接続を提供する。
+// Unix 系 OS では Unix ソケット、Windows では名前付きパイプを使う。
+package localsock
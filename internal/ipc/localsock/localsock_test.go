@@ -0,0 +1,69 @@
+package localsock
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestListenDial(t *testing.T) {
+	path := Path(t.TempDir(), "test.sock")
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+			t.Errorf("socket mode = %v (%v), want 0600", info.Mode().Perm(), err)
+		}
+	}
+
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer func() { _ = conn.Close() }()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		_, _ = conn.Write([]byte("echo " + line))
+	}()
+
+	conn, err := DialTimeout(path, time.Second)
+	if err != nil {
+		t.Fatalf("DialTimeout() error = %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	if _, err := conn.Write([]byte("hello\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	got, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || got != "echo hello\n" {
+		t.Errorf("reply = (%q, %v), want echo hello", got, err)
+	}
+
+	if err := ln.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if conn, err := Dial(path); err == nil {
+		_ = conn.Close()
+		t.Error("Dial() after Close succeeded, want error")
+	}
+}
+
+func TestListen_RemovesStaleSocket(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("named pipes leave no file behind")
+	}
+	path := Path(t.TempDir(), "stale.sock")
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() over stale file error = %v", err)
+	}
+	_ = ln.Close()
+}
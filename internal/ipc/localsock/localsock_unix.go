@@ -0,0 +1,43 @@
+//go:build !windows
+
+package localsock
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Path は dir に置く name のソケットのパスを返す。
+func Path(dir, name string) string {
+	return filepath.Join(dir, name)
+}
+
+// Listen は path に Unix ソケットを作成し、パーミッションを 0600 にする。
+// 古いソケットファイルが残っている場合は削除する。ソケットファイルはリスナーを閉じると削除される。
+func Listen(path string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("remove stale socket: %w", err)
+	}
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listen unix: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = ln.Close()
+		return nil, fmt.Errorf("chmod socket: %w", err)
+	}
+	return ln, nil
+}
+
+// Dial は path の Unix ソケットに接続する。
+func Dial(path string) (net.Conn, error) {
+	return net.Dial("unix", path)
+}
+
+// DialTimeout は timeout 以内に path の Unix ソケットに接続する。
+func DialTimeout(path string, timeout time.Duration) (net.Conn, error) {
+	return net.DialTimeout("unix", path, timeout)
+}
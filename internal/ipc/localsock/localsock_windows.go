@@ -0,0 +1,61 @@
+package localsock
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"golang.org/x/sys/windows"
+)
+
+// pipePrefix は名前付きパイプの名前空間。
+const pipePrefix = `\\.\pipe\`
+
+// dialRetryInterval はパイプのインスタンスが全て使用中の場合に接続を再試行する間隔。
+const dialRetryInterval = 10 * time.Millisecond
+
+// Path は dir の設定ディレクトリに対応する name の名前付きパイプのパスを返す。
+// パイプの名前はマシン全体で共有されるため、設定ディレクトリの絶対パスのハッシュで区別する。
+func Path(dir, name string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	sum := sha256.Sum256([]byte(strings.ToLower(dir)))
+	return pipePrefix + "moleport-" + hex.EncodeToString(sum[:8]) + "-" + name
+}
+
+// Listen は path の名前付きパイプを作成する。パイプには現在のユーザーのみが接続でき、リモートからの接続は拒否する。
+// 同じ名前のパイプを別のプロセスが作成済みの場合はエラーを返す。
+func Listen(path string) (net.Listener, error) {
+	return listenPipe(path)
+}
+
+// Dial は path の名前付きパイプに接続する。
+func Dial(path string) (net.Conn, error) {
+	return DialTimeout(path, 0)
+}
+
+// DialTimeout は timeout 以内に path の名前付きパイプに接続する。timeout が 0 の場合は使用中でも再試行しない。
+func DialTimeout(path string, timeout time.Duration) (net.Conn, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		h, err := windows.CreateFile(name, windows.GENERIC_READ|windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING,
+			windows.FILE_FLAG_OVERLAPPED|windows.SECURITY_SQOS_PRESENT|windows.SECURITY_IDENTIFICATION, 0)
+		if err == nil {
+			return newConn(h, path), nil
+		}
+		if !errors.Is(err, windows.ERROR_PIPE_BUSY) || !time.Now().Before(deadline) {
+			return nil, &net.OpError{Op: "dial", Net: "pipe", Addr: addr(path), Err: os.NewSyscallError("CreateFile", err)}
+		}
+		time.Sleep(dialRetryInterval)
+	}
+}
@@ -0,0 +1,172 @@
+package localsock
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// pipeBufferSize は名前付きパイプの入出力バッファーのサイズ。
+const pipeBufferSize = 64 * 1024
+
+// addr は名前付きパイプのアドレス。
+type addr string
+
+func (addr) Network() string  { return "pipe" }
+func (a addr) String() string { return string(a) }
+
+// conn は名前付きパイプの接続。非同期 I/O のハンドルを os.File で扱い、デッドラインに対応する。
+type conn struct {
+	*os.File
+	addr addr
+}
+
+func newConn(h windows.Handle, path string) *conn {
+	return &conn{File: os.NewFile(uintptr(h), path), addr: addr(path)}
+}
+
+func (c *conn) LocalAddr() net.Addr  { return c.addr }
+func (c *conn) RemoteAddr() net.Addr { return c.addr }
+
+// listener は名前付きパイプの接続を受け付ける。Accept ごとにパイプのインスタンスを作成して接続を待つ。
+type listener struct {
+	path   string
+	sa     *windows.SecurityAttributes
+	closed windows.Handle // Close でシグナル状態にするイベント
+
+	mu   sync.Mutex
+	next windows.Handle // 次の Accept で使う作成済みのインスタンス
+	done bool
+}
+
+// listenPipe は最初のパイプのインスタンスを作成し、path のパイプを占有する。
+func listenPipe(path string) (net.Listener, error) {
+	sa, err := currentUserOnly()
+	if err != nil {
+		return nil, err
+	}
+	h, err := createPipe(path, sa, true)
+	if err != nil {
+		return nil, &net.OpError{Op: "listen", Net: "pipe", Addr: addr(path), Err: os.NewSyscallError("CreateNamedPipe", err)}
+	}
+	closed, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		_ = windows.CloseHandle(h)
+		return nil, err
+	}
+	return &listener{path: path, sa: sa, closed: closed, next: h}, nil
+}
+
+// currentUserOnly は現在のユーザーのみにアクセスを許可するセキュリティ属性を返す。
+func currentUserOnly() (*windows.SecurityAttributes, error) {
+	user, err := windows.GetCurrentProcessToken().GetTokenUser()
+	if err != nil {
+		return nil, err
+	}
+	sd, err := windows.SecurityDescriptorFromString("D:P(A;;GA;;;" + user.User.Sid.String() + ")")
+	if err != nil {
+		return nil, err
+	}
+	sa := &windows.SecurityAttributes{SecurityDescriptor: sd}
+	sa.Length = uint32(unsafe.Sizeof(*sa))
+	return sa, nil
+}
+
+// createPipe はパイプのインスタンスを作成する。first の場合は同じ名前のパイプが既に存在すると失敗する。
+func createPipe(path string, sa *windows.SecurityAttributes, first bool) (windows.Handle, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	flags := uint32(windows.PIPE_ACCESS_DUPLEX | windows.FILE_FLAG_OVERLAPPED)
+	if first {
+		flags |= windows.FILE_FLAG_FIRST_PIPE_INSTANCE
+	}
+	mode := uint32(windows.PIPE_TYPE_BYTE | windows.PIPE_READMODE_BYTE | windows.PIPE_WAIT | windows.PIPE_REJECT_REMOTE_CLIENTS)
+	return windows.CreateNamedPipe(name, flags, mode, windows.PIPE_UNLIMITED_INSTANCES, pipeBufferSize, pipeBufferSize, 0, sa)
+}
+
+// Accept はクライアントが接続するまで待ち、接続を返す。
+func (l *listener) Accept() (net.Conn, error) {
+	l.mu.Lock()
+	if l.done {
+		l.mu.Unlock()
+		return nil, net.ErrClosed
+	}
+	h := l.next
+	l.next = 0
+	l.mu.Unlock()
+
+	if h == 0 {
+		var err error
+		if h, err = createPipe(l.path, l.sa, false); err != nil {
+			return nil, &net.OpError{Op: "accept", Net: "pipe", Addr: addr(l.path), Err: os.NewSyscallError("CreateNamedPipe", err)}
+		}
+	}
+	if err := l.connect(h); err != nil {
+		_ = windows.CloseHandle(h)
+		return nil, err
+	}
+
+	// 次の Accept までの間もパイプが存在するよう、次のインスタンスを先に作成する
+	l.mu.Lock()
+	if !l.done && l.next == 0 {
+		if next, err := createPipe(l.path, l.sa, false); err == nil {
+			l.next = next
+		}
+	}
+	l.mu.Unlock()
+	return newConn(h, l.path), nil
+}
+
+// connect はインスタンス h にクライアントが接続するか、リスナーが閉じられるまで待つ。
+// h は os.File に渡す前のため、ランタイムの I/O 完了ポートに関連付けられておらず、完了をイベントで待つ。
+func (l *listener) connect(h windows.Handle) error {
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = windows.CloseHandle(event) }()
+
+	ov := &windows.Overlapped{HEvent: event}
+	err = windows.ConnectNamedPipe(h, ov)
+	switch {
+	case err == nil, errors.Is(err, windows.ERROR_PIPE_CONNECTED):
+		return nil
+	case !errors.Is(err, windows.ERROR_IO_PENDING):
+		return os.NewSyscallError("ConnectNamedPipe", err)
+	}
+
+	var n uint32
+	i, err := windows.WaitForMultipleObjects([]windows.Handle{event, l.closed}, false, windows.INFINITE)
+	if err != nil || i != windows.WAIT_OBJECT_0 {
+		_ = windows.CancelIoEx(h, ov)
+		_ = windows.GetOverlappedResult(h, ov, &n, true)
+		return net.ErrClosed
+	}
+	if err := windows.GetOverlappedResult(h, ov, &n, false); err != nil {
+		return os.NewSyscallError("ConnectNamedPipe", err)
+	}
+	return nil
+}
+
+// Close は接続の待機を中断し、リスナーを閉じる。受け付け済みの接続は閉じない。
+func (l *listener) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done {
+		return nil
+	}
+	l.done = true
+	if l.next != 0 {
+		_ = windows.CloseHandle(l.next)
+		l.next = 0
+	}
+	return windows.SetEvent(l.closed)
+}
+
+func (l *listener) Addr() net.Addr { return addr(l.path) }
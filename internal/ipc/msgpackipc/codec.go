@@ -0,0 +1,213 @@
+package msgpackipc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// maxDepth は受け付ける配列・マップの入れ子の深さの上限。
+const maxDepth = 64
+
+// errUnsupported は JSON で表せない MessagePack の値（拡張型・文字列以外のマップのキー）を受信したことを示す。
+var errUnsupported = errors.New("msgpack: unsupported value")
+
+// encode は JSON をデコードした値 v（nil・bool・int64・uint64・float64・string・[]any・map[string]any）を
+// MessagePack でエンコードして b に追加する。
+func encode(b []byte, v any) ([]byte, error) {
+	switch v := v.(type) {
+	case nil:
+		return append(b, 0xc0), nil
+	case bool:
+		if v {
+			return append(b, 0xc3), nil
+		}
+		return append(b, 0xc2), nil
+	case int64:
+		return encodeInt(b, v), nil
+	case uint64:
+		if v <= math.MaxInt64 {
+			return encodeInt(b, int64(v)), nil
+		}
+		return binary.BigEndian.AppendUint64(append(b, 0xcf), v), nil
+	case float64:
+		return binary.BigEndian.AppendUint64(append(b, 0xcb), math.Float64bits(v)), nil
+	case string:
+		b = appendLen(b, len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		return append(b, v...), nil
+	case []any:
+		b = appendLen(b, len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		var err error
+		for _, e := range v {
+			if b, err = encode(b, e); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[string]any:
+		b = appendLen(b, len(v), 0x80, 16, 0, 0xde, 0xdf)
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		var err error
+		for _, k := range keys {
+			b, _ = encode(b, k)
+			if b, err = encode(b, v[k]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	default:
+		return nil, fmt.Errorf("msgpack: cannot encode %T", v)
+	}
+}
+
+// encodeInt は整数を最も短い形式でエンコードする。
+func encodeInt(b []byte, v int64) []byte {
+	switch {
+	case v >= -32 && v < 128:
+		return append(b, byte(v))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		return binary.BigEndian.AppendUint32(append(b, 0xd2), uint32(v))
+	default:
+		return binary.BigEndian.AppendUint64(append(b, 0xd3), uint64(v))
+	}
+}
+
+// appendLen は長さ n のヘッダーを追加する。n が fixMax 未満なら fix 形式、それ以外は 8・16・32 ビット長の形式を使う。
+// code8 が 0 の型（配列・マップ）は 8 ビット長の形式を持たない。
+func appendLen(b []byte, n int, fix byte, fixMax int, code8, code16, code32 byte) []byte {
+	switch {
+	case n < fixMax:
+		return append(b, fix|byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		return append(b, code8, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(b, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(b, code32), uint32(n))
+	}
+}
+
+// decode は r から MessagePack の値を 1 つ読み取り、JSON にエンコードできる値として返す。
+// bin 型は文字列として扱う。
+func decode(r *bufio.Reader, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errors.New("msgpack: nesting too deep")
+	}
+	c, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return decodeMap(r, int(c&0x0f), depth)
+	case c&0xf0 == 0x90:
+		return decodeArray(r, int(c&0x0f), depth)
+	case c&0xe0 == 0xa0:
+		return decodeString(r, int(c&0x1f))
+	}
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+	case 0xc4, 0xd9:
+		return decodeSized(r, 1, decodeString)
+	case 0xc5, 0xda:
+		return decodeSized(r, 2, decodeString)
+	case 0xc6, 0xdb:
+		return decodeSized(r, 4, decodeString)
+	case 0xca:
+		n, err := readUint(r, 4)
+		return float64(math.Float32frombits(uint32(n))), err
+	case 0xcb:
+		n, err := readUint(r, 8)
+		return math.Float64frombits(n), err
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		return readUint(r, 1<<(c-0xcc))
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		n, err := readUint(r, size)
+		shift := 64 - 8*size
+		return int64(n<<shift) >> shift, err
+	case 0xdc, 0xdd:
+		return decodeSized(r, 2<<(c-0xdc), func(r *bufio.Reader, n int) (any, error) { return decodeArray(r, n, depth) })
+	case 0xde, 0xdf:
+		return decodeSized(r, 2<<(c-0xde), func(r *bufio.Reader, n int) (any, error) { return decodeMap(r, n, depth) })
+	}
+	return nil, fmt.Errorf("%w: type 0x%02x", errUnsupported, c)
+}
+
+// decodeSized は size バイトの長さを読み取り、その長さで read を呼び出す。
+func decodeSized(r *bufio.Reader, size int, read func(*bufio.Reader, int) (any, error)) (any, error) {
+	n, err := readUint(r, size)
+	if err != nil {
+		return nil, err
+	}
+	if n > protocol.ScannerMaxBuf {
+		return nil, fmt.Errorf("msgpack: length %d exceeds limit", n)
+	}
+	return read(r, int(n))
+}
+
+// readUint は size バイトのビッグエンディアンの符号なし整数を読み取る。
+func readUint(r *bufio.Reader, size int) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[8-size:]); err != nil {
+		return 0, err
+	}
+	return binary.BigEndian.Uint64(buf[:]), nil
+}
+
+func decodeString(r *bufio.Reader, n int) (any, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return string(buf), nil
+}
+
+func decodeArray(r *bufio.Reader, n int, depth int) (any, error) {
+	arr := make([]any, 0, min(n, 1024))
+	for range n {
+		v, err := decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		arr = append(arr, v)
+	}
+	return arr, nil
+}
+
+func decodeMap(r *bufio.Reader, n int, depth int) (any, error) {
+	m := make(map[string]any, min(n, 1024))
+	for range n {
+		k, err := decode(r, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		key, ok := k.(string)
+		if !ok {
+			return nil, fmt.Errorf("%w: map key of type %T", errUnsupported, k)
+		}
+		if m[key], err = decode(r, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
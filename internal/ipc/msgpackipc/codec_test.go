@@ -0,0 +1,94 @@
+package msgpackipc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeDecode_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{"nil", nil},
+		{"bool", true},
+		{"positive fixint", int64(127)},
+		{"negative fixint", int64(-32)},
+		{"int32", int64(-70000)},
+		{"int64", int64(math.MinInt64)},
+		{"uint64", uint64(math.MaxUint64)},
+		{"float", 1.5},
+		{"fixstr", "hello"},
+		{"str8", strings.Repeat("a", 200)},
+		{"str16", strings.Repeat("あ", 1000)},
+		{"array16", make([]any, 20)},
+		{"nested", map[string]any{"jsonrpc": "2.0", "params": map[string]any{"types": []any{"ssh", "forward"}}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := encode(nil, tt.v)
+			if err != nil {
+				t.Fatalf("encode() error = %v", err)
+			}
+			got, err := decode(bufio.NewReader(bytes.NewReader(b)), 0)
+			if err != nil {
+				t.Fatalf("decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.v) {
+				t.Errorf("decode(encode(%v)) = %v", tt.v, got)
+			}
+		})
+	}
+}
+
+func TestDecode_Formats(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want any
+	}{
+		{"uint8", []byte{0xcc, 0xff}, uint64(255)},
+		{"uint16", []byte{0xcd, 0x01, 0x00}, uint64(256)},
+		{"int8", []byte{0xd0, 0x80}, int64(-128)},
+		{"int16", []byte{0xd1, 0xff, 0x00}, int64(-256)},
+		{"float32", []byte{0xca, 0x3f, 0xc0, 0x00, 0x00}, 1.5},
+		{"bin8 as string", []byte{0xc4, 0x02, 'o', 'k'}, "ok"},
+		{"fixmap", []byte{0x81, 0xa1, 'a', 0xc3}, map[string]any{"a": true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decode(bufio.NewReader(bytes.NewReader(tt.data)), 0)
+			if err != nil || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decode(% x) = %v, %v; want %v", tt.data, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode_Unsupported(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"ext", []byte{0xd4, 0x01, 0x00}},
+		{"never used", []byte{0xc1}},
+		{"integer map key", []byte{0x81, 0x01, 0xc0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decode(bufio.NewReader(bytes.NewReader(tt.data)), 0); !errors.Is(err, errUnsupported) {
+				t.Errorf("decode(% x) error = %v, want errUnsupported", tt.data, err)
+			}
+		})
+	}
+
+	deep := bytes.Repeat([]byte{0x91}, maxDepth+2)
+	if _, err := decode(bufio.NewReader(bytes.NewReader(append(deep, 0xc0))), 0); err == nil {
+		t.Error("decode() should reject deeply nested arrays")
+	}
+}
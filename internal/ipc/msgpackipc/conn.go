@@ -0,0 +1,142 @@
+package msgpackipc
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/ousiassllc/moleport/internal/ipc/localsock"
+)
+
+// Conn は MessagePack で通信する接続を、改行区切りの JSON で読み書きできるようにする。
+// Read は受信した MessagePack の値を 1 行の JSON に変換して返し、Write は JSON を 1 行ずつ MessagePack の値に変換して送信する。
+type Conn struct {
+	net.Conn
+	r    *bufio.Reader
+	rbuf bytes.Buffer
+
+	wmu  sync.Mutex
+	wbuf bytes.Buffer
+}
+
+// NewConn は conn を JSON で読み書きする Conn を返す。
+func NewConn(conn net.Conn) *Conn {
+	return &Conn{Conn: conn, r: bufio.NewReader(conn)}
+}
+
+// NetConn は変換前の接続を返す。接続元の UID の確認に使う。
+func (c *Conn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// Read は次の MessagePack の値を JSON の 1 行に変換して読み取る。
+// JSON で表せない値を受信した場合は、以降のメッセージの区切りが分からないためエラーを返す。
+func (c *Conn) Read(p []byte) (int, error) {
+	if c.rbuf.Len() == 0 {
+		v, err := decode(c.r, 0)
+		if err != nil {
+			return 0, err
+		}
+		line, err := json.Marshal(v)
+		if err != nil {
+			return 0, fmt.Errorf("msgpack: %w", err)
+		}
+		c.rbuf.Write(line)
+		c.rbuf.WriteByte('\n')
+	}
+	return c.rbuf.Read(p)
+}
+
+// Write は p の JSON を改行まで蓄積し、1 行ごとに MessagePack の値として送信する。
+func (c *Conn) Write(p []byte) (int, error) {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	c.wbuf.Write(p)
+	for {
+		i := bytes.IndexByte(c.wbuf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		out, err := transcode(c.wbuf.Next(i + 1))
+		if err != nil {
+			c.wbuf.Reset()
+			return 0, err
+		}
+		if _, err := c.Conn.Write(out); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// transcode は JSON の 1 行を MessagePack の値に変換する。
+func transcode(line []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("msgpack: %w", err)
+	}
+	return encode(nil, fromJSON(v))
+}
+
+// fromJSON は UseNumber でデコードした値の json.Number を int64・uint64・float64 に置き換える。
+func fromJSON(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if n, err := strconv.ParseUint(v.String(), 10, 64); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	case []any:
+		for i := range v {
+			v[i] = fromJSON(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = fromJSON(v[k])
+		}
+	}
+	return v
+}
+
+// listener は受け付けた接続を Conn に変換する。
+type listener struct {
+	net.Listener
+}
+
+func (l listener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return NewConn(conn), nil
+}
+
+// Listen は path に MessagePack で通信するソケット（Windows では名前付きパイプ）を作成する。
+// 古いソケットファイルが残っている場合は削除する。ソケットファイルはリスナーを閉じると削除される。
+func Listen(path string) (net.Listener, error) {
+	ln, err := localsock.Listen(path)
+	if err != nil {
+		return nil, err
+	}
+	return listener{ln}, nil
+}
+
+// Dialer は path の MessagePack のソケットに接続する関数を返す。client.NewIPCClientWithDialer に渡して使う。
+func Dialer(path string) func() (net.Conn, error) {
+	return func() (net.Conn, error) {
+		conn, err := localsock.Dial(path)
+		if err != nil {
+			return nil, err
+		}
+		return NewConn(conn), nil
+	}
+}
@@ -0,0 +1,99 @@
+package msgpackipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func echo(_ context.Context, _ string, _ string, params json.RawMessage) (any, *protocol.RPCError) {
+	return params, nil
+}
+
+// startServer は JSON-RPC の Unix ソケットに加えて MessagePack のソケットで受け付ける IPCServer を起動し、そのパスを返す。
+func startServer(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	srv := ipc.NewIPCServer(filepath.Join(dir, "test.sock"), echo)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+	path := filepath.Join(dir, "test.msgpack.sock")
+	ln, err := Listen(path)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv.Serve(ln)
+	return path
+}
+
+func TestListen_Permissions(t *testing.T) {
+	path := startServer(t)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket permission = %o, want 600", perm)
+	}
+}
+
+func TestConn_Client(t *testing.T) {
+	c := client.NewIPCClientWithDialer(Dialer(startServer(t)))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() { _ = c.Close() }()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	var got map[string]any
+	if err := c.Call(ctx, "test.echo", map[string]any{"name": "web", "port": 8080}, &got); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if got["name"] != "web" || got["port"] != float64(8080) {
+		t.Errorf("result = %v", got)
+	}
+}
+
+// TestConn_Wire はエンコーダーを使わずに組み立てた MessagePack のリクエストに応答することを確認する。
+func TestConn_Wire(t *testing.T) {
+	conn, err := net.Dial("unix", startServer(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// {"jsonrpc": "2.0", "id": 7, "method": "test.echo", "params": [true]}
+	req := []byte{0x84,
+		0xa7, 'j', 's', 'o', 'n', 'r', 'p', 'c', 0xa3, '2', '.', '0',
+		0xa2, 'i', 'd', 0x07,
+		0xa6, 'm', 'e', 't', 'h', 'o', 'd', 0xa9, 't', 'e', 's', 't', '.', 'e', 'c', 'h', 'o',
+		0xa6, 'p', 'a', 'r', 'a', 'm', 's', 0x91, 0xc3,
+	}
+	if _, err := conn.Write(req); err != nil {
+		t.Fatal(err)
+	}
+	v, err := decode(bufio.NewReader(conn), 0)
+	if err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	resp, ok := v.(map[string]any)
+	if !ok || resp["id"] != int64(7) || resp["jsonrpc"] != "2.0" {
+		t.Fatalf("response = %v", v)
+	}
+	if result, ok := resp["result"].([]any); !ok || len(result) != 1 || result[0] != true {
+		t.Errorf("result = %v, want [true]", resp["result"])
+	}
+}
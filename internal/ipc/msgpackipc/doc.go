@@ -0,0 +1,4 @@
+// Package msgpackipc は JSON-RPC 2.0 のメッセージを MessagePack でエンコードして送受信する Unix ソケットのトランスポートを提供する。
+// 接続は IPC サーバーが扱う改行区切りの JSON に変換するため、メソッドとハンドラは JSON-RPC の Unix ソケットと共通になる。
+// 他の言語の自動化ツールは、各言語の MessagePack ライブラリでメッセージを 1 つの値として読み書きすればよい。
+package msgpackipc
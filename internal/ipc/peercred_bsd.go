@@ -0,0 +1,31 @@
+//go:build darwin || freebsd
+
+package ipc
+
+import (
+	"net"
+
+	"golang.org/x/sys/unix"
+)
+
+// PeerCredAvailable は Unix ソケットの接続元の UID を取得できる OS かを表す。
+const PeerCredAvailable = true
+
+// peerUID は Unix ソケット接続の接続元プロセスの UID を LOCAL_PEERCRED で取得する。
+func peerUID(conn *net.UnixConn) (int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var cred *unix.Xucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = unix.GetsockoptXucred(int(fd), unix.SOL_LOCAL, unix.LOCAL_PEERCRED)
+	}); err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return int(cred.Uid), nil
+}
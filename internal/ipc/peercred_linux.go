@@ -0,0 +1,30 @@
+//go:build linux
+
+package ipc
+
+import (
+	"net"
+	"syscall"
+)
+
+// PeerCredAvailable は Unix ソケットの接続元の UID を取得できる OS かを表す。
+const PeerCredAvailable = true
+
+// peerUID は Unix ソケット接続の接続元プロセスの UID を SO_PEERCRED で取得する。
+func peerUID(conn *net.UnixConn) (int, error) {
+	raw, err := conn.SyscallConn()
+	if err != nil {
+		return 0, err
+	}
+	var cred *syscall.Ucred
+	var credErr error
+	if err := raw.Control(func(fd uintptr) {
+		cred, credErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	}); err != nil {
+		return 0, err
+	}
+	if credErr != nil {
+		return 0, credErr
+	}
+	return int(cred.Uid), nil
+}
@@ -0,0 +1,17 @@
+//go:build !linux && !darwin && !freebsd
+
+package ipc
+
+import (
+	"errors"
+	"net"
+)
+
+// PeerCredAvailable は Unix ソケットの接続元の UID を取得できる OS かを表す。
+// 取得できない OS ではデーモンが認証トークンの提示を必須にする。
+const PeerCredAvailable = false
+
+// peerUID はこの OS では接続元の UID を取得できないためエラーを返す。
+func peerUID(*net.UnixConn) (int, error) {
+	return 0, errors.New("peer credentials are not supported on this platform")
+}
@@ -0,0 +1,294 @@
+package convert
+
+import (
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/socksacl"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// ToHostInfo は core.SSHHost を protocol.HostInfo に変換する。
+func ToHostInfo(host core.SSHHost) protocol.HostInfo {
+	return protocol.HostInfo{
+		Name:               host.Name,
+		HostName:           host.HostName,
+		Port:               host.Port,
+		User:               host.User,
+		State:              connectionStateToWire(host.State),
+		ActiveForwardCount: host.ActiveForwardCount,
+		Notes:              host.Meta.Notes,
+		AuthHint:           host.Meta.AuthHint,
+		JumpDescription:    host.Meta.JumpDescription,
+		Tags:               host.Meta.Tags,
+		JumpChain:          host.JumpChain,
+		Health:             string(host.Health),
+		Source:             host.Source,
+	}
+}
+
+// ToSSHHost は protocol.HostInfo を core.SSHHost に変換する。
+func ToSSHHost(info protocol.HostInfo) core.SSHHost {
+	return core.SSHHost{
+		Name:               info.Name,
+		HostName:           info.HostName,
+		Port:               info.Port,
+		User:               info.User,
+		State:              ParseConnectionState(info.State),
+		ActiveForwardCount: info.ActiveForwardCount,
+		JumpChain:          info.JumpChain,
+		Health:             core.HostHealth(info.Health),
+		Source:             info.Source,
+		Meta: core.HostMetadata{
+			Notes:           info.Notes,
+			AuthHint:        info.AuthHint,
+			JumpDescription: info.JumpDescription,
+			Tags:            info.Tags,
+		},
+	}
+}
+
+// ToForwardInfo は core.ForwardRule を protocol.ForwardInfo に変換する。
+func ToForwardInfo(rule core.ForwardRule) protocol.ForwardInfo {
+	info := protocol.ForwardInfo{
+		Name:            rule.Name,
+		Host:            rule.Host,
+		Type:            forwardTypeToWire(rule.Type),
+		LocalPort:       rule.LocalPort,
+		RemoteHost:      rule.RemoteHost,
+		RemotePort:      rule.RemotePort,
+		RemoteBindAddr:  rule.RemoteBindAddr,
+		LocalBindAddr:   rule.LocalBindAddr,
+		AutoConnect:     rule.AutoConnect,
+		AutoReconnect:   rule.AutoReconnect,
+		ImportKey:       rule.ImportKey,
+		DeleteOnExpire:  rule.DeleteOnExpire,
+		HostDefined:     rule.HostDefined,
+		MaxUploadKbps:   rule.MaxUploadKbps,
+		MaxDownloadKbps: rule.MaxDownloadKbps,
+		AccessLog:       rule.AccessLog,
+		DependsOn:       rule.DependsOn,
+		OnStart:         rule.OnStart,
+		OnStop:          rule.OnStop,
+	}
+	if rule.ACL != nil {
+		info.ACL = &protocol.SocksACL{Allow: rule.ACL.Allow, Deny: rule.ACL.Deny}
+	}
+	if rule.TTL.Duration > 0 {
+		info.TTL = rule.TTL.String()
+	}
+	return info
+}
+
+// ToForwardRule は protocol.ForwardInfo を core.ForwardRule に変換する（ToForwardInfo の逆変換）。
+func ToForwardRule(info protocol.ForwardInfo) (core.ForwardRule, error) {
+	fwdType, err := core.ParseForwardType(info.Type)
+	if err != nil {
+		return core.ForwardRule{}, err
+	}
+	ttl, err := protocol.ParseTTL(info.TTL)
+	if err != nil {
+		return core.ForwardRule{}, err
+	}
+	return core.ForwardRule{
+		Name:            info.Name,
+		Host:            info.Host,
+		Type:            fwdType,
+		LocalPort:       info.LocalPort,
+		RemoteHost:      info.RemoteHost,
+		RemotePort:      info.RemotePort,
+		RemoteBindAddr:  info.RemoteBindAddr,
+		LocalBindAddr:   info.LocalBindAddr,
+		AutoConnect:     info.AutoConnect,
+		AutoReconnect:   info.AutoReconnect,
+		ImportKey:       info.ImportKey,
+		TTL:             core.Duration{Duration: ttl},
+		DeleteOnExpire:  info.DeleteOnExpire,
+		HostDefined:     info.HostDefined,
+		MaxUploadKbps:   info.MaxUploadKbps,
+		MaxDownloadKbps: info.MaxDownloadKbps,
+		ACL:             ToSocksACL(info.ACL),
+		AccessLog:       info.AccessLog,
+		DependsOn:       info.DependsOn,
+		OnStart:         info.OnStart,
+		OnStop:          info.OnStop,
+	}, nil
+}
+
+// ToForwardAddParams は core.ForwardRule を forward.add リクエストのパラメータに変換する。
+func ToForwardAddParams(rule core.ForwardRule) protocol.ForwardAddParams {
+	info := ToForwardInfo(rule)
+	return protocol.ForwardAddParams{
+		Name:            info.Name,
+		Host:            info.Host,
+		Type:            info.Type,
+		LocalPort:       info.LocalPort,
+		RemoteHost:      info.RemoteHost,
+		RemotePort:      info.RemotePort,
+		RemoteBindAddr:  info.RemoteBindAddr,
+		LocalBindAddr:   info.LocalBindAddr,
+		AutoConnect:     info.AutoConnect,
+		AutoReconnect:   info.AutoReconnect,
+		TTL:             info.TTL,
+		DeleteOnExpire:  info.DeleteOnExpire,
+		MaxUploadKbps:   info.MaxUploadKbps,
+		MaxDownloadKbps: info.MaxDownloadKbps,
+		ACL:             info.ACL,
+		AccessLog:       info.AccessLog,
+		DependsOn:       info.DependsOn,
+		OnStart:         info.OnStart,
+		OnStop:          info.OnStop,
+	}
+}
+
+// ToSocksACL は protocol.SocksACL を socksacl.ACL に変換する。nil の場合は nil を返す。
+func ToSocksACL(acl *protocol.SocksACL) *socksacl.ACL {
+	if acl == nil {
+		return nil
+	}
+	return &socksacl.ACL{Allow: acl.Allow, Deny: acl.Deny}
+}
+
+// ToSessionInfo は core.ForwardSession を protocol.SessionInfo に変換する。
+func ToSessionInfo(s core.ForwardSession) protocol.SessionInfo {
+	info := protocol.SessionInfo{
+		ID:              s.ID,
+		Name:            s.Rule.Name,
+		Host:            s.Rule.Host,
+		Type:            forwardTypeToWire(s.Rule.Type),
+		LocalPort:       s.Rule.LocalPort,
+		BoundPort:       s.BoundPort,
+		RemoteHost:      s.Rule.RemoteHost,
+		RemotePort:      s.Rule.RemotePort,
+		RemoteBindAddr:  s.Rule.RemoteBindAddr,
+		LocalBindAddr:   s.Rule.LocalBindAddr,
+		Status:          sessionStatusToWire(s.Status),
+		BytesSent:       s.BytesSent,
+		BytesReceived:   s.BytesReceived,
+		ReconnectCount:  s.ReconnectCount,
+		LastError:       s.LastError,
+		MaxUploadKbps:   s.Rule.MaxUploadKbps,
+		MaxDownloadKbps: s.Rule.MaxDownloadKbps,
+		SendRate:        s.SendRate,
+		ReceiveRate:     s.ReceiveRate,
+		RateHistory:     s.RateHistory,
+		ActiveConns:     s.ActiveConns,
+		StoppedReason:   string(s.StoppedReason),
+	}
+	if !s.ConnectedAt.IsZero() {
+		info.ConnectedAt = s.ConnectedAt.Format(time.RFC3339)
+	}
+	if !s.ExpiresAt.IsZero() {
+		info.ExpiresAt = s.ExpiresAt.Format(time.RFC3339)
+	}
+	if !s.StoppedAt.IsZero() {
+		info.StoppedAt = s.StoppedAt.Format(time.RFC3339)
+	}
+	for _, c := range s.Connections {
+		info.Connections = append(info.Connections, protocol.ConnInfo{Peer: c.Peer, Since: c.Since.Format(time.RFC3339)})
+	}
+	return info
+}
+
+// ToSessionMetrics は core.ForwardSession を event.metrics の protocol.SessionMetrics に変換する。
+func ToSessionMetrics(s core.ForwardSession) protocol.SessionMetrics {
+	m := protocol.SessionMetrics{
+		Name:          s.Rule.Name,
+		Status:        sessionStatusToWire(s.Status),
+		BytesSent:     s.BytesSent,
+		BytesReceived: s.BytesReceived,
+		SendRate:      s.SendRate,
+		ReceiveRate:   s.ReceiveRate,
+		ActiveConns:   s.ActiveConns,
+	}
+	if !s.ConnectedAt.IsZero() {
+		m.Uptime = time.Since(s.ConnectedAt).Truncate(time.Second).String()
+	}
+	return m
+}
+
+// connectionStateToWire は core.ConnectionState を IPC ワイヤー文字列に変換する。
+func connectionStateToWire(s core.ConnectionState) string {
+	switch s {
+	case core.Connected:
+		return protocol.StateConnected
+	case core.Connecting:
+		return protocol.StateConnecting
+	case core.Reconnecting:
+		return protocol.StateReconnecting
+	case core.PendingAuth:
+		return protocol.StatePendingAuth
+	case core.ConnectionError:
+		return protocol.StateError
+	default:
+		return protocol.StateDisconnected
+	}
+}
+
+// sessionStatusToWire は core.SessionStatus を IPC ワイヤー文字列に変換する。
+func sessionStatusToWire(s core.SessionStatus) string {
+	switch s {
+	case core.Active:
+		return protocol.SessionActive
+	case core.Starting:
+		return protocol.SessionStarting
+	case core.SessionReconnecting:
+		return protocol.SessionReconnecting
+	case core.SessionDraining:
+		return protocol.SessionDraining
+	case core.SessionError:
+		return protocol.SessionError
+	default:
+		return protocol.SessionStopped
+	}
+}
+
+// ParseConnectionState は IPC ワイヤー文字列を core.ConnectionState に変換する。
+func ParseConnectionState(s string) core.ConnectionState {
+	switch s {
+	case protocol.StateConnected:
+		return core.Connected
+	case protocol.StateConnecting:
+		return core.Connecting
+	case protocol.StateReconnecting:
+		return core.Reconnecting
+	case protocol.StatePendingAuth:
+		return core.PendingAuth
+	case protocol.StateError:
+		return core.ConnectionError
+	default:
+		return core.Disconnected
+	}
+}
+
+// ParseSessionStatus は IPC ワイヤー文字列を core.SessionStatus に変換する。
+func ParseSessionStatus(s string) core.SessionStatus {
+	switch s {
+	case protocol.SessionActive:
+		return core.Active
+	case protocol.SessionStarting:
+		return core.Starting
+	case protocol.SessionReconnecting:
+		return core.SessionReconnecting
+	case protocol.SessionDraining:
+		return core.SessionDraining
+	case protocol.SessionError:
+		return core.SessionError
+	default:
+		return core.Stopped
+	}
+}
+
+// forwardTypeToWire は core.ForwardType を IPC ワイヤー文字列に変換する。
+func forwardTypeToWire(t core.ForwardType) string {
+	switch t {
+	case core.Local:
+		return protocol.ForwardTypeLocal
+	case core.Remote:
+		return protocol.ForwardTypeRemote
+	case core.Dynamic:
+		return protocol.ForwardTypeDynamic
+	default:
+		return protocol.ForwardTypeLocal
+	}
+}
@@ -0,0 +1,260 @@
+package convert
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/socksacl"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestToHostInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		host core.SSHHost
+		want protocol.HostInfo
+	}{
+		{"connected host", core.SSHHost{
+			Name: "prod", HostName: "192.168.1.1", Port: 22, User: "admin",
+			State: core.Connected, ActiveForwardCount: 3,
+		}, protocol.HostInfo{
+			Name: "prod", HostName: "192.168.1.1", Port: 22, User: "admin",
+			State: "connected", ActiveForwardCount: 3,
+		}},
+		{"disconnected host", core.SSHHost{
+			Name: "staging", HostName: "10.0.0.1", Port: 2222, User: "deploy",
+			State: core.Disconnected,
+		}, protocol.HostInfo{
+			Name: "staging", HostName: "10.0.0.1", Port: 2222, User: "deploy",
+			State: "disconnected",
+		}},
+		{"pending_auth host uses snake_case wire format", core.SSHHost{
+			Name: "auth-host", HostName: "10.0.0.2", Port: 22, User: "user",
+			State: core.PendingAuth,
+		}, protocol.HostInfo{
+			Name: "auth-host", HostName: "10.0.0.2", Port: 22, User: "user",
+			State: "pending_auth",
+		}},
+		{"host with metadata", core.SSHHost{
+			Name: "bastion", HostName: "10.0.0.3", Port: 22, User: "ops",
+			State: core.Disconnected,
+			Meta:  core.HostMetadata{Notes: "shared bastion", AuthHint: "use yubikey", JumpDescription: "via vpn"},
+		}, protocol.HostInfo{
+			Name: "bastion", HostName: "10.0.0.3", Port: 22, User: "ops",
+			State: "disconnected", Notes: "shared bastion", AuthHint: "use yubikey", JumpDescription: "via vpn",
+		}},
+		{"host behind jump hosts", core.SSHHost{
+			Name: "db", HostName: "10.0.1.5", Port: 22, User: "app",
+			State: core.Disconnected, JumpChain: []string{"edge", "bastion"},
+		}, protocol.HostInfo{
+			Name: "db", HostName: "10.0.1.5", Port: 22, User: "app",
+			State: "disconnected", JumpChain: []string{"edge", "bastion"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToHostInfo(tt.host)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToHostInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToForwardInfo(t *testing.T) {
+	tests := []struct {
+		name string
+		rule core.ForwardRule
+		want protocol.ForwardInfo
+	}{
+		{"local forward rule", core.ForwardRule{
+			Name: "web", Host: "prod", Type: core.Local,
+			LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80, AutoConnect: true,
+		}, protocol.ForwardInfo{
+			Name: "web", Host: "prod", Type: "local",
+			LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80, AutoConnect: true,
+		}},
+		{"temporary rule with ttl", core.ForwardRule{
+			Name: "tmp", Host: "prod", Type: core.Dynamic, LocalPort: 1080,
+			TTL: core.Duration{Duration: 2 * time.Hour}, DeleteOnExpire: true,
+		}, protocol.ForwardInfo{
+			Name: "tmp", Host: "prod", Type: "dynamic", LocalPort: 1080, TTL: "2h0m0s", DeleteOnExpire: true,
+		}},
+		{"host-defined rule", core.ForwardRule{
+			Name: "db", Host: "prod", Type: core.Local, LocalPort: 5432, RemoteHost: "localhost", RemotePort: 5432, HostDefined: true,
+		}, protocol.ForwardInfo{
+			Name: "db", Host: "prod", Type: "local", LocalPort: 5432, RemoteHost: "localhost", RemotePort: 5432, HostDefined: true,
+		}},
+		{"rule with bandwidth limits", core.ForwardRule{
+			Name: "bulk", Host: "prod", Type: core.Dynamic, LocalPort: 1080, MaxUploadKbps: 512, MaxDownloadKbps: 2048,
+		}, protocol.ForwardInfo{
+			Name: "bulk", Host: "prod", Type: "dynamic", LocalPort: 1080, MaxUploadKbps: 512, MaxDownloadKbps: 2048,
+		}},
+		{"rule with socks acl", core.ForwardRule{
+			Name: "socks", Host: "prod", Type: core.Dynamic, LocalPort: 1080, ACL: &socksacl.ACL{Allow: []string{"10.0.0.0/8"}, Deny: []string{"*.corp"}}, AccessLog: core.AccessLogFile,
+		}, protocol.ForwardInfo{
+			Name: "socks", Host: "prod", Type: "dynamic", LocalPort: 1080, ACL: &protocol.SocksACL{Allow: []string{"10.0.0.0/8"}, Deny: []string{"*.corp"}}, AccessLog: "file",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToForwardInfo(tt.rule)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToForwardInfo() = %+v, want %+v", got, tt.want)
+			}
+			if back, err := ToForwardRule(got); err != nil || !reflect.DeepEqual(back, tt.rule) {
+				t.Errorf("ToForwardRule() = %+v, %v; want %+v", back, err, tt.rule)
+			}
+			if p := ToForwardAddParams(tt.rule); p.Name != tt.want.Name || p.TTL != tt.want.TTL || p.MaxUploadKbps != tt.want.MaxUploadKbps || p.AccessLog != tt.want.AccessLog || !reflect.DeepEqual(ToSocksACL(p.ACL), tt.rule.ACL) {
+				t.Errorf("ToForwardAddParams() = %+v, want fields of %+v", p, tt.want)
+			}
+		})
+	}
+	if _, err := ToForwardRule(protocol.ForwardInfo{Name: "x", Type: "tunnel"}); err == nil {
+		t.Error("ToForwardRule() should reject an unknown type")
+	}
+}
+
+func TestToSessionInfo(t *testing.T) {
+	connectedAt := time.Date(2026, 2, 11, 15, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		sess core.ForwardSession
+		want protocol.SessionInfo
+	}{
+		{"non-zero ConnectedAt formatted as RFC3339", core.ForwardSession{
+			ID:     "prod-local-8080",
+			Rule:   core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+			Status: core.Active, ConnectedAt: connectedAt,
+			BytesSent: 1024, BytesReceived: 2048, ReconnectCount: 1, LastError: "connection reset",
+		}, protocol.SessionInfo{
+			ID: "prod-local-8080", Name: "web", Host: "prod", Type: "local",
+			LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
+			Status: "active", ConnectedAt: connectedAt.Format(time.RFC3339),
+			BytesSent: 1024, BytesReceived: 2048, ReconnectCount: 1, LastError: "connection reset",
+		}},
+		{"zero ConnectedAt results in empty string", core.ForwardSession{
+			ID:     "staging-local-3000",
+			Rule:   core.ForwardRule{Name: "api", Host: "staging", Type: core.Local, LocalPort: 3000, RemoteHost: "localhost", RemotePort: 3000},
+			Status: core.Stopped, ConnectedAt: time.Time{},
+		}, protocol.SessionInfo{
+			ID: "staging-local-3000", Name: "api", Host: "staging", Type: "local",
+			LocalPort: 3000, RemoteHost: "localhost", RemotePort: 3000, Status: "stopped",
+		}},
+		{"allocated port reported as bound_port", core.ForwardSession{
+			ID:     "dev-dynamic-0",
+			Rule:   core.ForwardRule{Name: "socks", Host: "dev", Type: core.Dynamic},
+			Status: core.Active, BoundPort: 49152,
+		}, protocol.SessionInfo{
+			ID: "dev-dynamic-0", Name: "socks", Host: "dev", Type: "dynamic", BoundPort: 49152, Status: "active",
+		}},
+		{"ExpiresAt formatted as RFC3339", core.ForwardSession{
+			ID:     "dev-dynamic-1",
+			Rule:   core.ForwardRule{Name: "tmp", Host: "dev", Type: core.Dynamic, LocalPort: 1080},
+			Status: core.Active, ExpiresAt: connectedAt,
+		}, protocol.SessionInfo{
+			ID: "dev-dynamic-1", Name: "tmp", Host: "dev", Type: "dynamic", LocalPort: 1080, Status: "active",
+			ExpiresAt: connectedAt.Format(time.RFC3339),
+		}},
+		{"stop reason and time", core.ForwardSession{
+			Rule:   core.ForwardRule{Name: "tmp", Host: "dev", Type: core.Dynamic, LocalPort: 1080},
+			Status: core.Stopped, StoppedReason: core.StopReasonTTL, StoppedAt: connectedAt,
+		}, protocol.SessionInfo{
+			Name: "tmp", Host: "dev", Type: "dynamic", LocalPort: 1080, Status: "stopped",
+			StoppedReason: "ttl", StoppedAt: connectedAt.Format(time.RFC3339),
+		}},
+		{"bandwidth limits from rule", core.ForwardSession{
+			Rule:   core.ForwardRule{Name: "bulk", Host: "dev", Type: core.Dynamic, LocalPort: 1080, MaxDownloadKbps: 256},
+			Status: core.Active,
+		}, protocol.SessionInfo{
+			Name: "bulk", Host: "dev", Type: "dynamic", LocalPort: 1080, Status: "active", MaxDownloadKbps: 256,
+		}},
+		{"transfer rates and connections", core.ForwardSession{
+			Rule:     core.ForwardRule{Name: "socks", Host: "dev", Type: core.Dynamic, LocalPort: 1080},
+			Status:   core.Active,
+			SendRate: 512, ReceiveRate: 2048, RateHistory: []float64{1024, 2560}, ActiveConns: 2,
+			Connections: []core.ConnInfo{{Peer: "127.0.0.1:50000", Since: connectedAt}, {Since: connectedAt}},
+		}, protocol.SessionInfo{
+			Name: "socks", Host: "dev", Type: "dynamic", LocalPort: 1080, Status: "active",
+			SendRate: 512, ReceiveRate: 2048, RateHistory: []float64{1024, 2560}, ActiveConns: 2,
+			Connections: []protocol.ConnInfo{
+				{Peer: "127.0.0.1:50000", Since: connectedAt.Format(time.RFC3339)}, {Since: connectedAt.Format(time.RFC3339)},
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ToSessionInfo(tt.sess)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToSessionInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToSessionMetrics(t *testing.T) {
+	got := ToSessionMetrics(core.ForwardSession{
+		Rule: core.ForwardRule{Name: "web"}, Status: core.Active, ConnectedAt: time.Now().Add(-90 * time.Second),
+		BytesSent: 10, BytesReceived: 20, SendRate: 1.5, ReceiveRate: 3, ActiveConns: 4,
+	})
+	want := protocol.SessionMetrics{
+		Name: "web", Status: "active", BytesSent: 10, BytesReceived: 20, SendRate: 1.5, ReceiveRate: 3, ActiveConns: 4, Uptime: "1m30s",
+	}
+	if got != want {
+		t.Errorf("ToSessionMetrics() = %+v, want %+v", got, want)
+	}
+	if got := ToSessionMetrics(core.ForwardSession{Rule: core.ForwardRule{Name: "idle"}}); got.Uptime != "" {
+		t.Errorf("Uptime = %q, want empty for a stopped session", got.Uptime)
+	}
+}
+
+func TestParseConnectionState(t *testing.T) {
+	tests := []struct {
+		input string
+		want  core.ConnectionState
+	}{
+		{"connected", core.Connected}, {"connecting", core.Connecting},
+		{"reconnecting", core.Reconnecting}, {"error", core.ConnectionError},
+		{"disconnected", core.Disconnected}, {"unknown", core.Disconnected}, {"", core.Disconnected},
+	}
+	for _, tt := range tests {
+		if got := ParseConnectionState(tt.input); got != tt.want {
+			t.Errorf("ParseConnectionState(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseSessionStatus(t *testing.T) {
+	tests := []struct {
+		input string
+		want  core.SessionStatus
+	}{
+		{"active", core.Active}, {"starting", core.Starting},
+		{"reconnecting", core.SessionReconnecting}, {"draining", core.SessionDraining}, {"error", core.SessionError},
+		{"stopped", core.Stopped}, {"unknown", core.Stopped}, {"", core.Stopped},
+	}
+	for _, tt := range tests {
+		if got := ParseSessionStatus(tt.input); got != tt.want {
+			t.Errorf("ParseSessionStatus(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestToSSHHost(t *testing.T) {
+	host := ToSSHHost(protocol.HostInfo{
+		Name: "prod", HostName: "prod.example.com", Port: 22,
+		User: "deploy", State: "connected", ActiveForwardCount: 3,
+	})
+	if host.Name != "prod" || host.HostName != "prod.example.com" || host.Port != 22 {
+		t.Errorf("basic fields: Name=%q HostName=%q Port=%d", host.Name, host.HostName, host.Port)
+	}
+	if host.User != "deploy" || host.State != core.Connected || host.ActiveForwardCount != 3 {
+		t.Errorf("user/state: User=%q State=%v Count=%d", host.User, host.State, host.ActiveForwardCount)
+	}
+}
@@ -0,0 +1,2 @@
+// Package convert はコア型と IPC ワイヤー型の相互変換を提供する。
+package convert
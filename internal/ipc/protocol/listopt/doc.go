@@ -0,0 +1,2 @@
+// Package listopt は host.list・session.list に共通する絞り込み・ページング・フィールド選択を提供する。
+package listopt
@@ -0,0 +1,105 @@
+package listopt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Options は host.list・session.list に共通する絞り込み・ページング・フィールド選択のパラメータ。
+// すべて省略した場合は従来どおり全件・全フィールドを返す。
+type Options struct {
+	// State は状態による絞り込み（host.list は state、session.list は status と比較する）。空の場合は絞り込まない。
+	State string `json:"state,omitempty"`
+	// Offset は絞り込み後の先頭から読み飛ばす件数、Limit は返す最大件数（0 は無制限）。
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+	// Fields は返すフィールドの JSON 名。空の場合はすべて返す。name は常に返す。
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Validate はオフセット・件数が負でないことを確認する。
+func (o Options) Validate() error {
+	if o.Offset < 0 {
+		return fmt.Errorf("offset must not be negative: %d", o.Offset)
+	}
+	if o.Limit < 0 {
+		return fmt.Errorf("limit must not be negative: %d", o.Limit)
+	}
+	return nil
+}
+
+// Apply は items に State の絞り込み・ページング・フィールド選択を順に適用し、
+// 結果と、ページングする前の件数を返す。state は要素の状態を返す関数。
+func Apply[T any](items []T, opts Options, state func(T) string) ([]T, int, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, 0, err
+	}
+	if opts.State != "" {
+		filtered := make([]T, 0, len(items))
+		for _, item := range items {
+			if state(item) == opts.State {
+				filtered = append(filtered, item)
+			}
+		}
+		items = filtered
+	}
+	total := len(items)
+	items = Page(items, opts)
+	if err := SelectFields(items, opts.Fields); err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// Page は items から Offset・Limit の範囲を切り出す。範囲外の場合は空のスライスを返す。
+func Page[T any](items []T, opts Options) []T {
+	start := min(opts.Offset, len(items))
+	end := len(items)
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, end)
+	}
+	return items[start:end]
+}
+
+// SelectFields は items の各要素から fields に含まれないフィールドをゼロ値にする。
+// ゼロ値になったフィールドのうち omitempty のものは応答から省略される。
+// fields が空の場合は何もしない。未知のフィールド名を含む場合はエラーを返す。
+func SelectFields[T any](items []T, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	typ := reflect.TypeFor[T]()
+	keep := make([]bool, typ.NumField())
+	index := make(map[string]int, typ.NumField())
+	for i := range typ.NumField() {
+		name := jsonName(typ.Field(i))
+		index[name] = i
+		keep[i] = name == "name"
+	}
+	for _, f := range fields {
+		i, ok := index[f]
+		if !ok {
+			return fmt.Errorf("unknown field: %s", f)
+		}
+		keep[i] = true
+	}
+	for n := range items {
+		v := reflect.ValueOf(&items[n]).Elem()
+		for i, k := range keep {
+			if !k {
+				v.Field(i).SetZero()
+			}
+		}
+	}
+	return nil
+}
+
+// jsonName は構造体フィールドの JSON 名を返す。
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,86 @@
+package listopt
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type item struct {
+	Name    string    `json:"name"`
+	State   string    `json:"state"`
+	Port    int       `json:"port,omitempty"`
+	History []float64 `json:"history,omitempty"`
+}
+
+func TestPage(t *testing.T) {
+	items := []int{1, 2, 3, 4, 5}
+	tests := []struct {
+		name string
+		opts Options
+		want []int
+	}{
+		{"all", Options{}, []int{1, 2, 3, 4, 5}},
+		{"limit", Options{Limit: 2}, []int{1, 2}},
+		{"offset and limit", Options{Offset: 3, Limit: 10}, []int{4, 5}},
+		{"offset past end", Options{Offset: 9}, []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Page(items, tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Page(%+v) = %v, want %v", tt.opts, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectFields(t *testing.T) {
+	items := []item{{Name: "web", State: "active", Port: 8080, History: []float64{1, 2}}}
+	if err := SelectFields(items, []string{"state"}); err != nil {
+		t.Fatalf("SelectFields: %v", err)
+	}
+	want := item{Name: "web", State: "active"}
+	if !reflect.DeepEqual(items[0], want) {
+		t.Errorf("SelectFields = %+v, want %+v", items[0], want)
+	}
+	// 選択しなかった omitempty のフィールドは応答から省略される
+	data, err := json.Marshal(items[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"port"`) || strings.Contains(string(data), `"history"`) {
+		t.Errorf("JSON should omit unselected fields, got: %s", data)
+	}
+
+	if err := SelectFields(items, []string{"unknown"}); err == nil {
+		t.Error("SelectFields with an unknown field should fail")
+	}
+}
+
+func TestApply(t *testing.T) {
+	items := []item{
+		{Name: "a", State: "connected", Port: 22},
+		{Name: "b", State: "disconnected", Port: 22},
+		{Name: "c", State: "connected", Port: 22},
+	}
+	state := func(i item) string { return i.State }
+	got, total, err := Apply(items, Options{State: "connected", Limit: 1, Fields: []string{"state"}}, state)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if total != 2 {
+		t.Errorf("total = %d, want 2", total)
+	}
+	want := []item{{Name: "a", State: "connected"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Apply = %+v, want %+v", got, want)
+	}
+	if items[0].Port != 22 {
+		t.Error("Apply should not modify the caller's slice when filtering by state")
+	}
+
+	if _, _, err := Apply(items, Options{Offset: -1}, state); err == nil {
+		t.Error("Apply with a negative offset should fail")
+	}
+}
@@ -0,0 +1,36 @@
+package protocol
+
+// readOnlyMethods は状態を変更しない RPC メソッドの集合。
+// 読み取り専用クライアントはこの集合に含まれるメソッドのみ呼び出せる。
+var readOnlyMethods = map[string]struct{}{
+	"host.list":             {},
+	"host.stats":            {},
+	"host.changes":          {},
+	"forward.list":          {},
+	"forward.checkPort":     {},
+	"forward.export":        {},
+	"forward.listGroups":    {},
+	"session.list":          {},
+	"session.get":           {},
+	"session.changes":       {},
+	"config.get":            {},
+	"config.schema":         {},
+	"config.validate":       {},
+	"version.check":         {},
+	"daemon.status":         {},
+	"daemon.health":         {},
+	MethodEventsSubscribe:   {},
+	MethodEventsUnsubscribe: {},
+	MethodProfileList:       {},
+	MethodAuthLogin:         {},
+	MethodRPCCancel:         {},
+	MethodDaemonHello:       {},
+	MethodLogsSubscribe:     {},
+	MethodLogsUnsubscribe:   {},
+}
+
+// IsReadOnlyMethod は method が状態を変更しない読み取り専用メソッドかを返す。
+func IsReadOnlyMethod(method string) bool {
+	_, ok := readOnlyMethods[method]
+	return ok
+}
@@ -0,0 +1,48 @@
+package protocol
+
+import "testing"
+
+func TestIsReadOnlyMethod(t *testing.T) {
+	tests := []struct {
+		method string
+		want   bool
+	}{
+		{"host.list", true},
+		{"host.stats", true},
+		{"session.get", true},
+		{"host.changes", true},
+		{"session.changes", true},
+		{"config.get", true},
+		{"config.schema", true},
+		{"config.validate", true},
+		{"forward.checkPort", true},
+		{"forward.export", true},
+		{"forward.listGroups", true},
+		{"daemon.status", true},
+		{"daemon.health", true},
+		{MethodEventsSubscribe, true},
+		{MethodProfileList, true},
+		{MethodAuthLogin, true},
+		{MethodRPCCancel, true},
+		{MethodDaemonHello, true},
+		{MethodLogsSubscribe, true},
+		{"host.reload", false},
+		{"host.update", false},
+		{"ssh.connect", false},
+		{"forward.add", false},
+		{"forward.update", false},
+		{"forward.import", false},
+		{"forward.start", false},
+		{"forward.stopAll", false},
+		{"forward.startGroup", false},
+		{"config.update", false},
+		{"daemon.shutdown", false},
+		{"debug.faults", false},
+		{"unknown.method", false},
+	}
+	for _, tt := range tests {
+		if got := IsReadOnlyMethod(tt.method); got != tt.want {
+			t.Errorf("IsReadOnlyMethod(%q) = %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package protocol
 import (
 	"encoding/json"
 	"fmt"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcid"
 )
 
 // JSONRPCVersion は JSON-RPC プロトコルバージョンを表す。
@@ -34,22 +36,29 @@ const (
 	AuthenticationFailed = 1007
 	CredentialTimeout    = 1008
 	CredentialCancelled  = 1009
+	InvalidRuleName      = 1010
+	GroupNotFound        = 1011
+	InvalidConfig        = 1012
+	Unauthorized         = 1013
+	PermissionDenied     = 1014
+	RequestCancelled     = 1015
 )
 
 // Request は JSON-RPC 2.0 リクエストを表す。
+// ID がゼロ値（"id" フィールドなし）の場合は通知として扱う。
 type Request struct {
 	JSONRPC string          `json:"jsonrpc"`
-	ID      *int            `json:"id,omitempty"`
+	ID      rpcid.ID        `json:"id,omitzero"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
 }
 
 // Response は JSON-RPC 2.0 レスポンスを表す。
-// ID は *int を使用する。JSON-RPC 2.0 仕様では、パース不能なリクエストへのレスポンスで
-// "id": null を返す必要があるため。
+// JSON-RPC 2.0 仕様では、パース不能なリクエストへのレスポンスで "id": null を返す必要があるため、
+// ID のゼロ値は null としてシリアライズされる。
 type Response struct {
 	JSONRPC string          `json:"jsonrpc"`
-	ID      *int            `json:"id"`
+	ID      rpcid.ID        `json:"id"`
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   *RPCError       `json:"error,omitempty"`
 }
@@ -74,7 +83,7 @@ func (e *RPCError) Error() string {
 }
 
 // NewResponse は result を JSON にマーシャルして Response を生成する。
-func NewResponse(id *int, result any) (Response, error) {
+func NewResponse(id rpcid.ID, result any) (Response, error) {
 	data, err := json.Marshal(result)
 	if err != nil {
 		return Response{}, fmt.Errorf("marshal result: %w", err)
@@ -87,7 +96,7 @@ func NewResponse(id *int, result any) (Response, error) {
 }
 
 // NewErrorResponse はエラーコードとメッセージから Response を生成する。
-func NewErrorResponse(id *int, code int, message string) Response {
+func NewErrorResponse(id rpcid.ID, code int, message string) Response {
 	return Response{
 		JSONRPC: JSONRPCVersion,
 		ID:      id,
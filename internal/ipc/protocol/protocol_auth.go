@@ -0,0 +1,22 @@
+package protocol
+
+// クライアントの権限（ロール）。auth.login の Role と、リモート接続のクライアント証明書の OU で指定する。
+const (
+	// RoleAdmin は全てのメソッドを呼び出せる。
+	RoleAdmin = "admin"
+	// RoleReadOnly は IsReadOnlyMethod が true を返すメソッドのみ呼び出せる。
+	RoleReadOnly = "read-only"
+)
+
+// AuthLoginParams は auth.login リクエストのパラメータ。Token はデーモンが起動時に発行した認証トークン。
+// Role に RoleReadOnly を指定すると、管理者用のトークンでも読み取り専用で接続する（権限を上げることはできない）。
+type AuthLoginParams struct {
+	Token string `json:"token"`
+	Role  string `json:"role,omitempty"`
+}
+
+// AuthLoginResult は auth.login リクエストの結果。Role は接続に割り当てたロール。
+type AuthLoginResult struct {
+	Authenticated bool   `json:"authenticated"`
+	Role          string `json:"role"`
+}
@@ -1,5 +1,7 @@
 package protocol
 
+import "github.com/ousiassllc/moleport/internal/ipc/protocol/themeinfo"
+
 // --- 設定管理 ---
 
 // ConfigGetParams は config.get リクエストのパラメータ。
@@ -15,6 +17,7 @@ type ConfigGetResult struct {
 	Language      string                    `json:"language"`
 	UpdateCheck   UpdateCheckInfo           `json:"update_check"`
 	TUI           TUIInfo                   `json:"tui"`
+	UI            UIInfo                    `json:"ui"`
 }
 
 // UpdateCheckInfo はアップデートチェック設定の情報を表す。
@@ -43,6 +46,7 @@ type ReconnectInfo struct {
 	InitialDelay      string `json:"initial_delay"`
 	MaxDelay          string `json:"max_delay"`
 	KeepAliveInterval string `json:"keepalive_interval"`
+	KeepAliveCountMax int    `json:"keepalive_count_max"`
 }
 
 // SessionCfgInfo はセッション設定の情報を表す。
@@ -58,7 +62,15 @@ type LogInfo struct {
 
 // TUIInfo は TUI 設定の情報を表す。
 type TUIInfo struct {
-	Theme ThemeInfo `json:"theme"`
+	Theme  ThemeInfo             `json:"theme"`
+	Layout LayoutInfo            `json:"layout"`
+	Themes []themeinfo.UserTheme `json:"themes,omitempty"`
+}
+
+// LayoutInfo は TUI のペイン配分の設定の情報を表す。
+type LayoutInfo struct {
+	ForwardPercent int  `json:"forward_percent"`
+	HideLog        bool `json:"hide_log"`
 }
 
 // ThemeInfo はテーマ設定の情報を表す。
@@ -67,6 +79,11 @@ type ThemeInfo struct {
 	Accent string `json:"accent"`
 }
 
+// UIInfo は CLI/TUI 共通の表示設定の情報を表す。
+type UIInfo struct {
+	TimeFormat string `json:"time_format"`
+}
+
 // ConfigUpdateParams は config.update リクエストのパラメータ（部分更新）。
 // 各フィールドはポインタ型で、nil なら変更なしを意味する。
 type ConfigUpdateParams struct {
@@ -78,6 +95,7 @@ type ConfigUpdateParams struct {
 	Language      *string                          `json:"language,omitempty"`
 	UpdateCheck   *UpdateCheckUpdateInfo           `json:"update_check,omitempty"`
 	TUI           *TUIUpdateInfo                   `json:"tui,omitempty"`
+	UI            *UIUpdateInfo                    `json:"ui,omitempty"`
 }
 
 // UpdateCheckUpdateInfo はアップデートチェック設定の部分更新パラメータ。
@@ -87,7 +105,7 @@ type UpdateCheckUpdateInfo struct {
 }
 
 // HostConfigUpdateInfo はホスト別設定の部分更新パラメータ。
-// ReconnectUpdateInfo を共有型として再利用する。KeepAliveInterval はホスト別では無視される。
+// ReconnectUpdateInfo を共有型として再利用する。KeepAliveInterval と KeepAliveCountMax はホスト別では無視される。
 type HostConfigUpdateInfo struct {
 	Reconnect *ReconnectUpdateInfo `json:"reconnect,omitempty"`
 }
@@ -100,6 +118,7 @@ type ReconnectUpdateInfo struct {
 	InitialDelay      *string `json:"initial_delay,omitempty"`
 	MaxDelay          *string `json:"max_delay,omitempty"`
 	KeepAliveInterval *string `json:"keepalive_interval,omitempty"`
+	KeepAliveCountMax *int    `json:"keepalive_count_max,omitempty"`
 }
 
 // SessionCfgUpdateInfo はセッション設定の部分更新パラメータ。
@@ -115,7 +134,14 @@ type LogUpdateInfo struct {
 
 // TUIUpdateInfo は TUI 設定の部分更新パラメータ。
 type TUIUpdateInfo struct {
-	Theme *ThemeUpdateInfo `json:"theme,omitempty"`
+	Theme  *ThemeUpdateInfo  `json:"theme,omitempty"`
+	Layout *LayoutUpdateInfo `json:"layout,omitempty"`
+}
+
+// LayoutUpdateInfo は TUI のペイン配分の設定の部分更新パラメータ。
+type LayoutUpdateInfo struct {
+	ForwardPercent *int  `json:"forward_percent,omitempty"`
+	HideLog        *bool `json:"hide_log,omitempty"`
 }
 
 // ThemeUpdateInfo はテーマ設定の部分更新パラメータ。
@@ -124,7 +150,49 @@ type ThemeUpdateInfo struct {
 	Accent *string `json:"accent,omitempty"`
 }
 
+// UIUpdateInfo は表示設定の部分更新パラメータ。
+type UIUpdateInfo struct {
+	TimeFormat *string `json:"time_format,omitempty"`
+}
+
 // ConfigUpdateResult は config.update リクエストの結果。
 type ConfigUpdateResult struct {
 	OK bool `json:"ok"`
 }
+
+// ConfigSchemaResult は config.schema リクエストの結果。設定項目を親から順に並べる。
+type ConfigSchemaResult struct {
+	Fields []ConfigFieldSchema `json:"fields"`
+}
+
+// ConfigFieldSchema は設定項目 1 つのスキーマ。Path は YAML のキーをドットでつないだもので、
+// リストの要素は "[]"、マップの値は "*" で表す（例: "forwards[].local_port", "hosts.*.notes"）。
+type ConfigFieldSchema struct {
+	Path    string   `json:"path"`
+	Type    string   `json:"type"`           // "string" | "bool" | "int" | "duration" | "object" | "list" | "map"
+	Item    string   `json:"item,omitempty"` // list・map の要素の型
+	Default any      `json:"default,omitempty"`
+	Min     any      `json:"min,omitempty"`
+	Max     any      `json:"max,omitempty"`
+	Enum    []string `json:"enum,omitempty"`
+	Since   string   `json:"since"`
+}
+
+// ConfigValidateParams は config.validate リクエストのパラメータ。
+// Data は検証する config.yaml の内容で、空の場合はデーモンの config.yaml を検証する。
+type ConfigValidateParams struct {
+	Data string `json:"data,omitempty"`
+}
+
+// ConfigValidateResult は config.validate リクエストの結果。
+type ConfigValidateResult struct {
+	Valid  bool          `json:"valid"`
+	Issues []ConfigIssue `json:"issues,omitempty"`
+}
+
+// ConfigIssue は設定項目 1 つの検証エラー。InvalidConfig エラーの data にも使う。
+// Field は YAML のキーをドットでつないだパスで、YAML として解析できない場合は空。
+type ConfigIssue struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
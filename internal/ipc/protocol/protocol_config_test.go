@@ -2,6 +2,7 @@ package protocol
 
 import (
 	"encoding/json"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -88,167 +89,73 @@ func TestConfigUpdateParams_AllFields(t *testing.T) {
 	}
 }
 
-func TestSSHEventNotification_JSONRoundtrip(t *testing.T) {
-	original := SSHEventNotification{
-		Type:  "error",
-		Host:  "prod",
-		Error: "connection refused",
-	}
-
-	data, err := json.Marshal(original)
+// roundtrip は v を JSON で往復させた結果を返す。
+func roundtrip[T any](t *testing.T, v T) T {
+	t.Helper()
+	data, err := json.Marshal(v)
 	if err != nil {
-		t.Fatalf("Marshal SSHEventNotification: %v", err)
+		t.Fatalf("Marshal %T: %v", v, err)
 	}
-
-	var got SSHEventNotification
+	var got T
 	if err := json.Unmarshal(data, &got); err != nil {
-		t.Fatalf("Unmarshal SSHEventNotification: %v", err)
-	}
-
-	if got != original {
-		t.Errorf("SSHEventNotification roundtrip: got %+v, want %+v", got, original)
+		t.Fatalf("Unmarshal %T: %v", v, err)
 	}
+	return got
 }
 
-func TestSSHEventNotification_OmitsErrorWhenEmpty(t *testing.T) {
-	notif := SSHEventNotification{Type: "connected", Host: "prod"}
-	data, err := json.Marshal(notif)
+// assertOmits は v の JSON に keys が含まれないことを検証する。
+func assertOmits(t *testing.T, v any, keys ...string) {
+	t.Helper()
+	data, err := json.Marshal(v)
 	if err != nil {
-		t.Fatalf("Marshal SSHEventNotification: %v", err)
+		t.Fatalf("Marshal %T: %v", v, err)
 	}
-	if strings.Contains(string(data), `"error"`) {
-		t.Errorf("SSHEventNotification JSON should omit error when empty, got: %s", data)
+	for _, k := range keys {
+		if strings.Contains(string(data), `"`+k+`"`) {
+			t.Errorf("%T JSON should omit empty %s, got: %s", v, k, data)
+		}
 	}
 }
 
-func TestForwardEventNotification_JSONRoundtrip(t *testing.T) {
-	original := ForwardEventNotification{
-		Type:  "error",
-		Name:  "web",
-		Host:  "prod",
-		Error: "port in use",
-	}
-
-	data, err := json.Marshal(original)
-	if err != nil {
-		t.Fatalf("Marshal ForwardEventNotification: %v", err)
+func TestNotifications_JSONRoundtrip(t *testing.T) {
+	sshEv := SSHEventNotification{Type: "error", Host: "prod", Error: "connection refused"}
+	if got := roundtrip(t, sshEv); got != sshEv {
+		t.Errorf("SSHEventNotification roundtrip: got %+v, want %+v", got, sshEv)
 	}
-
-	var got ForwardEventNotification
-	if err := json.Unmarshal(data, &got); err != nil {
-		t.Fatalf("Unmarshal ForwardEventNotification: %v", err)
+	fwdEv := ForwardEventNotification{Type: "error", Name: "web", Host: "prod", Error: "port in use"}
+	if got := roundtrip(t, fwdEv); got != fwdEv {
+		t.Errorf("ForwardEventNotification roundtrip: got %+v, want %+v", got, fwdEv)
 	}
-
-	if got != original {
-		t.Errorf("ForwardEventNotification roundtrip: got %+v, want %+v", got, original)
+	metrics := MetricsEventNotification{Sessions: []SessionMetrics{
+		{Name: "web", Status: "active", BytesSent: 1024, BytesReceived: 2048, Uptime: "1h 30m"},
+		{Name: "db", Status: "active", BytesSent: 512, BytesReceived: 4096, Uptime: "45m"},
+	}}
+	if got := roundtrip(t, metrics); !reflect.DeepEqual(got, metrics) {
+		t.Errorf("MetricsEventNotification roundtrip: got %+v, want %+v", got, metrics)
 	}
 }
 
-func TestMetricsEventNotification_JSONRoundtrip(t *testing.T) {
-	original := MetricsEventNotification{
-		Sessions: []SessionMetrics{
-			{
-				Name:          "web",
-				Status:        "active",
-				BytesSent:     1024,
-				BytesReceived: 2048,
-				Uptime:        "1h 30m",
-			},
-			{
-				Name:          "db",
-				Status:        "active",
-				BytesSent:     512,
-				BytesReceived: 4096,
-				Uptime:        "45m",
-			},
-		},
-	}
-
-	data, err := json.Marshal(original)
-	if err != nil {
-		t.Fatalf("Marshal MetricsEventNotification: %v", err)
-	}
-
-	var got MetricsEventNotification
-	if err := json.Unmarshal(data, &got); err != nil {
-		t.Fatalf("Unmarshal MetricsEventNotification: %v", err)
-	}
-
-	if len(got.Sessions) != 2 {
-		t.Fatalf("len(Sessions) = %d, want 2", len(got.Sessions))
-	}
-	if got.Sessions[0] != original.Sessions[0] {
-		t.Errorf("Sessions[0] = %+v, want %+v", got.Sessions[0], original.Sessions[0])
-	}
-	if got.Sessions[1] != original.Sessions[1] {
-		t.Errorf("Sessions[1] = %+v, want %+v", got.Sessions[1], original.Sessions[1])
-	}
+func TestSSHEventNotification_OmitsErrorWhenEmpty(t *testing.T) {
+	assertOmits(t, SSHEventNotification{Type: "connected", Host: "prod"}, "error")
 }
 
-func TestVersionCheckResult_JSONRoundtrip(t *testing.T) {
-	original := VersionCheckResult{
+func TestVersionCheck_JSONRoundtrip(t *testing.T) {
+	result := VersionCheckResult{
 		CurrentVersion:  "v0.3.0",
 		LatestVersion:   "v0.4.0",
 		UpdateAvailable: true,
 		ReleaseURL:      "https://github.com/ousiassllc/moleport/releases/tag/v0.4.0",
 		CheckedAt:       "2026-03-04T10:00:00Z",
 	}
-
-	data, err := json.Marshal(original)
-	if err != nil {
-		t.Fatalf("Marshal VersionCheckResult: %v", err)
+	if got := roundtrip(t, result); got != result {
+		t.Errorf("VersionCheckResult roundtrip: got %+v, want %+v", got, result)
 	}
-
-	var got VersionCheckResult
-	if err := json.Unmarshal(data, &got); err != nil {
-		t.Fatalf("Unmarshal VersionCheckResult: %v", err)
-	}
-
-	if got != original {
-		t.Errorf("VersionCheckResult roundtrip: got %+v, want %+v", got, original)
+	info := UpdateCheckInfo{Enabled: true, Interval: "24h0m0s"}
+	if got := roundtrip(t, info); got != info {
+		t.Errorf("UpdateCheckInfo roundtrip: got %+v, want %+v", got, info)
 	}
 }
 
 func TestVersionCheckResult_OmitsEmptyFields(t *testing.T) {
-	result := VersionCheckResult{
-		CurrentVersion:  "v0.3.0",
-		UpdateAvailable: false,
-	}
-
-	data, err := json.Marshal(result)
-	if err != nil {
-		t.Fatalf("Marshal VersionCheckResult: %v", err)
-	}
-
-	s := string(data)
-	if strings.Contains(s, `"latest_version"`) {
-		t.Errorf("VersionCheckResult JSON should omit empty latest_version, got: %s", s)
-	}
-	if strings.Contains(s, `"release_url"`) {
-		t.Errorf("VersionCheckResult JSON should omit empty release_url, got: %s", s)
-	}
-	if strings.Contains(s, `"checked_at"`) {
-		t.Errorf("VersionCheckResult JSON should omit empty checked_at, got: %s", s)
-	}
-}
-
-func TestUpdateCheckInfo_JSONRoundtrip(t *testing.T) {
-	original := UpdateCheckInfo{
-		Enabled:  true,
-		Interval: "24h0m0s",
-	}
-
-	data, err := json.Marshal(original)
-	if err != nil {
-		t.Fatalf("Marshal UpdateCheckInfo: %v", err)
-	}
-
-	var got UpdateCheckInfo
-	if err := json.Unmarshal(data, &got); err != nil {
-		t.Fatalf("Unmarshal UpdateCheckInfo: %v", err)
-	}
-
-	if got != original {
-		t.Errorf("UpdateCheckInfo roundtrip: got %+v, want %+v", got, original)
-	}
+	assertOmits(t, VersionCheckResult{CurrentVersion: "v0.3.0"}, "latest_version", "release_url", "checked_at")
 }
@@ -15,6 +15,27 @@ type DaemonStatusResult struct {
 	ActiveSSHConnections int      `json:"active_ssh_connections"`
 	ActiveForwards       int      `json:"active_forwards"`
 	Warnings             []string `json:"warnings,omitempty"`
+	// Recovery は異常終了後の起動で前回状態から復元した内容。通常の起動では nil。
+	Recovery *RecoverySummary `json:"recovery,omitempty"`
+	// Faults は障害注入のカウンター。chaos ビルドでのみ設定される。
+	Faults *FaultCountersInfo `json:"faults,omitempty"`
+}
+
+// RecoverySummary は異常終了後の起動時に前回状態のスナップショットから復元した内容を表す。
+type RecoverySummary struct {
+	SnapshotAt string   `json:"snapshot_at,omitempty"`
+	Restored   []string `json:"restored"`
+	Failed     []string `json:"failed,omitempty"`
+	Hosts      []string `json:"hosts"`
+	// Sessions は再開したルールの前回の開始時刻と再接続回数。
+	Sessions []RecoveredSession `json:"sessions,omitempty"`
+}
+
+// RecoveredSession は異常終了の前に稼働していたフォワードの稼働状況。
+type RecoveredSession struct {
+	Name           string `json:"name"`
+	StartedAt      string `json:"started_at,omitempty"`
+	ReconnectCount int    `json:"reconnect_count"`
 }
 
 // DaemonShutdownParams は daemon.shutdown リクエストのパラメータ。
@@ -26,3 +47,33 @@ type DaemonShutdownParams struct {
 type DaemonShutdownResult struct {
 	OK bool `json:"ok"`
 }
+
+// ヘルスチェックの状態（HealthCheck.Status と DaemonHealthResult.Status）。
+const (
+	HealthOK = "ok"
+	// HealthWarn は稼働を継続しているが、一部のフォワードや SSH 接続がエラー状態であることを表す。
+	HealthWarn = "warn"
+	// HealthFail はソケットの消失や、指定したフォワードが active でないことを表す。
+	HealthFail = "fail"
+)
+
+// DaemonHealthParams は daemon.health リクエストのパラメータ。
+type DaemonHealthParams struct {
+	// Forwards は active であることを確認するルール名。active でない場合は fail になる。
+	Forwards []string `json:"forwards,omitempty"`
+}
+
+// DaemonHealthResult は daemon.health リクエストの結果。Status は Checks のうち最も悪い状態。
+type DaemonHealthResult struct {
+	Status         string        `json:"status"`
+	ActiveForwards int           `json:"active_forwards"`
+	ErrorForwards  int           `json:"error_forwards"`
+	Checks         []HealthCheck `json:"checks"`
+}
+
+// HealthCheck はサブシステムごとの確認結果。Name は "config"・"socket"・"forwards"・"ssh"・"forward:<ルール名>"。
+type HealthCheck struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+}
@@ -79,3 +79,22 @@ func TestDaemonStatusResult_OmitsEmptyWarnings(t *testing.T) {
 		t.Errorf("DaemonStatusResult JSON should omit empty warnings, got: %s", data)
 	}
 }
+
+func TestCompatible(t *testing.T) {
+	tests := []struct {
+		name            string
+		version, minVer int
+		want            bool
+	}{
+		{"same version", ProtocolVersion, MinProtocolVersion, true},
+		{"legacy daemon", LegacyProtocolVersion, LegacyProtocolVersion, true},
+		{"peer too old", MinProtocolVersion - 1, MinProtocolVersion - 1, false},
+		{"peer requires a newer version", ProtocolVersion + 1, ProtocolVersion + 1, false},
+		{"newer peer that still accepts this version", ProtocolVersion + 1, ProtocolVersion, true},
+	}
+	for _, tt := range tests {
+		if got := Compatible(tt.version, tt.minVer); got != tt.want {
+			t.Errorf("%s: Compatible(%d, %d) = %v, want %v", tt.name, tt.version, tt.minVer, got, tt.want)
+		}
+	}
+}
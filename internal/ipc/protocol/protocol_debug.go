@@ -0,0 +1,36 @@
+package protocol
+
+// --- デバッグ（障害注入） ---
+
+// DebugFaultsParams は debug.faults リクエストのパラメータ。
+// すべてのフィールドは省略可能で、省略されたフィールドは現在の値を維持する。
+// パラメータ自体を省略した場合は現在の設定とカウンターを返すのみ。
+type DebugFaultsParams struct {
+	Enabled                *bool    `json:"enabled,omitempty"`
+	DropProbability        *float64 `json:"drop_probability,omitempty"`
+	DropInterval           *string  `json:"drop_interval,omitempty"`
+	AcceptDelay            *string  `json:"accept_delay,omitempty"`
+	DialFailureProbability *float64 `json:"dial_failure_probability,omitempty"`
+}
+
+// FaultConfigInfo は障害注入の設定情報。
+type FaultConfigInfo struct {
+	Enabled                bool    `json:"enabled"`
+	DropProbability        float64 `json:"drop_probability"`
+	DropInterval           string  `json:"drop_interval"`
+	AcceptDelay            string  `json:"accept_delay"`
+	DialFailureProbability float64 `json:"dial_failure_probability"`
+}
+
+// FaultCountersInfo は注入した障害の累計回数。
+type FaultCountersInfo struct {
+	DroppedConnections int64 `json:"dropped_connections"`
+	DelayedAccepts     int64 `json:"delayed_accepts"`
+	FailedDials        int64 `json:"failed_dials"`
+}
+
+// DebugFaultsResult は debug.faults リクエストの結果。
+type DebugFaultsResult struct {
+	Config   FaultConfigInfo   `json:"config"`
+	Counters FaultCountersInfo `json:"counters"`
+}
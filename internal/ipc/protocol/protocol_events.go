@@ -5,11 +5,25 @@ package protocol
 // EventsSubscribeParams は events.subscribe リクエストのパラメータ。
 type EventsSubscribeParams struct {
 	Types []string `json:"types"`
+	// SinceID は再接続したクライアントが最後に受け取ったイベント ID。0 より大きい場合、
+	// それより後の SSH・フォワードイベントを結果の Replay で返す。
+	SinceID int64 `json:"since_id,omitempty"`
+	// Hosts を指定した場合、ホスト名を持つイベントはこれらのホストのものだけを配信する。
+	Hosts []string `json:"hosts,omitempty"`
+	// Rules を指定した場合、ルール名を持つイベントはこれらのルールのものだけを配信する。
+	Rules []string `json:"rules,omitempty"`
 }
 
 // EventsSubscribeResult は events.subscribe リクエストの結果。
 type EventsSubscribeResult struct {
 	SubscriptionID string `json:"subscription_id"`
+	// LastEventID は購読の開始時点で最後に発行したイベント ID。次の再接続時の SinceID に使う。
+	LastEventID int64 `json:"last_event_id"`
+	// Replay は SinceID より後に発生した SSH・フォワードイベントの通知（古い順）。
+	Replay []Notification `json:"replay,omitempty"`
+	// Truncated は SinceID より後のイベントの一部をデーモンが保持しておらず、Replay に含まれないことを示す。
+	// クライアントは一覧を取得し直す必要がある。
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // EventsUnsubscribeParams は events.unsubscribe リクエストのパラメータ。
@@ -26,17 +40,75 @@ type EventsUnsubscribeResult struct {
 
 // SSHEventNotification は SSH イベント通知を表す。
 type SSHEventNotification struct {
-	Type  string `json:"type"`
-	Host  string `json:"host"`
-	Error string `json:"error,omitempty"`
+	EventID int64  `json:"event_id,omitempty"` // events.subscribe の since_id に指定するイベント ID
+	Type    string `json:"type"`
+	Host    string `json:"host"`
+	Error   string `json:"error,omitempty"`
 }
 
+// HostEventNotification はホストの疎通確認結果の変化（health）またはホスト一覧の再読み込み（changed）の通知を表す。
+type HostEventNotification struct {
+	Type    string   `json:"type"`
+	Host    string   `json:"host,omitempty"`
+	Health  string   `json:"health,omitempty"`
+	Added   []string `json:"added,omitempty"`   // changed で追加されたホスト
+	Removed []string `json:"removed,omitempty"` // changed で削除されたホスト
+}
+
+// HostEventNotification の Type。
+const (
+	HostEventTypeHealth  = "health"
+	HostEventTypeChanged = "changed"
+)
+
+// ConfigEventNotification は config.yaml の変更を稼働中のデーモンへ反映したことの通知を表す。
+type ConfigEventNotification struct {
+	Type     string   `json:"type"`
+	Sections []string `json:"sections"` // 変更された config.yaml のトップレベルのキー
+}
+
+// ConfigEventTypeChanged は ConfigEventNotification の Type。
+const ConfigEventTypeChanged = "changed"
+
 // ForwardEventNotification はポートフォワーディングイベント通知を表す。
 type ForwardEventNotification struct {
-	Type  string `json:"type"`
-	Name  string `json:"name"`
-	Host  string `json:"host"`
-	Error string `json:"error,omitempty"`
+	EventID  int64  `json:"event_id,omitempty"` // events.subscribe の since_id に指定するイベント ID
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Host     string `json:"host"`
+	FromHost string `json:"from_host,omitempty"` // migrated の場合の移行元ホスト
+	Port     int    `json:"port,omitempty"`      // Listen 中のローカルポート（started/restored/migrated 等）
+	Conns    int    `json:"conns,omitempty"`     // draining の場合の完了を待つ転送中の接続数
+	Error    string `json:"error,omitempty"`
+}
+
+// DaemonEventNotification はデーモン自体のイベント通知を表す。
+type DaemonEventNotification struct {
+	Type      string             `json:"type"`
+	Recovery  *RecoverySummary   `json:"recovery,omitempty"`  // recovered の場合の復元内容
+	AutoStart *AutoStartProgress `json:"autostart,omitempty"` // autostart の場合の進捗
+}
+
+// AutoStartProgress はデーモン起動時の auto_connect のルールの自動開始の進捗を表す。
+type AutoStartProgress struct {
+	Rule    string `json:"rule"`
+	Status  string `json:"status"` // starting / started / retrying / failed
+	Attempt int    `json:"attempt"`
+	RetryIn string `json:"retry_in,omitempty"` // retrying の場合の次の試行までの時間
+	Error   string `json:"error,omitempty"`
+}
+
+// SummaryEventNotification はデーモン全体の集計値を定期的に通知する。
+// メニューバーやステータスバーなど、セッション単位のメトリクスを必要としない軽量クライアント向け。
+type SummaryEventNotification struct {
+	ActiveForwards int     `json:"active_forwards"`
+	TotalForwards  int     `json:"total_forwards"`
+	HostsConnected int     `json:"hosts_connected"`
+	BytesSent      int64   `json:"bytes_sent"`
+	BytesReceived  int64   `json:"bytes_received"`
+	ThroughputBps  float64 `json:"throughput_bps"` // 送受信合計の毎秒バイト数（指数移動平均）
+	Errors         int     `json:"errors"`         // 直近の集計間隔に発生したエラー数
+	Interval       string  `json:"interval"`
 }
 
 // MetricsEventNotification はメトリクスイベント通知を表す。
@@ -46,22 +118,27 @@ type MetricsEventNotification struct {
 
 // SessionMetrics はセッションのメトリクス情報を表す。
 type SessionMetrics struct {
-	Name          string `json:"name"`
-	Status        string `json:"status"`
-	BytesSent     int64  `json:"bytes_sent"`
-	BytesReceived int64  `json:"bytes_received"`
-	Uptime        string `json:"uptime"`
+	Name          string  `json:"name"`
+	Status        string  `json:"status"`
+	BytesSent     int64   `json:"bytes_sent"`
+	BytesReceived int64   `json:"bytes_received"`
+	SendRate      float64 `json:"send_rate"`    // 直近の送信レート（バイト/秒）
+	ReceiveRate   float64 `json:"receive_rate"` // 直近の受信レート（バイト/秒）
+	ActiveConns   int     `json:"active_conns"`
+	Uptime        string  `json:"uptime"`
 }
 
 // --- クレデンシャル認証 ---
 
 // CredentialRequestNotification はデーモンからクライアントへのクレデンシャル要求通知。
 type CredentialRequestNotification struct {
-	RequestID string       `json:"request_id"`
-	Type      string       `json:"type"` // "password" | "passphrase" | "keyboard-interactive"
-	Host      string       `json:"host"`
-	Prompt    string       `json:"prompt,omitempty"`
-	Prompts   []PromptData `json:"prompts,omitempty"`
+	RequestID   string       `json:"request_id"`
+	Type        string       `json:"type"` // "password" | "passphrase" | "keyboard-interactive" | "host-key"
+	Host        string       `json:"host"`
+	Prompt      string       `json:"prompt,omitempty"`
+	Prompts     []PromptData `json:"prompts,omitempty"`
+	KeyType     string       `json:"key_type,omitempty"`    // host-key の鍵の種別
+	Fingerprint string       `json:"fingerprint,omitempty"` // host-key の SHA256 フィンガープリント
 }
 
 // PromptData は keyboard-interactive 認証の個別プロンプト。
@@ -1,7 +1,24 @@
 package protocol
 
+import (
+	"fmt"
+	"time"
+)
+
 // --- ポートフォワーディング管理 ---
 
+// ParseTTL は ttl パラメータの期間文字列（"2h" 等）を解析する。空文字列は期限なしの 0 を返す。
+func ParseTTL(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid ttl %q: must be a positive duration such as 2h", s)
+	}
+	return ttl, nil
+}
+
 // ForwardListParams は forward.list リクエストのパラメータ。
 type ForwardListParams struct {
 	Host string `json:"host,omitempty"`
@@ -14,26 +31,56 @@ type ForwardListResult struct {
 
 // ForwardInfo はポートフォワーディングルールの情報を表す。
 type ForwardInfo struct {
-	Name           string `json:"name"`
-	Host           string `json:"host"`
-	Type           string `json:"type"`
-	LocalPort      int    `json:"local_port"`
-	RemoteHost     string `json:"remote_host,omitempty"`
-	RemotePort     int    `json:"remote_port,omitempty"`
-	RemoteBindAddr string `json:"remote_bind_addr,omitempty"`
-	AutoConnect    bool   `json:"auto_connect"`
+	Name            string    `json:"name"`
+	Host            string    `json:"host"`
+	Type            string    `json:"type"`
+	LocalPort       int       `json:"local_port"`
+	RemoteHost      string    `json:"remote_host,omitempty"`
+	RemotePort      int       `json:"remote_port,omitempty"`
+	RemoteBindAddr  string    `json:"remote_bind_addr,omitempty"`
+	LocalBindAddr   string    `json:"local_bind_addr,omitempty"`
+	AutoConnect     bool      `json:"auto_connect"`
+	AutoReconnect   bool      `json:"auto_reconnect,omitempty"`
+	ImportKey       string    `json:"import_key,omitempty"`
+	TTL             string    `json:"ttl,omitempty"`               // 開始から自動停止までの期間（"2h" 等）
+	DeleteOnExpire  bool      `json:"delete_on_expire,omitempty"`  // 期限切れ時にルールを削除する一時ルール
+	HostDefined     bool      `json:"host_defined,omitempty"`      // ssh_config のマジックコメント等でホストに定義されたルール
+	MaxUploadKbps   int       `json:"max_upload_kbps,omitempty"`   // 送信の帯域上限（kbps）
+	MaxDownloadKbps int       `json:"max_download_kbps,omitempty"` // 受信の帯域上限（kbps）
+	ACL             *SocksACL `json:"acl,omitempty"`               // dynamic の SOCKS5 で許可する宛先
+	AccessLog       string    `json:"access_log,omitempty"`        // 接続ごとのアクセスログの出力先（"file" / "log"）
+	DependsOn       []string  `json:"depends_on,omitempty"`        // 開始前に起動している必要があるルール名またはホスト名
+	OnStart         string    `json:"on_start,omitempty"`          // 開始後に実行するコマンド
+	OnStop          string    `json:"on_stop,omitempty"`           // 停止前に実行するコマンド
 }
 
 // ForwardAddParams は forward.add リクエストのパラメータ。
 type ForwardAddParams struct {
-	Name           string `json:"name,omitempty"`
-	Host           string `json:"host"`
-	Type           string `json:"type"`
-	LocalPort      int    `json:"local_port"`
-	RemoteHost     string `json:"remote_host,omitempty"`
-	RemotePort     int    `json:"remote_port,omitempty"`
-	RemoteBindAddr string `json:"remote_bind_addr,omitempty"`
-	AutoConnect    bool   `json:"auto_connect"`
+	Name            string    `json:"name,omitempty"`
+	Host            string    `json:"host"`
+	Type            string    `json:"type"`
+	LocalPort       int       `json:"local_port"`
+	RemoteHost      string    `json:"remote_host,omitempty"`
+	RemotePort      int       `json:"remote_port,omitempty"`
+	RemoteBindAddr  string    `json:"remote_bind_addr,omitempty"`
+	LocalBindAddr   string    `json:"local_bind_addr,omitempty"`
+	AutoConnect     bool      `json:"auto_connect"`
+	AutoReconnect   bool      `json:"auto_reconnect,omitempty"`
+	TTL             string    `json:"ttl,omitempty"`
+	DeleteOnExpire  bool      `json:"delete_on_expire,omitempty"`
+	MaxUploadKbps   int       `json:"max_upload_kbps,omitempty"`
+	MaxDownloadKbps int       `json:"max_download_kbps,omitempty"`
+	ACL             *SocksACL `json:"acl,omitempty"`
+	AccessLog       string    `json:"access_log,omitempty"`
+	DependsOn       []string  `json:"depends_on,omitempty"`
+	OnStart         string    `json:"on_start,omitempty"`
+	OnStop          string    `json:"on_stop,omitempty"`
+}
+
+// SocksACL は dynamic ルールの SOCKS5 プロキシで許可・拒否する宛先（CIDR またはホスト名のグロブ）。
+type SocksACL struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
 }
 
 // ForwardAddResult は forward.add リクエストの結果。
@@ -54,6 +101,7 @@ type ForwardDeleteResult struct {
 // ForwardStartParams は forward.start リクエストのパラメータ。
 type ForwardStartParams struct {
 	Name string `json:"name"`
+	TTL  string `json:"ttl,omitempty"` // 指定時はルールの TTL の代わりにこの期間で自動停止する
 }
 
 // ForwardStartResult は forward.start リクエストの結果。
@@ -73,6 +121,96 @@ type ForwardStopResult struct {
 	Status string `json:"status"`
 }
 
+// ForwardSetLimitParams は forward.setLimit リクエストのパラメータ。上限は kbps で、0 は無制限。
+type ForwardSetLimitParams struct {
+	Name            string `json:"name"`
+	MaxUploadKbps   int    `json:"max_upload_kbps"`
+	MaxDownloadKbps int    `json:"max_download_kbps"`
+}
+
+// ForwardSetLimitResult は forward.setLimit リクエストの結果。
+type ForwardSetLimitResult struct {
+	Name            string `json:"name"`
+	MaxUploadKbps   int    `json:"max_upload_kbps"`
+	MaxDownloadKbps int    `json:"max_download_kbps"`
+}
+
+// ForwardMigrateParams は forward.migrate リクエストのパラメータ。
+type ForwardMigrateParams struct {
+	Name string `json:"name"`
+	To   string `json:"to"`
+}
+
+// ForwardMigrateResult は forward.migrate リクエストの結果。
+type ForwardMigrateResult struct {
+	Name     string `json:"name"`
+	FromHost string `json:"from_host"`
+	Host     string `json:"host"`
+	Status   string `json:"status"`
+}
+
+// ForwardUpdateParams は forward.update リクエストのパラメータ。省略したフィールドは現在の値のまま変更しない。
+// 接続先ホストの変更は forward.migrate、帯域上限の変更は forward.setLimit を使う。
+type ForwardUpdateParams struct {
+	Name           string  `json:"name"`
+	LocalPort      *int    `json:"local_port,omitempty"`
+	RemoteHost     *string `json:"remote_host,omitempty"`
+	RemotePort     *int    `json:"remote_port,omitempty"`
+	LocalBindAddr  *string `json:"local_bind_addr,omitempty"`
+	RemoteBindAddr *string `json:"remote_bind_addr,omitempty"`
+	AutoConnect    *bool   `json:"auto_connect,omitempty"`
+	AutoReconnect  *bool   `json:"auto_reconnect,omitempty"`
+	TTL            *string `json:"ttl,omitempty"` // 空文字列で TTL を解除する
+	AccessLog      *string `json:"access_log,omitempty"`
+}
+
+// ForwardUpdateResult は forward.update リクエストの結果。Status は更新後のセッションの状態。
+type ForwardUpdateResult struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// ForwardExportParams は forward.export リクエストのパラメータ。Format は "yaml"（省略時）または "json"。
+type ForwardExportParams struct {
+	Format string `json:"format,omitempty"`
+}
+
+// ForwardExportResult は forward.export リクエストの結果。Data はルールとホスト別設定を書き出した文書。
+type ForwardExportResult struct {
+	Format string `json:"format"`
+	Data   string `json:"data"`
+}
+
+// ForwardImportParams は forward.import リクエストのパラメータ。Data は forward.export と同じ形式の文書（YAML または JSON）。
+// Replace なら同名のルールと設定済みのホスト別設定を置き換え、DryRun なら反映せずに変更内容のみ返す。
+type ForwardImportParams struct {
+	Data    string `json:"data"`
+	Replace bool   `json:"replace,omitempty"`
+	DryRun  bool   `json:"dry_run,omitempty"`
+}
+
+// ForwardImportResult は forward.import リクエストの結果。Skipped は見送ったルールと理由（"name: reason"）。
+type ForwardImportResult struct {
+	Added    []string `json:"added"`
+	Replaced []string `json:"replaced,omitempty"`
+	Hosts    []string `json:"hosts,omitempty"`
+	Skipped  []string `json:"skipped,omitempty"`
+}
+
+// ForwardCheckPortParams は forward.checkPort リクエストのパラメータ。
+type ForwardCheckPortParams struct {
+	Port int `json:"port"`
+}
+
+// ForwardCheckPortResult は forward.checkPort リクエストの結果。
+// 使用中の場合、Rule は使用中のルール名（他のプロセスの場合は空）、Suggestion は空いているポート（見つからない場合は 0）。
+type ForwardCheckPortResult struct {
+	Port       int    `json:"port"`
+	Available  bool   `json:"available"`
+	Rule       string `json:"rule,omitempty"`
+	Suggestion int    `json:"suggestion,omitempty"`
+}
+
 // ForwardStopAllResult は forward.stopAll リクエストの結果。
 type ForwardStopAllResult struct {
 	Stopped int `json:"stopped"`
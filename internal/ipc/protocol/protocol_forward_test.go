@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTTL(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"2h", 2 * time.Hour, false},
+		{"90m", 90 * time.Minute, false},
+		{"0s", 0, true},
+		{"-1h", 0, true},
+		{"tomorrow", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseTTL(tt.in)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("ParseTTL(%q) = %v, %v; want %v, wantErr %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
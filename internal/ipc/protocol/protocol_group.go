@@ -0,0 +1,31 @@
+package protocol
+
+// --- フォワードグループ ---
+
+// ForwardListGroupsResult は forward.listGroups リクエストの結果。
+type ForwardListGroupsResult struct {
+	Groups []ForwardGroupInfo `json:"groups"`
+}
+
+// ForwardGroupInfo はフォワードグループの情報を表す。Rules はグループに含まれるルール名の並び。
+type ForwardGroupInfo struct {
+	Name  string   `json:"name"`
+	Rules []string `json:"rules"`
+}
+
+// ForwardGroupParams は forward.startGroup / forward.stopGroup リクエストのパラメータ。
+type ForwardGroupParams struct {
+	Name string `json:"name"`
+}
+
+// ForwardStartGroupResult は forward.startGroup リクエストの結果。Started はこのリクエストで開始したルール名。
+type ForwardStartGroupResult struct {
+	Name    string   `json:"name"`
+	Started []string `json:"started"`
+}
+
+// ForwardStopGroupResult は forward.stopGroup リクエストの結果。Stopped はこのリクエストで停止したルール名。
+type ForwardStopGroupResult struct {
+	Name    string   `json:"name"`
+	Stopped []string `json:"stopped"`
+}
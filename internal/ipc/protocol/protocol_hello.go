@@ -0,0 +1,55 @@
+package protocol
+
+import "slices"
+
+// ProtocolVersion は IPC プロトコルのバージョン。既存のメソッドのパラメータや結果を互換性のない形に変えたときに上げる。
+// メソッドの追加など後方互換な拡張は、バージョンを上げずに機能フラグ（Capability*）で示す。
+const ProtocolVersion = 2
+
+// MinProtocolVersion は相手（デーモンまたはクライアント）に求める最も古いプロトコルバージョン。
+const MinProtocolVersion = 1
+
+// LegacyProtocolVersion は daemon.hello に対応していないデーモンのプロトコルバージョンとみなす値。
+const LegacyProtocolVersion = 1
+
+// デーモンの機能フラグ。daemon.hello の結果の capabilities に含まれる。
+const (
+	// CapabilityBatch は JSON-RPC のバッチを受け付けることを示す。
+	CapabilityBatch = "batch"
+	// CapabilityCancel は rpc.cancel で実行中のリクエストを取り消せることを示す。
+	CapabilityCancel = "rpc.cancel"
+	// CapabilityEventReplay は events.subscribe の since_id で取りこぼしたイベントを再送できることを示す。
+	CapabilityEventReplay = "events.replay"
+	// CapabilityEventFilter は events.subscribe の hosts・rules でイベントを絞り込めることを示す。
+	CapabilityEventFilter = "events.filter"
+)
+
+// Capabilities はこのバージョンのデーモンが対応する機能フラグを返す。
+func Capabilities() []string {
+	return []string{CapabilityBatch, CapabilityCancel, CapabilityEventReplay, CapabilityEventFilter}
+}
+
+// Compatible は相手のプロトコルバージョン version と、相手が求める最も古いバージョン minVersion が
+// このバージョンと互換かを返す。
+func Compatible(version, minVersion int) bool {
+	return version >= MinProtocolVersion && ProtocolVersion >= minVersion
+}
+
+// DaemonHelloParams は daemon.hello リクエストのパラメータ。
+type DaemonHelloParams struct {
+	ProtocolVersion int    `json:"protocol_version"`
+	ClientVersion   string `json:"client_version,omitempty"`
+}
+
+// DaemonHelloResult は daemon.hello リクエストの結果。
+type DaemonHelloResult struct {
+	ProtocolVersion    int      `json:"protocol_version"`
+	MinProtocolVersion int      `json:"min_protocol_version"`
+	ServerVersion      string   `json:"server_version"`
+	Capabilities       []string `json:"capabilities"`
+}
+
+// HasCapability はデーモンが機能フラグ capability に対応するかを返す。
+func (r DaemonHelloResult) HasCapability(capability string) bool {
+	return slices.Contains(r.Capabilities, capability)
+}
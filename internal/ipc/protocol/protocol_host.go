@@ -1,13 +1,37 @@
 package protocol
 
+import "github.com/ousiassllc/moleport/internal/ipc/protocol/listopt"
+
 // --- ホスト管理 ---
 
 // HostListParams は host.list リクエストのパラメータ。
-type HostListParams struct{}
+type HostListParams struct {
+	listopt.Options
+}
 
 // HostListResult は host.list リクエストの結果。
 type HostListResult struct {
 	Hosts []HostInfo `json:"hosts"`
+	// Total は state で絞り込んだ後、ページングする前のホスト数。
+	Total int `json:"total"`
+	// Revision はこの一覧の時点のリビジョン。host.changes の since に渡すと、以降の差分を取得できる。
+	Revision uint64 `json:"revision"`
+}
+
+// HostChangesParams は host.changes リクエストのパラメータ。
+type HostChangesParams struct {
+	// Since は前回取得したリビジョン（host.list・host.changes の revision）。
+	Since uint64 `json:"since"`
+}
+
+// HostChangesResult は host.changes リクエストの結果。
+// Reset が true の場合は差分を返せないため、Hosts に全件を返す。クライアントは手元の一覧を置き換える。
+type HostChangesResult struct {
+	Revision uint64     `json:"revision"`
+	Reset    bool       `json:"reset,omitempty"`
+	Hosts    []HostInfo `json:"hosts"`
+	// Removed は since の後に削除されたホスト名。
+	Removed []string `json:"removed"`
 }
 
 // HostInfo は SSH ホストの情報を表す。
@@ -18,6 +42,32 @@ type HostInfo struct {
 	User               string `json:"user"`
 	State              string `json:"state"`
 	ActiveForwardCount int    `json:"active_forward_count"`
+	Notes              string `json:"notes,omitempty"`
+	AuthHint           string `json:"auth_hint,omitempty"`
+	JumpDescription    string `json:"jump_description,omitempty"`
+	// Tags はホストを分類するタグ（"env:prod" 等）。
+	Tags []string `json:"tags,omitempty"`
+	// JumpChain は ProxyJump を展開した踏み台の並び（接続順）。
+	JumpChain []string `json:"jump_chain,omitempty"`
+	// Health はバックグラウンドの疎通確認の結果（reachable / unreachable）。未確認の場合は省略される。
+	Health string `json:"health,omitempty"`
+	// Source はホストの定義元（ssh_config / config）。
+	Source string `json:"source,omitempty"`
+}
+
+// HostStatsParams は host.stats リクエストのパラメータ。
+type HostStatsParams struct{}
+
+// HostStatsResult は host.stats リクエストの結果。接続中のホストのみを含む。
+type HostStatsResult struct {
+	Hosts []HostStats `json:"hosts"`
+}
+
+// HostStats は接続中ホストの接続品質を表す。
+type HostStats struct {
+	Name string `json:"name"`
+	// LatencyMs は直近の keepalive の往復時間（ミリ秒）。まだ計測していない場合は省略される。
+	LatencyMs float64 `json:"latency_ms,omitempty"`
 }
 
 // HostReloadParams は host.reload リクエストのパラメータ。
@@ -28,4 +78,62 @@ type HostReloadResult struct {
 	Total   int      `json:"total"`
 	Added   []string `json:"added"`
 	Removed []string `json:"removed"`
+	// Importable は ssh_config に定義され、まだ MolePort ルールになっていないフォワード。
+	Importable []ForwardInfo `json:"importable,omitempty"`
+}
+
+// HostUpdateParams は host.update リクエストのパラメータ（部分更新）。
+// 各フィールドはポインタ型で、nil なら変更なし、空文字列なら削除を意味する。
+type HostUpdateParams struct {
+	Name            string  `json:"name"`
+	Notes           *string `json:"notes,omitempty"`
+	AuthHint        *string `json:"auth_hint,omitempty"`
+	JumpDescription *string `json:"jump_description,omitempty"`
+	// Tags は nil なら変更なし、空の配列ならタグをすべて削除する。
+	Tags *[]string `json:"tags,omitempty"`
+}
+
+// HostUpdateResult は host.update リクエストの結果。
+type HostUpdateResult struct {
+	Host HostInfo `json:"host"`
+}
+
+// HostImportForwardsParams は host.importForwards リクエストのパラメータ。
+// ImportKeys が空の場合は対象ホスト（Host が空なら全ホスト）の候補すべてを対象にする。
+type HostImportForwardsParams struct {
+	Host       string   `json:"host,omitempty"`
+	ImportKeys []string `json:"import_keys,omitempty"`
+	DryRun     bool     `json:"dry_run,omitempty"`
+	Ignore     bool     `json:"ignore,omitempty"`
+}
+
+// HostImportForwardsResult は host.importForwards リクエストの結果。
+type HostImportForwardsResult struct {
+	Imported []ForwardInfo `json:"imported"`
+	Ignored  []string      `json:"ignored,omitempty"`
+}
+
+// HostAddParams は host.add リクエストのパラメータ。config.yaml の host_definitions にホストを追加する。
+type HostAddParams struct {
+	Name         string `json:"name"`
+	HostName     string `json:"hostname"`
+	Port         int    `json:"port,omitempty"`
+	User         string `json:"user,omitempty"`
+	IdentityFile string `json:"identity_file,omitempty"`
+	ProxyJump    string `json:"proxy_jump,omitempty"`
+}
+
+// HostAddResult は host.add リクエストの結果。
+type HostAddResult struct {
+	Host HostInfo `json:"host"`
+}
+
+// HostDeleteParams は host.delete リクエストのパラメータ。config.yaml で定義したホストのみ削除できる。
+type HostDeleteParams struct {
+	Name string `json:"name"`
+}
+
+// HostDeleteResult は host.delete リクエストの結果。
+type HostDeleteResult struct {
+	OK bool `json:"ok"`
 }
@@ -0,0 +1,37 @@
+package protocol
+
+import "time"
+
+// --- ログの配信 ---
+
+// LogsSubscribeParams は logs.subscribe リクエストのパラメータ。
+type LogsSubscribeParams struct {
+	// Level は配信する最低のログレベル（debug / info / warn / error）。省略時は info。
+	Level string `json:"level,omitempty"`
+	// Tail は結果に含める直近のログの件数。0 の場合は含めない。
+	Tail int `json:"tail,omitempty"`
+}
+
+// LogsSubscribeResult は logs.subscribe リクエストの結果。
+type LogsSubscribeResult struct {
+	SubscriptionID string      `json:"subscription_id"`
+	Records        []LogRecord `json:"records"` // Level 以上の直近のログ（古い順）
+}
+
+// LogsUnsubscribeParams は logs.unsubscribe リクエストのパラメータ。
+type LogsUnsubscribeParams struct {
+	SubscriptionID string `json:"subscription_id"`
+}
+
+// LogsUnsubscribeResult は logs.unsubscribe リクエストの結果。
+type LogsUnsubscribeResult struct {
+	OK bool `json:"ok"`
+}
+
+// LogRecord はデーモンのログ 1 件を表す。event.log 通知のパラメータとしても使う。
+type LogRecord struct {
+	Time    time.Time         `json:"time"`
+	Level   string            `json:"level"` // DEBUG / INFO / WARN / ERROR
+	Message string            `json:"message"`
+	Attrs   map[string]string `json:"attrs,omitempty"`
+}
@@ -0,0 +1,36 @@
+package protocol
+
+import "encoding/json"
+
+// DefaultProfile は profile パラメータ省略時に使われるプロファイル名。
+const DefaultProfile = "default"
+
+// ProfileParam は全リクエストの params に指定できる共通フィールド。
+// 指定されたプロファイルのホスト・ルール・状態に対して操作する。
+type ProfileParam struct {
+	Profile string `json:"profile,omitempty"`
+}
+
+// ProfileOf は params の profile フィールドを返す。未指定または params がオブジェクトでない場合は DefaultProfile を返す。
+func ProfileOf(params json.RawMessage) string {
+	var p ProfileParam
+	if len(params) == 0 || json.Unmarshal(params, &p) != nil || p.Profile == "" {
+		return DefaultProfile
+	}
+	return p.Profile
+}
+
+// ProfileListParams は profile.list リクエストのパラメータ。
+type ProfileListParams struct{}
+
+// ProfileListResult は profile.list リクエストの結果。
+type ProfileListResult struct {
+	Profiles []ProfileInfo `json:"profiles"`
+}
+
+// ProfileInfo はプロファイルの情報を表す。
+type ProfileInfo struct {
+	Name string `json:"name"`
+	// Loaded はデーモン内でプロファイルの SSH・フォワード管理が起動済みかを表す。
+	Loaded bool `json:"loaded"`
+}
@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestProfileOf(t *testing.T) {
+	tests := []struct {
+		name   string
+		params string
+		want   string
+	}{
+		{"no params", "", DefaultProfile},
+		{"no profile field", `{"name":"web"}`, DefaultProfile},
+		{"empty profile", `{"profile":""}`, DefaultProfile},
+		{"named profile", `{"name":"web","profile":"work"}`, "work"},
+		{"array params", `["work"]`, DefaultProfile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProfileOf(json.RawMessage(tt.params)); got != tt.want {
+				t.Errorf("ProfileOf(%s) = %q, want %q", tt.params, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package protocol
+
+import "github.com/ousiassllc/moleport/internal/ipc/protocol/rpcid"
+
+// RPCCancelParams は rpc.cancel リクエストのパラメータ。ID は取り消す実行中のリクエストの ID。
+type RPCCancelParams struct {
+	ID rpcid.ID `json:"id"`
+}
+
+// RPCCancelResult は rpc.cancel リクエストの結果。Cancelled は ID のリクエストが実行中で、取り消しを要求できたか。
+type RPCCancelResult struct {
+	Cancelled bool `json:"cancelled"`
+}
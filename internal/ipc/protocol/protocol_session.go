@@ -1,31 +1,73 @@
 package protocol
 
+import "github.com/ousiassllc/moleport/internal/ipc/protocol/listopt"
+
 // --- セッション情報 ---
 
 // SessionListParams は session.list リクエストのパラメータ。
-type SessionListParams struct{}
+type SessionListParams struct {
+	listopt.Options
+}
 
 // SessionListResult は session.list リクエストの結果。
 type SessionListResult struct {
 	Sessions []SessionInfo `json:"sessions"`
+	// Total は state で絞り込んだ後、ページングする前のセッション数。
+	Total int `json:"total"`
+	// Revision はこの一覧の時点のリビジョン。session.changes の since に渡すと、以降の差分を取得できる。
+	Revision uint64 `json:"revision"`
+}
+
+// SessionChangesParams は session.changes リクエストのパラメータ。
+type SessionChangesParams struct {
+	// Since は前回取得したリビジョン（session.list・session.changes の revision）。
+	Since uint64 `json:"since"`
+}
+
+// SessionChangesResult は session.changes リクエストの結果。
+// Reset が true の場合は差分を返せないため、Sessions に全件を返す。クライアントは手元の一覧を置き換える。
+type SessionChangesResult struct {
+	Revision uint64        `json:"revision"`
+	Reset    bool          `json:"reset,omitempty"`
+	Sessions []SessionInfo `json:"sessions"`
+	// Removed は since の後に削除されたセッションのルール名。
+	Removed []string `json:"removed"`
 }
 
 // SessionInfo はポートフォワーディングセッションの情報を表す。
 type SessionInfo struct {
-	ID             string `json:"id"`
-	Name           string `json:"name"`
-	Host           string `json:"host"`
-	Type           string `json:"type"`
-	LocalPort      int    `json:"local_port"`
-	RemoteHost     string `json:"remote_host,omitempty"`
-	RemotePort     int    `json:"remote_port,omitempty"`
-	RemoteBindAddr string `json:"remote_bind_addr,omitempty"`
-	Status         string `json:"status"`
-	ConnectedAt    string `json:"connected_at,omitempty"`
-	BytesSent      int64  `json:"bytes_sent"`
-	BytesReceived  int64  `json:"bytes_received"`
-	ReconnectCount int    `json:"reconnect_count"`
-	LastError      string `json:"last_error,omitempty"`
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Host            string     `json:"host"`
+	Type            string     `json:"type"`
+	LocalPort       int        `json:"local_port"`
+	BoundPort       int        `json:"bound_port,omitempty"` // Listen 中のローカルポート（local_port が 0 の場合は割り当てられたポート）
+	RemoteHost      string     `json:"remote_host,omitempty"`
+	RemotePort      int        `json:"remote_port,omitempty"`
+	RemoteBindAddr  string     `json:"remote_bind_addr,omitempty"`
+	LocalBindAddr   string     `json:"local_bind_addr,omitempty"`
+	Status          string     `json:"status"`
+	ConnectedAt     string     `json:"connected_at,omitempty"`
+	BytesSent       int64      `json:"bytes_sent"`
+	BytesReceived   int64      `json:"bytes_received"`
+	ReconnectCount  int        `json:"reconnect_count"`
+	LastError       string     `json:"last_error,omitempty"`
+	ExpiresAt       string     `json:"expires_at,omitempty"`        // TTL による自動停止の予定時刻（RFC 3339）
+	MaxUploadKbps   int        `json:"max_upload_kbps,omitempty"`   // 送信の帯域上限（kbps）。0 は無制限
+	MaxDownloadKbps int        `json:"max_download_kbps,omitempty"` // 受信の帯域上限（kbps）。0 は無制限
+	SendRate        float64    `json:"send_rate"`                   // 直近の送信レート（バイト/秒）
+	ReceiveRate     float64    `json:"receive_rate"`                // 直近の受信レート（バイト/秒）
+	RateHistory     []float64  `json:"rate_history,omitempty"`      // 送受信合計レートの推移（1 秒ごと、古い順）
+	ActiveConns     int        `json:"active_conns"`                // 転送中の接続数
+	Connections     []ConnInfo `json:"connections,omitempty"`       // 転送中の接続（開始順）
+	StoppedReason   string     `json:"stopped_reason,omitempty"`    // 停止・エラーの理由（user / ssh_lost / ttl / daemon_shutdown / error）
+	StoppedAt       string     `json:"stopped_at,omitempty"`        // 停止・エラーになった時刻（RFC 3339）
+}
+
+// ConnInfo は転送中の 1 つの接続を表す。
+type ConnInfo struct {
+	Peer  string `json:"peer,omitempty"` // 接続元アドレス
+	Since string `json:"since"`          // 接続の開始時刻（RFC 3339）
 }
 
 // SessionGetParams は session.get リクエストのパラメータ。
@@ -4,14 +4,15 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcid"
 )
 
 func TestRequest_JSONRoundtrip(t *testing.T) {
-	id := 1
 	params := json.RawMessage(`{"host":"example"}`)
 	req := Request{
 		JSONRPC: JSONRPCVersion,
-		ID:      &id,
+		ID:      rpcid.Number(1),
 		Method:  "ssh.connect",
 		Params:  params,
 	}
@@ -29,7 +30,7 @@ func TestRequest_JSONRoundtrip(t *testing.T) {
 	if got.JSONRPC != req.JSONRPC {
 		t.Errorf("JSONRPC = %q, want %q", got.JSONRPC, req.JSONRPC)
 	}
-	if got.ID == nil || *got.ID != *req.ID {
+	if got.ID != req.ID {
 		t.Errorf("ID = %v, want %v", got.ID, req.ID)
 	}
 	if got.Method != req.Method {
@@ -41,11 +42,10 @@ func TestRequest_JSONRoundtrip(t *testing.T) {
 }
 
 func TestResponse_JSONRoundtrip(t *testing.T) {
-	id := 42
 	result := json.RawMessage(`{"host":"example","status":"connected"}`)
 	resp := Response{
 		JSONRPC: JSONRPCVersion,
-		ID:      &id,
+		ID:      rpcid.String("req-42"),
 		Result:  result,
 	}
 
@@ -62,7 +62,7 @@ func TestResponse_JSONRoundtrip(t *testing.T) {
 	if got.JSONRPC != resp.JSONRPC {
 		t.Errorf("JSONRPC = %q, want %q", got.JSONRPC, resp.JSONRPC)
 	}
-	if got.ID == nil || *got.ID != *resp.ID {
+	if got.ID != resp.ID {
 		t.Errorf("ID = %v, want %v", got.ID, resp.ID)
 	}
 	if string(got.Result) != string(resp.Result) {
@@ -73,14 +73,14 @@ func TestResponse_JSONRoundtrip(t *testing.T) {
 	}
 }
 
-func TestResponse_NilID_MarshalAsNull(t *testing.T) {
-	resp := NewErrorResponse(nil, ParseError, "parse error")
+func TestResponse_ZeroID_MarshalAsNull(t *testing.T) {
+	resp := NewErrorResponse(rpcid.ID{}, ParseError, "parse error")
 	data, err := json.Marshal(resp)
 	if err != nil {
 		t.Fatalf("Marshal Response: %v", err)
 	}
 	if !strings.Contains(string(data), `"id":null`) {
-		t.Errorf("Response with nil ID should serialize as null, got: %s", data)
+		t.Errorf("Response with zero ID should serialize as null, got: %s", data)
 	}
 }
 
@@ -129,7 +129,7 @@ func TestNotification_OmitsIDField(t *testing.T) {
 	}
 }
 
-func TestRequest_NilID_OmitsIDField(t *testing.T) {
+func TestRequest_ZeroID_OmitsIDField(t *testing.T) {
 	req := Request{
 		JSONRPC: JSONRPCVersion,
 		Method:  EventSSH,
@@ -141,7 +141,7 @@ func TestRequest_NilID_OmitsIDField(t *testing.T) {
 	}
 
 	if strings.Contains(string(data), `"id"`) {
-		t.Errorf("Request with nil ID should not contain 'id' field, got: %s", data)
+		t.Errorf("Request with zero ID should not contain 'id' field, got: %s", data)
 	}
 }
 
@@ -157,9 +157,8 @@ func TestRPCError_Error(t *testing.T) {
 }
 
 func TestNewResponse(t *testing.T) {
-	id := 1
 	result := SSHConnectResult{Host: "prod", Status: "connected"}
-	resp, err := NewResponse(&id, result)
+	resp, err := NewResponse(rpcid.Number(1), result)
 	if err != nil {
 		t.Fatalf("NewResponse: %v", err)
 	}
@@ -167,7 +166,7 @@ func TestNewResponse(t *testing.T) {
 	if resp.JSONRPC != JSONRPCVersion {
 		t.Errorf("JSONRPC = %q, want %q", resp.JSONRPC, JSONRPCVersion)
 	}
-	if resp.ID == nil || *resp.ID != 1 {
+	if n, ok := resp.ID.Int(); !ok || n != 1 {
 		t.Errorf("ID = %v, want 1", resp.ID)
 	}
 	if resp.Error != nil {
@@ -184,13 +183,12 @@ func TestNewResponse(t *testing.T) {
 }
 
 func TestNewErrorResponse(t *testing.T) {
-	id := 5
-	resp := NewErrorResponse(&id, InternalError, "something went wrong")
+	resp := NewErrorResponse(rpcid.Number(5), InternalError, "something went wrong")
 
 	if resp.JSONRPC != JSONRPCVersion {
 		t.Errorf("JSONRPC = %q, want %q", resp.JSONRPC, JSONRPCVersion)
 	}
-	if resp.ID == nil || *resp.ID != 5 {
+	if n, ok := resp.ID.Int(); !ok || n != 5 {
 		t.Errorf("ID = %v, want 5", resp.ID)
 	}
 	if resp.Result != nil {
@@ -0,0 +1,131 @@
+package rpcerr
+
+import (
+	"errors"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ruleconflict"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// RuleNameErrorData は protocol.InvalidRuleName エラーの data。Suggestion は命名規則に沿った代替名。
+type RuleNameErrorData struct {
+	Name       string `json:"name"`
+	Reason     string `json:"reason"`
+	Suggestion string `json:"suggestion"`
+}
+
+// PortConflictData は protocol.PortConflict エラーの data。
+// Rule はポートを使用中のルール名（他のプロセスの場合は空）、Suggestion は空いているポート（見つからない場合は 0）。
+type PortConflictData struct {
+	Port       int    `json:"port"`
+	Rule       string `json:"rule,omitempty"`
+	Suggestion int    `json:"suggestion,omitempty"`
+}
+
+// DuplicateRuleData は同じ転送先のルールが既に存在する場合の protocol.RuleAlreadyExists エラーの data。
+// Rule は既存のルール名、Target は転送先。
+type DuplicateRuleData struct {
+	Rule   string `json:"rule"`
+	Target string `json:"target"`
+}
+
+// From はコアエラーを protocol.RPCError に変換する。
+// 構造化エラー型とセンチネルエラー（core.ErrPortInUse 等）に基づいてアプリケーション固有のエラーコードを割り当て、
+// どれにも当たらない場合は defaultCode を使う。エラーメッセージの文字列からは判定しない。
+func From(err error, defaultCode int) *protocol.RPCError {
+	msg := err.Error()
+
+	// センチネルエラー
+	switch {
+	case errors.Is(err, core.ErrCredentialTimeout):
+		return &protocol.RPCError{Code: protocol.CredentialTimeout, Message: msg}
+	case errors.Is(err, core.ErrCredentialCancelled):
+		return &protocol.RPCError{Code: protocol.CredentialCancelled, Message: msg}
+	}
+
+	// 構造化エラー型
+	var notFound *core.NotFoundError
+	if errors.As(err, &notFound) {
+		switch notFound.Resource {
+		case "host":
+			return &protocol.RPCError{Code: protocol.HostNotFound, Message: msg}
+		case "rule":
+			return &protocol.RPCError{Code: protocol.RuleNotFound, Message: msg}
+		case "group":
+			return &protocol.RPCError{Code: protocol.GroupNotFound, Message: msg}
+		}
+	}
+
+	var alreadyExists *core.AlreadyExistsError
+	if errors.As(err, &alreadyExists) {
+		return &protocol.RPCError{Code: protocol.RuleAlreadyExists, Message: msg}
+	}
+
+	var duplicate *ruleconflict.DuplicateError
+	if errors.As(err, &duplicate) {
+		return &protocol.RPCError{Code: protocol.RuleAlreadyExists, Message: msg, Data: DuplicateRuleData{Rule: duplicate.Rule, Target: duplicate.Target}}
+	}
+
+	var invalidName *core.InvalidRuleNameError
+	if errors.As(err, &invalidName) {
+		return &protocol.RPCError{Code: protocol.InvalidRuleName, Message: msg, Data: RuleNameErrorData{
+			Name:       invalidName.Name,
+			Reason:     invalidName.Reason,
+			Suggestion: invalidName.Suggestion,
+		}}
+	}
+
+	var portConflict *core.PortConflictError
+	if errors.As(err, &portConflict) {
+		return &protocol.RPCError{Code: protocol.PortConflict, Message: msg, Data: PortConflictData{
+			Port:       portConflict.Port,
+			Rule:       portConflict.Rule,
+			Suggestion: portConflict.Suggestion,
+		}}
+	}
+
+	var invalidConfig *core.InvalidConfigError
+	if errors.As(err, &invalidConfig) {
+		return &protocol.RPCError{Code: protocol.InvalidConfig, Message: msg, Data: ConfigIssues(err)}
+	}
+
+	var alreadyActive *core.AlreadyActiveError
+	if errors.As(err, &alreadyActive) {
+		return &protocol.RPCError{Code: protocol.AlreadyConnected, Message: msg}
+	}
+
+	var notConnected *core.NotConnectedError
+	if errors.As(err, &notConnected) {
+		return &protocol.RPCError{Code: protocol.NotConnected, Message: msg}
+	}
+
+	var authRequired *core.AuthRequiredError
+	if errors.As(err, &authRequired) {
+		return &protocol.RPCError{Code: protocol.AuthenticationFailed, Message: msg}
+	}
+
+	// 詳細を持たないエラーの種類
+	switch {
+	case errors.Is(err, core.ErrPortInUse):
+		return &protocol.RPCError{Code: protocol.PortConflict, Message: msg}
+	case errors.Is(err, core.ErrAuthFailed):
+		return &protocol.RPCError{Code: protocol.AuthenticationFailed, Message: msg}
+	}
+
+	return &protocol.RPCError{Code: defaultCode, Message: msg}
+}
+
+// ConfigIssues は設定の読み込み・検証のエラーを設定項目ごとの問題に変換する。
+// *core.InvalidConfigError 以外のエラー（YAML の構文エラー等）は Field が空の問題 1 つとして返す。
+func ConfigIssues(err error) []protocol.ConfigIssue {
+	var invalid *core.InvalidConfigError
+	if !errors.As(err, &invalid) {
+		return []protocol.ConfigIssue{{Message: err.Error()}}
+	}
+	issues := make([]protocol.ConfigIssue, len(invalid.Issues))
+	for i, issue := range invalid.Issues {
+		issues[i] = protocol.ConfigIssue{Field: issue.Field, Message: issue.Message}
+	}
+	return issues
+}
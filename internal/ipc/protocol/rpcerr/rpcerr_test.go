@@ -0,0 +1,201 @@
+package rpcerr
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ruleconflict"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// wrapError はエラーを別のメッセージでラップする（errors.As/Is テスト用）。
+func wrapError(msg string, err error) error {
+	return fmt.Errorf("%s: %w", msg, err)
+}
+
+func TestFrom(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		defaultCode int
+		wantCode    int
+		wantMsg     string
+	}{
+		// 構造化エラー型
+		{
+			name:        "host not found",
+			err:         &core.NotFoundError{Resource: "host", Name: "prod"},
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.HostNotFound,
+			wantMsg:     `host "prod" not found`,
+		},
+		{
+			name:        "rule not found",
+			err:         &core.NotFoundError{Resource: "rule", Name: "web"},
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.RuleNotFound,
+			wantMsg:     `rule "web" not found`,
+		},
+		{
+			name:        "group not found",
+			err:         &core.NotFoundError{Resource: "group", Name: "staging"},
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.GroupNotFound,
+			wantMsg:     `group "staging" not found`,
+		},
+		{
+			name:        "already exists",
+			err:         &core.AlreadyExistsError{Resource: "rule", Name: "web"},
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.RuleAlreadyExists,
+			wantMsg:     `rule "web" already exists`,
+		},
+		{
+			name:        "already active",
+			err:         &core.AlreadyActiveError{Name: "web"},
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.AlreadyConnected,
+			wantMsg:     `"web" is already active`,
+		},
+		{
+			name:        "not connected",
+			err:         &core.NotConnectedError{HostName: "prod"},
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.NotConnected,
+			wantMsg:     `host "prod" is not connected`,
+		},
+		{
+			name:        "auth required",
+			err:         &core.AuthRequiredError{HostName: "prod", Err: fmt.Errorf("ssh: unable to authenticate")},
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.AuthenticationFailed,
+			wantMsg:     "authentication required for prod: ssh: unable to authenticate",
+		},
+		// センチネルエラー
+		{
+			name:        "credential timeout",
+			err:         core.ErrCredentialTimeout,
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.CredentialTimeout,
+			wantMsg:     "credential timeout",
+		},
+		{
+			name:        "credential cancelled",
+			err:         core.ErrCredentialCancelled,
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.CredentialCancelled,
+			wantMsg:     "credential cancelled",
+		},
+		// ラップされた構造化エラー（errors.As で検出可能）
+		{
+			name:        "wrapped host not found",
+			err:         wrapError("operation failed", &core.NotFoundError{Resource: "host", Name: "staging"}),
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.HostNotFound,
+			wantMsg:     `operation failed: host "staging" not found`,
+		},
+		{
+			name:        "wrapped credential timeout",
+			err:         wrapError("connect failed", core.ErrCredentialTimeout),
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.CredentialTimeout,
+			wantMsg:     "connect failed: credential timeout",
+		},
+		// センチネルエラー（詳細を持たないエラーの種類）
+		{
+			name:        "port in use",
+			err:         fmt.Errorf("failed to listen on 127.0.0.1:8080: %w", &core.PortConflictError{Port: 8080}),
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.PortConflict,
+			wantMsg:     "failed to listen on 127.0.0.1:8080: port 8080 is already in use",
+		},
+		{
+			name:        "authentication failed",
+			err:         fmt.Errorf("failed to connect to prod: %w: %w", core.ErrAuthFailed, errors.New("ssh: unable to authenticate")),
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.AuthenticationFailed,
+			wantMsg:     "failed to connect to prod: authentication failed: ssh: unable to authenticate",
+		},
+		{
+			name:        "error strings are not guessed",
+			err:         fmt.Errorf("listen tcp :8080: bind: address already in use"),
+			defaultCode: protocol.InternalError,
+			wantCode:    protocol.InternalError,
+			wantMsg:     "listen tcp :8080: bind: address already in use",
+		},
+		// デフォルトコード
+		{
+			name:        "generic error uses defaultCode",
+			err:         fmt.Errorf("something unexpected happened"),
+			defaultCode: protocol.InvalidParams,
+			wantCode:    protocol.InvalidParams,
+			wantMsg:     "something unexpected happened",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := From(tt.err, tt.defaultCode)
+			if got.Code != tt.wantCode {
+				t.Errorf("Code = %d, want %d", got.Code, tt.wantCode)
+			}
+			if got.Message != tt.wantMsg {
+				t.Errorf("Message = %q, want %q", got.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestFrom_InvalidRuleNameCarriesSuggestion(t *testing.T) {
+	err := fmt.Errorf("add rule: %w", &core.InvalidRuleNameError{Name: "my rule", Reason: "bad", Suggestion: "my-rule"})
+	got := From(err, protocol.InternalError)
+	if got.Code != protocol.InvalidRuleName {
+		t.Errorf("Code = %d, want %d", got.Code, protocol.InvalidRuleName)
+	}
+	data, ok := got.Data.(RuleNameErrorData)
+	if !ok || data.Name != "my rule" || data.Suggestion != "my-rule" {
+		t.Errorf("Data = %#v, want suggestion my-rule", got.Data)
+	}
+}
+
+func TestFrom_PortConflictCarriesSuggestion(t *testing.T) {
+	err := fmt.Errorf("start forward: %w", &core.PortConflictError{Port: 8080, Rule: "web", Suggestion: 8081})
+	got := From(err, protocol.InternalError)
+	if got.Code != protocol.PortConflict {
+		t.Errorf("Code = %d, want %d", got.Code, protocol.PortConflict)
+	}
+	want := PortConflictData{Port: 8080, Rule: "web", Suggestion: 8081}
+	if data, ok := got.Data.(PortConflictData); !ok || data != want {
+		t.Errorf("Data = %#v, want %#v", got.Data, want)
+	}
+}
+
+func TestFrom_DuplicateRuleCarriesExistingRule(t *testing.T) {
+	err := fmt.Errorf("add rule: %w", &ruleconflict.DuplicateError{Rule: "web", Target: "127.0.0.1:80"})
+	got := From(err, protocol.InternalError)
+	if got.Code != protocol.RuleAlreadyExists {
+		t.Errorf("Code = %d, want %d", got.Code, protocol.RuleAlreadyExists)
+	}
+	want := DuplicateRuleData{Rule: "web", Target: "127.0.0.1:80"}
+	if data, ok := got.Data.(DuplicateRuleData); !ok || data != want {
+		t.Errorf("Data = %#v, want %#v", got.Data, want)
+	}
+}
+
+func TestFrom_InvalidConfigCarriesIssues(t *testing.T) {
+	err := &core.InvalidConfigError{Issues: []core.ConfigIssue{{Field: "log.level", Message: "invalid value"}}}
+	got := From(fmt.Errorf("update config: %w", err), protocol.InternalError)
+	if got.Code != protocol.InvalidConfig {
+		t.Errorf("Code = %d, want %d", got.Code, protocol.InvalidConfig)
+	}
+	issues, ok := got.Data.([]protocol.ConfigIssue)
+	if !ok || len(issues) != 1 || issues[0].Field != "log.level" {
+		t.Errorf("Data = %#v, want the log.level issue", got.Data)
+	}
+
+	if issues := ConfigIssues(fmt.Errorf("yaml: line 1")); len(issues) != 1 || issues[0].Field != "" {
+		t.Errorf("ConfigIssues(parse error) = %+v, want one issue without field", issues)
+	}
+}
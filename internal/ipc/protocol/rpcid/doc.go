@@ -0,0 +1,2 @@
+// Package rpcid は JSON-RPC 2.0 のリクエスト ID を提供する。
+package rpcid
@@ -0,0 +1,83 @@
+package rpcid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+// errInvalidID は ID が文字列・数値・null 以外の場合のエラー。
+var errInvalidID = errors.New("id must be a string, number, or null")
+
+// ID は JSON-RPC 2.0 のリクエスト ID を表す。
+// 仕様上 ID は文字列・数値・null のいずれかのため、受信した JSON 表現をそのまま保持し、
+// レスポンスでは同じ表現を返す。ゼロ値は「ID なし」（通知）を表し、null としてマーシャルされる。
+type ID struct {
+	raw string
+}
+
+// Number は数値 ID を生成する。
+func Number(n int64) ID {
+	return ID{raw: strconv.FormatInt(n, 10)}
+}
+
+// String は文字列 ID を生成する。
+func String(s string) ID {
+	data, _ := json.Marshal(s) //nolint:errcheck // string のマーシャルは失敗しない
+	return ID{raw: string(data)}
+}
+
+// IsZero は ID が指定されていない（通知である）かを返す。omitzero から利用される。
+func (id ID) IsZero() bool {
+	return id.raw == ""
+}
+
+// IsNull は ID が明示的に null で指定されたかを返す。
+func (id ID) IsNull() bool {
+	return id.raw == "null"
+}
+
+// Int は数値 ID を整数として返す。数値以外の ID の場合は false を返す。
+func (id ID) Int() (int64, bool) {
+	n, err := strconv.ParseInt(id.raw, 10, 64)
+	return n, err == nil
+}
+
+// String は ID の JSON 表現を返す。
+func (id ID) String() string {
+	if id.raw == "" {
+		return "null"
+	}
+	return id.raw
+}
+
+// MarshalJSON は ID を受信時と同じ JSON 表現でシリアライズする。ゼロ値は null になる。
+func (id ID) MarshalJSON() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
+// UnmarshalJSON は文字列・数値・null の ID をデシリアライズする。
+func (id *ID) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return errInvalidID
+	}
+	switch c := data[0]; {
+	case c == '"':
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	case c == '-' || (c >= '0' && c <= '9'):
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+	case string(data) == "null":
+	default:
+		return errInvalidID
+	}
+	id.raw = string(data)
+	return nil
+}
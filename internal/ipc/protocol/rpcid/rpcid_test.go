@@ -0,0 +1,71 @@
+package rpcid
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestID_UnmarshalRoundtrip(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		null bool
+	}{
+		{"number", `7`, false},
+		{"negative number", `-3`, false},
+		{"large number", `9007199254740993`, false},
+		{"string", `"abc"`, false},
+		{"escaped string", `"a\"b"`, false},
+		{"null", `null`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var id ID
+			if err := json.Unmarshal([]byte(tt.in), &id); err != nil {
+				t.Fatalf("Unmarshal(%s): %v", tt.in, err)
+			}
+			if id.IsNull() != tt.null {
+				t.Errorf("IsNull() = %v, want %v", id.IsNull(), tt.null)
+			}
+			data, err := json.Marshal(id)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(data) != tt.in {
+				t.Errorf("Marshal = %s, want %s", data, tt.in)
+			}
+		})
+	}
+}
+
+func TestID_UnmarshalInvalid(t *testing.T) {
+	for _, in := range []string{`true`, `{"a":1}`, `[1]`} {
+		var id ID
+		if err := json.Unmarshal([]byte(in), &id); err == nil {
+			t.Errorf("Unmarshal(%s) error = nil, want error", in)
+		}
+	}
+}
+
+func TestID_Int(t *testing.T) {
+	if n, ok := Number(12).Int(); !ok || n != 12 {
+		t.Errorf("Number(12).Int() = %d, %v", n, ok)
+	}
+	if _, ok := String("12").Int(); ok {
+		t.Error("String(\"12\").Int() ok = true, want false")
+	}
+	if _, ok := (ID{}).Int(); ok {
+		t.Error("zero ID Int() ok = true, want false")
+	}
+}
+
+func TestID_ZeroMarshalsNull(t *testing.T) {
+	var id ID
+	if !id.IsZero() || id.IsNull() {
+		t.Errorf("zero ID: IsZero=%v IsNull=%v", id.IsZero(), id.IsNull())
+	}
+	data, _ := json.Marshal(id)
+	if string(data) != "null" {
+		t.Errorf("Marshal(zero ID) = %s, want null", data)
+	}
+}
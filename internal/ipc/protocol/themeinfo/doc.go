@@ -0,0 +1,2 @@
+// Package themeinfo は config.get で返すユーザー定義のテーマの情報を提供する。
+package themeinfo
@@ -0,0 +1,20 @@
+package themeinfo
+
+// UserTheme はユーザー定義のテーマの情報を表す。
+type UserTheme struct {
+	Name   string `json:"name"`
+	Base   string `json:"base"`
+	Colors Colors `json:"colors"`
+}
+
+// Colors はユーザー定義のテーマの色を表す。空文字列の色はベースのテーマから引き継ぐ。
+type Colors struct {
+	Accent      string `json:"accent,omitempty"`
+	AccentDim   string `json:"accent_dim,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Muted       string `json:"muted,omitempty"`
+	Dim         string `json:"dim,omitempty"`
+	Error       string `json:"error,omitempty"`
+	Warning     string `json:"warning,omitempty"`
+	BgHighlight string `json:"bg_highlight,omitempty"`
+}
@@ -18,6 +18,7 @@ const (
 	SessionStarting     = "starting"
 	SessionStopped      = "stopped"
 	SessionReconnecting = "reconnecting"
+	SessionDraining     = "draining"
 	SessionError        = "error"
 )
 
@@ -35,6 +36,12 @@ const (
 	MethodEventsUnsubscribe  = "events.unsubscribe"
 	MethodCredentialRequest  = "credential.request"  //nolint:gosec // RPC method name, not a credential
 	MethodCredentialResponse = "credential.response" //nolint:gosec // RPC method name, not a credential
+	MethodProfileList        = "profile.list"
+	MethodAuthLogin          = "auth.login"
+	MethodRPCCancel          = "rpc.cancel"
+	MethodDaemonHello        = "daemon.hello"
+	MethodLogsSubscribe      = "logs.subscribe"
+	MethodLogsUnsubscribe    = "logs.unsubscribe"
 )
 
 // IPC ワイヤーフォーマット上のフォワードイベント種別文字列定数。
@@ -45,10 +52,36 @@ const (
 	ForwardEventTypeMetricsUpdated = "metrics_updated"
 	ForwardEventTypeReconnecting   = "reconnecting"
 	ForwardEventTypeRestored       = "restored"
+	ForwardEventTypeMigrated       = "migrated"
+	ForwardEventTypeExpiring       = "expiring"
+	ForwardEventTypeDenied         = "denied"
+	ForwardEventTypeDraining       = "draining"
+	ForwardEventTypeUpdated        = "updated"
+)
+
+// IPC ワイヤーフォーマット上のデーモンイベント種別文字列定数。
+const (
+	DaemonEventTypeShuttingDown = "shutting_down"
+	DaemonEventTypeRecovered    = "recovered"
+	DaemonEventTypeAutoStart    = "autostart"
+)
+
+// IPC ワイヤーフォーマット上の auto_connect のルールの自動開始の進捗の状態文字列定数。
+const (
+	AutoStartStatusStarting = "starting"
+	AutoStartStatusStarted  = "started"
+	AutoStartStatusRetrying = "retrying"
+	AutoStartStatusFailed   = "failed"
 )
 
 // IPC イベント通知メソッド名定数。
 const (
 	EventSSH     = "event.ssh"
 	EventForward = "event.forward"
+	EventDaemon  = "event.daemon"
+	EventSummary = "event.summary"
+	EventMetrics = "event.metrics"
+	EventHost    = "event.host"
+	EventConfig  = "event.config"
+	EventLog     = "event.log"
 )
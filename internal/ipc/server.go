@@ -2,6 +2,7 @@ package ipc
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -12,18 +13,22 @@ import (
 	"sync/atomic"
 	"time"
 
+	"github.com/ousiassllc/moleport/internal/ipc/localsock"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 )
 
 // HandlerFunc は RPC リクエストを処理するハンドラ関数の型。
-// clientID はリクエスト元のクライアント識別子。
-type HandlerFunc func(clientID string, method string, params json.RawMessage) (any, *protocol.RPCError)
+// clientID はリクエスト元のクライアント識別子。ctx はクライアントが rpc.cancel で取り消すか、接続が切れるとキャンセルされる。
+type HandlerFunc func(ctx context.Context, clientID string, method string, params json.RawMessage) (any, *protocol.RPCError)
 
 // IPCServer は Unix ドメインソケット上で JSON-RPC 2.0 通信を行うサーバー。
+// Serve で TLS の TCP リスナー等を追加し、同じハンドラでリクエストを受け付けることもできる。
 type IPCServer struct {
 	socketPath string
 	listener   net.Listener
+	extra      []net.Listener
 	handler    HandlerFunc
+	auth       Auth
 	clients    map[string]*clientConn
 	mu         sync.RWMutex
 	ctx        context.Context
@@ -46,6 +51,16 @@ type clientConn struct {
 	conn net.Conn
 	enc  *json.Encoder
 	mu   sync.Mutex
+	// authenticated は auth.login でトークンを提示済み（または TLS で認証済み）か。
+	authenticated atomic.Bool
+	// readOnly は読み取り専用メソッドのみ呼び出せるクライアントか。
+	readOnly atomic.Bool
+	// ctx は接続が切れるとキャンセルされ、実行中のリクエストの ctx の親になる。
+	ctx    context.Context
+	cancel context.CancelFunc
+	// inflight は実行中のリクエストの ID（JSON 表現）ごとの取り消し関数。
+	inflightMu sync.Mutex
+	inflight   map[string]context.CancelFunc
 }
 
 // NewIPCServer は新しい IPCServer を生成する。
@@ -57,33 +72,30 @@ func NewIPCServer(socketPath string, handler HandlerFunc) *IPCServer {
 	}
 }
 
-// Start はソケットを作成し、クライアント接続の受け付けを開始する。
+// Start はソケット（Windows では名前付きパイプ）を作成し、クライアント接続の受け付けを開始する。
+// 古いソケットファイルが残っている場合は削除する。
 func (s *IPCServer) Start(ctx context.Context) error {
-	// 古いソケットファイルがあれば削除する
-	if _, err := os.Stat(s.socketPath); err == nil {
-		if err := os.Remove(s.socketPath); err != nil {
-			return fmt.Errorf("remove stale socket: %w", err)
-		}
-	}
-
-	ln, err := net.Listen("unix", s.socketPath)
+	ln, err := localsock.Listen(s.socketPath)
 	if err != nil {
-		return fmt.Errorf("listen unix: %w", err)
-	}
-
-	if err := os.Chmod(s.socketPath, 0600); err != nil {
-		ln.Close()
-		return fmt.Errorf("chmod socket: %w", err)
+		return err
 	}
 
 	s.listener = ln
 	s.ctx, s.cancel = context.WithCancel(ctx)
 
-	go s.acceptLoop()
+	go s.acceptLoop(ln)
 
 	return nil
 }
 
+// Serve は Start 後に追加のリスナー ln での受け付けを開始する。ln は Stop で閉じる。
+func (s *IPCServer) Serve(ln net.Listener) {
+	s.mu.Lock()
+	s.extra = append(s.extra, ln)
+	s.mu.Unlock()
+	go s.acceptLoop(ln)
+}
+
 // Stop はリスナーを閉じ、全クライアント接続を切断し、ソケットファイルを削除する。
 func (s *IPCServer) Stop() error {
 	if s.cancel != nil {
@@ -98,6 +110,12 @@ func (s *IPCServer) Stop() error {
 	}
 
 	s.mu.Lock()
+	for _, ln := range s.extra {
+		if err := ln.Close(); err != nil {
+			slog.Debug("failed to close listener", "addr", ln.Addr().String(), "error", err)
+		}
+	}
+	s.extra = nil
 	for _, c := range s.clients {
 		if err := c.conn.Close(); err != nil {
 			slog.Debug("failed to close client connection", "client", c.id, "error", err)
@@ -146,9 +164,9 @@ func (s *IPCServer) BroadcastNotification(notification protocol.Notification) {
 	}
 }
 
-func (s *IPCServer) acceptLoop() {
+func (s *IPCServer) acceptLoop(ln net.Listener) {
 	for {
-		conn, err := s.listener.Accept()
+		conn, err := ln.Accept()
 		if err != nil {
 			// リスナーが閉じられた場合は終了
 			select {
@@ -162,13 +180,15 @@ func (s *IPCServer) acceptLoop() {
 			}
 		}
 
-		id := fmt.Sprintf("client-%d", s.nextID.Add(1))
-		c := &clientConn{
-			id:   id,
-			conn: conn,
-			enc:  json.NewEncoder(conn),
+		if !s.admit(conn) {
+			_ = conn.Close()
+			continue
 		}
 
+		id := fmt.Sprintf("client-%d", s.nextID.Add(1))
+		c := newClientConn(s.ctx, id, conn)
+		c.authenticated.Store(preAuthenticated(conn))
+
 		s.mu.Lock()
 		s.clients[id] = c
 		s.mu.Unlock()
@@ -186,6 +206,7 @@ func (s *IPCServer) acceptLoop() {
 
 func (s *IPCServer) readLoop(c *clientConn) {
 	defer func() {
+		c.cancel()
 		if err := c.conn.Close(); err != nil {
 			slog.Debug("failed to close client connection", "client", c.id, "error", err)
 		}
@@ -202,6 +223,10 @@ func (s *IPCServer) readLoop(c *clientConn) {
 		}
 	}()
 
+	if !s.handshake(c) {
+		return
+	}
+
 	scanner := bufio.NewScanner(c.conn)
 	// デフォルトの 64KB バッファで十分だが、大きなメッセージに備える
 	scanner.Buffer(make([]byte, 0, protocol.ScannerInitBuf), protocol.ScannerMaxBuf)
@@ -218,46 +243,29 @@ func (s *IPCServer) readLoop(c *clientConn) {
 			continue
 		}
 
-		var req protocol.Request
-		if err := json.Unmarshal(line, &req); err != nil {
-			// パースエラー: ID が不明なので null で返す
-			resp := protocol.NewErrorResponse(nil, protocol.ParseError, "parse error")
-			if err := c.send(resp); err != nil {
+		// 認証前のメッセージは auth.login の結果を後続のメッセージに反映するため順に処理する。
+		// 認証後は各行を並行して処理し、実行中のリクエストを rpc.cancel で取り消せるようにする
+		if !s.authorized(c) {
+			if !s.respond(c, line) {
 				return
 			}
 			continue
 		}
-
-		if req.JSONRPC != protocol.JSONRPCVersion {
-			if req.ID != nil {
-				resp := protocol.NewErrorResponse(req.ID, protocol.InvalidRequest, "invalid jsonrpc version")
-				if err := c.send(resp); err != nil {
-					return
-				}
-			}
-			continue
-		}
-
-		// ID が nil の場合は通知（レスポンス不要）
-		if req.ID == nil {
-			s.handler(c.id, req.Method, req.Params)
+		if isBatch(line) {
+			go s.respond(c, bytes.Clone(line))
 			continue
 		}
-
-		result, rpcErr := s.handler(c.id, req.Method, req.Params)
-		if rpcErr != nil {
-			resp := protocol.NewErrorResponse(req.ID, rpcErr.Code, rpcErr.Message)
-			if err := c.send(resp); err != nil {
-				return
-			}
+		// 後続の rpc.cancel が確実に見つけられるよう、登録までは受信順に行い、ハンドラの呼び出しだけを並行させる
+		call, resp, ok := s.prepare(c, line)
+		if call != nil {
+			go func() {
+				if resp, ok := call(); ok {
+					_ = c.send(resp)
+				}
+			}()
 			continue
 		}
-
-		resp, err := protocol.NewResponse(req.ID, result)
-		if err != nil {
-			resp = protocol.NewErrorResponse(req.ID, protocol.InternalError, "marshal result: "+err.Error())
-		}
-		if err := c.send(resp); err != nil {
+		if ok && c.send(resp) != nil {
 			return
 		}
 	}
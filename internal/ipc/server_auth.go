@@ -0,0 +1,137 @@
+package ipc
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// handshakeTimeout は TLS 接続のハンドシェイクのタイムアウト。
+const handshakeTimeout = 10 * time.Second
+
+// Auth は IPCServer の接続元の認証の設定。
+type Auth struct {
+	// Token が空でない場合、クライアントは auth.login でこのトークンを提示するまで他のメソッドを呼び出せない。
+	// TLS で接続したクライアントは証明書で認証済みとみなす。
+	Token string
+	// ReadOnlyToken を auth.login で提示したクライアントは読み取り専用になる。Token が空でも有効。
+	ReadOnlyToken string
+	// SameUID が true の場合、サーバーと異なる UID のプロセスからの Unix ソケット接続と、接続元の UID を取得できなかった接続を切断する。
+	// 接続元の UID を取得できない OS（PeerCredAvailable が false）では確認せず、呼び出し側で Token を必須にする。
+	SameUID bool
+}
+
+// SetAuth は接続元の認証を設定する。Start() の前に呼ぶ。
+func (s *IPCServer) SetAuth(auth Auth) {
+	s.auth = auth
+}
+
+// admit は接続 conn を受け付けるかを返す。Unix ソケット以外の接続（TLS など）は証明書やトークンで認証するため確認しない。
+func (s *IPCServer) admit(conn net.Conn) bool {
+	if !s.auth.SameUID || !PeerCredAvailable {
+		return true
+	}
+	// MessagePack などの変換を挟む接続は、変換前の Unix ソケットの接続で確認する
+	if w, ok := conn.(interface{ NetConn() net.Conn }); ok {
+		conn = w.NetConn()
+	}
+	uc, ok := conn.(*net.UnixConn)
+	if !ok {
+		return true
+	}
+	uid, err := peerUID(uc)
+	if err != nil {
+		slog.Warn("rejected ipc connection with unknown peer credentials", "error", err)
+		return false
+	}
+	if uid != os.Getuid() {
+		slog.Warn("rejected ipc connection from another user", "uid", uid)
+		return false
+	}
+	return true
+}
+
+// preAuthenticated は conn が auth.login なしで認証済みとみなせる接続（TLS）かを返す。
+func preAuthenticated(conn net.Conn) bool {
+	_, ok := conn.(*tls.Conn)
+	return ok
+}
+
+// authorized はクライアント c が auth.login 以外のメソッドを呼び出せるかを返す。
+func (s *IPCServer) authorized(c *clientConn) bool {
+	return s.auth.Token == "" || c.authenticated.Load()
+}
+
+// handshake は TLS 接続のハンドシェイクを完了し、クライアント証明書の OU に
+// protocol.RoleReadOnly を含む場合は読み取り専用にする。ハンドシェイクに失敗した場合は false を返す。
+func (s *IPCServer) handshake(c *clientConn) bool {
+	tc, ok := c.conn.(*tls.Conn)
+	if !ok {
+		return true
+	}
+	ctx, cancel := context.WithTimeout(s.ctx, handshakeTimeout)
+	defer cancel()
+	if err := tc.HandshakeContext(ctx); err != nil {
+		slog.Debug("tls handshake failed", "client", c.id, "error", err)
+		return false
+	}
+	if certs := tc.ConnectionState().PeerCertificates; len(certs) > 0 &&
+		slices.Contains(certs[0].Subject.OrganizationalUnit, protocol.RoleReadOnly) {
+		c.readOnly.Store(true)
+	}
+	return true
+}
+
+// permitted はクライアント c が method を呼び出せるかを返す。読み取り専用のクライアントは読み取り専用メソッドのみ呼び出せる。
+func permitted(c *clientConn, method string) bool {
+	return !c.readOnly.Load() || protocol.IsReadOnlyMethod(method)
+}
+
+// login は auth.login リクエストを処理する。トークンが不要な設定では提示されたトークンによらず成功する。
+// 読み取り専用トークンを提示した場合、または Role に読み取り専用を指定した場合は読み取り専用にする。
+// 一度読み取り専用になった接続は、管理者のトークンを提示し直しても管理者に戻らない。
+func (s *IPCServer) login(c *clientConn, req protocol.Request) protocol.Response {
+	var p protocol.AuthLoginParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "invalid params: "+err.Error())
+		}
+	}
+	if p.Role != "" && p.Role != protocol.RoleAdmin && p.Role != protocol.RoleReadOnly {
+		return protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "invalid role: "+p.Role)
+	}
+	switch {
+	case s.auth.ReadOnlyToken != "" && tokenEqual(p.Token, s.auth.ReadOnlyToken):
+		c.readOnly.Store(true)
+	case s.auth.Token == "" || c.authenticated.Load() || tokenEqual(p.Token, s.auth.Token):
+	default:
+		slog.Warn("ipc authentication failed", "client", c.id)
+		return protocol.NewErrorResponse(req.ID, protocol.Unauthorized, "invalid token")
+	}
+	if p.Role == protocol.RoleReadOnly {
+		c.readOnly.Store(true)
+	}
+	c.authenticated.Store(true)
+
+	role := protocol.RoleAdmin
+	if c.readOnly.Load() {
+		role = protocol.RoleReadOnly
+	}
+	resp, err := protocol.NewResponse(req.ID, protocol.AuthLoginResult{Authenticated: true, Role: role})
+	if err != nil {
+		return protocol.NewErrorResponse(req.ID, protocol.InternalError, "marshal result: "+err.Error())
+	}
+	return resp
+}
+
+func tokenEqual(got, want string) bool {
+	return subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
@@ -0,0 +1,270 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	ipcclient "github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func startAuthServer(t *testing.T, auth Auth) string {
+	t.Helper()
+	sockPath := filepath.Join(t.TempDir(), "test.sock")
+	srv := NewIPCServer(sockPath, echoHandler)
+	srv.SetAuth(auth)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start server: %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+	return sockPath
+}
+
+func TestServerAuth_TokenRequired(t *testing.T) {
+	sockPath := startAuthServer(t, Auth{Token: "secret", SameUID: true})
+
+	anon := connectTestClient(t, sockPath)
+	var rpcErr *protocol.RPCError
+	if err := anon.Call(testCtxWithCleanup(t), "echo", nil, nil); !errors.As(err, &rpcErr) || rpcErr.Code != protocol.Unauthorized {
+		t.Errorf("Call without login error = %v, want Unauthorized", err)
+	}
+
+	wrong := ipcclient.NewIPCClient(sockPath)
+	wrong.SetToken("guess")
+	if err := wrong.Connect(); err == nil {
+		_ = wrong.Close()
+		t.Error("Connect with a wrong token should fail")
+	}
+
+	ok := ipcclient.NewIPCClient(sockPath)
+	ok.SetToken("secret")
+	if err := ok.Connect(); err != nil {
+		t.Fatalf("Connect with the token: %v", err)
+	}
+	defer func() { _ = ok.Close() }()
+	if err := ok.Call(testCtxWithCleanup(t), "echo", map[string]string{"msg": "hi"}, nil); err != nil {
+		t.Errorf("Call after login: %v", err)
+	}
+}
+
+func TestServerAuth_TokenNotRequired(t *testing.T) {
+	sockPath := startAuthServer(t, Auth{SameUID: true})
+
+	c := ipcclient.NewIPCClient(sockPath)
+	c.SetToken("any")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+	if err := c.Call(testCtxWithCleanup(t), "echo", nil, nil); err != nil {
+		t.Errorf("Call: %v", err)
+	}
+}
+
+// rawSession は Unix ソケットに直接 JSON-RPC リクエストを送り、レスポンスを返す関数を返す。
+func rawSession(t *testing.T, sockPath string) func(method string, params any) protocol.Response {
+	t.Helper()
+	conn, err := net.Dial("unix", sockPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+	id := 0
+	return func(method string, params any) protocol.Response {
+		id++
+		data, err := json.Marshal(map[string]any{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.Write(append(data, '\n')); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		var resp protocol.Response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		return resp
+	}
+}
+
+func errCode(resp protocol.Response) int {
+	if resp.Error == nil {
+		return 0
+	}
+	return resp.Error.Code
+}
+
+func loginRole(t *testing.T, resp protocol.Response) string {
+	t.Helper()
+	if resp.Error != nil {
+		t.Fatalf("auth.login error = %+v", resp.Error)
+	}
+	var result protocol.AuthLoginResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	return result.Role
+}
+
+func TestServerAuth_ReadOnlyToken(t *testing.T) {
+	sockPath := startAuthServer(t, Auth{Token: "secret", ReadOnlyToken: "view"})
+
+	call := rawSession(t, sockPath)
+	if role := loginRole(t, call(protocol.MethodAuthLogin, protocol.AuthLoginParams{Token: "view"})); role != protocol.RoleReadOnly {
+		t.Errorf("role = %q, want %q", role, protocol.RoleReadOnly)
+	}
+	if code := errCode(call("forward.add", nil)); code != protocol.PermissionDenied {
+		t.Errorf("forward.add error code = %d, want PermissionDenied", code)
+	}
+	// 読み取り専用メソッドはハンドラまで届く（echoHandler は未知のメソッドに MethodNotFound を返す）
+	if code := errCode(call("host.list", nil)); code != protocol.MethodNotFound {
+		t.Errorf("host.list error code = %d, want MethodNotFound", code)
+	}
+	// 管理者のトークンを提示し直しても管理者にはならない
+	if role := loginRole(t, call(protocol.MethodAuthLogin, protocol.AuthLoginParams{Token: "secret"})); role != protocol.RoleReadOnly {
+		t.Errorf("role after re-login with the admin token = %q, want %q", role, protocol.RoleReadOnly)
+	}
+	if code := errCode(call("forward.add", nil)); code != protocol.PermissionDenied {
+		t.Errorf("forward.add after re-login error code = %d, want PermissionDenied", code)
+	}
+
+	c := ipcclient.NewIPCClient(sockPath)
+	c.SetToken("view")
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect with the read-only token: %v", err)
+	}
+	defer func() { _ = c.Close() }()
+	if !c.IsReadOnly() {
+		t.Error("IsReadOnly() = false after login with the read-only token")
+	}
+}
+
+func TestServerAuth_LoginWithReadOnlyToken(t *testing.T) {
+	sockPath := startAuthServer(t, Auth{Token: "secret", ReadOnlyToken: "view"})
+
+	c := connectTestClient(t, sockPath)
+	if err := c.Login(testCtxWithCleanup(t), "view"); err != nil {
+		t.Fatalf("Login with the read-only token: %v", err)
+	}
+	if !c.IsReadOnly() {
+		t.Error("IsReadOnly() = false after Login with the read-only token")
+	}
+	// クライアント側の制限を外しても、デーモンが状態を変更するメソッドを拒否する
+	c.SetReadOnly(false)
+	var rpcErr *protocol.RPCError
+	if err := c.Call(testCtxWithCleanup(t), "forward.add", nil, nil); !errors.As(err, &rpcErr) || rpcErr.Code != protocol.PermissionDenied {
+		t.Errorf("forward.add error = %v, want PermissionDenied", err)
+	}
+}
+
+func TestServerAuth_Role(t *testing.T) {
+	sockPath := startAuthServer(t, Auth{Token: "secret"})
+
+	admin := rawSession(t, sockPath)
+	if role := loginRole(t, admin(protocol.MethodAuthLogin, protocol.AuthLoginParams{Token: "secret"})); role != protocol.RoleAdmin {
+		t.Errorf("role = %q, want %q", role, protocol.RoleAdmin)
+	}
+	if code := errCode(admin("forward.add", nil)); code != protocol.MethodNotFound {
+		t.Errorf("forward.add error code = %d, want MethodNotFound", code)
+	}
+
+	viewer := rawSession(t, sockPath)
+	params := protocol.AuthLoginParams{Token: "secret", Role: protocol.RoleReadOnly}
+	if role := loginRole(t, viewer(protocol.MethodAuthLogin, params)); role != protocol.RoleReadOnly {
+		t.Errorf("role = %q, want %q", role, protocol.RoleReadOnly)
+	}
+	// 一度読み取り専用になった接続は再ログインしても管理者に戻らない
+	loginRole(t, viewer(protocol.MethodAuthLogin, protocol.AuthLoginParams{Token: "secret"}))
+	if code := errCode(viewer("forward.delete", nil)); code != protocol.PermissionDenied {
+		t.Errorf("forward.delete error code = %d, want PermissionDenied", code)
+	}
+
+	invalid := rawSession(t, sockPath)
+	if code := errCode(invalid(protocol.MethodAuthLogin, protocol.AuthLoginParams{Token: "secret", Role: "root"})); code != protocol.InvalidParams {
+		t.Errorf("auth.login with an invalid role error code = %d, want InvalidParams", code)
+	}
+}
+
+func TestServerAuth_RestrictToReadOnly(t *testing.T) {
+	// トークン不要の Unix ソケットでも、閲覧専用のクライアントはデーモン側の接続が読み取り専用になる
+	sockPath := startAuthServer(t, Auth{SameUID: true})
+
+	c := connectTestClient(t, sockPath)
+	if err := c.RestrictToReadOnly(testCtxWithCleanup(t)); err != nil {
+		t.Fatalf("RestrictToReadOnly: %v", err)
+	}
+	// クライアント側の制限を外しても、デーモンが状態を変更するメソッドを拒否する
+	c.SetReadOnly(false)
+	var rpcErr *protocol.RPCError
+	if err := c.Call(testCtxWithCleanup(t), "forward.add", nil, nil); !errors.As(err, &rpcErr) || rpcErr.Code != protocol.PermissionDenied {
+		t.Errorf("forward.add error = %v, want PermissionDenied", err)
+	}
+}
+
+// acceptUnix は Unix ソケットで接続し、サーバー側の接続を返す。
+func acceptUnix(t *testing.T) *net.UnixConn {
+	t.Helper()
+	ln, err := net.Listen("unix", filepath.Join(t.TempDir(), "peer.sock"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = ln.Close() })
+	client, err := net.Dial("unix", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = client.Close() })
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn.(*net.UnixConn)
+}
+
+func TestPeerUID(t *testing.T) {
+	if !PeerCredAvailable {
+		t.Skip("peer credentials are not supported on " + runtime.GOOS)
+	}
+	if uid, err := peerUID(acceptUnix(t)); err != nil || uid != os.Getuid() {
+		t.Errorf("peerUID() = %d, %v, want %d, nil", uid, err, os.Getuid())
+	}
+}
+
+func TestAdmit(t *testing.T) {
+	if !PeerCredAvailable {
+		t.Skip("peer credentials are not supported on " + runtime.GOOS)
+	}
+	s := &IPCServer{auth: Auth{SameUID: true}}
+	if !s.admit(acceptUnix(t)) {
+		t.Error("admit() = false for a connection from the same user")
+	}
+
+	// 接続元の UID を取得できない接続は所有者とみなさずに拒否する
+	closed := acceptUnix(t)
+	_ = closed.Close()
+	if s.admit(closed) {
+		t.Error("admit() = true for a connection with unknown peer credentials")
+	}
+
+	// Unix ソケット以外の接続は証明書やトークンで認証するため確認しない
+	a, b := net.Pipe()
+	defer func() { _ = a.Close(); _ = b.Close() }()
+	if !s.admit(a) {
+		t.Error("admit() = false for a non-unix connection")
+	}
+}
@@ -0,0 +1,132 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	ipcclient "github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestServer_BatchOverSocket(t *testing.T) {
+	_, sockPath := startTestServer(t, echoHandler)
+
+	conn, err := net.Dial("unix", sockPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	r := bufio.NewReader(conn)
+
+	send := func(line string) []byte {
+		t.Helper()
+		if _, err := conn.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		resp, err := r.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		return resp
+	}
+
+	// 通知の要素にはレスポンスを返さない
+	line := send(`[{"jsonrpc":"2.0","id":1,"method":"echo","params":{"n":1}},` +
+		`{"jsonrpc":"2.0","method":"echo"},` +
+		`{"jsonrpc":"2.0","id":2,"method":"unknown"}]`)
+	var resps []protocol.Response
+	if err := json.Unmarshal(line, &resps); err != nil {
+		t.Fatalf("batch response = %s: %v", line, err)
+	}
+	if len(resps) != 2 {
+		t.Fatalf("len(responses) = %d, want 2: %s", len(resps), line)
+	}
+	if id, _ := resps[0].ID.Int(); id != 1 || !jsonEqual(resps[0].Result, json.RawMessage(`{"n":1}`)) {
+		t.Errorf("responses[0] = %+v", resps[0])
+	}
+	if id, _ := resps[1].ID.Int(); id != 2 || resps[1].Error == nil || resps[1].Error.Code != protocol.MethodNotFound {
+		t.Errorf("responses[1] = %+v, want MethodNotFound", resps[1])
+	}
+
+	var single protocol.Response
+	if err := json.Unmarshal(send(`[]`), &single); err != nil || single.Error == nil || single.Error.Code != protocol.InvalidRequest {
+		t.Errorf("empty batch response = %+v, %v, want InvalidRequest", single, err)
+	}
+
+	// 通知だけのバッチにはレスポンスを返さないため、次に読めるのは後続リクエストへの応答になる
+	line = send(`[{"jsonrpc":"2.0","method":"echo"}]` + "\n" + `{"jsonrpc":"2.0","id":"after","method":"echo"}`)
+	if !jsonEqual(line, json.RawMessage(`{"jsonrpc":"2.0","id":"after","result":null}`)) {
+		t.Errorf("response after a notification-only batch = %s", line)
+	}
+}
+
+func TestIPCClient_Batch(t *testing.T) {
+	_, sockPath := startTestServer(t, echoHandler)
+	c := connectTestClient(t, sockPath)
+
+	var echoed map[string]string
+	calls := []ipcclient.BatchCall{
+		{Method: "echo", Params: map[string]string{"msg": "hi"}, Result: &echoed},
+		{Method: "error"},
+		{Method: "echo"},
+	}
+	if err := c.Batch(testCtxWithCleanup(t), calls); err != nil {
+		t.Fatalf("Batch: %v", err)
+	}
+	if calls[0].Err != nil || echoed["msg"] != "hi" {
+		t.Errorf("calls[0] = %v, %v", echoed, calls[0].Err)
+	}
+	var rpcErr *protocol.RPCError
+	if !errors.As(calls[1].Err, &rpcErr) || rpcErr.Code != protocol.InternalError {
+		t.Errorf("calls[1].Err = %v, want InternalError", calls[1].Err)
+	}
+	if calls[2].Err != nil {
+		t.Errorf("calls[2].Err = %v", calls[2].Err)
+	}
+
+	if err := c.Notify("echo", nil); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := c.Call(testCtxWithCleanup(t), "echo", nil, nil); err != nil {
+		t.Errorf("Call after Notify: %v", err)
+	}
+
+	c.SetReadOnly(true)
+	calls = []ipcclient.BatchCall{{Method: "forward.add"}}
+	if err := c.Batch(testCtxWithCleanup(t), calls); err != nil || !errors.Is(calls[0].Err, ipcclient.ErrReadOnly) {
+		t.Errorf("Batch on a read-only client = %v, calls[0].Err = %v, want ErrReadOnly", err, calls[0].Err)
+	}
+}
+
+func TestIPCClient_Hello(t *testing.T) {
+	// daemon.hello に対応していないデーモンは旧プロトコルとみなし、バッチを 1 件ずつの呼び出しに切り替える
+	_, sockPath := startTestServer(t, echoHandler)
+	legacy := connectTestClient(t, sockPath)
+	hello, err := legacy.Hello(testCtxWithCleanup(t), "v1.0.0")
+	if err != nil || hello.ProtocolVersion != protocol.LegacyProtocolVersion || len(hello.Capabilities) != 0 {
+		t.Fatalf("Hello() = %+v, %v, want the legacy protocol", hello, err)
+	}
+	calls := []ipcclient.BatchCall{{Method: "echo"}, {Method: "unknown"}}
+	if err := legacy.Batch(testCtxWithCleanup(t), calls); err != nil || calls[0].Err != nil || calls[1].Err == nil {
+		t.Errorf("Batch() on a legacy daemon = %v, calls = %+v", err, calls)
+	}
+
+	// 互換性のないプロトコルのデーモン
+	_, sockPath = startTestServer(t, func(ctx context.Context, id, method string, params json.RawMessage) (any, *protocol.RPCError) {
+		if method != protocol.MethodDaemonHello {
+			return echoHandler(ctx, id, method, params)
+		}
+		return protocol.DaemonHelloResult{ProtocolVersion: protocol.ProtocolVersion + 1, MinProtocolVersion: protocol.ProtocolVersion + 1, ServerVersion: "v9.0.0"}, nil
+	})
+	future := connectTestClient(t, sockPath)
+	hello, err = future.Hello(testCtxWithCleanup(t), "v1.0.0")
+	if !errors.Is(err, ipcclient.ErrIncompatibleProtocol) || hello == nil || hello.ServerVersion != "v9.0.0" {
+		t.Errorf("Hello() = %+v, %v, want ErrIncompatibleProtocol with the daemon version", hello, err)
+	}
+}
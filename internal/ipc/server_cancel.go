@@ -0,0 +1,64 @@
+package ipc
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// newClientConn は parent から派生した ctx を持つ clientConn を生成する。
+func newClientConn(parent context.Context, id string, conn net.Conn) *clientConn {
+	ctx, cancel := context.WithCancel(parent)
+	return &clientConn{
+		id:       id,
+		conn:     conn,
+		enc:      json.NewEncoder(conn),
+		ctx:      ctx,
+		cancel:   cancel,
+		inflight: make(map[string]context.CancelFunc),
+	}
+}
+
+// track は req の実行用に接続の ctx から派生した ctx を作り、rpc.cancel で取り消せるように登録する。
+// 返す関数は処理の完了後に呼び、登録を解除する。
+func (c *clientConn) track(req protocol.Request) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(c.ctx)
+	key := req.ID.String()
+	c.inflightMu.Lock()
+	c.inflight[key] = cancel
+	c.inflightMu.Unlock()
+	return ctx, func() {
+		c.inflightMu.Lock()
+		delete(c.inflight, key)
+		c.inflightMu.Unlock()
+		cancel()
+	}
+}
+
+// cancelRequest は rpc.cancel リクエストを処理し、同じ接続で実行中の ID のリクエストの ctx をキャンセルする。
+// 取り消されたリクエストには RequestCancelled のエラーが返る（処理が先に完了した場合はその結果が返る）。
+func (s *IPCServer) cancelRequest(c *clientConn, req protocol.Request) protocol.Response {
+	var p protocol.RPCCancelParams
+	if len(req.Params) > 0 {
+		if err := json.Unmarshal(req.Params, &p); err != nil {
+			return protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "invalid params: "+err.Error())
+		}
+	}
+	if p.ID.IsZero() || p.ID.IsNull() {
+		return protocol.NewErrorResponse(req.ID, protocol.InvalidParams, "id is required")
+	}
+
+	c.inflightMu.Lock()
+	cancel, ok := c.inflight[p.ID.String()]
+	c.inflightMu.Unlock()
+	if ok {
+		cancel()
+	}
+	resp, err := protocol.NewResponse(req.ID, protocol.RPCCancelResult{Cancelled: ok})
+	if err != nil {
+		return protocol.NewErrorResponse(req.ID, protocol.InternalError, "marshal result: "+err.Error())
+	}
+	return resp
+}
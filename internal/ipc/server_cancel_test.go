@@ -0,0 +1,78 @@
+package ipc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestServer_RPCCancel(t *testing.T) {
+	_, sockPath := startTestServer(t, echoHandler)
+
+	conn, err := net.Dial("unix", sockPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	lines := `{"jsonrpc":"2.0","id":1,"method":"wait"}` + "\n" +
+		`{"jsonrpc":"2.0","id":2,"method":"rpc.cancel","params":{"id":1}}` + "\n"
+	if _, err := conn.Write([]byte(lines)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	// 取り消しの応答と取り消されたリクエストの応答は順不同で届く
+	r := bufio.NewReader(conn)
+	resps := make(map[string]protocol.Response)
+	for range 2 {
+		line, err := r.ReadBytes('\n')
+		if err != nil {
+			t.Fatalf("Read: %v", err)
+		}
+		var resp protocol.Response
+		if err := json.Unmarshal(line, &resp); err != nil {
+			t.Fatalf("Unmarshal: %v", err)
+		}
+		resps[resp.ID.String()] = resp
+	}
+	if wait := resps["1"]; wait.Error == nil || wait.Error.Code != protocol.RequestCancelled {
+		t.Errorf("wait response = %+v, want RequestCancelled", wait)
+	}
+	if cancel := resps["2"]; !jsonEqual(cancel.Result, json.RawMessage(`{"cancelled":true}`)) {
+		t.Errorf("rpc.cancel response = %+v, want cancelled", cancel)
+	}
+}
+
+func TestIPCClient_CallCancelNotifiesServer(t *testing.T) {
+	cancelled := make(chan struct{})
+	handler := func(ctx context.Context, _ string, _ string, _ json.RawMessage) (any, *protocol.RPCError) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, nil
+	}
+	_, sockPath := startTestServer(t, handler)
+	c := connectTestClient(t, sockPath)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+	if err := c.Call(ctx, "ssh.connect", nil, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Call error = %v, want context.Canceled", err)
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler's context was not cancelled by rpc.cancel")
+	}
+
+	var result protocol.RPCCancelResult
+	if err := c.Call(testCtxWithCleanup(t), protocol.MethodRPCCancel, protocol.RPCCancelParams{}, &result); err == nil {
+		t.Error("rpc.cancel without id should fail")
+	}
+}
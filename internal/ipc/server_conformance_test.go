@@ -0,0 +1,139 @@
+package ipc
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// conformanceCase は testdata/jsonrpc_conformance.json の 1 ケース。
+// response が null の場合はレスポンスが返らないこと（通知）を期待する。
+// error.message が省略された場合はエラーコードのみ比較する。
+type conformanceCase struct {
+	Name     string `json:"name"`
+	Request  string `json:"request"`
+	Response *struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Result  json.RawMessage `json:"result"`
+		Error   *struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	} `json:"response"`
+}
+
+func loadConformanceCases(t *testing.T) []conformanceCase {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "jsonrpc_conformance.json"))
+	if err != nil {
+		t.Fatalf("read fixtures: %v", err)
+	}
+	var cases []conformanceCase
+	if err := json.Unmarshal(data, &cases); err != nil {
+		t.Fatalf("parse fixtures: %v", err)
+	}
+	return cases
+}
+
+func jsonEqual(a, b json.RawMessage) bool {
+	var x, y bytes.Buffer
+	if err := json.Compact(&x, a); err != nil {
+		return false
+	}
+	if err := json.Compact(&y, b); err != nil {
+		return false
+	}
+	return bytes.Equal(x.Bytes(), y.Bytes())
+}
+
+func TestDispatch_Conformance(t *testing.T) {
+	srv := NewIPCServer("", echoHandler)
+
+	for _, tc := range loadConformanceCases(t) {
+		t.Run(tc.Name, func(t *testing.T) {
+			resp, ok := srv.dispatch(newClientConn(context.Background(), "client-1", nil), []byte(tc.Request))
+			want := tc.Response
+			if want == nil {
+				if ok {
+					t.Fatalf("got response %+v, want none", resp)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("got no response")
+			}
+
+			data, err := json.Marshal(resp)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var got struct {
+				JSONRPC string          `json:"jsonrpc"`
+				ID      json.RawMessage `json:"id"`
+				Result  json.RawMessage `json:"result"`
+			}
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+
+			if got.JSONRPC != want.JSONRPC {
+				t.Errorf("jsonrpc = %q, want %q", got.JSONRPC, want.JSONRPC)
+			}
+			if !jsonEqual(got.ID, want.ID) {
+				t.Errorf("id = %s, want %s", got.ID, want.ID)
+			}
+			if want.Error == nil {
+				if resp.Error != nil {
+					t.Fatalf("error = %v, want result %s", resp.Error, want.Result)
+				}
+				if !jsonEqual(got.Result, want.Result) {
+					t.Errorf("result = %s, want %s", got.Result, want.Result)
+				}
+				return
+			}
+			if resp.Error == nil {
+				t.Fatalf("result = %s, want error %d", got.Result, want.Error.Code)
+			}
+			if resp.Error.Code != want.Error.Code {
+				t.Errorf("error.code = %d, want %d", resp.Error.Code, want.Error.Code)
+			}
+			if want.Error.Message != "" && resp.Error.Message != want.Error.Message {
+				t.Errorf("error.message = %q, want %q", resp.Error.Message, want.Error.Message)
+			}
+		})
+	}
+}
+
+func TestServer_StringIDOverSocket(t *testing.T) {
+	_, sockPath := startTestServer(t, echoHandler)
+
+	conn, err := net.Dial("unix", sockPath)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	// 通知にはレスポンスが返らないため、次に読めるのは後続リクエストへの応答になる
+	lines := `{"jsonrpc":"2.0","method":"echo","params":null}` + "\n" +
+		`{"jsonrpc":"2.0","id":"req-1","method":"echo","params":null}` + "\n"
+	if _, err := conn.Write([]byte(lines)); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	line, err := bufio.NewReader(conn).ReadBytes('\n')
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	want := `{"jsonrpc":"2.0","id":"req-1","result":null}`
+	if !jsonEqual(bytes.TrimSpace(line), json.RawMessage(want)) {
+		t.Errorf("response = %s, want %s", line, want)
+	}
+}
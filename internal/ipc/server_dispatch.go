@@ -0,0 +1,134 @@
+package ipc
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/rpcid"
+)
+
+// pendingCall はハンドラの呼び出しを待っているリクエスト。呼び出すと返すべきレスポンスを返す。
+type pendingCall func() (resp protocol.Response, ok bool)
+
+// respond は 1 行を処理してレスポンスを送信する。送信に失敗した場合は false を返す。
+func (s *IPCServer) respond(c *clientConn, line []byte) bool {
+	resp, ok := s.handleLine(c, line)
+	if !ok {
+		return true
+	}
+	return c.send(resp) == nil
+}
+
+// handleLine は受信した 1 行を処理し、返すべきレスポンスを返す。
+// 行が配列の場合は JSON-RPC のバッチとして各要素を順に処理し、レスポンスを配列で返す。
+// 全ての要素が通知の場合、または通知 1 件の場合は ok=false を返し、レスポンスを送信しない。
+func (s *IPCServer) handleLine(c *clientConn, line []byte) (resp any, ok bool) {
+	if !isBatch(line) {
+		return s.dispatch(c, line)
+	}
+
+	var batch []json.RawMessage
+	if err := json.Unmarshal(line, &batch); err != nil {
+		return protocol.NewErrorResponse(rpcid.ID{}, protocol.ParseError, "parse error"), true
+	}
+	if len(batch) == 0 {
+		return protocol.NewErrorResponse(rpcid.ID{}, protocol.InvalidRequest, "empty batch"), true
+	}
+	resps := make([]protocol.Response, 0, len(batch))
+	for _, msg := range batch {
+		if r, ok := s.dispatch(c, msg); ok {
+			resps = append(resps, r)
+		}
+	}
+	if len(resps) == 0 {
+		return nil, false
+	}
+	return resps, true
+}
+
+// isBatch は行が JSON の配列（バッチ）かを返す。
+func isBatch(line []byte) bool {
+	trimmed := bytes.TrimLeft(line, " \t\r")
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
+
+// dispatch は受信した 1 件の JSON-RPC メッセージを処理し、返すべきレスポンスを返す。
+// 通知（ID なし）の場合は ok=false を返し、レスポンスを送信しない。
+func (s *IPCServer) dispatch(c *clientConn, line []byte) (resp protocol.Response, ok bool) {
+	call, resp, ok := s.prepare(c, line)
+	if call != nil {
+		return call()
+	}
+	return resp, ok
+}
+
+// prepare は 1 件のメッセージを検証する。ハンドラを呼び出すリクエストの場合は、rpc.cancel で取り消せるように
+// 登録したうえで呼び出しを call として返す。それ以外（エラー、サーバー自身が処理するメソッド）は
+// 返すべきレスポンスを resp と ok で返す。
+func (s *IPCServer) prepare(c *clientConn, line []byte) (call pendingCall, resp protocol.Response, ok bool) {
+	var req protocol.Request
+	if err := json.Unmarshal(line, &req); err != nil {
+		// 不正な JSON はパースエラー、JSON として正しいがリクエストとして不正なものは InvalidRequest。
+		// いずれも ID が不明なので null で返す
+		if !json.Valid(line) {
+			return nil, protocol.NewErrorResponse(rpcid.ID{}, protocol.ParseError, "parse error"), true
+		}
+		return nil, protocol.NewErrorResponse(rpcid.ID{}, protocol.InvalidRequest, "invalid request: "+err.Error()), true
+	}
+
+	// "params": null は params 省略と同じ扱いにする
+	if bytes.Equal(req.Params, []byte("null")) {
+		req.Params = nil
+	}
+
+	switch {
+	case req.JSONRPC != protocol.JSONRPCVersion:
+		return nil, protocol.NewErrorResponse(req.ID, protocol.InvalidRequest, "invalid jsonrpc version"), true
+	case req.Method == "":
+		return nil, protocol.NewErrorResponse(req.ID, protocol.InvalidRequest, "method is required"), true
+	case len(req.Params) > 0 && req.Params[0] != '{' && req.Params[0] != '[':
+		return nil, protocol.NewErrorResponse(req.ID, protocol.InvalidRequest, "params must be an object or array"), true
+	}
+
+	// auth.login と rpc.cancel はサーバーが処理する。認証前のクライアントの他のメソッドと、読み取り専用のクライアントの
+	// 状態を変更するメソッドは拒否し、通知は破棄する
+	notify := req.ID.IsZero()
+	switch {
+	case req.Method == protocol.MethodAuthLogin:
+		return nil, s.login(c, req), !notify
+	case !s.authorized(c):
+		return nil, protocol.NewErrorResponse(req.ID, protocol.Unauthorized, "authentication required: call auth.login first"), !notify
+	case !permitted(c, req.Method):
+		return nil, protocol.NewErrorResponse(req.ID, protocol.PermissionDenied, "read-only client cannot call "+req.Method), !notify
+	case req.Method == protocol.MethodRPCCancel:
+		return nil, s.cancelRequest(c, req), !notify
+	}
+
+	// ID が省略された場合は通知（レスポンス不要）
+	if notify {
+		return func() (protocol.Response, bool) {
+			s.handler(c.ctx, c.id, req.Method, req.Params)
+			return protocol.Response{}, false
+		}, protocol.Response{}, false
+	}
+
+	ctx, done := c.track(req)
+	return func() (protocol.Response, bool) {
+		defer done()
+		result, rpcErr := s.handler(ctx, c.id, req.Method, req.Params)
+		if rpcErr != nil && ctx.Err() != nil {
+			rpcErr = &protocol.RPCError{Code: protocol.RequestCancelled, Message: "request cancelled"}
+		}
+		if rpcErr != nil {
+			resp := protocol.NewErrorResponse(req.ID, rpcErr.Code, rpcErr.Message)
+			resp.Error.Data = rpcErr.Data
+			return resp, true
+		}
+		resp, err := protocol.NewResponse(req.ID, result)
+		if err != nil {
+			resp = protocol.NewErrorResponse(req.ID, protocol.InternalError, "marshal result: "+err.Error())
+		}
+		return resp, true
+	}, protocol.Response{}, false
+}
@@ -183,7 +183,7 @@ func TestIPCClient_CallNotConnected(t *testing.T) {
 
 func TestIPCClient_CallContextTimeout(t *testing.T) {
 	// レスポンスを返さないハンドラでタイムアウトを検証する
-	slowHandler := func(_ string, method string, params json.RawMessage) (any, *protocol.RPCError) {
+	slowHandler := func(_ context.Context, _ string, method string, params json.RawMessage) (any, *protocol.RPCError) {
 		time.Sleep(5 * time.Second)
 		return nil, nil
 	}
@@ -19,13 +19,16 @@ func testCtxWithCleanup(t *testing.T) context.Context {
 	return ctx
 }
 
-// echoHandler はメソッド名に応じた固定レスポンスを返すテスト用ハンドラ。
-func echoHandler(_ string, method string, params json.RawMessage) (any, *protocol.RPCError) {
+// echoHandler はメソッド名に応じた固定レスポンスを返すテスト用ハンドラ。wait は ctx がキャンセルされるまで待つ。
+func echoHandler(ctx context.Context, _ string, method string, params json.RawMessage) (any, *protocol.RPCError) {
 	switch method {
 	case "echo":
 		return json.RawMessage(params), nil
 	case "error":
-		return nil, &protocol.RPCError{Code: protocol.InternalError, Message: "test error"}
+		return nil, &protocol.RPCError{Code: protocol.InternalError, Message: "test error", Data: map[string]string{"field": "x"}}
+	case "wait":
+		<-ctx.Done()
+		return nil, &protocol.RPCError{Code: protocol.InternalError, Message: ctx.Err().Error()}
 	default:
 		return nil, &protocol.RPCError{Code: protocol.MethodNotFound, Message: "method not found"}
 	}
@@ -86,6 +89,9 @@ func TestServerClient_ErrorResponse(t *testing.T) {
 	if rpcErr.Message != "test error" {
 		t.Errorf("RPCError.Message = %q, want %q", rpcErr.Message, "test error")
 	}
+	if data, ok := rpcErr.Data.(map[string]any); !ok || data["field"] != "x" {
+		t.Errorf("RPCError.Data = %#v, want the handler's error data", rpcErr.Data)
+	}
 }
 
 func TestServerClient_MultipleClients(t *testing.T) {
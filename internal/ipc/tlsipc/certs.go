@@ -0,0 +1,215 @@
+package tlsipc
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+const (
+	caCertFile     = "ca.pem"
+	caKeyFile      = "ca-key.pem"
+	serverCertFile = "server.pem"
+	serverKeyFile  = "server-key.pem"
+
+	// caValidity は CA 証明書の有効期間。
+	caValidity = 10 * 365 * 24 * time.Hour
+	// leafValidity はサーバー・クライアント証明書の有効期間。
+	leafValidity = 365 * 24 * time.Hour
+	// renewBefore は期限切れのどれだけ前からサーバー証明書を更新するか。
+	renewBefore = 30 * 24 * time.Hour
+)
+
+// Dir は設定ディレクトリ configDir の証明書ディレクトリを返す。
+func Dir(configDir string) string {
+	return filepath.Join(configDir, "tls")
+}
+
+// Authority は証明書を発行するデーモン専用の CA。
+type Authority struct {
+	Cert    *x509.Certificate
+	CertPEM []byte
+	key     *ecdsa.PrivateKey
+}
+
+// LoadOrCreateCA は dir の CA を読み込む。存在しない場合は新しく作成して保存する。
+func LoadOrCreateCA(dir string) (*Authority, error) {
+	certPEM, err := os.ReadFile(filepath.Join(dir, caCertFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return createCA(dir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read ca: %w", err)
+	}
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, caCertFile), filepath.Join(dir, caKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("load ca: %w", err)
+	}
+	key, ok := pair.PrivateKey.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("load ca: unsupported key type %T", pair.PrivateKey)
+	}
+	return &Authority{Cert: pair.Leaf, CertPEM: certPEM, key: key}, nil
+}
+
+// createCA は新しい CA を作成し、dir に保存する。
+func createCA(dir string) (*Authority, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	tmpl, err := template(pkix.Name{CommonName: "MolePort CA"}, caValidity)
+	if err != nil {
+		return nil, err
+	}
+	tmpl.IsCA = true
+	tmpl.BasicConstraintsValid = true
+	tmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		return nil, fmt.Errorf("create ca: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	certPEM, keyPEM, err := encodePair(der, key)
+	if err != nil {
+		return nil, err
+	}
+	if err := writePair(dir, caCertFile, caKeyFile, certPEM, keyPEM); err != nil {
+		return nil, err
+	}
+	return &Authority{Cert: cert, CertPEM: certPEM, key: key}, nil
+}
+
+// Issue は subject の証明書を発行し、PEM 形式の証明書と秘密鍵を返す。
+// hosts が空でない場合はそれらを SAN に含むサーバー証明書、空の場合はクライアント証明書を発行する。
+func (a *Authority) Issue(subject pkix.Name, hosts []string) (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl, err := template(subject, leafValidity)
+	if err != nil {
+		return nil, nil, err
+	}
+	tmpl.KeyUsage = x509.KeyUsageDigitalSignature
+	tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	if len(hosts) > 0 {
+		tmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+		for _, h := range hosts {
+			if ip := net.ParseIP(h); ip != nil {
+				tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+			} else {
+				tmpl.DNSNames = append(tmpl.DNSNames, h)
+			}
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, a.Cert, &key.PublicKey, a.key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("issue certificate: %w", err)
+	}
+	return encodePair(der, key)
+}
+
+// ClientBundle は name のクライアント証明書を発行し、CA 証明書・クライアント証明書・秘密鍵を 1 つにまとめた PEM を返す。
+// リモートの CLI/TUI は LoadClientConfig でこの PEM を読み込んで接続する。
+// readOnly の場合は OU に protocol.RoleReadOnly を含め、デーモンは読み取り専用メソッドのみを許可する。
+func (a *Authority) ClientBundle(name string, readOnly bool) ([]byte, error) {
+	subject := pkix.Name{CommonName: name}
+	if readOnly {
+		subject.OrganizationalUnit = []string{protocol.RoleReadOnly}
+	}
+	certPEM, keyPEM, err := a.Issue(subject, nil)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Concat(a.CertPEM, certPEM, keyPEM), nil
+}
+
+// EnsureServerCert は dir のサーバー証明書を返す。証明書がない場合、期限が近い場合、
+// hosts を含まない場合は新しく発行して置き換える。
+func EnsureServerCert(a *Authority, dir string, hosts []string, now time.Time) (tls.Certificate, error) {
+	pair, err := tls.LoadX509KeyPair(filepath.Join(dir, serverCertFile), filepath.Join(dir, serverKeyFile))
+	if err == nil && !NeedsRenewal(pair.Leaf, now) && covers(pair.Leaf, hosts) {
+		return pair, nil
+	}
+	if len(hosts) == 0 {
+		hosts = []string{"localhost", "127.0.0.1"}
+	}
+	certPEM, keyPEM, err := a.Issue(pkix.Name{CommonName: "MolePort daemon"}, hosts)
+	if err != nil {
+		return tls.Certificate{}, err
+	}
+	if err := writePair(dir, serverCertFile, serverKeyFile, certPEM, keyPEM); err != nil {
+		return tls.Certificate{}, err
+	}
+	return tls.X509KeyPair(certPEM, keyPEM)
+}
+
+// NeedsRenewal は証明書の期限が now から renewBefore 以内に切れる場合に true を返す。
+func NeedsRenewal(cert *x509.Certificate, now time.Time) bool {
+	return cert == nil || now.Add(renewBefore).After(cert.NotAfter)
+}
+
+// covers は証明書が hosts の全てを SAN に含むかを返す。
+func covers(cert *x509.Certificate, hosts []string) bool {
+	for _, h := range hosts {
+		if cert.VerifyHostname(h) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func template(subject pkix.Name, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      subject,
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(validity),
+	}, nil
+}
+
+func encodePair(der []byte, key *ecdsa.PrivateKey) (certPEM, keyPEM []byte, err error) {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+func writePair(dir, certFile, keyFile string, certPEM, keyPEM []byte) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create tls dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, keyFile), keyPEM, 0600); err != nil {
+		return fmt.Errorf("write %s: %w", keyFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, certFile), certPEM, 0600); err != nil {
+		return fmt.Errorf("write %s: %w", certFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+// Package tlsipc は別のマシンの CLI/TUI からデーモンを操作するための、相互 TLS 認証付き TCP トランスポートを提供する。
+// 証明書は設定ディレクトリの tls/ に置くデーモン専用の CA が発行し、サーバー証明書は期限が近づくと自動で更新する。
+package tlsipc
@@ -0,0 +1,150 @@
+package tlsipc
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func echo(_ context.Context, _ string, _ string, params json.RawMessage) (any, *protocol.RPCError) {
+	return params, nil
+}
+
+// startTLSServer は Unix ソケットに加えて TLS リスナーで受け付ける IPCServer を起動し、TLS のアドレスを返す。
+func startTLSServer(t *testing.T, dir string) string {
+	t.Helper()
+	srv := ipc.NewIPCServer(filepath.Join(t.TempDir(), "test.sock"), echo)
+	if err := srv.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = srv.Stop() })
+	ln, err := Listen("127.0.0.1:0", dir, nil)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	srv.Serve(ln)
+	return ln.Addr().String()
+}
+
+// writeBundle は dir の CA で発行したクライアント用 PEM を書き出し、そのパスを返す。
+func writeBundle(t *testing.T, dir string, readOnly bool) string {
+	t.Helper()
+	ca, err := LoadOrCreateCA(dir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA() error = %v", err)
+	}
+	bundle, err := ca.ClientBundle("laptop", readOnly)
+	if err != nil {
+		t.Fatalf("ClientBundle() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "client.pem")
+	if err := os.WriteFile(path, bundle, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func call(t *testing.T, addr, bundle string) error {
+	t.Helper()
+	cfg, err := LoadClientConfig(bundle)
+	if err != nil {
+		t.Fatalf("LoadClientConfig() error = %v", err)
+	}
+	c := client.NewIPCClientWithDialer(Dialer(addr, cfg))
+	if err := c.Connect(); err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got map[string]string
+	if err := c.Call(ctx, "echo", map[string]string{"msg": "hi"}, &got); err != nil {
+		return err
+	}
+	if got["msg"] != "hi" {
+		t.Errorf("echo = %v", got)
+	}
+	return nil
+}
+
+func TestTLS_MutualAuth(t *testing.T) {
+	dir := t.TempDir()
+	addr := startTLSServer(t, dir)
+
+	if err := call(t, addr, writeBundle(t, dir, false)); err != nil {
+		t.Errorf("call with a client certificate from the daemon CA: %v", err)
+	}
+	if err := call(t, addr, writeBundle(t, t.TempDir(), false)); err == nil {
+		t.Error("a client certificate from another CA should be rejected")
+	}
+}
+
+func TestTLS_ReadOnlyCertificate(t *testing.T) {
+	dir := t.TempDir()
+	addr := startTLSServer(t, dir)
+
+	cfg, err := LoadClientConfig(writeBundle(t, dir, true))
+	if err != nil {
+		t.Fatalf("LoadClientConfig() error = %v", err)
+	}
+	c := client.NewIPCClientWithDialer(Dialer(addr, cfg))
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() { _ = c.Close() }()
+	if !c.IsReadOnly() {
+		t.Error("a client with a read-only certificate should be read-only")
+	}
+}
+
+func TestLoadOrCreateCA_Persists(t *testing.T) {
+	dir := t.TempDir()
+	first, err := LoadOrCreateCA(dir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA() error = %v", err)
+	}
+	second, err := LoadOrCreateCA(dir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA() error = %v", err)
+	}
+	if first.Cert.SerialNumber.Cmp(second.Cert.SerialNumber) != 0 {
+		t.Error("LoadOrCreateCA() should reuse the saved CA")
+	}
+	if info, err := os.Stat(filepath.Join(dir, caKeyFile)); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("ca key stat = %v, %v, want mode 0600", info, err)
+	}
+}
+
+func TestEnsureServerCert_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	ca, err := LoadOrCreateCA(dir)
+	if err != nil {
+		t.Fatalf("LoadOrCreateCA() error = %v", err)
+	}
+	now := time.Now()
+	cert, err := EnsureServerCert(ca, dir, []string{"jump.example.com"}, now)
+	if err != nil {
+		t.Fatalf("EnsureServerCert() error = %v", err)
+	}
+	serial := cert.Leaf.SerialNumber
+
+	same, _ := EnsureServerCert(ca, dir, []string{"jump.example.com"}, now)
+	if same.Leaf.SerialNumber.Cmp(serial) != 0 {
+		t.Error("a valid certificate should be reused")
+	}
+	renewed, _ := EnsureServerCert(ca, dir, []string{"jump.example.com"}, cert.Leaf.NotAfter.Add(-24*time.Hour))
+	if renewed.Leaf.SerialNumber.Cmp(serial) == 0 {
+		t.Error("a certificate close to expiry should be renewed")
+	}
+	moved, _ := EnsureServerCert(ca, dir, []string{"10.0.0.5"}, now)
+	if moved.Leaf.VerifyHostname("10.0.0.5") != nil {
+		t.Error("a certificate should be reissued when the hosts change")
+	}
+}
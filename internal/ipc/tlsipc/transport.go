@@ -0,0 +1,120 @@
+package tlsipc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// dialTimeout はリモートのデーモンへの TCP 接続のタイムアウト。
+const dialTimeout = 10 * time.Second
+
+// ServerConfig は dir の CA で発行した証明書を持つクライアントだけを受け付ける TLS 設定を返す。
+// サーバー証明書はハンドシェイクごとに期限を確認し、期限が近づいた時点で発行し直す。
+func ServerConfig(dir string, hosts []string) (*tls.Config, error) {
+	ca, err := LoadOrCreateCA(dir)
+	if err != nil {
+		return nil, err
+	}
+	r := &rotator{ca: ca, dir: dir, hosts: hosts}
+	if _, err := r.certificate(nil); err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	pool.AddCert(ca.Cert)
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS13,
+		ClientAuth:     tls.RequireAndVerifyClientCert,
+		ClientCAs:      pool,
+		GetCertificate: r.certificate,
+	}, nil
+}
+
+// Listen は addr で TCP をリッスンし、ServerConfig の設定で TLS を終端するリスナーを返す。
+func Listen(addr, dir string, hosts []string) (net.Listener, error) {
+	cfg, err := ServerConfig(dir, hosts)
+	if err != nil {
+		return nil, err
+	}
+	ln, err := tls.Listen("tcp", addr, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("listen tls: %w", err)
+	}
+	return ln, nil
+}
+
+// rotator は期限が近づいたサーバー証明書を発行し直す。
+type rotator struct {
+	ca    *Authority
+	dir   string
+	hosts []string
+
+	mu   sync.Mutex
+	cert *tls.Certificate
+}
+
+func (r *rotator) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	if r.cert != nil && !NeedsRenewal(r.cert.Leaf, now) {
+		return r.cert, nil
+	}
+	cert, err := EnsureServerCert(r.ca, r.dir, r.hosts, now)
+	if err != nil {
+		return nil, err
+	}
+	r.cert = &cert
+	return r.cert, nil
+}
+
+// LoadClientConfig は Authority.ClientBundle で作成した PEM ファイルを読み込み、クライアント用の TLS 設定を返す。
+func LoadClientConfig(bundlePath string) (*tls.Config, error) {
+	data, err := os.ReadFile(bundlePath)
+	if err != nil {
+		return nil, fmt.Errorf("read client bundle: %w", err)
+	}
+	pool := x509.NewCertPool()
+	var certPEM, keyPEM []byte
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		switch block.Type {
+		case "CERTIFICATE":
+			cert, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, fmt.Errorf("parse client bundle: %w", err)
+			}
+			if cert.IsCA {
+				pool.AddCert(cert)
+			} else {
+				certPEM = pem.EncodeToMemory(block)
+			}
+		case "EC PRIVATE KEY", "PRIVATE KEY":
+			keyPEM = pem.EncodeToMemory(block)
+		}
+	}
+	if certPEM == nil || keyPEM == nil {
+		return nil, errors.New("client bundle must contain a client certificate and its private key")
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("load client bundle: %w", err)
+	}
+	return &tls.Config{
+		MinVersion:   tls.VersionTLS13,
+		RootCAs:      pool,
+		Certificates: []tls.Certificate{pair},
+	}, nil
+}
+
+// Dialer は cfg で addr に TLS 接続する関数を返す。client.NewIPCClientWithDialer に渡して使う。
+func Dialer(addr string, cfg *tls.Config) func() (net.Conn, error) {
+	return func() (net.Conn, error) {
+		return tls.DialWithDialer(&net.Dialer{Timeout: dialTimeout}, "tcp", addr, cfg)
+	}
+}
@@ -0,0 +1,3 @@
+// Package wsbridge は EventBroker のイベント通知を WebSocket で中継する読み取り専用のエンドポイントを提供する。
+// 外部のダッシュボード（ウォールボード等）が JSON-RPC クライアントを実装せずにトンネルの状態を表示するためのもの。
+package wsbridge
@@ -0,0 +1,93 @@
+package wsbridge
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// WebSocket のオペコード（RFC 6455）。
+const (
+	opText  = 0x1
+	opClose = 0x8
+	opPing  = 0x9
+	opPong  = 0xa
+)
+
+// maxFramePayload はクライアントから受け付けるフレームのペイロードの上限。
+// ブリッジはクライアントからのデータを使わないため、制御フレームが読めれば十分である。
+const maxFramePayload = 64 * 1024
+
+// acceptGUID は Sec-WebSocket-Accept の計算に使う固定値（RFC 6455）。
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// frame は受信した WebSocket のフレーム。
+type frame struct {
+	op      byte
+	payload []byte
+}
+
+// acceptKey はクライアントの Sec-WebSocket-Key に対する Sec-WebSocket-Accept を返す。
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// appendFrame はサーバーから送信する（マスクしない）単一フレームを b に追加する。
+func appendFrame(b []byte, op byte, payload []byte) []byte {
+	b = append(b, 0x80|op)
+	switch n := len(payload); {
+	case n < 126:
+		b = append(b, byte(n))
+	case n <= 0xffff:
+		b = binary.BigEndian.AppendUint16(append(b, 126), uint16(n))
+	default:
+		b = binary.BigEndian.AppendUint64(append(b, 127), uint64(n))
+	}
+	return append(b, payload...)
+}
+
+// readFrame はクライアントから 1 フレームを読み取り、マスクを解除したペイロードを返す。
+func readFrame(r *bufio.Reader) (frame, error) {
+	var h [2]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		return frame{}, err
+	}
+	if h[1]&0x80 == 0 {
+		return frame{}, errors.New("websocket: unmasked client frame")
+	}
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return frame{}, err
+		}
+		n = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(r, ext[:]); err != nil {
+			return frame{}, err
+		}
+		n = binary.BigEndian.Uint64(ext[:])
+	}
+	if n > maxFramePayload {
+		return frame{}, fmt.Errorf("websocket: frame of %d bytes exceeds limit", n)
+	}
+	var mask [4]byte
+	if _, err := io.ReadFull(r, mask[:]); err != nil {
+		return frame{}, err
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return frame{}, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+	return frame{op: h[0] & 0x0f, payload: payload}, nil
+}
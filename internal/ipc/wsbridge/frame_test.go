@@ -0,0 +1,68 @@
+package wsbridge
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+// maskedFrame はクライアントが送信する、マスクしたフレームを返す。
+func maskedFrame(op byte, payload []byte) []byte {
+	b := appendFrame(nil, op, nil)[:1]
+	key := [4]byte{0x12, 0x34, 0x56, 0x78}
+	switch n := len(payload); {
+	case n < 126:
+		b = append(b, 0x80|byte(n))
+	default:
+		b = append(b, 0x80|126, byte(n>>8), byte(n))
+	}
+	b = append(b, key[:]...)
+	for i, c := range payload {
+		b = append(b, c^key[i%4])
+	}
+	return b
+}
+
+func TestAcceptKey(t *testing.T) {
+	// RFC 6455 1.3 の例
+	if got := acceptKey("dGhlIHNhbXBsZSBub25jZQ=="); got != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("acceptKey() = %q", got)
+	}
+}
+
+func TestAppendFrame_Lengths(t *testing.T) {
+	tests := []struct {
+		size       int
+		headerSize int
+	}{
+		{125, 2},
+		{126, 4},
+		{65535, 4},
+		{65536, 10},
+	}
+	for _, tt := range tests {
+		b := appendFrame(nil, opText, make([]byte, tt.size))
+		if b[0] != 0x80|opText || len(b) != tt.headerSize+tt.size {
+			t.Errorf("frame of %d bytes: header %#x, length %d, want header size %d", tt.size, b[0], len(b), tt.headerSize)
+		}
+	}
+}
+
+func TestReadFrame(t *testing.T) {
+	payload := bytes.Repeat([]byte("ping"), 100)
+	f, err := readFrame(bufio.NewReader(bytes.NewReader(maskedFrame(opPing, payload))))
+	if err != nil {
+		t.Fatalf("readFrame() error = %v", err)
+	}
+	if f.op != opPing || !bytes.Equal(f.payload, payload) {
+		t.Errorf("frame = %#x %q", f.op, f.payload)
+	}
+
+	if _, err := readFrame(bufio.NewReader(bytes.NewReader(appendFrame(nil, opText, []byte("x"))))); err == nil {
+		t.Error("readFrame() should reject unmasked client frames")
+	}
+	tooLarge := []byte{0x80 | opText, 0x80 | 127, 0, 0, 0, 0, 0, 0x10, 0, 0}
+	if _, err := readFrame(bufio.NewReader(bytes.NewReader(tooLarge))); err == nil {
+		t.Error("readFrame() should reject frames over the limit")
+	}
+}
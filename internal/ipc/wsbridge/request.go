@@ -0,0 +1,72 @@
+package wsbridge
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
+)
+
+// defaultTypes は types を指定しない接続が購読するイベント種別。
+var defaultTypes = []string{"ssh", "forward"}
+
+// isUpgrade は r が WebSocket へのアップグレード要求かを返す。
+func isUpgrade(r *http.Request) bool {
+	return r.Method == http.MethodGet &&
+		headerContains(r.Header, "Connection", "upgrade") &&
+		headerContains(r.Header, "Upgrade", "websocket") &&
+		r.Header.Get("Sec-WebSocket-Version") == "13" &&
+		r.Header.Get("Sec-WebSocket-Key") != ""
+}
+
+// headerContains はカンマ区切りのヘッダー name に token（大文字小文字を区別しない）が含まれるかを返す。
+func headerContains(h http.Header, name, token string) bool {
+	for _, v := range h.Values(name) {
+		for t := range strings.SplitSeq(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// allowOrigin はブラウザからの接続の Origin を許可するかを返す。
+func (s *Server) allowOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || slices.Contains(s.origins, "*") || slices.Contains(s.origins, origin) {
+		return true
+	}
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// parseQuery はクエリパラメータ types・hosts・rules（カンマ区切りまたは繰り返し）から購読の条件を返す。
+func parseQuery(q url.Values) ([]string, broker.Filter, error) {
+	types := splitValues(q["types"])
+	if len(types) == 0 {
+		types = defaultTypes
+	}
+	for _, t := range types {
+		if !broker.IsEventType(t) {
+			return nil, broker.Filter{}, fmt.Errorf("invalid event type: %s", t)
+		}
+	}
+	return types, broker.Filter{Hosts: splitValues(q["hosts"]), Rules: splitValues(q["rules"])}, nil
+}
+
+// splitValues はカンマ区切りの値を分割し、空の要素を除いて返す。
+func splitValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for e := range strings.SplitSeq(v, ",") {
+			if e = strings.TrimSpace(e); e != "" {
+				out = append(out, e)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,232 @@
+package wsbridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+const (
+	// Path はイベントを中継するエンドポイントのパス。
+	Path = "/events"
+	// ClientIDPrefix は WebSocket の接続に割り当てる EventBroker のクライアント ID の接頭辞。
+	ClientIDPrefix = "ws-"
+
+	// queueSize は接続ごとに送信を待つ通知の上限。溢れた接続は切断する。
+	queueSize = 256
+	// writeTimeout は 1 フレームの送信のタイムアウト。
+	writeTimeout = 10 * time.Second
+	// readHeaderTimeout は HTTP リクエストヘッダーの受信のタイムアウト。
+	readHeaderTimeout = 10 * time.Second
+)
+
+// Broker は WebSocket の接続ごとにイベントを購読する EventBroker。
+type Broker interface {
+	SubscribeSince(clientID string, types []string, filter broker.Filter, sinceID int64) (string, broker.Replay)
+	RemoveClient(clientID string)
+}
+
+// Server は EventBroker の通知を WebSocket で中継する HTTP サーバー。
+// 通知は EventBroker の送信関数から Send で受け取る。接続からのメッセージは制御フレーム以外を無視する。
+type Server struct {
+	broker  Broker
+	origins []string
+	ln      net.Listener
+	http    *http.Server
+	nextID  atomic.Int64
+
+	mu      sync.Mutex
+	clients map[string]*client
+}
+
+// client は WebSocket の接続。
+type client struct {
+	id    string
+	conn  net.Conn
+	queue chan protocol.Notification
+	done  chan struct{}
+	once  sync.Once
+	wmu   sync.Mutex
+}
+
+// Owns は clientID が WebSocket の接続に割り当てたクライアント ID かを返す。
+func Owns(clientID string) bool {
+	return strings.HasPrefix(clientID, ClientIDPrefix)
+}
+
+// Listen は addr の TCP で WebSocket の接続の受け付けを開始する。
+// origins はブラウザからの接続を許可する Origin（"*" は全て）。Origin のない接続とリクエスト先と同じホストの Origin は常に許可する。
+func Listen(addr string, b Broker, origins []string) (*Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen event bridge: %w", err)
+	}
+	s := &Server{broker: b, origins: origins, ln: ln, clients: make(map[string]*client)}
+	mux := http.NewServeMux()
+	mux.HandleFunc(Path, s.serveEvents)
+	s.http = &http.Server{Handler: mux, ReadHeaderTimeout: readHeaderTimeout}
+	go func() {
+		if err := s.http.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("event bridge stopped", "error", err)
+		}
+	}()
+	return s, nil
+}
+
+// Addr はリッスンしているアドレスを返す。
+func (s *Server) Addr() net.Addr {
+	return s.ln.Addr()
+}
+
+// Close は受け付けを停止し、全ての接続を切断する。
+func (s *Server) Close() error {
+	err := s.http.Close()
+	s.mu.Lock()
+	for _, c := range s.clients {
+		c.close()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+// Send は clientID の接続に通知を送信する。送信を待つ通知が溢れた接続は切断する。
+func (s *Server) Send(clientID string, notification protocol.Notification) error {
+	s.mu.Lock()
+	c, ok := s.clients[clientID]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("client %s not found", clientID)
+	}
+	select {
+	case c.queue <- notification:
+		return nil
+	case <-c.done:
+		return fmt.Errorf("client %s closed", clientID)
+	default:
+		slog.Warn("event bridge client is too slow, disconnecting", "client", clientID)
+		c.close()
+		return fmt.Errorf("client %s: queue full", clientID)
+	}
+}
+
+// serveEvents は WebSocket にアップグレードし、クエリパラメータの条件でイベントを購読して中継する。
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request) {
+	types, filter, err := parseQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !s.allowOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return
+	}
+	if !isUpgrade(r) {
+		w.Header().Set("Upgrade", "websocket")
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return
+	}
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return
+	}
+	conn, rw, err := hj.Hijack()
+	if err != nil {
+		slog.Debug("event bridge hijack failed", "error", err)
+		return
+	}
+	_, _ = rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+	if err := rw.Flush(); err != nil {
+		_ = conn.Close()
+		return
+	}
+
+	c := &client{
+		id:    fmt.Sprintf("%s%d", ClientIDPrefix, s.nextID.Add(1)),
+		conn:  conn,
+		queue: make(chan protocol.Notification, queueSize),
+		done:  make(chan struct{}),
+	}
+	s.mu.Lock()
+	s.clients[c.id] = c
+	s.mu.Unlock()
+	defer func() {
+		s.broker.RemoveClient(c.id)
+		s.mu.Lock()
+		delete(s.clients, c.id)
+		s.mu.Unlock()
+		c.close()
+	}()
+
+	s.broker.SubscribeSince(c.id, types, filter, 0)
+	slog.Debug("event bridge client connected", "client", c.id, "remote", conn.RemoteAddr().String(), "types", types)
+	go c.writeLoop()
+	c.readLoop(rw.Reader)
+}
+
+// writeLoop は通知を JSON のテキストフレームとして順に送信する。
+func (c *client) writeLoop() {
+	for {
+		select {
+		case <-c.done:
+			return
+		case n := <-c.queue:
+			data, err := json.Marshal(n)
+			if err != nil {
+				continue
+			}
+			if err := c.write(opText, data); err != nil {
+				c.close()
+				return
+			}
+		}
+	}
+}
+
+// readLoop はクライアントのフレームを読み、ping に応答する。close を受信するか接続が切れると戻る。
+func (c *client) readLoop(r *bufio.Reader) {
+	for {
+		f, err := readFrame(r)
+		if err != nil {
+			return
+		}
+		switch f.op {
+		case opClose:
+			_ = c.write(opClose, f.payload[:min(len(f.payload), 2)])
+			return
+		case opPing:
+			if err := c.write(opPong, f.payload); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// write は 1 フレームを送信する。
+func (c *client) write(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err := c.conn.Write(appendFrame(nil, op, payload))
+	return err
+}
+
+// close は接続を閉じる。複数回呼んでも安全。
+func (c *client) close() {
+	c.once.Do(func() {
+		close(c.done)
+		_ = c.conn.Close()
+	})
+}
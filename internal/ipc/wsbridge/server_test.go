@@ -0,0 +1,190 @@
+package wsbridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/ipc/broker"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// subscription は fakeBroker が受け取った購読。
+type subscription struct {
+	clientID string
+	types    []string
+	filter   broker.Filter
+}
+
+type fakeBroker struct {
+	subscribed chan subscription
+	removed    chan string
+}
+
+func newFakeBroker() *fakeBroker {
+	return &fakeBroker{subscribed: make(chan subscription, 1), removed: make(chan string, 1)}
+}
+
+func (b *fakeBroker) SubscribeSince(clientID string, types []string, filter broker.Filter, _ int64) (string, broker.Replay) {
+	select {
+	case b.subscribed <- subscription{clientID, types, filter}:
+	default:
+	}
+	return "sub-1", broker.Replay{}
+}
+
+func (b *fakeBroker) RemoveClient(clientID string) {
+	select {
+	case b.removed <- clientID:
+	default:
+	}
+}
+
+func startBridge(t *testing.T, b Broker, origins []string) *Server {
+	t.Helper()
+	s, err := Listen("127.0.0.1:0", b, origins)
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	t.Cleanup(func() { _ = s.Close() })
+	return s
+}
+
+// dial は query でブリッジに接続し、ハンドシェイクの応答と接続を返す。
+func dial(t *testing.T, s *Server, query string, header http.Header) (*http.Response, net.Conn, *bufio.Reader) {
+	t.Helper()
+	conn, err := net.Dial("tcp", s.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	_ = conn.SetDeadline(time.Now().Add(5 * time.Second))
+	req, _ := http.NewRequest(http.MethodGet, "http://"+s.Addr().String()+Path+query, nil)
+	req.Header = http.Header{
+		"Connection":            {"Upgrade"},
+		"Upgrade":               {"websocket"},
+		"Sec-Websocket-Version": {"13"},
+		"Sec-Websocket-Key":     {"dGhlIHNhbXBsZSBub25jZQ=="},
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if err := req.Write(conn); err != nil {
+		t.Fatal(err)
+	}
+	r := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(r, req)
+	if err != nil {
+		t.Fatalf("read handshake response: %v", err)
+	}
+	return resp, conn, r
+}
+
+// readServerFrame はサーバーが送信した（マスクしない）フレームを読み取る。
+func readServerFrame(t *testing.T, r *bufio.Reader) (byte, []byte) {
+	t.Helper()
+	var h [2]byte
+	if _, err := io.ReadFull(r, h[:]); err != nil {
+		t.Fatalf("read frame: %v", err)
+	}
+	n := int(h[1] & 0x7f)
+	if n == 126 {
+		var ext [2]byte
+		_, _ = io.ReadFull(r, ext[:])
+		n = int(ext[0])<<8 | int(ext[1])
+	}
+	payload := make([]byte, n)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		t.Fatalf("read payload: %v", err)
+	}
+	return h[0] & 0x0f, payload
+}
+
+func TestServer_RelaysEvents(t *testing.T) {
+	b := newFakeBroker()
+	s := startBridge(t, b, nil)
+	resp, conn, r := dial(t, s, "?types=ssh,forward&hosts=prod", nil)
+	if resp.StatusCode != http.StatusSwitchingProtocols || resp.Header.Get("Sec-Websocket-Accept") != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Fatalf("handshake = %s, accept %q", resp.Status, resp.Header.Get("Sec-Websocket-Accept"))
+	}
+
+	sub := <-b.subscribed
+	if !Owns(sub.clientID) || !slices.Equal(sub.types, []string{"ssh", "forward"}) || !slices.Equal(sub.filter.Hosts, []string{"prod"}) {
+		t.Fatalf("subscription = %+v", sub)
+	}
+
+	notif := protocol.Notification{JSONRPC: protocol.JSONRPCVersion, Method: protocol.EventSSH, Params: json.RawMessage(`{"type":"connected","host":"prod"}`)}
+	if err := s.Send(sub.clientID, notif); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	op, payload := readServerFrame(t, r)
+	var got protocol.Notification
+	if op != opText || json.Unmarshal(payload, &got) != nil || got.Method != protocol.EventSSH {
+		t.Fatalf("frame = %#x %s", op, payload)
+	}
+
+	if _, err := conn.Write(maskedFrame(opPing, []byte("hi"))); err != nil {
+		t.Fatal(err)
+	}
+	if op, payload := readServerFrame(t, r); op != opPong || string(payload) != "hi" {
+		t.Errorf("pong = %#x %q", op, payload)
+	}
+
+	if _, err := conn.Write(maskedFrame(opClose, []byte{0x03, 0xe8})); err != nil {
+		t.Fatal(err)
+	}
+	if op, _ := readServerFrame(t, r); op != opClose {
+		t.Errorf("close reply opcode = %#x", op)
+	}
+	select {
+	case id := <-b.removed:
+		if id != sub.clientID {
+			t.Errorf("removed %q, want %q", id, sub.clientID)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription was not removed after close")
+	}
+	if err := s.Send(sub.clientID, notif); err == nil {
+		t.Error("Send() to a closed client should fail")
+	}
+}
+
+func TestServer_DefaultTypes(t *testing.T) {
+	b := newFakeBroker()
+	s := startBridge(t, b, nil)
+	if resp, _, _ := dial(t, s, "", nil); resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("handshake = %s", resp.Status)
+	}
+	if sub := <-b.subscribed; !slices.Equal(sub.types, defaultTypes) {
+		t.Errorf("types = %v, want %v", sub.types, defaultTypes)
+	}
+}
+
+func TestServer_Rejects(t *testing.T) {
+	s := startBridge(t, newFakeBroker(), []string{"https://wallboard.example.com"})
+	tests := []struct {
+		name   string
+		query  string
+		header http.Header
+		want   int
+	}{
+		{"invalid type", "?types=ssh,bogus", nil, http.StatusBadRequest},
+		{"foreign origin", "", http.Header{"Origin": {"https://evil.example.com"}}, http.StatusForbidden},
+		{"allowed origin", "", http.Header{"Origin": {"https://wallboard.example.com"}}, http.StatusSwitchingProtocols},
+		{"same host origin", "", http.Header{"Origin": {"http://" + s.Addr().String()}}, http.StatusSwitchingProtocols},
+		{"not an upgrade", "", http.Header{"Upgrade": {"h2c"}}, http.StatusUpgradeRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, _, _ := dial(t, s, tt.query, tt.header)
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
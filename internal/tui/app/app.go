@@ -1,24 +1,24 @@
 package app
 
 import (
-	"time"
-
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/client"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 	"github.com/ousiassllc/moleport/internal/tui/molecules"
+	"github.com/ousiassllc/moleport/internal/tui/notify"
+	"github.com/ousiassllc/moleport/internal/tui/organisms/helpmodal"
 	"github.com/ousiassllc/moleport/internal/tui/pages"
+	"github.com/ousiassllc/moleport/internal/tui/pages/configeditor"
+	"github.com/ousiassllc/moleport/internal/tui/pages/onboarding"
 )
 
 // DaemonManager はデーモンの起動・接続を抽象化するインターフェース。
 // tui/app パッケージが daemon パッケージに直接依存しないようにする。
-type DaemonManager interface {
-	StartDaemonProcess(configDir string) (int, error)
-	EnsureDaemonWithRetry(configDir string, maxWait time.Duration) (*client.IPCClient, error)
-}
+type DaemonManager = ipccmd.DaemonManager
 
 // dialogState はダイアログ関連の状態をグループ化する。
 type dialogState struct {
@@ -26,18 +26,24 @@ type dialogState struct {
 	showVersionConfirm bool
 	restarting         bool
 
-	updateNotifyDialog molecules.InfoDialog
-	showUpdateNotify   bool
-	pendingUpdateCheck *tui.UpdateCheckDoneMsg
+	updateNotice notify.UpdateNotice
 
-	showHelpModal bool
+	// help は表示中のヘルプモーダル。nil の場合は表示しない。
+	help *helpmodal.Modal
+
+	importConfirm     molecules.ConfirmDialog
+	showImportConfirm bool
+	importKeys        []string
+	importPrompted    bool
 }
 
 // pageState はページ遷移関連の状態をグループ化する。
 type pageState struct {
-	currentPage      string // "dashboard" | "theme" | "lang"
+	currentPage      string // "dashboard" | "theme" | "lang" | "config" | "onboarding"
 	themePage        pages.ThemePage
 	langPage         pages.LangPage
+	configPage       configeditor.Page
+	onboarding       onboarding.Page
 	currentPresetID  string
 	previousPresetID string
 	currentLang      string
@@ -52,7 +58,9 @@ type MainModel struct {
 	keys           tui.KeyMap
 	hosts          []core.SSHHost
 	sessions       []core.ForwardSession
+	notices        notify.Notifier
 	quitting       bool
+	readOnly       bool
 	subscriptionID string
 	version        string
 	configDir      string
@@ -61,6 +69,10 @@ type MainModel struct {
 	credRequest    *protocol.CredentialRequestNotification
 	credResponseCh chan<- *protocol.CredentialResponseParams
 
+	// hostRev・sessionRev は手元のホスト・セッション一覧のリビジョン。再取得の際はこれ以降の差分のみを取得する。
+	hostRev    uint64
+	sessionRev uint64
+
 	dialog dialogState
 	page   pageState
 
@@ -70,12 +82,19 @@ type MainModel struct {
 
 // NewMainModel は新しい MainModel を生成する。
 func NewMainModel(client *client.IPCClient, version string, configDir string) MainModel {
+	dashboard := pages.NewDashboardPage(version)
+	profile := protocol.DefaultProfile
+	if client != nil && client.Profile() != "" {
+		profile = client.Profile()
+	}
+	dashboard.SetProfile(profile)
 	return MainModel{
-		dashboard: pages.NewDashboardPage(version),
+		dashboard: dashboard,
 		client:    client,
 		version:   version,
 		configDir: configDir,
 		keys:      tui.DefaultKeyMap(),
+		notices:   notify.New(),
 		page:      pageState{currentPage: pageDashboard},
 	}
 }
@@ -86,17 +105,32 @@ func (m *MainModel) SetDaemonManager(dm DaemonManager) {
 	m.daemonMgr = dm
 }
 
+// SetRemote はリモートのデーモンに接続しているかを設定し、ステータスバーに表示する。
+func (m *MainModel) SetRemote(remote bool) {
+	m.dashboard.SetDaemonRemote(remote)
+}
+
+// SetReadOnly は閲覧専用モードを設定する。
+// IPC クライアントを読み取り専用スコープに切り替え、状態を変更する UI 操作を無効にする。
+func (m *MainModel) SetReadOnly(readOnly bool) {
+	m.readOnly = readOnly
+	if m.client != nil {
+		m.client.SetReadOnly(readOnly)
+	}
+	m.dashboard.SetReadOnly(readOnly)
+}
+
 // Init は Bubble Tea の Init メソッド。初期読み込みコマンドを返す。
 func (m MainModel) Init() tea.Cmd {
 	return tea.Batch(
-		m.loadHosts(),
-		m.loadSessions(),
-		m.subscribeEvents(),
+		ipccmd.LoadHosts(m.client),
+		ipccmd.LoadSessions(m.client),
+		ipccmd.SubscribeEvents(m.client),
 		m.metricsTick(),
 		m.dashboard.Init(),
-		m.loadConfig(),
-		m.checkDaemonVersion(),
-		m.checkLatestVersion(),
+		ipccmd.LoadConfig(m.client),
+		ipccmd.CheckDaemonVersion(m.client, m.version),
+		ipccmd.CheckLatestVersion(m.client, m.version),
 	)
 }
 
@@ -123,6 +157,11 @@ func (m MainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return model, cmd
 	}
 
+	// 5. 設定エディタメッセージ
+	if model, cmd, handled := m.handleConfigEditorMsg(msg); handled {
+		return model, cmd
+	}
+
 	// 未処理のメッセージはダッシュボードに転送
 	var dashCmd tea.Cmd
 	m.dashboard, dashCmd = m.dashboard.Update(msg)
@@ -134,20 +173,30 @@ func (m MainModel) View() string {
 	if m.quitting {
 		return i18n.T("tui.log.quitting") + "\n"
 	}
-	if m.dialog.showHelpModal {
+	if m.dialog.help != nil {
 		return m.renderHelpOverlay()
 	}
+	if m.notices.HistoryVisible() {
+		return m.notices.HistoryOverlay(m.width, m.height)
+	}
 	if m.dialog.showVersionConfirm {
 		return m.renderVersionConfirmOverlay()
 	}
-	if m.dialog.showUpdateNotify {
-		return m.renderUpdateNotifyOverlay()
+	if m.dialog.showImportConfirm {
+		return m.renderImportConfirmOverlay()
 	}
-	if m.page.currentPage == pageTheme {
-		return m.page.themePage.View()
+	if m.dialog.updateNotice.Visible() {
+		return m.dialog.updateNotice.View(m.width, m.height)
 	}
-	if m.page.currentPage == pageLang {
+	switch m.page.currentPage {
+	case pageTheme:
+		return m.page.themePage.View()
+	case pageLang:
 		return m.page.langPage.View()
+	case pageConfig:
+		return m.page.configPage.View()
+	case pageOnboarding:
+		return m.page.onboarding.View()
 	}
-	return m.dashboard.View()
+	return m.notices.Overlay(m.dashboard.View(), m.width)
 }
@@ -0,0 +1,45 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
+	"github.com/ousiassllc/moleport/internal/tui/pages/configeditor"
+)
+
+// handleConfigEditorMsg は設定エディタ関連のメッセージを処理する。
+// 処理した場合は handled=true を返す。
+func (m MainModel) handleConfigEditorMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tui.ConfigEditorLoadedMsg:
+		var cmd tea.Cmd
+		m.page.configPage, cmd = m.page.configPage.Update(msg)
+		return m, cmd, true
+
+	case tui.ConfigApplyRequestMsg:
+		return m, ipccmd.ApplyConfig(m.client, msg.Changes), true
+
+	case tui.ConfigAppliedMsg:
+		var cmd tea.Cmd
+		m.page.configPage, cmd = m.page.configPage.Update(msg)
+		if msg.Err != nil {
+			return m, tea.Batch(cmd, m.report(i18n.T("tui.config.apply_error", map[string]any{"Error": msg.Err}), tui.LogError)), true
+		}
+		// 言語・テーマ・時刻表示形式の変更を TUI に反映するため設定を読み直す
+		return m, tea.Batch(cmd, m.report(i18n.T("tui.config.applied"), tui.LogSuccess), ipccmd.LoadConfig(m.client)), true
+
+	case tui.ConfigEditorClosedMsg:
+		m.page.currentPage = pageDashboard
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// openConfigPage は設定エディタページを開き、スキーマと現在の設定値を読み込む。
+func (m *MainModel) openConfigPage() tea.Cmd {
+	m.page.configPage = configeditor.New()
+	m.page.configPage.SetSize(m.width, m.height)
+	m.page.currentPage = pageConfig
+	return ipccmd.LoadConfigEditor(m.client)
+}
@@ -2,11 +2,13 @@ package app
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 )
 
 func updModel(m MainModel, msg tea.Msg) MainModel {
@@ -17,13 +19,27 @@ func updModel(m MainModel, msg tea.Msg) MainModel {
 func keyMsg(r rune) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}} }
 
 func TestHandleKeyMsg_Help(t *testing.T) {
-	if !updModel(newTestModel("1.0.0"), keyMsg('?')).dialog.showHelpModal {
-		t.Error("showHelpModal should be true")
+	m := updModel(newTestModel("1.0.0"), keyMsg('?'))
+	if m.dialog.help == nil {
+		t.Fatal("'?' should open the help modal")
 	}
+	if m = updModel(m, keyMsg('a')); m.dialog.help == nil {
+		t.Error("help modal should stay open on other keys")
+	}
+	result, cmd := m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc should close the help modal")
+	}
+	if updModel(result.(MainModel), cmd()).dialog.help != nil {
+		t.Error("help modal should be closed after Esc")
+	}
+}
+
+func TestHandleKeyMsg_QuitWithActiveForwards(t *testing.T) {
 	m := newTestModel("1.0.0")
-	m.dialog.showHelpModal = true
-	if updModel(m, keyMsg('a')).dialog.showHelpModal {
-		t.Error("showHelpModal should be false after any key")
+	m.dashboard.SetForwardSessions([]core.ForwardSession{{Status: core.Active}})
+	if u := updModel(m, keyMsg('q')); u.quitting || !u.dashboard.IsInputActive() {
+		t.Error("q with active forwards should ask for confirmation")
 	}
 }
 
@@ -44,6 +60,16 @@ func TestHandleKeyMsg_ThemeAndLang(t *testing.T) {
 	}
 }
 
+func TestHandleKeyMsg_Logs(t *testing.T) {
+	result, cmd := newReadOnlyModel().Update(keyMsg('L'))
+	if !result.(MainModel).dashboard.LogViewerOpen() || cmd == nil {
+		t.Error("'L' should open the log viewer and subscribe to logs, also in read-only mode")
+	}
+	if _, cmd = newTestModel("1.0.0").Update(tui.LogsUnsubscribeMsg{SubscriptionID: "logs-1"}); cmd == nil {
+		t.Error("LogsUnsubscribeMsg should unsubscribe")
+	}
+}
+
 func TestHandleIPCMsg_HostsLoaded(t *testing.T) {
 	u := updModel(newTestModel("1.0.0"), tui.HostsLoadedMsg{Err: fmt.Errorf("timeout")})
 	if got := u.dashboard.LogLineCount(); got != 1 {
@@ -66,8 +92,23 @@ func TestHandleIPCMsg_HostsReloaded(t *testing.T) {
 	}
 }
 
+func TestHandleIPCMsg_HostChanges(t *testing.T) {
+	m := updModel(newTestModel("1.0.0"), tui.HostsLoadedMsg{Hosts: []core.SSHHost{{Name: "prod"}, {Name: "staging"}}, Revision: 2})
+	u := updModel(m, ipccmd.HostChangesMsg{Revision: 4, Hosts: []core.SSHHost{{Name: "dev"}}, Removed: []string{"staging"}})
+	if u.hostRev != 4 {
+		t.Errorf("hostRev = %d, want 4", u.hostRev)
+	}
+	if len(u.hosts) != 2 || u.hosts[0].Name != "prod" || u.hosts[1].Name != "dev" {
+		t.Errorf("hosts = %+v, want prod and dev", u.hosts)
+	}
+
+	if got := updModel(u, ipccmd.HostChangesMsg{Err: fmt.Errorf("err")}); len(got.hosts) != 2 || got.dashboard.LogLineCount() == u.dashboard.LogLineCount() {
+		t.Error("HostChangesMsg with an error should keep the hosts and log the error")
+	}
+}
+
 func TestHandleIPCMsg_SessionsLoaded(t *testing.T) {
-	u := updModel(newTestModel("1.0.0"), sessionsLoadedMsg{
+	u := updModel(newTestModel("1.0.0"), ipccmd.SessionsLoadedMsg{
 		Sessions: []core.ForwardSession{{ID: "s1", Rule: core.ForwardRule{Name: "web"}}},
 	})
 	if len(u.sessions) != 1 || u.sessions[0].ID != "s1" {
@@ -75,6 +116,30 @@ func TestHandleIPCMsg_SessionsLoaded(t *testing.T) {
 	}
 }
 
+func TestHandleIPCMsg_SessionChanges(t *testing.T) {
+	rule := core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080}
+	m := updModel(newTestModel("1.0.0"), ipccmd.SessionsLoadedMsg{Sessions: []core.ForwardSession{{Rule: rule}}, Revision: 3})
+	if m.sessionRev != 3 {
+		t.Fatalf("sessionRev = %d, want 3", m.sessionRev)
+	}
+
+	u := updModel(m, ipccmd.SessionChangesMsg{
+		Revision: 5,
+		Sessions: []core.ForwardSession{{Rule: core.ForwardRule{Name: "db"}, Status: core.Active}},
+	})
+	if u.sessionRev != 5 {
+		t.Errorf("sessionRev = %d, want 5", u.sessionRev)
+	}
+	if len(u.sessions) != 2 || !reflect.DeepEqual(u.sessions[0].Rule, rule) || u.sessions[1].Rule.Name != "db" {
+		t.Errorf("sessions = %+v, want web kept and db appended", u.sessions)
+	}
+
+	u = updModel(u, ipccmd.SessionChangesMsg{Revision: 6, Removed: []string{"web", "db"}})
+	if len(u.sessions) != 0 {
+		t.Errorf("sessions = %+v, want all removed", u.sessions)
+	}
+}
+
 func TestHandleIPCMsg_MetricsTick_ReturnsCmd(t *testing.T) {
 	if _, cmd := newTestModel("1.0.0").Update(tui.MetricsTickMsg{}); cmd == nil {
 		t.Error("MetricsTickMsg should return commands")
@@ -92,6 +157,16 @@ func TestHandleForwardMsg_LogOutput(t *testing.T) {
 	}
 }
 
+func TestHandleForwardMsg_Added(t *testing.T) {
+	m, cmd := newTestModel("1.0.0").Update(tui.ForwardAddedMsg{Name: "prod-local-8080", Started: true})
+	if got := m.(MainModel).dashboard.LogLineCount(); got != 1 {
+		t.Errorf("LogLineCount() = %d, want 1", got)
+	}
+	if cmd == nil {
+		t.Error("ForwardAddedMsg should reload the sessions")
+	}
+}
+
 func TestWindowSizeMsg(t *testing.T) {
 	u := updModel(newTestModel("1.0.0"), tea.WindowSizeMsg{Width: 120, Height: 40})
 	if u.width != 120 || u.height != 40 {
@@ -102,8 +177,26 @@ func TestWindowSizeMsg(t *testing.T) {
 func TestView_HelpModal(t *testing.T) {
 	m := newTestModel("1.0.0")
 	m.width, m.height = 80, 24
-	m.dialog.showHelpModal = true
+	m = updModel(m, keyMsg('?'))
 	if m.View() == "" {
 		t.Error("should render help overlay")
 	}
 }
+
+func TestLogOutputMsgNotDuplicated(t *testing.T) {
+	m := newTestModel("test")
+	result, _ := m.Update(tui.LogOutputMsg{Text: "テストメッセージ"})
+	if got := result.(MainModel).dashboard.LogLineCount(); got != 1 {
+		t.Errorf("LogLineCount() = %d, want 1", got)
+	}
+}
+
+func TestMainModel_ConfigEditorOpenAndClose(t *testing.T) {
+	u := updModel(newTestModel("test"), keyMsg('c'))
+	if u.page.currentPage != pageConfig {
+		t.Fatalf("currentPage = %q, want config", u.page.currentPage)
+	}
+	if u = updModel(u, tui.ConfigEditorClosedMsg{}); u.page.currentPage != pageDashboard {
+		t.Errorf("currentPage = %q, want dashboard after close", u.page.currentPage)
+	}
+}
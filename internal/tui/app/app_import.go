@@ -0,0 +1,76 @@
+package app
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+// maybeLoadImportCandidates はセッション中に一度だけ ssh_config のフォワード候補を問い合わせる。
+// 閲覧専用モードではルールを追加できないため問い合わせない。
+func (m *MainModel) maybeLoadImportCandidates() tea.Cmd {
+	if m.readOnly || m.client == nil || m.dialog.importPrompted {
+		return nil
+	}
+	m.dialog.importPrompted = true
+	return ipccmd.LoadImportCandidates(m.client)
+}
+
+// handleImportCandidates は取り込み候補があれば確認ダイアログを表示する。
+// 他のダイアログ表示中は次回起動時に改めて確認する。
+func (m MainModel) handleImportCandidates(msg ipccmd.ImportCandidatesMsg) (MainModel, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.report(i18n.T("tui.log.import_error", map[string]any{"Error": msg.Err}), tui.LogError)
+	}
+	if len(msg.Forwards) == 0 || m.dialog.showVersionConfirm || m.dialog.updateNotice.Visible() || m.dialog.restarting {
+		return m, nil
+	}
+
+	keys := make([]string, len(msg.Forwards))
+	names := make([]string, len(msg.Forwards))
+	for i, f := range msg.Forwards {
+		keys[i] = f.ImportKey
+		names[i] = f.Name
+	}
+	message := i18n.T("tui.log.import_prompt", map[string]any{"Count": len(keys), "Names": strings.Join(names, ", ")})
+	m.dialog.importConfirm = molecules.NewConfirmDialog(message)
+	m.dialog.importKeys = keys
+	m.dialog.showImportConfirm = true
+	return m, nil
+}
+
+// handleImportConfirmResult は取り込み確認の結果に応じて取り込みまたは見送りを記録する。
+func (m MainModel) handleImportConfirmResult(confirmed bool) (MainModel, tea.Cmd) {
+	m.dialog.showImportConfirm = false
+	keys := m.dialog.importKeys
+	m.dialog.importKeys = nil
+	return m, ipccmd.ImportForwards(m.client, keys, !confirmed)
+}
+
+// handleForwardsImported は取り込み結果をログに出し、取り込んだ場合はセッション一覧を再取得する。
+func (m MainModel) handleForwardsImported(msg ipccmd.ForwardsImportedMsg) (MainModel, tea.Cmd) {
+	switch {
+	case msg.Err != nil:
+		return m, m.report(i18n.T("tui.log.import_error", map[string]any{"Error": msg.Err}), tui.LogError)
+	case msg.Ignored:
+		m.dashboard.AppendLog(i18n.T("tui.log.imports_ignored", map[string]any{"Count": msg.Count}), tui.LogInfo)
+		return m, nil
+	}
+	return m, tea.Batch(
+		m.report(i18n.T("tui.log.forwards_imported", map[string]any{"Count": msg.Count}), tui.LogSuccess),
+		ipccmd.LoadSessions(m.client),
+	)
+}
+
+// renderImportConfirmOverlay は取り込み確認ダイアログのオーバーレイを描画する。
+func (m MainModel) renderImportConfirmOverlay() string {
+	return lipgloss.Place(m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		m.dialog.importConfirm.View(),
+	)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+var testImportCandidates = ipccmd.ImportCandidatesMsg{Forwards: []protocol.ForwardInfo{
+	{Name: "prod-local-8080", Host: "prod", ImportKey: "prod/LocalForward/8080 db:5432"},
+}}
+
+func TestImportCandidates_ShowsConfirm(t *testing.T) {
+	u := updModel(newTestModel("1.0.0"), testImportCandidates)
+	if !u.dialog.showImportConfirm {
+		t.Fatal("showImportConfirm should be true")
+	}
+	if len(u.dialog.importKeys) != 1 || u.dialog.importKeys[0] != "prod/LocalForward/8080 db:5432" {
+		t.Errorf("importKeys = %v", u.dialog.importKeys)
+	}
+}
+
+func TestImportCandidates_EmptyOrBusy(t *testing.T) {
+	if updModel(newTestModel("1.0.0"), ipccmd.ImportCandidatesMsg{}).dialog.showImportConfirm {
+		t.Error("no candidates should not show confirm")
+	}
+	m := newTestModel("1.0.0")
+	m.dialog.showVersionConfirm = true
+	if updModel(m, testImportCandidates).dialog.showImportConfirm {
+		t.Error("import confirm should not stack on version confirm")
+	}
+}
+
+func TestImportConfirmResult_ClosesDialog(t *testing.T) {
+	m := updModel(newTestModel("1.0.0"), testImportCandidates)
+	result, cmd := m.Update(molecules.ConfirmResultMsg{Confirmed: false})
+	u := result.(MainModel)
+	if u.dialog.showImportConfirm || u.dialog.importKeys != nil {
+		t.Error("dialog state should be cleared")
+	}
+	if cmd == nil {
+		t.Error("declining should record ignored imports")
+	}
+}
+
+func TestForwardsImported_Logs(t *testing.T) {
+	tests := []struct {
+		name string
+		msg  ipccmd.ForwardsImportedMsg
+	}{
+		{"error", ipccmd.ForwardsImportedMsg{Err: errors.New("boom")}},
+		{"ignored", ipccmd.ForwardsImportedMsg{Count: 1, Ignored: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := updModel(newTestModel("1.0.0"), tt.msg).dashboard.LogLineCount(); got != 1 {
+				t.Errorf("LogLineCount() = %d, want 1", got)
+			}
+		})
+	}
+}
+
+func TestReadOnly_HostsLoadedSkipsImportPrompt(t *testing.T) {
+	u := updModel(newReadOnlyModel(), tui.HostsLoadedMsg{Hosts: []core.SSHHost{{Name: "prod"}}})
+	if u.dialog.importPrompted {
+		t.Error("read-only mode should not query import candidates")
+	}
+}
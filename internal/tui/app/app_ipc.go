@@ -1,104 +1,23 @@
 package app
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
 	"github.com/ousiassllc/moleport/internal/tui"
-	"github.com/ousiassllc/moleport/internal/tui/theme"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 )
 
-const (
-	// metricsInterval はメトリクス更新の間隔。
-	metricsInterval = 2 * time.Second
-	// ipcReadTimeout は IPC 読み取り系操作のタイムアウト。
-	ipcReadTimeout = 5 * time.Second
-	// ipcWriteTimeout は IPC 書き込み系操作のタイムアウト。
-	ipcWriteTimeout = 10 * time.Second
-	// ipcCredentialTimeout はクレデンシャル待ちを含む操作のタイムアウト。
-	// サーバー側の core.CredentialTimeout に IPC オーバーヘッド分のバッファを加算。
-	ipcCredentialTimeout = core.CredentialTimeout + 10*time.Second
-	// ipcShutdownTimeout はシャットダウン操作のタイムアウト。
-	ipcShutdownTimeout = 2 * time.Second
-)
-
-// --- 内部メッセージ型 ---
-
-type sessionsLoadedMsg struct {
-	Sessions []core.ForwardSession
-}
-
-type subscriptionStartedMsg struct {
-	SubscriptionID string
-}
-
-// --- IPC 操作 ---
-
-// loadHosts は host.list を呼んでホスト一覧を取得する。
-func (m *MainModel) loadHosts() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), ipcReadTimeout)
-		defer cancel()
-		var result protocol.HostListResult
-		if err := m.client.Call(ctx, "host.list", nil, &result); err != nil {
-			return tui.HostsLoadedMsg{Err: err}
-		}
-		hosts := make([]core.SSHHost, len(result.Hosts))
-		for i, h := range result.Hosts {
-			hosts[i] = hostInfoToSSHHost(h)
-		}
-		return tui.HostsLoadedMsg{Hosts: hosts}
-	}
-}
-
-// loadSessions は session.list を呼んでセッション一覧を取得する。
-func (m *MainModel) loadSessions() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), ipcReadTimeout)
-		defer cancel()
-		var result protocol.SessionListResult
-		if err := m.client.Call(ctx, "session.list", nil, &result); err != nil {
-			return tui.LogOutputMsg{Text: i18n.T("tui.log.session_error", map[string]any{"Error": err}), Level: tui.LogError}
-		}
-		sessions := make([]core.ForwardSession, len(result.Sessions))
-		for i, s := range result.Sessions {
-			sessions[i] = sessionInfoToForwardSession(s)
-		}
-		return sessionsLoadedMsg{Sessions: sessions}
-	}
-}
-
-// subscribeEvents はイベント購読を開始する。
-func (m *MainModel) subscribeEvents() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), ipcReadTimeout)
-		defer cancel()
-		subID, err := m.client.Subscribe(ctx, []string{"ssh", "forward"})
-		if err != nil {
-			return tui.LogOutputMsg{Text: i18n.T("tui.log.subscribe_error", map[string]any{"Error": err}), Level: tui.LogError}
-		}
-		return subscriptionStartedMsg{SubscriptionID: subID}
-	}
-}
-
-// listenIPCEvents は IPC イベントチャネルから次の通知を受信する。
-func (m *MainModel) listenIPCEvents() tea.Cmd {
-	events := m.client.Events()
-	return func() tea.Msg {
-		notif, ok := <-events
-		if !ok {
-			return tui.IPCDisconnectedMsg{}
-		}
-		return tui.IPCNotificationMsg{Notification: notif}
-	}
-}
+// metricsInterval はメトリクス更新の間隔。
+const metricsInterval = 2 * time.Second
 
 func (m *MainModel) metricsTick() tea.Cmd {
 	return tea.Tick(metricsInterval, func(time.Time) tea.Msg {
@@ -106,72 +25,77 @@ func (m *MainModel) metricsTick() tea.Cmd {
 	})
 }
 
-// loadConfig は config.get を呼んでテーマ設定を取得する。
-func (m *MainModel) loadConfig() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), ipcReadTimeout)
-		defer cancel()
-		var result protocol.ConfigGetResult
-		if err := m.client.Call(ctx, "config.get", nil, &result); err != nil {
-			return tui.ConfigLoadedMsg{Err: err}
-		}
-		return tui.ConfigLoadedMsg{
-			ThemeBase:   result.TUI.Theme.Base,
-			ThemeAccent: result.TUI.Theme.Accent,
-			Language:    result.Language,
-		}
-	}
-}
-
-// saveTheme は config.update でテーマ設定を保存する。
-func (m *MainModel) saveTheme(presetID string) tea.Cmd {
-	return func() tea.Msg {
-		p, ok := theme.FindPreset(presetID)
-		if !ok {
-			return tui.ThemeSavedMsg{Err: fmt.Errorf("unknown preset: %s", presetID)}
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), ipcWriteTimeout)
-		defer cancel()
-		base := p.Base
-		accent := p.Accent
-		params := protocol.ConfigUpdateParams{
-			TUI: &protocol.TUIUpdateInfo{
-				Theme: &protocol.ThemeUpdateInfo{
-					Base:   &base,
-					Accent: &accent,
-				},
-			},
-		}
-		var result protocol.ConfigUpdateResult
-		if err := m.client.Call(ctx, "config.update", params, &result); err != nil {
-			return tui.ThemeSavedMsg{Err: err}
-		}
-		return tui.ThemeSavedMsg{}
-	}
-}
-
 // --- IPC 通知ハンドリング ---
 
-func (m *MainModel) handleIPCNotification(notif *protocol.Notification) {
+// handleIPCNotification はイベント通知をダッシュボードに反映する。
+// 利用者の対応が必要なイベント（エラー・再接続・デーモン停止・自動開始の失敗）はトーストでも通知する。
+func (m *MainModel) handleIPCNotification(notif *protocol.Notification) tea.Cmd {
 	switch notif.Method {
 	case protocol.EventSSH:
 		var evt protocol.SSHEventNotification
 		if err := json.Unmarshal(notif.Params, &evt); err != nil {
 			slog.Warn("failed to unmarshal notification", "method", notif.Method, "error", err)
-			return
+			return nil
 		}
-		state := protocol.ParseConnectionState(evt.Type)
+		state := convert.ParseConnectionState(evt.Type)
 		m.dashboard.UpdateHostState(evt.Host, state)
 		if evt.Error != "" {
 			m.dashboard.AppendLog(fmt.Sprintf("SSH [%s] %s: %s", evt.Host, evt.Type, evt.Error), tui.LogInfo)
 		}
+		return m.notices.SSH(evt)
 	case protocol.EventForward:
 		var evt protocol.ForwardEventNotification
 		if err := json.Unmarshal(notif.Params, &evt); err != nil {
 			slog.Warn("failed to unmarshal notification", "method", notif.Method, "error", err)
-			return
+			return nil
 		}
 		m.dashboard.AppendLog(fmt.Sprintf("Forward [%s] %s", evt.Name, evt.Type), tui.LogInfo)
-		// セッション一覧は次の metricsTick で再読み込みされる
+		// セッション一覧の変化は次の metricsTick で差分として取得する
+		return m.notices.Forward(evt)
+	case protocol.EventHost:
+		var evt protocol.HostEventNotification
+		if err := json.Unmarshal(notif.Params, &evt); err != nil {
+			slog.Warn("failed to unmarshal notification", "method", notif.Method, "error", err)
+			return nil
+		}
+		if evt.Type == protocol.HostEventTypeChanged {
+			m.dashboard.AppendLog(i18n.T("tui.log.hosts_changed", map[string]any{"Added": len(evt.Added), "Removed": len(evt.Removed)}), tui.LogInfo)
+			return ipccmd.LoadHostChanges(m.client, m.hostRev)
+		}
+		m.dashboard.UpdateHostHealth(evt.Host, core.HostHealth(evt.Health))
+	case protocol.EventLog:
+		var rec protocol.LogRecord
+		if err := json.Unmarshal(notif.Params, &rec); err != nil {
+			slog.Warn("failed to unmarshal notification", "method", notif.Method, "error", err)
+			return nil
+		}
+		m.dashboard.AppendDaemonLog(rec)
+	case protocol.EventConfig:
+		var evt protocol.ConfigEventNotification
+		if err := json.Unmarshal(notif.Params, &evt); err == nil {
+			m.dashboard.AppendLog(i18n.T("tui.log.config_changed", map[string]any{"Sections": strings.Join(evt.Sections, ", ")}), tui.LogInfo)
+		}
+		return ipccmd.LoadConfig(m.client)
+	case protocol.EventDaemon:
+		var evt protocol.DaemonEventNotification
+		if err := json.Unmarshal(notif.Params, &evt); err != nil {
+			slog.Warn("failed to unmarshal notification", "method", notif.Method, "error", err)
+			return nil
+		}
+		switch p := evt.AutoStart; {
+		case evt.Type == protocol.DaemonEventTypeShuttingDown:
+			m.dashboard.AppendLog(i18n.T("tui.notifications.daemon_shutting_down"), tui.LogError)
+		case evt.Type == protocol.DaemonEventTypeAutoStart && p != nil:
+			m.dashboard.AppendLog(fmt.Sprintf("AutoStart [%s] %s (#%d) %s", p.Rule, p.Status, p.Attempt, p.Error), tui.LogInfo)
+		}
+		return m.notices.Daemon(evt)
 	}
+	return nil
+}
+
+// report は text をログパネルに出力し、エラーと成功はトーストでも通知する。
+// 操作の結果がログパネルのスクロールに埋もれて見落とされないようにするため、利用者の操作の結果はこれで通知する。
+func (m *MainModel) report(text string, level tui.LogLevel) tea.Cmd {
+	m.dashboard.AppendLog(text, level)
+	return m.notices.Report(text, level)
 }
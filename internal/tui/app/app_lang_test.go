@@ -2,8 +2,10 @@ package app
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
 )
@@ -16,60 +18,50 @@ func cleanupLang(t *testing.T) {
 func TestMainModel_ConfigLoaded_LangUnset(t *testing.T) {
 	cleanupTheme(t)
 	cleanupLang(t)
-	u := updModel(newTestModel("test"), tui.ConfigLoadedMsg{})
-	if u.page.currentPage != pageLang || !u.page.isFirstLaunch {
-		t.Errorf("page=%q first=%v", u.page.currentPage, u.page.isFirstLaunch)
+	u := updModel(newTestModel("test"), tui.ConfigLoadedMsg{SSHConfigPath: "~/.ssh/config", AutoRestore: true})
+	if u.page.currentPage != pageOnboarding {
+		t.Fatalf("page=%q, want onboarding", u.page.currentPage)
+	}
+	u.width, u.height = 80, 24
+	if !strings.Contains(u.View(), "1/6") {
+		t.Errorf("view should show the first onboarding step:\n%s", u.View())
+	}
+	// Ctrl+C 以外のキーはウィザードに送る
+	if _, _, ok := u.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'q'}}); !ok {
+		t.Error("keys should be routed to the onboarding page")
+	}
+}
+
+func TestMainModel_OnboardingDone(t *testing.T) {
+	cleanupTheme(t)
+	cleanupLang(t)
+	m := newTestModel("test")
+	m.page.currentPage = pageOnboarding
+	u, cmd := m.handleOnboardingDone(tui.OnboardingDoneMsg{Lang: "ja", PresetID: "dark-blue", SSHConfigPath: "~/.ssh/config"})
+	if u.page.currentPage != pageDashboard || u.page.currentLang != "ja" || u.page.currentPresetID != "dark-blue" || cmd == nil {
+		t.Errorf("page=%q lang=%q preset=%q cmd=%v", u.page.currentPage, u.page.currentLang, u.page.currentPresetID, cmd)
 	}
 }
 
 func TestMainModel_LangSelected(t *testing.T) {
-	t.Run("first_launch", func(t *testing.T) {
-		cleanupTheme(t)
-		cleanupLang(t)
-		m := newTestModel("test")
-		m.page.currentPage = pageLang
-		m.page.isFirstLaunch = true
-		result, cmd := m.Update(tui.LangSelectedMsg{Lang: "ja"})
-		u := result.(MainModel)
-		if u.page.currentPage != pageTheme || u.page.currentLang != "ja" || cmd == nil {
-			t.Errorf("page=%q lang=%q cmd=%v", u.page.currentPage, u.page.currentLang, cmd)
-		}
-	})
-	t.Run("normal", func(t *testing.T) {
-		cleanupLang(t)
-		m := newTestModel("test")
-		m.page.currentPage = pageLang
-		result, cmd := m.Update(tui.LangSelectedMsg{Lang: "en"})
-		u := result.(MainModel)
-		if u.page.currentPage != pageDashboard || u.page.currentLang != "en" || cmd == nil {
-			t.Errorf("page=%q lang=%q cmd=%v", u.page.currentPage, u.page.currentLang, cmd)
-		}
-	})
+	cleanupLang(t)
+	m := newTestModel("test")
+	m.page.currentPage = pageLang
+	result, cmd := m.Update(tui.LangSelectedMsg{Lang: "en"})
+	u := result.(MainModel)
+	if u.page.currentPage != pageDashboard || u.page.currentLang != "en" || cmd == nil {
+		t.Errorf("page=%q lang=%q cmd=%v", u.page.currentPage, u.page.currentLang, cmd)
+	}
 }
 
 func TestMainModel_LangCancelled(t *testing.T) {
-	t.Run("first_launch", func(t *testing.T) {
-		cleanupTheme(t)
-		cleanupLang(t)
-		m := newTestModel("test")
-		m.page.currentPage = pageLang
-		m.page.isFirstLaunch = true
-		result, cmd := m.Update(tui.LangCancelledMsg{})
-		u := result.(MainModel)
-		if u.page.currentPage != pageTheme || u.page.currentLang != string(i18n.DefaultLang()) || cmd == nil {
-			t.Errorf("page=%q lang=%q cmd=%v", u.page.currentPage, u.page.currentLang, cmd)
-		}
-	})
-	t.Run("normal", func(t *testing.T) {
-		cleanupLang(t)
-		m := newTestModel("test")
-		m.page.currentPage = pageLang
-		result, cmd := m.Update(tui.LangCancelledMsg{})
-		u := result.(MainModel)
-		if u.page.currentPage != pageDashboard || cmd != nil {
-			t.Errorf("page=%q cmd=%v", u.page.currentPage, cmd)
-		}
-	})
+	m := newTestModel("test")
+	m.page.currentPage = pageLang
+	result, cmd := m.Update(tui.LangCancelledMsg{})
+	u := result.(MainModel)
+	if u.page.currentPage != pageDashboard || cmd != nil {
+		t.Errorf("page=%q cmd=%v", u.page.currentPage, cmd)
+	}
 }
 
 func TestMainModel_LangSavedMsg(t *testing.T) {
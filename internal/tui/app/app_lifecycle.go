@@ -4,6 +4,7 @@ import (
 	"context"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 )
 
 // refreshForwardPanel はフォワードパネルを最新のセッション情報で更新する。
@@ -16,7 +17,7 @@ func (m *MainModel) shutdown() tea.Cmd {
 	m.quitting = true
 	// IPC クライアントをクリーンアップ（daemon は停止しない）
 	if m.subscriptionID != "" {
-		ctx, cancel := context.WithTimeout(context.Background(), ipcShutdownTimeout)
+		ctx, cancel := context.WithTimeout(context.Background(), ipccmd.ShutdownTimeout)
 		defer cancel()
 		_ = m.client.Unsubscribe(ctx, m.subscriptionID) // ベストエフォート: シャットダウン中のため失敗しても無視
 	}
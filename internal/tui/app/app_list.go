@@ -0,0 +1,49 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
+)
+
+// handleListMsg はホスト・セッション一覧の取得と、定期更新での差分の取得に関するメッセージを処理する。
+// 処理した場合は handled=true を返す。
+func (m MainModel) handleListMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case ipccmd.HostChangesMsg:
+		if msg.Err != nil {
+			return m, m.report(i18n.T("tui.log.hosts_load_error", map[string]any{"Error": msg.Err}), tui.LogError), true
+		}
+		m.hosts = ipccmd.ApplyHostChanges(m.hosts, msg)
+		m.hostRev = msg.Revision
+		m.dashboard.SetHosts(m.hosts)
+		m.refreshForwardPanel()
+		return m, nil, true
+
+	case ipccmd.SessionsLoadedMsg:
+		m.sessions = msg.Sessions
+		m.sessionRev = msg.Revision
+		m.dashboard.SetForwardSessions(msg.Sessions)
+		m.dashboard.SetForwardGroups(msg.Groups)
+		m.dashboard.SetHostLatency(msg.Latency)
+		return m, nil, true
+
+	case ipccmd.SessionChangesMsg:
+		m.sessions = ipccmd.ApplySessionChanges(m.sessions, msg)
+		m.sessionRev = msg.Revision
+		m.dashboard.SetForwardSessions(m.sessions)
+		m.dashboard.SetForwardGroups(msg.Groups)
+		m.dashboard.SetHostLatency(msg.Latency)
+		return m, nil, true
+
+	case tui.MetricsTickMsg:
+		var cmds []tea.Cmd
+		if !m.dialog.restarting {
+			cmds = append(cmds, ipccmd.LoadSessionChanges(m.client, m.sessionRev))
+		}
+		cmds = append(cmds, m.metricsTick())
+		return m, tea.Batch(cmds...), true
+	}
+	return m, nil, false
+}
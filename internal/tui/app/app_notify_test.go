@@ -0,0 +1,59 @@
+package app
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestIPCNotification_ForwardErrorToasts(t *testing.T) {
+	data, err := json.Marshal(protocol.ForwardEventNotification{Type: protocol.ForwardEventTypeError, Name: "web", Error: "boom"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	msg := tui.IPCNotificationMsg{Notification: &protocol.Notification{Method: protocol.EventForward, Params: data}}
+	u := updModel(NewMainModel(client.NewIPCClient("/tmp/test.sock"), "1.0.0", "/tmp/test"), msg)
+	if len(u.notices.Active()) != 1 || u.dashboard.LogLineCount() != 1 {
+		t.Errorf("toasts = %d, log lines = %d, want 1/1", len(u.notices.Active()), u.dashboard.LogLineCount())
+	}
+}
+
+func TestNotificationHistory_Toggle(t *testing.T) {
+	u := updModel(newTestModel("1.0.0"), keyMsg('n'))
+	if !u.notices.HistoryVisible() {
+		t.Fatal("n should open notification history")
+	}
+	if updModel(u, keyMsg('a')).notices.HistoryVisible() {
+		t.Error("any key should close notification history")
+	}
+}
+
+func TestLogOutput_ToastsErrorsAndSuccesses(t *testing.T) {
+	tests := []struct {
+		level tui.LogLevel
+		toast bool
+	}{
+		{tui.LogInfo, false},
+		{tui.LogSuccess, true},
+		{tui.LogError, true},
+	}
+	for _, tt := range tests {
+		u := updModel(newTestModel("1.0.0"), tui.LogOutputMsg{Text: "forward web failed", Level: tt.level})
+		if got := len(u.notices.Active()) == 1; got != tt.toast {
+			t.Errorf("level %d: toast shown = %v, want %v", tt.level, got, tt.toast)
+		}
+		if u.dashboard.LogLineCount() != 1 {
+			t.Errorf("level %d: the message should also be logged", tt.level)
+		}
+	}
+
+	// デーモンの再起動中は通知しない
+	m := newTestModel("1.0.0")
+	m.dialog.restarting = true
+	if u := updModel(m, tui.LogOutputMsg{Text: "boom", Level: tui.LogError}); len(u.notices.Active()) != 0 {
+		t.Error("no toast should be shown while the daemon restarts")
+	}
+}
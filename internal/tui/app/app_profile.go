@@ -0,0 +1,28 @@
+package app
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
+)
+
+// handleProfileSwitched はプロファイル切り替えの結果を処理し、切り替え先のホスト・セッション・設定を再取得する。
+// イベントの受信は同じクライアントのチャネルで継続するため ListenEvents は再発行しない。
+func (m MainModel) handleProfileSwitched(msg ipccmd.ProfileSwitchedMsg) (MainModel, tea.Cmd) {
+	if msg.Err != nil {
+		return m, m.report(i18n.T("tui.log.profile_error", map[string]any{"Error": msg.Err}), tui.LogError)
+	}
+	if msg.SubscriptionID == "" {
+		m.dashboard.AppendLog(i18n.T("tui.log.profile_none", map[string]any{"Profile": msg.Profile}), tui.LogInfo)
+		return m, nil
+	}
+	m.subscriptionID = msg.SubscriptionID
+	m.dashboard.SetProfile(msg.Profile)
+	return m, tea.Batch(
+		m.report(i18n.T("tui.log.profile_switched", map[string]any{"Profile": msg.Profile}), tui.LogSuccess),
+		ipccmd.LoadHosts(m.client),
+		ipccmd.LoadSessions(m.client),
+		ipccmd.LoadConfig(m.client),
+	)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func newReadOnlyModel() MainModel {
+	m := NewMainModel(client.NewIPCClient("/tmp/test.sock"), "2.0.0", "/tmp/test")
+	m.dashboard.SetSize(80, 24)
+	m.SetReadOnly(true)
+	return m
+}
+
+func TestSetReadOnly_ScopesClient(t *testing.T) {
+	m := newReadOnlyModel()
+	if !m.client.IsReadOnly() || !m.dashboard.ReadOnly() {
+		t.Error("SetReadOnly(true) should propagate to client and dashboard")
+	}
+}
+
+func TestReadOnly_SettingsPagesDisabled(t *testing.T) {
+	for _, r := range []rune{'t', 'l', 'c'} {
+		u := updModel(newReadOnlyModel(), keyMsg(r))
+		if u.page.currentPage != pageDashboard {
+			t.Errorf("%q: currentPage = %q, want dashboard", r, u.page.currentPage)
+		}
+		if got := u.dashboard.LogLineCount(); got != 1 {
+			t.Errorf("%q: LogLineCount() = %d, want 1", r, got)
+		}
+	}
+}
+
+func TestReadOnly_VersionMismatchSkipsRestartDialog(t *testing.T) {
+	u := updModel(newReadOnlyModel(), tui.VersionCheckDoneMsg{DaemonVersion: "1.0.0", TUIVersion: "2.0.0"})
+	if u.dialog.showVersionConfirm {
+		t.Error("read-only mode should not offer daemon restart")
+	}
+}
+
+func TestReadOnly_FirstLaunchSkipsSetupPages(t *testing.T) {
+	u := updModel(newReadOnlyModel(), tui.ConfigLoadedMsg{})
+	if u.page.currentPage != pageDashboard {
+		t.Errorf("currentPage = %q, want dashboard", u.page.currentPage)
+	}
+}
@@ -2,32 +2,39 @@ package app
 
 import (
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 	"github.com/ousiassllc/moleport/internal/tui/pages"
 	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // ページ識別子の定数。
 const (
-	pageDashboard = "dashboard"
-	pageTheme     = "theme"
-	pageLang      = "lang"
+	pageDashboard  = "dashboard"
+	pageTheme      = "theme"
+	pageLang       = "lang"
+	pageConfig     = "config"
+	pageOnboarding = "onboarding"
 )
 
 // handleConfigLoaded は設定読み込み完了メッセージを処理する。
 func (m MainModel) handleConfigLoaded(msg tui.ConfigLoadedMsg) (MainModel, tea.Cmd) {
 	if msg.Err != nil {
-		if !m.dialog.restarting {
-			m.dashboard.AppendLog(i18n.T("tui.log.config_load_error", map[string]any{"Error": msg.Err}), tui.LogError)
+		if m.dialog.restarting {
+			return m, nil
 		}
-		return m, nil
+		return m, m.report(i18n.T("tui.log.config_load_error", map[string]any{"Error": msg.Err}), tui.LogError)
 	}
 
-	// 言語が未設定 → 初回起動: 言語選択ページから開始
-	if msg.Language == "" {
-		m.page.isFirstLaunch = true
-		m.openLangPage()
+	format.SetTimeFormat(msg.TimeFormat)
+	theme.SetUserPresets(msg.UserThemes)
+	m.dashboard.SetLayout(msg.ForwardPercent, msg.HideLog)
+
+	// 言語が未設定 → 初回起動: セットアップウィザードを開始（閲覧専用モードでは保存できないため省略）
+	if msg.Language == "" && !m.readOnly {
+		m.openOnboarding(msg)
 		return m, nil
 	}
 
@@ -35,8 +42,8 @@ func (m MainModel) handleConfigLoaded(msg tui.ConfigLoadedMsg) (MainModel, tea.C
 	_ = i18n.SetLang(i18n.Lang(msg.Language)) // ベストエフォート: 未知の言語でもフォールバックされる
 	m.page.currentLang = msg.Language
 
-	// テーマが未設定 → テーマ選択ページへ
-	if msg.ThemeBase == "" || msg.ThemeAccent == "" {
+	// テーマが未設定 → テーマ選択ページへ（閲覧専用モードではデフォルトを適用）
+	if !m.readOnly && (msg.ThemeBase == "" || msg.ThemeAccent == "") {
 		m.page.isFirstLaunch = true
 		m.page.currentPresetID = theme.DefaultPresetID()
 		m.page.previousPresetID = m.page.currentPresetID
@@ -45,6 +52,9 @@ func (m MainModel) handleConfigLoaded(msg tui.ConfigLoadedMsg) (MainModel, tea.C
 		m.page.currentPage = pageTheme
 	} else {
 		presetID := theme.PresetIDFromConfig(msg.ThemeBase, msg.ThemeAccent)
+		if _, ok := theme.FindPreset(presetID); !ok {
+			presetID = theme.DefaultPresetID() // 削除されたユーザーテーマなど
+		}
 		theme.Apply(presetID)
 		m.page.currentPresetID = presetID
 	}
@@ -57,7 +67,7 @@ func (m MainModel) handleThemeSelected(msg tui.ThemeSelectedMsg) (MainModel, tea
 	m.page.currentPresetID = msg.PresetID
 	m.page.currentPage = pageDashboard
 	m.page.isFirstLaunch = false
-	return m, m.saveTheme(msg.PresetID)
+	return m, ipccmd.SaveTheme(m.client, msg.PresetID)
 }
 
 // handleThemeCancelled はテーマキャンセルメッセージを処理する。
@@ -68,7 +78,7 @@ func (m MainModel) handleThemeCancelled() (MainModel, tea.Cmd) {
 		m.page.currentPresetID = defaultID
 		m.page.currentPage = pageDashboard
 		m.page.isFirstLaunch = false
-		return m, m.saveTheme(defaultID)
+		return m, ipccmd.SaveTheme(m.client, defaultID)
 	}
 	theme.Apply(m.page.previousPresetID)
 	m.page.currentPresetID = m.page.previousPresetID
@@ -79,7 +89,7 @@ func (m MainModel) handleThemeCancelled() (MainModel, tea.Cmd) {
 // handleThemeSaved はテーマ保存完了メッセージを処理する。
 func (m MainModel) handleThemeSaved(msg tui.ThemeSavedMsg) (MainModel, tea.Cmd) {
 	if msg.Err != nil && !m.dialog.restarting {
-		m.dashboard.AppendLog(i18n.T("tui.log.theme_save_error", map[string]any{"Error": msg.Err}), tui.LogError)
+		return m, m.report(i18n.T("tui.log.theme_save_error", map[string]any{"Error": msg.Err}), tui.LogError)
 	}
 	return m, nil
 }
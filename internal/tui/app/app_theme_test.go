@@ -10,7 +10,10 @@ import (
 
 func cleanupTheme(t *testing.T) {
 	t.Helper()
-	t.Cleanup(func() { theme.Apply(theme.DefaultPresetID()) })
+	t.Cleanup(func() {
+		theme.SetUserPresets(nil)
+		theme.Apply(theme.DefaultPresetID())
+	})
 }
 
 func TestMainModel_ConfigLoaded_Theme(t *testing.T) {
@@ -28,6 +31,23 @@ func TestMainModel_ConfigLoaded_Theme(t *testing.T) {
 			t.Errorf("page=%q preset=%q", u.page.currentPage, u.page.currentPresetID)
 		}
 	})
+	t.Run("user_theme", func(t *testing.T) {
+		cleanupTheme(t)
+		ocean := theme.NewUserPreset("ocean", "dark", theme.Palette{Accent: "#0EA5E9"})
+		u := updModel(newTestModel("test"), tui.ConfigLoadedMsg{
+			ThemeBase: "dark", ThemeAccent: "ocean", Language: "en", UserThemes: []theme.Preset{ocean},
+		})
+		if u.page.currentPresetID != "dark-ocean" || theme.Current().Accent != "#0EA5E9" {
+			t.Errorf("preset=%q accent=%q", u.page.currentPresetID, theme.Current().Accent)
+		}
+	})
+	t.Run("unknown_falls_back_to_default", func(t *testing.T) {
+		cleanupTheme(t)
+		u := updModel(newTestModel("test"), tui.ConfigLoadedMsg{ThemeBase: "dark", ThemeAccent: "ocean", Language: "en"})
+		if u.page.currentPage != pageDashboard || u.page.currentPresetID != theme.DefaultPresetID() {
+			t.Errorf("page=%q preset=%q", u.page.currentPage, u.page.currentPresetID)
+		}
+	})
 	t.Run("error", func(t *testing.T) {
 		u := updModel(newTestModel("test"), tui.ConfigLoadedMsg{Err: fmt.Errorf("err")})
 		if u.page.currentPage != pageDashboard {
@@ -7,7 +7,9 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 	"github.com/ousiassllc/moleport/internal/tui/molecules"
+	"github.com/ousiassllc/moleport/internal/tui/organisms/helpmodal"
 )
 
 // handleSystemMsg は tea.WindowSizeMsg と tea.KeyMsg を処理する。
@@ -20,6 +22,11 @@ func (m MainModel) handleSystemMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
 		m.dashboard.SetSize(msg.Width, msg.Height)
 		m.page.themePage.SetSize(msg.Width, msg.Height)
 		m.page.langPage.SetSize(msg.Width, msg.Height)
+		m.page.configPage.SetSize(msg.Width, msg.Height)
+		m.page.onboarding.SetSize(msg.Width, msg.Height)
+		if m.dialog.help != nil {
+			m.dialog.help.SetSize(msg.Width, msg.Height)
+		}
 		var cmd tea.Cmd
 		m.dashboard, cmd = m.dashboard.Update(msg)
 		return m, cmd, true
@@ -37,17 +44,19 @@ func (m MainModel) handleKeyMsg(msg tea.KeyMsg) (MainModel, tea.Cmd, bool) {
 	if key.Matches(msg, m.keys.ForceQuit) {
 		return m, m.shutdown(), true
 	}
-	// ヘルプモーダル表示中は任意のキーで閉じる
-	if m.dialog.showHelpModal {
-		m.dialog.showHelpModal = false
+	// ヘルプモーダル表示中はモーダルでスクロール・Esc で閉じる。通知履歴表示中は任意のキーで閉じる
+	if m.dialog.help != nil {
+		help, cmd := m.dialog.help.Update(msg)
+		m.dialog.help = &help
+		return m, cmd, true
+	}
+	if m.notices.Update(msg) {
 		return m, nil, true
 	}
 	// アップデート通知ダイアログ表示中は ForceQuit 以外はダイアログに転送
-	// showUpdateNotify と showVersionConfirm は相互排他（handleUpdateCheckDone でバッファリング）
-	if m.dialog.showUpdateNotify {
-		var cmd tea.Cmd
-		m.dialog.updateNotifyDialog, cmd = m.dialog.updateNotifyDialog.Update(msg)
-		return m, cmd, true
+	// アップデート通知とバージョン確認ダイアログは相互排他（確認ダイアログの表示中は通知を保留する）
+	if m.dialog.updateNotice.Visible() {
+		return m, m.dialog.updateNotice.Update(msg), true
 	}
 	// バージョン確認ダイアログ表示中は ForceQuit 以外はダイアログに転送
 	if m.dialog.showVersionConfirm {
@@ -55,32 +64,58 @@ func (m MainModel) handleKeyMsg(msg tea.KeyMsg) (MainModel, tea.Cmd, bool) {
 		m.dialog.versionConfirm, cmd = m.dialog.versionConfirm.Update(msg)
 		return m, cmd, true
 	}
-	// テーマページ表示中は ForceQuit 以外は themePage に転送
-	if m.page.currentPage == pageTheme {
+	if m.dialog.showImportConfirm {
 		var cmd tea.Cmd
-		m.page.themePage, cmd = m.page.themePage.Update(msg)
+		m.dialog.importConfirm, cmd = m.dialog.importConfirm.Update(msg)
 		return m, cmd, true
 	}
-	// 言語ページ表示中は ForceQuit 以外は langPage に転送
-	if m.page.currentPage == pageLang {
-		var cmd tea.Cmd
+	// テーマ・言語・設定エディタ・セットアップウィザードの表示中は ForceQuit 以外をページに転送
+	var cmd tea.Cmd
+	switch m.page.currentPage {
+	case pageTheme:
+		m.page.themePage, cmd = m.page.themePage.Update(msg)
+		return m, cmd, true
+	case pageLang:
 		m.page.langPage, cmd = m.page.langPage.Update(msg)
 		return m, cmd, true
+	case pageConfig:
+		m.page.configPage, cmd = m.page.configPage.Update(msg)
+		return m, cmd, true
+	case pageOnboarding:
+		m.page.onboarding, cmd = m.page.onboarding.Update(msg)
+		return m, cmd, true
 	}
-	// テキスト入力中は q/?/t/l をグローバル処理しない
+	// テキスト入力中は q/?/t/l/c/L をグローバル処理しない
 	if !m.dashboard.IsInputActive() {
 		switch {
 		case key.Matches(msg, m.keys.Quit):
+			if m.dashboard.RequestQuit() {
+				return m, nil, true
+			}
 			return m, m.shutdown(), true
 		case key.Matches(msg, m.keys.Help):
-			m.dialog.showHelpModal = true
+			help := helpmodal.New()
+			help.SetSize(m.width, m.height)
+			m.dialog.help = &help
+			return m, nil, true
+		case key.Matches(msg, m.keys.Notifications):
+			m.notices.OpenHistory()
 			return m, nil, true
+		case m.readOnly && (key.Matches(msg, m.keys.Theme) || key.Matches(msg, m.keys.Lang) || key.Matches(msg, m.keys.Config)):
+			return m, m.report(i18n.T("tui.log.read_only"), tui.LogError), true
 		case key.Matches(msg, m.keys.Theme):
 			m.openThemePage()
 			return m, nil, true
 		case key.Matches(msg, m.keys.Lang):
 			m.openLangPage()
 			return m, nil, true
+		case key.Matches(msg, m.keys.Config):
+			return m, m.openConfigPage(), true
+		case key.Matches(msg, m.keys.Logs):
+			m.dashboard.OpenLogViewer()
+			return m, ipccmd.SubscribeLogs(m.client), true
+		case key.Matches(msg, m.keys.Profile):
+			return m, ipccmd.SwitchProfile(m.client, m.subscriptionID), true
 		case key.Matches(msg, m.keys.Version):
 			m.dashboard.AppendLog(fmt.Sprintf("MolePort %s", m.version), tui.LogInfo)
 			return m, nil, true
@@ -96,42 +131,44 @@ func (m MainModel) handleIPCMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
 	case tui.HostsLoadedMsg:
 		if msg.Err != nil {
 			if !m.dialog.restarting {
-				m.dashboard.AppendLog(i18n.T("tui.log.hosts_load_error", map[string]any{"Error": msg.Err}), tui.LogError)
+				return m, m.report(i18n.T("tui.log.hosts_load_error", map[string]any{"Error": msg.Err}), tui.LogError), true
 			}
 		} else {
 			m.hosts = msg.Hosts
+			m.hostRev = msg.Revision
 			m.dashboard.SetHosts(msg.Hosts)
 			m.refreshForwardPanel()
 			m.dashboard.AppendLog(i18n.T("tui.log.hosts_loaded", map[string]any{"Count": len(msg.Hosts)}), tui.LogSuccess)
+			return m, m.maybeLoadImportCandidates(), true
 		}
 		return m, nil, true
 
 	case tui.HostsReloadedMsg:
 		if msg.Err != nil {
-			m.dashboard.AppendLog(i18n.T("tui.log.hosts_reload_error", map[string]any{"Error": msg.Err}), tui.LogError)
-		} else {
-			m.hosts = msg.Hosts
-			m.dashboard.SetHosts(msg.Hosts)
-			m.dashboard.AppendLog(i18n.T("tui.log.hosts_reloaded", map[string]any{"Count": len(msg.Hosts)}), tui.LogSuccess)
+			return m, m.report(i18n.T("tui.log.hosts_reload_error", map[string]any{"Error": msg.Err}), tui.LogError), true
 		}
-		return m, nil, true
+		m.hosts = msg.Hosts
+		m.dashboard.SetHosts(msg.Hosts)
+		return m, m.report(i18n.T("tui.log.hosts_reloaded", map[string]any{"Count": len(msg.Hosts)}), tui.LogSuccess), true
 
 	case tui.HostSelectedMsg:
 		// セットアップパネルが内部管理するため、ここでは何もしない
 		return m, nil, true
 
-	case subscriptionStartedMsg:
+	case ipccmd.SubscriptionStartedMsg:
 		m.subscriptionID = msg.SubscriptionID
-		return m, m.listenIPCEvents(), true
+		return m, ipccmd.ListenEvents(m.client), true
 
-	case sessionsLoadedMsg:
-		m.sessions = msg.Sessions
-		m.dashboard.SetForwardSessions(msg.Sessions)
-		return m, nil, true
+	case tui.LogsUnsubscribeMsg:
+		return m, ipccmd.UnsubscribeLogs(m.client, msg.SubscriptionID), true
 
 	case tui.IPCNotificationMsg:
-		m.handleIPCNotification(msg.Notification)
-		return m, m.listenIPCEvents(), true
+		cmd := m.handleIPCNotification(msg.Notification)
+		return m, tea.Batch(cmd, ipccmd.ListenEvents(m.client)), true
+
+	case molecules.ToastExpiredMsg:
+		m.notices.Update(msg)
+		return m, nil, true
 
 	case tui.IPCDisconnectedMsg:
 		if m.dialog.restarting {
@@ -140,60 +177,28 @@ func (m MainModel) handleIPCMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
 		m.dashboard.AppendLog(i18n.T("tui.log.daemon_disconnected"), tui.LogError)
 		return m, m.shutdown(), true
 
-	case tui.MetricsTickMsg:
-		var cmds []tea.Cmd
-		if !m.dialog.restarting {
-			cmds = append(cmds, m.loadSessions())
-		}
-		cmds = append(cmds, m.metricsTick())
-		return m, tea.Batch(cmds...), true
-	}
-	return m, nil, false
-}
-
-// handleForwardMsg はフォワード操作関連のメッセージを処理する。
-// 処理した場合は handled=true を返す。
-func (m MainModel) handleForwardMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
-	switch msg := msg.(type) {
-	case tui.ForwardAddRequestMsg:
-		cmd := m.handleForwardAdd(msg)
-		return m, cmd, true
-
-	case tui.ForwardToggleMsg:
-		return m, m.toggleForward(msg.RuleName), true
-
-	case tui.ForwardDeleteRequestMsg:
-		return m, m.deleteForwardRule(msg.RuleName), true
-
-	case tui.ForwardDeleteConfirmedMsg:
-		return m, m.deleteForwardRule(msg.RuleName), true
-
-	case tui.LogOutputMsg:
-		if !m.dialog.restarting {
-			m.dashboard.AppendLog(msg.Text, msg.Level)
-		}
-		return m, nil, true
 	}
-	return m, nil, false
+	return m.handleListMsg(msg)
 }
 
 // handleUIMsg は UI 状態管理関連のメッセージを処理する。
 // 処理した場合は handled=true を返す。
 func (m MainModel) handleUIMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
 	switch msg := msg.(type) {
+	case tui.HelpClosedMsg:
+		m.dialog.help = nil
+		return m, nil, true
+
 	case tui.VersionCheckDoneMsg:
 		model, cmd := m.handleVersionCheckDone(msg)
 		return model, cmd, true
 
 	case tui.UpdateCheckDoneMsg:
-		model, cmd := m.handleUpdateCheckDone(msg)
-		return model, cmd, true
+		m.dialog.updateNotice.Receive(msg, m.dialog.showVersionConfirm)
+		return m, nil, true
 
 	case molecules.InfoDismissedMsg:
-		if m.dialog.showUpdateNotify {
-			model, cmd := m.handleUpdateNotifyDismissed()
-			return model, cmd, true
-		}
+		m.dialog.updateNotice.Update(msg)
 		return m, nil, true
 
 	case molecules.ConfirmResultMsg:
@@ -201,9 +206,26 @@ func (m MainModel) handleUIMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
 			model, cmd := m.handleVersionConfirmResult(msg.Confirmed)
 			return model, cmd, true
 		}
-		return m, nil, true
+		if m.dialog.showImportConfirm {
+			model, cmd := m.handleImportConfirmResult(msg.Confirmed)
+			return model, cmd, true
+		}
+		// ダッシュボードの確認ダイアログの結果はダッシュボードに転送する
+		return m, nil, false
+
+	case ipccmd.ImportCandidatesMsg:
+		model, cmd := m.handleImportCandidates(msg)
+		return model, cmd, true
 
-	case daemonRestartDoneMsg:
+	case ipccmd.ForwardsImportedMsg:
+		model, cmd := m.handleForwardsImported(msg)
+		return model, cmd, true
+
+	case ipccmd.ProfileSwitchedMsg:
+		model, cmd := m.handleProfileSwitched(msg)
+		return model, cmd, true
+
+	case ipccmd.DaemonRestartedMsg:
 		model, cmd := m.handleDaemonRestartDone(msg)
 		return model, cmd, true
 
@@ -228,7 +250,11 @@ func (m MainModel) handleUIMsg(msg tea.Msg) (MainModel, tea.Cmd, bool) {
 		return model, cmd, true
 
 	case tui.LangCancelledMsg:
-		model, cmd := m.handleLangCancelled()
+		m.page.currentPage = pageDashboard
+		return m, nil, true
+
+	case tui.OnboardingDoneMsg:
+		model, cmd := m.handleOnboardingDone(msg)
 		return model, cmd, true
 
 	case tui.LangSavedMsg:
@@ -13,7 +13,7 @@ func TestUncoveredBranches(t *testing.T) {
 		t.Error("ctrl+c")
 	}
 	m := newTestModel("1")
-	m.dialog.showUpdateNotify, m.dialog.updateNotifyDialog = true, molecules.NewInfoDialog("t")
+	m.dialog.updateNotice.Receive(tui.UpdateCheckDoneMsg{UpdateAvailable: true}, false)
 	if _, _, ok := m.handleKeyMsg(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}); !ok {
 		t.Error("updateNotify")
 	}
@@ -36,8 +36,11 @@ func TestUncoveredBranches(t *testing.T) {
 	if _, c, ok := newTestModel("1").handleForwardMsg(tui.ForwardToggleMsg{RuleName: "w"}); !ok || c == nil {
 		t.Error("toggle")
 	}
-	if _, c, ok := newTestModel("1").handleForwardMsg(tui.ForwardDeleteRequestMsg{RuleName: "w"}); !ok || c == nil {
-		t.Error("delete")
+	if _, _, ok := newTestModel("1").handleForwardMsg(tui.ForwardDeleteRequestMsg{RuleName: "w"}); ok {
+		t.Error("delete request should be confirmed by the dashboard")
+	}
+	if _, c, ok := newTestModel("1").handleForwardMsg(tui.HostDeleteConfirmedMsg{Name: "h"}); !ok || c == nil {
+		t.Error("hostDeleteConfirmed")
 	}
 	if r, c, ok := newTestModel("1").handleUIMsg(tui.QuitRequestMsg{}); !ok || c == nil || !r.quitting {
 		t.Error("quit")
@@ -49,6 +52,9 @@ func TestUncoveredBranches(t *testing.T) {
 	if _, c, ok := newTestModel("1").handleForwardMsg(tui.ForwardDeleteConfirmedMsg{RuleName: "w"}); !ok || c == nil {
 		t.Error("deleteConfirmed")
 	}
+	if _, c, ok := newTestModel("1").handleForwardMsg(tui.ForwardBulkConfirmedMsg{Action: tui.BulkStop, RuleNames: []string{"a", "b"}}); !ok || c == nil {
+		t.Error("bulkConfirmed")
+	}
 	// handleSystemMsg fallthrough (unhandled msg type)
 	if _, _, ok := newTestModel("1").handleSystemMsg("unknown"); ok {
 		t.Error("unknown msg should not be handled")
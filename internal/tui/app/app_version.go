@@ -1,62 +1,32 @@
 package app
 
 import (
-	"context"
-	"fmt"
-	"log/slog"
-	"time"
-
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ousiassllc/moleport/internal/i18n"
-	"github.com/ousiassllc/moleport/internal/ipc/client"
-	"github.com/ousiassllc/moleport/internal/ipc/protocol"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 	"github.com/ousiassllc/moleport/internal/tui/molecules"
 )
 
-// daemonRestartDoneMsg はデーモン再起動完了を通知する内部メッセージ。
-type daemonRestartDoneMsg struct {
-	newClient *client.IPCClient
-	err       error
-}
-
-// checkDaemonVersion はデーモンのバージョンを取得して TUI と比較する Cmd を返す。
-// ゴルーチン安全のためクライアントポインタをローカル変数にキャプチャする。
-func (m *MainModel) checkDaemonVersion() tea.Cmd {
-	c := m.client // capture pointer for goroutine safety
-	version := m.version
-	return func() tea.Msg {
-		if c == nil {
-			return tui.VersionCheckDoneMsg{Match: true}
-		}
-		ctx, cancel := context.WithTimeout(context.Background(), ipcReadTimeout)
-		defer cancel()
-		var status protocol.DaemonStatusResult
-		if err := c.Call(ctx, "daemon.status", nil, &status); err != nil {
-			return tui.VersionCheckDoneMsg{Err: err}
-		}
-		if status.Version == "dev" || version == "dev" {
-			return tui.VersionCheckDoneMsg{Match: true}
-		}
-		return tui.VersionCheckDoneMsg{
-			Match:         status.Version == version,
-			DaemonVersion: status.Version,
-			TUIVersion:    version,
-		}
-	}
-}
-
 // handleVersionCheckDone はバージョンチェック結果を処理する。
 func (m MainModel) handleVersionCheckDone(msg tui.VersionCheckDoneMsg) (MainModel, tea.Cmd) {
 	if msg.Err != nil {
-		m.dashboard.AppendLog(i18n.T("tui.version.check_error", map[string]any{"Error": msg.Err}), tui.LogError)
+		return m, m.report(i18n.T("tui.version.check_error", map[string]any{"Error": msg.Err}), tui.LogError)
+	}
+	if msg.Match && !msg.Incompatible {
 		return m, nil
 	}
-	if msg.Match {
+	// 閲覧専用モードではデーモンを再起動できないため警告のみ表示する
+	if m.readOnly {
+		m.dashboard.SetVersionWarning(true)
 		return m, nil
 	}
-	message := i18n.T("tui.version.mismatch", map[string]any{"DaemonVersion": msg.DaemonVersion, "TUIVersion": msg.TUIVersion})
+	key := "tui.version.mismatch"
+	if msg.Incompatible {
+		key = "tui.version.incompatible"
+	}
+	message := i18n.T(key, map[string]any{"DaemonVersion": msg.DaemonVersion, "TUIVersion": msg.TUIVersion})
 	m.dialog.versionConfirm = molecules.NewConfirmDialog(message)
 	m.dialog.showVersionConfirm = true
 	return m, nil
@@ -67,76 +37,40 @@ func (m MainModel) handleVersionConfirmResult(confirmed bool) (MainModel, tea.Cm
 	m.dialog.showVersionConfirm = false
 	if confirmed {
 		m.dialog.restarting = true
-		m.dialog.pendingUpdateCheck = nil // 再起動するのでアップデート通知は不要
+		m.dashboard.SetDaemonRestarting(true)
+		m.dialog.updateNotice.Discard() // 再起動するのでアップデート通知は不要
 		m.dashboard.AppendLog(i18n.T("tui.version.restarting"), tui.LogInfo)
-		return m, m.restartDaemon()
+		return m, ipccmd.RestartDaemon(m.client, m.daemonMgr, m.configDir)
 	}
 	m.dashboard.SetVersionWarning(true)
 	m.dashboard.AppendLog(i18n.T("tui.version.mismatch_continue"), tui.LogInfo)
-	// バッファリングされたアップデート通知があれば表示する
-	if m.dialog.pendingUpdateCheck != nil {
-		pending := *m.dialog.pendingUpdateCheck
-		m.dialog.pendingUpdateCheck = nil
-		m = m.showUpdateNotifyDialog(pending)
-	}
+	// 保留中のアップデート通知があれば表示する
+	m.dialog.updateNotice.Flush()
 	return m, nil
 }
 
-// restartDaemon はデーモンを再起動する Cmd を返す。
-// ゴルーチン安全のため必要な値をすべてローカル変数にキャプチャする。
-func (m *MainModel) restartDaemon() tea.Cmd {
-	c := m.client                        // capture for goroutine
-	configDir := m.configDir             // capture for goroutine
-	dm := m.daemonMgr                    // capture for goroutine
-	credHandler := c.CredentialHandler() // save before shutdown
-	return func() tea.Msg {
-		// 1. デーモンをシャットダウン（失敗してもリスタートを続行する）
-		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		var result protocol.DaemonShutdownResult
-		if err := c.Call(ctx, "daemon.shutdown", protocol.DaemonShutdownParams{Purge: false}, &result); err != nil {
-			slog.Warn("daemon shutdown failed, proceeding with restart", "error", err)
-		}
-
-		// 2. 旧接続を閉じる
-		_ = c.Close()
-
-		// 3. 新しいデーモンプロセスを起動
-		if _, err := dm.StartDaemonProcess(configDir); err != nil {
-			return daemonRestartDoneMsg{err: fmt.Errorf("%s: %w", i18n.T("tui.log.daemon_start_failed"), err)}
-		}
-
-		// 4. 新しいデーモンに接続
-		newClient, err := dm.EnsureDaemonWithRetry(configDir, 5*time.Second)
-		if err != nil {
-			return daemonRestartDoneMsg{err: fmt.Errorf("%s: %w", i18n.T("tui.log.daemon_connect_failed"), err)}
-		}
-
-		// 5. クレデンシャルハンドラーを新しいクライアントに復元
-		if credHandler != nil {
-			newClient.SetCredentialHandler(credHandler)
-		}
-
-		return daemonRestartDoneMsg{newClient: newClient}
-	}
-}
-
 // handleDaemonRestartDone はデーモン再起動完了を処理する。
 // メインの Update ループで実行されるため、m.client の入れ替えはスレッドセーフ。
-func (m MainModel) handleDaemonRestartDone(msg daemonRestartDoneMsg) (MainModel, tea.Cmd) {
+func (m MainModel) handleDaemonRestartDone(msg ipccmd.DaemonRestartedMsg) (MainModel, tea.Cmd) {
 	m.dialog.restarting = false
-	if msg.err != nil {
-		m.dashboard.AppendLog(i18n.T("tui.version.restart_error", map[string]any{"Error": msg.err}), tui.LogError)
-		return m, nil
+	m.dashboard.SetDaemonRestarting(false)
+	if msg.Err != nil {
+		return m, m.report(i18n.T("tui.version.restart_error", map[string]any{"Error": msg.Err}), tui.LogError)
 	}
-	m.client = msg.newClient
+	m.client = msg.Client
 	m.subscriptionID = ""
-	m.dashboard.AppendLog(i18n.T("tui.version.restarted"), tui.LogSuccess)
+	// ログの購読は再起動前のデーモンとともに失われるため、ログビューアの表示中は購読し直す
+	var logsCmd tea.Cmd
+	if m.dashboard.LogViewerOpen() {
+		logsCmd = ipccmd.SubscribeLogs(m.client)
+	}
 	return m, tea.Batch(
-		m.loadHosts(),
-		m.loadSessions(),
-		m.subscribeEvents(),
-		m.loadConfig(),
+		m.report(i18n.T("tui.version.restarted"), tui.LogSuccess),
+		ipccmd.LoadHosts(m.client),
+		ipccmd.LoadSessions(m.client),
+		ipccmd.SubscribeEvents(m.client),
+		ipccmd.LoadConfig(m.client),
+		logsCmd,
 	)
 }
 
@@ -7,6 +7,7 @@ import (
 
 	"github.com/ousiassllc/moleport/internal/ipc/client"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/ipccmd"
 	"github.com/ousiassllc/moleport/internal/tui/molecules"
 )
 
@@ -25,6 +26,7 @@ func TestVersionCheckDone(t *testing.T) {
 	}{
 		{"match", tui.VersionCheckDoneMsg{Match: true}, false, 0},
 		{"mismatch", tui.VersionCheckDoneMsg{DaemonVersion: "1.0.0", TUIVersion: "2.0.0"}, true, 0},
+		{"incompatible protocol", tui.VersionCheckDoneMsg{Incompatible: true, DaemonVersion: "dev", TUIVersion: "2.0.0"}, true, 0},
 		{"error", tui.VersionCheckDoneMsg{Err: fmt.Errorf("connection refused")}, false, 1},
 	}
 	for _, tt := range tests {
@@ -71,12 +73,11 @@ func TestVersionConfirmResult_Yes(t *testing.T) {
 func TestDaemonRestartDone(t *testing.T) {
 	nc := client.NewIPCClient("/tmp/new.sock")
 	tests := []struct {
-		name    string
-		msg     daemonRestartDoneMsg
-		wantCmd bool
+		name string
+		msg  ipccmd.DaemonRestartedMsg
 	}{
-		{"error", daemonRestartDoneMsg{err: fmt.Errorf("failed")}, false},
-		{"success", daemonRestartDoneMsg{newClient: nc}, true},
+		{"error", ipccmd.DaemonRestartedMsg{Err: fmt.Errorf("failed")}},
+		{"success", ipccmd.DaemonRestartedMsg{Client: nc}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -89,8 +90,9 @@ func TestDaemonRestartDone(t *testing.T) {
 			if u.dialog.restarting {
 				t.Error("restarting should be false")
 			}
-			if (cmd != nil) != tt.wantCmd {
-				t.Errorf("cmd nil=%v, wantCmd=%v", cmd == nil, tt.wantCmd)
+			// 失敗・成功のどちらもトーストで通知し、成功時は再読み込みも行う
+			if cmd == nil || len(u.notices.Active()) != 1 {
+				t.Errorf("cmd nil=%v, toasts=%d, want a toast", cmd == nil, len(u.notices.Active()))
 			}
 			if got := u.dashboard.LogLineCount(); got != 1 {
 				t.Errorf("LogLineCount() = %d, want 1", got)
@@ -100,7 +102,7 @@ func TestDaemonRestartDone(t *testing.T) {
 	// success ケースでクライアントが入れ替わることを確認
 	m := NewMainModel(client.NewIPCClient("/tmp/old.sock"), "2.0.0", "/tmp/test")
 	m.dashboard.SetSize(80, 24)
-	result, _ := m.Update(daemonRestartDoneMsg{newClient: nc})
+	result, _ := m.Update(ipccmd.DaemonRestartedMsg{Client: nc})
 	u := result.(MainModel)
 	if u.client != nc || u.subscriptionID != "" {
 		t.Error("client should be replaced and subscriptionID reset")
@@ -143,62 +145,31 @@ func TestView_ShowsDialogOverlays(t *testing.T) {
 	t.Run("update_notify", func(t *testing.T) {
 		m := newTestModel("1.0.0")
 		m.width, m.height = 80, 24
-		m.dialog.showUpdateNotify = true
-		m.dialog.updateNotifyDialog = molecules.NewInfoDialog("MolePort 1.1.0 is available")
-		if !strings.Contains(m.View(), "MolePort 1.1.0 is available") {
+		m.dialog.updateNotice.Receive(tui.UpdateCheckDoneMsg{UpdateAvailable: true, CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}, false)
+		if !strings.Contains(m.View(), "1.1.0") {
 			t.Error("View should contain update notify dialog message")
 		}
 	})
 }
 
-func TestUpdateCheckDone(t *testing.T) {
-	tests := []struct {
-		name           string
-		msg            tui.UpdateCheckDoneMsg
-		versionConfirm bool
-		wantDialog     bool
-		wantPending    bool
-	}{
-		{"no_update", tui.UpdateCheckDoneMsg{UpdateAvailable: false}, false, false, false},
-		{"update_available", tui.UpdateCheckDoneMsg{UpdateAvailable: true, CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}, false, true, false},
-		{"error_ignored", tui.UpdateCheckDoneMsg{Err: fmt.Errorf("error")}, false, false, false},
-		{"buffered", tui.UpdateCheckDoneMsg{UpdateAvailable: true, CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}, true, false, true},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			m := newTestModel("1.0.0")
-			m.dialog.showVersionConfirm = tt.versionConfirm
-			result, _ := m.Update(tt.msg)
-			u := result.(MainModel)
-			if u.dialog.showUpdateNotify != tt.wantDialog {
-				t.Errorf("showUpdateNotify = %v, want %v", u.dialog.showUpdateNotify, tt.wantDialog)
-			}
-			if (u.dialog.pendingUpdateCheck != nil) != tt.wantPending {
-				t.Errorf("pendingUpdateCheck nil=%v, wantPending=%v", u.dialog.pendingUpdateCheck == nil, tt.wantPending)
-			}
-		})
-	}
-}
-
-func TestInfoDismissedMsg_ClosesDialog(t *testing.T) {
+func TestUpdateCheckDone_HeldDuringVersionConfirm(t *testing.T) {
 	m := newTestModel("1.0.0")
-	m.dialog.showUpdateNotify = true
-	m.dialog.updateNotifyDialog = molecules.NewInfoDialog("update available")
-	result, _ := m.Update(molecules.InfoDismissedMsg{})
-	if result.(MainModel).dialog.showUpdateNotify {
-		t.Error("showUpdateNotify should be false")
+	m.dialog.showVersionConfirm = true
+	u := updModel(m, tui.UpdateCheckDoneMsg{UpdateAvailable: true, CurrentVersion: "1.0.0", LatestVersion: "1.1.0"})
+	if u.dialog.updateNotice.Visible() || !u.dialog.updateNotice.Pending() {
+		t.Errorf("visible = %v, pending = %v, want false/true", u.dialog.updateNotice.Visible(), u.dialog.updateNotice.Pending())
 	}
 }
 
 func TestVersionConfirmNo_ShowsPendingUpdate(t *testing.T) {
 	m := newTestModel("1.0.0")
 	m.dialog.showVersionConfirm = true
-	m.dialog.pendingUpdateCheck = &tui.UpdateCheckDoneMsg{
+	m.dialog.updateNotice.Receive(tui.UpdateCheckDoneMsg{
 		UpdateAvailable: true, CurrentVersion: "1.0.0", LatestVersion: "1.1.0",
-	}
+	}, true)
 	result, _ := m.Update(molecules.ConfirmResultMsg{Confirmed: false})
 	u := result.(MainModel)
-	if u.dialog.showVersionConfirm || !u.dialog.showUpdateNotify || u.dialog.pendingUpdateCheck != nil {
-		t.Error("expected showVersionConfirm=false, showUpdateNotify=true, pendingUpdateCheck=nil")
+	if u.dialog.showVersionConfirm || !u.dialog.updateNotice.Visible() || u.dialog.updateNotice.Pending() {
+		t.Error("expected the version confirm to close and the held update notice to show")
 	}
 }
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/tui/atoms"
 )
@@ -42,6 +44,7 @@ func TestRenderSessionBadge(t *testing.T) {
 		{"Stopped", core.Stopped, "○"},
 		{"Error", core.SessionError, "✗"},
 		{"Reconnecting", core.SessionReconnecting, "◌"},
+		{"Draining", core.SessionDraining, "◐"},
 		{"Starting", core.Starting, "◌"},
 	}
 
@@ -55,6 +58,36 @@ func TestRenderSessionBadge(t *testing.T) {
 	}
 }
 
+func TestRenderHealthDot(t *testing.T) {
+	if got := atoms.RenderHealthDot(core.HealthUnknown); got != "" {
+		t.Errorf("RenderHealthDot(unknown) = %q, want empty", got)
+	}
+	for _, h := range []core.HostHealth{core.HealthReachable, core.HealthUnreachable} {
+		if got := atoms.RenderHealthDot(h); !strings.Contains(got, "•") {
+			t.Errorf("RenderHealthDot(%q) = %q, want dot", h, got)
+		}
+	}
+}
+
+func TestRenderLatency(t *testing.T) {
+	if got := atoms.RenderLatency(0); got != "" {
+		t.Errorf("RenderLatency(0) = %q, want empty", got)
+	}
+	tests := []struct {
+		rtt  time.Duration
+		want string
+	}{
+		{500 * time.Microsecond, "1ms"},
+		{42 * time.Millisecond, "42ms"},
+		{atoms.LatencyFair, "300ms"},
+	}
+	for _, tt := range tests {
+		if got := atoms.RenderLatency(tt.rtt); !strings.Contains(got, "●") || !strings.Contains(got, tt.want) {
+			t.Errorf("RenderLatency(%v) = %q, want dot and %q", tt.rtt, got, tt.want)
+		}
+	}
+}
+
 func TestRenderPortLabel(t *testing.T) {
 	tests := []struct {
 		port int
@@ -63,7 +96,7 @@ func TestRenderPortLabel(t *testing.T) {
 		{8080, ":8080"},
 		{22, ":22"},
 		{443, ":443"},
-		{0, ":0"},
+		{0, ":auto"},
 	}
 
 	for _, tt := range tests {
@@ -112,6 +145,40 @@ func TestRenderTraffic(t *testing.T) {
 	}
 }
 
+func TestRenderRate(t *testing.T) {
+	if got := atoms.RenderRate(1536); !strings.Contains(got, "⇅") || !strings.Contains(got, "/s") {
+		t.Errorf("RenderRate(1536) = %q, want ⇅ and /s", got)
+	}
+}
+
+func TestRenderSparkline(t *testing.T) {
+	if got := atoms.RenderSparkline(nil, 8); got != "" {
+		t.Errorf("RenderSparkline(nil) = %q, want empty", got)
+	}
+	if got := atoms.RenderSparkline([]float64{0, 0}, 8); !strings.Contains(got, "▁▁") {
+		t.Errorf("RenderSparkline(zeros) = %q, want lowest blocks", got)
+	}
+	got := atoms.RenderSparkline([]float64{999, 0, 50, 100}, 3)
+	if !strings.Contains(got, "▁▅█") || strings.Count(got, "█") != 1 {
+		t.Errorf("RenderSparkline() = %q, want last 3 values scaled to the peak", got)
+	}
+}
+
+func TestRenderTrafficGraph(t *testing.T) {
+	if got := atoms.RenderTrafficGraph(nil, 8, 2); got != "" {
+		t.Errorf("RenderTrafficGraph(nil) = %q, want empty", got)
+	}
+	// 2 行 = 8 段階。古い値は width*2 件を超えた分が切り捨てられる
+	got := atoms.RenderTrafficGraph([]float64{999, 0, 100, 50, 100, 0}, 2, 2)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("RenderTrafficGraph() = %d lines, want 2", len(lines))
+	}
+	if !strings.Contains(lines[0], "⡇⡇") || !strings.Contains(lines[1], "⣿⡇") {
+		t.Errorf("RenderTrafficGraph() = %q, want the last 4 values as an area chart", got)
+	}
+}
+
 func TestRenderDuration(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -164,3 +231,12 @@ func TestRenderDivider(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderHighlight(t *testing.T) {
+	for _, positions := range [][]int{nil, {0, 5}, {0, 1, 2, 3, 4, 5, 6, 7}} {
+		got := atoms.RenderHighlight("prod-web", positions, lipgloss.NewStyle())
+		if plain := ansi.Strip(got); plain != "prod-web" {
+			t.Errorf("RenderHighlight(%v) = %q, want text prod-web", positions, plain)
+		}
+	}
+}
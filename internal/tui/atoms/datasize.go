@@ -3,6 +3,7 @@ package atoms
 import (
 	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // RenderDataSize はバイト数を人間可読な文字列として描画する。
@@ -12,7 +13,8 @@ func RenderDataSize(bytes int64) string {
 
 // RenderTraffic は送受信トラフィックを ↑/↓ シンボル付きで描画する。
 func RenderTraffic(sent, received int64) string {
-	up := tui.DividerStyle().Render("↑") + RenderDataSize(sent)
-	down := tui.DividerStyle().Render("↓") + RenderDataSize(received)
+	g := theme.Symbols()
+	up := tui.DividerStyle().Render(g.Up) + RenderDataSize(sent)
+	down := tui.DividerStyle().Render(g.Down) + RenderDataSize(received)
 	return up + " " + down
 }
@@ -4,6 +4,7 @@ import (
 	"strings"
 
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // RenderDivider は指定幅の水平区切り線を描画する。
@@ -11,5 +12,5 @@ func RenderDivider(width int) string {
 	if width <= 0 {
 		return ""
 	}
-	return tui.DividerStyle().Render(strings.Repeat("─", width))
+	return tui.DividerStyle().Render(strings.Repeat(theme.Symbols().HLine, width))
 }
@@ -0,0 +1,58 @@
+package atoms
+
+import (
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// brailleBlank は点のない点字文字。点字 1 文字は横 2 × 縦 4 の点で構成される。
+const brailleBlank = '⠀'
+
+// brailleDots は点字 1 文字の左右の列ごとの、上から順の点のビット。
+var brailleDots = [2][4]rune{{0x01, 0x02, 0x04, 0x40}, {0x08, 0x10, 0x20, 0x80}}
+
+// RenderTrafficGraph は values の末尾 width*2 件を、最大値を基準にした高さ height 行の点字の面グラフとして描画する。
+// 1 文字が 2 件の値を表し、新しい値ほど右に描く。値がない場合は空文字列を返す。
+func RenderTrafficGraph(values []float64, width, height int) string {
+	if width <= 0 || height <= 0 {
+		return ""
+	}
+	if len(values) > width*2 {
+		values = values[len(values)-width*2:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	levels := make([]int, len(values))
+	for i, v := range values {
+		if peak > 0 && v > 0 {
+			levels[i] = max(int(v/peak*float64(height*4)+0.5), 1)
+		}
+	}
+
+	lines := make([]string, height)
+	for row := range height {
+		line := make([]rune, (len(levels)+1)/2)
+		for i := range line {
+			line[i] = brailleBlank
+			for col := range 2 {
+				idx := i*2 + col
+				if idx >= len(levels) {
+					break
+				}
+				for dot := range 4 {
+					if (height-1-row)*4+(3-dot) < levels[idx] {
+						line[i] |= brailleDots[col][dot]
+					}
+				}
+			}
+		}
+		lines[row] = tui.ActiveStyle().Render(string(line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,43 @@
+package atoms
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// RenderHighlight は s を style で描画し、positions（昇順の文字の位置）の文字をアクセントカラーの下線付きで強調する。
+func RenderHighlight(s string, positions []int, style lipgloss.Style) string {
+	if len(positions) == 0 {
+		return style.Render(s)
+	}
+	match := style.Foreground(tui.AccentColor()).Underline(true)
+	var b strings.Builder
+	var run []rune
+	matched, next := false, 0
+	flush := func() {
+		if len(run) == 0 {
+			return
+		}
+		if matched {
+			b.WriteString(match.Render(string(run)))
+		} else {
+			b.WriteString(style.Render(string(run)))
+		}
+		run = run[:0]
+	}
+	for i, r := range []rune(s) {
+		m := next < len(positions) && positions[next] == i
+		if m {
+			next++
+		}
+		if m != matched {
+			flush()
+			matched = m
+		}
+		run = append(run, r)
+	}
+	flush()
+	return b.String()
+}
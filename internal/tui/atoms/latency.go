@@ -0,0 +1,37 @@
+package atoms
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// 往復時間の色分けのしきい値。
+const (
+	// LatencyGood 未満の往復時間は良好（緑）として表示する。
+	LatencyGood = 100 * time.Millisecond
+	// LatencyFair 未満の往復時間はやや遅い（黄）、それ以上は遅い（赤）として表示する。
+	LatencyFair = 300 * time.Millisecond
+)
+
+// RenderLatency は keepalive の往復時間を "● 42ms" の形式で色付きで描画する。未計測（0）の場合は空文字列を返す。
+func RenderLatency(rtt time.Duration) string {
+	if rtt <= 0 {
+		return ""
+	}
+	style := tui.ActiveStyle()
+	switch {
+	case rtt >= LatencyFair:
+		style = tui.ErrorStyle()
+	case rtt >= LatencyGood:
+		style = tui.ReconnectingStyle()
+	}
+	ms := rtt.Milliseconds()
+	if ms == 0 {
+		// 1ms 未満も計測済みであることがわかるよう 1ms と表示する
+		ms = 1
+	}
+	return style.Render(theme.Symbols().Active) + tui.MutedStyle().Render(fmt.Sprintf(" %dms", ms))
+}
@@ -7,6 +7,10 @@ import (
 )
 
 // RenderPortLabel はポート番号をアクセントカラーでフォーマットされた文字列として描画する。
+// 0（開始時に自動で割り当てるポート）は ":auto" と描画する。
 func RenderPortLabel(port int) string {
+	if port == 0 {
+		return tui.ActiveStyle().Render(":auto")
+	}
 	return tui.ActiveStyle().Render(fmt.Sprintf(":%d", port))
 }
@@ -0,0 +1,37 @@
+package atoms
+
+import (
+	"github.com/ousiassllc/moleport/internal/format"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// RenderRate は毎秒バイト数を ⇅ シンボル付きのレートとして描画する。
+func RenderRate(bytesPerSec float64) string {
+	return tui.DividerStyle().Render(theme.Symbols().UpDown) + tui.MutedStyle().Render(format.Bytes(int64(bytesPerSec))+"/s")
+}
+
+// RenderSparkline は values の末尾 width 件を、最大値を基準にしたブロック文字の推移として描画する。
+// 値がない場合は空文字列を返す。
+func RenderSparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	sparkBlocks := theme.Symbols().Spark
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if peak > 0 {
+			level = min(int(v/peak*float64(len(sparkBlocks)-1)+0.5), len(sparkBlocks)-1)
+		}
+		line[i] = sparkBlocks[level]
+	}
+	return tui.ActiveStyle().Render(string(line))
+}
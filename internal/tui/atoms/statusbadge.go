@@ -3,30 +3,42 @@ package atoms
 import (
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
-// 接続状態に対応するシンボルの定義
-var connectionSymbols = map[core.ConnectionState]string{
-	core.Connected:       "●",
-	core.Disconnected:    "○",
-	core.ConnectionError: "✗",
-	core.Reconnecting:    "◌",
-	core.Connecting:      "◌",
-	core.PendingAuth:     "◎",
+// connectionSymbol は接続状態に対応するシンボルを返す。
+func connectionSymbol(state core.ConnectionState) (string, bool) {
+	g := theme.Symbols()
+	symbols := map[core.ConnectionState]string{
+		core.Connected:       g.Active,
+		core.Disconnected:    g.Inactive,
+		core.ConnectionError: g.Failed,
+		core.Reconnecting:    g.Pending,
+		core.Connecting:      g.Pending,
+		core.PendingAuth:     g.Auth,
+	}
+	s, ok := symbols[state]
+	return s, ok
 }
 
-// セッション状態に対応するシンボルの定義
-var sessionSymbols = map[core.SessionStatus]string{
-	core.Active:              "●",
-	core.Stopped:             "○",
-	core.SessionError:        "✗",
-	core.SessionReconnecting: "◌",
-	core.Starting:            "◌",
+// sessionSymbol はセッション状態に対応するシンボルを返す。
+func sessionSymbol(status core.SessionStatus) (string, bool) {
+	g := theme.Symbols()
+	symbols := map[core.SessionStatus]string{
+		core.Active:              g.Active,
+		core.Stopped:             g.Inactive,
+		core.SessionError:        g.Failed,
+		core.SessionReconnecting: g.Pending,
+		core.SessionDraining:     g.Draining,
+		core.Starting:            g.Pending,
+	}
+	s, ok := symbols[status]
+	return s, ok
 }
 
 // RenderConnectionBadge は SSH 接続状態をカラーシンボルとして描画する（シンボルのみ）。
 func RenderConnectionBadge(state core.ConnectionState) string {
-	symbol, ok := connectionSymbols[state]
+	symbol, ok := connectionSymbol(state)
 	if !ok {
 		return tui.MutedStyle().Render("?")
 	}
@@ -44,7 +56,7 @@ func RenderConnectionBadge(state core.ConnectionState) string {
 
 // RenderSessionBadge はセッション状態をカラーシンボルとして描画する（シンボルのみ）。
 func RenderSessionBadge(status core.SessionStatus) string {
-	symbol, ok := sessionSymbols[status]
+	symbol, ok := sessionSymbol(status)
 	if !ok {
 		return tui.MutedStyle().Render("?")
 	}
@@ -59,3 +71,15 @@ func RenderSessionBadge(status core.SessionStatus) string {
 		return tui.ReconnectingStyle().Render(symbol)
 	}
 }
+
+// RenderHealthDot はホストの疎通確認の結果を小さなドットとして描画する。未確認の場合は空文字列を返す。
+func RenderHealthDot(health core.HostHealth) string {
+	switch health {
+	case core.HealthReachable:
+		return tui.ActiveStyle().Render(theme.Symbols().Reachable)
+	case core.HealthUnreachable:
+		return tui.ErrorStyle().Render(theme.Symbols().Unreachable)
+	default:
+		return ""
+	}
+}
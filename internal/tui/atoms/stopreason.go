@@ -0,0 +1,23 @@
+package atoms
+
+import (
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/format"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// RenderStopReason はセッションの停止理由と時刻を描画する。理由が空の場合は空文字列を返す。
+func RenderStopReason(reason core.StopReason, at time.Time) string {
+	if reason == core.StopReasonNone {
+		return ""
+	}
+	label := i18n.T("tui.stop_reason." + string(reason))
+	if when := format.Time(at); when != "" {
+		label += " · " + when
+	}
+	return tui.MutedStyle().Render(theme.Symbols().Stop + " " + label)
+}
@@ -0,0 +1,85 @@
+package clipboard
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"golang.org/x/term"
+)
+
+// ErrUnavailable は OSC 52 を書き込む端末もコピーコマンドもない場合のエラー。
+var ErrUnavailable = errors.New("clipboard is not available")
+
+// テストで差し替えるため変数として定義する。
+var (
+	// terminal は OSC 52 を書き込む端末。端末でない場合は nil を返す。
+	// Bubble Tea の描画（標準出力）と混ざらないよう標準エラー出力に書き込む。
+	terminal = func() io.Writer {
+		if term.IsTerminal(int(os.Stderr.Fd())) {
+			return os.Stderr
+		}
+		return nil
+	}
+	// native は pbcopy・xclip・xsel・wl-copy・clip.exe 等のコピーコマンドでコピーする。
+	native = func(text string) error {
+		if clipboard.Unsupported {
+			return ErrUnavailable
+		}
+		return clipboard.WriteAll(text)
+	}
+)
+
+// Copy は text をシステムのクリップボードにコピーする。
+// 端末に OSC 52 のエスケープシーケンスを書き込み（tmux・screen 内ではパススルーで包む）、
+// SSH 経由でない場合は OSC 52 を解釈しない端末に備えてコピーコマンドでもコピーする。
+// どちらの方法でもコピーできなかった場合はエラーを返す。
+func Copy(text string) error {
+	var errs []error
+	copied := false
+	if out := terminal(); out != nil {
+		seq := osc52.New(text)
+		switch {
+		case os.Getenv("TMUX") != "":
+			seq = seq.Tmux()
+		case strings.HasPrefix(os.Getenv("TERM"), "screen"):
+			seq = seq.Screen()
+		}
+		if _, err := seq.WriteTo(out); err != nil {
+			errs = append(errs, err)
+		} else {
+			copied = true
+		}
+	}
+	// SSH 経由の場合、コピーコマンドはリモート側のクリップボードにコピーしてしまうため使わない
+	if os.Getenv("SSH_TTY") == "" && os.Getenv("SSH_CONNECTION") == "" {
+		if err := native(text); err != nil {
+			errs = append(errs, err)
+		} else {
+			copied = true
+		}
+	}
+	switch {
+	case copied:
+		return nil
+	case len(errs) == 0:
+		return ErrUnavailable
+	}
+	return errors.Join(errs...)
+}
+
+// CopyCmd は text をクリップボードにコピーし、結果を LogOutputMsg で通知するコマンドを返す。
+func CopyCmd(text string) tea.Cmd {
+	return func() tea.Msg {
+		if err := Copy(text); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.copy_failed", map[string]any{"Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.copied", map[string]any{"Text": text}), Level: tui.LogSuccess}
+	}
+}
@@ -0,0 +1,99 @@
+package clipboard
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// stub は OSC 52 の書き込み先とコピーコマンドを差し替え、コピーコマンドに渡した文字列を返す。
+func stub(t *testing.T, out io.Writer, nativeErr error) *[]string {
+	t.Helper()
+	for _, env := range []string{"TMUX", "TERM", "SSH_TTY", "SSH_CONNECTION"} {
+		t.Setenv(env, "")
+	}
+	origTerminal, origNative := terminal, native
+	t.Cleanup(func() { terminal, native = origTerminal, origNative })
+	var copied []string
+	terminal = func() io.Writer { return out }
+	native = func(text string) error {
+		copied = append(copied, text)
+		return nativeErr
+	}
+	return &copied
+}
+
+func TestCopy_OSC52AndNative(t *testing.T) {
+	var buf bytes.Buffer
+	copied := stub(t, &buf, nil)
+
+	if err := Copy("127.0.0.1:8080"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	want := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte("127.0.0.1:8080")) + "\x07"
+	if buf.String() != want {
+		t.Errorf("OSC 52 = %q, want %q", buf.String(), want)
+	}
+	if len(*copied) != 1 || (*copied)[0] != "127.0.0.1:8080" {
+		t.Errorf("native copy = %v", *copied)
+	}
+}
+
+func TestCopy_TmuxPassthrough(t *testing.T) {
+	var buf bytes.Buffer
+	stub(t, &buf, nil)
+	t.Setenv("TMUX", "/tmp/tmux-1000/default,1,0")
+
+	if err := Copy("x"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "\x1bPtmux;") {
+		t.Errorf("inside tmux the sequence should be wrapped in a DCS passthrough: %q", buf.String())
+	}
+}
+
+func TestCopy_SSHSkipsNative(t *testing.T) {
+	var buf bytes.Buffer
+	copied := stub(t, &buf, nil)
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+
+	if err := Copy("x"); err != nil {
+		t.Fatalf("Copy: %v", err)
+	}
+	if len(*copied) != 0 {
+		t.Errorf("over SSH the copy command should not run, got %v", *copied)
+	}
+}
+
+func TestCopy_Unavailable(t *testing.T) {
+	nativeErr := errors.New("no xclip")
+	stub(t, nil, nativeErr)
+	if err := Copy("x"); !errors.Is(err, nativeErr) {
+		t.Errorf("err = %v, want %v", err, nativeErr)
+	}
+
+	stub(t, nil, nil)
+	t.Setenv("SSH_TTY", "/dev/pts/0")
+	if err := Copy("x"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("err = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestCopyCmd(t *testing.T) {
+	stub(t, nil, nil)
+	msg, ok := CopyCmd("socks5://127.0.0.1:1080")().(tui.LogOutputMsg)
+	if !ok || msg.Level != tui.LogSuccess || !strings.Contains(msg.Text, "socks5://127.0.0.1:1080") {
+		t.Errorf("got %#v, want a success LogOutputMsg", msg)
+	}
+
+	stub(t, nil, errors.New("no xclip"))
+	msg, ok = CopyCmd("x")().(tui.LogOutputMsg)
+	if !ok || msg.Level != tui.LogError || !strings.Contains(msg.Text, "no xclip") {
+		t.Errorf("got %#v, want an error LogOutputMsg", msg)
+	}
+}
@@ -0,0 +1,2 @@
+// Package clipboard は TUI からシステムのクリップボードに文字列をコピーする機能を提供する。
+package clipboard
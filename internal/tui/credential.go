@@ -0,0 +1,25 @@
+package tui
+
+import (
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// CredentialPrompt はクレデンシャル要求の種別に応じた入力プロンプトを返す。
+func CredentialPrompt(req protocol.CredentialRequestNotification) string {
+	switch req.Type {
+	case "passphrase":
+		return i18n.T("tui.log.credential_passphrase_prompt", map[string]any{"Host": req.Host})
+	case "keyboard-interactive":
+		if len(req.Prompts) > 0 {
+			return req.Prompts[0].Prompt
+		}
+		return i18n.T("tui.log.credential_code_prompt", map[string]any{"Host": req.Host})
+	case "host-key":
+		return i18n.T("tui.log.credential_hostkey_prompt", map[string]any{
+			"Host": req.Host, "KeyType": req.KeyType, "Fingerprint": req.Fingerprint,
+		})
+	default:
+		return i18n.T("tui.log.credential_password_prompt", map[string]any{"Host": req.Host})
+	}
+}
@@ -0,0 +1,22 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestCredentialPrompt(t *testing.T) {
+	kbd := protocol.CredentialRequestNotification{Type: "keyboard-interactive", Prompts: []protocol.PromptData{{Prompt: "OTP: "}}}
+	if got := CredentialPrompt(kbd); got != "OTP: " {
+		t.Errorf("keyboard-interactive prompt = %q, want server prompt", got)
+	}
+	hostKey := protocol.CredentialRequestNotification{Type: "host-key", Host: "prod", KeyType: "ssh-ed25519", Fingerprint: "SHA256:abc"}
+	if got := CredentialPrompt(hostKey); !strings.Contains(got, "SHA256:abc") || !strings.Contains(got, "prod") {
+		t.Errorf("host-key prompt = %q, want host and fingerprint", got)
+	}
+	if got := CredentialPrompt(protocol.CredentialRequestNotification{Type: "password", Host: "prod"}); !strings.Contains(got, "prod") {
+		t.Errorf("password prompt = %q, want host", got)
+	}
+}
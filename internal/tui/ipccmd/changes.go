@@ -0,0 +1,130 @@
+package ipccmd
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// SessionChangesMsg は LoadSessionChanges の結果。Sessions は追加・変更されたセッション、Removed は削除されたルール名。
+// Reset が true の場合、Sessions は全件で、手元の一覧を置き換える。
+type SessionChangesMsg struct {
+	Revision uint64
+	Reset    bool
+	Sessions []core.ForwardSession
+	Removed  []string
+	Groups   []core.ForwardGroup
+	Latency  map[string]time.Duration
+}
+
+// LoadSessionChanges は session.changes で since の後のセッションの差分を取得し、あわせてフォワードグループの一覧と各ホストの往復時間を取得する。
+// 3 つのメソッドは LoadSessions と同様に 1 つのバッチで呼び出す。
+func LoadSessionChanges(c *client.IPCClient, since uint64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.SessionChangesResult
+		var groups protocol.ForwardListGroupsResult
+		var stats protocol.HostStatsResult
+		calls := []client.BatchCall{
+			{Method: "session.changes", Params: protocol.SessionChangesParams{Since: since}, Result: &result},
+			{Method: "forward.listGroups", Result: &groups},
+			{Method: "host.stats", Result: &stats},
+		}
+		err := c.Batch(ctx, calls)
+		if err == nil {
+			err = calls[0].Err
+		}
+		if err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.session_error", map[string]any{"Error": err}), Level: tui.LogError}
+		}
+		sessions := make([]core.ForwardSession, len(result.Sessions))
+		for i, s := range result.Sessions {
+			sessions[i] = sessionInfoToForwardSession(s)
+		}
+		return SessionChangesMsg{
+			Revision: result.Revision, Reset: result.Reset, Sessions: sessions, Removed: result.Removed,
+			Groups: toGroups(groups, calls[1].Err), Latency: toLatency(stats, calls[2].Err),
+		}
+	}
+}
+
+// ApplySessionChanges は current に msg の差分を適用した一覧を返す。current は変更しない。
+// 変更されたセッションは同じ位置で置き換え、新しいセッションは末尾に追加する。
+func ApplySessionChanges(current []core.ForwardSession, msg SessionChangesMsg) []core.ForwardSession {
+	return applyChanges(current, msg.Reset, msg.Sessions, msg.Removed, func(s core.ForwardSession) string { return s.Rule.Name })
+}
+
+// HostChangesMsg は LoadHostChanges の結果。Hosts は追加・変更されたホスト、Removed は削除されたホスト名。
+// Reset が true の場合、Hosts は全件で、手元の一覧を置き換える。
+type HostChangesMsg struct {
+	Revision uint64
+	Reset    bool
+	Hosts    []core.SSHHost
+	Removed  []string
+	Err      error
+}
+
+// LoadHostChanges は host.changes で since の後のホストの差分を取得する。
+func LoadHostChanges(c *client.IPCClient, since uint64) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.HostChangesResult
+		if err := c.Call(ctx, "host.changes", protocol.HostChangesParams{Since: since}, &result); err != nil {
+			return HostChangesMsg{Err: err}
+		}
+		hosts := make([]core.SSHHost, len(result.Hosts))
+		for i, h := range result.Hosts {
+			hosts[i] = convert.ToSSHHost(h)
+		}
+		return HostChangesMsg{Revision: result.Revision, Reset: result.Reset, Hosts: hosts, Removed: result.Removed}
+	}
+}
+
+// ApplyHostChanges は current に msg の差分を適用した一覧を返す。current は変更しない。
+func ApplyHostChanges(current []core.SSHHost, msg HostChangesMsg) []core.SSHHost {
+	return applyChanges(current, msg.Reset, msg.Hosts, msg.Removed, func(h core.SSHHost) string { return h.Name })
+}
+
+// applyChanges は current から removed のキーの要素を除き、changed の要素を同じキーの位置で置き換えた一覧を返す。
+// current にない要素は末尾に追加する。reset が true の場合は changed をそのまま返す。
+func applyChanges[T any](current []T, reset bool, changed []T, removed []string, key func(T) string) []T {
+	if reset {
+		return changed
+	}
+	drop := make(map[string]bool, len(removed))
+	for _, k := range removed {
+		drop[k] = true
+	}
+	pending := make(map[string]T, len(changed))
+	for _, item := range changed {
+		pending[key(item)] = item
+	}
+
+	result := make([]T, 0, len(current)+len(changed))
+	for _, item := range current {
+		k := key(item)
+		if drop[k] {
+			continue
+		}
+		if c, ok := pending[k]; ok {
+			item = c
+			delete(pending, k)
+		}
+		result = append(result, item)
+	}
+	for _, item := range changed {
+		if _, ok := pending[key(item)]; ok {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package ipccmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func sessionNames(sessions []core.ForwardSession) []string {
+	names := make([]string, len(sessions))
+	for i, s := range sessions {
+		names[i] = s.Rule.Name
+	}
+	return names
+}
+
+func TestApplySessionChanges(t *testing.T) {
+	current := []core.ForwardSession{
+		{Rule: core.ForwardRule{Name: "web"}, Status: core.Stopped},
+		{Rule: core.ForwardRule{Name: "db"}},
+		{Rule: core.ForwardRule{Name: "api"}},
+	}
+
+	got := ApplySessionChanges(current, SessionChangesMsg{
+		Sessions: []core.ForwardSession{
+			{Rule: core.ForwardRule{Name: "cache"}},
+			{Rule: core.ForwardRule{Name: "web"}, Status: core.Active, SendRate: 5},
+		},
+		Removed: []string{"db"},
+	})
+	if names, want := sessionNames(got), []string{"web", "api", "cache"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("names = %v, want %v", names, want)
+	}
+	if got[0].Status != core.Active || got[0].SendRate != 5 {
+		t.Errorf("web = %+v, want the changed session", got[0])
+	}
+	if current[0].Status != core.Stopped || len(current) != 3 {
+		t.Error("ApplySessionChanges should not modify current")
+	}
+
+	reset := ApplySessionChanges(current, SessionChangesMsg{Reset: true, Sessions: []core.ForwardSession{{Rule: core.ForwardRule{Name: "only"}}}})
+	if names := sessionNames(reset); !reflect.DeepEqual(names, []string{"only"}) {
+		t.Errorf("reset names = %v, want [only]", names)
+	}
+}
+
+func TestApplyHostChanges(t *testing.T) {
+	current := []core.SSHHost{{Name: "prod"}, {Name: "staging"}}
+	got := ApplyHostChanges(current, HostChangesMsg{
+		Hosts:   []core.SSHHost{{Name: "prod", State: core.Connected}, {Name: "dev"}},
+		Removed: []string{"staging"},
+	})
+	want := []core.SSHHost{{Name: "prod", State: core.Connected}, {Name: "dev"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ApplyHostChanges = %+v, want %+v", got, want)
+	}
+}
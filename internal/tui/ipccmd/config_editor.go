@@ -0,0 +1,80 @@
+package ipccmd
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// LoadConfigEditor は config.schema と config.get を呼んで設定エディタの項目と現在値を取得する。
+func LoadConfigEditor(c *client.IPCClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var schema protocol.ConfigSchemaResult
+		if err := c.Call(ctx, "config.schema", nil, &schema); err != nil {
+			return tui.ConfigEditorLoadedMsg{Err: err}
+		}
+		var current map[string]any
+		if err := c.Call(ctx, "config.get", nil, &current); err != nil {
+			return tui.ConfigEditorLoadedMsg{Err: err}
+		}
+		return tui.ConfigEditorLoadedMsg{Fields: schema.Fields, Values: flattenConfig(nil, nil, current)}
+	}
+}
+
+// ApplyConfig は設定エディタの変更をまとめて config.update で保存する。
+func ApplyConfig(c *client.IPCClient, changes []tui.ConfigChange) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		var result protocol.ConfigUpdateResult
+		err := c.Call(ctx, "config.update", configUpdateParams(changes), &result)
+		return tui.ConfigAppliedMsg{Err: err}
+	}
+}
+
+// flattenConfig は config.get の結果をスカラー値ごとに平坦化して dst に追加する。キーは名前順に並べる。
+func flattenConfig(dst []tui.ConfigValue, keys []string, v any) []tui.ConfigValue {
+	switch v := v.(type) {
+	case map[string]any:
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			dst = flattenConfig(dst, append(keys[:len(keys):len(keys)], name), v[name])
+		}
+	case bool:
+		dst = append(dst, tui.ConfigValue{Keys: keys, Value: strconv.FormatBool(v)})
+	case float64:
+		dst = append(dst, tui.ConfigValue{Keys: keys, Value: strconv.FormatFloat(v, 'f', -1, 64)})
+	case string:
+		dst = append(dst, tui.ConfigValue{Keys: keys, Value: v})
+	}
+	return dst
+}
+
+// configUpdateParams は変更の一覧を config.update の部分更新パラメータと同じ入れ子の形に組み立てる。
+func configUpdateParams(changes []tui.ConfigChange) map[string]any {
+	params := make(map[string]any)
+	for _, ch := range changes {
+		node := params
+		for _, k := range ch.Keys[:len(ch.Keys)-1] {
+			child, ok := node[k].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[k] = child
+			}
+			node = child
+		}
+		node[ch.Keys[len(ch.Keys)-1]] = ch.Value
+	}
+	return params
+}
@@ -0,0 +1,48 @@
+package ipccmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestFlattenConfig(t *testing.T) {
+	current := map[string]any{
+		"language":  "ja",
+		"reconnect": map[string]any{"enabled": true, "max_retries": float64(10)},
+		"hosts": map[string]any{
+			"web.example.com": map[string]any{"reconnect": map[string]any{"max_delay": "30s"}},
+		},
+	}
+
+	got := flattenConfig(nil, nil, current)
+	want := []tui.ConfigValue{
+		{Keys: []string{"hosts", "web.example.com", "reconnect", "max_delay"}, Value: "30s"},
+		{Keys: []string{"language"}, Value: "ja"},
+		{Keys: []string{"reconnect", "enabled"}, Value: "true"},
+		{Keys: []string{"reconnect", "max_retries"}, Value: "10"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("flattenConfig = %+v, want %+v", got, want)
+	}
+}
+
+func TestConfigUpdateParams(t *testing.T) {
+	got := configUpdateParams([]tui.ConfigChange{
+		{Keys: []string{"reconnect", "enabled"}, Value: false},
+		{Keys: []string{"reconnect", "max_retries"}, Value: 3},
+		{Keys: []string{"hosts", "web.example.com", "reconnect", "max_delay"}, Value: "1m"},
+		{Keys: []string{"language"}, Value: "en"},
+	})
+	want := map[string]any{
+		"reconnect": map[string]any{"enabled": false, "max_retries": 3},
+		"hosts": map[string]any{
+			"web.example.com": map[string]any{"reconnect": map[string]any{"max_delay": "1m"}},
+		},
+		"language": "en",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("configUpdateParams = %+v, want %+v", got, want)
+	}
+}
@@ -1,32 +1,32 @@
-package app
+package ipccmd
 
 import (
 	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
 )
 
-// hostInfoToSSHHost は IPC の HostInfo を core.SSHHost に変換する。
-func hostInfoToSSHHost(info protocol.HostInfo) core.SSHHost {
-	return core.SSHHost{
-		Name:               info.Name,
-		HostName:           info.HostName,
-		Port:               info.Port,
-		User:               info.User,
-		State:              protocol.ParseConnectionState(info.State),
-		ActiveForwardCount: info.ActiveForwardCount,
-	}
-}
-
 // sessionInfoToForwardSession は IPC の SessionInfo を core.ForwardSession に変換する。
 func sessionInfoToForwardSession(info protocol.SessionInfo) core.ForwardSession {
 	fwdType, _ := core.ParseForwardType(info.Type)
-	status := protocol.ParseSessionStatus(info.Status)
-	var connectedAt time.Time
+	status := convert.ParseSessionStatus(info.Status)
+	var connectedAt, expiresAt, stoppedAt time.Time
 	if info.ConnectedAt != "" {
 		connectedAt, _ = time.Parse(time.RFC3339, info.ConnectedAt) // パース失敗時はゼロ値（表示上は空欄）
 	}
+	if info.ExpiresAt != "" {
+		expiresAt, _ = time.Parse(time.RFC3339, info.ExpiresAt)
+	}
+	if info.StoppedAt != "" {
+		stoppedAt, _ = time.Parse(time.RFC3339, info.StoppedAt)
+	}
+	var conns []core.ConnInfo
+	for _, c := range info.Connections {
+		since, _ := time.Parse(time.RFC3339, c.Since)
+		conns = append(conns, core.ConnInfo{Peer: c.Peer, Since: since})
+	}
 	return core.ForwardSession{
 		ID: info.ID,
 		Rule: core.ForwardRule{
@@ -37,6 +37,7 @@ func sessionInfoToForwardSession(info protocol.SessionInfo) core.ForwardSession
 			RemoteHost:     info.RemoteHost,
 			RemotePort:     info.RemotePort,
 			RemoteBindAddr: info.RemoteBindAddr,
+			LocalBindAddr:  info.LocalBindAddr,
 		},
 		Status:         status,
 		ConnectedAt:    connectedAt,
@@ -44,5 +45,14 @@ func sessionInfoToForwardSession(info protocol.SessionInfo) core.ForwardSession
 		BytesReceived:  info.BytesReceived,
 		ReconnectCount: info.ReconnectCount,
 		LastError:      info.LastError,
+		BoundPort:      info.BoundPort,
+		ExpiresAt:      expiresAt,
+		SendRate:       info.SendRate,
+		ReceiveRate:    info.ReceiveRate,
+		RateHistory:    info.RateHistory,
+		ActiveConns:    info.ActiveConns,
+		Connections:    conns,
+		StoppedReason:  core.StopReason(info.StoppedReason),
+		StoppedAt:      stoppedAt,
 	}
 }
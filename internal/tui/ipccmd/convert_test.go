@@ -1,4 +1,4 @@
-package app
+package ipccmd
 
 import (
 	"testing"
@@ -6,28 +6,16 @@ import (
 
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/ipc/protocol"
-	"github.com/ousiassllc/moleport/internal/tui"
 )
 
-func TestHostInfoToSSHHost(t *testing.T) {
-	host := hostInfoToSSHHost(protocol.HostInfo{
-		Name: "prod", HostName: "prod.example.com", Port: 22,
-		User: "deploy", State: "connected", ActiveForwardCount: 3,
-	})
-	if host.Name != "prod" || host.HostName != "prod.example.com" || host.Port != 22 {
-		t.Errorf("basic fields: Name=%q HostName=%q Port=%d", host.Name, host.HostName, host.Port)
-	}
-	if host.User != "deploy" || host.State != core.Connected || host.ActiveForwardCount != 3 {
-		t.Errorf("user/state: User=%q State=%v Count=%d", host.User, host.State, host.ActiveForwardCount)
-	}
-}
-
 func TestSessionInfoToForwardSession(t *testing.T) {
 	session := sessionInfoToForwardSession(protocol.SessionInfo{
 		ID: "session-123", Name: "web", Host: "prod", Type: "local",
 		LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
 		Status: "active", ConnectedAt: "2025-01-01T00:00:00Z",
 		BytesSent: 1024, BytesReceived: 2048, ReconnectCount: 1, LastError: "timeout",
+		SendRate: 10, ReceiveRate: 20, RateHistory: []float64{30}, ActiveConns: 2,
+		Connections: []protocol.ConnInfo{{Peer: "127.0.0.1:50000", Since: "2025-01-01T00:00:00Z"}},
 	})
 	wantTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
 	if session.ID != "session-123" || session.Rule.Name != "web" || session.Rule.Host != "prod" {
@@ -46,11 +34,19 @@ func TestSessionInfoToForwardSession(t *testing.T) {
 		t.Errorf("metrics: Sent=%d Recv=%d Recon=%d Err=%q",
 			session.BytesSent, session.BytesReceived, session.ReconnectCount, session.LastError)
 	}
+	if session.SendRate != 10 || session.ReceiveRate != 20 || len(session.RateHistory) != 1 || session.ActiveConns != 2 {
+		t.Errorf("rates: Send=%v Recv=%v History=%v Conns=%d",
+			session.SendRate, session.ReceiveRate, session.RateHistory, session.ActiveConns)
+	}
+	if len(session.Connections) != 1 || session.Connections[0].Peer != "127.0.0.1:50000" || !session.Connections[0].Since.Equal(wantTime) {
+		t.Errorf("Connections = %+v, want 127.0.0.1:50000 since %v", session.Connections, wantTime)
+	}
 }
 
 func TestSessionInfoToForwardSession_EmptyConnectedAt(t *testing.T) {
 	session := sessionInfoToForwardSession(protocol.SessionInfo{
 		ID: "session-456", Name: "db", Host: "staging", Type: "local", Status: "stopped",
+		StoppedReason: "ssh_lost", StoppedAt: "2025-01-01T00:00:00Z",
 	})
 	if !session.ConnectedAt.IsZero() {
 		t.Errorf("ConnectedAt should be zero, got %v", session.ConnectedAt)
@@ -58,6 +54,9 @@ func TestSessionInfoToForwardSession_EmptyConnectedAt(t *testing.T) {
 	if session.Status != core.Stopped {
 		t.Errorf("Status = %v, want %v", session.Status, core.Stopped)
 	}
+	if session.StoppedReason != core.StopReasonSSHLost || !session.StoppedAt.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("stopped = %q at %v, want ssh_lost at 2025-01-01", session.StoppedReason, session.StoppedAt)
+	}
 }
 
 func TestSessionInfoToForwardSession_DynamicType(t *testing.T) {
@@ -68,43 +67,3 @@ func TestSessionInfoToForwardSession_DynamicType(t *testing.T) {
 		t.Errorf("Rule.Type = %v, want %v", session.Rule.Type, core.Dynamic)
 	}
 }
-
-func TestParseConnectionState(t *testing.T) {
-	tests := []struct {
-		input string
-		want  core.ConnectionState
-	}{
-		{"connected", core.Connected}, {"connecting", core.Connecting},
-		{"reconnecting", core.Reconnecting}, {"error", core.ConnectionError},
-		{"disconnected", core.Disconnected}, {"unknown", core.Disconnected}, {"", core.Disconnected},
-	}
-	for _, tt := range tests {
-		if got := protocol.ParseConnectionState(tt.input); got != tt.want {
-			t.Errorf("protocol.ParseConnectionState(%q) = %v, want %v", tt.input, got, tt.want)
-		}
-	}
-}
-
-func TestLogOutputMsgNotDuplicated(t *testing.T) {
-	m := newTestModel("test")
-	result, _ := m.Update(tui.LogOutputMsg{Text: "テストメッセージ"})
-	if got := result.(MainModel).dashboard.LogLineCount(); got != 1 {
-		t.Errorf("LogLineCount() = %d, want 1", got)
-	}
-}
-
-func TestParseSessionStatus(t *testing.T) {
-	tests := []struct {
-		input string
-		want  core.SessionStatus
-	}{
-		{"active", core.Active}, {"starting", core.Starting},
-		{"reconnecting", core.SessionReconnecting}, {"error", core.SessionError},
-		{"stopped", core.Stopped}, {"unknown", core.Stopped}, {"", core.Stopped},
-	}
-	for _, tt := range tests {
-		if got := protocol.ParseSessionStatus(tt.input); got != tt.want {
-			t.Errorf("ParseSessionStatus(%q) = %v, want %v", tt.input, got, tt.want)
-		}
-	}
-}
@@ -0,0 +1,5 @@
+// Package ipccmd は TUI からデーモンへの IPC 呼び出しを Bubble Tea の tea.Cmd として提供する。
+//
+// 各関数は IPC クライアントを受け取り、結果を tui パッケージのメッセージとして返す。
+// app パッケージの MainModel はここで生成したコマンドを発行し、結果メッセージを処理する。
+package ipccmd
@@ -0,0 +1,164 @@
+package ipccmd
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// AddForward は forward.add でルールを追加し、AutoConnect の場合は開始まで行う。
+// 成功した場合はデーモンが確定したルール名を tui.ForwardAddedMsg で返す。
+func AddForward(c *client.IPCClient, msg tui.ForwardAddRequestMsg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		params := protocol.ForwardAddParams{
+			Name:           msg.Name,
+			Host:           msg.Host,
+			Type:           msg.Type.String(),
+			LocalPort:      msg.LocalPort,
+			RemoteHost:     msg.RemoteHost,
+			RemotePort:     msg.RemotePort,
+			RemoteBindAddr: msg.RemoteBindAddr,
+			LocalBindAddr:  msg.LocalBindAddr,
+			AutoConnect:    msg.AutoConnect,
+		}
+		// 開始まで行う場合は、ローカルポートが使用中でないかを先に確認して空いているポートを提案する
+		if msg.AutoConnect && msg.Type != core.Remote {
+			if errMsg := checkLocalPort(ctx, c, msg.LocalPort); errMsg != nil {
+				return *errMsg
+			}
+		}
+		var result protocol.ForwardAddResult
+		if err := c.Call(ctx, "forward.add", params, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_add_error", map[string]any{"Error": err}), Level: tui.LogError}
+		}
+
+		// AutoConnect が設定されている場合はフォワードも開始
+		if msg.AutoConnect {
+			if errMsg := startAndRollback(c, result); errMsg != nil {
+				return *errMsg
+			}
+			return tui.ForwardAddedMsg{Name: result.Name, Started: true}
+		}
+		return tui.ForwardAddedMsg{Name: result.Name}
+	}
+}
+
+// checkLocalPort は forward.checkPort でローカルポートを確認し、使用中の場合はエラーログを返す。
+// 確認自体に失敗した場合は開始時の検査に任せて nil を返す。
+func checkLocalPort(ctx context.Context, c *client.IPCClient, port int) *tui.LogOutputMsg {
+	var result protocol.ForwardCheckPortResult
+	if err := c.Call(ctx, "forward.checkPort", protocol.ForwardCheckPortParams{Port: port}, &result); err != nil || result.Available {
+		return nil
+	}
+	key := "tui.log.port_in_use"
+	if result.Suggestion != 0 {
+		key = "tui.log.port_in_use_suggest"
+	}
+	return &tui.LogOutputMsg{Text: i18n.T(key, map[string]any{"Port": port, "Suggestion": result.Suggestion}), Level: tui.LogError}
+}
+
+// startAndRollback はフォワードの開始を試み、失敗時にルールを削除してロールバックする。
+// 成功時は nil を返す。
+func startAndRollback(c *client.IPCClient, result protocol.ForwardAddResult) *tui.LogOutputMsg {
+	startCtx, startCancel := context.WithTimeout(context.Background(), CredentialTimeout)
+	defer startCancel()
+	startParams := protocol.ForwardStartParams{Name: result.Name}
+	var startResult protocol.ForwardStartResult
+	if err := c.Call(startCtx, "forward.start", startParams, &startResult); err != nil {
+		delCtx, delCancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer delCancel()
+		delParams := protocol.ForwardDeleteParams(result)
+		var delResult protocol.ForwardDeleteResult
+		if delErr := c.Call(delCtx, "forward.delete", delParams, &delResult); delErr != nil {
+			return &tui.LogOutputMsg{Text: i18n.T("tui.log.forward_start_rollback_error", map[string]any{"Name": result.Name, "Error": err, "DeleteError": delErr}), Level: tui.LogError}
+		}
+		return &tui.LogOutputMsg{Text: i18n.T("tui.log.forward_start_error", map[string]any{"Name": result.Name, "Error": err}), Level: tui.LogError}
+	}
+	return nil
+}
+
+// DeleteForward は forward.delete でルールを削除する。
+func DeleteForward(c *client.IPCClient, ruleName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		params := protocol.ForwardDeleteParams{Name: ruleName}
+		var result protocol.ForwardDeleteResult
+		if err := c.Call(ctx, "forward.delete", params, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_delete_error", map[string]any{"Name": ruleName, "Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_deleted", map[string]any{"Name": ruleName}), Level: tui.LogSuccess}
+	}
+}
+
+// StartForward は forward.start でフォワードを開始する。
+func StartForward(c *client.IPCClient, ruleName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), CredentialTimeout)
+		defer cancel()
+		params := protocol.ForwardStartParams{Name: ruleName}
+		var result protocol.ForwardStartResult
+		if err := c.Call(ctx, "forward.start", params, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_start_error", map[string]any{"Name": ruleName, "Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_started", map[string]any{"Name": ruleName}), Level: tui.LogSuccess}
+	}
+}
+
+// StopForward は forward.stop でフォワードを停止する。
+func StopForward(c *client.IPCClient, ruleName string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		params := protocol.ForwardStopParams{Name: ruleName}
+		var result protocol.ForwardStopResult
+		if err := c.Call(ctx, "forward.stop", params, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_stop_error", map[string]any{"Name": ruleName, "Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_stopped", map[string]any{"Name": ruleName}), Level: tui.LogSuccess}
+	}
+}
+
+// BulkForward は選択した複数のルールを一括で開始・停止・削除する。結果はルールごとにログに出力される。
+func BulkForward(c *client.IPCClient, msg tui.ForwardBulkConfirmedMsg) tea.Cmd {
+	op := StartForward
+	switch msg.Action {
+	case tui.BulkStop:
+		op = StopForward
+	case tui.BulkDelete:
+		op = DeleteForward
+	}
+	cmds := make([]tea.Cmd, len(msg.RuleNames))
+	for i, name := range msg.RuleNames {
+		cmds[i] = op(c, name)
+	}
+	return tea.Batch(cmds...)
+}
+
+// UpdateForward は forward.update で編集ウィザードの内容をルールに反映する。稼働中のフォワードはデーモンが再開する。
+func UpdateForward(c *client.IPCClient, msg tui.ForwardUpdateRequestMsg) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), CredentialTimeout)
+		defer cancel()
+		params := protocol.ForwardUpdateParams{Name: msg.Name, LocalPort: &msg.LocalPort}
+		if msg.Type != core.Dynamic {
+			params.RemoteHost, params.RemotePort = &msg.RemoteHost, &msg.RemotePort
+		}
+		var result protocol.ForwardUpdateResult
+		if err := c.Call(ctx, "forward.update", params, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.forward_update_error", map[string]any{"Name": msg.Name, "Error": err}), Level: tui.LogError}
+		}
+		key := "tui.log.forward_updated"
+		if result.Status == protocol.SessionActive {
+			key = "tui.log.forward_updated_restarted"
+		}
+		return tui.LogOutputMsg{Text: i18n.T(key, map[string]any{"Name": result.Name}), Level: tui.LogSuccess}
+	}
+}
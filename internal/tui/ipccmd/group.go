@@ -0,0 +1,59 @@
+package ipccmd
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// toGroups は forward.listGroups の結果をフォワードグループの一覧に変換する。
+// 取得に失敗した場合（グループ未対応のデーモン等）は nil を返し、グループ表示を空にする。
+func toGroups(result protocol.ForwardListGroupsResult, err error) []core.ForwardGroup {
+	if err != nil {
+		return nil
+	}
+	groups := make([]core.ForwardGroup, len(result.Groups))
+	for i, g := range result.Groups {
+		groups[i] = core.ForwardGroup{Name: g.Name, Rules: g.Rules}
+	}
+	return groups
+}
+
+// ToggleGroup は msg.Start に応じてフォワードグループを一括で開始または停止する。
+func ToggleGroup(c *client.IPCClient, msg tui.ForwardGroupToggleMsg) tea.Cmd {
+	if msg.Start {
+		return startGroup(c, msg.Group)
+	}
+	return stopGroup(c, msg.Group)
+}
+
+// startGroup は forward.startGroup でグループの全ルールを開始する。
+func startGroup(c *client.IPCClient, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), CredentialTimeout)
+		defer cancel()
+		var result protocol.ForwardStartGroupResult
+		if err := c.Call(ctx, "forward.startGroup", protocol.ForwardGroupParams{Name: name}, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.group_start_error", map[string]any{"Name": name, "Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.group_started", map[string]any{"Name": name, "Count": len(result.Started)}), Level: tui.LogSuccess}
+	}
+}
+
+// stopGroup は forward.stopGroup でグループのアクティブなルールを停止する。
+func stopGroup(c *client.IPCClient, name string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		var result protocol.ForwardStopGroupResult
+		if err := c.Call(ctx, "forward.stopGroup", protocol.ForwardGroupParams{Name: name}, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.group_stop_error", map[string]any{"Name": name, "Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.group_stopped", map[string]any{"Name": name, "Count": len(result.Stopped)}), Level: tui.LogSuccess}
+	}
+}
@@ -0,0 +1,54 @@
+package ipccmd
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// toLatency は host.stats の結果を接続中ホストごとの keepalive の往復時間に変換する。
+// 取得に失敗した場合（host.stats 未対応のデーモン等）は nil を返し、往復時間の表示を空にする。
+func toLatency(result protocol.HostStatsResult, err error) map[string]time.Duration {
+	if err != nil {
+		return nil
+	}
+	latency := make(map[string]time.Duration, len(result.Hosts))
+	for _, h := range result.Hosts {
+		latency[h.Name] = time.Duration(h.LatencyMs * float64(time.Millisecond))
+	}
+	return latency
+}
+
+// AddHost は host.add で config.yaml にホストを定義し、ホスト一覧を取得し直す。
+func AddHost(c *client.IPCClient, def core.HostDefinition) tea.Cmd {
+	add := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		var result protocol.HostAddResult
+		if err := c.Call(ctx, "host.add", protocol.HostAddParams(def), &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.host_add_error", map[string]any{"Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.host_added", map[string]any{"Name": result.Host.Name}), Level: tui.LogSuccess}
+	}
+	return tea.Sequence(add, LoadHosts(c))
+}
+
+// DeleteHost は host.delete で config.yaml に定義したホストを削除し、ホスト一覧を取得し直す。
+func DeleteHost(c *client.IPCClient, name string) tea.Cmd {
+	del := func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		var result protocol.HostDeleteResult
+		if err := c.Call(ctx, "host.delete", protocol.HostDeleteParams{Name: name}, &result); err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.host_delete_error", map[string]any{"Error": err}), Level: tui.LogError}
+		}
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.host_deleted", map[string]any{"Name": name}), Level: tui.LogSuccess}
+	}
+	return tea.Sequence(del, LoadHosts(c))
+}
@@ -0,0 +1,53 @@
+package ipccmd
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// ImportCandidatesMsg は ssh_config から取り込めるフォワード候補の取得結果。
+type ImportCandidatesMsg struct {
+	Forwards []protocol.ForwardInfo
+	Err      error
+}
+
+// ForwardsImportedMsg は host.importForwards（取り込みまたは見送り）の完了を通知する。
+type ForwardsImportedMsg struct {
+	Count   int
+	Ignored bool
+	Err     error
+}
+
+// LoadImportCandidates は host.importForwards を dry_run で呼び、取り込み候補を取得する。
+func LoadImportCandidates(c *client.IPCClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.HostImportForwardsResult
+		params := protocol.HostImportForwardsParams{DryRun: true}
+		if err := c.Call(ctx, "host.importForwards", params, &result); err != nil {
+			return ImportCandidatesMsg{Err: err}
+		}
+		return ImportCandidatesMsg{Forwards: result.Imported}
+	}
+}
+
+// ImportForwards は keys の候補をルールとして取り込む。ignore が true なら見送りとして記録する。
+func ImportForwards(c *client.IPCClient, keys []string, ignore bool) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		var result protocol.HostImportForwardsResult
+		params := protocol.HostImportForwardsParams{ImportKeys: keys, Ignore: ignore}
+		if err := c.Call(ctx, "host.importForwards", params, &result); err != nil {
+			return ForwardsImportedMsg{Err: err}
+		}
+		if ignore {
+			return ForwardsImportedMsg{Count: len(result.Ignored), Ignored: true}
+		}
+		return ForwardsImportedMsg{Count: len(result.Imported)}
+	}
+}
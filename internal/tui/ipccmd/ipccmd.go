@@ -0,0 +1,195 @@
+package ipccmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/convert"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol/themeinfo"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+const (
+	// ReadTimeout は IPC 読み取り系操作のタイムアウト。
+	ReadTimeout = 5 * time.Second
+	// WriteTimeout は IPC 書き込み系操作のタイムアウト。
+	WriteTimeout = 10 * time.Second
+	// CredentialTimeout はクレデンシャル待ちを含む操作のタイムアウト。
+	// サーバー側の core.CredentialTimeout に IPC オーバーヘッド分のバッファを加算。
+	CredentialTimeout = core.CredentialTimeout + 10*time.Second
+	// ShutdownTimeout はシャットダウン操作のタイムアウト。
+	ShutdownTimeout = 2 * time.Second
+)
+
+// SessionsLoadedMsg は session.list の結果。Revision は一覧の時点のリビジョンで、以降の差分の取得に使う。
+// Groups は同時に取得した forward.listGroups の結果、Latency は host.stats の結果（ホスト名ごとの keepalive の往復時間）。
+type SessionsLoadedMsg struct {
+	Sessions []core.ForwardSession
+	Revision uint64
+	Groups   []core.ForwardGroup
+	Latency  map[string]time.Duration
+}
+
+// SubscriptionStartedMsg はイベント購読の開始を通知する。
+type SubscriptionStartedMsg struct {
+	SubscriptionID string
+}
+
+// LoadHosts は host.list を呼んでホスト一覧を取得する。
+func LoadHosts(c *client.IPCClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.HostListResult
+		if err := c.Call(ctx, "host.list", nil, &result); err != nil {
+			return tui.HostsLoadedMsg{Err: err}
+		}
+		hosts := make([]core.SSHHost, len(result.Hosts))
+		for i, h := range result.Hosts {
+			hosts[i] = convert.ToSSHHost(h)
+		}
+		return tui.HostsLoadedMsg{Hosts: hosts, Revision: result.Revision}
+	}
+}
+
+// LoadSessions は session.list でセッション一覧を取得し、あわせてフォワードグループの一覧と各ホストの往復時間を取得する。
+// 3 つのメソッドは 1 つのバッチで呼び出し、1 往復で取得する。
+func LoadSessions(c *client.IPCClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.SessionListResult
+		var groups protocol.ForwardListGroupsResult
+		var stats protocol.HostStatsResult
+		calls := []client.BatchCall{
+			{Method: "session.list", Result: &result},
+			{Method: "forward.listGroups", Result: &groups},
+			{Method: "host.stats", Result: &stats},
+		}
+		err := c.Batch(ctx, calls)
+		if err == nil {
+			err = calls[0].Err
+		}
+		if err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.session_error", map[string]any{"Error": err}), Level: tui.LogError}
+		}
+		sessions := make([]core.ForwardSession, len(result.Sessions))
+		for i, s := range result.Sessions {
+			sessions[i] = sessionInfoToForwardSession(s)
+		}
+		return SessionsLoadedMsg{Sessions: sessions, Revision: result.Revision, Groups: toGroups(groups, calls[1].Err), Latency: toLatency(stats, calls[2].Err)}
+	}
+}
+
+// SubscribeEvents はイベント購読を開始する。
+func SubscribeEvents(c *client.IPCClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		subID, err := c.Subscribe(ctx, []string{"ssh", "forward", "host", "config", "daemon"})
+		if err != nil {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.subscribe_error", map[string]any{"Error": err}), Level: tui.LogError}
+		}
+		return SubscriptionStartedMsg{SubscriptionID: subID}
+	}
+}
+
+// ListenEvents は IPC イベントチャネルから次の通知を受信する。
+func ListenEvents(c *client.IPCClient) tea.Cmd {
+	events := c.Events()
+	return func() tea.Msg {
+		notif, ok := <-events
+		if !ok {
+			return tui.IPCDisconnectedMsg{}
+		}
+		return tui.IPCNotificationMsg{Notification: notif}
+	}
+}
+
+// LoadConfig は config.get を呼んでテーマ・言語等の TUI が使う設定を取得する。
+func LoadConfig(c *client.IPCClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.ConfigGetResult
+		if err := c.Call(ctx, "config.get", nil, &result); err != nil {
+			return tui.ConfigLoadedMsg{Err: err}
+		}
+		return tui.ConfigLoadedMsg{
+			ThemeBase:      result.TUI.Theme.Base,
+			ThemeAccent:    result.TUI.Theme.Accent,
+			Language:       result.Language,
+			TimeFormat:     result.UI.TimeFormat,
+			SSHConfigPath:  result.SSHConfigPath,
+			AutoRestore:    result.Session.AutoRestore,
+			ForwardPercent: result.TUI.Layout.ForwardPercent,
+			HideLog:        result.TUI.Layout.HideLog,
+			UserThemes:     userPresets(result.TUI.Themes),
+		}
+	}
+}
+
+// userPresets は config.get のユーザーテーマをテーマのプリセットに変換する。
+func userPresets(themes []themeinfo.UserTheme) []theme.Preset {
+	var result []theme.Preset
+	for _, ut := range themes {
+		c := ut.Colors
+		result = append(result, theme.NewUserPreset(ut.Name, ut.Base, theme.Palette{
+			Accent: lipgloss.Color(c.Accent), AccentDim: lipgloss.Color(c.AccentDim),
+			Text: lipgloss.Color(c.Text), Muted: lipgloss.Color(c.Muted), Dim: lipgloss.Color(c.Dim),
+			Error: lipgloss.Color(c.Error), Warning: lipgloss.Color(c.Warning), BgHighlight: lipgloss.Color(c.BgHighlight),
+		}))
+	}
+	return result
+}
+
+// SaveTheme は config.update でテーマ設定を保存する。
+func SaveTheme(c *client.IPCClient, presetID string) tea.Cmd {
+	return func() tea.Msg {
+		p, ok := theme.FindPreset(presetID)
+		if !ok {
+			return tui.ThemeSavedMsg{Err: fmt.Errorf("unknown preset: %s", presetID)}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		base := p.Base
+		accent := p.Accent
+		params := protocol.ConfigUpdateParams{
+			TUI: &protocol.TUIUpdateInfo{
+				Theme: &protocol.ThemeUpdateInfo{
+					Base:   &base,
+					Accent: &accent,
+				},
+			},
+		}
+		var result protocol.ConfigUpdateResult
+		if err := c.Call(ctx, "config.update", params, &result); err != nil {
+			return tui.ThemeSavedMsg{Err: err}
+		}
+		return tui.ThemeSavedMsg{}
+	}
+}
+
+// SaveLang は config.update で言語設定を保存する。
+func SaveLang(c *client.IPCClient, lang string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		params := protocol.ConfigUpdateParams{
+			Language: &lang,
+		}
+		var result protocol.ConfigUpdateResult
+		if err := c.Call(ctx, "config.update", params, &result); err != nil {
+			return tui.LangSavedMsg{Err: fmt.Errorf("config.update: %w", err)}
+		}
+		return tui.LangSavedMsg{}
+	}
+}
@@ -0,0 +1,38 @@
+package ipccmd
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// logViewerTail はログビューアを開いたときに読み込む直近のログの件数。
+const logViewerTail = 500
+
+// SubscribeLogs は debug 以上のデーモンのログの購読を開始する。以降のログは event.log 通知で届く。
+func SubscribeLogs(c *client.IPCClient) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.LogsSubscribeResult
+		params := protocol.LogsSubscribeParams{Level: "debug", Tail: logViewerTail}
+		if err := c.Call(ctx, protocol.MethodLogsSubscribe, params, &result); err != nil {
+			return tui.LogsSubscribedMsg{Err: err}
+		}
+		return tui.LogsSubscribedMsg{SubscriptionID: result.SubscriptionID, Records: result.Records}
+	}
+}
+
+// UnsubscribeLogs はログの購読を解除する。失敗しても接続を閉じた時点で解除されるため結果は通知しない。
+func UnsubscribeLogs(c *client.IPCClient, subscriptionID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.LogsUnsubscribeResult
+		_ = c.Call(ctx, protocol.MethodLogsUnsubscribe, protocol.LogsUnsubscribeParams{SubscriptionID: subscriptionID}, &result)
+		return nil
+	}
+}
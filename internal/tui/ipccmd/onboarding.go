@@ -0,0 +1,41 @@
+package ipccmd
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// SaveOnboarding は config.update でセットアップウィザードで選んだ言語・テーマ・ssh_config のパス・自動復元の設定をまとめて保存する。
+func SaveOnboarding(c *client.IPCClient, msg tui.OnboardingDoneMsg) tea.Cmd {
+	return func() tea.Msg {
+		saved := tui.OnboardingSavedMsg{SSHConfigChanged: msg.SSHConfigChanged, AddForward: msg.AddForward}
+		p, ok := theme.FindPreset(msg.PresetID)
+		if !ok {
+			saved.Err = fmt.Errorf("unknown preset: %s", msg.PresetID)
+			return saved
+		}
+		params := protocol.ConfigUpdateParams{
+			Language: &msg.Lang,
+			Session:  &protocol.SessionCfgUpdateInfo{AutoRestore: &msg.AutoRestore},
+			TUI: &protocol.TUIUpdateInfo{
+				Theme: &protocol.ThemeUpdateInfo{Base: &p.Base, Accent: &p.Accent},
+			},
+		}
+		if msg.SSHConfigChanged {
+			params.SSHConfigPath = &msg.SSHConfigPath
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		var result protocol.ConfigUpdateResult
+		if err := c.Call(ctx, "config.update", params, &result); err != nil {
+			saved.Err = fmt.Errorf("config.update: %w", err)
+		}
+		return saved
+	}
+}
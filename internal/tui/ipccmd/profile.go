@@ -0,0 +1,67 @@
+package ipccmd
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// ProfileSwitchedMsg はプロファイル切り替えの結果。
+// 切り替え先がない場合は Profile が現在のプロファイルのまま SubscriptionID が空になる。
+type ProfileSwitchedMsg struct {
+	Profile        string
+	SubscriptionID string
+	Err            error
+}
+
+// SwitchProfile は profile.list の一覧で現在の次にあたるプロファイルへ切り替える。
+// 現在のプロファイルのイベント購読 subID を解除し、切り替え先で購読し直す。
+func SwitchProfile(c *client.IPCClient, subID string) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), WriteTimeout)
+		defer cancel()
+		current := currentProfile(c)
+		var result protocol.ProfileListResult
+		if err := c.Call(ctx, protocol.MethodProfileList, nil, &result); err != nil {
+			return ProfileSwitchedMsg{Profile: current, Err: err}
+		}
+		next := nextProfile(result.Profiles, current)
+		if next == current {
+			return ProfileSwitchedMsg{Profile: current}
+		}
+
+		if subID != "" {
+			_ = c.Unsubscribe(ctx, subID) // ベストエフォート: 旧プロファイルの購読は切断時にも破棄される
+		}
+		c.SetProfile(next)
+		newSubID, err := c.Subscribe(ctx, []string{"ssh", "forward", "host", "config", "daemon"})
+		if err != nil {
+			return ProfileSwitchedMsg{Profile: next, Err: err}
+		}
+		return ProfileSwitchedMsg{Profile: next, SubscriptionID: newSubID}
+	}
+}
+
+// currentProfile はクライアントに設定されたプロファイル名を返す。未設定の場合は既定プロファイル。
+func currentProfile(c *client.IPCClient) string {
+	if p := c.Profile(); p != "" {
+		return p
+	}
+	return protocol.DefaultProfile
+}
+
+// nextProfile は profiles のうち current の次のプロファイル名を返す。末尾の次は先頭に戻る。
+// current が一覧にない場合は先頭を返す。
+func nextProfile(profiles []protocol.ProfileInfo, current string) string {
+	if len(profiles) == 0 {
+		return current
+	}
+	for i, p := range profiles {
+		if p.Name == current {
+			return profiles[(i+1)%len(profiles)].Name
+		}
+	}
+	return profiles[0].Name
+}
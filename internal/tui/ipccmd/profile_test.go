@@ -0,0 +1,28 @@
+package ipccmd
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestNextProfile(t *testing.T) {
+	profiles := []protocol.ProfileInfo{{Name: "default"}, {Name: "personal"}, {Name: "work"}}
+	tests := []struct {
+		current string
+		want    string
+	}{
+		{"default", "personal"},
+		{"personal", "work"},
+		{"work", "default"},
+		{"missing", "default"},
+	}
+	for _, tt := range tests {
+		if got := nextProfile(profiles, tt.current); got != tt.want {
+			t.Errorf("nextProfile(%q) = %q, want %q", tt.current, got, tt.want)
+		}
+	}
+	if got := nextProfile(nil, "work"); got != "work" {
+		t.Errorf("nextProfile(nil) = %q, want work", got)
+	}
+}
@@ -0,0 +1,72 @@
+package ipccmd
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// DaemonManager はデーモンの起動・接続を抽象化するインターフェース。
+// tui パッケージが daemon パッケージに直接依存しないようにする。
+type DaemonManager interface {
+	StartDaemonProcess(configDir string) (int, error)
+	EnsureDaemonWithRetry(configDir string, maxWait time.Duration) (*client.IPCClient, error)
+}
+
+// DaemonRestartedMsg はデーモン再起動の結果。成功した場合は Client が新しいデーモンへの接続になる。
+type DaemonRestartedMsg struct {
+	Client *client.IPCClient
+	Err    error
+}
+
+// RestartDaemon はデーモンを停止して起動し直し、新しいデーモンに接続する。
+// クレデンシャルハンドラー・操作対象プロファイル・読み取り専用スコープは新しいクライアントに引き継ぐ。
+func RestartDaemon(c *client.IPCClient, dm DaemonManager, configDir string) tea.Cmd {
+	credHandler := c.CredentialHandler() // save before shutdown
+	return func() tea.Msg {
+		// 1. デーモンをシャットダウン（失敗してもリスタートを続行する）
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		var result protocol.DaemonShutdownResult
+		if err := c.Call(ctx, "daemon.shutdown", protocol.DaemonShutdownParams{Purge: false}, &result); err != nil {
+			slog.Warn("daemon shutdown failed, proceeding with restart", "error", err)
+		}
+
+		// 2. 旧接続を閉じる
+		_ = c.Close()
+
+		// 3. 新しいデーモンプロセスを起動
+		if _, err := dm.StartDaemonProcess(configDir); err != nil {
+			return DaemonRestartedMsg{Err: fmt.Errorf("%s: %w", i18n.T("tui.log.daemon_start_failed"), err)}
+		}
+
+		// 4. 新しいデーモンに接続
+		newClient, err := dm.EnsureDaemonWithRetry(configDir, 5*time.Second)
+		if err != nil {
+			return DaemonRestartedMsg{Err: fmt.Errorf("%s: %w", i18n.T("tui.log.daemon_connect_failed"), err)}
+		}
+
+		// 5. クレデンシャルハンドラー・操作対象プロファイル・読み取り専用スコープを新しいクライアントに復元
+		if credHandler != nil {
+			newClient.SetCredentialHandler(credHandler)
+		}
+		newClient.SetProfile(c.Profile())
+		if c.IsReadOnly() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			err := newClient.RestrictToReadOnly(ctx)
+			cancel()
+			if err != nil {
+				_ = newClient.Close()
+				return DaemonRestartedMsg{Err: fmt.Errorf("%s: %w", i18n.T("tui.log.daemon_connect_failed"), err)}
+			}
+		}
+
+		return DaemonRestartedMsg{Client: newClient}
+	}
+}
@@ -0,0 +1,67 @@
+package ipccmd
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/client"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// CheckDaemonVersion は daemon.hello でデーモンのプロトコルとバージョンを取得し、TUI の version と比較する。
+func CheckDaemonVersion(c *client.IPCClient, version string) tea.Cmd {
+	return func() tea.Msg {
+		if c == nil {
+			return tui.VersionCheckDoneMsg{Match: true}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		hello, err := c.Hello(ctx, version)
+		if errors.Is(err, client.ErrIncompatibleProtocol) {
+			return tui.VersionCheckDoneMsg{Incompatible: true, DaemonVersion: hello.ServerVersion, TUIVersion: version}
+		}
+		if err != nil {
+			return tui.VersionCheckDoneMsg{Err: err}
+		}
+		daemonVersion := hello.ServerVersion
+		if daemonVersion == "" {
+			// daemon.hello に対応していないデーモンは daemon.status でバージョンを取得する
+			var status protocol.DaemonStatusResult
+			if err := c.Call(ctx, "daemon.status", nil, &status); err != nil {
+				return tui.VersionCheckDoneMsg{Err: err}
+			}
+			daemonVersion = status.Version
+		}
+		if daemonVersion == "dev" || version == "dev" {
+			return tui.VersionCheckDoneMsg{Match: true}
+		}
+		return tui.VersionCheckDoneMsg{
+			Match:         daemonVersion == version,
+			DaemonVersion: daemonVersion,
+			TUIVersion:    version,
+		}
+	}
+}
+
+// CheckLatestVersion はデーモン経由で最新バージョンをチェックする。
+func CheckLatestVersion(c *client.IPCClient, version string) tea.Cmd {
+	return func() tea.Msg {
+		if c == nil || version == "dev" {
+			return tui.UpdateCheckDoneMsg{}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), ReadTimeout)
+		defer cancel()
+		var result protocol.VersionCheckResult
+		if err := c.Call(ctx, "version.check", protocol.VersionCheckParams{}, &result); err != nil {
+			return tui.UpdateCheckDoneMsg{Err: err}
+		}
+		return tui.UpdateCheckDoneMsg{
+			UpdateAvailable: result.UpdateAvailable,
+			CurrentVersion:  result.CurrentVersion,
+			LatestVersion:   result.LatestVersion,
+			ReleaseURL:      result.ReleaseURL,
+		}
+	}
+}
@@ -3,6 +3,7 @@ package tui
 import (
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // KeyMap はアプリケーション全体のキーバインドを定義する。
@@ -23,13 +24,43 @@ type KeyMap struct {
 	Enter      key.Binding
 	Disconnect key.Binding
 	Delete     key.Binding
+	AddHost    key.Binding
+	Info       key.Binding
 	Theme      key.Binding
 	Lang       key.Binding
 	Version    key.Binding
+	// Profile は操作対象プロファイルの切り替えキー。
+	Profile key.Binding
+	// Notifications は通知履歴の表示キー。
+	Notifications key.Binding
+	// Config は設定エディタの表示キー。
+	Config key.Binding
+	// Groups はフォワードパネルのグループ表示の切り替えキー。
+	Groups key.Binding
+	// Select はフォワードパネルで一括操作するルールの選択キー。
+	Select key.Binding
+	// Sort はホスト一覧の並び順の切り替えキー。
+	Sort key.Binding
+	// Edit はフォワードパネルで選択したルールの編集キー。
+	Edit key.Binding
+	// Logs はデーモンのログビューアの表示キー。
+	Logs key.Binding
+	// Copy は選択したフォワードの接続先（127.0.0.1:PORT や socks5:// の URL）をクリップボードにコピーするキー。
+	Copy key.Binding
+	// CopyCommand は選択したフォワードと同等の ssh コマンドラインをクリップボードにコピーするキー。
+	CopyCommand key.Binding
+	// Grow と Shrink はフォーカス中のペインの高さを広げる・狭めるキー。
+	Grow   key.Binding
+	Shrink key.Binding
+	// Zoom はフォーカス中のペインだけを表示する 1 ペイン表示の切り替えキー。
+	Zoom key.Binding
+	// ToggleLog はログパネルの表示・非表示の切り替えキー。
+	ToggleLog key.Binding
 }
 
 // DefaultKeyMap はデフォルトのキーバインドを返す。
 func DefaultKeyMap() KeyMap {
+	g := theme.Symbols()
 	return KeyMap{
 		Tab: key.NewBinding(
 			key.WithKeys("tab"),
@@ -57,11 +88,11 @@ func DefaultKeyMap() KeyMap {
 		),
 		Up: key.NewBinding(
 			key.WithKeys("up", "k"),
-			key.WithHelp("↑/k", i18n.T("tui.keys.up")),
+			key.WithHelp(g.Up+"/k", i18n.T("tui.keys.up")),
 		),
 		Down: key.NewBinding(
 			key.WithKeys("down", "j"),
-			key.WithHelp("↓/j", i18n.T("tui.keys.down")),
+			key.WithHelp(g.Down+"/j", i18n.T("tui.keys.down")),
 		),
 		Enter: key.NewBinding(
 			key.WithKeys("enter"),
@@ -75,6 +106,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("x"),
 			key.WithHelp("x", i18n.T("tui.keys.delete")),
 		),
+		AddHost: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", i18n.T("tui.keys.add_host")),
+		),
+		Info: key.NewBinding(
+			key.WithKeys("i"),
+			key.WithHelp("i", i18n.T("tui.keys.info")),
+		),
 		Theme: key.NewBinding(
 			key.WithKeys("t"),
 			key.WithHelp("t", i18n.T("tui.keys.theme")),
@@ -87,6 +126,62 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("v"),
 			key.WithHelp("v", i18n.T("tui.keys.version")),
 		),
+		Profile: key.NewBinding(
+			key.WithKeys("p"),
+			key.WithHelp("p", i18n.T("tui.keys.profile")),
+		),
+		Notifications: key.NewBinding(
+			key.WithKeys("n"),
+			key.WithHelp("n", i18n.T("tui.keys.notifications")),
+		),
+		Config: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", i18n.T("tui.keys.config")),
+		),
+		Groups: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", i18n.T("tui.keys.groups")),
+		),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("Space", i18n.T("tui.keys.mark")),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", i18n.T("tui.keys.sort")),
+		),
+		Edit: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", i18n.T("tui.keys.edit")),
+		),
+		Logs: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", i18n.T("tui.keys.logs")),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", i18n.T("tui.keys.copy")),
+		),
+		CopyCommand: key.NewBinding(
+			key.WithKeys("Y"),
+			key.WithHelp("Y", i18n.T("tui.keys.copy_command")),
+		),
+		Grow: key.NewBinding(
+			key.WithKeys("+", "="),
+			key.WithHelp("+", i18n.T("tui.keys.grow")),
+		),
+		Shrink: key.NewBinding(
+			key.WithKeys("-"),
+			key.WithHelp("-", i18n.T("tui.keys.shrink")),
+		),
+		Zoom: key.NewBinding(
+			key.WithKeys("z"),
+			key.WithHelp("z", i18n.T("tui.keys.zoom")),
+		),
+		ToggleLog: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", i18n.T("tui.keys.toggle_log")),
+		),
 	}
 }
 
@@ -102,6 +197,6 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Tab, k.Help, k.Search, k.Escape, k.Quit, k.ForceQuit},
 		{k.Up, k.Down},
-		{k.Enter, k.Disconnect, k.Delete, k.Theme, k.Lang, k.Version},
+		{k.Enter, k.Disconnect, k.Delete, k.Edit, k.AddHost, k.Info, k.Theme, k.Lang, k.Version, k.Profile, k.Notifications, k.Config},
 	}
 }
@@ -25,9 +25,22 @@ func TestDefaultKeyMap_AllBindingsHaveKeys(t *testing.T) {
 		{"Enter", km.Enter},
 		{"Disconnect", km.Disconnect},
 		{"Delete", km.Delete},
+		{"Edit", km.Edit},
+		{"AddHost", km.AddHost},
+		{"Info", km.Info},
 		{"Theme", km.Theme},
 		{"Lang", km.Lang},
 		{"Version", km.Version},
+		{"Profile", km.Profile},
+		{"Notifications", km.Notifications},
+		{"Config", km.Config},
+		{"Logs", km.Logs},
+		{"Copy", km.Copy},
+		{"CopyCommand", km.CopyCommand},
+		{"Grow", km.Grow},
+		{"Shrink", km.Shrink},
+		{"Zoom", km.Zoom},
+		{"ToggleLog", km.ToggleLog},
 	}
 
 	for _, b := range bindings {
@@ -77,9 +90,9 @@ func TestDefaultKeyMap_FullHelp(t *testing.T) {
 		t.Errorf("group 1 should have 2 bindings, got %d", len(groups[1]))
 	}
 
-	// グループ3: アクション (Enter, Disconnect, Delete, Theme, Lang, Version)
-	if len(groups[2]) != 6 {
-		t.Errorf("group 2 should have 6 bindings, got %d", len(groups[2]))
+	// グループ3: アクション (Enter, Disconnect, Delete, Edit, AddHost, Info, Theme, Lang, Version, Profile, Notifications, Config)
+	if len(groups[2]) != 12 {
+		t.Errorf("group 2 should have 12 bindings, got %d", len(groups[2]))
 	}
 }
 
@@ -99,9 +112,17 @@ func TestDefaultKeyMap_SpecificKeys(t *testing.T) {
 		{"Enter", km.Enter, "enter"},
 		{"Disconnect", km.Disconnect, "d"},
 		{"Delete", km.Delete, "x"},
+		{"Edit", km.Edit, "e"},
+		{"Sort", km.Sort, "s"},
+		{"AddHost", km.AddHost, "a"},
 		{"Theme", km.Theme, "t"},
 		{"Lang", km.Lang, "l"},
 		{"Version", km.Version, "v"},
+		{"Profile", km.Profile, "p"},
+		{"Config", km.Config, "c"},
+		{"Logs", km.Logs, "L"},
+		{"Copy", km.Copy, "y"},
+		{"CopyCommand", km.CopyCommand, "Y"},
 	}
 
 	for _, tt := range tests {
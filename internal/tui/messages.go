@@ -18,10 +18,11 @@ type HostSelectedMsg struct {
 	Host core.SSHHost
 }
 
-// HostsLoadedMsg はホスト一覧の初期読み込み完了時に発行される。
+// HostsLoadedMsg はホスト一覧の初期読み込み完了時に発行される。Revision は一覧の時点のリビジョンで、以降の差分の取得に使う。
 type HostsLoadedMsg struct {
-	Hosts []core.SSHHost
-	Err   error
+	Hosts    []core.SSHHost
+	Revision uint64
+	Err      error
 }
 
 // HostsReloadedMsg はホスト一覧の再読み込み完了時に発行される。
@@ -35,6 +36,71 @@ type ForwardToggleMsg struct {
 	RuleName string
 }
 
+// SessionDetailRequestMsg は稼働中のフォワードセッションの詳細ページの表示を要求する。
+type SessionDetailRequestMsg struct {
+	RuleName string
+}
+
+// SessionDetailClosedMsg はセッション詳細ページを閉じたことを通知する。
+type SessionDetailClosedMsg struct{}
+
+// ForwardCopyRequestMsg はフォワードの接続先（Command=true の場合は同等の ssh コマンドライン）のコピーを要求する。
+type ForwardCopyRequestMsg struct {
+	Session core.ForwardSession
+	Command bool
+}
+
+// LogViewerClosedMsg はログビューアを閉じたことを通知する。
+type LogViewerClosedMsg struct{}
+
+// HelpClosedMsg はヘルプモーダルを閉じたことを通知する。
+type HelpClosedMsg struct{}
+
+// LogsSubscribedMsg は logs.subscribe の結果。Records は購読開始時点の直近のログ（古い順）。
+type LogsSubscribedMsg struct {
+	SubscriptionID string
+	Records        []protocol.LogRecord
+	Err            error
+}
+
+// LogsUnsubscribeMsg は不要になったログの購読の解除を要求する。
+type LogsUnsubscribeMsg struct {
+	SubscriptionID string
+}
+
+// ForwardGroupToggleMsg はフォワードグループの一括開始（Start=true）または一括停止を要求する。
+type ForwardGroupToggleMsg struct {
+	Group string
+	Start bool
+}
+
+// ForwardGroupStopRequestMsg はフォワードグループの一括停止の確認を要求する。Count はグループ内の稼働中のルールの数。
+type ForwardGroupStopRequestMsg struct {
+	Group string
+	Count int
+}
+
+// BulkAction は選択した複数のルールへの一括操作。
+type BulkAction int
+
+const (
+	BulkStart BulkAction = iota
+	BulkStop
+	BulkDelete
+)
+
+// ForwardBulkRequestMsg は選択した複数のルールへの一括操作の確認を要求する。
+type ForwardBulkRequestMsg struct {
+	Action    BulkAction
+	RuleNames []string
+}
+
+// ForwardBulkConfirmedMsg は選択した複数のルールへの一括操作を確定する。
+type ForwardBulkConfirmedMsg struct {
+	Action    BulkAction
+	RuleNames []string
+}
+
 // ForwardDeleteRequestMsg はフォワーディングルールの削除確認を要求する。
 type ForwardDeleteRequestMsg struct {
 	RuleName string
@@ -45,6 +111,21 @@ type ForwardDeleteConfirmedMsg struct {
 	RuleName string
 }
 
+// HostAddRequestMsg は config.yaml へのホスト定義の追加を要求する。
+type HostAddRequestMsg struct {
+	Host core.HostDefinition
+}
+
+// HostDeleteRequestMsg は config.yaml で定義したホストの削除確認を要求する。
+type HostDeleteRequestMsg struct {
+	Name string
+}
+
+// HostDeleteConfirmedMsg は config.yaml で定義したホストの削除を確定する。
+type HostDeleteConfirmedMsg struct {
+	Name string
+}
+
 // SSHEventMsg は SSH イベントの通知。
 type SSHEventMsg struct {
 	Event core.SSHEvent
@@ -61,10 +142,32 @@ type ForwardAddRequestMsg struct {
 	RemoteHost     string
 	RemotePort     int
 	RemoteBindAddr string
+	LocalBindAddr  string
 	Name           string
 	AutoConnect    bool
 }
 
+// ForwardAddedMsg は forward.add でルールを追加した後に発行される。
+// Name はデーモンが確定したルール名で、名前を省略した場合は生成された名前になる。Started は開始まで行ったか。
+type ForwardAddedMsg struct {
+	Name    string
+	Started bool
+}
+
+// ForwardEditRequestMsg はフォワードパネルで選択したルールの編集を要求する。
+type ForwardEditRequestMsg struct {
+	Rule core.ForwardRule
+}
+
+// ForwardUpdateRequestMsg は編集ウィザード完了時に発行される。Dynamic では RemoteHost と RemotePort を変更しない。
+type ForwardUpdateRequestMsg struct {
+	Name       string
+	Type       core.ForwardType
+	LocalPort  int
+	RemoteHost string
+	RemotePort int
+}
+
 // LogLevel はログ行の種類を表す。
 type LogLevel int
 
@@ -104,47 +207,11 @@ type CredentialSubmitMsg struct {
 	Cancelled bool
 }
 
-// --- テーマ関連メッセージ ---
-
-// ThemeSelectedMsg はテーマ選択ページで確定時に発行される。
-type ThemeSelectedMsg struct {
-	PresetID string
-}
-
-// ThemeCancelledMsg はテーマ選択ページでキャンセル時に発行される。
-type ThemeCancelledMsg struct{}
-
-// ConfigLoadedMsg は config.get IPC の結果。
-type ConfigLoadedMsg struct {
-	ThemeBase   string
-	ThemeAccent string
-	Language    string
-	Err         error
-}
-
-// ThemeSavedMsg はテーマ保存 IPC の完了通知。
-type ThemeSavedMsg struct {
-	Err error
-}
-
-// --- 言語関連メッセージ ---
-
-// LangSelectedMsg は言語選択ページで確定時に発行される。
-type LangSelectedMsg struct {
-	Lang string
-}
-
-// LangCancelledMsg は言語選択ページでキャンセル時に発行される。
-type LangCancelledMsg struct{}
-
-// LangSavedMsg は言語保存 IPC の完了通知。
-type LangSavedMsg struct {
-	Err error
-}
-
 // VersionCheckDoneMsg はバージョンチェック結果を通知するメッセージ。
 type VersionCheckDoneMsg struct {
-	Match         bool
+	Match bool
+	// Incompatible はデーモンの IPC プロトコルが TUI と互換でないことを示す。
+	Incompatible  bool
 	DaemonVersion string
 	TUIVersion    string
 	Err           error
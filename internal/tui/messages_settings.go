@@ -0,0 +1,108 @@
+package tui
+
+import (
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// --- テーマ関連メッセージ ---
+
+// ThemeSelectedMsg はテーマ選択ページで確定時に発行される。
+type ThemeSelectedMsg struct {
+	PresetID string
+}
+
+// ThemeCancelledMsg はテーマ選択ページでキャンセル時に発行される。
+type ThemeCancelledMsg struct{}
+
+// ConfigLoadedMsg は config.get IPC の結果。
+type ConfigLoadedMsg struct {
+	ThemeBase     string
+	ThemeAccent   string
+	Language      string
+	TimeFormat    string
+	SSHConfigPath string
+	AutoRestore   bool
+	// ForwardPercent と HideLog は tui.layout のダッシュボードのペイン配分の設定。
+	ForwardPercent int
+	HideLog        bool
+	// UserThemes は tui.themes で定義したユーザーテーマのプリセット。
+	UserThemes []theme.Preset
+	Err        error
+}
+
+// ThemeSavedMsg はテーマ保存 IPC の完了通知。
+type ThemeSavedMsg struct {
+	Err error
+}
+
+// --- 言語関連メッセージ ---
+
+// LangSelectedMsg は言語選択ページで確定時に発行される。
+type LangSelectedMsg struct {
+	Lang string
+}
+
+// LangCancelledMsg は言語選択ページでキャンセル時に発行される。
+type LangCancelledMsg struct{}
+
+// LangSavedMsg は言語保存 IPC の完了通知。
+type LangSavedMsg struct {
+	Err error
+}
+
+// --- 設定エディタ関連メッセージ ---
+
+// ConfigValue は設定エディタで扱う設定値 1 つ。
+// Keys は YAML のキーの並びで、ドットを含むホスト名のようなマップのキーも 1 要素として保持する。
+type ConfigValue struct {
+	Keys  []string
+	Value string
+}
+
+// ConfigChange は設定エディタで確定した変更 1 つ。Value は bool・int・string のいずれか。
+type ConfigChange struct {
+	Keys  []string
+	Value any
+}
+
+// ConfigEditorLoadedMsg は設定エディタ用の config.schema と config.get の結果。
+type ConfigEditorLoadedMsg struct {
+	Fields []protocol.ConfigFieldSchema
+	Values []ConfigValue
+	Err    error
+}
+
+// ConfigApplyRequestMsg は設定エディタで変更の適用を要求したときに発行される。
+type ConfigApplyRequestMsg struct {
+	Changes []ConfigChange
+}
+
+// ConfigAppliedMsg は設定エディタの変更を config.update で保存した結果。
+type ConfigAppliedMsg struct {
+	Err error
+}
+
+// ConfigEditorClosedMsg は設定エディタを閉じたときに発行される。
+type ConfigEditorClosedMsg struct{}
+
+// --- 初回起動のセットアップ関連メッセージ ---
+
+// OnboardingDoneMsg は初回起動のセットアップウィザードの完了時に発行される。
+type OnboardingDoneMsg struct {
+	Lang          string
+	PresetID      string
+	SSHConfigPath string
+	AutoRestore   bool
+	// SSHConfigChanged は ssh_config のパスを変更したかを表す。パスの変更はデーモンの再起動後に反映される。
+	SSHConfigChanged bool
+	// AddForward は完了後に最初のフォワードを追加するかを表す。
+	AddForward bool
+}
+
+// OnboardingSavedMsg はセットアップウィザードで選んだ設定を config.update で保存した結果。
+type OnboardingSavedMsg struct {
+	SSHConfigChanged bool
+	AddForward       bool
+	Err              error
+}
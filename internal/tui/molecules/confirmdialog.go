@@ -9,6 +9,7 @@ import (
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
 	"github.com/ousiassllc/moleport/internal/tui/atoms"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // ConfirmResultMsg は確認ダイアログの結果を通知するメッセージ。
@@ -73,10 +74,11 @@ func (m ConfirmDialog) View() string {
 		noStyle.Render(" "+i18n.T("tui.confirm.no")+" "),
 	)
 
+	g := theme.Symbols()
 	hints := atoms.RenderKeyHint(
 		key.NewBinding(key.WithKeys("y"), key.WithHelp("y", i18n.T("tui.confirm.yes"))),
 		key.NewBinding(key.WithKeys("n"), key.WithHelp("n", i18n.T("tui.confirm.no"))),
-		key.NewBinding(key.WithKeys("←/→"), key.WithHelp("←/→", i18n.T("tui.confirm.switch_hint"))),
+		key.NewBinding(key.WithKeys("←/→"), key.WithHelp(g.ArrowBack+"/"+g.Arrow, i18n.T("tui.confirm.switch_hint"))),
 	)
 
 	content := lipgloss.JoinVertical(lipgloss.Left,
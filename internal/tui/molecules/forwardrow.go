@@ -6,8 +6,10 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/tui"
 	"github.com/ousiassllc/moleport/internal/tui/atoms"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // ForwardRow はポートフォワーディングセッション1行分の表示を担う。
@@ -33,7 +35,7 @@ func forwardTypeLabel(t core.ForwardType) string {
 }
 
 // View は ForwardRow を描画する。
-// 形式: "● [host] name L :8080 ──▸ remote:80     2h15m  ↑1.2MB ↓340KB"
+// 形式: "● [host] name L :8080 ──▸ remote:80     2h15m  ⏳1h 45m  ↑1.2MB ↓340KB  ⇅12KB/s ▂▃▅▇▅  ↻1  ⏹ <reason>  <last error>"
 func (r ForwardRow) View() string {
 	badge := atoms.RenderSessionBadge(r.Session.Status)
 
@@ -46,6 +48,8 @@ func (r ForwardRow) View() string {
 		maxNameWidth            = 20
 		narrowTerminalThreshold = 80
 		minNameWidth            = 6
+		maxErrorWidth           = 40
+		sparklineWidth          = 10
 	)
 
 	nameLabel := ""
@@ -55,18 +59,15 @@ func (r ForwardRow) View() string {
 		if r.Width > 0 && r.Width < narrowTerminalThreshold {
 			limit = min(limit, max(r.Width/5, minNameWidth))
 		}
-		runes := []rune(name)
-		if len(runes) > limit {
-			name = string(runes[:limit-1]) + "…"
-		}
-		nameLabel = tui.TextStyle().Bold(true).Render(name) + " "
+		nameLabel = tui.TextStyle().Bold(true).Render(truncate(name, limit)) + " "
 	}
 
 	typeLabel := tui.ActiveStyle().Render(forwardTypeLabel(r.Session.Rule.Type))
 
-	localPort := atoms.RenderPortLabel(r.Session.Rule.LocalPort)
+	localPort := atoms.RenderPortLabel(r.Session.ListenPort())
 
-	arrow := tui.DividerStyle().Render("──▸")
+	g := theme.Symbols()
+	arrow := tui.DividerStyle().Render(g.HLine + g.HLine + g.Pointer)
 
 	var route string
 	if r.Session.Rule.Type == core.Dynamic {
@@ -79,7 +80,12 @@ func (r ForwardRow) View() string {
 
 	var uptime string
 	if r.Session.Status == core.Active && !r.Session.ConnectedAt.IsZero() {
-		uptime = atoms.RenderDuration(time.Since(r.Session.ConnectedAt))
+		// relative 以外の表示形式では経過時間ではなく接続開始時刻を表示する
+		if format.TimeFormat() == core.TimeFormatRelative {
+			uptime = atoms.RenderDuration(time.Since(r.Session.ConnectedAt))
+		} else {
+			uptime = tui.MutedStyle().Render(format.Clock(r.Session.ConnectedAt))
+		}
 	}
 
 	traffic := atoms.RenderTraffic(r.Session.BytesSent, r.Session.BytesReceived)
@@ -90,7 +96,48 @@ func (r ForwardRow) View() string {
 	if uptime != "" {
 		row = lipgloss.JoinHorizontal(lipgloss.Top, row, "  ", uptime)
 	}
+	// TTL 付きのセッションは自動停止までの残り時間を表示する
+	if r.Session.Status == core.Active && !r.Session.ExpiresAt.IsZero() {
+		row = lipgloss.JoinHorizontal(lipgloss.Top, row, "  ",
+			tui.WarningStyle().Render(theme.Symbols().Timer+format.Duration(time.Until(r.Session.ExpiresAt))))
+	}
 	row = lipgloss.JoinHorizontal(lipgloss.Top, row, "  ", traffic)
+	// 稼働中のセッションは直近の転送レートと、幅に余裕があればその推移を表示する
+	if r.Session.Status == core.Active {
+		rate := atoms.RenderRate(r.Session.SendRate + r.Session.ReceiveRate)
+		if r.Width == 0 || r.Width >= narrowTerminalThreshold {
+			if spark := atoms.RenderSparkline(r.Session.RateHistory, sparklineWidth); spark != "" {
+				rate += " " + spark
+			}
+		}
+		row = lipgloss.JoinHorizontal(lipgloss.Top, row, "  ", rate)
+	}
+
+	if r.Session.ReconnectCount > 0 {
+		row = lipgloss.JoinHorizontal(lipgloss.Top, row, "  ",
+			tui.MutedStyle().Render(fmt.Sprintf("%s%d", theme.Symbols().Retry, r.Session.ReconnectCount)))
+	}
+	// 停止・エラーのセッションは停止理由と時刻を表示する
+	if r.Session.Status == core.Stopped || r.Session.Status == core.SessionError {
+		if reason := atoms.RenderStopReason(r.Session.StoppedReason, r.Session.StoppedAt); reason != "" {
+			row = lipgloss.JoinHorizontal(lipgloss.Top, row, "  ", reason)
+		}
+	}
+	// 停止理由が分かるよう、エラー・再接続待ちのセッションには直近のエラーを表示する
+	if r.Session.LastError != "" && r.Session.Status != core.Active {
+		row = lipgloss.JoinHorizontal(lipgloss.Top, row, "  ",
+			tui.ErrorStyle().Render(truncate(r.Session.LastError, maxErrorWidth)))
+	}
 
 	return row
 }
+
+// truncate は s が limit 文字を超える場合に末尾を省略記号に置き換えて切り詰める。
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) > limit {
+		ellipsis := theme.Symbols().Ellipsis
+		return string(runes[:max(limit-len([]rune(ellipsis)), 0)]) + ellipsis
+	}
+	return s
+}
@@ -201,12 +201,68 @@ func TestForwardRow_View_WithTraffic(t *testing.T) {
 			Status:        core.Active,
 			BytesSent:     2048,
 			BytesReceived: 4096,
+			SendRate:      1024,
+			ReceiveRate:   1024,
+			RateHistory:   []float64{0, 2048},
 		},
 		Width: 120,
 	}
 
 	out := row.View()
-	if out == "" {
-		t.Fatal("View() with traffic should produce non-empty output")
+	if !strings.Contains(out, "⇅2.0KB/s") || !strings.Contains(out, "▁█") {
+		t.Errorf("View() = %q, want rate and sparkline", out)
+	}
+	row.Width = 60
+	if out := row.View(); !strings.Contains(out, "⇅") || strings.Contains(out, "▁█") {
+		t.Errorf("View() = %q, want rate without sparkline on a narrow terminal", out)
+	}
+}
+
+func TestForwardRow_View_LastError(t *testing.T) {
+	session := core.ForwardSession{
+		Rule:           core.ForwardRule{Name: "web", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		Status:         core.SessionError,
+		ReconnectCount: 2,
+		LastError:      "listener closed: use of closed network connection",
+	}
+
+	out := ForwardRow{Session: session, Width: 120}.View()
+	if !strings.Contains(out, "listener closed") {
+		t.Error("View() should show the last error of a failed session")
+	}
+	if !strings.Contains(out, "↻2") {
+		t.Error("View() should show the reconnect count")
+	}
+
+	session.Status = core.Active
+	if out := (ForwardRow{Session: session, Width: 120}).View(); strings.Contains(out, "listener closed") {
+		t.Error("View() should not show the last error of an active session")
+	}
+}
+
+func TestForwardRow_View_ExpiresAt(t *testing.T) {
+	session := core.ForwardSession{
+		Rule:      core.ForwardRule{Name: "tmp", Type: core.Dynamic, LocalPort: 1080},
+		Status:    core.Active,
+		ExpiresAt: time.Now().Add(90*time.Minute + 30*time.Second),
+	}
+	if out := (ForwardRow{Session: session, Width: 120}).View(); !strings.Contains(out, "⏳1h 30m") {
+		t.Errorf("View() = %q, want remaining time until expiry", out)
+	}
+}
+
+func TestForwardRow_View_StoppedReason(t *testing.T) {
+	session := core.ForwardSession{
+		Rule:          core.ForwardRule{Name: "tmp", Type: core.Dynamic, LocalPort: 1080},
+		Status:        core.Stopped,
+		StoppedReason: core.StopReasonTTL,
+		StoppedAt:     time.Now().Add(-3 * time.Minute),
+	}
+	if out := (ForwardRow{Session: session, Width: 120}).View(); !strings.Contains(out, "⏹") {
+		t.Errorf("View() = %q, want the stop reason of a stopped session", out)
+	}
+	session.Status = core.Active
+	if out := (ForwardRow{Session: session, Width: 120}).View(); strings.Contains(out, "⏹") {
+		t.Errorf("View() = %q, should not show a stop reason for an active session", out)
 	}
 }
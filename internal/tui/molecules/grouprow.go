@@ -0,0 +1,62 @@
+package molecules
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/atoms"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// GroupRow はフォワードグループ1行分の表示を担う。Active はグループ内で稼働中のルール名の集合。
+type GroupRow struct {
+	Group  core.ForwardGroup
+	Active map[string]bool
+}
+
+// ActiveCount はグループ内で稼働中のルール数を返す。
+func (r GroupRow) ActiveCount() int {
+	n := 0
+	for _, name := range r.Group.Rules {
+		if r.Active[name] {
+			n++
+		}
+	}
+	return n
+}
+
+// View は GroupRow を描画する。
+// 形式: "● staging-stack  2/3 active  db, redis, web"（稼働中のルール名は強調表示）
+func (r GroupRow) View() string {
+	const maxNameWidth = 24
+
+	active, total := r.ActiveCount(), len(r.Group.Rules)
+	var badge string
+	switch {
+	case total > 0 && active == total:
+		badge = atoms.RenderSessionBadge(core.Active)
+	case active > 0:
+		badge = tui.WarningStyle().Render(theme.Symbols().Draining)
+	default:
+		badge = atoms.RenderSessionBadge(core.Stopped)
+	}
+
+	rules := make([]string, len(r.Group.Rules))
+	for i, name := range r.Group.Rules {
+		if r.Active[name] {
+			rules[i] = tui.ActiveStyle().Render(name)
+		} else {
+			rules[i] = tui.MutedStyle().Render(name)
+		}
+	}
+
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		badge, " ",
+		tui.TextStyle().Bold(true).Render(truncate(r.Group.Name, maxNameWidth)), "  ",
+		tui.MutedStyle().Render(i18n.T("tui.forward.group_rules", map[string]any{"Active": active, "Total": total})), "  ",
+		strings.Join(rules, tui.MutedStyle().Render(", ")),
+	)
+}
@@ -0,0 +1,24 @@
+package molecules
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestGroupRow_View(t *testing.T) {
+	row := GroupRow{
+		Group:  core.ForwardGroup{Name: "staging-stack", Rules: []string{"db", "redis", "web"}},
+		Active: map[string]bool{"db": true, "web": true},
+	}
+	if got := row.ActiveCount(); got != 2 {
+		t.Errorf("ActiveCount() = %d, want 2", got)
+	}
+	out := row.View()
+	for _, want := range []string{"staging-stack", "2/3", "db", "redis", "web"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() should contain %q, got %q", want, out)
+		}
+	}
+}
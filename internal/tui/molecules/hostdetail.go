@@ -0,0 +1,59 @@
+package molecules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/atoms"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// HostDetail は SSH ホストの詳細（接続先とユーザーが登録したメタデータ）の表示を担う。
+type HostDetail struct {
+	Host  core.SSHHost
+	Width int
+}
+
+// View は HostDetail を描画する。
+func (d HostDetail) View() string {
+	h := d.Host
+	rows := []string{
+		atoms.RenderConnectionBadge(h.State) + " " + tui.TextStyle().Bold(true).Render(h.Name),
+		d.field(i18n.T("tui.host_detail.address"), fmt.Sprintf("%s@%s:%d", h.User, h.HostName, h.Port)),
+	}
+	if h.Source == core.HostSourceConfig {
+		rows = append(rows, d.field(i18n.T("tui.host_detail.source"), "config.yaml"))
+	}
+	if len(h.JumpChain) > 0 {
+		route := append(append([]string{}, h.JumpChain...), h.Name)
+		rows = append(rows, d.field(i18n.T("tui.host_detail.route"), strings.Join(route, " "+theme.Symbols().Arrow+" ")))
+	}
+	if h.Meta.JumpDescription != "" {
+		rows = append(rows, d.field(i18n.T("tui.host_detail.jump"), h.Meta.JumpDescription))
+	}
+	if h.Meta.AuthHint != "" {
+		rows = append(rows, d.field(i18n.T("tui.host_detail.auth_hint"), h.Meta.AuthHint))
+	}
+	if len(h.Meta.Tags) > 0 {
+		rows = append(rows, d.field(i18n.T("tui.host_detail.tags"), strings.Join(h.Meta.Tags, ", ")))
+	}
+	rows = append(rows, "")
+	if h.Meta.Notes != "" {
+		rows = append(rows, tui.MutedStyle().Render(i18n.T("tui.host_detail.notes")+":"))
+		for _, line := range strings.Split(h.Meta.Notes, "\n") {
+			rows = append(rows, "  "+tui.TextStyle().Render(line))
+		}
+	} else {
+		rows = append(rows, tui.MutedStyle().Render(i18n.T("tui.host_detail.no_notes")))
+	}
+	rows = append(rows, "")
+	rows = append(rows, tui.MutedStyle().Render(i18n.T("tui.host_detail.hint")))
+	return strings.Join(rows, "\n")
+}
+
+func (d HostDetail) field(label, value string) string {
+	return tui.MutedStyle().Render(label+": ") + tui.TextStyle().Render(value)
+}
@@ -0,0 +1,49 @@
+package molecules
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// MatchHost はホストが pattern に一致するかと、強調する名前・アドレス（"user@hostname:port"）の文字の位置を返す。
+// 名前・HostName・ユーザーの順に照合し、pattern が空の場合は常に一致する。
+func MatchHost(h core.SSHHost, pattern string) (nameMatch, addrMatch []int, ok bool) {
+	if pattern == "" {
+		return nil, nil, true
+	}
+	if m := fuzzyMatch(pattern, h.Name); m != nil {
+		return m, nil, true
+	}
+	if m := fuzzyMatch(pattern, h.HostName); m != nil {
+		offset := utf8.RuneCountInString(h.User) + 1
+		for i := range m {
+			m[i] += offset
+		}
+		return nil, m, true
+	}
+	if m := fuzzyMatch(pattern, h.User); m != nil {
+		return nil, m, true
+	}
+	return nil, nil, false
+}
+
+// fuzzyMatch は pattern の文字が大文字小文字を区別せずに s に順に現れる場合、一致した s の文字（ルーン）の位置を返す。
+// 一致しない場合は nil を返す。
+func fuzzyMatch(pattern, s string) []int {
+	want := []rune(pattern)
+	if len(want) == 0 {
+		return nil
+	}
+	var pos []int
+	for i, r := range []rune(s) {
+		if unicode.ToLower(r) == unicode.ToLower(want[len(pos)]) {
+			pos = append(pos, i)
+			if len(pos) == len(want) {
+				return pos
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package molecules
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       []int
+	}{
+		{"pw", "prod-web", []int{0, 5}},
+		{"PW", "prod-web", []int{0, 5}},
+		{"wp", "prod-web", nil},
+		{"", "prod-web", nil},
+		{"db", "データdb", []int{3, 4}},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.s); !slices.Equal(got, tt.want) {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestMatchHost(t *testing.T) {
+	h := core.SSHHost{Name: "bastion", User: "ops", HostName: "10.0.0.1", Port: 22}
+	tests := []struct {
+		pattern         string
+		wantName, wantA []int
+		wantOK          bool
+	}{
+		{"", nil, nil, true},
+		{"bst", []int{0, 2, 3}, nil, true},
+		// アドレスは "ops@10.0.0.1:22" のため HostName の位置は "ops@" の分ずれる
+		{"10.1", nil, []int{4, 5, 6, 11}, true},
+		{"ps", nil, []int{1, 2}, true},
+		{"xyz", nil, nil, false},
+	}
+	for _, tt := range tests {
+		name, addr, ok := MatchHost(h, tt.pattern)
+		if ok != tt.wantOK || !slices.Equal(name, tt.wantName) || !slices.Equal(addr, tt.wantA) {
+			t.Errorf("MatchHost(%q) = %v, %v, %v, want %v, %v, %v", tt.pattern, name, addr, ok, tt.wantName, tt.wantA, tt.wantOK)
+		}
+	}
+}
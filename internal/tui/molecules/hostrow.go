@@ -14,10 +14,16 @@ type HostRow struct {
 	Host     core.SSHHost
 	Selected bool
 	Width    int
+	// NameMatch と AddrMatch はホスト一覧のフィルターに一致した名前・アドレスの文字の位置。強調して描画する。
+	NameMatch []int
+	AddrMatch []int
 }
 
 // View は HostRow を描画する。
-// 形式: "● hostname              user@addr:22     2 fwd"
+// 形式: "● hostname              user@addr:22     2 fwd •"
+// config.yaml で定義したホストは名前の後ろに "(config.yaml)" を付ける。
+// 接続中のホストは keepalive で計測した往復時間を色付きで続けて表示する。
+// 末尾のドットはバックグラウンドの疎通確認の結果で、未確認の場合は表示しない。
 func (r HostRow) View() string {
 	badge := atoms.RenderConnectionBadge(r.Host.State)
 
@@ -25,10 +31,13 @@ func (r HostRow) View() string {
 	if r.Selected {
 		nameStyle = nameStyle.Bold(true).Foreground(tui.AccentColor())
 	}
-	name := nameStyle.Render(r.Host.Name)
+	name := atoms.RenderHighlight(r.Host.Name, r.NameMatch, nameStyle)
+	if r.Host.Source == core.HostSourceConfig {
+		name += tui.MutedStyle().Render(" (config.yaml)")
+	}
 
-	addr := tui.MutedStyle().Render(
-		fmt.Sprintf("%s@%s:%d", r.Host.User, r.Host.HostName, r.Host.Port),
+	addr := atoms.RenderHighlight(
+		fmt.Sprintf("%s@%s:%d", r.Host.User, r.Host.HostName, r.Host.Port), r.AddrMatch, tui.MutedStyle(),
 	)
 
 	var forwards string
@@ -40,7 +49,12 @@ func (r HostRow) View() string {
 		forwards = tui.MutedStyle().Render("0 fwd")
 	}
 
-	return lipgloss.JoinHorizontal(lipgloss.Top,
-		badge, " ", name, "  ", addr, "  ", forwards,
-	)
+	parts := []string{badge, " ", name, "  ", addr, "  ", forwards}
+	if latency := atoms.RenderLatency(r.Host.Latency); latency != "" && r.Host.State == core.Connected {
+		parts = append(parts, "  ", latency)
+	}
+	if dot := atoms.RenderHealthDot(r.Host.Health); dot != "" {
+		parts = append(parts, " ", dot)
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, parts...)
 }
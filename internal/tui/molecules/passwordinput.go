@@ -15,7 +15,7 @@ type PasswordSubmitMsg struct {
 }
 
 // PasswordInput はパスワード入力欄を提供する Bubble Tea モデル。
-// 入力文字はマスクされる。
+// Show で表示した場合、入力文字はマスクされる。
 type PasswordInput struct {
 	textInput textinput.Model
 	prompt    string
@@ -36,10 +36,18 @@ func (m *PasswordInput) Show(prompt string) tea.Cmd {
 	m.prompt = prompt
 	m.active = true
 	m.textInput.Reset()
+	m.textInput.EchoMode = textinput.EchoPassword
 	m.textInput.Prompt = tui.ActiveStyle().Render("> ") + " "
 	return m.textInput.Focus()
 }
 
+// ShowPlain は入力文字をマスクせずに入力欄を表示する（ホスト鍵の確認など）。
+func (m *PasswordInput) ShowPlain(prompt string) tea.Cmd {
+	cmd := m.Show(prompt)
+	m.textInput.EchoMode = textinput.EchoNormal
+	return cmd
+}
+
 // Hide はパスワード入力を非表示にする。
 func (m *PasswordInput) Hide() {
 	m.active = false
@@ -1,6 +1,7 @@
 package molecules
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -93,3 +94,22 @@ func TestPasswordInput_InactiveIgnoresInput(t *testing.T) {
 		t.Error("should remain inactive")
 	}
 }
+
+func TestPasswordInput_ShowPlainEchoesInput(t *testing.T) {
+	pi := NewPasswordInput()
+	pi.ShowPlain("Trust?")
+	for _, r := range "once" {
+		pi, _ = pi.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if v := pi.View(); !strings.Contains(v, "once") {
+		t.Errorf("ShowPlain should echo input, got %q", v)
+	}
+
+	pi.Show("Password:")
+	for _, r := range "secret" {
+		pi, _ = pi.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if v := pi.View(); strings.Contains(v, "secret") {
+		t.Errorf("Show after ShowPlain should mask input, got %q", v)
+	}
+}
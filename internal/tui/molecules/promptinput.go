@@ -51,8 +51,8 @@ func (m PromptInput) Update(msg tea.Msg) (PromptInput, tea.Cmd) {
 // View は Bubble Tea の View メソッド。
 func (m PromptInput) View() string {
 	hints := atoms.RenderKeyHint(
-		key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", "実行")),
-		key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", "キャンセル")),
+		key.NewBinding(key.WithKeys("enter"), key.WithHelp("Enter", i18n.T("tui.keys.execute"))),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("Esc", i18n.T("tui.keys.cancel"))),
 	)
 	return m.textInput.View() + "  " + hints
 }
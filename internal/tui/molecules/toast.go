@@ -0,0 +1,86 @@
+package molecules
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// ToastSeverity はトースト通知の重要度を表す。
+type ToastSeverity int
+
+const (
+	ToastInfo ToastSeverity = iota
+	ToastSuccess
+	ToastWarning
+	ToastError
+)
+
+// TTL は重要度ごとの自動消去までの時間を返す。重要度が高いほど長く表示する。
+func (s ToastSeverity) TTL() time.Duration {
+	switch s {
+	case ToastWarning:
+		return 6 * time.Second
+	case ToastError:
+		return 10 * time.Second
+	default:
+		return 3 * time.Second
+	}
+}
+
+// ToastExpiredMsg はトーストの表示期限切れを通知するメッセージ。
+type ToastExpiredMsg struct {
+	ID int
+}
+
+// Toast は画面右上に一時的に表示される通知。
+type Toast struct {
+	ID       int
+	Severity ToastSeverity
+	Text     string
+	Time     time.Time
+}
+
+// Timer は TTL 経過後に ToastExpiredMsg を発行する Cmd を返す。
+func (t Toast) Timer() tea.Cmd {
+	id := t.ID
+	return tea.Tick(t.Severity.TTL(), func(time.Time) tea.Msg {
+		return ToastExpiredMsg{ID: id}
+	})
+}
+
+// Icon は重要度に応じた色付きアイコンを返す。
+func (t Toast) Icon() string {
+	switch t.Severity {
+	case ToastSuccess:
+		return tui.ActiveStyle().Render(theme.Symbols().Check)
+	case ToastWarning:
+		return tui.WarningStyle().Render("!")
+	case ToastError:
+		return tui.ErrorStyle().Render(theme.Symbols().Cross)
+	default:
+		return tui.MutedStyle().Render("i")
+	}
+}
+
+// View はトーストを幅 width の枠付きボックスとして描画する。
+func (t Toast) View(width int) string {
+	border := tui.MutedColor()
+	switch t.Severity {
+	case ToastWarning:
+		border = tui.WarningColor()
+	case ToastError:
+		border = tui.ErrorColor()
+	case ToastSuccess:
+		border = tui.AccentColor()
+	}
+	style := lipgloss.NewStyle().
+		Border(theme.Border()).
+		BorderForeground(border).
+		Padding(0, 1).
+		Width(max(width-2, 1))
+	return style.Render(t.Icon() + " " + tui.TextStyle().Render(t.Text))
+}
@@ -0,0 +1,35 @@
+package molecules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToastSeverity_TTL(t *testing.T) {
+	if !(ToastInfo.TTL() <= ToastWarning.TTL() && ToastWarning.TTL() < ToastError.TTL()) {
+		t.Errorf("TTL should grow with severity: info=%v warning=%v error=%v",
+			ToastInfo.TTL(), ToastWarning.TTL(), ToastError.TTL())
+	}
+	if ToastSuccess.TTL() != ToastInfo.TTL() {
+		t.Errorf("success TTL = %v, want %v", ToastSuccess.TTL(), ToastInfo.TTL())
+	}
+}
+
+func TestToast_View(t *testing.T) {
+	for _, sev := range []ToastSeverity{ToastInfo, ToastSuccess, ToastWarning, ToastError} {
+		toast := Toast{ID: 1, Severity: sev, Text: "forward failed"}
+		view := toast.View(40)
+		if !strings.Contains(view, "forward failed") {
+			t.Errorf("severity %d: view should contain text, got %q", sev, view)
+		}
+		if got := len(strings.Split(view, "\n")); got != 3 {
+			t.Errorf("severity %d: view lines = %d, want 3 (bordered single line)", sev, got)
+		}
+	}
+}
+
+func TestToast_Timer(t *testing.T) {
+	if (Toast{ID: 7}).Timer() == nil {
+		t.Error("Timer should return a command")
+	}
+}
@@ -3,6 +3,7 @@ package molecules
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ousiassllc/moleport/internal/core"
 )
@@ -57,6 +58,28 @@ func TestHostRow_View_Disconnected(t *testing.T) {
 	}
 }
 
+func TestHostRow_View_Health(t *testing.T) {
+	host := core.SSHHost{Name: "bastion", HostName: "10.0.0.1", Port: 22, User: "ops"}
+	if out := (HostRow{Host: host, Width: 120}).View(); strings.Contains(out, "•") {
+		t.Error("View() should not show a health dot before the first probe")
+	}
+	host.Health = core.HealthUnreachable
+	if out := (HostRow{Host: host, Width: 120}).View(); !strings.Contains(out, "•") {
+		t.Error("View() should show a health dot for a probed host")
+	}
+}
+
+func TestHostRow_View_Latency(t *testing.T) {
+	host := core.SSHHost{Name: "bastion", HostName: "10.0.0.1", Port: 22, User: "ops", Latency: 42 * time.Millisecond}
+	if out := (HostRow{Host: host, Width: 120}).View(); strings.Contains(out, "42ms") {
+		t.Error("View() should not show latency for a disconnected host")
+	}
+	host.State = core.Connected
+	if out := (HostRow{Host: host, Width: 120}).View(); !strings.Contains(out, "42ms") {
+		t.Error("View() should show latency for a connected host")
+	}
+}
+
 // ---------------------------------------------------------------------------
 // ConfirmDialog: Init / View
 // ---------------------------------------------------------------------------
@@ -133,3 +156,48 @@ func TestPromptInput_View(t *testing.T) {
 		t.Error("View() should contain prompt character '>'")
 	}
 }
+
+// ---------------------------------------------------------------------------
+// HostDetail.View
+// ---------------------------------------------------------------------------
+
+func TestHostDetail_View_WithMetadata(t *testing.T) {
+	d := HostDetail{
+		Host: core.SSHHost{
+			Name:      "bastion",
+			HostName:  "10.0.0.1",
+			Port:      22,
+			User:      "ops",
+			State:     core.Disconnected,
+			JumpChain: []string{"edge", "jump1"},
+			Meta: core.HostMetadata{
+				Notes:           "line one\nline two",
+				AuthHint:        "use yubikey",
+				JumpDescription: "via office vpn",
+			},
+		},
+		Width: 80,
+	}
+
+	out := d.View()
+	for _, want := range []string{"bastion", "ops@10.0.0.1:22", "edge → jump1 → bastion", "use yubikey", "via office vpn", "line one", "line two"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("View() should contain %q", want)
+		}
+	}
+}
+
+func TestHostDetail_View_NoMetadata(t *testing.T) {
+	d := HostDetail{
+		Host:  core.SSHHost{Name: "plain", HostName: "example.com", Port: 22, User: "me"},
+		Width: 80,
+	}
+
+	out := d.View()
+	if !strings.Contains(out, "plain") {
+		t.Error("View() should contain host name")
+	}
+	if strings.Contains(out, "use yubikey") {
+		t.Error("View() should not contain metadata")
+	}
+}
@@ -0,0 +1,5 @@
+// Package notify は TUI のトースト通知と通知履歴のオーバーレイを提供する。
+//
+// 操作結果のログとデーモンからのイベント通知のうち、利用者の対応が必要なものをトーストに変換する。
+// app パッケージの MainModel は Notifier を保持し、メッセージの転送と描画の合成だけを行う。
+package notify
@@ -0,0 +1,47 @@
+package notify
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+// SSH は SSH イベントのうちエラーと再接続をトーストで通知する。
+func (n *Notifier) SSH(evt protocol.SSHEventNotification) tea.Cmd {
+	if evt.Error != "" {
+		return n.center.Push(molecules.ToastError, i18n.T("tui.notifications.ssh_error", map[string]any{"Host": evt.Host, "Error": evt.Error}))
+	}
+	if evt.Type == protocol.StateReconnecting {
+		return n.center.Push(molecules.ToastWarning, i18n.T("tui.notifications.ssh_reconnecting", map[string]any{"Host": evt.Host}))
+	}
+	return nil
+}
+
+// Forward は転送イベントのうちエラー・再接続・期限切れ間近・ドレインをトーストで通知する。
+func (n *Notifier) Forward(evt protocol.ForwardEventNotification) tea.Cmd {
+	switch evt.Type {
+	case protocol.ForwardEventTypeError:
+		return n.center.Push(molecules.ToastError, i18n.T("tui.notifications.forward_error", map[string]any{"Name": evt.Name, "Error": evt.Error}))
+	case protocol.ForwardEventTypeReconnecting:
+		return n.center.Push(molecules.ToastWarning, i18n.T("tui.notifications.forward_reconnecting", map[string]any{"Name": evt.Name}))
+	case protocol.ForwardEventTypeExpiring:
+		return n.center.Push(molecules.ToastWarning, i18n.T("tui.notifications.forward_expiring", map[string]any{"Name": evt.Name}))
+	case protocol.ForwardEventTypeDraining:
+		return n.center.Push(molecules.ToastInfo, i18n.T("tui.notifications.forward_draining", map[string]any{"Name": evt.Name, "Conns": evt.Conns}))
+	}
+	return nil
+}
+
+// Daemon はデーモンの停止と自動開始の失敗をトーストで通知する。
+func (n *Notifier) Daemon(evt protocol.DaemonEventNotification) tea.Cmd {
+	switch p := evt.AutoStart; evt.Type {
+	case protocol.DaemonEventTypeShuttingDown:
+		return n.center.Push(molecules.ToastWarning, i18n.T("tui.notifications.daemon_shutting_down"))
+	case protocol.DaemonEventTypeAutoStart:
+		if p != nil && p.Status == protocol.AutoStartStatusFailed {
+			return n.center.Push(molecules.ToastError, i18n.T("tui.notifications.autostart_failed", map[string]any{"Name": p.Rule, "Error": p.Error}))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package notify
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+	"github.com/ousiassllc/moleport/internal/tui/organisms"
+)
+
+// Notifier はトースト通知と通知履歴の表示状態を管理する。
+type Notifier struct {
+	center      organisms.NotificationCenter
+	showHistory bool
+}
+
+// New は新しい Notifier を生成する。
+func New() Notifier {
+	return Notifier{center: organisms.NewNotificationCenter()}
+}
+
+// Report はログ出力に対応するトーストを表示する。エラーと成功のみ通知し、それ以外は nil を返す。
+func (n *Notifier) Report(text string, level tui.LogLevel) tea.Cmd {
+	switch level {
+	case tui.LogError:
+		return n.center.Push(molecules.ToastError, text)
+	case tui.LogSuccess:
+		return n.center.Push(molecules.ToastSuccess, text)
+	}
+	return nil
+}
+
+// OpenHistory は通知履歴のオーバーレイを表示する。
+func (n *Notifier) OpenHistory() {
+	n.showHistory = true
+}
+
+// HistoryVisible は通知履歴のオーバーレイを表示中かを返す。
+func (n Notifier) HistoryVisible() bool {
+	return n.showHistory
+}
+
+// Update はトーストの期限切れと、通知履歴の表示中のキー入力を処理する。
+// 履歴は任意のキーで閉じる。メッセージを処理した場合は true を返す。
+func (n *Notifier) Update(msg tea.Msg) bool {
+	switch msg := msg.(type) {
+	case molecules.ToastExpiredMsg:
+		n.center.Dismiss(msg.ID)
+		return true
+	case tea.KeyMsg:
+		if n.showHistory {
+			n.showHistory = false
+			return true
+		}
+	}
+	return false
+}
+
+// Overlay は base の右上に表示中のトーストを重ねて描画する。
+func (n Notifier) Overlay(base string, width int) string {
+	return n.center.Overlay(base, width)
+}
+
+// HistoryOverlay は通知履歴を画面中央に描画する。
+func (n Notifier) HistoryOverlay(width, height int) string {
+	return lipgloss.Place(width, height,
+		lipgloss.Center, lipgloss.Center,
+		n.center.HistoryView(height),
+	)
+}
+
+// Active は表示中のトーストを返す。
+func (n Notifier) Active() []molecules.Toast {
+	return n.center.Active()
+}
+
+// History は通知履歴を古い順に返す。
+func (n Notifier) History() []molecules.Toast {
+	return n.center.History()
+}
@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+func TestNotifier_Report(t *testing.T) {
+	tests := []struct {
+		level tui.LogLevel
+		toast bool
+	}{
+		{tui.LogInfo, false},
+		{tui.LogSuccess, true},
+		{tui.LogError, true},
+	}
+	for _, tt := range tests {
+		n := New()
+		if cmd := n.Report("forward web failed", tt.level); (cmd != nil) != tt.toast || (len(n.Active()) == 1) != tt.toast {
+			t.Errorf("level %d: toast shown = %v, want %v", tt.level, len(n.Active()) == 1, tt.toast)
+		}
+	}
+}
+
+func TestNotifier_Events(t *testing.T) {
+	tests := []struct {
+		name  string
+		push  func(*Notifier) tea.Cmd
+		toast bool
+	}{
+		{"ssh error", func(n *Notifier) tea.Cmd {
+			return n.SSH(protocol.SSHEventNotification{Type: protocol.StateError, Host: "prod", Error: "boom"})
+		}, true},
+		{"ssh reconnecting", func(n *Notifier) tea.Cmd {
+			return n.SSH(protocol.SSHEventNotification{Type: protocol.StateReconnecting, Host: "prod"})
+		}, true},
+		{"ssh connected", func(n *Notifier) tea.Cmd {
+			return n.SSH(protocol.SSHEventNotification{Type: protocol.StateConnected, Host: "prod"})
+		}, false},
+		{"forward error", func(n *Notifier) tea.Cmd {
+			return n.Forward(protocol.ForwardEventNotification{Type: protocol.ForwardEventTypeError, Name: "web", Error: "boom"})
+		}, true},
+		{"forward draining", func(n *Notifier) tea.Cmd {
+			return n.Forward(protocol.ForwardEventNotification{Type: protocol.ForwardEventTypeDraining, Name: "web", Conns: 2})
+		}, true},
+		{"forward started", func(n *Notifier) tea.Cmd {
+			return n.Forward(protocol.ForwardEventNotification{Type: protocol.ForwardEventTypeStarted, Name: "web"})
+		}, false},
+		{"daemon shutting down", func(n *Notifier) tea.Cmd {
+			return n.Daemon(protocol.DaemonEventNotification{Type: protocol.DaemonEventTypeShuttingDown})
+		}, true},
+		{"autostart failed", func(n *Notifier) tea.Cmd {
+			return n.Daemon(protocol.DaemonEventNotification{Type: protocol.DaemonEventTypeAutoStart,
+				AutoStart: &protocol.AutoStartProgress{Rule: "web", Status: protocol.AutoStartStatusFailed}})
+		}, true},
+		{"autostart retrying", func(n *Notifier) tea.Cmd {
+			return n.Daemon(protocol.DaemonEventNotification{Type: protocol.DaemonEventTypeAutoStart,
+				AutoStart: &protocol.AutoStartProgress{Rule: "web", Status: protocol.AutoStartStatusRetrying}})
+		}, false},
+		{"autostart without progress", func(n *Notifier) tea.Cmd {
+			return n.Daemon(protocol.DaemonEventNotification{Type: protocol.DaemonEventTypeAutoStart})
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := New()
+			tt.push(&n)
+			if got := len(n.Active()) == 1; got != tt.toast {
+				t.Errorf("toast shown = %v, want %v", got, tt.toast)
+			}
+		})
+	}
+}
+
+func TestNotifier_ToastExpired(t *testing.T) {
+	n := New()
+	n.Report("boom", tui.LogError)
+	if !n.Update(molecules.ToastExpiredMsg{ID: n.Active()[0].ID}) {
+		t.Fatal("ToastExpiredMsg should be handled")
+	}
+	if len(n.Active()) != 0 || len(n.History()) != 1 {
+		t.Errorf("active = %d, history = %d, want 0/1", len(n.Active()), len(n.History()))
+	}
+}
+
+func TestNotifier_HistoryClosesOnAnyKey(t *testing.T) {
+	n := New()
+	key := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}}
+	if n.Update(key) {
+		t.Error("keys should pass through while the history is hidden")
+	}
+	n.OpenHistory()
+	if !n.Update(key) || n.HistoryVisible() {
+		t.Error("any key should close the history")
+	}
+}
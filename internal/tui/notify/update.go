@@ -0,0 +1,82 @@
+package notify
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+// UpdateNotice は新しいバージョンの公開を知らせるダイアログを管理する。
+// 他のダイアログの表示中に届いた通知は保留し、Flush で表示する。
+type UpdateNotice struct {
+	dialog  molecules.InfoDialog
+	visible bool
+	pending *tui.UpdateCheckDoneMsg
+}
+
+// Receive は最新バージョンチェックの結果を受け取る。エラーと更新がない場合は何もしない。
+// hold が true の場合は表示せずに保留する。
+func (u *UpdateNotice) Receive(msg tui.UpdateCheckDoneMsg, hold bool) {
+	if msg.Err != nil || !msg.UpdateAvailable {
+		return
+	}
+	if hold {
+		u.pending = &msg
+		return
+	}
+	u.show(msg)
+}
+
+// Flush は保留中の通知があれば表示する。
+func (u *UpdateNotice) Flush() {
+	if u.pending == nil {
+		return
+	}
+	pending := *u.pending
+	u.pending = nil
+	u.show(pending)
+}
+
+// Discard は保留中の通知を破棄する。
+func (u *UpdateNotice) Discard() {
+	u.pending = nil
+}
+
+// Visible はダイアログを表示中かを返す。
+func (u UpdateNotice) Visible() bool {
+	return u.visible
+}
+
+// Pending は保留中の通知があるかを返す。
+func (u UpdateNotice) Pending() bool {
+	return u.pending != nil
+}
+
+// Update はキー入力をダイアログに転送し、InfoDismissedMsg でダイアログを閉じる。
+func (u *UpdateNotice) Update(msg tea.Msg) tea.Cmd {
+	if _, ok := msg.(molecules.InfoDismissedMsg); ok {
+		u.visible = false
+		return nil
+	}
+	var cmd tea.Cmd
+	u.dialog, cmd = u.dialog.Update(msg)
+	return cmd
+}
+
+// View はダイアログを画面中央に描画する。
+func (u UpdateNotice) View(width, height int) string {
+	return lipgloss.Place(width, height, lipgloss.Center, lipgloss.Center, u.dialog.View())
+}
+
+func (u *UpdateNotice) show(msg tui.UpdateCheckDoneMsg) {
+	message := i18n.T("tui.update.available", map[string]any{
+		"Latest": msg.LatestVersion, "Current": msg.CurrentVersion,
+	})
+	if msg.ReleaseURL != "" {
+		message += "\n" + msg.ReleaseURL
+	}
+	u.dialog = molecules.NewInfoDialog(message)
+	u.visible = true
+}
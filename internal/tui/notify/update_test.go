@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+func TestUpdateNotice_Receive(t *testing.T) {
+	available := tui.UpdateCheckDoneMsg{UpdateAvailable: true, CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}
+	tests := []struct {
+		name        string
+		msg         tui.UpdateCheckDoneMsg
+		hold        bool
+		wantVisible bool
+		wantPending bool
+	}{
+		{"no_update", tui.UpdateCheckDoneMsg{UpdateAvailable: false}, false, false, false},
+		{"update_available", available, false, true, false},
+		{"error_ignored", tui.UpdateCheckDoneMsg{Err: fmt.Errorf("error")}, false, false, false},
+		{"held", available, true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var u UpdateNotice
+			u.Receive(tt.msg, tt.hold)
+			if u.Visible() != tt.wantVisible || u.Pending() != tt.wantPending {
+				t.Errorf("visible = %v, pending = %v, want %v/%v", u.Visible(), u.Pending(), tt.wantVisible, tt.wantPending)
+			}
+		})
+	}
+}
+
+func TestUpdateNotice_FlushAndDiscard(t *testing.T) {
+	msg := tui.UpdateCheckDoneMsg{UpdateAvailable: true, CurrentVersion: "1.0.0", LatestVersion: "1.1.0"}
+
+	var u UpdateNotice
+	u.Receive(msg, true)
+	u.Flush()
+	if !u.Visible() || u.Pending() {
+		t.Errorf("after Flush: visible = %v, pending = %v, want true/false", u.Visible(), u.Pending())
+	}
+	if !strings.Contains(u.View(80, 24), "1.1.0") {
+		t.Error("View should contain the latest version")
+	}
+
+	var d UpdateNotice
+	d.Receive(msg, true)
+	d.Discard()
+	d.Flush()
+	if d.Visible() || d.Pending() {
+		t.Error("a discarded notice should not be shown")
+	}
+}
+
+func TestUpdateNotice_Dismissed(t *testing.T) {
+	var u UpdateNotice
+	u.Receive(tui.UpdateCheckDoneMsg{UpdateAvailable: true, LatestVersion: "1.1.0"}, false)
+	u.Update(molecules.InfoDismissedMsg{})
+	if u.Visible() {
+		t.Error("InfoDismissedMsg should close the dialog")
+	}
+}
@@ -0,0 +1,225 @@
+package configtree
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// editableTypes は設定エディタで編集できるスキーマの型。object・list・map は編集対象にしない。
+var editableTypes = map[string]bool{"string": true, "bool": true, "int": true, "duration": true}
+
+// Item は編集可能な設定項目 1 つ。
+type Item struct {
+	Keys  []string
+	Field protocol.ConfigFieldSchema
+	Value string
+}
+
+// Name は項目のキー名（パスの末尾）を返す。
+func (it Item) Name() string { return it.Keys[len(it.Keys)-1] }
+
+// Path は項目のキーをドットでつないだパスを返す。
+func (it Item) Path() string { return strings.Join(it.Keys, ".") }
+
+// Diff は未適用の変更 1 つ。
+type Diff struct {
+	Path string
+	Old  string
+	New  string
+}
+
+// section は設定項目の親キーごとのまとまり。
+type section struct {
+	title     string
+	items     []int
+	collapsed bool
+}
+
+// row は描画する 1 行。item が -1 の行はセクションの見出し。
+type row struct {
+	section int
+	item    int
+}
+
+// Tree は設定項目をセクションごとに折りたたみ可能なツリーで表示し、インライン編集を提供する。
+type Tree struct {
+	items    []Item
+	sections []section
+	pending  map[int]string
+	keys     tui.KeyMap
+
+	cursor  int
+	offset  int
+	editing bool
+	input   textinput.Model
+	err     string
+
+	width  int
+	height int
+}
+
+// New は config.schema のフィールドと config.get の値から Tree を生成する。
+// スキーマに対応するスカラー値だけを項目とし、親キーごとのセクションにまとめてスキーマの順に並べる。
+func New(fields []protocol.ConfigFieldSchema, values []tui.ConfigValue) Tree {
+	type indexed struct {
+		order int
+		item  Item
+	}
+	var found []indexed
+	for _, v := range values {
+		idx := matchField(fields, v.Keys)
+		if idx < 0 || !editableTypes[fields[idx].Type] {
+			continue
+		}
+		found = append(found, indexed{order: idx, item: Item{Keys: v.Keys, Field: fields[idx], Value: v.Value}})
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].order < found[j].order })
+
+	input := textinput.New()
+	input.CharLimit = 256
+	t := Tree{pending: make(map[int]string), keys: tui.DefaultKeyMap(), input: input}
+	var grouped [][]Item
+	sectionIndex := make(map[string]int)
+	for _, f := range found {
+		title := strings.Join(f.item.Keys[:len(f.item.Keys)-1], ".")
+		if title == "" {
+			title = i18n.T("tui.config.general")
+		}
+		s, ok := sectionIndex[title]
+		if !ok {
+			s = len(t.sections)
+			sectionIndex[title] = s
+			t.sections = append(t.sections, section{title: title})
+			grouped = append(grouped, nil)
+		}
+		grouped[s] = append(grouped[s], f.item)
+	}
+	// items は表示順に並べ、Diffs・Changes もその順で返す
+	for s, items := range grouped {
+		for _, it := range items {
+			t.sections[s].items = append(t.sections[s].items, len(t.items))
+			t.items = append(t.items, it)
+		}
+	}
+	return t
+}
+
+// matchField は keys に一致するスキーマのフィールドの位置を返す。"*" はマップの任意のキーに一致する。
+func matchField(fields []protocol.ConfigFieldSchema, keys []string) int {
+	for i, f := range fields {
+		segs := strings.Split(f.Path, ".")
+		if len(segs) != len(keys) {
+			continue
+		}
+		match := true
+		for j, seg := range segs {
+			if seg != "*" && seg != keys[j] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i
+		}
+	}
+	return -1
+}
+
+// Len は編集可能な項目数を返す。
+func (t Tree) Len() int { return len(t.items) }
+
+// IsEditing はインライン編集中かを返す。
+func (t Tree) IsEditing() bool { return t.editing }
+
+// HasPending は未適用の変更があるかを返す。
+func (t Tree) HasPending() bool { return len(t.pending) > 0 }
+
+// Diffs は未適用の変更を項目の順に返す。
+func (t Tree) Diffs() []Diff {
+	var diffs []Diff
+	for i, it := range t.items {
+		if v, ok := t.pending[i]; ok {
+			diffs = append(diffs, Diff{Path: it.Path(), Old: it.Value, New: v})
+		}
+	}
+	return diffs
+}
+
+// Changes は未適用の変更を config.update 用に型変換して返す。
+func (t Tree) Changes() []tui.ConfigChange {
+	var changes []tui.ConfigChange
+	for i, it := range t.items {
+		v, ok := t.pending[i]
+		if !ok {
+			continue
+		}
+		var value any = v
+		switch it.Field.Type {
+		case "bool":
+			value = v == "true"
+		case "int":
+			value, _ = strconv.Atoi(v) // 入力時に Validate で検証済み
+		}
+		changes = append(changes, tui.ConfigChange{Keys: it.Keys, Value: value})
+	}
+	return changes
+}
+
+// Revert は未適用の変更をすべて破棄する。
+func (t *Tree) Revert() {
+	t.pending = make(map[int]string)
+	t.err = ""
+}
+
+// Commit は未適用の変更を現在値として確定する。config.update の成功後に呼ぶ。
+func (t *Tree) Commit() {
+	for i, v := range t.pending {
+		t.items[i].Value = v
+	}
+	t.pending = make(map[int]string)
+}
+
+// SetSize はツリーの描画領域のサイズを設定する。
+func (t *Tree) SetSize(width, height int) {
+	t.width = width
+	t.height = max(height, 1)
+	t.input.Width = max(width-30, 10)
+}
+
+// current は項目 i の変更後の値（未変更なら現在値）を返す。
+func (t Tree) current(i int) string {
+	if v, ok := t.pending[i]; ok {
+		return v
+	}
+	return t.items[i].Value
+}
+
+// set は項目 i の変更後の値を設定する。現在値と同じ場合は変更を取り消す。
+func (t *Tree) set(i int, v string) {
+	if v == t.items[i].Value {
+		delete(t.pending, i)
+		return
+	}
+	t.pending[i] = v
+}
+
+// rows は折りたたみ状態を反映した描画行の一覧を返す。
+func (t Tree) rows() []row {
+	var rows []row
+	for s, sec := range t.sections {
+		rows = append(rows, row{section: s, item: -1})
+		if sec.collapsed {
+			continue
+		}
+		for _, i := range sec.items {
+			rows = append(rows, row{section: s, item: i})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,135 @@
+package configtree
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+var testFields = []protocol.ConfigFieldSchema{
+	{Path: "ssh_config_path", Type: "string", Since: "1.0.0"},
+	{Path: "reconnect", Type: "object", Since: "1.0.0"},
+	{Path: "reconnect.enabled", Type: "bool", Default: true, Since: "1.0.0"},
+	{Path: "reconnect.max_retries", Type: "int", Min: float64(0), Since: "1.0.0"},
+	{Path: "hosts", Type: "map", Item: "object", Since: "1.0.0"},
+	{Path: "hosts.*.reconnect.max_delay", Type: "duration", Since: "1.0.0"},
+	{Path: "language", Type: "string", Enum: []string{"en", "ja"}, Since: "1.0.0"},
+}
+
+// newTestTree は testFields に対応する値を持つ Tree を返す。値は config.get と同じく名前順で渡す。
+func newTestTree() Tree {
+	tr := New(testFields, []tui.ConfigValue{
+		{Keys: []string{"hosts", "web.example.com", "reconnect", "max_delay"}, Value: "30s"},
+		{Keys: []string{"language"}, Value: "en"},
+		{Keys: []string{"reconnect", "enabled"}, Value: "true"},
+		{Keys: []string{"reconnect", "max_retries"}, Value: "10"},
+		{Keys: []string{"ssh_config_path"}, Value: "~/.ssh/config"},
+		{Keys: []string{"unknown"}, Value: "ignored"},
+	})
+	tr.SetSize(80, 30)
+	return tr
+}
+
+func press(tr Tree, msgs ...tea.KeyMsg) Tree {
+	for _, msg := range msgs {
+		tr, _ = tr.Update(msg)
+	}
+	return tr
+}
+
+var (
+	down  = tea.KeyMsg{Type: tea.KeyDown}
+	enter = tea.KeyMsg{Type: tea.KeyEnter}
+	esc   = tea.KeyMsg{Type: tea.KeyEsc}
+)
+
+func typeText(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+func TestNew_OrdersBySchemaAndGroupsSections(t *testing.T) {
+	tr := newTestTree()
+
+	var paths []string
+	for _, it := range tr.items {
+		paths = append(paths, it.Path())
+	}
+	wantPaths := []string{"ssh_config_path", "language", "reconnect.enabled", "reconnect.max_retries", "hosts.web.example.com.reconnect.max_delay"}
+	if !reflect.DeepEqual(paths, wantPaths) {
+		t.Fatalf("paths = %v, want %v", paths, wantPaths)
+	}
+	// ssh_config_path と language は同じ全般セクションにまとまる
+	var titles []string
+	for _, s := range tr.sections {
+		titles = append(titles, s.title)
+	}
+	if len(titles) != 3 || titles[1] != "reconnect" || titles[2] != "hosts.web.example.com.reconnect" {
+		t.Errorf("sections = %v", titles)
+	}
+}
+
+func TestUpdate_ToggleCycleAndEdit(t *testing.T) {
+	tr := newTestTree()
+	// 行: [0]全般 [1]ssh_config_path [2]language [3]reconnect [4]enabled [5]max_retries ...
+	tr = press(tr, down, down, enter) // language: en → ja
+	tr = press(tr, down, down, enter) // reconnect.enabled: true → false
+	tr = press(tr, down, enter, typeText("x"), enter)
+	if !tr.IsEditing() || tr.err == "" {
+		t.Fatalf("invalid int should keep editing with an error, editing=%v err=%q", tr.IsEditing(), tr.err)
+	}
+	tr.input.SetValue("3")
+	tr = press(tr, enter)
+	if tr.IsEditing() {
+		t.Fatal("valid value should finish editing")
+	}
+
+	want := []tui.ConfigChange{
+		{Keys: []string{"language"}, Value: "ja"},
+		{Keys: []string{"reconnect", "enabled"}, Value: false},
+		{Keys: []string{"reconnect", "max_retries"}, Value: 3},
+	}
+	if got := tr.Changes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Changes = %+v, want %+v", got, want)
+	}
+	if diffs := tr.Diffs(); len(diffs) != 3 || diffs[0] != (Diff{Path: "language", Old: "en", New: "ja"}) {
+		t.Errorf("Diffs = %+v", diffs)
+	}
+
+	// 元の値に戻すと変更は取り消される
+	tr = press(tr, tea.KeyMsg{Type: tea.KeyUp}, enter)
+	if len(tr.Changes()) != 2 {
+		t.Errorf("toggling back should drop the change, got %+v", tr.Changes())
+	}
+
+	tr.Commit()
+	if tr.HasPending() || tr.items[1].Value != "ja" {
+		t.Errorf("Commit should apply pending values, pending=%v language=%q", tr.pending, tr.items[1].Value)
+	}
+}
+
+func TestUpdate_EscCancelsEdit(t *testing.T) {
+	tr := press(newTestTree(), down, enter, typeText("/tmp/cfg"), esc)
+	if tr.IsEditing() || tr.HasPending() {
+		t.Errorf("Esc should cancel editing without changes, editing=%v pending=%v", tr.IsEditing(), tr.pending)
+	}
+}
+
+func TestUpdate_CollapseSection(t *testing.T) {
+	tr := press(newTestTree(), enter)
+	if got := len(tr.rows()); got != 6 {
+		t.Errorf("rows after collapsing general = %d, want 6", got)
+	}
+	if !strings.Contains(tr.View(), "▸") {
+		t.Error("collapsed section should be rendered with ▸")
+	}
+}
+
+func TestRevert(t *testing.T) {
+	tr := press(newTestTree(), down, down, enter)
+	tr.Revert()
+	if tr.HasPending() {
+		t.Error("Revert should drop all pending changes")
+	}
+}
@@ -0,0 +1,93 @@
+package configtree
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Update はキー入力を処理する。編集中は入力欄に、それ以外はカーソル移動と項目の操作に使う。
+func (t Tree) Update(msg tea.Msg) (Tree, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if t.editing {
+		if ok {
+			switch {
+			case key.Matches(keyMsg, t.keys.Enter):
+				t.submit()
+				return t, nil
+			case key.Matches(keyMsg, t.keys.Escape):
+				t.editing = false
+				t.err = ""
+				t.input.Blur()
+				return t, nil
+			}
+		}
+		var cmd tea.Cmd
+		t.input, cmd = t.input.Update(msg)
+		return t, cmd
+	}
+	if !ok {
+		return t, nil
+	}
+
+	rows := t.rows()
+	switch {
+	case key.Matches(keyMsg, t.keys.Up):
+		if t.cursor > 0 {
+			t.cursor--
+		}
+	case key.Matches(keyMsg, t.keys.Down):
+		if t.cursor < len(rows)-1 {
+			t.cursor++
+		}
+	case key.Matches(keyMsg, t.keys.Enter) && t.cursor < len(rows):
+		r := rows[t.cursor]
+		if r.item < 0 {
+			t.sections[r.section].collapsed = !t.sections[r.section].collapsed
+			return t, nil
+		}
+		return t, t.activate(r.item)
+	}
+	t.scroll()
+	return t, nil
+}
+
+// activate は項目 i を操作する。bool は反転、列挙値は次の値に切り替え、それ以外はインライン編集を開始する。
+func (t *Tree) activate(i int) tea.Cmd {
+	field := t.items[i].Field
+	switch {
+	case field.Type == "bool":
+		next := "true"
+		if t.current(i) == "true" {
+			next = "false"
+		}
+		t.set(i, next)
+		return nil
+	case len(field.Enum) > 0:
+		pos := slices.Index(field.Enum, t.current(i))
+		t.set(i, field.Enum[(pos+1)%len(field.Enum)])
+		return nil
+	}
+	t.editing = true
+	t.err = ""
+	t.input.SetValue(t.current(i))
+	t.input.CursorEnd()
+	return t.input.Focus()
+}
+
+// submit は編集中の値を検証し、有効であれば変更として記録して編集を終える。
+func (t *Tree) submit() {
+	rows := t.rows()
+	i := rows[t.cursor].item
+	value := strings.TrimSpace(t.input.Value())
+	if err := Validate(t.items[i].Field, value); err != nil {
+		t.err = err.Error()
+		return
+	}
+	t.set(i, value)
+	t.editing = false
+	t.err = ""
+	t.input.Blur()
+}
@@ -0,0 +1,88 @@
+package configtree
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// View はツリーを描画する。一覧の下に選択中の項目のスキーマと入力エラーを表示する。
+func (t Tree) View() string {
+	if len(t.items) == 0 {
+		return tui.MutedStyle().Render("  " + i18n.T("tui.config.empty"))
+	}
+	rows := t.rows()
+	end := min(t.offset+t.listHeight(), len(rows))
+	lines := make([]string, 0, end-t.offset+3)
+	for n := t.offset; n < end; n++ {
+		lines = append(lines, t.renderRow(rows[n], n == t.cursor))
+	}
+	if t.cursor < len(rows) && rows[t.cursor].item >= 0 {
+		it := t.items[rows[t.cursor].item]
+		lines = append(lines, "", tui.MutedStyle().Render("  "+it.Path()+"  "+describe(it.Field)))
+	}
+	if t.err != "" {
+		lines = append(lines, tui.ErrorStyle().Render("  "+t.err))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderRow はセクションの見出しまたは設定項目の 1 行を描画する。変更済みの項目は変更前後の値を並べる。
+func (t Tree) renderRow(r row, selected bool) string {
+	prefix := "  "
+	if selected {
+		prefix = "> "
+	}
+	if r.item < 0 {
+		sec := t.sections[r.section]
+		marker := theme.Symbols().Expanded
+		if sec.collapsed {
+			marker = theme.Symbols().Pointer
+		}
+		line := fmt.Sprintf("%s%s %s", prefix, marker, sec.title)
+		if selected {
+			return tui.SelectedStyle().Render(line)
+		}
+		return tui.TitleStyle().Render(line)
+	}
+
+	it := t.items[r.item]
+	label := fmt.Sprintf("%s    %-22s ", prefix, it.Name())
+	if selected {
+		label = tui.SelectedStyle().Render(label)
+		if t.editing {
+			return label + t.input.View()
+		}
+	}
+	if v, ok := t.pending[r.item]; ok {
+		return label + tui.MutedStyle().Render(DisplayValue(it.Value)+" "+theme.Symbols().Arrow+" ") + tui.WarningStyle().Render(DisplayValue(v)+" *")
+	}
+	return label + tui.TextStyle().Render(DisplayValue(it.Value))
+}
+
+// listHeight は一覧に使える行数を返す。空行・スキーマの説明・入力エラーの 3 行を除く。
+func (t Tree) listHeight() int {
+	return max(t.height-3, 1)
+}
+
+// scroll はカーソル行が一覧の表示範囲に入るようにスクロール位置を調整する。
+func (t *Tree) scroll() {
+	h := t.listHeight()
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+h {
+		t.offset = t.cursor - h + 1
+	}
+}
+
+// displayValue は空文字列を表示用の "(empty)" に置き換える。
+func DisplayValue(v string) string {
+	if v == "" {
+		return i18n.T("tui.config.empty_value")
+	}
+	return v
+}
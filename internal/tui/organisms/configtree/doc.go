@@ -0,0 +1,2 @@
+// Package configtree は設定エディタで使う、設定項目のツリー表示とインライン編集を提供する。
+package configtree
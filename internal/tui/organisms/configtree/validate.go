@@ -0,0 +1,95 @@
+package configtree
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+// Validate は value が field のスキーマ（型・範囲・列挙値）を満たすかを検証する。
+// デーモン側でも config.update 時に検証されるため、ここでは入力時に分かる誤りだけを扱う。
+func Validate(field protocol.ConfigFieldSchema, value string) error {
+	if len(field.Enum) > 0 && !slices.Contains(field.Enum, value) {
+		return errors.New(i18n.T("tui.config.invalid_enum", map[string]any{"Values": strings.Join(field.Enum, ", ")}))
+	}
+	switch field.Type {
+	case "bool":
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New(i18n.T("tui.config.invalid_bool"))
+		}
+	case "int":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.New(i18n.T("tui.config.invalid_int"))
+		}
+		if minV, ok := schemaNumber(field.Min); ok && float64(n) < minV {
+			return errors.New(i18n.T("tui.config.too_small", map[string]any{"Min": field.Min}))
+		}
+		if maxV, ok := schemaNumber(field.Max); ok && float64(n) > maxV {
+			return errors.New(i18n.T("tui.config.too_large", map[string]any{"Max": field.Max}))
+		}
+	case "duration":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return errors.New(i18n.T("tui.config.invalid_duration"))
+		}
+		if minV, ok := schemaDuration(field.Min); ok && d < minV {
+			return errors.New(i18n.T("tui.config.too_small", map[string]any{"Min": field.Min}))
+		}
+		if maxV, ok := schemaDuration(field.Max); ok && d > maxV {
+			return errors.New(i18n.T("tui.config.too_large", map[string]any{"Max": field.Max}))
+		}
+	case "string":
+		if maxV, ok := schemaNumber(field.Max); ok && float64(utf8.RuneCountInString(value)) > maxV {
+			return errors.New(i18n.T("tui.config.too_long", map[string]any{"Max": field.Max}))
+		}
+	}
+	return nil
+}
+
+// schemaNumber は JSON から復元したスキーマの数値制約を float64 で返す。
+func schemaNumber(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// schemaDuration はスキーマの期間制約（"30s" 等）を time.Duration で返す。
+func schemaDuration(v any) (time.Duration, bool) {
+	s, ok := v.(string)
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(s)
+	return d, err == nil
+}
+
+// describe は項目のスキーマ（型・デフォルト値・制約・追加バージョン）を 1 行にまとめる。
+func describe(field protocol.ConfigFieldSchema) string {
+	parts := []string{field.Type}
+	if field.Default != nil {
+		parts = append(parts, fmt.Sprintf("%s=%v", i18n.T("tui.config.default"), field.Default))
+	}
+	if field.Min != nil {
+		parts = append(parts, fmt.Sprintf("min=%v", field.Min))
+	}
+	if field.Max != nil {
+		parts = append(parts, fmt.Sprintf("max=%v", field.Max))
+	}
+	if len(field.Enum) > 0 {
+		parts = append(parts, strings.Join(field.Enum, "|"))
+	}
+	parts = append(parts, fmt.Sprintf("%s %s", i18n.T("tui.config.since"), field.Since))
+	return strings.Join(parts, "  ")
+}
@@ -0,0 +1,40 @@
+package configtree
+
+import (
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+)
+
+func TestValidate(t *testing.T) {
+	port := protocol.ConfigFieldSchema{Type: "int", Min: float64(1), Max: float64(65535)}
+	interval := protocol.ConfigFieldSchema{Type: "duration", Min: "30s"}
+	level := protocol.ConfigFieldSchema{Type: "string", Enum: []string{"debug", "info"}}
+	name := protocol.ConfigFieldSchema{Type: "string", Max: float64(3)}
+
+	tests := []struct {
+		name    string
+		field   protocol.ConfigFieldSchema
+		value   string
+		wantErr bool
+	}{
+		{"int ok", port, "8080", false},
+		{"int not a number", port, "80a", true},
+		{"int below min", port, "0", true},
+		{"int above max", port, "70000", true},
+		{"duration ok", interval, "5m", false},
+		{"duration invalid", interval, "5 minutes", true},
+		{"duration below min", interval, "10s", true},
+		{"enum ok", level, "info", false},
+		{"enum invalid", level, "trace", true},
+		{"string too long", name, "abcd", true},
+		{"bool invalid", protocol.ConfigFieldSchema{Type: "bool"}, "yes", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.field, tt.value); (err != nil) != tt.wantErr {
+				t.Errorf("Validate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+		})
+	}
+}
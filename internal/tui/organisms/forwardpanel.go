@@ -9,17 +9,23 @@ import (
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
 	"github.com/ousiassllc/moleport/internal/tui/molecules"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // ForwardPanel はポートフォワーディングセッション一覧を表示するパネル。
-// 全ホストのフォワードを一括表示する。
+// 全ホストのフォワードを一括表示し、グループ表示ではフォワードグループ単位で表示する。
 type ForwardPanel struct {
 	sessions []core.ForwardSession
 	cursor   int
-	keys     tui.KeyMap
-	focused  bool
-	width    int
-	height   int
+	groups   []core.ForwardGroup
+	grouped  bool
+	gcursor  int
+	// marked は一括操作のために選択したルール名。
+	marked  map[string]bool
+	keys    tui.KeyMap
+	focused bool
+	width   int
+	height  int
 }
 
 // NewForwardPanel は新しい ForwardPanel を生成する。
@@ -37,6 +43,7 @@ func (p *ForwardPanel) SetFocused(focused bool) {
 // SetSessions はセッション一覧を設定する。
 func (p *ForwardPanel) SetSessions(sessions []core.ForwardSession) {
 	p.sessions = sessions
+	p.pruneSelection()
 	if p.cursor >= len(sessions) {
 		if len(sessions) > 0 {
 			p.cursor = len(sessions) - 1
@@ -63,6 +70,17 @@ func (p ForwardPanel) Update(msg tea.Msg) (ForwardPanel, tea.Cmd) {
 		return p, nil
 	}
 
+	if key.Matches(keyMsg, p.keys.Groups) {
+		p.grouped = !p.grouped
+		return p, nil
+	}
+	if p.grouped {
+		return p.updateGroups(keyMsg)
+	}
+	if p, cmd, handled := p.updateSelection(keyMsg); handled {
+		return p, cmd
+	}
+
 	switch {
 	case key.Matches(keyMsg, p.keys.Up):
 		if p.cursor > 0 {
@@ -73,6 +91,11 @@ func (p ForwardPanel) Update(msg tea.Msg) (ForwardPanel, tea.Cmd) {
 			p.cursor++
 		}
 	case key.Matches(keyMsg, p.keys.Enter):
+		if name, ok := p.DetailTarget(); ok {
+			return p, func() tea.Msg {
+				return tui.SessionDetailRequestMsg{RuleName: name}
+			}
+		}
 		if s := p.selectedSession(); s != nil {
 			return p, func() tea.Msg {
 				return tui.ForwardToggleMsg{RuleName: s.Rule.Name}
@@ -90,6 +113,19 @@ func (p ForwardPanel) Update(msg tea.Msg) (ForwardPanel, tea.Cmd) {
 				return tui.ForwardDeleteRequestMsg{RuleName: s.Rule.Name}
 			}
 		}
+	case key.Matches(keyMsg, p.keys.Edit):
+		if s := p.selectedSession(); s != nil {
+			return p, func() tea.Msg {
+				return tui.ForwardEditRequestMsg{Rule: s.Rule}
+			}
+		}
+	case key.Matches(keyMsg, p.keys.Copy), key.Matches(keyMsg, p.keys.CopyCommand):
+		if s := p.selectedSession(); s != nil {
+			command := key.Matches(keyMsg, p.keys.CopyCommand)
+			return p, func() tea.Msg {
+				return tui.ForwardCopyRequestMsg{Session: *s, Command: command}
+			}
+		}
 	}
 
 	return p, nil
@@ -103,11 +139,26 @@ func (p ForwardPanel) selectedSession() *core.ForwardSession {
 	return &s
 }
 
+// DetailTarget は Enter で詳細ページを開くルール名を返す。
+// グループ表示や複数選択中ではなく、カーソル位置のセッションが稼働中の場合のみ ok=true を返す。
+func (p ForwardPanel) DetailTarget() (string, bool) {
+	if s := p.selectedSession(); s != nil && s.Status == core.Active && !p.grouped && len(p.marked) == 0 {
+		return s.Rule.Name, true
+	}
+	return "", false
+}
+
 // View はパネルを描画する。
 func (p ForwardPanel) View() string {
+	if p.grouped {
+		return p.groupsView()
+	}
 	innerWidth, innerHeight := PanelInnerSize(p.width, p.height)
 
 	title := i18n.T("tui.forward.title", map[string]any{"Count": len(p.sessions)})
+	if len(p.marked) > 0 {
+		title += " · " + i18n.T("tui.forward.selected", map[string]any{"Count": len(p.marked)})
+	}
 
 	var rows []string
 
@@ -140,6 +191,9 @@ func (p ForwardPanel) View() string {
 			if i == p.cursor {
 				prefix = tui.ActiveStyle().Render("> ")
 			}
+			if p.marked[p.sessions[i].Rule.Name] {
+				prefix += tui.ActiveStyle().Render(theme.Symbols().Check + " ")
+			}
 			rows = append(rows, prefix+row.View())
 		}
 	}
@@ -0,0 +1,80 @@
+package organisms
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+// SetGroups はフォワードグループの一覧を設定する。
+func (p *ForwardPanel) SetGroups(groups []core.ForwardGroup) {
+	p.groups = groups
+	p.gcursor = max(min(p.gcursor, len(groups)-1), 0)
+}
+
+// updateGroups はグループ表示中のキー入力を処理する。
+// Enter はグループ内のルールがすべて稼働中なら一括停止の確認、そうでなければ一括開始を要求する。
+func (p ForwardPanel) updateGroups(keyMsg tea.KeyMsg) (ForwardPanel, tea.Cmd) {
+	switch {
+	case key.Matches(keyMsg, p.keys.Up):
+		if p.gcursor > 0 {
+			p.gcursor--
+		}
+	case key.Matches(keyMsg, p.keys.Down):
+		if p.gcursor < len(p.groups)-1 {
+			p.gcursor++
+		}
+	case key.Matches(keyMsg, p.keys.Enter):
+		if p.gcursor < len(p.groups) {
+			row := p.groupRow(p.groups[p.gcursor])
+			var msg tea.Msg = tui.ForwardGroupToggleMsg{Group: row.Group.Name, Start: true}
+			if row.ActiveCount() == len(row.Group.Rules) {
+				msg = tui.ForwardGroupStopRequestMsg{Group: row.Group.Name, Count: row.ActiveCount()}
+			}
+			return p, func() tea.Msg { return msg }
+		}
+	}
+	return p, nil
+}
+
+// groupRow はグループの表示行を、現在のセッション状態から組み立てる。
+func (p ForwardPanel) groupRow(g core.ForwardGroup) molecules.GroupRow {
+	active := make(map[string]bool)
+	for _, s := range p.sessions {
+		if s.Status == core.Active {
+			active[s.Rule.Name] = true
+		}
+	}
+	return molecules.GroupRow{Group: g, Active: active}
+}
+
+// groupsView はグループ表示のパネルを描画する。
+func (p ForwardPanel) groupsView() string {
+	innerWidth, innerHeight := PanelInnerSize(p.width, p.height)
+	title := i18n.T("tui.forward.groups_title", map[string]any{"Count": len(p.groups)})
+
+	var rows []string
+	if len(p.groups) == 0 {
+		rows = append(rows, tui.MutedStyle().Render(i18n.T("tui.forward.groups_empty")))
+	}
+	maxRows := max(innerHeight, 1)
+	offset := max(p.gcursor-maxRows+1, 0)
+	for i := offset; i < len(p.groups) && i < offset+maxRows; i++ {
+		var prefix string
+		if i == p.gcursor {
+			prefix = tui.ActiveStyle().Render("> ")
+		}
+		rows = append(rows, prefix+p.groupRow(p.groups[i]).View())
+	}
+
+	border := tui.UnfocusedBorder()
+	if p.focused {
+		border = tui.FocusedBorder()
+	}
+	return tui.RenderWithBorderTitle(border, innerWidth, innerHeight, title, strings.Join(rows, "\n"))
+}
@@ -0,0 +1,66 @@
+package organisms
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestForwardPanel_GroupView(t *testing.T) {
+	p := NewForwardPanel()
+	p.SetFocused(true)
+	p.SetSize(100, 10)
+	p.SetSessions(append(makeSessions("db"), core.ForwardSession{Rule: core.ForwardRule{Name: "web"}, Status: core.Stopped}))
+	p.SetGroups([]core.ForwardGroup{
+		{Name: "staging", Rules: []string{"db", "web"}},
+		{Name: "data", Rules: []string{"db"}},
+	})
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if view := p.View(); !strings.Contains(view, "staging") || !strings.Contains(view, "1/2") {
+		t.Errorf("group view should list groups with active counts, got %q", view)
+	}
+
+	tests := []struct {
+		keys []tea.KeyMsg
+		want tea.Msg
+	}{
+		{nil, tui.ForwardGroupToggleMsg{Group: "staging", Start: true}},
+		{[]tea.KeyMsg{{Type: tea.KeyDown}}, tui.ForwardGroupStopRequestMsg{Group: "data", Count: 1}},
+	}
+	for _, tt := range tests {
+		q := p
+		for _, k := range tt.keys {
+			q, _ = q.Update(k)
+		}
+		_, cmd := q.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if cmd == nil {
+			t.Fatal("Enter in group view should return a command")
+		}
+		if got := cmd(); got != tt.want {
+			t.Errorf("msg = %#v, want %#v", got, tt.want)
+		}
+	}
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("g")})
+	if view := p.View(); strings.Contains(view, "1/2") {
+		t.Errorf("pressing g again should return to the session list, got %q", view)
+	}
+}
+
+func TestForwardPanel_SetGroups_ClampsCursor(t *testing.T) {
+	p := NewForwardPanel()
+	p.SetGroups([]core.ForwardGroup{{Name: "a"}, {Name: "b"}})
+	p.gcursor = 1
+	p.SetGroups([]core.ForwardGroup{{Name: "a"}})
+	if p.gcursor != 0 {
+		t.Errorf("gcursor = %d, want 0", p.gcursor)
+	}
+	p.SetGroups(nil)
+	if p.gcursor != 0 {
+		t.Errorf("gcursor after empty = %d, want 0", p.gcursor)
+	}
+}
@@ -0,0 +1,100 @@
+package organisms
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// updateSelection は複数選択に関するキー入力を処理する。処理した場合は handled=true を返す。
+// Space でカーソル位置のルールの選択を切り替えて次の行に進み、Esc で選択を解除する。
+// 選択がある場合の Enter・d・x は、選択したルールの一括開始・一括停止・一括削除を要求する。
+func (p ForwardPanel) updateSelection(keyMsg tea.KeyMsg) (ForwardPanel, tea.Cmd, bool) {
+	if key.Matches(keyMsg, p.keys.Select) {
+		if s := p.selectedSession(); s != nil {
+			if p.marked == nil {
+				p.marked = make(map[string]bool)
+			}
+			if p.marked[s.Rule.Name] {
+				delete(p.marked, s.Rule.Name)
+			} else {
+				p.marked[s.Rule.Name] = true
+			}
+			p.cursor = min(p.cursor+1, len(p.sessions)-1)
+		}
+		return p, nil, true
+	}
+	if len(p.marked) == 0 {
+		return p, nil, false
+	}
+	var action tui.BulkAction
+	switch {
+	case key.Matches(keyMsg, p.keys.Escape):
+		p.ClearSelection()
+		return p, nil, true
+	case key.Matches(keyMsg, p.keys.Enter):
+		action = tui.BulkStart
+	case key.Matches(keyMsg, p.keys.Disconnect):
+		action = tui.BulkStop
+	case key.Matches(keyMsg, p.keys.Delete):
+		action = tui.BulkDelete
+	default:
+		return p, nil, false
+	}
+	names := p.bulkTargets(action)
+	if len(names) == 0 {
+		return p, nil, true
+	}
+	msg := tui.ForwardBulkRequestMsg{Action: action, RuleNames: names}
+	return p, func() tea.Msg { return msg }, true
+}
+
+// bulkTargets は選択中のルールのうち action の対象になるルール名を返す。
+// 一括開始は停止中のルール、一括停止は稼働中のルールのみを対象にする。
+func (p ForwardPanel) bulkTargets(action tui.BulkAction) []string {
+	var names []string
+	for _, s := range p.sessions {
+		if !p.marked[s.Rule.Name] {
+			continue
+		}
+		active := s.Status == core.Active
+		if (action == tui.BulkStart && active) || (action == tui.BulkStop && !active) {
+			continue
+		}
+		names = append(names, s.Rule.Name)
+	}
+	return names
+}
+
+// MarkedRules は選択中のルール名をセッション一覧の順に返す。
+func (p ForwardPanel) MarkedRules() []string {
+	var names []string
+	for _, s := range p.sessions {
+		if p.marked[s.Rule.Name] {
+			names = append(names, s.Rule.Name)
+		}
+	}
+	return names
+}
+
+// ClearSelection はルールの選択をすべて解除する。
+func (p *ForwardPanel) ClearSelection() {
+	p.marked = nil
+}
+
+// pruneSelection はセッション一覧から無くなったルールの選択を解除する。
+func (p *ForwardPanel) pruneSelection() {
+	if len(p.marked) == 0 {
+		return
+	}
+	exists := make(map[string]bool, len(p.sessions))
+	for _, s := range p.sessions {
+		exists[s.Rule.Name] = true
+	}
+	for name := range p.marked {
+		if !exists[name] {
+			delete(p.marked, name)
+		}
+	}
+}
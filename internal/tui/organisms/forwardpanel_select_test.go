@@ -0,0 +1,81 @@
+package organisms
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestForwardPanel_SelectAndBulk(t *testing.T) {
+	p := NewForwardPanel()
+	p.SetFocused(true)
+	p.SetSize(100, 10)
+	sessions := makeSessions("api", "db", "web")
+	sessions[1].Status = core.Stopped
+	p.SetSessions(sessions)
+
+	space := tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+	p, _ = p.Update(space)
+	p, _ = p.Update(space)
+	if got := p.MarkedRules(); !slices.Equal(got, []string{"api", "db"}) {
+		t.Fatalf("MarkedRules = %v, want [api db]", got)
+	}
+	if p.cursor != 2 {
+		t.Errorf("cursor = %d, want 2 (Space advances)", p.cursor)
+	}
+	if !strings.Contains(p.View(), "Selected 2") {
+		t.Errorf("title should show the selection count:\n%s", p.View())
+	}
+
+	tests := []struct {
+		key    tea.KeyMsg
+		action tui.BulkAction
+		want   []string
+	}{
+		{tea.KeyMsg{Type: tea.KeyEnter}, tui.BulkStart, []string{"db"}},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}}, tui.BulkStop, []string{"api"}},
+		{tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}, tui.BulkDelete, []string{"api", "db"}},
+	}
+	for _, tt := range tests {
+		_, cmd := p.Update(tt.key)
+		if cmd == nil {
+			t.Fatalf("%s: expected a bulk request", tt.key)
+		}
+		msg, ok := cmd().(tui.ForwardBulkRequestMsg)
+		if !ok || msg.Action != tt.action || !slices.Equal(msg.RuleNames, tt.want) {
+			t.Errorf("%s: msg = %+v, want action %d on %v", tt.key, msg, tt.action, tt.want)
+		}
+	}
+
+	// 一覧から無くなったルールの選択は解除される
+	p.SetSessions(makeSessions("api", "web"))
+	if got := p.MarkedRules(); !slices.Equal(got, []string{"api"}) {
+		t.Errorf("MarkedRules after reload = %v, want [api]", got)
+	}
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if len(p.MarkedRules()) != 0 {
+		t.Error("Esc should clear the selection")
+	}
+	// 選択がない場合の Enter は従来どおりカーソル位置のルールを対象にする（稼働中なので詳細ページを開く）
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd == nil {
+		t.Fatal("Enter without selection should act on the cursor row")
+	} else if _, ok := cmd().(tui.SessionDetailRequestMsg); !ok {
+		t.Error("Enter without selection should emit SessionDetailRequestMsg")
+	}
+}
+
+func TestForwardPanel_BulkWithoutTargets(t *testing.T) {
+	p := NewForwardPanel()
+	p.SetFocused(true)
+	p.SetSessions(makeSessions("api"))
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	// すべて稼働中のため一括開始の対象はない
+	if _, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil {
+		t.Error("bulk start without stopped rules should do nothing")
+	}
+}
@@ -98,7 +98,9 @@ func TestForwardPanel_Update_UpDown(t *testing.T) {
 func TestForwardPanel_Update_Enter(t *testing.T) {
 	p := NewForwardPanel()
 	p.SetFocused(true)
-	p.SetSessions(makeSessions("my-rule"))
+	sessions := makeSessions("my-rule")
+	sessions[0].Status = core.Stopped
+	p.SetSessions(sessions)
 	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
 	if cmd == nil {
 		t.Fatal("Enter should produce a cmd")
@@ -112,6 +114,23 @@ func TestForwardPanel_Update_Enter(t *testing.T) {
 	}
 }
 
+func TestForwardPanel_Update_EnterOpensDetail(t *testing.T) {
+	p := NewForwardPanel()
+	p.SetFocused(true)
+	p.SetSessions(makeSessions("my-rule"))
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter on an active session should produce a cmd")
+	}
+	req, ok := cmd().(tui.SessionDetailRequestMsg)
+	if !ok {
+		t.Fatalf("expected SessionDetailRequestMsg, got %T", cmd())
+	}
+	if req.RuleName != "my-rule" {
+		t.Errorf("RuleName=%q want my-rule", req.RuleName)
+	}
+}
+
 func TestForwardPanel_Update_Delete(t *testing.T) {
 	p := NewForwardPanel()
 	p.SetFocused(true)
@@ -129,6 +148,39 @@ func TestForwardPanel_Update_Delete(t *testing.T) {
 	}
 }
 
+func TestForwardPanel_Update_Edit(t *testing.T) {
+	p := NewForwardPanel()
+	p.SetFocused(true)
+	p.SetSessions(makeSessions("edit-rule"))
+	_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'e'}})
+	if cmd == nil {
+		t.Fatal("Edit key should produce a cmd")
+	}
+	edit, ok := cmd().(tui.ForwardEditRequestMsg)
+	if !ok || edit.Rule.Name != "edit-rule" || edit.Rule.LocalPort != 8080 {
+		t.Errorf("msg = %+v, want ForwardEditRequestMsg for edit-rule", cmd())
+	}
+}
+
+func TestForwardPanel_Update_Copy(t *testing.T) {
+	p := NewForwardPanel()
+	p.SetFocused(true)
+	p.SetSessions(makeSessions("copy-rule"))
+	for _, tt := range []struct {
+		key     rune
+		command bool
+	}{{'y', false}, {'Y', true}} {
+		_, cmd := p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{tt.key}})
+		if cmd == nil {
+			t.Fatalf("%c should produce a cmd", tt.key)
+		}
+		msg, ok := cmd().(tui.ForwardCopyRequestMsg)
+		if !ok || msg.Session.Rule.Name != "copy-rule" || msg.Command != tt.command {
+			t.Errorf("%c: msg = %+v, want ForwardCopyRequestMsg{Command: %v}", tt.key, cmd(), tt.command)
+		}
+	}
+}
+
 func TestForwardPanel_Update_NonKeyMsg(t *testing.T) {
 	p := NewForwardPanel()
 	p.SetFocused(true)
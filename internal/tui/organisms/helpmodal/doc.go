@@ -0,0 +1,2 @@
+// Package helpmodal はペインごとのキー操作を一覧表示するヘルプモーダルを提供する。
+package helpmodal
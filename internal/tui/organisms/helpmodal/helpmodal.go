@@ -0,0 +1,173 @@
+package helpmodal
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+const (
+	// columnWidth は 2 列で表示する場合の 1 列の幅。画面の幅が 2 列分に満たない場合は 1 列で表示する。
+	columnWidth = 56
+	// columnGap は 2 列で表示する場合の列の間隔。
+	columnGap = 4
+	// chromeWidth・chromeHeight は枠線と余白、タイトル・ヒントの行の分の幅と高さ。
+	chromeWidth  = 4
+	chromeHeight = 6
+)
+
+// Modal はペインごとのキー操作と、同じ操作に割り当てた別名のキーを一覧表示するモーダル。
+// 画面の幅に応じて 1 列または 2 列で表示し、高さが足りない場合は ↑/↓ でスクロールする。
+type Modal struct {
+	sections []section
+	offset   int
+	keys     tui.KeyMap
+	pageUp   key.Binding
+	pageDown key.Binding
+	width    int
+	height   int
+}
+
+// New は現在の言語でキー操作の一覧を表示する Modal を生成する。
+func New() Modal {
+	return Modal{
+		sections: sections(),
+		keys:     tui.DefaultKeyMap(),
+		pageUp:   key.NewBinding(key.WithKeys("pgup", "ctrl+u")),
+		pageDown: key.NewBinding(key.WithKeys("pgdown", "ctrl+d")),
+	}
+}
+
+// SetSize はモーダルを表示する画面のサイズを設定する。
+func (m *Modal) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+	m.offset = min(m.offset, m.maxOffset())
+}
+
+// Update はキー入力を処理する。Esc・?・q でモーダルを閉じ、HelpClosedMsg を発行する。
+func (m Modal) Update(msg tea.KeyMsg) (Modal, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Escape), key.Matches(msg, m.keys.Help), key.Matches(msg, m.keys.Quit):
+		return m, func() tea.Msg { return tui.HelpClosedMsg{} }
+	case key.Matches(msg, m.keys.Up):
+		m.offset--
+	case key.Matches(msg, m.keys.Down):
+		m.offset++
+	case key.Matches(msg, m.pageUp):
+		m.offset -= m.bodyHeight()
+	case key.Matches(msg, m.pageDown):
+		m.offset += m.bodyHeight()
+	}
+	m.offset = min(max(m.offset, 0), m.maxOffset())
+	return m, nil
+}
+
+// View はモーダルを枠付きで描画する。
+func (m Modal) View() string {
+	lines := m.lines()
+	body := lines[m.offset:min(m.offset+m.bodyHeight(), len(lines))]
+	hint := i18n.T("tui.help.hint")
+	if len(lines) > m.bodyHeight() {
+		hint += fmt.Sprintf("  (%d-%d/%d)", m.offset+1, m.offset+len(body), len(lines))
+	}
+	content := lipgloss.JoinVertical(lipgloss.Left,
+		tui.TitleStyle().Render(i18n.T("tui.help.title")),
+		"",
+		strings.Join(body, "\n"),
+		"",
+		tui.MutedStyle().Render(hint),
+	)
+	return tui.FocusedBorder().Render(content)
+}
+
+// lines は画面の幅に合わせて配置したキー操作の一覧を 1 行ずつ返す。
+func (m Modal) lines() []string {
+	width := m.contentWidth()
+	if width < columnWidth*2+columnGap {
+		blocks := make([][]string, len(m.sections))
+		for i, s := range m.sections {
+			blocks[i] = renderSection(s, width)
+		}
+		return joinBlocks(blocks)
+	}
+
+	// セクションの順序を保ったまま、高い方の列の行数が最小になる位置で左右の列に分ける
+	blocks := make([][]string, len(m.sections))
+	for i, s := range m.sections {
+		blocks[i] = renderSection(s, columnWidth)
+	}
+	var left, right []string
+	for split := 1; split <= len(blocks); split++ {
+		l, r := joinBlocks(blocks[:split]), joinBlocks(blocks[split:])
+		if left == nil || max(len(l), len(r)) < max(len(left), len(right)) {
+			left, right = l, r
+		}
+	}
+	lines := make([]string, max(len(left), len(right)))
+	for i := range lines {
+		var l, r string
+		if i < len(left) {
+			l = left[i]
+		}
+		if i < len(right) {
+			r = right[i]
+		}
+		lines[i] = lipgloss.NewStyle().Width(columnWidth+columnGap).Render(l) + r
+	}
+	return lines
+}
+
+// joinBlocks はセクションごとの行を空行を挟んで連結する。
+func joinBlocks(blocks [][]string) []string {
+	var lines []string
+	for i, block := range blocks {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, block...)
+	}
+	return lines
+}
+
+// renderSection はセクションの見出しとキー操作を幅 width に収めて描画する。説明が収まらない場合は折り返す。
+func renderSection(s section, width int) []string {
+	keyWidth := 0
+	for _, e := range s.entries {
+		keyWidth = max(keyWidth, lipgloss.Width(e.keys))
+	}
+	descWidth := max(width-keyWidth-4, 10)
+	lines := []string{tui.HeaderStyle().Render(s.title)}
+	for _, e := range s.entries {
+		keys := tui.KeyStyle().Width(keyWidth + 4).Render("  " + e.keys)
+		desc := tui.DescStyle().Width(descWidth).Render(e.desc)
+		lines = append(lines, strings.Split(lipgloss.JoinHorizontal(lipgloss.Top, keys, desc), "\n")...)
+	}
+	return lines
+}
+
+// contentWidth はモーダル内でキー操作の一覧に使える幅を返す。
+func (m Modal) contentWidth() int {
+	if m.width <= 0 {
+		return columnWidth
+	}
+	return max(m.width-chromeWidth, 20)
+}
+
+// bodyHeight はキー操作の一覧の表示に使える行数を返す。
+func (m Modal) bodyHeight() int {
+	if m.height <= 0 {
+		return len(m.lines())
+	}
+	return max(m.height-chromeHeight, 1)
+}
+
+// maxOffset はスクロール位置の上限を返す。
+func (m Modal) maxOffset() int {
+	return max(len(m.lines())-m.bodyHeight(), 0)
+}
@@ -0,0 +1,73 @@
+package helpmodal
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestSections_Translated(t *testing.T) {
+	for _, s := range sections() {
+		if strings.HasPrefix(s.title, "tui.help.") {
+			t.Errorf("missing translation for section %q", s.title)
+		}
+		for _, e := range s.entries {
+			if strings.HasPrefix(e.desc, "tui.help.") {
+				t.Errorf("missing translation for %q (%s)", e.desc, e.keys)
+			}
+		}
+	}
+}
+
+func TestModal_Layout(t *testing.T) {
+	narrow := New()
+	narrow.SetSize(80, 200)
+	wide := New()
+	wide.SetSize(140, 200)
+
+	if first := wide.lines()[0]; !strings.Contains(first, "Global") || !strings.Contains(first, "Setup pane") {
+		t.Errorf("wide screen should lay sections out in two columns, first line = %q", first)
+	}
+	if n, w := len(narrow.lines()), len(wide.lines()); w >= n {
+		t.Errorf("two columns should need fewer lines: narrow=%d wide=%d", n, w)
+	}
+	for _, want := range []string{"q / Ctrl+C", "↑ / k  ↓ / j", "Esc / L", "Session detail"} {
+		if !strings.Contains(narrow.View(), want) {
+			t.Errorf("view should contain %q", want)
+		}
+	}
+}
+
+func TestModal_ScrollAndClose(t *testing.T) {
+	m := New()
+	m.SetSize(80, 20)
+	if !strings.Contains(m.View(), "(1-14/") {
+		t.Fatalf("short screen should show the scroll position:\n%s", m.View())
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgDown})
+	if m.offset != 15 {
+		t.Errorf("offset = %d, want 15", m.offset)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyUp})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyPgUp})
+	if m.offset != 0 {
+		t.Errorf("offset = %d, want 0 (clamped)", m.offset)
+	}
+	if _, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}); cmd != nil {
+		t.Error("other keys should not close the modal")
+	}
+
+	for _, msg := range []tea.KeyMsg{{Type: tea.KeyEsc}, {Type: tea.KeyRunes, Runes: []rune{'?'}}, {Type: tea.KeyRunes, Runes: []rune{'q'}}} {
+		_, cmd := m.Update(msg)
+		if cmd == nil {
+			t.Fatalf("%s should close the modal", msg)
+		}
+		if _, ok := cmd().(tui.HelpClosedMsg); !ok {
+			t.Errorf("%s: got %#v, want HelpClosedMsg", msg, cmd())
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package helpmodal
+
+import (
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// entry はキー操作 1 件。keys は同じ操作に割り当てた別名のキーを " / " で並べた表示。
+type entry struct {
+	keys string
+	desc string
+}
+
+// section はペインまたは画面ごとのキー操作の一覧。
+type section struct {
+	title   string
+	entries []entry
+}
+
+// helpEntry は tui.help.<section>.<id> の説明を持つ entry を返す。
+func helpEntry(keys, section, id string) entry {
+	return entry{keys: keys, desc: i18n.T("tui.help." + section + "." + id)}
+}
+
+// sections は全体・転送一覧・ホスト一覧・セッション詳細・ログビューアのキー操作を返す。
+func sections() []section {
+	g := theme.Symbols()
+	arrows := g.Up + " / k  " + g.Down + " / j"
+	return []section{
+		{title: i18n.T("tui.help.global.title"), entries: []entry{
+			helpEntry("Tab", "global", "tab"),
+			helpEntry("/", "global", "slash"),
+			helpEntry("+ / -", "global", "resize"),
+			helpEntry("z", "global", "zoom"),
+			helpEntry("o", "global", "toggle_log"),
+			helpEntry("L", "global", "logs"),
+			helpEntry("n", "global", "n"),
+			helpEntry("p", "global", "p"),
+			helpEntry("c", "global", "c"),
+			helpEntry("t", "global", "t"),
+			helpEntry("l", "global", "l"),
+			helpEntry("v", "global", "v"),
+			helpEntry("?", "global", "question"),
+			helpEntry("q / Ctrl+C", "global", "q"),
+		}},
+		{title: i18n.T("tui.help.forwards.title"), entries: []entry{
+			helpEntry(arrows, "forwards", "arrows"),
+			helpEntry("Enter", "forwards", "enter"),
+			helpEntry("Space", "forwards", "space"),
+			helpEntry("d", "forwards", "d"),
+			helpEntry("x", "forwards", "x"),
+			helpEntry("e", "forwards", "e"),
+			helpEntry("y", "forwards", "copy"),
+			helpEntry("Y", "forwards", "copy_command"),
+			helpEntry("g", "forwards", "g"),
+			helpEntry("Esc", "forwards", "esc"),
+		}},
+		{title: i18n.T("tui.help.hosts.title"), entries: []entry{
+			helpEntry(arrows, "hosts", "arrows"),
+			helpEntry("Enter", "hosts", "enter"),
+			helpEntry("i", "hosts", "i"),
+			helpEntry("a", "hosts", "a"),
+			helpEntry("x", "hosts", "x"),
+			helpEntry("g", "hosts", "g"),
+			helpEntry("s", "hosts", "s"),
+			helpEntry("Esc", "hosts", "esc"),
+		}},
+		{title: i18n.T("tui.help.detail.title"), entries: []entry{
+			helpEntry("d", "detail", "d"),
+			helpEntry("y", "detail", "copy"),
+			helpEntry("Y", "detail", "copy_command"),
+			helpEntry("Esc / Enter", "detail", "esc"),
+		}},
+		{title: i18n.T("tui.help.logs.title"), entries: []entry{
+			helpEntry("/", "logs", "slash"),
+			helpEntry("f", "logs", "f"),
+			helpEntry(arrows, "logs", "arrows"),
+			helpEntry("PgUp / Ctrl+U", "logs", "page_up"),
+			helpEntry("PgDn / Ctrl+D", "logs", "page_down"),
+			helpEntry("g / Home", "logs", "top"),
+			helpEntry("G / End", "logs", "bottom"),
+			helpEntry("Esc / L", "logs", "esc"),
+		}},
+	}
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 const logMaxOutputLines = 100
@@ -78,9 +79,9 @@ func styleLogEntry(entry logEntry) string {
 	}
 	switch entry.level {
 	case tui.LogError:
-		return tui.ErrorStyle().Render("✗") + " " + tui.MutedStyle().Render(entry.text)
+		return tui.ErrorStyle().Render(theme.Symbols().Cross) + " " + tui.MutedStyle().Render(entry.text)
 	case tui.LogSuccess:
-		return tui.ActiveStyle().Render("✓") + " " + tui.MutedStyle().Render(entry.text)
+		return tui.ActiveStyle().Render(theme.Symbols().Check) + " " + tui.MutedStyle().Render(entry.text)
 	default:
 		return tui.MutedStyle().Render(entry.text)
 	}
@@ -0,0 +1,2 @@
+// Package logviewer はデーモンのログを表示する、検索と追従モードを備えたログビューアを提供する。
+package logviewer
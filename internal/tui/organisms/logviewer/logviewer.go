@@ -0,0 +1,183 @@
+package logviewer
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// maxRecords は保持するログの最大件数。超えた分は古いものから破棄する。
+const maxRecords = 1000
+
+// Viewer は logs.subscribe で受け取ったデーモンのログを表示するビューア。
+// ログレベルごとに色分けし、検索語を含むログに絞り込める。追従モードでは新しいログが届くたびに末尾を表示する。
+type Viewer struct {
+	records []protocol.LogRecord
+	// top は追従モードでないときに表示する先頭のログの位置（絞り込み後の一覧での位置）。
+	top    int
+	follow bool
+	// search は検索語の入力欄。searching は検索語の入力中か。
+	search    textinput.Model
+	searching bool
+	status    string
+
+	keys     tui.KeyMap
+	toggle   key.Binding
+	first    key.Binding
+	last     key.Binding
+	pageUp   key.Binding
+	pageDown key.Binding
+	width    int
+	height   int
+}
+
+// New は追従モードの Viewer を生成する。
+func New() Viewer {
+	search := textinput.New()
+	search.Placeholder = i18n.T("tui.log_viewer.search_placeholder")
+	search.CharLimit = 128
+	return Viewer{
+		follow:   true,
+		search:   search,
+		keys:     tui.DefaultKeyMap(),
+		toggle:   key.NewBinding(key.WithKeys("f")),
+		first:    key.NewBinding(key.WithKeys("g", "home")),
+		last:     key.NewBinding(key.WithKeys("G", "end")),
+		pageUp:   key.NewBinding(key.WithKeys("pgup", "ctrl+u")),
+		pageDown: key.NewBinding(key.WithKeys("pgdown", "ctrl+d")),
+	}
+}
+
+// SetRecords は表示するログを records（古い順）で置き換える。
+func (v *Viewer) SetRecords(records []protocol.LogRecord) {
+	v.records = append([]protocol.LogRecord(nil), records[max(len(records)-maxRecords, 0):]...)
+	v.status = ""
+}
+
+// Append はログを 1 件追加する。
+func (v *Viewer) Append(rec protocol.LogRecord) {
+	v.records = append(v.records, rec)
+	if len(v.records) > maxRecords {
+		v.records = v.records[len(v.records)-maxRecords:]
+	}
+}
+
+// SetStatus はログの下に表示する状態（購読の失敗等）を設定する。
+func (v *Viewer) SetStatus(status string) {
+	v.status = status
+}
+
+// SetSize はビューアのサイズを設定する。
+func (v *Viewer) SetSize(width, height int) {
+	v.width = width
+	v.height = height
+	v.search.Width = max(width-20, 10)
+}
+
+// Following は追従モードかを返す。
+func (v Viewer) Following() bool {
+	return v.follow
+}
+
+// Update はキー入力を処理する。Esc（検索語がない場合）または L でビューアを閉じ、LogViewerClosedMsg を発行する。
+// / で検索語を入力し、f で追従モードを切り替える。↑/↓・PgUp/PgDn でスクロールすると追従モードを解除し、G で末尾に戻って追従する。
+func (v Viewer) Update(msg tea.Msg) (Viewer, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		if v.searching {
+			v.search, cmd = v.search.Update(msg)
+		}
+		return v, cmd
+	}
+	if v.searching {
+		return v.updateSearch(keyMsg)
+	}
+
+	switch {
+	case key.Matches(keyMsg, v.keys.Escape) && v.search.Value() != "":
+		v.search.Reset()
+		v.top = 0
+	case key.Matches(keyMsg, v.keys.Escape), key.Matches(keyMsg, v.keys.Logs):
+		return v, func() tea.Msg { return tui.LogViewerClosedMsg{} }
+	case key.Matches(keyMsg, v.keys.Search):
+		v.searching = true
+		return v, v.search.Focus()
+	case key.Matches(keyMsg, v.toggle):
+		v.follow = !v.follow
+		v.top = v.start()
+	case key.Matches(keyMsg, v.keys.Up):
+		v.scroll(-1)
+	case key.Matches(keyMsg, v.keys.Down):
+		v.scroll(1)
+	case key.Matches(keyMsg, v.pageUp):
+		v.scroll(-v.bodyHeight())
+	case key.Matches(keyMsg, v.pageDown):
+		v.scroll(v.bodyHeight())
+	case key.Matches(keyMsg, v.first):
+		v.follow, v.top = false, 0
+	case key.Matches(keyMsg, v.last):
+		v.follow = true
+	}
+	return v, nil
+}
+
+// updateSearch は検索語の入力中のキーを処理する。Enter で入力を終えて絞り込みを保ち、Esc で検索語を消す。
+func (v Viewer) updateSearch(keyMsg tea.KeyMsg) (Viewer, tea.Cmd) {
+	switch keyMsg.Type {
+	case tea.KeyEnter:
+		v.searching = false
+		v.search.Blur()
+		return v, nil
+	case tea.KeyEsc:
+		v.searching = false
+		v.search.Blur()
+		v.search.Reset()
+		return v, nil
+	}
+	var cmd tea.Cmd
+	v.search, cmd = v.search.Update(keyMsg)
+	v.top = 0
+	return v, cmd
+}
+
+// scroll は表示位置を delta 行移動して追従モードを解除する。末尾まで移動した場合は追従モードに戻る。
+func (v *Viewer) scroll(delta int) {
+	bottom := max(len(v.visible())-v.bodyHeight(), 0)
+	v.top = min(max(v.start()+delta, 0), bottom)
+	v.follow = v.top == bottom && delta > 0
+}
+
+// start は表示する先頭のログの絞り込み後の一覧での位置を返す。
+func (v Viewer) start() int {
+	bottom := max(len(v.visible())-v.bodyHeight(), 0)
+	if v.follow {
+		return bottom
+	}
+	return min(v.top, bottom)
+}
+
+// visible は検索語を含むログを古い順に返す。検索語は大文字と小文字を区別しない。
+func (v Viewer) visible() []protocol.LogRecord {
+	query := strings.ToLower(v.search.Value())
+	if query == "" {
+		return v.records
+	}
+	var out []protocol.LogRecord
+	for _, rec := range v.records {
+		if strings.Contains(strings.ToLower(plainLine(rec)), query) {
+			out = append(out, rec)
+		}
+	}
+	return out
+}
+
+// bodyHeight はログの表示に使える行数を返す。ヘッダー・検索欄・ヒントの分を除く。
+func (v Viewer) bodyHeight() int {
+	return max(v.height-4, 1)
+}
@@ -0,0 +1,102 @@
+package logviewer
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func keyRunes(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func testRecords(n int) []protocol.LogRecord {
+	records := make([]protocol.LogRecord, n)
+	for i := range records {
+		records[i] = protocol.LogRecord{Time: time.Now(), Level: "INFO", Message: fmt.Sprintf("line-%02d", i)}
+	}
+	return records
+}
+
+func TestViewer_FollowAndScroll(t *testing.T) {
+	v := New()
+	v.SetSize(80, 10) // 本文は 6 行
+	v.SetRecords(testRecords(20))
+	if view := v.View(); !strings.Contains(view, "line-19") || strings.Contains(view, "line-13") {
+		t.Fatalf("follow mode should show the tail:\n%s", view)
+	}
+
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyUp})
+	if v.Following() {
+		t.Error("scrolling up should leave follow mode")
+	}
+	v.Append(protocol.LogRecord{Level: "INFO", Message: "line-20"})
+	if view := v.View(); strings.Contains(view, "line-20") || !strings.Contains(view, "line-13") {
+		t.Errorf("new logs should not move the view while not following:\n%s", view)
+	}
+
+	v, _ = v.Update(keyRunes("G"))
+	if !v.Following() || !strings.Contains(v.View(), "line-20") {
+		t.Error("G should jump to the tail and resume following")
+	}
+	v, _ = v.Update(keyRunes("g"))
+	if v.Following() || !strings.Contains(v.View(), "line-00") {
+		t.Error("g should jump to the head")
+	}
+}
+
+func TestViewer_Search(t *testing.T) {
+	v := New()
+	v.SetSize(80, 20)
+	v.SetRecords([]protocol.LogRecord{
+		{Level: "INFO", Message: "forward started", Attrs: map[string]string{"rule": "web"}},
+		{Level: "ERROR", Message: "dial failed", Attrs: map[string]string{"rule": "db"}},
+	})
+
+	v, _ = v.Update(keyRunes("/"))
+	for _, r := range "RULE=DB" {
+		v, _ = v.Update(keyRunes(string(r)))
+	}
+	v, _ = v.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	view := v.View()
+	if !strings.Contains(view, "dial failed") || strings.Contains(view, "forward started") {
+		t.Errorf("search should filter case-insensitively by message and attrs:\n%s", view)
+	}
+
+	// Esc は検索語を消してから閉じる
+	v, cmd := v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd != nil || !strings.Contains(v.View(), "forward started") {
+		t.Error("first Esc should clear the search")
+	}
+	_, cmd = v.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("second Esc should close the viewer")
+	}
+	if _, ok := cmd().(tui.LogViewerClosedMsg); !ok {
+		t.Errorf("got %#v, want LogViewerClosedMsg", cmd())
+	}
+}
+
+func TestViewer_KeepsLatestRecords(t *testing.T) {
+	v := New()
+	v.SetRecords(testRecords(maxRecords + 5))
+	v.Append(protocol.LogRecord{Message: "last"})
+	if len(v.records) != maxRecords || v.records[0].Message != "line-06" {
+		t.Errorf("records = %d (first %q), want %d starting at line-06", len(v.records), v.records[0].Message, maxRecords)
+	}
+}
+
+func TestRecordText(t *testing.T) {
+	rec := protocol.LogRecord{Message: "msg", Attrs: map[string]string{"b": "two words", "a": "1"}}
+	if got, want := recordText(rec), `msg a=1 b="two words"`; got != want {
+		t.Errorf("recordText = %q, want %q", got, want)
+	}
+	if got := matchPositions("Web web", "web"); fmt.Sprint(got) != "[0 1 2 4 5 6]" {
+		t.Errorf("matchPositions = %v", got)
+	}
+}
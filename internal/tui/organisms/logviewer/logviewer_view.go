@@ -0,0 +1,126 @@
+package logviewer
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/format"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/atoms"
+)
+
+// View はビューアを描画する。
+func (v Viewer) View() string {
+	records := v.visible()
+	title := i18n.T("tui.log_viewer.title", map[string]any{"Count": len(records)})
+	header := tui.HeaderStyle().Render("  " + title)
+	if v.follow {
+		header += "  " + tui.ActiveStyle().Render(i18n.T("tui.log_viewer.following"))
+	}
+
+	body := make([]string, 0, v.bodyHeight())
+	start := v.start()
+	query := strings.ToLower(v.search.Value())
+	for _, rec := range records[start:min(start+v.bodyHeight(), len(records))] {
+		body = append(body, lipgloss.NewStyle().MaxWidth(max(v.width-2, 1)).Render(" "+renderRecord(rec, query)))
+	}
+	if len(records) == 0 {
+		body = append(body, "  "+tui.MutedStyle().Render(i18n.T("tui.log_viewer.empty")))
+	}
+	for len(body) < v.bodyHeight() {
+		body = append(body, "")
+	}
+
+	var search string
+	switch {
+	case v.searching:
+		search = "  / " + v.search.View()
+	case v.status != "":
+		search = "  " + tui.ErrorStyle().Render(v.status)
+	case v.search.Value() != "":
+		search = "  " + tui.MutedStyle().Render("/ "+v.search.Value())
+	}
+	hint := tui.MutedStyle().Render("  " + i18n.T("tui.log_viewer.hint"))
+	return lipgloss.JoinVertical(lipgloss.Left, header, strings.Join(body, "\n"), search, hint)
+}
+
+// renderRecord はログ 1 件を "時刻 レベル メッセージ key=value ..." の形式で、レベルに応じた色で描画する。
+// query を含む箇所は強調する。
+func renderRecord(rec protocol.LogRecord, query string) string {
+	style := levelStyle(rec.Level)
+	text := recordText(rec)
+	return tui.MutedStyle().Render(format.Clock(rec.Time)+" ") +
+		style.Bold(true).Render(fmt.Sprintf("%-5s", rec.Level)) + " " +
+		atoms.RenderHighlight(text, matchPositions(text, query), messageStyle(rec.Level))
+}
+
+// plainLine は検索の対象にするログ 1 件の文字列を返す。
+func plainLine(rec protocol.LogRecord) string {
+	return rec.Level + " " + recordText(rec)
+}
+
+// recordText はメッセージと属性（キーの昇順）を 1 行にまとめて返す。
+func recordText(rec protocol.LogRecord) string {
+	var b strings.Builder
+	b.WriteString(rec.Message)
+	for _, k := range slices.Sorted(maps.Keys(rec.Attrs)) {
+		v := rec.Attrs[k]
+		if strings.ContainsAny(v, " \t\"=") {
+			v = fmt.Sprintf("%q", v)
+		}
+		fmt.Fprintf(&b, " %s=%s", k, v)
+	}
+	return b.String()
+}
+
+// matchPositions は text 中の query（小文字）に一致する文字の位置を昇順で返す。大文字と小文字は区別しない。
+func matchPositions(text, query string) []int {
+	if query == "" {
+		return nil
+	}
+	runes := []rune(strings.ToLower(text))
+	q := []rune(query)
+	var positions []int
+	for i := 0; i+len(q) <= len(runes); {
+		if slices.Equal(runes[i:i+len(q)], q) {
+			for j := range q {
+				positions = append(positions, i+j)
+			}
+			i += len(q)
+			continue
+		}
+		i++
+	}
+	return positions
+}
+
+// levelStyle はログレベルのラベルのスタイルを返す。
+func levelStyle(level string) lipgloss.Style {
+	switch level {
+	case "ERROR":
+		return tui.ErrorStyle()
+	case "WARN":
+		return tui.WarningStyle()
+	case "DEBUG":
+		return tui.MutedStyle()
+	default:
+		return tui.ActiveStyle()
+	}
+}
+
+// messageStyle はログのメッセージのスタイルを返す。エラーと警告はレベルの色、それ以外は通常の文字色で描画する。
+func messageStyle(level string) lipgloss.Style {
+	switch level {
+	case "ERROR", "WARN":
+		return levelStyle(level)
+	case "DEBUG":
+		return tui.MutedStyle()
+	default:
+		return tui.TextStyle()
+	}
+}
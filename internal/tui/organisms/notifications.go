@@ -0,0 +1,112 @@
+package organisms
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ousiassllc/moleport/internal/format"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+const (
+	// maxVisibleToasts は同時に表示するトーストの最大数。超えた分は古いものから隠す。
+	maxVisibleToasts = 3
+	// maxNotificationHistory は履歴に保持する通知の最大数。
+	maxNotificationHistory = 50
+	// toastWidth はトーストの表示幅。
+	toastWidth = 44
+)
+
+// NotificationCenter はトースト通知の表示と履歴を管理する。
+type NotificationCenter struct {
+	active  []molecules.Toast
+	history []molecules.Toast
+	nextID  int
+}
+
+// NewNotificationCenter は新しい NotificationCenter を生成する。
+func NewNotificationCenter() NotificationCenter {
+	return NotificationCenter{}
+}
+
+// Push は通知を追加し、自動消去タイマーの Cmd を返す。
+func (c *NotificationCenter) Push(severity molecules.ToastSeverity, text string) tea.Cmd {
+	c.nextID++
+	t := molecules.Toast{ID: c.nextID, Severity: severity, Text: text, Time: time.Now()}
+	c.active = append(c.active, t)
+	c.history = append(c.history, t)
+	if len(c.history) > maxNotificationHistory {
+		c.history = c.history[len(c.history)-maxNotificationHistory:]
+	}
+	return t.Timer()
+}
+
+// Dismiss は ID のトーストを表示から取り除く。履歴には残る。
+func (c *NotificationCenter) Dismiss(id int) {
+	for i, t := range c.active {
+		if t.ID == id {
+			c.active = append(c.active[:i:i], c.active[i+1:]...)
+			return
+		}
+	}
+}
+
+// Active は表示中のトーストを返す。
+func (c NotificationCenter) Active() []molecules.Toast {
+	return c.active
+}
+
+// History は通知履歴を古い順に返す。
+func (c NotificationCenter) History() []molecules.Toast {
+	return c.history
+}
+
+// Overlay は base の右上に表示中のトーストを重ねて描画する。
+func (c NotificationCenter) Overlay(base string, width int) string {
+	if len(c.active) == 0 || width < toastWidth+10 {
+		return base
+	}
+	visible := c.active
+	if len(visible) > maxVisibleToasts {
+		visible = visible[len(visible)-maxVisibleToasts:]
+	}
+	boxes := make([]string, len(visible))
+	for i, t := range visible {
+		// 新しい通知を上に表示する
+		boxes[len(visible)-1-i] = t.View(toastWidth)
+	}
+	toasts := strings.Split(lipgloss.JoinVertical(lipgloss.Left, boxes...), "\n")
+
+	lines := strings.Split(base, "\n")
+	left := width - toastWidth
+	for i, tl := range toasts {
+		if i >= len(lines) {
+			break
+		}
+		head := ansi.Truncate(lines[i], left, "")
+		pad := left - ansi.StringWidth(head)
+		lines[i] = head + strings.Repeat(" ", max(pad, 0)) + tl
+	}
+	return strings.Join(lines, "\n")
+}
+
+// HistoryView は通知履歴を新しい順に一覧表示するパネルを描画する。
+func (c NotificationCenter) HistoryView(height int) string {
+	lines := []string{tui.TitleStyle().Render(i18n.T("tui.notifications.title")), ""}
+	if len(c.history) == 0 {
+		lines = append(lines, tui.MutedStyle().Render("  "+i18n.T("tui.notifications.empty")))
+	}
+	limit := max(height-8, 1)
+	for i := len(c.history) - 1; i >= 0 && len(c.history)-i <= limit; i-- {
+		t := c.history[i]
+		lines = append(lines, "  "+t.Icon()+" "+
+			tui.MutedStyle().Render(format.Clock(t.Time))+" "+tui.TextStyle().Render(t.Text))
+	}
+	lines = append(lines, "", tui.MutedStyle().Render("  "+i18n.T("tui.help.any_key_close")))
+	return tui.FocusedBorder().Render(lipgloss.JoinVertical(lipgloss.Left, lines...))
+}
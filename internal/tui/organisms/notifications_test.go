@@ -0,0 +1,79 @@
+package organisms
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+func TestNotificationCenter_PushDismiss(t *testing.T) {
+	c := NewNotificationCenter()
+	if cmd := c.Push(molecules.ToastError, "first"); cmd == nil {
+		t.Error("Push should return a dismiss timer")
+	}
+	c.Push(molecules.ToastInfo, "second")
+
+	if len(c.Active()) != 2 || len(c.History()) != 2 {
+		t.Fatalf("active = %d, history = %d, want 2/2", len(c.Active()), len(c.History()))
+	}
+
+	c.Dismiss(c.Active()[0].ID)
+	if len(c.Active()) != 1 || c.Active()[0].Text != "second" {
+		t.Errorf("Active() = %+v, want only second", c.Active())
+	}
+	if len(c.History()) != 2 {
+		t.Errorf("Dismiss should keep history, got %d", len(c.History()))
+	}
+}
+
+func TestNotificationCenter_HistoryLimit(t *testing.T) {
+	c := NewNotificationCenter()
+	for i := range maxNotificationHistory + 5 {
+		c.Push(molecules.ToastInfo, fmt.Sprintf("n%d", i))
+	}
+	h := c.History()
+	if len(h) != maxNotificationHistory || h[0].Text != "n5" {
+		t.Errorf("history len = %d, first = %q", len(h), h[0].Text)
+	}
+}
+
+func TestNotificationCenter_Overlay(t *testing.T) {
+	base := strings.TrimSuffix(strings.Repeat(strings.Repeat("x", 100)+"\n", 10), "\n")
+
+	c := NewNotificationCenter()
+	if got := c.Overlay(base, 100); got != base {
+		t.Error("Overlay without toasts should return base unchanged")
+	}
+
+	c.Push(molecules.ToastWarning, "reconnecting")
+	got := c.Overlay(base, 100)
+	lines := strings.Split(got, "\n")
+	if len(lines) != 10 {
+		t.Fatalf("line count = %d, want 10", len(lines))
+	}
+	if !strings.Contains(got, "reconnecting") {
+		t.Error("Overlay should contain toast text")
+	}
+	for i, l := range lines {
+		if w := lipgloss.Width(l); w != 100 {
+			t.Errorf("line %d width = %d, want 100", i, w)
+		}
+	}
+	if c.Overlay(base, 20) != base {
+		t.Error("Overlay should be skipped on narrow screens")
+	}
+}
+
+func TestNotificationCenter_HistoryView(t *testing.T) {
+	c := NewNotificationCenter()
+	c.Push(molecules.ToastError, "older")
+	c.Push(molecules.ToastInfo, "newer")
+
+	view := c.HistoryView(30)
+	if i, j := strings.Index(view, "newer"), strings.Index(view, "older"); i < 0 || j < 0 || i > j {
+		t.Errorf("HistoryView should list newest first, got %q", view)
+	}
+}
@@ -3,6 +3,7 @@ package setuppanel
 import (
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -23,6 +24,7 @@ const (
 	StepRemotePort                   // リモートポート入力（Dynamic ではスキップ）
 	StepRuleName                     // ルール名入力（任意）
 	StepConfirm                      // 確認
+	StepNewHost                      // config.yaml に定義するホストの入力
 )
 
 // Panel はホスト選択 + フォワード追加ウィザードを提供するパネル。
@@ -45,6 +47,19 @@ type Panel struct {
 	remoteHost   string
 	remotePort   string
 	ruleName     string
+	// editing は編集中のルール。nil の場合は新規作成のウィザード。
+	editing *core.ForwardRule
+	// rules は登録済みのルール。確認ステップで待ち受けポートと転送先の重複を警告するのに使う。
+	rules []core.ForwardRule
+
+	showDetail bool
+
+	// filterInput はホスト一覧のフィルター。filtering はフィルターの入力中か。
+	filterInput textinput.Model
+	filtering   bool
+	// sortMode と groupMode はホスト一覧の並び順とグループ化の方法。
+	sortMode  SortMode
+	groupMode GroupMode
 
 	focused bool
 	width   int
@@ -65,12 +80,18 @@ func New() Panel {
 	nameIn.Placeholder = i18n.T("tui.setup_panel.rule_name_placeholder")
 	nameIn.CharLimit = 64
 
+	filterIn := textinput.New()
+	filterIn.Prompt = ""
+	filterIn.Placeholder = i18n.T("tui.setup_panel.filter_placeholder")
+	filterIn.CharLimit = 64
+
 	return Panel{
 		typeOptions: []string{"Local (-L)", "Remote (-R)", "Dynamic (-D)"},
 		keys:        tui.DefaultKeyMap(),
 		portInput:   portIn,
 		hostInput:   hostIn,
 		nameInput:   nameIn,
+		filterInput: filterIn,
 	}
 }
 
@@ -91,6 +112,11 @@ func (p *Panel) SetHosts(hosts []core.SSHHost) {
 	}
 }
 
+// SetRules は登録済みのルールを設定する。
+func (p *Panel) SetRules(rules []core.ForwardRule) {
+	p.rules = rules
+}
+
 // SetSize はパネルのサイズを設定する。
 func (p *Panel) SetSize(width, height int) {
 	p.width = width
@@ -105,10 +131,10 @@ func (p Panel) Hosts() []core.SSHHost {
 // IsInputActive はテキスト入力中かどうかを返す。
 func (p Panel) IsInputActive() bool {
 	switch p.step {
-	case StepLocalPort, StepRemoteHost, StepRemotePort, StepRuleName:
+	case StepLocalPort, StepRemoteHost, StepRemotePort, StepRuleName, StepNewHost:
 		return true
 	}
-	return false
+	return p.filtering
 }
 
 // UpdateHostState は指定ホストの状態を更新する。
@@ -121,6 +147,23 @@ func (p *Panel) UpdateHostState(hostName string, state core.ConnectionState) {
 	}
 }
 
+// UpdateHostHealth は指定ホストの疎通確認の結果を更新する。
+func (p *Panel) UpdateHostHealth(hostName string, health core.HostHealth) {
+	for i := range p.hosts {
+		if p.hosts[i].Name == hostName {
+			p.hosts[i].Health = health
+			break
+		}
+	}
+}
+
+// SetHostLatency は各ホストの keepalive の往復時間を更新する。latency に含まれないホストは未計測とする。
+func (p *Panel) SetHostLatency(latency map[string]time.Duration) {
+	for i := range p.hosts {
+		p.hosts[i].Latency = latency[p.hosts[i].Name]
+	}
+}
+
 // Update はキー入力を処理する。
 func (p Panel) Update(msg tea.Msg) (Panel, tea.Cmd) {
 	if !p.focused {
@@ -144,7 +187,7 @@ func (p Panel) Update(msg tea.Msg) (Panel, tea.Cmd) {
 		return p.updateIdle(keyMsg, p.keys)
 	case StepSelectType:
 		return p.updateSelectType(keyMsg, p.keys)
-	case StepLocalPort, StepRemoteHost, StepRemotePort, StepRuleName:
+	case StepLocalPort, StepRemoteHost, StepRemotePort, StepRuleName, StepNewHost:
 		return p.updateTextInput(msg)
 	case StepConfirm:
 		return p.updateConfirm(keyMsg, p.keys)
@@ -161,6 +204,7 @@ func (p *Panel) resetWizard() {
 	p.remoteHost = ""
 	p.remotePort = ""
 	p.ruleName = ""
+	p.editing = nil
 	p.portInput.Blur()
 	p.hostInput.Blur()
 	p.nameInput.Blur()
@@ -174,6 +218,9 @@ var wizardSteps = map[bool][]WizardStep{
 
 func (p Panel) stepProgress() (current int, total int) {
 	steps := wizardSteps[p.selectedType == core.Dynamic]
+	if p.editing != nil {
+		steps = editSteps[p.selectedType == core.Dynamic]
+	}
 	total = len(steps)
 	for i, s := range steps {
 		if s == p.step {
@@ -0,0 +1,39 @@
+package setuppanel
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/ruleconflict"
+	"github.com/ousiassllc/moleport/internal/i18n"
+)
+
+// conflictWarning は確認中のルールが登録済みのルールと待ち受けポートまたは転送先で重複する場合に警告文を返す。
+// 重複しない場合は空文字列を返す。
+func (p Panel) conflictWarning() string {
+	rule := core.ForwardRule{Name: p.ruleName, Host: p.selectedHost, Type: p.selectedType}
+	if p.editing != nil {
+		rule = *p.editing
+	}
+	rule.LocalPort, _ = strconv.Atoi(p.localPort)
+	if p.selectedType != core.Dynamic {
+		rule.RemoteHost = p.remoteHost
+		rule.RemotePort, _ = strconv.Atoi(p.remotePort)
+	}
+
+	err := ruleconflict.Check(rule, p.rules)
+	var conflict *core.PortConflictError
+	var duplicate *ruleconflict.DuplicateError
+	switch {
+	case errors.As(err, &conflict):
+		key := "tui.setup_panel.conflict_port"
+		if conflict.Suggestion != 0 {
+			key = "tui.setup_panel.conflict_port_suggest"
+		}
+		return i18n.T(key, map[string]any{"Port": conflict.Port, "Rule": conflict.Rule, "Suggestion": conflict.Suggestion})
+	case errors.As(err, &duplicate):
+		return i18n.T("tui.setup_panel.conflict_duplicate", map[string]any{"Rule": duplicate.Rule, "Target": duplicate.Target})
+	}
+	return ""
+}
@@ -0,0 +1,38 @@
+package setuppanel
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestPanel_ConflictWarning(t *testing.T) {
+	web := core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80}
+	tests := []struct {
+		name      string
+		localPort string
+		host      string
+		port      string
+		editing   *core.ForwardRule
+		want      string
+	}{
+		{"free", "9090", "localhost", "3000", nil, ""},
+		{"port taken", "8080", "localhost", "3000", nil, "8081"},
+		{"same target", "9090", "127.0.0.1", "80", nil, "127.0.0.1:80"},
+		{"editing itself", "8080", "localhost", "80", &web, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.SetRules([]core.ForwardRule{web})
+			p.selectedHost, p.selectedType = "prod", core.Local
+			p.localPort, p.remoteHost, p.remotePort = tt.localPort, tt.host, tt.port
+			p.editing = tt.editing
+			got := p.conflictWarning()
+			if (got == "") != (tt.want == "") || !strings.Contains(got, tt.want) {
+				t.Errorf("conflictWarning() = %q, want it to mention %q", got, tt.want)
+			}
+		})
+	}
+}
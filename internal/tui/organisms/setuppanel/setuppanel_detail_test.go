@@ -0,0 +1,85 @@
+package setuppanel
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestPanel_HostDetailToggle(t *testing.T) {
+	p := New()
+	p.focused = true
+	p.SetSize(80, 20)
+	p.hosts = []core.SSHHost{{
+		Name: "bastion", User: "ops", HostName: "10.0.0.1", Port: 22,
+		Meta: core.HostMetadata{AuthHint: "use yubikey"},
+	}}
+
+	info := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'i'}}
+	p, _ = p.Update(info)
+	if !p.showDetail {
+		t.Fatal("i should open host detail")
+	}
+	if !strings.Contains(p.View(), "use yubikey") {
+		t.Error("detail view should contain auth hint")
+	}
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.showDetail {
+		t.Error("Esc should close host detail")
+	}
+	if p.step != StepIdle {
+		t.Errorf("step = %v, want StepIdle", p.step)
+	}
+
+	// ホストがない場合は開かない
+	empty := New()
+	empty.focused = true
+	empty, _ = empty.Update(info)
+	if empty.showDetail {
+		t.Error("detail should not open without hosts")
+	}
+}
+
+func TestPanel_EnterClosesHostDetail(t *testing.T) {
+	p := New()
+	p.focused = true
+	p.hosts = []core.SSHHost{{Name: "bastion"}}
+	p.showDetail = true
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.showDetail {
+		t.Error("Enter should leave detail view and start wizard")
+	}
+	if p.step != StepSelectType {
+		t.Errorf("step = %v, want StepSelectType", p.step)
+	}
+}
+
+func TestPanel_RuleNameSlugified(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	p := New()
+	p.focused = true
+	p.hosts = []core.SSHHost{{Name: "db.prod"}}
+	p, _ = p.Update(enter) // -> SelectType
+	p, _ = p.Update(enter) // -> LocalPort
+	p = typeRunes(p, "5432")
+	p, _ = p.Update(enter) // -> RemoteHost
+	p, _ = p.Update(enter) // -> RemotePort
+	p, _ = p.Update(enter) // -> RuleName
+	if p.nameInput.Placeholder != "db-prod-local-5432" {
+		t.Errorf("placeholder = %q, want slug", p.nameInput.Placeholder)
+	}
+
+	p = typeRunes(p, "my db")
+	p, _ = p.Update(enter)
+	if p.step != StepRuleName || p.nameInput.Value() != "my-db" {
+		t.Fatalf("invalid name: step=%d value=%q, want slug suggestion", p.step, p.nameInput.Value())
+	}
+	p, _ = p.Update(enter)
+	if p.step != StepConfirm || p.ruleName != "my-db" {
+		t.Errorf("step=%d ruleName=%q, want confirm with my-db", p.step, p.ruleName)
+	}
+}
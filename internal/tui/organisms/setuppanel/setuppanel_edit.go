@@ -0,0 +1,56 @@
+package setuppanel
+
+import (
+	"strconv"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// editSteps はフォワード種別ごとの編集ウィザードのステップ順序を定義する。種別とルール名は変更しない。
+var editSteps = map[bool][]WizardStep{
+	true:  {StepLocalPort, StepConfirm},                                 // Dynamic
+	false: {StepLocalPort, StepRemoteHost, StepRemotePort, StepConfirm}, // Local/Remote
+}
+
+// StartEdit は rule を編集するウィザードを開始する。ホスト・種別・ルール名は変更せず、
+// 各ステップでは現在の値をプレースホルダーに表示して、空のまま確定した場合はその値を引き継ぐ。
+func (p *Panel) StartEdit(rule core.ForwardRule) tea.Cmd {
+	p.resetWizard()
+	p.showDetail = false
+	p.editing = &rule
+	p.selectedHost = rule.Host
+	p.selectedType = rule.Type
+	p.ruleName = rule.Name
+	p.step = StepLocalPort
+	p.portInput.Reset()
+	p.portInput.Placeholder = strconv.Itoa(rule.LocalPort)
+	p.portInput.Focus()
+	return textinput.Blink
+}
+
+// finishEdit は編集中の場合にルール名の入力を省いて確認ステップに進み、true を返す。
+func (p *Panel) finishEdit() bool {
+	if p.editing == nil {
+		return false
+	}
+	p.step = StepConfirm
+	p.portInput.Blur()
+	p.hostInput.Blur()
+	return true
+}
+
+// submitEdit は編集ウィザードを閉じ、入力した内容で ForwardUpdateRequestMsg を発行するコマンドを返す。
+func (p *Panel) submitEdit(localPort, remotePort int) tea.Cmd {
+	msg := tui.ForwardUpdateRequestMsg{
+		Name:       p.ruleName,
+		Type:       p.selectedType,
+		LocalPort:  localPort,
+		RemoteHost: p.remoteHost,
+		RemotePort: remotePort,
+	}
+	p.resetWizard()
+	return func() tea.Msg { return msg }
+}
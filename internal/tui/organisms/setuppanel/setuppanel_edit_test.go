@@ -0,0 +1,57 @@
+package setuppanel
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestPanel_StartEdit_KeepsDefaults(t *testing.T) {
+	p := New()
+	p.focused = true
+	_ = p.StartEdit(core.ForwardRule{Name: "db", Host: "bastion", Type: core.Local, LocalPort: 15432, RemoteHost: "db", RemotePort: 5432})
+	if p.step != StepLocalPort || !p.IsInputActive() {
+		t.Fatalf("step = %v, want StepLocalPort", p.step)
+	}
+
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	p = typeRunes(p, "15433")
+	p, _ = p.Update(enter) // -> StepRemoteHost
+	p, _ = p.Update(enter) // -> StepRemotePort
+	if cur, total := p.stepProgress(); cur != 3 || total != 4 {
+		t.Errorf("progress = %d/%d, want 3/4", cur, total)
+	}
+	p, _ = p.Update(enter) // -> StepConfirm（ルール名は入力しない）
+	if p.step != StepConfirm {
+		t.Fatalf("step = %v, want StepConfirm", p.step)
+	}
+
+	p, cmd := p.Update(enter)
+	want := tui.ForwardUpdateRequestMsg{Name: "db", Type: core.Local, LocalPort: 15433, RemoteHost: "db", RemotePort: 5432}
+	if cmd == nil || cmd() != want {
+		t.Fatalf("msg = %v, want %+v", cmd, want)
+	}
+	if p.step != StepIdle || p.editing != nil {
+		t.Errorf("wizard should be reset after submit, step = %v", p.step)
+	}
+}
+
+func TestPanel_StartEdit_DynamicSkipsRemote(t *testing.T) {
+	p := New()
+	p.focused = true
+	_ = p.StartEdit(core.ForwardRule{Name: "socks", Host: "bastion", Type: core.Dynamic, LocalPort: 1080})
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.step != StepConfirm {
+		t.Fatalf("step = %v, want StepConfirm", p.step)
+	}
+	if got := p.View(); got == "" {
+		t.Error("View() should render the confirm step")
+	}
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.step != StepIdle || p.editing != nil {
+		t.Errorf("Esc should cancel the edit, step = %v", p.step)
+	}
+}
@@ -0,0 +1,104 @@
+package setuppanel
+
+import (
+	"slices"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+// StartFilter はホスト一覧のフィルターの入力を開始する。ウィザードの途中では何もしない。
+func (p *Panel) StartFilter() tea.Cmd {
+	if p.step != StepIdle {
+		return nil
+	}
+	p.showDetail = false
+	p.filtering = true
+	return p.filterInput.Focus()
+}
+
+// updateFilter はフィルターの入力中のキーを処理する。
+// Enter で入力を終えてフィルターを保ち、Esc でフィルターを解除する。↑/↓ は一致するホストの間でカーソルを移動する。
+func (p Panel) updateFilter(keyMsg tea.KeyMsg) (Panel, tea.Cmd) {
+	prevCursor := p.hostCursor
+	var cmd tea.Cmd
+	switch keyMsg.Type {
+	case tea.KeyEsc:
+		p.clearFilter()
+	case tea.KeyEnter:
+		p.filtering = false
+		p.filterInput.Blur()
+	case tea.KeyUp:
+		p.moveCursor(-1)
+	case tea.KeyDown:
+		p.moveCursor(1)
+	default:
+		p.filterInput, cmd = p.filterInput.Update(keyMsg)
+		// 選択中のホストが一致しなくなった場合は最初に一致するホストを選択する
+		if visible := p.visibleHosts(); len(visible) > 0 && !slices.Contains(visible, p.hostCursor) {
+			p.hostCursor = visible[0]
+		}
+	}
+	return p, tea.Batch(cmd, p.hostSelected(prevCursor))
+}
+
+// clearFilter はフィルターを解除する。カーソルは選択中のホストに留まる。
+func (p *Panel) clearFilter() {
+	p.filtering = false
+	p.filterInput.Blur()
+	p.filterInput.Reset()
+}
+
+// visibleHosts はフィルターに一致するホストの p.hosts での位置を表示する順に返す。
+func (p Panel) visibleHosts() []int {
+	pattern := p.filterInput.Value()
+	var visible []int
+	for i, h := range p.hosts {
+		if _, _, ok := molecules.MatchHost(h, pattern); ok {
+			visible = append(visible, i)
+		}
+	}
+	p.orderHosts(visible)
+	return visible
+}
+
+// selectable はカーソルが一覧に表示されているホストを指しているかを返す。
+func (p Panel) selectable() bool {
+	return slices.Contains(p.visibleHosts(), p.hostCursor)
+}
+
+// moveCursor はフィルターに一致するホストの間でカーソルを delta 件移動する。
+func (p *Panel) moveCursor(delta int) {
+	visible := p.visibleHosts()
+	i := slices.Index(visible, p.hostCursor)
+	if i < 0 {
+		if len(visible) > 0 {
+			p.hostCursor = visible[0]
+		}
+		return
+	}
+	if j := i + delta; j >= 0 && j < len(visible) {
+		p.hostCursor = visible[j]
+	}
+}
+
+// hostSelected はカーソルが prevCursor から移動した場合に HostSelectedMsg を発行するコマンドを返す。
+func (p Panel) hostSelected(prevCursor int) tea.Cmd {
+	if prevCursor == p.hostCursor || p.hostCursor >= len(p.hosts) {
+		return nil
+	}
+	host := p.hosts[p.hostCursor]
+	return func() tea.Msg {
+		return tui.HostSelectedMsg{Host: host}
+	}
+}
+
+// viewFilter はフィルターの入力欄または適用中のフィルターを描画する。
+func (p Panel) viewFilter() string {
+	if p.filtering {
+		return tui.ActiveStyle().Render("/ ") + p.filterInput.View()
+	}
+	return tui.MutedStyle().Render("/ " + p.filterInput.Value() + "  " + i18n.T("tui.setup_panel.filter_clear"))
+}
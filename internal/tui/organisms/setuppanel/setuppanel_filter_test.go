@@ -0,0 +1,72 @@
+package setuppanel
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPanel_Filter(t *testing.T) {
+	p := New()
+	p.focused = true
+	p.SetSize(80, 20)
+	p.hosts = makeHosts("alpha", "prod-web", "prod-db", "staging")
+	p.hostCursor = 3
+
+	p.StartFilter()
+	if !p.IsInputActive() {
+		t.Fatal("filter input should be active")
+	}
+	p = typeRunes(p, "prd")
+	if got := p.visibleHosts(); !slices.Equal(got, []int{1, 2}) {
+		t.Fatalf("visibleHosts = %v, want [1 2]", got)
+	}
+	// 選択中のホストが一致しなくなった場合は最初に一致するホストを選択する
+	if p.hostCursor != 1 {
+		t.Errorf("hostCursor = %d, want 1", p.hostCursor)
+	}
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if p.hostCursor != 2 {
+		t.Errorf("hostCursor = %d, want 2", p.hostCursor)
+	}
+	view := p.View()
+	if strings.Contains(view, "alpha") || !strings.Contains(view, "prod-db") {
+		t.Errorf("view should list only matching hosts:\n%s", view)
+	}
+
+	// Enter で入力を終えてもフィルターは保たれる
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.filtering || p.step != StepIdle || len(p.visibleHosts()) != 2 {
+		t.Errorf("Enter should keep the filter: filtering = %v, step = %v", p.filtering, p.step)
+	}
+
+	// Esc でフィルターを解除しても選択は保たれる
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if p.filterInput.Value() != "" || len(p.visibleHosts()) != 4 {
+		t.Error("Esc should clear the filter")
+	}
+	if p.hostCursor != 2 {
+		t.Errorf("hostCursor after clear = %d, want 2", p.hostCursor)
+	}
+}
+
+func TestPanel_FilterNoMatches(t *testing.T) {
+	p := New()
+	p.focused = true
+	p.SetSize(80, 20)
+	p.hosts = makeHosts("alpha")
+	p.StartFilter()
+	p = typeRunes(p, "zz")
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	// 一致するホストがない場合はウィザードを開始しない
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.step != StepIdle {
+		t.Errorf("step = %v, want StepIdle", p.step)
+	}
+	if !strings.Contains(p.View(), "No matching hosts") {
+		t.Errorf("view should show no matches:\n%s", p.View())
+	}
+}
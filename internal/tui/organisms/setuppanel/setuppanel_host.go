@@ -0,0 +1,57 @@
+package setuppanel
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// newHostPlaceholder はホスト定義の入力欄のプレースホルダー。
+const newHostPlaceholder = "bastion ops@203.0.113.10:22"
+
+// parseHostSpec は "名前 [user@]hostname[:port]" 形式の入力をホスト定義に変換する。
+func parseHostSpec(s string) (core.HostDefinition, bool) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return core.HostDefinition{}, false
+	}
+	def := core.HostDefinition{Name: fields[0]}
+	target := fields[1]
+	if i := strings.LastIndex(target, "@"); i >= 0 {
+		def.User, target = target[:i], target[i+1:]
+	}
+	def.HostName = strings.Trim(target, "[]")
+	if host, port, err := net.SplitHostPort(target); err == nil {
+		n, err := strconv.Atoi(port)
+		if err != nil {
+			return core.HostDefinition{}, false
+		}
+		def.HostName, def.Port = host, n
+	}
+	return def, def.Validate() == nil
+}
+
+// deleteHost は config.yaml で定義したホストの削除を要求する。ssh_config のホストはエラーをログに出す。
+func deleteHost(host core.SSHHost) tea.Cmd {
+	if host.Source != core.HostSourceConfig {
+		return func() tea.Msg {
+			return tui.LogOutputMsg{Text: i18n.T("tui.log.host_delete_ssh_config", map[string]any{"Name": host.Name}), Level: tui.LogError}
+		}
+	}
+	return func() tea.Msg { return tui.HostDeleteRequestMsg{Name: host.Name} }
+}
+
+// viewNewHost はホスト定義の入力欄を描画する。
+func (p Panel) viewNewHost() []string {
+	return []string{
+		tui.MutedStyle().Render(i18n.T("tui.setup_panel.new_host_format")),
+		tui.TextStyle().Render(i18n.T("tui.setup_panel.label_new_host")+": ") + p.hostInput.View(),
+		"",
+		tui.MutedStyle().Render(i18n.T("tui.setup_panel.enter_add_host")),
+	}
+}
@@ -0,0 +1,89 @@
+package setuppanel
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestParseHostSpec(t *testing.T) {
+	tests := []struct {
+		input string
+		want  core.HostDefinition
+		ok    bool
+	}{
+		{"bastion ops@203.0.113.10:2222", core.HostDefinition{Name: "bastion", User: "ops", HostName: "203.0.113.10", Port: 2222}, true},
+		{"db db.example.com", core.HostDefinition{Name: "db", HostName: "db.example.com"}, true},
+		{"v6 [2001:db8::1]:22", core.HostDefinition{Name: "v6", HostName: "2001:db8::1", Port: 22}, true},
+		{"bastion", core.HostDefinition{}, false},
+		{"web* web.example.com", core.HostDefinition{}, false},
+		{"web web.example.com:99999", core.HostDefinition{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseHostSpec(tt.input)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Errorf("parseHostSpec(%q) = %+v, %v; want %+v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestPanel_AddHost(t *testing.T) {
+	p := New()
+	p.focused = true
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if p.step != StepNewHost || !p.IsInputActive() {
+		t.Fatalf("step = %v, want StepNewHost with active input", p.step)
+	}
+
+	// 形式が不正な入力では確定しない
+	p = typeRunes(p, "bastion")
+	p, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.step != StepNewHost || cmd != nil {
+		t.Fatalf("invalid input should stay in StepNewHost, step = %v", p.step)
+	}
+
+	p = typeRunes(p, " ops@10.0.0.1")
+	p, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if p.step != StepIdle || cmd == nil {
+		t.Fatalf("step = %v, want StepIdle with command", p.step)
+	}
+	msg, ok := cmd().(tui.HostAddRequestMsg)
+	if !ok || msg.Host.Name != "bastion" || msg.Host.User != "ops" || msg.Host.HostName != "10.0.0.1" {
+		t.Errorf("msg = %+v, want HostAddRequestMsg for bastion", msg)
+	}
+}
+
+func TestPanel_DeleteHost(t *testing.T) {
+	p := New()
+	p.focused = true
+	p.hosts = []core.SSHHost{
+		{Name: "web", Source: core.HostSourceSSHConfig},
+		{Name: "bastion", Source: core.HostSourceConfig},
+	}
+	del := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'x'}}
+
+	_, cmd := p.Update(del)
+	if _, ok := cmd().(tui.LogOutputMsg); !ok {
+		t.Error("deleting ssh_config host should log an error")
+	}
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd = p.Update(del)
+	if msg, ok := cmd().(tui.HostDeleteRequestMsg); !ok || msg.Name != "bastion" {
+		t.Errorf("msg = %+v, want HostDeleteRequestMsg for bastion", msg)
+	}
+}
+
+func TestPanel_SetHostLatency(t *testing.T) {
+	p := New()
+	p.SetHosts([]core.SSHHost{{Name: "prod", Latency: time.Second}, {Name: "staging"}})
+	p.SetHostLatency(map[string]time.Duration{"staging": 20 * time.Millisecond})
+	hosts := p.Hosts()
+	// 結果に含まれないホスト（切断済み等）は未計測に戻す
+	if hosts[0].Latency != 0 || hosts[1].Latency != 20*time.Millisecond {
+		t.Errorf("Latency = %v, %v; want 0, 20ms", hosts[0].Latency, hosts[1].Latency)
+	}
+}
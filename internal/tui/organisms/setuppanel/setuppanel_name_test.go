@@ -0,0 +1,94 @@
+package setuppanel
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestPanel_RuleNameSuggestion(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	p := setupWizardAt(StepLocalPort)
+	p = typeRunes(p, "3000")
+	p, _ = p.Update(enter) // -> RemoteHost
+	p, _ = p.Update(enter) // -> RemotePort
+	p, _ = p.Update(enter) // -> RuleName
+	if p.step != StepRuleName {
+		t.Fatalf("step=%d want StepRuleName", p.step)
+	}
+	if want := "test-host-local-3000"; p.nameInput.Placeholder != want {
+		t.Errorf("placeholder=%q want %q", p.nameInput.Placeholder, want)
+	}
+
+	d := setupWizardAt(StepSelectType)
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyDown})
+	d, _ = d.Update(enter)
+	d = typeRunes(d, "1080")
+	d, _ = d.Update(enter) // -> RuleName
+	if want := "test-host-dynamic-1080"; d.nameInput.Placeholder != want {
+		t.Errorf("dynamic placeholder=%q want %q", d.nameInput.Placeholder, want)
+	}
+}
+
+func TestPanel_RuleNameSuggestionAvoidsExisting(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	p := setupWizardAt(StepLocalPort)
+	p.SetRules([]core.ForwardRule{{Name: "test-host-local-3000"}, {Name: "my-web"}})
+	p = typeRunes(p, "3000")
+	p, _ = p.Update(enter) // -> RemoteHost
+	p, _ = p.Update(enter) // -> RemotePort
+	p, _ = p.Update(enter) // -> RuleName
+	if want := "test-host-local-3000-2"; p.nameInput.Placeholder != want {
+		t.Errorf("placeholder=%q want %q", p.nameInput.Placeholder, want)
+	}
+	p = typeRunes(p, "my web")
+	p, _ = p.Update(enter)
+	if got := p.nameInput.Value(); got != "my-web-2" {
+		t.Errorf("name input=%q want my-web-2", got)
+	}
+}
+
+func TestPanel_RuleNameInvalidIsReplaced(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	p := setupWizardAt(StepLocalPort)
+	p = typeRunes(p, "3000")
+	p, _ = p.Update(enter) // -> RemoteHost
+	p, _ = p.Update(enter) // -> RemotePort
+	p, _ = p.Update(enter) // -> RuleName
+	p = typeRunes(p, "my web")
+	p, _ = p.Update(enter)
+	if p.step != StepRuleName {
+		t.Fatalf("step=%d want StepRuleName for an invalid name", p.step)
+	}
+	if got := p.nameInput.Value(); got != "my-web" {
+		t.Errorf("name input=%q want my-web", got)
+	}
+	p, _ = p.Update(enter)
+	if p.step != StepConfirm || p.ruleName != "my-web" {
+		t.Errorf("step=%d ruleName=%q want StepConfirm my-web", p.step, p.ruleName)
+	}
+}
+
+func TestPanel_RuleNameEmptyIsLeftToDaemon(t *testing.T) {
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+	p := setupWizardAt(StepLocalPort)
+	p = typeRunes(p, "8080")
+	p, _ = p.Update(enter) // -> RemoteHost
+	p, _ = p.Update(enter) // -> RemotePort
+	p, _ = p.Update(enter) // -> RuleName
+	p, _ = p.Update(enter) // -> Confirm
+	_, cmd := p.Update(enter)
+	if cmd == nil {
+		t.Fatal("confirm Enter should produce cmd")
+	}
+	msg, ok := cmd().(tui.ForwardAddRequestMsg)
+	if !ok {
+		t.Fatalf("expected ForwardAddRequestMsg, got %T", cmd())
+	}
+	if msg.Name != "" {
+		t.Errorf("msg.Name = %q, want empty so that the daemon generates the name", msg.Name)
+	}
+}
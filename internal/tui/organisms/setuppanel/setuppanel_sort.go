@@ -0,0 +1,141 @@
+package setuppanel
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+)
+
+// SortMode はホスト一覧の並び順。
+type SortMode int
+
+const (
+	SortDefault  SortMode = iota // ssh_config の定義順
+	SortName                     // 名前順
+	SortState                    // 接続状態順（接続中のホストが先）
+	SortForwards                 // アクティブなフォワード数の多い順
+)
+
+// GroupMode はホスト一覧のグループ化の方法。
+type GroupMode int
+
+const (
+	GroupNone   GroupMode = iota
+	GroupPrefix           // 名前の区切り文字（"-" "." "_"）より前の部分
+	GroupTag              // config.yaml の tags の先頭のタグ
+)
+
+// stateRank は SortState での接続状態の順位を返す。小さいほど先に並ぶ。
+func stateRank(s core.ConnectionState) int {
+	switch s {
+	case core.Connected:
+		return 0
+	case core.Connecting, core.Reconnecting, core.PendingAuth:
+		return 1
+	case core.ConnectionError:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// groupKey は mode でのホストのグループ名を返す。グループに属さない場合は空を返す。
+func groupKey(h core.SSHHost, mode GroupMode) string {
+	switch mode {
+	case GroupPrefix:
+		if i := strings.IndexAny(h.Name, "-._"); i > 0 {
+			return h.Name[:i]
+		}
+	case GroupTag:
+		if len(h.Meta.Tags) > 0 {
+			return h.Meta.Tags[0]
+		}
+	}
+	return ""
+}
+
+// orderHosts は p.hosts の位置 indices をグループ名・並び順に従って並べ替える。
+// グループ名は昇順で、グループに属さないホストは最後に並ぶ。同順位のホストは定義順を保つ。
+func (p Panel) orderHosts(indices []int) {
+	slices.SortStableFunc(indices, func(a, b int) int {
+		ha, hb := p.hosts[a], p.hosts[b]
+		if p.groupMode != GroupNone {
+			ga, gb := groupKey(ha, p.groupMode), groupKey(hb, p.groupMode)
+			if (ga == "") != (gb == "") {
+				if ga == "" {
+					return 1
+				}
+				return -1
+			}
+			if c := cmp.Compare(ga, gb); c != 0 {
+				return c
+			}
+		}
+		switch p.sortMode {
+		case SortName:
+			return cmp.Compare(strings.ToLower(ha.Name), strings.ToLower(hb.Name))
+		case SortState:
+			return cmp.Compare(stateRank(ha.State), stateRank(hb.State))
+		case SortForwards:
+			return cmp.Compare(hb.ActiveForwardCount, ha.ActiveForwardCount)
+		}
+		return 0
+	})
+}
+
+// CycleSort はホスト一覧の並び順を定義順・名前順・接続状態順・フォワード数順の順に切り替える。
+func (p *Panel) CycleSort() {
+	p.sortMode = (p.sortMode + 1) % (SortForwards + 1)
+}
+
+// CycleGroup はホスト一覧のグループ化をなし・名前の接頭辞・タグの順に切り替える。
+func (p *Panel) CycleGroup() {
+	p.groupMode = (p.groupMode + 1) % (GroupTag + 1)
+}
+
+// sortLabelKeys と groupLabelKeys は並び順・グループ化の方法の表示名の翻訳キー。
+var (
+	sortLabelKeys = map[SortMode]string{
+		SortName:     "tui.setup_panel.sort_name",
+		SortState:    "tui.setup_panel.sort_state",
+		SortForwards: "tui.setup_panel.sort_forwards",
+	}
+	groupLabelKeys = map[GroupMode]string{
+		GroupPrefix: "tui.setup_panel.group_prefix",
+		GroupTag:    "tui.setup_panel.group_tag",
+	}
+)
+
+// orderLabel はタイトルに添える並び順とグループ化の表示を返す。既定の場合は空を返す。
+func (p Panel) orderLabel() string {
+	var parts []string
+	if k, ok := sortLabelKeys[p.sortMode]; ok {
+		parts = append(parts, i18n.T("tui.setup_panel.sort_label", map[string]any{"Mode": i18n.T(k)}))
+	}
+	if k, ok := groupLabelKeys[p.groupMode]; ok {
+		parts = append(parts, i18n.T("tui.setup_panel.group_label", map[string]any{"Mode": i18n.T(k)}))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// hostLines はホスト一覧に描画する行を返す。グループ化している場合はグループの先頭に見出しの行を挟む。
+// 各行は p.hosts の位置で、見出しの行は -1 とし、行の位置ごとの見出しを headers に返す。
+func (p Panel) hostLines(visible []int) (lines []int, headers map[int]string) {
+	if p.groupMode == GroupNone {
+		return visible, nil
+	}
+	headers = make(map[int]string)
+	prev := ""
+	for n, i := range visible {
+		if g := groupKey(p.hosts[i], p.groupMode); n == 0 || g != prev {
+			headers[len(lines)] = cmp.Or(g, i18n.T("tui.setup_panel.ungrouped"))
+			lines = append(lines, -1)
+			prev = g
+		}
+		lines = append(lines, i)
+	}
+	return lines, headers
+}
@@ -0,0 +1,98 @@
+package setuppanel
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// sortTestHosts は並び替え・グループ化の確認に使うホスト一覧を返す。
+func sortTestHosts() []core.SSHHost {
+	return []core.SSHHost{
+		{Name: "staging-web", State: core.Disconnected, ActiveForwardCount: 0, Meta: core.HostMetadata{Tags: []string{"env:stg"}}},
+		{Name: "bastion", State: core.Connected, ActiveForwardCount: 1},
+		{Name: "prod-db", State: core.ConnectionError, ActiveForwardCount: 0, Meta: core.HostMetadata{Tags: []string{"env:prod"}}},
+		{Name: "prod-web", State: core.Connected, ActiveForwardCount: 3, Meta: core.HostMetadata{Tags: []string{"env:prod", "team:web"}}},
+	}
+}
+
+func TestPanel_SortModes(t *testing.T) {
+	p := New()
+	p.hosts = sortTestHosts()
+	tests := []struct {
+		mode SortMode
+		want []int
+	}{
+		{SortDefault, []int{0, 1, 2, 3}},
+		{SortName, []int{1, 2, 3, 0}},
+		{SortState, []int{1, 3, 2, 0}},
+		{SortForwards, []int{3, 1, 0, 2}},
+	}
+	for _, tt := range tests {
+		p.sortMode = tt.mode
+		if got := p.visibleHosts(); !slices.Equal(got, tt.want) {
+			t.Errorf("sort %d: visibleHosts = %v, want %v", tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestPanel_GroupModes(t *testing.T) {
+	p := New()
+	p.hosts = sortTestHosts()
+	p.sortMode = SortName
+
+	p.groupMode = GroupPrefix
+	lines, headers := p.hostLines(p.visibleHosts())
+	if want := []int{-1, 2, 3, -1, 0, -1, 1}; !slices.Equal(lines, want) {
+		t.Errorf("prefix lines = %v, want %v", lines, want)
+	}
+	if headers[0] != "prod" || headers[3] != "staging" || headers[5] != "(ungrouped)" {
+		t.Errorf("prefix headers = %v", headers)
+	}
+
+	p.groupMode = GroupTag
+	lines, headers = p.hostLines(p.visibleHosts())
+	if want := []int{-1, 2, 3, -1, 0, -1, 1}; !slices.Equal(lines, want) {
+		t.Errorf("tag lines = %v, want %v", lines, want)
+	}
+	if headers[0] != "env:prod" || headers[3] != "env:stg" {
+		t.Errorf("tag headers = %v", headers)
+	}
+}
+
+func TestPanel_SortAndGroupKeys(t *testing.T) {
+	p := New()
+	p.focused = true
+	p.SetSize(80, 20)
+	p.hosts = sortTestHosts()
+
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'s'}})
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'g'}})
+	if p.sortMode != SortName || p.groupMode != GroupPrefix {
+		t.Fatalf("sortMode = %d, groupMode = %d, want name and prefix", p.sortMode, p.groupMode)
+	}
+	view := p.View()
+	if !strings.Contains(view, "sort: name") || !strings.Contains(view, "prod") {
+		t.Errorf("view should show the sort mode and group headers:\n%s", view)
+	}
+
+	// カーソルは表示順に移動し、見出しの行は飛ばす
+	p.hostCursor = 3
+	p, _ = p.Update(tea.KeyMsg{Type: tea.KeyDown})
+	if p.hostCursor != 0 {
+		t.Errorf("hostCursor = %d, want 0 (staging-web)", p.hostCursor)
+	}
+
+	for range 2 {
+		p.CycleGroup()
+	}
+	for range 3 {
+		p.CycleSort()
+	}
+	if p.sortMode != SortDefault || p.groupMode != GroupNone || p.orderLabel() != "" {
+		t.Errorf("modes should cycle back to default: sort %d, group %d", p.sortMode, p.groupMode)
+	}
+}
@@ -1,23 +1,29 @@
 package setuppanel
 
 import (
-	"fmt"
 	"strconv"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/core/rulename"
 	"github.com/ousiassllc/moleport/internal/tui"
 )
 
 func (p Panel) updateTextInputs(msg tea.Msg) (Panel, tea.Cmd) {
 	switch p.step {
+	case StepIdle:
+		if p.filtering {
+			var cmd tea.Cmd
+			p.filterInput, cmd = p.filterInput.Update(msg)
+			return p, cmd
+		}
 	case StepLocalPort, StepRemotePort:
 		var cmd tea.Cmd
 		p.portInput, cmd = p.portInput.Update(msg)
 		return p, cmd
-	case StepRemoteHost:
+	case StepRemoteHost, StepNewHost:
 		var cmd tea.Cmd
 		p.hostInput, cmd = p.hostInput.Update(msg)
 		return p, cmd
@@ -30,19 +36,50 @@ func (p Panel) updateTextInputs(msg tea.Msg) (Panel, tea.Cmd) {
 }
 
 func (p Panel) updateIdle(keyMsg tea.KeyMsg, keys tui.KeyMap) (Panel, tea.Cmd) {
+	if p.filtering {
+		return p.updateFilter(keyMsg)
+	}
 	prevCursor := p.hostCursor
 
+	if p.showDetail && key.Matches(keyMsg, keys.Escape) {
+		p.showDetail = false
+		return p, nil
+	}
+
 	switch {
+	case key.Matches(keyMsg, keys.Escape):
+		p.clearFilter()
+		return p, nil
 	case key.Matches(keyMsg, keys.Up):
-		if p.hostCursor > 0 {
-			p.hostCursor--
-		}
+		p.moveCursor(-1)
 	case key.Matches(keyMsg, keys.Down):
-		if p.hostCursor < len(p.hosts)-1 {
-			p.hostCursor++
+		p.moveCursor(1)
+	case key.Matches(keyMsg, keys.Sort):
+		p.CycleSort()
+		return p, nil
+	case key.Matches(keyMsg, keys.Groups):
+		p.CycleGroup()
+		return p, nil
+	case key.Matches(keyMsg, keys.Info):
+		if p.selectable() {
+			p.showDetail = !p.showDetail
 		}
+		return p, nil
+	case key.Matches(keyMsg, keys.AddHost):
+		p.showDetail = false
+		p.step = StepNewHost
+		p.hostInput.Reset()
+		p.hostInput.Placeholder = newHostPlaceholder
+		p.hostInput.Focus()
+		return p, textinput.Blink
+	case key.Matches(keyMsg, keys.Delete):
+		if p.selectable() {
+			return p, deleteHost(p.hosts[p.hostCursor])
+		}
+		return p, nil
 	case key.Matches(keyMsg, keys.Enter):
-		if len(p.hosts) > 0 && p.hostCursor < len(p.hosts) {
+		if p.selectable() {
+			p.showDetail = false
 			p.selectedHost = p.hosts[p.hostCursor].Name
 			p.step = StepSelectType
 			p.typeCursor = 0
@@ -52,15 +89,7 @@ func (p Panel) updateIdle(keyMsg tea.KeyMsg, keys tui.KeyMap) (Panel, tea.Cmd) {
 		return p, nil
 	}
 
-	// カーソルが移動した場合に HostSelectedMsg を発行
-	if prevCursor != p.hostCursor && len(p.hosts) > 0 {
-		host := p.hosts[p.hostCursor]
-		return p, func() tea.Msg {
-			return tui.HostSelectedMsg{Host: host}
-		}
-	}
-
-	return p, nil
+	return p, p.hostSelected(prevCursor)
 }
 
 func (p Panel) updateSelectType(keyMsg tea.KeyMsg, keys tui.KeyMap) (Panel, tea.Cmd) {
@@ -98,7 +127,7 @@ func (p Panel) updateTextInput(msg tea.Msg) (Panel, tea.Cmd) {
 		switch p.step {
 		case StepLocalPort, StepRemotePort:
 			value = p.portInput.Value()
-		case StepRemoteHost:
+		case StepRemoteHost, StepNewHost:
 			value = p.hostInput.Value()
 		case StepRuleName:
 			value = p.nameInput.Value()
@@ -115,7 +144,8 @@ func (p Panel) advanceFromTextStep(value string) (Panel, tea.Cmd) {
 		if value == "" {
 			value = p.portInput.Placeholder
 		}
-		if err := validatePortStr(value); err != nil {
+		// Local/Dynamic の 0 は開始時に空いているポートを割り当てる
+		if err := validatePortStr(value); err != nil && (value != "0" || p.selectedType == core.Remote) {
 			return p, nil // 無効な値は無視
 		}
 		p.localPort = value
@@ -123,16 +153,21 @@ func (p Panel) advanceFromTextStep(value string) (Panel, tea.Cmd) {
 			// Dynamic の場合は RemoteHost/RemotePort をスキップ
 			p.remoteHost = ""
 			p.remotePort = "0"
+			if p.finishEdit() {
+				return p, nil
+			}
 			p.step = StepRuleName
 			p.nameInput.Reset()
-			suggestion := fmt.Sprintf("%s-dynamic-%s", p.selectedHost, p.localPort)
-			p.nameInput.Placeholder = suggestion
+			p.nameInput.Placeholder = p.suggestName()
 			p.nameInput.Focus()
 			return p, textinput.Blink
 		}
 		p.step = StepRemoteHost
 		p.hostInput.Reset()
 		p.hostInput.Placeholder = "localhost"
+		if p.editing != nil {
+			p.hostInput.Placeholder = p.editing.RemoteHost
+		}
 		p.hostInput.Focus()
 		return p, textinput.Blink
 
@@ -144,6 +179,12 @@ func (p Panel) advanceFromTextStep(value string) (Panel, tea.Cmd) {
 		p.step = StepRemotePort
 		p.portInput.Reset()
 		p.portInput.Placeholder = p.localPort
+		if p.localPort == "0" {
+			p.portInput.Placeholder = "" // 自動割り当ての 0 はリモートポートの候補にしない
+		}
+		if p.editing != nil {
+			p.portInput.Placeholder = strconv.Itoa(p.editing.RemotePort)
+		}
 		p.portInput.Focus()
 		return p, textinput.Blink
 
@@ -155,19 +196,30 @@ func (p Panel) advanceFromTextStep(value string) (Panel, tea.Cmd) {
 			return p, nil
 		}
 		p.remotePort = value
+		if p.finishEdit() {
+			return p, nil
+		}
 		p.step = StepRuleName
 		p.nameInput.Reset()
-		typeStr := p.selectedType.String()
-		suggestion := fmt.Sprintf("%s-%s-%s", p.selectedHost, typeStr, p.localPort)
-		p.nameInput.Placeholder = suggestion
+		p.nameInput.Placeholder = p.suggestName()
 		p.nameInput.Focus()
 		return p, textinput.Blink
 
+	case StepNewHost:
+		def, ok := parseHostSpec(value)
+		if !ok {
+			return p, nil // 無効な値は無視
+		}
+		p.resetWizard()
+		return p, func() tea.Msg { return tui.HostAddRequestMsg{Host: def} }
+
 	case StepRuleName:
-		if value == "" {
-			// プレースホルダーの値を使用
-			value = p.nameInput.Placeholder
+		if value != "" && !rulename.Valid(value) {
+			// 命名規則に沿った名前に置き換えて再確認を促す
+			p.nameInput.SetValue(rulename.Suggest(value, p.ruleNames()))
+			return p, nil
 		}
+		// 空欄の場合は名前を省略し、デーモンが既存のルールと重複しない名前を生成する
 		p.ruleName = value
 		p.step = StepConfirm
 		p.portInput.Blur()
@@ -179,10 +231,29 @@ func (p Panel) advanceFromTextStep(value string) (Panel, tea.Cmd) {
 	return p, nil
 }
 
+// suggestName は入力中のルールの名前の候補を返す。デーモンが名前を省略したルールに付ける名前と同じく
+// rulename.Generate で生成し、登録済みのルールと重複しないようにする。
+func (p Panel) suggestName() string {
+	port, _ := strconv.Atoi(p.localPort)
+	return rulename.Generate(p.selectedHost, p.selectedType, port, p.ruleNames())
+}
+
+// ruleNames は登録済みのルール名を返す。
+func (p Panel) ruleNames() []string {
+	names := make([]string, len(p.rules))
+	for i, r := range p.rules {
+		names[i] = r.Name
+	}
+	return names
+}
+
 func (p Panel) updateConfirm(keyMsg tea.KeyMsg, keys tui.KeyMap) (Panel, tea.Cmd) {
 	if key.Matches(keyMsg, keys.Enter) {
 		localPort, _ := strconv.Atoi(p.localPort)
 		remotePort, _ := strconv.Atoi(p.remotePort)
+		if p.editing != nil {
+			return p, p.submitEdit(localPort, remotePort)
+		}
 
 		msg := tui.ForwardAddRequestMsg{
 			Host:        p.selectedHost,
@@ -2,6 +2,7 @@ package setuppanel
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/textinput"
@@ -10,6 +11,7 @@ import (
 	"github.com/ousiassllc/moleport/internal/tui"
 	"github.com/ousiassllc/moleport/internal/tui/molecules"
 	"github.com/ousiassllc/moleport/internal/tui/organisms"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // View はパネルを描画する。
@@ -21,7 +23,19 @@ func (p Panel) View() string {
 
 	switch p.step {
 	case StepIdle:
+		if p.showDetail && p.hostCursor < len(p.hosts) {
+			host := p.hosts[p.hostCursor]
+			title = i18n.T("tui.host_detail.title", map[string]any{"Name": host.Name})
+			rows = []string{molecules.HostDetail{Host: host, Width: innerWidth}.View()}
+			break
+		}
 		title = i18n.T("tui.setup_panel.title", map[string]any{"Count": len(p.hosts)})
+		if p.filterInput.Value() != "" {
+			title = i18n.T("tui.setup_panel.filtered_title", map[string]any{"Count": len(p.visibleHosts()), "Total": len(p.hosts)})
+		}
+		if label := p.orderLabel(); label != "" {
+			title += " · " + label
+		}
 		rows = p.viewHostList(innerWidth, innerHeight)
 	case StepSelectType:
 		title = p.wizardTitleText()
@@ -41,6 +55,9 @@ func (p Panel) View() string {
 	case StepConfirm:
 		title = p.wizardTitleText()
 		rows = p.viewConfirm()
+	case StepNewHost:
+		title = i18n.T("tui.setup_panel.new_host_title")
+		rows = p.viewNewHost()
 	}
 
 	border := tui.UnfocusedBorder()
@@ -52,32 +69,52 @@ func (p Panel) View() string {
 	return tui.RenderWithBorderTitle(border, innerWidth, innerHeight, title, content)
 }
 
+// viewHostList はフィルターに一致するホストの一覧を描画する。フィルターの入力中または適用中は先頭にフィルターを表示し、
+// グループ化している場合はグループの見出しを挟む。
 func (p Panel) viewHostList(innerWidth, innerHeight int) []string {
 	var rows []string
+	pattern := p.filterInput.Value()
+	if p.filtering || pattern != "" {
+		rows = append(rows, p.viewFilter())
+		innerHeight--
+	}
+	visible := p.visibleHosts()
 
-	if len(p.hosts) == 0 {
+	switch {
+	case len(p.hosts) == 0:
 		rows = append(rows, tui.MutedStyle().Render(i18n.T("tui.setup_panel.no_hosts")))
-	} else {
+	case len(visible) == 0:
+		rows = append(rows, tui.MutedStyle().Render(i18n.T("tui.setup_panel.no_matches")))
+	default:
 		maxRows := innerHeight
 		if maxRows < 1 {
 			maxRows = 1
 		}
 
+		lines, headers := p.hostLines(visible)
 		offset := 0
-		if p.hostCursor >= maxRows {
-			offset = p.hostCursor - maxRows + 1
+		if cursor := slices.Index(lines, p.hostCursor); cursor >= maxRows {
+			offset = cursor - maxRows + 1
 		}
 
 		end := offset + maxRows
-		if end > len(p.hosts) {
-			end = len(p.hosts)
+		if end > len(lines) {
+			end = len(lines)
 		}
 
-		for i := offset; i < end; i++ {
+		for n := offset; n < end; n++ {
+			i := lines[n]
+			if i < 0 {
+				rows = append(rows, tui.HeaderStyle().Render(headers[n]))
+				continue
+			}
+			nameMatch, addrMatch, _ := molecules.MatchHost(p.hosts[i], pattern)
 			row := molecules.HostRow{
-				Host:     p.hosts[i],
-				Selected: i == p.hostCursor,
-				Width:    innerWidth,
+				Host:      p.hosts[i],
+				Selected:  i == p.hostCursor,
+				Width:     innerWidth,
+				NameMatch: nameMatch,
+				AddrMatch: addrMatch,
 			}
 			var prefix string
 			if i == p.hostCursor {
@@ -91,6 +128,9 @@ func (p Panel) viewHostList(innerWidth, innerHeight int) []string {
 }
 
 func (p Panel) wizardTitleText() string {
+	if p.editing != nil {
+		return i18n.T("tui.setup_panel.edit_title") + " > " + p.ruleName
+	}
 	title := i18n.T("tui.setup_panel.wizard_title") + " > " + p.selectedHost
 	if p.step > StepSelectType {
 		title += " > " + p.selectedType.String()
@@ -135,14 +175,25 @@ func (p Panel) viewConfirm() []string {
 	} else {
 		rows = append(rows, tui.TextStyle().Render(fmt.Sprintf(":%s %s %s:%s",
 			p.localPort,
-			tui.MutedStyle().Render("→"),
+			tui.MutedStyle().Render(theme.Symbols().Arrow),
 			p.remoteHost,
 			p.remotePort,
 		)))
 	}
 
-	rows = append(rows, tui.MutedStyle().Render("Name: ")+tui.TextStyle().Render(p.ruleName))
+	name := tui.TextStyle().Render(p.ruleName)
+	if p.ruleName == "" {
+		name = tui.MutedStyle().Render(i18n.T("tui.setup_panel.auto_name", map[string]any{"Name": p.nameInput.Placeholder}))
+	}
+	rows = append(rows, tui.MutedStyle().Render("Name: ")+name)
+	if warning := p.conflictWarning(); warning != "" {
+		rows = append(rows, tui.WarningStyle().Render(warning))
+	}
 	rows = append(rows, "")
-	rows = append(rows, tui.MutedStyle().Render(i18n.T("tui.setup_panel.enter_create")))
+	hint := "tui.setup_panel.enter_create"
+	if p.editing != nil {
+		hint = "tui.setup_panel.enter_update"
+	}
+	rows = append(rows, tui.MutedStyle().Render(i18n.T(hint)))
 	return rows
 }
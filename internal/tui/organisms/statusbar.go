@@ -2,10 +2,15 @@ package organisms
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/ousiassllc/moleport/internal/format"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/atoms"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
 // StatusBarStats はステータスバーに表示する統計情報。
@@ -14,14 +19,26 @@ type StatusBarStats struct {
 	ConnectedHosts int
 	TotalForwards  int
 	ActiveForwards int
+	// ReconnectingHosts は再接続中のホスト数。
+	ReconnectingHosts int
+	// MaxLatency は接続中ホストの keepalive の往復時間の最大値。未計測の場合は 0。
+	MaxLatency time.Duration
+	// UploadRate・DownloadRate は全セッションの直近の送信・受信レートの合計（バイト/秒）。
+	UploadRate   float64
+	DownloadRate float64
 }
 
 // StatusBar はアプリケーション下部に表示するステータスバー。
+// 左端にデーモンとの接続状態と操作対象のプロファイル、続けてホスト・フォワードの集計と合計のスループットを表示する。
 type StatusBar struct {
 	stats       StatusBarStats
 	focusedPane tui.FocusPane
 	width       int
 	warning     string
+	// remote はリモートのデーモンに接続しているか、restarting はデーモンを再起動中か。
+	remote     bool
+	restarting bool
+	profile    string
 }
 
 // NewStatusBar は新しい StatusBar を生成する。
@@ -44,6 +61,21 @@ func (s *StatusBar) SetWarning(text string) {
 	s.warning = text
 }
 
+// SetRemote はリモートのデーモンに接続しているかを設定する。
+func (s *StatusBar) SetRemote(remote bool) {
+	s.remote = remote
+}
+
+// SetRestarting はデーモンを再起動中かを設定する。再起動中はデーモンから切り離された状態として表示する。
+func (s *StatusBar) SetRestarting(restarting bool) {
+	s.restarting = restarting
+}
+
+// SetProfile は操作対象のプロファイル名を設定する。
+func (s *StatusBar) SetProfile(profile string) {
+	s.profile = profile
+}
+
 // SetWidth は表示幅を設定する。
 func (s *StatusBar) SetWidth(width int) {
 	s.width = width
@@ -51,14 +83,21 @@ func (s *StatusBar) SetWidth(width int) {
 
 // View はステータスバーを描画する。
 func (s StatusBar) View() string {
-	sep := tui.DividerStyle().Render(" │ ")
+	g := theme.Symbols()
+	sep := tui.DividerStyle().Render(" " + g.VLine + " ")
 
 	stats := fmt.Sprintf(
-		"%s %s  %s %s%s%s %s  %s %s",
+		"%s %s  %s %s",
 		tui.ActiveStyle().Render(fmt.Sprintf("%d", s.stats.TotalHosts)),
 		i18n.T("tui.statusbar.hosts"),
 		tui.ActiveStyle().Render(fmt.Sprintf("%d", s.stats.ConnectedHosts)),
 		i18n.T("tui.statusbar.connected"),
+	)
+	if s.stats.ReconnectingHosts > 0 {
+		stats += "  " + tui.WarningStyle().Render(fmt.Sprintf("%d", s.stats.ReconnectingHosts)) + " " + i18n.T("tui.statusbar.reconnecting")
+	}
+	stats += fmt.Sprintf(
+		"%s%s %s  %s %s",
 		sep,
 		tui.ActiveStyle().Render(fmt.Sprintf("%d", s.stats.TotalForwards)),
 		i18n.T("tui.statusbar.forwards"),
@@ -66,6 +105,13 @@ func (s StatusBar) View() string {
 		i18n.T("tui.statusbar.active"),
 	)
 
+	stats += sep + tui.DividerStyle().Render(g.Up) + tui.TextStyle().Render(format.Bytes(int64(s.stats.UploadRate))+"/s") +
+		" " + tui.DividerStyle().Render(g.Down) + tui.TextStyle().Render(format.Bytes(int64(s.stats.DownloadRate))+"/s")
+
+	if latency := atoms.RenderLatency(s.stats.MaxLatency); latency != "" {
+		stats += sep + i18n.T("tui.statusbar.max_latency") + " " + latency
+	}
+
 	// ペインに応じたキーヒント
 	var contextHints string
 	switch s.focusedPane {
@@ -101,7 +147,7 @@ func (s StatusBar) View() string {
 		warningText = sep + tui.WarningStyle().Render(s.warning)
 	}
 
-	left := tui.MutedStyle().Render(" ") + stats + warningText
+	left := tui.MutedStyle().Render(" ") + s.renderDaemon() + sep + stats + warningText
 	right := hints
 
 	if s.width <= 0 {
@@ -110,9 +156,28 @@ func (s StatusBar) View() string {
 
 	gap := s.width - lipgloss.Width(left) - lipgloss.Width(right)
 	if gap < 3 {
-		return left
+		// 狭い端末ではキーヒントを省き、収まらない集計は末尾を切り詰める
+		return ansi.Truncate(left, s.width, g.Ellipsis)
 	}
 
 	padding := lipgloss.NewStyle().Width(gap).Render("")
 	return left + padding + right
 }
+
+// renderDaemon はデーモンとの接続状態と操作対象のプロファイルを描画する。
+func (s StatusBar) renderDaemon() string {
+	g := theme.Symbols()
+	var daemon string
+	switch {
+	case s.restarting:
+		daemon = tui.WarningStyle().Render(g.Pending + " " + i18n.T("tui.statusbar.daemon_restarting"))
+	case s.remote:
+		daemon = tui.ActiveStyle().Render(g.Active + " " + i18n.T("tui.statusbar.daemon_remote"))
+	default:
+		daemon = tui.ActiveStyle().Render(g.Active + " " + i18n.T("tui.statusbar.daemon"))
+	}
+	if s.profile == "" {
+		return daemon
+	}
+	return daemon + " " + tui.MutedStyle().Render(i18n.T("tui.statusbar.profile", map[string]any{"Profile": s.profile}))
+}
@@ -3,7 +3,9 @@ package organisms
 import (
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/ousiassllc/moleport/internal/tui"
 )
 
@@ -100,3 +102,43 @@ func TestStatusBar_View_Warning(t *testing.T) {
 		t.Error("View() should not contain cleared warning")
 	}
 }
+
+func TestStatusBar_View_MaxLatency(t *testing.T) {
+	sb := NewStatusBar()
+	sb.SetWidth(200)
+	if strings.Contains(sb.View(), "ms") {
+		t.Error("View() should not show latency before the first measurement")
+	}
+	sb.SetStats(StatusBarStats{TotalHosts: 1, ConnectedHosts: 1, MaxLatency: 150 * time.Millisecond})
+	if !strings.Contains(sb.View(), "150ms") {
+		t.Error("View() should contain the max latency")
+	}
+}
+
+func TestStatusBar_TrafficDaemonAndProfile(t *testing.T) {
+	sb := NewStatusBar()
+	sb.SetProfile("work")
+	sb.SetStats(StatusBarStats{TotalHosts: 3, ConnectedHosts: 1, ReconnectingHosts: 2, UploadRate: 2048, DownloadRate: 3 * 1024 * 1024})
+	sb.SetWidth(200)
+
+	view := sb.View()
+	for _, want := range []string{"● daemon", "profile: work", "2 reconnecting", "↑2.0KB/s", "↓3.0MB/s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q: %s", want, view)
+		}
+	}
+
+	sb.SetRemote(true)
+	if !strings.Contains(sb.View(), "remote daemon") {
+		t.Errorf("remote daemon should be indicated: %s", sb.View())
+	}
+	sb.SetRestarting(true)
+	if !strings.Contains(sb.View(), "◌ restarting daemon") {
+		t.Errorf("restart should be indicated: %s", sb.View())
+	}
+
+	sb.SetWidth(40)
+	if got := lipgloss.Width(sb.View()); got > 40 {
+		t.Errorf("narrow view width = %d, want <= 40", got)
+	}
+}
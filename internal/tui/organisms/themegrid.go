@@ -143,7 +143,7 @@ func (g ThemeGrid) viewColumn(title string, presets []theme.Preset, baseIdx, wid
 }
 
 func (g ThemeGrid) viewPresetRow(preset theme.Preset, selected bool) string {
-	swatch := lipgloss.NewStyle().Foreground(preset.Palette.Accent).Render("●")
+	swatch := lipgloss.NewStyle().Foreground(preset.Palette.Accent).Render(theme.Symbols().Active)
 
 	if selected {
 		label := lipgloss.NewStyle().Bold(true).Foreground(preset.Palette.Accent).Render(preset.Label)
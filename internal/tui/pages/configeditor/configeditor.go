@@ -0,0 +1,161 @@
+package configeditor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	tui "github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/organisms/configtree"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// maxPreviewLines は未適用の変更のプレビューに表示する最大件数。
+const maxPreviewLines = 5
+
+// Page は設定エディタページ。config.schema と config.get から設定項目のツリーを表示し、
+// 変更をまとめて config.update で適用する。
+type Page struct {
+	tree     configtree.Tree
+	loaded   bool
+	status   string
+	applying bool
+	// confirmDiscard は未適用の変更がある状態で Esc が押され、再度の Esc を待っていることを示す。
+	confirmDiscard bool
+
+	keys   tui.KeyMap
+	apply  key.Binding
+	revert key.Binding
+	width  int
+	height int
+}
+
+// New は新しい Page を生成する。項目は ConfigEditorLoadedMsg を受け取るまで表示しない。
+func New() Page {
+	return Page{
+		keys:   tui.DefaultKeyMap(),
+		apply:  key.NewBinding(key.WithKeys("a")),
+		revert: key.NewBinding(key.WithKeys("r")),
+	}
+}
+
+// Init は Bubble Tea の Init メソッド。
+func (p Page) Init() tea.Cmd { return nil }
+
+// Update はメッセージを処理する。
+func (p Page) Update(msg tea.Msg) (Page, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tui.ConfigEditorLoadedMsg:
+		if msg.Err != nil {
+			p.status = i18n.T("tui.config.load_error", map[string]any{"Error": msg.Err})
+			return p, nil
+		}
+		p.tree = configtree.New(msg.Fields, msg.Values)
+		p.tree.SetSize(p.width, p.treeHeight())
+		p.loaded = true
+		return p, nil
+
+	case tui.ConfigAppliedMsg:
+		p.applying = false
+		if msg.Err != nil {
+			p.status = i18n.T("tui.config.apply_error", map[string]any{"Error": msg.Err})
+			return p, nil
+		}
+		p.tree.Commit()
+		p.status = i18n.T("tui.config.applied")
+		return p, nil
+
+	case tea.KeyMsg:
+		return p.handleKey(msg)
+	}
+	return p, nil
+}
+
+// handleKey はキー入力を処理する。インライン編集中はツリーにすべて転送する。
+func (p Page) handleKey(msg tea.KeyMsg) (Page, tea.Cmd) {
+	if p.loaded && p.tree.IsEditing() {
+		var cmd tea.Cmd
+		p.tree, cmd = p.tree.Update(msg)
+		return p, cmd
+	}
+	if key.Matches(msg, p.keys.Escape) {
+		if p.tree.HasPending() && !p.confirmDiscard {
+			p.confirmDiscard = true
+			p.status = i18n.T("tui.config.discard_confirm")
+			return p, nil
+		}
+		return p, func() tea.Msg { return tui.ConfigEditorClosedMsg{} }
+	}
+	p.confirmDiscard = false
+	p.status = ""
+	if !p.loaded {
+		return p, nil
+	}
+
+	switch {
+	case key.Matches(msg, p.apply):
+		if !p.tree.HasPending() || p.applying {
+			return p, nil
+		}
+		p.applying = true
+		changes := p.tree.Changes()
+		return p, func() tea.Msg { return tui.ConfigApplyRequestMsg{Changes: changes} }
+	case key.Matches(msg, p.revert):
+		p.tree.Revert()
+		return p, nil
+	}
+	var cmd tea.Cmd
+	p.tree, cmd = p.tree.Update(msg)
+	return p, cmd
+}
+
+// View は設定エディタページを描画する。
+func (p Page) View() string {
+	header := tui.HeaderStyle().Render("  " + i18n.T("tui.config.header"))
+	body := tui.MutedStyle().Render("  " + i18n.T("tui.config.loading"))
+	if p.loaded {
+		body = p.tree.View()
+	}
+	sections := []string{header, "", body, "", p.renderPreview()}
+	if p.status != "" {
+		sections = append(sections, "", tui.WarningStyle().Render("  "+p.status))
+	}
+	help := tui.MutedStyle().Render("  " + i18n.T("tui.config.help"))
+	sections = append(sections, "", help)
+	return lipgloss.JoinVertical(lipgloss.Left, sections...)
+}
+
+// renderPreview は未適用の変更の一覧を描画する。
+func (p Page) renderPreview() string {
+	title := tui.TitleStyle().Render("  " + i18n.T("tui.config.pending_title"))
+	if !p.loaded || !p.tree.HasPending() {
+		return title + "\n" + tui.MutedStyle().Render("    "+i18n.T("tui.config.no_pending"))
+	}
+	diffs := p.tree.Diffs()
+	lines := []string{title}
+	for i, d := range diffs {
+		if i == maxPreviewLines {
+			lines = append(lines, tui.MutedStyle().Render(fmt.Sprintf("    +%d", len(diffs)-maxPreviewLines)))
+			break
+		}
+		lines = append(lines, fmt.Sprintf("    %s: %s %s %s", d.Path, configtree.DisplayValue(d.Old), theme.Symbols().Arrow, tui.WarningStyle().Render(configtree.DisplayValue(d.New))))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// treeHeight はツリーに割り当てる行数を返す。ヘッダー・プレビュー・状態表示・ヘルプと余白の分を除く。
+func (p Page) treeHeight() int {
+	return max(p.height-maxPreviewLines-10, 3)
+}
+
+// SetSize はページのサイズを設定する。
+func (p *Page) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	if p.loaded {
+		p.tree.SetSize(width, p.treeHeight())
+	}
+}
@@ -0,0 +1,83 @@
+package configeditor_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/pages/configeditor"
+)
+
+func loadedConfigPage() configeditor.Page {
+	p := configeditor.New()
+	p.SetSize(100, 40)
+	p, _ = p.Update(tui.ConfigEditorLoadedMsg{
+		Fields: []protocol.ConfigFieldSchema{{Path: "session.auto_restore", Type: "bool", Since: "1.0.0"}},
+		Values: []tui.ConfigValue{{Keys: []string{"session", "auto_restore"}, Value: "true"}},
+	})
+	return p
+}
+
+func sendKeys(p configeditor.Page, msgs ...tea.KeyMsg) (configeditor.Page, tea.Cmd) {
+	var cmd tea.Cmd
+	for _, msg := range msgs {
+		p, cmd = p.Update(msg)
+	}
+	return p, cmd
+}
+
+func TestPage_ApplyEmitsChanges(t *testing.T) {
+	p, cmd := sendKeys(loadedConfigPage(),
+		tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter},
+		tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'a'}})
+	if cmd == nil {
+		t.Fatal("a with pending changes should produce a command")
+	}
+	req, ok := cmd().(tui.ConfigApplyRequestMsg)
+	if !ok || len(req.Changes) != 1 || req.Changes[0].Value != false {
+		t.Fatalf("apply request = %+v", req)
+	}
+	if !strings.Contains(p.View(), "session.auto_restore") {
+		t.Error("pending change should be shown in the preview")
+	}
+
+	p, _ = p.Update(tui.ConfigAppliedMsg{})
+	if !strings.Contains(p.View(), "Settings applied") {
+		t.Errorf("applied status should be shown, view:\n%s", p.View())
+	}
+}
+
+func TestPage_ApplyErrorKeepsPending(t *testing.T) {
+	p, _ := sendKeys(loadedConfigPage(), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+	p, _ = p.Update(tui.ConfigAppliedMsg{Err: errors.New("invalid value")})
+	if view := p.View(); !strings.Contains(view, "invalid value") || !strings.Contains(view, "session.auto_restore:") {
+		t.Errorf("apply error should keep pending changes, view:\n%s", view)
+	}
+}
+
+func TestPage_EscWithPendingNeedsConfirmation(t *testing.T) {
+	p, _ := sendKeys(loadedConfigPage(), tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyEnter})
+
+	p, cmd := p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd != nil {
+		t.Fatal("first Esc with pending changes should ask for confirmation")
+	}
+	_, cmd = p.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("second Esc should close the page")
+	}
+	if _, ok := cmd().(tui.ConfigEditorClosedMsg); !ok {
+		t.Error("expected ConfigEditorClosedMsg")
+	}
+}
+
+func TestPage_LoadError(t *testing.T) {
+	p := configeditor.New()
+	p, _ = p.Update(tui.ConfigEditorLoadedMsg{Err: errors.New("boom")})
+	if !strings.Contains(p.View(), "boom") {
+		t.Error("load error should be shown")
+	}
+}
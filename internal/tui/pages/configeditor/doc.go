@@ -0,0 +1,3 @@
+// Package configeditor は config.schema と config.get から設定項目のツリーを表示し、
+// 変更をまとめて config.update で適用する設定エディタページを提供する。
+package configeditor
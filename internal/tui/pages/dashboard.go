@@ -4,12 +4,14 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/i18n"
 	"github.com/ousiassllc/moleport/internal/tui"
 	"github.com/ousiassllc/moleport/internal/tui/molecules"
 	"github.com/ousiassllc/moleport/internal/tui/organisms"
+	"github.com/ousiassllc/moleport/internal/tui/organisms/logviewer"
 	"github.com/ousiassllc/moleport/internal/tui/organisms/setuppanel"
+	"github.com/ousiassllc/moleport/internal/tui/pages/panes"
+	"github.com/ousiassllc/moleport/internal/tui/pages/sessiondetail"
 )
 
 // DashboardPage は3パネル + ステータスバーで構成されるレイアウト。
@@ -23,11 +25,24 @@ type DashboardPage struct {
 	statusBar     organisms.StatusBar
 	passwordInput molecules.PasswordInput
 	keys          tui.KeyMap
+	// confirm は表示中の確認ダイアログ（削除・一括操作・グループの停止・終了）。nil の場合は表示しない。
+	confirm *confirmation
+	// detail は表示中のセッション詳細ページ。nil の場合は表示しない。errors・traffic はルールごとの直近のエラーとレート。
+	detail  *SessionDetailPage
+	errors  sessiondetail.ErrorHistory
+	traffic sessiondetail.TrafficHistory
+	// logs は表示中のデーモンのログビューア。nil の場合は表示しない。logSubscription はログの購読 ID。
+	logs            *logviewer.Viewer
+	logSubscription string
+
+	// layout はペイン配分（フォワードパネルの割合・1 ペイン表示・ログパネルの表示）。
+	layout panes.Layout
 
 	focusedPane tui.FocusPane
 	width       int
 	height      int
 	version     string
+	readOnly    bool
 }
 
 // NewDashboardPage は新しい DashboardPage を生成する。
@@ -39,6 +54,7 @@ func NewDashboardPage(version string) DashboardPage {
 		statusBar:     organisms.NewStatusBar(),
 		passwordInput: molecules.NewPasswordInput(),
 		keys:          tui.DefaultKeyMap(),
+		layout:        panes.New(),
 		focusedPane:   tui.PaneSetup,
 		version:       version,
 	}
@@ -63,10 +79,24 @@ func (d DashboardPage) Update(msg tea.Msg) (DashboardPage, tea.Cmd) {
 			if cmd != nil {
 				cmds = append(cmds, cmd)
 			}
+			// 入力を終えたらログパネルを隠している場合に入力欄の分の高さを戻す
+			if !d.passwordInput.Active() {
+				d.updateSizes()
+			}
 			return d, tea.Batch(cmds...)
 		}
 	}
 
+	if nd, cmd, handled := d.updateConfirm(msg); handled {
+		return nd, cmd
+	}
+	if nd, cmd, handled := d.updateDetail(msg); handled {
+		return nd, cmd
+	}
+	if nd, cmd, handled := d.updateLogs(msg); handled {
+		return nd, cmd
+	}
+
 	// PasswordSubmitMsg はパスワード入力完了の通知
 	if submitMsg, ok := msg.(molecules.PasswordSubmitMsg); ok {
 		return d, func() tea.Msg {
@@ -91,12 +121,21 @@ func (d DashboardPage) Update(msg tea.Msg) (DashboardPage, tea.Cmd) {
 			return d, nil
 		}
 
-		// / でセットアップパネルにフォーカス（テキスト入力中でない場合）
+		// / でセットアップパネルにフォーカスし、ホスト一覧のフィルターを開始する（テキスト入力中でない場合）
 		if key.Matches(msg, d.keys.Search) && !d.IsInputActive() {
 			d.setFocus(tui.PaneSetup)
+			return d, d.setup.StartFilter()
+		}
+
+		if !d.IsInputActive() && d.layout.Update(msg, d.keys, d.focusedPane) {
+			d.updateSizes()
 			return d, nil
 		}
 
+		if d.readOnly && d.isMutatingKey(msg) {
+			return d, readOnlyRejected()
+		}
+
 		// フォーカス中のパネルにキーを送る
 		switch d.focusedPane {
 		case tui.PaneForwards:
@@ -119,6 +158,11 @@ func (d DashboardPage) Update(msg tea.Msg) (DashboardPage, tea.Cmd) {
 		d.handleSSHEvent(msg.Event)
 	case tui.LogOutputMsg:
 		d.log.AppendOutput(msg.Text, msg.Level)
+	case tui.OnboardingSavedMsg:
+		d.handleOnboardingSaved(msg)
+	case tui.ForwardEditRequestMsg:
+		d.setFocus(tui.PaneSetup)
+		return d, d.setup.StartEdit(msg.Rule)
 	}
 
 	// SetupPanel にメッセージを転送（テキスト入力の blink 等）
@@ -144,6 +188,9 @@ func (d DashboardPage) Update(msg tea.Msg) (DashboardPage, tea.Cmd) {
 func (d DashboardPage) renderHeader() string {
 	appName := tui.HeaderStyle().Render("  MolePort")
 	version := tui.MutedStyle().Render(d.version)
+	if d.readOnly {
+		version = tui.WarningStyle().Render(i18n.T("tui.statusbar.read_only")) + "  " + version
+	}
 
 	gap := d.width - lipgloss.Width(appName) - lipgloss.Width(version) - 1
 	if gap < 1 {
@@ -159,90 +206,33 @@ func (d DashboardPage) View() string {
 	if d.width == 0 || d.height == 0 {
 		return "Loading..."
 	}
-
-	header := d.renderHeader()
-	forwardView := d.forward.View()
-	setupView := d.setup.View()
-
-	// パスワード入力がアクティブな場合はログパネルの代わりに表示
-	var logView string
-	if d.passwordInput.Active() {
-		logView = d.passwordInput.View()
-	} else {
-		logView = d.log.View()
+	if d.confirm != nil {
+		return d.renderConfirm()
 	}
-
-	statusView := d.statusBar.View()
-
-	return lipgloss.JoinVertical(lipgloss.Left,
-		header,
-		forwardView,
-		setupView,
-		logView,
-		statusView,
-	)
-}
-
-// --- パネルへのアクセサ ---
-
-// SetHosts はホスト一覧を設定する。
-func (d *DashboardPage) SetHosts(hosts []core.SSHHost) {
-	d.setup.SetHosts(hosts)
-	d.updateStats()
-}
-
-// SetForwardSessions はフォワードセッション一覧を設定する。
-func (d *DashboardPage) SetForwardSessions(sessions []core.ForwardSession) {
-	d.forward.SetSessions(sessions)
-	d.updateStats()
-}
-
-// UpdateHostState はホストの接続状態を更新する。
-func (d *DashboardPage) UpdateHostState(hostName string, state core.ConnectionState) {
-	d.setup.UpdateHostState(hostName, state)
-	d.updateStats()
-}
-
-// AppendLog はログ出力を追加する。
-func (d *DashboardPage) AppendLog(text string, level tui.LogLevel) {
-	d.log.AppendOutput(text, level)
-}
-
-// LogLineCount はログ出力の行数を返す。
-func (d DashboardPage) LogLineCount() int {
-	return d.log.OutputLen()
-}
-
-// FocusedPane は現在のフォーカスペインを返す。
-func (d DashboardPage) FocusedPane() tui.FocusPane {
-	return d.focusedPane
-}
-
-// IsInputActive はテキスト入力中かどうかを返す。
-func (d DashboardPage) IsInputActive() bool {
-	if d.passwordInput.Active() {
-		return true
+	if d.detail != nil {
+		return d.detail.View()
+	}
+	if d.logs != nil && !d.passwordInput.Active() {
+		return d.logs.View()
 	}
-	return d.focusedPane == tui.PaneSetup && d.setup.IsInputActive()
-}
 
-// ShowPasswordInput はパスワード入力を表示する。
-func (d *DashboardPage) ShowPasswordInput(prompt string) tea.Cmd {
-	return d.passwordInput.Show(prompt)
-}
+	rows := []string{d.renderHeader()}
+	switch {
+	case !d.layout.SinglePane(d.height):
+		rows = append(rows, d.forward.View(), d.setup.View())
+	case d.focusedPane == tui.PaneForwards:
+		rows = append(rows, panes.RenderTabs(d.focusedPane), d.forward.View())
+	default:
+		rows = append(rows, panes.RenderTabs(d.focusedPane), d.setup.View())
+	}
 
-// SetVersionWarning はバージョン不一致の警告表示を切り替える。
-func (d *DashboardPage) SetVersionWarning(show bool) {
-	if show {
-		d.statusBar.SetWarning(i18n.T("tui.version.mismatch_warning"))
-	} else {
-		d.statusBar.SetWarning("")
+	// パスワード入力がアクティブな場合はログパネルの代わりに表示
+	if d.passwordInput.Active() {
+		rows = append(rows, d.passwordInput.View())
+	} else if d.layout.LogVisible(d.height) {
+		rows = append(rows, d.log.View())
 	}
-}
 
-// SetSize はサイズを設定する。
-func (d *DashboardPage) SetSize(width, height int) {
-	d.width = width
-	d.height = height
-	d.updateSizes()
+	rows = append(rows, d.statusBar.View())
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
@@ -0,0 +1,113 @@
+package pages
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// --- パネルへのアクセサ ---
+
+// SetHosts はホスト一覧を設定する。
+func (d *DashboardPage) SetHosts(hosts []core.SSHHost) {
+	d.setup.SetHosts(hosts)
+	d.updateStats()
+}
+
+// SetForwardSessions はフォワードセッション一覧を設定する。
+func (d *DashboardPage) SetForwardSessions(sessions []core.ForwardSession) {
+	d.forward.SetSessions(sessions)
+	rules := make([]core.ForwardRule, len(sessions))
+	for i, session := range sessions {
+		rules[i] = session.Rule
+	}
+	d.setup.SetRules(rules)
+	d.refreshDetail()
+	d.updateStats()
+}
+
+// SetForwardGroups はフォワードグループ一覧を設定する。
+func (d *DashboardPage) SetForwardGroups(groups []core.ForwardGroup) {
+	d.forward.SetGroups(groups)
+}
+
+// UpdateHostState はホストの接続状態を更新する。
+func (d *DashboardPage) UpdateHostState(hostName string, state core.ConnectionState) {
+	d.setup.UpdateHostState(hostName, state)
+	d.updateStats()
+}
+
+// UpdateHostHealth はホストの疎通確認の結果を更新する。
+func (d *DashboardPage) UpdateHostHealth(hostName string, health core.HostHealth) {
+	d.setup.UpdateHostHealth(hostName, health)
+}
+
+// SetHostLatency は各ホストの keepalive の往復時間を更新する。
+func (d *DashboardPage) SetHostLatency(latency map[string]time.Duration) {
+	d.setup.SetHostLatency(latency)
+	d.updateStats()
+}
+
+// AppendLog はログ出力を追加する。
+func (d *DashboardPage) AppendLog(text string, level tui.LogLevel) {
+	d.log.AppendOutput(text, level)
+}
+
+// LogLineCount はログ出力の行数を返す。
+func (d DashboardPage) LogLineCount() int {
+	return d.log.OutputLen()
+}
+
+// FocusedPane は現在のフォーカスペインを返す。
+func (d DashboardPage) FocusedPane() tui.FocusPane {
+	return d.focusedPane
+}
+
+// IsInputActive はテキスト入力中かどうかを返す。
+func (d DashboardPage) IsInputActive() bool {
+	if d.passwordInput.Active() || d.confirm != nil || d.detail != nil || d.logs != nil {
+		return true
+	}
+	return d.focusedPane == tui.PaneSetup && d.setup.IsInputActive()
+}
+
+// ShowPasswordInput はパスワード入力を表示する。
+func (d *DashboardPage) ShowPasswordInput(prompt string) tea.Cmd {
+	cmd := d.passwordInput.Show(prompt)
+	d.updateSizes()
+	return cmd
+}
+
+// ShowPlainInput は入力文字をマスクしない確認入力を表示する。
+func (d *DashboardPage) ShowPlainInput(prompt string) tea.Cmd {
+	cmd := d.passwordInput.ShowPlain(prompt)
+	d.updateSizes()
+	return cmd
+}
+
+// SetDaemonRemote はステータスバーにリモートのデーモンに接続していることを表示するかを設定する。
+func (d *DashboardPage) SetDaemonRemote(remote bool) {
+	d.statusBar.SetRemote(remote)
+}
+
+// SetDaemonRestarting はステータスバーにデーモンの再起動中を表示するかを設定する。
+func (d *DashboardPage) SetDaemonRestarting(restarting bool) {
+	d.statusBar.SetRestarting(restarting)
+}
+
+// SetProfile はステータスバーに表示する操作対象のプロファイル名を設定する。
+func (d *DashboardPage) SetProfile(profile string) {
+	d.statusBar.SetProfile(profile)
+}
+
+// SetVersionWarning はバージョン不一致の警告表示を切り替える。
+func (d *DashboardPage) SetVersionWarning(show bool) {
+	if show {
+		d.statusBar.SetWarning(i18n.T("tui.version.mismatch_warning"))
+	} else {
+		d.statusBar.SetWarning("")
+	}
+}
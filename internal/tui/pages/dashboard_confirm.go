@@ -0,0 +1,95 @@
+package pages
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+// bulkConfirmKeys は一括操作の種類ごとの確認メッセージの翻訳キー。
+var bulkConfirmKeys = map[tui.BulkAction]string{
+	tui.BulkStart:  "tui.forward.bulk_start_confirm",
+	tui.BulkStop:   "tui.forward.bulk_stop_confirm",
+	tui.BulkDelete: "tui.forward.bulk_delete_confirm",
+}
+
+// confirmation は表示中の確認ダイアログと、確定したときに発行するメッセージ。
+type confirmation struct {
+	dialog    molecules.ConfirmDialog
+	onConfirm tea.Msg
+}
+
+// askConfirm は message を尋ねる確認ダイアログを表示する。確定した場合は onConfirm を発行する。
+func (d *DashboardPage) askConfirm(message string, onConfirm tea.Msg) {
+	d.confirm = &confirmation{dialog: molecules.NewConfirmDialog(message), onConfirm: onConfirm}
+}
+
+// RequestQuit は稼働中のフォワードがある場合に終了の確認ダイアログを表示し、true を返す。
+// 確定した場合は QuitRequestMsg を発行する。稼働中のフォワードがない場合は何もせず false を返す。
+func (d *DashboardPage) RequestQuit() bool {
+	active := 0
+	for _, s := range d.forward.Sessions() {
+		if s.Status == core.Active {
+			active++
+		}
+	}
+	if active == 0 {
+		return false
+	}
+	d.askConfirm(i18n.T("tui.confirm.quit_active", map[string]any{"Count": active}), tui.QuitRequestMsg{})
+	return true
+}
+
+// updateConfirm は確認が必要な操作の要求と確認ダイアログに関するメッセージを処理する。処理した場合は handled=true を返す。
+// 確認ダイアログの表示中はキー入力をダイアログに送り、確定した場合は選択を解除して要求ごとの確定メッセージを発行する。
+func (d DashboardPage) updateConfirm(msg tea.Msg) (DashboardPage, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tui.ForwardBulkRequestMsg:
+		d.askConfirm(i18n.T(bulkConfirmKeys[msg.Action], map[string]any{
+			"Count": len(msg.RuleNames), "Names": strings.Join(msg.RuleNames, ", "),
+		}), tui.ForwardBulkConfirmedMsg(msg))
+		return d, nil, true
+	case tui.ForwardDeleteRequestMsg:
+		d.askConfirm(i18n.T("tui.confirm.delete_forward", map[string]any{"Name": msg.RuleName}),
+			tui.ForwardDeleteConfirmedMsg(msg))
+		return d, nil, true
+	case tui.ForwardGroupStopRequestMsg:
+		d.askConfirm(i18n.T("tui.confirm.stop_group", map[string]any{"Group": msg.Group, "Count": msg.Count}),
+			tui.ForwardGroupToggleMsg{Group: msg.Group, Start: false})
+		return d, nil, true
+	case tui.HostDeleteRequestMsg:
+		d.askConfirm(i18n.T("tui.confirm.delete_host", map[string]any{"Name": msg.Name}), tui.HostDeleteConfirmedMsg(msg))
+		return d, nil, true
+	case molecules.ConfirmResultMsg:
+		if d.confirm == nil {
+			return d, nil, false
+		}
+		onConfirm := d.confirm.onConfirm
+		d.confirm = nil
+		if !msg.Confirmed {
+			return d, nil, true
+		}
+		d.forward.ClearSelection()
+		return d, func() tea.Msg { return onConfirm }, true
+	case tea.KeyMsg:
+		if d.confirm == nil {
+			return d, nil, false
+		}
+		c := *d.confirm
+		var cmd tea.Cmd
+		c.dialog, cmd = c.dialog.Update(msg)
+		d.confirm = &c
+		return d, cmd, true
+	}
+	return d, nil, false
+}
+
+// renderConfirm は確認ダイアログを画面の中央に描画する。
+func (d DashboardPage) renderConfirm() string {
+	return lipgloss.Place(d.width, d.height, lipgloss.Center, lipgloss.Center, d.confirm.dialog.View())
+}
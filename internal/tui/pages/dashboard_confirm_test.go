@@ -0,0 +1,94 @@
+package pages
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+)
+
+func TestDashboardConfirm_Bulk(t *testing.T) {
+	d := newTestDashboard()
+	req := tui.ForwardBulkRequestMsg{Action: tui.BulkStop, RuleNames: []string{"api", "db"}}
+
+	d, _ = d.Update(req)
+	if !d.IsInputActive() {
+		t.Fatal("bulk confirm should capture input")
+	}
+	if view := d.View(); !strings.Contains(view, "Stop 2 forwards") {
+		t.Errorf("view should show the confirm dialog:\n%s", view)
+	}
+
+	d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if cmd == nil {
+		t.Fatal("y should confirm")
+	}
+	d, cmd = d.Update(cmd())
+	if d.IsInputActive() || cmd == nil {
+		t.Fatal("confirm should close the dialog and emit the bulk operation")
+	}
+	msg, ok := cmd().(tui.ForwardBulkConfirmedMsg)
+	if !ok || msg.Action != tui.BulkStop || !slices.Equal(msg.RuleNames, req.RuleNames) {
+		t.Errorf("msg = %+v, want confirmed stop of api and db", msg)
+	}
+
+	// キャンセルした場合は何もしない
+	d, _ = d.Update(req)
+	d, cmd = d.Update(molecules.ConfirmResultMsg{Confirmed: false})
+	if d.IsInputActive() || cmd != nil {
+		t.Error("cancel should close the dialog without a command")
+	}
+}
+
+func TestDashboardConfirm_Requests(t *testing.T) {
+	tests := []struct {
+		req  tea.Msg
+		text string
+		want tea.Msg
+	}{
+		{tui.ForwardDeleteRequestMsg{RuleName: "web"}, `Delete forwarding rule "web"?`, tui.ForwardDeleteConfirmedMsg{RuleName: "web"}},
+		{tui.HostDeleteRequestMsg{Name: "bastion"}, `Delete host "bastion"`, tui.HostDeleteConfirmedMsg{Name: "bastion"}},
+		{tui.ForwardGroupStopRequestMsg{Group: "data", Count: 2}, `Stop 2 forwards in group "data"?`, tui.ForwardGroupToggleMsg{Group: "data", Start: false}},
+	}
+	for _, tt := range tests {
+		d, _ := newTestDashboard().Update(tt.req)
+		if view := d.View(); !strings.Contains(view, tt.text) {
+			t.Errorf("%T: view should show %q:\n%s", tt.req, tt.text, view)
+		}
+		// 既定の選択は No のため Enter では何もしない
+		d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+		if d, cmd = d.Update(cmd()); cmd != nil || d.IsInputActive() {
+			t.Errorf("%T: Enter on the default No should cancel", tt.req)
+		}
+
+		d, _ = d.Update(tt.req)
+		d, cmd = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+		if _, cmd = d.Update(cmd()); cmd == nil || cmd() != tt.want {
+			t.Errorf("%T: confirm should emit %#v", tt.req, tt.want)
+		}
+	}
+}
+
+func TestDashboardRequestQuit(t *testing.T) {
+	d := newTestDashboard()
+	d.SetForwardSessions([]core.ForwardSession{{Rule: core.ForwardRule{Name: "web"}, Status: core.Stopped}})
+	if d.RequestQuit() {
+		t.Fatal("quit should not ask for confirmation without active forwards")
+	}
+
+	d.SetForwardSessions([]core.ForwardSession{{Rule: core.ForwardRule{Name: "web"}, Status: core.Active}})
+	if !d.RequestQuit() || !strings.Contains(d.View(), "Active forwards: 1") {
+		t.Fatalf("quit should ask for confirmation with active forwards:\n%s", d.View())
+	}
+	d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if _, cmd = d.Update(cmd()); cmd == nil {
+		t.Fatal("confirm should request quit")
+	}
+	if _, ok := cmd().(tui.QuitRequestMsg); !ok {
+		t.Errorf("got %#v, want QuitRequestMsg", cmd())
+	}
+}
@@ -0,0 +1,77 @@
+package pages
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/clipboard"
+	"github.com/ousiassllc/moleport/internal/tui/pages/sessiondetail"
+)
+
+// SessionDetailPage は稼働中のフォワードセッションの詳細ページ。
+type SessionDetailPage = sessiondetail.Page
+
+// updateDetail はセッション詳細ページに関するメッセージを処理する。処理した場合は handled=true を返す。
+// フォワードパネルからのコピーの要求もここで処理する。詳細ページの表示中はキー入力をページに送る。閲覧専用モードではページからの停止を拒否する。
+func (d DashboardPage) updateDetail(msg tea.Msg) (DashboardPage, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tui.SessionDetailRequestMsg:
+		if s, ok := d.findSession(msg.RuleName); ok {
+			page := sessiondetail.New(s)
+			page.SetHistory(d.errors.Recent(msg.RuleName), d.traffic.Rates(msg.RuleName))
+			page.SetSize(d.width, d.height)
+			d.detail = &page
+		}
+		return d, nil, true
+	case tui.SessionDetailClosedMsg:
+		d.detail = nil
+		return d, nil, true
+	case tui.ForwardCopyRequestMsg:
+		if msg.Command {
+			return d, clipboard.CopyCmd(sessiondetail.SSHCommand(msg.Session)), true
+		}
+		return d, clipboard.CopyCmd(sessiondetail.Endpoint(msg.Session)), true
+	case tea.KeyMsg:
+		if d.detail == nil {
+			return d, nil, false
+		}
+		if d.readOnly && key.Matches(msg, d.keys.Disconnect) {
+			return d, readOnlyRejected(), true
+		}
+		page, cmd := d.detail.Update(msg)
+		d.detail = &page
+		return d, cmd, true
+	}
+	return d, nil, false
+}
+
+// refreshDetail はセッション一覧の更新を直近のエラー・トラフィックの記録と表示中の詳細ページに反映する。
+// 表示中のルールが削除された場合は詳細ページを閉じる。
+func (d *DashboardPage) refreshDetail() {
+	now := time.Now()
+	d.errors.Observe(d.forward.Sessions(), now)
+	d.traffic.Observe(d.forward.Sessions(), now)
+	if d.detail == nil {
+		return
+	}
+	name := d.detail.RuleName()
+	if s, ok := d.findSession(name); ok {
+		d.detail.SetSession(s)
+		d.detail.SetHistory(d.errors.Recent(name), d.traffic.Rates(name))
+	} else {
+		d.detail = nil
+	}
+}
+
+// findSession はルール名に一致するフォワードセッションを返す。
+func (d DashboardPage) findSession(name string) (core.ForwardSession, bool) {
+	for _, s := range d.forward.Sessions() {
+		if s.Rule.Name == name {
+			return s, true
+		}
+	}
+	return core.ForwardSession{}, false
+}
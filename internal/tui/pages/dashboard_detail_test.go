@@ -0,0 +1,57 @@
+package pages
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func detailSession(lastError string) core.ForwardSession {
+	return core.ForwardSession{
+		Rule:   core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		Status: core.Active, ConnectedAt: time.Now().Add(-90 * time.Second), LastError: lastError,
+		ActiveConns: 1, Connections: []core.ConnInfo{{Peer: "127.0.0.1:50000", Since: time.Now()}},
+	}
+}
+
+func TestDashboardSessionDetail(t *testing.T) {
+	d := newTestDashboard()
+	d.SetForwardSessions([]core.ForwardSession{detailSession("")})
+	d.SetForwardSessions([]core.ForwardSession{detailSession("connection refused")})
+
+	d, _ = d.Update(tui.SessionDetailRequestMsg{RuleName: "web"})
+	if !d.IsInputActive() {
+		t.Fatal("session detail should capture input")
+	}
+	if view := d.View(); !strings.Contains(view, "connection refused") || !strings.Contains(view, "ssh -N -L") {
+		t.Errorf("view should show the session detail with recent errors:\n%s", view)
+	}
+
+	_, cmd := d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Fatal("d should stop the forward")
+	}
+	if msg, ok := cmd().(tui.ForwardToggleMsg); !ok || msg.RuleName != "web" {
+		t.Errorf("d: got %#v, want ForwardToggleMsg for web", cmd())
+	}
+
+	d, cmd = d.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Esc should close the detail page")
+	}
+	d, _ = d.Update(cmd())
+	if d.IsInputActive() {
+		t.Error("Esc should close the detail page")
+	}
+
+	// ルールが削除された場合は詳細ページを閉じる
+	d, _ = d.Update(tui.SessionDetailRequestMsg{RuleName: "web"})
+	d.SetForwardSessions(nil)
+	if d.IsInputActive() {
+		t.Error("detail page should close when the rule disappears")
+	}
+}
@@ -1,6 +1,8 @@
 package pages
 
 import (
+	"time"
+
 	"github.com/ousiassllc/moleport/internal/core"
 	"github.com/ousiassllc/moleport/internal/tui"
 	"github.com/ousiassllc/moleport/internal/tui/organisms"
@@ -20,37 +22,64 @@ func (d *DashboardPage) setFocus(pane tui.FocusPane) {
 	d.forward.SetFocused(pane == tui.PaneForwards)
 	d.setup.SetFocused(pane == tui.PaneSetup)
 	d.statusBar.SetFocusedPane(pane)
+	// 1 ペイン表示ではフォーカス中のペインに全ての高さを割り当てる
+	if d.layout.SinglePane(d.height) {
+		d.updateSizes()
+	}
+}
+
+// SetSize はサイズを設定する。
+func (d *DashboardPage) SetSize(width, height int) {
+	d.width = width
+	d.height = height
+	d.updateSizes()
+}
+
+// SetLayout は config.yaml の tui.layout の設定を適用する。forwardPercent が 0 の場合は既定値を使う。
+func (d *DashboardPage) SetLayout(forwardPercent int, hideLog bool) {
+	d.layout.Set(forwardPercent, hideLog)
+	d.updateSizes()
 }
 
 func (d *DashboardPage) updateSizes() {
 	if d.width <= 0 || d.height <= 0 {
 		return
 	}
+	if d.detail != nil {
+		d.detail.SetSize(d.width, d.height)
+	}
+	if d.logs != nil {
+		d.logs.SetSize(d.width, d.height)
+	}
 
 	const (
-		headerHeight         = 1
-		logHeight            = 5 // 3 content + 2 border
-		statusBarHeight      = 1
-		forwardHeightPercent = 40 // remaining の何%をフォワードパネルに割り当てるか
-		minForwardHeight     = 3
-		minSetupHeight       = 5
-		minTotalHeight       = 8
+		headerHeight     = 1
+		logHeight        = 5 // 3 content + 2 border
+		statusBarHeight  = 1
+		tabBarHeight     = 1
+		minForwardHeight = 3
+		minSetupHeight   = 5
+		minTotalHeight   = 8
 	)
 
-	fixedLines := headerHeight + logHeight + statusBarHeight
-	remaining := d.height - fixedLines
-	if remaining < minTotalHeight {
-		remaining = minTotalHeight
+	fixedLines := headerHeight + statusBarHeight
+	if d.layout.LogVisible(d.height) || d.passwordInput.Active() {
+		fixedLines += logHeight
 	}
-
-	forwardHeight := remaining * forwardHeightPercent / 100
-	if forwardHeight < minForwardHeight {
-		forwardHeight = minForwardHeight
+	if d.layout.SinglePane(d.height) {
+		fixedLines += tabBarHeight
 	}
+	remaining := max(d.height-fixedLines, minTotalHeight)
 
-	setupHeight := remaining - forwardHeight
-	if setupHeight < minSetupHeight {
-		setupHeight = minSetupHeight
+	var forwardHeight, setupHeight int
+	switch {
+	case !d.layout.SinglePane(d.height):
+		forwardHeight = max(remaining*d.layout.ForwardPercent/100, minForwardHeight)
+		setupHeight = max(remaining-forwardHeight, minSetupHeight)
+	case d.focusedPane == tui.PaneForwards:
+		forwardHeight = remaining
+	default:
+		setupHeight = remaining
 	}
 
 	d.forward.SetSize(d.width, forwardHeight)
@@ -79,22 +108,34 @@ func (d *DashboardPage) updateStats() {
 	hosts := d.setup.Hosts()
 	sessions := d.forward.Sessions()
 
-	var connected, activeForwards int
+	var connected, reconnecting, activeForwards int
+	var maxLatency time.Duration
+	var upload, download float64
 	for _, h := range hosts {
-		if h.State == core.Connected {
+		switch h.State {
+		case core.Connected:
 			connected++
+			maxLatency = max(maxLatency, h.Latency)
+		case core.Reconnecting:
+			reconnecting++
 		}
 	}
 	for _, s := range sessions {
 		if s.Status == core.Active {
 			activeForwards++
 		}
+		upload += s.SendRate
+		download += s.ReceiveRate
 	}
 
 	d.statusBar.SetStats(organisms.StatusBarStats{
-		TotalHosts:     len(hosts),
-		ConnectedHosts: connected,
-		TotalForwards:  len(sessions),
-		ActiveForwards: activeForwards,
+		TotalHosts:        len(hosts),
+		ConnectedHosts:    connected,
+		ReconnectingHosts: reconnecting,
+		TotalForwards:     len(sessions),
+		ActiveForwards:    activeForwards,
+		MaxLatency:        maxLatency,
+		UploadRate:        upload,
+		DownloadRate:      download,
 	})
 }
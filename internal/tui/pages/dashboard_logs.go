@@ -0,0 +1,73 @@
+package pages
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/organisms/logviewer"
+)
+
+// OpenLogViewer はデーモンのログビューアを開く。ログは LogsSubscribedMsg と AppendDaemonLog で受け取る。
+func (d *DashboardPage) OpenLogViewer() {
+	v := logviewer.New()
+	v.SetSize(d.width, d.height)
+	d.logs = &v
+}
+
+// LogViewerOpen はログビューアを表示中かを返す。
+func (d DashboardPage) LogViewerOpen() bool {
+	return d.logs != nil
+}
+
+// AppendDaemonLog は event.log 通知で届いたログをビューアに追加する。
+func (d *DashboardPage) AppendDaemonLog(rec protocol.LogRecord) {
+	if d.logs != nil {
+		d.logs.Append(rec)
+	}
+}
+
+// updateLogs はログの購読結果とログビューアのキー入力を処理する。処理した場合は handled=true を返す。
+// ビューアを閉じた場合や購読が完了する前に閉じていた場合は、不要になった購読の解除を LogsUnsubscribeMsg で要求する。
+func (d DashboardPage) updateLogs(msg tea.Msg) (DashboardPage, tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case tui.LogsSubscribedMsg:
+		switch {
+		case msg.Err != nil:
+			if d.logs != nil {
+				d.logs.SetStatus(i18n.T("tui.log_viewer.subscribe_error", map[string]any{"Error": msg.Err}))
+			}
+			return d, nil, true
+		case d.logs == nil:
+			return d, unsubscribeLogs(msg.SubscriptionID), true
+		}
+		stale := d.logSubscription
+		d.logSubscription = msg.SubscriptionID
+		d.logs.SetRecords(msg.Records)
+		if stale == msg.SubscriptionID {
+			stale = ""
+		}
+		return d, unsubscribeLogs(stale), true
+	case tui.LogViewerClosedMsg:
+		stale := d.logSubscription
+		d.logs = nil
+		d.logSubscription = ""
+		return d, unsubscribeLogs(stale), true
+	case tea.KeyMsg:
+		if d.logs == nil {
+			return d, nil, false
+		}
+		v, cmd := d.logs.Update(msg)
+		d.logs = &v
+		return d, cmd, true
+	}
+	return d, nil, false
+}
+
+// unsubscribeLogs はログの購読の解除を要求するコマンドを返す。購読 ID が空の場合は nil を返す。
+func unsubscribeLogs(subscriptionID string) tea.Cmd {
+	if subscriptionID == "" {
+		return nil
+	}
+	return func() tea.Msg { return tui.LogsUnsubscribeMsg{SubscriptionID: subscriptionID} }
+}
@@ -0,0 +1,74 @@
+package pages
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/ipc/protocol"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// unsubscribed は cmd が要求するログの購読の解除の ID を返す。要求しない場合は空文字列を返す。
+func unsubscribed(t *testing.T, cmd tea.Cmd) string {
+	t.Helper()
+	if cmd == nil {
+		return ""
+	}
+	msg, ok := cmd().(tui.LogsUnsubscribeMsg)
+	if !ok {
+		t.Fatalf("got %#v, want LogsUnsubscribeMsg", cmd())
+	}
+	return msg.SubscriptionID
+}
+
+func TestDashboardLogViewer(t *testing.T) {
+	d := newTestDashboard()
+	d.OpenLogViewer()
+	if !d.IsInputActive() {
+		t.Fatal("log viewer should capture input")
+	}
+
+	d, cmd := d.Update(tui.LogsSubscribedMsg{SubscriptionID: "logs-1", Records: []protocol.LogRecord{{Level: "INFO", Message: "daemon started"}}})
+	if id := unsubscribed(t, cmd); id != "" {
+		t.Errorf("first subscription should be kept, got unsubscribe %q", id)
+	}
+	d.AppendDaemonLog(protocol.LogRecord{Level: "ERROR", Message: "dial failed"})
+	if view := d.View(); !strings.Contains(view, "daemon started") || !strings.Contains(view, "dial failed") {
+		t.Errorf("view should show the subscribed logs:\n%s", view)
+	}
+
+	// 再購読した場合は以前の購読を解除する
+	d, cmd = d.Update(tui.LogsSubscribedMsg{SubscriptionID: "logs-2"})
+	if id := unsubscribed(t, cmd); id != "logs-1" {
+		t.Errorf("unsubscribe = %q, want logs-1", id)
+	}
+
+	d, cmd = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'L'}})
+	if cmd == nil {
+		t.Fatal("L should close the log viewer")
+	}
+	d, cmd = d.Update(cmd())
+	if d.LogViewerOpen() || d.IsInputActive() {
+		t.Error("log viewer should be closed")
+	}
+	if id := unsubscribed(t, cmd); id != "logs-2" {
+		t.Errorf("unsubscribe = %q, want logs-2", id)
+	}
+
+	// 購読の完了前に閉じた場合は届いた購読を解除する
+	_, cmd = d.Update(tui.LogsSubscribedMsg{SubscriptionID: "logs-3"})
+	if id := unsubscribed(t, cmd); id != "logs-3" {
+		t.Errorf("unsubscribe = %q, want logs-3", id)
+	}
+}
+
+func TestDashboardLogViewer_SubscribeError(t *testing.T) {
+	d := newTestDashboard()
+	d.OpenLogViewer()
+	d, _ = d.Update(tui.LogsSubscribedMsg{Err: errors.New("permission denied")})
+	if view := d.View(); !strings.Contains(view, "permission denied") {
+		t.Errorf("view should show the subscribe error:\n%s", view)
+	}
+}
@@ -0,0 +1,24 @@
+package pages
+
+import (
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// handleOnboardingSaved はセットアップウィザードの設定の保存結果をログに表示する。
+// ssh_config のパスを変更した場合はデーモンの再起動が必要なことを、最初のフォワードを追加する場合は
+// セットアップペインにフォーカスしてホストの選び方を案内する。
+func (d *DashboardPage) handleOnboardingSaved(msg tui.OnboardingSavedMsg) {
+	if msg.Err != nil {
+		d.AppendLog(i18n.T("tui.onboarding.save_error", map[string]any{"Error": msg.Err}), tui.LogError)
+	} else {
+		d.AppendLog(i18n.T("tui.onboarding.saved"), tui.LogSuccess)
+		if msg.SSHConfigChanged {
+			d.AppendLog(i18n.T("tui.onboarding.restart_required"), tui.LogInfo)
+		}
+	}
+	if msg.AddForward {
+		d.setFocus(tui.PaneSetup)
+		d.AppendLog(i18n.T("tui.onboarding.first_forward_hint"), tui.LogInfo)
+	}
+}
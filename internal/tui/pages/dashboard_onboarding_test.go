@@ -0,0 +1,27 @@
+package pages
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestDashboardOnboardingSaved(t *testing.T) {
+	d := newTestDashboard()
+	d.setFocus(tui.PaneForwards)
+	d, _ = d.Update(tui.OnboardingSavedMsg{SSHConfigChanged: true, AddForward: true})
+	// 保存完了・再起動の案内・最初のフォワードの案内
+	if got := d.LogLineCount(); got != 3 {
+		t.Errorf("LogLineCount() = %d, want 3", got)
+	}
+	if d.FocusedPane() != tui.PaneSetup {
+		t.Error("adding a first forward should focus the setup pane")
+	}
+
+	d = newTestDashboard()
+	d, _ = d.Update(tui.OnboardingSavedMsg{SSHConfigChanged: true, Err: errors.New("write failed")})
+	if got := d.LogLineCount(); got != 1 {
+		t.Errorf("on error LogLineCount() = %d, want 1", got)
+	}
+}
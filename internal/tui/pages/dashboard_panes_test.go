@@ -0,0 +1,63 @@
+package pages
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/tui/pages/panes"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestDashboardSetLayout(t *testing.T) {
+	d := newTestDashboard()
+	d.SetLayout(5, true)
+	if d.layout.ForwardPercent != panes.MinForwardPercent || !d.layout.HideLog {
+		t.Errorf("SetLayout(5, true) = %+v, want clamped percent and hidden log", d.layout)
+	}
+}
+
+func TestDashboardZoomAndLogToggle(t *testing.T) {
+	d := NewDashboardPage("0.1.0")
+	d.SetSize(80, 40)
+	if strings.Contains(d.View(), "[Tab]") || !d.layout.LogVisible(d.height) {
+		t.Fatal("a 40-row terminal should show the split layout with the log pane")
+	}
+
+	d, _ = d.Update(runeKey('z'))
+	view := d.View()
+	if !strings.Contains(view, "[Tab]") || strings.Contains(view, "Active Forwards") {
+		t.Errorf("z should show only the focused setup pane with tabs:\n%s", view)
+	}
+	if got := lipgloss.Height(view); got > 40 {
+		t.Errorf("zoomed view height = %d, want <= 40", got)
+	}
+
+	d, _ = d.Update(runeKey('z'))
+	d, _ = d.Update(runeKey('o'))
+	if d.layout.LogVisible(d.height) {
+		t.Error("o should hide the log pane")
+	}
+}
+
+func TestDashboardCompactLayout(t *testing.T) {
+	d := NewDashboardPage("0.1.0")
+	d.SetSize(80, 15)
+	view := d.View()
+	if !d.layout.SinglePane(d.height) || d.layout.LogVisible(d.height) || !strings.Contains(view, "[Tab]") {
+		t.Fatalf("a 15-row terminal should use the tabbed single-pane layout:\n%s", view)
+	}
+	if got := lipgloss.Height(view); got > 15 {
+		t.Errorf("compact view height = %d, want <= 15", got)
+	}
+
+	// Tab で表示するペインを切り替える
+	d, _ = d.Update(tea.KeyMsg{Type: tea.KeyTab})
+	if !strings.Contains(d.View(), "Active Forwards") {
+		t.Errorf("Tab should switch to the forwards pane:\n%s", d.View())
+	}
+}
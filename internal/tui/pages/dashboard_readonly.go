@@ -0,0 +1,43 @@
+package pages
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// SetReadOnly は閲覧専用モードを切り替える。
+// 有効な場合、フォワードの開始/停止/削除やウィザードの起動などの状態を変更するキー操作を無効にする。
+func (d *DashboardPage) SetReadOnly(readOnly bool) {
+	d.readOnly = readOnly
+}
+
+// ReadOnly は閲覧専用モードかどうかを返す。
+func (d DashboardPage) ReadOnly() bool {
+	return d.readOnly
+}
+
+// isMutatingKey は閲覧専用モードで無効にするキーかを判定する。
+// テキスト入力中のキーは対象外（閲覧専用モードではウィザードが開始されないため）。
+// 稼働中のセッションで詳細ページを開く Enter も対象外。
+func (d DashboardPage) isMutatingKey(msg tea.KeyMsg) bool {
+	if d.IsInputActive() {
+		return false
+	}
+	if _, ok := d.forward.DetailTarget(); ok && d.focusedPane == tui.PaneForwards && key.Matches(msg, d.keys.Enter) {
+		return false
+	}
+	return key.Matches(msg, d.keys.Enter) ||
+		key.Matches(msg, d.keys.Disconnect) ||
+		key.Matches(msg, d.keys.Delete) ||
+		key.Matches(msg, d.keys.Edit) ||
+		key.Matches(msg, d.keys.AddHost)
+}
+
+// readOnlyRejected は閲覧専用モードで操作を拒否したことをログに出力するコマンドを返す。
+func readOnlyRejected() tea.Cmd {
+	return func() tea.Msg {
+		return tui.LogOutputMsg{Text: i18n.T("tui.log.read_only"), Level: tui.LogError}
+	}
+}
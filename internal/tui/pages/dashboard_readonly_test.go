@@ -0,0 +1,71 @@
+package pages
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func TestDashboardReadOnly_BlocksMutatingKeys(t *testing.T) {
+	d := newTestDashboard()
+	d.SetHosts([]core.SSHHost{{Name: "test-host", State: core.Disconnected}})
+	d.SetForwardSessions([]core.ForwardSession{{ID: "s1", Rule: core.ForwardRule{Name: "web"}, Status: core.Stopped}})
+	d.SetReadOnly(true)
+
+	keys := []tea.KeyMsg{
+		{Type: tea.KeyEnter},
+		{Type: tea.KeyRunes, Runes: []rune{'d'}},
+		{Type: tea.KeyRunes, Runes: []rune{'x'}},
+	}
+	for _, pane := range []tui.FocusPane{tui.PaneSetup, tui.PaneForwards} {
+		d.setFocus(pane)
+		for _, k := range keys {
+			_, cmd := d.Update(k)
+			if cmd == nil {
+				t.Fatalf("pane %v key %q: expected rejection cmd", pane, k.String())
+			}
+			msg, ok := cmd().(tui.LogOutputMsg)
+			if !ok || msg.Level != tui.LogError {
+				t.Errorf("pane %v key %q: got %#v, want error LogOutputMsg", pane, k.String(), msg)
+			}
+		}
+	}
+	if d.IsInputActive() {
+		t.Error("wizard should not start in read-only mode")
+	}
+}
+
+func TestDashboardReadOnly_SessionDetail(t *testing.T) {
+	d := newTestDashboard()
+	d.SetForwardSessions([]core.ForwardSession{{ID: "s1", Rule: core.ForwardRule{Name: "web"}, Status: core.Active}})
+	d.SetReadOnly(true)
+	d.setFocus(tui.PaneForwards)
+
+	// 稼働中のセッションの詳細ページは閲覧専用モードでも開ける
+	d, cmd := d.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Enter should open the session detail")
+	}
+	d, _ = d.Update(cmd())
+	_, cmd = d.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'d'}})
+	if cmd == nil {
+		t.Fatal("d should be rejected in the detail page")
+	}
+	if msg, ok := cmd().(tui.LogOutputMsg); !ok || msg.Level != tui.LogError {
+		t.Errorf("d: got %#v, want error LogOutputMsg", cmd())
+	}
+}
+
+func TestDashboardReadOnly_HeaderBadge(t *testing.T) {
+	d := newTestDashboard()
+	if strings.Contains(d.renderHeader(), "READ ONLY") {
+		t.Error("header should not show badge by default")
+	}
+	d.SetReadOnly(true)
+	if !strings.Contains(d.renderHeader(), "READ ONLY") {
+		t.Errorf("header = %q, want READ ONLY badge", d.renderHeader())
+	}
+}
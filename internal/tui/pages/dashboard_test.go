@@ -1,6 +1,7 @@
 package pages
 
 import (
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -48,3 +49,19 @@ func TestDashboardSlashKeyIgnoredDuringInput(t *testing.T) {
 		t.Fatalf("expected IsInputActive() = false in idle state")
 	}
 }
+
+func TestDashboardStatusBarAggregatesTraffic(t *testing.T) {
+	d := NewDashboardPage("0.1.0")
+	d.SetSize(200, 30)
+	d.SetHosts([]core.SSHHost{{Name: "a", State: core.Reconnecting}, {Name: "b", State: core.Connected}})
+	d.SetForwardSessions([]core.ForwardSession{
+		{Rule: core.ForwardRule{Name: "web"}, Status: core.Active, SendRate: 1024, ReceiveRate: 2048},
+		{Rule: core.ForwardRule{Name: "db"}, Status: core.Active, SendRate: 1024, ReceiveRate: 2048},
+	})
+	view := d.View()
+	for _, want := range []string{"1 reconnecting", "↑2.0KB/s", "↓4.0KB/s"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("status bar should contain %q", want)
+		}
+	}
+}
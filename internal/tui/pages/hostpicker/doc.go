@@ -0,0 +1,3 @@
+// Package hostpicker はダッシュボードの外で使う最小限のホスト選択画面を提供する。
+// ホスト一覧のフィルターと同じあいまい検索で絞り込み、選んだホスト名を返す。
+package hostpicker
@@ -0,0 +1,168 @@
+package hostpicker
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/molecules"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// maxRows は一度に表示するホストの行数の上限。
+const maxRows = 10
+
+// match はフィルターに一致したホストと、強調する文字の位置。
+type match struct {
+	host      core.SSHHost
+	nameMatch []int
+	addrMatch []int
+}
+
+// Model はあいまい検索でホストを 1 つ選ぶ画面。
+// 文字を入力すると一覧を絞り込み、Enter でカーソル位置のホストを選んで終了する。Esc・Ctrl+C は選ばずに終了する。
+type Model struct {
+	hosts   []core.SSHHost
+	input   textinput.Model
+	matches []match
+	cursor  int
+	width   int
+	chosen  string
+	done    bool
+}
+
+// New は hosts から選ぶ画面を生成する。
+func New(hosts []core.SSHHost) Model {
+	input := textinput.New()
+	input.Prompt = ""
+	input.Placeholder = i18n.T("tui.setup_panel.filter_placeholder")
+	input.Focus()
+	m := Model{hosts: hosts, input: input}
+	m.filter()
+	return m
+}
+
+// Init はカーソルの点滅を開始する。
+func (m Model) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+// Update はキー入力と端末サイズの変更を処理する。
+func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.input.Width = max(msg.Width-4, 10)
+		return m, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			m.done = true
+			return m, tea.Quit
+		case "enter":
+			if len(m.matches) == 0 {
+				return m, nil
+			}
+			m.chosen = m.matches[m.cursor].host.Name
+			m.done = true
+			return m, tea.Quit
+		case "up", "ctrl+p":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			return m, nil
+		case "down", "ctrl+n":
+			if m.cursor < len(m.matches)-1 {
+				m.cursor++
+			}
+			return m, nil
+		}
+	}
+
+	prev := m.input.Value()
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	if m.input.Value() != prev {
+		m.filter()
+		m.cursor = 0
+	}
+	return m, cmd
+}
+
+// filter は入力中の文字列で一覧を絞り込む。
+func (m *Model) filter() {
+	pattern := m.input.Value()
+	m.matches = m.matches[:0]
+	for _, h := range m.hosts {
+		if nameMatch, addrMatch, ok := molecules.MatchHost(h, pattern); ok {
+			m.matches = append(m.matches, match{host: h, nameMatch: nameMatch, addrMatch: addrMatch})
+		}
+	}
+}
+
+// View は画面を描画する。選択を終えた後は何も描画しない。
+func (m Model) View() string {
+	if m.done {
+		return ""
+	}
+	g := theme.Symbols()
+	var b strings.Builder
+
+	b.WriteString(tui.TitleStyle().Render(i18n.T("tui.host_picker.title", map[string]any{
+		"Count": len(m.matches), "Total": len(m.hosts),
+	})))
+	b.WriteString("\n")
+	b.WriteString(tui.KeyStyle().Render("/ ") + m.input.View())
+	b.WriteString("\n")
+
+	if len(m.matches) == 0 {
+		b.WriteString("  " + tui.MutedStyle().Render(i18n.T("tui.setup_panel.no_matches")) + "\n")
+	}
+	// カーソルが常に見えるように表示する範囲をずらす
+	start := max(m.cursor-maxRows+1, 0)
+	end := min(start+maxRows, len(m.matches))
+	for i := start; i < end; i++ {
+		mt := m.matches[i]
+		prefix := "  "
+		if i == m.cursor {
+			prefix = tui.ActiveStyle().Render(g.Pointer + " ")
+		}
+		row := molecules.HostRow{
+			Host:      mt.host,
+			Selected:  i == m.cursor,
+			Width:     m.width,
+			NameMatch: mt.nameMatch,
+			AddrMatch: mt.addrMatch,
+		}
+		b.WriteString(prefix + row.View() + "\n")
+	}
+
+	b.WriteString(fmt.Sprintf("%s %s  %s %s  %s %s",
+		tui.KeyStyle().Render("["+g.Up+g.Down+"]"), tui.DescStyle().Render(i18n.T("tui.keys.select")),
+		tui.KeyStyle().Render("[Enter]"), tui.DescStyle().Render(i18n.T("tui.host_picker.connect")),
+		tui.KeyStyle().Render("[Esc]"), tui.DescStyle().Render(i18n.T("tui.keys.cancel")),
+	))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// Selected は選んだホスト名を返す。選ばずに終了した場合は false を返す。
+func (m Model) Selected() (string, bool) {
+	return m.chosen, m.chosen != ""
+}
+
+// Run は端末にホストの選択画面を表示し、選んだホスト名を返す。
+// 標準出力をコマンドの結果に使えるよう、画面は標準エラー出力に描画する。
+func Run(hosts []core.SSHHost) (string, bool, error) {
+	final, err := tea.NewProgram(New(hosts), tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return "", false, err
+	}
+	name, ok := final.(Model).Selected()
+	return name, ok, nil
+}
@@ -0,0 +1,107 @@
+package hostpicker
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func press(t *testing.T, m Model, keys ...tea.KeyMsg) (Model, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, k := range keys {
+		var next tea.Model
+		next, cmd = m.Update(k)
+		m = next.(Model)
+	}
+	return m, cmd
+}
+
+func typeText(s string) []tea.KeyMsg {
+	keys := make([]tea.KeyMsg, 0, len(s))
+	for _, r := range s {
+		keys = append(keys, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return keys
+}
+
+var (
+	enter = tea.KeyMsg{Type: tea.KeyEnter}
+	esc   = tea.KeyMsg{Type: tea.KeyEsc}
+	down  = tea.KeyMsg{Type: tea.KeyDown}
+	up    = tea.KeyMsg{Type: tea.KeyUp}
+)
+
+func testHosts() []core.SSHHost {
+	return []core.SSHHost{
+		{Name: "prod-web", HostName: "10.0.0.1", User: "deploy", Port: 22},
+		{Name: "prod-db", HostName: "10.0.0.2", User: "deploy", Port: 22},
+		{Name: "staging", HostName: "stg.example.com", User: "admin", Port: 2222},
+	}
+}
+
+func TestModel_FilterAndSelect(t *testing.T) {
+	m := New(testHosts())
+	m, _ = press(t, m, typeText("-db")...)
+	if len(m.matches) != 1 || m.matches[0].host.Name != "prod-db" {
+		t.Fatalf("matches = %+v, want only prod-db", m.matches)
+	}
+	if v := m.View(); !strings.Contains(v, "prod-db") || strings.Contains(v, "staging") {
+		t.Errorf("View() should show only the matching host:\n%s", v)
+	}
+
+	m, cmd := press(t, m, enter)
+	if cmd == nil {
+		t.Fatal("Enter should quit the program")
+	}
+	name, ok := m.Selected()
+	if !ok || name != "prod-db" {
+		t.Errorf("Selected() = (%q, %v), want (prod-db, true)", name, ok)
+	}
+	if m.View() != "" {
+		t.Error("View() should be empty after selecting")
+	}
+}
+
+func TestModel_CursorMovesAndResetsOnFilter(t *testing.T) {
+	m := New(testHosts())
+	m, _ = press(t, m, down, down, down)
+	if m.cursor != 2 {
+		t.Errorf("cursor = %d, want 2 (clamped to the last host)", m.cursor)
+	}
+	m, _ = press(t, m, up)
+	if m.cursor != 1 {
+		t.Errorf("cursor = %d, want 1", m.cursor)
+	}
+
+	m, _ = press(t, m, typeText("prod")...)
+	if m.cursor != 0 || len(m.matches) != 2 {
+		t.Errorf("cursor = %d, matches = %d, want 0 and 2", m.cursor, len(m.matches))
+	}
+	m, _ = press(t, m, down, enter)
+	if name, _ := m.Selected(); name != "prod-db" {
+		t.Errorf("Selected() = %q, want prod-db", name)
+	}
+}
+
+func TestModel_CancelAndNoMatches(t *testing.T) {
+	m := New(testHosts())
+	m, _ = press(t, m, typeText("zzz")...)
+	if !strings.Contains(m.View(), "No matching hosts") {
+		t.Errorf("View() should show the no-match message:\n%s", m.View())
+	}
+	m, cmd := press(t, m, enter)
+	if cmd != nil || m.done {
+		t.Error("Enter with no matches should be ignored")
+	}
+
+	m, cmd = press(t, m, esc)
+	if cmd == nil {
+		t.Fatal("Esc should quit the program")
+	}
+	if _, ok := m.Selected(); ok {
+		t.Error("Selected() should be false after cancelling")
+	}
+}
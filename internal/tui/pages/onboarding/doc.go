@@ -0,0 +1,3 @@
+// Package onboarding は初回起動時のセットアップウィザードを提供する。
+// 言語・テーマ・ssh_config のパス・自動復元を順に選び、最後に config.yaml へまとめて保存する。
+package onboarding
@@ -0,0 +1,151 @@
+package onboarding
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/organisms"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// step はウィザードの手順。
+type step int
+
+const (
+	stepLanguage step = iota
+	stepTheme
+	stepSSHConfig
+	stepAutoRestore
+	stepFirstForward
+	stepSummary
+	stepCount
+)
+
+// toggleKey は はい / いいえ を切り替えるキー。
+var toggleKey = key.NewBinding(key.WithKeys("left", "right", "h", "l", " "))
+
+// Page は初回起動時のセットアップウィザード。
+// 言語・テーマ・ssh_config のパス・自動復元・最初のフォワードを追加するかを 1 画面ずつ選び、
+// 最後の確認で OnboardingDoneMsg を発行する。最初の手順で Esc を押すと既定値のままセットアップを終える。
+type Page struct {
+	step        step
+	langs       []i18n.LangInfo
+	langCursor  int
+	grid        organisms.ThemeGrid
+	path        textinput.Model
+	initialPath string
+	autoRestore bool
+	addForward  bool
+	keys        tui.KeyMap
+	width       int
+	height      int
+}
+
+// New は config.get で取得した ssh_config のパスと自動復元の設定を初期値とするウィザードを生成する。
+// 言語の初期値は環境変数から解決した現在の言語、テーマの初期値は既定のプリセット。
+func New(sshConfigPath string, autoRestore bool) Page {
+	langs := i18n.SupportedLangs()
+	cursor := 0
+	for i, l := range langs {
+		if l.Code == i18n.CurrentLang() {
+			cursor = i
+		}
+	}
+	path := textinput.New()
+	path.Prompt = "> "
+	path.CharLimit = 1024
+	path.SetValue(sshConfigPath)
+	return Page{
+		langs:       langs,
+		langCursor:  cursor,
+		grid:        organisms.NewThemeGrid(theme.DefaultPresetID()),
+		path:        path,
+		initialPath: sshConfigPath,
+		autoRestore: autoRestore,
+		keys:        tui.DefaultKeyMap(),
+	}
+}
+
+// SetSize はページのサイズを設定する。
+func (p *Page) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+	p.grid.SetSize(width, height-8) // ヘッダー・説明・ヒントと余白の分
+	p.path.Width = max(width-8, 20)
+}
+
+// Update はキー入力を処理する。Enter で次の手順へ進み、Esc で前の手順へ戻る。
+func (p Page) Update(msg tea.KeyMsg) (Page, tea.Cmd) {
+	switch {
+	case key.Matches(msg, p.keys.Escape):
+		if p.step == stepLanguage {
+			return p, p.done()
+		}
+		return p.moveTo(p.step - 1), nil
+	case key.Matches(msg, p.keys.Enter):
+		switch p.step {
+		case stepLanguage:
+			_ = i18n.SetLang(p.langs[p.langCursor].Code) // ベストエフォート: 未知の言語でもフォールバックされる
+		case stepSummary:
+			return p, p.done()
+		}
+		return p.moveTo(p.step + 1), nil
+	}
+
+	var cmd tea.Cmd
+	switch p.step {
+	case stepLanguage:
+		if key.Matches(msg, p.keys.Up) {
+			p.langCursor = max(p.langCursor-1, 0)
+		} else if key.Matches(msg, p.keys.Down) {
+			p.langCursor = min(p.langCursor+1, len(p.langs)-1)
+		}
+	case stepTheme:
+		p.grid, cmd = p.grid.Update(msg)
+	case stepSSHConfig:
+		p.path, cmd = p.path.Update(msg)
+	case stepAutoRestore:
+		if key.Matches(msg, toggleKey) {
+			p.autoRestore = !p.autoRestore
+		}
+	case stepFirstForward:
+		if key.Matches(msg, toggleKey) {
+			p.addForward = !p.addForward
+		}
+	}
+	return p, cmd
+}
+
+// moveTo は手順 s に移動する。ssh_config の手順ではパスの入力欄にフォーカスする。
+func (p Page) moveTo(s step) Page {
+	p.step = s
+	if s == stepSSHConfig {
+		p.path.Focus()
+	} else {
+		p.path.Blur()
+	}
+	return p
+}
+
+// done は選んだ設定で OnboardingDoneMsg を発行するコマンドを返す。
+func (p Page) done() tea.Cmd {
+	msg := tui.OnboardingDoneMsg{
+		Lang:             string(i18n.CurrentLang()),
+		PresetID:         p.grid.SelectedPresetID(),
+		SSHConfigPath:    p.sshConfigPath(),
+		AutoRestore:      p.autoRestore,
+		SSHConfigChanged: p.sshConfigPath() != p.initialPath,
+		AddForward:       p.addForward,
+	}
+	return func() tea.Msg { return msg }
+}
+
+// sshConfigPath は入力された ssh_config のパスを返す。空の場合は初期値を返す。
+func (p Page) sshConfigPath() string {
+	if v := p.path.Value(); v != "" {
+		return v
+	}
+	return p.initialPath
+}
@@ -0,0 +1,82 @@
+package onboarding
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+func press(t *testing.T, p Page, keys ...tea.KeyMsg) (Page, tea.Cmd) {
+	t.Helper()
+	var cmd tea.Cmd
+	for _, k := range keys {
+		p, cmd = p.Update(k)
+	}
+	return p, cmd
+}
+
+var (
+	enter = tea.KeyMsg{Type: tea.KeyEnter}
+	esc   = tea.KeyMsg{Type: tea.KeyEsc}
+	space = tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}
+)
+
+func TestPage_Walkthrough(t *testing.T) {
+	t.Cleanup(func() {
+		_ = i18n.SetLang(i18n.DefaultLang())
+		theme.Apply(theme.DefaultPresetID())
+	})
+	p := New("~/.ssh/config", true)
+	p.SetSize(100, 30)
+
+	// 言語 → テーマ（↓ でアクセントを変更）→ ssh_config
+	p, _ = press(t, p, enter, tea.KeyMsg{Type: tea.KeyDown}, enter)
+	if p.step != stepSSHConfig || !strings.Contains(p.View(), "3/6") {
+		t.Fatalf("step = %d, want ssh_config:\n%s", p.step, p.View())
+	}
+	p, _ = press(t, p, tea.KeyMsg{Type: tea.KeyCtrlU}, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/nonexistent/config")})
+	if !strings.Contains(p.View(), "not found") {
+		t.Errorf("a missing ssh_config should be reported:\n%s", p.View())
+	}
+	// 自動復元を無効にし、最初のフォワードの追加を有効にして確認画面へ
+	p, _ = press(t, p, enter, space, enter, space, enter)
+	view := p.View()
+	for _, want := range []string{"6/6", "/nonexistent/config", "Dark / Blue"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("summary should contain %q:\n%s", want, view)
+		}
+	}
+
+	_, cmd := press(t, p, enter)
+	if cmd == nil {
+		t.Fatal("Enter on the summary should finish the wizard")
+	}
+	got, ok := cmd().(tui.OnboardingDoneMsg)
+	want := tui.OnboardingDoneMsg{
+		Lang: "en", PresetID: "dark-blue", SSHConfigPath: "/nonexistent/config",
+		AutoRestore: false, SSHConfigChanged: true, AddForward: true,
+	}
+	if !ok || got != want {
+		t.Errorf("got %#v, want %#v", cmd(), want)
+	}
+}
+
+func TestPage_EscGoesBackThenSkips(t *testing.T) {
+	p := New("~/.ssh/config", true)
+	p, cmd := press(t, p, enter, esc)
+	if p.step != stepLanguage || cmd != nil {
+		t.Fatalf("Esc should go back to the language step, step = %d", p.step)
+	}
+	_, cmd = press(t, p, esc)
+	if cmd == nil {
+		t.Fatal("Esc on the first step should skip the wizard")
+	}
+	got, ok := cmd().(tui.OnboardingDoneMsg)
+	if !ok || got.SSHConfigPath != "~/.ssh/config" || got.SSHConfigChanged || !got.AutoRestore || got.PresetID != theme.DefaultPresetID() {
+		t.Errorf("skipping should keep the defaults, got %#v", cmd())
+	}
+}
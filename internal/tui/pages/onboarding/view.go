@@ -0,0 +1,125 @@
+package onboarding
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// stepKeys は手順ごとの見出し・説明・キー操作のヒントの翻訳キーの接尾辞。
+var stepKeys = [stepCount]string{"language", "theme", "ssh_config", "auto_restore", "first_forward", "summary"}
+
+// View は現在の手順を描画する。
+func (p Page) View() string {
+	name := stepKeys[p.step]
+	rows := []string{
+		tui.HeaderStyle().Render("  " + i18n.T("tui.onboarding.title")),
+		"",
+		tui.TitleStyle().Render("  " + i18n.T("tui.onboarding.step", map[string]any{
+			"Step": int(p.step) + 1, "Total": int(stepCount), "Title": i18n.T("tui.onboarding." + name),
+		})),
+		tui.MutedStyle().Render("  " + i18n.T("tui.onboarding."+name+"_desc")),
+		"",
+	}
+	rows = append(rows, p.body()...)
+	rows = append(rows, "", tui.MutedStyle().Render("  "+i18n.T("tui.onboarding.hint_"+name)))
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// body は現在の手順の選択肢や入力欄を描画する。
+func (p Page) body() []string {
+	switch p.step {
+	case stepLanguage:
+		rows := make([]string, len(p.langs))
+		for i, l := range p.langs {
+			rows[i] = "    " + l.Label
+			if i == p.langCursor {
+				rows[i] = tui.SelectedStyle().Render("  > " + l.Label)
+			}
+		}
+		return rows
+	case stepTheme:
+		return []string{p.grid.View()}
+	case stepSSHConfig:
+		status := tui.ActiveStyle().Render(i18n.T("tui.onboarding.ssh_config_found"))
+		if !fileExists(p.sshConfigPath()) {
+			status = tui.WarningStyle().Render(i18n.T("tui.onboarding.ssh_config_missing"))
+		}
+		return []string{"  " + p.path.View(), "", "  " + status}
+	case stepAutoRestore:
+		return []string{"  " + renderChoice(p.autoRestore)}
+	case stepFirstForward:
+		return []string{"  " + renderChoice(p.addForward)}
+	}
+	return p.summary()
+}
+
+// summary は選んだ設定の一覧を描画する。
+func (p Page) summary() []string {
+	presetName := p.grid.SelectedPresetID()
+	if preset, ok := theme.FindPreset(presetName); ok {
+		presetName = "Dark / " + preset.Label
+		if preset.Base == "light" {
+			presetName = "Light / " + preset.Label
+		}
+	}
+	items := []struct{ label, value string }{
+		{i18n.T("tui.onboarding.language"), p.langs[p.langCursor].Label},
+		{i18n.T("tui.onboarding.theme"), presetName},
+		{i18n.T("tui.onboarding.ssh_config"), p.sshConfigPath()},
+		{i18n.T("tui.onboarding.auto_restore"), yesNo(p.autoRestore)},
+		{i18n.T("tui.onboarding.first_forward"), yesNo(p.addForward)},
+	}
+	width := 0
+	for _, item := range items {
+		width = max(width, lipgloss.Width(item.label))
+	}
+	rows := make([]string, len(items))
+	for i, item := range items {
+		rows[i] = "  " + tui.MutedStyle().Render(item.label+strings.Repeat(" ", width-lipgloss.Width(item.label))+"  ") +
+			tui.TextStyle().Render(item.value)
+	}
+	return rows
+}
+
+// renderChoice は はい / いいえ の選択肢を、選んだ方を強調して描画する。
+func renderChoice(yes bool) string {
+	choices := []string{i18n.T("tui.onboarding.yes"), i18n.T("tui.onboarding.no")}
+	selected := 1
+	if yes {
+		selected = 0
+	}
+	for i, c := range choices {
+		if i == selected {
+			choices[i] = tui.SelectedStyle().Render("(" + theme.Symbols().Active + ") " + c)
+		} else {
+			choices[i] = tui.MutedStyle().Render("( ) " + c)
+		}
+	}
+	return strings.Join(choices, "   ")
+}
+
+func yesNo(v bool) string {
+	if v {
+		return i18n.T("tui.onboarding.yes")
+	}
+	return i18n.T("tui.onboarding.no")
+}
+
+// fileExists は path のファイルが TUI を実行しているマシンに存在するかを返す。先頭の ~ はホームディレクトリに展開する。
+func fileExists(path string) bool {
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || rest[0] == '/' || rest[0] == filepath.Separator) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return false
+		}
+		path = home + rest
+	}
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
@@ -0,0 +1,3 @@
+// Package panes はダッシュボードのペイン配分（フォワードパネルの割合・1 ペイン表示・ログパネルの表示）と、
+// 1 ペイン表示で使うタブ行の描画を提供する。
+package panes
@@ -0,0 +1,99 @@
+package panes
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+// ペイン配分の定数。
+const (
+	DefaultForwardPercent = 40 // フォワードパネルに割り当てる高さの既定の割合（%）
+	MinForwardPercent     = 10
+	MaxForwardPercent     = 90
+	forwardPercentStep    = 10 // +/- で変える割合
+	// CompactHeight 未満の高さの端末ではログパネルを隠し、フォーカス中のペインだけをタブ付きで表示する
+	CompactHeight = 20
+)
+
+// Layout はダッシュボードのペイン配分。
+type Layout struct {
+	// ForwardPercent はフォワードパネルとセットアップパネルの高さのうちフォワードパネルに割り当てる割合（%）。
+	ForwardPercent int
+	// Zoomed が true の場合はフォーカス中のペインだけを表示する。HideLog が true の場合はログパネルを表示しない。
+	Zoomed  bool
+	HideLog bool
+}
+
+// New は既定のペイン配分を返す。
+func New() Layout {
+	return Layout{ForwardPercent: DefaultForwardPercent}
+}
+
+// Set は config.yaml の tui.layout の設定を適用する。forwardPercent が 0 の場合は既定値を使う。
+func (l *Layout) Set(forwardPercent int, hideLog bool) {
+	if forwardPercent == 0 {
+		forwardPercent = DefaultForwardPercent
+	}
+	l.ForwardPercent = clamp(forwardPercent)
+	l.HideLog = hideLog
+}
+
+// SinglePane は高さ height の端末でフォーカス中のペインだけを表示するかを返す。
+func (l Layout) SinglePane(height int) bool {
+	return l.Zoomed || height < CompactHeight
+}
+
+// LogVisible は高さ height の端末でログパネルを表示するかを返す。1 ペイン表示の小さな端末ではログパネルを隠す。
+func (l Layout) LogVisible(height int) bool {
+	return !l.HideLog && height >= CompactHeight
+}
+
+// Update はペイン配分を変えるキー（+ / - / z / o）を処理し、処理した場合は true を返す。
+// + / - はフォーカス中のペイン focused を広げる・狭める。
+func (l *Layout) Update(msg tea.KeyMsg, keys tui.KeyMap, focused tui.FocusPane) bool {
+	step := forwardPercentStep
+	if focused == tui.PaneSetup {
+		step = -step
+	}
+	switch {
+	case key.Matches(msg, keys.Grow):
+		l.ForwardPercent = clamp(l.ForwardPercent + step)
+	case key.Matches(msg, keys.Shrink):
+		l.ForwardPercent = clamp(l.ForwardPercent - step)
+	case key.Matches(msg, keys.Zoom):
+		l.Zoomed = !l.Zoomed
+	case key.Matches(msg, keys.ToggleLog):
+		l.HideLog = !l.HideLog
+	default:
+		return false
+	}
+	return true
+}
+
+func clamp(percent int) int {
+	return min(max(percent, MinForwardPercent), MaxForwardPercent)
+}
+
+// RenderTabs は 1 ペイン表示で表示中のペイン focused を示すタブ行を描画する。
+func RenderTabs(focused tui.FocusPane) string {
+	tabs := []struct {
+		pane  tui.FocusPane
+		label string
+	}{
+		{tui.PaneForwards, i18n.T("tui.layout.forwards")},
+		{tui.PaneSetup, i18n.T("tui.layout.hosts")},
+	}
+	parts := make([]string, 0, len(tabs)+1)
+	for _, t := range tabs {
+		if t.pane == focused {
+			parts = append(parts, tui.SelectedStyle().Render(" "+t.label+" "))
+		} else {
+			parts = append(parts, tui.MutedStyle().Render(" "+t.label+" "))
+		}
+	}
+	parts = append(parts, tui.MutedStyle().Render(" "+i18n.T("tui.layout.tab_hint")))
+	return lipgloss.JoinHorizontal(lipgloss.Top, append([]string{" "}, parts...)...)
+}
@@ -0,0 +1,62 @@
+package panes
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/ousiassllc/moleport/internal/tui"
+)
+
+func runeKey(r rune) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}}
+}
+
+func TestLayoutResize(t *testing.T) {
+	keys := tui.DefaultKeyMap()
+	l := New()
+
+	// セットアップパネルにフォーカスしている場合、+ はセットアップパネルを広げる
+	l.Update(runeKey('+'), keys, tui.PaneSetup)
+	if l.ForwardPercent != 30 {
+		t.Errorf("after + on setup: ForwardPercent = %d, want 30", l.ForwardPercent)
+	}
+	for range 10 {
+		l.Update(runeKey('+'), keys, tui.PaneForwards)
+	}
+	if l.ForwardPercent != MaxForwardPercent {
+		t.Errorf("ForwardPercent = %d, want clamped to %d", l.ForwardPercent, MaxForwardPercent)
+	}
+	l.Update(runeKey('-'), keys, tui.PaneForwards)
+	if l.ForwardPercent != 80 {
+		t.Errorf("after - on forwards: ForwardPercent = %d, want 80", l.ForwardPercent)
+	}
+	if l.Update(runeKey('x'), keys, tui.PaneForwards) {
+		t.Error("Update should not handle unrelated keys")
+	}
+}
+
+func TestLayoutSet(t *testing.T) {
+	l := New()
+	l.Set(5, true)
+	if l.ForwardPercent != MinForwardPercent || !l.HideLog {
+		t.Errorf("Set(5, true) = %+v, want clamped percent and hidden log", l)
+	}
+	l.Set(0, false)
+	if l.ForwardPercent != DefaultForwardPercent {
+		t.Errorf("Set(0, false): ForwardPercent = %d, want default %d", l.ForwardPercent, DefaultForwardPercent)
+	}
+}
+
+func TestLayoutVisibility(t *testing.T) {
+	l := New()
+	if l.SinglePane(40) || !l.LogVisible(40) {
+		t.Error("a 40-row terminal should show the split layout with the log pane")
+	}
+	if !l.SinglePane(CompactHeight-1) || l.LogVisible(CompactHeight-1) {
+		t.Error("a small terminal should show a single pane without the log pane")
+	}
+	l.Zoomed, l.HideLog = true, true
+	if !l.SinglePane(40) || l.LogVisible(40) {
+		t.Error("zoomed layout with a hidden log should show a single pane without the log pane")
+	}
+}
@@ -0,0 +1,3 @@
+// Package sessiondetail はダッシュボードで稼働中のフォワードセッションの詳細を表示するページと、
+// その表示に使う直近のエラー・トラフィックの記録を提供する。
+package sessiondetail
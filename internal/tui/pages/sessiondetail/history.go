@@ -0,0 +1,97 @@
+package sessiondetail
+
+import (
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+// maxRecentErrors はルールごとに保持する直近のエラーの件数。
+const maxRecentErrors = 5
+
+// RecentError はフォワードセッションで発生したエラーと、それを検知した時刻。
+type RecentError struct {
+	At      time.Time
+	Message string
+}
+
+// ErrorHistory はフォワードセッションの LastError の変化からルールごとの直近のエラーを記録する。
+type ErrorHistory struct {
+	last   map[string]string
+	recent map[string][]RecentError
+}
+
+// Observe はセッション一覧の LastError を確認し、前回から変化したエラーを記録する。
+func (h *ErrorHistory) Observe(sessions []core.ForwardSession, now time.Time) {
+	if h.last == nil {
+		h.last = make(map[string]string)
+		h.recent = make(map[string][]RecentError)
+	}
+	for _, s := range sessions {
+		name := s.Rule.Name
+		if s.LastError != "" && s.LastError != h.last[name] {
+			errs := append(h.recent[name], RecentError{At: now, Message: s.LastError})
+			h.recent[name] = errs[max(len(errs)-maxRecentErrors, 0):]
+		}
+		h.last[name] = s.LastError
+	}
+}
+
+// Recent はルールの直近のエラーを古い順に返す。
+func (h ErrorHistory) Recent(name string) []RecentError {
+	return h.recent[name]
+}
+
+// trafficWindow はセッション詳細のトラフィックグラフに表示する期間。
+const trafficWindow = 5 * time.Minute
+
+// trafficSample は 1 回のメトリクス更新で取得した送受信合計レート。
+type trafficSample struct {
+	at   time.Time
+	rate float64
+}
+
+// TrafficHistory はメトリクス更新ごとのセッション一覧からルールごとの送受信合計レートを trafficWindow の間記録する。
+type TrafficHistory struct {
+	samples map[string][]trafficSample
+}
+
+// Observe はセッション一覧のレートを記録する。停止中のセッションは 0 として記録し、一覧から無くなったルールの記録は破棄する。
+// 前回の記録から 1 秒未満の更新は前回の記録を置き換える。
+func (h *TrafficHistory) Observe(sessions []core.ForwardSession, now time.Time) {
+	if h.samples == nil {
+		h.samples = make(map[string][]trafficSample)
+	}
+	seen := make(map[string]bool, len(sessions))
+	for _, s := range sessions {
+		name := s.Rule.Name
+		seen[name] = true
+		rate := 0.0
+		if s.Status == core.Active {
+			rate = s.SendRate + s.ReceiveRate
+		}
+		samples := h.samples[name]
+		if n := len(samples); n > 0 && now.Sub(samples[n-1].at) < time.Second {
+			samples = samples[:n-1]
+		}
+		samples = append(samples, trafficSample{at: now, rate: rate})
+		for len(samples) > 0 && now.Sub(samples[0].at) > trafficWindow {
+			samples = samples[1:]
+		}
+		h.samples[name] = samples
+	}
+	for name := range h.samples {
+		if !seen[name] {
+			delete(h.samples, name)
+		}
+	}
+}
+
+// Rates はルールの記録済みのレートを古い順に返す。
+func (h TrafficHistory) Rates(name string) []float64 {
+	out := make([]float64, len(h.samples[name]))
+	for i, s := range h.samples[name] {
+		out[i] = s.rate
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package sessiondetail
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestErrorHistory_Observe(t *testing.T) {
+	var h ErrorHistory
+	now := time.Now()
+	for i, msg := range []string{"a", "a", "", "a", "b", "c", "d", "e", "f"} {
+		h.Observe([]core.ForwardSession{detailSession(msg)}, now.Add(time.Duration(i)*time.Second))
+	}
+	var got []string
+	for _, e := range h.Recent("web") {
+		got = append(got, e.Message)
+	}
+	if want := "b,c,d,e,f"; strings.Join(got, ",") != want {
+		t.Errorf("recent errors = %v, want %s", got, want)
+	}
+}
+
+func TestTrafficHistory_Observe(t *testing.T) {
+	var h TrafficHistory
+	now := time.Now()
+	active := detailSession("")
+	active.SendRate, active.ReceiveRate = 100, 200
+	stopped := active
+	stopped.Status = core.Stopped
+
+	h.Observe([]core.ForwardSession{active}, now.Add(-trafficWindow-time.Second))
+	h.Observe([]core.ForwardSession{active}, now.Add(-2*time.Second))
+	h.Observe([]core.ForwardSession{stopped}, now.Add(-time.Second))
+	h.Observe([]core.ForwardSession{active}, now.Add(-500*time.Millisecond)) // 1 秒未満の更新は置き換える
+	if got := h.Rates("web"); !slices.Equal(got, []float64{300, 300}) {
+		t.Errorf("rates = %v, want [300 300] (expired sample dropped, close samples merged)", got)
+	}
+	h.Observe([]core.ForwardSession{stopped}, now.Add(time.Second))
+	if got := h.Rates("web"); !slices.Equal(got, []float64{300, 300, 0}) {
+		t.Errorf("rates = %v, want stopped session recorded as 0", got)
+	}
+	h.Observe(nil, now.Add(2*time.Second))
+	if got := h.Rates("web"); len(got) != 0 {
+		t.Errorf("rates = %v, want removed rule discarded", got)
+	}
+}
@@ -0,0 +1,234 @@
+package sessiondetail
+
+import (
+	"cmp"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/core"
+	"github.com/ousiassllc/moleport/internal/format"
+	"github.com/ousiassllc/moleport/internal/i18n"
+	"github.com/ousiassllc/moleport/internal/tui"
+	"github.com/ousiassllc/moleport/internal/tui/atoms"
+	"github.com/ousiassllc/moleport/internal/tui/clipboard"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+// trafficGraphHeight はトラフィックグラフの行数。
+const trafficGraphHeight = 4
+
+// Page は稼働中のフォワードセッションの詳細ページ。
+// ライブメトリクスとトラフィックグラフ、転送中の接続、直近のエラー、稼働時間、同等の ssh コマンドラインを表示する。
+type Page struct {
+	session core.ForwardSession
+	errors  []RecentError
+	traffic []float64
+	keys    tui.KeyMap
+	width   int
+	height  int
+}
+
+// New は session の詳細ページを生成する。
+func New(session core.ForwardSession) Page {
+	return Page{session: session, keys: tui.DefaultKeyMap()}
+}
+
+// RuleName は表示中のルール名を返す。
+func (p Page) RuleName() string {
+	return p.session.Rule.Name
+}
+
+// SetSession は表示中のセッション情報を更新する。
+func (p *Page) SetSession(session core.ForwardSession) {
+	p.session = session
+}
+
+// SetHistory は直近のエラーと、メトリクス更新ごとの送受信合計レートの推移（古い順）を設定する。
+func (p *Page) SetHistory(errors []RecentError, traffic []float64) {
+	p.errors = errors
+	p.traffic = traffic
+}
+
+// SetSize はページのサイズを設定する。
+func (p *Page) SetSize(width, height int) {
+	p.width = width
+	p.height = height
+}
+
+// Update はキー入力を処理する。Esc・Enter・q でページを閉じ、稼働中であれば d でフォワードを停止する。
+// y で接続先を、Y で同等の ssh コマンドラインをクリップボードにコピーする。
+func (p Page) Update(msg tea.Msg) (Page, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return p, nil
+	}
+	switch {
+	case key.Matches(keyMsg, p.keys.Escape), key.Matches(keyMsg, p.keys.Enter), key.Matches(keyMsg, p.keys.Quit):
+		return p, func() tea.Msg { return tui.SessionDetailClosedMsg{} }
+	case key.Matches(keyMsg, p.keys.Disconnect):
+		if p.session.Status == core.Active {
+			name := p.session.Rule.Name
+			return p, func() tea.Msg { return tui.ForwardToggleMsg{RuleName: name} }
+		}
+	case key.Matches(keyMsg, p.keys.Copy):
+		return p, clipboard.CopyCmd(Endpoint(p.session))
+	case key.Matches(keyMsg, p.keys.CopyCommand):
+		return p, clipboard.CopyCmd(SSHCommand(p.session))
+	}
+	return p, nil
+}
+
+// View は詳細ページを描画する。
+func (p Page) View() string {
+	s := p.session
+	header := tui.HeaderStyle().Render("  " + i18n.T("tui.session_detail.title", map[string]any{"Name": s.Rule.Name}))
+	rows := []string{
+		header, "",
+		atoms.RenderSessionBadge(s.Status) + " " + tui.TextStyle().Bold(true).Render(s.Rule.Name) +
+			tui.MutedStyle().Render(" ["+s.Rule.Host+"] "+s.Rule.Type.String()),
+		p.field("route", routeLabel(s)),
+	}
+	if s.Status == core.Active && !s.ConnectedAt.IsZero() {
+		rows = append(rows, p.field("uptime", format.Duration(time.Since(s.ConnectedAt))))
+	}
+	if !s.ExpiresAt.IsZero() {
+		rows = append(rows, p.field("expires", format.Duration(time.Until(s.ExpiresAt))))
+	}
+	rows = append(rows,
+		p.field("reconnects", strconv.Itoa(s.ReconnectCount)),
+		tui.MutedStyle().Render(i18n.T("tui.session_detail.traffic")+": ")+atoms.RenderTraffic(s.BytesSent, s.BytesReceived),
+		tui.MutedStyle().Render(i18n.T("tui.session_detail.rate")+": ")+
+			tui.DividerStyle().Render(theme.Symbols().Up)+tui.MutedStyle().Render(format.Bytes(int64(s.SendRate))+"/s ")+
+			tui.DividerStyle().Render(theme.Symbols().Down)+tui.MutedStyle().Render(format.Bytes(int64(s.ReceiveRate))+"/s"),
+		"",
+	)
+	rows = append(rows, p.trafficRows()...)
+	rows = append(rows, "")
+	rows = append(rows, p.connectionRows()...)
+	rows = append(rows, "")
+	rows = append(rows, p.errorRows()...)
+	rows = append(rows, "",
+		tui.TitleStyle().Render(i18n.T("tui.session_detail.ssh_command")),
+		"  "+tui.TextStyle().Render(SSHCommand(s)),
+		"",
+		tui.MutedStyle().Render("  "+i18n.T("tui.session_detail.hint")),
+	)
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// trafficRows はメトリクス更新ごとの送受信合計レートの推移を点字のグラフとして描画する。
+func (p Page) trafficRows() []string {
+	title := i18n.T("tui.session_detail.throughput", map[string]any{"Minutes": int(trafficWindow.Minutes())})
+	peak := 0.0
+	for _, v := range p.traffic {
+		peak = max(peak, v)
+	}
+	rows := []string{tui.TitleStyle().Render(title) + tui.MutedStyle().Render("  "+i18n.T("tui.session_detail.peak")+" "+format.Bytes(int64(peak))+"/s")}
+	graph := atoms.RenderTrafficGraph(p.traffic, max(p.width-4, 10), trafficGraphHeight)
+	if graph == "" {
+		return append(rows, "  "+tui.MutedStyle().Render(i18n.T("tui.session_detail.no_traffic")))
+	}
+	for _, line := range strings.Split(graph, "\n") {
+		rows = append(rows, "  "+line)
+	}
+	return rows
+}
+
+// connectionRows は転送中の接続の一覧を描画する。
+func (p Page) connectionRows() []string {
+	conns := p.session.Connections
+	rows := []string{tui.TitleStyle().Render(i18n.T("tui.session_detail.connections", map[string]any{"Count": len(conns)}))}
+	if len(conns) == 0 {
+		return append(rows, "  "+tui.MutedStyle().Render(i18n.T("tui.session_detail.no_connections")))
+	}
+	for _, c := range conns {
+		peer := c.Peer
+		if peer == "" {
+			peer = "-"
+		}
+		rows = append(rows, "  "+tui.TextStyle().Render(fmt.Sprintf("%-24s", peer))+" "+
+			tui.MutedStyle().Render(format.Clock(c.Since)+" ("+format.Duration(time.Since(c.Since))+")"))
+	}
+	return rows
+}
+
+// errorRows は直近のエラーと停止理由を描画する。
+func (p Page) errorRows() []string {
+	rows := []string{tui.TitleStyle().Render(i18n.T("tui.session_detail.errors"))}
+	if reason := atoms.RenderStopReason(p.session.StoppedReason, p.session.StoppedAt); reason != "" {
+		rows = append(rows, "  "+reason)
+	}
+	if len(p.errors) == 0 && p.session.LastError == "" {
+		return append(rows, "  "+tui.MutedStyle().Render(i18n.T("tui.session_detail.no_errors")))
+	}
+	if len(p.errors) == 0 {
+		return append(rows, "  "+tui.ErrorStyle().Render(p.session.LastError))
+	}
+	for i := len(p.errors) - 1; i >= 0; i-- {
+		e := p.errors[i]
+		rows = append(rows, "  "+tui.MutedStyle().Render(format.Clock(e.At)+" ")+tui.ErrorStyle().Render(e.Message))
+	}
+	return rows
+}
+
+func (p Page) field(label, value string) string {
+	return tui.MutedStyle().Render(i18n.T("tui.session_detail."+label)+": ") + tui.TextStyle().Render(value)
+}
+
+// routeLabel は待ち受けアドレスと転送先を "127.0.0.1:8080 → localhost:80" の形式で返す。
+func routeLabel(s core.ForwardSession) string {
+	local := net.JoinHostPort(s.Rule.LocalBindHost(), strconv.Itoa(s.ListenPort()))
+	switch s.Rule.Type {
+	case core.Remote:
+		bind := s.Rule.Host
+		if s.Rule.RemoteBindAddr != "" {
+			bind = s.Rule.RemoteBindAddr
+		}
+		return net.JoinHostPort(bind, strconv.Itoa(s.Rule.RemotePort)) + " " + theme.Symbols().Arrow + " " + local
+	case core.Dynamic:
+		return local + " " + theme.Symbols().Arrow + " SOCKS"
+	default:
+		return local + " " + theme.Symbols().Arrow + " " + net.JoinHostPort(s.Rule.RemoteHost, strconv.Itoa(s.Rule.RemotePort))
+	}
+}
+
+// Endpoint はセッションの利用者が接続する先を返す。
+// Local は "127.0.0.1:8080"、Dynamic は "socks5://127.0.0.1:1080"、Remote はリモートホスト上で接続する待ち受けアドレスを返す。
+func Endpoint(s core.ForwardSession) string {
+	r := s.Rule
+	switch r.Type {
+	case core.Remote:
+		return net.JoinHostPort(cmp.Or(r.RemoteBindAddr, "localhost"), strconv.Itoa(r.RemotePort))
+	case core.Dynamic:
+		return "socks5://" + net.JoinHostPort(r.LocalBindHost(), strconv.Itoa(s.ListenPort()))
+	default:
+		return net.JoinHostPort(r.LocalBindHost(), strconv.Itoa(s.ListenPort()))
+	}
+}
+
+// SSHCommand はセッションと同じ転送を OpenSSH で行うコマンドラインを返す。
+// Local/Dynamic の待ち受けポートは実際に Listen しているポートを使う。
+func SSHCommand(s core.ForwardSession) string {
+	r := s.Rule
+	local := net.JoinHostPort(r.LocalBindHost(), strconv.Itoa(s.ListenPort()))
+	var spec string
+	switch r.Type {
+	case core.Remote:
+		remote := strconv.Itoa(r.RemotePort)
+		if r.RemoteBindAddr != "" {
+			remote = net.JoinHostPort(r.RemoteBindAddr, remote)
+		}
+		spec = "-R " + remote + ":" + local
+	case core.Dynamic:
+		spec = "-D " + local
+	default:
+		spec = "-L " + local + ":" + net.JoinHostPort(r.RemoteHost, strconv.Itoa(r.RemotePort))
+	}
+	return strings.Join([]string{"ssh", "-N", spec, r.Host}, " ")
+}
@@ -0,0 +1,82 @@
+package sessiondetail
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ousiassllc/moleport/internal/core"
+)
+
+func TestSSHCommand(t *testing.T) {
+	tests := []struct {
+		name string
+		sess core.ForwardSession
+		want string
+	}{
+		{"local", core.ForwardSession{Rule: core.ForwardRule{
+			Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80,
+		}}, "ssh -N -L 127.0.0.1:8080:localhost:80 prod"},
+		{"local with bound port", core.ForwardSession{Rule: core.ForwardRule{
+			Host: "prod", Type: core.Local, LocalBindAddr: "::1", RemoteHost: "db", RemotePort: 5432,
+		}, BoundPort: 40000}, "ssh -N -L [::1]:40000:db:5432 prod"},
+		{"remote", core.ForwardSession{Rule: core.ForwardRule{
+			Host: "bastion", Type: core.Remote, LocalPort: 3000, RemotePort: 9000,
+		}}, "ssh -N -R 9000:127.0.0.1:3000 bastion"},
+		{"remote with bind address", core.ForwardSession{Rule: core.ForwardRule{
+			Host: "bastion", Type: core.Remote, LocalPort: 3000, RemotePort: 9000, RemoteBindAddr: "0.0.0.0",
+		}}, "ssh -N -R 0.0.0.0:9000:127.0.0.1:3000 bastion"},
+		{"dynamic", core.ForwardSession{Rule: core.ForwardRule{
+			Host: "dev", Type: core.Dynamic, LocalPort: 1080,
+		}}, "ssh -N -D 127.0.0.1:1080 dev"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SSHCommand(tt.sess); got != tt.want {
+				t.Errorf("SSHCommand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := []struct {
+		name string
+		rule core.ForwardRule
+		want string
+	}{
+		{"local", core.ForwardRule{Type: core.Local, LocalPort: 8080, RemoteHost: "db", RemotePort: 5432}, "127.0.0.1:8080"},
+		{"local ipv6", core.ForwardRule{Type: core.Local, LocalBindAddr: "::1", LocalPort: 8080}, "[::1]:8080"},
+		{"dynamic", core.ForwardRule{Type: core.Dynamic, LocalPort: 1080}, "socks5://127.0.0.1:1080"},
+		{"remote", core.ForwardRule{Type: core.Remote, LocalPort: 3000, RemotePort: 9000}, "localhost:9000"},
+		{"remote with bind address", core.ForwardRule{Type: core.Remote, RemotePort: 9000, RemoteBindAddr: "0.0.0.0"}, "0.0.0.0:9000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Endpoint(core.ForwardSession{Rule: tt.rule}); got != tt.want {
+				t.Errorf("Endpoint() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func detailSession(lastError string) core.ForwardSession {
+	return core.ForwardSession{
+		Rule:   core.ForwardRule{Name: "web", Host: "prod", Type: core.Local, LocalPort: 8080, RemoteHost: "localhost", RemotePort: 80},
+		Status: core.Active, ConnectedAt: time.Now().Add(-90 * time.Second), LastError: lastError,
+		ActiveConns: 1, Connections: []core.ConnInfo{{Peer: "127.0.0.1:50000", Since: time.Now()}},
+	}
+}
+
+func TestPage_View(t *testing.T) {
+	p := New(detailSession(""))
+	p.SetHistory([]RecentError{{At: time.Now(), Message: "dial timeout"}}, []float64{0, 1024, 2048})
+	p.SetSize(100, 30)
+	view := p.View()
+	for _, want := range []string{"web", "127.0.0.1:8080 → localhost:80", "1m 30s", "peak 2.0KB/s", "⢸⡇", "127.0.0.1:50000", "dial timeout",
+		"ssh -N -L 127.0.0.1:8080:localhost:80 prod"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view should contain %q:\n%s", want, view)
+		}
+	}
+}
@@ -36,10 +36,10 @@ func MutedStyle() lipgloss.Style {
 	return lipgloss.NewStyle().Foreground(theme.Current().Muted)
 }
 
-// SelectedStyle は選択中アイテムのハイライトスタイルを返す。
+// SelectedStyle は選択中アイテムのハイライトスタイルを返す。色が使えない場合は反転表示で示す。
 func SelectedStyle() lipgloss.Style {
 	p := theme.Current()
-	return lipgloss.NewStyle().Background(p.BgHighlight).Foreground(p.Accent).Bold(true)
+	return lipgloss.NewStyle().Background(p.BgHighlight).Foreground(p.Accent).Bold(true).Reverse(theme.Colorless())
 }
 
 // TextStyle は標準テキスト用スタイルを返す。
@@ -95,7 +95,7 @@ func HeaderStyle() lipgloss.Style {
 // FocusedBorder はフォーカス中のパネルボーダースタイルを返す。
 func FocusedBorder() lipgloss.Style {
 	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(theme.Border()).
 		BorderForeground(theme.Current().Accent).
 		Padding(0, 1)
 }
@@ -103,7 +103,7 @@ func FocusedBorder() lipgloss.Style {
 // UnfocusedBorder は非フォーカスのパネルボーダースタイルを返す。
 func UnfocusedBorder() lipgloss.Style {
 	return lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(theme.Border()).
 		BorderForeground(theme.Current().Dim).
 		Padding(0, 1)
 }
@@ -130,8 +130,8 @@ func RenderWithBorderTitle(style lipgloss.Style, width, height int, title, conte
 	borderFg := style.GetBorderTopForeground()
 	borderColor := lipgloss.NewStyle().Foreground(borderFg)
 
-	// NOTE: RoundedBorder 前提。他のボーダースタイルでは正しく動作しない。
-	b := lipgloss.RoundedBorder()
+	// NOTE: style が theme.Border() のボーダーを使う前提。
+	b := theme.Border()
 	prefix := borderColor.Render(b.TopLeft+b.Top) + " " + title + " "
 	prefixWidth := lipgloss.Width(prefix)
 
@@ -258,3 +258,21 @@ func TestRenderWithBorderTitle_EmptyContent(t *testing.T) {
 		t.Error("should contain title even with empty content")
 	}
 }
+
+func TestPlainMode_ASCIIBorderAndReverseSelection(t *testing.T) {
+	t.Cleanup(func() { theme.SetPlain(false) })
+	theme.SetPlain(true)
+
+	rendered := RenderWithBorderTitle(FocusedBorder(), 30, 3, "Title", "content")
+	for _, r := range rendered {
+		if r > 0x7e && r != '\n' {
+			t.Fatalf("plain mode border should be ASCII, got %q", rendered)
+		}
+	}
+	if lines := strings.Split(rendered, "\n"); !strings.HasPrefix(lines[0], "+- Title ") || lipgloss.Width(lines[0]) != lipgloss.Width(lines[1]) {
+		t.Errorf("top border = %q, want an ASCII title line as wide as the body", lines[0])
+	}
+	if !SelectedStyle().GetReverse() {
+		t.Error("SelectedStyle should use reverse video when colors are disabled")
+	}
+}
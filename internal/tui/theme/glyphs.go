@@ -0,0 +1,115 @@
+package theme
+
+import (
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Glyphs は TUI が描画する記号のセット。
+type Glyphs struct {
+	// Active・Inactive・Failed・Pending・Draining・Auth は接続・セッション状態のバッジ。
+	Active   string
+	Inactive string
+	Failed   string
+	Pending  string
+	Draining string
+	Auth     string
+	// Reachable と Unreachable はホストの疎通確認の結果。
+	Reachable   string
+	Unreachable string
+	// Check と Cross は操作の成功・失敗と、一括操作の選択の記号。
+	Check string
+	Cross string
+	// Arrow と ArrowBack は転送の向きや変更前後を示す矢印。
+	Arrow     string
+	ArrowBack string
+	// Up・Down・UpDown は送信・受信・送受信の記号。
+	Up     string
+	Down   string
+	UpDown string
+	// Pointer と Expanded は折りたたんだ・展開した行の記号。Pointer は転送経路の矢印の先端にも使う。
+	Pointer  string
+	Expanded string
+	Ellipsis string
+	// HLine と VLine は区切り線。
+	HLine string
+	VLine string
+	// Timer・Retry・Stop は自動停止までの残り時間・再接続回数・停止理由の記号。
+	Timer string
+	Retry string
+	Stop  string
+	// Spark はスパークラインの高さ 8 段階の文字。
+	Spark []rune
+}
+
+var unicodeGlyphs = Glyphs{
+	Active: "●", Inactive: "○", Failed: "✗", Pending: "◌", Draining: "◐", Auth: "◎",
+	Reachable: "•", Unreachable: "•",
+	Check: "✓", Cross: "✗",
+	Arrow: "→", ArrowBack: "←",
+	Up: "↑", Down: "↓", UpDown: "⇅",
+	Pointer: "▸", Expanded: "▾", Ellipsis: "…",
+	HLine: "─", VLine: "│",
+	Timer: "⏳", Retry: "↻", Stop: "⏹",
+	Spark: []rune("▁▂▃▄▅▆▇█"),
+}
+
+// asciiGlyphs は色なしでも状態を見分けられるよう、状態ごとに異なる ASCII の記号を割り当てる。
+var asciiGlyphs = Glyphs{
+	Active: "*", Inactive: "-", Failed: "x", Pending: "~", Draining: "%", Auth: "!",
+	Reachable: "+", Unreachable: "x",
+	Check: "+", Cross: "x",
+	Arrow: "->", ArrowBack: "<-",
+	Up: "^", Down: "v", UpDown: "^v",
+	Pointer: ">", Expanded: "v", Ellipsis: "...",
+	HLine: "-", VLine: "|",
+	Timer: "T-", Retry: "R", Stop: "[]",
+	Spark: []rune("_.-:=+*#"),
+}
+
+var (
+	plain        bool
+	savedProfile termenv.Profile // SetPlain(true) の前の色のプロファイル
+)
+
+// SetPlain はプレーンモードを切り替える。プレーンモードでは色を使わず、記号とボーダーを ASCII にする。
+func SetPlain(on bool) {
+	mu.Lock()
+	defer mu.Unlock()
+	switch {
+	case on && !plain:
+		savedProfile = lipgloss.ColorProfile()
+		lipgloss.SetColorProfile(termenv.Ascii)
+	case !on && plain:
+		lipgloss.SetColorProfile(savedProfile)
+	}
+	plain = on
+}
+
+// Plain はプレーンモードかどうかを返す。
+func Plain() bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	return plain
+}
+
+// Colorless は色を使わずに描画するかどうかを返す。プレーンモードのほか、NO_COLOR などで端末の色が無効な場合も true を返す。
+func Colorless() bool {
+	return Plain() || lipgloss.ColorProfile() == termenv.Ascii
+}
+
+// Symbols は現在のモードの記号のセットを返す。
+func Symbols() Glyphs {
+	if Plain() {
+		return asciiGlyphs
+	}
+	return unicodeGlyphs
+}
+
+// Border はパネルのボーダーを返す。プレーンモードでは ASCII のボーダーを使う。
+func Border() lipgloss.Border {
+	if Plain() {
+		return lipgloss.ASCIIBorder()
+	}
+	return lipgloss.RoundedBorder()
+}
@@ -0,0 +1,77 @@
+package theme_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/ousiassllc/moleport/internal/tui/theme"
+)
+
+func TestSetPlain(t *testing.T) {
+	before := lipgloss.ColorProfile()
+	t.Cleanup(func() { theme.SetPlain(false) })
+
+	theme.SetPlain(true)
+	if !theme.Plain() || !theme.Colorless() {
+		t.Error("Plain() and Colorless() should be true after SetPlain(true)")
+	}
+	if got := lipgloss.ColorProfile(); got != termenv.Ascii {
+		t.Errorf("ColorProfile() = %v, want Ascii", got)
+	}
+	if got := theme.Border(); got != lipgloss.ASCIIBorder() {
+		t.Errorf("Border() = %+v, want ASCIIBorder", got)
+	}
+	if got := theme.Symbols().Check; got != "+" {
+		t.Errorf("Symbols().Check = %q, want %q", got, "+")
+	}
+
+	theme.SetPlain(false)
+	if theme.Plain() {
+		t.Error("Plain() should be false after SetPlain(false)")
+	}
+	if got := lipgloss.ColorProfile(); got != before {
+		t.Errorf("ColorProfile() = %v, want restored %v", got, before)
+	}
+	if got := theme.Symbols().Check; got != "✓" {
+		t.Errorf("Symbols().Check = %q, want %q", got, "✓")
+	}
+}
+
+func TestSymbols_PlainIsASCII(t *testing.T) {
+	t.Cleanup(func() { theme.SetPlain(false) })
+	theme.SetPlain(true)
+
+	g := reflect.ValueOf(theme.Symbols())
+	for i := range g.NumField() {
+		s := g.Field(i)
+		text := ""
+		if s.Kind() == reflect.String {
+			text = s.String()
+		} else {
+			text = string(s.Interface().([]rune))
+		}
+		for _, r := range text {
+			if r > 0x7e {
+				t.Errorf("%s = %q contains a non-ASCII rune", g.Type().Field(i).Name, text)
+			}
+		}
+	}
+
+	// 色なしでも見分けられるよう、状態のバッジは互いに異なる記号を使う
+	sym := theme.Symbols()
+	seen := map[string]string{}
+	for name, s := range map[string]string{
+		"Active": sym.Active, "Inactive": sym.Inactive, "Failed": sym.Failed,
+		"Pending": sym.Pending, "Draining": sym.Draining, "Auth": sym.Auth,
+	} {
+		if other, ok := seen[s]; ok {
+			t.Errorf("%s and %s share the symbol %q", name, other, s)
+		}
+		seen[s] = name
+	}
+	if sym.Reachable == sym.Unreachable {
+		t.Errorf("Reachable and Unreachable share the symbol %q", sym.Reachable)
+	}
+}
@@ -1,8 +1,8 @@
 package theme
 
 var presetOrder = []string{
-	"dark-violet", "dark-blue", "dark-green", "dark-cyan", "dark-orange",
-	"light-violet", "light-blue", "light-green", "light-cyan", "light-orange",
+	"dark-violet", "dark-blue", "dark-green", "dark-cyan", "dark-orange", "dark-solarized", "dark-contrast",
+	"light-violet", "light-blue", "light-green", "light-cyan", "light-orange", "light-solarized", "light-contrast",
 }
 
 var presets = map[string]Preset{
@@ -48,6 +48,23 @@ var presets = map[string]Preset{
 			Error: "#EF4444", Warning: "#F59E0B", BgHighlight: "#27272A",
 		},
 	},
+	// Solarized と High Contrast は共通ベースを使わず、ベースの色もテーマごとに定義する
+	"dark-solarized": {
+		ID: "dark-solarized", Base: "dark", Accent: "solarized", Label: "Solarized",
+		Palette: Palette{
+			Accent: "#268BD2", AccentDim: "#2AA198",
+			Text: "#93A1A1", Muted: "#657B83", Dim: "#586E75",
+			Error: "#DC322F", Warning: "#B58900", BgHighlight: "#073642",
+		},
+	},
+	"dark-contrast": {
+		ID: "dark-contrast", Base: "dark", Accent: "contrast", Label: "High Contrast",
+		Palette: Palette{
+			Accent: "#FFD700", AccentDim: "#FFB000",
+			Text: "#FFFFFF", Muted: "#D4D4D4", Dim: "#A3A3A3",
+			Error: "#FF5555", Warning: "#FFAA00", BgHighlight: "#3A3A3A",
+		},
+	},
 
 	// Light テーマ
 	// 共通ベース: Text=#18181B, Muted=#A1A1AA, Dim=#D4D4D8, Error=#DC2626, Warning=#D97706, BgHighlight=#F4F4F5
@@ -91,4 +108,20 @@ var presets = map[string]Preset{
 			Error: "#DC2626", Warning: "#D97706", BgHighlight: "#F4F4F5",
 		},
 	},
+	"light-solarized": {
+		ID: "light-solarized", Base: "light", Accent: "solarized", Label: "Solarized",
+		Palette: Palette{
+			Accent: "#268BD2", AccentDim: "#2AA198",
+			Text: "#073642", Muted: "#839496", Dim: "#93A1A1",
+			Error: "#DC322F", Warning: "#B58900", BgHighlight: "#EEE8D5",
+		},
+	},
+	"light-contrast": {
+		ID: "light-contrast", Base: "light", Accent: "contrast", Label: "High Contrast",
+		Palette: Palette{
+			Accent: "#0000CC", AccentDim: "#00008B",
+			Text: "#000000", Muted: "#333333", Dim: "#666666",
+			Error: "#B00000", Warning: "#8A4B00", BgHighlight: "#E0E0E0",
+		},
+	},
 }
@@ -30,7 +30,8 @@ type Preset struct {
 
 var (
 	current Palette
-	mu      sync.RWMutex // current のみを保護する。presets, presetOrder は初期化後に不変。
+	users   []Preset     // SetUserPresets で登録したユーザーテーマのプリセット
+	mu      sync.RWMutex // current, users, plain を保護する。presets, presetOrder は初期化後に不変。
 )
 
 func init() {
@@ -51,7 +52,7 @@ func Current() Palette {
 // Apply は指定された presetID のパレットを適用する。
 // 存在しない ID の場合は何もしない。
 func Apply(presetID string) {
-	p, ok := presets[presetID]
+	p, ok := FindPreset(presetID)
 	if !ok {
 		return
 	}
@@ -60,21 +61,23 @@ func Apply(presetID string) {
 	current = p.Palette
 }
 
-// Presets は全プリセットを定義順で返す。
+// Presets は全プリセットを定義順で返す。ユーザーテーマは組み込みのプリセットの後に登録順で並ぶ。
 func Presets() []Preset {
-	result := make([]Preset, len(presetOrder))
-	for i, id := range presetOrder {
-		result[i] = presets[id]
+	mu.RLock()
+	defer mu.RUnlock()
+	result := make([]Preset, 0, len(presetOrder)+len(users))
+	for _, id := range presetOrder {
+		result = append(result, presets[id])
 	}
-	return result
+	return append(result, users...)
 }
 
 // PresetsByBase は指定された base ("dark" or "light") のプリセットを定義順で返す。
 func PresetsByBase(base string) []Preset {
 	var result []Preset
-	for _, id := range presetOrder {
-		if presets[id].Base == base {
-			result = append(result, presets[id])
+	for _, p := range Presets() {
+		if p.Base == base {
+			result = append(result, p)
 		}
 	}
 	return result
@@ -82,8 +85,52 @@ func PresetsByBase(base string) []Preset {
 
 // FindPreset は指定された ID のプリセットを返す。
 func FindPreset(id string) (Preset, bool) {
-	p, ok := presets[id]
-	return p, ok
+	if p, ok := presets[id]; ok {
+		return p, true
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, p := range users {
+		if p.ID == id {
+			return p, true
+		}
+	}
+	return Preset{}, false
+}
+
+// NewUserPreset は config.yaml の tui.themes で定義したユーザーテーマのプリセットを返す。
+// colors の空の色は base の Violet プリセットから引き継ぎ、AccentDim が空の場合は Accent を使う。
+func NewUserPreset(name, base string, colors Palette) Preset {
+	if base != "light" {
+		base = "dark"
+	}
+	p := presets[PresetIDFromConfig(base, "violet")].Palette
+	if colors.AccentDim == "" && colors.Accent != "" {
+		colors.AccentDim = colors.Accent
+	}
+	for dst, c := range map[*lipgloss.Color]lipgloss.Color{
+		&p.Accent: colors.Accent, &p.AccentDim: colors.AccentDim,
+		&p.Text: colors.Text, &p.Muted: colors.Muted, &p.Dim: colors.Dim,
+		&p.Error: colors.Error, &p.Warning: colors.Warning, &p.BgHighlight: colors.BgHighlight,
+	} {
+		if c != "" {
+			*dst = c
+		}
+	}
+	return Preset{ID: PresetIDFromConfig(base, name), Base: base, Accent: name, Label: name, Palette: p}
+}
+
+// SetUserPresets はユーザーテーマのプリセットを ps で置き換える。組み込みのプリセットと ID が重なるものは無視する。
+func SetUserPresets(ps []Preset) {
+	var registered []Preset
+	for _, p := range ps {
+		if _, builtin := presets[p.ID]; !builtin {
+			registered = append(registered, p)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	users = registered
 }
 
 // DefaultPresetID はデフォルトのプリセット ID を返す。
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/ousiassllc/moleport/internal/core/themeconf"
 	"github.com/ousiassllc/moleport/internal/tui/theme"
 )
 
@@ -58,19 +59,19 @@ func TestApply_UnknownIDKeepsCurrent(t *testing.T) {
 	}
 }
 
-func TestPresets_Returns10(t *testing.T) {
+func TestPresets_Returns14(t *testing.T) {
 	all := theme.Presets()
-	if len(all) != 10 {
-		t.Fatalf("Presets() returned %d items, want 10", len(all))
+	if len(all) != 14 {
+		t.Fatalf("Presets() returned %d items, want 14", len(all))
 	}
 
-	// Dark×5 が先、Light×5 が後の順序を検証
-	for i := 0; i < 5; i++ {
+	// Dark×7 が先、Light×7 が後の順序を検証
+	for i := 0; i < 7; i++ {
 		if all[i].Base != "dark" {
 			t.Errorf("Presets()[%d].Base = %q, want %q", i, all[i].Base, "dark")
 		}
 	}
-	for i := 5; i < 10; i++ {
+	for i := 7; i < 14; i++ {
 		if all[i].Base != "light" {
 			t.Errorf("Presets()[%d].Base = %q, want %q", i, all[i].Base, "light")
 		}
@@ -79,8 +80,8 @@ func TestPresets_Returns10(t *testing.T) {
 
 func TestPresetsByBase(t *testing.T) {
 	dark := theme.PresetsByBase("dark")
-	if len(dark) != 5 {
-		t.Errorf("PresetsByBase(dark) returned %d items, want 5", len(dark))
+	if len(dark) != 7 {
+		t.Errorf("PresetsByBase(dark) returned %d items, want 7", len(dark))
 	}
 	for _, p := range dark {
 		if p.Base != "dark" {
@@ -89,8 +90,8 @@ func TestPresetsByBase(t *testing.T) {
 	}
 
 	light := theme.PresetsByBase("light")
-	if len(light) != 5 {
-		t.Errorf("PresetsByBase(light) returned %d items, want 5", len(light))
+	if len(light) != 7 {
+		t.Errorf("PresetsByBase(light) returned %d items, want 7", len(light))
 	}
 	for _, p := range light {
 		if p.Base != "light" {
@@ -131,3 +132,49 @@ func TestPresetIDFromConfig(t *testing.T) {
 		t.Errorf("PresetIDFromConfig(dark, violet) = %q, want %q", got, "dark-violet")
 	}
 }
+
+func TestPresets_AccentsMatchConfig(t *testing.T) {
+	for _, base := range []string{"dark", "light"} {
+		for _, accent := range themeconf.Accents {
+			if _, ok := theme.FindPreset(theme.PresetIDFromConfig(base, accent)); !ok {
+				t.Errorf("preset %s-%s not found", base, accent)
+			}
+		}
+	}
+}
+
+func TestUserPresets(t *testing.T) {
+	t.Cleanup(func() {
+		theme.SetUserPresets(nil)
+		theme.Apply(theme.DefaultPresetID())
+	})
+
+	ocean := theme.NewUserPreset("ocean", "light", theme.Palette{Accent: "#0EA5E9", Text: "16"})
+	if ocean.ID != "light-ocean" || ocean.Base != "light" || ocean.Label != "ocean" {
+		t.Errorf("NewUserPreset() = %+v", ocean)
+	}
+	light, _ := theme.FindPreset("light-violet")
+	want := light.Palette
+	want.Accent, want.AccentDim, want.Text = "#0EA5E9", "#0EA5E9", "16"
+	if ocean.Palette != want {
+		t.Errorf("NewUserPreset().Palette = %+v, want %+v", ocean.Palette, want)
+	}
+
+	shadow := theme.NewUserPreset("violet", "dark", theme.Palette{Accent: "#000000"})
+	theme.SetUserPresets([]theme.Preset{ocean, shadow})
+	if got := theme.PresetsByBase("light"); got[len(got)-1].ID != "light-ocean" {
+		t.Errorf("PresetsByBase(light) last = %q, want light-ocean", got[len(got)-1].ID)
+	}
+	if p, _ := theme.FindPreset("dark-violet"); p.Palette.Accent == "#000000" {
+		t.Error("a user theme must not replace a built-in preset")
+	}
+	theme.Apply("light-ocean")
+	if got := theme.Current().Accent; got != "#0EA5E9" {
+		t.Errorf("after Apply(light-ocean): Accent = %q", got)
+	}
+
+	theme.SetUserPresets(nil)
+	if _, ok := theme.FindPreset("light-ocean"); ok {
+		t.Error("SetUserPresets(nil) should remove user themes")
+	}
+}